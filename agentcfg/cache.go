@@ -18,44 +18,172 @@
 package agentcfg
 
 import (
+	"sync"
 	"time"
 
 	gocache "github.com/patrickmn/go-cache"
 
 	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
 )
 
 const (
 	cleanupInterval = 60 * time.Second
 )
 
+var (
+	registry     = monitoring.Default.NewRegistry("apm-server.agentcfg")
+	fetchErrors  = monitoring.NewInt(registry, "fetch.errors")
+	staleResults = monitoring.NewInt(registry, "fetch.stale")
+	negativeHits = monitoring.NewInt(registry, "cache.negative.hits")
+)
+
 type cache struct {
 	logger  *logp.Logger
 	gocache *gocache.Cache
+
+	// stale holds the last successfully fetched result for each query,
+	// which is returned if fetching fails. stale is nil if returning
+	// stale results is disabled.
+	stale *gocache.Cache
+
+	// negative holds "no configuration found" results, which are cached
+	// separately from other results so their expiration can be configured
+	// independently. negative is nil if the negative cache is disabled.
+	negative           *gocache.Cache
+	negativeMaxEntries int
+
+	mu         sync.Mutex
+	refreshing map[string]bool
 }
 
-func newCache(logger *logp.Logger, exp time.Duration) *cache {
-	logger.Infof("Cache creation with expiration %v.", exp)
-	return &cache{
-		logger:  logger,
-		gocache: gocache.New(exp, cleanupInterval)}
+func newCache(logger *logp.Logger, cfg FetcherConfig) *cache {
+	logger.Infof("Cache creation with expiration %v.", cfg.CacheExpiration)
+	c := &cache{
+		logger:     logger,
+		gocache:    gocache.New(cfg.CacheExpiration, cleanupInterval),
+		refreshing: make(map[string]bool),
+	}
+	if cfg.MaxStale > 0 {
+		c.stale = gocache.New(cfg.MaxStale, cleanupInterval)
+	}
+	if cfg.NegativeCacheExpiration > 0 {
+		c.negative = gocache.New(cfg.NegativeCacheExpiration, cleanupInterval)
+		c.negativeMaxEntries = cfg.NegativeCacheMaxEntries
+	}
+	return c
 }
 
-func (c *cache) fetch(query Query, fetch func() (Result, error)) (Result, error) {
+// fetch returns the cached result for query, or calls fetch to retrieve it.
+//
+// If fetch fails and a previously fetched result for query is available,
+// the previous result is returned with Stale set, and refresh is called
+// in the background to update the cache. Until the background refresh
+// completes, the previous result is returned without calling fetch.
+func (c *cache) fetch(query Query, fetch, refresh func() (Result, error)) (Result, error) {
+	id := query.id()
 	// return from cache if possible
-	value, found := c.gocache.Get(query.id())
+	value, found := c.gocache.Get(id)
 	if found && value != nil {
 		return value.(Result), nil
 	}
+	if c.negative != nil {
+		if value, found := c.negative.Get(id); found && value != nil {
+			negativeHits.Inc()
+			return value.(Result), nil
+		}
+	}
+	if c.isRefreshing(id) {
+		if result, ok := c.getStale(id); ok {
+			return result, nil
+		}
+	}
 	// retrieve resource from external source
 	result, err := fetch()
 	if err != nil {
+		fetchErrors.Inc()
+		if stale, ok := c.getStale(id); ok {
+			c.logger.Warnf("Fetching agent configuration for %q failed, returning last known configuration: %s", id, err)
+			c.refreshInBackground(id, refresh)
+			return stale, nil
+		}
 		return result, err
 	}
-	c.gocache.SetDefault(query.id(), result)
+	c.set(id, result)
+	return result, nil
+}
 
+func (c *cache) set(id string, result Result) {
+	if c.negative != nil && isNotFound(result) && c.negative.ItemCount() < c.negativeMaxEntries {
+		c.negative.SetDefault(id, result)
+		// Remove any result cached before the configuration was deleted.
+		c.gocache.Delete(id)
+	} else {
+		c.gocache.SetDefault(id, result)
+		if c.negative != nil {
+			c.negative.Delete(id)
+		}
+	}
+	if c.stale != nil {
+		c.stale.SetDefault(id, result)
+	}
 	if c.logger.IsDebug() {
-		c.logger.Debugf("Cache size %v. Added ID %v.", c.gocache.ItemCount(), query.id())
+		c.logger.Debugf("Cache size %v. Added ID %v.", c.gocache.ItemCount(), id)
 	}
-	return result, nil
+}
+
+// isNotFound reports whether result indicates that Kibana holds
+// no agent configuration for the query.
+func isNotFound(result Result) bool {
+	return result.Source.Etag == EtagSentinel && len(result.Source.Settings) == 0
+}
+
+func (c *cache) getStale(id string) (Result, bool) {
+	if c.stale == nil {
+		return Result{}, false
+	}
+	value, found := c.stale.Get(id)
+	if !found || value == nil {
+		return Result{}, false
+	}
+	staleResults.Inc()
+	result := value.(Result)
+	result.Stale = true
+	return result, true
+}
+
+func (c *cache) isRefreshing(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refreshing[id]
+}
+
+// refreshInBackground calls refresh in a background goroutine, updating
+// the cache if it succeeds. At most one refresh per ID is in flight.
+func (c *cache) refreshInBackground(id string, refresh func() (Result, error)) {
+	if refresh == nil {
+		return
+	}
+	c.mu.Lock()
+	if c.refreshing[id] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[id] = true
+	c.mu.Unlock()
+
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, id)
+			c.mu.Unlock()
+		}()
+		result, err := refresh()
+		if err != nil {
+			fetchErrors.Inc()
+			c.logger.Warnf("Refreshing agent configuration for %q failed: %s", id, err)
+			return
+		}
+		c.set(id, result)
+	}()
 }
@@ -30,8 +30,8 @@ import (
 )
 
 var (
-	defaultResult  = Result{Source{Settings: Settings{"a": "default"}, Etag: "123"}}
-	externalResult = Result{Source{Settings: Settings{"a": "b"}, Etag: "123"}}
+	defaultResult  = Result{Source: Source{Settings: Settings{"a": "default"}, Etag: "123"}}
+	externalResult = Result{Source: Source{Settings: Settings{"a": "b"}, Etag: "123"}}
 )
 
 type cacheSetup struct {
@@ -43,7 +43,7 @@ type cacheSetup struct {
 func newCacheSetup(service string, exp time.Duration, init bool) cacheSetup {
 	setup := cacheSetup{
 		query:  Query{Service: Service{Name: service}, Etag: "123"},
-		cache:  newCache(logp.NewLogger(""), exp),
+		cache:  newCache(logp.NewLogger(""), FetcherConfig{CacheExpiration: exp}),
 		result: defaultResult,
 	}
 	if init {
@@ -69,14 +69,14 @@ func TestCache_fetchAndAdd(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			setup := newCacheSetup(name, exp, testCase.init)
 
-			doc, err := setup.cache.fetch(setup.query, testCase.fetchFunc)
+			doc, err := setup.cache.fetch(setup.query, testCase.fetchFunc, testCase.fetchFunc)
 			assert.Equal(t, testCase.doc, doc)
 			if testCase.shouldFail {
 				require.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 				//ensure value is cached afterwards
-				cachedDoc, error := setup.cache.fetch(setup.query, testCase.fetchFunc)
+				cachedDoc, error := setup.cache.fetch(setup.query, testCase.fetchFunc, testCase.fetchFunc)
 				require.NoError(t, error)
 				assert.Equal(t, doc, cachedDoc)
 			}
@@ -86,16 +86,96 @@ func TestCache_fetchAndAdd(t *testing.T) {
 	t.Run("CacheKeyExpires", func(t *testing.T) {
 		exp := 100 * time.Millisecond
 		setup := newCacheSetup(t.Name(), exp, false)
-		doc, err := setup.cache.fetch(setup.query, testFn)
+		doc, err := setup.cache.fetch(setup.query, testFn, testFn)
 		require.NoError(t, err)
 		require.NotNil(t, doc)
 		time.Sleep(exp)
-		emptyDoc, error := setup.cache.fetch(setup.query, testFnNil)
+		emptyDoc, error := setup.cache.fetch(setup.query, testFnNil, testFnNil)
 		require.NoError(t, error)
 		assert.Equal(t, emptyDoc, Result{})
 	})
 }
 
+func TestCache_fetchStale(t *testing.T) {
+	query := Query{Service: Service{Name: t.Name()}, Etag: "123"}
+	c := newCache(logp.NewLogger(""), FetcherConfig{CacheExpiration: time.Nanosecond, MaxStale: time.Hour})
+
+	doc, err := c.fetch(query, testFn, testFn)
+	require.NoError(t, err)
+	assert.Equal(t, externalResult, doc)
+
+	// Fetching fails, and the refresh blocks until released, so the
+	// previous result is returned while the refresh is in flight.
+	release := make(chan struct{})
+	refreshed := Result{Source: Source{Settings: Settings{"a": "refreshed"}, Etag: "456"}}
+	refresh := func() (Result, error) {
+		<-release
+		return refreshed, nil
+	}
+	time.Sleep(time.Millisecond)
+	doc, err = c.fetch(query, testFnErr, refresh)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Source: externalResult.Source, Stale: true}, doc)
+
+	doc, err = c.fetch(query, testFnErr, refresh)
+	require.NoError(t, err)
+	assert.True(t, doc.Stale)
+
+	close(release)
+	assert.Eventually(t, func() bool { return !c.isRefreshing(query.id()) }, time.Second, time.Millisecond)
+	doc, err = c.fetch(query, testFnErr, testFnErr)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Source: refreshed.Source, Stale: true}, doc)
+}
+
+func TestCache_fetchStaleDisabled(t *testing.T) {
+	query := Query{Service: Service{Name: t.Name()}, Etag: "123"}
+	c := newCache(logp.NewLogger(""), FetcherConfig{CacheExpiration: time.Nanosecond})
+
+	_, err := c.fetch(query, testFn, testFn)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = c.fetch(query, testFnErr, testFn)
+	assert.Error(t, err)
+}
+
+func TestCache_fetchNegative(t *testing.T) {
+	cfg := FetcherConfig{
+		CacheExpiration:         time.Nanosecond,
+		NegativeCacheExpiration: time.Hour,
+		NegativeCacheMaxEntries: 1,
+	}
+	c := newCache(logp.NewLogger(""), cfg)
+
+	var fetches int
+	fetchNotFound := func() (Result, error) {
+		fetches++
+		return zeroResult(), nil
+	}
+	query := func(name string) Query { return Query{Service: Service{Name: name}} }
+
+	for i := 0; i < 3; i++ {
+		doc, err := c.fetch(query("a"), fetchNotFound, nil)
+		require.NoError(t, err)
+		assert.Equal(t, zeroResult(), doc)
+	}
+	assert.Equal(t, 1, fetches)
+
+	// The negative cache is full, so "b" is cached with the regular expiration.
+	for i := 0; i < 2; i++ {
+		time.Sleep(time.Millisecond)
+		_, err := c.fetch(query("b"), fetchNotFound, nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, fetches)
+
+	// Results with configuration are never cached in the negative cache.
+	time.Sleep(time.Millisecond)
+	_, err := c.fetch(query("c"), testFn, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.negative.ItemCount())
+}
+
 func BenchmarkFetchAndAdd(b *testing.B) {
 	// this micro benchmark only accounts for the underlying cache
 	// providing some benchmark baseline in case the cache library changes in the future
@@ -109,7 +189,7 @@ func BenchmarkFetchAndAdd(b *testing.B) {
 		exp := 5 * time.Minute
 		setup := newCacheSetup(b.Name(), exp, true)
 		for i := 0; i < b.N; i++ {
-			setup.cache.fetch(setup.query, testFn)
+			setup.cache.fetch(setup.query, testFn, testFn)
 		}
 	})
 
@@ -121,7 +201,7 @@ func BenchmarkFetchAndAdd(b *testing.B) {
 		q := Query{Service: Service{}}
 		for i := 0; i < b.N; i++ {
 			q.Service.Name = fmt.Sprintf("%v", b.N)
-			setup.cache.fetch(q, testFn)
+			setup.cache.fetch(q, testFn, testFn)
 		}
 	})
 }
@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package agentcfg
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+// CustomSettingsPrefix is the prefix of custom settings keys, which are of
+// the form "custom.<namespace>.<name>".
+const CustomSettingsPrefix = "custom."
+
+var customDropped = monitoring.NewInt(registry, "custom.dropped")
+
+// customSettingsLimits holds the size limits for custom settings.
+type customSettingsLimits struct {
+	maxValueSize int
+	maxTotalSize int
+}
+
+// filterCustom returns result with custom settings removed, except for those
+// in one of the namespaces requested by the query, and within limits.
+//
+// Custom settings are added in order of their keys, so that the same
+// settings are returned for the same stored configuration. Settings that
+// would exceed the limits, or that have malformed keys, are dropped.
+func filterCustom(namespaces []string, limits customSettingsLimits, result Result) Result {
+	var custom []string
+	for k := range result.Source.Settings {
+		if strings.HasPrefix(k, CustomSettingsPrefix) {
+			custom = append(custom, k)
+		}
+	}
+	if len(custom) == 0 {
+		return result
+	}
+
+	requested := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		requested[namespace] = true
+	}
+	settings := make(Settings, len(result.Source.Settings))
+	for k, v := range result.Source.Settings {
+		if !strings.HasPrefix(k, CustomSettingsPrefix) {
+			settings[k] = v
+		}
+	}
+	sort.Strings(custom)
+	var totalSize int
+	for _, k := range custom {
+		namespace, name := splitCustomKey(k)
+		if !requested[namespace] || name == "" {
+			continue
+		}
+		v := result.Source.Settings[k]
+		size := len(k) + len(v)
+		if len(v) > limits.maxValueSize || totalSize+size > limits.maxTotalSize {
+			customDropped.Inc()
+			continue
+		}
+		totalSize += size
+		settings[k] = v
+	}
+	result.Source.Settings = settings
+	return result
+}
+
+// splitCustomKey splits a custom settings key into its namespace and name.
+func splitCustomKey(k string) (namespace, name string) {
+	k = strings.TrimPrefix(k, CustomSettingsPrefix)
+	i := strings.IndexByte(k, '.')
+	if i <= 0 {
+		return "", ""
+	}
+	return k[:i], k[i+1:]
+}
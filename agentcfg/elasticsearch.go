@@ -0,0 +1,160 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package agentcfg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+)
+
+// ElasticsearchIndex is the index in which Kibana stores agent configuration.
+const ElasticsearchIndex = ".apm-agent-configuration"
+
+type esSearchResponse struct {
+	Hits struct {
+		Hits []json.RawMessage `json:"hits"`
+	} `json:"hits"`
+}
+
+type esHit struct {
+	ID     string `json:"_id"`
+	Source struct {
+		Etag           string `json:"etag"`
+		AppliedByAgent bool   `json:"applied_by_agent"`
+	} `json:"_source"`
+}
+
+// requestElasticsearch searches the agent configuration index directly,
+// returning the best matching hit in the same form as the Kibana search API.
+//
+// Like Kibana, the configuration is marked as applied by the agent if the
+// query Etag matches, or the query asks for it to be marked as applied.
+func (f *Fetcher) requestElasticsearch(ctx context.Context, query Query) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(esQuery(query.Service)); err != nil {
+		return nil, err
+	}
+	statusCode, body, err := f.esClient.SearchQuery(ctx, ElasticsearchIndex, &buf)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrMsgSendToElasticsearchFailed)
+	}
+	defer body.Close()
+
+	if statusCode == http.StatusNotFound {
+		// The index does not exist until Kibana creates the first configuration.
+		return nil, nil
+	}
+	if statusCode >= http.StatusBadRequest {
+		b, _ := ioutil.ReadAll(body)
+		return nil, errors.New(string(b))
+	}
+
+	var resp esSearchResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, errors.Wrap(err, ErrMsgReadElasticsearchResponse)
+	}
+	if len(resp.Hits.Hits) == 0 {
+		return nil, nil
+	}
+	hit := resp.Hits.Hits[0]
+	if err := f.markAppliedByAgent(ctx, query, hit); err != nil {
+		// Failing to mark the configuration as applied does not
+		// prevent the configuration from being returned.
+		f.logger.With(logp.Error(err)).Warn("marking agent configuration as applied failed")
+	}
+	return hit, nil
+}
+
+func (f *Fetcher) markAppliedByAgent(ctx context.Context, query Query, raw json.RawMessage) error {
+	var hit esHit
+	if err := json.Unmarshal(raw, &hit); err != nil {
+		return err
+	}
+	markAsApplied := query.MarkAsAppliedByAgent != nil && *query.MarkAsAppliedByAgent
+	if hit.ID == "" || hit.Source.AppliedByAgent || (!markAsApplied && query.Etag != hit.Source.Etag) {
+		return nil
+	}
+	resp, err := esapi.UpdateRequest{
+		Index:      ElasticsearchIndex,
+		DocumentID: hit.ID,
+		Body:       strings.NewReader(`{"doc":{"applied_by_agent":true}}`),
+	}.Do(ctx, f.esClient)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return errors.New(string(b))
+	}
+	return nil
+}
+
+// esQuery returns the search request body used by Kibana for finding the
+// agent configuration for a service: an exact match on service name and
+// environment is preferred over a match on the name only, which in turn is
+// preferred over a match on the environment only, and lastly a configuration
+// for all services and environments.
+func esQuery(service Service) map[string]interface{} {
+	var should []map[string]interface{}
+	if service.Name != "" {
+		should = append(should, boostedFilter(ServiceName, service.Name, 2))
+	}
+	if service.Environment != "" {
+		should = append(should, boostedFilter(ServiceEnv, service.Environment, 1))
+	}
+	should = append(should, missing(ServiceName), missing(ServiceEnv))
+	return map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"minimum_should_match": 2,
+				"should":               should,
+			},
+		},
+	}
+}
+
+func boostedFilter(field, value string, boost float64) map[string]interface{} {
+	return map[string]interface{}{
+		"constant_score": map[string]interface{}{
+			"filter": map[string]interface{}{"term": map[string]interface{}{field: value}},
+			"boost":  boost,
+		},
+	}
+}
+
+func missing(field string) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must_not": []map[string]interface{}{
+				{"exists": map[string]interface{}{"field": field}},
+			},
+		},
+	}
+}
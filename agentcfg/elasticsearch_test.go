@@ -0,0 +1,163 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package agentcfg
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/elasticsearch/estest"
+	"github.com/elastic/apm-server/kibana"
+	"github.com/elastic/apm-server/tests"
+)
+
+func TestFetcher_FetchElasticsearch(t *testing.T) {
+	esClient := func(t *testing.T, statusCode int, body m) elasticsearch.Client {
+		client, err := estest.NewElasticsearchClient(estest.NewTransport(t, statusCode, body))
+		require.NoError(t, err)
+		return client
+	}
+	searchResponse := func(hits ...m) m {
+		return m{"hits": m{"total": m{"value": len(hits)}, "hits": hits}}
+	}
+	expectedResult := func(t *testing.T, doc m) Result {
+		b, err := json.Marshal(doc)
+		result, err := newResult(b, err)
+		require.NoError(t, err)
+		return result
+	}
+	newFetcher := func(kb kibana.Client, es elasticsearch.Client, primary bool) *Fetcher {
+		return NewElasticsearchFetcher(kb, es, FetcherConfig{
+			CacheExpiration:      testExpiration,
+			ElasticsearchPrimary: primary,
+		})
+	}
+
+	t.Run("Primary", func(t *testing.T) {
+		kb := tests.MockKibana(http.StatusOK, mockDoc(0.1), mockVersion, true)
+		es := esClient(t, http.StatusOK, searchResponse(mockDoc(0.5)))
+		result, err := newFetcher(kb, es, true).Fetch(context.Background(), query(t.Name()))
+		require.NoError(t, err)
+		assert.Equal(t, expectedResult(t, mockDoc(0.5)), result)
+	})
+
+	t.Run("NoKibana", func(t *testing.T) {
+		es := esClient(t, http.StatusOK, searchResponse(mockDoc(0.5)))
+		result, err := newFetcher(nil, es, false).Fetch(context.Background(), query(t.Name()))
+		require.NoError(t, err)
+		assert.Equal(t, expectedResult(t, mockDoc(0.5)), result)
+	})
+
+	t.Run("KibanaPreferred", func(t *testing.T) {
+		kb := tests.MockKibana(http.StatusOK, mockDoc(0.1), mockVersion, true)
+		es := esClient(t, http.StatusOK, searchResponse(mockDoc(0.5)))
+		result, err := newFetcher(kb, es, false).Fetch(context.Background(), query(t.Name()))
+		require.NoError(t, err)
+		assert.Equal(t, expectedResult(t, mockDoc(0.1)), result)
+	})
+
+	t.Run("KibanaFailureFallback", func(t *testing.T) {
+		kb := tests.MockKibana(http.StatusServiceUnavailable, m{"error": "an error"}, mockVersion, true)
+		es := esClient(t, http.StatusOK, searchResponse(mockDoc(0.5)))
+		result, err := newFetcher(kb, es, false).Fetch(context.Background(), query(t.Name()))
+		require.NoError(t, err)
+		assert.Equal(t, expectedResult(t, mockDoc(0.5)), result)
+	})
+
+	t.Run("NoHits", func(t *testing.T) {
+		es := esClient(t, http.StatusOK, searchResponse())
+		result, err := newFetcher(nil, es, true).Fetch(context.Background(), query(t.Name()))
+		require.NoError(t, err)
+		assert.Equal(t, zeroResult(), result)
+	})
+
+	t.Run("IndexNotFound", func(t *testing.T) {
+		es := esClient(t, http.StatusNotFound, m{"error": "index_not_found_exception"})
+		result, err := newFetcher(nil, es, true).Fetch(context.Background(), query(t.Name()))
+		require.NoError(t, err)
+		assert.Equal(t, zeroResult(), result)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		es := esClient(t, -1, nil)
+		_, err := newFetcher(nil, es, true).Fetch(context.Background(), query(t.Name()))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), ErrMsgSendToElasticsearchFailed)
+	})
+}
+
+func TestFetcher_FetchElasticsearchMarkApplied(t *testing.T) {
+	fetch := func(t *testing.T, q Query, doc m) []map[string]interface{} {
+		var updates []map[string]interface{}
+		transport := estest.NewTransportFunc(t, func(req map[string]interface{}) (int, map[string]interface{}) {
+			if doc, ok := req["doc"]; ok {
+				updates = append(updates, doc.(map[string]interface{}))
+				return http.StatusOK, m{"result": "updated"}
+			}
+			return http.StatusOK, m{"hits": m{"hits": []m{doc}}}
+		})
+		es, err := estest.NewElasticsearchClient(transport)
+		require.NoError(t, err)
+		fetcher := NewElasticsearchFetcher(nil, es, FetcherConfig{
+			CacheExpiration:      testExpiration,
+			ElasticsearchPrimary: true,
+		})
+		_, err = fetcher.Fetch(context.Background(), q)
+		require.NoError(t, err)
+		return updates
+	}
+	markAsApplied := true
+
+	updates := fetch(t, query(t.Name()), mockDoc(0.5))
+	assert.Equal(t, []map[string]interface{}{{"applied_by_agent": true}}, updates)
+
+	updates = fetch(t, Query{Service: Service{Name: t.Name()}, Etag: "456"}, mockDoc(0.5))
+	assert.Empty(t, updates)
+
+	updates = fetch(t, Query{Service: Service{Name: t.Name()}, MarkAsAppliedByAgent: &markAsApplied}, mockDoc(0.5))
+	assert.Equal(t, []map[string]interface{}{{"applied_by_agent": true}}, updates)
+
+	applied := mockDoc(0.5)
+	applied["_source"].(m)["applied_by_agent"] = true
+	updates = fetch(t, query(t.Name()), applied)
+	assert.Empty(t, updates)
+}
+
+func TestElasticsearchQuery(t *testing.T) {
+	q := esQuery(Service{Name: "opbeans", Environment: "production"})
+	should := q["query"].(map[string]interface{})["bool"].(map[string]interface{})["should"].([]map[string]interface{})
+	require.Len(t, should, 4)
+	assert.Equal(t, boostedFilter(ServiceName, "opbeans", 2), should[0])
+	assert.Equal(t, boostedFilter(ServiceEnv, "production", 1), should[1])
+	assert.Equal(t, missing(ServiceName), should[2])
+	assert.Equal(t, missing(ServiceEnv), should[3])
+
+	q = esQuery(Service{Name: "opbeans"})
+	should = q["query"].(map[string]interface{})["bool"].(map[string]interface{})["should"].([]map[string]interface{})
+	assert.Equal(t, []map[string]interface{}{
+		boostedFilter(ServiceName, "opbeans", 2),
+		missing(ServiceName),
+		missing(ServiceEnv),
+	}, should)
+}
@@ -0,0 +1,148 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package agentcfg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+// EncryptedValuePrefix is the prefix of encrypted settings values, which are
+// of the form "encrypted:<base64-encoded nonce and AES-GCM ciphertext>".
+const EncryptedValuePrefix = "encrypted:"
+
+var encryptedDropped = monitoring.NewInt(registry, "encrypted.dropped")
+
+// Cipher encrypts and decrypts sensitive settings values.
+type Cipher struct {
+	aeads []cipher.AEAD
+}
+
+// NewCipher returns a new Cipher for the given base64-encoded AES keys.
+//
+// Values are encrypted with the first key, and decrypted with whichever
+// key they were encrypted with, so keys can be rotated by adding a new
+// key to the front of keys.
+func NewCipher(keys []string) (*Cipher, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no encryption keys specified")
+	}
+	aeads := make([]cipher.AEAD, len(keys))
+	for i, key := range keys {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid encryption key %d", i)
+		}
+		block, err := aes.NewCipher(decoded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid encryption key %d", i)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		aeads[i] = aead
+	}
+	return &Cipher{aeads: aeads}, nil
+}
+
+// Encrypt encrypts value with the first key, returning a value with the
+// prefix EncryptedValuePrefix.
+func (c *Cipher) Encrypt(value string) (string, error) {
+	aead := c.aeads[0]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return EncryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value previously returned by Encrypt.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, EncryptedValuePrefix) {
+		return "", errors.New("value is not encrypted")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, EncryptedValuePrefix))
+	if err != nil {
+		return "", errors.Wrap(err, "invalid encrypted value")
+	}
+	for _, aead := range c.aeads {
+		if len(sealed) < aead.NonceSize() {
+			continue
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		if plaintext, err := aead.Open(nil, nonce, ciphertext, nil); err == nil {
+			return string(plaintext), nil
+		}
+	}
+	return "", errors.New("failed to decrypt value with any of the configured keys")
+}
+
+// decryptSettings returns result with encrypted settings decrypted.
+//
+// Encrypted settings are only decrypted if decrypt is true, i.e. for
+// authenticated queries from trusted agents; otherwise they are dropped.
+// Encrypted settings that cannot be decrypted, for example because no keys
+// are configured, are also dropped. Encrypted values are never returned.
+func decryptSettings(c *Cipher, decrypt bool, result Result) (Result, []error) {
+	var encrypted []string
+	for k, v := range result.Source.Settings {
+		if strings.HasPrefix(v, EncryptedValuePrefix) {
+			encrypted = append(encrypted, k)
+		}
+	}
+	if len(encrypted) == 0 {
+		return result, nil
+	}
+
+	// Copy the settings, as result may be shared through the cache.
+	settings := make(Settings, len(result.Source.Settings))
+	for k, v := range result.Source.Settings {
+		settings[k] = v
+	}
+	var errs []error
+	for _, k := range encrypted {
+		delete(settings, k)
+		if !decrypt {
+			continue
+		}
+		if c == nil {
+			encryptedDropped.Inc()
+			errs = append(errs, errors.Errorf("cannot decrypt setting %q: no encryption keys configured", k))
+			continue
+		}
+		v, err := c.Decrypt(result.Source.Settings[k])
+		if err != nil {
+			encryptedDropped.Inc()
+			errs = append(errs, errors.Wrapf(err, "cannot decrypt setting %q", k))
+			continue
+		}
+		settings[k] = v
+	}
+	result.Source.Settings = settings
+	return result, errs
+}
@@ -0,0 +1,111 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package agentcfg
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKey1 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", 32)))
+	testKey2 = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("2", 16)))
+)
+
+func TestCipher(t *testing.T) {
+	c1, err := NewCipher([]string{testKey1})
+	require.NoError(t, err)
+	encrypted, err := c1.Encrypt("s3cr3t")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, EncryptedValuePrefix))
+	assert.NotContains(t, encrypted, "s3cr3t")
+
+	decrypted, err := c1.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", decrypted)
+
+	// Values encrypted with a previous key can be decrypted after rotation.
+	rotated, err := NewCipher([]string{testKey2, testKey1})
+	require.NoError(t, err)
+	decrypted, err = rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", decrypted)
+
+	c2, err := NewCipher([]string{testKey2})
+	require.NoError(t, err)
+	_, err = c2.Decrypt(encrypted)
+	assert.EqualError(t, err, "failed to decrypt value with any of the configured keys")
+	_, err = c2.Decrypt("s3cr3t")
+	assert.EqualError(t, err, "value is not encrypted")
+	_, err = c2.Decrypt(EncryptedValuePrefix + "!")
+	assert.Error(t, err)
+}
+
+func TestNewCipherInvalidKeys(t *testing.T) {
+	_, err := NewCipher(nil)
+	assert.EqualError(t, err, "no encryption keys specified")
+	_, err = NewCipher([]string{"!"})
+	assert.Error(t, err)
+	_, err = NewCipher([]string{base64.StdEncoding.EncodeToString([]byte("short"))})
+	assert.Error(t, err)
+}
+
+func TestDecryptSettings(t *testing.T) {
+	c, err := NewCipher([]string{testKey1})
+	require.NoError(t, err)
+	encrypted, err := c.Encrypt("user:pass")
+	require.NoError(t, err)
+	input := Result{Source: Source{
+		Agent: "java",
+		Settings: Settings{
+			"transaction_sample_rate":  "0.1",
+			"custom.proxy.credentials": encrypted,
+		}}}
+
+	// decrypted for authenticated queries from trusted agents
+	result, errs := decryptSettings(c, true, input)
+	assert.Empty(t, errs)
+	assert.Equal(t, Settings{
+		"transaction_sample_rate":  "0.1",
+		"custom.proxy.credentials": "user:pass",
+	}, result.Source.Settings)
+
+	// dropped for unauthenticated queries, or queries from insecure agents
+	result, errs = decryptSettings(c, false, input)
+	assert.Empty(t, errs)
+	assert.Equal(t, Settings{"transaction_sample_rate": "0.1"}, result.Source.Settings)
+
+	// dropped if no keys are configured
+	result, errs = decryptSettings(nil, true, input)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, Settings{"transaction_sample_rate": "0.1"}, result.Source.Settings)
+
+	// dropped if decryption fails
+	other, err := NewCipher([]string{testKey2})
+	require.NoError(t, err)
+	result, errs = decryptSettings(other, true, input)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, Settings{"transaction_sample_rate": "0.1"}, result.Source.Settings)
+
+	// input is not modified
+	assert.Equal(t, encrypted, input.Source.Settings["custom.proxy.credentials"])
+}
@@ -31,15 +31,18 @@ import (
 	"github.com/elastic/beats/v7/libbeat/logp"
 
 	"github.com/elastic/apm-server/convert"
+	"github.com/elastic/apm-server/elasticsearch"
 	"github.com/elastic/apm-server/kibana"
 )
 
 // Error Messages used to signal fetching errors
 const (
-	ErrMsgSendToKibanaFailed   = "sending request to kibana failed"
-	ErrMsgReadKibanaResponse   = "unable to read Kibana response body"
-	ErrUnauthorized            = "Unauthorized"
-	TransactionSamplingRateKey = "transaction_sample_rate"
+	ErrMsgSendToKibanaFailed        = "sending request to kibana failed"
+	ErrMsgReadKibanaResponse        = "unable to read Kibana response body"
+	ErrMsgSendToElasticsearchFailed = "sending request to elasticsearch failed"
+	ErrMsgReadElasticsearchResponse = "unable to read Elasticsearch response body"
+	ErrUnauthorized                 = "Unauthorized"
+	TransactionSamplingRateKey      = "transaction_sample_rate"
 )
 
 // KibanaMinVersion specifies the minimal required version of Kibana
@@ -52,29 +55,175 @@ const endpoint = "/api/apm/settings/agent-configuration/search"
 // It implements the Fetch method to retrieve agent configuration information.
 type Fetcher struct {
 	*cache
-	logger *logp.Logger
-	client kibana.Client
+	logger       *logp.Logger
+	client       kibana.Client
+	esClient     elasticsearch.Client
+	esPrimary    bool
+	customLimits customSettingsLimits
+	cipher       *Cipher
+	history      *ServiceHistory
+}
+
+// FetcherConfig holds configuration for creating a Fetcher.
+type FetcherConfig struct {
+	// CacheExpiration holds the duration for which results are cached.
+	CacheExpiration time.Duration
+
+	// NegativeCacheExpiration holds the duration for which results for
+	// queries with no agent configuration are cached. If zero, such
+	// results are cached like any other.
+	NegativeCacheExpiration time.Duration
+
+	// NegativeCacheMaxEntries holds the maximum number of queries held
+	// in the negative cache.
+	NegativeCacheMaxEntries int
+
+	// MaxStale holds the maximum duration for which the last successfully
+	// fetched result is returned when fetching fails. If zero, fetching
+	// errors are returned instead.
+	MaxStale time.Duration
+
+	// MaxCustomValueSize and MaxCustomTotalSize hold the size limits
+	// for custom settings returned to agents.
+	MaxCustomValueSize int
+	MaxCustomTotalSize int
+
+	// EncryptionKeys holds base64-encoded AES keys for decrypting
+	// sensitive settings.
+	EncryptionKeys []string
+
+	// ElasticsearchPrimary controls whether Elasticsearch is queried
+	// instead of Kibana, rather than only when fetching from Kibana fails.
+	ElasticsearchPrimary bool
 }
 
 // NewFetcher returns a Fetcher instance.
-func NewFetcher(client kibana.Client, cacheExpiration time.Duration) *Fetcher {
+//
+// Results are cached for cfg.CacheExpiration, and results for queries with
+// no agent configuration in Kibana for cfg.NegativeCacheExpiration. If
+// fetching from Kibana fails, the last successfully fetched result is returned
+// for up to cfg.MaxStale, and refreshed in the background. If cfg.MaxStale is
+// zero, fetching errors are returned instead.
+//
+// Custom settings are returned within the limits of cfg.MaxCustomValueSize
+// and cfg.MaxCustomTotalSize.
+//
+// Encrypted settings are decrypted with cfg.EncryptionKeys, and only
+// returned for queries from trusted agents.
+func NewFetcher(client kibana.Client, cfg FetcherConfig) *Fetcher {
+	return NewElasticsearchFetcher(client, nil, cfg)
+}
+
+// NewElasticsearchFetcher returns a Fetcher instance which additionally reads
+// agent configuration directly from Elasticsearch using esClient.
+//
+// If cfg.ElasticsearchPrimary is true, or client is nil, Elasticsearch is the
+// only source queried. Otherwise Kibana is queried first, and Elasticsearch is
+// queried if fetching from Kibana fails.
+func NewElasticsearchFetcher(client kibana.Client, esClient elasticsearch.Client, cfg FetcherConfig) *Fetcher {
 	logger := logp.NewLogger("agentcfg")
+	var c *Cipher
+	if len(cfg.EncryptionKeys) > 0 {
+		var err error
+		if c, err = NewCipher(cfg.EncryptionKeys); err != nil {
+			logger.With(logp.Error(err)).Error("invalid agent config encryption keys, encrypted settings will be dropped")
+		}
+	}
 	return &Fetcher{
-		client: client,
-		logger: logger,
-		cache:  newCache(logger, cacheExpiration),
+		client:    client,
+		esClient:  esClient,
+		esPrimary: cfg.ElasticsearchPrimary,
+		logger:    logger,
+		cache:     newCache(logger, cfg),
+		customLimits: customSettingsLimits{
+			maxValueSize: cfg.MaxCustomValueSize,
+			maxTotalSize: cfg.MaxCustomTotalSize,
+		},
+		cipher: c,
 	}
 }
 
+// ElasticsearchEnabled reports whether f reads agent configuration
+// directly from Elasticsearch, in which case no Kibana client is required.
+func (f *Fetcher) ElasticsearchEnabled() bool {
+	return f.esClient != nil
+}
+
+// SetServiceHistory sets the ServiceHistory in which the services for
+// which agent configuration is fetched are recorded.
+func (f *Fetcher) SetServiceHistory(history *ServiceHistory) {
+	f.history = history
+}
+
+// Warm fetches agent configuration for services, caching the results so
+// that subsequent queries for the services are served from the cache.
+// Warm returns the number of services for which agent configuration was
+// fetched, and stops when ctx is cancelled. Services for which fetching
+// fails are skipped, and the first error is returned after warming the
+// remaining services.
+func (f *Fetcher) Warm(ctx context.Context, services []Service) (int, error) {
+	var warmed, failed int
+	var firstErr error
+	for _, service := range services {
+		if err := ctx.Err(); err != nil {
+			return warmed, err
+		}
+		if _, err := f.Fetch(ctx, Query{Service: service}); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			failed++
+			continue
+		}
+		warmed++
+	}
+	if firstErr != nil {
+		return warmed, errors.Wrapf(firstErr, "fetching agent configuration failed for %d services", failed)
+	}
+	return warmed, nil
+}
+
 // Fetch retrieves agent configuration, fetched from Kibana or a local temporary cache.
+//
+// If the result is stale, i.e. the last known configuration returned because
+// fetching from Kibana failed, then Result.Stale will be true.
 func (f *Fetcher) Fetch(ctx context.Context, query Query) (Result, error) {
-	req := func() (Result, error) {
-		return newResult(f.request(ctx, convert.ToReader(query)))
+	req := func(ctx context.Context) func() (Result, error) {
+		return func() (Result, error) {
+			return newResult(f.requestSource(ctx, query))
+		}
 	}
-	result, err := f.fetch(query, req)
+	// The background refresh must not be tied to the lifetime of ctx.
+	result, err := f.fetch(query, req(ctx), req(context.Background()))
+	if err == nil && f.history != nil && query.Authenticated {
+		// Only services of authenticated backend agents are recorded,
+		// so that unauthenticated requests cannot fill the history.
+		f.history.Record(query.Service)
+	}
+	decrypt := query.Authenticated && len(query.InsecureAgents) == 0
+	result, decryptErrs := decryptSettings(f.cipher, decrypt, result)
+	for _, err := range decryptErrs {
+		f.logger.Warn(err)
+	}
+	result = filterCustom(query.CustomNamespaces, f.customLimits, result)
 	return sanitize(query.InsecureAgents, result), err
 }
 
+func (f *Fetcher) requestSource(ctx context.Context, query Query) ([]byte, error) {
+	if f.esClient == nil {
+		return f.request(ctx, convert.ToReader(query))
+	}
+	if f.client == nil || f.esPrimary {
+		return f.requestElasticsearch(ctx, query)
+	}
+	result, err := f.request(ctx, convert.ToReader(query))
+	if err != nil {
+		f.logger.With(logp.Error(err)).Warn("fetching agent configuration from Kibana failed, reading from Elasticsearch")
+		return f.requestElasticsearch(ctx, query)
+	}
+	return result, nil
+}
+
 func (f *Fetcher) request(ctx context.Context, r io.Reader) ([]byte, error) {
 	resp, err := f.client.Send(ctx, http.MethodPost, endpoint, nil, nil, r)
 	if err != nil {
@@ -102,7 +251,9 @@ func sanitize(insecureAgents []string, result Result) Result {
 	}
 	hasDataForAgent := containsAnyPrefix(result.Source.Agent, insecureAgents) || result.Source.Agent == ""
 	if !hasDataForAgent {
-		return zeroResult()
+		sanitized := zeroResult()
+		sanitized.Stale = result.Stale
+		return sanitized
 	}
 	settings := Settings{}
 	for k, v := range result.Source.Settings {
@@ -110,7 +261,7 @@ func sanitize(insecureAgents []string, result Result) Result {
 			settings[k] = v
 		}
 	}
-	return Result{Source: Source{Etag: result.Source.Etag, Settings: settings}}
+	return Result{Source: Source{Etag: result.Source.Etag, Settings: settings}, Stale: result.Stale}
 }
 
 func containsAnyPrefix(s string, prefixes []string) bool {
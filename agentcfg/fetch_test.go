@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -44,14 +45,14 @@ func TestFetcher_Fetch(t *testing.T) {
 
 	t.Run("ExpectationFailed", func(t *testing.T) {
 		kb := tests.MockKibana(http.StatusExpectationFailed, m{"error": "an error"}, mockVersion, true)
-		_, err := NewFetcher(kb, testExpiration).Fetch(context.Background(), query(t.Name()))
+		_, err := NewFetcher(kb, FetcherConfig{CacheExpiration: testExpiration}).Fetch(context.Background(), query(t.Name()))
 		require.Error(t, err)
 		assert.Equal(t, "{\"error\":\"an error\"}", err.Error())
 	})
 
 	t.Run("NotFound", func(t *testing.T) {
 		kb := tests.MockKibana(http.StatusNotFound, m{}, mockVersion, true)
-		result, err := NewFetcher(kb, testExpiration).Fetch(context.Background(), query(t.Name()))
+		result, err := NewFetcher(kb, FetcherConfig{CacheExpiration: testExpiration}).Fetch(context.Background(), query(t.Name()))
 		require.NoError(t, err)
 		assert.Equal(t, zeroResult(), result)
 	})
@@ -61,7 +62,7 @@ func TestFetcher_Fetch(t *testing.T) {
 		b, err := json.Marshal(mockDoc(0.5))
 		expectedResult, err := newResult(b, err)
 		require.NoError(t, err)
-		result, err := NewFetcher(kb, testExpiration).Fetch(context.Background(), query(t.Name()))
+		result, err := NewFetcher(kb, FetcherConfig{CacheExpiration: testExpiration}).Fetch(context.Background(), query(t.Name()))
 		require.NoError(t, err)
 		assert.Equal(t, expectedResult, result)
 	})
@@ -85,7 +86,7 @@ func TestFetcher_Fetch(t *testing.T) {
 			assert.Equal(t, expectedResult, result)
 		}
 
-		fetcher := NewFetcher(nil, time.Minute)
+		fetcher := NewFetcher(nil, FetcherConfig{CacheExpiration: time.Minute})
 
 		// nothing cached yet
 		fetch(fetcher, 0.5, 0.5)
@@ -100,6 +101,36 @@ func TestFetcher_Fetch(t *testing.T) {
 	})
 }
 
+func TestFetcher_Warm(t *testing.T) {
+	history := NewServiceHistory(filepath.Join(t.TempDir(), "services.json"), 10)
+	fetcher := NewFetcher(nil, FetcherConfig{CacheExpiration: time.Minute})
+	fetcher.SetServiceHistory(history)
+
+	services := []Service{{Name: "opbeans"}, {Name: "opbeans-go", Environment: "production"}}
+	fetcher.client = tests.MockKibana(http.StatusOK, mockDoc(0.5), mockVersion, true)
+	warmed, err := fetcher.Warm(context.Background(), services)
+	require.NoError(t, err)
+	assert.Equal(t, 2, warmed)
+
+	// Only services of authenticated queries are recorded.
+	assert.Empty(t, history.Services())
+	_, err = fetcher.Fetch(context.Background(), Query{Service: services[0], Authenticated: true})
+	require.NoError(t, err)
+	assert.Equal(t, services[:1], history.Services())
+
+	// Warmed services are fetched from the cache.
+	fetcher.client = tests.MockKibana(http.StatusInternalServerError, m{}, mockVersion, true)
+	result, err := fetcher.Fetch(context.Background(), Query{Service: services[1]})
+	require.NoError(t, err)
+	assert.Equal(t, Settings{"sampling_rate": "0.5"}, result.Source.Settings)
+
+	// Warming continues past errors, returning the first.
+	warmed, err = fetcher.Warm(context.Background(), []Service{{Name: "opbeans-node"}, {Name: "opbeans"}})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "fetching agent configuration failed for 1 services")
+	assert.Equal(t, 1, warmed)
+}
+
 func TestSanitize(t *testing.T) {
 	input := Result{Source: Source{
 		Agent:    "python",
@@ -126,6 +157,47 @@ func TestSanitize(t *testing.T) {
 	assert.Equal(t, zeroResult(), sanitize(insecureAgents, input))
 }
 
+func TestFilterCustom(t *testing.T) {
+	limits := customSettingsLimits{maxValueSize: 5, maxTotalSize: 40}
+	input := Result{Source: Source{
+		Agent: "java",
+		Settings: Settings{
+			"transaction_sample_rate": "0.1",
+			"custom.acme.a":           "1",
+			"custom.acme.b":           "22",
+			"custom.acme.large":       "666666",
+			"custom.other.a":          "1",
+			"custom.malformed":        "1",
+		}}}
+
+	// custom settings removed if no namespace requested
+	assert.Equal(t, Settings{"transaction_sample_rate": "0.1"}, filterCustom(nil, limits, input).Source.Settings)
+
+	// only requested namespaces included, large values dropped
+	assert.Equal(t, Settings{
+		"transaction_sample_rate": "0.1",
+		"custom.acme.a":           "1",
+		"custom.acme.b":           "22",
+	}, filterCustom([]string{"acme"}, limits, input).Source.Settings)
+
+	// settings exceeding the total size are dropped in key order
+	assert.Equal(t, Settings{
+		"transaction_sample_rate": "0.1",
+		"custom.acme.a":           "1",
+		"custom.acme.b":           "22",
+	}, filterCustom([]string{"acme", "other"}, limits, input).Source.Settings)
+	limits.maxTotalSize = 60
+	assert.Equal(t, Settings{
+		"transaction_sample_rate": "0.1",
+		"custom.acme.a":           "1",
+		"custom.acme.b":           "22",
+		"custom.other.a":          "1",
+	}, filterCustom([]string{"acme", "other"}, limits, input).Source.Settings)
+
+	// input is not modified
+	assert.Len(t, input.Source.Settings, 6)
+}
+
 func TestCustomJSON(t *testing.T) {
 	expected := Result{Source: Source{
 		Etag:     "123",
@@ -0,0 +1,210 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package agentcfg
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+)
+
+// serviceHistorySaveDelay is the delay after which newly recorded
+// services are persisted, so that the file is rewritten at most once
+// per delay.
+const serviceHistorySaveDelay = 30 * time.Second
+
+// ServiceHistory records the services for which agent configuration is
+// fetched, persisting them to a file so that their agent configuration
+// can be fetched again when the server restarts.
+type ServiceHistory struct {
+	path        string
+	maxServices int
+	saveDelay   time.Duration
+	logger      *logp.Logger
+	now         func() time.Time
+
+	mu        sync.Mutex
+	services  map[Service]time.Time
+	saveTimer *time.Timer
+
+	// saveMu serializes writing the file, which is done without
+	// holding mu.
+	saveMu sync.Mutex
+}
+
+type serviceHistoryFile struct {
+	Services []serviceHistoryEntry `json:"services"`
+}
+
+type serviceHistoryEntry struct {
+	Service
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// NewServiceHistory returns a new ServiceHistory persisted to path,
+// holding at most maxServices services.
+func NewServiceHistory(path string, maxServices int) *ServiceHistory {
+	return &ServiceHistory{
+		path:        path,
+		maxServices: maxServices,
+		saveDelay:   serviceHistorySaveDelay,
+		logger:      logp.NewLogger("agentcfg"),
+		now:         time.Now,
+		services:    make(map[Service]time.Time),
+	}
+}
+
+// Load loads the services persisted by a previous server. If no services
+// have been persisted, Load returns without error.
+func (h *ServiceHistory) Load() error {
+	data, err := ioutil.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var file serviceHistoryFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, entry := range file.Services {
+		if lastSeen, ok := h.services[entry.Service]; !ok || entry.LastSeen.After(lastSeen) {
+			h.services[entry.Service] = entry.LastSeen
+		}
+	}
+	h.evict()
+	return nil
+}
+
+// Services returns the recorded services, most recently seen first.
+func (h *ServiceHistory) Services() []Service {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entries := h.entries()
+	services := make([]Service, len(entries))
+	for i, entry := range entries {
+		services[i] = entry.Service
+	}
+	return services
+}
+
+// Record records that agent configuration was fetched for service.
+//
+// When a service is recorded for the first time, the services are
+// persisted in the background after a delay, so that recording does
+// not wait for the file to be written. Services recorded within the
+// delay before the server stops are not persisted.
+func (h *ServiceHistory) Record(service Service) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, ok := h.services[service]
+	if !ok && len(h.services) >= h.maxServices {
+		h.evictOldest()
+	}
+	h.services[service] = h.now()
+	if !ok && h.saveTimer == nil {
+		h.saveTimer = time.AfterFunc(h.saveDelay, func() {
+			if err := h.Save(); err != nil {
+				h.logger.With(logp.Error(err)).Warn("persisting agent configuration service history failed")
+			}
+		})
+	}
+}
+
+// Save persists the recorded services.
+func (h *ServiceHistory) Save() error {
+	h.mu.Lock()
+	if h.saveTimer != nil {
+		h.saveTimer.Stop()
+		h.saveTimer = nil
+	}
+	entries := h.entries()
+	h.mu.Unlock()
+
+	h.saveMu.Lock()
+	defer h.saveMu.Unlock()
+	return h.save(entries)
+}
+
+// evictOldest removes the least recently seen service. h.mu must be held.
+func (h *ServiceHistory) evictOldest() {
+	var oldest Service
+	var oldestSeen time.Time
+	first := true
+	for service, lastSeen := range h.services {
+		if first || lastSeen.Before(oldestSeen) {
+			oldest, oldestSeen, first = service, lastSeen, false
+		}
+	}
+	delete(h.services, oldest)
+}
+
+// evict removes the least recently seen services exceeding maxServices.
+// h.mu must be held.
+func (h *ServiceHistory) evict() {
+	if len(h.services) <= h.maxServices {
+		return
+	}
+	for _, entry := range h.entries()[h.maxServices:] {
+		delete(h.services, entry.Service)
+	}
+}
+
+// entries returns the recorded services, most recently seen first.
+// h.mu must be held.
+func (h *ServiceHistory) entries() []serviceHistoryEntry {
+	entries := make([]serviceHistoryEntry, 0, len(h.services))
+	for service, lastSeen := range h.services {
+		entries = append(entries, serviceHistoryEntry{Service: service, LastSeen: lastSeen})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].LastSeen.Equal(entries[j].LastSeen) {
+			return entries[i].LastSeen.After(entries[j].LastSeen)
+		}
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Environment < entries[j].Environment
+	})
+	return entries
+}
+
+// save writes entries to a temporary file, and renames it to h.path
+// so a partially written file is never loaded. h.saveMu must be held.
+func (h *ServiceHistory) save(entries []serviceHistoryEntry) error {
+	data, err := json.Marshal(serviceHistoryFile{Services: entries})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0750); err != nil {
+		return err
+	}
+	tmp := h.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.path)
+}
@@ -0,0 +1,87 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package agentcfg
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "services.json")
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	history := NewServiceHistory(path, 2)
+	history.now = func() time.Time { return now }
+
+	// Nothing has been persisted yet.
+	require.NoError(t, history.Load())
+	assert.Empty(t, history.Services())
+
+	opbeans := Service{Name: "opbeans", Environment: "production"}
+	opbeansGo := Service{Name: "opbeans-go"}
+	opbeansNode := Service{Name: "opbeans-node"}
+	history.Record(opbeans)
+	now = now.Add(time.Minute)
+	history.Record(opbeansGo)
+	now = now.Add(time.Minute)
+	history.Record(opbeans)
+	assert.Equal(t, []Service{opbeans, opbeansGo}, history.Services())
+
+	// The least recently seen service is evicted.
+	now = now.Add(time.Minute)
+	history.Record(opbeansNode)
+	assert.Equal(t, []Service{opbeansNode, opbeans}, history.Services())
+
+	// Services are not persisted until saved.
+	loaded := NewServiceHistory(path, 2)
+	require.NoError(t, loaded.Load())
+	assert.Empty(t, loaded.Services())
+
+	require.NoError(t, history.Save())
+	loaded = NewServiceHistory(path, 2)
+	require.NoError(t, loaded.Load())
+	assert.Equal(t, []Service{opbeansNode, opbeans}, loaded.Services())
+
+	// A smaller limit evicts services when loading.
+	loaded = NewServiceHistory(path, 1)
+	require.NoError(t, loaded.Load())
+	assert.Equal(t, []Service{opbeansNode}, loaded.Services())
+}
+
+func TestServiceHistorySaveDelay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.json")
+	history := NewServiceHistory(path, 10)
+	history.saveDelay = time.Millisecond
+	history.Record(Service{Name: "opbeans"})
+
+	assert.Eventually(t, func() bool {
+		loaded := NewServiceHistory(path, 10)
+		return loaded.Load() == nil && len(loaded.Services()) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestServiceHistoryLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("{"), 0600))
+	assert.Error(t, NewServiceHistory(path, 10).Load())
+}
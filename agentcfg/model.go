@@ -29,6 +29,8 @@ const (
 	ServiceEnv = "service.environment"
 	// Etag / If-None-Match keyword
 	Etag = "ifnonematch"
+	// CustomNamespaces keyword
+	CustomNamespaces = "custom_namespaces"
 	// EtagSentinel is a value to return back to agents when Kibana doesn't have any configuration
 	EtagSentinel = "-"
 )
@@ -42,6 +44,10 @@ var (
 // Result models a Kibana response
 type Result struct {
 	Source Source `json:"_source"`
+
+	// Stale is true if the result is the last known result, returned
+	// because fetching the current result failed.
+	Stale bool `json:"-"`
 }
 
 // Source is the Elasticsearch _source
@@ -76,6 +82,18 @@ type Query struct {
 	// identified by UnrestrictedSettings. Otherwise, if InsecureAgents is empty,
 	// the agent name is ignored and no restrictions are applied.
 	InsecureAgents []string `json:"-"`
+
+	// Authenticated indicates that the query was made by an agent with
+	// verified credentials, such as a secret token or API Key. Encrypted
+	// settings are only decrypted for authenticated queries without
+	// InsecureAgents.
+	Authenticated bool `json:"-"`
+
+	// CustomNamespaces holds the namespaces of custom settings requested
+	// by the agent. Custom settings, with keys of the form
+	// "custom.<namespace>.<name>", are only included in results for
+	// queries requesting their namespace.
+	CustomNamespaces []string `json:"-"`
 }
 
 func (q Query) id() string {
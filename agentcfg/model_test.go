@@ -42,7 +42,7 @@ func TestNewDoc(t *testing.T) {
 
 		d, err := newResult(inp, nil)
 		require.NoError(t, err)
-		assert.Equal(t, Result{Source{Etag: "123", Settings: Settings{"sample_rate": "0.5"}}}, d)
+		assert.Equal(t, Result{Source: Source{Etag: "123", Settings: Settings{"sample_rate": "0.5"}}}, d)
 	})
 }
 
@@ -31,6 +31,7 @@ import (
 // time the clients are closed are acknowledged.
 type waitPublishedAcker struct {
 	active int64 // atomic
+	acked  int64 // atomic
 
 	mu    sync.Mutex
 	empty *sync.Cond
@@ -53,9 +54,15 @@ func (w *waitPublishedAcker) AddEvent(event beat.Event, published bool) {
 
 // ACKEvents is called when published events have been acknowledged.
 func (w *waitPublishedAcker) ACKEvents(n int) {
+	atomic.AddInt64(&w.acked, int64(n))
 	w.decref(int64(n))
 }
 
+// ackedEvents returns the total number of events acknowledged.
+func (w *waitPublishedAcker) ackedEvents() int64 {
+	return atomic.LoadInt64(&w.acked)
+}
+
 // Open must be called exactly once before any new pipeline client is opened,
 // incrementing the acker's reference count.
 func (w *waitPublishedAcker) Open() {
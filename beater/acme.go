@@ -0,0 +1,44 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/elastic/beats/v7/libbeat/paths"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+// newACMEManager returns an autocert.Manager for provisioning and renewing
+// TLS certificates for the configured domains. Certificates are obtained
+// and renewed lazily in the background, as TLS connections are accepted.
+//
+// The certificate authority's Terms of Service are only accepted if
+// acme.accept_tos is set.
+func newACMEManager(cfg config.ACMEConfig) *autocert.Manager {
+	return &autocert.Manager{
+		Prompt:      func(tosURL string) bool { return cfg.AcceptTOS },
+		Cache:       autocert.DirCache(paths.Resolve(paths.Data, cfg.CacheDir)),
+		HostPolicy:  autocert.HostWhitelist(cfg.Domains...),
+		RenewBefore: cfg.RenewBefore,
+		Email:       cfg.Email,
+		Client:      &acme.Client{DirectoryURL: cfg.DirectoryURL},
+	}
+}
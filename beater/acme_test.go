@@ -0,0 +1,50 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestNewACMEManager(t *testing.T) {
+	cfg := config.DefaultConfig().ACME
+	cfg.Enabled = true
+	cfg.AcceptTOS = true
+	cfg.Domains = []string{"apm.example.com"}
+	cfg.Email = "admin@example.com"
+	cfg.DirectoryURL = "https://acme.example.com/directory"
+	cfg.RenewBefore = time.Hour
+
+	manager := newACMEManager(cfg)
+	assert.Equal(t, "admin@example.com", manager.Email)
+	assert.Equal(t, time.Hour, manager.RenewBefore)
+	assert.Equal(t, "https://acme.example.com/directory", manager.Client.DirectoryURL)
+	assert.NoError(t, manager.HostPolicy(context.Background(), "apm.example.com"))
+	assert.Error(t, manager.HostPolicy(context.Background(), "other.example.com"))
+	assert.Contains(t, manager.TLSConfig().NextProtos, "acme-tls/1")
+	assert.True(t, manager.Prompt("https://acme.example.com/tos"))
+
+	cfg.AcceptTOS = false
+	assert.False(t, newACMEManager(cfg).Prompt("https://acme.example.com/tos"))
+}
@@ -0,0 +1,362 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package alerthook posts a summary of events matching configured
+// rules to a webhook, for immediate signals without a separate
+// alerting pipeline.
+package alerthook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+)
+
+const (
+	// EventError and EventTransaction identify the
+	// types of events which may be matched by rules.
+	EventError       = "error"
+	EventTransaction = "transaction"
+
+	// queueSize holds the maximum number of alerts waiting to be posted.
+	queueSize = 100
+)
+
+var (
+	registry          = monitoring.Default.NewRegistry("apm-server.alert_webhook")
+	monitoringMatched = monitoring.NewInt(registry, "matched")
+	monitoringSent    = monitoring.NewInt(registry, "sent")
+	monitoringDropped = monitoring.NewInt(registry, "dropped")
+	monitoringErrors  = monitoring.NewInt(registry, "errors")
+)
+
+// Rule holds the conditions an event must meet to be alerted on.
+type Rule struct {
+	// Name identifies the rule in alerts.
+	Name string
+
+	// Event holds the type of event matched by the rule,
+	// EventError or EventTransaction.
+	Event string
+
+	// ServiceName, if non-empty, holds the service name of matching events.
+	ServiceName string
+
+	// Labels, if non-empty, holds labels which matching events must have.
+	Labels map[string]string
+
+	// MinDuration, if positive, holds the minimum duration of
+	// matching transactions.
+	MinDuration time.Duration
+}
+
+// Config holds configuration for a Hook.
+type Config struct {
+	// URL holds the webhook URL, to which alerts are posted as JSON.
+	URL string
+
+	// Headers holds additional HTTP headers sent with each request.
+	Headers map[string]string
+
+	// MaxPerMinute holds the maximum number of alerts posted per minute.
+	MaxPerMinute int
+
+	// Rules holds the rules for matching events.
+	Rules []Rule
+
+	// Client is the http.Client used for posting alerts.
+	//
+	// If Client is nil, http.DefaultClient will be used.
+	Client *http.Client
+
+	// Logger is the logger for logging failed requests.
+	//
+	// If Logger is nil, a new logger will be constructed.
+	Logger *logp.Logger
+}
+
+// Validate validates the config.
+func (config Config) Validate() error {
+	if config.URL == "" {
+		return errors.New("URL unspecified")
+	}
+	if config.MaxPerMinute <= 0 {
+		return errors.New("MaxPerMinute unspecified or non-positive")
+	}
+	for _, rule := range config.Rules {
+		if rule.Event != EventError && rule.Event != EventTransaction {
+			return errors.Errorf("invalid Event %q for rule %q", rule.Event, rule.Name)
+		}
+	}
+	return nil
+}
+
+// Alert holds the summary of an event matching a rule,
+// which is posted to the webhook.
+type Alert struct {
+	Rule        string            `json:"rule"`
+	Event       string            `json:"event"`
+	Timestamp   time.Time         `json:"@timestamp"`
+	Service     AlertService      `json:"service"`
+	TraceID     string            `json:"trace_id,omitempty"`
+	Transaction *AlertTransaction `json:"transaction,omitempty"`
+	Error       *AlertError       `json:"error,omitempty"`
+	Labels      common.MapStr     `json:"labels,omitempty"`
+}
+
+// AlertService identifies the service of an alerted event.
+type AlertService struct {
+	Name        string `json:"name"`
+	Environment string `json:"environment,omitempty"`
+}
+
+// AlertTransaction summarizes an alerted transaction.
+type AlertTransaction struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name,omitempty"`
+	Type       string  `json:"type,omitempty"`
+	Result     string  `json:"result,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// AlertError summarizes an alerted error.
+type AlertError struct {
+	ID            string `json:"id"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Message       string `json:"message,omitempty"`
+	Culprit       string `json:"culprit,omitempty"`
+}
+
+// Hook matches events against rules, and posts alerts for
+// matching events to a webhook, subject to a rate limit.
+type Hook struct {
+	config  Config
+	limiter *rate.Limiter
+	queue   chan Alert
+}
+
+// New returns a new Hook with the given config.
+func New(config Config) (*Hook, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid alert webhook config")
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger(logs.AlertWebhook)
+	}
+	return &Hook{
+		config:  config,
+		limiter: rate.NewLimiter(rate.Limit(float64(config.MaxPerMinute)/60), config.MaxPerMinute),
+		queue:   make(chan Alert, queueSize),
+	}, nil
+}
+
+// BatchProcessor returns a model.BatchProcessor which queues alerts for
+// events in each batch matching a rule, and then passes the batch to next.
+//
+// Alerts are dropped if the rate limit is exceeded, or if too many alerts
+// are waiting to be posted; processing is never blocked.
+func (h *Hook) BatchProcessor(next model.BatchProcessor) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		for _, tx := range batch.Transactions {
+			if rule, ok := h.matchTransaction(tx); ok {
+				h.enqueue(transactionAlert(rule, tx))
+			}
+		}
+		for _, e := range batch.Errors {
+			if rule, ok := h.matchError(e); ok {
+				h.enqueue(errorAlert(rule, e))
+			}
+		}
+		return next.ProcessBatch(ctx, batch)
+	})
+}
+
+func (h *Hook) matchTransaction(tx *model.Transaction) (string, bool) {
+	for _, rule := range h.config.Rules {
+		if rule.Event != EventTransaction || (rule.MinDuration > 0 && tx.Duration < rule.MinDuration) {
+			continue
+		}
+		if matchCommon(rule, &tx.Metadata, tx.Labels) {
+			return rule.Name, true
+		}
+	}
+	return "", false
+}
+
+func (h *Hook) matchError(e *model.Error) (string, bool) {
+	for _, rule := range h.config.Rules {
+		if rule.Event != EventError {
+			continue
+		}
+		if matchCommon(rule, &e.Metadata, e.Labels) {
+			return rule.Name, true
+		}
+	}
+	return "", false
+}
+
+// matchCommon reports whether the service and labels of an
+// event match rule. Event labels take precedence over
+// metadata labels with the same key.
+func matchCommon(rule Rule, metadata *model.Metadata, labels common.MapStr) bool {
+	if rule.ServiceName != "" && rule.ServiceName != metadata.Service.Name {
+		return false
+	}
+	for k, v := range rule.Labels {
+		value, ok := labels[k]
+		if !ok {
+			if value, ok = metadata.Labels[k]; !ok {
+				return false
+			}
+		}
+		if fmt.Sprint(value) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *Hook) enqueue(alert Alert) {
+	monitoringMatched.Inc()
+	if !h.limiter.Allow() {
+		monitoringDropped.Inc()
+		return
+	}
+	select {
+	case h.queue <- alert:
+	default:
+		monitoringDropped.Inc()
+	}
+}
+
+// Run posts queued alerts to the webhook until ctx is cancelled.
+func (h *Hook) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case alert := <-h.queue:
+			if err := h.post(ctx, alert); err != nil {
+				monitoringErrors.Inc()
+				h.config.Logger.Errorf("failed to post alert for rule %q: %s", alert.Rule, err)
+				continue
+			}
+			monitoringSent.Inc()
+		}
+	}
+}
+
+func (h *Hook) post(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, h.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.config.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := h.config.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
+
+func transactionAlert(rule string, tx *model.Transaction) Alert {
+	return Alert{
+		Rule:      rule,
+		Event:     EventTransaction,
+		Timestamp: tx.Timestamp,
+		Service:   alertService(&tx.Metadata),
+		TraceID:   tx.TraceID,
+		Transaction: &AlertTransaction{
+			ID:         tx.ID,
+			Name:       tx.Name,
+			Type:       tx.Type,
+			Result:     tx.Result,
+			DurationMs: float64(tx.Duration) / float64(time.Millisecond),
+		},
+		Labels: mergeLabels(&tx.Metadata, tx.Labels),
+	}
+}
+
+func errorAlert(rule string, e *model.Error) Alert {
+	alertError := &AlertError{
+		ID:            e.ID,
+		TransactionID: e.TransactionID,
+		Culprit:       e.Culprit,
+	}
+	if e.Exception != nil {
+		alertError.Message = e.Exception.Message
+	} else if e.Log != nil {
+		alertError.Message = e.Log.Message
+	}
+	return Alert{
+		Rule:      rule,
+		Event:     EventError,
+		Timestamp: e.Timestamp,
+		Service:   alertService(&e.Metadata),
+		TraceID:   e.TraceID,
+		Error:     alertError,
+		Labels:    mergeLabels(&e.Metadata, e.Labels),
+	}
+}
+
+func alertService(metadata *model.Metadata) AlertService {
+	return AlertService{
+		Name:        metadata.Service.Name,
+		Environment: metadata.Service.Environment,
+	}
+}
+
+func mergeLabels(metadata *model.Metadata, labels common.MapStr) common.MapStr {
+	if len(metadata.Labels) == 0 && len(labels) == 0 {
+		return nil
+	}
+	merged := make(common.MapStr, len(metadata.Labels)+len(labels))
+	for k, v := range metadata.Labels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,165 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package alerthook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/model"
+)
+
+func TestHookPostsMatchingEvents(t *testing.T) {
+	alerts := make(chan Alert, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer abc", r.Header.Get("Authorization"))
+		var alert Alert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		alerts <- alert
+	}))
+	defer srv.Close()
+
+	hook, err := New(Config{
+		URL:          srv.URL,
+		Headers:      map[string]string{"Authorization": "Bearer abc"},
+		MaxPerMinute: 60,
+		Rules: []Rule{{
+			Name:   "checkout errors",
+			Event:  EventError,
+			Labels: map[string]string{"team": "checkout"},
+		}, {
+			Name:        "slow opbeans",
+			Event:       EventTransaction,
+			ServiceName: "opbeans",
+			MinDuration: 5 * time.Second,
+		}},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hook.Run(ctx)
+
+	metadata := model.Metadata{
+		Service: model.Service{Name: "opbeans", Environment: "production"},
+		Labels:  common.MapStr{"team": "checkout"},
+	}
+	timestamp := time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)
+	batch := model.Batch{
+		Transactions: []*model.Transaction{{
+			Metadata: metadata, ID: "fast", Duration: time.Second,
+		}, {
+			Metadata: metadata, ID: "slow", TraceID: "trace", Name: "GET /", Type: "request",
+			Duration: 6 * time.Second, Timestamp: timestamp,
+		}},
+		Errors: []*model.Error{{
+			// Event labels take precedence over metadata labels.
+			Metadata: metadata, ID: "other-team", Labels: common.MapStr{"team": "search"},
+		}, {
+			Metadata: metadata, ID: "err", TransactionID: "slow", TraceID: "trace",
+			Exception: &model.Exception{Message: "boom"}, Timestamp: timestamp,
+		}},
+	}
+	var processed bool
+	err = hook.BatchProcessor(model.ProcessBatchFunc(func(ctx context.Context, b *model.Batch) error {
+		processed = true
+		return nil
+	})).ProcessBatch(ctx, &batch)
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	received := make(map[string]Alert)
+	for i := 0; i < 2; i++ {
+		select {
+		case alert := <-alerts:
+			received[alert.Event] = alert
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for alert")
+		}
+	}
+	assert.Equal(t, Alert{
+		Rule:      "slow opbeans",
+		Event:     EventTransaction,
+		Timestamp: timestamp,
+		Service:   AlertService{Name: "opbeans", Environment: "production"},
+		TraceID:   "trace",
+		Transaction: &AlertTransaction{
+			ID: "slow", Name: "GET /", Type: "request", DurationMs: 6000,
+		},
+		Labels: common.MapStr{"team": "checkout"},
+	}, received[EventTransaction])
+	assert.Equal(t, Alert{
+		Rule:      "checkout errors",
+		Event:     EventError,
+		Timestamp: timestamp,
+		Service:   AlertService{Name: "opbeans", Environment: "production"},
+		TraceID:   "trace",
+		Error:     &AlertError{ID: "err", TransactionID: "slow", Message: "boom"},
+		Labels:    common.MapStr{"team": "checkout"},
+	}, received[EventError])
+
+	select {
+	case alert := <-alerts:
+		t.Fatalf("unexpected alert: %+v", alert)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHookRateLimit(t *testing.T) {
+	hook, err := New(Config{
+		URL:          "http://testing.invalid",
+		MaxPerMinute: 2,
+		Rules:        []Rule{{Name: "errors", Event: EventError}},
+	})
+	require.NoError(t, err)
+
+	before := monitoringDropped.Get()
+	batch := model.Batch{Errors: []*model.Error{{}, {}, {}, {}}}
+	err = hook.BatchProcessor(model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		return nil
+	})).ProcessBatch(context.Background(), &batch)
+	require.NoError(t, err)
+	assert.Len(t, hook.queue, 2)
+	assert.Equal(t, int64(2), monitoringDropped.Get()-before)
+}
+
+func TestConfigValidate(t *testing.T) {
+	_, err := New(Config{MaxPerMinute: 1})
+	assert.EqualError(t, err, "invalid alert webhook config: URL unspecified")
+
+	_, err = New(Config{URL: "http://testing.invalid"})
+	assert.EqualError(t, err, "invalid alert webhook config: MaxPerMinute unspecified or non-positive")
+
+	_, err = New(Config{
+		URL:          "http://testing.invalid",
+		MaxPerMinute: 1,
+		Rules:        []Rule{{Name: "spans", Event: "span"}},
+	})
+	assert.EqualError(t, err, `invalid alert webhook config: invalid Event "span" for rule "spans"`)
+}
@@ -35,6 +35,7 @@ import (
 
 	"github.com/elastic/apm-server/approvaltest"
 	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/errorcode"
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/tests/loader"
@@ -46,18 +47,19 @@ func TestAssetHandler(t *testing.T) {
 		"method": {
 			r:    httptest.NewRequest(http.MethodGet, "/", nil),
 			code: http.StatusMethodNotAllowed,
-			body: beatertest.ResultErrWrap(request.MapResultIDToStatus[request.IDResponseErrorsMethodNotAllowed].Keyword),
+			body: beatertest.ResultErrWrap(request.MapResultIDToStatus[request.IDResponseErrorsMethodNotAllowed].Keyword,
+				errorcode.MethodNotAllowed),
 		},
 		"decode": {
 			contentType: "invalid",
 			code:        http.StatusBadRequest,
 			body: beatertest.ResultErrWrap(fmt.Sprintf("%s: invalid content type: invalid",
-				request.MapResultIDToStatus[request.IDResponseErrorsDecode].Keyword)),
+				request.MapResultIDToStatus[request.IDResponseErrorsDecode].Keyword), errorcode.Decode),
 		},
 		"validate": {
 			missingServiceName: true,
 			code:               http.StatusBadRequest,
-			body:               beatertest.ResultErrWrap(fmt.Sprintf("%s: error validating sourcemap: bundle_filepath, service_name and service_version must be sent", request.MapResultIDToStatus[request.IDResponseErrorsValidate].Keyword)),
+			body:               beatertest.ResultErrWrap(fmt.Sprintf("%s: error validating sourcemap: bundle_filepath, service_name and service_version must be sent", request.MapResultIDToStatus[request.IDResponseErrorsValidate].Keyword), errorcode.Validate),
 		},
 		"shuttingDown": {
 			reporter: func(ctx context.Context, p publish.PendingReq) error {
@@ -65,14 +67,14 @@ func TestAssetHandler(t *testing.T) {
 			},
 			code: http.StatusServiceUnavailable,
 			body: beatertest.ResultErrWrap(fmt.Sprintf("%s: %s",
-				request.MapResultIDToStatus[request.IDResponseErrorsShuttingDown].Keyword, publish.ErrChannelClosed)),
+				request.MapResultIDToStatus[request.IDResponseErrorsShuttingDown].Keyword, publish.ErrChannelClosed), errorcode.ShuttingDown),
 		},
 		"queue": {
 			reporter: func(ctx context.Context, p publish.PendingReq) error {
 				return errors.New("500")
 			},
 			code: http.StatusServiceUnavailable,
-			body: beatertest.ResultErrWrap(fmt.Sprintf("%s: 500", request.MapResultIDToStatus[request.IDResponseErrorsFullQueue].Keyword)),
+			body: beatertest.ResultErrWrap(fmt.Sprintf("%s: 500", request.MapResultIDToStatus[request.IDResponseErrorsFullQueue].Keyword), errorcode.QueueFull),
 		},
 		"valid-full-payload": {
 			sourcemapInput: func() string {
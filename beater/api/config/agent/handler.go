@@ -30,10 +30,12 @@ import (
 	"github.com/elastic/beats/v7/libbeat/monitoring"
 
 	"github.com/elastic/apm-server/agentcfg"
+	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/convert"
+	"github.com/elastic/apm-server/elasticsearch"
 	"github.com/elastic/apm-server/kibana"
 )
 
@@ -46,6 +48,10 @@ const (
 	msgMethodUnsupported          = "method not supported"
 	msgNoKibanaConnection         = "unable to retrieve connection to Kibana"
 	msgServiceUnavailable         = "service unavailable"
+
+	// staleWarning is the Warning header value set when the last known
+	// agent configuration is returned because fetching from Kibana failed.
+	staleWarning = `110 - "Response is Stale"`
 )
 
 var (
@@ -62,9 +68,34 @@ var (
 )
 
 // Handler returns a request.Handler for managing agent central configuration requests.
-func Handler(client kibana.Client, config *config.AgentConfig, defaultServiceEnvironment string) request.Handler {
+//
+// If esClient is non-nil, agent configuration is also read directly from
+// Elasticsearch, and the Kibana client is not required.
+func Handler(client kibana.Client, esClient elasticsearch.Client, config *config.AgentConfig, defaultServiceEnvironment string) request.Handler {
+	fetcher := agentcfg.NewElasticsearchFetcher(client, esClient, FetcherConfig(config))
+	return FetcherHandler(fetcher, client, config, defaultServiceEnvironment)
+}
+
+// FetcherConfig returns the agentcfg.FetcherConfig for cfg.
+func FetcherConfig(cfg *config.AgentConfig) agentcfg.FetcherConfig {
+	return agentcfg.FetcherConfig{
+		CacheExpiration:         cfg.Cache.Expiration,
+		NegativeCacheExpiration: cfg.NegativeCache.Expiration,
+		NegativeCacheMaxEntries: cfg.NegativeCache.MaxEntries,
+		MaxStale:                cfg.MaxStale,
+		MaxCustomValueSize:      int(cfg.Custom.MaxValueSize),
+		MaxCustomTotalSize:      int(cfg.Custom.MaxTotalSize),
+		EncryptionKeys:          cfg.Encryption.Keys,
+		ElasticsearchPrimary:    cfg.Source == config.AgentConfigSourceElasticsearch,
+	}
+}
+
+// FetcherHandler returns a request.Handler for managing agent central
+// configuration requests, fetching agent configuration with fetcher.
+//
+// client must be the Kibana client used by fetcher, if any.
+func FetcherHandler(fetcher *agentcfg.Fetcher, client kibana.Client, config *config.AgentConfig, defaultServiceEnvironment string) request.Handler {
 	cacheControl := fmt.Sprintf("max-age=%v, must-revalidate", config.Cache.Expiration.Seconds())
-	fetcher := agentcfg.NewFetcher(client, config.Cache.Expiration)
 
 	return func(c *request.Context) {
 		// error handling
@@ -77,7 +108,7 @@ func Handler(client kibana.Client, config *config.AgentConfig, defaultServiceEnv
 			return
 		}
 
-		if valid := validateClient(c, client, c.AuthResult.Authorized); !valid {
+		if !fetcher.ElasticsearchEnabled() && !validateClient(c, client, c.AuthResult.Authorized) {
 			c.Write()
 			return
 		}
@@ -91,6 +122,16 @@ func Handler(client kibana.Client, config *config.AgentConfig, defaultServiceEnv
 		if query.Service.Environment == "" {
 			query.Service.Environment = defaultServiceEnvironment
 		}
+		if err := authorization.AuthorizeService(c.Request.Context(), query.Service.Name); err != nil {
+			var serviceErr *authorization.ServiceUnauthorizedError
+			if errors.As(err, &serviceErr) {
+				c.Result.SetWithError(request.IDResponseErrorsForbidden, err)
+			} else {
+				extractInternalError(c, err, c.AuthResult.Authorized)
+			}
+			c.Write()
+			return
+		}
 
 		result, err := fetcher.Fetch(c.Request.Context(), query)
 		if err != nil {
@@ -101,9 +142,19 @@ func Handler(client kibana.Client, config *config.AgentConfig, defaultServiceEnv
 		}
 
 		// configuration successfully fetched
-		c.Header().Set(headers.CacheControl, cacheControl)
+		if query.Authenticated {
+			// Responses to authenticated queries may hold decrypted
+			// settings, which must not be stored by shared caches.
+			c.Header().Set(headers.CacheControl, "private, "+cacheControl)
+		} else {
+			c.Header().Set(headers.CacheControl, cacheControl)
+		}
 		c.Header().Set(headers.Etag, fmt.Sprintf("\"%s\"", result.Source.Etag))
 		c.Header().Set(headers.AccessControlExposeHeaders, headers.Etag)
+		if result.Stale {
+			// Kibana could not be queried; the last known configuration is returned.
+			c.Header().Set(headers.Warning, staleWarning)
+		}
 
 		if result.Source.Etag == ifNoneMatch(c) {
 			c.Result.SetDefault(request.IDResponseValidNotModified)
@@ -154,7 +205,13 @@ func buildQuery(c *request.Context) (query agentcfg.Query, err error) {
 
 	switch r.Method {
 	case http.MethodPost:
-		err = convert.FromReader(r.Body, &query)
+		var body struct {
+			agentcfg.Query
+			CustomNamespaces []string `json:"custom_namespaces"`
+		}
+		err = convert.FromReader(r.Body, &body)
+		query = body.Query
+		query.CustomNamespaces = body.CustomNamespaces
 	case http.MethodGet:
 		params := r.URL.Query()
 		query = agentcfg.Query{
@@ -163,6 +220,9 @@ func buildQuery(c *request.Context) (query agentcfg.Query, err error) {
 				Environment: params.Get(agentcfg.ServiceEnv),
 			},
 		}
+		if namespaces := params.Get(agentcfg.CustomNamespaces); namespaces != "" {
+			query.CustomNamespaces = strings.Split(namespaces, ",")
+		}
 	default:
 		err = errors.Errorf("%s: %s", msgMethodUnsupported, r.Method)
 	}
@@ -172,6 +232,8 @@ func buildQuery(c *request.Context) (query agentcfg.Query, err error) {
 	}
 	if c.IsRum {
 		query.InsecureAgents = rumAgents
+	} else {
+		query.Authenticated = c.AuthResult.Authenticated
 	}
 	query.Etag = ifNoneMatch(c)
 	return
@@ -190,6 +252,14 @@ func extractInternalError(c *request.Context, err error, withAuth bool) {
 		body = authErrMsg(msg, agentcfg.ErrMsgReadKibanaResponse, withAuth)
 		keyword = agentcfg.ErrMsgReadKibanaResponse
 
+	case strings.Contains(msg, agentcfg.ErrMsgSendToElasticsearchFailed):
+		body = authErrMsg(msg, agentcfg.ErrMsgSendToElasticsearchFailed, withAuth)
+		keyword = agentcfg.ErrMsgSendToElasticsearchFailed
+
+	case strings.Contains(msg, agentcfg.ErrMsgReadElasticsearchResponse):
+		body = authErrMsg(msg, agentcfg.ErrMsgReadElasticsearchResponse, withAuth)
+		keyword = agentcfg.ErrMsgReadElasticsearchResponse
+
 	case strings.Contains(msg, agentcfg.ErrUnauthorized):
 		fullMsg := "APM Server is not authorized to query Kibana. " +
 			"Please configure apm-server.kibana.username and apm-server.kibana.password, " +
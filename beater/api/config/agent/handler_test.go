@@ -19,13 +19,16 @@ package agent
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -38,10 +41,14 @@ import (
 	libkibana "github.com/elastic/beats/v7/libbeat/kibana"
 
 	"github.com/elastic/apm-server/agentcfg"
+	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/errorcode"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/convert"
+	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/elasticsearch/estest"
 	"github.com/elastic/apm-server/kibana"
 	"github.com/elastic/apm-server/tests"
 )
@@ -119,8 +126,8 @@ var (
 			queryParams:            map[string]string{"service.name": "opbeans-ruby"},
 			respStatus:             http.StatusServiceUnavailable,
 			respCacheControlHeader: "max-age=300, must-revalidate",
-			respBody:               map[string]string{"error": agentcfg.ErrMsgSendToKibanaFailed},
-			respBodyToken:          map[string]string{"error": fmt.Sprintf("%s: testerror", agentcfg.ErrMsgSendToKibanaFailed)},
+			respBody:               map[string]string{"error": agentcfg.ErrMsgSendToKibanaFailed, "code": string(errorcode.Unavailable)},
+			respBodyToken:          map[string]string{"error": fmt.Sprintf("%s: testerror", agentcfg.ErrMsgSendToKibanaFailed), "code": string(errorcode.Unavailable)},
 		},
 
 		"NoConnection": {
@@ -128,8 +135,8 @@ var (
 			method:                 http.MethodGet,
 			respStatus:             http.StatusServiceUnavailable,
 			respCacheControlHeader: "max-age=300, must-revalidate",
-			respBody:               map[string]string{"error": msgNoKibanaConnection},
-			respBodyToken:          map[string]string{"error": msgNoKibanaConnection},
+			respBody:               map[string]string{"error": msgNoKibanaConnection, "code": string(errorcode.Unavailable)},
+			respBodyToken:          map[string]string{"error": msgNoKibanaConnection, "code": string(errorcode.Unavailable)},
 		},
 
 		"InvalidVersion": {
@@ -138,17 +145,17 @@ var (
 			method:                 http.MethodGet,
 			respStatus:             http.StatusServiceUnavailable,
 			respCacheControlHeader: "max-age=300, must-revalidate",
-			respBody:               map[string]string{"error": msgKibanaVersionNotCompatible},
+			respBody:               map[string]string{"error": msgKibanaVersionNotCompatible, "code": string(errorcode.Unavailable)},
 			respBodyToken: map[string]string{"error": fmt.Sprintf("%s: min version 7.5.0, "+
-				"configured version 7.2.0", msgKibanaVersionNotCompatible)},
+				"configured version 7.2.0", msgKibanaVersionNotCompatible), "code": string(errorcode.Unavailable)},
 		},
 
 		"NoService": {
 			kbClient:               tests.MockKibana(http.StatusOK, m{}, mockVersion, true),
 			method:                 http.MethodGet,
 			respStatus:             http.StatusBadRequest,
-			respBody:               map[string]string{"error": msgInvalidQuery},
-			respBodyToken:          map[string]string{"error": "service.name is required"},
+			respBody:               map[string]string{"error": msgInvalidQuery, "code": string(errorcode.InvalidQuery)},
+			respBodyToken:          map[string]string{"error": "service.name is required", "code": string(errorcode.InvalidQuery)},
 			respCacheControlHeader: "max-age=300, must-revalidate",
 		},
 
@@ -157,8 +164,8 @@ var (
 			method:                 http.MethodPut,
 			respStatus:             http.StatusMethodNotAllowed,
 			respCacheControlHeader: "max-age=300, must-revalidate",
-			respBody:               map[string]string{"error": msgMethodUnsupported},
-			respBodyToken:          map[string]string{"error": fmt.Sprintf("%s: PUT", msgMethodUnsupported)},
+			respBody:               map[string]string{"error": msgMethodUnsupported, "code": string(errorcode.MethodNotAllowed)},
+			respBodyToken:          map[string]string{"error": fmt.Sprintf("%s: PUT", msgMethodUnsupported), "code": string(errorcode.MethodNotAllowed)},
 		},
 
 		"Unauthorized": {
@@ -167,10 +174,10 @@ var (
 			queryParams:            map[string]string{"service.name": "opbeans-node"},
 			respStatus:             http.StatusServiceUnavailable,
 			respCacheControlHeader: "max-age=300, must-revalidate",
-			respBody:               map[string]string{"error": agentcfg.ErrUnauthorized},
+			respBody:               map[string]string{"error": agentcfg.ErrUnauthorized, "code": string(errorcode.Unavailable)},
 			respBodyToken: map[string]string{"error": "APM Server is not authorized to query Kibana. " +
 				"Please configure apm-server.kibana.username and apm-server.kibana.password, " +
-				"and ensure the user has the necessary privileges."},
+				"and ensure the user has the necessary privileges.", "code": string(errorcode.Unavailable)},
 		},
 	}
 )
@@ -181,7 +188,7 @@ func TestAgentConfigHandler(t *testing.T) {
 	for name, tc := range testcases {
 
 		runTest := func(t *testing.T, expectedBody map[string]string, authorized bool) {
-			h := Handler(tc.kbClient, &cfg, "")
+			h := Handler(tc.kbClient, nil, &cfg, "")
 			r := httptest.NewRequest(tc.method, target(tc.queryParams), nil)
 			for k, v := range tc.requestHeader {
 				r.Header.Set(k, v)
@@ -212,12 +219,37 @@ func TestAgentConfigHandler(t *testing.T) {
 
 func TestAgentConfigHandler_NoKibanaClient(t *testing.T) {
 	cfg := config.AgentConfig{Cache: &config.Cache{Expiration: time.Nanosecond}}
-	h := Handler(nil, &cfg, "")
+	h := Handler(nil, nil, &cfg, "")
 
 	w := sendRequest(h, httptest.NewRequest(http.MethodGet, "/config", nil))
 	assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
 }
 
+func TestAgentConfigHandler_ElasticsearchNoKibanaClient(t *testing.T) {
+	esClient, err := estest.NewElasticsearchClient(estest.NewTransport(t, http.StatusOK, m{
+		"hits": m{
+			"hits": []m{{
+				"_id": "1",
+				"_source": m{
+					"settings": m{"sampling_rate": 0.5},
+					"etag":     "abc",
+				},
+			}},
+		},
+	}))
+	require.NoError(t, err)
+	cfg := config.AgentConfig{
+		Cache:  &config.Cache{Expiration: time.Nanosecond},
+		Source: config.AgentConfigSourceElasticsearch,
+	}
+	h := Handler(nil, esClient, &cfg, "")
+
+	w := sendRequest(h, httptest.NewRequest(http.MethodGet, "/config?service.name=opbeans", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `"abc"`, w.Header().Get(headers.Etag))
+	assert.JSONEq(t, `{"sampling_rate":"0.5"}`, w.Body.String())
+}
+
 func TestAgentConfigHandler_PostOk(t *testing.T) {
 
 	kb := tests.MockKibana(http.StatusOK, m{
@@ -230,13 +262,39 @@ func TestAgentConfigHandler_PostOk(t *testing.T) {
 	}, mockVersion, true)
 
 	var cfg = config.AgentConfig{Cache: &config.Cache{Expiration: time.Nanosecond}}
-	h := Handler(kb, &cfg, "")
+	h := Handler(kb, nil, &cfg, "")
 
 	w := sendRequest(h, httptest.NewRequest(http.MethodPost, "/config", convert.ToReader(m{
 		"service": m{"name": "opbeans-node"}})))
 	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
 }
 
+func TestAgentConfigHandler_Stale(t *testing.T) {
+	kb := &failingKibanaClient{Client: tests.MockKibana(http.StatusOK, m{
+		"_id": "1",
+		"_source": m{
+			"settings": m{
+				"sampling_rate": 0.5,
+			},
+		},
+	}, mockVersion, true)}
+
+	cfg := config.AgentConfig{Cache: &config.Cache{Expiration: time.Nanosecond}, MaxStale: time.Hour}
+	h := Handler(kb, nil, &cfg, "")
+
+	w := sendRequest(h, httptest.NewRequest(http.MethodGet, "/config?service.name=opbeans-node", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Empty(t, w.Header().Get(headers.Warning))
+	body := w.Body.String()
+
+	kb.fail = true
+	time.Sleep(time.Millisecond)
+	w = sendRequest(h, httptest.NewRequest(http.MethodGet, "/config?service.name=opbeans-node", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, `110 - "Response is Stale"`, w.Header().Get(headers.Warning))
+	assert.Equal(t, body, w.Body.String())
+}
+
 func TestAgentConfigHandler_DefaultServiceEnvironment(t *testing.T) {
 	kb := &recordingKibanaClient{
 		Client: tests.MockKibana(http.StatusOK, m{
@@ -250,7 +308,7 @@ func TestAgentConfigHandler_DefaultServiceEnvironment(t *testing.T) {
 	}
 
 	var cfg = config.AgentConfig{Cache: &config.Cache{Expiration: time.Nanosecond}}
-	h := Handler(kb, &cfg, "default")
+	h := Handler(kb, nil, &cfg, "default")
 
 	sendRequest(h, httptest.NewRequest(http.MethodPost, "/config", convert.ToReader(m{"service": m{"name": "opbeans-node", "environment": "specified"}})))
 	sendRequest(h, httptest.NewRequest(http.MethodPost, "/config", convert.ToReader(m{"service": m{"name": "opbeans-node"}})))
@@ -262,6 +320,26 @@ func TestAgentConfigHandler_DefaultServiceEnvironment(t *testing.T) {
 	assert.Equal(t, `{"service":{"name":"opbeans-node","environment":"default"},"etag":""}`, string(body1))
 }
 
+func TestAgentConfigServiceUnauthorized(t *testing.T) {
+	h := getHandler("node-js")
+	r := httptest.NewRequest(http.MethodPost, "/backend", convert.ToReader(m{
+		"service": m{"name": "opbeans"}}))
+	r = r.WithContext(authorization.ContextWithAuthorization(r.Context(), serviceAuthorization{"other"}))
+	ctx, w := newRequestContext(r)
+	ctx.AuthResult.Authorized = true
+	h(ctx)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `not authorized for service \"opbeans\"`)
+
+	r = httptest.NewRequest(http.MethodPost, "/backend", convert.ToReader(m{
+		"service": m{"name": "other"}}))
+	r = r.WithContext(authorization.ContextWithAuthorization(r.Context(), serviceAuthorization{"other"}))
+	ctx, w = newRequestContext(r)
+	ctx.AuthResult.Authorized = true
+	h(ctx)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
 func TestAgentConfigRum(t *testing.T) {
 	h := getHandler("rum-js")
 	r := httptest.NewRequest(http.MethodPost, "/rum", convert.ToReader(m{
@@ -310,6 +388,101 @@ func TestAgentConfigNoLeak(t *testing.T) {
 	assert.Equal(t, map[string]string{}, actual)
 }
 
+func TestAgentConfigCustomSettings(t *testing.T) {
+	kb := tests.MockKibana(http.StatusOK, m{
+		"_id": "1",
+		"_source": m{
+			"settings": m{
+				"transaction_sample_rate": 0.5,
+				"custom.acme.feature":     "on",
+				"custom.other.feature":    "off",
+			},
+			"etag":       "123",
+			"agent_name": "java",
+		},
+	}, mockVersion, true)
+	cfg := config.AgentConfig{
+		Cache:  &config.Cache{Expiration: time.Nanosecond},
+		Custom: config.CustomSettingsConfig{MaxValueSize: 1024, MaxTotalSize: 1024},
+	}
+	h := Handler(kb, nil, &cfg, "")
+
+	for name, r := range map[string]*http.Request{
+		"get": httptest.NewRequest(http.MethodGet, "/config?service.name=opbeans&custom_namespaces=acme", nil),
+		"post": httptest.NewRequest(http.MethodPost, "/config", convert.ToReader(m{
+			"service": m{"name": "opbeans"}, "custom_namespaces": []string{"acme"}})),
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := sendRequest(h, r)
+			var actual map[string]string
+			json.Unmarshal(w.Body.Bytes(), &actual)
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.Equal(t, map[string]string{"transaction_sample_rate": "0.5", "custom.acme.feature": "on"}, actual)
+		})
+	}
+
+	// custom settings are omitted unless requested
+	w := sendRequest(h, httptest.NewRequest(http.MethodGet, "/config?service.name=opbeans", nil))
+	var actual map[string]string
+	json.Unmarshal(w.Body.Bytes(), &actual)
+	assert.Equal(t, map[string]string{"transaction_sample_rate": "0.5"}, actual)
+}
+
+func TestAgentConfigEncryptedSettings(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", 32)))
+	cipher, err := agentcfg.NewCipher([]string{key})
+	require.NoError(t, err)
+	encrypted, err := cipher.Encrypt("user:pass")
+	require.NoError(t, err)
+	kb := tests.MockKibana(http.StatusOK, m{
+		"_id": "1",
+		"_source": m{
+			"settings": m{
+				"transaction_sample_rate":  0.5,
+				"custom.proxy.credentials": encrypted,
+			},
+			"etag":       "123",
+			"agent_name": "java",
+		},
+	}, mockVersion, true)
+	cfg := config.AgentConfig{
+		Cache:      &config.Cache{Expiration: time.Second},
+		Custom:     config.CustomSettingsConfig{MaxValueSize: 1024, MaxTotalSize: 1024},
+		Encryption: config.AgentConfigEncryptionConfig{Keys: []string{key}},
+	}
+	h := Handler(kb, nil, &cfg, "")
+
+	for name, tc := range map[string]struct {
+		authResult   authorization.Result
+		cacheControl string
+		expected     map[string]string
+	}{
+		"authenticated": {
+			authResult:   authorization.Result{Authorized: true, Authenticated: true},
+			cacheControl: "private, max-age=1, must-revalidate",
+			expected:     map[string]string{"transaction_sample_rate": "0.5", "custom.proxy.credentials": "user:pass"},
+		},
+		"unauthenticated": {
+			// e.g. no secret token or API Keys are configured
+			authResult:   authorization.Result{Authorized: true},
+			cacheControl: "max-age=1, must-revalidate",
+			expected:     map[string]string{"transaction_sample_rate": "0.5"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/config?service.name=opbeans&custom_namespaces=proxy", nil)
+			ctx, w := newRequestContext(r)
+			ctx.AuthResult = tc.authResult
+			h(ctx)
+			var actual map[string]string
+			json.Unmarshal(w.Body.Bytes(), &actual)
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.cacheControl, w.Header().Get(headers.CacheControl))
+		})
+	}
+}
+
 func TestAgentConfigRateLimit(t *testing.T) {
 	h := getHandler("rum-js")
 	r := httptest.NewRequest(http.MethodPost, "/rum", convert.ToReader(m{
@@ -321,7 +494,7 @@ func TestAgentConfigRateLimit(t *testing.T) {
 	var actual map[string]string
 	json.Unmarshal(w.Body.Bytes(), &actual)
 	assert.Equal(t, http.StatusTooManyRequests, w.Code, w.Body.String())
-	assert.Equal(t, map[string]string{"error": "too many requests"}, actual)
+	assert.Equal(t, map[string]string{"error": "too many requests", "code": "ERR_RATE_LIMIT"}, actual)
 }
 
 func getHandler(agent string) request.Handler {
@@ -338,7 +511,7 @@ func getHandler(agent string) request.Handler {
 	}, mockVersion, true)
 
 	var cfg = config.AgentConfig{Cache: &config.Cache{Expiration: time.Nanosecond}}
-	return Handler(kb, &cfg, "")
+	return Handler(kb, nil, &cfg, "")
 }
 
 func TestIfNoneMatch(t *testing.T) {
@@ -362,7 +535,7 @@ func TestAgentConfigTraceContext(t *testing.T) {
 	kibanaCfg := config.KibanaConfig{Enabled: true, ClientConfig: libkibana.DefaultClientConfig()}
 	kibanaCfg.Host = "testKibana:12345"
 	client := kibana.NewConnectingClient(&kibanaCfg)
-	handler := Handler(client, &config.AgentConfig{Cache: &config.Cache{Expiration: 5 * time.Minute}}, "")
+	handler := Handler(client, nil, &config.AgentConfig{Cache: &config.Cache{Expiration: 5 * time.Minute}}, "")
 	_, spans, _ := apmtest.WithTransaction(func(ctx context.Context) {
 		// When the handler is called with a context containing
 		// a transaction, the underlying Kibana query should create a span
@@ -415,3 +588,28 @@ func (c *recordingKibanaClient) Send(ctx context.Context, method string, path st
 	c.requests = append(c.requests, req.WithContext(ctx))
 	return c.Client.Send(ctx, method, path, params, header, body)
 }
+
+type failingKibanaClient struct {
+	kibana.Client
+	fail bool
+}
+
+func (c *failingKibanaClient) Send(ctx context.Context, method string, path string, params url.Values, header http.Header, body io.Reader) (*http.Response, error) {
+	if c.fail {
+		return nil, errors.New("connection refused")
+	}
+	return c.Client.Send(ctx, method, path, params, header, body)
+}
+
+// serviceAuthorization is an authorization.Authorization
+// which is authorized for a single service.
+type serviceAuthorization struct {
+	serviceName string
+}
+
+func (a serviceAuthorization) AuthorizedFor(_ context.Context, resource elasticsearch.Resource) (authorization.Result, error) {
+	if resource == authorization.ResourceService(a.serviceName) || resource == authorization.ResourceInternal {
+		return authorization.Result{Authorized: true}, nil
+	}
+	return authorization.Result{Code: authorization.CodeInsufficientPrivileges}, nil
+}
@@ -0,0 +1,218 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package intake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/beater/authorization"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/publish"
+)
+
+// ackLevel defines at which point of processing the server responds
+// to an intake request, trading latency for delivery guarantees.
+type ackLevel string
+
+const (
+	// ackLevelValidate responds once all events have been decoded and
+	// validated; events are processed in the background, and processing
+	// errors such as a full queue are not reported to the agent.
+	ackLevelValidate ackLevel = config.AckLevelValidate
+
+	// ackLevelEnqueue responds once all events have been validated and
+	// enqueued for publishing. This is the default.
+	ackLevelEnqueue ackLevel = config.AckLevelEnqueue
+
+	// ackLevelOutput responds once all events have been acknowledged
+	// by the output.
+	ackLevelOutput ackLevel = config.AckLevelOutput
+)
+
+// AckPolicy controls the ack levels agents may request for an intake
+// route, and bounds the processing of requests acknowledged before their
+// events have been enqueued, or while waiting for the output.
+type AckPolicy struct {
+	levels  []ackLevel
+	timeout time.Duration
+
+	// background limits the number of batches processed in the
+	// background, and is shared by the policies of all routes.
+	background chan struct{}
+}
+
+// defaultAckPolicy is used by handlers without an AckPolicy,
+// allowing only the default enqueue level.
+var defaultAckPolicy = &AckPolicy{
+	levels:  []ackLevel{ackLevelEnqueue},
+	timeout: 30 * time.Second,
+}
+
+// NewAckPolicies returns the AckPolicies for backend and RUM intake routes
+// according to cfg, sharing the limit on batches processed in the background.
+func NewAckPolicies(cfg config.AckLevelConfig) (backend, rum *AckPolicy) {
+	background := make(chan struct{}, cfg.MaxBackgroundBatches)
+	newPolicy := func(levels []string) *AckPolicy {
+		p := &AckPolicy{timeout: cfg.Timeout, background: background}
+		for _, level := range levels {
+			p.levels = append(p.levels, ackLevel(level))
+		}
+		return p
+	}
+	return newPolicy(cfg.Backend), newPolicy(cfg.RUM)
+}
+
+// parseAckLevel returns the ack level requested by r, which must be
+// allowed by p. Requests without an ack level use the enqueue level.
+//
+// Anonymous requests may only use the enqueue level, regardless of p,
+// so that their events are always processed before responding.
+func (p *AckPolicy) parseAckLevel(r *http.Request, anonymous bool) (ackLevel, *stream.Error) {
+	level := ackLevel(r.Header.Get(headers.ElasticAPMAckLevel))
+	if level == "" {
+		return ackLevelEnqueue, nil
+	}
+	levels := p.levels
+	if anonymous {
+		levels = defaultAckPolicy.levels
+	}
+	allowed := make([]string, len(levels))
+	for i, l := range levels {
+		if l == level {
+			return level, nil
+		}
+		allowed[i] = string(l)
+	}
+	return "", &stream.Error{
+		Type: stream.InvalidInputErrType,
+		Message: fmt.Sprintf(
+			"invalid %s header value '%s', expected one of: %s",
+			headers.ElasticAPMAckLevel, level, strings.Join(allowed, ", "),
+		),
+	}
+}
+
+// backgroundBatchProcessor returns a model.BatchProcessor which processes
+// batches in the background, such that the agent receives a response without
+// waiting for events to be enqueued. Processing errors and panics are logged.
+//
+// The request's authorization for the services of the batch's events is
+// checked before returning, so that unauthorized events are rejected as
+// with the other ack levels. Batches are processed with the values of ctx,
+// such as the request's authorization, but not its cancellation, and are
+// cancelled after the policy's timeout. Once the limit on batches processed
+// in the background has been reached, batches are processed before
+// returning, applying backpressure to the agent.
+func (p *AckPolicy) backgroundBatchProcessor(ctx context.Context, processor model.BatchProcessor) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(_ context.Context, batch *model.Batch) error {
+		if err := authorization.AuthorizeBatch(ctx, batch); err != nil {
+			return err
+		}
+		select {
+		case p.background <- struct{}{}:
+		default:
+			return processor.ProcessBatch(ctx, batch)
+		}
+		go func() {
+			logger := logp.NewLogger(logs.Handler)
+			defer func() { <-p.background }()
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Errorw("panic processing batch", "panic", r, "error.stack_trace", string(debug.Stack()))
+				}
+			}()
+			ctx, cancel := context.WithTimeout(detachedContext{ctx}, p.timeout)
+			defer cancel()
+			if err := processor.ProcessBatch(ctx, batch); err != nil {
+				logger.Errorw("failed to process batch", "error", err)
+			}
+		}()
+		return nil
+	})
+}
+
+// detachedContext is a context.Context with the values of its parent,
+// which is never cancelled.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// processStream reads and processes events from reader, recording accepted
+// events and errors in res, and returning res once events have been processed
+// to the extent required by the ack level.
+func processStream(
+	c *request.Context,
+	processor *stream.Processor,
+	batchProcessor model.BatchProcessor,
+	acks *AckPolicy,
+	level ackLevel,
+	metadata *model.Metadata,
+	reader io.Reader,
+	res *stream.Result,
+) *stream.Result {
+	ctx := c.Request.Context()
+	var waiter *publish.ACKWaiter
+	switch level {
+	case ackLevelValidate:
+		batchProcessor = acks.backgroundBatchProcessor(ctx, batchProcessor)
+	case ackLevelOutput:
+		waiter = publish.NewACKWaiter()
+		ctx = publish.ContextWithACKWaiter(ctx, waiter)
+	}
+	processor.HandleStreamResult(ctx, c.RateLimiter, metadata, reader, batchProcessor, res)
+	if waiter != nil {
+		waitCtx, cancel := context.WithTimeout(ctx, acks.timeout)
+		err := waiter.Wait(waitCtx)
+		cancel()
+		if err != nil {
+			res.Add(&stream.Error{
+				Type:    stream.ServerErrType,
+				Message: "timed out waiting for output acknowledgement",
+			})
+		}
+	}
+	c.Intake = request.Intake{
+		AgentName:          metadata.Service.Agent.Name,
+		AgentVersion:       metadata.Service.Agent.Version,
+		ServiceName:        metadata.Service.Name,
+		ServiceEnvironment: metadata.Service.Environment,
+		Accepted:           res.Accepted,
+	}
+	return res
+}
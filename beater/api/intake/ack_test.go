@@ -0,0 +1,307 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package intake
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.elastic.co/apm/apmtest"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/outputs"
+	"github.com/elastic/beats/v7/libbeat/publisher"
+	"github.com/elastic/beats/v7/libbeat/publisher/pipeline"
+	"github.com/elastic/beats/v7/libbeat/publisher/queue"
+	"github.com/elastic/beats/v7/libbeat/publisher/queue/memqueue"
+
+	"github.com/elastic/apm-server/beater/authorization"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/tests/loader"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestIntakeHandlerAckLevelInvalid(t *testing.T) {
+	c, w := newAckLevelContext(t, context.Background(), "sometime")
+	h := Handler(stream.BackendProcessor(config.DefaultConfig()), nil, newBackendAckPolicy())
+	h(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, request.IDResponseErrorsValidate, c.Result.ID)
+	assert.EqualError(t, c.Result.Err,
+		"invalid Elastic-Apm-Ack-Level header value 'sometime', expected one of: validate, enqueue, output",
+	)
+}
+
+func TestIntakeHandlerAckLevelValidate(t *testing.T) {
+	processed := make(chan struct{})
+	batchProcessor := model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		defer close(processed)
+		return publish.ErrFull
+	})
+
+	c, w := newAckLevelContext(t, context.Background(), "validate")
+	h := Handler(stream.BackendProcessor(config.DefaultConfig()), batchProcessor, newBackendAckPolicy())
+	h(c)
+
+	// Processing errors are not reported to the agent.
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, request.IDResponseValidAccepted, c.Result.ID)
+	select {
+	case <-processed:
+	case <-time.After(10 * time.Second):
+		t.Fatal("batch not processed")
+	}
+}
+
+func TestIntakeHandlerAckLevelValidateServiceUnauthorized(t *testing.T) {
+	var processed bool
+	batchProcessor := model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		processed = true
+		return nil
+	})
+
+	// Service authorization is checked before responding,
+	// rather than when the batch is processed in the background.
+	ctx := authorization.ContextWithAuthorization(context.Background(), serviceAuthorization{})
+	c, w := newAckLevelContext(t, ctx, "validate")
+	h := Handler(stream.BackendProcessor(config.DefaultConfig()), batchProcessor, newBackendAckPolicy())
+	h(c)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, request.IDResponseErrorsForbidden, c.Result.ID)
+	assert.False(t, processed)
+}
+
+func TestIntakeHandlerAckLevelEnqueue(t *testing.T) {
+	c, w := newAckLevelContext(t, context.Background(), "enqueue")
+	h := Handler(stream.BackendProcessor(config.DefaultConfig()),
+		model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+			return publish.ErrFull
+		}),
+		newBackendAckPolicy(),
+	)
+	h(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, request.IDResponseErrorsFullQueue, c.Result.ID)
+}
+
+func TestIntakeHandlerAckLevelOutput(t *testing.T) {
+	pipeline := newAckPipeline(t)
+	publisher, err := publish.NewPublisher(
+		pipeline, apmtest.DiscardTracer, &publish.PublisherConfig{
+			TransformConfig: &transform.Config{},
+		},
+	)
+	require.NoError(t, err)
+	defer publisher.Stop(context.Background())
+	batchProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		return publisher.Send(ctx, publish.PendingReq{Transformable: batch})
+	})
+	h := Handler(stream.BackendProcessor(config.DefaultConfig()), batchProcessor, newBackendAckPolicy())
+
+	// There is no output, so the events are never acknowledged.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	c, w := newAckLevelContext(t, ctx, "output")
+	h(c)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.EqualError(t, c.Result.Err, "timed out waiting for output acknowledgement")
+
+	// Set an output which acknowledges events immediately.
+	assert.NoError(t, pipeline.OutputReloader().Reload(nil,
+		func(outputs.Observer, common.ConfigNamespace) (outputs.Group, error) {
+			return outputs.Group{Clients: []outputs.Client{&ackClient{}}}, nil
+		},
+	))
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c, w = newAckLevelContext(t, ctx, "output")
+	h(c)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.NoError(t, c.Result.Err)
+}
+
+func TestIntakeHandlerAckLevelNotAllowed(t *testing.T) {
+	_, rumAcks := NewAckPolicies(config.DefaultConfig().AckLevel)
+	for name, acks := range map[string]*AckPolicy{"rum": rumAcks, "default": nil} {
+		t.Run(name, func(t *testing.T) {
+			c, w := newAckLevelContext(t, context.Background(), "validate")
+			Handler(stream.BackendProcessor(config.DefaultConfig()), nil, acks)(c)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.EqualError(t, c.Result.Err,
+				"invalid Elastic-Apm-Ack-Level header value 'validate', expected one of: enqueue",
+			)
+		})
+	}
+}
+
+func TestIntakeHandlerAckLevelAnonymous(t *testing.T) {
+	// Anonymous requests may only use the enqueue level,
+	// even if the route allows other levels.
+	c, w := newAckLevelContext(t, context.Background(), "validate")
+	c.AuthResult = authorization.Result{Authorized: true, Anonymous: true}
+	Handler(stream.BackendProcessor(config.DefaultConfig()), nil, newBackendAckPolicy())(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.EqualError(t, c.Result.Err,
+		"invalid Elastic-Apm-Ack-Level header value 'validate', expected one of: enqueue",
+	)
+}
+
+func TestIntakeHandlerAckLevelValidateBackground(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AckLevel.MaxBackgroundBatches = 1
+	cfg.AckLevel.Timeout = time.Minute
+	acks, _ := NewAckPolicies(cfg.AckLevel)
+
+	type ctxKey struct{}
+	unblock := make(chan struct{})
+	processed := make(chan error, 1)
+	h := Handler(stream.BackendProcessor(cfg), model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		return publish.ErrFull
+	}), acks)
+
+	// The batch is processed in the background with the request's
+	// values, but not its cancellation.
+	requestCtx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+	blockingHandler := Handler(stream.BackendProcessor(cfg), model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		<-unblock
+		if _, ok := ctx.Deadline(); !ok {
+			processed <- errors.New("no deadline")
+		} else if ctx.Value(ctxKey{}) != "value" {
+			processed <- errors.New("missing request value")
+		} else {
+			processed <- ctx.Err()
+		}
+		return nil
+	}), acks)
+	c, w := newAckLevelContext(t, requestCtx, "validate")
+	blockingHandler(c)
+	cancel()
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	// Once the limit on batches processed in the background is
+	// reached, batches are processed before responding.
+	c, w = newAckLevelContext(t, context.Background(), "validate")
+	h(c)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	close(unblock)
+	select {
+	case err := <-processed:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("batch not processed")
+	}
+}
+
+func TestIntakeHandlerAckLevelValidatePanic(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AckLevel.MaxBackgroundBatches = 1
+	acks, _ := NewAckPolicies(cfg.AckLevel)
+
+	processed := make(chan struct{}, 2)
+	h := Handler(stream.BackendProcessor(cfg), model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		processed <- struct{}{}
+		panic("boom")
+	}), acks)
+
+	// Panics in the background are recovered from, releasing
+	// the slot for batches processed in the background.
+	for i := 0; i < 2; i++ {
+		c, w := newAckLevelContext(t, context.Background(), "validate")
+		h(c)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		select {
+		case <-processed:
+		case <-time.After(10 * time.Second):
+			t.Fatal("batch not processed")
+		}
+		assert.Eventually(t, func() bool { return len(acks.background) == 0 }, 10*time.Second, time.Millisecond)
+	}
+}
+
+// serviceAuthorization is an authorization.Authorization
+// which is not authorized for any service.
+type serviceAuthorization struct{}
+
+func (serviceAuthorization) AuthorizedFor(_ context.Context, resource elasticsearch.Resource) (authorization.Result, error) {
+	if strings.HasPrefix(string(resource), authorization.ServiceResourcePrefix) {
+		return authorization.Result{Code: authorization.CodeInsufficientPrivileges}, nil
+	}
+	return authorization.Result{Authorized: true}, nil
+}
+
+func newBackendAckPolicy() *AckPolicy {
+	backend, _ := NewAckPolicies(config.DefaultConfig().AckLevel)
+	return backend
+}
+
+func newAckLevelContext(t *testing.T, ctx context.Context, level string) (*request.Context, *httptest.ResponseRecorder) {
+	data, err := loader.LoadDataAsBytes("../testdata/intake-v2/errors.ndjson")
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data)).WithContext(ctx)
+	r.Header.Set(headers.ContentType, "application/x-ndjson")
+	r.Header.Set(headers.ElasticAPMAckLevel, level)
+	w := httptest.NewRecorder()
+	c := request.NewContext()
+	c.Reset(w, r)
+	return c, w
+}
+
+func newAckPipeline(t testing.TB) *pipeline.Pipeline {
+	pipeline, err := pipeline.New(
+		beat.Info{},
+		pipeline.Monitors{},
+		func(lis queue.ACKListener) (queue.Queue, error) {
+			return memqueue.NewQueue(nil, memqueue.Settings{
+				ACKListener: lis,
+				Events:      100,
+			}), nil
+		},
+		outputs.Group{},
+		pipeline.Settings{},
+	)
+	require.NoError(t, err)
+	return pipeline
+}
+
+type ackClient struct{}
+
+func (*ackClient) String() string { return "ack_client" }
+func (*ackClient) Close() error   { return nil }
+func (*ackClient) Publish(_ context.Context, batch publisher.Batch) error {
+	batch.ACK()
+	return nil
+}
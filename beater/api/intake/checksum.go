@@ -0,0 +1,113 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package intake
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/apm-server/beater/errorcode"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/decoder"
+	"github.com/elastic/apm-server/processor/stream"
+)
+
+// crc32cChecksumPrefix is the prefix of Elastic-Apm-Checksum values holding
+// the CRC32C (Castagnoli) checksum of the decompressed request body, as 8
+// hexadecimal digits.
+const crc32cChecksumPrefix = "crc32c="
+
+// checksumBodyEvents holds the maximum size of decompressed request bodies
+// with a checksum, which are buffered in memory, as a multiple of the maximum
+// event size.
+const checksumBodyEvents = 100
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// hasChecksum reports whether r carries a payload checksum, either in the
+// Elastic-Apm-Checksum header, or in a trailer of the same name declared
+// in the Trailer header.
+func hasChecksum(r *http.Request) bool {
+	if r.Header.Get(headers.ElasticAPMChecksum) != "" {
+		return true
+	}
+	_, ok := r.Trailer[headers.ElasticAPMChecksum]
+	return ok
+}
+
+// verifyChecksum reads the full decompressed request body from body, and
+// verifies it against the checksum supplied with r. The body is buffered
+// so that no events are processed unless the checksum matches, and so that
+// trailers, which are only available once the body has been read, may be
+// used. Bodies larger than maxSize bytes are rejected without being read
+// further.
+func verifyChecksum(r *http.Request, body io.Reader, maxSize int64) (io.Reader, *stream.Error) {
+	var buf bytes.Buffer
+	hash := crc32.New(crc32cTable)
+	limited := &decoder.LimitedReader{R: body, N: maxSize}
+	if _, err := io.Copy(io.MultiWriter(&buf, hash), limited); err != nil {
+		if limited.N < 0 {
+			return nil, &stream.Error{
+				Type:    stream.InputTooLargeErrType,
+				Message: fmt.Sprintf("request body with checksum exceeds the limit of %d bytes", maxSize),
+			}
+		}
+		return nil, &stream.Error{
+			Type:    stream.InvalidInputErrType,
+			Message: err.Error(),
+		}
+	}
+	value := r.Header.Get(headers.ElasticAPMChecksum)
+	if value == "" {
+		value = r.Trailer.Get(headers.ElasticAPMChecksum)
+	}
+	expected, err := parseChecksum(value)
+	if err != nil {
+		return nil, &stream.Error{
+			Type:    stream.InvalidInputErrType,
+			Message: err.Error(),
+		}
+	}
+	if actual := hash.Sum32(); actual != expected {
+		return nil, &stream.Error{
+			Type: stream.InvalidInputErrType,
+			Code: errorcode.ChecksumMismatch,
+			Message: fmt.Sprintf(
+				"checksum mismatch: expected %s%08x, computed %s%08x",
+				crc32cChecksumPrefix, expected, crc32cChecksumPrefix, actual,
+			),
+		}
+	}
+	return &buf, nil
+}
+
+// parseChecksum parses a CRC32C checksum from an Elastic-Apm-Checksum value.
+func parseChecksum(value string) (uint32, error) {
+	if strings.HasPrefix(value, crc32cChecksumPrefix) {
+		checksum, err := strconv.ParseUint(value[len(crc32cChecksumPrefix):], 16, 32)
+		if err == nil {
+			return uint32(checksum), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid %s %q: expected %s<hex>", headers.ElasticAPMChecksum, value, crc32cChecksumPrefix)
+}
@@ -0,0 +1,177 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package intake
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/tests/loader"
+)
+
+func TestIntakeHandlerChecksum(t *testing.T) {
+	data, err := loader.LoadDataAsBytes("../testdata/intake-v2/errors.ndjson")
+	require.NoError(t, err)
+	checksum := fmt.Sprintf("crc32c=%08x", crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)))
+
+	var gzipped bytes.Buffer
+	zw := gzip.NewWriter(&gzipped)
+	_, err = zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	for name, test := range map[string]struct {
+		body     []byte
+		header   string
+		trailer  string
+		encoding string
+
+		// maxEventSize, if non-zero, overrides the default max_event_size.
+		maxEventSize int
+
+		code      int
+		errorCode string
+		message   string
+	}{
+		"header": {
+			body: data, header: checksum,
+			code: http.StatusAccepted,
+		},
+		"trailer": {
+			body: data, trailer: checksum,
+			code: http.StatusAccepted,
+		},
+		"compressed": {
+			body: gzipped.Bytes(), header: checksum, encoding: "gzip",
+			code: http.StatusAccepted,
+		},
+		"mismatch": {
+			body: data, header: "crc32c=00000000",
+			code: http.StatusBadRequest, errorCode: "ERR_CHECKSUM_MISMATCH",
+			message: "checksum mismatch: expected crc32c=00000000, computed " + checksum,
+		},
+		"missing_trailer": {
+			body: data, trailer: "",
+			code: http.StatusBadRequest, errorCode: "ERR_VALIDATE",
+			message: `invalid Elastic-Apm-Checksum "": expected crc32c=<hex>`,
+		},
+		"invalid": {
+			body: data, header: "md5=abc",
+			code: http.StatusBadRequest, errorCode: "ERR_VALIDATE",
+			message: `invalid Elastic-Apm-Checksum "md5=abc": expected crc32c=<hex>`,
+		},
+		"too_large": {
+			body: gzipped.Bytes(), header: checksum, encoding: "gzip", maxEventSize: 10,
+			code: http.StatusRequestEntityTooLarge, errorCode: "ERR_TOO_LARGE",
+			message: "request body with checksum exceeds the limit of 1000 bytes",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.Body = &trailerReader{Reader: bytes.NewReader(test.body), r: r, value: test.trailer}
+			r.Header.Set(headers.ContentType, "application/x-ndjson")
+			if test.encoding != "" {
+				r.Header.Set(headers.ContentEncoding, test.encoding)
+			}
+			if test.header != "" {
+				r.Header.Set(headers.ElasticAPMChecksum, test.header)
+			} else {
+				// Declare the trailer; its value is only set once the body has been read.
+				r.Trailer = http.Header{headers.ElasticAPMChecksum: nil}
+			}
+			w := httptest.NewRecorder()
+			c := request.NewContext()
+			c.Reset(w, r)
+
+			var events int
+			batchProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+				events += batch.Len()
+				return nil
+			})
+			cfg := config.DefaultConfig()
+			if test.maxEventSize != 0 {
+				cfg.MaxEventSize = test.maxEventSize
+			}
+			Handler(stream.BackendProcessor(cfg), batchProcessor, nil)(c)
+			require.Equal(t, test.code, w.Code, w.Body.String())
+			if test.code == http.StatusAccepted {
+				assert.Equal(t, 5, events)
+				return
+			}
+			assert.Zero(t, events)
+
+			var result struct {
+				Errors []map[string]interface{}
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			require.Len(t, result.Errors, 1)
+			assert.Equal(t, test.errorCode, result.Errors[0]["code"])
+			assert.Equal(t, test.message, result.Errors[0]["message"])
+		})
+	}
+}
+
+func TestIntakeHandlerChecksumStreaming(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/?stream", nil)
+	r.ProtoMajor, r.ProtoMinor = 2, 0
+	r.Header.Set(headers.ContentType, "application/x-ndjson")
+	r.Header.Set(headers.ElasticAPMChecksum, "crc32c=00000000")
+	w := httptest.NewRecorder()
+	c := request.NewContext()
+	c.Reset(w, r)
+
+	Handler(stream.BackendProcessor(config.DefaultConfig()), nil, nil)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.EqualError(t, c.Result.Err, "checksums are not supported for streaming intake")
+}
+
+// trailerReader sets the Elastic-Apm-Checksum trailer of r once the
+// request body has been read, as net/http does for chunked requests.
+type trailerReader struct {
+	io.Reader
+	r     *http.Request
+	value string
+}
+
+func (tr *trailerReader) Read(p []byte) (int, error) {
+	n, err := tr.Reader.Read(p)
+	if err == io.EOF && tr.r.Trailer != nil {
+		tr.r.Trailer.Set(headers.ElasticAPMChecksum, tr.value)
+	}
+	return n, err
+}
+
+func (tr *trailerReader) Close() error {
+	return nil
+}
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package intake
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/stream"
+)
+
+// Forwarder is an interface for forwarding validated intake request
+// bodies to another APM Server.
+type Forwarder interface {
+	// Forward forwards body, the uncompressed ndjson body of the intake
+	// request r, returning once the request has been accepted.
+	Forward(ctx context.Context, r *http.Request, body []byte) error
+}
+
+// forwardStream decodes and validates the events read from reader, and
+// then forwards the valid lines of the request body with forwarder if
+// any events are valid. Invalid lines are not forwarded, so that the
+// receiving APM Server accepts all of the forwarded events.
+//
+// Events are not processed locally. If forwarding fails, no events are
+// reported as accepted.
+func forwardStream(
+	c *request.Context,
+	processor *stream.Processor,
+	forwarder Forwarder,
+	metadata *model.Metadata,
+	reader io.Reader,
+) *stream.Result {
+	var body bytes.Buffer
+	c.Request = c.Request.WithContext(stream.ContextWithValidLines(c.Request.Context(), &body))
+	discard := model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return nil })
+	res := processStream(c, processor, discard, defaultAckPolicy, ackLevelEnqueue, metadata, reader, &stream.Result{})
+	if res.Accepted == 0 {
+		return res
+	}
+	for _, err := range res.Errors {
+		if err.Type != stream.InvalidInputErrType && err.Type != stream.InputTooLargeErrType {
+			// The body may not have been read in full, so
+			// not all of the accepted events can be forwarded.
+			res.Accepted = 0
+			return res
+		}
+	}
+	if err := forwarder.Forward(c.Request.Context(), c.Request, body.Bytes()); err != nil {
+		res.Accepted = 0
+		res.Add(&stream.Error{
+			Type:    stream.ServerErrType,
+			Message: "failed to forward events: " + err.Error(),
+		})
+	}
+	return res
+}
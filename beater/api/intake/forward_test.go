@@ -0,0 +1,123 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package intake
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/tests/loader"
+)
+
+func TestForwardingHandler(t *testing.T) {
+	data, err := loader.LoadDataAsBytes("../testdata/intake-v2/errors.ndjson")
+	require.NoError(t, err)
+
+	var forwarder forwarderFunc = func(ctx context.Context, r *http.Request, body []byte) error {
+		assert.Equal(t, string(data), string(body))
+		return nil
+	}
+	c, w := newForwardingContext(data)
+	ForwardingHandler(stream.BackendProcessor(config.DefaultConfig()), forwarder, nil)(c)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, request.IDResponseValidAccepted, c.Result.ID)
+}
+
+func TestForwardingHandlerInvalid(t *testing.T) {
+	var forwarded bool
+	var forwarder forwarderFunc = func(ctx context.Context, r *http.Request, body []byte) error {
+		forwarded = true
+		return nil
+	}
+	c, w := newForwardingContext([]byte(`{"metadata": {}}`))
+	ForwardingHandler(stream.BackendProcessor(config.DefaultConfig()), forwarder, nil)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, request.IDResponseErrorsValidate, c.Result.ID)
+	assert.False(t, forwarded)
+}
+
+func TestForwardingHandlerPartiallyInvalid(t *testing.T) {
+	metadata := `{"metadata": {"service": {"name": "svc", "agent": {"name": "go", "version": "1.0.0"}}}}`
+	transaction := `{"transaction": {"id": "945254c567a5417e", "trace_id": "0123456789abcdef0123456789abcdef", "type": "request", "duration": 32.592981, "span_count": {"started": 0}}}`
+	invalid := `{"transaction": {"id": "945254c567a5417e"}}`
+	data := []byte(metadata + "\n" + invalid + "\n" + transaction + "\n" + `{"unknown": {}}` + "\n")
+
+	var forwarded []byte
+	var forwarder forwarderFunc = func(ctx context.Context, r *http.Request, body []byte) error {
+		forwarded = body
+		return nil
+	}
+	c, w := newForwardingContext(data)
+	ForwardingHandler(stream.BackendProcessor(config.DefaultConfig()), forwarder, nil)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"accepted":1`)
+	assert.Equal(t, metadata+"\n"+transaction+"\n", string(forwarded))
+}
+
+func TestForwardingHandlerError(t *testing.T) {
+	data, err := loader.LoadDataAsBytes("../testdata/intake-v2/errors.ndjson")
+	require.NoError(t, err)
+
+	var forwarder forwarderFunc = func(ctx context.Context, r *http.Request, body []byte) error {
+		return errors.New("connection refused")
+	}
+	c, w := newForwardingContext(data)
+	ForwardingHandler(stream.BackendProcessor(config.DefaultConfig()), forwarder, nil)(c)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, request.IDResponseErrorsInternal, c.Result.ID)
+	assert.EqualError(t, c.Result.Err, "failed to forward events: connection refused")
+	assert.Contains(t, w.Body.String(), `"accepted":0`)
+}
+
+func TestForwardingHandlerStreaming(t *testing.T) {
+	var forwarder forwarderFunc = func(ctx context.Context, r *http.Request, body []byte) error {
+		return nil
+	}
+	c, w := newForwardingContext(nil)
+	c.Request.URL.RawQuery = "stream"
+	c.Request.ProtoMajor = 2
+	ForwardingHandler(stream.BackendProcessor(config.DefaultConfig()), forwarder, nil)(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.EqualError(t, c.Result.Err, "streaming intake is not supported when forwarding")
+}
+
+type forwarderFunc func(ctx context.Context, r *http.Request, body []byte) error
+
+func (f forwarderFunc) Forward(ctx context.Context, r *http.Request, body []byte) error {
+	return f(ctx, r, body)
+}
+
+func newForwardingContext(body []byte) (*request.Context, *httptest.ResponseRecorder) {
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	r.Header.Set(headers.ContentType, "application/x-ndjson")
+	w := httptest.NewRecorder()
+	c := request.NewContext()
+	c.Reset(w, r)
+	return c, w
+}
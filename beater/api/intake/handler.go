@@ -18,33 +18,79 @@
 package intake
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/monitoring"
 
+	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/decoder"
+	"github.com/elastic/apm-server/log"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/utility"
 )
 
 var (
 	// MonitoringMap holds a mapping for request.IDs to monitoring counters
 	MonitoringMap = request.DefaultMonitoringMapForRegistry(registry)
 	registry      = monitoring.Default.NewRegistry("apm-server.server")
+
+	// streamAckInterval is the interval at which acknowledgements are written
+	// to the response body of streaming intake requests.
+	streamAckInterval = time.Second
 )
 
+// streamQueryParam is the query parameter used by agents to request periodic
+// acknowledgements while streaming events over a long-lived request.
+const streamQueryParam = "stream"
+
+// maxServiceNameLength and serviceNameRegexp restrict the service name given
+// in the Elastic-Apm-Service-Name header, like the service name of metadata.
+const maxServiceNameLength = 1024
+
+var serviceNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9 _-]+$`)
+
+// ndjsonContentType is the content type of intake request bodies, and of
+// streaming intake response bodies.
+const ndjsonContentType = "application/x-ndjson"
+
 // Handler returns a request.Handler for managing intake requests for backend and rum events.
-func Handler(processor *stream.Processor, batchProcessor model.BatchProcessor) request.Handler {
+// If acks is nil, agents may only request the default enqueue ack level.
+func Handler(processor *stream.Processor, batchProcessor model.BatchProcessor, acks *AckPolicy) request.Handler {
+	return handler(processor, batchProcessor, nil, acks)
+}
+
+// ForwardingHandler returns a request.Handler for managing intake requests
+// for backend and rum events, which are validated and then forwarded to
+// another APM Server by forwarder rather than being processed locally.
+// The ack level requested by agents, which must be allowed by acks, is
+// forwarded as well.
+func ForwardingHandler(processor *stream.Processor, forwarder Forwarder, acks *AckPolicy) request.Handler {
+	return handler(processor, nil, forwarder, acks)
+}
+
+func handler(processor *stream.Processor, batchProcessor model.BatchProcessor, forwarder Forwarder, acks *AckPolicy) request.Handler {
+	if acks == nil {
+		acks = defaultAckPolicy
+	}
 	return func(c *request.Context) {
 
-		serr := validateRequest(c.Request)
+		serr := validateRequest(c.Request, processor.IsRUM())
 		if serr != nil {
 			sendError(c, serr)
 			return
@@ -57,22 +103,286 @@ func Handler(processor *stream.Processor, batchProcessor model.BatchProcessor) r
 			return
 		}
 
+		level, serr := acks.parseAckLevel(c.Request, c.AuthResult.Anonymous)
+		if serr != nil {
+			sendError(c, serr)
+			return
+		}
+
+		streaming := hasStreamQueryParam(c.Request)
+		checksum := hasChecksum(c.Request)
+		// Streaming results is only a preference expressed in the Accept
+		// header; where results can't be streamed, they are sent in the
+		// regular response once the request body has been processed.
+		streamResults := acceptsNDJSON(c.Request) && c.Request.ProtoMajor >= 2 && !checksum && forwarder == nil
+		if streaming && c.Request.ProtoMajor < 2 {
+			// HTTP/1.x does not allow reading the request body
+			// after the response has started, so acknowledgements
+			// can only be streamed back to the agent over HTTP/2.
+			sendError(c, &stream.Error{
+				Type:    stream.InvalidInputErrType,
+				Message: "streaming intake requires HTTP/2",
+			})
+			return
+		}
+		if streaming && checksum {
+			// Checksums can only be verified once the full request
+			// body has been read, which defeats streaming.
+			sendError(c, &stream.Error{
+				Type:    stream.InvalidInputErrType,
+				Message: "checksums are not supported for streaming intake",
+			})
+			return
+		}
+		if streaming && forwarder != nil {
+			sendError(c, &stream.Error{
+				Type:    stream.InvalidInputErrType,
+				Message: "streaming intake is not supported when forwarding",
+			})
+			return
+		}
+
 		reader, serr := bodyReader(c.Request)
 		if serr != nil {
 			sendError(c, serr)
 			return
 		}
+		defer reader.Close()
+
+		var body io.Reader = reader
+		if checksum {
+			maxSize := int64(processor.MaxEventSize) * checksumBodyEvents
+			body, serr = verifyChecksum(c.Request, reader, maxSize)
+			if serr != nil && serr.Type == stream.InputTooLargeErrType {
+				sendTooLarge(c, serr)
+				return
+			} else if serr != nil {
+				sendError(c, serr)
+				return
+			}
+		}
+		if !isNDJSONContentType(c.Request) {
+			// Only RUM requests may be sent without the ndjson content type,
+			// e.g. by navigator.sendBeacon, so the payload must be sniffed.
+			body, serr = sniffNDJSON(body)
+			if serr != nil {
+				sendError(c, serr)
+				return
+			}
+		}
+
+		if processor.MissingMetadata != nil {
+			serviceName, serr := synthesizedServiceName(c)
+			if serr != nil {
+				sendError(c, serr)
+				return
+			}
+			if serviceName != "" {
+				c.Request = c.Request.WithContext(stream.ContextWithServiceName(c.Request.Context(), serviceName))
+			}
+		}
 
 		metadata := model.Metadata{
 			UserAgent: model.UserAgent{Original: c.RequestMetadata.UserAgent},
 			Client:    model.Client{IP: c.RequestMetadata.ClientIP},
 			System:    model.System{IP: c.RequestMetadata.SystemIP}}
-		res := processor.HandleStream(c.Request.Context(), c.RateLimiter, &metadata, reader, batchProcessor)
+		if forwarder != nil {
+			sendResponse(c, forwardStream(c, processor, forwarder, &metadata, body))
+			return
+		}
+		if streaming || streamResults {
+			handleStreaming(c, processor, batchProcessor, acks, level, &metadata, body, streaming, streamResults)
+			return
+		}
+		res := processStream(c, processor, batchProcessor, acks, level, &metadata, body, &stream.Result{})
 		sendResponse(c, res)
 	}
 }
 
+// IsStreamingRequest reports whether r is an HTTP/2 intake request streaming
+// acknowledgements or results back to the agent, which may be long-lived.
+func IsStreamingRequest(r *http.Request) bool {
+	return r.ProtoMajor >= 2 && (hasStreamQueryParam(r) || acceptsNDJSON(r))
+}
+
+func hasStreamQueryParam(r *http.Request) bool {
+	_, ok := r.URL.Query()[streamQueryParam]
+	return ok
+}
+
+// streamAck is written to the response body of streaming intake requests
+// every streamAckInterval, holding the number of events accepted so far.
+// When results are streamed, it is also written after each batch of
+// events is accepted.
+type streamAck struct {
+	Accepted int64 `json:"accepted"`
+}
+
+// streamError is written to the response body of intake requests which
+// stream results, for each error encountered while processing events.
+type streamError struct {
+	Error *stream.Error `json:"error"`
+}
+
+// streamSummary is written as the last line of the response body of intake
+// requests which stream results, in place of the full stream.Result.
+type streamSummary struct {
+	Accepted   int64 `json:"accepted"`
+	ErrorCount int   `json:"error_count"`
+}
+
+// handleStreaming processes an intake request, writing its outcome to the
+// response body incrementally as ndjson lines.
+//
+// The response status and headers are sent immediately. If acks is true,
+// the agent keeps the request body open and continuously streams events,
+// and the number of accepted events is periodically written to the
+// response. If results is true, the number of accepted events is written
+// after each batch, and errors are written as they are encountered rather
+// than being collected, giving agents sending very large batches early
+// feedback without the server holding all errors in memory.
+//
+// Once the request body has been consumed, the final stream.Result, or a
+// streamSummary if results are streamed, is written as the last line of
+// the response body.
+func handleStreaming(
+	c *request.Context,
+	processor *stream.Processor,
+	batchProcessor model.BatchProcessor,
+	ackPolicy *AckPolicy,
+	level ackLevel,
+	metadata *model.Metadata,
+	reader io.Reader,
+	acks, results bool,
+) {
+	var accepted int64
+	countingProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		if err := batchProcessor.ProcessBatch(ctx, batch); err != nil {
+			return err
+		}
+		atomic.AddInt64(&accepted, int64(batch.Len()))
+		return nil
+	})
+
+	c.Header().Set(headers.ContentType, ndjsonContentType)
+	w := newNDJSONWriter(c)
+	w.writeLine(streamAck{})
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	if acks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(streamAckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					w.writeLine(streamAck{Accepted: atomic.LoadInt64(&accepted)})
+				}
+			}
+		}()
+	}
+
+	res := &stream.Result{}
+	var listener *resultsListener
+	if results {
+		listener = &resultsListener{w: w}
+		res = stream.NewResult(listener)
+	}
+	processStream(c, processor, countingProcessor, ackPolicy, level, metadata, reader, res)
+	close(done)
+	wg.Wait()
+	if listener != nil {
+		w.writeLine(streamSummary{Accepted: listener.accepted, ErrorCount: listener.errors})
+	} else {
+		w.writeLine(res)
+	}
+
+	// The response status has already been sent, so errors are only
+	// reported in the response body; record the result for monitoring.
+	_, id := resultStatus(res)
+	var err error
+	if errMsg := res.Error(); errMsg != "" {
+		err = errors.New(errMsg)
+	}
+	c.Result.Set(id, http.StatusAccepted, request.MapResultIDToStatus[id].Keyword, nil, err)
+}
+
+// resultsListener is a stream.ResultListener which writes accepted event
+// counts and errors to a streaming response as they are added.
+type resultsListener struct {
+	w        *ndjsonWriter
+	accepted int64
+	errors   int
+}
+
+func (l *resultsListener) AddedAccepted(n int) {
+	l.accepted += int64(n)
+	l.w.writeLine(streamAck{Accepted: l.accepted})
+}
+
+func (l *resultsListener) AddedError(err *stream.Error) {
+	l.errors++
+	l.w.writeLine(streamError{Error: err})
+}
+
+// ndjsonWriter writes ndjson lines to a streaming response, flushing the
+// response after each line. It is safe for concurrent use.
+type ndjsonWriter struct {
+	mu      sync.Mutex
+	c       *request.Context
+	enc     *json.Encoder
+	flusher http.Flusher
+}
+
+func newNDJSONWriter(c *request.Context) *ndjsonWriter {
+	w := c.Stream(http.StatusAccepted)
+	flusher, _ := w.(http.Flusher)
+	return &ndjsonWriter{c: c, enc: json.NewEncoder(w), flusher: flusher}
+}
+
+func (w *ndjsonWriter) writeLine(v interface{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.enc.Encode(v); err != nil {
+		if w.c.Logger != nil {
+			w.c.Logger.Errorw("write error", "error", err)
+		}
+		return
+	}
+	if w.flusher != nil {
+		w.flusher.Flush()
+	}
+}
+
 func sendResponse(c *request.Context, sr *stream.Result) {
+	code, id := resultStatus(sr)
+
+	var body interface{}
+	if code >= http.StatusBadRequest {
+		// this signals to the client that we're closing the connection
+		// but also signals to http.Server that it should close it:
+		// https://golang.org/src/net/http/server.go#L1254
+		c.Header().Add(headers.Connection, "Close")
+		body = sr
+	} else if _, ok := c.Request.URL.Query()["verbose"]; ok {
+		body = sr
+	}
+	var err error
+	if errMsg := sr.Error(); errMsg != "" {
+		err = errors.New(errMsg)
+	}
+	c.Result.Set(id, code, request.MapResultIDToStatus[id].Keyword, body, err)
+	c.Write()
+}
+
+// resultStatus returns the HTTP status code and request.ResultID
+// corresponding to the most severe error in sr.
+func resultStatus(sr *stream.Result) (int, request.ResultID) {
 	code := http.StatusAccepted
 	id := request.IDResponseValidAccepted
 	set := func(c int, i request.ResultID) {
@@ -95,6 +405,8 @@ L:
 			set(request.MapResultIDToStatus[request.IDResponseErrorsValidate].Code, request.IDResponseErrorsValidate)
 		case stream.RateLimitErrType:
 			set(request.MapResultIDToStatus[request.IDResponseErrorsRateLimit].Code, request.IDResponseErrorsRateLimit)
+		case stream.ForbiddenErrType:
+			set(request.MapResultIDToStatus[request.IDResponseErrorsForbidden].Code, request.IDResponseErrorsForbidden)
 		case stream.QueueFullErrType:
 			set(request.MapResultIDToStatus[request.IDResponseErrorsFullQueue].Code, request.IDResponseErrorsFullQueue)
 			break L
@@ -105,31 +417,62 @@ L:
 			set(request.MapResultIDToStatus[request.IDResponseErrorsInternal].Code, request.IDResponseErrorsInternal)
 		}
 	}
-
-	var body interface{}
-	if code >= http.StatusBadRequest {
-		// this signals to the client that we're closing the connection
-		// but also signals to http.Server that it should close it:
-		// https://golang.org/src/net/http/server.go#L1254
-		c.Header().Add(headers.Connection, "Close")
-		body = sr
-	} else if _, ok := c.Request.URL.Query()["verbose"]; ok {
-		body = sr
-	}
-	var err error
-	if errMsg := sr.Error(); errMsg != "" {
-		err = errors.New(errMsg)
-	}
-	c.Result.Set(id, code, request.MapResultIDToStatus[id].Keyword, body, err)
-	c.Write()
+	return code, id
 }
+
 func sendError(c *request.Context, err *stream.Error) {
 	sr := stream.Result{}
 	sr.Add(err)
 	sendResponse(c, &sr)
 }
 
-func validateRequest(r *http.Request) *stream.Error {
+// sendTooLarge rejects a request whose body is too large as a whole with
+// 413 Request Entity Too Large. Unlike oversized events, which are reported
+// with 400 Bad Request for backwards compatibility, this happens before any
+// events are decoded.
+func sendTooLarge(c *request.Context, err *stream.Error) {
+	sr := stream.Result{}
+	sr.Add(err)
+	c.Header().Add(headers.Connection, "Close")
+	id := request.IDResponseErrorsRequestTooLarge
+	c.Result.Set(id, http.StatusRequestEntityTooLarge, request.MapResultIDToStatus[id].Keyword, &sr, errors.New(sr.Error()))
+	c.Write()
+}
+
+// synthesizedServiceName returns the service name for metadata synthesized
+// for the request's stream: the service name given in the request header,
+// validated like the service name of metadata, or else the name of the
+// service to which the request's API Key is restricted.
+func synthesizedServiceName(c *request.Context) (string, *stream.Error) {
+	if serviceName := c.Request.Header.Get(headers.ElasticAPMServiceName); serviceName != "" {
+		if len(serviceName) > maxServiceNameLength || !serviceNameRegexp.MatchString(serviceName) {
+			return "", &stream.Error{
+				Type: stream.InvalidInputErrType,
+				Message: fmt.Sprintf("invalid %s header: expected at most %d characters matching %s",
+					headers.ElasticAPMServiceName, maxServiceNameLength, serviceNameRegexp),
+			}
+		}
+		return serviceName, nil
+	}
+	serviceName, err := authorization.ServiceName(c.Request.Context())
+	if err != nil {
+		// The stream may still hold valid metadata, so the
+		// request is not rejected for failing to find the
+		// API Key's service.
+		logger := c.Logger
+		if logger == nil {
+			logger = logp.NewLogger(logs.Handler)
+		}
+		logger.With(logp.Error(err)).Warn("failed to get service name of API Key")
+		return "", nil
+	}
+	return serviceName, nil
+}
+
+// validateRequest validates the method and content type of r. If allowPlainText
+// is true, requests with a text/plain or missing content type are accepted, as
+// sent by browsers using navigator.sendBeacon or no-cors fetch requests.
+func validateRequest(r *http.Request, allowPlainText bool) *stream.Error {
 	if r.Method != http.MethodPost {
 		return &stream.Error{
 			Type:    stream.MethodForbiddenErrType,
@@ -137,7 +480,7 @@ func validateRequest(r *http.Request) *stream.Error {
 		}
 	}
 
-	if !strings.Contains(r.Header.Get(headers.ContentType), "application/x-ndjson") {
+	if !isNDJSONContentType(r) && !(allowPlainText && isPlainTextContentType(r)) {
 		return &stream.Error{
 			Type:    stream.InvalidInputErrType,
 			Message: fmt.Sprintf("invalid content type: '%s'", r.Header.Get(headers.ContentType)),
@@ -146,7 +489,48 @@ func validateRequest(r *http.Request) *stream.Error {
 	return nil
 }
 
+func isNDJSONContentType(r *http.Request) bool {
+	return strings.Contains(r.Header.Get(headers.ContentType), ndjsonContentType)
+}
+
+// acceptsNDJSON reports whether the agent requested the results of r to be
+// streamed as ndjson, by including it in the Accept header.
+func acceptsNDJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get(headers.Accept), ndjsonContentType)
+}
+
+func isPlainTextContentType(r *http.Request) bool {
+	contentType := r.Header.Get(headers.ContentType)
+	return contentType == "" || strings.HasPrefix(contentType, "text/plain")
+}
+
+// sniffNDJSONSize is the maximum number of bytes read by sniffNDJSON.
+const sniffNDJSONSize = 512
+
+// sniffNDJSON reads the beginning of r to check that it looks like ndjson,
+// i.e. that the first non-whitespace character opens a JSON object, and
+// returns a reader which yields the full content of r.
+func sniffNDJSON(r io.Reader) (io.Reader, *stream.Error) {
+	prefix := make([]byte, sniffNDJSONSize)
+	n, err := io.ReadFull(r, prefix)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, &stream.Error{
+			Type:    stream.InvalidInputErrType,
+			Message: err.Error(),
+		}
+	}
+	prefix = prefix[:n]
+	if trimmed := bytes.TrimLeft(prefix, " \t\r\n"); len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, &stream.Error{
+			Type:    stream.InvalidInputErrType,
+			Message: "invalid content: expected ndjson",
+		}
+	}
+	return io.MultiReader(bytes.NewReader(prefix), r), nil
+}
+
 func bodyReader(r *http.Request) (io.ReadCloser, *stream.Error) {
+	start := time.Now()
 	reader, err := decoder.CompressedRequestReader(r)
 	if err != nil {
 		return nil, &stream.Error{
@@ -154,5 +538,24 @@ func bodyReader(r *http.Request) (io.ReadCloser, *stream.Error) {
 			Message: err.Error(),
 		}
 	}
+	timings := utility.PhaseTimingsFromContext(r.Context())
+	if timings != nil && r.Header.Get(headers.ContentEncoding) != "" {
+		timings.Add(utility.PhaseDecompress, time.Since(start))
+		reader = &decompressTimingReader{ReadCloser: reader, timings: timings}
+	}
 	return reader, nil
 }
+
+// decompressTimingReader records the time spent reading from a
+// decompressing reader against the decompress phase.
+type decompressTimingReader struct {
+	io.ReadCloser
+	timings *utility.PhaseTimings
+}
+
+func (r *decompressTimingReader) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := r.ReadCloser.Read(p)
+	r.timings.Add(utility.PhaseDecompress, time.Since(start))
+	return n, err
+}
@@ -22,10 +22,14 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/elastic/apm-server/approvaltest"
 	"github.com/elastic/apm-server/beater/api/ratelimit"
@@ -34,12 +38,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/elasticsearch"
 	"github.com/elastic/apm-server/processor/stream"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/tests/loader"
+	"github.com/elastic/apm-server/utility"
 )
 
 func TestIntakeHandler(t *testing.T) {
@@ -114,6 +121,15 @@ func TestIntakeHandler(t *testing.T) {
 				return publish.ErrFull
 			}),
 			code: http.StatusServiceUnavailable, id: request.IDResponseErrorsFullQueue},
+		"ServiceUnauthorized": {
+			path: "errors.ndjson",
+			batchProcessor: model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+				return &authorization.ServiceUnauthorizedError{
+					ServiceName: "1234_service-12a3",
+					Reason:      "API Key is not scoped to this service",
+				}
+			}),
+			code: http.StatusForbidden, id: request.IDResponseErrorsForbidden},
 		"InvalidEvent": {
 			path: "invalid-event.ndjson",
 			code: http.StatusBadRequest, id: request.IDResponseErrorsValidate},
@@ -145,7 +161,7 @@ func TestIntakeHandler(t *testing.T) {
 				tc.c.RateLimiter = tc.rateLimit.ForIP(&http.Request{})
 			}
 			// call handler
-			h := Handler(tc.processor, tc.batchProcessor)
+			h := Handler(tc.processor, tc.batchProcessor, nil)
 			h(tc.c)
 
 			require.Equal(t, string(tc.id), string(tc.c.Result.ID))
@@ -227,3 +243,331 @@ func compressedRequest(t *testing.T, compressionType string, compressPayload boo
 	req.Header.Set(headers.ContentEncoding, compressionType)
 	return req
 }
+
+func TestIntakeHandlerPhaseTimings(t *testing.T) {
+	tc := testcaseIntakeHandler{r: compressedRequest(t, "gzip", true)}
+	tc.setup(t)
+	tc.c.Request = tc.c.Request.WithContext(utility.ContextWithPhaseTimings(tc.c.Request.Context(), &tc.c.PhaseTimings))
+
+	Handler(tc.processor, tc.batchProcessor, nil)(tc.c)
+	require.Equal(t, http.StatusAccepted, tc.w.Code)
+	assert.NotZero(t, tc.c.PhaseTimings.Duration(utility.PhaseDecompress))
+	assert.NotZero(t, tc.c.PhaseTimings.Duration(utility.PhaseDecode))
+	assert.NotZero(t, tc.c.PhaseTimings.Duration(utility.PhasePublish))
+}
+
+func TestIntakeHandlerStreaming(t *testing.T) {
+	defer func(interval time.Duration) { streamAckInterval = interval }(streamAckInterval)
+	streamAckInterval = 10 * time.Millisecond
+
+	data, err := loader.LoadDataAsBytes("../testdata/intake-v2/errors.ndjson")
+	require.NoError(t, err)
+	lines := bytes.SplitN(data, []byte("\n"), 3)
+	metadata, event := lines[0], lines[1]
+
+	pr, pw := io.Pipe()
+	r := httptest.NewRequest(http.MethodPost, "/?stream", pr)
+	r.ProtoMajor, r.ProtoMinor = 2, 0
+	r.Header.Set(headers.ContentType, "application/x-ndjson")
+	w := newFlushRecorder()
+	c := request.NewContext()
+	c.Reset(w, r)
+
+	h := Handler(stream.BackendProcessor(config.DefaultConfig()),
+		model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return nil }),
+		nil,
+	)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h(c)
+	}()
+
+	assert.Equal(t, `{"accepted":0}`, <-w.lines)
+	go func() {
+		pw.Write(append(metadata, '\n'))
+		for i := 0; i < 10; i++ {
+			pw.Write(append(event, '\n'))
+		}
+	}()
+	// Wait for an acknowledgement of the first full batch of
+	// events, while the request body is still open.
+	for line := range w.lines {
+		if line == `{"accepted":10}` {
+			break
+		}
+		assert.Equal(t, `{"accepted":0}`, line)
+	}
+	pw.Close()
+	<-done
+	close(w.lines)
+
+	assert.Equal(t, http.StatusAccepted, w.code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get(headers.ContentType))
+	assert.Equal(t, request.IDResponseValidAccepted, c.Result.ID)
+	assert.NoError(t, c.Result.Err)
+
+	var last string
+	for line := range w.lines {
+		last = line
+	}
+	assert.Equal(t, `{"accepted":10}`, last)
+}
+
+func TestIntakeHandlerStreamingResults(t *testing.T) {
+	data, err := loader.LoadDataAsBytes("../testdata/intake-v2/errors.ndjson")
+	require.NoError(t, err)
+	lines := bytes.SplitN(data, []byte("\n"), 3)
+	metadata, event := lines[0], lines[1]
+
+	var body bytes.Buffer
+	body.Write(append(metadata, '\n'))
+	for i := 0; i < 10; i++ {
+		body.Write(append(event, '\n'))
+	}
+	body.WriteString("{\"error\": {\"id\": }}\n")
+	for i := 0; i < 2; i++ {
+		body.Write(append(event, '\n'))
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", &body)
+	r.ProtoMajor, r.ProtoMinor = 2, 0
+	r.Header.Set(headers.ContentType, "application/x-ndjson")
+	r.Header.Set(headers.Accept, "application/x-ndjson")
+	w := newFlushRecorder()
+	c := request.NewContext()
+	c.Reset(w, r)
+
+	h := Handler(stream.BackendProcessor(config.DefaultConfig()),
+		model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return nil }),
+		nil,
+	)
+	h(c)
+	close(w.lines)
+
+	assert.Equal(t, http.StatusAccepted, w.code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get(headers.ContentType))
+	assert.Equal(t, request.IDResponseErrorsValidate, c.Result.ID)
+	assert.Error(t, c.Result.Err)
+
+	var responseLines []string
+	for line := range w.lines {
+		responseLines = append(responseLines, line)
+	}
+	require.Len(t, responseLines, 5)
+	assert.Equal(t, `{"accepted":0}`, responseLines[0])
+	assert.Equal(t, `{"accepted":10}`, responseLines[1])
+	var errLine struct {
+		Error map[string]interface{} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(responseLines[2]), &errLine))
+	assert.Equal(t, `{"error": {"id": }}`, errLine.Error["document"])
+	assert.Equal(t, "ERR_DECODE", errLine.Error["code"])
+	assert.Contains(t, errLine.Error["message"], "data read error")
+	assert.Equal(t, `{"accepted":12}`, responseLines[3])
+	assert.Equal(t, `{"accepted":12,"error_count":1}`, responseLines[4])
+}
+
+func TestIntakeHandlerStreamingHTTP1(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/?stream", nil)
+	r.Header.Set(headers.ContentType, "application/x-ndjson")
+	w := httptest.NewRecorder()
+	c := request.NewContext()
+	c.Reset(w, r)
+
+	h := Handler(stream.BackendProcessor(config.DefaultConfig()), nil, nil)
+	h(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, request.IDResponseErrorsValidate, c.Result.ID)
+	assert.EqualError(t, c.Result.Err, "streaming intake requires HTTP/2")
+
+}
+
+func TestIntakeHandlerStreamingResultsHTTP1(t *testing.T) {
+	data, err := loader.LoadDataAsBytes("../testdata/intake-v2/errors.ndjson")
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
+	r.Header.Set(headers.ContentType, "application/x-ndjson")
+	r.Header.Set(headers.Accept, "application/x-ndjson")
+	w := httptest.NewRecorder()
+	c := request.NewContext()
+	c.Reset(w, r)
+
+	// Results can't be streamed over HTTP/1.x, so they are
+	// sent in the regular response instead.
+	h := Handler(stream.BackendProcessor(config.DefaultConfig()),
+		model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return nil }),
+		nil,
+	)
+	h(c)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, request.IDResponseValidAccepted, c.Result.ID)
+	assert.NotEqual(t, "application/x-ndjson", w.Header().Get(headers.ContentType))
+}
+
+// flushRecorder is an http.ResponseWriter which sends each line written
+// to the response body over a channel when flushed.
+type flushRecorder struct {
+	header http.Header
+	code   int
+	buf    bytes.Buffer
+	lines  chan string
+}
+
+func newFlushRecorder() *flushRecorder {
+	return &flushRecorder{header: make(http.Header), lines: make(chan string, 1000)}
+}
+
+func (w *flushRecorder) Header() http.Header        { return w.header }
+func (w *flushRecorder) WriteHeader(statusCode int) { w.code = statusCode }
+func (w *flushRecorder) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *flushRecorder) Flush() {
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			return
+		}
+		w.lines <- strings.TrimSpace(line)
+	}
+}
+
+func TestIntakeHandlerPlainText(t *testing.T) {
+	data, err := loader.LoadDataAsBytes("../testdata/intake-v2/transactions_spans_rum.ndjson")
+	require.NoError(t, err)
+	cfg := config.DefaultConfig()
+
+	for name, test := range map[string]struct {
+		processor   *stream.Processor
+		contentType string
+		body        []byte
+		code        int
+	}{
+		"rum_text_plain": {
+			processor:   stream.RUMV2Processor(cfg),
+			contentType: "text/plain;charset=UTF-8",
+			body:        data,
+			code:        http.StatusAccepted,
+		},
+		"rum_no_content_type": {
+			processor: stream.RUMV2Processor(cfg),
+			body:      data,
+			code:      http.StatusAccepted,
+		},
+		"rum_text_plain_not_ndjson": {
+			processor:   stream.RUMV2Processor(cfg),
+			contentType: "text/plain",
+			body:        []byte("hello, world"),
+			code:        http.StatusBadRequest,
+		},
+		"rum_text_html": {
+			processor:   stream.RUMV2Processor(cfg),
+			contentType: "text/html",
+			body:        data,
+			code:        http.StatusBadRequest,
+		},
+		"backend_text_plain": {
+			processor:   stream.BackendProcessor(cfg),
+			contentType: "text/plain",
+			body:        data,
+			code:        http.StatusBadRequest,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(test.body))
+			if test.contentType != "" {
+				r.Header.Set(headers.ContentType, test.contentType)
+			}
+			w := httptest.NewRecorder()
+			c := request.NewContext()
+			c.Reset(w, r)
+
+			batchProcessor := model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return nil })
+			Handler(test.processor, batchProcessor, nil)(c)
+			assert.Equal(t, test.code, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestIntakeHandlerMissingMetadataServiceName(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MissingMetadata.Action = config.MissingMetadataActionSynthesize
+
+	body := `{"error": {"id": "1", "log": {"message": "one"}}}`
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set(headers.ContentType, "application/x-ndjson")
+	r.Header.Set(headers.ElasticAPMServiceName, "thermostat")
+	w := httptest.NewRecorder()
+	c := request.NewContext()
+	c.Reset(w, r)
+
+	var events []*model.Error
+	batchProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		events = append(events, batch.Errors...)
+		return nil
+	})
+	Handler(stream.BackendProcessor(cfg), batchProcessor, nil)(c)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.Len(t, events, 1)
+	assert.Equal(t, "thermostat", events[0].Metadata.Service.Name)
+}
+
+func TestIntakeHandlerMissingMetadataInvalidServiceName(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MissingMetadata.Action = config.MissingMetadataActionSynthesize
+
+	for _, serviceName := range []string{"thermostat/1", "thermostat\n", strings.Repeat("a", 1025)} {
+		body := `{"error": {"id": "1", "log": {"message": "one"}}}`
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set(headers.ContentType, "application/x-ndjson")
+		r.Header.Set(headers.ElasticAPMServiceName, serviceName)
+		w := httptest.NewRecorder()
+		c := request.NewContext()
+		c.Reset(w, r)
+
+		batchProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+			t.Fatal("unexpected batch")
+			return nil
+		})
+		Handler(stream.BackendProcessor(cfg), batchProcessor, nil)(c)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid Elastic-Apm-Service-Name header")
+	}
+}
+
+func TestIntakeHandlerMissingMetadataAPIKeyServiceName(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.MissingMetadata.Action = config.MissingMetadataActionSynthesize
+
+	body := `{"error": {"id": "1", "log": {"message": "one"}}}`
+	ctx := authorization.ContextWithAuthorization(context.Background(), serviceNameAuthorization("thermostat"))
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)).WithContext(ctx)
+	r.Header.Set(headers.ContentType, "application/x-ndjson")
+	w := httptest.NewRecorder()
+	c := request.NewContext()
+	c.Reset(w, r)
+
+	var events []*model.Error
+	batchProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		events = append(events, batch.Errors...)
+		return nil
+	})
+	Handler(stream.BackendProcessor(cfg), batchProcessor, nil)(c)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.Len(t, events, 1)
+	assert.Equal(t, "thermostat", events[0].Metadata.Service.Name)
+}
+
+// serviceNameAuthorization is an authorization.Authorization
+// restricted to the named service.
+type serviceNameAuthorization string
+
+func (serviceNameAuthorization) AuthorizedFor(context.Context, elasticsearch.Resource) (authorization.Result, error) {
+	return authorization.Result{Authorized: true}, nil
+}
+
+func (a serviceNameAuthorization) ServiceName(context.Context) (string, error) {
+	return string(a), nil
+}
@@ -0,0 +1,83 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package intaketelemetry
+
+import (
+	"net/http"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/middleware"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+var (
+	// MonitoringMap holds a mapping for request.IDs to monitoring counters
+	MonitoringMap = request.DefaultMonitoringMapForRegistry(registry)
+	registry      = monitoring.Default.NewRegistry("apm-server.intake_telemetry")
+)
+
+// Query parameters for restricting the report to matching agents.
+const (
+	serviceNameQueryParam = "service.name"
+	tlsVersionQueryParam  = "tls_version"
+	compressionQueryParam = "compression"
+)
+
+// Handler returns a request.Handler for reporting the statistics tracked by tracker.
+//
+// Only GET requests are accepted. The report may be restricted with the
+// service.name, tls_version and compression query parameters, e.g.
+// `?tls_version=1.0` reports the agents which sent requests using TLS 1.0.
+func Handler(tracker *Tracker) request.Handler {
+	return func(c *request.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Result.SetDefault(request.IDResponseErrorsMethodNotAllowed)
+			c.Write()
+			return
+		}
+		report := Report{Agents: []AgentReport{}}
+		if tracker != nil {
+			query := c.Request.URL.Query()
+			report = tracker.Report(Filter{
+				ServiceName: query.Get(serviceNameQueryParam),
+				TLSVersion:  query.Get(tlsVersionQueryParam),
+				Compression: query.Get(compressionQueryParam),
+			})
+		}
+		c.Result.SetWithBody(request.IDResponseValidOK, report)
+		c.Write()
+	}
+}
+
+// Middleware returns a middleware.Middleware which records intake
+// requests with tracker, after they have been handled.
+//
+// Middleware should precede the authorization middleware, so that
+// requests rejected for missing or invalid credentials are recorded.
+// CORS preflight requests are not recorded.
+func Middleware(tracker *Tracker) middleware.Middleware {
+	return func(h request.Handler) (request.Handler, error) {
+		return func(c *request.Context) {
+			h(c)
+			if c.Request.Method != http.MethodOptions {
+				tracker.RecordRequest(c)
+			}
+		}, nil
+	}
+}
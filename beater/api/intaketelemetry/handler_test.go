@@ -0,0 +1,84 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package intaketelemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestHandler(t *testing.T) {
+	tracker := NewTracker(config.DefaultConfig().IntakeTelemetry)
+	recordRequest(tracker, request.Intake{ServiceName: "opbeans", Accepted: 3}, "gzip", nil, http.StatusAccepted)
+	recordRequest(tracker, request.Intake{ServiceName: "other", Accepted: 1}, "", nil, http.StatusAccepted)
+
+	c := request.NewContext()
+	w := httptest.NewRecorder()
+	c.Reset(w, httptest.NewRequest(http.MethodGet, "/?compression=gzip", nil))
+	Handler(tracker)(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	require.Len(t, report.Agents, 1)
+	assert.Equal(t, Agent{ServiceName: "opbeans", ClientIP: "10.0.0.1"}, report.Agents[0].Agent)
+	assert.Equal(t, int64(3), report.Agents[0].Events)
+	assert.Equal(t, map[string]int64{"gzip": 1}, report.Agents[0].Compression)
+	assert.Equal(t, map[string]int64{"none": 1}, report.Agents[0].TLSVersion)
+}
+
+func TestHandlerMethodNotAllowed(t *testing.T) {
+	c := request.NewContext()
+	w := httptest.NewRecorder()
+	c.Reset(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	Handler(NewTracker(config.DefaultConfig().IntakeTelemetry))(c)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestMiddleware(t *testing.T) {
+	tracker := NewTracker(config.DefaultConfig().IntakeTelemetry)
+	h, err := Middleware(tracker)(func(c *request.Context) {
+		c.Intake = request.Intake{ServiceName: "opbeans", AgentName: "java", Accepted: 2}
+		c.Result.SetDefault(request.IDResponseValidAccepted)
+	})
+	require.NoError(t, err)
+
+	c := request.NewContext()
+	c.Reset(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	h(c)
+
+	report := tracker.Report(Filter{})
+	require.Len(t, report.Agents, 1)
+	assert.Equal(t, Agent{ServiceName: "opbeans", AgentName: "java"}, report.Agents[0].Agent)
+	assert.Equal(t, int64(1), report.Agents[0].Requests)
+	assert.Equal(t, int64(2), report.Agents[0].Events)
+	assert.Zero(t, report.Agents[0].Errors)
+
+	// CORS preflight requests are not recorded.
+	c.Reset(httptest.NewRecorder(), httptest.NewRequest(http.MethodOptions, "/", nil))
+	h(c)
+	assert.Equal(t, int64(1), tracker.Report(Filter{}).Agents[0].Requests)
+}
@@ -0,0 +1,281 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package intaketelemetry tracks statistics of intake requests per agent,
+// such as the compression codecs and TLS versions used, and reports them
+// so that outdated or misconfigured agents can be found.
+package intaketelemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+// none is recorded for requests sent without compression or TLS.
+const none = "none"
+
+// Agent identifies the agent, and the client, for which requests are tracked.
+//
+// The client IP is not tracked for RUM requests, so that each browser
+// does not take up a slot of its own.
+type Agent struct {
+	ServiceName        string `json:"service.name,omitempty"`
+	ServiceEnvironment string `json:"service.environment,omitempty"`
+	AgentName          string `json:"agent.name,omitempty"`
+	AgentVersion       string `json:"agent.version,omitempty"`
+	ClientIP           string `json:"client.ip,omitempty"`
+}
+
+// Stats holds the statistics of requests sent by an agent.
+type Stats struct {
+	Requests int64 `json:"requests"`
+	Events   int64 `json:"events"`
+
+	// Connections holds the number of connections over which the
+	// agent sent its first request on the connection.
+	Connections int64 `json:"connections"`
+
+	// Errors holds the number of requests responded to with an error.
+	Errors int64 `json:"errors"`
+
+	// Compression holds the number of requests per Content-Encoding.
+	Compression map[string]int64 `json:"compression"`
+
+	// TLSVersion holds the number of connections per TLS version.
+	TLSVersion map[string]int64 `json:"tls_version"`
+
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// AgentReport holds the statistics of requests sent by an agent.
+type AgentReport struct {
+	Agent Agent `json:"agent"`
+	Stats
+}
+
+// Report holds the statistics of requests sent by all tracked agents.
+type Report struct {
+	// Since holds the start of the reporting window, or the time from
+	// which requests have been tracked if later.
+	Since  time.Time     `json:"since"`
+	Agents []AgentReport `json:"agents"`
+}
+
+// Filter restricts a report to the agents matching all non-empty fields.
+type Filter struct {
+	ServiceName string
+	TLSVersion  string
+	Compression string
+}
+
+// Tracker tracks statistics of intake requests per agent.
+type Tracker struct {
+	maxAgents int
+	window    time.Duration
+	now       func() time.Time
+	since     time.Time
+
+	mu     sync.Mutex
+	agents map[Agent]*Stats
+}
+
+// NewTracker returns a new Tracker with the given configuration.
+func NewTracker(cfg config.IntakeTelemetryConfig) *Tracker {
+	t := &Tracker{
+		maxAgents: cfg.MaxAgents,
+		window:    cfg.Window,
+		now:       time.Now,
+		agents:    make(map[Agent]*Stats),
+	}
+	t.since = t.now()
+	return t
+}
+
+type connKey struct{}
+
+// conn records whether a request sent over a connection has been tracked.
+type conn struct {
+	tracked int32
+}
+
+// ConnContext returns a copy of ctx identifying the connection c, so that
+// the connections, and TLS versions, of agents are counted once for all
+// requests sent over a connection. ConnContext is intended to be used as
+// http.Server.ConnContext; without it, each request is counted as a
+// connection of its own.
+func ConnContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, &conn{})
+}
+
+// RecordRequest records the intake request handled with c, attributing
+// it to the agent identified by the request's metadata.
+func (t *Tracker) RecordRequest(c *request.Context) {
+	agent := Agent{
+		ServiceName:        c.Intake.ServiceName,
+		ServiceEnvironment: c.Intake.ServiceEnvironment,
+		AgentName:          c.Intake.AgentName,
+		AgentVersion:       c.Intake.AgentVersion,
+	}
+	if ip := c.RequestMetadata.ClientIP; ip != nil && !c.IsRum {
+		agent.ClientIP = ip.String()
+	}
+	compression := strings.ToLower(c.Request.Header.Get("Content-Encoding"))
+	if compression == "" {
+		compression = none
+	}
+	newConn := true
+	if cn, ok := c.Request.Context().Value(connKey{}).(*conn); ok {
+		newConn = atomic.CompareAndSwapInt32(&cn.tracked, 0, 1)
+	}
+	t.record(agent, compression, tlsVersion(c.Request), newConn, c.Intake.Accepted, c.Result.StatusCode >= http.StatusBadRequest)
+}
+
+func (t *Tracker) record(agent Agent, compression, tlsVersion string, newConn bool, events int, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	stats, ok := t.agents[agent]
+	if ok && now.Sub(stats.LastSeen) >= t.window {
+		// Restart tracking the agent's statistics if the agent
+		// was not seen within the reporting window.
+		delete(t.agents, agent)
+		ok = false
+	}
+	if !ok {
+		if len(t.agents) >= t.maxAgents {
+			t.expire(now)
+		}
+		if len(t.agents) >= t.maxAgents {
+			agent = Agent{}
+			stats = t.agents[agent]
+		}
+		if stats == nil {
+			stats = &Stats{
+				Compression: make(map[string]int64),
+				TLSVersion:  make(map[string]int64),
+			}
+			t.agents[agent] = stats
+		}
+	}
+	stats.Requests++
+	stats.Events += int64(events)
+	if failed {
+		stats.Errors++
+	}
+	stats.Compression[compression]++
+	if newConn {
+		stats.Connections++
+		stats.TLSVersion[tlsVersion]++
+	}
+	stats.LastSeen = now
+}
+
+// expire removes the agents not seen within the reporting window.
+// t.mu must be held.
+func (t *Tracker) expire(now time.Time) {
+	for agent, stats := range t.agents {
+		if now.Sub(stats.LastSeen) >= t.window {
+			delete(t.agents, agent)
+		}
+	}
+}
+
+// Report returns the statistics of all agents seen within the reporting
+// window and matching filter, sorted by agent.
+func (t *Tracker) Report(filter Filter) Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.expire(now)
+	report := Report{Since: t.since, Agents: []AgentReport{}}
+	if start := now.Add(-t.window); start.After(report.Since) {
+		report.Since = start
+	}
+	for agent, stats := range t.agents {
+		if filter.ServiceName != "" && agent.ServiceName != filter.ServiceName {
+			continue
+		}
+		if filter.TLSVersion != "" && stats.TLSVersion[filter.TLSVersion] == 0 {
+			continue
+		}
+		if filter.Compression != "" && stats.Compression[filter.Compression] == 0 {
+			continue
+		}
+		report.Agents = append(report.Agents, AgentReport{Agent: agent, Stats: copyStats(stats)})
+	}
+	sort.Slice(report.Agents, func(i, j int) bool {
+		ai, aj := report.Agents[i].Agent, report.Agents[j].Agent
+		if ai.ServiceName != aj.ServiceName {
+			return ai.ServiceName < aj.ServiceName
+		}
+		if ai.ServiceEnvironment != aj.ServiceEnvironment {
+			return ai.ServiceEnvironment < aj.ServiceEnvironment
+		}
+		if ai.AgentName != aj.AgentName {
+			return ai.AgentName < aj.AgentName
+		}
+		if ai.AgentVersion != aj.AgentVersion {
+			return ai.AgentVersion < aj.AgentVersion
+		}
+		return ai.ClientIP < aj.ClientIP
+	})
+	return report
+}
+
+// copyStats returns a copy of stats which may be used without holding t.mu.
+func copyStats(stats *Stats) Stats {
+	out := *stats
+	out.Compression = make(map[string]int64, len(stats.Compression))
+	for k, v := range stats.Compression {
+		out.Compression[k] = v
+	}
+	out.TLSVersion = make(map[string]int64, len(stats.TLSVersion))
+	for k, v := range stats.TLSVersion {
+		out.TLSVersion[k] = v
+	}
+	return out
+}
+
+// tlsVersion returns the TLS version used for r, or "none" if r was
+// not received over TLS.
+func tlsVersion(r *http.Request) string {
+	if r.TLS == nil {
+		return none
+	}
+	switch r.TLS.Version {
+	case tls.VersionTLS10:
+		return "1.0"
+	case tls.VersionTLS11:
+		return "1.1"
+	case tls.VersionTLS12:
+		return "1.2"
+	case tls.VersionTLS13:
+		return "1.3"
+	}
+	return "unknown"
+}
@@ -0,0 +1,173 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package intaketelemetry
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestTrackerReport(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(config.IntakeTelemetryConfig{MaxAgents: 10, Window: time.Hour})
+	tracker.now = func() time.Time { return now }
+	tracker.since = now
+
+	goAgent := request.Intake{ServiceName: "opbeans-go", AgentName: "go", AgentVersion: "1.11.0", Accepted: 5}
+	nodeAgent := request.Intake{ServiceName: "opbeans-node", AgentName: "nodejs", AgentVersion: "3.0.0", Accepted: 2}
+	recordRequest(tracker, goAgent, "gzip", &tls.ConnectionState{Version: tls.VersionTLS13}, http.StatusAccepted)
+	now = now.Add(time.Minute)
+	recordRequest(tracker, goAgent, "", &tls.ConnectionState{Version: tls.VersionTLS10}, http.StatusBadRequest)
+	recordRequest(tracker, nodeAgent, "deflate", nil, http.StatusAccepted)
+
+	goReport := AgentReport{
+		Agent: Agent{ServiceName: "opbeans-go", AgentName: "go", AgentVersion: "1.11.0", ClientIP: "10.0.0.1"},
+		Stats: Stats{
+			Requests:    2,
+			Events:      10,
+			Connections: 2,
+			Errors:      1,
+			Compression: map[string]int64{"gzip": 1, "none": 1},
+			TLSVersion:  map[string]int64{"1.3": 1, "1.0": 1},
+			LastSeen:    now,
+		},
+	}
+	nodeReport := AgentReport{
+		Agent: Agent{ServiceName: "opbeans-node", AgentName: "nodejs", AgentVersion: "3.0.0", ClientIP: "10.0.0.1"},
+		Stats: Stats{
+			Requests:    1,
+			Events:      2,
+			Connections: 1,
+			Compression: map[string]int64{"deflate": 1},
+			TLSVersion:  map[string]int64{"none": 1},
+			LastSeen:    now,
+		},
+	}
+	assert.Equal(t, Report{
+		Since:  time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+		Agents: []AgentReport{goReport, nodeReport},
+	}, tracker.Report(Filter{}))
+
+	assert.Equal(t, []AgentReport{nodeReport}, tracker.Report(Filter{ServiceName: "opbeans-node"}).Agents)
+	assert.Equal(t, []AgentReport{goReport}, tracker.Report(Filter{TLSVersion: "1.0"}).Agents)
+	assert.Equal(t, []AgentReport{goReport}, tracker.Report(Filter{Compression: "none"}).Agents)
+	assert.Equal(t, []AgentReport{}, tracker.Report(Filter{TLSVersion: "1.1"}).Agents)
+}
+
+func TestTrackerWindow(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(config.IntakeTelemetryConfig{MaxAgents: 1, Window: time.Hour})
+	tracker.now = func() time.Time { return now }
+	tracker.since = now
+
+	recordRequest(tracker, request.Intake{ServiceName: "a", Accepted: 1}, "", nil, http.StatusAccepted)
+	now = now.Add(30 * time.Minute)
+	recordRequest(tracker, request.Intake{ServiceName: "b", Accepted: 1}, "", nil, http.StatusAccepted)
+	report := tracker.Report(Filter{})
+	assert.Equal(t, time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC), report.Since)
+	assert.Len(t, report.Agents, 2)
+
+	// Agents not seen within the window are expired,
+	// freeing their slots for other agents.
+	now = now.Add(time.Hour)
+	recordRequest(tracker, request.Intake{ServiceName: "c", Accepted: 1}, "", nil, http.StatusAccepted)
+	report = tracker.Report(Filter{})
+	assert.Equal(t, now.Add(-time.Hour), report.Since)
+	if assert.Len(t, report.Agents, 1) {
+		assert.Equal(t, "c", report.Agents[0].Agent.ServiceName)
+		assert.Equal(t, int64(1), report.Agents[0].Requests)
+	}
+}
+
+func TestTrackerConnections(t *testing.T) {
+	tracker := NewTracker(config.DefaultConfig().IntakeTelemetry)
+	connCtx := ConnContext(context.Background(), nil)
+	for i := 0; i < 3; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/", nil).WithContext(connCtx)
+		r.TLS = &tls.ConnectionState{Version: tls.VersionTLS12}
+		c := request.NewContext()
+		c.Reset(httptest.NewRecorder(), r)
+		c.Intake = request.Intake{ServiceName: "opbeans"}
+		tracker.RecordRequest(c)
+	}
+
+	report := tracker.Report(Filter{})
+	require.Len(t, report.Agents, 1)
+	assert.Equal(t, int64(3), report.Agents[0].Requests)
+	assert.Equal(t, int64(1), report.Agents[0].Connections)
+	assert.Equal(t, map[string]int64{"1.2": 1}, report.Agents[0].TLSVersion)
+}
+
+func TestTrackerRUMClientIP(t *testing.T) {
+	tracker := NewTracker(config.DefaultConfig().IntakeTelemetry)
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+		c := request.NewContext()
+		c.Reset(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+		c.IsRum = true
+		c.RequestMetadata.ClientIP = net.ParseIP(ip)
+		c.Intake = request.Intake{ServiceName: "opbeans-rum", AgentName: "rum-js"}
+		tracker.RecordRequest(c)
+	}
+
+	// RUM requests are not tracked per client IP.
+	report := tracker.Report(Filter{})
+	require.Len(t, report.Agents, 1)
+	assert.Equal(t, Agent{ServiceName: "opbeans-rum", AgentName: "rum-js"}, report.Agents[0].Agent)
+	assert.Equal(t, int64(2), report.Agents[0].Requests)
+}
+
+func TestTrackerMaxAgents(t *testing.T) {
+	tracker := NewTracker(config.IntakeTelemetryConfig{MaxAgents: 1, Window: time.Hour})
+	recordRequest(tracker, request.Intake{ServiceName: "a", Accepted: 1}, "", nil, http.StatusAccepted)
+	recordRequest(tracker, request.Intake{ServiceName: "b", Accepted: 1}, "", nil, http.StatusAccepted)
+	recordRequest(tracker, request.Intake{ServiceName: "c", Accepted: 1}, "", nil, http.StatusAccepted)
+
+	// Agents beyond the limit are recorded against the empty agent.
+	report := tracker.Report(Filter{})
+	if assert.Len(t, report.Agents, 2) {
+		assert.Equal(t, Agent{}, report.Agents[0].Agent)
+		assert.Equal(t, int64(2), report.Agents[0].Requests)
+		assert.Equal(t, "a", report.Agents[1].Agent.ServiceName)
+		assert.Equal(t, int64(1), report.Agents[1].Requests)
+	}
+}
+
+func recordRequest(tracker *Tracker, intake request.Intake, encoding string, state *tls.ConnectionState, status int) {
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	if encoding != "" {
+		r.Header.Set("Content-Encoding", encoding)
+	}
+	r.TLS = state
+	c := request.NewContext()
+	c.Reset(httptest.NewRecorder(), r)
+	c.RequestMetadata.ClientIP = net.ParseIP("10.0.0.1")
+	c.Intake = intake
+	c.Result.StatusCode = status
+	tracker.RecordRequest(c)
+}
@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loadshedding
+
+import (
+	"net/http"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/middleware"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+var (
+	// MonitoringMap holds a mapping for request.IDs to monitoring counters
+	MonitoringMap = request.DefaultMonitoringMapForRegistry(registry)
+	registry      = monitoring.Default.NewRegistry("apm-server.load_shedding")
+)
+
+// serviceNameQueryParam is the query parameter for restricting the
+// report to a single service.
+const serviceNameQueryParam = "service.name"
+
+// Handler returns a request.Handler for reporting the drops tracked by tracker.
+//
+// If tracker is nil, an empty report is returned.
+func Handler(tracker *Tracker) request.Handler {
+	return func(c *request.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Result.SetDefault(request.IDResponseErrorsMethodNotAllowed)
+			c.Write()
+			return
+		}
+		report := Report{Services: []ServiceReport{}}
+		if tracker != nil {
+			report = tracker.Report(c.Request.URL.Query().Get(serviceNameQueryParam))
+		}
+		c.Result.SetWithBody(request.IDResponseValidOK, report)
+		c.Write()
+	}
+}
+
+// Middleware returns a middleware.Middleware which records requests
+// rejected due to rate limiting with tracker.
+func Middleware(tracker *Tracker) middleware.Middleware {
+	return func(h request.Handler) (request.Handler, error) {
+		return func(c *request.Context) {
+			h(c)
+			if c.Result.ID == request.IDResponseErrorsRateLimit {
+				tracker.Record(Service{}, ReasonRateLimit, 1, 0)
+			}
+		}, nil
+	}
+}
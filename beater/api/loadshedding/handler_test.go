@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loadshedding
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestHandler(t *testing.T) {
+	tracker := NewTracker(config.DefaultConfig().LoadSheddingReport)
+	tracker.Record(Service{Name: "opbeans"}, ReasonQueueFull, 1, 2)
+	tracker.Record(Service{Name: "other"}, ReasonQueueFull, 1, 3)
+
+	c := request.NewContext()
+	w := httptest.NewRecorder()
+	c.Reset(w, httptest.NewRequest(http.MethodGet, "/?service.name=opbeans", nil))
+	Handler(tracker)(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, []ServiceReport{{
+		Service: &Service{Name: "opbeans"},
+		Dropped: map[Reason]Counts{ReasonQueueFull: {Requests: 1, Events: 2}},
+	}}, report.Services)
+}
+
+func TestHandlerMethodNotAllowed(t *testing.T) {
+	c := request.NewContext()
+	w := httptest.NewRecorder()
+	c.Reset(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	Handler(NewTracker(config.DefaultConfig().LoadSheddingReport))(c)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestMiddleware(t *testing.T) {
+	tracker := NewTracker(config.DefaultConfig().LoadSheddingReport)
+	m := Middleware(tracker)
+	h, err := m(func(c *request.Context) {
+		c.Result.SetDefault(request.IDResponseErrorsRateLimit)
+	})
+	require.NoError(t, err)
+
+	c := request.NewContext()
+	c.Reset(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	h(c)
+	assert.Equal(t, []ServiceReport{{
+		Dropped: map[Reason]Counts{ReasonRateLimit: {Requests: 1}},
+	}}, tracker.Report("").Services)
+
+	// Drops not attributed to a service are reported without one.
+	encoded, err := json.Marshal(tracker.Report("").Services)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"dropped":{"rate_limit":{"requests":1,"events":0}}}]`, string(encoded))
+}
@@ -0,0 +1,237 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package loadshedding tracks the events and requests dropped or rejected
+// by the server due to load, per service, and reports them to agents' owners.
+package loadshedding
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+)
+
+// resolution is the duration of each bucket of tracked drops.
+const resolution = time.Minute
+
+// Reason identifies the reason for events being dropped or rejected.
+type Reason string
+
+const (
+	// ReasonQueueFull is recorded for events dropped because the
+	// publisher queue was full.
+	ReasonQueueFull Reason = "queue_full"
+
+	// ReasonRateLimit is recorded for requests rejected due to rate
+	// limiting. Rate limits are applied per client IP, before events
+	// are decoded, so these are not attributed to a service.
+	ReasonRateLimit Reason = "rate_limit"
+)
+
+// Service identifies the service for which drops are tracked.
+type Service struct {
+	Name        string `json:"name,omitempty"`
+	Environment string `json:"environment,omitempty"`
+}
+
+// Counts holds the number of requests and events dropped or rejected.
+//
+// The number of events is zero for requests rejected before their
+// events were decoded.
+type Counts struct {
+	Requests int64 `json:"requests"`
+	Events   int64 `json:"events"`
+}
+
+// ServiceReport holds the drops for a service within the reporting window.
+//
+// Service is nil for drops which are not attributed to a service, such as
+// requests rejected due to rate limiting, or drops for services beyond the
+// configured maximum.
+type ServiceReport struct {
+	Service *Service          `json:"service,omitempty"`
+	Dropped map[Reason]Counts `json:"dropped"`
+}
+
+// Report holds the drops for all services within the reporting window.
+type Report struct {
+	// Since holds the start of the reporting window.
+	Since    time.Time       `json:"since"`
+	Services []ServiceReport `json:"services"`
+}
+
+// Tracker tracks dropped events and rejected requests per service,
+// for a sliding window of time.
+type Tracker struct {
+	maxServices int
+	now         func() time.Time
+
+	mu      sync.Mutex
+	buckets []bucket
+}
+
+type bucket struct {
+	start    time.Time
+	services map[Service]map[Reason]*Counts
+}
+
+// NewTracker returns a new Tracker with the given configuration.
+func NewTracker(cfg config.LoadSheddingReportConfig) *Tracker {
+	n := int((cfg.Window + resolution - 1) / resolution)
+	return &Tracker{
+		maxServices: cfg.MaxServices,
+		now:         time.Now,
+		buckets:     make([]bucket, n),
+	}
+}
+
+// Record records the given number of requests and events dropped or
+// rejected for service due to reason.
+func (t *Tracker) Record(service Service, reason Reason, requests, events int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b := t.currentBucket()
+	reasons, ok := b.services[service]
+	if !ok {
+		if len(b.services) >= t.maxServices {
+			service = Service{}
+			reasons = b.services[service]
+		}
+		if reasons == nil {
+			reasons = make(map[Reason]*Counts)
+			b.services[service] = reasons
+		}
+	}
+	counts, ok := reasons[reason]
+	if !ok {
+		counts = &Counts{}
+		reasons[reason] = counts
+	}
+	counts.Requests += requests
+	counts.Events += events
+}
+
+// currentBucket returns the bucket for the current time, resetting it
+// if it holds counts from a previous window. t.mu must be held.
+func (t *Tracker) currentBucket() *bucket {
+	start := t.now().Truncate(resolution)
+	b := &t.buckets[int(start.Unix()/int64(resolution/time.Second))%len(t.buckets)]
+	if !b.start.Equal(start) {
+		b.start = start
+		b.services = make(map[Service]map[Reason]*Counts)
+	}
+	return b
+}
+
+// Report returns the drops tracked within the window, sorted by service.
+// If serviceName is non-empty, only drops for that service are reported.
+func (t *Tracker) Report(serviceName string) Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now().Truncate(resolution)
+	since := now.Add(-time.Duration(len(t.buckets)-1) * resolution)
+	services := make(map[Service]map[Reason]Counts)
+	for _, b := range t.buckets {
+		if b.start.Before(since) || b.start.After(now) {
+			continue
+		}
+		for service, reasons := range b.services {
+			if serviceName != "" && service.Name != serviceName {
+				continue
+			}
+			dropped, ok := services[service]
+			if !ok {
+				dropped = make(map[Reason]Counts)
+				services[service] = dropped
+			}
+			for reason, counts := range reasons {
+				total := dropped[reason]
+				total.Requests += counts.Requests
+				total.Events += counts.Events
+				dropped[reason] = total
+			}
+		}
+	}
+
+	report := Report{Since: since, Services: make([]ServiceReport, 0, len(services))}
+	for service, dropped := range services {
+		serviceReport := ServiceReport{Dropped: dropped}
+		if service != (Service{}) {
+			service := service
+			serviceReport.Service = &service
+		}
+		report.Services = append(report.Services, serviceReport)
+	}
+	sort.Slice(report.Services, func(i, j int) bool {
+		si, sj := report.Services[i].Service, report.Services[j].Service
+		if si == nil || sj == nil {
+			return si == nil && sj != nil
+		}
+		if si.Name != sj.Name {
+			return si.Name < sj.Name
+		}
+		return si.Environment < sj.Environment
+	})
+	return report
+}
+
+// BatchProcessor returns a model.BatchProcessor which calls next, and
+// records the batch's events as dropped if the publisher queue is full.
+func (t *Tracker) BatchProcessor(next model.BatchProcessor) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		err := next.ProcessBatch(ctx, batch)
+		if err == publish.ErrFull {
+			for service, events := range batchServices(batch) {
+				t.Record(service, ReasonQueueFull, 1, events)
+			}
+		}
+		return err
+	})
+}
+
+// batchServices returns the number of events in batch for each service.
+func batchServices(batch *model.Batch) map[Service]int64 {
+	services := make(map[Service]int64)
+	add := func(metadata *model.Metadata) {
+		services[Service{
+			Name:        metadata.Service.Name,
+			Environment: metadata.Service.Environment,
+		}]++
+	}
+	for _, event := range batch.Transactions {
+		add(&event.Metadata)
+	}
+	for _, event := range batch.Spans {
+		add(&event.Metadata)
+	}
+	for _, event := range batch.Metricsets {
+		add(&event.Metadata)
+	}
+	for _, event := range batch.Errors {
+		add(&event.Metadata)
+	}
+	for _, event := range batch.Profiles {
+		add(&event.Metadata)
+	}
+	return services
+}
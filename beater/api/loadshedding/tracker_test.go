@@ -0,0 +1,107 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package loadshedding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+)
+
+func TestTrackerReport(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 30, 0, time.UTC)
+	tracker := NewTracker(config.LoadSheddingReportConfig{Window: 3 * time.Minute, MaxServices: 10})
+	tracker.now = func() time.Time { return now }
+
+	opbeans := Service{Name: "opbeans", Environment: "production"}
+	tracker.Record(opbeans, ReasonQueueFull, 1, 10)
+	tracker.Record(Service{}, ReasonRateLimit, 1, 0)
+	now = now.Add(time.Minute)
+	tracker.Record(opbeans, ReasonQueueFull, 2, 5)
+
+	assert.Equal(t, Report{
+		Since: time.Date(2021, 1, 1, 11, 59, 0, 0, time.UTC),
+		Services: []ServiceReport{{
+			Dropped: map[Reason]Counts{ReasonRateLimit: {Requests: 1}},
+		}, {
+			Service: &opbeans,
+			Dropped: map[Reason]Counts{ReasonQueueFull: {Requests: 3, Events: 15}},
+		}},
+	}, tracker.Report(""))
+
+	assert.Equal(t, []ServiceReport{{
+		Service: &opbeans,
+		Dropped: map[Reason]Counts{ReasonQueueFull: {Requests: 3, Events: 15}},
+	}}, tracker.Report("opbeans").Services)
+
+	// Drops older than the window are no longer reported,
+	// and their buckets are reused.
+	now = now.Add(2 * time.Minute)
+	tracker.Record(opbeans, ReasonQueueFull, 1, 1)
+	assert.Equal(t, []ServiceReport{{
+		Service: &opbeans,
+		Dropped: map[Reason]Counts{ReasonQueueFull: {Requests: 3, Events: 6}},
+	}}, tracker.Report("").Services)
+}
+
+func TestTrackerMaxServices(t *testing.T) {
+	tracker := NewTracker(config.LoadSheddingReportConfig{Window: time.Minute, MaxServices: 1})
+	tracker.Record(Service{Name: "a"}, ReasonQueueFull, 1, 1)
+	tracker.Record(Service{Name: "b"}, ReasonQueueFull, 1, 2)
+	tracker.Record(Service{Name: "c"}, ReasonQueueFull, 1, 3)
+	assert.Equal(t, []ServiceReport{{
+		Dropped: map[Reason]Counts{ReasonQueueFull: {Requests: 2, Events: 5}},
+	}, {
+		Service: &Service{Name: "a"},
+		Dropped: map[Reason]Counts{ReasonQueueFull: {Requests: 1, Events: 1}},
+	}}, tracker.Report("").Services)
+}
+
+func TestTrackerBatchProcessor(t *testing.T) {
+	tracker := NewTracker(config.DefaultConfig().LoadSheddingReport)
+	var processErr error
+	processor := tracker.BatchProcessor(model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		return processErr
+	}))
+
+	opbeans := model.Metadata{Service: model.Service{Name: "opbeans"}}
+	batch := &model.Batch{
+		Transactions: []*model.Transaction{{Metadata: opbeans}},
+		Spans:        []*model.Span{{Metadata: opbeans}, {Metadata: opbeans}},
+		Errors:       []*model.Error{{Metadata: model.Metadata{Service: model.Service{Name: "other"}}}},
+	}
+	require.NoError(t, processor.ProcessBatch(context.Background(), batch))
+	assert.Empty(t, tracker.Report("").Services)
+
+	processErr = publish.ErrFull
+	assert.Equal(t, publish.ErrFull, processor.ProcessBatch(context.Background(), batch))
+	assert.Equal(t, []ServiceReport{{
+		Service: &Service{Name: "opbeans"},
+		Dropped: map[Reason]Counts{ReasonQueueFull: {Requests: 1, Events: 3}},
+	}, {
+		Service: &Service{Name: "other"},
+		Dropped: map[Reason]Counts{ReasonQueueFull: {Requests: 1, Events: 1}},
+	}}, tracker.Report("").Services)
+}
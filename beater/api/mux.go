@@ -18,22 +18,36 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"net/http/pprof"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/monitoring"
+	"github.com/elastic/beats/v7/libbeat/paths"
 
+	"github.com/elastic/apm-server/agentcfg"
 	"github.com/elastic/apm-server/beater/api/asset/sourcemap"
 	"github.com/elastic/apm-server/beater/api/config/agent"
 	"github.com/elastic/apm-server/beater/api/intake"
+	"github.com/elastic/apm-server/beater/api/intaketelemetry"
+	"github.com/elastic/apm-server/beater/api/loadshedding"
 	"github.com/elastic/apm-server/beater/api/profile"
 	"github.com/elastic/apm-server/beater/api/root"
+	"github.com/elastic/apm-server/beater/api/routinghint"
+	"github.com/elastic/apm-server/beater/api/sampling"
+	"github.com/elastic/apm-server/beater/api/wellknown"
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/forward"
+	"github.com/elastic/apm-server/beater/jaeger"
 	"github.com/elastic/apm-server/beater/middleware"
+	"github.com/elastic/apm-server/beater/otlp"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/beater/usage"
+	"github.com/elastic/apm-server/beater/zipkin"
+	"github.com/elastic/apm-server/elasticsearch"
 	"github.com/elastic/apm-server/kibana"
 	logs "github.com/elastic/apm-server/log"
 	"github.com/elastic/apm-server/model"
@@ -49,6 +63,8 @@ const (
 
 	// AgentConfigPath defines the path to query for agent config management
 	AgentConfigPath = "/config/v1/agents"
+	// SamplingDecisionPath defines the path to query for head-sampling decisions
+	SamplingDecisionPath = "/config/v1/sampling"
 	// AssetSourcemapPath defines the path to upload sourcemaps
 	AssetSourcemapPath = "/assets/v1/sourcemaps"
 	// IntakePath defines the path to ingest monitored events
@@ -56,6 +72,25 @@ const (
 	// ProfilePath defines the path to ingest profiles
 	ProfilePath = "/intake/v2/profile"
 
+	// OpenTelemetry routes
+
+	// OTLPTracesPath defines the path to ingest OTLP/HTTP traces
+	OTLPTracesPath = "/v1/traces"
+	// OTLPMetricsPath defines the path to ingest OTLP/HTTP metrics
+	OTLPMetricsPath = "/v1/metrics"
+	// OTLPLogsPath defines the path to ingest OTLP/HTTP logs
+	OTLPLogsPath = "/v1/logs"
+
+	// Jaeger routes
+
+	// JaegerTracesPath defines the path to ingest Jaeger Thrift-over-HTTP spans
+	JaegerTracesPath = "/api/traces"
+
+	// Zipkin routes
+
+	// ZipkinSpansPath defines the path to ingest Zipkin v2 JSON spans
+	ZipkinSpansPath = "/api/v2/spans"
+
 	// RUM routes
 
 	// AgentConfigRUMPath defines the path to query for the RUM agent config management
@@ -64,6 +99,13 @@ const (
 	IntakeRUMPath = "/intake/v2/rum/events"
 
 	IntakeRUMV3Path = "/intake/v3/rum/events"
+
+	// LoadSheddingReportPath defines the path to query for events dropped by the server
+	LoadSheddingReportPath = "/load_shedding/v1/report"
+	// IntakeTelemetryPath defines the path to query for statistics of intake requests per agent
+	IntakeTelemetryPath = "/intake_telemetry/v1/report"
+	// WellKnownPath defines the path to query for the endpoints, protocols, and limits of the server
+	WellKnownPath = "/.well-known/apm-agent-configuration"
 )
 
 // NewMux registers apm handlers to paths building up the APM Server API.
@@ -84,28 +126,92 @@ func NewMux(beatInfo beat.Info, beaterConfig *config.Config, report publish.Repo
 		reporter:       report,
 		batchProcessor: batchProcessor,
 	}
+	if beaterConfig.Forward.Enabled {
+		forwarder, err := forward.NewForwarder(beaterConfig.Forward)
+		if err != nil {
+			return nil, err
+		}
+		builder.forwarder = forwarder
+	}
+	if err := builder.newAgentConfigFetcher(); err != nil {
+		return nil, err
+	}
+	if beaterConfig.Warmup.Enabled {
+		warmAgentConfig(logger, beaterConfig.Warmup, builder.agentcfgFetcher)
+	}
+	if beaterConfig.LoadSheddingReport.Enabled {
+		builder.loadShedding = loadshedding.NewTracker(beaterConfig.LoadSheddingReport)
+		builder.batchProcessor = builder.loadShedding.BatchProcessor(batchProcessor)
+	}
+	if beaterConfig.IntakeTelemetry.Enabled {
+		builder.intakeTelemetry = intaketelemetry.NewTracker(beaterConfig.IntakeTelemetry)
+	}
+	if beaterConfig.RoutingHint.Enabled {
+		builder.batchProcessor = routinghint.BatchProcessor(builder.batchProcessor)
+	}
+	if beaterConfig.DecodeLimits.Enabled {
+		builder.decodeLimiter = stream.NewDecodeLimiter(
+			beaterConfig.DecodeLimits.MaxConcurrentDecoders,
+			beaterConfig.DecodeLimits.DecoderTimeout,
+		)
+	}
+	builder.backendAcks, builder.rumAcks = intake.NewAckPolicies(beaterConfig.AckLevel)
+	builder.otlpHandlers = otlp.NewHTTPHandlers(builder.batchProcessor, beaterConfig.OTel, builder.decodeLimiter)
 
 	type route struct {
 		path      string
 		handlerFn func() (request.Handler, error)
+
+		// name holds the name under which the route is advertised by
+		// the well-known endpoint. Routes without a name are not advertised.
+		name string
+
+		// enabled reports whether the route accepts requests, or
+		// rejects them with its kill switch.
+		enabled bool
 	}
+	rum := beaterConfig.RumConfig.IsEnabled()
+	agentConfig := agentConfigEnabled(beaterConfig, builder.agentcfgFetcher)
 	routeMap := []route{
-		{RootPath, builder.rootHandler},
-		{AssetSourcemapPath, builder.sourcemapHandler},
-		{AgentConfigPath, builder.backendAgentConfigHandler},
-		{AgentConfigRUMPath, builder.rumAgentConfigHandler},
-		{IntakeRUMPath, builder.rumIntakeHandler},
-		{IntakeRUMV3Path, builder.rumV3IntakeHandler},
-		{IntakePath, builder.backendIntakeHandler},
+		{RootPath, builder.rootHandler, "", true},
+		{AssetSourcemapPath, builder.sourcemapHandler, "sourcemaps", sourcemapUploadEnabled(beaterConfig)},
+		{AgentConfigPath, builder.backendAgentConfigHandler, "agent_config", agentConfig},
+		{AgentConfigRUMPath, builder.rumAgentConfigHandler, "agent_config_rum", agentConfig && rum},
+		{SamplingDecisionPath, builder.samplingDecisionHandler, "sampling", beaterConfig.Sampling.Head.Enabled},
+		{IntakeRUMPath, builder.rumIntakeHandler, "intake_rum", rum},
+		{IntakeRUMV3Path, builder.rumV3IntakeHandler, "intake_rum_v3", rum},
+		{IntakePath, builder.backendIntakeHandler, "intake", true},
 		// The profile endpoint is in Beta
-		{ProfilePath, builder.profileHandler},
+		{ProfilePath, builder.profileHandler, "profile", true},
+		{LoadSheddingReportPath, builder.loadSheddingReportHandler, "", beaterConfig.LoadSheddingReport.Enabled},
+		{IntakeTelemetryPath, builder.intakeTelemetryHandler, "", beaterConfig.IntakeTelemetry.Enabled},
+		{WellKnownPath, builder.wellKnownHandler, "", beaterConfig.WellKnown.Enabled},
+		{OTLPTracesPath, builder.otlpTracesHandler, "otlp_traces", true},
+		{OTLPMetricsPath, builder.otlpMetricsHandler, "otlp_metrics", true},
+		{OTLPLogsPath, builder.otlpLogsHandler, "otlp_logs", true},
+		{JaegerTracesPath, builder.jaegerTracesHandler, "jaeger", true},
+		{ZipkinSpansPath, builder.zipkinSpansHandler, "zipkin", true},
 	}
 
+	builder.endpoints = make(map[string]wellknown.Endpoint)
+	for _, route := range routeMap {
+		if route.name != "" {
+			builder.endpoints[route.name] = wellknown.Endpoint{Path: route.path, Enabled: route.enabled}
+		}
+	}
 	for _, route := range routeMap {
 		h, err := route.handlerFn()
 		if err != nil {
 			return nil, err
 		}
+		var breaker *middleware.PanicBreaker
+		if cfg := beaterConfig.PanicBreaker; cfg.Enabled {
+			breaker = middleware.NewPanicBreaker(cfg.Threshold, cfg.Window, cfg.Cooldown)
+		}
+		h, err = middleware.Wrap(h, middleware.PanicIsolationMiddleware(route.path, breaker))
+		if err != nil {
+			return nil, err
+		}
 		logger.Infof("Path %s added to request handler", route.path)
 		mux.Handle(route.path, pool.HTTPHandler(h))
 	}
@@ -127,33 +233,214 @@ func NewMux(beatInfo beat.Info, beaterConfig *config.Config, report publish.Repo
 }
 
 type routeBuilder struct {
-	info           beat.Info
-	cfg            *config.Config
-	authBuilder    *authorization.Builder
-	reporter       publish.Reporter
-	batchProcessor model.BatchProcessor
+	info            beat.Info
+	cfg             *config.Config
+	authBuilder     *authorization.Builder
+	reporter        publish.Reporter
+	batchProcessor  model.BatchProcessor
+	forwarder       intake.Forwarder
+	loadShedding    *loadshedding.Tracker
+	intakeTelemetry *intaketelemetry.Tracker
+	otlpHandlers    otlp.HTTPHandlers
+	decodeLimiter   *stream.DecodeLimiter
+
+	// endpoints holds the routes advertised by the well-known endpoint.
+	endpoints map[string]wellknown.Endpoint
+
+	// backendAcks and rumAcks hold the ack levels allowed
+	// for backend and RUM intake requests.
+	backendAcks *intake.AckPolicy
+	rumAcks     *intake.AckPolicy
+
+	// agentcfgFetcher is shared by the agent configuration handlers,
+	// so that agent configuration is cached once for all agents.
+	agentcfgFetcher *agentcfg.Fetcher
+	kibanaClient    kibana.Client
 }
 
 func (r *routeBuilder) profileHandler() (request.Handler, error) {
 	h := profile.Handler(r.batchProcessor)
 	authHandler := r.authBuilder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
-	return middleware.Wrap(h, backendMiddleware(r.cfg, authHandler, profile.MonitoringMap)...)
+	return middleware.Wrap(h, r.eventsMiddleware(backendMiddleware(r.cfg, authHandler, profile.MonitoringMap))...)
+}
+
+func (r *routeBuilder) otlpTracesHandler() (request.Handler, error) {
+	return r.otlpHandler(r.otlpHandlers.Traces, otlp.HTTPTracesMonitoringMap)
+}
+
+func (r *routeBuilder) otlpMetricsHandler() (request.Handler, error) {
+	return r.otlpHandler(r.otlpHandlers.Metrics, otlp.HTTPMetricsMonitoringMap)
+}
+
+func (r *routeBuilder) otlpLogsHandler() (request.Handler, error) {
+	return r.otlpHandler(r.otlpHandlers.Logs, otlp.HTTPLogsMonitoringMap)
+}
+
+func (r *routeBuilder) otlpHandler(h request.Handler, m map[request.ResultID]*monitoring.Int) (request.Handler, error) {
+	authHandler := r.authBuilder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
+	return middleware.Wrap(h, r.eventsMiddleware(otlpMiddleware(r.cfg, authHandler, m))...)
+}
+
+func (r *routeBuilder) jaegerTracesHandler() (request.Handler, error) {
+	h := jaeger.HTTPTracesHandler(
+		r.batchProcessor,
+		r.cfg.OTel,
+		r.cfg.JaegerConfig.HTTP.MaxRequestSize,
+		r.decodeLimiter,
+	)
+	authHandler := r.authBuilder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
+	return middleware.Wrap(h, r.eventsMiddleware(backendMiddleware(r.cfg, authHandler, jaeger.HTTPMonitoringMap))...)
+}
+
+func (r *routeBuilder) zipkinSpansHandler() (request.Handler, error) {
+	h := zipkin.HTTPSpansHandler(
+		r.batchProcessor,
+		otlp.InstrumentationScopeRules(r.cfg.OTel),
+		r.cfg.Zipkin.MaxRequestSize,
+		r.decodeLimiter,
+	)
+	authHandler := r.authBuilder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
+	return middleware.Wrap(h, r.eventsMiddleware(backendMiddleware(r.cfg, authHandler, zipkin.MonitoringMap))...)
 }
 
 func (r *routeBuilder) backendIntakeHandler() (request.Handler, error) {
-	h := intake.Handler(stream.BackendProcessor(r.cfg), r.batchProcessor)
+	h := r.intakeHandler(stream.BackendProcessor(r.cfg))
 	authHandler := r.authBuilder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
-	return middleware.Wrap(h, backendMiddleware(r.cfg, authHandler, intake.MonitoringMap)...)
+	return middleware.Wrap(h, r.intakeMiddleware(backendMiddleware(r.cfg, authHandler, intake.MonitoringMap))...)
 }
 
 func (r *routeBuilder) rumIntakeHandler() (request.Handler, error) {
-	h := intake.Handler(stream.RUMV2Processor(r.cfg), r.batchProcessor)
-	return middleware.Wrap(h, rumMiddleware(r.cfg, nil, intake.MonitoringMap)...)
+	h := r.intakeHandler(stream.RUMV2Processor(r.cfg))
+	authHandler := r.authBuilder.ForAnonymousPrivilege(authorization.PrivilegeEventWrite.Action)
+	return middleware.Wrap(h, r.intakeMiddleware(rumMiddleware(r.cfg, authHandler, intake.MonitoringMap))...)
 }
 
 func (r *routeBuilder) rumV3IntakeHandler() (request.Handler, error) {
-	h := intake.Handler(stream.RUMV3Processor(r.cfg), r.batchProcessor)
-	return middleware.Wrap(h, rumMiddleware(r.cfg, nil, intake.MonitoringMap)...)
+	h := r.intakeHandler(stream.RUMV3Processor(r.cfg))
+	authHandler := r.authBuilder.ForAnonymousPrivilege(authorization.PrivilegeEventWrite.Action)
+	return middleware.Wrap(h, r.intakeMiddleware(rumMiddleware(r.cfg, authHandler, intake.MonitoringMap))...)
+}
+
+// intakeMiddleware appends middleware for recording rate limited
+// intake requests, if load shedding reporting is enabled, and for
+// recording the time spent in each phase of processing intake
+// requests, if phase timings are enabled. Intake requests are
+// recorded for intake telemetry, if enabled, ahead of all other
+// middleware, so that unauthorized requests are recorded as well.
+func (r *routeBuilder) intakeMiddleware(m []middleware.Middleware) []middleware.Middleware {
+	if r.loadShedding != nil {
+		m = append(m, loadshedding.Middleware(r.loadShedding))
+	}
+	if r.intakeTelemetry != nil {
+		m = append([]middleware.Middleware{intaketelemetry.Middleware(r.intakeTelemetry)}, m...)
+	}
+	if r.cfg.PhaseTimings.Enabled {
+		m = append(m, middleware.PhaseTimingMiddleware(r.cfg.PhaseTimings.Log))
+	}
+	return r.eventsMiddleware(m)
+}
+
+// eventsMiddleware appends middleware for routes receiving events:
+// for recording the size of requests against the services whose
+// events they contain, if usage reporting is enabled, and for sending
+// a routing hint derived from the events' trace IDs, if enabled.
+func (r *routeBuilder) eventsMiddleware(m []middleware.Middleware) []middleware.Middleware {
+	if r.cfg.UsageReport.Enabled {
+		m = append(m, usage.Middleware())
+	}
+	if r.cfg.RoutingHint.Enabled {
+		m = append(m, routinghint.Middleware(r.cfg.RoutingHint))
+	}
+	return m
+}
+
+func (r *routeBuilder) loadSheddingReportHandler() (request.Handler, error) {
+	h := loadshedding.Handler(r.loadShedding)
+	authHandler := r.authBuilder.ForAnyOfPrivileges(authorization.ActionAny)
+	msg := "Load shedding report endpoint is disabled. " +
+		"Configure the `apm-server.load_shedding_report` section in apm-server.yml to enable it."
+	return middleware.Wrap(h, append(backendMiddleware(r.cfg, authHandler, loadshedding.MonitoringMap),
+		middleware.KillSwitchMiddleware(r.cfg.LoadSheddingReport.Enabled, msg))...)
+}
+
+func (r *routeBuilder) intakeTelemetryHandler() (request.Handler, error) {
+	h := intaketelemetry.Handler(r.intakeTelemetry)
+	// The report reveals the services, agents and client IPs sending
+	// to the server, so it is restricted to the agent configuration
+	// privilege rather than any privilege held by ingesting agents.
+	authHandler := r.authBuilder.ForPrivilege(authorization.PrivilegeAgentConfigRead.Action)
+	msg := "Intake telemetry endpoint is disabled. " +
+		"Configure the `apm-server.intake_telemetry` section in apm-server.yml to enable it."
+	return middleware.Wrap(h, append(backendMiddleware(r.cfg, authHandler, intaketelemetry.MonitoringMap),
+		middleware.KillSwitchMiddleware(r.cfg.IntakeTelemetry.Enabled, msg))...)
+}
+
+func (r *routeBuilder) samplingDecisionHandler() (request.Handler, error) {
+	h := sampling.Handler(sampling.NewDecider(r.cfg.Sampling.Head), r.cfg.DefaultServiceEnvironment)
+	authHandler := r.authBuilder.ForPrivilege(authorization.PrivilegeAgentConfigRead.Action)
+	msg := "Sampling decision endpoint is disabled. " +
+		"Configure the `apm-server.sampling.head` section in apm-server.yml to enable it."
+	return middleware.Wrap(h, append(backendMiddleware(r.cfg, authHandler, sampling.MonitoringMap),
+		middleware.KillSwitchMiddleware(r.cfg.Sampling.Head.Enabled, msg))...)
+}
+
+func (r *routeBuilder) wellKnownHandler() (request.Handler, error) {
+	h := wellknown.Handler(r.wellKnownDocument())
+	msg := "Well-known endpoint is disabled. " +
+		"Configure the `apm-server.well_known` section in apm-server.yml to enable it."
+	m := append(apmMiddleware(wellknown.MonitoringMap),
+		middleware.ResponseHeadersMiddleware(r.cfg.ResponseHeaders),
+		middleware.KillSwitchMiddleware(r.cfg.WellKnown.Enabled, msg),
+	)
+	return middleware.Wrap(h, append(m, responseCompressionMiddleware(r.cfg)...)...)
+}
+
+// wellKnownDocument describes the endpoints registered by NewMux, and the
+// protocols, compression, and limits of the server, for agents discovering
+// them from the server URL.
+func (r *routeBuilder) wellKnownDocument() wellknown.Document {
+	doc := wellknown.Document{
+		Version:   r.info.Version,
+		Endpoints: r.endpoints,
+		// gRPC services are served on the same address as HTTP.
+		Protocols: []string{
+			"elastic/http",
+			"otlp/http", "otlp/grpc",
+			"jaeger/http", "jaeger/grpc",
+			"zipkin/http",
+			"opencensus/grpc",
+		},
+		Compression: wellknown.Compression{
+			Request:  []string{"gzip", "deflate"},
+			Response: []string{},
+		},
+		Limits: wellknown.Limits{
+			MaxEventSize:      r.cfg.MaxEventSize,
+			MaxHeaderSize:     r.cfg.MaxHeaderSize,
+			ReadTimeoutMillis: r.cfg.ReadTimeout.Milliseconds(),
+		},
+	}
+	if r.cfg.ResponseCompression.Enabled {
+		doc.Compression.Response = append(doc.Compression.Response, "gzip")
+	}
+	if r.cfg.RumConfig.IsEnabled() {
+		doc.Limits.RUMEventRateLimit = r.cfg.RumConfig.EventRate.Limit
+	}
+	return doc
+}
+
+// intakeHandler returns an intake handler for processor, which forwards
+// requests to another APM Server if forwarding is enabled.
+func (r *routeBuilder) intakeHandler(processor *stream.Processor) request.Handler {
+	processor.DecodeLimiter = r.decodeLimiter
+	acks := r.backendAcks
+	if processor.IsRUM() {
+		acks = r.rumAcks
+	}
+	if r.forwarder != nil {
+		return intake.ForwardingHandler(processor, r.forwarder, acks)
+	}
+	return intake.Handler(processor, r.batchProcessor, acks)
 }
 
 func (r *routeBuilder) sourcemapHandler() (request.Handler, error) {
@@ -164,32 +451,94 @@ func (r *routeBuilder) sourcemapHandler() (request.Handler, error) {
 
 func (r *routeBuilder) rootHandler() (request.Handler, error) {
 	h := root.Handler(root.HandlerConfig{Version: r.info.Version})
-	return middleware.Wrap(h, rootMiddleware(r.cfg, r.authBuilder.ForAnyOfPrivileges(authorization.ActionAny))...)
+	m := rootMiddleware(r.cfg, r.authBuilder.ForAnyOfPrivileges(authorization.ActionAny))
+	return middleware.Wrap(h, append(m, responseCompressionMiddleware(r.cfg)...)...)
 }
 
 func (r *routeBuilder) backendAgentConfigHandler() (request.Handler, error) {
 	authHandler := r.authBuilder.ForPrivilege(authorization.PrivilegeAgentConfigRead.Action)
-	return agentConfigHandler(r.cfg, authHandler, backendMiddleware)
+	return agentConfigHandler(r.cfg, r.agentcfgFetcher, r.kibanaClient, authHandler, backendMiddleware)
 }
 
 func (r *routeBuilder) rumAgentConfigHandler() (request.Handler, error) {
-	return agentConfigHandler(r.cfg, nil, rumMiddleware)
+	return agentConfigHandler(r.cfg, r.agentcfgFetcher, r.kibanaClient, nil, rumMiddleware)
 }
 
-type middlewareFunc func(*config.Config, *authorization.Handler, map[request.ResultID]*monitoring.Int) []middleware.Middleware
+// newAgentConfigFetcher creates the agent configuration fetcher shared
+// by the agent configuration handlers.
+func (r *routeBuilder) newAgentConfigFetcher() error {
+	if r.cfg.Kibana.Enabled {
+		r.kibanaClient = kibana.NewConnectingClient(&r.cfg.Kibana)
+	}
+	var esClient elasticsearch.Client
+	if r.cfg.AgentConfig.ElasticsearchEnabled() {
+		var err error
+		if esClient, err = elasticsearch.NewClient(r.cfg.AgentConfig.ESConfig); err != nil {
+			return err
+		}
+	}
+	r.agentcfgFetcher = agentcfg.NewElasticsearchFetcher(r.kibanaClient, esClient, agent.FetcherConfig(r.cfg.AgentConfig))
+	return nil
+}
+
+// warmAgentConfig records the services for which agent configuration
+// is fetched, and fetches agent configuration for the services recorded
+// by a previous server, so the first agent requests are served from
+// the cache.
+func warmAgentConfig(logger *logp.Logger, cfg config.WarmupConfig, fetcher *agentcfg.Fetcher) {
+	history := agentcfg.NewServiceHistory(paths.Resolve(paths.Data, cfg.ServicesPath), cfg.MaxServices)
+	if err := history.Load(); err != nil {
+		logger.With(logp.Error(err)).Warn("loading agent configuration service history failed")
+	}
+	fetcher.SetServiceHistory(history)
 
-func agentConfigHandler(cfg *config.Config, authHandler *authorization.Handler, middlewareFunc middlewareFunc) (request.Handler, error) {
-	var client kibana.Client
-	if cfg.Kibana.Enabled {
-		client = kibana.NewConnectingClient(&cfg.Kibana)
+	services := history.Services()
+	if len(services) == 0 {
+		return
 	}
-	h := agent.Handler(client, cfg.AgentConfig, cfg.DefaultServiceEnvironment)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	warmed, err := fetcher.Warm(ctx, services)
+	if err != nil {
+		logger.With(logp.Error(err)).Warnf("warming agent configuration cache failed, warmed %d of %d services", warmed, len(services))
+		return
+	}
+	logger.Infof("Agent configuration cache warmed for %d services", warmed)
+}
+
+type middlewareFunc func(*config.Config, *authorization.Handler, map[request.ResultID]*monitoring.Int) []middleware.Middleware
+
+func agentConfigHandler(
+	cfg *config.Config,
+	fetcher *agentcfg.Fetcher,
+	client kibana.Client,
+	authHandler *authorization.Handler,
+	middlewareFunc middlewareFunc,
+) (request.Handler, error) {
+	h := agent.FetcherHandler(fetcher, client, cfg.AgentConfig, cfg.DefaultServiceEnvironment)
 	msg := "Agent remote configuration is disabled. " +
-		"Configure the `apm-server.kibana` section in apm-server.yml to enable it. " +
+		"Configure the `apm-server.kibana` section in apm-server.yml to enable it, " +
+		"or set `apm-server.agent.config.source` to `elasticsearch`. " +
 		"If you are using a RUM agent, you also need to configure the `apm-server.rum` section. " +
 		"If you are not using remote configuration, you can safely ignore this error."
-	ks := middleware.KillSwitchMiddleware(cfg.Kibana.Enabled, msg)
-	return middleware.Wrap(h, append(middlewareFunc(cfg, authHandler, agent.MonitoringMap), ks)...)
+	ks := middleware.KillSwitchMiddleware(agentConfigEnabled(cfg, fetcher), msg)
+	m := append(middlewareFunc(cfg, authHandler, agent.MonitoringMap), ks)
+	return middleware.Wrap(h, append(m, responseCompressionMiddleware(cfg)...)...)
+}
+
+// agentConfigEnabled reports whether agent remote configuration can be
+// fetched, from Kibana or from Elasticsearch.
+func agentConfigEnabled(cfg *config.Config, fetcher *agentcfg.Fetcher) bool {
+	return cfg.Kibana.Enabled || fetcher.ElasticsearchEnabled()
+}
+
+// responseCompressionMiddleware returns the middleware for compressing
+// responses, if enabled.
+func responseCompressionMiddleware(cfg *config.Config) []middleware.Middleware {
+	if !cfg.ResponseCompression.Enabled {
+		return nil
+	}
+	return []middleware.Middleware{middleware.ResponseCompressionMiddleware(cfg.ResponseCompression.MinSize)}
 }
 
 func apmMiddleware(m map[request.ResultID]*monitoring.Int) []middleware.Middleware {
@@ -213,7 +562,25 @@ func backendMiddleware(cfg *config.Config, auth *authorization.Handler, m map[re
 	return backendMiddleware
 }
 
-func rumMiddleware(cfg *config.Config, _ *authorization.Handler, m map[request.ResultID]*monitoring.Int) []middleware.Middleware {
+func otlpMiddleware(cfg *config.Config, auth *authorization.Handler, m map[request.ResultID]*monitoring.Int) []middleware.Middleware {
+	if !cfg.RumConfig.IsEnabled() {
+		return backendMiddleware(cfg, auth, m)
+	}
+	// With RUM enabled, browsers may send OTLP/HTTP requests, which are
+	// subject to the same CORS configuration as RUM requests. Preflight
+	// requests carry no credentials, so CORS is handled before authorization.
+	otlpMiddleware := append(apmMiddleware(m),
+		middleware.ResponseHeadersMiddleware(cfg.ResponseHeaders),
+		middleware.CrossOriginMiddleware(cfg.RumConfig.AllowOrigins, cfg.RumConfig.AllowHeaders),
+		middleware.AuthorizationMiddleware(auth, true),
+	)
+	if cfg.AugmentEnabled {
+		otlpMiddleware = append(otlpMiddleware, middleware.SystemMetadataMiddleware())
+	}
+	return otlpMiddleware
+}
+
+func rumMiddleware(cfg *config.Config, auth *authorization.Handler, m map[request.ResultID]*monitoring.Int) []middleware.Middleware {
 	msg := "RUM endpoint is disabled. " +
 		"Configure the `apm-server.rum` section in apm-server.yml to enable ingestion of RUM events. " +
 		"If you are not using the RUM agent, you can safely ignore this error."
@@ -225,6 +592,16 @@ func rumMiddleware(cfg *config.Config, _ *authorization.Handler, m map[request.R
 		middleware.CORSMiddleware(cfg.RumConfig.AllowOrigins, cfg.RumConfig.AllowHeaders),
 		middleware.KillSwitchMiddleware(cfg.RumConfig.IsEnabled(), msg),
 	)
+	if auth != nil && cfg.Auth.Anonymous.Enabled {
+		// With anonymous access enabled, requests with credentials are
+		// authorized as for backend agents, and requests without are
+		// restricted to allowed agents and services, and rate limited
+		// separately.
+		rumMiddleware = append(rumMiddleware,
+			middleware.AuthorizationMiddleware(auth, true),
+			middleware.SetAnonymousRateLimitMiddleware(&cfg.Auth.Anonymous.RateLimit),
+		)
+	}
 	if cfg.AugmentEnabled {
 		rumMiddleware = append(rumMiddleware, middleware.UserMetadataMiddleware())
 	}
@@ -238,9 +615,14 @@ func sourcemapMiddleware(cfg *config.Config, auth *authorization.Handler) []midd
 	if cfg.DataStreams.Enabled {
 		msg = "When APM Server is managed by Fleet, Sourcemaps must be uploaded directly to Elasticsearch."
 	}
-	enabled := cfg.RumConfig.IsEnabled() && cfg.RumConfig.SourceMapping.IsEnabled() && !cfg.DataStreams.Enabled
 	return append(backendMiddleware(cfg, auth, sourcemap.MonitoringMap),
-		middleware.KillSwitchMiddleware(enabled, msg))
+		middleware.KillSwitchMiddleware(sourcemapUploadEnabled(cfg), msg))
+}
+
+// sourcemapUploadEnabled reports whether sourcemaps can be uploaded to
+// the server. With data streams, sourcemaps are uploaded to Elasticsearch.
+func sourcemapUploadEnabled(cfg *config.Config) bool {
+	return cfg.RumConfig.IsEnabled() && cfg.RumConfig.SourceMapping.IsEnabled() && !cfg.DataStreams.Enabled
 }
 
 func rootMiddleware(cfg *config.Config, auth *authorization.Handler) []middleware.Middleware {
@@ -27,6 +27,7 @@ import (
 
 	"github.com/elastic/apm-server/approvaltest"
 	"github.com/elastic/apm-server/beater/api/intake"
+	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/middleware"
@@ -61,7 +62,7 @@ func TestOPTIONS(t *testing.T) {
 	c.Reset(w, httptest.NewRequest(http.MethodOptions, "/", nil))
 	h(c)
 
-	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
 	done <- struct{}{}
 }
 
@@ -74,6 +75,56 @@ func TestRUMHandler_NoAuthorizationRequired(t *testing.T) {
 	approvaltest.ApproveJSON(t, approvalPathIntakeRUM(t.Name()), rec.Body.Bytes())
 }
 
+func TestRUMHandler_AnonymousAuth(t *testing.T) {
+	cfg := cfgEnabledRUM()
+	cfg.SecretToken = "1234"
+	cfg.Auth.Anonymous.Enabled = true
+	cfg.Auth.Anonymous.RateLimit.Limit = 1
+	h := newTestMux(t, cfg)
+
+	for _, path := range []string{IntakeRUMPath, IntakeRUMV3Path} {
+		// Requests without credentials are permitted.
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.NotEqual(t, http.StatusUnauthorized, w.Code)
+
+		// Requests with invalid credentials are not.
+		req = httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set(headers.Authorization, "Bearer wrong")
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRUMHandler_AnonymousRateLimit(t *testing.T) {
+	cfg := cfgEnabledRUM()
+	cfg.SecretToken = "1234"
+	cfg.Auth.Anonymous.Enabled = true
+	cfg.Auth.Anonymous.RateLimit.Limit = 7
+	builder, err := authorization.NewBuilder(cfg)
+	require.NoError(t, err)
+	authHandler := builder.ForAnonymousPrivilege(authorization.PrivilegeEventWrite.Action)
+
+	var limit float64
+	h, err := middleware.Wrap(func(c *request.Context) {
+		limit = float64(c.RateLimiter.Limit())
+	}, rumMiddleware(cfg, authHandler, intake.MonitoringMap)...)
+	require.NoError(t, err)
+
+	c := request.NewContext()
+	c.Reset(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	h(c)
+	assert.Equal(t, float64(7), limit)
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(headers.Authorization, "Bearer 1234")
+	c.Reset(httptest.NewRecorder(), req)
+	h(c)
+	assert.Equal(t, float64(cfg.RumConfig.EventRate.Limit), limit)
+}
+
 func TestRUMHandler_KillSwitchMiddleware(t *testing.T) {
 	t.Run("OffRum", func(t *testing.T) {
 		rec, err := requestToMuxerWithPattern(config.DefaultConfig(), IntakeRUMPath)
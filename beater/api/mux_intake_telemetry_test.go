@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/api/intaketelemetry"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+)
+
+func TestIntakeTelemetryHandler_KillSwitchMiddleware(t *testing.T) {
+	rec, err := requestToMuxerWithHeader(config.DefaultConfig(), IntakeTelemetryPath, http.MethodGet, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Intake telemetry endpoint is disabled")
+}
+
+func TestIntakeTelemetryHandler_AuthorizationMiddleware(t *testing.T) {
+	cfg := cfgEnabledIntakeTelemetry()
+	cfg.SecretToken = "1234"
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		rec, err := requestToMuxerWithHeader(cfg, IntakeTelemetryPath, http.MethodGet, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Authorized", func(t *testing.T) {
+		h := map[string]string{headers.Authorization: "Bearer 1234"}
+		rec, err := requestToMuxerWithHeader(cfg, IntakeTelemetryPath, http.MethodGet, h)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"agents":[]`)
+	})
+}
+
+func TestIntakeTelemetryHandler_RecordsUnauthorized(t *testing.T) {
+	cfg := cfgEnabledIntakeTelemetry()
+	cfg.SecretToken = "1234"
+	mux := newTestMux(t, cfg)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, IntakePath, nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, IntakeTelemetryPath, nil)
+	req.Header.Set(headers.Authorization, "Bearer 1234")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var report intaketelemetry.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	require.Len(t, report.Agents, 1)
+	assert.Equal(t, int64(1), report.Agents[0].Requests)
+	assert.Equal(t, int64(1), report.Agents[0].Errors)
+}
+
+func cfgEnabledIntakeTelemetry() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.IntakeTelemetry.Enabled = true
+	return cfg
+}
@@ -0,0 +1,97 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/otlp"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestOTLPHandler_AuthorizationMiddleware(t *testing.T) {
+	for _, path := range []string{OTLPTracesPath, OTLPMetricsPath, OTLPLogsPath} {
+		t.Run(path, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.SecretToken = "1234"
+			rec, err := requestToMuxerWithPattern(cfg, path)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+			h := map[string]string{
+				headers.Authorization: "Bearer 1234",
+				headers.ContentType:   "application/x-protobuf",
+			}
+			rec, err = requestToMuxerWithHeader(cfg, path, http.MethodPost, h)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
+
+func TestOTLPHandler_CORSMiddleware(t *testing.T) {
+	cfg := cfgEnabledRUM()
+	cfg.SecretToken = "1234"
+	cfg.RumConfig.AllowOrigins = []string{"foo"}
+	h := newTestMux(t, cfg)
+
+	for _, path := range []string{OTLPTracesPath, OTLPMetricsPath, OTLPLogsPath} {
+		// Preflight requests are answered without credentials.
+		req := httptest.NewRequest(http.MethodOptions, path, nil)
+		req.Header.Set(headers.Origin, "foo")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "foo", w.Header().Get(headers.AccessControlAllowOrigin))
+
+		req = httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set(headers.Origin, "bar")
+		req.Header.Set(headers.Authorization, "Bearer 1234")
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		// Requests without an origin are not subject to CORS.
+		req = httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set(headers.Authorization, "Bearer 1234")
+		req.Header.Set(headers.ContentType, "application/x-protobuf")
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestOTLPHandler_MonitoringMiddleware(t *testing.T) {
+	// send GET request resulting in 405 MethodNotAllowed error
+	expected := map[request.ResultID]int{
+		request.IDRequestCount:                   1,
+		request.IDResponseCount:                  1,
+		request.IDResponseErrorsCount:            1,
+		request.IDResponseErrorsMethodNotAllowed: 1,
+	}
+	testMonitoringMiddleware(t, OTLPTracesPath, otlp.HTTPTracesMonitoringMap, expected)
+	testMonitoringMiddleware(t, OTLPMetricsPath, otlp.HTTPMetricsMonitoringMap, expected)
+	testMonitoringMiddleware(t, OTLPLogsPath, otlp.HTTPLogsMonitoringMap, expected)
+}
@@ -51,6 +51,23 @@ func TestRootHandler_AuthorizationMiddleware(t *testing.T) {
 	})
 }
 
+func TestRootHandler_ResponseCompression(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ResponseCompression.MinSize = 1
+	h := map[string]string{headers.AcceptEncoding: "gzip"}
+
+	rec, err := requestToMuxerWithHeader(cfg, RootPath, http.MethodGet, h)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get(headers.ContentEncoding))
+
+	cfg.ResponseCompression.Enabled = false
+	rec, err = requestToMuxerWithHeader(cfg, RootPath, http.MethodGet, h)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(headers.ContentEncoding))
+}
+
 func TestRootHandler_PanicMiddleware(t *testing.T) {
 	testPanicMiddleware(t, "/", approvalPathRoot(t.Name()))
 }
@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+)
+
+func TestSamplingDecisionHandler_KillSwitchMiddleware(t *testing.T) {
+	rec, err := requestToMuxerWithHeader(config.DefaultConfig(), SamplingDecisionPath+"?service.name=opbeans", http.MethodGet, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Sampling decision endpoint is disabled")
+}
+
+func TestSamplingDecisionHandler_AuthorizationMiddleware(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Sampling.Head.Enabled = true
+	cfg.SecretToken = "1234"
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		rec, err := requestToMuxerWithHeader(cfg, SamplingDecisionPath+"?service.name=opbeans", http.MethodGet, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Authorized", func(t *testing.T) {
+		h := map[string]string{headers.Authorization: "Bearer 1234"}
+		rec, err := requestToMuxerWithHeader(cfg, SamplingDecisionPath+"?service.name=opbeans", http.MethodGet, h)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"sampled":true,"sample_rate":1,"reason":"default"}`, rec.Body.String())
+	})
+}
@@ -0,0 +1,65 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch"
+)
+
+func TestWarmupAgentConfig(t *testing.T) {
+	var searches int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&searches, 1)
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Write([]byte(`{"hits":{"hits":[{"_id":"1","_source":{"settings":{"sampling_rate":0.5},"etag":"abc"}}]}}`))
+	}))
+	defer srv.Close()
+
+	servicesPath := filepath.Join(t.TempDir(), "services.json")
+	require.NoError(t, ioutil.WriteFile(servicesPath, []byte(`{"services":[
+		{"name":"opbeans","last_seen":"2021-01-01T12:00:00Z"},
+		{"name":"opbeans-go","environment":"production","last_seen":"2021-01-01T11:00:00Z"}
+	]}`), 0600))
+
+	cfg := config.DefaultConfig()
+	cfg.AgentConfig.Source = config.AgentConfigSourceElasticsearch
+	cfg.AgentConfig.ESConfig = elasticsearch.DefaultConfig()
+	cfg.AgentConfig.ESConfig.Hosts = elasticsearch.Hosts{srv.URL}
+	cfg.Warmup.Enabled = true
+	cfg.Warmup.ServicesPath = servicesPath
+
+	// Agent configuration for the persisted services is fetched when
+	// the server starts, and served from the cache afterwards.
+	r := httptest.NewRequest(http.MethodGet, AgentConfigPath+"?service.name=opbeans-go&service.environment=production", nil)
+	rec, err := requestToMuxer(cfg, r)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"sampling_rate":"0.5"}`, rec.Body.String())
+	assert.Equal(t, int64(2), atomic.LoadInt64(&searches))
+}
@@ -0,0 +1,91 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/api/wellknown"
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestWellKnownHandler_KillSwitchMiddleware(t *testing.T) {
+	rec, err := requestToMuxerWithHeader(config.DefaultConfig(), WellKnownPath, http.MethodGet, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Well-known endpoint is disabled")
+}
+
+func TestWellKnownHandler(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.WellKnown.Enabled = true
+	cfg.SecretToken = "1234"
+	rumEnabled := true
+	cfg.RumConfig.Enabled = &rumEnabled
+
+	// The document is served without authorization, so agents can
+	// discover the server's capabilities before sending data.
+	rec, err := requestToMuxerWithHeader(cfg, WellKnownPath, http.MethodGet, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc wellknown.Document
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "1.2.3", doc.Version)
+	assert.Equal(t, wellknown.Endpoint{Path: IntakePath, Enabled: true}, doc.Endpoints["intake"])
+	assert.Equal(t, wellknown.Endpoint{Path: IntakeRUMPath, Enabled: true}, doc.Endpoints["intake_rum"])
+	assert.Equal(t, wellknown.Endpoint{Path: AgentConfigPath, Enabled: false}, doc.Endpoints["agent_config"])
+	assert.Equal(t, wellknown.Endpoint{Path: SamplingDecisionPath, Enabled: false}, doc.Endpoints["sampling"])
+	assert.Contains(t, doc.Protocols, "otlp/grpc")
+	assert.Equal(t, wellknown.Compression{Request: []string{"gzip", "deflate"}, Response: []string{"gzip"}}, doc.Compression)
+	assert.Equal(t, wellknown.Limits{
+		MaxEventSize:      cfg.MaxEventSize,
+		MaxHeaderSize:     cfg.MaxHeaderSize,
+		ReadTimeoutMillis: cfg.ReadTimeout.Milliseconds(),
+		RUMEventRateLimit: cfg.RumConfig.EventRate.Limit,
+	}, doc.Limits)
+}
+
+func TestWellKnownHandlerEndpointsMatchRoutes(t *testing.T) {
+	for name, rumEnabled := range map[string]bool{"rum enabled": true, "rum disabled": false} {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.WellKnown.Enabled = true
+			cfg.RumConfig.Enabled = &rumEnabled
+
+			rec, err := requestToMuxerWithHeader(cfg, WellKnownPath, http.MethodGet, nil)
+			require.NoError(t, err)
+			var doc wellknown.Document
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+			assert.Len(t, doc.Endpoints, 13)
+
+			// Endpoints are advertised as disabled if and only if
+			// requests are rejected by their kill switch.
+			for name, endpoint := range doc.Endpoints {
+				rec, err := requestToMuxerWithHeader(cfg, endpoint.Path, http.MethodPost, nil)
+				require.NoError(t, err)
+				assert.Equal(t, !endpoint.Enabled, rec.Code == http.StatusForbidden, name)
+			}
+		})
+	}
+}
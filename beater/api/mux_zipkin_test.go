@@ -0,0 +1,59 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/beater/zipkin"
+)
+
+func TestZipkinHandler_AuthorizationMiddleware(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SecretToken = "1234"
+	rec, err := requestToMuxerWithPattern(cfg, ZipkinSpansPath)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// The empty body fails to decode once the request is authorized.
+	h := map[string]string{
+		headers.Authorization: "Bearer 1234",
+		headers.ContentType:   "application/json",
+	}
+	rec, err = requestToMuxerWithHeader(cfg, ZipkinSpansPath, http.MethodPost, h)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestZipkinHandler_MonitoringMiddleware(t *testing.T) {
+	// send GET request resulting in 405 MethodNotAllowed error,
+	// which is counted only as an error response by the Zipkin registry
+	expected := map[request.ResultID]int{
+		request.IDRequestCount:        1,
+		request.IDResponseCount:       1,
+		request.IDResponseErrorsCount: 1,
+	}
+	testMonitoringMiddleware(t, ZipkinSpansPath, zipkin.MonitoringMap, expected)
+}
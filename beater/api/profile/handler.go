@@ -28,6 +28,7 @@ import (
 
 	"github.com/elastic/beats/v7/libbeat/monitoring"
 
+	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/decoder"
@@ -185,6 +186,13 @@ func Handler(processor model.BatchProcessor) request.Handler {
 					err: err,
 				}
 			}
+			var serviceErr *authorization.ServiceUnauthorizedError
+			if errors.As(err, &serviceErr) {
+				return nil, requestError{
+					id:  request.IDResponseErrorsForbidden,
+					err: err,
+				}
+			}
 			return nil, err
 		}
 		return &result{Accepted: len(modelProfiles)}, nil
@@ -35,7 +35,7 @@ func TestRootHandler(t *testing.T) {
 		Handler(HandlerConfig{Version: "1.2.3"})(c)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
-		assert.Equal(t, `{"error":"404 page not found"}`+"\n", w.Body.String())
+		assert.Equal(t, `{"code":"ERR_NOT_FOUND","error":"404 page not found"}`+"\n", w.Body.String())
 	})
 
 	t.Run("ok", func(t *testing.T) {
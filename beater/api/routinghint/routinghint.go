@@ -0,0 +1,139 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package routinghint provides a routing hint in intake responses, derived
+// from the trace IDs of the request's events, so that load balancers using
+// consistent hashing can route all events of a trace to the same server.
+package routinghint
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sync"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/middleware"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model"
+)
+
+type hinterKey struct{}
+
+// hinter records the first trace ID processed for a request, and sets the
+// routing hint derived from it in the response headers.
+//
+// Events may be processed concurrently with writing the response, e.g. when
+// events are acknowledged asynchronously, so the trace ID is recorded under
+// a lock and the headers are only modified on the goroutine writing them.
+type hinter struct {
+	mu      sync.Mutex
+	cfg     config.RoutingHintConfig
+	traceID string
+	written bool
+}
+
+// set records traceID for the routing hint, unless a trace ID has already
+// been recorded or the response headers have already been written.
+func (h *hinter) set(traceID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.written || h.traceID != "" {
+		return
+	}
+	h.traceID = traceID
+}
+
+// writeHeader sets the routing hint in header, if a trace ID has been
+// recorded, and prevents further trace IDs from being recorded.
+func (h *hinter) writeHeader(header http.Header) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.written = true
+	if h.traceID == "" {
+		return
+	}
+	hint := Hint(h.traceID)
+	if h.cfg.Header != "" {
+		header.Set(h.cfg.Header, hint)
+	}
+	if h.cfg.Cookie != "" {
+		cookie := http.Cookie{Name: h.cfg.Cookie, Value: hint, Path: "/", HttpOnly: true}
+		header.Add("Set-Cookie", cookie.String())
+	}
+}
+
+// Hint returns the routing hint for traceID: the hex-encoded 64-bit FNV-1a
+// hash of the trace ID.
+func Hint(traceID string) string {
+	h := fnv.New64a()
+	h.Write([]byte(traceID))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Middleware returns a middleware.Middleware which sends a routing hint,
+// as configured by cfg, in responses to requests with events processed by
+// a processor returned by BatchProcessor.
+//
+// The routing hint is only sent if a traced event has been processed before
+// the response headers are written. No routing hint is sent for streamed
+// responses, or for events acknowledged asynchronously, as their headers are
+// written before the events are processed.
+func Middleware(cfg config.RoutingHintConfig) middleware.Middleware {
+	return func(h request.Handler) (request.Handler, error) {
+		return func(c *request.Context) {
+			hinter := &hinter{cfg: cfg}
+			c.BeforeWriteHeader(hinter.writeHeader)
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), hinterKey{}, hinter))
+			h(c)
+		}, nil
+	}
+}
+
+// BatchProcessor returns a model.BatchProcessor which records the trace ID
+// of the first traced event for the routing hint, if the context holds a
+// request from Middleware, and then calls next.
+func BatchProcessor(next model.BatchProcessor) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		if hinter, ok := ctx.Value(hinterKey{}).(*hinter); ok {
+			if traceID := firstTraceID(batch); traceID != "" {
+				hinter.set(traceID)
+			}
+		}
+		return next.ProcessBatch(ctx, batch)
+	})
+}
+
+func firstTraceID(batch *model.Batch) string {
+	for _, event := range batch.Transactions {
+		if event.TraceID != "" {
+			return event.TraceID
+		}
+	}
+	for _, event := range batch.Spans {
+		if event.TraceID != "" {
+			return event.TraceID
+		}
+	}
+	for _, event := range batch.Errors {
+		if event.TraceID != "" {
+			return event.TraceID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package routinghint
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/middleware"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model"
+)
+
+func TestHint(t *testing.T) {
+	hint := Hint("0123456789abcdef0123456789abcdef")
+	assert.Len(t, hint, 16)
+	assert.Equal(t, hint, Hint("0123456789abcdef0123456789abcdef"))
+	assert.NotEqual(t, hint, Hint("fedcba9876543210fedcba9876543210"))
+}
+
+func TestMiddleware(t *testing.T) {
+	processor := BatchProcessor(model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		return nil
+	}))
+	for name, test := range map[string]struct {
+		cfg    config.RoutingHintConfig
+		batch  model.Batch
+		header string
+		cookie string
+	}{
+		"header": {
+			cfg: config.RoutingHintConfig{Enabled: true, Header: "Elastic-Apm-Routing-Hint"},
+			batch: model.Batch{
+				Metricsets: []*model.Metricset{{}},
+				Spans:      []*model.Span{{TraceID: "trace_b"}},
+				Errors:     []*model.Error{{TraceID: "trace_c"}},
+			},
+			header: Hint("trace_b"),
+		},
+		"cookie": {
+			cfg:    config.RoutingHintConfig{Enabled: true, Cookie: "apm_route"},
+			batch:  model.Batch{Transactions: []*model.Transaction{{TraceID: "trace_a"}}},
+			cookie: Hint("trace_a"),
+		},
+		"no_trace": {
+			cfg:   config.RoutingHintConfig{Enabled: true, Header: "Elastic-Apm-Routing-Hint", Cookie: "apm_route"},
+			batch: model.Batch{Metricsets: []*model.Metricset{{}}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h, err := middleware.Wrap(func(c *request.Context) {
+				batch := test.batch
+				require.NoError(t, processor.ProcessBatch(c.Request.Context(), &batch))
+				// Only the first trace ID is used.
+				require.NoError(t, processor.ProcessBatch(c.Request.Context(), &model.Batch{
+					Transactions: []*model.Transaction{{TraceID: "trace_z"}},
+				}))
+				c.Result.SetDefault(request.IDResponseValidAccepted)
+				c.Write()
+			}, Middleware(test.cfg))
+			require.NoError(t, err)
+
+			c := request.NewContext()
+			rec := httptest.NewRecorder()
+			c.Reset(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+			h(c)
+
+			resp := rec.Result()
+			if test.header != "" {
+				assert.Equal(t, test.header, resp.Header.Get(test.cfg.Header))
+			}
+			if test.cookie != "" {
+				cookies := resp.Cookies()
+				require.Len(t, cookies, 1)
+				assert.Equal(t, test.cfg.Cookie, cookies[0].Name)
+				assert.Equal(t, test.cookie, cookies[0].Value)
+			}
+			if test.header == "" && test.cookie == "" {
+				// The first batch has no trace ID, so the second batch's is used.
+				assert.Equal(t, Hint("trace_z"), resp.Header.Get("Elastic-Apm-Routing-Hint"))
+			}
+		})
+	}
+}
+
+func TestMiddlewareProcessedAfterWrite(t *testing.T) {
+	processor := BatchProcessor(model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		return nil
+	}))
+	cfg := config.RoutingHintConfig{Enabled: true, Header: "Elastic-Apm-Routing-Hint"}
+	h, err := middleware.Wrap(func(c *request.Context) {
+		// Events processed after the response headers have been
+		// written, as for streamed or asynchronously acknowledged
+		// requests, cannot be used for the routing hint.
+		w := c.Stream(http.StatusOK)
+		require.NoError(t, processor.ProcessBatch(c.Request.Context(), &model.Batch{
+			Transactions: []*model.Transaction{{TraceID: "trace_a"}},
+		}))
+		w.Write([]byte("{}"))
+	}, Middleware(cfg))
+	require.NoError(t, err)
+
+	c := request.NewContext()
+	rec := httptest.NewRecorder()
+	c.Reset(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	h(c)
+	assert.Empty(t, rec.Result().Header.Get("Elastic-Apm-Routing-Hint"))
+}
+
+func TestBatchProcessorNoMiddleware(t *testing.T) {
+	var processed bool
+	processor := BatchProcessor(model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		processed = true
+		return nil
+	}))
+	err := processor.ProcessBatch(context.Background(), &model.Batch{
+		Transactions: []*model.Transaction{{TraceID: "trace_a"}},
+	})
+	require.NoError(t, err)
+	assert.True(t, processed)
+}
@@ -0,0 +1,188 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+// maxBudgets limits the number of service budgets tracked by a Decider.
+// Service names are chosen by clients, so budgets are never evicted, as
+// that would allow clients to reset the budgets of other services by
+// sending queries for many services. Once the limit is reached, services
+// without a budget share a budget per policy.
+const maxBudgets = 10000
+
+// Reason describes why a sampling decision was made.
+type Reason string
+
+const (
+	// ReasonUpstream indicates the decision follows the upstream
+	// trace context's sampled flag.
+	ReasonUpstream Reason = "upstream"
+
+	// ReasonPolicy indicates the decision was made by applying
+	// a policy's sample rate.
+	ReasonPolicy Reason = "policy"
+
+	// ReasonBudget indicates the trace was not sampled because
+	// the budget of sampled traces was exhausted.
+	ReasonBudget Reason = "budget"
+
+	// ReasonDefault indicates no policy matched, and the trace was sampled.
+	ReasonDefault Reason = "default"
+)
+
+// Query holds the attributes of a new trace for which a sampling decision is requested.
+type Query struct {
+	Service struct {
+		Name        string `json:"name"`
+		Environment string `json:"environment,omitempty"`
+	} `json:"service"`
+	Trace struct {
+		Name string `json:"name,omitempty"`
+	} `json:"trace"`
+
+	// Traceparent holds the W3C traceparent of the upstream trace
+	// context, if any.
+	Traceparent string `json:"traceparent,omitempty"`
+}
+
+// Decision holds a sampling decision.
+type Decision struct {
+	Sampled    bool    `json:"sampled"`
+	SampleRate float64 `json:"sample_rate"`
+	Reason     Reason  `json:"reason"`
+}
+
+// Decider makes sampling decisions for new traces based on head-sampling
+// policies, and on budgets of sampled traces per policy and service.
+type Decider struct {
+	policies   []config.HeadSamplingPolicy
+	maxBudgets int
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	budgets map[budgetKey]*rate.Limiter
+}
+
+// NewDecider returns a new Decider for the given config.
+func NewDecider(cfg config.HeadSamplingConfig) *Decider {
+	return &Decider{
+		policies:   cfg.Policies,
+		maxBudgets: maxBudgets,
+		rand:       rand.New(rand.NewSource(rand.Int63())),
+		budgets:    make(map[budgetKey]*rate.Limiter),
+	}
+}
+
+type budgetKey struct {
+	policy             int
+	serviceName        string
+	serviceEnvironment string
+}
+
+// Decide returns the sampling decision for a new trace.
+func (d *Decider) Decide(q Query) Decision {
+	if sampled, ok := upstreamSampled(q.Traceparent); ok {
+		sampleRate := 0.0
+		if sampled {
+			sampleRate = 1
+		}
+		return Decision{Sampled: sampled, SampleRate: sampleRate, Reason: ReasonUpstream}
+	}
+
+	for i, policy := range d.policies {
+		if !matches(policy, q) {
+			continue
+		}
+		decision := Decision{SampleRate: policy.SampleRate, Reason: ReasonPolicy}
+		d.mu.Lock()
+		decision.Sampled = d.rand.Float64() < policy.SampleRate
+		d.mu.Unlock()
+		if decision.Sampled && policy.MaxTracesPerSecond > 0 {
+			key := budgetKey{policy: i, serviceName: q.Service.Name, serviceEnvironment: q.Service.Environment}
+			if !d.budget(key, policy.MaxTracesPerSecond).Allow() {
+				decision.Sampled = false
+				decision.Reason = ReasonBudget
+			}
+		}
+		return decision
+	}
+	return Decision{Sampled: true, SampleRate: 1, Reason: ReasonDefault}
+}
+
+func (d *Decider) budget(key budgetKey, maxPerSecond float64) *rate.Limiter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if limiter, ok := d.budgets[key]; ok {
+		return limiter
+	}
+	if len(d.budgets) >= d.maxBudgets {
+		// Use the policy's shared budget. Queries always specify
+		// a service name, so the key cannot clash with a service's.
+		// Shared budgets exceed the limit by at most one per policy.
+		key = budgetKey{policy: key.policy}
+		if limiter, ok := d.budgets[key]; ok {
+			return limiter
+		}
+	}
+	burst := int(maxPerSecond)
+	if burst < 1 {
+		burst = 1
+	}
+	limiter := rate.NewLimiter(rate.Limit(maxPerSecond), burst)
+	d.budgets[key] = limiter
+	return limiter
+}
+
+func matches(policy config.HeadSamplingPolicy, q Query) bool {
+	if policy.Service.Name != "" && policy.Service.Name != q.Service.Name {
+		return false
+	}
+	if policy.Service.Environment != "" && policy.Service.Environment != q.Service.Environment {
+		return false
+	}
+	if policy.Trace.Name != "" && policy.Trace.Name != q.Trace.Name {
+		return false
+	}
+	return true
+}
+
+// upstreamSampled parses the sampled flag of a W3C traceparent,
+// returning false for the second result if traceparent is empty
+// or invalid.
+func upstreamSampled(traceparent string) (sampled bool, ok bool) {
+	// version-traceid-parentid-flags
+	parts := strings.Split(traceparent, "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return false, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return false, false
+	}
+	return flags[0]&0x01 == 0x01, true
+}
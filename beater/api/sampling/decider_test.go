@@ -0,0 +1,135 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func newTestDecider(t *testing.T, policies ...config.HeadSamplingPolicy) *Decider {
+	d := NewDecider(config.HeadSamplingConfig{Enabled: true, Policies: policies})
+	d.rand = rand.New(rand.NewSource(0))
+	return d
+}
+
+func newQuery(serviceName, serviceEnvironment, traceName string) Query {
+	var q Query
+	q.Service.Name = serviceName
+	q.Service.Environment = serviceEnvironment
+	q.Trace.Name = traceName
+	return q
+}
+
+func TestDeciderUpstream(t *testing.T) {
+	var policy config.HeadSamplingPolicy // never sample
+	d := newTestDecider(t, policy)
+
+	q := newQuery("opbeans", "", "")
+	q.Traceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	assert.Equal(t, Decision{Sampled: true, SampleRate: 1, Reason: ReasonUpstream}, d.Decide(q))
+
+	q.Traceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00"
+	assert.Equal(t, Decision{Sampled: false, SampleRate: 0, Reason: ReasonUpstream}, d.Decide(q))
+
+	// invalid traceparents are ignored
+	q.Traceparent = "00-0af7651916cd43dd8448eb211c80319c-01"
+	assert.Equal(t, Decision{Sampled: false, SampleRate: 0, Reason: ReasonPolicy}, d.Decide(q))
+}
+
+func TestDeciderPolicies(t *testing.T) {
+	var checkout, production config.HeadSamplingPolicy
+	checkout.Service.Name = "opbeans"
+	checkout.Trace.Name = "POST /checkout"
+	checkout.SampleRate = 1
+	production.Service.Environment = "production"
+	production.SampleRate = 0
+	d := newTestDecider(t, checkout, production)
+
+	for _, tc := range []struct {
+		query    Query
+		expected Decision
+	}{{
+		query:    newQuery("opbeans", "production", "POST /checkout"),
+		expected: Decision{Sampled: true, SampleRate: 1, Reason: ReasonPolicy},
+	}, {
+		query:    newQuery("opbeans", "production", "GET /"),
+		expected: Decision{Sampled: false, SampleRate: 0, Reason: ReasonPolicy},
+	}, {
+		query:    newQuery("opbeans", "staging", "GET /"),
+		expected: Decision{Sampled: true, SampleRate: 1, Reason: ReasonDefault},
+	}} {
+		assert.Equal(t, tc.expected, d.Decide(tc.query), tc.query)
+	}
+}
+
+func TestDeciderSampleRate(t *testing.T) {
+	var policy config.HeadSamplingPolicy
+	policy.SampleRate = 0.25
+	d := newTestDecider(t, policy)
+
+	var sampled int
+	for i := 0; i < 10000; i++ {
+		if d.Decide(newQuery("opbeans", "", "")).Sampled {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 2500, sampled, 200)
+}
+
+func TestDeciderBudget(t *testing.T) {
+	var policy config.HeadSamplingPolicy
+	policy.SampleRate = 1
+	policy.MaxTracesPerSecond = 2
+	d := newTestDecider(t, policy)
+
+	decide := func(serviceName string) Decision {
+		return d.Decide(newQuery(serviceName, "", ""))
+	}
+	assert.True(t, decide("opbeans").Sampled)
+	assert.True(t, decide("opbeans").Sampled)
+	assert.Equal(t, Decision{Sampled: false, SampleRate: 1, Reason: ReasonBudget}, decide("opbeans"))
+
+	// budgets are tracked per service
+	assert.True(t, decide("opbeans-rum").Sampled)
+}
+
+func TestDeciderMaxBudgets(t *testing.T) {
+	var policy config.HeadSamplingPolicy
+	policy.SampleRate = 1
+	policy.MaxTracesPerSecond = 1
+	d := newTestDecider(t, policy)
+	d.maxBudgets = 2
+
+	decide := func(serviceName string) Decision {
+		return d.Decide(newQuery(serviceName, "", ""))
+	}
+	assert.True(t, decide("opbeans").Sampled)
+	assert.True(t, decide("opbeans-rum").Sampled)
+
+	// Further services share a budget, and do not evict the
+	// budgets of the services already tracked.
+	assert.True(t, decide("a").Sampled)
+	assert.Equal(t, ReasonBudget, decide("b").Reason)
+	assert.Equal(t, ReasonBudget, decide("opbeans").Reason)
+	assert.Len(t, d.budgets, 3)
+}
@@ -0,0 +1,114 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/convert"
+)
+
+var (
+	// MonitoringMap holds a mapping for request.IDs to monitoring counters
+	MonitoringMap = request.DefaultMonitoringMapForRegistry(registry)
+	registry      = monitoring.Default.NewRegistry("apm-server.head_sampling")
+
+	sampledCounter   = monitoring.NewInt(registry, "decisions.sampled")
+	unsampledCounter = monitoring.NewInt(registry, "decisions.unsampled")
+)
+
+const (
+	serviceNameParam        = "service.name"
+	serviceEnvironmentParam = "service.environment"
+	traceNameParam          = "trace.name"
+	traceparentParam        = "traceparent"
+
+	// maxQuerySize holds the maximum size of a POST request body.
+	maxQuerySize = 64 * 1024
+)
+
+// errQueryTooLarge is returned for POST request bodies exceeding maxQuerySize.
+var errQueryTooLarge = errors.New("request body too large")
+
+// Handler returns a request.Handler for answering sampling decision requests.
+//
+// If a query does not specify a service environment, defaultServiceEnvironment
+// is used for matching policies.
+func Handler(decider *Decider, defaultServiceEnvironment string) request.Handler {
+	return func(c *request.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodPost {
+			c.Result.SetDefault(request.IDResponseErrorsMethodNotAllowed)
+			c.Write()
+			return
+		}
+		query, err := buildQuery(c.Request)
+		if err == errQueryTooLarge {
+			c.Result.SetWithError(request.IDResponseErrorsRequestTooLarge, err)
+			c.Write()
+			return
+		} else if err != nil {
+			c.Result.SetWithError(request.IDResponseErrorsInvalidQuery, err)
+			c.Write()
+			return
+		}
+		if query.Service.Environment == "" {
+			query.Service.Environment = defaultServiceEnvironment
+		}
+		decision := decider.Decide(query)
+		if decision.Sampled {
+			sampledCounter.Inc()
+		} else {
+			unsampledCounter.Inc()
+		}
+		c.Result.SetWithBody(request.IDResponseValidOK, decision)
+		c.Write()
+	}
+}
+
+func buildQuery(r *http.Request) (Query, error) {
+	var query Query
+	if r.Method == http.MethodPost {
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxQuerySize+1))
+		if err != nil {
+			return query, err
+		}
+		if len(body) > maxQuerySize {
+			return query, errQueryTooLarge
+		}
+		if err := convert.FromBytes(body, &query, nil); err != nil {
+			return query, err
+		}
+	} else {
+		params := r.URL.Query()
+		query.Service.Name = params.Get(serviceNameParam)
+		query.Service.Environment = params.Get(serviceEnvironmentParam)
+		query.Trace.Name = params.Get(traceNameParam)
+		query.Traceparent = params.Get(traceparentParam)
+	}
+	if query.Service.Name == "" {
+		return query, errors.New(serviceNameParam + " is required")
+	}
+	return query, nil
+}
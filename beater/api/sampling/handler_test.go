@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestHandler(t *testing.T) {
+	var policy config.HeadSamplingPolicy
+	policy.Service.Environment = "production"
+	policy.SampleRate = 0
+	h := Handler(newTestDecider(t, policy), "production")
+
+	for name, r := range map[string]*http.Request{
+		"GET":  httptest.NewRequest(http.MethodGet, "/?service.name=opbeans&trace.name=GET+/", nil),
+		"POST": httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"service":{"name":"opbeans"},"trace":{"name":"GET /"}}`)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := request.NewContext()
+			w := httptest.NewRecorder()
+			c.Reset(w, r)
+			h(c)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			// the default service environment is used for matching policies
+			var decision Decision
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &decision))
+			assert.Equal(t, Decision{Sampled: false, SampleRate: 0, Reason: ReasonPolicy}, decision)
+		})
+	}
+}
+
+func TestHandlerInvalidQuery(t *testing.T) {
+	h := Handler(newTestDecider(t), "")
+	for name, tc := range map[string]struct {
+		r    *http.Request
+		code int
+	}{
+		"MissingServiceName": {
+			r:    httptest.NewRequest(http.MethodGet, "/", nil),
+			code: http.StatusBadRequest,
+		},
+		"InvalidBody": {
+			r:    httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{`)),
+			code: http.StatusBadRequest,
+		},
+		"BodyTooLarge": {
+			r: httptest.NewRequest(http.MethodPost, "/", strings.NewReader(
+				`{"service":{"name":"`+strings.Repeat("x", maxQuerySize)+`"}}`,
+			)),
+			code: http.StatusRequestEntityTooLarge,
+		},
+		"MethodNotAllowed": {
+			r:    httptest.NewRequest(http.MethodPut, "/", nil),
+			code: http.StatusMethodNotAllowed,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := request.NewContext()
+			w := httptest.NewRecorder()
+			c.Reset(w, tc.r)
+			h(c)
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package wellknown
+
+import (
+	"net/http"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/request"
+)
+
+var (
+	// MonitoringMap holds a mapping for request.IDs to monitoring counters
+	MonitoringMap = request.DefaultMonitoringMapForRegistry(registry)
+	registry      = monitoring.Default.NewRegistry("apm-server.well_known")
+)
+
+// Document describes the capabilities of the server, so agents configured
+// with only the server URL can discover where and how to send data.
+type Document struct {
+	// Version holds the APM Server version.
+	Version string `json:"version"`
+
+	// Endpoints holds the endpoints of the server, keyed by name.
+	Endpoints map[string]Endpoint `json:"endpoints"`
+
+	// Protocols holds the names of the protocols accepted by the server,
+	// such as "otlp/grpc".
+	Protocols []string `json:"protocols"`
+
+	Compression Compression `json:"compression"`
+	Limits      Limits      `json:"limits"`
+}
+
+// Endpoint describes an endpoint of the server.
+type Endpoint struct {
+	// Path holds the path of the endpoint, relative to the server URL.
+	Path string `json:"path"`
+
+	// Enabled reports whether the endpoint is enabled. Requests to
+	// disabled endpoints are rejected.
+	Enabled bool `json:"enabled"`
+}
+
+// Compression holds the content codings supported by the server.
+type Compression struct {
+	// Request holds the supported Content-Encoding values of requests.
+	Request []string `json:"request"`
+
+	// Response holds the supported Accept-Encoding values of requests,
+	// with which responses are compressed.
+	Response []string `json:"response"`
+}
+
+// Limits holds the limits enforced by the server on requests.
+type Limits struct {
+	MaxEventSize      int   `json:"max_event_size"`
+	MaxHeaderSize     int   `json:"max_header_size"`
+	ReadTimeoutMillis int64 `json:"read_timeout_ms"`
+
+	// RUMEventRateLimit holds the maximum number of RUM events accepted
+	// per second from a single client IP address, or zero if RUM is
+	// disabled.
+	RUMEventRateLimit int `json:"rum_event_rate_limit,omitempty"`
+}
+
+// Handler returns a request.Handler for serving doc.
+func Handler(doc Document) request.Handler {
+	return func(c *request.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead:
+		default:
+			c.Result.SetDefault(request.IDResponseErrorsMethodNotAllowed)
+			c.Write()
+			return
+		}
+		c.Result.SetWithBody(request.IDResponseValidOK, doc)
+		c.Write()
+	}
+}
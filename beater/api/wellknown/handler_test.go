@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package wellknown
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestHandler(t *testing.T) {
+	doc := Document{
+		Version:     "1.2.3",
+		Endpoints:   map[string]Endpoint{"intake": {Path: "/intake/v2/events", Enabled: true}},
+		Protocols:   []string{"elastic/http"},
+		Compression: Compression{Request: []string{"gzip"}, Response: []string{}},
+		Limits:      Limits{MaxEventSize: 100, MaxHeaderSize: 200, ReadTimeoutMillis: 300},
+	}
+
+	c := request.NewContext()
+	w := httptest.NewRecorder()
+	c.Reset(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	Handler(doc)(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out))
+	assert.Equal(t, map[string]interface{}{
+		"version": "1.2.3",
+		"endpoints": map[string]interface{}{
+			"intake": map[string]interface{}{"path": "/intake/v2/events", "enabled": true},
+		},
+		"protocols":   []interface{}{"elastic/http"},
+		"compression": map[string]interface{}{"request": []interface{}{"gzip"}, "response": []interface{}{}},
+		"limits": map[string]interface{}{
+			"max_event_size":  100.0,
+			"max_header_size": 200.0,
+			"read_timeout_ms": 300.0,
+		},
+	}, out)
+}
+
+func TestHandlerMethodNotAllowed(t *testing.T) {
+	c := request.NewContext()
+	w := httptest.NewRecorder()
+	c.Reset(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	Handler(Document{})(c)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
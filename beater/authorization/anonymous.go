@@ -0,0 +1,77 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/apm-server/beater/config"
+	es "github.com/elastic/apm-server/elasticsearch"
+)
+
+// anonymousAuth implements the Authorization interface for requests without
+// credentials to endpoints permitting anonymous access. Such requests are
+// authorized, but their events are restricted to allowed agents and services.
+type anonymousAuth struct {
+	allowAgent   map[string]bool
+	allowService map[string]bool
+}
+
+func newAnonymousAuth(cfg config.AnonymousAuthConfig) *anonymousAuth {
+	return &anonymousAuth{
+		allowAgent:   makeAllowed(cfg.AllowAgent),
+		allowService: makeAllowed(cfg.AllowService),
+	}
+}
+
+func makeAllowed(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[name] = true
+	}
+	return allowed
+}
+
+// AuthorizedFor authorizes any resource other than services which are not
+// allowed for anonymous access.
+func (a *anonymousAuth) AuthorizedFor(_ context.Context, resource es.Resource) (Result, error) {
+	if serviceName := string(resource); strings.HasPrefix(serviceName, ServiceResourcePrefix) {
+		serviceName = serviceName[len(ServiceResourcePrefix):]
+		if a.allowService != nil && !a.allowService[serviceName] {
+			return Result{Reason: "service is not allowed for anonymous access"}, nil
+		}
+	}
+	return Result{Authorized: true, Anonymous: true}, nil
+}
+
+// authorizeAgent returns an error if events of the named agent
+// are not allowed for anonymous access.
+func (a *anonymousAuth) authorizeAgent(serviceName, agentName string) error {
+	if a.allowAgent != nil && !a.allowAgent[agentName] {
+		return &ServiceUnauthorizedError{
+			ServiceName: serviceName,
+			Reason:      fmt.Sprintf("agent %q is not allowed for anonymous access", agentName),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/model"
+)
+
+func TestAnonymousAuthorizationFor(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SecretToken = "abc123"
+	cfg.Auth.Anonymous.Enabled = true
+	builder, err := NewBuilder(cfg)
+	require.NoError(t, err)
+
+	// Only handlers created with ForAnonymousPrivilege permit anonymous access.
+	handler := builder.ForPrivilege(PrivilegeEventWrite.Action)
+	assert.Equal(t, denyAuth{
+		reason: "missing or improperly formatted Authorization header: " +
+			"expected 'Authorization: Bearer secret_token' or 'Authorization: ApiKey base64(API key ID:API key)'",
+		code: CodeMissingCredentials,
+	}, handler.AuthorizationFor("", ""))
+
+	handler = builder.ForAnonymousPrivilege(PrivilegeEventWrite.Action)
+	auth := handler.AuthorizationFor("", "")
+	result, err := auth.AuthorizedFor(context.Background(), ResourceInternal)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Authorized: true, Anonymous: true}, result)
+
+	// Requests with credentials are authorized as usual.
+	auth = handler.AuthorizationFor(headers.Bearer, "wrong")
+	result, err = auth.AuthorizedFor(context.Background(), ResourceInternal)
+	require.NoError(t, err)
+	assert.False(t, result.Authorized)
+	assert.False(t, result.Anonymous)
+}
+
+func TestAnonymousAuthorizationForNoCredentialsConfigured(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Auth.Anonymous.Enabled = true
+	builder, err := NewBuilder(cfg)
+	require.NoError(t, err)
+
+	// Credentials cannot be verified, so restrictions
+	// cannot be avoided by sending arbitrary credentials.
+	handler := builder.ForAnonymousPrivilege(PrivilegeEventWrite.Action)
+	auth := handler.AuthorizationFor(headers.Bearer, "anything")
+	result, err := auth.AuthorizedFor(context.Background(), ResourceInternal)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Authorized: true, Anonymous: true}, result)
+}
+
+func TestAnonymousServiceBatchProcessor(t *testing.T) {
+	auth := newAnonymousAuth(config.AnonymousAuthConfig{
+		Enabled:      true,
+		AllowAgent:   []string{"rum-js"},
+		AllowService: []string{"opbeans-rum"},
+	})
+	processor := ServiceBatchProcessor(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		return nil
+	}))
+	batch := func(serviceName, agentName string) *model.Batch {
+		return &model.Batch{Transactions: []*model.Transaction{{
+			Metadata: model.Metadata{Service: model.Service{
+				Name:  serviceName,
+				Agent: model.Agent{Name: agentName},
+			}},
+		}}}
+	}
+
+	ctx := ContextWithAuthorization(context.Background(), auth)
+	assert.NoError(t, processor.ProcessBatch(ctx, batch("opbeans-rum", "rum-js")))
+	assert.EqualError(t,
+		processor.ProcessBatch(ctx, batch("opbeans-rum", "go")),
+		`not authorized for service "opbeans-rum": agent "go" is not allowed for anonymous access`,
+	)
+	assert.EqualError(t,
+		processor.ProcessBatch(ctx, batch("opbeans-go", "rum-js")),
+		`not authorized for service "opbeans-go": service is not allowed for anonymous access`,
+	)
+
+	// Empty allowlists allow any agent or service.
+	ctx = ContextWithAuthorization(context.Background(), newAnonymousAuth(config.AnonymousAuthConfig{Enabled: true}))
+	assert.NoError(t, processor.ProcessBatch(ctx, batch("opbeans-go", "go")))
+}
@@ -19,10 +19,13 @@ package authorization
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/elastic/apm-server/beater/errorcode"
 	es "github.com/elastic/apm-server/elasticsearch"
 )
 
@@ -36,6 +39,15 @@ const (
 	// The API Key needs to grant privileges to additional resources for successful processing of requests.
 	ResourceInternal = es.Resource("-")
 	ResourceAny      = es.Resource("*")
+
+	// APIKeyServiceNameMetadata is the key of the API Key metadata
+	// holding the name of the single service to which the API Key is
+	// restricted, recorded by `apm-server apikey create --service`.
+	APIKeyServiceNameMetadata = "service.name"
+
+	// serviceNameResource identifies the cached service name of an
+	// API Key, distinct from the resources of cached permissions.
+	serviceNameResource = es.Resource("metadata:service.name")
 )
 
 type apikeyBuilder struct {
@@ -62,9 +74,12 @@ func (a *apikeyBuilder) forKey(key string) *apikeyAuth {
 // An api key is considered to be authorized when the api key has the configured privileges for the requested resource.
 // Permissions are fetched from Elasticsearch and then cached in a global cache.
 func (a *apikeyAuth) AuthorizedFor(ctx context.Context, resource es.Resource) (Result, error) {
-	privileges := a.cache.get(id(a.key, resource))
+	privileges, code := a.cache.get(id(a.key, resource))
+	if code != "" {
+		return unauthenticatedResult(code), nil
+	}
 	if privileges != nil {
-		return Result{Authorized: a.allowed(privileges)}, nil
+		return a.result(privileges), nil
 	}
 
 	if a.cache.isFull() {
@@ -73,12 +88,66 @@ func (a *apikeyAuth) AuthorizedFor(ctx context.Context, resource es.Resource) (R
 			"or consider increasing config option `apm-server.api_key.limit`")
 	}
 
-	privileges, err := a.queryES(ctx, resource)
+	privileges, code, err := a.queryES(ctx, resource)
 	if err != nil {
 		return Result{}, err
 	}
+	if code != "" {
+		a.cache.addUnauthenticated(id(a.key, resource), code)
+		return unauthenticatedResult(code), nil
+	}
 	a.cache.add(id(a.key, resource), privileges)
-	return Result{Authorized: a.allowed(privileges)}, nil
+	return a.result(privileges), nil
+}
+
+func (a *apikeyAuth) result(permissions es.Permissions) Result {
+	if !a.allowed(permissions) {
+		return Result{
+			Reason: "API Key lacks the privileges required for this request",
+			Code:   CodeInsufficientPrivileges,
+		}
+	}
+	return Result{Authorized: true, Authenticated: true}
+}
+
+// ServiceName returns the name of the service recorded in the API Key's
+// metadata as APIKeyServiceNameMetadata, or an empty string if there is
+// none. The API Key's information is fetched from Elasticsearch using
+// the API Key itself, and then cached like its permissions.
+func (a *apikeyAuth) ServiceName(ctx context.Context) (string, error) {
+	cacheID := id(a.key, serviceNameResource)
+	if name, ok := a.cache.getServiceName(cacheID); ok {
+		return name, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(a.key)
+	if err != nil {
+		return "", nil
+	}
+	keyID := strings.SplitN(string(decoded), ":", 2)[0]
+	info, err := es.GetAPIKeys(ctx, a.esClient, es.GetAPIKeyRequest{
+		APIKeyQuery: es.APIKeyQuery{ID: &keyID},
+		Owner:       true,
+		Credentials: a.key,
+	})
+	if err != nil {
+		return "", err
+	}
+	var name string
+	for _, apikey := range info.APIKeys {
+		if apikey.ID == keyID {
+			name, _ = apikey.Metadata[APIKeyServiceNameMetadata].(string)
+		}
+	}
+	a.cache.addServiceName(cacheID, name)
+	return name, nil
+}
+
+func unauthenticatedResult(code errorcode.Code) Result {
+	reason := "invalid API Key"
+	if code == CodeExpiredAPIKey {
+		reason = "API Key has expired"
+	}
+	return Result{Reason: reason, Code: code}
 }
 
 func (a *apikeyAuth) allowed(permissions es.Permissions) bool {
@@ -94,7 +163,10 @@ func (a *apikeyAuth) allowed(permissions es.Permissions) bool {
 	return allowed
 }
 
-func (a *apikeyAuth) queryES(ctx context.Context, resource es.Resource) (es.Permissions, error) {
+// queryES queries the API Key's permissions for resource. If Elasticsearch
+// fails to authenticate the API Key, queryES returns a Result.Code
+// identifying the reason instead.
+func (a *apikeyAuth) queryES(ctx context.Context, resource es.Resource) (es.Permissions, errorcode.Code, error) {
 	request := es.HasPrivilegesRequest{
 		Applications: []es.Application{
 			{
@@ -110,14 +182,20 @@ func (a *apikeyAuth) queryES(ctx context.Context, resource es.Resource) (es.Perm
 	if err != nil {
 		var eserr *es.Error
 		if errors.As(err, &eserr) && eserr.StatusCode == http.StatusUnauthorized {
-			return es.Permissions{}, nil
+			// Elasticsearch reports the reason for authentication
+			// failures in the response body, but not as a distinct
+			// error type, so we look for expired keys in the message.
+			if strings.Contains(strings.ToLower(eserr.Error()), "expired") {
+				return nil, CodeExpiredAPIKey, nil
+			}
+			return nil, CodeInvalidAPIKey, nil
 		}
-		return nil, err
+		return nil, "", err
 	}
 	if permissions, ok := info.Application[Application][resource]; ok {
-		return permissions, nil
+		return permissions, "", nil
 	}
-	return es.Permissions{}, nil
+	return es.Permissions{}, "", nil
 }
 
 func id(apiKey string, resource es.Resource) string {
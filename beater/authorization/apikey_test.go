@@ -53,21 +53,26 @@ func TestApikeyBuilder(t *testing.T) {
 	// check that cache is actually shared between apiKeyHandlers
 	result, err := handler1.AuthorizedFor(context.Background(), resource)
 	assert.NoError(t, err)
-	assert.Equal(t, Result{Authorized: true}, result)
+	assert.Equal(t, Result{Authorized: true, Authenticated: true}, result)
 
 	result, err = handler2.AuthorizedFor(context.Background(), resource)
 	assert.NoError(t, err)
-	assert.Equal(t, Result{Authorized: true}, result)
+	assert.Equal(t, Result{Authorized: true, Authenticated: true}, result)
 }
 
 func TestAPIKey_AuthorizedFor(t *testing.T) {
+	insufficientPrivileges := Result{
+		Reason: "API Key lacks the privileges required for this request",
+		Code:   CodeInsufficientPrivileges,
+	}
+
 	t.Run("cache full", func(t *testing.T) {
 		tc := &apikeyTestcase{cache: newPrivilegesCache(time.Millisecond, 1)}
 		tc.setup(t)
 		handler := tc.builder.forKey("")
 
 		result, err := handler.AuthorizedFor(context.Background(), "data:ingest")
-		assert.Equal(t, Result{Authorized: false}, result)
+		assert.Equal(t, insufficientPrivileges, result)
 		assert.NoError(t, err)
 
 		result, err = handler.AuthorizedFor(context.Background(), "apm:read")
@@ -90,11 +95,11 @@ func TestAPIKey_AuthorizedFor(t *testing.T) {
 
 		result, err := handler.AuthorizedFor(context.Background(), resourceValid)
 		require.NoError(t, err)
-		assert.Equal(t, Result{Authorized: true}, result)
+		assert.Equal(t, Result{Authorized: true, Authenticated: true}, result)
 
 		result, err = handler.AuthorizedFor(context.Background(), resourceInvalid)
 		require.NoError(t, err)
-		assert.Equal(t, Result{Authorized: false}, result)
+		assert.Equal(t, insufficientPrivileges, result)
 
 		result, err = handler.AuthorizedFor(context.Background(), resourceMissing)
 		require.Error(t, err)
@@ -108,15 +113,15 @@ func TestAPIKey_AuthorizedFor(t *testing.T) {
 
 		result, err := handler.AuthorizedFor(context.Background(), "foo")
 		require.NoError(t, err)
-		assert.Equal(t, Result{Authorized: true}, result)
+		assert.Equal(t, Result{Authorized: true, Authenticated: true}, result)
 
 		result, err = handler.AuthorizedFor(context.Background(), "bar")
 		require.NoError(t, err)
-		assert.Equal(t, Result{Authorized: false}, result)
+		assert.Equal(t, insufficientPrivileges, result)
 
 		result, err = handler.AuthorizedFor(context.Background(), "missing")
 		require.NoError(t, err)
-		assert.Equal(t, Result{Authorized: false}, result)
+		assert.Equal(t, insufficientPrivileges, result)
 		assert.Equal(t, 3, tc.cache.cache.ItemCount())
 	})
 
@@ -140,10 +145,32 @@ func TestAPIKey_AuthorizedFor(t *testing.T) {
 
 		result, err := handler.AuthorizedFor(context.Background(), "xyz")
 		require.NoError(t, err)
-		assert.Equal(t, Result{Authorized: false}, result)
+		assert.Equal(t, Result{Reason: "invalid API Key", Code: CodeInvalidAPIKey}, result)
 		assert.Equal(t, 1, tc.cache.cache.ItemCount()) // unauthorized responses are cached
 	})
 
+	t.Run("expired status from ES", func(t *testing.T) {
+		tc := &apikeyTestcase{transport: estest.NewTransport(t, http.StatusUnauthorized, map[string]interface{}{
+			"error": map[string]interface{}{
+				"type":                                "security_exception",
+				"reason":                              "unable to authenticate with provided credentials",
+				"additional_unsuccessful_credentials": "API key: api key is expired",
+			},
+		})}
+		tc.setup(t)
+		handler := tc.builder.forKey("12a3")
+
+		result, err := handler.AuthorizedFor(context.Background(), "xyz")
+		require.NoError(t, err)
+		assert.Equal(t, Result{Reason: "API Key has expired", Code: CodeExpiredAPIKey}, result)
+
+		// unauthenticated results are cached along with the reason
+		result, err = handler.AuthorizedFor(context.Background(), "xyz")
+		require.NoError(t, err)
+		assert.Equal(t, Result{Reason: "API Key has expired", Code: CodeExpiredAPIKey}, result)
+		assert.Equal(t, 1, tc.cache.cache.ItemCount())
+	})
+
 	t.Run("invalid status from ES", func(t *testing.T) {
 		tc := &apikeyTestcase{transport: estest.NewTransport(t, http.StatusNotFound, nil)}
 		tc.setup(t)
@@ -218,3 +245,38 @@ func TestApikeyBuilderTraceContext(t *testing.T) {
 	require.Len(t, spans, 1)
 	assert.Equal(t, "elasticsearch", spans[0].Subtype)
 }
+
+func TestAPIKey_ServiceName(t *testing.T) {
+	// base64("key-id:secret")
+	key := "a2V5LWlkOnNlY3JldA=="
+	for name, test := range map[string]struct {
+		metadata map[string]interface{}
+		expected string
+	}{
+		"service":    {metadata: map[string]interface{}{"application": "apm", "service.name": "opbeans"}, expected: "opbeans"},
+		"no_service": {metadata: map[string]interface{}{"application": "apm"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tc := &apikeyTestcase{transport: estest.NewTransport(t, http.StatusOK, map[string]interface{}{
+				"api_keys": []map[string]interface{}{{"id": "key-id", "name": "sensor", "metadata": test.metadata}},
+			})}
+			tc.setup(t)
+			name, err := tc.builder.forKey(key).ServiceName(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, name)
+
+			cached, ok := tc.cache.getServiceName(id(key, serviceNameResource))
+			assert.True(t, ok)
+			assert.Equal(t, test.expected, cached)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		tc := &apikeyTestcase{transport: estest.NewTransport(t, http.StatusInternalServerError, nil)}
+		tc.setup(t)
+		_, err := tc.builder.forKey(key).ServiceName(context.Background())
+		assert.Error(t, err)
+		_, ok := tc.cache.getServiceName(id(key, serviceNameResource))
+		assert.False(t, ok)
+	})
+}
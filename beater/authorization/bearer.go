@@ -39,5 +39,8 @@ func (b bearerBuilder) forToken(token string) *bearerAuth {
 }
 
 func (b *bearerAuth) AuthorizedFor(context.Context, elasticsearch.Resource) (Result, error) {
-	return Result{Authorized: b.authorized}, nil
+	if !b.authorized {
+		return Result{Reason: "invalid secret token", Code: CodeInvalidSecretToken}, nil
+	}
+	return Result{Authorized: true, Authenticated: true}, nil
 }
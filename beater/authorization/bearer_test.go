@@ -40,7 +40,11 @@ func TestBearerAuth(t *testing.T) {
 			bearer := tc.builder.forToken(tc.token)
 			result, err := bearer.AuthorizedFor(context.Background(), "")
 			assert.NoError(t, err)
-			assert.Equal(t, Result{Authorized: tc.authorized}, result)
+			if tc.authorized {
+				assert.Equal(t, Result{Authorized: true, Authenticated: true}, result)
+			} else {
+				assert.Equal(t, Result{Reason: "invalid secret token", Code: CodeInvalidSecretToken}, result)
+			}
 		})
 	}
 }
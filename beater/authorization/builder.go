@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/errorcode"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/elasticsearch"
 )
@@ -31,6 +32,11 @@ import (
 type Builder struct {
 	apikey *apikeyBuilder
 	bearer *bearerBuilder
+	bypass []bypassRule
+
+	// anonymous is non-nil if anonymous access is enabled, and is only
+	// used by Handlers created with ForAnonymousPrivilege.
+	anonymous *anonymousAuth
 }
 
 // Handler returns the authorization method according to provided information
@@ -49,6 +55,50 @@ type Result struct {
 
 	// Reason holds an optional reason for unauthorized results.
 	Reason string
+
+	// Code identifies why the authorization attempt was unsuccessful,
+	// and is one of the Code* constants for unauthorized results.
+	Code errorcode.Code
+
+	// Anonymous indicates that the request was authorized
+	// without credentials, with anonymous access.
+	Anonymous bool
+
+	// Authenticated indicates that the request was authorized
+	// with verified credentials, i.e. a secret token or API Key.
+	Authenticated bool
+}
+
+// Codes identifying why an authorization attempt was unsuccessful, allowing
+// clients to distinguish failures programmatically. The codes are part of
+// the server's error codes, which are defined by package errorcode.
+const (
+	CodeMissingCredentials     = errorcode.MissingCredentials
+	CodeUnsupportedScheme      = errorcode.UnsupportedScheme
+	CodeInvalidSecretToken     = errorcode.InvalidSecretToken
+	CodeInvalidAPIKey          = errorcode.InvalidAPIKey
+	CodeExpiredAPIKey          = errorcode.ExpiredAPIKey
+	CodeInsufficientPrivileges = errorcode.InsufficientPrivileges
+)
+
+const (
+	docsBaseURL = "https://www.elastic.co/guide/en/apm/server/current/"
+
+	// realm is included in the challenges returned by Handler.Challenges.
+	realm = "apm-server"
+)
+
+// DocumentationURL returns a link to documentation for resolving the
+// authorization failure identified by code.
+func DocumentationURL(code errorcode.Code) string {
+	switch code {
+	case CodeInvalidSecretToken:
+		return docsBaseURL + "secret-token.html"
+	case CodeInvalidAPIKey, CodeExpiredAPIKey, CodeInsufficientPrivileges:
+		return docsBaseURL + "api-key.html"
+	default:
+		return docsBaseURL + "secure-communication-agents.html"
+	}
 }
 
 const (
@@ -76,6 +126,14 @@ func NewBuilder(cfg *config.Config) (*Builder, error) {
 	if cfg.SecretToken != "" {
 		b.bearer = &bearerBuilder{cfg.SecretToken}
 	}
+	bypass, err := newBypassRules(cfg.AuthBypass)
+	if err != nil {
+		return nil, err
+	}
+	b.bypass = bypass
+	if cfg.Auth.Anonymous.Enabled {
+		b.anonymous = newAnonymousAuth(cfg.Auth.Anonymous)
+	}
 	return &b, nil
 }
 
@@ -86,15 +144,28 @@ func (b *Builder) ForPrivilege(privilege elasticsearch.PrivilegeAction) *Handler
 
 // ForAnyOfPrivileges creates an authorization Handler checking for any of the provided privileges
 func (b *Builder) ForAnyOfPrivileges(privileges ...elasticsearch.PrivilegeAction) *Handler {
-	handler := Handler{bearer: b.bearer}
+	handler := Handler{bearer: b.bearer, bypass: b.bypass}
 	if b.apikey != nil {
 		handler.apikey = newApikeyBuilder(b.apikey.esClient, b.apikey.cache, privileges)
 	}
 	return &handler
 }
 
+// ForAnonymousPrivilege creates an authorization Handler checking for this privilege,
+// which permits requests without credentials if anonymous access is enabled.
+func (b *Builder) ForAnonymousPrivilege(privilege elasticsearch.PrivilegeAction) *Handler {
+	handler := b.ForPrivilege(privilege)
+	handler.anonymous = b.anonymous
+	return handler
+}
+
 // AuthorizationFor returns proper authorization implementation depending on the given kind, configured with the token.
 func (h *Handler) AuthorizationFor(kind string, token string) Authorization {
+	if h.anonymous != nil && (kind == "" || h.apikey == nil && h.bearer == nil) {
+		// Credentials cannot be verified when neither secret token nor
+		// API Key authorization is enabled, so the request is anonymous.
+		return h.anonymous
+	}
 	if h.apikey == nil && h.bearer == nil {
 		return allowAuth{}
 	}
@@ -103,16 +174,36 @@ func (h *Handler) AuthorizationFor(kind string, token string) Authorization {
 		if h.apikey != nil {
 			return h.apikey.forKey(token)
 		}
+		return denyAuth{reason: "API Key authorization is not enabled", code: CodeUnsupportedScheme}
 	case headers.Bearer:
 		if h.bearer != nil {
 			return h.bearer.forToken(token)
 		}
+		return denyAuth{reason: "secret token authorization is not enabled", code: CodeUnsupportedScheme}
 	default:
 		expected := "expected 'Authorization: Bearer secret_token' or 'Authorization: ApiKey base64(API key ID:API key)'"
 		if kind == "" {
-			return denyAuth{reason: "missing or improperly formatted Authorization header: " + expected}
+			return denyAuth{
+				reason: "missing or improperly formatted Authorization header: " + expected,
+				code:   CodeMissingCredentials,
+			}
+		}
+		return denyAuth{
+			reason: fmt.Sprintf("unknown Authorization kind %s: %s", kind, expected),
+			code:   CodeUnsupportedScheme,
 		}
-		return denyAuth{reason: fmt.Sprintf("unknown Authorization kind %s: %s", kind, expected)}
 	}
-	return denyAuth{}
+}
+
+// Challenges returns the WWW-Authenticate challenges for the enabled
+// authorization schemes, or nil if authorization is not required.
+func (h *Handler) Challenges() []string {
+	var challenges []string
+	if h.bearer != nil {
+		challenges = append(challenges, fmt.Sprintf("%s realm=%q", headers.Bearer, realm))
+	}
+	if h.apikey != nil {
+		challenges = append(challenges, fmt.Sprintf("%s realm=%q", headers.APIKey, realm))
+	}
+	return challenges
 }
@@ -83,7 +83,7 @@ func TestBuilder(t *testing.T) {
 			if tc.withApikey {
 				assert.IsType(t, &apikeyAuth{}, auth)
 			} else if tc.withBearer {
-				assert.Equal(t, denyAuth{}, auth)
+				assert.Equal(t, denyAuth{reason: "API Key authorization is not enabled", code: CodeUnsupportedScheme}, auth)
 			} else {
 				assert.Equal(t, allowAuth{}, auth)
 			}
@@ -92,21 +92,27 @@ func TestBuilder(t *testing.T) {
 			if tc.withBearer {
 				assert.IsType(t, &bearerAuth{}, auth)
 			} else if tc.withApikey {
-				assert.Equal(t, denyAuth{}, auth)
+				assert.Equal(t, denyAuth{reason: "secret token authorization is not enabled", code: CodeUnsupportedScheme}, auth)
 			} else {
 				assert.Equal(t, allowAuth{}, auth)
 			}
 
 			auth = h.AuthorizationFor("Anything", "")
 			if tc.withBearer || tc.withApikey {
-				assert.Equal(t, denyAuth{reason: `unknown Authorization kind Anything: expected 'Authorization: Bearer secret_token' or 'Authorization: ApiKey base64(API key ID:API key)'`}, auth)
+				assert.Equal(t, denyAuth{
+					reason: `unknown Authorization kind Anything: expected 'Authorization: Bearer secret_token' or 'Authorization: ApiKey base64(API key ID:API key)'`,
+					code:   CodeUnsupportedScheme,
+				}, auth)
 			} else {
 				assert.Equal(t, allowAuth{}, auth)
 			}
 
 			auth = h.AuthorizationFor("", "Value")
 			if tc.withBearer || tc.withApikey {
-				assert.Equal(t, denyAuth{reason: `missing or improperly formatted Authorization header: expected 'Authorization: Bearer secret_token' or 'Authorization: ApiKey base64(API key ID:API key)'`}, auth)
+				assert.Equal(t, denyAuth{
+					reason: `missing or improperly formatted Authorization header: expected 'Authorization: Bearer secret_token' or 'Authorization: ApiKey base64(API key ID:API key)'`,
+					code:   CodeMissingCredentials,
+				}, auth)
 			} else {
 				assert.Equal(t, allowAuth{}, auth)
 			}
@@ -0,0 +1,84 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package authorization
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/utility"
+)
+
+// bypassRule matches requests which are exempt from authorization.
+type bypassRule struct {
+	paths    map[string]bool
+	networks []*net.IPNet
+}
+
+func newBypassRules(cfg []config.AuthBypassRule) ([]bypassRule, error) {
+	rules := make([]bypassRule, len(cfg))
+	for i, in := range cfg {
+		rules[i].paths = make(map[string]bool, len(in.Paths))
+		for _, path := range in.Paths {
+			rules[i].paths[path] = true
+		}
+		for _, addr := range in.SourceAddresses {
+			network, err := config.ParseSourceAddress(addr)
+			if err != nil {
+				return nil, err
+			}
+			rules[i].networks = append(rules[i].networks, network)
+		}
+	}
+	return rules, nil
+}
+
+// matches reports whether the rule matches a request for path, received
+// from ip. ip may be nil, e.g. for requests received over a Unix socket,
+// in which case no rule matches. A rule without paths or source addresses
+// matches nothing.
+func (r bypassRule) matches(path string, ip net.IP) bool {
+	if !r.paths[path] || ip == nil {
+		return false
+	}
+	for _, network := range r.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Bypassed reports whether r is exempt from authorization, according to
+// the configured `apm-server.auth_bypass` rules.
+//
+// Source addresses are matched against r.RemoteAddr, the address of the
+// connecting peer, and never against headers controlled by the client.
+func (h *Handler) Bypassed(r *http.Request) bool {
+	if len(h.bypass) == 0 {
+		return false
+	}
+	ip := utility.ParseIP(r.RemoteAddr)
+	for _, rule := range h.bypass {
+		if rule.matches(r.URL.Path, ip) {
+			return true
+		}
+	}
+	return false
+}
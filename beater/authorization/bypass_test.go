@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package authorization
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestHandlerBypassed(t *testing.T) {
+	builder, err := NewBuilder(&config.Config{
+		SecretToken: "foo",
+		AuthBypass: []config.AuthBypassRule{
+			{Paths: []string{"/"}, SourceAddresses: []string{"127.0.0.1", "::1"}},
+			{Paths: []string{"/intake/v2/events"}, SourceAddresses: []string{"10.0.0.0/8"}},
+		},
+	})
+	require.NoError(t, err)
+	handler := builder.ForPrivilege(PrivilegeEventWrite.Action)
+
+	for _, tc := range []struct {
+		path       string
+		remoteAddr string
+		bypassed   bool
+	}{
+		{path: "/", remoteAddr: "127.0.0.1:1234", bypassed: true},
+		{path: "/", remoteAddr: "[::1]:1234", bypassed: true},
+		{path: "/", remoteAddr: "192.0.2.1:1234", bypassed: false},
+		{path: "/", remoteAddr: "10.1.2.3:1234", bypassed: false},
+		{path: "/", remoteAddr: "@", bypassed: false},
+		{path: "/intake/v2/events", remoteAddr: "10.1.2.3:1234", bypassed: true},
+		{path: "/intake/v2/events", remoteAddr: "127.0.0.1:1234", bypassed: false},
+		{path: "/intake/v2/events", remoteAddr: "192.0.2.1:1234", bypassed: false},
+		{path: "/intake/v2/events", remoteAddr: "@", bypassed: false},
+	} {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		req.RemoteAddr = tc.remoteAddr
+		assert.Equal(t, tc.bypassed, handler.Bypassed(req), "%s from %s", tc.path, tc.remoteAddr)
+	}
+}
+
+func TestHandlerBypassedNoRules(t *testing.T) {
+	builder, err := NewBuilder(&config.Config{SecretToken: "foo"})
+	require.NoError(t, err)
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "127.0.0.1:1234"
+	assert.False(t, builder.ForAnyOfPrivileges(ActionAny).Bypassed(req))
+}
+
+func TestHandlerBypassedOneSidedRules(t *testing.T) {
+	// Rules are validated to have both paths and source addresses;
+	// rules missing either never match, rather than matching any
+	// path or source address.
+	for name, rule := range map[string]config.AuthBypassRule{
+		"paths":            {Paths: []string{"/"}},
+		"source_addresses": {SourceAddresses: []string{"0.0.0.0/0"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, rule.Validate())
+
+			builder, err := NewBuilder(&config.Config{
+				SecretToken: "foo",
+				AuthBypass:  []config.AuthBypassRule{rule},
+			})
+			require.NoError(t, err)
+			handler := builder.ForAnyOfPrivileges(ActionAny)
+			for _, path := range []string{"/", "/intake/v2/events"} {
+				req := httptest.NewRequest("GET", path, nil)
+				req.RemoteAddr = "192.0.2.1:1234"
+				assert.False(t, handler.Bypassed(req), path)
+			}
+		})
+	}
+}
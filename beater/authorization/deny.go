@@ -20,15 +20,17 @@ package authorization
 import (
 	"context"
 
+	"github.com/elastic/apm-server/beater/errorcode"
 	"github.com/elastic/apm-server/elasticsearch"
 )
 
 // denyAuth implements the Authorization interface. It denies all authorization requests.
 type denyAuth struct {
 	reason string
+	code   errorcode.Code
 }
 
 // AuthorizedFor always returns false
 func (d denyAuth) AuthorizedFor(context.Context, elasticsearch.Resource) (Result, error) {
-	return Result{Authorized: false, Reason: d.reason}, nil
+	return Result{Authorized: false, Reason: d.reason, Code: d.code}, nil
 }
@@ -25,9 +25,9 @@ import (
 )
 
 func TestDenyAuth(t *testing.T) {
-	handler := denyAuth{}
+	handler := denyAuth{reason: "no", code: CodeMissingCredentials}
 
 	result, err := handler.AuthorizedFor(context.Background(), "")
 	assert.NoError(t, err)
-	assert.Equal(t, Result{Authorized: false}, result)
+	assert.Equal(t, Result{Authorized: false, Reason: "no", Code: CodeMissingCredentials}, result)
 }
@@ -20,6 +20,7 @@ package authorization
 import (
 	"time"
 
+	"github.com/elastic/apm-server/beater/errorcode"
 	es "github.com/elastic/apm-server/elasticsearch"
 
 	"github.com/patrickmn/go-cache"
@@ -54,13 +55,46 @@ func (c *privilegesCache) isFull() bool {
 	return c.cache.ItemCount() >= c.size
 }
 
-func (c *privilegesCache) get(id string) es.Permissions {
+// unauthenticated is cached in place of permissions for API Keys which
+// Elasticsearch failed to authenticate, holding the Result.Code.
+type unauthenticated errorcode.Code
+
+// get returns the cached permissions for id, or nil if there are none. If the
+// API Key could not be authenticated, get returns nil and the Result.Code.
+func (c *privilegesCache) get(id string) (es.Permissions, errorcode.Code) {
 	if val, exists := c.cache.Get(id); exists {
-		return val.(es.Permissions)
+		if code, ok := val.(unauthenticated); ok {
+			return nil, errorcode.Code(code)
+		}
+		return val.(es.Permissions), ""
 	}
-	return nil
+	return nil, ""
 }
 
 func (c *privilegesCache) add(id string, privileges es.Permissions) {
 	c.cache.SetDefault(id, privileges)
 }
+
+// serviceName is cached for API Keys in place of permissions, holding
+// the service name recorded in the API Key's metadata, if any.
+type serviceName string
+
+// getServiceName returns the cached service name for id, and whether
+// there is one cached.
+func (c *privilegesCache) getServiceName(id string) (string, bool) {
+	if val, exists := c.cache.Get(id); exists {
+		name, ok := val.(serviceName)
+		return string(name), ok
+	}
+	return "", false
+}
+
+func (c *privilegesCache) addServiceName(id string, name string) {
+	c.cache.SetDefault(id, serviceName(name))
+}
+
+// addUnauthenticated records that the API Key for id could not be
+// authenticated, for the reason identified by code.
+func (c *privilegesCache) addUnauthenticated(id string, code errorcode.Code) {
+	c.cache.SetDefault(id, unauthenticated(code))
+}
@@ -37,12 +37,20 @@ func TestPrivilegesCache(t *testing.T) {
 	}
 	cache.add("oneMore", elasticsearch.Permissions{})
 	assert.True(t, cache.isFull())
-	assert.NotNil(t, cache.get("oneMore"))
+	permissions, _ := cache.get("oneMore")
+	assert.NotNil(t, permissions)
 	time.Sleep(time.Millisecond)
-	assert.Nil(t, cache.get("oneMore"))
+	permissions, _ = cache.get("oneMore")
+	assert.Nil(t, permissions)
 
 	p := elasticsearch.Permissions{"a": true, "b": false}
 	cache.add("id1", p)
-	assert.Equal(t, p, cache.get("id1"))
-	assert.Nil(t, cache.get("oneMore"))
+	permissions, code := cache.get("id1")
+	assert.Equal(t, p, permissions)
+	assert.Empty(t, code)
+
+	cache.addUnauthenticated("id2", CodeExpiredAPIKey)
+	permissions, code = cache.get("id2")
+	assert.Nil(t, permissions)
+	assert.Equal(t, CodeExpiredAPIKey, code)
 }
@@ -0,0 +1,178 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	es "github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modelprocessor"
+)
+
+// ServiceResourcePrefix is the prefix of application privilege resources
+// identifying services. API Keys may be scoped to specific services by
+// granting privileges to the resource "service:<name>" in addition to
+// ResourceInternal, rather than to ResourceAny.
+const ServiceResourcePrefix = "service:"
+
+type authorizationKey struct{}
+
+// ResourceService returns the application privilege resource
+// for events of the named service.
+func ResourceService(serviceName string) es.Resource {
+	return es.Resource(ServiceResourcePrefix + serviceName)
+}
+
+// ContextWithAuthorization returns a copy of ctx with auth, the
+// Authorization with which the request has been authorized.
+func ContextWithAuthorization(ctx context.Context, auth Authorization) context.Context {
+	return context.WithValue(ctx, authorizationKey{}, auth)
+}
+
+// Authenticated reports whether the request's Authorization, added to the
+// context by ContextWithAuthorization, verified the request's credentials,
+// i.e. a secret token or API Key. Anonymous requests, and requests without
+// an Authorization in the context, such as bypassed requests, are not
+// authenticated.
+func Authenticated(ctx context.Context) bool {
+	switch auth := ctx.Value(authorizationKey{}).(type) {
+	case *bearerAuth:
+		return auth.authorized
+	case *apikeyAuth:
+		// API Keys are only added to the context once authorized.
+		return true
+	}
+	return false
+}
+
+// ServiceUnauthorizedError is returned by the model.BatchProcessor returned
+// by ServiceBatchProcessor, when the request is not authorized for the
+// service of an event.
+type ServiceUnauthorizedError struct {
+	ServiceName string
+	Reason      string
+}
+
+func (e *ServiceUnauthorizedError) Error() string {
+	return fmt.Sprintf("not authorized for service %q: %s", e.ServiceName, e.Reason)
+}
+
+// GRPCStatus returns a PermissionDenied status for e,
+// which is returned to gRPC clients.
+func (e *ServiceUnauthorizedError) GRPCStatus() *status.Status {
+	return status.New(codes.PermissionDenied, e.Error())
+}
+
+// ServiceBatchProcessor returns a model.BatchProcessor which checks that the
+// request's Authorization, added to the context by ContextWithAuthorization,
+// is authorized for the services of all events in each batch before passing
+// it to next, as described by AuthorizeBatch.
+func ServiceBatchProcessor(next model.BatchProcessor) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		if err := AuthorizeBatch(ctx, batch); err != nil {
+			return err
+		}
+		return next.ProcessBatch(ctx, batch)
+	})
+}
+
+// AuthorizeBatch checks that the request's Authorization, added to the
+// context by ContextWithAuthorization, is authorized for the services of
+// all events in batch. Otherwise, it returns a *ServiceUnauthorizedError.
+//
+// Anonymous requests are additionally restricted to events of the agents
+// allowed for anonymous access. Requests without an Authorization in the
+// context, such as bypassed requests, are not restricted.
+//
+// AuthorizeBatch must be called while handling the request, before batch
+// is processed in the background, so that its result can be reported.
+func AuthorizeBatch(ctx context.Context, batch *model.Batch) error {
+	auth, ok := ctx.Value(authorizationKey{}).(Authorization)
+	if !ok {
+		return nil
+	}
+	authorized := make(map[string]bool)
+	anonymous, _ := auth.(*anonymousAuth)
+	authorize := modelprocessor.MetadataProcessorFunc(func(ctx context.Context, meta *model.Metadata) error {
+		serviceName := meta.Service.Name
+		if anonymous != nil {
+			if err := anonymous.authorizeAgent(serviceName, meta.Service.Agent.Name); err != nil {
+				return err
+			}
+		}
+		if authorized[serviceName] {
+			return nil
+		}
+		if err := authorizeService(ctx, auth, serviceName); err != nil {
+			return err
+		}
+		authorized[serviceName] = true
+		return nil
+	})
+	return authorize.ProcessBatch(ctx, batch)
+}
+
+// ServiceName returns the name of the single service to which the
+// request's Authorization, added to the context by ContextWithAuthorization,
+// is restricted, such as an API Key created for one service, or an empty
+// string if the Authorization is not restricted to a single service.
+func ServiceName(ctx context.Context) (string, error) {
+	auth, ok := ctx.Value(authorizationKey{}).(Authorization)
+	if !ok {
+		return "", nil
+	}
+	if namer, ok := auth.(interface {
+		ServiceName(context.Context) (string, error)
+	}); ok {
+		return namer.ServiceName(ctx)
+	}
+	return "", nil
+}
+
+// AuthorizeService checks that the request's Authorization, added to the
+// context by ContextWithAuthorization, is authorized for the named service,
+// such as for agent configuration queries. Otherwise, it returns a
+// *ServiceUnauthorizedError. Requests without an Authorization in the
+// context are not restricted.
+func AuthorizeService(ctx context.Context, serviceName string) error {
+	auth, ok := ctx.Value(authorizationKey{}).(Authorization)
+	if !ok {
+		return nil
+	}
+	return authorizeService(ctx, auth, serviceName)
+}
+
+func authorizeService(ctx context.Context, auth Authorization, serviceName string) error {
+	result, err := auth.AuthorizedFor(ctx, ResourceService(serviceName))
+	if err != nil {
+		return err
+	}
+	if !result.Authorized {
+		reason := result.Reason
+		if result.Code == CodeInsufficientPrivileges {
+			reason = "API Key is not scoped to this service"
+		}
+		return &ServiceUnauthorizedError{ServiceName: serviceName, Reason: reason}
+	}
+	return nil
+}
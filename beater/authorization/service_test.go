@@ -0,0 +1,112 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	es "github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/model"
+)
+
+func TestServiceBatchProcessor(t *testing.T) {
+	var queried []es.Resource
+	auth := authorizationFunc(func(ctx context.Context, resource es.Resource) (Result, error) {
+		queried = append(queried, resource)
+		if resource == ResourceService("opbeans") {
+			return Result{Authorized: true}, nil
+		}
+		return Result{
+			Reason: "API Key lacks the privileges required for this request",
+			Code:   CodeInsufficientPrivileges,
+		}, nil
+	})
+
+	var processed int
+	processor := ServiceBatchProcessor(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		processed++
+		return nil
+	}))
+	batch := func(serviceNames ...string) *model.Batch {
+		var b model.Batch
+		for _, name := range serviceNames {
+			b.Transactions = append(b.Transactions, &model.Transaction{
+				Metadata: model.Metadata{Service: model.Service{Name: name}},
+			})
+		}
+		return &b
+	}
+
+	// Requests without an authorization are not restricted.
+	require.NoError(t, processor.ProcessBatch(context.Background(), batch("opbeans", "other")))
+	assert.Equal(t, 1, processed)
+	assert.Empty(t, queried)
+
+	// Each service is checked once per batch.
+	ctx := ContextWithAuthorization(context.Background(), auth)
+	require.NoError(t, processor.ProcessBatch(ctx, batch("opbeans", "opbeans")))
+	assert.Equal(t, 2, processed)
+	assert.Equal(t, []es.Resource{"service:opbeans"}, queried)
+
+	err := processor.ProcessBatch(ctx, batch("opbeans", "other"))
+	assert.EqualError(t, err, `not authorized for service "other": API Key is not scoped to this service`)
+	var serviceErr *ServiceUnauthorizedError
+	require.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, "other", serviceErr.ServiceName)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, 2, processed)
+}
+
+func TestServiceBatchProcessorError(t *testing.T) {
+	auth := authorizationFunc(func(ctx context.Context, resource es.Resource) (Result, error) {
+		return Result{}, errors.New("boom")
+	})
+	processor := ServiceBatchProcessor(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		panic("unexpected call")
+	}))
+	ctx := ContextWithAuthorization(context.Background(), auth)
+	err := processor.ProcessBatch(ctx, &model.Batch{Errors: []*model.Error{{}}})
+	assert.EqualError(t, err, "boom")
+}
+
+type authorizationFunc func(context.Context, es.Resource) (Result, error)
+
+func (f authorizationFunc) AuthorizedFor(ctx context.Context, resource es.Resource) (Result, error) {
+	return f(ctx, resource)
+}
+
+func TestAuthenticated(t *testing.T) {
+	assert.False(t, Authenticated(context.Background()))
+	for auth, expected := range map[Authorization]bool{
+		&bearerAuth{authorized: true}:  true,
+		&bearerAuth{authorized: false}: false,
+		&apikeyAuth{}:                  true,
+		&anonymousAuth{}:               false,
+		allowAuth{}:                    false,
+	} {
+		ctx := ContextWithAuthorization(context.Background(), auth)
+		assert.Equal(t, expected, Authenticated(ctx), "%T", auth)
+	}
+}
@@ -20,6 +20,7 @@ package beater
 import (
 	"context"
 	"net"
+	"net/http"
 	"regexp"
 	"runtime"
 	"strings"
@@ -41,16 +42,32 @@ import (
 	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/management"
 	esoutput "github.com/elastic/beats/v7/libbeat/outputs/elasticsearch"
+	"github.com/elastic/beats/v7/libbeat/paths"
+	"github.com/elastic/beats/v7/libbeat/processors"
 	"github.com/elastic/beats/v7/libbeat/publisher/pipetool"
 
+	"github.com/elastic/apm-server/beater/alerthook"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/otlp"
+	"github.com/elastic/apm-server/beater/preflight"
+	"github.com/elastic/apm-server/beater/synthetics"
+	"github.com/elastic/apm-server/beater/usage"
 	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/featureflag"
+	"github.com/elastic/apm-server/geoip"
+	"github.com/elastic/apm-server/idxmgmt"
+	"github.com/elastic/apm-server/idxmgmt/ilm"
+	"github.com/elastic/apm-server/indexrouting"
 	"github.com/elastic/apm-server/ingest/pipeline"
+	"github.com/elastic/apm-server/internaldocs"
 	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/lookup"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/model/modelprocessor"
+	"github.com/elastic/apm-server/partitionkey"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/sampling"
+	"github.com/elastic/apm-server/sidelookup"
 	"github.com/elastic/apm-server/sourcemap"
 	"github.com/elastic/apm-server/transform"
 )
@@ -149,6 +166,7 @@ func (bt *beater) Run(b *beat.Beat) error {
 	}
 	<-done
 	bt.waitPublished.Wait(ctx)
+	serverLifecycle.drained(bt.logger, bt.waitPublished.ackedEvents())
 	return nil
 }
 
@@ -192,6 +210,7 @@ func (bt *beater) start(ctx context.Context, cancelContext context.CancelFunc, b
 		bt.stopServer = func() {
 			defer close(done)
 			defer closeTracer()
+			b.Manager.UpdateStatus(management.Stopping, "Stopping")
 			if bt.config.ShutdownTimeout > 0 {
 				time.AfterFunc(bt.config.ShutdownTimeout, cancelContext)
 			}
@@ -201,6 +220,16 @@ func (bt *beater) start(ctx context.Context, cancelContext context.CancelFunc, b
 
 	} else {
 		// Management disabled, use statically defined config.
+		if bt.config.Preflight.Enabled {
+			if err := bt.runPreflightChecks(b); err != nil {
+				return nil, err
+			}
+		}
+		if bt.config.Warmup.Enabled && bt.config.Warmup.WaitForOutput {
+			if err := bt.waitForOutput(b); err != nil {
+				return nil, err
+			}
+		}
 		s, err := newServerRunner(ctx, serverRunnerParams{
 			sharedServerRunnerParams: sharedArgs,
 			Pipeline:                 b.Publisher,
@@ -274,6 +303,7 @@ type serverRunner struct {
 	acker         *waitPublishedAcker
 	namespace     string
 	config        *config.Config
+	rawConfig     *common.Config
 	beat          *beat.Beat
 	logger        *logp.Logger
 	tracer        *apm.Tracer
@@ -309,6 +339,17 @@ func newServerRunner(ctx context.Context, args serverRunnerParams) (*serverRunne
 		cfg.Kibana.ClientConfig = *args.KibanaConfig
 	}
 
+	// Feature flags are updated whenever a server is created, so that
+	// changes delivered through central management take effect without
+	// restarting the process.
+	changed, err := featureflag.Default.Update(cfg.FeatureFlags)
+	if err != nil {
+		return nil, err
+	}
+	for flag, enabled := range changed {
+		args.Logger.Infof("feature flag %q changed, enabled: %t", flag, enabled)
+	}
+
 	runServerContext, cancel := context.WithCancel(ctx)
 	return &serverRunner{
 		backgroundContext:      ctx,
@@ -316,6 +357,7 @@ func newServerRunner(ctx context.Context, args serverRunnerParams) (*serverRunne
 		cancelRunServerContext: cancel,
 
 		config:        cfg,
+		rawConfig:     args.RawConfig,
 		acker:         args.Acker,
 		pipeline:      args.Pipeline,
 		namespace:     args.Namespace,
@@ -347,10 +389,22 @@ func (s *serverRunner) Start() {
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		s.run()
+		if err := s.run(); err != nil && !errors.Is(err, context.Canceled) {
+			s.logger.With(logp.Error(err)).Error("APM Server failed")
+			s.updateStatus(management.Failed, err.Error())
+		}
 	}()
 }
 
+// updateStatus reports the server's status to Fleet, if the server is
+// managed. Fleet reports the status of the configuration being applied,
+// but not whether the server subsequently runs.
+func (s *serverRunner) updateStatus(status management.Status, msg string) {
+	if s.beat.Manager != nil && s.beat.Manager.Enabled() {
+		s.beat.Manager.UpdateStatus(status, msg)
+	}
+}
+
 func (s *serverRunner) run() error {
 	// Send config to telemetry.
 	recordAPMServerConfig(s.config)
@@ -366,12 +420,114 @@ func (s *serverRunner) run() error {
 		TransformConfig: transformConfig,
 	}
 
-	// When the publisher stops cleanly it will close its pipeline client,
-	// calling the acker's Close method. We need to call Open for each new
-	// publisher to ensure we wait for all clients and enqueued events to
-	// be closed at shutdown time.
-	s.acker.Open()
+	if s.config.Journal.Enabled {
+		publisherConfig.Journal = &publish.JournalConfig{
+			Dir:     paths.Resolve(paths.Data, s.config.Journal.Path),
+			MaxSize: int64(s.config.Journal.MaxSize),
+			MaxAge:  s.config.Journal.MaxAge,
+
+			SegmentSize: config.JournalSegmentSize,
+		}
+	}
+	procs := processors.NewList(s.logger)
+	if checker := s.newPipelineChecker(); checker != nil {
+		checker.check(s.runServerContext)
+		deregister, err := checker.checkOnConnect(s.runServerContext)
+		if err != nil {
+			return err
+		}
+		defer deregister()
+		if s.config.PipelineCheck.Fallback {
+			procs.AddProcessor(checker)
+		}
+	}
+	if s.config.Lookup.Enabled {
+		enricher, err := lookup.NewEnricher(
+			paths.Resolve(paths.Config, s.config.Lookup.Path),
+			s.config.Lookup.Field,
+			s.config.Lookup.TargetField,
+		)
+		if err != nil {
+			return err
+		}
+		go enricher.Watch(s.runServerContext, s.config.Lookup.ReloadPeriod)
+		procs.AddProcessor(enricher)
+	}
+	if s.config.GeoIP.Enabled {
+		enricher, err := geoip.NewEnricher(paths.Resolve(paths.Config, s.config.GeoIP.Database))
+		if err != nil {
+			return err
+		}
+		go enricher.Watch(s.runServerContext, s.config.GeoIP.ReloadPeriod)
+		procs.AddProcessor(enricher)
+	}
+	if s.config.Pseudonymization.Enabled {
+		// Fields supported by the model are pseudonymized before
+		// sampling, in wrapRunServerWithIntakeProcessors. The others
+		// are pseudonymized here, after GeoIP enrichment.
+		_, processor := modelprocessor.NewPseudonymize(s.config.Pseudonymization.Salt, s.config.Pseudonymization.Rules())
+		if processor != nil {
+			procs.AddProcessor(processor)
+		}
+	}
+	if s.config.IndexRouting.Enabled {
+		router, err := indexrouting.NewRouter(
+			paths.Resolve(paths.Config, s.config.IndexRouting.Path),
+			transformConfig.DataStreams,
+		)
+		if err != nil {
+			return err
+		}
+		go router.Watch(s.runServerContext, s.config.IndexRouting.ReloadPeriod)
+		procs.AddProcessor(router)
+	}
+	if s.config.InternalDocuments.Enabled {
+		// Added after index routing, so internal documents
+		// are directed away from services' index aliases.
+		processor, err := newInternalDocumentsProcessor(s.config.InternalDocuments)
+		if err != nil {
+			return err
+		}
+		procs.AddProcessor(processor)
+	}
+	if s.config.IndexMetadata.Enabled {
+		// Added after index routing and internal documents,
+		// so the aliases they set are stored as the index.
+		processor, err := s.newIndexMetadataProcessor()
+		if err != nil {
+			return err
+		}
+		procs.AddProcessor(processor)
+	}
+	if s.config.PartitionKey.Enabled {
+		s.checkPartitionKeyOutput()
+		procs.AddProcessor(&partitionkey.Processor{FallbackField: s.config.PartitionKey.Fallback})
+	}
+	var usageTracker *usage.Tracker
+	if s.config.UsageReport.Enabled {
+		usageTracker = usage.NewTracker(s.config.UsageReport.MaxServices, s.config.DefaultServiceEnvironment)
+		procs.AddProcessor(usageTracker.Processor())
+	}
+	if len(procs.List) > 0 {
+		publisherConfig.Processor = procs
+	}
+	if s.config.EventQueues.Enabled {
+		publisherConfig.Queues = &publish.QueuesConfig{
+			Traces:  newPublishQueueConfig(s.config.EventQueues.Traces),
+			Errors:  newPublishQueueConfig(s.config.EventQueues.Errors),
+			Metrics: newPublishQueueConfig(s.config.EventQueues.Metrics),
+		}
+	}
+
+	// When the publisher stops cleanly it will close its pipeline clients,
+	// calling the acker's Close method for each. We need to call Open for
+	// each new pipeline client to ensure we wait for all clients and enqueued
+	// events to be closed at shutdown time.
 	pipeline := pipetool.WithACKer(s.pipeline, s.acker)
+	pipeline = pipetool.WithClientConfigEdit(pipeline, func(cfg beat.ClientConfig) (beat.ClientConfig, error) {
+		s.acker.Open()
+		return cfg, nil
+	})
 
 	publisher, err := publish.NewPublisher(pipeline, s.tracer, publisherConfig)
 	if err != nil {
@@ -383,6 +539,28 @@ func (s *serverRunner) run() error {
 	// wrap depending on the configuration in order to inject behaviour.
 	reporter := publisher.Send
 	runServer := newBaseRunServer(reporter)
+	// Intake processors wrap the base runServer directly, so they
+	// process events before any processors injected by the wrappers
+	// below, such as aggregation and tail-based sampling.
+	runServer = s.wrapRunServerWithIntakeProcessors(runServer)
+	if s.config.DecodeLimits.Enabled {
+		// Limit in-flight events around the entire processing
+		// pipeline, so all protocols are subject to the limit.
+		runServer = runServerWithInFlightLimit(runServer, s.config.DecodeLimits.MaxInFlightEvents)
+	}
+	if usageTracker != nil {
+		runServer = runServerWithUsage(runServer, usageTracker, s.config.UsageReport.Interval)
+	}
+	if s.config.Synthetics.Enabled {
+		runServer = runServerWithSynthetics(runServer)
+	}
+	if s.config.AlertWebhook.Enabled {
+		hook, err := newAlertHook(s.config.AlertWebhook)
+		if err != nil {
+			return err
+		}
+		runServer = runServerWithAlertHook(runServer, hook)
+	}
 	if s.tracerServer != nil {
 		runServer = runServerWithTracerServer(runServer, s.tracerServer, s.tracer)
 	}
@@ -394,6 +572,16 @@ func (s *serverRunner) run() error {
 	runServer = s.wrapRunServerWithPreprocessors(runServer)
 
 	var batchProcessor model.BatchProcessor = &reporterBatchProcessor{reporter}
+	if s.config.OTel.Export.Enabled {
+		// Export traces alongside publishing events, after
+		// any unsampled transactions have been discarded.
+		exporter, err := otlp.NewExporter(s.config.OTel.Export)
+		if err != nil {
+			return err
+		}
+		defer exporter.Shutdown(s.backgroundContext)
+		batchProcessor = modelprocessor.Chained{exporter, batchProcessor}
+	}
 	if !s.config.Sampling.KeepUnsampled {
 		// The server has been configured to discard unsampled
 		// transactions. Make sure this is done just before calling
@@ -403,18 +591,78 @@ func (s *serverRunner) run() error {
 		}
 	}
 
-	if err := runServer(s.runServerContext, ServerParams{
-		Info:           s.beat.Info,
-		Config:         s.config,
-		Managed:        s.beat.Manager != nil && s.beat.Manager.Enabled(),
-		Namespace:      s.namespace,
-		Logger:         s.logger,
-		Tracer:         s.tracer,
-		BatchProcessor: batchProcessor,
-	}); err != nil {
-		return err
+	if s.config.RumConfig.IsEnabled() && s.config.RumConfig.ErrorSampling.Enabled {
+		// Sample RUM errors just before calling the publisher,
+		// so error aggregations account for all errors.
+		cfg := s.config.RumConfig.ErrorSampling
+		batchProcessor = modelprocessor.Chained{
+			sampling.NewRUMErrorSampler(sampling.ErrorSamplerConfig{
+				MaxPerKey: cfg.MaxPerKey,
+				Interval:  cfg.Interval,
+				MaxGroups: cfg.MaxGroups,
+			}),
+			batchProcessor,
+		}
+	}
+
+	serverLifecycle.started(s.logger, s.config, s.rawConfig)
+	s.updateStatus(management.Running, "Running")
+	err = runServer(s.runServerContext, ServerParams{
+		Info:            s.beat.Info,
+		Config:          s.config,
+		Managed:         s.beat.Manager != nil && s.beat.Manager.Enabled(),
+		Namespace:       s.namespace,
+		Logger:          s.logger,
+		Tracer:          s.tracer,
+		BatchProcessor:  batchProcessor,
+		TransformConfig: transformConfig,
+	})
+	serverLifecycle.stopping(s.logger, err, s.acker.ackedEvents())
+	if err == nil {
+		err = publisher.Stop(s.backgroundContext)
+	}
+	serverLifecycle.stopped(s.logger, s.acker.ackedEvents())
+	return err
+}
+
+// newInternalDocumentsProcessor returns an internaldocs.Processor
+// for setting the indices and IDs of internal documents.
+func newInternalDocumentsProcessor(cfg config.InternalDocumentsConfig) (*internaldocs.Processor, error) {
+	processor := &internaldocs.Processor{Namespace: cfg.Namespace}
+	if cfg.Index != "" {
+		index, err := internaldocs.NewFormatIndex(cfg.Index)
+		if err != nil {
+			return nil, err
+		}
+		processor.Index = index
+	}
+	if cfg.ID == config.InternalDocumentsIDContentHash {
+		processor.IDs = internaldocs.ContentHashID{}
+	}
+	return processor, nil
+}
+
+// checkPartitionKeyOutput logs a warning if the output partitions events,
+// but does not use the partition key set by partitionkey.Processor.
+func (s *serverRunner) checkPartitionKeyOutput() {
+	if s.beat == nil || s.beat.Config == nil || s.beat.Config.Output.Name() != "kafka" {
+		return
+	}
+	key, _ := s.beat.Config.Output.Config().String("key", -1)
+	if key != partitionkey.KeyFormat {
+		s.logger.Warnf(
+			"partition_key is enabled, but output.kafka.key is %q: set it to %q to partition events by trace",
+			key, partitionkey.KeyFormat,
+		)
+	}
+}
+
+func newPublishQueueConfig(cfg config.EventQueueConfig) publish.QueueConfig {
+	return publish.QueueConfig{
+		Size:           cfg.Size,
+		FlushMinEvents: cfg.Flush.MinEvents,
+		FlushTimeout:   cfg.Flush.Timeout,
 	}
-	return publisher.Stop(s.backgroundContext)
 }
 
 func (s *serverRunner) wrapRunServerWithPreprocessors(runServer RunServerFunc) RunServerFunc {
@@ -432,6 +680,46 @@ func (s *serverRunner) wrapRunServerWithPreprocessors(runServer RunServerFunc) R
 	return WrapRunServerWithProcessors(runServer, processors...)
 }
 
+// wrapRunServerWithIntakeProcessors wraps runServer such that events received
+// by the intake handlers are first passed through processors which must run
+// before events are aggregated or stored for tail-based sampling.
+//
+// This must be applied to the base runServer function, as each wrapper
+// prepends its processors to those of the runServer functions it wraps.
+func (s *serverRunner) wrapRunServerWithIntakeProcessors(runServer RunServerFunc) RunServerFunc {
+	var processors []model.BatchProcessor
+	if s.config.Pseudonymization.Enabled {
+		// Pseudonymize the fields held by the model before events are
+		// stored for tail-based sampling or aggregated, so their raw
+		// values are never stored. Rules for other fields, such as
+		// labels, are applied as events are published.
+		processor, _ := modelprocessor.NewPseudonymize(s.config.Pseudonymization.Salt, s.config.Pseudonymization.Rules())
+		if processor != nil {
+			processors = append(processors, processor)
+		}
+	}
+	if s.config.TransactionResult.Enabled {
+		// Normalize transaction.result before aggregation,
+		// so metrics are grouped by the normalized values.
+		processors = append(processors, newNormalizeTransactionResult(s.config.TransactionResult))
+	}
+	return WrapRunServerWithProcessors(runServer, processors...)
+}
+
+// newNormalizeTransactionResult returns a model.BatchProcessor for normalizing
+// transaction.result values according to cfg.
+func newNormalizeTransactionResult(cfg config.TransactionResultConfig) *modelprocessor.NormalizeTransactionResult {
+	rules := make([]modelprocessor.TransactionResultRule, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		rules[i] = modelprocessor.TransactionResultRule{
+			// Rules are validated when the config is unpacked.
+			Match:  regexp.MustCompile(rule.Match),
+			Result: rule.Result,
+		}
+	}
+	return &modelprocessor.NormalizeTransactionResult{Rules: rules, HTTP: cfg.HTTP, GRPC: cfg.GRPC}
+}
+
 // checkConfig verifies the global configuration doesn't use unsupported settings
 //
 // TODO(axw) remove this, nobody expects dashboard setup from apm-server.
@@ -458,6 +746,134 @@ func checkConfig(logger *logp.Logger) error {
 	return nil
 }
 
+// runPreflightChecks runs the startup preflight checks and logs a consolidated
+// report. If strict mode is enabled, an error is returned if any check fails.
+func (bt *beater) runPreflightChecks(b *beat.Beat) error {
+	client, err := elasticsearchOutputClient(b)
+	if err != nil {
+		return errors.Wrap(err, "preflight checks")
+	}
+	args := preflight.Params{Info: b.Info, Config: bt.config, Elasticsearch: client}
+	if client != nil {
+		if args.Credentials, err = preflightCredentials(b, bt.config); err != nil {
+			return errors.Wrap(err, "preflight checks")
+		}
+	}
+	var ilmRawConfig *common.Config
+	if bt.rawConfig.HasField("ilm") {
+		if ilmRawConfig, err = bt.rawConfig.Child("ilm", -1); err != nil {
+			return err
+		}
+	}
+	ilmConfig, err := ilm.NewConfig(b.Info, ilmRawConfig)
+	if err != nil {
+		return err
+	}
+	args.ILM = ilmConfig
+
+	ctx, cancel := context.WithTimeout(context.Background(), bt.config.Preflight.Timeout)
+	defer cancel()
+	report := preflight.Run(ctx, args)
+	if !report.Failed() {
+		bt.logger.Info(report.String())
+		return nil
+	}
+	bt.logger.Warn(report.String())
+	if bt.config.Preflight.Strict {
+		return errors.New("preflight checks failed, refusing to start in strict mode")
+	}
+	return nil
+}
+
+// preflightCredentials returns Elasticsearch clients for the credentials,
+// other than the output's, whose runtime privileges are checked by the
+// preflight checks.
+func preflightCredentials(b *beat.Beat, cfg *config.Config) (map[string]elasticsearch.Client, error) {
+	esConfigs := make(map[string]*elasticsearch.Config)
+	if cfg.RumConfig.IsEnabled() && cfg.RumConfig.SourceMapping.IsEnabled() && cfg.RumConfig.SourceMapping.ESConfig != nil {
+		esConfigs[preflight.CredentialsSourceMapping] = cfg.RumConfig.SourceMapping.ESConfig
+	}
+	if cfg.AgentConfig.ElasticsearchEnabled() && cfg.AgentConfig.ESConfig != nil {
+		esConfigs[preflight.CredentialsAgentConfig] = cfg.AgentConfig.ESConfig
+	}
+	if cfg.Kibana.Enabled {
+		// The Kibana credentials are checked against the output's
+		// Elasticsearch cluster, which Kibana is expected to share.
+		esConfig := elasticsearch.DefaultConfig()
+		if err := elasticsearchOutputConfig(b).Unpack(esConfig); err != nil {
+			return nil, errors.Wrap(err, "unpacking Elasticsearch config")
+		}
+		esConfig.Username = cfg.Kibana.Username
+		esConfig.Password = cfg.Kibana.Password
+		esConfig.APIKey = cfg.Kibana.APIKey
+		esConfigs[preflight.CredentialsKibana] = esConfig
+	}
+	clients := make(map[string]elasticsearch.Client, len(esConfigs))
+	for credentials, esConfig := range esConfigs {
+		client, err := elasticsearch.NewClient(esConfig)
+		if err != nil {
+			return nil, errors.Wrapf(err, "creating Elasticsearch client for %s", credentials)
+		}
+		clients[credentials] = client
+	}
+	return clients, nil
+}
+
+// waitForOutput waits until the Elasticsearch output is reachable, so the
+// output's connections are established by the time the server starts
+// accepting requests, or until the warm-up timeout expires.
+func (bt *beater) waitForOutput(b *beat.Beat) error {
+	client, err := elasticsearchOutputClient(b)
+	if err != nil {
+		return errors.Wrap(err, "warm-up")
+	}
+	if client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), bt.config.Warmup.Timeout)
+	defer cancel()
+	if err := waitForElasticsearch(ctx, client, time.Second); err != nil {
+		bt.logger.With(logp.Error(err)).Warn("Elasticsearch output is not reachable, starting server regardless")
+		return nil
+	}
+	bt.logger.Info("Elasticsearch output is reachable")
+	return nil
+}
+
+// waitForElasticsearch queries Elasticsearch every interval until a
+// query succeeds, returning the last error if ctx is done first.
+func waitForElasticsearch(ctx context.Context, client elasticsearch.Client, interval time.Duration) error {
+	for {
+		_, err := elasticsearch.GetVersion(ctx, client)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(interval):
+		}
+	}
+}
+
+// elasticsearchOutputClient returns a client for the Elasticsearch output,
+// or nil if the output is not elasticsearch.
+func elasticsearchOutputClient(b *beat.Beat) (elasticsearch.Client, error) {
+	esConfig := elasticsearchOutputConfig(b)
+	if esConfig == nil {
+		return nil, nil
+	}
+	cfg := elasticsearch.DefaultConfig()
+	if err := esConfig.Unpack(cfg); err != nil {
+		return nil, errors.Wrap(err, "unpacking Elasticsearch config")
+	}
+	client, err := elasticsearch.NewClient(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating Elasticsearch client")
+	}
+	return client, nil
+}
+
 // elasticsearchOutputConfig returns nil if the output is not elasticsearch
 func elasticsearchOutputConfig(b *beat.Beat) *common.Config {
 	if hasElasticsearchOutput(b) {
@@ -470,6 +886,32 @@ func hasElasticsearchOutput(b *beat.Beat) bool {
 	return b.Config != nil && b.Config.Output.Name() == "elasticsearch"
 }
 
+// newIndexMetadataProcessor returns an idxmgmt.NewMetadataProcessor for the
+// index management config of the apm-server section, which is the only part
+// of the root config passed to the server.
+func (s *serverRunner) newIndexMetadataProcessor() (beat.Processor, error) {
+	rootConfig := common.NewConfig()
+	if err := rootConfig.SetChild("apm-server", -1, s.rawConfig); err != nil {
+		return nil, err
+	}
+	return idxmgmt.NewMetadataProcessor(s.beat.Info, rootConfig)
+}
+
+// noPipeline is the special pipeline name which, when configured as
+// `output.elasticsearch.pipeline`, disables the use of ingest pipelines.
+const noPipeline = "_none"
+
+// pipelinesDisabled reports whether the use of ingest pipelines is disabled,
+// by setting `output.elasticsearch.pipeline` to noPipeline.
+func pipelinesDisabled(b *beat.Beat) bool {
+	esConfig := elasticsearchOutputConfig(b)
+	if esConfig == nil {
+		return false
+	}
+	pipeline, _ := esConfig.String("pipeline", -1)
+	return pipeline == noPipeline
+}
+
 // registerPipelineCallback registers an Elasticsearch connection callback
 // that ensures the configured pipeline is installed, if configured to do
 // so. If data streams are enabled, then pipeline registration is always
@@ -495,7 +937,9 @@ func (bt *beater) registerPipelineCallback(b *beat.Beat) error {
 
 	bt.logger.Info("Registering pipeline callback")
 	overwrite := bt.config.Register.Ingest.Pipeline.ShouldOverwrite()
+	upgrade := bt.config.TemplateUpgrade.Enabled
 	path := bt.config.Register.Ingest.Pipeline.Path
+	version := b.Info.Version
 
 	// ensure setup cmd is working properly
 	b.OverwritePipelinesCallback = func(esConfig *common.Config) error {
@@ -503,11 +947,17 @@ func (bt *beater) registerPipelineCallback(b *beat.Beat) error {
 		if err != nil {
 			return err
 		}
-		return pipeline.RegisterPipelines(conn, overwrite, path)
+		return pipeline.RegisterPipelines(conn, overwrite, upgrade, path, version)
+	}
+	if pipelinesDisabled(b) {
+		// `setup --pipelines` still registers pipelines, but they are
+		// not registered on connection since they will not be used.
+		bt.logger.Info("Ingest pipelines disabled: pipeline registration on connection disabled")
+		return nil
 	}
 	// ensure pipelines are registered when new ES connection is established.
 	_, err := esoutput.RegisterConnectCallback(func(conn *eslegclient.Connection) error {
-		return pipeline.RegisterPipelines(conn, overwrite, path)
+		return pipeline.RegisterPipelines(conn, overwrite, upgrade, path, version)
 	})
 	return err
 }
@@ -578,6 +1028,7 @@ func (bt *beater) Stop() {
 		"stopping apm-server... waiting maximum of %v seconds for queues to drain",
 		bt.config.ShutdownTimeout.Seconds(),
 	)
+	serverLifecycle.requestShutdown()
 	bt.stopServer()
 	bt.stopped = true
 }
@@ -597,17 +1048,130 @@ func runServerWithTracerServer(runServer RunServerFunc, tracerServer *tracerServ
 	}
 }
 
+// runServerWithInFlightLimit wraps runServer such that batches are
+// rejected with publish.ErrFull while more than maxEvents events are
+// being processed.
+func runServerWithInFlightLimit(runServer RunServerFunc, maxEvents int) RunServerFunc {
+	return func(ctx context.Context, args ServerParams) error {
+		args.BatchProcessor = &modelprocessor.LimitInFlightEvents{
+			Processor: args.BatchProcessor,
+			MaxEvents: maxEvents,
+		}
+		return runServer(ctx, args)
+	}
+}
+
+// runServerWithUsage wraps runServer such that usage of received events
+// is recorded with tracker, and usage documents are published every
+// interval until the server shuts down.
+func runServerWithUsage(runServer RunServerFunc, tracker *usage.Tracker, interval time.Duration) RunServerFunc {
+	return func(ctx context.Context, args ServerParams) error {
+		// Usage documents are published directly, so they
+		// are not themselves recorded.
+		processor := args.BatchProcessor
+		args.BatchProcessor = tracker.BatchProcessor(processor)
+		g, ctx := errgroup.WithContext(ctx)
+		g.Go(func() error {
+			return tracker.Run(ctx, processor, interval)
+		})
+		g.Go(func() error {
+			return runServer(ctx, args)
+		})
+		return g.Wait()
+	}
+}
+
+// runServerWithSynthetics wraps runServer such that it also runs
+// the configured synthetic monitors until the server shuts down.
+func runServerWithSynthetics(runServer RunServerFunc) RunServerFunc {
+	return func(ctx context.Context, args ServerParams) error {
+		monitors := make([]synthetics.Monitor, len(args.Config.Synthetics.Monitors))
+		for i, in := range args.Config.Synthetics.Monitors {
+			monitors[i] = synthetics.Monitor{
+				URL:                in.URL,
+				ServiceName:        in.Service.Name,
+				ServiceEnvironment: in.Service.Environment,
+				Interval:           in.Interval,
+				Timeout:            in.Timeout,
+				ExpectedStatus:     in.ExpectedStatus,
+			}
+		}
+		g, ctx := errgroup.WithContext(ctx)
+		g.Go(func() error {
+			return synthetics.Run(ctx, synthetics.Config{
+				Monitors:       monitors,
+				BatchProcessor: args.BatchProcessor,
+			})
+		})
+		g.Go(func() error {
+			return runServer(ctx, args)
+		})
+		return g.Wait()
+	}
+}
+
+// newAlertHook returns a new alerthook.Hook for cfg.
+func newAlertHook(cfg config.AlertWebhookConfig) (*alerthook.Hook, error) {
+	rules := make([]alerthook.Rule, len(cfg.Rules))
+	for i, in := range cfg.Rules {
+		rules[i] = alerthook.Rule{
+			Name:        in.Name,
+			Event:       in.Event,
+			ServiceName: in.Service,
+			Labels:      in.Labels,
+			MinDuration: in.MinDuration,
+		}
+	}
+	return alerthook.New(alerthook.Config{
+		URL:          cfg.URL,
+		Headers:      cfg.Headers,
+		MaxPerMinute: cfg.MaxPerMinute,
+		Rules:        rules,
+		Client:       &http.Client{Timeout: cfg.Timeout},
+	})
+}
+
+// runServerWithAlertHook wraps runServer such that events matching
+// the hook's rules are alerted on, until the server shuts down.
+func runServerWithAlertHook(runServer RunServerFunc, hook *alerthook.Hook) RunServerFunc {
+	return func(ctx context.Context, args ServerParams) error {
+		args.BatchProcessor = hook.BatchProcessor(args.BatchProcessor)
+		g, ctx := errgroup.WithContext(ctx)
+		g.Go(func() error {
+			return hook.Run(ctx)
+		})
+		g.Go(func() error {
+			return runServer(ctx, args)
+		})
+		return g.Wait()
+	}
+}
+
 func newTransformConfig(beatInfo beat.Info, cfg *config.Config) (*transform.Config, error) {
 	transformConfig := &transform.Config{
-		DataStreams: cfg.DataStreams.Enabled,
+		DataStreams:      cfg.DataStreams.Enabled,
+		DedupStacktraces: cfg.StacktraceDedup.Enabled,
 		RUM: transform.RUMConfig{
 			LibraryPattern:      regexp.MustCompile(cfg.RumConfig.LibraryPattern),
 			ExcludeFromGrouping: regexp.MustCompile(cfg.RumConfig.ExcludeFromGrouping),
 		},
 	}
+	if cfg.Expiry.Enabled {
+		transformConfig.Expiry = cfg.Expiry.TTL
+	}
+	if cfg.KeywordTruncation.Enabled {
+		fields := cfg.KeywordTruncation.Fields
+		if len(fields) == 0 {
+			fields = config.DefaultKeywordTruncationFields
+		}
+		transformConfig.KeywordTruncation = transform.KeywordTruncationConfig{
+			MaxLength: cfg.KeywordTruncation.MaxLength,
+			Fields:    fields,
+		}
+	}
 
 	if cfg.RumConfig.IsEnabled() && cfg.RumConfig.SourceMapping.IsEnabled() && cfg.RumConfig.SourceMapping.ESConfig != nil {
-		store, err := newSourcemapStore(beatInfo, cfg.RumConfig.SourceMapping)
+		store, err := newSourcemapStore(beatInfo, cfg.RumConfig.SourceMapping, cfg.SideLookups)
 		if err != nil {
 			return nil, err
 		}
@@ -617,13 +1181,44 @@ func newTransformConfig(beatInfo beat.Info, cfg *config.Config) (*transform.Conf
 	return transformConfig, nil
 }
 
-func newSourcemapStore(beatInfo beat.Info, cfg *config.SourceMapping) (*sourcemap.Store, error) {
+func newSourcemapStore(beatInfo beat.Info, cfg *config.SourceMapping, lookups config.SideLookupsConfig) (*sourcemap.Store, error) {
 	esClient, err := elasticsearch.NewClient(cfg.ESConfig)
 	if err != nil {
 		return nil, err
 	}
 	index := strings.ReplaceAll(cfg.IndexPattern, "%{[observer.version]}", beatInfo.Version)
-	return sourcemap.NewStore(esClient, index, cfg.Cache.Expiration)
+	external := make([]sourcemap.ExternalSource, len(cfg.External))
+	for i, ext := range cfg.External {
+		external[i] = sourcemap.ExternalSource{
+			ServiceName:    ext.ServiceName,
+			ServiceVersion: ext.ServiceVersion,
+			URL:            ext.URL,
+			Headers:        ext.Headers,
+			Timeout:        ext.Timeout,
+			Proxy:          ext.Proxy,
+
+			MaxSize:               int64(ext.MaxSize),
+			MaxConcurrentRequests: ext.MaxConcurrentRequests,
+		}
+	}
+	store, err := sourcemap.NewStore(esClient, index, cfg.Cache.Expiration, sourcemap.VersionSelection(cfg.VersionSelection), external...)
+	if err != nil {
+		return nil, err
+	}
+	if lookups.Enabled {
+		pool, err := sidelookup.NewPool(sidelookup.Config{
+			Workers:          lookups.Workers,
+			QueueSize:        lookups.QueueSize,
+			Timeout:          lookups.Timeout,
+			FailureThreshold: lookups.CircuitBreaker.Failures,
+			Cooldown:         lookups.CircuitBreaker.Cooldown,
+		})
+		if err != nil {
+			return nil, err
+		}
+		store.SetLookupPool(pool)
+	}
+	return store, nil
 }
 
 // WrapRunServerWithProcessors wraps runServer such that it wraps args.Reporter
@@ -36,6 +36,8 @@ import (
 	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch/estest"
+	"github.com/elastic/apm-server/idxmgmt"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
@@ -291,3 +293,88 @@ func TestTransformConfig(t *testing.T) {
 func newBool(v bool) *bool {
 	return &v
 }
+
+func TestWaitForElasticsearch(t *testing.T) {
+	var requests int
+	var available bool
+	client, err := estest.NewElasticsearchClient(estest.NewTransportFunc(t, func(map[string]interface{}) (int, map[string]interface{}) {
+		requests++
+		if !available || requests < 3 {
+			return http.StatusInternalServerError, nil
+		}
+		return http.StatusOK, map[string]interface{}{"version": map[string]interface{}{"number": "7.14.0"}}
+	}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, waitForElasticsearch(ctx, client, time.Millisecond))
+
+	available = true
+	requests = 0
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, waitForElasticsearch(ctx, client, time.Millisecond))
+	assert.Equal(t, 3, requests)
+}
+
+func TestPipelinesDisabled(t *testing.T) {
+	test := func(outputConfig map[string]interface{}, expected bool) {
+		var beatConfig beat.BeatConfig
+		require.NoError(t, beatConfig.Output.Unpack(common.MustNewConfigFrom(outputConfig)))
+		assert.Equal(t, expected, pipelinesDisabled(&beat.Beat{Config: &beatConfig}))
+	}
+	test(map[string]interface{}{"elasticsearch": map[string]interface{}{}}, false)
+	test(map[string]interface{}{"elasticsearch": map[string]interface{}{"pipeline": "custom"}}, false)
+	test(map[string]interface{}{"elasticsearch": map[string]interface{}{"pipeline": "_none"}}, true)
+	test(map[string]interface{}{"console": map[string]interface{}{"pipeline": "_none"}}, false)
+	assert.False(t, pipelinesDisabled(&beat.Beat{}))
+}
+
+func TestIndexMetadataProcessor(t *testing.T) {
+	s := &serverRunner{
+		beat:      &beat.Beat{Info: beat.Info{Version: "7.0.0"}},
+		rawConfig: common.MustNewConfigFrom(map[string]interface{}{"data_streams.enabled": true}),
+	}
+	processor, err := s.newIndexMetadataProcessor()
+	require.NoError(t, err)
+
+	event, err := processor.Run(&beat.Event{Fields: common.MapStr{
+		"processor":   common.MapStr{"event": "transaction"},
+		"data_stream": common.MapStr{"type": "traces", "dataset": "apm", "namespace": "default"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, common.MapStr{
+		idxmgmt.MetaIndexField:     "traces-apm-default",
+		idxmgmt.MetaEventTypeField: "transaction",
+	}, event.Meta)
+}
+
+func TestIntakeProcessorsRunFirst(t *testing.T) {
+	s := &serverRunner{config: config.DefaultConfig()}
+	s.config.TransactionResult = config.TransactionResultConfig{Enabled: true, HTTP: true}
+
+	// Record the transaction results seen by a processor injected by
+	// an outer wrapper, as done for aggregation and tail-based sampling.
+	var results []string
+	recordResults := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		for _, tx := range batch.Transactions {
+			results = append(results, tx.Result)
+		}
+		return nil
+	})
+	var runServer RunServerFunc = func(ctx context.Context, args ServerParams) error {
+		return args.BatchProcessor.ProcessBatch(ctx, &model.Batch{
+			Transactions: []*model.Transaction{{Result: "HTTP 200"}},
+		})
+	}
+	runServer = s.wrapRunServerWithIntakeProcessors(runServer)
+	runServer = WrapRunServerWithProcessors(runServer, recordResults)
+	runServer = s.wrapRunServerWithPreprocessors(runServer)
+
+	err := runServer(context.Background(), ServerParams{BatchProcessor: model.ProcessBatchFunc(
+		func(context.Context, *model.Batch) error { return nil },
+	)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"HTTP 2xx"}, results)
+}
@@ -17,7 +17,13 @@
 
 package beatertest
 
-import "fmt"
+import (
+	"fmt"
 
-// ResultErrWrap wraps given input into the expected result error string
-func ResultErrWrap(s string) string { return fmt.Sprintf("{\"error\":\"%+v\"}\n", s) }
+	"github.com/elastic/apm-server/beater/errorcode"
+)
+
+// ResultErrWrap wraps given input and error code into the expected result error string
+func ResultErrWrap(s string, code errorcode.Code) string {
+	return fmt.Sprintf("{\"code\":\"%s\",\"error\":\"%+v\"}\n", code, s)
+}
@@ -0,0 +1,78 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Acknowledgement levels which agents may request for intake requests.
+const (
+	AckLevelValidate = "validate"
+	AckLevelEnqueue  = "enqueue"
+	AckLevelOutput   = "output"
+)
+
+// AckLevelConfig holds configuration for the acknowledgement levels
+// agents may request with the Elastic-Apm-Ack-Level header.
+type AckLevelConfig struct {
+	// Backend holds the ack levels allowed for backend intake requests.
+	Backend []string `config:"backend"`
+
+	// RUM holds the ack levels allowed for RUM intake requests.
+	RUM []string `config:"rum"`
+
+	// MaxBackgroundBatches holds the maximum number of batches of events
+	// processed in the background for requests with the validate level.
+	// Further batches are processed before responding.
+	MaxBackgroundBatches int `config:"max_background_batches" validate:"min=1"`
+
+	// Timeout holds the maximum duration for waiting for the output to
+	// acknowledge events, and for processing batches in the background.
+	Timeout time.Duration `config:"timeout"`
+}
+
+func (c *AckLevelConfig) Validate() error {
+	for _, levels := range [][]string{c.Backend, c.RUM} {
+		for _, level := range levels {
+			switch level {
+			case AckLevelValidate, AckLevelEnqueue, AckLevelOutput:
+			default:
+				return errors.Errorf(
+					"invalid ack level %q, expected one of: %s, %s, %s",
+					level, AckLevelValidate, AckLevelEnqueue, AckLevelOutput,
+				)
+			}
+		}
+	}
+	if c.Timeout <= 0 {
+		return errors.New("ack level timeout must be greater than 0")
+	}
+	return nil
+}
+
+func defaultAckLevelConfig() AckLevelConfig {
+	return AckLevelConfig{
+		Backend:              []string{AckLevelValidate, AckLevelEnqueue, AckLevelOutput},
+		RUM:                  []string{AckLevelEnqueue},
+		MaxBackgroundBatches: 100,
+		Timeout:              30 * time.Second,
+	}
+}
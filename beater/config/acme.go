@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/acme"
+)
+
+// ACMEConfig holds configuration for automatically provisioning and
+// renewing the server's TLS certificate using ACME, e.g. Let's Encrypt.
+//
+// TLS-ALPN-01 challenges are always served on the server's listener.
+// HTTP-01 challenges are served only if HTTPChallengeHost is set.
+type ACMEConfig struct {
+	// Enabled controls whether TLS certificates are provisioned with ACME.
+	// ACME cannot be enabled together with apm-server.ssl.
+	Enabled bool `config:"enabled"`
+
+	// AcceptTOS records that the operator accepts the Terms of Service of
+	// the ACME certificate authority. It must be true for ACME to be enabled.
+	AcceptTOS bool `config:"accept_tos"`
+
+	// Domains holds the domain names for which certificates may be
+	// requested. Requests for any other server name are rejected.
+	Domains []string `config:"domains"`
+
+	// Email holds an optional contact email address for the ACME account.
+	Email string `config:"email"`
+
+	// CacheDir holds the directory in which the account key and
+	// certificates are stored, relative to the data path.
+	CacheDir string `config:"cache_dir" validate:"required"`
+
+	// DirectoryURL holds the URL of the ACME directory endpoint.
+	DirectoryURL string `config:"directory_url" validate:"required"`
+
+	// HTTPChallengeHost holds an optional address, e.g. ":80", on which
+	// to serve HTTP-01 challenges. Other plain HTTP requests received on
+	// this address are redirected to HTTPS.
+	HTTPChallengeHost string `config:"http_challenge_host"`
+
+	// RenewBefore holds how long before expiry certificates are renewed.
+	RenewBefore time.Duration `config:"renew_before"`
+}
+
+func (c *ACMEConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if !c.AcceptTOS {
+		return errors.New("acme requires accept_tos to be set, to accept the ACME certificate authority's Terms of Service")
+	}
+	if len(c.Domains) == 0 {
+		return errors.New("acme requires at least one domain")
+	}
+	if c.RenewBefore <= 0 {
+		return errors.New("acme renew_before must be greater than zero")
+	}
+	return nil
+}
+
+func defaultACMEConfig() ACMEConfig {
+	return ACMEConfig{
+		CacheDir:     "acme",
+		DirectoryURL: acme.LetsEncryptURL,
+		RenewBefore:  30 * 24 * time.Hour,
+	}
+}
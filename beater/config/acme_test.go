@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestACMEConfigInvalid(t *testing.T) {
+	for name, cfg := range map[string]map[string]interface{}{
+		"missing accept_tos": {"acme": map[string]interface{}{"enabled": true, "domains": []string{"apm.example.com"}}},
+		"missing domains":    {"acme": map[string]interface{}{"enabled": true, "accept_tos": true}},
+		"zero renew_before":  {"acme": map[string]interface{}{"enabled": true, "accept_tos": true, "domains": []string{"apm.example.com"}, "renew_before": 0}},
+		"ssl enabled": {
+			"acme": map[string]interface{}{"enabled": true, "accept_tos": true, "domains": []string{"apm.example.com"}},
+			"ssl":  map[string]interface{}{"enabled": true, "certificate": "cert.pem", "key": "key.pem"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(cfg), nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestACMEConfigValid(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"acme": map[string]interface{}{"enabled": true, "accept_tos": true, "domains": []string{"apm.example.com"}},
+	}), nil)
+	require.NoError(t, err)
+	assert.True(t, cfg.ACME.Enabled)
+	assert.Equal(t, []string{"apm.example.com"}, cfg.ACME.Domains)
+}
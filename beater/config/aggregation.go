@@ -28,12 +28,25 @@ const (
 
 	defaultServiceDestinationAggregationInterval  = time.Minute
 	defaultServiceDestinationAggregationMaxGroups = 10000
+
+	defaultTransactionMarksAggregationInterval  = time.Minute
+	defaultTransactionMarksAggregationMaxGroups = 10000
+
+	defaultErrorAggregationInterval  = time.Minute
+	defaultErrorAggregationMaxGroups = 10000
+
+	defaultBreakdownAggregationInterval        = time.Minute
+	defaultBreakdownAggregationMaxGroups       = 10000
+	defaultBreakdownAggregationMaxPendingSpans = 100000
 )
 
 // AggregationConfig holds configuration related to various metrics aggregations.
 type AggregationConfig struct {
 	Transactions        TransactionAggregationConfig        `config:"transactions"`
 	ServiceDestinations ServiceDestinationAggregationConfig `config:"service_destinations"`
+	TransactionMarks    TransactionMarksAggregationConfig   `config:"transaction_marks"`
+	Errors              ErrorAggregationConfig              `config:"errors"`
+	Breakdown           BreakdownAggregationConfig          `config:"breakdown"`
 }
 
 // TransactionAggregationConfig holds configuration related to transaction metrics aggregation.
@@ -51,6 +64,40 @@ type ServiceDestinationAggregationConfig struct {
 	MaxGroups int           `config:"max_groups" validate:"min=1"`
 }
 
+// TransactionMarksAggregationConfig holds configuration related to transaction marks aggregation,
+// for RUM page-load marks.
+type TransactionMarksAggregationConfig struct {
+	Enabled   bool          `config:"enabled"`
+	Interval  time.Duration `config:"interval" validate:"min=1"`
+	MaxGroups int           `config:"max_groups" validate:"min=1"`
+}
+
+// ErrorAggregationConfig holds configuration related to error metrics aggregation,
+// counting errors per service and grouping key.
+type ErrorAggregationConfig struct {
+	Enabled   bool          `config:"enabled"`
+	Interval  time.Duration `config:"interval" validate:"min=1"`
+	MaxGroups int           `config:"max_groups" validate:"min=1"`
+}
+
+// BreakdownAggregationConfig holds configuration related to breakdown metrics
+// aggregation, computing span self-time per span type from received spans.
+type BreakdownAggregationConfig struct {
+	Enabled   bool          `config:"enabled"`
+	Interval  time.Duration `config:"interval" validate:"min=1"`
+	MaxGroups int           `config:"max_groups" validate:"min=1"`
+
+	// MaxPendingSpans holds the maximum number of spans buffered
+	// until their transaction is received, and of transactions held
+	// for spans received after them.
+	MaxPendingSpans int `config:"max_pending_spans" validate:"min=1"`
+
+	// Agents holds the agent name prefixes for which breakdown metrics
+	// are computed. If empty, they are computed for OpenTelemetry and
+	// Jaeger agents, which do not send breakdown metrics themselves.
+	Agents []string `config:"agents"`
+}
+
 func defaultAggregationConfig() AggregationConfig {
 	return AggregationConfig{
 		Transactions: TransactionAggregationConfig{
@@ -64,5 +111,18 @@ func defaultAggregationConfig() AggregationConfig {
 			Interval:  defaultServiceDestinationAggregationInterval,
 			MaxGroups: defaultServiceDestinationAggregationMaxGroups,
 		},
+		TransactionMarks: TransactionMarksAggregationConfig{
+			Interval:  defaultTransactionMarksAggregationInterval,
+			MaxGroups: defaultTransactionMarksAggregationMaxGroups,
+		},
+		Errors: ErrorAggregationConfig{
+			Interval:  defaultErrorAggregationInterval,
+			MaxGroups: defaultErrorAggregationMaxGroups,
+		},
+		Breakdown: BreakdownAggregationConfig{
+			Interval:        defaultBreakdownAggregationInterval,
+			MaxGroups:       defaultBreakdownAggregationMaxGroups,
+			MaxPendingSpans: defaultBreakdownAggregationMaxPendingSpans,
+		},
 	}
 }
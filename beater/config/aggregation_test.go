@@ -57,6 +57,11 @@ func TestAggregationConfigInvalid(t *testing.T) {
 		key:    "aggregation.transactions.hdrhistogram_significant_figures",
 		value:  float64(6),
 		expect: "Error processing configuration: requires value > 5 accessing 'aggregation.transactions.hdrhistogram_significant_figures'",
+	}, {
+		name:   "non-positive breakdown max_pending_spans",
+		key:    "aggregation.breakdown.max_pending_spans",
+		value:  float64(0),
+		expect: "Error processing configuration: requires value < 1 accessing 'aggregation.breakdown.max_pending_spans'",
 	}} {
 		t.Run(test.name, func(t *testing.T) {
 			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
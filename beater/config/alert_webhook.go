@@ -0,0 +1,123 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	alertWebhookEventError       = "error"
+	alertWebhookEventTransaction = "transaction"
+)
+
+// AlertWebhookConfig holds configuration for posting a summary of events
+// matching any of the configured rules to a webhook.
+type AlertWebhookConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// URL holds the webhook URL, to which alerts are posted as JSON.
+	URL string `config:"url"`
+
+	// Headers holds additional HTTP headers sent with each request,
+	// e.g. for authorization.
+	Headers map[string]string `config:"headers"`
+
+	// Timeout holds the maximum duration of a webhook request.
+	Timeout time.Duration `config:"timeout"`
+
+	// MaxPerMinute holds the maximum number of alerts posted per minute.
+	// Alerts in excess of the limit are dropped.
+	MaxPerMinute int `config:"max_per_minute"`
+
+	// Rules holds the rules for matching events. An alert is posted
+	// for each event matching any rule.
+	Rules []AlertWebhookRule `config:"rules"`
+}
+
+// AlertWebhookRule holds the conditions an event must meet to be alerted on.
+type AlertWebhookRule struct {
+	// Name identifies the rule in alerts.
+	Name string `config:"name" validate:"required"`
+
+	// Event holds the type of event matched by the rule:
+	// "error" or "transaction".
+	Event string `config:"event" validate:"required"`
+
+	// Service, if non-empty, holds the service name of matching events.
+	Service string `config:"service"`
+
+	// Labels, if non-empty, holds labels which matching events must have.
+	Labels map[string]string `config:"labels"`
+
+	// MinDuration, if positive, holds the minimum duration of
+	// matching transactions.
+	MinDuration time.Duration `config:"min_duration"`
+}
+
+func (c *AlertWebhookConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.URL == "" {
+		return errors.New("url must be specified")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return errors.Wrap(err, "invalid url")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("invalid url %q: scheme must be http or https", c.URL)
+	}
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+	if c.MaxPerMinute <= 0 {
+		return errors.New("max_per_minute must be positive")
+	}
+	if len(c.Rules) == 0 {
+		return errors.New("at least one rule must be specified")
+	}
+	return nil
+}
+
+func (r *AlertWebhookRule) Validate() error {
+	switch r.Event {
+	case alertWebhookEventError:
+		if r.MinDuration > 0 {
+			return errors.Errorf("rule %q: min_duration may only be specified for transactions", r.Name)
+		}
+	case alertWebhookEventTransaction:
+	default:
+		return errors.Errorf("rule %q: invalid event %q, expected %q or %q",
+			r.Name, r.Event, alertWebhookEventError, alertWebhookEventTransaction,
+		)
+	}
+	return nil
+}
+
+func defaultAlertWebhookConfig() AlertWebhookConfig {
+	return AlertWebhookConfig{
+		Enabled:      false,
+		Timeout:      5 * time.Second,
+		MaxPerMinute: 60,
+	}
+}
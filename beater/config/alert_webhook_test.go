@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestAlertWebhookConfigInvalid(t *testing.T) {
+	rules := []map[string]interface{}{{"name": "errors", "event": "error"}}
+	for name, test := range map[string]struct {
+		alertWebhook map[string]interface{}
+		err          string
+	}{
+		"no url": {
+			alertWebhook: map[string]interface{}{"enabled": true, "rules": rules},
+			err:          "url must be specified",
+		},
+		"invalid scheme": {
+			alertWebhook: map[string]interface{}{"enabled": true, "url": "ftp://example.com", "rules": rules},
+			err:          "scheme must be http or https",
+		},
+		"non-positive max_per_minute": {
+			alertWebhook: map[string]interface{}{
+				"enabled": true, "url": "http://example.com", "max_per_minute": 0, "rules": rules,
+			},
+			err: "max_per_minute must be positive",
+		},
+		"no rules": {
+			alertWebhook: map[string]interface{}{"enabled": true, "url": "http://example.com"},
+			err:          "at least one rule must be specified",
+		},
+		"invalid event": {
+			alertWebhook: map[string]interface{}{
+				"enabled": true, "url": "http://example.com",
+				"rules": []map[string]interface{}{{"name": "spans", "event": "span"}},
+			},
+			err: `rule "spans": invalid event "span"`,
+		},
+		"error min_duration": {
+			alertWebhook: map[string]interface{}{
+				"enabled": true, "url": "http://example.com",
+				"rules": []map[string]interface{}{{"name": "errors", "event": "error", "min_duration": "1s"}},
+			},
+			err: `rule "errors": min_duration may only be specified for transactions`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"alert_webhook": test.alertWebhook,
+			}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
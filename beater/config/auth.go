@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultAnonymousRateLimit        = 100
+	defaultAnonymousRateLimitLRUSize = 1000
+)
+
+// AuthConfig holds configuration related to authorization.
+type AuthConfig struct {
+	Anonymous AnonymousAuthConfig `config:"anonymous"`
+}
+
+// AnonymousAuthConfig holds configuration for permitting RUM agents to send
+// events without credentials. Anonymous requests are restricted to events
+// of allowed agents and services, and are rate limited separately from
+// authenticated requests.
+type AnonymousAuthConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// AllowAgent holds the names of agents whose events may be sent
+	// anonymously. If empty, events of any agent are allowed.
+	AllowAgent []string `config:"allow_agent"`
+
+	// AllowService holds the names of services whose events may be
+	// sent anonymously. If empty, events of any service are allowed.
+	AllowService []string `config:"allow_service"`
+
+	// RateLimit holds the per-IP event rate limit for anonymous
+	// requests, which applies in place of rum.event_rate.
+	RateLimit EventRate `config:"rate_limit"`
+}
+
+func (c *AnonymousAuthConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.RateLimit.Limit <= 0 {
+		return errors.New("rate_limit.limit must be greater than zero")
+	}
+	if c.RateLimit.LruSize <= 0 {
+		return errors.New("rate_limit.lru_size must be greater than zero")
+	}
+	return nil
+}
+
+func defaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		Anonymous: AnonymousAuthConfig{
+			RateLimit: EventRate{
+				Limit:   defaultAnonymousRateLimit,
+				LruSize: defaultAnonymousRateLimitLRUSize,
+			},
+		},
+	}
+}
@@ -0,0 +1,81 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AuthBypassRule identifies requests which are exempt from authorization,
+// even when a secret token or API Keys are required, e.g. health probes
+// sent by a sidecar over the loopback interface.
+//
+// A request matches the rule if its path is listed in Paths, and it was
+// received from an address in SourceAddresses. Both lists must be
+// non-empty, so that a rule cannot exempt all paths, or all sources.
+type AuthBypassRule struct {
+	// Paths holds the exact request paths matched by the rule.
+	Paths []string `config:"paths"`
+
+	// SourceAddresses holds the IP addresses or CIDR ranges matched by
+	// the rule. Addresses are matched against the connecting peer's
+	// address, never against headers such as X-Forwarded-For.
+	SourceAddresses []string `config:"source_addresses"`
+}
+
+func (r *AuthBypassRule) Validate() error {
+	if len(r.Paths) == 0 || len(r.SourceAddresses) == 0 {
+		return errors.New("both paths and source_addresses must be specified")
+	}
+	for _, path := range r.Paths {
+		if !strings.HasPrefix(path, "/") {
+			return errors.Errorf("invalid path %q: must begin with '/'", path)
+		}
+	}
+	for _, addr := range r.SourceAddresses {
+		if _, err := ParseSourceAddress(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseSourceAddress parses addr, an IP address or CIDR range, returning
+// the corresponding network. IP addresses are returned as single-address
+// networks.
+func ParseSourceAddress(addr string) (*net.IPNet, error) {
+	if strings.Contains(addr, "/") {
+		_, network, err := net.ParseCIDR(addr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid source address %q", addr)
+		}
+		return network, nil
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, errors.Errorf("invalid source address %q", addr)
+	}
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 8*net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestAuthBypassConfig(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"auth_bypass": []map[string]interface{}{{
+			"paths":            []string{"/"},
+			"source_addresses": []string{"127.0.0.1", "::1", "10.0.0.0/8"},
+		}},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []AuthBypassRule{{
+		Paths:           []string{"/"},
+		SourceAddresses: []string{"127.0.0.1", "::1", "10.0.0.0/8"},
+	}}, cfg.AuthBypass)
+}
+
+func TestAuthBypassConfigInvalid(t *testing.T) {
+	for name, rule := range map[string]map[string]interface{}{
+		"empty":                 {},
+		"paths only":            {"paths": []string{"/"}},
+		"source addresses only": {"source_addresses": []string{"127.0.0.1"}},
+		"relative path":         {"paths": []string{"healthz"}, "source_addresses": []string{"127.0.0.1"}},
+		"invalid IP":            {"paths": []string{"/"}, "source_addresses": []string{"localhost"}},
+		"invalid CIDR":          {"paths": []string{"/"}, "source_addresses": []string{"10.0.0.0/33"}},
+		"invalid suffix":        {"paths": []string{"/"}, "source_addresses": []string{"127.0.0.1:8200"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"auth_bypass": []map[string]interface{}{rule},
+			}), nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseSourceAddress(t *testing.T) {
+	for addr, expected := range map[string]string{
+		"127.0.0.1":       "127.0.0.1/32",
+		"::1":             "::1/128",
+		"10.1.2.3/8":      "10.0.0.0/8",
+		"fd00::/8":        "fd00::/8",
+		"::ffff:10.0.0.1": "10.0.0.1/32",
+	} {
+		network, err := ParseSourceAddress(addr)
+		require.NoError(t, err)
+		assert.Equal(t, expected, network.String())
+	}
+}
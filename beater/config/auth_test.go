@@ -0,0 +1,59 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestAnonymousAuthConfigInvalid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg map[string]interface{}
+		err string
+	}{
+		"zero_limit": {
+			cfg: map[string]interface{}{"enabled": true, "rate_limit": map[string]interface{}{"limit": 0}},
+			err: "rate_limit.limit must be greater than zero",
+		},
+		"zero_lru_size": {
+			cfg: map[string]interface{}{"enabled": true, "rate_limit": map[string]interface{}{"lru_size": 0}},
+			err: "rate_limit.lru_size must be greater than zero",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"auth": map[string]interface{}{"anonymous": tc.cfg},
+			}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestAnonymousAuthConfigDisabled(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"auth.anonymous.rate_limit.limit": 0,
+	}), nil)
+	require.NoError(t, err)
+	assert.False(t, cfg.Auth.Anonymous.Enabled)
+}
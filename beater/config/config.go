@@ -18,6 +18,7 @@
 package config
 
 import (
+	"encoding/base64"
 	"net"
 	"strings"
 	"time"
@@ -25,10 +26,13 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/common/cfgtype"
 	"github.com/elastic/beats/v7/libbeat/common/transport/tlscommon"
 	"github.com/elastic/beats/v7/libbeat/kibana"
 	"github.com/elastic/beats/v7/libbeat/logp"
 
+	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/httpproxy"
 	logs "github.com/elastic/apm-server/log"
 )
 
@@ -37,12 +41,24 @@ const (
 	DefaultPort = "8200"
 
 	msgInvalidConfigAgentCfg = "invalid value for `apm-server.agent.config.cache.expiration`, only accepting full seconds"
+
+	// AgentConfigSourceKibana fetches agent configuration via the Kibana API.
+	AgentConfigSourceKibana = "kibana"
+
+	// AgentConfigSourceElasticsearch reads agent configuration directly
+	// from the .apm-agent-configuration index in Elasticsearch.
+	AgentConfigSourceElasticsearch = "elasticsearch"
 )
 
 type KibanaConfig struct {
 	Enabled             bool   `config:"enabled"`
 	APIKey              string `config:"api_key"`
 	kibana.ClientConfig `config:",inline"`
+
+	// Proxy holds the proxy configuration for requests to Kibana.
+	// Unlike other destinations, Kibana is connected to directly
+	// unless a proxy URL is configured.
+	Proxy httpproxy.Config `config:",inline"`
 }
 
 func (k *KibanaConfig) Unpack(cfg *common.Config) error {
@@ -64,32 +80,74 @@ func defaultKibanaConfig() KibanaConfig {
 
 // Config holds configuration information nested under the key `apm-server`
 type Config struct {
-	Host                      string                  `config:"host"`
-	MaxHeaderSize             int                     `config:"max_header_size"`
-	IdleTimeout               time.Duration           `config:"idle_timeout"`
-	ReadTimeout               time.Duration           `config:"read_timeout"`
-	WriteTimeout              time.Duration           `config:"write_timeout"`
-	MaxEventSize              int                     `config:"max_event_size"`
-	ShutdownTimeout           time.Duration           `config:"shutdown_timeout"`
-	TLS                       *tlscommon.ServerConfig `config:"ssl"`
-	MaxConnections            int                     `config:"max_connections"`
-	ResponseHeaders           map[string][]string     `config:"response_headers"`
-	Expvar                    *ExpvarConfig           `config:"expvar"`
-	Pprof                     *PprofConfig            `config:"pprof"`
-	AugmentEnabled            bool                    `config:"capture_personal_data"`
-	SelfInstrumentation       *InstrumentationConfig  `config:"instrumentation"`
-	RumConfig                 *RumConfig              `config:"rum"`
-	Register                  *RegisterConfig         `config:"register"`
-	Mode                      Mode                    `config:"mode"`
-	Kibana                    KibanaConfig            `config:"kibana"`
-	AgentConfig               *AgentConfig            `config:"agent.config"`
-	SecretToken               string                  `config:"secret_token"`
-	APIKeyConfig              *APIKeyConfig           `config:"api_key"`
-	JaegerConfig              JaegerConfig            `config:"jaeger"`
-	Aggregation               AggregationConfig       `config:"aggregation"`
-	Sampling                  SamplingConfig          `config:"sampling"`
-	DataStreams               DataStreamsConfig       `config:"data_streams"`
-	DefaultServiceEnvironment string                  `config:"default_service_environment"`
+	Host                      string                    `config:"host"`
+	MaxHeaderSize             int                       `config:"max_header_size"`
+	IdleTimeout               time.Duration             `config:"idle_timeout"`
+	ReadTimeout               time.Duration             `config:"read_timeout"`
+	WriteTimeout              time.Duration             `config:"write_timeout"`
+	MaxEventSize              int                       `config:"max_event_size"`
+	ShutdownTimeout           time.Duration             `config:"shutdown_timeout"`
+	TLS                       *tlscommon.ServerConfig   `config:"ssl"`
+	MaxConnections            int                       `config:"max_connections"`
+	ResponseHeaders           map[string][]string       `config:"response_headers"`
+	Expvar                    *ExpvarConfig             `config:"expvar"`
+	Pprof                     *PprofConfig              `config:"pprof"`
+	AugmentEnabled            bool                      `config:"capture_personal_data"`
+	SelfInstrumentation       *InstrumentationConfig    `config:"instrumentation"`
+	RumConfig                 *RumConfig                `config:"rum"`
+	Register                  *RegisterConfig           `config:"register"`
+	Mode                      Mode                      `config:"mode"`
+	Kibana                    KibanaConfig              `config:"kibana"`
+	AgentConfig               *AgentConfig              `config:"agent.config"`
+	SecretToken               string                    `config:"secret_token"`
+	APIKeyConfig              *APIKeyConfig             `config:"api_key"`
+	JaegerConfig              JaegerConfig              `config:"jaeger"`
+	Zipkin                    ZipkinConfig              `config:"zipkin"`
+	Aggregation               AggregationConfig         `config:"aggregation"`
+	Sampling                  SamplingConfig            `config:"sampling"`
+	DataStreams               DataStreamsConfig         `config:"data_streams"`
+	DefaultServiceEnvironment string                    `config:"default_service_environment"`
+	OTel                      OTelConfig                `config:"otel"`
+	Preflight                 PreflightConfig           `config:"preflight"`
+	Warmup                    WarmupConfig              `config:"warmup"`
+	SLO                       SLOConfig                 `config:"slo"`
+	Synthetics                SyntheticsConfig          `config:"synthetics"`
+	Forward                   ForwardConfig             `config:"forward"`
+	LoadSheddingReport        LoadSheddingReportConfig  `config:"load_shedding_report"`
+	FastValidation            FastValidationConfig      `config:"fast_validation"`
+	ACME                      ACMEConfig                `config:"acme"`
+	ResponseCompression       ResponseCompressionConfig `config:"response_compression"`
+	EventQueues               EventQueuesConfig         `config:"event_queues"`
+	Journal                   JournalConfig             `config:"journal"`
+	FeatureFlags              FeatureFlagsConfig        `config:"feature_flags"`
+	AuthBypass                []AuthBypassRule          `config:"auth_bypass"`
+	TemplateUpgrade           TemplateUpgradeConfig     `config:"template_upgrade"`
+	PipelineCheck             PipelineCheckConfig       `config:"pipeline_check"`
+	Expiry                    ExpiryConfig              `config:"expiry"`
+	PhaseTimings              PhaseTimingsConfig        `config:"phase_timings"`
+	ProxyProtocol             ProxyProtocolConfig       `config:"proxy_protocol"`
+	IndexRouting              IndexRoutingConfig        `config:"index_routing"`
+	DecodeLimits              DecodeLimitsConfig        `config:"decode_limits"`
+	UsageReport               UsageReportConfig         `config:"usage_report"`
+	RoutingHint               RoutingHintConfig         `config:"routing_hint"`
+	GRPC                      GRPCConfig                `config:"grpc"`
+	Lookup                    LookupConfig              `config:"lookup"`
+	AlertWebhook              AlertWebhookConfig        `config:"alert_webhook"`
+	Auth                      AuthConfig                `config:"auth"`
+	TransactionResult         TransactionResultConfig   `config:"transaction_result"`
+	InternalDocuments         InternalDocumentsConfig   `config:"internal_documents"`
+	StacktraceDedup           StacktraceDedupConfig     `config:"stacktrace_dedup"`
+	KeywordTruncation         KeywordTruncationConfig   `config:"keyword_truncation"`
+	IntakeTelemetry           IntakeTelemetryConfig     `config:"intake_telemetry"`
+	SideLookups               SideLookupsConfig         `config:"side_lookups"`
+	PartitionKey              PartitionKeyConfig        `config:"partition_key"`
+	MissingMetadata           MissingMetadataConfig     `config:"missing_metadata"`
+	GeoIP                     GeoIPConfig               `config:"geoip"`
+	WellKnown                 WellKnownConfig           `config:"well_known"`
+	Pseudonymization          PseudonymizationConfig    `config:"pseudonymization"`
+	IndexMetadata             IndexMetadataConfig       `config:"index_metadata"`
+	PanicBreaker              PanicBreakerConfig        `config:"panic_breaker"`
+	AckLevel                  AckLevelConfig            `config:"ack_level"`
 
 	Pipeline string
 }
@@ -111,6 +169,171 @@ type PprofConfig struct {
 // AgentConfig holds remote agent config information
 type AgentConfig struct {
 	Cache *Cache `config:"cache"`
+
+	// MaxStale holds the maximum duration for which the last known
+	// agent configuration is returned when fetching from Kibana fails.
+	// If zero, fetching errors are returned instead.
+	MaxStale time.Duration `config:"max_stale" validate:"min=0"`
+
+	// NegativeCache holds configuration for caching queries for which
+	// Kibana holds no agent configuration.
+	NegativeCache NegativeCacheConfig `config:"negative_cache"`
+
+	// Custom holds size limits for custom settings, which are returned
+	// only to agents requesting their namespace.
+	Custom CustomSettingsConfig `config:"custom"`
+
+	// Encryption holds the keys for decrypting sensitive settings,
+	// which are stored encrypted.
+	Encryption AgentConfigEncryptionConfig `config:"encryption"`
+
+	// Source holds the primary source of agent configuration: either
+	// AgentConfigSourceKibana or AgentConfigSourceElasticsearch.
+	Source string `config:"source"`
+
+	// ElasticsearchFallback controls whether agent configuration is read
+	// from Elasticsearch when fetching from Kibana fails, or when Kibana
+	// is not configured.
+	ElasticsearchFallback bool `config:"elasticsearch_fallback"`
+
+	// ESConfig holds the Elasticsearch connection settings used for reading
+	// agent configuration. If unspecified, the hosts and TLS settings of
+	// output.elasticsearch are used with the apm-server.kibana credentials.
+	ESConfig *elasticsearch.Config `config:"elasticsearch"`
+
+	esConfigured bool
+}
+
+// ElasticsearchEnabled returns whether agent configuration may be read
+// directly from Elasticsearch, either as the primary source or as a fallback.
+func (c *AgentConfig) ElasticsearchEnabled() bool {
+	return c != nil && (c.Source == AgentConfigSourceElasticsearch || c.ElasticsearchFallback)
+}
+
+func (c *AgentConfig) Unpack(in *common.Config) error {
+	type agentConfig AgentConfig
+	cfg := agentConfig(*defaultAgentConfig())
+	if err := in.Unpack(&cfg); err != nil {
+		return errors.Wrap(err, "error unpacking agent config")
+	}
+	*c = AgentConfig(cfg)
+	c.esConfigured = in.HasField("elasticsearch")
+	if negativeCache, err := in.Child("negative_cache", -1); err != nil || !negativeCache.HasField("expiration") {
+		// The negative cache expiration defaults to the cache expiration.
+		c.NegativeCache.Expiration = c.Cache.Expiration
+	}
+	return errors.Wrap(c.Validate(), "invalid agent config")
+}
+
+func (c *AgentConfig) Validate() error {
+	switch c.Source {
+	case AgentConfigSourceKibana, AgentConfigSourceElasticsearch:
+	default:
+		return errors.Errorf("invalid source %q, expected %q or %q",
+			c.Source, AgentConfigSourceKibana, AgentConfigSourceElasticsearch)
+	}
+	if c.Cache != nil && c.NegativeCache.Expiration > c.Cache.Expiration {
+		return errors.Errorf("negative_cache.expiration (%s) must not exceed cache.expiration (%s)",
+			c.NegativeCache.Expiration, c.Cache.Expiration)
+	}
+	return nil
+}
+
+// setup falls back to the hosts and TLS settings of output.elasticsearch when
+// agent.config.elasticsearch is not specified. Agent configuration is owned by
+// Kibana, so the output credentials are replaced with those configured for
+// apm-server.kibana.
+func (c *AgentConfig) setup(log *logp.Logger, kibanaCfg KibanaConfig, outputESCfg *common.Config) error {
+	if !c.ElasticsearchEnabled() || c.esConfigured || outputESCfg == nil {
+		return nil
+	}
+	log.Info("Falling back to elasticsearch output for agent configuration")
+	if err := outputESCfg.Unpack(c.ESConfig); err != nil {
+		return errors.Wrap(err, "error unpacking output.elasticsearch config for agent configuration")
+	}
+	c.ESConfig.Username = kibanaCfg.Username
+	c.ESConfig.Password = kibanaCfg.Password
+	c.ESConfig.APIKey = kibanaCfg.APIKey
+	if c.ESConfig.Username == "" && c.ESConfig.APIKey == "" {
+		log.Warn("No apm-server.kibana credentials configured, agent configuration will be read from Elasticsearch without credentials")
+	}
+	return nil
+}
+
+// AgentConfigEncryptionConfig holds configuration for decrypting
+// sensitive agent configuration settings.
+type AgentConfigEncryptionConfig struct {
+	// Keys holds base64-encoded AES keys of 16, 24, or 32 bytes.
+	// The first key is used for encrypting values; all keys are
+	// tried for decrypting values, to allow rotating keys.
+	Keys []string `config:"keys"`
+}
+
+func (c *AgentConfigEncryptionConfig) Validate() error {
+	for i, key := range c.Keys {
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return errors.Wrapf(err, "invalid encryption key %d", i)
+		}
+		switch len(decoded) {
+		case 16, 24, 32:
+		default:
+			return errors.Errorf("invalid encryption key %d: must be 16, 24, or 32 bytes, got %d", i, len(decoded))
+		}
+	}
+	return nil
+}
+
+type CustomSettingsConfig struct {
+	// MaxValueSize holds the maximum size of a custom setting's value.
+	// Custom settings with larger values are not returned to agents.
+	MaxValueSize cfgtype.ByteSize `config:"max_value_size"`
+
+	// MaxTotalSize holds the maximum total size of the custom setting
+	// keys and values returned in a single response. Custom settings are
+	// added in order of their keys until the limit is reached.
+	MaxTotalSize cfgtype.ByteSize `config:"max_total_size"`
+}
+
+func (c *CustomSettingsConfig) Validate() error {
+	if c.MaxValueSize <= 0 {
+		return errors.New("max_value_size must be positive")
+	}
+	if c.MaxTotalSize <= 0 {
+		return errors.New("max_total_size must be positive")
+	}
+	return nil
+}
+
+// NegativeCacheConfig holds configuration for caching "no configuration found"
+// results separately from other agent configuration results.
+type NegativeCacheConfig struct {
+	// Expiration holds the duration for which a "no configuration found"
+	// result is cached. If zero, such results are cached like any other.
+	// Expiration defaults to, and must not exceed, the cache expiration.
+	Expiration time.Duration `config:"expiration" validate:"min=0"`
+
+	// MaxEntries holds the maximum number of service name and environment
+	// combinations held in the negative cache. When the negative cache is
+	// full, further "no configuration found" results are cached like any other.
+	MaxEntries int `config:"max_entries" validate:"min=1"`
+}
+
+func defaultAgentConfig() *AgentConfig {
+	return &AgentConfig{
+		Cache:    &Cache{Expiration: 30 * time.Second},
+		MaxStale: 24 * time.Hour,
+		NegativeCache: NegativeCacheConfig{
+			Expiration: 30 * time.Second,
+			MaxEntries: 10000,
+		},
+		Custom: CustomSettingsConfig{
+			MaxValueSize: 1024,
+			MaxTotalSize: 16 * 1024,
+		},
+		Source:   AgentConfigSourceKibana,
+		ESConfig: elasticsearch.DefaultConfig(),
+	}
 }
 
 // Cache holds config information about cache expiration
@@ -130,6 +353,10 @@ func NewConfig(ucfg *common.Config, outputESCfg *common.Config) (*Config, error)
 		return nil, errors.New(msgInvalidConfigAgentCfg)
 	}
 
+	if err := c.AgentConfig.setup(logger, c.Kibana, outputESCfg); err != nil {
+		return nil, err
+	}
+
 	if err := c.RumConfig.setup(logger, c.DataStreams.Enabled, outputESCfg); err != nil {
 		return nil, err
 	}
@@ -165,6 +392,18 @@ func NewConfig(ucfg *common.Config, outputESCfg *common.Config) (*Config, error)
 		)
 	}
 
+	if c.ACME.Enabled && c.TLS.IsEnabled() {
+		return nil, errors.New("apm-server.acme and apm-server.ssl cannot both be enabled")
+	}
+
+	if c.FastValidation.Enabled && c.SecretToken == "" && !c.APIKeyConfig.IsEnabled() {
+		logger.Warn("" +
+			"apm-server.fast_validation.enabled is true, but neither " +
+			"apm-server.secret_token nor apm-server.api_key are configured; " +
+			"all events will be validated",
+		)
+	}
+
 	if c.DataStreams.Enabled || (outputESCfg != nil && (outputESCfg.HasField("pipeline") || outputESCfg.HasField("pipelines"))) {
 		c.Pipeline = ""
 	}
@@ -197,17 +436,57 @@ func DefaultConfig() *Config {
 			Enabled: new(bool),
 			URL:     "/debug/vars",
 		},
-		Pprof:        &PprofConfig{Enabled: false},
-		RumConfig:    defaultRum(),
-		Register:     defaultRegisterConfig(true),
-		Mode:         ModeProduction,
-		Kibana:       defaultKibanaConfig(),
-		AgentConfig:  &AgentConfig{Cache: &Cache{Expiration: 30 * time.Second}},
-		Pipeline:     defaultAPMPipeline,
-		APIKeyConfig: defaultAPIKeyConfig(),
-		JaegerConfig: defaultJaeger(),
-		Aggregation:  defaultAggregationConfig(),
-		Sampling:     defaultSamplingConfig(),
-		DataStreams:  defaultDataStreamsConfig(),
+		Pprof:               &PprofConfig{Enabled: false},
+		RumConfig:           defaultRum(),
+		Register:            defaultRegisterConfig(true),
+		Mode:                ModeProduction,
+		Kibana:              defaultKibanaConfig(),
+		AgentConfig:         defaultAgentConfig(),
+		Pipeline:            defaultAPMPipeline,
+		APIKeyConfig:        defaultAPIKeyConfig(),
+		JaegerConfig:        defaultJaeger(),
+		Zipkin:              defaultZipkinConfig(),
+		Aggregation:         defaultAggregationConfig(),
+		Sampling:            defaultSamplingConfig(),
+		DataStreams:         defaultDataStreamsConfig(),
+		OTel:                defaultOTelConfig(),
+		Preflight:           defaultPreflightConfig(),
+		Warmup:              defaultWarmupConfig(),
+		SLO:                 defaultSLOConfig(),
+		Synthetics:          defaultSyntheticsConfig(),
+		Forward:             defaultForwardConfig(),
+		LoadSheddingReport:  defaultLoadSheddingReportConfig(),
+		FastValidation:      defaultFastValidationConfig(),
+		ACME:                defaultACMEConfig(),
+		ResponseCompression: defaultResponseCompressionConfig(),
+		EventQueues:         defaultEventQueuesConfig(),
+		Journal:             defaultJournalConfig(),
+		TemplateUpgrade:     defaultTemplateUpgradeConfig(),
+		PipelineCheck:       defaultPipelineCheckConfig(),
+		Expiry:              defaultExpiryConfig(),
+		PhaseTimings:        defaultPhaseTimingsConfig(),
+		ProxyProtocol:       defaultProxyProtocolConfig(),
+		IndexRouting:        defaultIndexRoutingConfig(),
+		DecodeLimits:        defaultDecodeLimitsConfig(),
+		UsageReport:         defaultUsageReportConfig(),
+		RoutingHint:         defaultRoutingHintConfig(),
+		GRPC:                defaultGRPCConfig(),
+		Lookup:              defaultLookupConfig(),
+		AlertWebhook:        defaultAlertWebhookConfig(),
+		Auth:                defaultAuthConfig(),
+		TransactionResult:   defaultTransactionResultConfig(),
+		InternalDocuments:   defaultInternalDocumentsConfig(),
+		StacktraceDedup:     defaultStacktraceDedupConfig(),
+		KeywordTruncation:   defaultKeywordTruncationConfig(),
+		IntakeTelemetry:     defaultIntakeTelemetryConfig(),
+		SideLookups:         defaultSideLookupsConfig(),
+		PartitionKey:        defaultPartitionKeyConfig(),
+		MissingMetadata:     defaultMissingMetadataConfig(),
+		GeoIP:               defaultGeoIPConfig(),
+		WellKnown:           defaultWellKnownConfig(),
+		Pseudonymization:    defaultPseudonymizationConfig(),
+		IndexMetadata:       defaultIndexMetadataConfig(),
+		PanicBreaker:        defaultPanicBreakerConfig(),
+		AckLevel:            defaultAckLevelConfig(),
 	}
 }
@@ -31,6 +31,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/common/transport/tlscommon"
 
 	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/httpproxy"
 )
 
 var testdataCertificateConfig = tlscommon.CertificateConfig{
@@ -49,6 +50,14 @@ func TestUnpackConfig(t *testing.T) {
 	kibanaHeadersConfig.Kibana.Enabled = true
 	kibanaHeadersConfig.Kibana.Headers = map[string]string{"foo": "bar"}
 
+	kibanaProxyConfig := DefaultConfig()
+	kibanaProxyConfig.Kibana.Enabled = true
+	kibanaProxyConfig.Kibana.Proxy = httpproxy.Config{URL: "socks5://proxy:1080", NoProxy: []string{"kibana.internal"}}
+
+	headSamplingPolicy := HeadSamplingPolicy{SampleRate: 0.1, MaxTracesPerSecond: 10}
+	headSamplingPolicy.Service.Name = "opbeans"
+	headSamplingPolicy.Trace.Name = "GET /"
+
 	responseHeadersConfig := DefaultConfig()
 	responseHeadersConfig.ResponseHeaders = map[string][]string{
 		"k1": []string{"v1"},
@@ -107,9 +116,27 @@ func TestUnpackConfig(t *testing.T) {
 						},
 						"index_pattern":       "apm-test*",
 						"elasticsearch.hosts": []string{"localhost:9201", "localhost:9202"},
+						"external": []map[string]interface{}{{
+							"service.name":    "opbeans-rum",
+							"service.version": "1.0.0",
+							"url":             "s3://sourcemaps/{service.version}/{bundle_filepath}.map",
+							"headers":         map[string]interface{}{"Authorization": "Bearer abc"},
+							"max_size":        "1MiB",
+						}},
+						"version_selection": "upload_time",
 					},
 					"library_pattern":       "^custom",
 					"exclude_from_grouping": "^grouping",
+					"bot_traffic": map[string]interface{}{
+						"enabled":            true,
+						"action":             "drop",
+						"user_agent_pattern": "(?i)crawler",
+					},
+					"error_sampling": map[string]interface{}{
+						"enabled":     true,
+						"max_per_key": 5,
+						"interval":    "10s",
+					},
 				},
 				"register": map[string]interface{}{
 					"ingest": map[string]interface{}{
@@ -119,12 +146,22 @@ func TestUnpackConfig(t *testing.T) {
 						},
 					},
 				},
-				"kibana":                        map[string]interface{}{"enabled": "true"},
-				"agent.config.cache.expiration": "2m",
-				"jaeger.grpc.enabled":           true,
-				"jaeger.grpc.host":              "localhost:12345",
-				"jaeger.http.enabled":           true,
-				"jaeger.http.host":              "localhost:6789",
+				"kibana":                                  map[string]interface{}{"enabled": "true"},
+				"agent.config.cache.expiration":           "2m",
+				"agent.config.max_stale":                  "1h",
+				"agent.config.negative_cache.expiration":  "1m",
+				"agent.config.negative_cache.max_entries": 100,
+				"agent.config.custom.max_value_size":      "2KiB",
+				"agent.config.custom.max_total_size":      "8KiB",
+				"agent.config.encryption.keys":            []string{"MTExMTExMTExMTExMTExMQ=="},
+				"agent.config.source":                     "elasticsearch",
+				"agent.config.elasticsearch.hosts":        []string{"localhost:9203"},
+				"jaeger.grpc.enabled":                     true,
+				"jaeger.grpc.host":                        "localhost:12345",
+				"jaeger.http.enabled":                     true,
+				"jaeger.http.host":                        "localhost:6789",
+				"jaeger.http.max_request_size":            1024,
+				"zipkin.max_request_size":                 2048,
 				"api_key": map[string]interface{}{
 					"enabled":             true,
 					"limit":               200,
@@ -140,8 +177,264 @@ func TestUnpackConfig(t *testing.T) {
 					"service_destinations": map[string]interface{}{
 						"max_groups": 456,
 					},
+					"transaction_marks": map[string]interface{}{
+						"enabled":  true,
+						"interval": "30s",
+					},
+					"errors": map[string]interface{}{
+						"enabled":    true,
+						"max_groups": 789,
+					},
+					"breakdown": map[string]interface{}{
+						"enabled":           true,
+						"max_pending_spans": 1000,
+						"agents":            []string{"opentelemetry/go"},
+					},
+				},
+				"sampling.head": map[string]interface{}{
+					"enabled": true,
+					"policies": []map[string]interface{}{{
+						"service.name":          "opbeans",
+						"trace.name":            "GET /",
+						"sample_rate":           0.1,
+						"max_traces_per_second": 10,
+					}},
 				},
 				"default_service_environment": "overridden",
+				"otel": map[string]interface{}{
+					"export": map[string]interface{}{
+						"enabled":     true,
+						"endpoint":    "collector:4317",
+						"insecure":    true,
+						"headers":     map[string]interface{}{"authorization": "Bearer abc"},
+						"compression": "gzip",
+						"timeout":     "10s",
+					},
+					"logs.min_severity": "warn",
+				},
+				"preflight": map[string]interface{}{
+					"enabled": true,
+					"strict":  true,
+					"timeout": "5s",
+				},
+				"warmup": map[string]interface{}{
+					"enabled":         true,
+					"wait_for_output": false,
+					"services_path":   "/var/lib/apm-server/services.json",
+				},
+				"slo": map[string]interface{}{
+					"enabled":  true,
+					"interval": "30s",
+					"services": []map[string]interface{}{{
+						"name":              "opbeans",
+						"environment":       "production",
+						"max_failure_rate":  0.05,
+						"latency.threshold": "500ms",
+					}, {
+						"name":               "opbeans-rum",
+						"latency.threshold":  "2s",
+						"latency.percentile": 99,
+					}},
+				},
+				"synthetics": map[string]interface{}{
+					"enabled": true,
+					"monitors": []map[string]interface{}{{
+						"url":          "http://opbeans:3000/healthz",
+						"service.name": "opbeans",
+						"interval":     "1m",
+					}, {
+						"url":                 "http://opbeans:3000/",
+						"service.name":        "opbeans",
+						"service.environment": "production",
+						"timeout":             "5s",
+						"expected_status":     204,
+					}},
+				},
+				"forward": map[string]interface{}{
+					"enabled":           true,
+					"url":               "https://central:8200",
+					"api_key":           "abc123",
+					"compression_level": 9,
+					"timeout":           "5s",
+					"max_retries":       5,
+					"backoff.init":      "2s",
+					"backoff.max":       "1m",
+				},
+				"load_shedding_report": map[string]interface{}{
+					"enabled":      true,
+					"window":       "1h",
+					"max_services": 100,
+				},
+				"fast_validation": map[string]interface{}{
+					"enabled": true,
+					"min_agent_versions": map[string]interface{}{
+						"go": "1.11.0",
+					},
+				},
+				"acme": map[string]interface{}{
+					"enabled":             false,
+					"accept_tos":          true,
+					"domains":             []string{"apm.example.com"},
+					"email":               "admin@example.com",
+					"cache_dir":           "certs",
+					"directory_url":       "https://acme.example.com/directory",
+					"http_challenge_host": ":80",
+					"renew_before":        "168h",
+				},
+				"response_compression": map[string]interface{}{
+					"enabled":  false,
+					"min_size": 4096,
+				},
+				"event_queues": map[string]interface{}{
+					"enabled":                 true,
+					"traces.size":             64,
+					"errors.flush.min_events": 10,
+					"errors.flush.timeout":    "100ms",
+				},
+				"journal": map[string]interface{}{
+					"enabled":  true,
+					"path":     "/var/lib/apm-server/journal",
+					"max_size": "100MiB",
+					"max_age":  "1h",
+				},
+				"feature_flags": map[string]interface{}{
+					"slo_evaluation": false,
+				},
+				"auth_bypass": []map[string]interface{}{{
+					"paths":            []string{"/"},
+					"source_addresses": []string{"127.0.0.1", "::1"},
+				}},
+				"template_upgrade.enabled": true,
+				"pipeline_check": map[string]interface{}{
+					"fallback": true,
+					"timeout":  "1s",
+				},
+				"expiry": map[string]interface{}{
+					"enabled": true,
+					"ttl":     map[string]interface{}{"span": "168h"},
+				},
+				"phase_timings": map[string]interface{}{
+					"enabled": true,
+					"log":     true,
+				},
+				"proxy_protocol": map[string]interface{}{
+					"enabled":         true,
+					"trusted_proxies": []string{"10.0.0.0/8"},
+				},
+				"index_routing": map[string]interface{}{
+					"enabled":       true,
+					"path":          "/etc/apm-server/routing.yml",
+					"reload.period": "1m",
+				},
+				"decode_limits": map[string]interface{}{
+					"enabled":                 true,
+					"max_concurrent_decoders": 8,
+					"max_in_flight_events":    1000,
+				},
+				"usage_report": map[string]interface{}{
+					"enabled":  true,
+					"interval": "5m",
+				},
+				"routing_hint": map[string]interface{}{
+					"enabled": true,
+					"cookie":  "apm_route",
+				},
+				"grpc.reflection": true,
+				"lookup": map[string]interface{}{
+					"enabled":       true,
+					"path":          "/etc/apm-server/owners.json",
+					"field":         "labels.team",
+					"target_field":  "organization",
+					"reload.period": "1m",
+				},
+				"alert_webhook": map[string]interface{}{
+					"enabled":        true,
+					"url":            "https://hooks.example.com/apm",
+					"headers":        map[string]interface{}{"authorization": "Bearer abc"},
+					"max_per_minute": 10,
+					"rules": []map[string]interface{}{{
+						"name":    "checkout errors",
+						"event":   "error",
+						"service": "opbeans",
+						"labels":  map[string]interface{}{"team": "checkout"},
+					}, {
+						"name":         "slow transactions",
+						"event":        "transaction",
+						"min_duration": "5s",
+					}},
+				},
+				"auth": map[string]interface{}{
+					"anonymous": map[string]interface{}{
+						"enabled":       true,
+						"allow_agent":   []string{"rum-js"},
+						"allow_service": []string{"opbeans-rum"},
+						"rate_limit":    map[string]interface{}{"limit": 10},
+					},
+				},
+				"transaction_result": map[string]interface{}{
+					"enabled": true,
+					"grpc":    false,
+					"rules": []map[string]interface{}{{
+						"match":  "(?i)^success$",
+						"result": "success",
+					}},
+				},
+				"internal_documents": map[string]interface{}{
+					"enabled": true,
+					"index":   "apm-internal-%{+yyyy.MM.dd}",
+					"id":      "content_hash",
+				},
+				"stacktrace_dedup.enabled": true,
+				"keyword_truncation": map[string]interface{}{
+					"enabled":    true,
+					"max_length": 256,
+					"fields":     []string{"transaction.name", "labels.team"},
+				},
+				"intake_telemetry": map[string]interface{}{
+					"enabled":    true,
+					"max_agents": 50,
+					"window":     "1h",
+				},
+				"side_lookups": map[string]interface{}{
+					"enabled":                  true,
+					"workers":                  4,
+					"timeout":                  "500ms",
+					"circuit_breaker.failures": 0,
+				},
+				"partition_key": map[string]interface{}{
+					"enabled":  true,
+					"fallback": "",
+				},
+				"missing_metadata": map[string]interface{}{
+					"action":               "synthesize",
+					"default_service_name": "sensor",
+				},
+				"geoip": map[string]interface{}{
+					"enabled":       true,
+					"database":      "/etc/apm-server/GeoIP2-City.mmdb",
+					"reload.period": "1h",
+				},
+				"well_known.enabled":     true,
+				"index_metadata.enabled": true,
+				"ack_level": map[string]interface{}{
+					"rum":                    []string{"validate", "enqueue"},
+					"max_background_batches": 10,
+					"timeout":                "5s",
+				},
+				"panic_breaker": map[string]interface{}{
+					"enabled":   true,
+					"threshold": 3,
+					"window":    "30s",
+					"cooldown":  "5m",
+				},
+				"pseudonymization": map[string]interface{}{
+					"enabled": true,
+					"salt":    "secret",
+					"fields": []map[string]interface{}{
+						{"field": "user.id"},
+						{"field": "client.ip", "method": "truncate", "ipv4_prefix": 16},
+					},
+				},
 			},
 			outCfg: &Config{
 				Host:            "localhost:3000",
@@ -185,10 +478,32 @@ func TestUnpackConfig(t *testing.T) {
 							MaxRetries: 3,
 							Backoff:    elasticsearch.DefaultBackoffConfig,
 						},
-						esConfigured: true,
+						External: []ExternalSourcemapConfig{{
+							ServiceName:    "opbeans-rum",
+							ServiceVersion: "1.0.0",
+							URL:            "s3://sourcemaps/{service.version}/{bundle_filepath}.map",
+							Headers:        map[string]string{"Authorization": "Bearer abc"},
+							Timeout:        10 * time.Second,
+							MaxSize:        1024 * 1024,
+
+							MaxConcurrentRequests: 10,
+						}},
+						esConfigured:     true,
+						VersionSelection: "upload_time",
 					},
 					LibraryPattern:      "^custom",
 					ExcludeFromGrouping: "^grouping",
+					BotTraffic: BotTrafficConfig{
+						Enabled:          true,
+						Action:           "drop",
+						UserAgentPattern: "(?i)crawler",
+					},
+					ErrorSampling: ErrorSamplingConfig{
+						Enabled:   true,
+						MaxPerKey: 5,
+						Interval:  10 * time.Second,
+						MaxGroups: 10000,
+					},
 				},
 				Register: &RegisterConfig{
 					Ingest: &IngestConfig{
@@ -203,8 +518,23 @@ func TestUnpackConfig(t *testing.T) {
 					Enabled:      true,
 					ClientConfig: defaultKibanaConfig().ClientConfig,
 				},
-				AgentConfig: &AgentConfig{Cache: &Cache{Expiration: 2 * time.Minute}},
-				Pipeline:    defaultAPMPipeline,
+				AgentConfig: &AgentConfig{
+					Cache:         &Cache{Expiration: 2 * time.Minute},
+					MaxStale:      time.Hour,
+					NegativeCache: NegativeCacheConfig{Expiration: time.Minute, MaxEntries: 100},
+					Custom:        CustomSettingsConfig{MaxValueSize: 2 * 1024, MaxTotalSize: 8 * 1024},
+					Encryption:    AgentConfigEncryptionConfig{Keys: []string{"MTExMTExMTExMTExMTExMQ=="}},
+					Source:        AgentConfigSourceElasticsearch,
+					ESConfig: &elasticsearch.Config{
+						Hosts:      elasticsearch.Hosts{"localhost:9203"},
+						Protocol:   "http",
+						Timeout:    5 * time.Second,
+						MaxRetries: 3,
+						Backoff:    elasticsearch.DefaultBackoffConfig,
+					},
+					esConfigured: true,
+				},
+				Pipeline: defaultAPMPipeline,
 				JaegerConfig: JaegerConfig{
 					GRPC: JaegerGRPCConfig{
 						Enabled: true,
@@ -220,8 +550,9 @@ func TestUnpackConfig(t *testing.T) {
 						}(),
 					},
 					HTTP: JaegerHTTPConfig{
-						Enabled: true,
-						Host:    "localhost:6789",
+						Enabled:        true,
+						Host:           "localhost:6789",
+						MaxRequestSize: 1024,
 					},
 				},
 				APIKeyConfig: &APIKeyConfig{
@@ -248,6 +579,23 @@ func TestUnpackConfig(t *testing.T) {
 						Interval:  time.Minute,
 						MaxGroups: 456,
 					},
+					TransactionMarks: TransactionMarksAggregationConfig{
+						Enabled:   true,
+						Interval:  30 * time.Second,
+						MaxGroups: 10000,
+					},
+					Errors: ErrorAggregationConfig{
+						Enabled:   true,
+						Interval:  time.Minute,
+						MaxGroups: 789,
+					},
+					Breakdown: BreakdownAggregationConfig{
+						Enabled:         true,
+						Interval:        time.Minute,
+						MaxGroups:       10000,
+						MaxPendingSpans: 1000,
+						Agents:          []string{"opentelemetry/go"},
+					},
 				},
 				Sampling: SamplingConfig{
 					KeepUnsampled: true,
@@ -260,8 +608,250 @@ func TestUnpackConfig(t *testing.T) {
 						StorageGCInterval:     5 * time.Minute,
 						TTL:                   30 * time.Minute,
 					},
+					Head: HeadSamplingConfig{
+						Enabled:  true,
+						Policies: []HeadSamplingPolicy{headSamplingPolicy},
+					},
 				},
 				DefaultServiceEnvironment: "overridden",
+				OTel: OTelConfig{
+					Export: OTelExportConfig{
+						Enabled:     true,
+						Endpoint:    "collector:4317",
+						Insecure:    true,
+						Headers:     map[string]string{"authorization": "Bearer abc"},
+						Compression: "gzip",
+						Timeout:     10 * time.Second,
+					},
+					Logs: OTelLogsConfig{MinSeverity: "warn"},
+				},
+				Preflight: PreflightConfig{
+					Enabled: true,
+					Strict:  true,
+					Timeout: 5 * time.Second,
+				},
+				Warmup: WarmupConfig{
+					Enabled:       true,
+					Timeout:       30 * time.Second,
+					WaitForOutput: false,
+					ServicesPath:  "/var/lib/apm-server/services.json",
+					MaxServices:   1000,
+				},
+				SLO: SLOConfig{
+					Enabled:  true,
+					Interval: 30 * time.Second,
+					Services: []SLOServiceConfig{{
+						Name:           "opbeans",
+						Environment:    "production",
+						MaxFailureRate: 0.05,
+						Latency:        SLOLatencyConfig{Threshold: 500 * time.Millisecond, Percentile: 95},
+					}, {
+						Name:    "opbeans-rum",
+						Latency: SLOLatencyConfig{Threshold: 2 * time.Second, Percentile: 99},
+					}},
+				},
+				Synthetics: SyntheticsConfig{
+					Enabled: true,
+					Monitors: []SyntheticMonitorConfig{{
+						URL:            "http://opbeans:3000/healthz",
+						Service:        SyntheticMonitorServiceConfig{Name: "opbeans"},
+						Interval:       time.Minute,
+						Timeout:        10 * time.Second,
+						ExpectedStatus: 200,
+					}, {
+						URL:            "http://opbeans:3000/",
+						Service:        SyntheticMonitorServiceConfig{Name: "opbeans", Environment: "production"},
+						Interval:       30 * time.Second,
+						Timeout:        5 * time.Second,
+						ExpectedStatus: 204,
+					}},
+				},
+				Forward: ForwardConfig{
+					Enabled:          true,
+					URL:              "https://central:8200",
+					APIKey:           "abc123",
+					CompressionLevel: 9,
+					Timeout:          5 * time.Second,
+					MaxRetries:       5,
+					Backoff:          ForwardBackoffConfig{Init: 2 * time.Second, Max: time.Minute},
+				},
+				LoadSheddingReport: LoadSheddingReportConfig{
+					Enabled:     true,
+					Window:      time.Hour,
+					MaxServices: 100,
+				},
+				FastValidation: FastValidationConfig{
+					Enabled:          true,
+					MinAgentVersions: map[string]string{"go": "1.11.0"},
+				},
+				ACME: ACMEConfig{
+					Enabled:           false,
+					AcceptTOS:         true,
+					Domains:           []string{"apm.example.com"},
+					Email:             "admin@example.com",
+					CacheDir:          "certs",
+					DirectoryURL:      "https://acme.example.com/directory",
+					HTTPChallengeHost: ":80",
+					RenewBefore:       168 * time.Hour,
+				},
+				ResponseCompression: ResponseCompressionConfig{
+					Enabled: false,
+					MinSize: 4096,
+				},
+				EventQueues: EventQueuesConfig{
+					Enabled: true,
+					Traces: EventQueueConfig{
+						Size:  64,
+						Flush: EventQueueFlushConfig{Timeout: time.Second},
+					},
+					Errors: EventQueueConfig{
+						Flush: EventQueueFlushConfig{MinEvents: 10, Timeout: 100 * time.Millisecond},
+					},
+					Metrics: EventQueueConfig{
+						Flush: EventQueueFlushConfig{Timeout: time.Second},
+					},
+				},
+				Journal: JournalConfig{
+					Enabled: true,
+					Path:    "/var/lib/apm-server/journal",
+					MaxSize: 100 * 1024 * 1024,
+					MaxAge:  time.Hour,
+				},
+				FeatureFlags: FeatureFlagsConfig{"slo_evaluation": false},
+				AuthBypass: []AuthBypassRule{{
+					Paths:           []string{"/"},
+					SourceAddresses: []string{"127.0.0.1", "::1"},
+				}},
+				TemplateUpgrade: TemplateUpgradeConfig{Enabled: true},
+				PipelineCheck: PipelineCheckConfig{
+					Enabled:  true,
+					Fallback: true,
+					Timeout:  time.Second,
+				},
+				Expiry: ExpiryConfig{
+					Enabled: true,
+					TTL:     map[string]time.Duration{"span": 7 * 24 * time.Hour},
+				},
+				PhaseTimings: PhaseTimingsConfig{Enabled: true, Log: true},
+				ProxyProtocol: ProxyProtocolConfig{
+					Enabled:        true,
+					TrustedProxies: []string{"10.0.0.0/8"},
+					HeaderTimeout:  5 * time.Second,
+				},
+				IndexRouting: IndexRoutingConfig{
+					Enabled:      true,
+					Path:         "/etc/apm-server/routing.yml",
+					ReloadPeriod: time.Minute,
+				},
+				DecodeLimits: DecodeLimitsConfig{
+					Enabled:               true,
+					MaxConcurrentDecoders: 8,
+					DecoderTimeout:        time.Second,
+					MaxInFlightEvents:     1000,
+				},
+				UsageReport: UsageReportConfig{
+					Enabled:     true,
+					Interval:    5 * time.Minute,
+					MaxServices: 1000,
+				},
+				RoutingHint: RoutingHintConfig{
+					Enabled: true,
+					Header:  "Elastic-Apm-Routing-Hint",
+					Cookie:  "apm_route",
+				},
+				GRPC: GRPCConfig{Reflection: true},
+				Lookup: LookupConfig{
+					Enabled:      true,
+					Path:         "/etc/apm-server/owners.json",
+					Field:        "labels.team",
+					TargetField:  "organization",
+					ReloadPeriod: time.Minute,
+				},
+				AlertWebhook: AlertWebhookConfig{
+					Enabled:      true,
+					URL:          "https://hooks.example.com/apm",
+					Headers:      map[string]string{"authorization": "Bearer abc"},
+					Timeout:      5 * time.Second,
+					MaxPerMinute: 10,
+					Rules: []AlertWebhookRule{{
+						Name:    "checkout errors",
+						Event:   "error",
+						Service: "opbeans",
+						Labels:  map[string]string{"team": "checkout"},
+					}, {
+						Name:        "slow transactions",
+						Event:       "transaction",
+						MinDuration: 5 * time.Second,
+					}},
+				},
+				Auth: AuthConfig{
+					Anonymous: AnonymousAuthConfig{
+						Enabled:      true,
+						AllowAgent:   []string{"rum-js"},
+						AllowService: []string{"opbeans-rum"},
+						RateLimit:    EventRate{Limit: 10, LruSize: 1000},
+					},
+				},
+				TransactionResult: TransactionResultConfig{
+					Enabled: true,
+					HTTP:    true,
+					Rules:   []TransactionResultRule{{Match: "(?i)^success$", Result: "success"}},
+				},
+				InternalDocuments: InternalDocumentsConfig{
+					Enabled: true,
+					Index:   "apm-internal-%{+yyyy.MM.dd}",
+					ID:      "content_hash",
+				},
+				StacktraceDedup: StacktraceDedupConfig{Enabled: true},
+				KeywordTruncation: KeywordTruncationConfig{
+					Enabled:   true,
+					MaxLength: 256,
+					Fields:    []string{"transaction.name", "labels.team"},
+				},
+				IntakeTelemetry: IntakeTelemetryConfig{Enabled: true, MaxAgents: 50, Window: time.Hour},
+				SideLookups: SideLookupsConfig{
+					Enabled:   true,
+					Workers:   4,
+					QueueSize: 100,
+					Timeout:   500 * time.Millisecond,
+					CircuitBreaker: SideLookupsCircuitBreakerConfig{
+						Failures: 0,
+						Cooldown: 30 * time.Second,
+					},
+				},
+				PartitionKey: PartitionKeyConfig{Enabled: true},
+				MissingMetadata: MissingMetadataConfig{
+					Action:             "synthesize",
+					DefaultServiceName: "sensor",
+				},
+				GeoIP: GeoIPConfig{
+					Enabled:      true,
+					Database:     "/etc/apm-server/GeoIP2-City.mmdb",
+					ReloadPeriod: time.Hour,
+				},
+				WellKnown:     WellKnownConfig{Enabled: true},
+				IndexMetadata: IndexMetadataConfig{Enabled: true},
+				AckLevel: AckLevelConfig{
+					Backend:              []string{"validate", "enqueue", "output"},
+					RUM:                  []string{"validate", "enqueue"},
+					MaxBackgroundBatches: 10,
+					Timeout:              5 * time.Second,
+				},
+				Zipkin: ZipkinConfig{MaxRequestSize: 2048},
+				PanicBreaker: PanicBreakerConfig{
+					Enabled:   true,
+					Threshold: 3,
+					Window:    30 * time.Second,
+					Cooldown:  5 * time.Minute,
+				},
+				Pseudonymization: PseudonymizationConfig{
+					Enabled: true,
+					Salt:    "secret",
+					Fields: []PseudonymizationFieldConfig{
+						{Field: "user.id", Method: "hash", IPv4Prefix: 24, IPv6Prefix: 48},
+						{Field: "client.ip", Method: "truncate", IPv4Prefix: 16, IPv6Prefix: 48},
+					},
+				},
 			},
 		},
 		"merge config with default": {
@@ -306,6 +896,7 @@ func TestUnpackConfig(t *testing.T) {
 					"policies":          []map[string]interface{}{{"sample_rate": 0.5}},
 					"interval":          "2m",
 					"ingest_rate_decay": 1.0,
+					"storage_limit":     "1GiB",
 				},
 			},
 			outCfg: &Config{
@@ -342,11 +933,21 @@ func TestUnpackConfig(t *testing.T) {
 						Cache: &Cache{
 							Expiration: 7 * time.Second,
 						},
-						IndexPattern: "apm-*-sourcemap*",
-						ESConfig:     elasticsearch.DefaultConfig(),
+						IndexPattern:     "apm-*-sourcemap*",
+						ESConfig:         elasticsearch.DefaultConfig(),
+						VersionSelection: "latest",
 					},
 					LibraryPattern:      "rum",
 					ExcludeFromGrouping: "^/webpack",
+					BotTraffic: BotTrafficConfig{
+						Action:           "tag",
+						UserAgentPattern: defaultBotUserAgentPattern,
+					},
+					ErrorSampling: ErrorSamplingConfig{
+						MaxPerKey: 100,
+						Interval:  time.Minute,
+						MaxGroups: 10000,
+					},
 				},
 				Register: &RegisterConfig{
 					Ingest: &IngestConfig{
@@ -356,9 +957,16 @@ func TestUnpackConfig(t *testing.T) {
 						},
 					},
 				},
-				Kibana:      defaultKibanaConfig(),
-				AgentConfig: &AgentConfig{Cache: &Cache{Expiration: 30 * time.Second}},
-				Pipeline:    defaultAPMPipeline,
+				Kibana: defaultKibanaConfig(),
+				AgentConfig: &AgentConfig{
+					Cache:         &Cache{Expiration: 30 * time.Second},
+					MaxStale:      24 * time.Hour,
+					NegativeCache: NegativeCacheConfig{Expiration: 30 * time.Second, MaxEntries: 10000},
+					Custom:        CustomSettingsConfig{MaxValueSize: 1024, MaxTotalSize: 16 * 1024},
+					Source:        AgentConfigSourceKibana,
+					ESConfig:      elasticsearch.DefaultConfig(),
+				},
+				Pipeline: defaultAPMPipeline,
 				JaegerConfig: JaegerConfig{
 					GRPC: JaegerGRPCConfig{
 						Enabled: true,
@@ -373,8 +981,9 @@ func TestUnpackConfig(t *testing.T) {
 						}(),
 					},
 					HTTP: JaegerHTTPConfig{
-						Enabled: false,
-						Host:    "localhost:14268",
+						Enabled:        false,
+						Host:           "localhost:14268",
+						MaxRequestSize: 4 * 1024 * 1024,
 					},
 				},
 				APIKeyConfig: &APIKeyConfig{Enabled: true, LimitPerMin: 100, ESConfig: elasticsearch.DefaultConfig()},
@@ -390,6 +999,22 @@ func TestUnpackConfig(t *testing.T) {
 						Interval:  time.Minute,
 						MaxGroups: 10000,
 					},
+					TransactionMarks: TransactionMarksAggregationConfig{
+						Enabled:   false,
+						Interval:  time.Minute,
+						MaxGroups: 10000,
+					},
+					Errors: ErrorAggregationConfig{
+						Enabled:   false,
+						Interval:  time.Minute,
+						MaxGroups: 10000,
+					},
+					Breakdown: BreakdownAggregationConfig{
+						Enabled:         false,
+						Interval:        time.Minute,
+						MaxGroups:       10000,
+						MaxPendingSpans: 100000,
+					},
 				},
 				Sampling: SamplingConfig{
 					KeepUnsampled: false,
@@ -402,8 +1027,114 @@ func TestUnpackConfig(t *testing.T) {
 						StorageDir:            "tail_sampling",
 						StorageGCInterval:     5 * time.Minute,
 						TTL:                   30 * time.Minute,
+						StorageLimit:          1024 * 1024 * 1024,
 					},
 				},
+				OTel: OTelConfig{
+					Export: OTelExportConfig{Timeout: 5 * time.Second},
+					Logs:   OTelLogsConfig{MinSeverity: "error"},
+				},
+				Preflight: PreflightConfig{
+					Enabled: false,
+					Strict:  false,
+					Timeout: 10 * time.Second,
+				},
+				Warmup: WarmupConfig{
+					Timeout:       30 * time.Second,
+					WaitForOutput: true,
+					ServicesPath:  "warmup_services.json",
+					MaxServices:   1000,
+				},
+				SLO: SLOConfig{
+					Enabled:  false,
+					Interval: time.Minute,
+				},
+				Synthetics: SyntheticsConfig{Enabled: false},
+				Forward: ForwardConfig{
+					CompressionLevel: 5,
+					Timeout:          10 * time.Second,
+					MaxRetries:       3,
+					Backoff:          ForwardBackoffConfig{Init: time.Second, Max: 10 * time.Second},
+				},
+				LoadSheddingReport: LoadSheddingReportConfig{
+					Window:      15 * time.Minute,
+					MaxServices: 1000,
+				},
+				FastValidation: FastValidationConfig{},
+				ACME: ACMEConfig{
+					CacheDir:     "acme",
+					DirectoryURL: "https://acme-v02.api.letsencrypt.org/directory",
+					RenewBefore:  30 * 24 * time.Hour,
+				},
+				ResponseCompression: ResponseCompressionConfig{
+					Enabled: true,
+					MinSize: 1024,
+				},
+				EventQueues: EventQueuesConfig{
+					Enabled: false,
+					Traces:  EventQueueConfig{Flush: EventQueueFlushConfig{Timeout: time.Second}},
+					Errors:  EventQueueConfig{Flush: EventQueueFlushConfig{Timeout: time.Second}},
+					Metrics: EventQueueConfig{Flush: EventQueueFlushConfig{Timeout: time.Second}},
+				},
+				Journal: JournalConfig{
+					Enabled: false,
+					Path:    "journal",
+					MaxSize: 1024 * 1024 * 1024,
+					MaxAge:  24 * time.Hour,
+				},
+				TemplateUpgrade: TemplateUpgradeConfig{Enabled: false},
+				PipelineCheck: PipelineCheckConfig{
+					Enabled:  true,
+					Fallback: false,
+					Timeout:  5 * time.Second,
+				},
+				PhaseTimings:  PhaseTimingsConfig{Enabled: false},
+				ProxyProtocol: ProxyProtocolConfig{HeaderTimeout: 5 * time.Second},
+				IndexRouting:  IndexRoutingConfig{Path: "index_routing.yml", ReloadPeriod: 10 * time.Second},
+				DecodeLimits: DecodeLimitsConfig{
+					MaxConcurrentDecoders: 64,
+					DecoderTimeout:        time.Second,
+					MaxInFlightEvents:     50000,
+				},
+				UsageReport: UsageReportConfig{Interval: time.Minute, MaxServices: 1000},
+				RoutingHint: RoutingHintConfig{Header: "Elastic-Apm-Routing-Hint"},
+				GRPC:        GRPCConfig{Reflection: false},
+				Lookup: LookupConfig{
+					Path:         "lookup.csv",
+					Field:        "service.name",
+					TargetField:  "labels",
+					ReloadPeriod: 10 * time.Second,
+				},
+				AlertWebhook: AlertWebhookConfig{Timeout: 5 * time.Second, MaxPerMinute: 60},
+				Auth: AuthConfig{
+					Anonymous: AnonymousAuthConfig{
+						RateLimit: EventRate{Limit: 100, LruSize: 1000},
+					},
+				},
+				TransactionResult: TransactionResultConfig{HTTP: true, GRPC: true},
+				InternalDocuments: InternalDocumentsConfig{ID: "auto"},
+				KeywordTruncation: KeywordTruncationConfig{MaxLength: 1024},
+				IntakeTelemetry:   IntakeTelemetryConfig{MaxAgents: 1000, Window: 24 * time.Hour},
+				SideLookups: SideLookupsConfig{
+					Workers:   10,
+					QueueSize: 100,
+					Timeout:   time.Second,
+					CircuitBreaker: SideLookupsCircuitBreakerConfig{
+						Failures: 5,
+						Cooldown: 30 * time.Second,
+					},
+				},
+				PartitionKey:    PartitionKeyConfig{Fallback: "service.name"},
+				MissingMetadata: MissingMetadataConfig{Action: "reject"},
+				GeoIP:           GeoIPConfig{Database: "GeoLite2-City.mmdb", ReloadPeriod: time.Minute},
+				PanicBreaker:    PanicBreakerConfig{Threshold: 5, Window: time.Minute, Cooldown: time.Minute},
+				Zipkin:          ZipkinConfig{MaxRequestSize: 4 * 1024 * 1024},
+				AckLevel: AckLevelConfig{
+					Backend:              []string{"validate", "enqueue", "output"},
+					RUM:                  []string{"enqueue"},
+					MaxBackgroundBatches: 100,
+					Timeout:              30 * time.Second,
+				},
 			},
 		},
 		"kibana trailing slash": {
@@ -426,6 +1157,16 @@ func TestUnpackConfig(t *testing.T) {
 			},
 			outCfg: kibanaHeadersConfig,
 		},
+		"kibana proxy": {
+			inpCfg: map[string]interface{}{
+				"kibana": map[string]interface{}{
+					"enabled":   "true",
+					"proxy_url": "socks5://proxy:1080",
+					"no_proxy":  []string{"kibana.internal"},
+				},
+			},
+			outCfg: kibanaProxyConfig,
+		},
 		"response headers": {
 			inpCfg: map[string]interface{}{
 				"response_headers": map[string]interface{}{
@@ -585,9 +1326,111 @@ func TestAgentConfig(t *testing.T) {
 		cfg, err := NewConfig(common.MustNewConfigFrom(map[string]string{"agent.config.cache.expiration": "123000ms"}), nil)
 		require.NoError(t, err)
 		assert.Equal(t, time.Second*123, cfg.AgentConfig.Cache.Expiration)
+		// The negative cache expiration defaults to the cache expiration.
+		assert.Equal(t, time.Second*123, cfg.AgentConfig.NegativeCache.Expiration)
+	})
+
+	t.Run("InvalidNegativeCacheExpiration", func(t *testing.T) {
+		cfg, err := NewConfig(common.MustNewConfigFrom(map[string]string{
+			"agent.config.cache.expiration":          "30s",
+			"agent.config.negative_cache.expiration": "1m",
+		}), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "negative_cache.expiration (1m0s) must not exceed cache.expiration (30s)")
+		assert.Nil(t, cfg)
+	})
+
+	t.Run("InvalidCustomSize", func(t *testing.T) {
+		for _, key := range []string{"agent.config.custom.max_value_size", "agent.config.custom.max_total_size"} {
+			cfg, err := NewConfig(common.MustNewConfigFrom(map[string]string{key: "0"}), nil)
+			require.Error(t, err, key)
+			assert.Nil(t, cfg)
+		}
+	})
+
+	t.Run("InvalidEncryptionKey", func(t *testing.T) {
+		for _, key := range []string{"not-base64!", "c2hvcnQ="} {
+			cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"agent.config.encryption.keys": []string{key},
+			}), nil)
+			require.Error(t, err, key)
+			assert.Nil(t, cfg)
+		}
+	})
+
+	t.Run("InvalidSource", func(t *testing.T) {
+		cfg, err := NewConfig(common.MustNewConfigFrom(map[string]string{"agent.config.source": "fleet"}), nil)
+		require.Error(t, err)
+		assert.Nil(t, cfg)
+	})
+
+	t.Run("ElasticsearchOutputFallback", func(t *testing.T) {
+		outputESCfg := common.MustNewConfigFrom(map[string]interface{}{
+			"hosts":    []string{"output:9200"},
+			"username": "output_user",
+			"password": "output_pass",
+		})
+		cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+			"agent.config.elasticsearch_fallback": true,
+			"kibana.username":                     "kibana_user",
+			"kibana.password":                     "kibana_pass",
+		}), outputESCfg)
+		require.NoError(t, err)
+		assert.Equal(t, elasticsearch.Hosts{"output:9200"}, cfg.AgentConfig.ESConfig.Hosts)
+		assert.Equal(t, "kibana_user", cfg.AgentConfig.ESConfig.Username)
+		assert.Equal(t, "kibana_pass", cfg.AgentConfig.ESConfig.Password)
+
+		cfg, err = NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+			"agent.config.elasticsearch_fallback": true,
+		}), outputESCfg)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.AgentConfig.ESConfig.Username)
+		assert.Empty(t, cfg.AgentConfig.ESConfig.Password)
+
+		cfg, err = NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+			"agent.config.source":              "elasticsearch",
+			"agent.config.elasticsearch.hosts": []string{"agentcfg:9200"},
+		}), outputESCfg)
+		require.NoError(t, err)
+		assert.Equal(t, elasticsearch.Hosts{"agentcfg:9200"}, cfg.AgentConfig.ESConfig.Hosts)
+
+		cfg, err = NewConfig(common.NewConfig(), outputESCfg)
+		require.NoError(t, err)
+		assert.Equal(t, elasticsearch.Hosts{"localhost:9200"}, cfg.AgentConfig.ESConfig.Hosts)
 	})
 }
 
+func TestTailSamplingPolicyMinDuration(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"sampling.tail.enabled": true,
+		"sampling.tail.policies": []map[string]interface{}{
+			{"trace.min_duration": "500ms", "sample_rate": 1.0},
+			{"sample_rate": 0.1},
+		},
+	}), nil)
+	require.NoError(t, err)
+	require.Len(t, cfg.Sampling.Tail.Policies, 2)
+	assert.Equal(t, 500*time.Millisecond, cfg.Sampling.Tail.Policies[0].Trace.MinDuration)
+	assert.Zero(t, cfg.Sampling.Tail.Policies[1].Trace.MinDuration)
+
+	_, err = NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"sampling.tail.enabled": true,
+		"sampling.tail.policies": []map[string]interface{}{
+			{"trace.min_duration": "500ms", "sample_rate": 1.0},
+		},
+	}), nil)
+	assert.EqualError(t, err, "Error processing configuration: invalid tail sampling config: no default (empty criteria) policy specified accessing 'sampling.tail'")
+}
+
+func TestKibanaProxyInvalid(t *testing.T) {
+	_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"kibana.enabled":   true,
+		"kibana.proxy_url": "ftp://proxy",
+	}), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unsupported proxy URL scheme "ftp"`)
+}
+
 func TestNewConfig_ESConfig(t *testing.T) {
 	ucfg, err := common.NewConfigFrom(`{"rum.enabled":true,"api_key.enabled":true,"sampling.tail.policies":[{"sample_rate": 0.5}]}`)
 	require.NoError(t, err)
@@ -0,0 +1,72 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DecodeLimitsConfig holds configuration for limiting the resources used
+// by decoding events, protecting the server from requests holding large
+// batches of events regardless of the number of concurrent requests.
+type DecodeLimitsConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// MaxConcurrentDecoders holds the maximum number of batches of intake
+	// events that may be decoded concurrently, across all Elastic APM,
+	// OTLP/HTTP and Jaeger HTTP requests.
+	MaxConcurrentDecoders int `config:"max_concurrent_decoders"`
+
+	// DecoderTimeout holds the maximum amount of time to wait for another
+	// decoder to finish when MaxConcurrentDecoders batches are being decoded,
+	// before responding with a queue full error.
+	DecoderTimeout time.Duration `config:"decoder_timeout"`
+
+	// MaxInFlightEvents holds the maximum number of events, from all
+	// protocols, that may be decoded but not yet accepted by the publisher.
+	// Batches of events which would exceed the limit are rejected with a
+	// queue full error.
+	MaxInFlightEvents int `config:"max_in_flight_events"`
+}
+
+func (c *DecodeLimitsConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxConcurrentDecoders <= 0 {
+		return errors.New("max_concurrent_decoders must be positive")
+	}
+	if c.DecoderTimeout < 0 {
+		return errors.New("decoder_timeout must not be negative")
+	}
+	if c.MaxInFlightEvents <= 0 {
+		return errors.New("max_in_flight_events must be positive")
+	}
+	return nil
+}
+
+func defaultDecodeLimitsConfig() DecodeLimitsConfig {
+	return DecodeLimitsConfig{
+		Enabled:               false,
+		MaxConcurrentDecoders: 64,
+		DecoderTimeout:        time.Second,
+		MaxInFlightEvents:     50000,
+	}
+}
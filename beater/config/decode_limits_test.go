@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestDecodeLimitsConfigInvalid(t *testing.T) {
+	for name, test := range map[string]struct {
+		decodeLimits map[string]interface{}
+		err          string
+	}{
+		"non-positive max concurrent decoders": {
+			decodeLimits: map[string]interface{}{"enabled": true, "max_concurrent_decoders": 0},
+			err:          "max_concurrent_decoders must be positive",
+		},
+		"negative decoder timeout": {
+			decodeLimits: map[string]interface{}{"enabled": true, "decoder_timeout": "-1s"},
+			err:          "decoder_timeout must not be negative",
+		},
+		"non-positive max in-flight events": {
+			decodeLimits: map[string]interface{}{"enabled": true, "max_in_flight_events": 0},
+			err:          "max_in_flight_events must be positive",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"decode_limits": test.decodeLimits,
+			}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// EventQueuesConfig holds configuration for publishing traces, errors, and
+// metrics through separate queues and pipeline clients, so that bursts of
+// one event type do not delay the publishing of other event types.
+type EventQueuesConfig struct {
+	// Enabled controls whether events are published through separate
+	// queues by event type. If false, all events share a single queue.
+	Enabled bool `config:"enabled"`
+
+	// Traces holds configuration for the queue of transactions and spans.
+	Traces EventQueueConfig `config:"traces"`
+
+	// Errors holds configuration for the queue of errors.
+	Errors EventQueueConfig `config:"errors"`
+
+	// Metrics holds configuration for the queue of metricsets and profiles.
+	Metrics EventQueueConfig `config:"metrics"`
+}
+
+// EventQueueConfig holds configuration for a single event queue.
+type EventQueueConfig struct {
+	// Size holds the number of pending requests buffered by the queue.
+	// If Size is zero, the number of CPUs is used.
+	Size int `config:"size" validate:"min=0"`
+
+	// Flush holds configuration for buffering events before publishing.
+	Flush EventQueueFlushConfig `config:"flush"`
+}
+
+// EventQueueFlushConfig holds configuration for buffering events
+// before publishing them.
+type EventQueueFlushConfig struct {
+	// MinEvents holds the minimum number of events to buffer before
+	// publishing them. If MinEvents is zero or one, events are
+	// published immediately.
+	MinEvents int `config:"min_events" validate:"min=0"`
+
+	// Timeout holds the maximum duration for which events are buffered.
+	Timeout time.Duration `config:"timeout"`
+}
+
+func (c *EventQueueFlushConfig) Validate() error {
+	if c.MinEvents > 1 && c.Timeout <= 0 {
+		return errors.New("timeout must be positive if min_events is greater than one")
+	}
+	return nil
+}
+
+func defaultEventQueuesConfig() EventQueuesConfig {
+	return EventQueuesConfig{
+		Enabled: false,
+		Traces:  defaultEventQueueConfig(),
+		Errors:  defaultEventQueueConfig(),
+		Metrics: defaultEventQueueConfig(),
+	}
+}
+
+func defaultEventQueueConfig() EventQueueConfig {
+	return EventQueueConfig{
+		Flush: EventQueueFlushConfig{Timeout: time.Second},
+	}
+}
@@ -0,0 +1,41 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestEventQueuesConfigInvalid(t *testing.T) {
+	for name, eventQueues := range map[string]map[string]interface{}{
+		"negative size":       {"traces.size": -1},
+		"negative min_events": {"errors.flush.min_events": -1},
+		"missing timeout":     {"metrics.flush.min_events": 10, "metrics.flush.timeout": 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"event_queues": eventQueues,
+			}), nil)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// expiryEventTypes holds the event types, as recorded in processor.event,
+// for which a document time-to-live may be configured.
+var expiryEventTypes = map[string]bool{
+	"error":       true,
+	"metric":      true,
+	"profile":     true,
+	"span":        true,
+	"transaction": true,
+}
+
+// ExpiryConfig holds configuration for stamping documents with an expiry
+// timestamp, for enforcing retention per event type downstream.
+type ExpiryConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// TTL maps event types to the time-to-live of their documents.
+	// Documents of event types without a time-to-live are not stamped.
+	TTL map[string]time.Duration `config:"ttl"`
+}
+
+func (c *ExpiryConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.TTL) == 0 {
+		return fmt.Errorf("ttl must be specified for at least one event type")
+	}
+	var unknown []string
+	for eventType, ttl := range c.TTL {
+		if !expiryEventTypes[eventType] {
+			unknown = append(unknown, eventType)
+		} else if ttl <= 0 {
+			return fmt.Errorf("ttl for %s must be positive", eventType)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf(
+			"unknown event types: %s (expected error, metric, profile, span, or transaction)",
+			strings.Join(unknown, ", "),
+		)
+	}
+	return nil
+}
+
+func defaultExpiryConfig() ExpiryConfig {
+	return ExpiryConfig{Enabled: false}
+}
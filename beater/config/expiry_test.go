@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestExpiryConfig(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"expiry": map[string]interface{}{
+			"enabled": true,
+			"ttl":     map[string]interface{}{"span": "168h", "error": "2160h"},
+		},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"span":  7 * 24 * time.Hour,
+		"error": 90 * 24 * time.Hour,
+	}, cfg.Expiry.TTL)
+}
+
+func TestExpiryConfigInvalid(t *testing.T) {
+	for name, test := range map[string]struct {
+		expiry map[string]interface{}
+		err    string
+	}{
+		"no ttl": {
+			expiry: map[string]interface{}{"enabled": true},
+			err:    "ttl must be specified for at least one event type",
+		},
+		"unknown event type": {
+			expiry: map[string]interface{}{"enabled": true, "ttl": map[string]interface{}{"spam": "1h"}},
+			err:    "unknown event types: spam",
+		},
+		"non-positive ttl": {
+			expiry: map[string]interface{}{"enabled": true, "ttl": map[string]interface{}{"span": "0s"}},
+			err:    "ttl for span must be positive",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"expiry": test.expiry,
+			}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
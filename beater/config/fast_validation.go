@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+// FastValidationConfig holds configuration for skipping validation of
+// backend agent events, relying on the typed decoders alone.
+//
+// Metadata is always validated, and RUM events are never affected.
+type FastValidationConfig struct {
+	// Enabled controls whether validation is skipped for events sent by
+	// agents matching MinAgentVersions. Validation is only skipped for
+	// requests authenticated with a secret token or API Key, and never
+	// for anonymous or bypassed requests.
+	Enabled bool `config:"enabled"`
+
+	// MinAgentVersions maps agent names to the minimum agent version
+	// for which validation is skipped. Events sent by agents not listed,
+	// or by older agents, are always validated.
+	MinAgentVersions map[string]string `config:"min_agent_versions"`
+}
+
+func (c *FastValidationConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.MinAgentVersions) == 0 {
+		return errors.New("fast validation requires at least one entry in min_agent_versions")
+	}
+	for name, version := range c.MinAgentVersions {
+		if _, err := common.NewVersion(version); err != nil {
+			return errors.Wrapf(err, "invalid minimum version for agent %q", name)
+		}
+	}
+	return nil
+}
+
+func defaultFastValidationConfig() FastValidationConfig {
+	return FastValidationConfig{}
+}
@@ -0,0 +1,45 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/elastic/apm-server/featureflag"
+)
+
+// FeatureFlagsConfig maps feature flag names to their state, overriding
+// the flag defaults. Feature flags may be updated through central
+// management without restarting the server process.
+type FeatureFlagsConfig map[string]bool
+
+func (c FeatureFlagsConfig) Validate() error {
+	var unknown []string
+	for name := range c {
+		if !featureflag.Known(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown feature flags: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
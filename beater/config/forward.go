@@ -0,0 +1,100 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common/transport/tlscommon"
+)
+
+// ForwardConfig holds configuration for forwarding intake requests to
+// another APM Server, rather than publishing events to the output.
+//
+// This enables hub-and-spoke topologies, in which edge APM Servers close
+// to agents validate events and relay them to a central APM Server.
+type ForwardConfig struct {
+	// Enabled controls whether intake requests are forwarded.
+	Enabled bool `config:"enabled"`
+
+	// URL holds the base URL of the APM Server to which requests are forwarded.
+	URL string `config:"url"`
+
+	// SecretToken holds the secret token used to authorize forwarded requests.
+	SecretToken string `config:"secret_token"`
+
+	// APIKey holds the base64-encoded API Key used to authorize forwarded
+	// requests. If specified, APIKey takes precedence over SecretToken.
+	APIKey string `config:"api_key"`
+
+	// TLS holds the TLS configuration for connections to URL.
+	TLS *tlscommon.Config `config:"ssl"`
+
+	// CompressionLevel holds the gzip compression level for forwarded
+	// request bodies. A compression level of zero disables compression.
+	CompressionLevel int `config:"compression_level" validate:"min=0, max=9"`
+
+	// Timeout holds the maximum amount of time to wait for each attempt
+	// to forward a request.
+	Timeout time.Duration `config:"timeout" validate:"min=1"`
+
+	// MaxRetries holds the maximum number of times a failed request is
+	// retried, while the originating intake request has not timed out.
+	MaxRetries int `config:"max_retries" validate:"min=0"`
+
+	// Backoff holds the backoff configuration for retrying failed requests.
+	Backoff ForwardBackoffConfig `config:"backoff"`
+}
+
+// ForwardBackoffConfig holds configuration for exponential backoff.
+type ForwardBackoffConfig struct {
+	Init time.Duration `config:"init" validate:"min=1"`
+	Max  time.Duration `config:"max" validate:"min=1"`
+}
+
+func (c *ForwardConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.URL == "" {
+		return errors.New("url must be specified when forwarding is enabled")
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return errors.Wrap(err, "invalid forwarding url")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("invalid forwarding url scheme %q", u.Scheme)
+	}
+	return nil
+}
+
+func defaultForwardConfig() ForwardConfig {
+	return ForwardConfig{
+		CompressionLevel: 5,
+		Timeout:          10 * time.Second,
+		MaxRetries:       3,
+		Backoff: ForwardBackoffConfig{
+			Init: time.Second,
+			Max:  10 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// GeoIPConfig holds configuration for adding geographical information about
+// client IP addresses to events using a MaxMind database, rather than the
+// Elasticsearch ingest pipeline, e.g. for outputs other than Elasticsearch.
+type GeoIPConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Database holds the path to a MaxMind GeoIP2 or GeoLite2 City
+	// database, in the configuration directory if relative.
+	Database string `config:"database"`
+
+	// ReloadPeriod holds how often to look for an updated database,
+	// e.g. one replaced by MaxMind's geoipupdate tool.
+	ReloadPeriod time.Duration `config:"reload.period"`
+}
+
+func (c *GeoIPConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Database == "" {
+		return errors.New("database must be specified")
+	}
+	if c.ReloadPeriod <= 0 {
+		return errors.New("reload.period must be positive")
+	}
+	return nil
+}
+
+func defaultGeoIPConfig() GeoIPConfig {
+	return GeoIPConfig{
+		Enabled:      false,
+		Database:     "GeoLite2-City.mmdb",
+		ReloadPeriod: time.Minute,
+	}
+}
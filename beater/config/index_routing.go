@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// IndexRoutingConfig holds configuration for routing events of specific
+// services to custom index aliases or data stream namespaces, according
+// to the rules in a mapping file.
+type IndexRoutingConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Path holds the YAML file listing the routing rules, of which the
+	// first matching an event is applied. A relative path is taken from
+	// the configuration directory.
+	Path string `config:"path"`
+
+	// ReloadPeriod bounds how long events are routed by outdated rules
+	// after the mapping file is edited.
+	ReloadPeriod time.Duration `config:"reload.period"`
+}
+
+func (c *IndexRoutingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Path == "" {
+		return errors.New("path must be specified")
+	}
+	if c.ReloadPeriod <= 0 {
+		return errors.New("reload.period must be positive")
+	}
+	return nil
+}
+
+func defaultIndexRoutingConfig() IndexRoutingConfig {
+	return IndexRoutingConfig{
+		Enabled:      false,
+		Path:         "index_routing.yml",
+		ReloadPeriod: 10 * time.Second,
+	}
+}
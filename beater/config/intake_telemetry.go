@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// IntakeTelemetryConfig holds configuration for tracking statistics of
+// intake requests per agent, such as the compression codecs and TLS
+// versions used, and reporting them via the intake telemetry endpoint.
+type IntakeTelemetryConfig struct {
+	// Enabled controls whether intake requests are tracked and reported.
+	Enabled bool `config:"enabled"`
+
+	// MaxAgents holds the maximum number of agents tracked. Requests
+	// of additional agents are attributed to no agent.
+	MaxAgents int `config:"max_agents" validate:"min=1"`
+
+	// Window holds the duration for which agents are reported after
+	// their last request. Agents not seen within the window are no
+	// longer tracked, freeing their slot for other agents.
+	Window time.Duration `config:"window"`
+}
+
+func (c *IntakeTelemetryConfig) Validate() error {
+	if c.Window < time.Minute {
+		return errors.New("intake telemetry window must be at least 1m")
+	}
+	return nil
+}
+
+func defaultIntakeTelemetryConfig() IntakeTelemetryConfig {
+	return IntakeTelemetryConfig{
+		MaxAgents: 1000,
+		Window:    24 * time.Hour,
+	}
+}
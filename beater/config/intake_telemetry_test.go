@@ -0,0 +1,41 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestIntakeTelemetryConfigInvalid(t *testing.T) {
+	_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"intake_telemetry.max_agents": 0,
+	}), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires value < 1 accessing 'intake_telemetry.max_agents'")
+
+	_, err = NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"intake_telemetry.window": "30s",
+	}), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "intake telemetry window must be at least 1m")
+}
@@ -0,0 +1,78 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common/fmtstr"
+)
+
+const (
+	// InternalDocumentsIDAuto leaves generating the IDs
+	// of internal documents to Elasticsearch.
+	InternalDocumentsIDAuto = "auto"
+
+	// InternalDocumentsIDContentHash generates the IDs of internal
+	// documents from a hash of their content.
+	InternalDocumentsIDContentHash = "content_hash"
+)
+
+// InternalDocumentsConfig holds configuration for the indices and document
+// IDs of internal documents, i.e. onboarding documents and metrics aggregated
+// by APM Server, so that they can be directed away from production aliases.
+type InternalDocumentsConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Index holds an event format string for the index alias of internal
+	// documents, e.g. "apm-internal-%{+yyyy.MM.dd}". If empty, internal
+	// documents are indexed as usual. Index is ignored with data streams.
+	Index string `config:"index"`
+
+	// Namespace holds the data stream namespace of internal documents
+	// when data streams are enabled. If empty, internal documents are
+	// indexed as usual.
+	Namespace string `config:"namespace"`
+
+	// ID holds the method for generating the IDs of internal documents,
+	// "auto" or "content_hash".
+	ID string `config:"id"`
+}
+
+func (c *InternalDocumentsConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Index != "" {
+		if _, err := fmtstr.CompileEvent(c.Index); err != nil {
+			return errors.Wrapf(err, "invalid index %q", c.Index)
+		}
+	}
+	switch c.ID {
+	case InternalDocumentsIDAuto, InternalDocumentsIDContentHash:
+	default:
+		return errors.Errorf("invalid id %q, expected %q or %q", c.ID, InternalDocumentsIDAuto, InternalDocumentsIDContentHash)
+	}
+	return nil
+}
+
+func defaultInternalDocumentsConfig() InternalDocumentsConfig {
+	return InternalDocumentsConfig{
+		ID: InternalDocumentsIDAuto,
+	}
+}
@@ -24,8 +24,9 @@ import (
 )
 
 const (
-	defaultJaegerGRPCHost = "localhost:14250"
-	defaultJaegerHTTPHost = "localhost:14268"
+	defaultJaegerGRPCHost           = "localhost:14250"
+	defaultJaegerHTTPHost           = "localhost:14268"
+	defaultJaegerHTTPMaxRequestSize = 4 * 1024 * 1024
 )
 
 // JaegerConfig holds configuration for Jaeger span collection.
@@ -46,6 +47,11 @@ type JaegerGRPCConfig struct {
 type JaegerHTTPConfig struct {
 	Enabled bool   `config:"enabled"`
 	Host    string `config:"host"`
+
+	// MaxRequestSize holds the maximum size of a Thrift-encoded batch of
+	// spans sent to the Jaeger HTTP endpoint, whether served by the Jaeger
+	// HTTP server or on the APM Server port.
+	MaxRequestSize int `config:"max_request_size" validate:"min=1"`
 }
 
 func (c *JaegerConfig) setup(cfg *Config) error {
@@ -69,8 +75,9 @@ func defaultJaeger() JaegerConfig {
 			Host:    defaultJaegerGRPCHost,
 		},
 		HTTP: JaegerHTTPConfig{
-			Enabled: false,
-			Host:    defaultJaegerHTTPHost,
+			Enabled:        false,
+			Host:           defaultJaegerHTTPHost,
+			MaxRequestSize: defaultJaegerHTTPMaxRequestSize,
 		},
 	}
 }
@@ -30,8 +30,9 @@ func TestJaeger_default(t *testing.T) {
 			Host:    "localhost:14250",
 		},
 		HTTP: JaegerHTTPConfig{
-			Enabled: false,
-			Host:    "localhost:14268",
+			Enabled:        false,
+			Host:           "localhost:14268",
+			MaxRequestSize: 4 * 1024 * 1024,
 		},
 	}
 	assert.Equal(t, expected, defaultJaeger())
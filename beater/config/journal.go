@@ -0,0 +1,71 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common/cfgtype"
+)
+
+// JournalSegmentSize holds the size in bytes after which a new journal
+// file is started.
+const JournalSegmentSize = 64 * 1024 * 1024
+
+// JournalConfig holds configuration for journaling accepted events to local
+// disk until they have been acknowledged by the output, and replaying them
+// on startup after the server crashed.
+type JournalConfig struct {
+	// Enabled controls whether accepted events are journaled.
+	Enabled bool `config:"enabled"`
+
+	// Path holds the directory in which journal files are stored,
+	// relative to the data path if not absolute.
+	Path string `config:"path"`
+
+	// MaxSize holds the maximum total size of journal files, which must be
+	// at least the size of a journal file, 64MB. While the journal is full,
+	// events are published without being journaled.
+	MaxSize cfgtype.ByteSize `config:"max_size"`
+
+	// MaxAge holds the maximum age of journal files replayed on startup.
+	// Events in older journal files are discarded. If zero, journal files
+	// are replayed regardless of their age.
+	MaxAge time.Duration `config:"max_age" validate:"min=0"`
+}
+
+func (c *JournalConfig) Validate() error {
+	if c.Path == "" {
+		return errors.New("path must be specified")
+	}
+	if c.MaxSize < JournalSegmentSize {
+		return errors.Errorf("max_size must be at least %d bytes, the size of a journal file", JournalSegmentSize)
+	}
+	return nil
+}
+
+func defaultJournalConfig() JournalConfig {
+	return JournalConfig{
+		Enabled: false,
+		Path:    "journal",
+		MaxSize: 1024 * 1024 * 1024,
+		MaxAge:  24 * time.Hour,
+	}
+}
@@ -0,0 +1,52 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/common/cfgtype"
+)
+
+func TestJournalConfig(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"journal": map[string]interface{}{"enabled": true, "max_size": "100MiB"},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, cfgtype.ByteSize(100*1024*1024), cfg.Journal.MaxSize)
+}
+
+func TestJournalConfigInvalid(t *testing.T) {
+	for name, journal := range map[string]map[string]interface{}{
+		"empty path":       {"path": ""},
+		"invalid max_size": {"max_size": "lots"},
+		"small max_size":   {"max_size": "10MiB"},
+		"negative max_age": {"max_age": "-1h"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"journal": journal,
+			}), nil)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"github.com/pkg/errors"
+)
+
+// DefaultKeywordTruncationFields holds the keyword fields truncated when
+// KeywordTruncationConfig.Fields is empty: those whose values are not
+// length-limited by all intake protocols, e.g. OpenTelemetry and Jaeger.
+var DefaultKeywordTruncationFields = []string{
+	"error.culprit",
+	"error.log.logger_name",
+	"service.environment",
+	"service.name",
+	"service.node.name",
+	"span.action",
+	"span.destination.service.resource",
+	"span.name",
+	"span.subtype",
+	"span.type",
+	"transaction.name",
+	"transaction.result",
+	"transaction.type",
+	"url.original",
+	"user_agent.original",
+}
+
+// KeywordTruncationConfig holds configuration for truncating keyword
+// fields, so that overly long values are indexed in truncated form rather
+// than silently not being indexed by Elasticsearch.
+type KeywordTruncationConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// MaxLength holds the maximum number of characters of keyword field
+	// values. This should match the ignore_above value of the template.
+	MaxLength int `config:"max_length"`
+
+	// Fields holds the names of the keyword fields to truncate. If empty,
+	// DefaultKeywordTruncationFields is used.
+	Fields []string `config:"fields"`
+}
+
+func (c *KeywordTruncationConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxLength <= 0 {
+		return errors.New("max_length must be greater than 0")
+	}
+	for _, field := range c.Fields {
+		if field == "" {
+			return errors.New("fields must not contain empty names")
+		}
+	}
+	return nil
+}
+
+func defaultKeywordTruncationConfig() KeywordTruncationConfig {
+	return KeywordTruncationConfig{
+		MaxLength: 1024,
+	}
+}
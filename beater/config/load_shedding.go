@@ -0,0 +1,55 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LoadSheddingReportConfig holds configuration for tracking the events
+// and requests dropped or rejected by the server, per service, and
+// reporting them via the load shedding report endpoint.
+type LoadSheddingReportConfig struct {
+	// Enabled controls whether dropped events are tracked and reported.
+	Enabled bool `config:"enabled"`
+
+	// Window holds the duration for which dropped events are reported.
+	// Dropped events are tracked with a resolution of one minute.
+	Window time.Duration `config:"window"`
+
+	// MaxServices holds the maximum number of services tracked within
+	// each minute. Drops for additional services are attributed to no
+	// service.
+	MaxServices int `config:"max_services" validate:"min=1"`
+}
+
+func (c *LoadSheddingReportConfig) Validate() error {
+	if c.Window < time.Minute {
+		return errors.New("load shedding report window must be at least 1m")
+	}
+	return nil
+}
+
+func defaultLoadSheddingReportConfig() LoadSheddingReportConfig {
+	return LoadSheddingReportConfig{
+		Window:      15 * time.Minute,
+		MaxServices: 1000,
+	}
+}
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LookupConfig holds configuration for annotating events with fields
+// from a lookup file, joined on the value of an event field.
+type LookupConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Path holds the lookup file, relative to the configuration directory
+	// unless absolute: a CSV file whose first column holds the lookup keys,
+	// or a JSON object keyed by lookup key. See lookup.LoadTable.
+	Path string `config:"path"`
+
+	// Field holds the event field whose value is looked up,
+	// e.g. "service.name" or "labels.team".
+	Field string `config:"field"`
+
+	// TargetField holds the object field under which the looked up
+	// fields are added to events.
+	TargetField string `config:"target_field"`
+
+	// ReloadPeriod holds how often the lookup file is polled, so edited
+	// keys take effect without restarting the server.
+	ReloadPeriod time.Duration `config:"reload.period"`
+}
+
+func (c *LookupConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Path == "" {
+		return errors.New("path must be specified")
+	}
+	if c.Field == "" {
+		return errors.New("field must be specified")
+	}
+	if c.TargetField == "" {
+		return errors.New("target_field must be specified")
+	}
+	if c.ReloadPeriod <= 0 {
+		return errors.New("reload.period must be positive")
+	}
+	return nil
+}
+
+func defaultLookupConfig() LookupConfig {
+	return LookupConfig{
+		Enabled:      false,
+		Path:         "lookup.csv",
+		Field:        "service.name",
+		TargetField:  "labels",
+		ReloadPeriod: 10 * time.Second,
+	}
+}
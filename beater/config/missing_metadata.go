@@ -0,0 +1,57 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import "github.com/pkg/errors"
+
+const (
+	// MissingMetadataActionReject rejects intake streams
+	// without valid metadata.
+	MissingMetadataActionReject = "reject"
+
+	// MissingMetadataActionSynthesize accepts events of intake streams
+	// without valid metadata, synthesizing minimal metadata for them.
+	MissingMetadataActionSynthesize = "synthesize"
+)
+
+// MissingMetadataConfig holds configuration for handling intake streams
+// whose first line is missing, or is not a valid metadata object, such
+// as streams sent by constrained devices.
+type MissingMetadataConfig struct {
+	// Action holds the action taken for streams without valid metadata,
+	// "reject" or "synthesize".
+	Action string `config:"action"`
+
+	// DefaultServiceName holds the service name of synthesized metadata
+	// for requests without an Elastic-Apm-Service-Name header. If empty,
+	// streams without valid metadata from such requests are rejected.
+	DefaultServiceName string `config:"default_service_name"`
+}
+
+func (c *MissingMetadataConfig) Validate() error {
+	switch c.Action {
+	case MissingMetadataActionReject, MissingMetadataActionSynthesize:
+	default:
+		return errors.Errorf("invalid action %q, expected %q or %q", c.Action, MissingMetadataActionReject, MissingMetadataActionSynthesize)
+	}
+	return nil
+}
+
+func defaultMissingMetadataConfig() MissingMetadataConfig {
+	return MissingMetadataConfig{Action: MissingMetadataActionReject}
+}
@@ -0,0 +1,196 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+// OTelConfig holds configuration related to the translation of
+// OpenTelemetry data into the Elastic APM data model.
+type OTelConfig struct {
+	// InstrumentationScopes holds rules for renaming, down-sampling
+	// or dropping spans by their instrumentation scope (library).
+	InstrumentationScopes []InstrumentationScopeConfig `config:"instrumentation_scopes"`
+
+	// ResourceMappings holds rules for mapping resource attributes, and
+	// Jaeger process tags, to APM metadata fields.
+	ResourceMappings []ResourceMappingConfig `config:"resource_mappings"`
+
+	// Export holds configuration for exporting traces to an
+	// OpenTelemetry collector.
+	Export OTelExportConfig `config:"export"`
+
+	// Logs holds configuration for translating OpenTelemetry log records.
+	Logs OTelLogsConfig `config:"logs"`
+}
+
+// OTelLogSeverities holds the names of the OpenTelemetry log severities
+// which may be used as the minimum severity of log records, in order.
+var OTelLogSeverities = []string{"trace", "debug", "info", "warn", "error", "fatal"}
+
+// OTelLogsConfig holds configuration for translating OpenTelemetry log
+// records into APM errors.
+type OTelLogsConfig struct {
+	// MinSeverity holds the name of the minimum severity of log records
+	// recorded as errors, one of OTelLogSeverities. Log records with a
+	// lower severity are dropped.
+	MinSeverity string `config:"min_severity"`
+}
+
+func (c *OTelLogsConfig) Validate() error {
+	for _, severity := range OTelLogSeverities {
+		if c.MinSeverity == severity {
+			return nil
+		}
+	}
+	return errors.Errorf(
+		"invalid min_severity %q, expected one of %s",
+		c.MinSeverity, strings.Join(OTelLogSeverities, ", "),
+	)
+}
+
+// OTelExportConfig holds configuration for translating transactions and
+// spans into OpenTelemetry traces, and exporting them with OTLP/gRPC.
+type OTelExportConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Endpoint holds the host:port of the OTLP/gRPC endpoint.
+	Endpoint string `config:"endpoint"`
+
+	// Insecure disables TLS for the connection to Endpoint.
+	Insecure bool `config:"insecure"`
+
+	// Headers holds additional gRPC metadata to send with each request,
+	// e.g. for authentication.
+	Headers map[string]string `config:"headers"`
+
+	// Compression holds the compression to use for requests. The only
+	// supported compression is "gzip"; the empty string disables it.
+	Compression string `config:"compression"`
+
+	// Timeout holds the maximum amount of time to wait for each export
+	// request to complete.
+	Timeout time.Duration `config:"timeout" validate:"min=1"`
+}
+
+func (c *OTelExportConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Endpoint == "" {
+		return errors.New("endpoint must be specified when OpenTelemetry export is enabled")
+	}
+	switch c.Compression {
+	case "", "gzip":
+	default:
+		return errors.Errorf("unsupported compression %q", c.Compression)
+	}
+	return nil
+}
+
+func defaultOTelConfig() OTelConfig {
+	return OTelConfig{
+		Export: OTelExportConfig{
+			Timeout: 5 * time.Second,
+		},
+		Logs: OTelLogsConfig{
+			MinSeverity: "error",
+		},
+	}
+}
+
+// InstrumentationScopeConfig holds a rule for spans produced by
+// instrumentation scopes matching Name.
+type InstrumentationScopeConfig struct {
+	// Name holds the instrumentation scope name to match. The name may
+	// end with a "*" wildcard, in which case it is treated as a prefix.
+	Name string `config:"name" validate:"required"`
+
+	// Rename, if non-empty, replaces the recorded instrumentation scope name.
+	Rename string `config:"rename"`
+
+	// SampleRate holds the fraction of traces for which spans of the
+	// instrumentation scope will be kept. A sample rate of zero drops
+	// all spans of the instrumentation scope.
+	SampleRate float64 `config:"sample_rate" validate:"min=0, max=1"`
+}
+
+func (c *InstrumentationScopeConfig) Unpack(in *common.Config) error {
+	type instrumentationScopeConfig InstrumentationScopeConfig
+	cfg := instrumentationScopeConfig{SampleRate: 1}
+	if err := in.Unpack(&cfg); err != nil {
+		return errors.Wrap(err, "error unpacking instrumentation scope config")
+	}
+	*c = InstrumentationScopeConfig(cfg)
+	return nil
+}
+
+// ResourceMappingFields holds the names of the APM metadata fields which
+// resource attributes may be mapped to.
+var ResourceMappingFields = []string{
+	"service.name",
+	"service.version",
+	"service.environment",
+	"service.node.name",
+	"cloud.provider",
+	"cloud.account.id",
+	"cloud.region",
+	"cloud.availability_zone",
+	"cloud.instance.id",
+	"cloud.instance.name",
+	"cloud.project.id",
+	"container.id",
+	"container.name",
+	"host.hostname",
+	"host.name",
+	"kubernetes.namespace",
+	"kubernetes.node.name",
+	"kubernetes.pod.name",
+	"kubernetes.pod.uid",
+}
+
+// ResourceMappingConfig holds a rule for mapping a resource attribute,
+// or Jaeger process tag, to an APM metadata field.
+type ResourceMappingConfig struct {
+	// Attribute holds the name of the resource attribute.
+	Attribute string `config:"attribute" validate:"required"`
+
+	// Field holds the name of the APM metadata field, which must be one
+	// of ResourceMappingFields.
+	Field string `config:"field" validate:"required"`
+
+	// Override controls whether the mapped value takes precedence over a
+	// value translated from standard resource attributes. By default, the
+	// mapped value is only used if the field would otherwise be empty.
+	Override bool `config:"override"`
+}
+
+func (c *ResourceMappingConfig) Validate() error {
+	for _, field := range ResourceMappingFields {
+		if c.Field == field {
+			return nil
+		}
+	}
+	return errors.Errorf("unsupported field %q", c.Field)
+}
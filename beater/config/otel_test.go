@@ -0,0 +1,125 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestOTelConfigInstrumentationScopes(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"otel.instrumentation_scopes": []map[string]interface{}{
+			{"name": "io.opentelemetry.jdbc", "rename": "jdbc"},
+			{"name": "io.opentelemetry.*", "sample_rate": 0.1},
+		},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, OTelConfig{
+		InstrumentationScopes: []InstrumentationScopeConfig{
+			{Name: "io.opentelemetry.jdbc", Rename: "jdbc", SampleRate: 1},
+			{Name: "io.opentelemetry.*", SampleRate: 0.1},
+		},
+		Export: OTelExportConfig{Timeout: 5 * time.Second},
+		Logs:   OTelLogsConfig{MinSeverity: "error"},
+	}, cfg.OTel)
+}
+
+func TestOTelLogsConfigInvalid(t *testing.T) {
+	_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"otel.logs.min_severity": "verbose",
+	}), nil)
+	assert.Error(t, err)
+}
+
+func TestOTelConfigInvalid(t *testing.T) {
+	for name, scope := range map[string]map[string]interface{}{
+		"missing name":         {"sample_rate": 0.5},
+		"negative sample_rate": {"name": "foo", "sample_rate": -1},
+		"sample_rate too high": {"name": "foo", "sample_rate": 1.5},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"otel.instrumentation_scopes": []map[string]interface{}{scope},
+			}), nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestOTelConfigResourceMappings(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"otel.resource_mappings": []map[string]interface{}{
+			{"attribute": "deployment.node", "field": "service.node.name"},
+			{"attribute": "dc", "field": "cloud.availability_zone", "override": true},
+		},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []ResourceMappingConfig{
+		{Attribute: "deployment.node", Field: "service.node.name"},
+		{Attribute: "dc", Field: "cloud.availability_zone", Override: true},
+	}, cfg.OTel.ResourceMappings)
+}
+
+func TestOTelConfigResourceMappingsInvalid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		mapping map[string]interface{}
+		err     string
+	}{
+		"missing attribute": {
+			mapping: map[string]interface{}{"field": "service.node.name"},
+			err:     "string value is not set",
+		},
+		"missing field": {
+			mapping: map[string]interface{}{"attribute": "dc"},
+			err:     "string value is not set",
+		},
+		"unsupported field": {
+			mapping: map[string]interface{}{"attribute": "dc", "field": "labels.dc"},
+			err:     `unsupported field "labels.dc"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"otel.resource_mappings": []map[string]interface{}{tc.mapping},
+			}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestOTelExportConfigInvalid(t *testing.T) {
+	for name, export := range map[string]map[string]interface{}{
+		"missing endpoint":        {"enabled": true},
+		"unsupported compression": {"enabled": true, "endpoint": "collector:4317", "compression": "zstd"},
+		"zero timeout":            {"enabled": true, "endpoint": "collector:4317", "timeout": "0s"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"otel.export": export,
+			}), nil)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PanicBreakerConfig holds configuration for disabling routes whose
+// handlers repeatedly panic, so that a request pattern crashing a handler
+// cannot keep the server busy recovering from panics.
+type PanicBreakerConfig struct {
+	// Enabled controls whether routes are disabled after repeated panics.
+	Enabled bool `config:"enabled"`
+
+	// Threshold holds the number of panics within Window after which
+	// a route is disabled.
+	Threshold int `config:"threshold" validate:"min=1"`
+
+	// Window holds the duration within which panics are counted.
+	Window time.Duration `config:"window"`
+
+	// Cooldown holds the duration for which a route is disabled,
+	// responding with 503 Service Unavailable.
+	Cooldown time.Duration `config:"cooldown"`
+}
+
+func (c *PanicBreakerConfig) Validate() error {
+	if c.Window <= 0 {
+		return errors.New("panic breaker window must be greater than 0")
+	}
+	if c.Cooldown <= 0 {
+		return errors.New("panic breaker cooldown must be greater than 0")
+	}
+	return nil
+}
+
+func defaultPanicBreakerConfig() PanicBreakerConfig {
+	return PanicBreakerConfig{
+		Threshold: 5,
+		Window:    time.Minute,
+		Cooldown:  time.Minute,
+	}
+}
@@ -0,0 +1,34 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+// PartitionKeyConfig holds configuration for setting the partition key of
+// events published to streaming outputs, such as Kafka, from their trace ID,
+// so that all events of a trace are written to the same partition.
+type PartitionKeyConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Fallback holds the name of the event field used as the partition
+	// key of events without a trace ID, such as metrics. If empty, events
+	// without a trace ID are distributed across partitions by the output.
+	Fallback string `config:"fallback"`
+}
+
+func defaultPartitionKeyConfig() PartitionKeyConfig {
+	return PartitionKeyConfig{Fallback: "service.name"}
+}
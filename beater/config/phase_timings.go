@@ -0,0 +1,35 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+// PhaseTimingsConfig holds configuration for recording the time spent in
+// each phase of processing intake requests, such as authorization, rate
+// limiting, decompression, decoding, validation and publishing.
+type PhaseTimingsConfig struct {
+	// Enabled controls whether phase timings are recorded in the
+	// monitoring histograms under apm-server.phases.
+	Enabled bool `config:"enabled"`
+
+	// Log controls whether phase timings are added to the request logs.
+	// Phase timings are only logged if Enabled is true.
+	Log bool `config:"log"`
+}
+
+func defaultPhaseTimingsConfig() PhaseTimingsConfig {
+	return PhaseTimingsConfig{Enabled: false}
+}
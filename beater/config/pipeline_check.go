@@ -0,0 +1,51 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+)
+
+const defaultPipelineCheckTimeout = 5 * time.Second
+
+// PipelineCheckConfig holds configuration related to checking that
+// documents can be routed to the configured ingest pipeline.
+type PipelineCheckConfig struct {
+	// Enabled controls whether the Elasticsearch cluster is checked
+	// for ingest nodes and the ingest pipeline on startup, and each
+	// time the Elasticsearch output connects.
+	Enabled bool `config:"enabled"`
+
+	// Fallback controls whether documents should be indexed without
+	// an ingest pipeline while the check fails. Documents indexed without
+	// the pipeline lack the fields it adds, so Fallback is disabled by
+	// default, in which case the failure is logged and the pipeline is
+	// used regardless.
+	Fallback bool `config:"fallback"`
+
+	// Timeout holds the maximum amount of time to spend on the check.
+	Timeout time.Duration `config:"timeout" validate:"min=1"`
+}
+
+func defaultPipelineCheckConfig() PipelineCheckConfig {
+	return PipelineCheckConfig{
+		Enabled:  true,
+		Fallback: false,
+		Timeout:  defaultPipelineCheckTimeout,
+	}
+}
@@ -0,0 +1,47 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+)
+
+const defaultPreflightTimeout = 10 * time.Second
+
+// PreflightConfig holds configuration related to checks
+// performed when the server starts.
+type PreflightConfig struct {
+	// Enabled controls whether preflight checks are performed on startup.
+	Enabled bool `config:"enabled"`
+
+	// Strict controls whether the server should refuse to start if
+	// any of the preflight checks fail.
+	Strict bool `config:"strict"`
+
+	// Timeout holds the maximum amount of time to spend
+	// on performing all preflight checks.
+	Timeout time.Duration `config:"timeout" validate:"min=1"`
+}
+
+func defaultPreflightConfig() PreflightConfig {
+	return PreflightConfig{
+		Enabled: false,
+		Strict:  false,
+		Timeout: defaultPreflightTimeout,
+	}
+}
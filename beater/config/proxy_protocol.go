@@ -0,0 +1,64 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ProxyProtocolConfig holds configuration for accepting PROXY protocol
+// (v1 or v2) headers on the server's listener, sent by TCP load balancers
+// such as HAProxy or AWS NLB to convey the original client address.
+type ProxyProtocolConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// TrustedProxies holds the IP addresses or CIDR ranges of the load
+	// balancers permitted to send PROXY protocol headers. Connections from
+	// trusted proxies must begin with a PROXY protocol header; connections
+	// from other addresses are served as is. If empty, all connections must
+	// begin with a PROXY protocol header.
+	TrustedProxies []string `config:"trusted_proxies"`
+
+	// HeaderTimeout holds the maximum amount of time to wait for the
+	// PROXY protocol header after a connection has been accepted.
+	HeaderTimeout time.Duration `config:"header_timeout"`
+}
+
+func (c *ProxyProtocolConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.HeaderTimeout <= 0 {
+		return errors.New("header_timeout must be positive")
+	}
+	for _, addr := range c.TrustedProxies {
+		if _, err := ParseSourceAddress(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func defaultProxyProtocolConfig() ProxyProtocolConfig {
+	return ProxyProtocolConfig{
+		Enabled:       false,
+		HeaderTimeout: 5 * time.Second,
+	}
+}
@@ -0,0 +1,67 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestProxyProtocolConfig(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"proxy_protocol": map[string]interface{}{
+			"enabled":         true,
+			"trusted_proxies": []string{"10.0.0.0/8", "192.168.1.1"},
+		},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, ProxyProtocolConfig{
+		Enabled:        true,
+		TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1"},
+		HeaderTimeout:  5 * time.Second,
+	}, cfg.ProxyProtocol)
+}
+
+func TestProxyProtocolConfigInvalid(t *testing.T) {
+	for name, test := range map[string]struct {
+		proxyProtocol map[string]interface{}
+		err           string
+	}{
+		"invalid trusted proxy": {
+			proxyProtocol: map[string]interface{}{"enabled": true, "trusted_proxies": []string{"10.0.0.0/33"}},
+			err:           `invalid source address "10.0.0.0/33"`,
+		},
+		"non-positive header timeout": {
+			proxyProtocol: map[string]interface{}{"enabled": true, "header_timeout": "0s"},
+			err:           "header_timeout must be positive",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"proxy_protocol": test.proxyProtocol,
+			}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
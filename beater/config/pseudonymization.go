@@ -0,0 +1,129 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/pseudonymize"
+)
+
+// PseudonymizationConfig holds configuration for replacing user identifiers
+// in events with salted hashes or generalized values before they are
+// published, so events of a user can be correlated without storing the
+// raw identifiers.
+type PseudonymizationConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Salt holds the secret key with which field values are hashed.
+	// Hashes only remain stable, and correlatable, while the salt is
+	// unchanged.
+	Salt string `config:"salt"`
+
+	// Fields holds the policies of the fields to pseudonymize.
+	// If empty, pseudonymize.DefaultRules are used.
+	Fields []PseudonymizationFieldConfig `config:"fields"`
+}
+
+// PseudonymizationFieldConfig holds the policy for pseudonymizing a field.
+type PseudonymizationFieldConfig struct {
+	Field string `config:"field" validate:"required"`
+
+	// Method holds the pseudonymization method, "hash", "truncate",
+	// or "remove".
+	Method string `config:"method"`
+
+	// IPv4Prefix and IPv6Prefix hold the number of leading bits of IP
+	// addresses kept by the "truncate" method.
+	IPv4Prefix int `config:"ipv4_prefix"`
+	IPv6Prefix int `config:"ipv6_prefix"`
+}
+
+func (c *PseudonymizationConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	for _, rule := range c.Rules() {
+		if rule.Method == pseudonymize.MethodHash && c.Salt == "" {
+			return errors.Errorf("salt must be specified to hash field %q", rule.Field)
+		}
+	}
+	return nil
+}
+
+// Rules returns the pseudonymization rules for the configured fields.
+func (c *PseudonymizationConfig) Rules() []pseudonymize.Rule {
+	if len(c.Fields) == 0 {
+		return pseudonymize.DefaultRules()
+	}
+	rules := make([]pseudonymize.Rule, len(c.Fields))
+	for i, field := range c.Fields {
+		rules[i] = pseudonymize.Rule{
+			Field:      field.Field,
+			Method:     pseudonymize.Method(field.Method),
+			IPv4Prefix: field.IPv4Prefix,
+			IPv6Prefix: field.IPv6Prefix,
+		}
+	}
+	return rules
+}
+
+func (c *PseudonymizationFieldConfig) Unpack(in *common.Config) error {
+	type pseudonymizationFieldConfig PseudonymizationFieldConfig
+	cfg := pseudonymizationFieldConfig{
+		Method:     string(pseudonymize.MethodHash),
+		IPv4Prefix: 24,
+		IPv6Prefix: 48,
+	}
+	if err := in.Unpack(&cfg); err != nil {
+		return errors.Wrap(err, "error unpacking pseudonymization field config")
+	}
+	*c = PseudonymizationFieldConfig(cfg)
+	return c.Validate()
+}
+
+func (c *PseudonymizationFieldConfig) Validate() error {
+	switch pseudonymize.Method(c.Method) {
+	case pseudonymize.MethodHash, pseudonymize.MethodTruncate, pseudonymize.MethodRemove:
+	default:
+		return errors.Errorf("invalid method %q for field %q, expected %q, %q, or %q",
+			c.Method, c.Field, pseudonymize.MethodHash, pseudonymize.MethodTruncate, pseudonymize.MethodRemove)
+	}
+	switch c.Field {
+	case "client.ip", "source.ip":
+		// Client IP addresses are pseudonymized before events are sampled
+		// or aggregated, where they must remain IP addresses.
+		if pseudonymize.Method(c.Method) == pseudonymize.MethodHash {
+			return errors.Errorf("field %q cannot be hashed, expected method %q or %q",
+				c.Field, pseudonymize.MethodTruncate, pseudonymize.MethodRemove)
+		}
+	}
+	if c.IPv4Prefix < 0 || c.IPv4Prefix > 32 {
+		return errors.Errorf("ipv4_prefix of field %q must be between 0 and 32", c.Field)
+	}
+	if c.IPv6Prefix < 0 || c.IPv6Prefix > 128 {
+		return errors.Errorf("ipv6_prefix of field %q must be between 0 and 128", c.Field)
+	}
+	return nil
+}
+
+func defaultPseudonymizationConfig() PseudonymizationConfig {
+	return PseudonymizationConfig{}
+}
@@ -0,0 +1,102 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/pseudonymize"
+)
+
+func TestPseudonymizationConfigInvalid(t *testing.T) {
+	for name, test := range map[string]struct {
+		pseudonymization map[string]interface{}
+		err              string
+	}{
+		"no salt for default fields": {
+			pseudonymization: map[string]interface{}{"enabled": true},
+			err:              `salt must be specified to hash field "user.id"`,
+		},
+		"no salt for hashed field": {
+			pseudonymization: map[string]interface{}{
+				"enabled": true,
+				"fields":  []map[string]interface{}{{"field": "user.name"}},
+			},
+			err: `salt must be specified to hash field "user.name"`,
+		},
+		"invalid method": {
+			pseudonymization: map[string]interface{}{
+				"fields": []map[string]interface{}{{"field": "user.id", "method": "encrypt"}},
+			},
+			err: `invalid method "encrypt"`,
+		},
+		"invalid prefix": {
+			pseudonymization: map[string]interface{}{
+				"fields": []map[string]interface{}{{"field": "client.ip", "method": "truncate", "ipv4_prefix": 33}},
+			},
+			err: "ipv4_prefix of field \"client.ip\" must be between 0 and 32",
+		},
+		"hashed client IP": {
+			pseudonymization: map[string]interface{}{
+				"salt":   "secret",
+				"fields": []map[string]interface{}{{"field": "source.ip"}},
+			},
+			err: `field "source.ip" cannot be hashed, expected method "truncate" or "remove"`,
+		},
+		"no field": {
+			pseudonymization: map[string]interface{}{
+				"fields": []map[string]interface{}{{"method": "truncate"}},
+			},
+			err: "string value is not set",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"pseudonymization": test.pseudonymization,
+			}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestPseudonymizationConfigRules(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"pseudonymization": map[string]interface{}{
+			"enabled": true,
+			"fields": []map[string]interface{}{
+				{"field": "client.ip", "method": "truncate", "ipv6_prefix": 64},
+			},
+		},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []pseudonymize.Rule{
+		{Field: "client.ip", Method: pseudonymize.MethodTruncate, IPv4Prefix: 24, IPv6Prefix: 64},
+	}, cfg.Pseudonymization.Rules())
+
+	cfg, err = NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"pseudonymization": map[string]interface{}{"enabled": true, "salt": "secret"},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, pseudonymize.DefaultRules(), cfg.Pseudonymization.Rules())
+}
@@ -0,0 +1,37 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+// ResponseCompressionConfig holds configuration for gzip-compressing the
+// JSON responses of the agent configuration and server information
+// endpoints, for clients which accept gzip encoding.
+type ResponseCompressionConfig struct {
+	// Enabled controls whether responses are compressed.
+	Enabled bool `config:"enabled"`
+
+	// MinSize holds the minimum size in bytes of response bodies
+	// to compress. Smaller responses are sent uncompressed.
+	MinSize int `config:"min_size" validate:"min=1"`
+}
+
+func defaultResponseCompressionConfig() ResponseCompressionConfig {
+	return ResponseCompressionConfig{
+		Enabled: true,
+		MinSize: 1024,
+	}
+}
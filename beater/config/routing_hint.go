@@ -0,0 +1,51 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"github.com/pkg/errors"
+)
+
+// RoutingHintConfig holds configuration for emitting a routing hint in
+// intake responses, derived from the trace IDs of the request's events,
+// so load balancers can route the events of a trace to the same server.
+type RoutingHintConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Header holds the name of the response header in which the routing
+	// hint is sent. If empty, no header is sent.
+	Header string `config:"header"`
+
+	// Cookie holds the name of the cookie in which the routing hint is
+	// set. If empty, no cookie is set.
+	Cookie string `config:"cookie"`
+}
+
+func (c *RoutingHintConfig) Validate() error {
+	if c.Enabled && c.Header == "" && c.Cookie == "" {
+		return errors.New("routing_hint requires a header or a cookie")
+	}
+	return nil
+}
+
+func defaultRoutingHintConfig() RoutingHintConfig {
+	return RoutingHintConfig{
+		Enabled: false,
+		Header:  "Elastic-Apm-Routing-Hint",
+	}
+}
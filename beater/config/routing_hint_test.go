@@ -0,0 +1,41 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestRoutingHintConfig(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"routing_hint": map[string]interface{}{"enabled": true, "header": "", "cookie": "apm_route"},
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, RoutingHintConfig{Enabled: true, Cookie: "apm_route"}, cfg.RoutingHint)
+
+	_, err = NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"routing_hint": map[string]interface{}{"enabled": true, "header": ""},
+	}), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "routing_hint requires a header or a cookie")
+}
@@ -24,9 +24,12 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/common/cfgtype"
 	"github.com/elastic/beats/v7/libbeat/logp"
 
 	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/httpproxy"
+	"github.com/elastic/apm-server/sourcemap"
 )
 
 const (
@@ -37,6 +40,24 @@ const (
 	defaultLibraryPattern           = "node_modules|bower_components|~"
 	defaultSourcemapCacheExpiration = 5 * time.Minute
 	defaultSourcemapIndexPattern    = "apm-*-sourcemap*"
+	defaultExternalSourcemapTimeout = 10 * time.Second
+	defaultExternalSourcemapMaxSize = 50 * 1024 * 1024
+	defaultExternalSourcemapMaxReqs = 10
+	defaultErrorSamplingMaxPerKey   = 100
+	defaultErrorSamplingInterval    = time.Minute
+	defaultErrorSamplingMaxGroups   = 10000
+
+	// defaultBotUserAgentPattern matches the User-Agent of common crawlers,
+	// headless browsers, and synthetic monitoring tools. "bot" is only matched
+	// as a word, or at the end of a product name like "Googlebot/2.1", so that
+	// device names like "CUBOT" are not classified as bots.
+	defaultBotUserAgentPattern = `(?i)(?:bot|crawler|spider)(?:/|-|$)|\bbot\b|crawl|slurp|headless|phantomjs|lighthouse|pagespeed|pingdom|gtmetrix|selenium|puppeteer|playwright|synthetics`
+
+	// BotTrafficActionTag tags events from bots with user_agent.bot.
+	BotTrafficActionTag = "tag"
+
+	// BotTrafficActionDrop drops events from bots.
+	BotTrafficActionDrop = "drop"
 )
 
 // RumConfig holds config information related to the RUM endpoint
@@ -50,6 +71,8 @@ type RumConfig struct {
 	LibraryPattern      string              `config:"library_pattern"`
 	ExcludeFromGrouping string              `config:"exclude_from_grouping"`
 	SourceMapping       *SourceMapping      `config:"source_mapping"`
+	BotTraffic          BotTrafficConfig    `config:"bot_traffic"`
+	ErrorSampling       ErrorSamplingConfig `config:"error_sampling"`
 }
 
 // EventRate holds config information about event rate limiting
@@ -58,13 +81,133 @@ type EventRate struct {
 	LruSize int `config:"lru_size"`
 }
 
+// BotTrafficConfig holds config information about classifying RUM events
+// sent from bots, headless browsers, and synthetic monitoring tools.
+type BotTrafficConfig struct {
+	Enabled          bool   `config:"enabled"`
+	Action           string `config:"action"`
+	UserAgentPattern string `config:"user_agent_pattern"`
+}
+
+// Validate validates the bot traffic config.
+func (c *BotTrafficConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Action {
+	case BotTrafficActionTag, BotTrafficActionDrop:
+	default:
+		return errors.Errorf("invalid action %q, expected %q or %q", c.Action, BotTrafficActionTag, BotTrafficActionDrop)
+	}
+	if _, err := regexp.Compile(c.UserAgentPattern); err != nil {
+		return errors.Wrapf(err, "Invalid regex for `user_agent_pattern`: ")
+	}
+	return nil
+}
+
+// ErrorSamplingConfig holds config information about sampling RUM errors,
+// keeping at most MaxPerKey errors per service and grouping key in each
+// Interval, so that a single broken release cannot flood the pipeline with
+// identical errors.
+type ErrorSamplingConfig struct {
+	Enabled   bool          `config:"enabled"`
+	MaxPerKey int           `config:"max_per_key"`
+	Interval  time.Duration `config:"interval"`
+
+	// MaxGroups limits the number of service and grouping key pairs
+	// tracked in each interval. Errors of further pairs are kept.
+	MaxGroups int `config:"max_groups"`
+}
+
+// Validate validates the error sampling config.
+func (c *ErrorSamplingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.MaxPerKey <= 0 {
+		return errors.New("max_per_key must be greater than zero")
+	}
+	if c.Interval <= 0 {
+		return errors.New("interval must be greater than zero")
+	}
+	if c.MaxGroups <= 0 {
+		return errors.New("max_groups must be greater than zero")
+	}
+	return nil
+}
+
 // SourceMapping holds sourecemap config information
 type SourceMapping struct {
-	Cache        *Cache                `config:"cache"`
-	Enabled      *bool                 `config:"enabled"`
-	IndexPattern string                `config:"index_pattern"`
-	ESConfig     *elasticsearch.Config `config:"elasticsearch"`
+	Cache        *Cache                    `config:"cache"`
+	Enabled      *bool                     `config:"enabled"`
+	IndexPattern string                    `config:"index_pattern"`
+	ESConfig     *elasticsearch.Config     `config:"elasticsearch"`
+	External     []ExternalSourcemapConfig `config:"external"`
 	esConfigured bool
+
+	// VersionSelection determines which of the uploaded versions of a
+	// sourcemap is applied: "latest", or "upload_time" for the version
+	// uploaded most recently before the event occurred.
+	VersionSelection string `config:"version_selection"`
+}
+
+// ExternalSourcemapConfig holds config information about fetching source
+// maps of a service from an external artifact store, for bundles without
+// an uploaded source map.
+type ExternalSourcemapConfig struct {
+	ServiceName string `config:"service.name" validate:"required"`
+
+	// ServiceVersion, if non-empty, restricts the source to the given
+	// service version.
+	ServiceVersion string `config:"service.version"`
+
+	// URL holds the source map URL template, with the scheme http, https,
+	// s3, or gs. See sourcemap.ExternalSource for supported placeholders.
+	URL string `config:"url" validate:"required"`
+
+	Headers map[string]string `config:"headers"`
+	Timeout time.Duration     `config:"timeout"`
+
+	// MaxSize holds the maximum size of a fetched source map.
+	MaxSize cfgtype.ByteSize `config:"max_size"`
+
+	// MaxConcurrentRequests holds the maximum number of concurrent
+	// requests to the source.
+	MaxConcurrentRequests int `config:"max_concurrent_requests"`
+
+	// Proxy holds the proxy configuration for requests to the source.
+	Proxy httpproxy.Config `config:",inline"`
+}
+
+func (c *ExternalSourcemapConfig) Unpack(in *common.Config) error {
+	type externalSourcemapConfig ExternalSourcemapConfig
+	cfg := externalSourcemapConfig{
+		Timeout:               defaultExternalSourcemapTimeout,
+		MaxSize:               defaultExternalSourcemapMaxSize,
+		MaxConcurrentRequests: defaultExternalSourcemapMaxReqs,
+	}
+	if err := in.Unpack(&cfg); err != nil {
+		return errors.Wrap(err, "error unpacking external sourcemap config")
+	}
+	*c = ExternalSourcemapConfig(cfg)
+	return c.Validate()
+}
+
+// Validate validates the external sourcemap config.
+func (c *ExternalSourcemapConfig) Validate() error {
+	if err := sourcemap.ValidateURL(c.URL); err != nil {
+		return errors.Wrapf(err, "invalid url %q", c.URL)
+	}
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be greater than zero")
+	}
+	if c.MaxSize <= 0 {
+		return errors.New("max_size must be greater than zero")
+	}
+	if c.MaxConcurrentRequests <= 0 {
+		return errors.New("max_concurrent_requests must be greater than zero")
+	}
+	return c.Proxy.Validate()
 }
 
 // IsEnabled indicates whether RUM endpoint is enabled or not
@@ -127,14 +270,21 @@ func (s *SourceMapping) Unpack(inp *common.Config) error {
 	if inp.HasField("elasticsearch") {
 		s.esConfigured = true
 	}
+	switch sourcemap.VersionSelection(s.VersionSelection) {
+	case sourcemap.VersionSelectionLatest, sourcemap.VersionSelectionUploadTime:
+	default:
+		return errors.Errorf("invalid version_selection %q, expected %q or %q", s.VersionSelection,
+			sourcemap.VersionSelectionLatest, sourcemap.VersionSelectionUploadTime)
+	}
 	return nil
 }
 
 func defaultSourcemapping() *SourceMapping {
 	return &SourceMapping{
-		Cache:        &Cache{Expiration: defaultSourcemapCacheExpiration},
-		IndexPattern: defaultSourcemapIndexPattern,
-		ESConfig:     elasticsearch.DefaultConfig(),
+		Cache:            &Cache{Expiration: defaultSourcemapCacheExpiration},
+		IndexPattern:     defaultSourcemapIndexPattern,
+		ESConfig:         elasticsearch.DefaultConfig(),
+		VersionSelection: string(sourcemap.VersionSelectionLatest),
 	}
 }
 
@@ -149,5 +299,14 @@ func defaultRum() *RumConfig {
 		SourceMapping:       defaultSourcemapping(),
 		LibraryPattern:      defaultLibraryPattern,
 		ExcludeFromGrouping: defaultExcludeFromGrouping,
+		BotTraffic: BotTrafficConfig{
+			Action:           BotTrafficActionTag,
+			UserAgentPattern: defaultBotUserAgentPattern,
+		},
+		ErrorSampling: ErrorSamplingConfig{
+			MaxPerKey: defaultErrorSamplingMaxPerKey,
+			Interval:  defaultErrorSamplingInterval,
+			MaxGroups: defaultErrorSamplingMaxGroups,
+		},
 	}
 }
@@ -18,7 +18,9 @@
 package config
 
 import (
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -63,3 +65,128 @@ func TestDefaultRum(t *testing.T) {
 	c := DefaultConfig()
 	assert.Equal(t, defaultRum(), c.RumConfig)
 }
+
+func TestBotTrafficConfigValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg BotTrafficConfig
+		err string
+	}{
+		"disabled": {cfg: BotTrafficConfig{Action: "invalid"}},
+		"default":  {cfg: BotTrafficConfig{Enabled: true, Action: "tag", UserAgentPattern: defaultBotUserAgentPattern}},
+		"drop":     {cfg: BotTrafficConfig{Enabled: true, Action: "drop", UserAgentPattern: "bot"}},
+		"invalid_action": {
+			cfg: BotTrafficConfig{Enabled: true, Action: "ignore", UserAgentPattern: "bot"},
+			err: `invalid action "ignore", expected "tag" or "drop"`,
+		},
+		"invalid_pattern": {
+			cfg: BotTrafficConfig{Enabled: true, Action: "tag", UserAgentPattern: "(bot"},
+			err: "Invalid regex for `user_agent_pattern`: : error parsing regexp: missing closing ): `(bot`",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestDefaultBotUserAgentPattern(t *testing.T) {
+	pattern := regexp.MustCompile(defaultBotUserAgentPattern)
+	for _, ua := range []string{
+		"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)",
+		"Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+		"Mozilla/5.0 (compatible; Baiduspider/2.0; +http://www.baidu.com/search/spider.html)",
+		"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/90.0.4430.0 Safari/537.36",
+		"Mozilla/5.0 (compatible; Yahoo! Slurp; http://help.yahoo.com/help/us/ysearch/slurp)",
+		"Twitterbot",
+	} {
+		assert.True(t, pattern.MatchString(ua), ua)
+	}
+	for _, ua := range []string{
+		"Mozilla/5.0 (Linux; Android 9; CUBOT X19) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.91 Mobile Safari/537.36",
+		"Mozilla/5.0 (Linux; Android 10; CUBOT_P40 Build/QP1A.190711.020) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/89.0.4389.105 Mobile Safari/537.36",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.93 Safari/537.36",
+	} {
+		assert.False(t, pattern.MatchString(ua), ua)
+	}
+}
+
+func TestErrorSamplingConfigValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg ErrorSamplingConfig
+		err string
+	}{
+		"disabled": {cfg: ErrorSamplingConfig{}},
+		"valid":    {cfg: ErrorSamplingConfig{Enabled: true, MaxPerKey: 1, Interval: time.Second, MaxGroups: 1}},
+		"invalid_max_per_key": {
+			cfg: ErrorSamplingConfig{Enabled: true, Interval: time.Second, MaxGroups: 1},
+			err: "max_per_key must be greater than zero",
+		},
+		"invalid_interval": {
+			cfg: ErrorSamplingConfig{Enabled: true, MaxPerKey: 1, MaxGroups: 1},
+			err: "interval must be greater than zero",
+		},
+		"invalid_max_groups": {
+			cfg: ErrorSamplingConfig{Enabled: true, MaxPerKey: 1, Interval: time.Second},
+			err: "max_groups must be greater than zero",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestExternalSourcemapConfigInvalid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg map[string]interface{}
+		err string
+	}{
+		"missing_service_name": {
+			cfg: map[string]interface{}{"url": "https://example.com/{bundle_filepath}.map"},
+			err: "string value is not set",
+		},
+		"missing_url": {
+			cfg: map[string]interface{}{"service.name": "opbeans-rum"},
+			err: "string value is not set",
+		},
+		"unsupported_scheme": {
+			cfg: map[string]interface{}{"service.name": "opbeans-rum", "url": "ftp://example.com/{bundle_filepath}.map"},
+			err: `invalid url "ftp://example.com/{bundle_filepath}.map": unsupported scheme "ftp", expected http, https, s3, or gs`,
+		},
+		"invalid_timeout": {
+			cfg: map[string]interface{}{"service.name": "opbeans-rum", "url": "gs://bucket/{bundle_filepath}.map", "timeout": "0s"},
+			err: "timeout must be greater than zero",
+		},
+		"invalid_proxy": {
+			cfg: map[string]interface{}{"service.name": "opbeans-rum", "url": "gs://bucket/{bundle_filepath}.map", "proxy_url": "ftp://proxy"},
+			err: `unsupported proxy URL scheme "ftp", expected http, https, or socks5`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+				"rum.source_mapping.external": []map[string]interface{}{tc.cfg},
+			}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestSourceMappingVersionSelectionInvalid(t *testing.T) {
+	_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"rum.source_mapping.version_selection": "oldest",
+	}), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid version_selection "oldest", expected "latest" or "upload_time"`)
+}
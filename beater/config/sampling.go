@@ -24,6 +24,7 @@ import (
 
 	"github.com/elastic/apm-server/elasticsearch"
 	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/common/cfgtype"
 	"github.com/elastic/beats/v7/libbeat/logp"
 )
 
@@ -35,6 +36,53 @@ type SamplingConfig struct {
 
 	// Tail holds tail-sampling configuration.
 	Tail *TailSamplingConfig `config:"tail"`
+
+	// Head holds configuration for server-assisted head-sampling.
+	Head HeadSamplingConfig `config:"head"`
+}
+
+// HeadSamplingConfig holds configuration related to server-assisted
+// head-sampling, where agents request a sampling decision for new traces.
+type HeadSamplingConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// Policies holds head-sampling policies. The first policy matching
+	// a trace determines its sample rate. Traces not matching any policy
+	// are sampled.
+	Policies []HeadSamplingPolicy `config:"policies"`
+}
+
+// HeadSamplingPolicy holds a head-sampling policy.
+type HeadSamplingPolicy struct {
+	// Service holds attributes of the service which this policy matches.
+	Service struct {
+		Name        string `config:"name"`
+		Environment string `config:"environment"`
+	} `config:"service"`
+
+	// Trace holds attributes of the trace which this policy matches.
+	Trace struct {
+		Name string `config:"name"`
+	} `config:"trace"`
+
+	// SampleRate holds the sample rate applied for this policy.
+	// Defaults to 1, sampling all matching traces.
+	SampleRate float64 `config:"sample_rate" validate:"min=0, max=1"`
+
+	// MaxTracesPerSecond holds the budget of traces sampled per second
+	// for each service matching this policy. Once exhausted, further
+	// traces are not sampled. If zero, the budget is unlimited.
+	MaxTracesPerSecond float64 `config:"max_traces_per_second" validate:"min=0"`
+}
+
+func (p *HeadSamplingPolicy) Unpack(in *common.Config) error {
+	type headSamplingPolicy HeadSamplingPolicy
+	cfg := headSamplingPolicy{SampleRate: 1}
+	if err := in.Unpack(&cfg); err != nil {
+		return errors.Wrap(err, "error unpacking head sampling policy")
+	}
+	*p = HeadSamplingPolicy(cfg)
+	return nil
 }
 
 // TailSamplingConfig holds configuration related to tail-sampling.
@@ -54,6 +102,12 @@ type TailSamplingConfig struct {
 	StorageGCInterval     time.Duration         `config:"storage_gc_interval" validate:"min=1s"`
 	TTL                   time.Duration         `config:"ttl" validate:"min=1s"`
 
+	// StorageLimit holds the maximum size of local tail-sampling storage.
+	// Once reached, trace events are indexed without waiting for a sampling
+	// decision, unless their root transaction was rejected by the sampling
+	// reservoir. If zero, storage is unlimited.
+	StorageLimit cfgtype.ByteSize `config:"storage_limit"`
+
 	esConfigured bool
 }
 
@@ -67,8 +121,9 @@ type TailSamplingPolicy struct {
 
 	// Trace holds attributes of the trace which this policy matches.
 	Trace struct {
-		Name    string `config:"name"`
-		Outcome string `config:"outcome"`
+		Name        string        `config:"name"`
+		Outcome     string        `config:"outcome"`
+		MinDuration time.Duration `config:"min_duration" validate:"min=0"`
 	} `config:"trace"`
 
 	// SampleRate holds the sample rate applied for this policy.
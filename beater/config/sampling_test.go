@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/beats/v7/libbeat/common"
 )
@@ -50,3 +51,16 @@ func TestSamplingPoliciesValidation(t *testing.T) {
 		assert.EqualError(t, err, "Error processing configuration: invalid tail sampling config: no default (empty criteria) policy specified accessing 'sampling.tail'")
 	})
 }
+
+func TestHeadSamplingPolicyDefaultSampleRate(t *testing.T) {
+	cfg, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{
+		"sampling.head.policies": []map[string]interface{}{
+			{"service.name": "opbeans", "max_traces_per_second": 10},
+			{"service.name": "opbeans-rum", "sample_rate": 0},
+		},
+	}), nil)
+	require.NoError(t, err)
+	require.Len(t, cfg.Sampling.Head.Policies, 2)
+	assert.Equal(t, 1.0, cfg.Sampling.Head.Policies[0].SampleRate)
+	assert.Equal(t, 0.0, cfg.Sampling.Head.Policies[1].SampleRate)
+}
@@ -0,0 +1,80 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SideLookupsConfig holds configuration for the worker pool in which
+// lookups against external dependencies are performed while enriching
+// events, such as fetching source maps.
+type SideLookupsConfig struct {
+	// Enabled controls whether lookups are performed in the worker pool.
+	// If disabled, lookups are performed inline, without limits.
+	Enabled bool `config:"enabled"`
+
+	// Workers holds the maximum number of concurrent lookups.
+	Workers int `config:"workers" validate:"min=1"`
+
+	// QueueSize holds the maximum number of lookups waiting for a worker.
+	// Further lookups are skipped.
+	QueueSize int `config:"queue_size" validate:"min=0"`
+
+	// Timeout holds the maximum duration of a lookup.
+	Timeout time.Duration `config:"timeout"`
+
+	// CircuitBreaker holds configuration for skipping lookups while
+	// the dependency is failing.
+	CircuitBreaker SideLookupsCircuitBreakerConfig `config:"circuit_breaker"`
+}
+
+// SideLookupsCircuitBreakerConfig holds configuration for the circuit
+// breaker of the side lookups worker pool.
+type SideLookupsCircuitBreakerConfig struct {
+	// Failures holds the number of consecutive failed lookups after
+	// which lookups are skipped. If zero, lookups are never skipped.
+	Failures int `config:"failures" validate:"min=0"`
+
+	// Cooldown holds the duration for which lookups are skipped.
+	Cooldown time.Duration `config:"cooldown"`
+}
+
+func (c *SideLookupsConfig) Validate() error {
+	if c.Timeout <= 0 {
+		return errors.New("side_lookups timeout must be greater than zero")
+	}
+	if c.CircuitBreaker.Failures > 0 && c.CircuitBreaker.Cooldown <= 0 {
+		return errors.New("side_lookups circuit_breaker cooldown must be greater than zero")
+	}
+	return nil
+}
+
+func defaultSideLookupsConfig() SideLookupsConfig {
+	return SideLookupsConfig{
+		Workers:   10,
+		QueueSize: 100,
+		Timeout:   time.Second,
+		CircuitBreaker: SideLookupsCircuitBreakerConfig{
+			Failures: 5,
+			Cooldown: 30 * time.Second,
+		},
+	}
+}
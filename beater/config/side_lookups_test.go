@@ -0,0 +1,57 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestSideLookupsConfigInvalid(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg map[string]interface{}
+		err string
+	}{
+		"workers": {
+			cfg: map[string]interface{}{"workers": 0},
+			err: "requires value < 1 accessing 'side_lookups.workers'",
+		},
+		"queue_size": {
+			cfg: map[string]interface{}{"queue_size": -1},
+			err: "requires value < 0 accessing 'side_lookups.queue_size'",
+		},
+		"timeout": {
+			cfg: map[string]interface{}{"timeout": "0s"},
+			err: "side_lookups timeout must be greater than zero",
+		},
+		"cooldown": {
+			cfg: map[string]interface{}{"circuit_breaker.cooldown": "0s"},
+			err: "side_lookups circuit_breaker cooldown must be greater than zero",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewConfig(common.MustNewConfigFrom(map[string]interface{}{"side_lookups": tc.cfg}), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+const (
+	defaultSLOInterval          = time.Minute
+	defaultSLOLatencyPercentile = 95
+)
+
+// SLOConfig holds configuration related to the evaluation of
+// per-service service level objectives.
+type SLOConfig struct {
+	// Enabled controls whether service level objectives are evaluated.
+	Enabled bool `config:"enabled"`
+
+	// Interval holds the interval at which service level objectives
+	// are evaluated, and violation documents are published.
+	Interval time.Duration `config:"interval" validate:"min=1"`
+
+	// Services holds the service level objectives of individual services.
+	Services []SLOServiceConfig `config:"services"`
+}
+
+// SLOServiceConfig holds the service level objectives for a service.
+type SLOServiceConfig struct {
+	// Name holds the service name to match.
+	Name string `config:"name" validate:"required"`
+
+	// Environment, if non-empty, holds the service environment to match.
+	Environment string `config:"environment"`
+
+	// MaxFailureRate holds the maximum fraction of failed transactions
+	// within an interval. If MaxFailureRate is zero, the failure rate
+	// is not evaluated.
+	MaxFailureRate float64 `config:"max_failure_rate" validate:"min=0, max=1"`
+
+	// Latency holds the latency objective of the service.
+	Latency SLOLatencyConfig `config:"latency"`
+}
+
+// SLOLatencyConfig holds a service's latency objective.
+type SLOLatencyConfig struct {
+	// Threshold holds the maximum transaction duration at Percentile.
+	// If Threshold is zero, latency is not evaluated.
+	Threshold time.Duration `config:"threshold" validate:"min=0"`
+
+	// Percentile holds the transaction duration percentile to compare
+	// against Threshold.
+	Percentile float64 `config:"percentile" validate:"min=0, max=100"`
+}
+
+func (c *SLOServiceConfig) Unpack(in *common.Config) error {
+	type sloServiceConfig SLOServiceConfig
+	cfg := sloServiceConfig{
+		Latency: SLOLatencyConfig{Percentile: defaultSLOLatencyPercentile},
+	}
+	if err := in.Unpack(&cfg); err != nil {
+		return errors.Wrap(err, "error unpacking service level objective config")
+	}
+	*c = SLOServiceConfig(cfg)
+	return nil
+}
+
+func defaultSLOConfig() SLOConfig {
+	return SLOConfig{
+		Enabled:  false,
+		Interval: defaultSLOInterval,
+	}
+}
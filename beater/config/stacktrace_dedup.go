@@ -0,0 +1,31 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+// StacktraceDedupConfig holds configuration for deduplicating stacktraces
+// within an error's exception chain.
+type StacktraceDedupConfig struct {
+	// Enabled controls whether the stacktrace of an exception which is
+	// identical to that of an earlier exception in the same chain is
+	// replaced by `stacktrace_ref`, the position of that exception.
+	Enabled bool `config:"enabled"`
+}
+
+func defaultStacktraceDedupConfig() StacktraceDedupConfig {
+	return StacktraceDedupConfig{Enabled: false}
+}
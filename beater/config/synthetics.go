@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+const (
+	defaultSyntheticMonitorInterval       = 30 * time.Second
+	defaultSyntheticMonitorTimeout        = 10 * time.Second
+	defaultSyntheticMonitorExpectedStatus = http.StatusOK
+)
+
+// SyntheticsConfig holds configuration related to synthetic monitors,
+// which periodically probe URLs and record the results as transactions.
+type SyntheticsConfig struct {
+	// Enabled controls whether synthetic monitors are run.
+	Enabled bool `config:"enabled"`
+
+	// Monitors holds the synthetic monitors to run.
+	Monitors []SyntheticMonitorConfig `config:"monitors"`
+}
+
+// SyntheticMonitorConfig holds configuration for a synthetic monitor.
+type SyntheticMonitorConfig struct {
+	// URL holds the URL to probe with an HTTP GET request.
+	URL string `config:"url" validate:"required"`
+
+	// Service holds the service to which the monitor's transactions belong.
+	Service SyntheticMonitorServiceConfig `config:"service"`
+
+	// Interval holds the interval at which the URL is probed.
+	Interval time.Duration `config:"interval" validate:"min=1"`
+
+	// Timeout holds the maximum duration of a probe.
+	Timeout time.Duration `config:"timeout" validate:"min=1"`
+
+	// ExpectedStatus holds the HTTP status code of a successful probe.
+	ExpectedStatus int `config:"expected_status" validate:"min=100, max=599"`
+}
+
+// SyntheticMonitorServiceConfig holds the service name and environment
+// of a synthetic monitor.
+type SyntheticMonitorServiceConfig struct {
+	Name        string `config:"name" validate:"required"`
+	Environment string `config:"environment"`
+}
+
+func (c *SyntheticMonitorConfig) Unpack(in *common.Config) error {
+	type syntheticMonitorConfig SyntheticMonitorConfig
+	cfg := syntheticMonitorConfig{
+		Interval:       defaultSyntheticMonitorInterval,
+		Timeout:        defaultSyntheticMonitorTimeout,
+		ExpectedStatus: defaultSyntheticMonitorExpectedStatus,
+	}
+	if err := in.Unpack(&cfg); err != nil {
+		return errors.Wrap(err, "error unpacking synthetic monitor config")
+	}
+	*c = SyntheticMonitorConfig(cfg)
+	return nil
+}
+
+func defaultSyntheticsConfig() SyntheticsConfig {
+	return SyntheticsConfig{Enabled: false}
+}
@@ -0,0 +1,73 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// TransactionResultConfig holds configuration for normalizing transaction.result
+// values, so that agents reporting the same result differently, e.g. "200" and
+// "HTTP 2xx", do not fragment charts and aggregations.
+type TransactionResultConfig struct {
+	Enabled bool `config:"enabled"`
+
+	// HTTP enables normalizing HTTP status codes to their class,
+	// e.g. "HTTP 200" to "HTTP 2xx".
+	HTTP bool `config:"http"`
+
+	// GRPC enables normalizing gRPC status codes to their names,
+	// e.g. "14" to "UNAVAILABLE".
+	GRPC bool `config:"grpc"`
+
+	// Rules holds custom normalization rules, applied in order
+	// before the built-in HTTP and gRPC normalizations.
+	Rules []TransactionResultRule `config:"rules"`
+}
+
+// TransactionResultRule holds a custom rule for normalizing transaction.result values.
+type TransactionResultRule struct {
+	// Match holds a regular expression matching the results to normalize.
+	Match string `config:"match"`
+
+	// Result holds the normalized result, which may refer to submatches
+	// of Match, e.g. "${1}".
+	Result string `config:"result"`
+}
+
+func (r *TransactionResultRule) Validate() error {
+	if r.Match == "" {
+		return errors.New("match must be specified")
+	}
+	if _, err := regexp.Compile(r.Match); err != nil {
+		return errors.Wrapf(err, "invalid match %q", r.Match)
+	}
+	if r.Result == "" {
+		return errors.New("result must be specified")
+	}
+	return nil
+}
+
+func defaultTransactionResultConfig() TransactionResultConfig {
+	return TransactionResultConfig{
+		HTTP: true,
+		GRPC: true,
+	}
+}
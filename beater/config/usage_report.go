@@ -0,0 +1,54 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// UsageReportConfig holds configuration for periodically publishing
+// per-service intake usage documents.
+type UsageReportConfig struct {
+	// Enabled controls whether usage documents are published.
+	Enabled bool `config:"enabled"`
+
+	// Interval holds the interval at which usage documents are published.
+	// Each document holds the usage of a service within the interval.
+	Interval time.Duration `config:"interval"`
+
+	// MaxServices holds the maximum number of services tracked within
+	// each interval. Usage of additional services is attributed to no
+	// service.
+	MaxServices int `config:"max_services" validate:"min=1"`
+}
+
+func (c *UsageReportConfig) Validate() error {
+	if c.Interval < time.Second {
+		return errors.New("usage report interval must be at least 1s")
+	}
+	return nil
+}
+
+func defaultUsageReportConfig() UsageReportConfig {
+	return UsageReportConfig{
+		Interval:    time.Minute,
+		MaxServices: 1000,
+	}
+}
@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"time"
+)
+
+const (
+	defaultWarmupTimeout      = 30 * time.Second
+	defaultWarmupServicesPath = "warmup_services.json"
+	defaultWarmupMaxServices  = 1000
+)
+
+// WarmupConfig holds configuration related to warming up the server
+// on startup, before it starts accepting requests.
+type WarmupConfig struct {
+	// Enabled controls whether the server is warmed up on startup.
+	Enabled bool `config:"enabled"`
+
+	// Timeout holds the maximum amount of time to spend warming up,
+	// after which the server starts accepting requests regardless.
+	Timeout time.Duration `config:"timeout" validate:"min=1"`
+
+	// WaitForOutput controls whether the server waits for the
+	// Elasticsearch output to be reachable.
+	WaitForOutput bool `config:"wait_for_output"`
+
+	// ServicesPath holds the path of the file in which the services
+	// recently querying agent configuration are persisted, relative
+	// to the data directory. Agent configuration for these services
+	// is fetched and cached on startup.
+	ServicesPath string `config:"services_path"`
+
+	// MaxServices holds the maximum number of services persisted.
+	MaxServices int `config:"max_services" validate:"min=1"`
+}
+
+func defaultWarmupConfig() WarmupConfig {
+	return WarmupConfig{
+		Timeout:       defaultWarmupTimeout,
+		WaitForOutput: true,
+		ServicesPath:  defaultWarmupServicesPath,
+		MaxServices:   defaultWarmupMaxServices,
+	}
+}
@@ -0,0 +1,32 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+const defaultZipkinMaxRequestSize = 4 * 1024 * 1024
+
+// ZipkinConfig holds configuration for the Zipkin v2 spans endpoint
+// served on the APM Server port.
+type ZipkinConfig struct {
+	// MaxRequestSize holds the maximum size of an uncompressed JSON
+	// array of spans sent to the Zipkin endpoint.
+	MaxRequestSize int `config:"max_request_size" validate:"min=1"`
+}
+
+func defaultZipkinConfig() ZipkinConfig {
+	return ZipkinConfig{MaxRequestSize: defaultZipkinMaxRequestSize}
+}
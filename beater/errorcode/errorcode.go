@@ -0,0 +1,102 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package errorcode defines machine-readable codes identifying the kinds of
+// errors reported by the server's HTTP and gRPC endpoints and logs, so that
+// agents and automation can handle errors without parsing error messages.
+package errorcode
+
+// Code identifies a kind of error.
+type Code string
+
+const (
+	// Decode indicates that the request body could not be decoded.
+	Decode Code = "ERR_DECODE"
+
+	// Validate indicates that the request or its events are invalid.
+	Validate Code = "ERR_VALIDATE"
+
+	// TooLarge indicates that the request body or an event is too large.
+	TooLarge Code = "ERR_TOO_LARGE"
+
+	// ChecksumMismatch indicates that the request body does not match
+	// the checksum supplied with the request.
+	ChecksumMismatch Code = "ERR_CHECKSUM_MISMATCH"
+
+	// RateLimit indicates that the request was rate limited.
+	RateLimit Code = "ERR_RATE_LIMIT"
+
+	// QueueFull indicates that events could not be published
+	// because the internal queue is full.
+	QueueFull Code = "ERR_QUEUE_FULL"
+
+	// ShuttingDown indicates that the server is shutting down.
+	ShuttingDown Code = "ERR_SHUTTING_DOWN"
+
+	// MethodNotAllowed indicates that the request method is not supported.
+	MethodNotAllowed Code = "ERR_METHOD_NOT_ALLOWED"
+
+	// Unauthorized indicates that the request is missing valid credentials.
+	Unauthorized Code = "ERR_UNAUTHORIZED"
+
+	// Forbidden indicates that the request's credentials do not grant
+	// the privileges required for the request.
+	Forbidden Code = "ERR_FORBIDDEN"
+
+	// MissingCredentials indicates that the request had no credentials,
+	// or an improperly formatted Authorization header.
+	MissingCredentials Code = "ERR_MISSING_CREDENTIALS"
+
+	// UnsupportedScheme indicates that the request's Authorization
+	// header used an unknown or disabled authorization scheme.
+	UnsupportedScheme Code = "ERR_UNSUPPORTED_SCHEME"
+
+	// InvalidSecretToken indicates that the request's secret token
+	// did not match the configured secret token.
+	InvalidSecretToken Code = "ERR_INVALID_SECRET_TOKEN"
+
+	// InvalidAPIKey indicates that the request's API Key is unknown
+	// to Elasticsearch, or has been invalidated.
+	InvalidAPIKey Code = "ERR_INVALID_API_KEY"
+
+	// ExpiredAPIKey indicates that the request's API Key has expired.
+	ExpiredAPIKey Code = "ERR_EXPIRED_API_KEY"
+
+	// InsufficientPrivileges indicates that the request's API Key is
+	// valid, but lacks the privileges required for the request.
+	InsufficientPrivileges Code = "ERR_INSUFFICIENT_PRIVILEGES"
+
+	// NotFound indicates that the requested resource does not exist.
+	NotFound Code = "ERR_NOT_FOUND"
+
+	// InvalidQuery indicates that the request's query is invalid.
+	InvalidQuery Code = "ERR_INVALID_QUERY"
+
+	// Timeout indicates that the request timed out.
+	Timeout Code = "ERR_TIMEOUT"
+
+	// Unavailable indicates that a service required for handling
+	// the request is unavailable.
+	Unavailable Code = "ERR_UNAVAILABLE"
+
+	// Internal indicates an unexpected error in the server.
+	Internal Code = "ERR_INTERNAL"
+)
+
+// Domain is the domain of error codes attached to gRPC errors
+// as google.rpc.ErrorInfo details.
+const Domain = "apm-server"
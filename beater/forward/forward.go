@@ -0,0 +1,202 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package forward provides a Forwarder for relaying intake requests
+// to another APM Server.
+package forward
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common/backoff"
+	"github.com/elastic/beats/v7/libbeat/common/transport"
+	"github.com/elastic/beats/v7/libbeat/common/transport/tlscommon"
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/utility"
+)
+
+// forwardedHeaders holds the names of intake request headers which are
+// copied to forwarded requests.
+var forwardedHeaders = []string{
+	headers.UserAgent,
+	headers.Origin,
+	headers.ElasticAPMAckLevel,
+}
+
+// Forwarder forwards intake request bodies to another APM Server.
+type Forwarder struct {
+	cfg    config.ForwardConfig
+	url    *url.URL
+	auth   string
+	client *http.Client
+	logger *logp.Logger
+}
+
+// NewForwarder returns a new Forwarder with the given configuration.
+func NewForwarder(cfg config.ForwardConfig) (*Forwarder, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid forwarding url")
+	}
+	httpTransport, err := newHTTPTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var auth string
+	switch {
+	case cfg.APIKey != "":
+		auth = headers.APIKey + " " + cfg.APIKey
+	case cfg.SecretToken != "":
+		auth = headers.Bearer + " " + cfg.SecretToken
+	}
+	return &Forwarder{
+		cfg:    cfg,
+		url:    u,
+		auth:   auth,
+		client: &http.Client{Transport: httpTransport, Timeout: cfg.Timeout},
+		logger: logp.NewLogger(logs.Forward),
+	}, nil
+}
+
+func newHTTPTransport(cfg config.ForwardConfig) (*http.Transport, error) {
+	var tlsConfig *tlscommon.TLSConfig
+	if cfg.TLS.IsEnabled() {
+		var err error
+		if tlsConfig, err = tlscommon.LoadTLSConfig(cfg.TLS); err != nil {
+			return nil, err
+		}
+	}
+	dialer := transport.NetDialer(cfg.Timeout)
+	tlsDialer, err := transport.TLSDialer(dialer, tlsConfig, cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		Dial:            dialer.Dial,
+		DialTLS:         tlsDialer.Dial,
+		TLSClientConfig: tlsConfig.ToConfig(),
+	}, nil
+}
+
+// Forward forwards body, the uncompressed ndjson body of the intake request
+// r, to the same path on the configured APM Server.
+//
+// Requests which fail due to network errors or server-side errors are
+// retried with exponential backoff, up to the configured maximum number
+// of retries or until ctx is cancelled. Forward returns nil once the
+// APM Server has accepted the request.
+func (f *Forwarder) Forward(ctx context.Context, r *http.Request, body []byte) error {
+	contentEncoding := ""
+	if f.cfg.CompressionLevel > 0 {
+		var buf bytes.Buffer
+		zw, err := gzip.NewWriterLevel(&buf, f.cfg.CompressionLevel)
+		if err != nil {
+			return err
+		}
+		if _, err := zw.Write(body); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+		contentEncoding = "gzip"
+	}
+
+	u := *f.url
+	u.Path = strings.TrimSuffix(u.Path, "/") + r.URL.Path
+	u.RawQuery = r.URL.RawQuery
+	header := make(http.Header)
+	for _, k := range forwardedHeaders {
+		if v := r.Header.Get(k); v != "" {
+			header.Set(k, v)
+		}
+	}
+	header.Set(headers.ContentType, "application/x-ndjson")
+	if contentEncoding != "" {
+		header.Set(headers.ContentEncoding, contentEncoding)
+	}
+	if f.auth != "" {
+		header.Set(headers.Authorization, f.auth)
+	}
+	if ip := utility.ExtractIP(r); ip != nil {
+		header.Set(headers.XForwardedFor, ip.String())
+	}
+
+	b := backoff.NewEqualJitterBackoff(ctx.Done(), f.cfg.Backoff.Init, f.cfg.Backoff.Max)
+	for attempt := 0; ; attempt++ {
+		err := f.send(ctx, u.String(), header, body)
+		if err == nil {
+			return nil
+		}
+		if _, ok := err.(*permanentError); ok || attempt >= f.cfg.MaxRetries {
+			return err
+		}
+		f.logger.With(logp.Error(err)).Debugf("forwarding attempt %d failed, retrying", attempt+1)
+		if !b.Wait() {
+			return err
+		}
+	}
+}
+
+func (f *Forwarder) send(ctx context.Context, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err}
+	}
+	req.Header = header.Clone()
+	resp, err := f.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	switch {
+	case resp.StatusCode == http.StatusAccepted:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("forwarding failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	default:
+		return &permanentError{fmt.Errorf(
+			"forwarding failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody),
+		)}
+	}
+}
+
+// permanentError wraps an error for a request which should not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
@@ -0,0 +1,157 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package forward_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/forward"
+)
+
+func TestForward(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(zr)
+		require.NoError(t, err)
+		requests <- r
+		bodies <- string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	cfg := config.DefaultConfig().Forward
+	cfg.URL = srv.URL + "/prefix/"
+	cfg.APIKey = "abc123"
+	forwarder, err := forward.NewForwarder(cfg)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/intake/v2/rum/events?verbose", nil)
+	r.RemoteAddr = "10.1.2.3:1234"
+	r.Header.Set("User-Agent", "rum-agent")
+	r.Header.Set("Origin", "https://example.com")
+	r.Header.Set("Authorization", "Bearer not-forwarded")
+	err = forwarder.Forward(context.Background(), r, []byte(`{"metadata":{}}`))
+	require.NoError(t, err)
+
+	forwarded := <-requests
+	assert.Equal(t, "/prefix/intake/v2/rum/events", forwarded.URL.Path)
+	assert.Equal(t, "verbose", forwarded.URL.RawQuery)
+	assert.Equal(t, "application/x-ndjson", forwarded.Header.Get("Content-Type"))
+	assert.Equal(t, "gzip", forwarded.Header.Get("Content-Encoding"))
+	assert.Equal(t, "ApiKey abc123", forwarded.Header.Get("Authorization"))
+	assert.Equal(t, "rum-agent", forwarded.Header.Get("User-Agent"))
+	assert.Equal(t, "https://example.com", forwarded.Header.Get("Origin"))
+	assert.Equal(t, "10.1.2.3", forwarded.Header.Get("X-Forwarded-For"))
+	assert.Equal(t, `{"metadata":{}}`, <-bodies)
+}
+
+func TestForwardRetries(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(zr)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"metadata":{}}`, string(body))
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	// The whole body is sent again with each attempt.
+	forwarder := newForwarder(t, srv.URL, 2)
+	err := forwarder.Forward(context.Background(), httptest.NewRequest(http.MethodPost, "/", nil), []byte(`{"metadata":{}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestForwardRetriesExhausted(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	forwarder := newForwarder(t, srv.URL, 2)
+	err := forwarder.Forward(context.Background(), httptest.NewRequest(http.MethodPost, "/", nil), nil)
+	assert.EqualError(t, err, "forwarding failed with status 429: ")
+	assert.Equal(t, 3, attempts)
+}
+
+func TestForwardPermanentError(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	forwarder := newForwarder(t, srv.URL, 2)
+	err := forwarder.Forward(context.Background(), httptest.NewRequest(http.MethodPost, "/", nil), nil)
+	assert.EqualError(t, err, "forwarding failed with status 401: invalid token")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestForwardUncompressed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get("Content-Encoding") != "" || !strings.HasPrefix(string(body), "{") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	cfg := config.DefaultConfig().Forward
+	cfg.URL = srv.URL
+	cfg.CompressionLevel = 0
+	forwarder, err := forward.NewForwarder(cfg)
+	require.NoError(t, err)
+	err = forwarder.Forward(context.Background(), httptest.NewRequest(http.MethodPost, "/", nil), []byte("{}"))
+	assert.NoError(t, err)
+}
+
+func newForwarder(t testing.TB, url string, maxRetries int) *forward.Forwarder {
+	cfg := config.DefaultConfig().Forward
+	cfg.URL = url
+	cfg.MaxRetries = maxRetries
+	cfg.Backoff = config.ForwardBackoffConfig{Init: time.Millisecond, Max: time.Millisecond}
+	forwarder, err := forward.NewForwarder(cfg)
+	require.NoError(t, err)
+	return forwarder
+}
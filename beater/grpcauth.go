@@ -23,6 +23,7 @@ import (
 
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/interceptors"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -45,15 +46,54 @@ func newAuthUnaryServerInterceptor(builder *authorization.Builder) grpc.UnarySer
 		handler grpc.UnaryHandler,
 	) (resp interface{}, err error) {
 		if strings.HasPrefix(info.FullMethod, "/opentelemetry") {
-			if err := verifyGRPCAuthorization(ctx, authHandler); err != nil {
+			auth, err := verifyGRPCAuthorization(ctx, authHandler)
+			if err != nil {
 				return nil, err
 			}
+			ctx = authorization.ContextWithAuthorization(ctx, auth)
 		}
 		return handler(ctx, req)
 	}
 }
 
-func verifyGRPCAuthorization(ctx context.Context, authHandler *authorization.Handler) error {
+// newAuthStreamServerInterceptor returns a grpc.StreamServerInterceptor which
+// performs per-stream auth using "Authorization" metadata for OpenCensus methods.
+func newAuthStreamServerInterceptor(builder *authorization.Builder) grpc.StreamServerInterceptor {
+	authHandler := builder.ForPrivilege(authorization.PrivilegeEventWrite.Action)
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if strings.HasPrefix(info.FullMethod, "/opencensus") {
+			auth, err := verifyGRPCAuthorization(stream.Context(), authHandler)
+			if err != nil {
+				return err
+			}
+			stream = &authorizedServerStream{
+				ServerStream: stream,
+				ctx:          authorization.ContextWithAuthorization(stream.Context(), auth),
+			}
+		}
+		return handler(srv, stream)
+	}
+}
+
+// authorizedServerStream wraps a grpc.ServerStream,
+// overriding its context to record the authorization.
+type authorizedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// verifyGRPCAuthorization verifies the "Authorization" metadata in ctx,
+// returning the authorization if the request is authorized.
+func verifyGRPCAuthorization(ctx context.Context, authHandler *authorization.Handler) (authorization.Authorization, error) {
 	var authHeader string
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
 		if values := md.Get(headers.Authorization); len(values) > 0 {
@@ -63,14 +103,17 @@ func verifyGRPCAuthorization(ctx context.Context, authHandler *authorization.Han
 	auth := authHandler.AuthorizationFor(authorization.ParseAuthorizationHeader(authHeader))
 	result, err := auth.AuthorizedFor(ctx, authorization.ResourceInternal)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !result.Authorized {
 		message := "unauthorized"
 		if result.Reason != "" {
 			message = result.Reason
 		}
-		return status.Error(codes.Unauthenticated, message)
+		if result.Code == "" {
+			return nil, status.Error(codes.Unauthenticated, message)
+		}
+		return nil, interceptors.ErrorWithCode(status.New(codes.Unauthenticated, message), result.Code)
 	}
-	return nil
+	return auth, nil
 }
@@ -0,0 +1,104 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+var (
+	grpcHealthMonitoringMap     = request.DefaultMonitoringMapForRegistry(monitoring.Default.NewRegistry("apm-server.grpc.health"))
+	grpcReflectionMonitoringMap = request.DefaultMonitoringMapForRegistry(monitoring.Default.NewRegistry("apm-server.grpc.reflection"))
+
+	// grpcServicesMonitoringMaps provides mappings from the fully qualified
+	// gRPC method names of the health and reflection services to their
+	// respective monitoring maps.
+	grpcServicesMonitoringMaps = map[string]map[request.ResultID]*monitoring.Int{
+		"/grpc.health.v1.Health/Check":                                   grpcHealthMonitoringMap,
+		"/grpc.health.v1.Health/Watch":                                   grpcHealthMonitoringMap,
+		"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": grpcReflectionMonitoringMap,
+	}
+)
+
+// registerGRPCHealthAndReflection registers the grpc.health.v1 health service
+// with srv, reporting all services registered so far as serving, and the
+// server reflection service if enabled by cfg.
+//
+// The returned health server should be shut down before stopping srv, so
+// clients checking health stop sending new requests while srv drains.
+func registerGRPCHealthAndReflection(srv *grpc.Server, cfg config.GRPCConfig) *grpcHealthServer {
+	healthServer := &grpcHealthServer{Server: health.NewServer(), shutdown: make(chan struct{})}
+	for service := range srv.GetServiceInfo() {
+		healthServer.SetServingStatus(service, grpc_health_v1.HealthCheckResponse_SERVING)
+	}
+	grpc_health_v1.RegisterHealthServer(srv, healthServer)
+	if cfg.Reflection {
+		reflection.Register(srv)
+	}
+	return healthServer
+}
+
+// grpcHealthServer wraps health.Server, ending Watch streams when the
+// server is shut down so they do not block graceful stopping.
+type grpcHealthServer struct {
+	*health.Server
+	shutdownOnce sync.Once
+	shutdown     chan struct{}
+}
+
+// Shutdown sets all serving statuses to NOT_SERVING, and ends Watch streams.
+func (s *grpcHealthServer) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		s.Server.Shutdown()
+		close(s.shutdown)
+	})
+}
+
+// Watch implements grpc_health_v1.HealthServer.
+func (s *grpcHealthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.shutdown:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return s.Server.Watch(req, watchStream{stream, ctx})
+}
+
+type watchStream struct {
+	grpc_health_v1.Health_WatchServer
+	ctx context.Context
+}
+
+func (s watchStream) Context() context.Context {
+	return s.ctx
+}
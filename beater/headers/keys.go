@@ -20,6 +20,7 @@ package headers
 // http header keys
 const (
 	Accept                     = "Accept"
+	AcceptEncoding             = "Accept-Encoding"
 	AccessControlAllowHeaders  = "Access-Control-Allow-Headers"
 	AccessControlAllowMethods  = "Access-Control-Allow-Methods"
 	AccessControlAllowOrigin   = "Access-Control-Allow-Origin"
@@ -33,10 +34,16 @@ const (
 	ContentEncoding            = "Content-Encoding"
 	ContentLength              = "Content-Length"
 	ContentType                = "Content-Type"
+	ElasticAPMAckLevel         = "Elastic-Apm-Ack-Level"
+	ElasticAPMChecksum         = "Elastic-Apm-Checksum"
+	ElasticAPMServiceName      = "Elastic-Apm-Service-Name"
 	Etag                       = "Etag"
 	IfNoneMatch                = "If-None-Match"
 	Origin                     = "Origin"
 	UserAgent                  = "User-Agent"
 	Vary                       = "Vary"
+	Warning                    = "Warning"
+	WWWAuthenticate            = "WWW-Authenticate"
 	XContentTypeOptions        = "X-Content-Type-Options"
+	XForwardedFor              = "X-Forwarded-For"
 )
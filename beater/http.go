@@ -19,16 +19,23 @@ package beater
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"go.elastic.co/apm"
 	"go.elastic.co/apm/module/apmhttp"
+	"golang.org/x/net/http2"
 	"golang.org/x/net/netutil"
 
 	"github.com/elastic/apm-server/beater/api"
+	"github.com/elastic/apm-server/beater/api/intake"
+	"github.com/elastic/apm-server/beater/api/intaketelemetry"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/proxyproto"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/beats/v7/libbeat/beat"
@@ -43,6 +50,11 @@ type httpServer struct {
 	logger       *logp.Logger
 	reporter     publish.Reporter
 	grpcListener net.Listener
+
+	// acmeChallengeServer serves ACME HTTP-01 challenges,
+	// if ACME and apm-server.acme.http_challenge_host are
+	// configured.
+	acmeChallengeServer *http.Server
 }
 
 func newHTTPServer(logger *logp.Logger, info beat.Info, cfg *config.Config, tracer *apm.Tracer, reporter publish.Reporter, batchProcessor model.BatchProcessor) (*httpServer, error) {
@@ -53,15 +65,18 @@ func newHTTPServer(logger *logp.Logger, info beat.Info, cfg *config.Config, trac
 
 	server := &http.Server{
 		Addr: cfg.Host,
-		Handler: apmhttp.Wrap(mux,
+		Handler: http2WriteTimeout(apmhttp.Wrap(mux,
 			apmhttp.WithServerRequestIgnorer(doNotTrace),
 			apmhttp.WithTracer(tracer),
-		),
+		), cfg.WriteTimeout),
 		IdleTimeout:    cfg.IdleTimeout,
 		ReadTimeout:    cfg.ReadTimeout,
 		WriteTimeout:   cfg.WriteTimeout,
 		MaxHeaderBytes: cfg.MaxHeaderSize,
 	}
+	if cfg.IntakeTelemetry.Enabled {
+		server.ConnContext = intaketelemetry.ConnContext
+	}
 
 	if cfg.TLS.IsEnabled() {
 		tlsServerConfig, err := tlscommon.LoadTLSServerConfig(cfg.TLS)
@@ -71,6 +86,21 @@ func newHTTPServer(logger *logp.Logger, info beat.Info, cfg *config.Config, trac
 		server.TLSConfig = tlsServerConfig.BuildServerConfig("")
 	}
 
+	var acmeChallengeServer *http.Server
+	if cfg.ACME.Enabled {
+		manager := newACMEManager(cfg.ACME)
+		server.TLSConfig = manager.TLSConfig()
+		if cfg.ACME.HTTPChallengeHost != "" {
+			acmeChallengeServer = &http.Server{
+				Addr:         cfg.ACME.HTTPChallengeHost,
+				Handler:      manager.HTTPHandler(nil),
+				IdleTimeout:  cfg.IdleTimeout,
+				ReadTimeout:  cfg.ReadTimeout,
+				WriteTimeout: cfg.WriteTimeout,
+			}
+		}
+	}
+
 	// Configure the server with gmux. The returned net.Listener will receive
 	// gRPC connections, while all other requests will be handled by s.Handler.
 	//
@@ -79,8 +109,9 @@ func newHTTPServer(logger *logp.Logger, info beat.Info, cfg *config.Config, trac
 	if err != nil {
 		return nil, err
 	}
+	serveHTTP2WithoutWriteTimeout(server)
 
-	return &httpServer{server, cfg, logger, reporter, grpcListener}, nil
+	return &httpServer{server, cfg, logger, reporter, grpcListener, acmeChallengeServer}, nil
 }
 
 func (h *httpServer) start() error {
@@ -108,6 +139,13 @@ func (h *httpServer) start() error {
 		h.logger.Info("RUM endpoints disabled.")
 	}
 
+	if h.cfg.ProxyProtocol.Enabled {
+		if lis, err = proxyProtocolListener(lis, h.cfg.ProxyProtocol); err != nil {
+			return err
+		}
+		h.logger.Info("PROXY protocol enabled.")
+	}
+
 	if h.cfg.MaxConnections > 0 {
 		lis = netutil.LimitListener(lis, h.cfg.MaxConnections)
 		h.logger.Infof("Connection limit set to: %d", h.cfg.MaxConnections)
@@ -125,6 +163,13 @@ func (h *httpServer) start() error {
 		h.logger.Info("SSL enabled.")
 		return h.ServeTLS(lis, "", "")
 	}
+	if h.cfg.ACME.Enabled {
+		h.logger.Infof("SSL enabled, with certificates provisioned by ACME for: %s", strings.Join(h.cfg.ACME.Domains, ", "))
+		if h.acmeChallengeServer != nil {
+			go h.serveACMEChallenges()
+		}
+		return h.ServeTLS(lis, "", "")
+	}
 	if h.cfg.SecretToken != "" {
 		h.logger.Warn("Secret token is set, but SSL is not enabled.")
 	}
@@ -133,7 +178,20 @@ func (h *httpServer) start() error {
 	return h.Serve(lis)
 }
 
+// serveACMEChallenges serves ACME HTTP-01 challenges until the server is stopped.
+func (h *httpServer) serveACMEChallenges() {
+	h.logger.Infof("Serving ACME HTTP challenges on: %s", h.acmeChallengeServer.Addr)
+	if err := h.acmeChallengeServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		h.logger.Errorf("error serving ACME HTTP challenges: %s", err.Error())
+	}
+}
+
 func (h *httpServer) stop() {
+	if h.acmeChallengeServer != nil {
+		if err := h.acmeChallengeServer.Close(); err != nil {
+			h.logger.Errorf("error closing ACME HTTP challenge server: %s", err.Error())
+		}
+	}
 	h.logger.Infof("Stop listening on: %s", h.Server.Addr)
 	if err := h.Shutdown(context.Background()); err != nil {
 		h.logger.Errorf("error stopping http server: %s", err.Error())
@@ -160,6 +218,79 @@ func (h *httpServer) listen() (net.Listener, error) {
 	return net.Listen(network, addr)
 }
 
+// proxyProtocolListener wraps lis to accept PROXY protocol headers from the
+// configured trusted proxies, so that the original client address is used as
+// the remote address of requests.
+func proxyProtocolListener(lis net.Listener, cfg config.ProxyProtocolConfig) (net.Listener, error) {
+	trusted := make([]*net.IPNet, len(cfg.TrustedProxies))
+	for i, addr := range cfg.TrustedProxies {
+		network, err := config.ParseSourceAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		trusted[i] = network
+	}
+	return proxyproto.NewListener(lis, trusted, cfg.HeaderTimeout), nil
+}
+
+// serveHTTP2WithoutWriteTimeout configures server to serve HTTP/2 connections
+// without its write timeout, which the HTTP/2 server applies to every stream,
+// so that streaming intake requests are not ended by it. The write timeout
+// is applied to other HTTP/2 requests by http2WriteTimeout.
+//
+// The read timeout only applies to HTTP/2 connections until the connection
+// preface has been read, and not to streams.
+func serveHTTP2WithoutWriteTimeout(server *http.Server) {
+	serveConn, ok := server.TLSNextProto[http2.NextProtoTLS]
+	if !ok || server.WriteTimeout <= 0 {
+		return
+	}
+	// Only the fields read by the HTTP/2 server are copied.
+	http2Server := &http.Server{
+		ReadTimeout:    server.ReadTimeout,
+		MaxHeaderBytes: server.MaxHeaderBytes,
+		ConnState:      server.ConnState,
+		ErrorLog:       server.ErrorLog,
+	}
+	server.TLSNextProto[http2.NextProtoTLS] = func(_ *http.Server, conn *tls.Conn, h http.Handler) {
+		serveConn(http2Server, conn, h)
+	}
+}
+
+// http2WriteTimeout returns an http.Handler which applies the write timeout
+// to HTTP/2 requests other than streaming intake requests, which are served
+// without it by serveHTTP2WithoutWriteTimeout.
+//
+// As with the HTTP/2 server's own write timeout, the request context is
+// cancelled and the stream is reset once the timeout expires.
+func http2WriteTimeout(h http.Handler, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.ProtoMajor < 2 || isStreamingIntakeRequest(req) {
+			h.ServeHTTP(w, req)
+			return
+		}
+		deadline := time.Now().Add(timeout)
+		ctx, cancel := context.WithDeadline(req.Context(), deadline)
+		defer cancel()
+		h.ServeHTTP(w, req.WithContext(ctx))
+		if !time.Now().Before(deadline) {
+			// Reset the stream.
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
+
+func isStreamingIntakeRequest(req *http.Request) bool {
+	switch req.URL.Path {
+	case api.IntakePath, api.IntakeRUMPath, api.IntakeRUMV3Path:
+		return intake.IsStreamingRequest(req)
+	}
+	return false
+}
+
 func doNotTrace(req *http.Request) bool {
 	// Don't trace root url (healthcheck) requests.
 	return req.URL.Path == api.RootPath
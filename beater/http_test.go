@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/gmux"
+
+	"github.com/elastic/apm-server/beater/api"
+)
+
+func TestStreamingIntakeTimeouts(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	srv := httptest.NewUnstartedServer(http2WriteTimeout(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			body, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return
+			}
+			w.Write(body)
+		},
+	), timeout))
+	srv.Config.ReadTimeout = timeout
+	srv.Config.WriteTimeout = timeout
+	_, err := gmux.ConfigureServer(srv.Config, nil)
+	require.NoError(t, err)
+	serveHTTP2WithoutWriteTimeout(srv.Config)
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	// post sends a request body slowly, taking longer than the timeouts.
+	post := func(path string) (string, error) {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.Write([]byte("slow"))
+			time.Sleep(4 * timeout)
+			pw.Write([]byte(" stream"))
+			pw.Close()
+		}()
+		resp, err := srv.Client().Post(srv.URL+path, "application/x-ndjson", pr)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		require.Equal(t, 2, resp.ProtoMajor)
+		body, err := ioutil.ReadAll(resp.Body)
+		return string(body), err
+	}
+
+	// Streaming intake requests outlive the read and write timeouts.
+	body, err := post(api.IntakePath + "?stream")
+	require.NoError(t, err)
+	assert.Equal(t, "slow stream", body)
+
+	// Other requests, and the stream query parameter on other routes,
+	// are still subject to the timeouts.
+	_, err = post(api.IntakePath)
+	assert.Error(t, err)
+	_, err = post(api.AgentConfigPath + "?stream")
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package interceptors
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/elastic/apm-server/beater/errorcode"
+	"github.com/elastic/apm-server/publish"
+)
+
+// ErrorCode returns a grpc.UnaryServerInterceptor that attaches a
+// machine-readable error code to errors returned by the handler, as a
+// google.rpc.ErrorInfo status detail with the error code as its reason.
+//
+// ErrorCode should be added after Logging to include `error.code` in
+// log records, and after Timeout to report timeouts.
+func ErrorCode() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			err = withErrorCode(err)
+		}
+		return resp, err
+	}
+}
+
+// ErrorCodeFromError returns the machine-readable error code attached
+// to err by the ErrorCode interceptor, or an empty code if there is none.
+func ErrorCodeFromError(err error) errorcode.Code {
+	s, ok := status.FromError(err)
+	if !ok {
+		return ""
+	}
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == errorcode.Domain {
+			return errorcode.Code(info.Reason)
+		}
+	}
+	return ""
+}
+
+func withErrorCode(err error) error {
+	if ErrorCodeFromError(err) != "" {
+		return err
+	}
+	var s *status.Status
+	var code errorcode.Code
+	switch {
+	case errors.Is(err, publish.ErrFull):
+		s = status.New(codes.ResourceExhausted, err.Error())
+		code = errorcode.QueueFull
+	case errors.Is(err, publish.ErrChannelClosed):
+		s = status.New(codes.Unavailable, err.Error())
+		code = errorcode.ShuttingDown
+	default:
+		s, _ = status.FromError(err)
+		code = grpcErrorCode(s.Code())
+	}
+	return ErrorWithCode(s, code)
+}
+
+// ErrorWithCode returns an error for s with code attached, for handlers
+// which can identify errors more precisely than the ErrorCode interceptor,
+// which maps gRPC status codes to error codes.
+func ErrorWithCode(s *status.Status, code errorcode.Code) error {
+	withDetails, err := s.WithDetails(&errdetails.ErrorInfo{
+		Reason: string(code),
+		Domain: errorcode.Domain,
+	})
+	if err != nil {
+		return s.Err()
+	}
+	return withDetails.Err()
+}
+
+func grpcErrorCode(code codes.Code) errorcode.Code {
+	switch code {
+	case codes.Unauthenticated:
+		return errorcode.Unauthorized
+	case codes.PermissionDenied:
+		return errorcode.Forbidden
+	case codes.DeadlineExceeded, codes.Canceled:
+		return errorcode.Timeout
+	case codes.InvalidArgument:
+		return errorcode.Validate
+	case codes.ResourceExhausted:
+		return errorcode.RateLimit
+	case codes.NotFound:
+		return errorcode.NotFound
+	case codes.Unimplemented:
+		return errorcode.MethodNotAllowed
+	case codes.Unavailable:
+		return errorcode.Unavailable
+	}
+	return errorcode.Internal
+}
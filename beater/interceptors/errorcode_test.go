@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package interceptors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/elastic/apm-server/beater/errorcode"
+	"github.com/elastic/apm-server/beater/interceptors"
+	"github.com/elastic/apm-server/publish"
+)
+
+func TestErrorCode(t *testing.T) {
+	for _, tc := range []struct {
+		err        error
+		statusCode codes.Code
+		errorCode  errorcode.Code
+	}{{
+		err:        publish.ErrFull,
+		statusCode: codes.ResourceExhausted,
+		errorCode:  errorcode.QueueFull,
+	}, {
+		err:        fmt.Errorf("wrapped: %w", publish.ErrChannelClosed),
+		statusCode: codes.Unavailable,
+		errorCode:  errorcode.ShuttingDown,
+	}, {
+		err:        status.Error(codes.Unauthenticated, "unauthenticated"),
+		statusCode: codes.Unauthenticated,
+		errorCode:  errorcode.Unauthorized,
+	}, {
+		err:        interceptors.ErrorWithCode(status.New(codes.Unauthenticated, "invalid API Key"), errorcode.InvalidAPIKey),
+		statusCode: codes.Unauthenticated,
+		errorCode:  errorcode.InvalidAPIKey,
+	}, {
+		err:        status.Error(codes.DeadlineExceeded, "request timed out"),
+		statusCode: codes.DeadlineExceeded,
+		errorCode:  errorcode.Timeout,
+	}, {
+		err:        errors.New("boom"),
+		statusCode: codes.Unknown,
+		errorCode:  errorcode.Internal,
+	}} {
+		interceptor := interceptors.ErrorCode()
+		_, err := interceptor(context.Background(), "request_arg", &grpc.UnaryServerInfo{},
+			func(context.Context, interface{}) (interface{}, error) { return nil, tc.err },
+		)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, tc.statusCode, s.Code())
+		assert.Equal(t, status.Convert(tc.err).Message(), s.Message())
+		assert.Equal(t, tc.errorCode, interceptors.ErrorCodeFromError(err))
+	}
+}
+
+func TestErrorCodeNoError(t *testing.T) {
+	interceptor := interceptors.ErrorCode()
+	resp, err := interceptor(context.Background(), "request_arg", &grpc.UnaryServerInfo{},
+		func(context.Context, interface{}) (interface{}, error) { return 123, nil },
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 123, resp)
+	assert.Equal(t, errorcode.Code(""), interceptors.ErrorCodeFromError(err))
+}
@@ -60,6 +60,9 @@ func Logging(logger *logp.Logger) grpc.UnaryServerInterceptor {
 		)
 
 		if err != nil {
+			if code := ErrorCodeFromError(err); code != "" {
+				logger = logger.With("error.code", string(code))
+			}
 			logger.With("error.message", res.Message()).Error(logp.Error(err))
 		} else {
 			logger.Info(res.Message())
@@ -32,6 +32,8 @@ import (
 	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/logp/configure"
+
+	"github.com/elastic/apm-server/beater/errorcode"
 )
 
 func TestLogging(t *testing.T) {
@@ -86,6 +88,7 @@ func TestLogging(t *testing.T) {
 			assert.Error(t, err)
 			assert.Equal(t, zapcore.ErrorLevel, entry.Entry.Level)
 			assert.Equal(t, "internal server error", fields["error.message"])
+			assert.NotContains(t, fields, "error.code")
 		} else {
 			assert.NoError(t, err)
 			assert.Equal(t, zapcore.InfoLevel, entry.Entry.Level)
@@ -99,3 +102,21 @@ func TestLogging(t *testing.T) {
 		assert.Equal(t, tc.statusCode.String(), fields["grpc.response.status_code"])
 	}
 }
+
+func TestLoggingErrorCode(t *testing.T) {
+	require.NoError(t, logp.DevelopmentSetup(logp.ToObserverOutput()))
+	logger := logp.NewLogger("interceptor.logging.test")
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+	chained := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return ErrorCode()(ctx, req, &grpc.UnaryServerInfo{}, handler)
+	}
+	_, err := Logging(logger)(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "method"}, chained)
+	assert.Error(t, err)
+
+	entries := logp.ObserverLogs().TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, string(errorcode.Unauthorized), entries[0].ContextMap()["error.code"])
+}
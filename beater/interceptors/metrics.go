@@ -36,14 +36,7 @@ func Metrics(
 	logger *logp.Logger,
 	methodMetrics ...map[string]map[request.ResultID]*monitoring.Int,
 ) grpc.UnaryServerInterceptor {
-
-	allMethodMetrics := make(map[string]map[request.ResultID]*monitoring.Int)
-	for _, methodMetrics := range methodMetrics {
-		for method, metrics := range methodMetrics {
-			allMethodMetrics[method] = metrics
-		}
-	}
-
+	allMethodMetrics := mergeMethodMetrics(methodMetrics)
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -57,27 +50,76 @@ func Metrics(
 			).Error("metrics registry missing")
 			return handler(ctx, req)
 		}
+		var resp interface{}
+		err := recordMetrics(m, func() (err error) {
+			resp, err = handler(ctx, req)
+			return err
+		})
+		return resp, err
+	}
+}
 
-		m[request.IDRequestCount].Inc()
-		defer m[request.IDResponseCount].Inc()
-
-		resp, err := handler(ctx, req)
+// StreamMetrics returns a grpc.StreamServerInterceptor that increments
+// metrics for gRPC streaming method calls. Each stream is counted as a
+// single request. The full gRPC method name will be used to look up a
+// monitoring map in any of the given maps; the last one wins.
+func StreamMetrics(
+	logger *logp.Logger,
+	methodMetrics ...map[string]map[request.ResultID]*monitoring.Int,
+) grpc.StreamServerInterceptor {
+	allMethodMetrics := mergeMethodMetrics(methodMetrics)
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		m, ok := allMethodMetrics[info.FullMethod]
+		if !ok {
+			// Streaming methods without metrics, such as those of
+			// the gRPC health and reflection services, are expected.
+			logger.With(
+				"grpc.request.method", info.FullMethod,
+			).Debug("metrics registry missing")
+			return handler(srv, stream)
+		}
+		return recordMetrics(m, func() error {
+			return handler(srv, stream)
+		})
+	}
+}
 
-		responseID := request.IDResponseValidCount
-		if err != nil {
-			responseID = request.IDResponseErrorsCount
-			if s, ok := status.FromError(err); ok {
-				switch s.Code() {
-				case codes.Unauthenticated:
-					m[request.IDResponseErrorsUnauthorized].Inc()
-				case codes.DeadlineExceeded:
-					m[request.IDResponseErrorsTimeout].Inc()
-				}
-			}
+func mergeMethodMetrics(
+	methodMetrics []map[string]map[request.ResultID]*monitoring.Int,
+) map[string]map[request.ResultID]*monitoring.Int {
+	allMethodMetrics := make(map[string]map[request.ResultID]*monitoring.Int)
+	for _, methodMetrics := range methodMetrics {
+		for method, metrics := range methodMetrics {
+			allMethodMetrics[method] = metrics
 		}
+	}
+	return allMethodMetrics
+}
 
-		m[responseID].Inc()
+func recordMetrics(m map[request.ResultID]*monitoring.Int, handle func() error) error {
+	m[request.IDRequestCount].Inc()
+	defer m[request.IDResponseCount].Inc()
 
-		return resp, err
+	err := handle()
+
+	responseID := request.IDResponseValidCount
+	if err != nil {
+		responseID = request.IDResponseErrorsCount
+		if s, ok := status.FromError(err); ok {
+			switch s.Code() {
+			case codes.Unauthenticated:
+				m[request.IDResponseErrorsUnauthorized].Inc()
+			case codes.DeadlineExceeded:
+				m[request.IDResponseErrorsTimeout].Inc()
+			}
+		}
 	}
+
+	m[responseID].Inc()
+	return err
 }
@@ -23,6 +23,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -119,6 +121,69 @@ func TestMetrics(t *testing.T) {
 	}
 }
 
+func TestStreamMetrics(t *testing.T) {
+	registry := monitoring.NewRegistry()
+
+	monitoringMap := request.MonitoringMapForRegistry(registry, monitoringKeys)
+	methodName := "test_stream_method_name"
+	logger := logp.NewLogger("interceptor.metrics.test")
+
+	testMap := map[string]map[request.ResultID]*monitoring.Int{
+		methodName: monitoringMap,
+	}
+	i := StreamMetrics(logger, testMap)
+	info := &grpc.StreamServerInfo{
+		FullMethod: methodName,
+	}
+
+	for _, tc := range []struct {
+		f             grpc.StreamHandler
+		monitoringInt map[request.ResultID]int64
+	}{{
+		f: func(srv interface{}, stream grpc.ServerStream) error {
+			return status.Error(codes.Unauthenticated, "error")
+		},
+		monitoringInt: map[request.ResultID]int64{
+			request.IDRequestCount:               1,
+			request.IDResponseCount:              1,
+			request.IDResponseErrorsCount:        1,
+			request.IDResponseErrorsUnauthorized: 1,
+		},
+	}, {
+		f: func(srv interface{}, stream grpc.ServerStream) error {
+			return nil
+		},
+		monitoringInt: map[request.ResultID]int64{
+			request.IDRequestCount:       1,
+			request.IDResponseCount:      1,
+			request.IDResponseValidCount: 1,
+		},
+	}} {
+		i(nil, nil, info, tc.f)
+		assertMonitoring(t, tc.monitoringInt, monitoringMap)
+		beatertest.ClearRegistry(monitoringMap)
+	}
+}
+
+func TestStreamMetricsUnknownMethod(t *testing.T) {
+	require.NoError(t, logp.DevelopmentSetup(logp.ToObserverOutput()))
+	i := StreamMetrics(logp.NewLogger("interceptor.metrics.test"))
+	info := &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}
+
+	var called bool
+	err := i(nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+		called = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+
+	logs := logp.ObserverLogs().TakeAll()
+	require.Len(t, logs, 1)
+	assert.Equal(t, zapcore.DebugLevel, logs[0].Level)
+	assert.Equal(t, "metrics registry missing", logs[0].Message)
+}
+
 func assertMonitoring(t *testing.T, expected map[request.ResultID]int64, actual map[request.ResultID]*monitoring.Int) {
 	for _, k := range monitoringKeys {
 		if val, ok := expected[k]; ok {
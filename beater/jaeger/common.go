@@ -59,14 +59,16 @@ func consumeBatch(
 	return consumer.ConsumeTraces(ctx, traces)
 }
 
-type authFunc func(context.Context, model.Batch) error
+// authFunc authorizes a batch, returning a context
+// recording the authorization if it is authorized.
+type authFunc func(context.Context, model.Batch) (context.Context, error)
 
-func noAuth(context.Context, model.Batch) error {
-	return nil
+func noAuth(ctx context.Context, _ model.Batch) (context.Context, error) {
+	return ctx, nil
 }
 
 func makeAuthFunc(authTag string, authHandler *authorization.Handler) authFunc {
-	return func(ctx context.Context, batch model.Batch) error {
+	return func(ctx context.Context, batch model.Batch) (context.Context, error) {
 		var kind, token string
 		for i, kv := range batch.Process.GetTags() {
 			if kv.Key != authTag {
@@ -81,12 +83,12 @@ func makeAuthFunc(authTag string, authHandler *authorization.Handler) authFunc {
 		result, err := auth.AuthorizedFor(ctx, authorization.ResourceInternal)
 		if !result.Authorized {
 			if err != nil {
-				return errors.Wrap(err, errNotAuthorized.Error())
+				return nil, errors.Wrap(err, errNotAuthorized.Error())
 			}
 			// NOTE(axw) for now at least, we do not return result.Reason in the error message,
 			// as it refers to the "Authorization header" which is incorrect for Jaeger.
-			return errNotAuthorized
+			return nil, errNotAuthorized
 		}
-		return nil
+		return authorization.ContextWithAuthorization(ctx, auth), nil
 	}
 }
@@ -78,7 +78,8 @@ func (c *grpcCollector) PostSpans(ctx context.Context, r *api_v2.PostSpansReques
 }
 
 func (c *grpcCollector) postSpans(ctx context.Context, batch model.Batch) error {
-	if err := c.auth(ctx, batch); err != nil {
+	ctx, err := c.auth(ctx, batch)
+	if err != nil {
 		gRPCCollectorMonitoringMap.inc(request.IDResponseErrorsUnauthorized)
 		return status.Error(codes.Unauthenticated, err.Error())
 	}
@@ -145,6 +146,11 @@ func (s *grpcSampler) fetchSamplingRate(ctx context.Context, service string) (fl
 }
 
 func (s *grpcSampler) validateKibanaClient(ctx context.Context) error {
+	if s.fetcher != nil && s.fetcher.ElasticsearchEnabled() {
+		// Agent configuration is read directly from
+		// Elasticsearch, so Kibana is not required.
+		return nil
+	}
 	if s.client == nil {
 		gRPCSamplingMonitoringMap.inc(request.IDResponseErrorsServiceUnavailable)
 		return errors.New("jaeger remote sampling endpoint is disabled, " +
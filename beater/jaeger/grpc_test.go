@@ -106,8 +106,8 @@ func (tc *testGRPCCollector) setup(t *testing.T) {
 		tc.request = &api_v2.PostSpansRequest{Batch: *batches[0]}
 	}
 
-	tc.collector = &grpcCollector{authFunc(func(context.Context, model.Batch) error {
-		return tc.authError
+	tc.collector = &grpcCollector{authFunc(func(ctx context.Context, _ model.Batch) (context.Context, error) {
+		return ctx, tc.authError
 	}), tracesConsumerFunc(func(ctx context.Context, td pdata.Traces) error {
 		return tc.consumerErr
 	})}
@@ -209,7 +209,7 @@ func (tc *testGRPCSampler) setup() {
 		tc.kibanaVersion = common.MustNewVersion("7.7.0")
 	}
 	client := tests.MockKibana(tc.kibanaCode, tc.kibanaBody, *tc.kibanaVersion, true)
-	fetcher := agentcfg.NewFetcher(client, time.Second)
+	fetcher := agentcfg.NewFetcher(client, agentcfg.FetcherConfig{CacheExpiration: time.Second})
 	tc.sampler = &grpcSampler{logp.L(), client, fetcher}
 	beatertest.ClearRegistry(gRPCSamplingMonitoringMap)
 }
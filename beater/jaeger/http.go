@@ -18,22 +18,30 @@
 package jaeger
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"mime"
 	"net/http"
 
 	"github.com/apache/thrift/lib/go/thrift"
-	"github.com/jaegertracing/jaeger/model"
+	jaegermodel "github.com/jaegertracing/jaeger/model"
 	converter "github.com/jaegertracing/jaeger/model/converter/thrift/jaeger"
 	"github.com/jaegertracing/jaeger/thrift-gen/jaeger"
 	"go.opentelemetry.io/collector/consumer"
 
 	"github.com/elastic/beats/v7/libbeat/monitoring"
 
+	"github.com/elastic/apm-server/beater/authorization"
+	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/middleware"
+	"github.com/elastic/apm-server/beater/otlp"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+	"github.com/elastic/apm-server/processor/stream"
 )
 
 const (
@@ -41,18 +49,44 @@ const (
 )
 
 var (
-	httpRegistry      = monitoring.Default.NewRegistry("apm-server.jaeger.http")
-	monitoringKeys    = append(request.DefaultResultIDs, request.IDEventReceivedCount)
-	httpMonitoringMap = request.MonitoringMapForRegistry(httpRegistry, monitoringKeys)
+	httpRegistry   = monitoring.Default.NewRegistry("apm-server.jaeger.http")
+	monitoringKeys = append(request.DefaultResultIDs, request.IDEventReceivedCount)
+
+	// HTTPMonitoringMap holds a mapping for request.IDs to monitoring
+	// counters for Jaeger Thrift-over-HTTP requests.
+	HTTPMonitoringMap = request.MonitoringMapForRegistry(httpRegistry, monitoringKeys)
 )
 
+// HTTPTracesHandler returns a request.Handler which accepts batches of
+// Thrift-encoded spans, as sent to the Jaeger collector's /api/traces
+// endpoint, and passes them to processor. Process tags are mapped to metadata
+// fields, and spans renamed, down-sampled or dropped by instrumentation
+// scope, as described by otelConfig.
+//
+// Request bodies larger than maxRequestSize bytes are rejected. If limiter
+// is non-nil, a decoder is acquired from it while decoding each request body,
+// after the body has been read in full.
+func HTTPTracesHandler(
+	processor model.BatchProcessor,
+	otelConfig config.OTelConfig,
+	maxRequestSize int,
+	limiter *stream.DecodeLimiter,
+) request.Handler {
+	consumer := &otel.Consumer{
+		Processor:             processor,
+		InstrumentationScopes: otlp.InstrumentationScopeRules(otelConfig),
+		ResourceMappings:      otelConfig.ResourceMappings,
+	}
+	return newHTTPHandler(consumer, maxRequestSize, limiter)
+}
+
 // newHTTPMux returns a new http.ServeMux which accepts Thrift-encoded spans.
-func newHTTPMux(consumer consumer.TracesConsumer) (*http.ServeMux, error) {
+func newHTTPMux(consumer consumer.TracesConsumer, maxRequestSize int) (*http.ServeMux, error) {
 	handler, err := middleware.Wrap(
-		newHTTPHandler(consumer),
+		newHTTPHandler(consumer, maxRequestSize, nil),
 		middleware.LogMiddleware(),
 		middleware.RecoverPanicMiddleware(),
-		middleware.MonitoringMiddleware(httpMonitoringMap),
+		middleware.MonitoringMiddleware(HTTPMonitoringMap),
 		middleware.RequestTimeMiddleware(),
 	)
 	if err != nil {
@@ -66,11 +100,13 @@ func newHTTPMux(consumer consumer.TracesConsumer) (*http.ServeMux, error) {
 }
 
 type httpHandler struct {
-	consumer consumer.TracesConsumer
+	consumer       consumer.TracesConsumer
+	maxRequestSize int64
+	limiter        *stream.DecodeLimiter
 }
 
-func newHTTPHandler(consumer consumer.TracesConsumer) request.Handler {
-	h := &httpHandler{consumer}
+func newHTTPHandler(consumer consumer.TracesConsumer, maxRequestSize int, limiter *stream.DecodeLimiter) request.Handler {
+	h := &httpHandler{consumer: consumer, maxRequestSize: int64(maxRequestSize), limiter: limiter}
 	return h.handle
 }
 
@@ -98,8 +134,12 @@ func (h *httpHandler) handleTraces(c *request.Context) {
 		c.Result.SetWithError(request.IDResponseErrorsValidate, err)
 		return
 	}
+	var protocolFactory thrift.TProtocolFactory
 	switch contentType {
 	case "application/x-thrift", "application/vnd.apache.thrift.binary":
+		protocolFactory = thrift.NewTBinaryProtocolFactoryDefault()
+	case "application/vnd.apache.thrift.compact":
+		protocolFactory = thrift.NewTCompactProtocolFactory()
 	default:
 		c.Result.SetWithError(
 			request.IDResponseErrorsValidate,
@@ -108,22 +148,63 @@ func (h *httpHandler) handleTraces(c *request.Context) {
 		return
 	}
 
-	var batch jaeger.Batch
-	transport := thrift.NewStreamTransport(c.Request.Body, ioutil.Discard)
-	protocol := thrift.NewTBinaryProtocolFactoryDefault().GetProtocol(transport)
-	if err := batch.Read(protocol); err != nil {
+	// Read the body before acquiring a decoder, so decoders are
+	// not held while waiting for clients to send more data. The
+	// server discards the remainder of bodies exceeding the limit,
+	// as for any unread body.
+	body, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, h.maxRequestSize+1))
+	if err != nil {
 		c.Result.SetWithError(request.IDResponseErrorsDecode, err)
 		return
 	}
-
-	modelBatch := model.Batch{
-		Process: converter.ToDomainProcess(batch.Process),
-		Spans:   converter.ToDomain(batch.Spans, batch.Process),
+	if int64(len(body)) > h.maxRequestSize {
+		c.Result.SetWithError(
+			request.IDResponseErrorsRequestTooLarge,
+			fmt.Errorf("request body exceeds %d bytes", h.maxRequestSize),
+		)
+		return
+	}
+	if !h.limiter.Acquire(c.Request.Context()) {
+		c.Result.SetWithError(
+			request.IDResponseErrorsFullQueue,
+			errors.New("too many concurrent decoders"),
+		)
+		return
+	}
+	modelBatch, err := decodeBatch(body, protocolFactory)
+	h.limiter.Release()
+	if err != nil {
+		c.Result.SetWithError(request.IDResponseErrorsDecode, err)
+		return
 	}
-	if err := consumeBatch(c.Request.Context(), modelBatch, h.consumer, httpMonitoringMap); err != nil {
+
+	// Trace context propagated in the request headers is used
+	// for spans whose trace or parent IDs are missing.
+	ctx := otel.ContextWithTraceHeaders(c.Request.Context(), c.Request.Header)
+	if err := consumeBatch(ctx, modelBatch, h.consumer, HTTPMonitoringMap); err != nil {
 		// TODO(axw) map errors from the consumer back to appropriate error codes?
+		var serviceErr *authorization.ServiceUnauthorizedError
+		if errors.As(err, &serviceErr) {
+			c.Result.SetWithError(request.IDResponseErrorsForbidden, err)
+			return
+		}
 		c.Result.SetWithError(request.IDResponseErrorsInternal, err)
 		return
 	}
 	c.Result.SetDefault(request.IDResponseValidAccepted)
 }
+
+// decodeBatch decodes a Thrift-encoded batch of spans, converting them
+// to the Jaeger domain model.
+func decodeBatch(body []byte, protocolFactory thrift.TProtocolFactory) (jaegermodel.Batch, error) {
+	var batch jaeger.Batch
+	transport := thrift.NewStreamTransport(bytes.NewReader(body), ioutil.Discard)
+	protocol := protocolFactory.GetProtocol(transport)
+	if err := batch.Read(protocol); err != nil {
+		return jaegermodel.Batch{}, err
+	}
+	return jaegermodel.Batch{
+		Process: converter.ToDomainProcess(batch.Process),
+		Spans:   converter.ToDomain(batch.Spans, batch.Process),
+	}, nil
+}
@@ -22,10 +22,12 @@ import (
 	"context"
 	"errors"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
 	jaegerthrift "github.com/jaegertracing/jaeger/thrift-gen/jaeger"
@@ -34,9 +36,14 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 
 	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/stream"
 )
 
+const testMaxRequestSize = 1024 * 1024
+
 type httpMuxTest struct {
 	spans         []*jaegerthrift.Span
 	consumerError error
@@ -86,13 +93,13 @@ func TestHTTPMux(t *testing.T) {
 
 func testHTTPMux(t *testing.T, test httpMuxTest) {
 	t.Helper()
-	beatertest.ClearRegistry(httpMonitoringMap)
+	beatertest.ClearRegistry(HTTPMonitoringMap)
 
 	var consumed bool
 	mux, err := newHTTPMux(tracesConsumerFunc(func(ctx context.Context, _ pdata.Traces) error {
 		consumed = true
 		return test.consumerError
-	}))
+	}), testMaxRequestSize)
 	require.NoError(t, err)
 
 	body := encodeThriftSpans(test.spans...)
@@ -103,7 +110,7 @@ func testHTTPMux(t *testing.T, test httpMuxTest) {
 	mux.ServeHTTP(recorder, req)
 	assert.Equal(t, test.expectedStatusCode, recorder.Code)
 	assert.True(t, consumed)
-	assertMonitoring(t, test.expectedMonitoringMap, httpMonitoringMap)
+	assertMonitoring(t, test.expectedMonitoringMap, HTTPMonitoringMap)
 }
 
 func assertMonitoring(t *testing.T, expected map[request.ResultID]int64, actual monitoringMap) {
@@ -118,24 +125,24 @@ func assertMonitoring(t *testing.T, expected map[request.ResultID]int64, actual
 
 func TestHTTPHandler_UnknownRoute(t *testing.T) {
 	c, recorder := newRequestContext("POST", "/foo", nil)
-	newHTTPHandler(nopConsumer())(c)
+	newHTTPHandler(nopConsumer(), testMaxRequestSize, nil)(c)
 	assert.Equal(t, http.StatusNotFound, recorder.Code)
-	assert.Equal(t, `{"error":"404 page not found: unknown route"}`+"\n", recorder.Body.String())
+	assert.Equal(t, `{"code":"ERR_NOT_FOUND","error":"404 page not found: unknown route"}`+"\n", recorder.Body.String())
 }
 
 func TestHTTPMux_MethodNotAllowed(t *testing.T) {
 	c, recorder := newRequestContext("GET", "/api/traces", nil)
-	newHTTPHandler(nopConsumer())(c)
+	newHTTPHandler(nopConsumer(), testMaxRequestSize, nil)(c)
 	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
-	assert.Equal(t, `{"error":"method not supported: only POST requests are allowed"}`+"\n", recorder.Body.String())
+	assert.Equal(t, `{"code":"ERR_METHOD_NOT_ALLOWED","error":"method not supported: only POST requests are allowed"}`+"\n", recorder.Body.String())
 }
 
 func TestHTTPMux_InvalidContentType(t *testing.T) {
 	c, recorder := newRequestContext("POST", "/api/traces", nil)
 	c.Request.Header.Set("Content-Type", "application/json")
-	newHTTPHandler(nopConsumer())(c)
+	newHTTPHandler(nopConsumer(), testMaxRequestSize, nil)(c)
 	assert.Equal(t, http.StatusBadRequest, recorder.Code)
-	assert.Equal(t, `{"error":"data validation error: unsupported content-type \"application/json\""}`+"\n", recorder.Body.String())
+	assert.Equal(t, `{"code":"ERR_VALIDATE","error":"data validation error: unsupported content-type \"application/json\""}`+"\n", recorder.Body.String())
 }
 
 func TestHTTPMux_ValidContentTypes(t *testing.T) {
@@ -143,17 +150,61 @@ func TestHTTPMux_ValidContentTypes(t *testing.T) {
 		body := encodeThriftSpans(&jaegerthrift.Span{})
 		c, recorder := newRequestContext("POST", "/api/traces", body)
 		c.Request.Header.Set("Content-Type", contentType)
-		newHTTPHandler(nopConsumer())(c)
+		newHTTPHandler(nopConsumer(), testMaxRequestSize, nil)(c)
 		assert.Equal(t, http.StatusAccepted, recorder.Code)
 		assert.Equal(t, ``, recorder.Body.String())
 	}
 }
 
+func TestHTTPMux_CompactProtocol(t *testing.T) {
+	batch := &jaegerthrift.Batch{
+		Process: &jaegerthrift.Process{ServiceName: "whatever"},
+		Spans:   []*jaegerthrift.Span{{OperationName: "compact"}},
+	}
+	transport := thrift.NewTMemoryBuffer()
+	require.NoError(t, batch.Write(thrift.NewTCompactProtocol(transport)))
+
+	var consumed pdata.Traces
+	var consumer tracesConsumerFunc = func(ctx context.Context, traces pdata.Traces) error {
+		consumed = traces
+		return nil
+	}
+	c, recorder := newRequestContext("POST", "/api/traces", bytes.NewReader(transport.Buffer.Bytes()))
+	c.Request.Header.Set("Content-Type", "application/vnd.apache.thrift.compact")
+	newHTTPHandler(consumer, testMaxRequestSize, nil)(c)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	require.Equal(t, 1, consumed.SpanCount())
+	spans := consumed.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	assert.Equal(t, "compact", spans.At(0).Name())
+}
+
+func TestHTTPTracesHandler_PropagationHeaders(t *testing.T) {
+	var batches []*model.Batch
+	processor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	c, recorder := newRequestContext("POST", "/api/traces", encodeThriftSpans(&jaegerthrift.Span{
+		SpanId:        0x145254c567a5417e,
+		OperationName: "root",
+	}))
+	c.Request.Header.Set("X-Amzn-Trace-Id", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+	HTTPTracesHandler(processor, config.OTelConfig{}, testMaxRequestSize, nil)(c)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Transactions, 1)
+	tx := batches[0].Transactions[0]
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", tx.TraceID)
+	assert.Equal(t, "53995c3f42cd8ad8", tx.ParentID)
+	assert.Equal(t, "1-5759e988-bd862e3fe1be46a994272793", tx.Labels["xray_root"])
+}
+
 func TestHTTPMux_InvalidBody(t *testing.T) {
 	c, recorder := newRequestContext("POST", "/api/traces", strings.NewReader(`¯\_(ツ)_/¯`))
-	newHTTPHandler(nopConsumer())(c)
+	newHTTPHandler(nopConsumer(), testMaxRequestSize, nil)(c)
 	assert.Equal(t, http.StatusBadRequest, recorder.Code)
-	assert.Regexp(t, `{"error":"data decoding error: .*"}`+"\n", recorder.Body.String())
+	assert.Regexp(t, `{"code":"ERR_DECODE","error":"data decoding error: .*"}`+"\n", recorder.Body.String())
 }
 
 func TestHTTPMux_ConsumerError(t *testing.T) {
@@ -161,9 +212,43 @@ func TestHTTPMux_ConsumerError(t *testing.T) {
 		return errors.New("bauch tut weh")
 	}
 	c, recorder := newRequestContext("POST", "/api/traces", encodeThriftSpans(&jaegerthrift.Span{}))
-	newHTTPHandler(consumer)(c)
+	newHTTPHandler(consumer, testMaxRequestSize, nil)(c)
 	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-	assert.Regexp(t, `{"error":"internal error: bauch tut weh"}`+"\n", recorder.Body.String())
+	assert.Regexp(t, `{"code":"ERR_INTERNAL","error":"internal error: bauch tut weh"}`+"\n", recorder.Body.String())
+}
+
+func TestHTTPMux_RequestTooLarge(t *testing.T) {
+	var consumed bool
+	var consumer tracesConsumerFunc = func(ctx context.Context, _ pdata.Traces) error {
+		consumed = true
+		return nil
+	}
+	body, err := ioutil.ReadAll(encodeThriftSpans(&jaegerthrift.Span{OperationName: strings.Repeat("x", 100)}))
+	require.NoError(t, err)
+	c, recorder := newRequestContext("POST", "/api/traces", bytes.NewReader(body))
+	newHTTPHandler(consumer, len(body)-1, nil)(c)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.False(t, consumed)
+
+	// Bodies of exactly the maximum size are accepted.
+	c, recorder = newRequestContext("POST", "/api/traces", bytes.NewReader(body))
+	newHTTPHandler(consumer, len(body), nil)(c)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.True(t, consumed)
+}
+
+func TestHTTPMux_DecodeLimiter(t *testing.T) {
+	limiter := stream.NewDecodeLimiter(1, 10*time.Millisecond)
+	require.True(t, limiter.Acquire(context.Background()))
+	c, recorder := newRequestContext("POST", "/api/traces", encodeThriftSpans(&jaegerthrift.Span{}))
+	newHTTPHandler(nopConsumer(), testMaxRequestSize, limiter)(c)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "too many concurrent decoders")
+
+	limiter.Release()
+	c, recorder = newRequestContext("POST", "/api/traces", encodeThriftSpans(&jaegerthrift.Span{}))
+	newHTTPHandler(nopConsumer(), testMaxRequestSize, limiter)(c)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
 }
 
 func newRequestContext(method, path string, body io.Reader) (*request.Context, *httptest.ResponseRecorder) {
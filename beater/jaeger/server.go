@@ -33,9 +33,12 @@ import (
 	"github.com/elastic/beats/v7/libbeat/logp"
 
 	"github.com/elastic/apm-server/agentcfg"
+	"github.com/elastic/apm-server/beater/api/config/agent"
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/interceptors"
+	"github.com/elastic/apm-server/beater/otlp"
+	"github.com/elastic/apm-server/elasticsearch"
 	"github.com/elastic/apm-server/kibana"
 	logs "github.com/elastic/apm-server/log"
 	"github.com/elastic/apm-server/model"
@@ -70,7 +73,11 @@ func NewServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, proc
 	if !cfg.JaegerConfig.GRPC.Enabled && !cfg.JaegerConfig.HTTP.Enabled {
 		return nil, nil
 	}
-	traceConsumer := &otel.Consumer{Processor: processor}
+	traceConsumer := &otel.Consumer{
+		Processor:             processor,
+		InstrumentationScopes: otlp.InstrumentationScopeRules(cfg.OTel),
+		ResourceMappings:      cfg.OTel.ResourceMappings,
+	}
 
 	srv := &Server{logger: logger}
 	if cfg.JaegerConfig.GRPC.Enabled {
@@ -113,7 +120,14 @@ func NewServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, proc
 		var fetcher *agentcfg.Fetcher
 		if cfg.Kibana.Enabled {
 			client = kibana.NewConnectingClient(&cfg.Kibana)
-			fetcher = agentcfg.NewFetcher(client, cfg.AgentConfig.Cache.Expiration)
+			fetcher = agentcfg.NewFetcher(client, agent.FetcherConfig(cfg.AgentConfig))
+		}
+		if cfg.AgentConfig.ElasticsearchEnabled() {
+			esClient, err := elasticsearch.NewClient(cfg.AgentConfig.ESConfig)
+			if err != nil {
+				return nil, err
+			}
+			fetcher = agentcfg.NewElasticsearchFetcher(client, esClient, agent.FetcherConfig(cfg.AgentConfig))
 		}
 		RegisterGRPCServices(
 			srv.grpc.server,
@@ -123,6 +137,7 @@ func NewServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, proc
 			processor,
 			client,
 			fetcher,
+			cfg.OTel,
 		)
 	}
 	if cfg.JaegerConfig.HTTP.Enabled {
@@ -131,7 +146,7 @@ func NewServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, proc
 		if err != nil {
 			return nil, err
 		}
-		httpMux, err := newHTTPMux(traceConsumer)
+		httpMux, err := newHTTPMux(traceConsumer, cfg.JaegerConfig.HTTP.MaxRequestSize)
 		if err != nil {
 			return nil, err
 		}
@@ -148,6 +163,9 @@ func NewServer(logger *logp.Logger, cfg *config.Config, tracer *apm.Tracer, proc
 }
 
 // RegisterGRPCServices registers Jaeger gRPC services with srv.
+//
+// Process tags are mapped to metadata fields, and spans renamed, down-sampled
+// or dropped by instrumentation scope, as described by otelConfig.
 func RegisterGRPCServices(
 	srv *grpc.Server,
 	authBuilder *authorization.Builder,
@@ -156,12 +174,17 @@ func RegisterGRPCServices(
 	processor model.BatchProcessor,
 	kibanaClient kibana.Client,
 	agentcfgFetcher *agentcfg.Fetcher,
+	otelConfig config.OTelConfig,
 ) {
 	auth := noAuth
 	if authTag != "" {
 		auth = makeAuthFunc(authTag, authBuilder.ForPrivilege(authorization.PrivilegeEventWrite.Action))
 	}
-	traceConsumer := &otel.Consumer{Processor: processor}
+	traceConsumer := &otel.Consumer{
+		Processor:             processor,
+		InstrumentationScopes: otlp.InstrumentationScopeRules(otelConfig),
+		ResourceMappings:      otelConfig.ResourceMappings,
+	}
 	api_v2.RegisterCollectorServiceServer(srv, &grpcCollector{auth, traceConsumer})
 	api_v2.RegisterSamplingManagerServer(srv, &grpcSampler{logger, kibanaClient, agentcfgFetcher})
 }
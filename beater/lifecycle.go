@@ -0,0 +1,313 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/config"
+	logs "github.com/elastic/apm-server/log"
+)
+
+const (
+	lifecycleStateRunning  = "running"
+	lifecycleStateStopping = "stopping"
+	lifecycleStateStopped  = "stopped"
+
+	shutdownReasonShutdown = "shutdown"
+	shutdownReasonReload   = "reload"
+)
+
+// serverLifecycle records the lifecycle of the most recently started
+// APM Server in the "state" monitoring namespace, and logs a structured
+// record of each transition, so that server starts, restarts and stops
+// are auditable from the monitoring cluster.
+var serverLifecycle = &lifecycle{}
+
+func init() {
+	monitoring.NewFunc(apmRegistry, "lifecycle", serverLifecycle.report, monitoring.Report)
+}
+
+type lifecycle struct {
+	mu                sync.Mutex
+	state             string
+	configHash        string
+	features          []string
+	listeners         []string
+	startTime         time.Time
+	stopTime          time.Time
+	shutdownReason    string
+	shutdownRequested bool
+	ackedAtStop       int64
+	drainedEvents     int64
+}
+
+// requestShutdown records that the process is shutting down, as opposed to
+// a server being stopped for reloading its configuration.
+func (l *lifecycle) requestShutdown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.shutdownRequested = true
+}
+
+// started records that a server has started with the given config.
+func (l *lifecycle) started(logger *logp.Logger, cfg *config.Config, rawConfig *common.Config) {
+	configHash, err := hashConfig(rawConfig)
+	if err != nil {
+		logger.Warnf("failed to hash config: %s", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state = lifecycleStateRunning
+	l.configHash = configHash
+	l.features = enabledFeatures(cfg)
+	l.listeners = listenerAddresses(cfg)
+	l.startTime = time.Now()
+	l.stopTime = time.Time{}
+	l.shutdownReason = ""
+	l.drainedEvents = 0
+	logger.Named(logs.Lifecycle).Infow("APM Server started",
+		"state", l.state,
+		"config_hash", l.configHash,
+		"features", l.features,
+		"listeners", l.listeners,
+	)
+}
+
+// stopping records that a server is stopping, either because it returned
+// runErr, or because it was stopped. ackedEvents holds the number of events
+// acknowledged by the output so far, for counting the events drained while
+// stopping.
+func (l *lifecycle) stopping(logger *logp.Logger, runErr error, ackedEvents int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case runErr != nil:
+		l.shutdownReason = "error: " + runErr.Error()
+	case l.shutdownRequested:
+		l.shutdownReason = shutdownReasonShutdown
+	default:
+		l.shutdownReason = shutdownReasonReload
+	}
+	l.state = lifecycleStateStopping
+	l.ackedAtStop = ackedEvents
+	logger.Named(logs.Lifecycle).Infow("APM Server stopping",
+		"state", l.state,
+		"config_hash", l.configHash,
+		"shutdown_reason", l.shutdownReason,
+	)
+}
+
+// stopped records that a server has stopped, after its queued events have
+// been drained. ackedEvents holds the number of events acknowledged by the
+// output so far.
+func (l *lifecycle) stopped(logger *logp.Logger, ackedEvents int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.state = lifecycleStateStopped
+	l.stopTime = time.Now()
+	l.drainedEvents = ackedEvents - l.ackedAtStop
+	logger.Named(logs.Lifecycle).Infow("APM Server stopped",
+		"state", l.state,
+		"config_hash", l.configHash,
+		"shutdown_reason", l.shutdownReason,
+		"drained_events", l.drainedEvents,
+		"uptime", l.stopTime.Sub(l.startTime).String(),
+	)
+}
+
+// drained records that all events published before shutdown have been
+// acknowledged by the output, or the shutdown timeout has been reached.
+// ackedEvents holds the number of events acknowledged by the output so far.
+func (l *lifecycle) drained(logger *logp.Logger, ackedEvents int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.state != lifecycleStateStopped {
+		return
+	}
+	l.drainedEvents = ackedEvents - l.ackedAtStop
+	logger.Named(logs.Lifecycle).Infow("APM Server shutdown complete",
+		"state", l.state,
+		"config_hash", l.configHash,
+		"shutdown_reason", l.shutdownReason,
+		"drained_events", l.drainedEvents,
+	)
+}
+
+func (l *lifecycle) report(m monitoring.Mode, V monitoring.Visitor) {
+	V.OnRegistryStart()
+	defer V.OnRegistryFinished()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.state == "" {
+		return
+	}
+	monitoring.ReportString(V, "state", l.state)
+	monitoring.ReportString(V, "config_hash", l.configHash)
+	monitoring.ReportStringSlice(V, "features", l.features)
+	monitoring.ReportStringSlice(V, "listeners", l.listeners)
+	monitoring.ReportString(V, "started", l.startTime.UTC().Format(time.RFC3339))
+	if !l.stopTime.IsZero() {
+		monitoring.ReportString(V, "stopped", l.stopTime.UTC().Format(time.RFC3339))
+	}
+	monitoring.ReportNamespace(V, "shutdown", func() {
+		// requested reports whether the process is shutting down,
+		// as opposed to the server being reloaded, as soon as the
+		// shutdown is requested. monitoring.ReportBool reports the
+		// key rather than the value, so the visitor is called directly.
+		V.OnKey("requested")
+		V.OnBool(l.shutdownRequested)
+		if l.shutdownReason != "" {
+			monitoring.ReportString(V, "reason", l.shutdownReason)
+			monitoring.ReportInt(V, "drained_events", l.drainedEvents)
+		}
+	})
+}
+
+// hashConfig returns a hex-encoded SHA-256 hash of rawConfig, which is
+// stable across restarts with the same configuration.
+func hashConfig(rawConfig *common.Config) (string, error) {
+	var m map[string]interface{}
+	if rawConfig != nil {
+		if err := rawConfig.Unpack(&m); err != nil {
+			return "", err
+		}
+	}
+	// encoding/json sorts map keys, making the encoding deterministic.
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// enabledFeatures returns the sorted names of optional features enabled in cfg.
+//
+// Features are derived from cfg: any config section with an "enabled" setting
+// or an IsEnabled method is a feature, named by its config path. Sections of
+// disabled features are not inspected, so e.g. "rum.source_mapping" is only
+// reported when RUM is enabled. Features which are not enabled by a setting
+// of their own are added explicitly.
+func enabledFeatures(cfg *config.Config) []string {
+	var names []string
+	collectEnabledFeatures(reflect.ValueOf(cfg).Elem(), "", &names)
+	if cfg.AgentConfig.ElasticsearchEnabled() {
+		names = append(names, "agent_config.elasticsearch")
+	}
+	if cfg.RumConfig.IsEnabled() && cfg.RumConfig.SourceMapping.IsEnabled() && len(cfg.RumConfig.SourceMapping.External) > 0 {
+		names = append(names, "rum.source_mapping.external")
+	}
+	if cfg.MissingMetadata.Action == config.MissingMetadataActionSynthesize {
+		names = append(names, "missing_metadata.synthesize")
+	}
+	sort.Strings(names)
+	return names
+}
+
+var configPkgPath = reflect.TypeOf(config.Config{}).PkgPath()
+
+// collectEnabledFeatures appends to names the config paths of the enabled
+// features in the struct value v, whose config path is prefix.
+func collectEnabledFeatures(v reflect.Value, prefix string, names *[]string) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := strings.Split(field.Tag.Get("config"), ",")[0]
+		if field.PkgPath != "" || name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+		fieldValue := v.Field(i)
+		if enabled, ok := featureEnabled(fieldValue); ok {
+			if !enabled {
+				continue
+			}
+			*names = append(*names, path)
+		}
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type().PkgPath() == configPkgPath {
+			collectEnabledFeatures(fieldValue, path+".", names)
+		}
+	}
+}
+
+// featureEnabled reports whether the config section v is enabled, and whether
+// v is a feature at all: a struct, or pointer to a struct, with an IsEnabled
+// method or an "enabled" setting. Nil pointers to features are disabled.
+func featureEnabled(v reflect.Value) (enabled, ok bool) {
+	type isEnabler interface {
+		IsEnabled() bool
+	}
+	ptr := v
+	if v.Kind() != reflect.Ptr {
+		if !v.CanAddr() {
+			return false, false
+		}
+		ptr = v.Addr()
+	}
+	if ptr.Type().Elem().Kind() != reflect.Struct {
+		return false, false
+	}
+	if enabler, isFeature := ptr.Interface().(isEnabler); isFeature {
+		return !ptr.IsNil() && enabler.IsEnabled(), true
+	}
+	field, isFeature := ptr.Type().Elem().FieldByName("Enabled")
+	if !isFeature || field.Tag.Get("config") != "enabled" {
+		return false, false
+	}
+	if ptr.IsNil() {
+		return false, true
+	}
+	switch enabledValue := ptr.Elem().FieldByIndex(field.Index); enabledValue.Kind() {
+	case reflect.Bool:
+		return enabledValue.Bool(), true
+	case reflect.Ptr:
+		return !enabledValue.IsNil() && enabledValue.Elem().Bool(), true
+	}
+	return false, false
+}
+
+// listenerAddresses returns the addresses the server is configured to listen on.
+func listenerAddresses(cfg *config.Config) []string {
+	listeners := []string{cfg.Host}
+	if cfg.JaegerConfig.GRPC.Enabled {
+		listeners = append(listeners, cfg.JaegerConfig.GRPC.Host)
+	}
+	if cfg.JaegerConfig.HTTP.Enabled {
+		listeners = append(listeners, cfg.JaegerConfig.HTTP.Host)
+	}
+	return listeners
+}
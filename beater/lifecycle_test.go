@@ -0,0 +1,119 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func TestLifecycle(t *testing.T) {
+	var l lifecycle
+	registry := monitoring.NewRegistry()
+	monitoring.NewFunc(registry, "lifecycle", l.report, monitoring.Report)
+	snapshot := func() map[string]interface{} {
+		state, _ := monitoring.CollectStructSnapshot(registry, monitoring.Full, false)["lifecycle"].(map[string]interface{})
+		return state
+	}
+	assert.Empty(t, snapshot())
+
+	logger := logp.NewLogger("")
+	cfg := config.DefaultConfig()
+	cfg.JaegerConfig.GRPC.Enabled = true
+	cfg.JaegerConfig.GRPC.Host = "localhost:14250"
+	cfg.Synthetics.Enabled = true
+	l.started(logger, cfg, common.MustNewConfigFrom(map[string]interface{}{"host": "localhost:8200"}))
+
+	state := snapshot()
+	assert.Equal(t, "running", state["state"])
+	assert.Len(t, state["config_hash"], 64)
+	assert.Equal(t, []string{
+		"aggregation.service_destinations", "aggregation.transactions",
+		"jaeger.grpc", "pipeline_check", "register.ingest.pipeline",
+		"response_compression", "synthetics",
+	}, state["features"])
+	assert.Equal(t, []string{"localhost:8200", "localhost:14250"}, state["listeners"])
+	assert.Equal(t, map[string]interface{}{"requested": false}, state["shutdown"])
+
+	l.stopping(logger, nil, 10)
+	assert.Equal(t, "stopping", snapshot()["state"])
+	l.stopped(logger, 15)
+	state = snapshot()
+	assert.Equal(t, "stopped", state["state"])
+	assert.Contains(t, state, "stopped")
+	assert.Equal(t, map[string]interface{}{
+		"requested": false, "reason": "reload", "drained_events": int64(5),
+	}, state["shutdown"])
+
+	l.started(logger, cfg, nil)
+	l.requestShutdown()
+	assert.Equal(t, map[string]interface{}{"requested": true}, snapshot()["shutdown"])
+	l.stopping(logger, nil, 20)
+	l.stopped(logger, 20)
+	l.drained(logger, 27)
+	assert.Equal(t, map[string]interface{}{
+		"requested": true, "reason": "shutdown", "drained_events": int64(7),
+	}, snapshot()["shutdown"])
+
+	l.started(logger, cfg, nil)
+	l.stopping(logger, errors.New("boom"), 0)
+	assert.Equal(t, "error: boom", snapshot()["shutdown"].(map[string]interface{})["reason"])
+}
+
+func TestEnabledFeatures(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Aggregation.TransactionMarks.Enabled = true
+	cfg.Expiry.Enabled = true
+	cfg.RumConfig.BotTraffic.Enabled = true
+	assert.Contains(t, enabledFeatures(cfg), "aggregation.transaction_marks")
+	assert.Contains(t, enabledFeatures(cfg), "expiry")
+	// RUM features are only reported when RUM is enabled.
+	assert.NotContains(t, enabledFeatures(cfg), "rum.bot_traffic")
+
+	rumEnabled := true
+	cfg.RumConfig.Enabled = &rumEnabled
+	cfg.MissingMetadata.Action = config.MissingMetadataActionSynthesize
+	features := enabledFeatures(cfg)
+	assert.Contains(t, features, "rum")
+	assert.Contains(t, features, "rum.bot_traffic")
+	assert.Contains(t, features, "missing_metadata.synthesize")
+	assert.True(t, sort.StringsAreSorted(features))
+}
+
+func TestHashConfig(t *testing.T) {
+	hash := func(m map[string]interface{}) string {
+		h, err := hashConfig(common.MustNewConfigFrom(m))
+		require.NoError(t, err)
+		return h
+	}
+	h1 := hash(map[string]interface{}{"host": "localhost:8200", "rum": map[string]interface{}{"enabled": true}})
+	h2 := hash(map[string]interface{}{"rum.enabled": true, "host": "localhost:8200"})
+	h3 := hash(map[string]interface{}{"host": "localhost:8201", "rum": map[string]interface{}{"enabled": true}})
+	assert.Equal(t, h1, h2)
+	assert.NotEqual(t, h1, h3)
+}
@@ -18,41 +18,74 @@
 package middleware
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/utility"
 )
 
 // AuthorizationHandler provides an interface for obtaining an authorization.Authorization
-// for a given auth kind and value.
+// for a given auth kind and value, for identifying requests exempt from authorization,
+// and for obtaining the WWW-Authenticate challenges returned for unauthorized requests.
 type AuthorizationHandler interface {
 	AuthorizationFor(kind, value string) authorization.Authorization
+	Bypassed(*http.Request) bool
+	Challenges() []string
 }
 
 // AuthorizationMiddleware returns a Middleware to only let authorized requests pass through
 func AuthorizationMiddleware(auth AuthorizationHandler, required bool) Middleware {
 	return func(h request.Handler) (request.Handler, error) {
 		return func(c *request.Context) {
+			if auth.Bypassed(c.Request) {
+				c.AuthResult = authorization.Result{Authorized: true}
+				h(c)
+				return
+			}
+			start := time.Now()
 			header := c.Request.Header.Get(headers.Authorization)
-			auth := auth.AuthorizationFor(authorization.ParseAuthorizationHeader(header))
+			authz := auth.AuthorizationFor(authorization.ParseAuthorizationHeader(header))
 
-			result, err := auth.AuthorizedFor(c.Request.Context(), authorization.ResourceInternal)
+			result, err := authz.AuthorizedFor(c.Request.Context(), authorization.ResourceInternal)
+			c.PhaseTimings.Add(utility.PhaseAuth, time.Since(start))
 			if err != nil {
 				c.Result.SetDefault(request.IDResponseErrorsServiceUnavailable)
 				c.Result.Err = err
 				c.Write()
 				return
 			} else if required && !result.Authorized {
+				// Agents rely on 401 for all authorization failures;
+				// the response body's code tells the reasons apart.
 				id := request.IDResponseErrorsUnauthorized
+				for _, challenge := range auth.Challenges() {
+					c.Header().Add(headers.WWWAuthenticate, challenge)
+				}
 				status := request.MapResultIDToStatus[id]
 				if result.Reason != "" {
 					status.Keyword = result.Reason
 				}
-				c.Result.Set(id, status.Code, status.Keyword, nil, nil)
+				var body interface{}
+				if result.Code != "" {
+					body = map[string]string{
+						"error":             status.Keyword,
+						"code":              string(result.Code),
+						"documentation_url": authorization.DocumentationURL(result.Code),
+					}
+				}
+				c.Result.Set(id, status.Code, status.Keyword, body, nil)
+				c.Result.Code = result.Code
 				c.Write()
 				return
 			}
 			c.AuthResult = result
+			if result.Authorized {
+				// Record the authorization, so events can be checked
+				// against the services to which it is scoped.
+				c.Request = c.Request.WithContext(authorization.ContextWithAuthorization(c.Request.Context(), authz))
+			}
 
 			h(c)
 		}, nil
@@ -34,6 +34,7 @@ import (
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/model"
 )
 
 func TestAuthorizationMiddleware(t *testing.T) {
@@ -47,6 +48,7 @@ func TestAuthorizationMiddleware(t *testing.T) {
 			securedResult: authorization.Result{
 				Authorized: false,
 				Reason:     "missing or improperly formatted Authorization header: expected 'Authorization: Bearer secret_token' or 'Authorization: ApiKey base64(API key ID:API key)'",
+				Code:       authorization.CodeMissingCredentials,
 			},
 		},
 		"invalid header": {
@@ -54,18 +56,21 @@ func TestAuthorizationMiddleware(t *testing.T) {
 			securedResult: authorization.Result{
 				Authorized: false,
 				Reason:     "unknown Authorization kind Foo: expected 'Authorization: Bearer secret_token' or 'Authorization: ApiKey base64(API key ID:API key)'",
+				Code:       authorization.CodeUnsupportedScheme,
 			},
 		},
 		"invalid token": {
 			header: "Bearer Bar",
 			securedResult: authorization.Result{
 				Authorized: false,
+				Reason:     "invalid secret token",
+				Code:       authorization.CodeInvalidSecretToken,
 			},
 		},
 		"bearer": {
 			header:             "Bearer foo",
 			allowedWhenSecured: true,
-			securedResult:      authorization.Result{Authorized: true},
+			securedResult:      authorization.Result{Authorized: true, Authenticated: true},
 		},
 	} {
 		setup := func(token string) (*authorization.Handler, *request.Context, *httptest.ResponseRecorder) {
@@ -86,12 +91,14 @@ func TestAuthorizationMiddleware(t *testing.T) {
 				assert.Equal(t, http.StatusAccepted, rec.Code)
 			} else {
 				assert.Equal(t, http.StatusUnauthorized, rec.Code)
-				// response body should be something like `{"error":"unauthorized"}`
-				reason := tc.securedResult.Reason
-				if reason == "" {
-					reason = "unauthorized"
-				}
-				expected, err := json.Marshal(map[string]interface{}{"error": reason})
+				assert.Equal(t, `Bearer realm="apm-server"`, rec.Header().Get(headers.WWWAuthenticate))
+				// response body should be something like
+				// `{"code":"ERR_INVALID_SECRET_TOKEN","documentation_url":"...","error":"invalid secret token"}`
+				expected, err := json.Marshal(map[string]interface{}{
+					"error":             tc.securedResult.Reason,
+					"code":              tc.securedResult.Code,
+					"documentation_url": authorization.DocumentationURL(tc.securedResult.Code),
+				})
 				require.NoError(t, err)
 				assert.Equal(t, string(expected)+"\n", rec.Body.String())
 			}
@@ -135,18 +142,119 @@ func TestAuthorizationMiddlewareError(t *testing.T) {
 		m := AuthorizationMiddleware(handler, required)
 		Apply(m, beatertest.Handler202)(c)
 		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
-		assert.Equal(t, `{"error":"service unavailable"}`+"\n", rec.Body.String())
+		assert.Equal(t, `{"code":"ERR_UNAVAILABLE","error":"service unavailable"}`+"\n", rec.Body.String())
 		assert.EqualError(t, c.Result.Err, "internal details should not be leaked")
 		assert.Zero(t, c.AuthResult)
 	}
 }
 
+func TestAuthorizationMiddlewareInsufficientPrivileges(t *testing.T) {
+	auth := authorizationFunc(func(ctx context.Context, resource elasticsearch.Resource) (authorization.Result, error) {
+		return authorization.Result{
+			Reason: "API Key lacks the privileges required for this request",
+			Code:   authorization.CodeInsufficientPrivileges,
+		}, nil
+	})
+	handler := authorizationHandlerFunc(func(kind, value string) authorization.Authorization {
+		return auth
+	})
+	c, rec := beatertest.DefaultContextWithResponseRecorder()
+	m := AuthorizationMiddleware(handler, true)
+	Apply(m, beatertest.Handler202)(c)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{
+		"error": "API Key lacks the privileges required for this request",
+		"code": "ERR_INSUFFICIENT_PRIVILEGES",
+		"documentation_url": "https://www.elastic.co/guide/en/apm/server/current/api-key.html"
+	}`, rec.Body.String())
+}
+
+func TestAuthorizationMiddlewareContext(t *testing.T) {
+	var queried []elasticsearch.Resource
+	auth := authorizationFunc(func(ctx context.Context, resource elasticsearch.Resource) (authorization.Result, error) {
+		queried = append(queried, resource)
+		return authorization.Result{Authorized: true}, nil
+	})
+	handler := authorizationHandlerFunc(func(kind, value string) authorization.Authorization {
+		return auth
+	})
+	c, rec := beatertest.DefaultContextWithResponseRecorder()
+	m := AuthorizationMiddleware(handler, true)
+	Apply(m, func(c *request.Context) {
+		// The authorization is recorded in the request context,
+		// so events can be checked against their services.
+		processor := authorization.ServiceBatchProcessor(model.ProcessBatchFunc(
+			func(context.Context, *model.Batch) error { return nil },
+		))
+		err := processor.ProcessBatch(c.Request.Context(), &model.Batch{
+			Transactions: []*model.Transaction{{Metadata: model.Metadata{Service: model.Service{Name: "opbeans"}}}},
+		})
+		require.NoError(t, err)
+		beatertest.Handler202(c)
+	})(c)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, []elasticsearch.Resource{authorization.ResourceInternal, "service:opbeans"}, queried)
+}
+
+func TestAuthorizationMiddlewareBypass(t *testing.T) {
+	builder, err := authorization.NewBuilder(&config.Config{
+		SecretToken: "foo",
+		AuthBypass: []config.AuthBypassRule{{
+			Paths:           []string{"/"},
+			SourceAddresses: []string{"127.0.0.1", "::1"},
+		}},
+	})
+	require.NoError(t, err)
+	handler := builder.ForAnyOfPrivileges(authorization.ActionAny)
+
+	for name, tc := range map[string]struct {
+		path       string
+		remoteAddr string
+		header     http.Header
+		expected   int
+	}{
+		"loopback IPv4": {path: "/", remoteAddr: "127.0.0.1:1234", expected: http.StatusAccepted},
+		"loopback IPv6": {path: "/", remoteAddr: "[::1]:1234", expected: http.StatusAccepted},
+		"other path":    {path: "/intake/v2/events", remoteAddr: "127.0.0.1:1234", expected: http.StatusUnauthorized},
+		"other address": {path: "/", remoteAddr: "192.0.2.1:1234", expected: http.StatusUnauthorized},
+		"forwarded for loopback": {
+			path:       "/",
+			remoteAddr: "192.0.2.1:1234",
+			header:     http.Header{"X-Forwarded-For": []string{"127.0.0.1"}},
+			expected:   http.StatusUnauthorized,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, rec := beatertest.DefaultContextWithResponseRecorder()
+			c.Request = httptest.NewRequest(http.MethodGet, tc.path, nil)
+			c.Request.RemoteAddr = tc.remoteAddr
+			for k, v := range tc.header {
+				c.Request.Header[k] = v
+			}
+			m := AuthorizationMiddleware(handler, true)
+			Apply(m, beatertest.Handler202)(c)
+			assert.Equal(t, tc.expected, rec.Code)
+			if tc.expected == http.StatusAccepted {
+				assert.Equal(t, authorization.Result{Authorized: true}, c.AuthResult)
+			}
+		})
+	}
+}
+
 type authorizationHandlerFunc func(kind, value string) authorization.Authorization
 
 func (f authorizationHandlerFunc) AuthorizationFor(kind, value string) authorization.Authorization {
 	return f(kind, value)
 }
 
+func (f authorizationHandlerFunc) Bypassed(*http.Request) bool {
+	return false
+}
+
+func (f authorizationHandlerFunc) Challenges() []string {
+	return nil
+}
+
 type authorizationFunc func(context.Context, elasticsearch.Resource) (authorization.Result, error)
 
 func (f authorizationFunc) AuthorizedFor(ctx context.Context, resource elasticsearch.Resource) (authorization.Result, error) {
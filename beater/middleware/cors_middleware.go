@@ -20,6 +20,7 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 	"github.com/ryanuber/go-glob"
@@ -33,51 +34,54 @@ var (
 	supportedMethods = strings.Join([]string{http.MethodPost, http.MethodOptions}, ", ")
 )
 
+// maxCachedOrigins is the maximum number of distinct origins for which
+// the result of matching against the allowed origins is cached.
+const maxCachedOrigins = 1000
+
 // CORSMiddleware returns a middleware serving preflight OPTION requests and terminating requests if they do not
 // match the required valid origin.
+//
+// Preflight responses are precomputed, and the result of matching each origin against the allowed origins is
+// cached, as preflight requests make up a substantial part of RUM traffic.
 func CORSMiddleware(allowedOrigins, allowedHeaders []string) Middleware {
-	var isAllowed = func(origin string) bool {
-		for _, allowed := range allowedOrigins {
-			if glob.Glob(allowed, origin) {
-				return true
-			}
-		}
-		return false
+	allowHeaders := make([]string, 0, len(allowedHeaders)+len(supportedHeaders))
+	allowHeaders = append(allowHeaders, allowedHeaders...)
+	allowHeaders = append(allowHeaders, supportedHeaders...)
+	preflightHeaders := http.Header{
+		// tell browsers to cache response requestHeaders for up to 1 hour (browsers might ignore this)
+		headers.AccessControlMaxAge: {"3600"},
+		// origin must be part of the cache key so that we can handle multiple allowed origins
+		headers.Vary: {headers.Origin},
+		// required if Access-Control-Request-Method and Access-Control-Request-Headers are in the requestHeaders
+		headers.AccessControlAllowMethods:  {supportedMethods},
+		headers.AccessControlAllowHeaders:  {strings.Join(allowHeaders, ", ")},
+		headers.AccessControlExposeHeaders: {headers.Etag},
 	}
+	origins := newOriginCache(allowedOrigins, maxCachedOrigins)
 
 	return func(h request.Handler) (request.Handler, error) {
 		return func(c *request.Context) {
 			// origin header is always set by the browser
 			origin := c.Request.Header.Get(headers.Origin)
-			validOrigin := isAllowed(origin)
+			allowOrigin, validOrigin := origins.allowOrigin(origin)
 
 			if c.Request.Method == http.MethodOptions {
+				header := c.Header()
+				for k, v := range preflightHeaders {
+					header[k] = v
+				}
 				// setting the ACAO header is the way to tell the browser to go ahead with the request
 				if validOrigin {
 					// do not set the configured origin(s), echo the received origin instead
-					c.Header().Set(headers.AccessControlAllowOrigin, origin)
+					header[headers.AccessControlAllowOrigin] = allowOrigin
 				}
-
-				// tell browsers to cache response requestHeaders for up to 1 hour (browsers might ignore this)
-				c.Header().Set(headers.AccessControlMaxAge, "3600")
-				// origin must be part of the cache key so that we can handle multiple allowed origins
-				c.Header().Set(headers.Vary, "Origin")
-
-				// required if Access-Control-Request-Method and Access-Control-Request-Headers are in the requestHeaders
-				c.Header().Set(headers.AccessControlAllowMethods, supportedMethods)
-				h := append(allowedHeaders, supportedHeaders...)
-				c.Header().Set(headers.AccessControlAllowHeaders, strings.Join(h, ", "))
-
-				c.Header().Set(headers.AccessControlExposeHeaders, headers.Etag)
-
-				c.Header().Set(headers.ContentLength, "0")
-
-				c.Result.SetDefault(request.IDResponseValidOK)
+				c.Result.SetDefault(request.IDResponseValidNoContent)
 				c.Write()
 
 			} else if validOrigin {
 				// we need to check the origin and set the ACAO header in both the OPTIONS preflight and the actual request
-				c.Header().Set(headers.AccessControlAllowOrigin, origin)
+				c.Header()[headers.AccessControlAllowOrigin] = allowOrigin
+				c.Header().Add(headers.Vary, headers.Origin)
 				h(c)
 
 			} else {
@@ -88,3 +92,72 @@ func CORSMiddleware(allowedOrigins, allowedHeaders []string) Middleware {
 		}, nil
 	}
 }
+
+// CrossOriginMiddleware applies CORSMiddleware to requests with an Origin
+// header, as sent by browsers, and passes other requests through unchanged.
+// This allows endpoints used by both browsers and backend agents to answer
+// CORS requests without rejecting backend requests, which have no origin.
+func CrossOriginMiddleware(allowedOrigins, allowedHeaders []string) Middleware {
+	cors := CORSMiddleware(allowedOrigins, allowedHeaders)
+	return func(h request.Handler) (request.Handler, error) {
+		corsHandler, err := cors(h)
+		if err != nil {
+			return nil, err
+		}
+		return func(c *request.Context) {
+			if c.Request.Header.Get(headers.Origin) == "" {
+				h(c)
+				return
+			}
+			corsHandler(c)
+		}, nil
+	}
+}
+
+// originCache caches the result of matching origins against the allowed origins.
+type originCache struct {
+	allowedOrigins []string
+	maxSize        int
+
+	mu    sync.RWMutex
+	cache map[string][]string
+}
+
+func newOriginCache(allowedOrigins []string, maxSize int) *originCache {
+	return &originCache{
+		allowedOrigins: allowedOrigins,
+		maxSize:        maxSize,
+		cache:          make(map[string][]string),
+	}
+}
+
+// allowOrigin reports whether origin is allowed and, if so, returns the
+// Access-Control-Allow-Origin header value to send. The returned slice
+// is shared, and must not be modified.
+func (c *originCache) allowOrigin(origin string) ([]string, bool) {
+	c.mu.RLock()
+	value, ok := c.cache[origin]
+	c.mu.RUnlock()
+	if ok {
+		return value, value != nil
+	}
+
+	if c.isAllowed(origin) {
+		value = []string{origin}
+	}
+	c.mu.Lock()
+	if len(c.cache) < c.maxSize {
+		c.cache[origin] = value
+	}
+	c.mu.Unlock()
+	return value, value != nil
+}
+
+func (c *originCache) isAllowed(origin string) bool {
+	for _, allowed := range c.allowedOrigins {
+		if glob.Glob(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -24,8 +24,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/errorcode"
 	"github.com/elastic/apm-server/beater/headers"
 	"github.com/elastic/apm-server/beater/request"
 )
@@ -44,8 +46,8 @@ func TestCORSMiddleware(t *testing.T) {
 		assert.Equal(t, "Origin", rec.Header().Get(headers.Vary))
 		assert.Equal(t, "POST, OPTIONS", rec.Header().Get(headers.AccessControlAllowMethods))
 		assert.Equal(t, "Content-Type, Content-Encoding, Accept", rec.Header().Get(headers.AccessControlAllowHeaders))
-		assert.Equal(t, "0", rec.Header().Get(headers.ContentLength))
-		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get(headers.ContentLength))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
 		assert.Empty(t, rec.Body.String())
 	}
 
@@ -66,6 +68,7 @@ func TestCORSMiddleware(t *testing.T) {
 
 			assert.Equal(t, http.StatusAccepted, rec.Code)
 			assert.Equal(t, origin, rec.Header().Get(headers.AccessControlAllowOrigin))
+			assert.Equal(t, "Origin", rec.Header().Get(headers.Vary))
 		}
 	})
 
@@ -77,7 +80,7 @@ func TestCORSMiddleware(t *testing.T) {
 			assert.Equal(t,
 				beatertest.ResultErrWrap(fmt.Sprintf("%s: origin: '%s' is not allowed",
 					request.MapResultIDToStatus[request.IDResponseErrorsForbidden].Keyword,
-					origin)),
+					origin), errorcode.Forbidden),
 				rec.Body.String())
 		}
 	})
@@ -87,4 +90,75 @@ func TestCORSMiddleware(t *testing.T) {
 		assert.Contains(t, rec.Header().Get(headers.AccessControlAllowHeaders), "Authorization")
 	})
 
+	t.Run("CachedOrigins", func(t *testing.T) {
+		h, err := CORSMiddleware([]string{"w*yz"}, nil)(beatertest.Handler202)
+		require.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			for origin, code := range map[string]int{"wxyz": http.StatusAccepted, "xyz": http.StatusForbidden} {
+				c, rec := beatertest.ContextWithResponseRecorder(http.MethodPost, "/")
+				c.Request.Header.Set(headers.Origin, origin)
+				h(c)
+				assert.Equal(t, code, rec.Code, origin)
+			}
+		}
+	})
+
+}
+
+func TestCrossOriginMiddleware(t *testing.T) {
+	h, err := CrossOriginMiddleware([]string{"w*yz"}, nil)(beatertest.Handler202)
+	require.NoError(t, err)
+	for _, test := range []struct {
+		origin, method string
+		code           int
+	}{
+		{origin: "", method: http.MethodPost, code: http.StatusAccepted},
+		{origin: "wxyz", method: http.MethodPost, code: http.StatusAccepted},
+		{origin: "xyz", method: http.MethodPost, code: http.StatusForbidden},
+		{origin: "wxyz", method: http.MethodOptions, code: http.StatusNoContent},
+	} {
+		c, rec := beatertest.ContextWithResponseRecorder(test.method, "/")
+		if test.origin != "" {
+			c.Request.Header.Set(headers.Origin, test.origin)
+		}
+		h(c)
+		assert.Equal(t, test.code, rec.Code, test)
+		if test.origin == "" {
+			assert.Empty(t, rec.Header().Get(headers.AccessControlAllowOrigin))
+		}
+	}
+}
+
+func TestOriginCache(t *testing.T) {
+	cache := newOriginCache([]string{"w*yz", "abc"}, 2)
+	for i := 0; i < 2; i++ {
+		for _, origin := range []string{"wxyz", "xyz", "abc"} {
+			value, ok := cache.allowOrigin(origin)
+			if origin == "xyz" {
+				assert.False(t, ok)
+				assert.Nil(t, value)
+			} else {
+				assert.True(t, ok)
+				assert.Equal(t, []string{origin}, value)
+			}
+		}
+	}
+	// Only the first maxSize origins are cached.
+	assert.Len(t, cache.cache, 2)
+	assert.Contains(t, cache.cache, "wxyz")
+	assert.Contains(t, cache.cache, "xyz")
+}
+
+func BenchmarkCORSMiddlewarePreflight(b *testing.B) {
+	h, err := CORSMiddleware([]string{"*.example.com"}, []string{"Authorization"})(beatertest.Handler202)
+	require.NoError(b, err)
+	c := request.NewContext()
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set(headers.Origin, "https://www.example.com")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Reset(httptest.NewRecorder(), r)
+		h(c)
+	}
 }
@@ -102,6 +102,9 @@ func loggerWithResult(c *request.Context) *logp.Logger {
 	if c.Result.Err != nil {
 		logger = logger.With("error.message", c.Result.Err.Error())
 	}
+	if code := c.Result.ErrorCode(); code != "" {
+		logger = logger.With("error.code", string(code))
+	}
 	if c.Result.Stacktrace != "" {
 		logger = logger.With("error.stack_trace", c.Result.Stacktrace)
 	}
@@ -71,7 +71,7 @@ func TestLogMiddleware(t *testing.T) {
 			level:   zapcore.ErrorLevel,
 			handler: beatertest.Handler403,
 			code:    http.StatusForbidden,
-			ecsKeys: []string{"url.original", "error.message"},
+			ecsKeys: []string{"url.original", "error.message", "error.code"},
 		},
 		{
 			name:    "Panic",
@@ -79,7 +79,7 @@ func TestLogMiddleware(t *testing.T) {
 			level:   zapcore.ErrorLevel,
 			handler: Apply(RecoverPanicMiddleware(), beatertest.HandlerPanic),
 			code:    http.StatusInternalServerError,
-			ecsKeys: []string{"url.original", "error.message", "error.stack_trace"},
+			ecsKeys: []string{"url.original", "error.message", "error.code", "error.stack_trace"},
 		},
 		{
 			name:    "Error without keyword",
@@ -0,0 +1,181 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/request"
+	logs "github.com/elastic/apm-server/log"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	panicsRegistry = monitoring.Default.NewRegistry("apm-server.panics")
+
+	// monitoringPanics counts the panics recovered from while handling requests.
+	monitoringPanics = monitoring.NewInt(panicsRegistry, "total")
+	// monitoringBreakerTrips counts the times a route was disabled after repeated panics.
+	monitoringBreakerTrips = monitoring.NewInt(panicsRegistry, "breaker.trips")
+	// monitoringBreakerRejected counts the requests rejected by disabled routes.
+	monitoringBreakerRejected = monitoring.NewInt(panicsRegistry, "breaker.rejected")
+
+	// crashReportHeaders holds the request headers whose values are included
+	// in crash reports. Values of other headers, which may hold credentials
+	// or personal data, are redacted.
+	crashReportHeaders = map[string]bool{
+		"Accept":           true,
+		"Accept-Encoding":  true,
+		"Content-Encoding": true,
+		"Content-Length":   true,
+		"Content-Type":     true,
+		"User-Agent":       true,
+	}
+)
+
+// PanicBreaker tracks the panics of a route, disabling the route for
+// a cooldown period once threshold panics occurred within window.
+type PanicBreaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	panics    []time.Time
+	openUntil time.Time
+}
+
+// NewPanicBreaker returns a new PanicBreaker.
+func NewPanicBreaker(threshold int, window, cooldown time.Duration) *PanicBreaker {
+	return &PanicBreaker{
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow reports whether requests may currently be handled.
+func (b *PanicBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.now().Before(b.openUntil)
+}
+
+// recordPanic records a panic, reporting whether it disabled the route.
+func (b *PanicBreaker) recordPanic() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	for len(b.panics) > 0 && now.Sub(b.panics[0]) > b.window {
+		b.panics = b.panics[1:]
+	}
+	b.panics = append(b.panics, now)
+	if len(b.panics) < b.threshold {
+		return false
+	}
+	b.panics = b.panics[:0]
+	b.openUntil = now.Add(b.cooldown)
+	return true
+}
+
+// PanicIsolationMiddleware returns a middleware logging a crash report for
+// each panic recovered from by RecoverPanicMiddleware while handling requests
+// to route, and counting the panic in the apm-server.panics metrics. The crash
+// report holds the stack trace and a summary of the request, with query strings
+// and the values of headers which may hold credentials or personal data redacted.
+//
+// If breaker is not nil, requests to route are rejected with 503 Service
+// Unavailable while the breaker is open, without calling the handler.
+// The middleware is expected to wrap RecoverPanicMiddleware.
+func PanicIsolationMiddleware(route string, breaker *PanicBreaker) Middleware {
+	logger := logp.NewLogger(logs.Panic)
+	return func(h request.Handler) (request.Handler, error) {
+		return func(c *request.Context) {
+			if breaker != nil && !breaker.allow() {
+				monitoringBreakerRejected.Inc()
+				c.Result.SetWithError(
+					request.IDResponseErrorsServiceUnavailable,
+					errors.Errorf("route %s is temporarily disabled after repeated panics", route),
+				)
+				c.Write()
+				return
+			}
+			h(c)
+			if c.Result.Stacktrace == "" {
+				return
+			}
+			monitoringPanics.Inc()
+			logger.With(crashReportFields(route, c)...).Error("recovered from panic handling request")
+			if breaker != nil && breaker.recordPanic() {
+				monitoringBreakerTrips.Inc()
+				logger.With("http.route", route).Errorf(
+					"disabling route %s for %s after %d panics within %s",
+					route, breaker.cooldown, breaker.threshold, breaker.window,
+				)
+			}
+		}, nil
+	}
+}
+
+func crashReportFields(route string, c *request.Context) []interface{} {
+	r := c.Request
+	fields := []interface{}{
+		"http.route", route,
+		"http.request.method", r.Method,
+		"url.path", r.URL.Path,
+		"http.request.headers", redactHeaders(r.Header),
+		"error.stack_trace", c.Result.Stacktrace,
+	}
+	if r.URL.RawQuery != "" {
+		fields = append(fields, "url.query", redacted)
+	}
+	if r.ContentLength >= 0 {
+		fields = append(fields, "http.request.body.bytes", r.ContentLength)
+	}
+	if c.Result.Err != nil {
+		fields = append(fields, "error.message", c.Result.Err.Error())
+	}
+	return fields
+}
+
+// redactHeaders returns the request headers as a sorted list of "name: value"
+// strings, with the values of headers not in crashReportHeaders redacted.
+func redactHeaders(header http.Header) []string {
+	headers := make([]string, 0, len(header))
+	for name, values := range header {
+		value := redacted
+		if crashReportHeaders[http.CanonicalHeaderKey(name)] {
+			value = strings.Join(values, ", ")
+		}
+		headers = append(headers, name+": "+value)
+	}
+	sort.Strings(headers)
+	return headers
+}
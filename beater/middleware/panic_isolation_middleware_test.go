@@ -0,0 +1,114 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/request"
+	logs "github.com/elastic/apm-server/log"
+)
+
+func TestPanicIsolationMiddleware(t *testing.T) {
+	require.NoError(t, logp.DevelopmentSetup(logp.ToObserverOutput()))
+
+	c, w := beatertest.ContextWithResponseRecorder(http.MethodPost, "/intake/v2/events?secret=abc")
+	c.Request.Header.Set("Authorization", "Bearer abc")
+	c.Request.Header.Set("Content-Type", "application/x-ndjson")
+	total := monitoringPanics.Get()
+	Apply(PanicIsolationMiddleware("/intake/v2/events", nil), Apply(RecoverPanicMiddleware(), beatertest.HandlerPanic))(c)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, total+1, monitoringPanics.Get())
+
+	entries := logp.ObserverLogs().TakeAll()
+	require.Len(t, entries, 1)
+	assert.Equal(t, logs.Panic, entries[0].LoggerName)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "/intake/v2/events", fields["http.route"])
+	assert.Equal(t, http.MethodPost, fields["http.request.method"])
+	assert.Equal(t, "/intake/v2/events", fields["url.path"])
+	assert.Equal(t, redacted, fields["url.query"])
+	assert.Equal(t, []interface{}{"Authorization: [REDACTED]", "Content-Type: application/x-ndjson"}, fields["http.request.headers"])
+	assert.Equal(t, "panic on Handle", fields["error.message"])
+	assert.Contains(t, fields["error.stack_trace"], "HandlerPanic")
+
+	t.Run("NoPanic", func(t *testing.T) {
+		c, w := beatertest.DefaultContextWithResponseRecorder()
+		Apply(PanicIsolationMiddleware("/", nil), Apply(RecoverPanicMiddleware(), beatertest.Handler202))(c)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Empty(t, logp.ObserverLogs().TakeAll())
+	})
+}
+
+func TestPanicIsolationMiddlewareBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := NewPanicBreaker(2, time.Minute, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	var handlerCalls int
+	h := Apply(PanicIsolationMiddleware("/", breaker), Apply(RecoverPanicMiddleware(), func(c *request.Context) {
+		handlerCalls++
+		beatertest.HandlerPanic(c)
+	}))
+	serve := func() int {
+		c, w := beatertest.DefaultContextWithResponseRecorder()
+		h(c)
+		return w.Code
+	}
+	serveFrom := func(remoteAddr string) int {
+		c, w := beatertest.DefaultContextWithResponseRecorder()
+		c.Request.RemoteAddr = remoteAddr
+		h(c)
+		return w.Code
+	}
+
+	// Panics outside of the window do not disable the route.
+	assert.Equal(t, http.StatusInternalServerError, serve())
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, http.StatusInternalServerError, serve())
+	assert.Equal(t, 2, handlerCalls)
+
+	trips, rejected := monitoringBreakerTrips.Get(), monitoringBreakerRejected.Get()
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusInternalServerError, serve())
+	assert.Equal(t, trips+1, monitoringBreakerTrips.Get())
+
+	// The route is disabled until the cooldown has passed.
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	now = now.Add(59 * time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	assert.Equal(t, 3, handlerCalls)
+	assert.Equal(t, rejected+2, monitoringBreakerRejected.Get())
+
+	// The breaker is kept per route, so requests from all clients are rejected.
+	assert.Equal(t, http.StatusServiceUnavailable, serveFrom("10.4.5.6:1234"))
+	assert.Equal(t, 3, handlerCalls)
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusInternalServerError, serve())
+	assert.Equal(t, 4, handlerCalls)
+}
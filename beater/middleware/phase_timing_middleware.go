@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	metrics "github.com/rcrowley/go-metrics"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+	"github.com/elastic/beats/v7/libbeat/monitoring/adapter"
+
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/utility"
+)
+
+var (
+	phaseTimingsRegistry = monitoring.Default.NewRegistry("apm-server.phases")
+
+	// phaseTimingsSamples holds, for each phase, a sample of the
+	// durations in microseconds spent in the phase by requests.
+	phaseTimingsSamples = newPhaseTimingsSamples()
+)
+
+func newPhaseTimingsSamples() [utility.NumPhases]metrics.Sample {
+	var samples [utility.NumPhases]metrics.Sample
+	registry := adapter.NewGoMetrics(phaseTimingsRegistry, "histogram", adapter.Accept)
+	for p := utility.Phase(0); p < utility.NumPhases; p++ {
+		// Use an exponentially decaying sample, biased towards the
+		// last 5 minutes, so the histograms reflect recent requests
+		// rather than all requests since the server started.
+		samples[p] = metrics.NewExpDecaySample(1028, 0.015)
+		registry.Register(p.String()+"_us", metrics.NewHistogram(samples[p]))
+	}
+	return samples
+}
+
+// PhaseTimingMiddleware returns a middleware which makes the request's phase timings
+// available through the request context, and records the time spent in each phase of
+// processing the request in monitoring histograms once the request has been handled.
+// Phases which a request did not go through are not recorded.
+//
+// If log is true, the phase timings are also added to the request logger.
+func PhaseTimingMiddleware(log bool) Middleware {
+	return func(h request.Handler) (request.Handler, error) {
+		return func(c *request.Context) {
+			c.Request = c.Request.WithContext(utility.ContextWithPhaseTimings(c.Request.Context(), &c.PhaseTimings))
+			h(c)
+
+			var fields []interface{}
+			for p := utility.Phase(0); p < utility.NumPhases; p++ {
+				d := c.PhaseTimings.Duration(p)
+				if d <= 0 {
+					continue
+				}
+				phaseTimingsSamples[p].Update(d.Microseconds())
+				if log {
+					fields = append(fields, "phases."+p.String(), d)
+				}
+			}
+			if len(fields) > 0 && c.Logger != nil {
+				c.Logger = c.Logger.With(fields...)
+			}
+		}, nil
+	}
+}
@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/utility"
+)
+
+func TestPhaseTimingMiddleware(t *testing.T) {
+	handler := func(c *request.Context) {
+		timings := utility.PhaseTimingsFromContext(c.Request.Context())
+		require.NotNil(t, timings)
+		timings.Add(utility.PhaseDecode, 2*time.Millisecond)
+		timings.Add(utility.PhasePublish, time.Millisecond)
+	}
+
+	for _, log := range []bool{false, true} {
+		require.NoError(t, logp.DevelopmentSetup(logp.ToObserverOutput()))
+		decodeCount := phaseTimingsSamples[utility.PhaseDecode].Count()
+		publishCount := phaseTimingsSamples[utility.PhasePublish].Count()
+		authCount := phaseTimingsSamples[utility.PhaseAuth].Count()
+
+		c, _ := beatertest.DefaultContextWithResponseRecorder()
+		c.Logger = logp.NewLogger("test")
+		Apply(PhaseTimingMiddleware(log), handler)(c)
+		c.Logger.Info("handled request")
+		entries := logp.ObserverLogs().TakeAll()
+		require.Len(t, entries, 1)
+		loggedFields := entries[0].ContextMap()
+
+		assert.Equal(t, decodeCount+1, phaseTimingsSamples[utility.PhaseDecode].Count())
+		assert.Equal(t, publishCount+1, phaseTimingsSamples[utility.PhasePublish].Count())
+		assert.Equal(t, authCount, phaseTimingsSamples[utility.PhaseAuth].Count())
+
+		if log {
+			assert.Equal(t, map[string]interface{}{
+				"phases.decode":  2 * time.Millisecond,
+				"phases.publish": time.Millisecond,
+			}, loggedFields)
+		} else {
+			assert.Empty(t, loggedFields)
+		}
+	}
+}
@@ -18,9 +18,12 @@
 package middleware
 
 import (
+	"time"
+
 	"github.com/elastic/apm-server/beater/api/ratelimit"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/utility"
 )
 
 const burstMultiplier = 3
@@ -31,7 +34,27 @@ func SetIPRateLimitMiddleware(cfg *config.EventRate) Middleware {
 
 	return func(h request.Handler) (request.Handler, error) {
 		return func(c *request.Context) {
+			start := time.Now()
 			c.RateLimiter = store.ForIP(c.Request)
+			c.PhaseTimings.Add(utility.PhaseRateLimit, time.Since(start))
+			h(c)
+		}, err
+	}
+}
+
+// SetAnonymousRateLimitMiddleware sets a rate limiter for requests authorized
+// with anonymous access, replacing any rate limiter set by SetIPRateLimitMiddleware.
+// It must follow AuthorizationMiddleware.
+func SetAnonymousRateLimitMiddleware(cfg *config.EventRate) Middleware {
+	store, err := ratelimit.NewStore(cfg.LruSize, cfg.Limit, burstMultiplier)
+
+	return func(h request.Handler) (request.Handler, error) {
+		return func(c *request.Context) {
+			if c.AuthResult.Anonymous {
+				start := time.Now()
+				c.RateLimiter = store.ForIP(c.Request)
+				c.PhaseTimings.Add(utility.PhaseRateLimit, time.Since(start))
+			}
 			h(c)
 		}, err
 	}
@@ -28,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/errorcode"
 	"github.com/elastic/apm-server/beater/request"
 )
 
@@ -39,7 +40,7 @@ func TestPanicHandler(t *testing.T) {
 
 		// response assertions
 		assert.Equal(t, http.StatusForbidden, w.Code)
-		assert.Equal(t, beatertest.ResultErrWrap("forbidden request"), w.Body.String())
+		assert.Equal(t, beatertest.ResultErrWrap("forbidden request", errorcode.Forbidden), w.Body.String())
 		// result assertions e.g. for logging
 		assert.NotNil(t, c.Result.Err)
 		assert.Empty(t, c.Result.Stacktrace)
@@ -52,7 +53,7 @@ func TestPanicHandler(t *testing.T) {
 
 		// response assertions
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Equal(t, beatertest.ResultErrWrap(keywordPanic), w.Body.String())
+		assert.Equal(t, beatertest.ResultErrWrap(keywordPanic, errorcode.Internal), w.Body.String())
 
 		// result assertions e.g. for logging
 		assert.NotNil(t, c.Result.Err)
@@ -0,0 +1,49 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+)
+
+func TestResponseCompressionMiddleware(t *testing.T) {
+	handler := func(c *request.Context) {
+		c.Result.SetWithBody(request.IDResponseValidOK, strings.Repeat("a", 100))
+		c.Write()
+	}
+
+	c, rec := beatertest.DefaultContextWithResponseRecorder()
+	c.Request.Header.Set(headers.AcceptEncoding, "gzip")
+	Apply(ResponseCompressionMiddleware(10), handler)(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get(headers.ContentEncoding))
+
+	c, rec = beatertest.DefaultContextWithResponseRecorder()
+	c.Request.Header.Set(headers.AcceptEncoding, "gzip")
+	Apply(ResponseCompressionMiddleware(1000), handler)(c)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(headers.ContentEncoding))
+}
@@ -0,0 +1,116 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package opencensus
+
+import (
+	"context"
+
+	agentmetricspb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/metrics/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/ocmetrics"
+	"go.opentelemetry.io/collector/receiver/opencensusreceiver/octrace"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"google.golang.org/grpc"
+
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+// AgentName is recorded as the telemetry SDK name for data received
+// through the OpenCensus agent protocol, unless the sender specified one.
+const AgentName = "opencensus"
+
+var (
+	monitoringKeys = append(request.DefaultResultIDs,
+		request.IDResponseErrorsUnauthorized,
+		request.IDResponseErrorsTimeout,
+	)
+	gRPCMetricsRegistry      = monitoring.Default.NewRegistry("apm-server.opencensus.grpc.metrics")
+	gRPCMetricsMonitoringMap = request.MonitoringMapForRegistry(gRPCMetricsRegistry, monitoringKeys)
+	gRPCTracesRegistry       = monitoring.Default.NewRegistry("apm-server.opencensus.grpc.traces")
+	gRPCTracesMonitoringMap  = request.MonitoringMapForRegistry(gRPCTracesRegistry, monitoringKeys)
+
+	// RegistryMonitoringMaps provides mappings from the fully qualified gRPC
+	// method name to its respective monitoring map.
+	RegistryMonitoringMaps = map[string]map[request.ResultID]*monitoring.Int{
+		metricsFullMethod: gRPCMetricsMonitoringMap,
+		tracesFullMethod:  gRPCTracesMonitoringMap,
+	}
+)
+
+const (
+	metricsFullMethod = "/opencensus.proto.agent.metrics.v1.MetricsService/Export"
+	tracesFullMethod  = "/opencensus.proto.agent.trace.v1.TraceService/Export"
+)
+
+// RegisterGRPCServices registers OpenCensus agent protocol services with the
+// given gRPC server. OpenCensus data is translated to OpenTelemetry data by
+// the receivers, and then into the Elastic APM model by otel.Consumer. Spans
+// are renamed, down-sampled or dropped by instrumentation scope as described
+// by instrumentationScopes.
+func RegisterGRPCServices(grpcServer *grpc.Server, processor model.BatchProcessor, instrumentationScopes []otel.InstrumentationScopeRule) error {
+	consumer := &otel.Consumer{Processor: processor, InstrumentationScopes: instrumentationScopes}
+	traceReceiver, err := octrace.New("opencensus", tracesConsumer{consumer})
+	if err != nil {
+		return errors.Wrap(err, "failed to create OpenCensus trace receiver")
+	}
+	metricsReceiver, err := ocmetrics.New("opencensus", metricsConsumer{consumer})
+	if err != nil {
+		return errors.Wrap(err, "failed to create OpenCensus metrics receiver")
+	}
+	agenttracepb.RegisterTraceServiceServer(grpcServer, traceReceiver)
+	agentmetricspb.RegisterMetricsServiceServer(grpcServer, metricsReceiver)
+	return nil
+}
+
+// tracesConsumer wraps otel.Consumer, identifying the agent
+// as OpenCensus before translating traces.
+type tracesConsumer struct {
+	*otel.Consumer
+}
+
+func (c tracesConsumer) ConsumeTraces(ctx context.Context, traces pdata.Traces) error {
+	resourceSpans := traces.ResourceSpans()
+	for i := 0; i < resourceSpans.Len(); i++ {
+		setAgentName(resourceSpans.At(i).Resource())
+	}
+	return c.Consumer.ConsumeTraces(ctx, traces)
+}
+
+// metricsConsumer wraps otel.Consumer, identifying the agent
+// as OpenCensus before translating metrics.
+type metricsConsumer struct {
+	*otel.Consumer
+}
+
+func (c metricsConsumer) ConsumeMetrics(ctx context.Context, metrics pdata.Metrics) error {
+	resourceMetrics := metrics.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		setAgentName(resourceMetrics.At(i).Resource())
+	}
+	return c.Consumer.ConsumeMetrics(ctx, metrics)
+}
+
+func setAgentName(resource pdata.Resource) {
+	// InsertString does nothing if the attribute already exists.
+	resource.Attributes().InsertString(conventions.AttributeTelemetrySDKName, AgentName)
+}
@@ -0,0 +1,109 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package opencensus_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/elastic/apm-server/beater/interceptors"
+	"github.com/elastic/apm-server/beater/opencensus"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+func TestExportTraces(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	var batchProcessor model.ProcessBatchFunc = func(ctx context.Context, batch *model.Batch) error {
+		batches <- batch
+		return nil
+	}
+
+	conn := newServer(t, batchProcessor)
+	client := agenttracepb.NewTraceServiceClient(conn)
+	stream, err := client.Export(context.Background())
+	require.NoError(t, err)
+
+	err = stream.Send(&agenttracepb.ExportTraceServiceRequest{
+		Node: &commonpb.Node{
+			ServiceInfo: &commonpb.ServiceInfo{Name: "service_name"},
+			LibraryInfo: &commonpb.LibraryInfo{
+				Language:           commonpb.LibraryInfo_GO_LANG,
+				CoreLibraryVersion: "0.22.0",
+			},
+		},
+		Spans: []*tracepb.Span{{
+			TraceId: []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+			SpanId:  []byte{0, 1, 2, 3, 4, 5, 6, 7},
+			Name:    &tracepb.TruncatableString{Value: "operation_name"},
+		}},
+	})
+	require.NoError(t, err)
+
+	var batch *model.Batch
+	select {
+	case batch = <-batches:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for batch")
+	}
+	require.NoError(t, stream.CloseSend())
+	_, err = stream.Recv()
+	assert.Error(t, err) // io.EOF
+
+	require.Len(t, batch.Transactions, 1)
+	tx := batch.Transactions[0]
+	assert.Equal(t, "operation_name", tx.Name)
+	assert.Equal(t, "000102030405060708090a0b0c0d0e0f", tx.TraceID)
+	assert.Equal(t, "service_name", tx.Metadata.Service.Name)
+	assert.Equal(t, "opencensus/go", tx.Metadata.Service.Agent.Name)
+	assert.Equal(t, "0.22.0", tx.Metadata.Service.Agent.Version)
+
+	actual := map[string]interface{}{}
+	monitoring.GetRegistry("apm-server.opencensus.grpc.traces").Do(monitoring.Full, func(key string, value interface{}) {
+		actual[key] = value
+	})
+	assert.Equal(t, int64(1), actual["request.count"])
+}
+
+func newServer(t *testing.T, batchProcessor model.BatchProcessor) *grpc.ClientConn {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	logger := logp.NewLogger("opencensus.grpc.test")
+	srv := grpc.NewServer(
+		grpc.StreamInterceptor(interceptors.StreamMetrics(logger, opencensus.RegistryMonitoringMaps)),
+	)
+	err = opencensus.RegisterGRPCServices(srv, batchProcessor, nil)
+	require.NoError(t, err)
+
+	go srv.Serve(lis)
+	t.Cleanup(srv.GracefulStop)
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
@@ -0,0 +1,126 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/featureflag"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+)
+
+// Exporter is a model.BatchProcessor which translates transactions and
+// spans into OpenTelemetry traces, and exports them with OTLP/gRPC.
+//
+// Traces are queued and sent in the background, with retries. ProcessBatch
+// never blocks on the remote endpoint, and never fails: if the queue is full,
+// the traces are dropped and a warning is logged.
+type Exporter struct {
+	logger *logp.Logger
+	traces component.TracesExporter
+}
+
+// NewExporter returns a new Exporter with the given configuration.
+// The exporter begins connecting to the configured endpoint immediately,
+// and must be shut down by calling Shutdown.
+func NewExporter(cfg config.OTelExportConfig) (*Exporter, error) {
+	factory := otlpexporter.NewFactory()
+	exporterConfig := factory.CreateDefaultConfig().(*otlpexporter.Config)
+	exporterConfig.Endpoint = cfg.Endpoint
+	exporterConfig.Compression = cfg.Compression
+	exporterConfig.TLSSetting.Insecure = cfg.Insecure
+	exporterConfig.Timeout = cfg.Timeout
+	for k, v := range cfg.Headers {
+		exporterConfig.Headers[k] = v
+	}
+
+	ctx := context.Background()
+	params := component.ExporterCreateParams{Logger: zapLogger(logs.OtelExport)}
+	traces, err := factory.CreateTracesExporter(ctx, params, exporterConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := traces.Start(ctx, exporterHost{}); err != nil {
+		return nil, err
+	}
+	return &Exporter{logger: logp.NewLogger(logs.OtelExport), traces: traces}, nil
+}
+
+// ProcessBatch translates the transactions and spans in batch into
+// OpenTelemetry traces, and enqueues them for export.
+//
+// Nothing is exported while the OTLPExport feature flag is disabled.
+func (e *Exporter) ProcessBatch(ctx context.Context, batch *model.Batch) error {
+	if !featureflag.Enabled(featureflag.OTLPExport) {
+		return nil
+	}
+	traces := otel.TracesFromBatch(batch)
+	if traces.SpanCount() == 0 {
+		return nil
+	}
+	if err := e.traces.ConsumeTraces(ctx, traces); err != nil {
+		e.logger.With(logp.Error(err)).Warn("failed to enqueue traces for export")
+	}
+	return nil
+}
+
+// Shutdown stops the exporter, waiting for queued traces to be sent
+// until ctx is cancelled.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	return e.traces.Shutdown(ctx)
+}
+
+// zapLogger returns a *zap.Logger which writes to the libbeat logging
+// core, for passing to OpenTelemetry Collector components.
+func zapLogger(selector string) *zap.Logger {
+	var core zapcore.Core
+	logp.NewLogger(selector, zap.WrapCore(func(in zapcore.Core) zapcore.Core {
+		core = in
+		return in
+	}))
+	return zap.New(core).Named(selector)
+}
+
+// exporterHost is a component.Host for starting OpenTelemetry Collector
+// exporters outside of the collector.
+type exporterHost struct{}
+
+func (exporterHost) ReportFatalError(error) {}
+
+func (exporterHost) GetFactory(component.Kind, configmodels.Type) component.Factory {
+	return nil
+}
+
+func (exporterHost) GetExtensions() map[configmodels.NamedEntity]component.Extension {
+	return nil
+}
+
+func (exporterHost) GetExporters() map[configmodels.DataType]map[configmodels.NamedEntity]component.Exporter {
+	return nil
+}
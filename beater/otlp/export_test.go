@@ -0,0 +1,97 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/otlp"
+	"github.com/elastic/apm-server/model"
+)
+
+func TestExporter(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	var batchProcessor model.ProcessBatchFunc = func(ctx context.Context, batch *model.Batch) error {
+		batches <- batch
+		return nil
+	}
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	err = otlp.RegisterGRPCServices(srv, batchProcessor, config.OTelConfig{})
+	require.NoError(t, err)
+	go srv.Serve(lis)
+	defer srv.GracefulStop()
+
+	exporter, err := otlp.NewExporter(config.OTelExportConfig{
+		Enabled:  true,
+		Endpoint: lis.Addr().String(),
+		Insecure: true,
+		Timeout:  time.Second,
+	})
+	require.NoError(t, err)
+	defer exporter.Shutdown(context.Background())
+
+	err = exporter.ProcessBatch(context.Background(), &model.Batch{
+		Transactions: []*model.Transaction{{
+			Metadata:  model.Metadata{Service: model.Service{Name: "service_name"}},
+			TraceID:   "0123456789abcdef0123456789abcdef",
+			ID:        "945254c567a5417e",
+			Name:      "transaction_name",
+			Timestamp: time.Unix(123, 0),
+		}},
+		// Errors are not exported.
+		Errors: []*model.Error{{}},
+	})
+	require.NoError(t, err)
+
+	select {
+	case batch := <-batches:
+		require.Len(t, batch.Transactions, 1)
+		tx := batch.Transactions[0]
+		assert.Equal(t, "service_name", tx.Metadata.Service.Name)
+		assert.Equal(t, "0123456789abcdef0123456789abcdef", tx.TraceID)
+		assert.Equal(t, "945254c567a5417e", tx.ID)
+		assert.Equal(t, "transaction_name", tx.Name)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for exported traces")
+	}
+}
+
+func TestExporterNoTraces(t *testing.T) {
+	exporter, err := otlp.NewExporter(config.OTelExportConfig{
+		Enabled:  true,
+		Endpoint: "localhost:0",
+		Insecure: true,
+		Timeout:  time.Second,
+	})
+	require.NoError(t, err)
+	defer exporter.Shutdown(context.Background())
+
+	err = exporter.ProcessBatch(context.Background(), &model.Batch{Errors: []*model.Error{{}}})
+	assert.NoError(t, err)
+}
@@ -23,10 +23,12 @@ import (
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver/logs"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/metrics"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/trace"
 	"google.golang.org/grpc"
 
+	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/request"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/processor/otel"
@@ -42,66 +44,136 @@ var (
 	gRPCMetricsMonitoringMap = request.MonitoringMapForRegistry(gRPCMetricsRegistry, monitoringKeys)
 	gRPCTracesRegistry       = monitoring.Default.NewRegistry("apm-server.otlp.grpc.traces")
 	gRPCTracesMonitoringMap  = request.MonitoringMapForRegistry(gRPCTracesRegistry, monitoringKeys)
+	gRPCLogsRegistry         = monitoring.Default.NewRegistry("apm-server.otlp.grpc.logs")
+	gRPCLogsMonitoringMap    = request.MonitoringMapForRegistry(gRPCLogsRegistry, monitoringKeys)
 
 	// RegistryMonitoringMaps provides mappings from the fully qualified gRPC
 	// method name to its respective monitoring map.
 	RegistryMonitoringMaps = map[string]map[request.ResultID]*monitoring.Int{
 		metricsFullMethod: gRPCMetricsMonitoringMap,
 		tracesFullMethod:  gRPCTracesMonitoringMap,
+		logsFullMethod:    gRPCLogsMonitoringMap,
 	}
+
+	gRPCConsumer monitoredConsumer
 )
 
 const (
 	metricsFullMethod = "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export"
 	tracesFullMethod  = "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+	logsFullMethod    = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
 )
 
 func init() {
-	monitoring.NewFunc(gRPCMetricsRegistry, "consumer", collectMetricsMonitoring, monitoring.Report)
+	monitoring.NewFunc(gRPCMetricsRegistry, "consumer", gRPCConsumer.collectMetricsMonitoring, monitoring.Report)
+	monitoring.NewFunc(gRPCLogsRegistry, "consumer", gRPCConsumer.collectLogsMonitoring, monitoring.Report)
+}
+
+// newConsumer returns an otel.Consumer which passes OpenTelemetry data,
+// translated as described by otelConfig, to processor.
+//
+// Spans are renamed, down-sampled, or dropped according to their
+// instrumentation scope. Resource attributes are mapped to metadata fields
+// according to the resource mappings. Log records below the configured
+// minimum severity are dropped.
+func newConsumer(processor model.BatchProcessor, otelConfig config.OTelConfig) *otel.Consumer {
+	return &otel.Consumer{
+		Processor:             processor,
+		InstrumentationScopes: InstrumentationScopeRules(otelConfig),
+		ResourceMappings:      otelConfig.ResourceMappings,
+		MinLogSeverity:        otel.LogSeverity(otelConfig.Logs.MinSeverity),
+	}
+}
+
+// InstrumentationScopeRules returns the configured instrumentation scope
+// rules of otelConfig, for use in an otel.Consumer.
+func InstrumentationScopeRules(otelConfig config.OTelConfig) []otel.InstrumentationScopeRule {
+	if len(otelConfig.InstrumentationScopes) == 0 {
+		return nil
+	}
+	rules := make([]otel.InstrumentationScopeRule, len(otelConfig.InstrumentationScopes))
+	for i, scope := range otelConfig.InstrumentationScopes {
+		rules[i] = otel.InstrumentationScopeRule{
+			Name:       scope.Name,
+			Rename:     scope.Rename,
+			SampleRate: scope.SampleRate,
+		}
+	}
+	return rules
 }
 
 // RegisterGRPCServices registers OTLP consumer services with the given gRPC server.
-func RegisterGRPCServices(grpcServer *grpc.Server, processor model.BatchProcessor) error {
-	consumer := &otel.Consumer{Processor: processor}
+//
+// OpenTelemetry data is translated as described by otelConfig; see
+// newConsumer.
+func RegisterGRPCServices(
+	grpcServer *grpc.Server,
+	processor model.BatchProcessor,
+	otelConfig config.OTelConfig,
+) error {
+	consumer := newConsumer(processor, otelConfig)
 
 	// TODO(axw) stop assuming we have only one OTLP gRPC service running
 	// at any time, and instead aggregate metrics from consumers that are
 	// dynamically registered and unregistered.
-	setCurrentMonitoredConsumer(consumer)
+	gRPCConsumer.set(consumer)
 
 	traceReceiver := trace.New("otlp", consumer)
 	metricsReceiver := metrics.New("otlp", consumer)
+	logsReceiver := logs.New("otlp", consumer)
 	if err := otlpreceiver.RegisterTraceReceiver(context.Background(), traceReceiver, grpcServer, nil); err != nil {
 		return errors.Wrap(err, "failed to register OTLP trace receiver")
 	}
 	if err := otlpreceiver.RegisterMetricsReceiver(context.Background(), metricsReceiver, grpcServer, nil); err != nil {
 		return errors.Wrap(err, "failed to register OTLP metrics receiver")
 	}
+	if err := otlpreceiver.RegisterLogsReceiver(context.Background(), logsReceiver, grpcServer, nil); err != nil {
+		return errors.Wrap(err, "failed to register OTLP logs receiver")
+	}
 	return nil
 }
 
-var (
-	currentMonitoredConsumerMu sync.RWMutex
-	currentMonitoredConsumer   *otel.Consumer
-)
+// monitoredConsumer holds the most recently registered otel.Consumer,
+// whose stats are reported in a monitoring registry.
+type monitoredConsumer struct {
+	mu       sync.RWMutex
+	consumer *otel.Consumer
+}
 
-func setCurrentMonitoredConsumer(c *otel.Consumer) {
-	currentMonitoredConsumerMu.Lock()
-	defer currentMonitoredConsumerMu.Unlock()
-	currentMonitoredConsumer = c
+func (m *monitoredConsumer) set(c *otel.Consumer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.consumer = c
 }
 
-func collectMetricsMonitoring(mode monitoring.Mode, V monitoring.Visitor) {
-	currentMonitoredConsumerMu.RLock()
-	c := currentMonitoredConsumer
-	currentMonitoredConsumerMu.RUnlock()
+func (m *monitoredConsumer) stats() (otel.ConsumerStats, bool) {
+	m.mu.RLock()
+	c := m.consumer
+	m.mu.RUnlock()
 	if c == nil {
+		return otel.ConsumerStats{}, false
+	}
+	return c.Stats(), true
+}
+
+func (m *monitoredConsumer) collectMetricsMonitoring(mode monitoring.Mode, V monitoring.Visitor) {
+	stats, ok := m.stats()
+	if !ok {
 		return
 	}
 
 	V.OnRegistryStart()
 	defer V.OnRegistryFinished()
-
-	stats := c.Stats()
 	monitoring.ReportInt(V, "unsupported_dropped", stats.UnsupportedMetricsDropped)
 }
+
+func (m *monitoredConsumer) collectLogsMonitoring(mode monitoring.Mode, V monitoring.Visitor) {
+	stats, ok := m.stats()
+	if !ok {
+		return
+	}
+
+	V.OnRegistryStart()
+	defer V.OnRegistryFinished()
+	monitoring.ReportInt(V, "unsupported_dropped", stats.UnsupportedLogsDropped)
+}
@@ -32,6 +32,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/status"
 
+	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/interceptors"
 	"github.com/elastic/apm-server/beater/otlp"
 	"github.com/elastic/apm-server/model"
@@ -44,6 +45,8 @@ var (
 	exportMetricsServiceResponseType = proto.MessageType("opentelemetry.proto.collector.metrics.v1.ExportMetricsServiceResponse")
 	exportTraceServiceRequestType    = proto.MessageType("opentelemetry.proto.collector.trace.v1.ExportTraceServiceRequest")
 	exportTraceServiceResponseType   = proto.MessageType("opentelemetry.proto.collector.trace.v1.ExportTraceServiceResponse")
+	exportLogsServiceRequestType     = proto.MessageType("opentelemetry.proto.collector.logs.v1.ExportLogsServiceRequest")
+	exportLogsServiceResponseType    = proto.MessageType("opentelemetry.proto.collector.logs.v1.ExportLogsServiceResponse")
 )
 
 func TestConsumeTraces(t *testing.T) {
@@ -173,6 +176,64 @@ func TestConsumeMetrics(t *testing.T) {
 	}, actual)
 }
 
+func TestConsumeLogs(t *testing.T) {
+	var batches []*model.Batch
+	var batchProcessor model.ProcessBatchFunc = func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	}
+
+	// Send a minimal log to verify that everything is connected properly.
+	//
+	// We intentionally do not check the published event contents; those are
+	// tested in processor/otel.
+	cannedRequest := jsonExportLogsServiceRequest(`{
+"resource_logs": [
+  {
+    "instrumentation_library_logs": [
+      {
+        "logs": [
+	  {
+	    "severity_number": 17,
+	    "name": "error"
+	  },
+	  {
+	    "severity_number": 9,
+	    "name": "info"
+	  }
+	]
+      }
+    ]
+  }
+]
+}`)
+
+	conn := newServer(t, batchProcessor)
+	err := conn.Invoke(
+		context.Background(), "/opentelemetry.proto.collector.logs.v1.LogsService/Export",
+		cannedRequest, newExportLogsServiceResponse(),
+	)
+	assert.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0].Errors, 1)
+
+	actual := map[string]interface{}{}
+	monitoring.GetRegistry("apm-server.otlp.grpc.logs").Do(monitoring.Full, func(key string, value interface{}) {
+		actual[key] = value
+	})
+	assert.Equal(t, map[string]interface{}{
+		// The INFO log record is dropped.
+		"consumer.unsupported_dropped": int64(1),
+
+		"request.count":                int64(1),
+		"response.count":               int64(1),
+		"response.errors.count":        int64(0),
+		"response.valid.count":         int64(1),
+		"response.errors.timeout":      int64(0),
+		"response.errors.unauthorized": int64(0),
+	}, actual)
+}
+
 func jsonExportTraceServiceRequest(j string) interface{} {
 	request := reflect.New(exportTraceServiceRequestType.Elem()).Interface()
 	decoder := json.NewDecoder(strings.NewReader(j))
@@ -201,6 +262,20 @@ func newExportMetricsServiceResponse() interface{} {
 	return reflect.New(exportMetricsServiceResponseType.Elem()).Interface()
 }
 
+func jsonExportLogsServiceRequest(j string) interface{} {
+	request := reflect.New(exportLogsServiceRequestType.Elem()).Interface()
+	decoder := json.NewDecoder(strings.NewReader(j))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(request); err != nil {
+		panic(err)
+	}
+	return request
+}
+
+func newExportLogsServiceResponse() interface{} {
+	return reflect.New(exportLogsServiceResponseType.Elem()).Interface()
+}
+
 func newServer(t *testing.T, batchProcessor model.BatchProcessor) *grpc.ClientConn {
 	lis, err := net.Listen("tcp", "localhost:0")
 	require.NoError(t, err)
@@ -208,7 +283,7 @@ func newServer(t *testing.T, batchProcessor model.BatchProcessor) *grpc.ClientCo
 	srv := grpc.NewServer(
 		grpc.UnaryInterceptor(interceptors.Metrics(logger, otlp.RegistryMonitoringMaps)),
 	)
-	err = otlp.RegisterGRPCServices(srv, batchProcessor)
+	err = otlp.RegisterGRPCServices(srv, batchProcessor, config.OTelConfig{})
 	require.NoError(t, err)
 
 	go srv.Serve(lis)
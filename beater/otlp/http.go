@@ -0,0 +1,320 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/authorization"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/decoder"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/publish"
+)
+
+var (
+	httpMetricsRegistry = monitoring.Default.NewRegistry("apm-server.otlp.http.metrics")
+	httpTracesRegistry  = monitoring.Default.NewRegistry("apm-server.otlp.http.traces")
+	httpLogsRegistry    = monitoring.Default.NewRegistry("apm-server.otlp.http.logs")
+
+	// HTTPMetricsMonitoringMap holds a mapping for request.IDs to
+	// monitoring counters for OTLP/HTTP metrics requests.
+	HTTPMetricsMonitoringMap = request.DefaultMonitoringMapForRegistry(httpMetricsRegistry)
+	// HTTPTracesMonitoringMap holds a mapping for request.IDs to
+	// monitoring counters for OTLP/HTTP traces requests.
+	HTTPTracesMonitoringMap = request.DefaultMonitoringMapForRegistry(httpTracesRegistry)
+	// HTTPLogsMonitoringMap holds a mapping for request.IDs to
+	// monitoring counters for OTLP/HTTP logs requests.
+	HTTPLogsMonitoringMap = request.DefaultMonitoringMapForRegistry(httpLogsRegistry)
+
+	httpConsumer monitoredConsumer
+)
+
+const (
+	protobufMediaType = "application/x-protobuf"
+	jsonMediaType     = "application/json"
+
+	// maxHTTPRequestSize is the maximum size of an uncompressed
+	// OTLP/HTTP request body, matching the default maximum message
+	// size accepted by OTLP/gRPC.
+	maxHTTPRequestSize = 4 * 1024 * 1024
+)
+
+var errTooManyDecoders = errors.New("too many concurrent decoders")
+
+func init() {
+	monitoring.NewFunc(httpMetricsRegistry, "consumer", httpConsumer.collectMetricsMonitoring, monitoring.Report)
+	monitoring.NewFunc(httpLogsRegistry, "consumer", httpConsumer.collectLogsMonitoring, monitoring.Report)
+}
+
+// HTTPHandlers holds request.Handlers for the OTLP/HTTP endpoints.
+type HTTPHandlers struct {
+	Traces  request.Handler
+	Metrics request.Handler
+	Logs    request.Handler
+}
+
+// NewHTTPHandlers returns request.Handlers for receiving OTLP traces,
+// metrics and logs over HTTP, encoded as either protobuf or JSON.
+//
+// OpenTelemetry data is translated as described by otelConfig; see
+// newConsumer.
+//
+// If limiter is non-nil, a decoder is acquired from it while decoding each
+// request body, after the body has been read in full.
+func NewHTTPHandlers(
+	processor model.BatchProcessor,
+	otelConfig config.OTelConfig,
+	limiter *stream.DecodeLimiter,
+) HTTPHandlers {
+	consumer := newConsumer(processor, otelConfig)
+	httpConsumer.set(consumer)
+
+	return HTTPHandlers{
+		Traces: httpHandler(
+			"opentelemetry.proto.collector.trace.v1.ExportTraceServiceRequest",
+			limiter,
+			func(data []byte) (func(context.Context) error, error) {
+				traces := pdata.NewTraces()
+				if err := traces.FromOtlpProtoBytes(data); err != nil {
+					return nil, err
+				}
+				return func(ctx context.Context) error {
+					return consumer.ConsumeTraces(ctx, traces)
+				}, nil
+			},
+		),
+		Metrics: httpHandler(
+			"opentelemetry.proto.collector.metrics.v1.ExportMetricsServiceRequest",
+			limiter,
+			func(data []byte) (func(context.Context) error, error) {
+				metrics := pdata.NewMetrics()
+				if err := metrics.FromOtlpProtoBytes(data); err != nil {
+					return nil, err
+				}
+				return func(ctx context.Context) error {
+					return consumer.ConsumeMetrics(ctx, metrics)
+				}, nil
+			},
+		),
+		Logs: httpHandler(
+			"opentelemetry.proto.collector.logs.v1.ExportLogsServiceRequest",
+			limiter,
+			func(data []byte) (func(context.Context) error, error) {
+				logs := pdata.NewLogs()
+				if err := logs.FromOtlpProtoBytes(data); err != nil {
+					return nil, err
+				}
+				return func(ctx context.Context) error {
+					return consumer.ConsumeLogs(ctx, logs)
+				}, nil
+			},
+		),
+	}
+}
+
+// httpHandler returns a request.Handler which reads an OTLP export
+// request, and passes its protobuf encoding to decode. The function
+// returned by decode is then called to consume the decoded request.
+//
+// JSON-encoded requests are decoded into the message type identified
+// by requestMessageName, and re-encoded as protobuf.
+func httpHandler(
+	requestMessageName string,
+	limiter *stream.DecodeLimiter,
+	decode func([]byte) (func(context.Context) error, error),
+) request.Handler {
+	requestMessageType := proto.MessageType(requestMessageName)
+	handle := func(c *request.Context) (string, error) {
+		if c.Request.Method != http.MethodPost {
+			return "", requestError{
+				id:  request.IDResponseErrorsMethodNotAllowed,
+				err: errors.New("only POST requests are supported"),
+			}
+		}
+		mediaType, _, err := mime.ParseMediaType(c.Request.Header.Get(headers.ContentType))
+		if err != nil || (mediaType != protobufMediaType && mediaType != jsonMediaType) {
+			return "", requestError{
+				id: request.IDResponseErrorsValidate,
+				err: fmt.Errorf(
+					"invalid content type %q, expected %q or %q",
+					c.Request.Header.Get(headers.ContentType), protobufMediaType, jsonMediaType,
+				),
+			}
+		}
+
+		ok := c.RateLimiter == nil || c.RateLimiter.Allow()
+		if !ok {
+			return "", requestError{
+				id:  request.IDResponseErrorsRateLimit,
+				err: errors.New("rate limit exceeded"),
+			}
+		}
+
+		reader, err := decoder.CompressedRequestReader(c.Request)
+		if err != nil {
+			return "", requestError{
+				id:  request.IDResponseErrorsValidate,
+				err: err,
+			}
+		}
+		defer reader.Close()
+		r := &decoder.LimitedReader{R: reader, N: maxHTTPRequestSize}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			if r.N < 0 {
+				return "", requestError{
+					id:  request.IDResponseErrorsRequestTooLarge,
+					err: err,
+				}
+			}
+			return "", requestError{
+				id:  request.IDResponseErrorsDecode,
+				err: errors.Wrap(err, "failed to read request body"),
+			}
+		}
+
+		if !limiter.Acquire(c.Request.Context()) {
+			return "", requestError{
+				id:  request.IDResponseErrorsFullQueue,
+				err: errTooManyDecoders,
+			}
+		}
+		consume, err := decodeRequest(requestMessageType, mediaType, data, decode)
+		limiter.Release()
+		if err != nil {
+			if err, ok := err.(decodeError); ok {
+				return "", requestError{
+					id:  request.IDResponseErrorsDecode,
+					err: errors.Wrap(err.err, "failed to decode request"),
+				}
+			}
+			return "", err
+		}
+
+		// Trace context propagated in the request headers is used
+		// for spans whose trace or parent IDs are missing.
+		ctx := otel.ContextWithTraceHeaders(c.Request.Context(), c.Request.Header)
+		if err := consume(ctx); err != nil {
+			switch err {
+			case publish.ErrChannelClosed:
+				return "", requestError{
+					id:  request.IDResponseErrorsShuttingDown,
+					err: errors.New("server is shutting down"),
+				}
+			case publish.ErrFull:
+				return "", requestError{
+					id:  request.IDResponseErrorsFullQueue,
+					err: err,
+				}
+			}
+			var serviceErr *authorization.ServiceUnauthorizedError
+			if errors.As(err, &serviceErr) {
+				return "", requestError{
+					id:  request.IDResponseErrorsForbidden,
+					err: err,
+				}
+			}
+			return "", err
+		}
+		return mediaType, nil
+	}
+	return func(c *request.Context) {
+		mediaType, err := handle(c)
+		if err != nil {
+			switch err := err.(type) {
+			case requestError:
+				c.Result.SetWithError(err.id, err)
+			default:
+				c.Result.SetWithError(request.IDResponseErrorsInternal, err)
+			}
+			c.Write()
+			return
+		}
+		// The response is an empty Export*ServiceResponse message,
+		// which is encoded as zero bytes in protobuf.
+		c.Result.SetDefault(request.IDResponseValidOK)
+		c.Header().Set(headers.ContentType, mediaType)
+		w := c.Stream(c.Result.StatusCode)
+		if mediaType == jsonMediaType {
+			w.Write([]byte("{}"))
+		}
+	}
+}
+
+type requestError struct {
+	id  request.ResultID
+	err error
+}
+
+func (e requestError) Error() string {
+	return e.err.Error()
+}
+
+// decodeError is returned by decodeRequest when the
+// request body could not be decoded.
+type decodeError struct {
+	err error
+}
+
+func (e decodeError) Error() string {
+	return e.err.Error()
+}
+
+// decodeRequest decodes an OTLP export request body with the given media
+// type, returning a function for consuming the decoded request.
+func decodeRequest(
+	requestMessageType reflect.Type,
+	mediaType string,
+	data []byte,
+	decode func([]byte) (func(context.Context) error, error),
+) (func(context.Context) error, error) {
+	if mediaType == jsonMediaType {
+		msg := reflect.New(requestMessageType.Elem()).Interface().(proto.Message)
+		unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+		if err := unmarshaler.Unmarshal(bytes.NewReader(data), msg); err != nil {
+			return nil, decodeError{err}
+		}
+		var err error
+		if data, err = proto.Marshal(msg); err != nil {
+			return nil, err
+		}
+	}
+	consume, err := decode(data)
+	if err != nil {
+		return nil, decodeError{err}
+	}
+	return consume, nil
+}
@@ -0,0 +1,287 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otlp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/otlp"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/stream"
+	"github.com/elastic/apm-server/publish"
+)
+
+func TestHTTPTracesProtobuf(t *testing.T) {
+	var batches []*model.Batch
+	handlers := otlp.NewHTTPHandlers(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	}), config.OTelConfig{}, nil)
+
+	traces := pdata.NewTraces()
+	traces.ResourceSpans().Resize(1)
+	traces.ResourceSpans().At(0).InstrumentationLibrarySpans().Resize(1)
+	spans := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(1)
+	spans.At(0).SetTraceID(pdata.NewTraceID([16]byte{1}))
+	spans.At(0).SetSpanID(pdata.NewSpanID([8]byte{2}))
+	spans.At(0).SetName("operation_name")
+	body, err := traces.ToOtlpProtoBytes()
+	require.NoError(t, err)
+
+	rec := sendHTTPRequest(handlers.Traces, "application/x-protobuf", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-protobuf", rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Body.String())
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Transactions, 1)
+	assert.Equal(t, "operation_name", batches[0].Transactions[0].Name)
+}
+
+func TestHTTPTracesJSON(t *testing.T) {
+	var batches []*model.Batch
+	handlers := otlp.NewHTTPHandlers(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	}), config.OTelConfig{}, nil)
+
+	rec := sendHTTPRequest(handlers.Traces, "application/json", []byte(`{
+  "resourceSpans": [{
+    "instrumentationLibrarySpans": [{
+      "spans": [{
+        "traceId": "0123456789abcdef0123456789abcdef",
+        "spanId": "945254c567a5417e",
+        "name": "operation_name",
+        "unknownField": true
+      }]
+    }]
+  }]
+}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "{}", rec.Body.String())
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Transactions, 1)
+	tx := batches[0].Transactions[0]
+	assert.Equal(t, "operation_name", tx.Name)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", tx.TraceID)
+	assert.Equal(t, "945254c567a5417e", tx.ID)
+}
+
+func TestHTTPTracesPropagationHeaders(t *testing.T) {
+	var batches []*model.Batch
+	handlers := otlp.NewHTTPHandlers(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	}), config.OTelConfig{}, nil)
+
+	// The root span has no trace ID, and the child span has a trace ID
+	// of its own; only missing IDs are taken from the B3 headers.
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{
+  "resourceSpans": [{
+    "instrumentationLibrarySpans": [{
+      "spans": [{
+        "spanId": "945254c567a5417e",
+        "name": "root"
+      }, {
+        "traceId": "0123456789abcdef0123456789abcdef",
+        "spanId": "b4d2b80e9d8c6e2c",
+        "parentSpanId": "945254c567a5417e",
+        "kind": "SPAN_KIND_CLIENT",
+        "name": "child"
+      }]
+    }]
+  }]
+}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-B3-TraceId", "80f198ee56343ba864fe8b2a57d3eff7")
+	req.Header.Set("X-B3-SpanId", "e457b5a2e4d86bd1")
+	req.Header.Set("X-B3-Sampled", "1")
+	rec := serveHTTP(handlers.Traces, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Transactions, 1)
+	require.Len(t, batches[0].Spans, 1)
+
+	tx := batches[0].Transactions[0]
+	assert.Equal(t, "80f198ee56343ba864fe8b2a57d3eff7", tx.TraceID)
+	assert.Equal(t, "e457b5a2e4d86bd1", tx.ParentID)
+	assert.Equal(t, "945254c567a5417e", tx.ID)
+	assert.Equal(t, true, tx.Labels["b3_sampled"])
+
+	span := batches[0].Spans[0]
+	assert.Equal(t, "0123456789abcdef0123456789abcdef", span.TraceID)
+	assert.Equal(t, "945254c567a5417e", span.ParentID)
+	assert.NotContains(t, span.Labels, "b3_trace_id")
+}
+
+func TestHTTPMetricsJSON(t *testing.T) {
+	var batches []*model.Batch
+	handlers := otlp.NewHTTPHandlers(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	}), config.OTelConfig{}, nil)
+
+	rec := sendHTTPRequest(handlers.Metrics, "application/json", []byte(`{
+  "resourceMetrics": [{
+    "instrumentationLibraryMetrics": [{
+      "metrics": [{
+        "name": "int_gauge_metric",
+        "intGauge": {"dataPoints": [{"timeUnixNano": "1", "value": "1"}]}
+      }]
+    }]
+  }]
+}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Metricsets, 1)
+	assert.Equal(t, []model.Sample{{Name: "int_gauge_metric", Value: 1}}, batches[0].Metricsets[0].Samples)
+}
+
+func TestHTTPLogsGzip(t *testing.T) {
+	var batches []*model.Batch
+	handlers := otlp.NewHTTPHandlers(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	}), config.OTelConfig{}, nil)
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(`{
+  "resourceLogs": [{
+    "instrumentationLibraryLogs": [{
+      "logs": [{"severityNumber": "SEVERITY_NUMBER_ERROR", "name": "error"}]
+    }]
+  }]
+}`))
+	require.NoError(t, zw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/", &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	rec := serveHTTP(handlers.Logs, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0].Errors, 1)
+}
+
+func TestHTTPErrors(t *testing.T) {
+	var processErr error
+	handlers := otlp.NewHTTPHandlers(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		return processErr
+	}), config.OTelConfig{}, nil)
+
+	for name, test := range map[string]struct {
+		method      string
+		contentType string
+		body        string
+		processErr  error
+		status      int
+		err         string
+	}{
+		"method": {
+			method:      http.MethodGet,
+			contentType: "application/json",
+			status:      http.StatusMethodNotAllowed,
+			err:         "only POST requests are supported",
+		},
+		"content_type": {
+			contentType: "text/plain",
+			status:      http.StatusBadRequest,
+			err:         "invalid content type",
+		},
+		"invalid_json": {
+			contentType: "application/json",
+			body:        `{"resourceSpans": 123}`,
+			status:      http.StatusBadRequest,
+			err:         "failed to decode request",
+		},
+		"invalid_protobuf": {
+			contentType: "application/x-protobuf",
+			body:        "\xff\xff\xff",
+			status:      http.StatusBadRequest,
+			err:         "failed to decode request",
+		},
+		"queue_full": {
+			contentType: "application/json",
+			body:        `{}`,
+			processErr:  publish.ErrFull,
+			status:      http.StatusServiceUnavailable,
+			err:         "queue is full",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			processErr = test.processErr
+			method := test.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+			rec := serveHTTP(handlers.Traces, req)
+			assert.Equal(t, test.status, rec.Code)
+			assert.Contains(t, rec.Body.String(), test.err)
+		})
+	}
+}
+
+func TestHTTPDecodeLimiter(t *testing.T) {
+	var batches []*model.Batch
+	limiter := stream.NewDecodeLimiter(1, 10*time.Millisecond)
+	handlers := otlp.NewHTTPHandlers(model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	}), config.OTelConfig{}, limiter)
+
+	// Occupy the only decoder, so the request cannot be decoded.
+	require.True(t, limiter.Acquire(context.Background()))
+	rec := sendHTTPRequest(handlers.Traces, "application/json", []byte(`{}`))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "too many concurrent decoders")
+	assert.Empty(t, batches)
+
+	limiter.Release()
+	rec = sendHTTPRequest(handlers.Traces, "application/json", []byte(`{}`))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, batches, 1)
+}
+
+func sendHTTPRequest(h request.Handler, contentType string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return serveHTTP(h, req)
+}
+
+func serveHTTP(h request.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	request.NewContextPool().HTTPHandler(h).ServeHTTP(rec, req)
+	return rec
+}
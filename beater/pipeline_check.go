@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	esoutput "github.com/elastic/beats/v7/libbeat/outputs/elasticsearch"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch"
+)
+
+// pipelineChecker checks that the Elasticsearch cluster has ingest nodes,
+// and the ingest pipeline if it is not registered by the server on
+// connection. The check is performed on startup, and again each time the
+// Elasticsearch output connects, e.g. after the cluster was unavailable.
+//
+// If fallback is enabled, pipelineChecker is also a beat.Processor which
+// removes the ingest pipeline from events while the check fails, so
+// documents are indexed without it rather than all failing.
+type pipelineChecker struct {
+	logger      *logp.Logger
+	client      elasticsearch.Client
+	pipeline    string
+	checkExists bool
+	config      config.PipelineCheckConfig
+
+	// fallback is 1 while the check fails and fallback is enabled.
+	fallback int32
+
+	// checking is 1 while a check triggered by a connection is running,
+	// so checks do not pile up while the output reconnects repeatedly.
+	checking int32
+}
+
+// newPipelineChecker returns a new pipelineChecker for the configured ingest
+// pipeline, or nil if the check is disabled or cannot be performed.
+func (s *serverRunner) newPipelineChecker() *pipelineChecker {
+	pipeline := s.config.Pipeline
+	if pipeline == "" || !s.config.PipelineCheck.Enabled {
+		return nil
+	}
+	esConfig := elasticsearchOutputConfig(s.beat)
+	if esConfig == nil {
+		return nil
+	}
+	cfg := elasticsearch.DefaultConfig()
+	if err := esConfig.Unpack(cfg); err != nil {
+		s.logger.Warnf("failed to check ingest pipeline %q: %s", pipeline, err)
+		return nil
+	}
+	client, err := elasticsearch.NewClient(cfg)
+	if err != nil {
+		s.logger.Warnf("failed to check ingest pipeline %q: %s", pipeline, err)
+		return nil
+	}
+	return &pipelineChecker{
+		logger:      s.logger,
+		client:      client,
+		pipeline:    pipeline,
+		checkExists: !s.config.Register.Ingest.Pipeline.IsEnabled(),
+		config:      s.config.PipelineCheck,
+	}
+}
+
+// check checks the cluster, falling back to indexing documents without the
+// pipeline if the check fails and fallback is enabled, and restoring the
+// pipeline once the check succeeds. Checks which fail with an error leave
+// the fallback unchanged.
+func (c *pipelineChecker) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+	problem, err := checkIngestPipeline(ctx, c.client, c.pipeline, c.checkExists)
+	switch {
+	case err != nil:
+		c.logger.Warnf("failed to check ingest pipeline %q: %s", c.pipeline, err)
+	case problem == "":
+		if atomic.CompareAndSwapInt32(&c.fallback, 1, 0) {
+			c.logger.Infof("ingest pipeline %q is available; indexing documents with it", c.pipeline)
+		}
+	case c.config.Fallback:
+		if atomic.CompareAndSwapInt32(&c.fallback, 0, 1) {
+			c.logger.Warnf("%s; indexing documents without ingest pipeline %q", problem, c.pipeline)
+		}
+	default:
+		c.logger.Errorf("%s; documents sent to ingest pipeline %q will fail to be indexed", problem, c.pipeline)
+	}
+}
+
+// checkOnConnect registers a callback for checking the cluster each time the
+// Elasticsearch output connects, until ctx is done. The returned function
+// deregisters the callback.
+func (c *pipelineChecker) checkOnConnect(ctx context.Context) (func(), error) {
+	id, err := esoutput.RegisterConnectCallback(func(*eslegclient.Connection) error {
+		// Check asynchronously, as the output cannot publish
+		// until its connect callbacks have returned.
+		if atomic.CompareAndSwapInt32(&c.checking, 0, 1) {
+			go func() {
+				defer atomic.StoreInt32(&c.checking, 0)
+				c.check(ctx)
+			}()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() { esoutput.DeregisterConnectCallback(id) }, nil
+}
+
+// Run removes the ingest pipeline from event while falling back to
+// indexing documents without it.
+func (c *pipelineChecker) Run(event *beat.Event) (*beat.Event, error) {
+	if atomic.LoadInt32(&c.fallback) == 1 && event.Meta != nil {
+		event.Meta.Delete("pipeline")
+	}
+	return event, nil
+}
+
+func (c *pipelineChecker) String() string {
+	return fmt.Sprintf("pipeline_check=[pipeline=%s]", c.pipeline)
+}
+
+// checkIngestPipeline checks that the cluster has ingest nodes and, if
+// checkExists is true, that the pipeline exists. If either is not the case,
+// checkIngestPipeline returns a description of the problem.
+func checkIngestPipeline(ctx context.Context, client elasticsearch.Client, pipeline string, checkExists bool) (string, error) {
+	nodes, err := elasticsearch.CountIngestNodes(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	if nodes == 0 {
+		return "Elasticsearch cluster has no ingest nodes", nil
+	}
+	if checkExists {
+		exists, err := elasticsearch.IngestPipelineExists(ctx, client, pipeline)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return fmt.Sprintf("ingest pipeline %q does not exist", pipeline), nil
+		}
+	}
+	return "", nil
+}
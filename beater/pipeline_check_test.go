@@ -0,0 +1,127 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch"
+)
+
+func TestCheckIngestPipeline(t *testing.T) {
+	for name, test := range map[string]struct {
+		ingestNodes    int
+		pipelineExists bool
+		checkExists    bool
+		problem        string
+	}{
+		"ok": {
+			ingestNodes: 1, pipelineExists: true, checkExists: true,
+		},
+		"no ingest nodes": {
+			ingestNodes: 0, pipelineExists: true, checkExists: true,
+			problem: "Elasticsearch cluster has no ingest nodes",
+		},
+		"missing pipeline": {
+			ingestNodes: 2, checkExists: true,
+			problem: `ingest pipeline "apm" does not exist`,
+		},
+		"missing pipeline not checked": {
+			ingestNodes: 2,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/_nodes/ingest:true/_none":
+					fmt.Fprintf(w, `{"_nodes":{"total":%d}}`, test.ingestNodes)
+				case "/_ingest/pipeline/apm":
+					if !test.pipelineExists {
+						w.WriteHeader(http.StatusNotFound)
+					}
+					fmt.Fprint(w, `{}`)
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			defer srv.Close()
+
+			client, err := elasticsearch.NewClient(&elasticsearch.Config{Hosts: elasticsearch.Hosts{srv.Listener.Addr().String()}})
+			require.NoError(t, err)
+			problem, err := checkIngestPipeline(context.Background(), client, "apm", test.checkExists)
+			require.NoError(t, err)
+			assert.Equal(t, test.problem, problem)
+		})
+	}
+}
+
+func TestCheckIngestPipelineError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client, err := elasticsearch.NewClient(&elasticsearch.Config{Hosts: elasticsearch.Hosts{srv.Listener.Addr().String()}})
+	require.NoError(t, err)
+	_, err = checkIngestPipeline(context.Background(), client, "apm", true)
+	assert.Error(t, err)
+}
+
+func TestPipelineCheckerFallback(t *testing.T) {
+	var ingestNodes int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"_nodes":{"total":%d}}`, atomic.LoadInt32(&ingestNodes))
+	}))
+	defer srv.Close()
+
+	client, err := elasticsearch.NewClient(&elasticsearch.Config{Hosts: elasticsearch.Hosts{srv.Listener.Addr().String()}})
+	require.NoError(t, err)
+	checker := &pipelineChecker{
+		logger:   logp.NewLogger("test"),
+		client:   client,
+		pipeline: "apm",
+		config:   config.PipelineCheckConfig{Enabled: true, Fallback: true, Timeout: time.Second},
+	}
+	run := func() common.MapStr {
+		event, err := checker.Run(&beat.Event{Meta: common.MapStr{"pipeline": "apm"}})
+		require.NoError(t, err)
+		return event.Meta
+	}
+
+	// Documents are indexed without the pipeline while the cluster has
+	// no ingest nodes, and with it again once the check succeeds.
+	checker.check(context.Background())
+	assert.Equal(t, common.MapStr{}, run())
+	atomic.StoreInt32(&ingestNodes, 1)
+	checker.check(context.Background())
+	assert.Equal(t, common.MapStr{"pipeline": "apm"}, run())
+}
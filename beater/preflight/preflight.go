@@ -0,0 +1,333 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package preflight provides checks for verifying the environment in which
+// APM Server runs, such as the compatibility of Elasticsearch and Kibana,
+// and the privileges of the configured credentials.
+package preflight
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	libilm "github.com/elastic/beats/v7/libbeat/idxmgmt/ilm"
+	"github.com/elastic/beats/v7/libbeat/kibana"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/idxmgmt/ilm"
+)
+
+// Status describes the outcome of a single preflight check.
+type Status int
+
+const (
+	// StatusOK indicates that the check passed.
+	StatusOK Status = iota
+	// StatusWarning indicates that the check found a potential problem,
+	// which does not prevent the server from running.
+	StatusWarning
+	// StatusFailed indicates that the check failed.
+	StatusFailed
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "OK"
+	case StatusWarning:
+		return "WARN"
+	case StatusFailed:
+		return "FAIL"
+	}
+	return "UNKNOWN"
+}
+
+// Result holds the result of a single preflight check.
+type Result struct {
+	// Check holds the name of the check.
+	Check string
+
+	// Status holds the outcome of the check.
+	Status Status
+
+	// Message holds an actionable description of the outcome.
+	Message string
+}
+
+// Report holds the results of all preflight checks.
+type Report []Result
+
+// Failed reports whether any of the checks in r failed.
+func (r Report) Failed() bool {
+	for _, result := range r {
+		if result.Status == StatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a consolidated, human-readable report.
+func (r Report) String() string {
+	var sb strings.Builder
+	sb.WriteString("Preflight check report:")
+	for _, result := range r {
+		fmt.Fprintf(&sb, "\n  [%s] %s: %s", result.Status, result.Check, result.Message)
+	}
+	return sb.String()
+}
+
+// Params holds parameters for Run.
+type Params struct {
+	// Info holds the beat info, used for checking version compatibility.
+	Info beat.Info
+
+	// Config holds the APM Server configuration.
+	Config *config.Config
+
+	// ILM holds the ILM configuration, used for checking the existence
+	// of ILM policies. ILM is only checked if data streams are disabled.
+	ILM ilm.Config
+
+	// Elasticsearch holds a client for the Elasticsearch output.
+	// If Elasticsearch is nil, Elasticsearch checks will be skipped.
+	Elasticsearch elasticsearch.Client
+
+	// Credentials holds Elasticsearch clients for the credentials other
+	// than the output's requiring runtime privileges, keyed by the config
+	// namespace of the credentials, e.g. CredentialsKibana. The privileges
+	// of credentials without a client are not checked.
+	Credentials map[string]elasticsearch.Client
+}
+
+// Run runs all preflight checks, returning a report of their results.
+func Run(ctx context.Context, args Params) Report {
+	var report Report
+	if args.Elasticsearch == nil {
+		report = append(report, Result{
+			Check:   "elasticsearch",
+			Status:  StatusWarning,
+			Message: "output is not Elasticsearch, skipping Elasticsearch checks",
+		})
+	} else {
+		report = append(report, checkElasticsearchVersion(ctx, args.Elasticsearch, args.Info.Version))
+		for _, privileges := range RuntimePrivileges(args.Config) {
+			client := args.Elasticsearch
+			if privileges.Credentials != CredentialsOutput {
+				client = args.Credentials[privileges.Credentials]
+			}
+			if client != nil {
+				report = append(report, checkPrivileges(ctx, client, privileges))
+			}
+		}
+		report = append(report, checkTemplates(ctx, args.Elasticsearch, args.Config, args.Info.Version)...)
+		if !args.Config.DataStreams.Enabled {
+			report = append(report, checkILMPolicies(ctx, args.Elasticsearch, args.ILM)...)
+		}
+	}
+	if args.Config.Kibana.Enabled {
+		report = append(report, checkKibana(args.Config.Kibana, args.Config.Preflight.Timeout))
+	}
+	return report
+}
+
+func checkElasticsearchVersion(ctx context.Context, client elasticsearch.Client, version string) Result {
+	const check = "elasticsearch version"
+	serverVersion, err := common.NewVersion(version)
+	if err != nil {
+		return Result{Check: check, Status: StatusFailed, Message: err.Error()}
+	}
+	esVersion, err := elasticsearch.GetVersion(ctx, client)
+	if err != nil {
+		return Result{
+			Check:   check,
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("failed to connect to Elasticsearch: %s; check output.elasticsearch.hosts and credentials", err),
+		}
+	}
+	minVersion := common.MustNewVersion(fmt.Sprintf("%d.%d.0", serverVersion.Major, serverVersion.Minor))
+	if esVersion.LessThan(minVersion) || esVersion.Major > serverVersion.Major+1 {
+		return Result{
+			Check:  check,
+			Status: StatusFailed,
+			Message: fmt.Sprintf(
+				"Elasticsearch version %s is not compatible with APM Server version %s; upgrade Elasticsearch to at least %s",
+				esVersion, serverVersion, minVersion,
+			),
+		}
+	}
+	return Result{Check: check, Status: StatusOK, Message: fmt.Sprintf("Elasticsearch version %s", esVersion)}
+}
+
+func checkPrivileges(ctx context.Context, client elasticsearch.Client, privileges CredentialPrivileges) Result {
+	check := "elasticsearch privileges"
+	if privileges.Credentials != CredentialsOutput {
+		check += fmt.Sprintf(" (%s)", privileges.Credentials)
+	}
+	resp, err := elasticsearch.HasPrivileges(ctx, client, elasticsearch.HasPrivilegesRequest{
+		Applications: privileges.Applications,
+		Cluster:      privileges.Cluster,
+		Index:        privileges.Index,
+	}, "")
+	if err != nil {
+		return Result{Check: check, Status: StatusFailed, Message: fmt.Sprintf("failed to check privileges: %s", err)}
+	}
+	if resp.HasAll {
+		return Result{Check: check, Status: StatusOK, Message: fmt.Sprintf("user %q has all required privileges", resp.Username)}
+	}
+	var missing []string
+	for _, privilege := range privileges.Cluster {
+		if !resp.Cluster[privilege] {
+			missing = append(missing, fmt.Sprintf("cluster:%s", privilege))
+		}
+	}
+	for _, index := range privileges.Index {
+		for _, name := range index.Names {
+			for _, privilege := range index.Privileges {
+				if !resp.Index[elasticsearch.Resource(name)][privilege] {
+					missing = append(missing, fmt.Sprintf("index[%s]:%s", name, privilege))
+				}
+			}
+		}
+	}
+	for _, app := range privileges.Applications {
+		for _, resource := range app.Resources {
+			for _, privilege := range app.Privileges {
+				if !resp.Application[app.Name][resource][privilege] {
+					missing = append(missing, fmt.Sprintf("application[%s][%s]:%s", app.Name, resource, privilege))
+				}
+			}
+		}
+	}
+	return Result{
+		Check:  check,
+		Status: StatusFailed,
+		Message: fmt.Sprintf(
+			"user %q is missing required privileges: %s",
+			resp.Username, strings.Join(missing, ", "),
+		),
+	}
+}
+
+func checkTemplates(ctx context.Context, client elasticsearch.Client, cfg *config.Config, version string) []Result {
+	const check = "index templates"
+	var results []Result
+	if cfg.DataStreams.Enabled {
+		for _, name := range dataStreamIndexTemplates {
+			exists, err := elasticsearch.IndexTemplateExists(ctx, client, name)
+			switch {
+			case err != nil:
+				results = append(results, Result{Check: check, Status: StatusFailed, Message: fmt.Sprintf("failed to check index template %q: %s", name, err)})
+			case !exists:
+				results = append(results, Result{Check: check, Status: StatusFailed, Message: fmt.Sprintf("index template %q not found; install the APM integration package", name)})
+			}
+		}
+	} else {
+		name := "apm-" + version
+		exists, err := elasticsearch.LegacyTemplateExists(ctx, client, name)
+		switch {
+		case err != nil:
+			results = append(results, Result{Check: check, Status: StatusFailed, Message: fmt.Sprintf("failed to check index template %q: %s", name, err)})
+		case !exists:
+			results = append(results, Result{Check: check, Status: StatusWarning, Message: fmt.Sprintf(
+				"index template %q not found; it will be loaded when connecting to Elasticsearch if setup.template.enabled is true, "+
+					"otherwise run `apm-server setup --index-management`", name,
+			)})
+		}
+	}
+	if len(results) == 0 {
+		results = append(results, Result{Check: check, Status: StatusOK, Message: "all index templates exist"})
+	}
+	return results
+}
+
+func checkILMPolicies(ctx context.Context, client elasticsearch.Client, cfg ilm.Config) []Result {
+	const check = "ILM policies"
+	if cfg.Mode == libilm.ModeDisabled {
+		return nil
+	}
+	status := StatusFailed
+	if cfg.Setup.Enabled {
+		status = StatusWarning
+	}
+	var results []Result
+	for _, name := range ilmPolicyNames(cfg) {
+		exists, err := elasticsearch.ILMPolicyExists(ctx, client, name)
+		switch {
+		case err != nil:
+			results = append(results, Result{Check: check, Status: StatusFailed, Message: fmt.Sprintf("failed to check ILM policy %q: %s", name, err)})
+		case !exists:
+			message := fmt.Sprintf("ILM policy %q not found", name)
+			if cfg.Setup.Enabled {
+				message += "; it will be created when connecting to Elasticsearch"
+			} else {
+				message += "; enable apm-server.ilm.setup.enabled or create the policy manually"
+			}
+			results = append(results, Result{Check: check, Status: status, Message: message})
+		}
+	}
+	if len(results) == 0 {
+		results = append(results, Result{Check: check, Status: StatusOK, Message: "all ILM policies exist"})
+	}
+	return results
+}
+
+func checkKibana(cfg config.KibanaConfig, timeout time.Duration) Result {
+	const check = "kibana"
+	clientConfig := cfg.ClientConfig
+	clientConfig.Timeout = timeout
+	if cfg.APIKey != "" {
+		headers := make(map[string]string, len(clientConfig.Headers)+1)
+		for k, v := range clientConfig.Headers {
+			headers[k] = v
+		}
+		headers["Authorization"] = "ApiKey " + base64.StdEncoding.EncodeToString([]byte(cfg.APIKey))
+		clientConfig.Headers = headers
+		clientConfig.Username = ""
+		clientConfig.Password = ""
+	}
+	client, err := kibana.NewClientWithConfig(&clientConfig)
+	if err != nil {
+		return Result{
+			Check:   check,
+			Status:  StatusFailed,
+			Message: fmt.Sprintf("failed to connect to Kibana: %s; check apm-server.kibana.host and credentials", err),
+		}
+	}
+	return Result{Check: check, Status: StatusOK, Message: fmt.Sprintf("Kibana version %s", client.Version.String())}
+}
+
+// ilmPolicyNames returns the sorted, unique ILM policy names referenced by cfg.
+func ilmPolicyNames(cfg ilm.Config) []string {
+	unique := make(map[string]struct{})
+	for _, mapping := range cfg.Setup.Mappings {
+		unique[mapping.PolicyName] = struct{}{}
+	}
+	names := make([]string, 0, len(unique))
+	for name := range unique {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,230 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/idxmgmt/ilm"
+)
+
+func TestRunElasticsearchOK(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"version": map[string]interface{}{"number": "7.14.1"}})
+	})
+	mux.HandleFunc("/_security/user/_has_privileges", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"username": "apm_writer", "has_all_requested": true})
+	})
+	mux.HandleFunc("/_template/apm-7.14.0", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/_ilm/policy/apm-rollover-30-days", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{})
+	})
+
+	report := Run(context.Background(), Params{
+		Info:          beat.Info{Version: "7.14.0"},
+		Config:        config.DefaultConfig(),
+		ILM:           newILMConfig(t),
+		Elasticsearch: newElasticsearchClient(t, mux),
+	})
+	assert.False(t, report.Failed(), report.String())
+	assert.Equal(t, Report{
+		{Check: "elasticsearch version", Status: StatusOK, Message: "Elasticsearch version 7.14.1"},
+		{Check: "elasticsearch privileges", Status: StatusOK, Message: `user "apm_writer" has all required privileges`},
+		{Check: "index templates", Status: StatusOK, Message: "all index templates exist"},
+		{Check: "ILM policies", Status: StatusOK, Message: "all ILM policies exist"},
+	}, report)
+}
+
+func TestRunElasticsearchFailures(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"version": map[string]interface{}{"number": "7.13.0"}})
+	})
+	mux.HandleFunc("/_security/user/_has_privileges", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"username":          "apm_writer",
+			"has_all_requested": false,
+			"cluster":           map[string]bool{"monitor": true},
+			"index": map[string]interface{}{
+				"apm-*": map[string]bool{"create_doc": true, "create_index": false},
+			},
+		})
+	})
+
+	cfg := config.DefaultConfig()
+	report := Run(context.Background(), Params{
+		Info:          beat.Info{Version: "7.14.0"},
+		Config:        cfg,
+		ILM:           newILMConfig(t),
+		Elasticsearch: newElasticsearchClient(t, mux),
+	})
+	assert.True(t, report.Failed())
+	assert.Equal(t, Report{{
+		Check:   "elasticsearch version",
+		Status:  StatusFailed,
+		Message: "Elasticsearch version 7.13.0 is not compatible with APM Server version 7.14.0; upgrade Elasticsearch to at least 7.14.0",
+	}, {
+		Check:   "elasticsearch privileges",
+		Status:  StatusFailed,
+		Message: `user "apm_writer" is missing required privileges: index[apm-*]:create_index`,
+	}, {
+		Check:   "index templates",
+		Status:  StatusWarning,
+		Message: `index template "apm-7.14.0" not found; it will be loaded when connecting to Elasticsearch if setup.template.enabled is true, otherwise run ` + "`apm-server setup --index-management`",
+	}, {
+		Check:   "ILM policies",
+		Status:  StatusWarning,
+		Message: `ILM policy "apm-rollover-30-days" not found; it will be created when connecting to Elasticsearch`,
+	}}, report)
+}
+
+func TestRunKibanaCredentialsPrivileges(t *testing.T) {
+	outputMux := http.NewServeMux()
+	outputMux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"version": map[string]interface{}{"number": "7.14.0"}})
+	})
+	outputMux.HandleFunc("/_security/user/_has_privileges", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{"username": "apm_writer", "has_all_requested": true})
+	})
+	outputMux.HandleFunc("/_template/apm-7.14.0", func(w http.ResponseWriter, r *http.Request) {})
+	outputMux.HandleFunc("/_ilm/policy/apm-rollover-30-days", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{})
+	})
+
+	var kibanaRequest map[string]interface{}
+	kibanaMux := http.NewServeMux()
+	kibanaMux.HandleFunc("/_security/user/_has_privileges", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&kibanaRequest))
+		writeJSON(w, map[string]interface{}{
+			"username":          "apm_kibana",
+			"has_all_requested": false,
+			"application": map[string]interface{}{
+				"kibana-.kibana": map[string]interface{}{
+					"space:default": map[string]bool{"feature_apm.read": false},
+				},
+			},
+		})
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.Kibana.Enabled = true
+	report := Run(context.Background(), Params{
+		Info:          beat.Info{Version: "7.14.0"},
+		Config:        cfg,
+		ILM:           newILMConfig(t),
+		Elasticsearch: newElasticsearchClient(t, outputMux),
+		Credentials: map[string]elasticsearch.Client{
+			CredentialsKibana: newElasticsearchClient(t, kibanaMux),
+		},
+	})
+	assert.Equal(t, map[string]interface{}{
+		"application": []interface{}{map[string]interface{}{
+			"application": "kibana-.kibana",
+			"privileges":  []interface{}{"feature_apm.read"},
+			"resources":   []interface{}{"space:default"},
+		}},
+	}, kibanaRequest)
+	assert.Equal(t, Report{
+		{Check: "elasticsearch version", Status: StatusOK, Message: "Elasticsearch version 7.14.0"},
+		{Check: "elasticsearch privileges", Status: StatusOK, Message: `user "apm_writer" has all required privileges`},
+		{
+			Check:   "elasticsearch privileges (apm-server.kibana)",
+			Status:  StatusFailed,
+			Message: `user "apm_kibana" is missing required privileges: application[kibana-.kibana][space:default]:feature_apm.read`,
+		},
+		{Check: "index templates", Status: StatusOK, Message: "all index templates exist"},
+		{Check: "ILM policies", Status: StatusOK, Message: "all ILM policies exist"},
+	}, report[:5])
+}
+
+func TestRunDataStreamsIndexTemplates(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	for _, name := range dataStreamIndexTemplates[1:] {
+		mux.HandleFunc("/_index_template/"+name, func(w http.ResponseWriter, r *http.Request) {})
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.DataStreams.Enabled = true
+	results := checkTemplates(context.Background(), newElasticsearchClient(t, mux), cfg, "7.14.0")
+	assert.Equal(t, []Result{{
+		Check:   "index templates",
+		Status:  StatusFailed,
+		Message: `index template "traces-apm" not found; install the APM integration package`,
+	}}, results)
+}
+
+func TestRunNoElasticsearch(t *testing.T) {
+	report := Run(context.Background(), Params{
+		Info:   beat.Info{Version: "7.14.0"},
+		Config: config.DefaultConfig(),
+	})
+	assert.False(t, report.Failed())
+	assert.Equal(t, Report{{
+		Check:   "elasticsearch",
+		Status:  StatusWarning,
+		Message: "output is not Elasticsearch, skipping Elasticsearch checks",
+	}}, report)
+}
+
+func TestReportString(t *testing.T) {
+	report := Report{
+		{Check: "a", Status: StatusOK, Message: "fine"},
+		{Check: "b", Status: StatusFailed, Message: "broken"},
+	}
+	assert.Equal(t, "Preflight check report:\n  [OK] a: fine\n  [FAIL] b: broken", report.String())
+}
+
+func newILMConfig(t testing.TB) ilm.Config {
+	cfg, err := ilm.NewConfig(beat.Info{Version: "7.14.0"}, nil)
+	require.NoError(t, err)
+	return cfg
+}
+
+func newElasticsearchClient(t testing.TB, handler http.Handler) elasticsearch.Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	client, err := elasticsearch.NewClient(&elasticsearch.Config{
+		Hosts: elasticsearch.Hosts{srv.Listener.Addr().String()},
+	})
+	require.NoError(t, err)
+	return client
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,157 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package preflight
+
+import (
+	"strings"
+
+	libilm "github.com/elastic/beats/v7/libbeat/idxmgmt/ilm"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/idxmgmt"
+)
+
+// dataStreamIndexTemplates holds the names of the index templates
+// installed by the APM integration package.
+var dataStreamIndexTemplates = []string{
+	"traces-apm",
+	"traces-sampled",
+	"logs-apm.error",
+	"metrics-apm.app",
+	"metrics-apm.internal",
+	"metrics-apm.profiling",
+}
+
+// Privileges holds Elasticsearch cluster, index, and application privileges.
+type Privileges struct {
+	Cluster      []elasticsearch.PrivilegeAction `json:"cluster,omitempty"`
+	Index        []elasticsearch.IndexPrivileges `json:"index,omitempty"`
+	Applications []elasticsearch.Application     `json:"applications,omitempty"`
+}
+
+// Config namespaces of the credentials requiring runtime privileges.
+const (
+	CredentialsOutput        = "output.elasticsearch"
+	CredentialsSourceMapping = "apm-server.rum.source_mapping.elasticsearch"
+	CredentialsKibana        = "apm-server.kibana"
+	CredentialsAgentConfig   = "apm-server.agent.config.elasticsearch"
+)
+
+// CredentialPrivileges holds the privileges required by a set of credentials.
+type CredentialPrivileges struct {
+	// Credentials holds the config namespace of the credentials.
+	Credentials string `json:"credentials"`
+
+	Privileges
+}
+
+// RuntimePrivileges returns the Elasticsearch privileges required for
+// running APM Server with the given config, grouped by the credentials
+// requiring them: the output credentials for indexing events, the source
+// mapping credentials, which default to the output credentials, for reading
+// source maps, the Kibana credentials for querying agent configuration, and
+// the agent configuration credentials, which default to the Kibana
+// credentials, for reading agent configuration directly from Elasticsearch
+// and marking it as applied.
+func RuntimePrivileges(cfg *config.Config) []CredentialPrivileges {
+	output := CredentialPrivileges{
+		Credentials: CredentialsOutput,
+		Privileges: Privileges{
+			Cluster: []elasticsearch.PrivilegeAction{"monitor"},
+		},
+	}
+	if cfg.DataStreams.Enabled {
+		output.Index = append(output.Index, elasticsearch.IndexPrivileges{
+			Names:      []string{"traces-apm*", "logs-apm*", "metrics-apm*"},
+			Privileges: []elasticsearch.PrivilegeAction{"auto_configure", "create_doc"},
+		})
+	} else {
+		output.Index = append(output.Index, elasticsearch.IndexPrivileges{
+			Names:      []string{"apm-*"},
+			Privileges: []elasticsearch.PrivilegeAction{"create_doc", "create_index"},
+		})
+	}
+	privileges := []CredentialPrivileges{output}
+	if cfg.RumConfig.IsEnabled() && cfg.RumConfig.SourceMapping.IsEnabled() {
+		privileges = append(privileges, CredentialPrivileges{
+			Credentials: CredentialsSourceMapping,
+			Privileges: Privileges{
+				Index: []elasticsearch.IndexPrivileges{{
+					Names:      []string{strings.ReplaceAll(cfg.RumConfig.SourceMapping.IndexPattern, "%{[observer.version]}", "*")},
+					Privileges: []elasticsearch.PrivilegeAction{"read"},
+				}},
+			},
+		})
+	}
+	if cfg.Kibana.Enabled {
+		privileges = append(privileges, CredentialPrivileges{
+			Credentials: CredentialsKibana,
+			Privileges: Privileges{
+				Applications: []elasticsearch.Application{{
+					Name:       "kibana-.kibana",
+					Privileges: []elasticsearch.PrivilegeAction{"feature_apm.read"},
+					Resources:  []elasticsearch.Resource{"space:default"},
+				}},
+			},
+		})
+	}
+	if cfg.AgentConfig.ElasticsearchEnabled() {
+		// Agent configuration read directly from Elasticsearch
+		// is marked as applied by updating the configuration.
+		privileges = append(privileges, CredentialPrivileges{
+			Credentials: CredentialsAgentConfig,
+			Privileges: Privileges{
+				Index: []elasticsearch.IndexPrivileges{{
+					Names:                  []string{".apm-agent-configuration"},
+					Privileges:             []elasticsearch.PrivilegeAction{"read", "write"},
+					AllowRestrictedIndices: true,
+				}},
+			},
+		})
+	}
+	return privileges
+}
+
+// SetupPrivileges returns the Elasticsearch privileges required by the
+// output credentials for setting up index templates, ILM policies, and
+// ingest pipelines with the given config.
+//
+// When data streams are enabled, setup is performed by installing the
+// APM integration package, and no setup privileges are required.
+func SetupPrivileges(cfg *config.Config, idxConfig *idxmgmt.IndexManagementConfig) Privileges {
+	var privileges Privileges
+	if cfg.DataStreams.Enabled {
+		return privileges
+	}
+	privileges.Cluster = append(privileges.Cluster, "monitor")
+	if idxConfig.Template.Enabled {
+		privileges.Cluster = append(privileges.Cluster, "manage_index_templates")
+	}
+	if idxConfig.ILM.Mode != libilm.ModeDisabled && idxConfig.ILM.Setup.Enabled {
+		privileges.Cluster = append(privileges.Cluster, "manage_ilm")
+		privileges.Index = append(privileges.Index, elasticsearch.IndexPrivileges{
+			Names:      []string{"apm-*"},
+			Privileges: []elasticsearch.PrivilegeAction{"manage"},
+		})
+	}
+	if cfg.Register.Ingest.Pipeline.IsEnabled() {
+		privileges.Cluster = append(privileges.Cluster, "manage_ingest_pipelines")
+	}
+	return privileges
+}
@@ -0,0 +1,141 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package preflight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/idxmgmt"
+)
+
+func TestRuntimePrivileges(t *testing.T) {
+	output := CredentialPrivileges{
+		Credentials: CredentialsOutput,
+		Privileges: Privileges{
+			Cluster: []elasticsearch.PrivilegeAction{"monitor"},
+			Index: []elasticsearch.IndexPrivileges{{
+				Names:      []string{"apm-*"},
+				Privileges: []elasticsearch.PrivilegeAction{"create_doc", "create_index"},
+			}},
+		},
+	}
+	cfg := config.DefaultConfig()
+	assert.Equal(t, []CredentialPrivileges{output}, RuntimePrivileges(cfg))
+
+	cfg.DataStreams.Enabled = true
+	assert.Equal(t, []CredentialPrivileges{{
+		Credentials: CredentialsOutput,
+		Privileges: Privileges{
+			Cluster: []elasticsearch.PrivilegeAction{"monitor"},
+			Index: []elasticsearch.IndexPrivileges{{
+				Names:      []string{"traces-apm*", "logs-apm*", "metrics-apm*"},
+				Privileges: []elasticsearch.PrivilegeAction{"auto_configure", "create_doc"},
+			}},
+		},
+	}}, RuntimePrivileges(cfg))
+
+	rumEnabled := true
+	cfg = config.DefaultConfig()
+	cfg.RumConfig.Enabled = &rumEnabled
+	cfg.Kibana.Enabled = true
+	assert.Equal(t, []CredentialPrivileges{output, {
+		Credentials: CredentialsSourceMapping,
+		Privileges: Privileges{
+			Index: []elasticsearch.IndexPrivileges{{
+				Names:      []string{"apm-*-sourcemap*"},
+				Privileges: []elasticsearch.PrivilegeAction{"read"},
+			}},
+		},
+	}, {
+		Credentials: CredentialsKibana,
+		Privileges: Privileges{
+			Applications: []elasticsearch.Application{{
+				Name:       "kibana-.kibana",
+				Privileges: []elasticsearch.PrivilegeAction{"feature_apm.read"},
+				Resources:  []elasticsearch.Resource{"space:default"},
+			}},
+		},
+	}}, RuntimePrivileges(cfg))
+
+	cfg = config.DefaultConfig()
+	cfg.AgentConfig.Source = config.AgentConfigSourceElasticsearch
+	assert.Equal(t, []CredentialPrivileges{output, {
+		Credentials: CredentialsAgentConfig,
+		Privileges: Privileges{
+			Index: []elasticsearch.IndexPrivileges{{
+				Names:                  []string{".apm-agent-configuration"},
+				Privileges:             []elasticsearch.PrivilegeAction{"read", "write"},
+				AllowRestrictedIndices: true,
+			}},
+		},
+	}}, RuntimePrivileges(cfg))
+}
+
+func TestSetupPrivileges(t *testing.T) {
+	for name, test := range map[string]struct {
+		config   map[string]interface{}
+		expected Privileges
+	}{
+		"default": {
+			expected: Privileges{
+				Cluster: []elasticsearch.PrivilegeAction{"monitor", "manage_index_templates", "manage_ilm", "manage_ingest_pipelines"},
+				Index: []elasticsearch.IndexPrivileges{{
+					Names:      []string{"apm-*"},
+					Privileges: []elasticsearch.PrivilegeAction{"manage"},
+				}},
+			},
+		},
+		"ilm and pipelines disabled": {
+			config: map[string]interface{}{
+				"apm-server.ilm.enabled":                      false,
+				"apm-server.register.ingest.pipeline.enabled": false,
+				"setup.template.enabled":                      true,
+			},
+			expected: Privileges{
+				Cluster: []elasticsearch.PrivilegeAction{"monitor", "manage_index_templates"},
+			},
+		},
+		"data streams": {
+			config:   map[string]interface{}{"apm-server.data_streams.enabled": true},
+			expected: Privileges{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rootConfig := common.MustNewConfigFrom(test.config)
+			info := beat.Info{Version: "7.14.0"}
+			idxConfig, err := idxmgmt.NewIndexManagementConfig(info, rootConfig)
+			require.NoError(t, err)
+
+			apmServerConfig, err := rootConfig.Child("apm-server", -1)
+			if err != nil {
+				apmServerConfig = common.NewConfig()
+			}
+			cfg, err := config.NewConfig(apmServerConfig, nil)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, SetupPrivileges(cfg, idxConfig))
+		})
+	}
+}
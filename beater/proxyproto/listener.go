@@ -0,0 +1,289 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package proxyproto provides a net.Listener which accepts PROXY protocol
+// headers, as sent by TCP load balancers such as HAProxy and AWS NLB, and
+// reports the original client address as the connection's remote address.
+//
+// Both the human-readable v1 and the binary v2 formats of the protocol are
+// supported: https://www.haproxy.org/download/2.3/doc/proxy-protocol.txt
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// v1MaxHeaderLength is the maximum length of a v1 header,
+	// including the terminating CRLF.
+	v1MaxHeaderLength = 107
+
+	v2HeaderLength = 16
+)
+
+var (
+	v1Prefix    = []byte("PROXY ")
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// Listener is a net.Listener which reads a PROXY protocol header from the
+// beginning of connections accepted from trusted proxies.
+type Listener struct {
+	net.Listener
+
+	trusted       []*net.IPNet
+	headerTimeout time.Duration
+}
+
+// NewListener returns a new Listener wrapping l. Connections accepted from
+// addresses in trusted must begin with a PROXY protocol header; connections
+// from other addresses are returned unmodified. If trusted is empty, all
+// connections must begin with a header.
+//
+// The header is read on the first call to the connection's Read or
+// RemoteAddr methods, so that a slow client does not block Accept.
+// Reading the header fails if it is not received within headerTimeout.
+func NewListener(l net.Listener, trusted []*net.IPNet, headerTimeout time.Duration) *Listener {
+	return &Listener{Listener: l, trusted: trusted, headerTimeout: headerTimeout}
+}
+
+// Accept waits for and returns the next connection to the listener.
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.isTrusted(conn.RemoteAddr()) {
+		return conn, nil
+	}
+	return &Conn{Conn: conn, headerTimeout: l.headerTimeout}, nil
+}
+
+func (l *Listener) isTrusted(addr net.Addr) bool {
+	if len(l.trusted) == 0 {
+		return true
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, network := range l.trusted {
+		if network.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conn is a net.Conn which begins with a PROXY protocol header.
+type Conn struct {
+	net.Conn
+	headerTimeout time.Duration
+
+	once       sync.Once
+	reader     *bufio.Reader
+	remoteAddr net.Addr
+	localAddr  net.Addr
+	err        error
+}
+
+// Read reads data from the connection, following the PROXY protocol header.
+// If the header could not be read, the error is returned.
+func (c *Conn) Read(p []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(p)
+}
+
+// RemoteAddr returns the source address conveyed by the PROXY protocol
+// header, or the address of the proxy if the header conveys no address
+// or could not be read.
+func (c *Conn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr returns the destination address conveyed by the PROXY protocol
+// header, or the connection's local address if the header conveys no
+// address or could not be read.
+func (c *Conn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.localAddr != nil {
+		return c.localAddr
+	}
+	return c.Conn.LocalAddr()
+}
+
+func (c *Conn) readHeader() {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(c.headerTimeout)); err != nil {
+		c.err = err
+		return
+	}
+	c.reader = bufio.NewReader(c.Conn)
+	c.remoteAddr, c.localAddr, c.err = readHeader(c.reader)
+	if err := c.Conn.SetReadDeadline(time.Time{}); err != nil && c.err == nil {
+		c.err = err
+	}
+	if c.err != nil {
+		c.err = errors.Wrap(c.err, "error reading PROXY protocol header")
+	}
+}
+
+// readHeader reads a v1 or v2 PROXY protocol header from r, returning the
+// source and destination addresses it conveys. The returned addresses are
+// nil if the header conveys no addresses, e.g. for health checks sent by
+// the proxy itself.
+func readHeader(r *bufio.Reader) (src, dst net.Addr, err error) {
+	prefix, err := r.Peek(len(v1Prefix))
+	if err != nil {
+		return nil, nil, err
+	}
+	if bytes.Equal(prefix, v1Prefix) {
+		return readV1Header(r)
+	}
+	if prefix, err = r.Peek(len(v2Signature)); err != nil {
+		return nil, nil, err
+	}
+	if bytes.Equal(prefix, v2Signature) {
+		return readV2Header(r)
+	}
+	return nil, nil, errors.New("missing PROXY protocol header")
+}
+
+func readV1Header(r *bufio.Reader) (src, dst net.Addr, err error) {
+	var line []byte
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) == v1MaxHeaderLength {
+			return nil, nil, errors.New("v1 header too long")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		line = append(line, b)
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) < 2 {
+		return nil, nil, errors.New("invalid v1 header")
+	}
+	switch fields[1] {
+	case "UNKNOWN":
+		return nil, nil, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, nil, errors.Errorf("unsupported v1 protocol %q", fields[1])
+	}
+	if len(fields) != 6 {
+		return nil, nil, errors.New("invalid v1 header")
+	}
+	if src, err = parseV1Addr(fields[2], fields[4]); err != nil {
+		return nil, nil, err
+	}
+	if dst, err = parseV1Addr(fields[3], fields[5]); err != nil {
+		return nil, nil, err
+	}
+	return src, dst, nil
+}
+
+func parseV1Addr(ip, port string) (*net.TCPAddr, error) {
+	addr := &net.TCPAddr{IP: net.ParseIP(ip)}
+	if addr.IP == nil {
+		return nil, errors.Errorf("invalid v1 address %q", ip)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, errors.Errorf("invalid v1 port %q", port)
+	}
+	addr.Port = int(p)
+	return addr, nil
+}
+
+func readV2Header(r *bufio.Reader) (src, dst net.Addr, err error) {
+	var header [v2HeaderLength]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, nil, err
+	}
+	version, command := header[12]>>4, header[12]&0x0f
+	family, transport := header[13]>>4, header[13]&0x0f
+	length := binary.BigEndian.Uint16(header[14:])
+	if version != 2 {
+		return nil, nil, errors.Errorf("unsupported v2 header version %d", version)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, err
+	}
+
+	const (
+		commandLocal = 0x0
+		commandProxy = 0x1
+
+		familyInet  = 0x1
+		familyInet6 = 0x2
+
+		transportStream = 0x1
+	)
+	switch command {
+	case commandLocal:
+		return nil, nil, nil
+	case commandProxy:
+	default:
+		return nil, nil, errors.Errorf("unsupported v2 command %d", command)
+	}
+	if transport != transportStream {
+		// Addresses of unsupported transports are ignored,
+		// as recommended by the specification.
+		return nil, nil, nil
+	}
+
+	var ipLen int
+	switch family {
+	case familyInet:
+		ipLen = net.IPv4len
+	case familyInet6:
+		ipLen = net.IPv6len
+	default:
+		return nil, nil, nil
+	}
+	if len(payload) < 2*ipLen+4 {
+		return nil, nil, errors.New("invalid v2 address length")
+	}
+	src = &net.TCPAddr{
+		IP:   net.IP(payload[:ipLen]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLen:])),
+	}
+	dst = &net.TCPAddr{
+		IP:   net.IP(payload[ipLen : 2*ipLen]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLen+2:])),
+	}
+	return src, dst, nil
+}
@@ -0,0 +1,173 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package proxyproto
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListener(t *testing.T) {
+	for name, test := range map[string]struct {
+		header []byte
+		remote string
+		local  string
+	}{
+		"v1_tcp4": {
+			header: []byte("PROXY TCP4 192.0.2.1 198.51.100.1 56324 8200\r\n"),
+			remote: "192.0.2.1:56324",
+			local:  "198.51.100.1:8200",
+		},
+		"v1_tcp6": {
+			header: []byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 8200\r\n"),
+			remote: "[2001:db8::1]:56324",
+			local:  "[2001:db8::2]:8200",
+		},
+		"v1_unknown": {
+			header: []byte("PROXY UNKNOWN\r\n"),
+		},
+		"v2_inet": {
+			header: v2Header(0x21, 0x11, []byte{192, 0, 2, 1, 198, 51, 100, 1, 0xdc, 0x04, 0x20, 0x08}),
+			remote: "192.0.2.1:56324",
+			local:  "198.51.100.1:8200",
+		},
+		"v2_inet6": {
+			header: v2Header(0x21, 0x21, append(append(
+				net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2")...),
+				0xdc, 0x04, 0x20, 0x08,
+			)),
+			remote: "[2001:db8::1]:56324",
+			local:  "[2001:db8::2]:8200",
+		},
+		"v2_local": {
+			header: v2Header(0x20, 0x00, nil),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			lis := newTestListener(t, nil)
+			client := dial(t, lis, append(test.header, "hello"...))
+			defer client.Close()
+
+			conn, err := lis.Accept()
+			require.NoError(t, err)
+			defer conn.Close()
+			if test.remote == "" {
+				// The header conveys no addresses,
+				// so the proxy's address is used.
+				assert.Equal(t, client.LocalAddr().String(), conn.RemoteAddr().String())
+				assert.Equal(t, client.RemoteAddr().String(), conn.LocalAddr().String())
+			} else {
+				assert.Equal(t, test.remote, conn.RemoteAddr().String())
+				assert.Equal(t, test.local, conn.LocalAddr().String())
+			}
+			assertRead(t, conn, "hello")
+		})
+	}
+}
+
+func TestListenerInvalidHeader(t *testing.T) {
+	for name, header := range map[string]string{
+		"missing":          "GET / HTTP/1.1\r\n",
+		"v1_too_long":      "PROXY TCP4 " + string(make([]byte, v1MaxHeaderLength)),
+		"v1_invalid_ip":    "PROXY TCP4 192.0.2.256 198.51.100.1 56324 8200\r\n",
+		"v1_invalid_port":  "PROXY TCP4 192.0.2.1 198.51.100.1 65536 8200\r\n",
+		"v1_invalid_proto": "PROXY UDP4 192.0.2.1 198.51.100.1 56324 8200\r\n",
+		"v2_short_address": string(v2Header(0x21, 0x11, []byte{192, 0, 2, 1})),
+	} {
+		t.Run(name, func(t *testing.T) {
+			lis := newTestListener(t, nil)
+			client := dial(t, lis, []byte(header))
+			defer client.Close()
+
+			conn, err := lis.Accept()
+			require.NoError(t, err)
+			defer conn.Close()
+			assert.Equal(t, client.LocalAddr().String(), conn.RemoteAddr().String())
+			_, err = conn.Read(make([]byte, 1))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "error reading PROXY protocol header")
+		})
+	}
+}
+
+func TestListenerHeaderTimeout(t *testing.T) {
+	lis := newTestListener(t, nil)
+	lis.headerTimeout = 10 * time.Millisecond
+	client := dial(t, lis, []byte("PROXY"))
+	defer client.Close()
+
+	conn, err := lis.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Read(make([]byte, 1))
+	require.Error(t, err)
+	netErr, ok := errors.Cause(err).(net.Error)
+	require.True(t, ok)
+	assert.True(t, netErr.Timeout())
+}
+
+func TestListenerUntrusted(t *testing.T) {
+	_, trusted, err := net.ParseCIDR("192.0.2.0/24")
+	require.NoError(t, err)
+	lis := newTestListener(t, []*net.IPNet{trusted})
+	client := dial(t, lis, []byte("hello"))
+	defer client.Close()
+
+	conn, err := lis.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.IsType(t, &net.TCPConn{}, conn)
+	assert.Equal(t, client.LocalAddr().String(), conn.RemoteAddr().String())
+	assertRead(t, conn, "hello")
+}
+
+func newTestListener(t testing.TB, trusted []*net.IPNet) *Listener {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+	return NewListener(lis, trusted, time.Second)
+}
+
+func dial(t testing.TB, lis net.Listener, data []byte) net.Conn {
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	require.NoError(t, err)
+	_, err = conn.Write(data)
+	require.NoError(t, err)
+	return conn
+}
+
+func assertRead(t testing.TB, conn net.Conn, expected string) {
+	buf := make([]byte, len(expected))
+	_, err := io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(buf))
+}
+
+func v2Header(versionCommand, familyTransport byte, addrs []byte) []byte {
+	header := append([]byte{}, v2Signature...)
+	header = append(header, versionCommand, familyTransport, 0, 0)
+	binary.BigEndian.PutUint16(header[14:], uint16(len(addrs)))
+	return append(header, addrs...)
+}
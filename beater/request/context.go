@@ -18,7 +18,10 @@
 package request
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"io"
 	"net"
 	"net/http"
 	"strings"
@@ -30,6 +33,7 @@ import (
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/headers"
 	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/utility"
 )
 
 const (
@@ -51,8 +55,25 @@ type Context struct {
 	Result          Result
 	RequestMetadata Metadata
 
+	// PhaseTimings records the time spent in each phase of
+	// processing the request.
+	PhaseTimings utility.PhaseTimings
+
+	// Intake holds information about the events received in the
+	// request, recorded by intake handlers.
+	Intake Intake
+
 	w             http.ResponseWriter
 	writeAttempts int
+	streaming     bool
+
+	// beforeWriteHeader holds functions to call with the response
+	// headers immediately before they are written.
+	beforeWriteHeader []func(http.Header)
+
+	// compressMinSize, if positive, enables gzip compression of
+	// response bodies of at least compressMinSize bytes.
+	compressMinSize int
 }
 
 // Metadata contains metadata extracted from the request by middleware,
@@ -63,6 +84,17 @@ type Metadata struct {
 	UserAgent string
 }
 
+// Intake holds information about the events received in an intake request.
+type Intake struct {
+	AgentName          string
+	AgentVersion       string
+	ServiceName        string
+	ServiceEnvironment string
+
+	// Accepted holds the number of events accepted.
+	Accepted int
+}
+
 // NewContext creates an empty Context struct
 func NewContext() *Context {
 	return &Context{}
@@ -77,9 +109,14 @@ func (c *Context) Reset(w http.ResponseWriter, r *http.Request) {
 	c.IsRum = false
 	c.Result.Reset()
 	c.RequestMetadata.Reset()
+	c.PhaseTimings.Reset()
+	c.Intake = Intake{}
 
 	c.w = w
 	c.writeAttempts = 0
+	c.streaming = false
+	c.compressMinSize = 0
+	c.beforeWriteHeader = c.beforeWriteHeader[:0]
 }
 
 // Reset sets all attribtues of the Metadata instance to it's zero value
@@ -94,21 +131,53 @@ func (c *Context) Header() http.Header {
 	return c.w.Header()
 }
 
+// BeforeWriteHeader registers fn to be called with the response headers
+// immediately before they are written by Write or Stream, on the goroutine
+// writing the response.
+func (c *Context) BeforeWriteHeader(fn func(http.Header)) {
+	c.beforeWriteHeader = append(c.beforeWriteHeader, fn)
+}
+
+func (c *Context) callBeforeWriteHeader() {
+	for _, fn := range c.beforeWriteHeader {
+		fn(c.w.Header())
+	}
+}
+
+// CompressResponse enables gzip compression of the response body written by
+// Write, if the client accepts gzip encoding and the encoded body is at least
+// minSize bytes long.
+func (c *Context) CompressResponse(minSize int) {
+	c.compressMinSize = minSize
+}
+
 // MultipleWriteAttempts returns a boolean set to true if Write() was called multiple times.
 func (c *Context) MultipleWriteAttempts() bool {
 	return c.writeAttempts > 1
 }
 
+// Stream writes the response headers with the given status code and returns the
+// response writer, for handlers that stream the response body instead of writing
+// a single result. Once Stream has been called, calls to Write are ignored.
+func (c *Context) Stream(statusCode int) http.ResponseWriter {
+	c.streaming = true
+	c.callBeforeWriteHeader()
+	c.w.Header().Set(headers.XContentTypeOptions, "nosniff")
+	c.w.WriteHeader(statusCode)
+	return c.w
+}
+
 // Write sets response headers, and writes the body to the response writer.
 // In case body is nil only the headers will be set.
 // In case statusCode indicates an error response, the body is also set as error in the context.
 // Only first call with write to http response.
 func (c *Context) Write() {
-	if c.MultipleWriteAttempts() {
+	if c.streaming || c.MultipleWriteAttempts() {
 		return
 	}
 	c.writeAttempts++
 
+	c.callBeforeWriteHeader()
 	c.w.Header().Set(headers.XContentTypeOptions, "nosniff")
 
 	body := c.Result.Body
@@ -120,25 +189,85 @@ func (c *Context) Write() {
 	// wrap body in map: necessary to keep current logic
 	if c.Result.Failure() {
 		if b, ok := body.(string); ok {
-			body = map[string]string{"error": b}
+			m := map[string]string{"error": b}
+			if code := c.Result.ErrorCode(); code != "" {
+				m["code"] = string(code)
+			}
+			body = m
 		}
 	}
 
 	var err error
-	if c.acceptJSON() {
+	if c.compressMinSize > 0 {
+		err = c.writeCompressed(body)
+	} else if c.acceptJSON() {
 		c.w.Header().Set(headers.ContentType, "application/json")
 		c.w.WriteHeader(c.Result.StatusCode)
-		err = c.writeJSON(body, true)
+		err = writeJSON(c.w, body, true)
 	} else {
 		c.w.Header().Set(headers.ContentType, "text/plain; charset=utf-8")
 		c.w.WriteHeader(c.Result.StatusCode)
-		err = c.writePlain(body)
+		err = writePlain(c.w, body)
 	}
 	if err != nil {
 		c.errOnWrite(err)
 	}
 }
 
+// writeCompressed encodes body into a buffer, and then writes it to the
+// response, gzip-compressed if the client accepts it and the encoded body
+// is at least c.compressMinSize bytes long.
+func (c *Context) writeCompressed(body interface{}) error {
+	var buf bytes.Buffer
+	var err error
+	if c.acceptJSON() {
+		c.w.Header().Set(headers.ContentType, "application/json")
+		err = writeJSON(&buf, body, true)
+	} else {
+		c.w.Header().Set(headers.ContentType, "text/plain; charset=utf-8")
+		err = writePlain(&buf, body)
+	}
+	if err != nil {
+		c.w.WriteHeader(c.Result.StatusCode)
+		return err
+	}
+
+	// The response body depends on the Accept-Encoding request header,
+	// regardless of whether this particular response is compressed.
+	c.w.Header().Add(headers.Vary, headers.AcceptEncoding)
+	if buf.Len() < c.compressMinSize || !c.acceptGzip() {
+		c.w.WriteHeader(c.Result.StatusCode)
+		_, err := buf.WriteTo(c.w)
+		return err
+	}
+	c.w.Header().Set(headers.ContentEncoding, "gzip")
+	c.w.Header().Del(headers.ContentLength)
+	c.w.WriteHeader(c.Result.StatusCode)
+	zw := gzip.NewWriter(c.w)
+	if _, err := buf.WriteTo(zw); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func (c *Context) acceptGzip() bool {
+	for _, v := range c.Request.Header[headers.AcceptEncoding] {
+		for _, encoding := range strings.Split(v, ",") {
+			encoding = strings.TrimSpace(encoding)
+			if i := strings.IndexByte(encoding, ';'); i >= 0 {
+				if strings.TrimSpace(encoding[i+1:]) == "q=0" {
+					continue
+				}
+				encoding = strings.TrimSpace(encoding[:i])
+			}
+			if encoding == "gzip" || encoding == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (c *Context) acceptJSON() bool {
 	acceptHeader := c.Request.Header.Get(headers.Accept)
 	for _, s := range mimeTypesJSON {
@@ -149,21 +278,21 @@ func (c *Context) acceptJSON() bool {
 	return false
 }
 
-func (c *Context) writeJSON(body interface{}, pretty bool) error {
-	enc := json.NewEncoder(c.w)
+func writeJSON(w io.Writer, body interface{}, pretty bool) error {
+	enc := json.NewEncoder(w)
 	if pretty {
 		enc.SetIndent("", "  ")
 	}
 	return enc.Encode(body)
 }
 
-func (c *Context) writePlain(body interface{}) error {
+func writePlain(w io.Writer, body interface{}) error {
 	if b, ok := body.(string); ok {
-		_, err := c.w.Write([]byte(b + "\n"))
+		_, err := w.Write([]byte(b + "\n"))
 		return err
 	}
 	// unexpected behavior to return json but changing this would be breaking
-	return c.writeJSON(body, false)
+	return writeJSON(w, body, false)
 }
 
 func (c *Context) errOnWrite(err error) {
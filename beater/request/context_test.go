@@ -18,18 +18,25 @@
 package request
 
 import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/beats/v7/libbeat/logp"
 
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/utility"
 )
 
 func TestContext_Reset(t *testing.T) {
@@ -41,13 +48,17 @@ func TestContext_Reset(t *testing.T) {
 
 	c := Context{
 		Request: r1, w: w1,
-		Logger: logp.NewLogger(""),
+		Logger:            logp.NewLogger(""),
+		compressMinSize:   1,
+		beforeWriteHeader: []func(http.Header){func(http.Header) {}},
+		Intake:            Intake{AgentName: "go", Accepted: 1},
 		Result: Result{
 			StatusCode: http.StatusServiceUnavailable,
 			Err:        errors.New("foo"),
 			Stacktrace: "bar",
 		},
 	}
+	c.PhaseTimings.Add(utility.PhaseAuth, time.Second)
 	c.Reset(w2, r2)
 
 	// use reflection to ensure all fields of `context` are tested
@@ -63,16 +74,41 @@ func TestContext_Reset(t *testing.T) {
 			assert.Equal(t, w2, c.w)
 		case "writeAttempts":
 			assert.Equal(t, 0, c.writeAttempts)
+		case "streaming":
+			assert.False(t, c.streaming)
+		case "compressMinSize":
+			assert.Equal(t, 0, c.compressMinSize)
+		case "beforeWriteHeader":
+			assert.Empty(t, c.beforeWriteHeader)
 		case "Result":
 			assertResultIsEmpty(t, cVal.Field(i).Interface().(Result))
 		case "RequestMetadata":
 			assert.Equal(t, Metadata{}, cVal.Field(i).Interface().(Metadata))
+		case "PhaseTimings":
+			assert.Zero(t, c.PhaseTimings.Total())
 		default:
 			assert.Empty(t, cVal.Field(i).Interface(), cType.Field(i).Name)
 		}
 	}
 }
 
+func TestContext_BeforeWriteHeader(t *testing.T) {
+	for name, write := range map[string]func(*Context){
+		"write":  func(c *Context) { c.Write() },
+		"stream": func(c *Context) { c.Stream(http.StatusOK) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c := NewContext()
+			c.Reset(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			c.Result.SetDefault(IDResponseValidOK)
+			c.BeforeWriteHeader(func(h http.Header) { h.Set("X-Test", "before") })
+			write(c)
+			assert.Equal(t, "before", w.Result().Header.Get("X-Test"))
+		})
+	}
+}
+
 func TestContext_Header(t *testing.T) {
 	w := httptest.NewRecorder()
 	w.Header().Set(headers.Etag, "abcd")
@@ -97,6 +133,18 @@ func TestContext_Write(t *testing.T) {
 		assert.Empty(t, w.Body.String())
 	})
 
+	t.Run("Stream", func(t *testing.T) {
+		c, w := mockContextAccept("*/*")
+		sw := c.Stream(http.StatusAccepted)
+		sw.Write([]byte("foo\n"))
+		c.Result = Result{Body: "bar", StatusCode: http.StatusBadRequest}
+		c.Write()
+
+		testHeaderXContentTypeOptions(t, c)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "foo\n", w.Body.String())
+	})
+
 	t.Run("EmptyBody", func(t *testing.T) {
 		c, w := mockContextAccept("*/*")
 		c.Result = Result{Body: nil, StatusCode: http.StatusAccepted}
@@ -196,6 +244,48 @@ func TestContext_Write(t *testing.T) {
 	})
 }
 
+func TestContext_WriteCompressed(t *testing.T) {
+	body := map[string]interface{}{"xyz": strings.Repeat("a", 100)}
+	for name, tc := range map[string]struct {
+		acceptEncoding string
+		minSize        int
+		compressed     bool
+	}{
+		"gzip":           {acceptEncoding: "gzip", minSize: 10, compressed: true},
+		"gzip_deflate":   {acceptEncoding: "deflate, gzip;q=0.5", minSize: 10, compressed: true},
+		"gzip_q0":        {acceptEncoding: "gzip;q=0", minSize: 10, compressed: false},
+		"any":            {acceptEncoding: "*", minSize: 10, compressed: true},
+		"no_gzip":        {acceptEncoding: "deflate", minSize: 10, compressed: false},
+		"none":           {acceptEncoding: "", minSize: 10, compressed: false},
+		"below_min_size": {acceptEncoding: "gzip", minSize: 1000, compressed: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, w := mockContextAccept("application/json")
+			c.Request.Header.Set(headers.AcceptEncoding, tc.acceptEncoding)
+			c.CompressResponse(tc.minSize)
+			c.Result = Result{StatusCode: http.StatusOK, Body: body}
+			c.Write()
+
+			testHeader(t, c, "application/json")
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "Accept-Encoding", w.Header().Get(headers.Vary))
+
+			var r io.Reader = w.Body
+			if tc.compressed {
+				assert.Equal(t, "gzip", w.Header().Get(headers.ContentEncoding))
+				zr, err := gzip.NewReader(w.Body)
+				require.NoError(t, err)
+				r = zr
+			} else {
+				assert.Empty(t, w.Header().Get(headers.ContentEncoding))
+			}
+			var decoded map[string]interface{}
+			require.NoError(t, json.NewDecoder(r).Decode(&decoded))
+			assert.Equal(t, body, decoded)
+		})
+	}
+}
+
 func testHeaderXContentTypeOptions(t *testing.T, c *Context) {
 	assert.Equal(t, "nosniff", c.w.Header().Get(headers.XContentTypeOptions))
 }
@@ -23,6 +23,8 @@ import (
 	"github.com/elastic/beats/v7/libbeat/monitoring"
 
 	"github.com/pkg/errors"
+
+	"github.com/elastic/apm-server/beater/errorcode"
 )
 
 const (
@@ -49,6 +51,8 @@ const (
 	IDResponseValidOK ResultID = "response.valid.ok"
 	// IDResponseValidAccepted identifies responses with status code 202
 	IDResponseValidAccepted ResultID = "response.valid.accepted"
+	// IDResponseValidNoContent identifies responses with status code 204
+	IDResponseValidNoContent ResultID = "response.valid.nocontent"
 
 	// IDResponseErrorsForbidden identifies responses for forbidden requests
 	IDResponseErrorsForbidden ResultID = "response.errors.forbidden"
@@ -86,21 +90,22 @@ var (
 	MapResultIDToStatus = map[ResultID]Status{
 		IDResponseValidOK:                  {Code: http.StatusOK, Keyword: "request ok"},
 		IDResponseValidAccepted:            {Code: http.StatusAccepted, Keyword: "request accepted"},
+		IDResponseValidNoContent:           {Code: http.StatusNoContent, Keyword: "no content"},
 		IDResponseValidNotModified:         {Code: http.StatusNotModified, Keyword: "not modified"},
-		IDResponseErrorsForbidden:          {Code: http.StatusForbidden, Keyword: "forbidden request"},
-		IDResponseErrorsUnauthorized:       {Code: http.StatusUnauthorized, Keyword: "unauthorized"},
-		IDResponseErrorsNotFound:           {Code: http.StatusNotFound, Keyword: "404 page not found"},
-		IDResponseErrorsRequestTooLarge:    {Code: http.StatusRequestEntityTooLarge, Keyword: "request body too large"},
-		IDResponseErrorsInvalidQuery:       {Code: http.StatusBadRequest, Keyword: "invalid query"},
-		IDResponseErrorsDecode:             {Code: http.StatusBadRequest, Keyword: "data decoding error"},
-		IDResponseErrorsValidate:           {Code: http.StatusBadRequest, Keyword: "data validation error"},
-		IDResponseErrorsMethodNotAllowed:   {Code: http.StatusMethodNotAllowed, Keyword: "method not supported"},
-		IDResponseErrorsRateLimit:          {Code: http.StatusTooManyRequests, Keyword: "too many requests"},
-		IDResponseErrorsTimeout:            {Code: http.StatusServiceUnavailable, Keyword: "request timed out"},
-		IDResponseErrorsFullQueue:          {Code: http.StatusServiceUnavailable, Keyword: "queue is full"},
-		IDResponseErrorsShuttingDown:       {Code: http.StatusServiceUnavailable, Keyword: "server is shutting down"},
-		IDResponseErrorsServiceUnavailable: {Code: http.StatusServiceUnavailable, Keyword: "service unavailable"},
-		IDResponseErrorsInternal:           {Code: http.StatusInternalServerError, Keyword: "internal error"},
+		IDResponseErrorsForbidden:          {Code: http.StatusForbidden, Keyword: "forbidden request", ErrorCode: errorcode.Forbidden},
+		IDResponseErrorsUnauthorized:       {Code: http.StatusUnauthorized, Keyword: "unauthorized", ErrorCode: errorcode.Unauthorized},
+		IDResponseErrorsNotFound:           {Code: http.StatusNotFound, Keyword: "404 page not found", ErrorCode: errorcode.NotFound},
+		IDResponseErrorsRequestTooLarge:    {Code: http.StatusRequestEntityTooLarge, Keyword: "request body too large", ErrorCode: errorcode.TooLarge},
+		IDResponseErrorsInvalidQuery:       {Code: http.StatusBadRequest, Keyword: "invalid query", ErrorCode: errorcode.InvalidQuery},
+		IDResponseErrorsDecode:             {Code: http.StatusBadRequest, Keyword: "data decoding error", ErrorCode: errorcode.Decode},
+		IDResponseErrorsValidate:           {Code: http.StatusBadRequest, Keyword: "data validation error", ErrorCode: errorcode.Validate},
+		IDResponseErrorsMethodNotAllowed:   {Code: http.StatusMethodNotAllowed, Keyword: "method not supported", ErrorCode: errorcode.MethodNotAllowed},
+		IDResponseErrorsRateLimit:          {Code: http.StatusTooManyRequests, Keyword: "too many requests", ErrorCode: errorcode.RateLimit},
+		IDResponseErrorsTimeout:            {Code: http.StatusServiceUnavailable, Keyword: "request timed out", ErrorCode: errorcode.Timeout},
+		IDResponseErrorsFullQueue:          {Code: http.StatusServiceUnavailable, Keyword: "queue is full", ErrorCode: errorcode.QueueFull},
+		IDResponseErrorsShuttingDown:       {Code: http.StatusServiceUnavailable, Keyword: "server is shutting down", ErrorCode: errorcode.ShuttingDown},
+		IDResponseErrorsServiceUnavailable: {Code: http.StatusServiceUnavailable, Keyword: "service unavailable", ErrorCode: errorcode.Unavailable},
+		IDResponseErrorsInternal:           {Code: http.StatusInternalServerError, Keyword: "internal error", ErrorCode: errorcode.Internal},
 	}
 
 	// DefaultResultIDs is a list of the default result IDs used by the package.
@@ -110,10 +115,12 @@ var (
 // ResultID unique string identifying a requests Result
 type ResultID string
 
-// Status holds statuscode and keyword information
+// Status holds statuscode and keyword information, and the
+// machine-readable error code of failure statuses.
 type Status struct {
-	Code    int
-	Keyword string
+	Code      int
+	Keyword   string
+	ErrorCode errorcode.Code
 }
 
 // Result holds information about a processed request
@@ -124,6 +131,11 @@ type Result struct {
 	Body       interface{}
 	Err        error
 	Stacktrace string
+
+	// Code holds the machine-readable error code of a failed result,
+	// for failures identified more precisely than by the result's ID.
+	// If Code is empty, the error code is derived from the ID.
+	Code errorcode.Code
 }
 
 // DefaultMonitoringMapForRegistry returns map matching resultIDs to monitoring counters for given registry.
@@ -155,6 +167,7 @@ func (r *Result) Reset() {
 	r.Body = nil
 	r.Err = nil
 	r.Stacktrace = ""
+	r.Code = ""
 }
 
 // Failure returns a bool indicating whether it is describing a successful result or not
@@ -162,6 +175,20 @@ func (r *Result) Failure() bool {
 	return r.StatusCode >= http.StatusBadRequest
 }
 
+// ErrorCode returns the machine-readable error code of a failed result:
+// Code if set, or else the code derived from the result's ID. An empty code
+// is returned for successful results, and for failures with an ID that has
+// no error code.
+func (r *Result) ErrorCode() errorcode.Code {
+	if !r.Failure() {
+		return ""
+	}
+	if r.Code != "" {
+		return r.Code
+	}
+	return MapResultIDToStatus[r.ID].ErrorCode
+}
+
 // SetDefault derives information about the result solely from the ID.
 func (r *Result) SetDefault(id ResultID) {
 	r.set(id, nil, nil)
@@ -189,6 +216,7 @@ func (r *Result) Set(id ResultID, statusCode int, keyword string, body interface
 	r.Keyword = keyword
 	r.Body = body
 	r.Err = err
+	r.Code = ""
 
 	if r.Failure() {
 		if err == nil {
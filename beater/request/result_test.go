@@ -189,10 +189,25 @@ func TestResult_Failure(t *testing.T) {
 	assert.True(t, (&Result{StatusCode: http.StatusServiceUnavailable}).Failure())
 }
 
+func TestResult_ErrorCode(t *testing.T) {
+	assert.Empty(t, (&Result{ID: IDResponseValidAccepted, StatusCode: http.StatusAccepted}).ErrorCode())
+	assert.Empty(t, (&Result{ID: IDUnset, StatusCode: http.StatusBadRequest}).ErrorCode())
+	for id, status := range MapResultIDToStatus {
+		r := Result{}
+		r.SetDefault(id)
+		if r.Failure() {
+			assert.NotEmpty(t, r.ErrorCode(), id)
+			assert.Equal(t, status.ErrorCode, r.ErrorCode(), id)
+		} else {
+			assert.Empty(t, r.ErrorCode(), id)
+		}
+	}
+}
+
 func TestDefaultMonitoringMapForRegistry(t *testing.T) {
 	mockRegistry := monitoring.Default.NewRegistry("mock-default")
 	m := DefaultMonitoringMapForRegistry(mockRegistry)
-	assert.Equal(t, 22, len(m))
+	assert.Equal(t, 23, len(m))
 	for id := range m {
 		assert.Equal(t, int64(0), m[id].Get())
 	}
@@ -32,15 +32,19 @@ import (
 	"github.com/elastic/beats/v7/libbeat/version"
 
 	"github.com/elastic/apm-server/agentcfg"
+	"github.com/elastic/apm-server/beater/api/config/agent"
 	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/beater/interceptors"
 	"github.com/elastic/apm-server/beater/jaeger"
+	"github.com/elastic/apm-server/beater/opencensus"
 	"github.com/elastic/apm-server/beater/otlp"
+	"github.com/elastic/apm-server/elasticsearch"
 	"github.com/elastic/apm-server/kibana"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/model/modelprocessor"
 	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
 )
 
 // RunServerFunc is a function which runs the APM Server until a
@@ -71,6 +75,10 @@ type ServerParams struct {
 	// BatchProcessor is the model.BatchProcessor that is used
 	// for publishing events to the output, such as Elasticsearch.
 	BatchProcessor model.BatchProcessor
+
+	// TransformConfig holds the configuration used for transforming
+	// events when they are published, such as RUM source mapping.
+	TransformConfig *transform.Config
 }
 
 // newBaseRunServer returns the base RunServerFunc.
@@ -106,10 +114,16 @@ type server struct {
 
 	httpServer   *httpServer
 	grpcServer   *grpc.Server
+	grpcHealth   *grpcHealthServer
 	jaegerServer *jaeger.Server
 }
 
 func newServer(logger *logp.Logger, info beat.Info, cfg *config.Config, tracer *apm.Tracer, reporter publish.Reporter, batchProcessor model.BatchProcessor) (server, error) {
+	if cfg.APIKeyConfig.IsEnabled() || cfg.Auth.Anonymous.Enabled {
+		// Reject events of services to which the request's API Key
+		// is not scoped, or which are not allowed for anonymous access.
+		batchProcessor = authorization.ServiceBatchProcessor(batchProcessor)
+	}
 	httpServer, err := newHTTPServer(logger, info, cfg, tracer, reporter, batchProcessor)
 	if err != nil {
 		return server{}, err
@@ -118,6 +132,7 @@ func newServer(logger *logp.Logger, info beat.Info, cfg *config.Config, tracer *
 	if err != nil {
 		return server{}, err
 	}
+	grpcHealth := registerGRPCHealthAndReflection(grpcServer, cfg.GRPC)
 	jaegerServer, err := jaeger.NewServer(logger, cfg, tracer, batchProcessor)
 	if err != nil {
 		return server{}, err
@@ -127,6 +142,7 @@ func newServer(logger *logp.Logger, info beat.Info, cfg *config.Config, tracer *
 		cfg:          cfg,
 		httpServer:   httpServer,
 		grpcServer:   grpcServer,
+		grpcHealth:   grpcHealth,
 		jaegerServer: jaegerServer,
 	}, nil
 }
@@ -143,6 +159,7 @@ func newGRPCServer(
 	// NOTE(axw) even if TLS is enabled we should not use grpc.Creds, as TLS is handled by the net/http server.
 	apmInterceptor := apmgrpc.NewUnaryServerInterceptor(apmgrpc.WithRecovery(), apmgrpc.WithTracer(tracer))
 	authInterceptor := newAuthUnaryServerInterceptor(authBuilder)
+	authStreamInterceptor := newAuthStreamServerInterceptor(authBuilder)
 
 	logger = logger.Named("grpc")
 	srv := grpc.NewServer(
@@ -150,10 +167,15 @@ func newGRPCServer(
 			apmInterceptor,
 			interceptors.ClientMetadata(),
 			interceptors.Logging(logger),
-			interceptors.Metrics(logger, otlp.RegistryMonitoringMaps, jaeger.RegistryMonitoringMaps),
+			interceptors.ErrorCode(),
+			interceptors.Metrics(logger, otlp.RegistryMonitoringMaps, jaeger.RegistryMonitoringMaps, grpcServicesMonitoringMaps),
 			interceptors.Timeout(),
 			authInterceptor,
 		),
+		grpc.ChainStreamInterceptor(
+			interceptors.StreamMetrics(logger, opencensus.RegistryMonitoringMaps, grpcServicesMonitoringMaps),
+			authStreamInterceptor,
+		),
 	)
 
 	if cfg.AugmentEnabled {
@@ -168,10 +190,20 @@ func newGRPCServer(
 	var agentcfgFetcher *agentcfg.Fetcher
 	if cfg.Kibana.Enabled {
 		kibanaClient = kibana.NewConnectingClient(&cfg.Kibana)
-		agentcfgFetcher = agentcfg.NewFetcher(kibanaClient, cfg.AgentConfig.Cache.Expiration)
+		agentcfgFetcher = agentcfg.NewFetcher(kibanaClient, agent.FetcherConfig(cfg.AgentConfig))
+	}
+	if cfg.AgentConfig.ElasticsearchEnabled() {
+		esClient, err := elasticsearch.NewClient(cfg.AgentConfig.ESConfig)
+		if err != nil {
+			return nil, err
+		}
+		agentcfgFetcher = agentcfg.NewElasticsearchFetcher(kibanaClient, esClient, agent.FetcherConfig(cfg.AgentConfig))
 	}
-	jaeger.RegisterGRPCServices(srv, authBuilder, jaeger.ElasticAuthTag, logger, batchProcessor, kibanaClient, agentcfgFetcher)
-	if err := otlp.RegisterGRPCServices(srv, batchProcessor); err != nil {
+	jaeger.RegisterGRPCServices(srv, authBuilder, jaeger.ElasticAuthTag, logger, batchProcessor, kibanaClient, agentcfgFetcher, cfg.OTel)
+	if err := otlp.RegisterGRPCServices(srv, batchProcessor, cfg.OTel); err != nil {
+		return nil, err
+	}
+	if err := opencensus.RegisterGRPCServices(srv, batchProcessor, otlp.InstrumentationScopeRules(cfg.OTel)); err != nil {
 		return nil, err
 	}
 	return srv, nil
@@ -180,7 +212,16 @@ func newGRPCServer(
 func (s server) run() error {
 	s.logger.Infof("Starting apm-server [%s built %s]. Hit CTRL-C to stop it.", version.Commit(), version.BuildTime())
 	var g errgroup.Group
-	g.Go(s.httpServer.start)
+	g.Go(func() error {
+		err := s.httpServer.start()
+		if err != http.ErrServerClosed {
+			// The HTTP server failed to start, e.g. because its address
+			// could not be listened on. Stop the other servers, which
+			// would otherwise keep the server running without it.
+			s.stop()
+		}
+		return err
+	})
 	g.Go(func() error {
 		return s.grpcServer.Serve(s.httpServer.grpcListener)
 	})
@@ -198,6 +239,9 @@ func (s server) stop() {
 	if s.jaegerServer != nil {
 		s.jaegerServer.Stop()
 	}
+	// Report the gRPC services as not serving before draining,
+	// so clients checking health stop sending new requests.
+	s.grpcHealth.Shutdown()
 	s.grpcServer.GracefulStop()
 	s.httpServer.stop()
 }
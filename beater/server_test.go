@@ -28,6 +28,7 @@ import (
 	"os"
 	"reflect"
 	"runtime"
+	"sync"
 	"testing"
 	"time"
 
@@ -54,6 +55,8 @@ import (
 
 	"github.com/elastic/apm-server/beater/api"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/errorcode"
+	"github.com/elastic/apm-server/beater/interceptors"
 	"github.com/elastic/apm-server/elasticsearch"
 	"github.com/elastic/apm-server/tests/loader"
 )
@@ -437,6 +440,7 @@ func TestServerOTLPGRPC(t *testing.T) {
 	err = invokeExport(ctx, conn)
 	assert.Error(t, err)
 	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, errorcode.MissingCredentials, interceptors.ErrorCodeFromError(err))
 
 	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("Authorization", "Bearer abc123"))
 	err = invokeExport(ctx, conn)
@@ -465,7 +469,8 @@ func TestServerConfigReload(t *testing.T) {
 		"data_streams.enabled": true,
 	})
 	apmBeat, cfg := newBeat(t, cfg, nil, nil)
-	apmBeat.Manager = &mockManager{enabled: true}
+	manager := &mockManager{enabled: true}
+	apmBeat.Manager = manager
 	beater, err := newTestBeater(t, apmBeat, cfg, nil)
 	require.NoError(t, err)
 	beater.start()
@@ -508,6 +513,8 @@ func TestServerConfigReload(t *testing.T) {
 	addr1, err := beater.waitListenAddr(1 * time.Second)
 	require.NoError(t, err)
 	assert.NotEmpty(t, healthcheck(addr1)) // non-empty as there's no auth required
+	status, _ := manager.lastStatus()
+	assert.Equal(t, management.Running, status)
 
 	// Reload config, causing the HTTP server to be restarted.
 	require.NoError(t, inputConfig.SetString("apm-server.secret_token", -1, "secret"))
@@ -521,6 +528,18 @@ func TestServerConfigReload(t *testing.T) {
 	// First HTTP server should have been stopped.
 	_, err = http.Get("http://" + addr1)
 	assert.Error(t, err)
+
+	// Reload config with an address that cannot be listened on,
+	// causing the server to fail and report its status as failed.
+	require.NoError(t, inputConfig.SetString("apm-server.host", -1, "testing.invalid:123"))
+	err = reloadable.Reload([]*reload.ConfigWithMeta{{Config: inputConfig}})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		status, _ := manager.lastStatus()
+		return status == management.Failed
+	}, 10*time.Second, 10*time.Millisecond)
+	_, msg := manager.lastStatus()
+	assert.Contains(t, msg, "testing.invalid")
 }
 
 type chanClient struct {
@@ -635,8 +654,28 @@ func body(t *testing.T, response *http.Response) string {
 type mockManager struct {
 	management.Manager
 	enabled bool
+
+	mu       sync.Mutex
+	statuses []management.Status
+	messages []string
 }
 
 func (m *mockManager) Enabled() bool {
 	return m.enabled
 }
+
+func (m *mockManager) UpdateStatus(status management.Status, msg string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = append(m.statuses, status)
+	m.messages = append(m.messages, msg)
+}
+
+func (m *mockManager) lastStatus() (management.Status, string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.statuses) == 0 {
+		return management.Unknown, ""
+	}
+	return m.statuses[len(m.statuses)-1], m.messages[len(m.messages)-1]
+}
@@ -0,0 +1,232 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package synthetics provides synthetic monitors, which periodically
+// probe URLs and record the results as transactions.
+package synthetics
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.elastic.co/apm"
+	"go.elastic.co/apm/module/apmhttp"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+)
+
+const (
+	transactionType = "synthetic"
+	agentName       = "synthetics"
+)
+
+// Monitor holds the configuration of a synthetic monitor.
+type Monitor struct {
+	// URL holds the URL to probe with an HTTP GET request.
+	URL string
+
+	// ServiceName and ServiceEnvironment identify the service
+	// to which the monitor's transactions belong.
+	ServiceName        string
+	ServiceEnvironment string
+
+	// Interval holds the interval at which the URL is probed.
+	Interval time.Duration
+
+	// Timeout holds the maximum duration of a probe.
+	Timeout time.Duration
+
+	// ExpectedStatus holds the HTTP status code of a successful probe.
+	ExpectedStatus int
+}
+
+// Config holds configuration for running synthetic monitors.
+type Config struct {
+	// Monitors holds the synthetic monitors to run.
+	Monitors []Monitor
+
+	// BatchProcessor is a model.BatchProcessor for processing
+	// the transactions recorded for each probe.
+	BatchProcessor model.BatchProcessor
+
+	// Client is the http.Client used for probing.
+	//
+	// If Client is nil, http.DefaultClient will be used.
+	Client *http.Client
+
+	// Logger is the logger for logging probe results.
+	//
+	// If Logger is nil, a new logger will be constructed.
+	Logger *logp.Logger
+}
+
+// Validate validates the config.
+func (config Config) Validate() error {
+	if config.BatchProcessor == nil {
+		return errors.New("BatchProcessor unspecified")
+	}
+	for _, m := range config.Monitors {
+		if m.URL == "" {
+			return errors.New("URL unspecified")
+		}
+		if m.Interval <= 0 {
+			return errors.Errorf("Interval for %q unspecified or negative", m.URL)
+		}
+		if m.Timeout <= 0 {
+			return errors.Errorf("Timeout for %q unspecified or negative", m.URL)
+		}
+	}
+	return nil
+}
+
+// Run runs the configured monitors until ctx is cancelled, probing each
+// monitor's URL at its interval and processing a transaction describing
+// the result.
+//
+// Each probe request carries a traceparent header, so if the probed
+// service is instrumented, its transactions are part of the same trace.
+func Run(ctx context.Context, config Config) error {
+	if err := config.Validate(); err != nil {
+		return errors.Wrap(err, "invalid synthetics config")
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger(logs.Synthetics)
+	}
+	var wg sync.WaitGroup
+	for _, m := range config.Monitors {
+		wg.Add(1)
+		go func(m Monitor) {
+			defer wg.Done()
+			runMonitor(ctx, config, m)
+		}(m)
+	}
+	wg.Wait()
+	return nil
+}
+
+func runMonitor(ctx context.Context, config Config, m Monitor) {
+	ticker := time.NewTicker(m.Interval)
+	defer ticker.Stop()
+	for {
+		tx := probe(ctx, config.Client, m)
+		if ctx.Err() != nil {
+			return
+		}
+		config.Logger.Debugf("probed %s: %s", m.URL, tx.Result)
+		batch := model.Batch{Transactions: []*model.Transaction{tx}}
+		if err := config.BatchProcessor.ProcessBatch(ctx, &batch); err != nil {
+			config.Logger.With(logp.Error(err)).Warnf("processing synthetic transaction failed: %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe performs a single HTTP GET request to the monitor's URL,
+// and returns a transaction describing its outcome.
+func probe(ctx context.Context, client *http.Client, m Monitor) *model.Transaction {
+	traceContext := newTraceContext()
+	sampled := true
+	tx := &model.Transaction{
+		Metadata: model.Metadata{
+			Service: model.Service{
+				Name:        m.ServiceName,
+				Environment: m.ServiceEnvironment,
+				Agent:       model.Agent{Name: agentName},
+			},
+		},
+		ID:                  traceContext.Span.String(),
+		TraceID:             traceContext.Trace.String(),
+		Type:                transactionType,
+		Name:                "GET " + m.URL,
+		Sampled:             &sampled,
+		RepresentativeCount: 1,
+		URL:                 model.ParseURL(m.URL, "", ""),
+		HTTP:                &model.Http{Request: &model.Req{Method: http.MethodGet}},
+	}
+
+	start := time.Now()
+	statusCode, err := get(ctx, client, m, traceContext)
+	tx.Timestamp = start
+	tx.Duration = time.Since(start)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		tx.Result = "timeout"
+		tx.Outcome = "failure"
+	case err != nil:
+		tx.Result = "error"
+		tx.Outcome = "failure"
+	default:
+		tx.HTTP.Response = &model.Resp{MinimalResp: model.MinimalResp{StatusCode: statusCode}}
+		tx.Result = fmt.Sprintf("HTTP %dxx", statusCode/100)
+		if statusCode == m.ExpectedStatus {
+			tx.Outcome = "success"
+		} else {
+			tx.Outcome = "failure"
+		}
+	}
+	return tx
+}
+
+// get sends an HTTP GET request to the monitor's URL, propagating
+// traceContext, and returns the response status code.
+func get(ctx context.Context, client *http.Client, m Monitor, traceContext apm.TraceContext) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodGet, m.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	headerValue := apmhttp.FormatTraceparentHeader(traceContext)
+	req.Header.Set(apmhttp.ElasticTraceparentHeader, headerValue)
+	req.Header.Set(apmhttp.W3CTraceparentHeader, headerValue)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func newTraceContext() apm.TraceContext {
+	var traceContext apm.TraceContext
+	rand.Read(traceContext.Trace[:])
+	rand.Read(traceContext.Span[:])
+	traceContext.Options = traceContext.Options.WithRecorded(true)
+	return traceContext
+}
@@ -0,0 +1,164 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package synthetics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.elastic.co/apm/module/apmhttp"
+
+	"github.com/elastic/apm-server/model"
+)
+
+func TestConfigInvalid(t *testing.T) {
+	batchProcessor := model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return nil })
+	for _, test := range []struct {
+		config Config
+		err    string
+	}{{
+		config: Config{},
+		err:    "BatchProcessor unspecified",
+	}, {
+		config: Config{BatchProcessor: batchProcessor, Monitors: []Monitor{{}}},
+		err:    "URL unspecified",
+	}, {
+		config: Config{BatchProcessor: batchProcessor, Monitors: []Monitor{{URL: "http://testing.invalid"}}},
+		err:    `Interval for "http://testing.invalid" unspecified or negative`,
+	}, {
+		config: Config{BatchProcessor: batchProcessor, Monitors: []Monitor{{URL: "http://testing.invalid", Interval: time.Second}}},
+		err:    `Timeout for "http://testing.invalid" unspecified or negative`,
+	}} {
+		err := Run(context.Background(), test.config)
+		assert.EqualError(t, err, "invalid synthetics config: "+test.err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	traceparents := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get(apmhttp.W3CTraceparentHeader)
+		switch r.URL.Path {
+		case "/slow":
+			<-r.Context().Done()
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	transactions := make(chan *model.Transaction, 10)
+	batchProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		for _, tx := range batch.Transactions {
+			transactions <- tx
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	newMonitor := func(path string) Monitor {
+		return Monitor{
+			URL:                srv.URL + path,
+			ServiceName:        "opbeans",
+			ServiceEnvironment: "production",
+			Interval:           time.Hour,
+			Timeout:            100 * time.Millisecond,
+			ExpectedStatus:     http.StatusOK,
+		}
+	}
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, Config{
+			Monitors:       []Monitor{newMonitor("/ok"), newMonitor("/error"), newMonitor("/slow")},
+			BatchProcessor: batchProcessor,
+		})
+	}()
+
+	results := make(map[string]*model.Transaction)
+	for i := 0; i < 3; i++ {
+		select {
+		case tx := <-transactions:
+			results[tx.URL.Path] = tx
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for synthetic transactions")
+		}
+	}
+	cancel()
+	require.NoError(t, <-done)
+
+	for path, expected := range map[string]struct {
+		result  string
+		outcome string
+		status  int
+	}{
+		"/ok":    {result: "HTTP 2xx", outcome: "success", status: http.StatusOK},
+		"/error": {result: "HTTP 5xx", outcome: "failure", status: http.StatusInternalServerError},
+		"/slow":  {result: "timeout", outcome: "failure"},
+	} {
+		tx := results[path]
+		require.NotNil(t, tx, path)
+		assert.Equal(t, "synthetic", tx.Type)
+		assert.Equal(t, "GET "+srv.URL+path, tx.Name)
+		assert.Equal(t, model.Service{
+			Name:        "opbeans",
+			Environment: "production",
+			Agent:       model.Agent{Name: "synthetics"},
+		}, tx.Metadata.Service)
+		assert.Equal(t, expected.result, tx.Result, path)
+		assert.Equal(t, expected.outcome, tx.Outcome, path)
+		assert.NotZero(t, tx.Timestamp)
+		assert.NotZero(t, tx.Duration)
+		assert.Len(t, tx.TraceID, 32)
+		assert.Len(t, tx.ID, 16)
+		if expected.status != 0 {
+			require.NotNil(t, tx.HTTP.Response, path)
+			assert.Equal(t, expected.status, tx.HTTP.Response.StatusCode, path)
+		} else {
+			assert.Nil(t, tx.HTTP.Response, path)
+		}
+	}
+
+	// Each monitor sends one request; don't close traceparents, as the
+	// server's handler may still be running.
+	for i := 0; i < 3; i++ {
+		var traceparent string
+		select {
+		case traceparent = <-traceparents:
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for synthetic requests")
+		}
+		traceContext, err := apmhttp.ParseTraceparentHeader(traceparent)
+		require.NoError(t, err)
+		assert.Equal(t, traceContext.Trace.String(), findTraceID(results, traceContext.Trace.String()))
+	}
+}
+
+func findTraceID(transactions map[string]*model.Transaction, traceID string) string {
+	for _, tx := range transactions {
+		if tx.TraceID == traceID {
+			return tx.TraceID
+		}
+	}
+	return ""
+}
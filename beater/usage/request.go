@@ -0,0 +1,72 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package usage
+
+import (
+	"context"
+	"math"
+	"sync"
+)
+
+type requestKey struct{}
+
+// Request records the services whose events were received in a request,
+// for attributing the request's size to them.
+type Request struct {
+	mu       sync.Mutex
+	tracker  *Tracker
+	services map[Service]int64
+}
+
+// ContextWithRequest returns a copy of ctx holding r.
+func ContextWithRequest(ctx context.Context, r *Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+func (r *Request) addServices(t *Tracker, services map[Service]int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tracker = t
+	if r.services == nil {
+		r.services = make(map[Service]int64)
+	}
+	for service, events := range services {
+		r.services[service] += events
+	}
+}
+
+// Finish records bytesIn as the request's size, attributing it to the
+// request's services in proportion to their number of events. Requests
+// with no events are not recorded.
+func (r *Request) Finish(bytesIn int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tracker == nil {
+		return
+	}
+	var total int64
+	for _, events := range r.services {
+		total += events
+	}
+	r.tracker.mu.Lock()
+	defer r.tracker.mu.Unlock()
+	for service, events := range r.services {
+		share := int64(math.Round(float64(bytesIn) * float64(events) / float64(total)))
+		r.tracker.usage(service).BytesIn += share
+	}
+}
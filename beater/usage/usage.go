@@ -0,0 +1,332 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package usage tracks intake usage per service, and periodically
+// publishes it as metricsets for chargeback and capacity reporting.
+package usage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+)
+
+const (
+	// MetricsetName holds the metricset.name of usage documents.
+	MetricsetName = "service_usage"
+
+	// DataStreamDataset holds the data stream dataset in which usage
+	// documents are stored, when data streams are enabled.
+	DataStreamDataset = "apm.usage"
+)
+
+// Event types for which usage is tracked.
+const (
+	eventTypeTransaction = "transaction"
+	eventTypeSpan        = "span"
+	eventTypeError       = "error"
+	eventTypeMetricset   = "metricset"
+	eventTypeProfile     = "profile"
+)
+
+var eventTypes = []string{
+	eventTypeTransaction,
+	eventTypeSpan,
+	eventTypeError,
+	eventTypeMetricset,
+	eventTypeProfile,
+}
+
+// Service identifies the service for which usage is tracked.
+type Service struct {
+	Name        string
+	Environment string
+}
+
+// Usage holds the usage of a service.
+type Usage struct {
+	// Events holds the number of events received, by event type.
+	Events map[string]int64
+
+	// BytesIn holds the number of request body bytes received,
+	// before decompression.
+	BytesIn int64
+
+	// BytesIndexedEstimate holds an estimate of the number of bytes
+	// indexed, based on the size of the JSON-encoded documents.
+	BytesIndexedEstimate int64
+}
+
+// Tracker tracks intake usage per service.
+type Tracker struct {
+	maxServices        int
+	defaultEnvironment string
+
+	mu       sync.Mutex
+	services map[Service]*Usage
+}
+
+// NewTracker returns a new Tracker which tracks up to maxServices services
+// at a time. Usage of additional services is attributed to no service.
+//
+// Events with no service environment are attributed to defaultEnvironment,
+// matching the environment with which they are indexed.
+func NewTracker(maxServices int, defaultEnvironment string) *Tracker {
+	return &Tracker{
+		maxServices:        maxServices,
+		defaultEnvironment: defaultEnvironment,
+		services:           make(map[Service]*Usage),
+	}
+}
+
+// usage returns the Usage for service. t.mu must be held.
+func (t *Tracker) usage(service Service) *Usage {
+	usage, ok := t.services[service]
+	if !ok {
+		if len(t.services) >= t.maxServices {
+			service = Service{}
+			usage = t.services[service]
+		}
+		if usage == nil {
+			usage = &Usage{Events: make(map[string]int64)}
+			t.services[service] = usage
+		}
+	}
+	return usage
+}
+
+// Snapshot returns the usage tracked since the previous call to Snapshot,
+// and resets the tracked usage.
+func (t *Tracker) Snapshot() map[Service]Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	snapshot := make(map[Service]Usage, len(t.services))
+	for service, usage := range t.services {
+		snapshot[service] = *usage
+	}
+	t.services = make(map[Service]*Usage)
+	return snapshot
+}
+
+// BatchProcessor returns a model.BatchProcessor which records the batch's
+// events, and then calls next. If the context holds a Request, the batch's
+// services are recorded for attributing the request's size.
+func (t *Tracker) BatchProcessor(next model.BatchProcessor) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		services := make(map[Service]int64)
+		t.mu.Lock()
+		add := func(metadata *model.Metadata, eventType string) {
+			service := Service{
+				Name:        metadata.Service.Name,
+				Environment: metadata.Service.Environment,
+			}
+			if service.Environment == "" {
+				service.Environment = t.defaultEnvironment
+			}
+			t.usage(service).Events[eventType]++
+			services[service]++
+		}
+		for _, event := range batch.Transactions {
+			add(&event.Metadata, eventTypeTransaction)
+		}
+		for _, event := range batch.Spans {
+			add(&event.Metadata, eventTypeSpan)
+		}
+		for _, event := range batch.Errors {
+			add(&event.Metadata, eventTypeError)
+		}
+		for _, event := range batch.Metricsets {
+			add(&event.Metadata, eventTypeMetricset)
+		}
+		for _, event := range batch.Profiles {
+			add(&event.Metadata, eventTypeProfile)
+		}
+		t.mu.Unlock()
+		if r, ok := ctx.Value(requestKey{}).(*Request); ok {
+			r.addServices(t, services)
+		}
+		return next.ProcessBatch(ctx, batch)
+	})
+}
+
+// Processor returns a beat.Processor which estimates the number of bytes
+// indexed for each service from the size of the events' fields, as they
+// would be encoded in JSON. Usage documents are not included.
+func (t *Tracker) Processor() beat.Processor {
+	return indexedBytesProcessor{t}
+}
+
+type indexedBytesProcessor struct {
+	t *Tracker
+}
+
+func (p indexedBytesProcessor) Run(event *beat.Event) (*beat.Event, error) {
+	if name, _ := event.Fields.GetValue("metricset.name"); name == MetricsetName {
+		return event, nil
+	}
+	var service Service
+	service.Name, _ = getString(event.Fields, "service.name")
+	service.Environment, _ = getString(event.Fields, "service.environment")
+	size := estimateSize(event.Fields)
+
+	p.t.mu.Lock()
+	defer p.t.mu.Unlock()
+	p.t.usage(service).BytesIndexedEstimate += size
+	return event, nil
+}
+
+func (indexedBytesProcessor) String() string {
+	return "usage"
+}
+
+// estimateSize estimates the size of the JSON encoding of v, without
+// encoding it. Strings are assumed not to require escaping, and numbers
+// and other values of unknown size are assumed to take 8 bytes.
+func estimateSize(v interface{}) int64 {
+	switch v := v.(type) {
+	case common.MapStr:
+		return estimateMapSize(v)
+	case map[string]interface{}:
+		return estimateMapSize(v)
+	case []interface{}:
+		size := delimitersSize(len(v))
+		for _, v := range v {
+			size += estimateSize(v)
+		}
+		return size
+	case []string:
+		size := delimitersSize(len(v))
+		for _, v := range v {
+			size += int64(len(v) + 2)
+		}
+		return size
+	case string:
+		return int64(len(v) + 2) // quotes
+	case bool:
+		return 5
+	case nil:
+		return 4
+	}
+	return 8
+}
+
+func estimateMapSize(m map[string]interface{}) int64 {
+	size := delimitersSize(len(m))
+	for k, v := range m {
+		size += int64(len(k)+3) + estimateSize(v) // quotes and colon
+	}
+	return size
+}
+
+// delimitersSize returns the size of the brackets and commas
+// of an array or object with n elements.
+func delimitersSize(n int) int64 {
+	if n == 0 {
+		return 2
+	}
+	return int64(n + 1)
+}
+
+func getString(fields common.MapStr, key string) (string, bool) {
+	v, err := fields.GetValue(key)
+	if err != nil {
+		return "", false
+	}
+	s, ok := v.(string)
+	return s, ok
+}
+
+// Run publishes usage documents to processor every interval, until ctx is
+// cancelled. Usage tracked since the final publication is published before
+// Run returns.
+func (t *Tracker) Run(ctx context.Context, processor model.BatchProcessor, interval time.Duration) error {
+	logger := logp.NewLogger(logs.Usage)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var done bool
+		select {
+		case <-ctx.Done():
+			done = true
+		case <-ticker.C:
+		}
+		batch := model.Batch{Metricsets: makeMetricsets(time.Now(), interval, t.Snapshot())}
+		if len(batch.Metricsets) > 0 {
+			// Publish with a background context, so the final usage
+			// documents are published during shutdown.
+			if err := processor.ProcessBatch(context.Background(), &batch); err != nil {
+				logger.With(logp.Error(err)).Warn("failed to publish usage documents")
+			}
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// makeMetricsets returns usage documents for the given usage, sorted by service.
+func makeMetricsets(timestamp time.Time, interval time.Duration, usage map[Service]Usage) []*model.Metricset {
+	services := make([]Service, 0, len(usage))
+	for service := range usage {
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		si, sj := services[i], services[j]
+		if si.Name != sj.Name {
+			return si.Name < sj.Name
+		}
+		return si.Environment < sj.Environment
+	})
+
+	metricsets := make([]*model.Metricset, len(services))
+	for i, service := range services {
+		u := usage[service]
+		samples := make([]model.Sample, 0, len(eventTypes)+3)
+		for _, eventType := range eventTypes {
+			samples = append(samples, model.Sample{
+				Name:  "usage.events." + eventType,
+				Value: float64(u.Events[eventType]),
+			})
+		}
+		samples = append(samples,
+			model.Sample{Name: "usage.bytes_in", Value: float64(u.BytesIn)},
+			model.Sample{Name: "usage.bytes_indexed_estimate", Value: float64(u.BytesIndexedEstimate)},
+			model.Sample{Name: "metricset.period", Value: float64(interval.Milliseconds())},
+		)
+		metricsets[i] = &model.Metricset{
+			Timestamp: timestamp,
+			Metadata: model.Metadata{
+				Service: model.Service{
+					Name:        service.Name,
+					Environment: service.Environment,
+				},
+			},
+			Name:              MetricsetName,
+			DataStreamDataset: DataStreamDataset,
+			Samples:           samples,
+		}
+	}
+	return metricsets
+}
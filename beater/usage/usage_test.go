@@ -0,0 +1,158 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package usage_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/middleware"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/beater/usage"
+	"github.com/elastic/apm-server/model"
+)
+
+func TestTrackerBatchProcessor(t *testing.T) {
+	tracker := usage.NewTracker(2, "default")
+	processor := tracker.BatchProcessor(model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		return nil
+	}))
+
+	metadata := func(name, environment string) model.Metadata {
+		return model.Metadata{Service: model.Service{Name: name, Environment: environment}}
+	}
+	err := processor.ProcessBatch(context.Background(), &model.Batch{
+		Transactions: []*model.Transaction{{Metadata: metadata("a", "production")}},
+		Spans:        []*model.Span{{Metadata: metadata("a", "production")}, {Metadata: metadata("a", "production")}},
+		Errors:       []*model.Error{{Metadata: metadata("b", "")}},
+		// Service "c" exceeds the maximum number of services.
+		Metricsets: []*model.Metricset{{Metadata: metadata("c", "production")}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[usage.Service]usage.Usage{
+		{Name: "a", Environment: "production"}: {Events: map[string]int64{"transaction": 1, "span": 2}},
+		{Name: "b", Environment: "default"}:    {Events: map[string]int64{"error": 1}},
+		{}:                                     {Events: map[string]int64{"metricset": 1}},
+	}, tracker.Snapshot())
+	assert.Empty(t, tracker.Snapshot())
+}
+
+func TestTrackerProcessor(t *testing.T) {
+	tracker := usage.NewTracker(10, "")
+	processor := tracker.Processor()
+
+	event := &beat.Event{Fields: common.MapStr{
+		"service": common.MapStr{"name": "a", "environment": "production"},
+	}}
+	out, err := processor.Run(event)
+	require.NoError(t, err)
+	assert.Equal(t, event, out)
+
+	// Usage documents are not recorded.
+	_, err = processor.Run(&beat.Event{Fields: common.MapStr{
+		"service":   common.MapStr{"name": "a", "environment": "production"},
+		"metricset": common.MapStr{"name": usage.MetricsetName},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[usage.Service]usage.Usage{
+		{Name: "a", Environment: "production"}: {
+			Events:               map[string]int64{},
+			BytesIndexedEstimate: int64(len(event.Fields.String())), // exact for unescaped strings
+		},
+	}, tracker.Snapshot())
+}
+
+func TestMiddleware(t *testing.T) {
+	tracker := usage.NewTracker(10, "")
+	processor := tracker.BatchProcessor(model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		return nil
+	}))
+
+	h, err := middleware.Wrap(func(c *request.Context) {
+		_, err := ioutil.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		processor.ProcessBatch(c.Request.Context(), &model.Batch{
+			Transactions: []*model.Transaction{
+				{Metadata: model.Metadata{Service: model.Service{Name: "a"}}},
+				{Metadata: model.Metadata{Service: model.Service{Name: "a"}}},
+				{Metadata: model.Metadata{Service: model.Service{Name: "a"}}},
+			},
+			Errors: []*model.Error{
+				{Metadata: model.Metadata{Service: model.Service{Name: "b"}}},
+			},
+		})
+	}, usage.Middleware())
+	require.NoError(t, err)
+
+	c := request.NewContext()
+	c.Reset(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", 100))))
+	h(c)
+
+	snapshot := tracker.Snapshot()
+	assert.Equal(t, int64(75), snapshot[usage.Service{Name: "a"}].BytesIn)
+	assert.Equal(t, int64(25), snapshot[usage.Service{Name: "b"}].BytesIn)
+}
+
+func TestTrackerRun(t *testing.T) {
+	tracker := usage.NewTracker(10, "")
+	processor := tracker.BatchProcessor(model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		return nil
+	}))
+	require.NoError(t, processor.ProcessBatch(context.Background(), &model.Batch{
+		Transactions: []*model.Transaction{{Metadata: model.Metadata{Service: model.Service{Name: "a"}}}},
+	}))
+
+	published := make(chan *model.Batch, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := tracker.Run(ctx, model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		published <- batch
+		return nil
+	}), time.Minute)
+	require.NoError(t, err)
+
+	batch := <-published
+	require.Len(t, batch.Metricsets, 1)
+	ms := batch.Metricsets[0]
+	assert.Equal(t, "a", ms.Metadata.Service.Name)
+	assert.Equal(t, usage.MetricsetName, ms.Name)
+	assert.Equal(t, usage.DataStreamDataset, ms.DataStreamDataset)
+	assert.Equal(t, []model.Sample{
+		{Name: "usage.events.transaction", Value: 1},
+		{Name: "usage.events.span", Value: 0},
+		{Name: "usage.events.error", Value: 0},
+		{Name: "usage.events.metricset", Value: 0},
+		{Name: "usage.events.profile", Value: 0},
+		{Name: "usage.bytes_in", Value: 0},
+		{Name: "usage.bytes_indexed_estimate", Value: 0},
+		{Name: "metricset.period", Value: 60000},
+	}, ms.Samples)
+}
@@ -0,0 +1,158 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package zipkin provides an HTTP handler for receiving spans
+// encoded with the Zipkin v2 JSON model.
+package zipkin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+
+	zipkinmodel "github.com/openzipkin/zipkin-go/model"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	zipkintranslator "go.opentelemetry.io/collector/translator/trace/zipkin"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/authorization"
+	"github.com/elastic/apm-server/beater/headers"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/decoder"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+	"github.com/elastic/apm-server/processor/stream"
+)
+
+const jsonMediaType = "application/json"
+
+var (
+	registry       = monitoring.Default.NewRegistry("apm-server.zipkin.http")
+	monitoringKeys = append(request.DefaultResultIDs, request.IDEventReceivedCount)
+
+	// MonitoringMap holds a mapping for request.IDs to monitoring
+	// counters for Zipkin v2 spans requests.
+	MonitoringMap = request.MonitoringMapForRegistry(registry, monitoringKeys)
+)
+
+// HTTPSpansHandler returns a request.Handler which accepts JSON arrays of
+// Zipkin v2 spans, as sent to a Zipkin collector's /api/v2/spans endpoint,
+// translates them into transactions and spans, and passes them to processor.
+// Spans are renamed, down-sampled or dropped by instrumentation scope as
+// described by instrumentationScopes.
+//
+// Uncompressed request bodies larger than maxRequestSize bytes are rejected.
+// If limiter is non-nil, a decoder is acquired from it while decoding each
+// request body, after the body has been read in full.
+func HTTPSpansHandler(
+	processor model.BatchProcessor,
+	instrumentationScopes []otel.InstrumentationScopeRule,
+	maxRequestSize int,
+	limiter *stream.DecodeLimiter,
+) request.Handler {
+	consumer := &otel.Consumer{Processor: processor, InstrumentationScopes: instrumentationScopes}
+	return func(c *request.Context) {
+		if c.Request.Method != http.MethodPost {
+			c.Result.SetWithError(
+				request.IDResponseErrorsMethodNotAllowed,
+				errors.New("only POST requests are allowed"),
+			)
+			c.Write()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.Request.Header.Get(headers.ContentType))
+		if err != nil || mediaType != jsonMediaType {
+			c.Result.SetWithError(
+				request.IDResponseErrorsValidate,
+				fmt.Errorf("invalid content type %q, expected %q", c.Request.Header.Get(headers.ContentType), jsonMediaType),
+			)
+			c.Write()
+			return
+		}
+
+		if c.RateLimiter != nil && !c.RateLimiter.Allow() {
+			c.Result.SetWithError(request.IDResponseErrorsRateLimit, errors.New("rate limit exceeded"))
+			c.Write()
+			return
+		}
+
+		reader, err := decoder.CompressedRequestReader(c.Request)
+		if err != nil {
+			c.Result.SetWithError(request.IDResponseErrorsValidate, err)
+			c.Write()
+			return
+		}
+		defer reader.Close()
+
+		// Read the body before acquiring a decoder, so decoders are
+		// not held while waiting for clients to send more data.
+		r := &decoder.LimitedReader{R: reader, N: int64(maxRequestSize)}
+		body, err := ioutil.ReadAll(r)
+		if err != nil {
+			if r.N < 0 {
+				c.Result.SetWithError(request.IDResponseErrorsRequestTooLarge, err)
+			} else {
+				c.Result.SetWithError(request.IDResponseErrorsDecode, err)
+			}
+			c.Write()
+			return
+		}
+		if !limiter.Acquire(c.Request.Context()) {
+			c.Result.SetWithError(
+				request.IDResponseErrorsFullQueue,
+				errors.New("too many concurrent decoders"),
+			)
+			c.Write()
+			return
+		}
+		traces, err := decodeSpans(body)
+		limiter.Release()
+		if err != nil {
+			c.Result.SetWithError(request.IDResponseErrorsDecode, err)
+			c.Write()
+			return
+		}
+		if err := consumer.ConsumeTraces(c.Request.Context(), traces); err != nil {
+			id := request.IDResponseErrorsInternal
+			var serviceErr *authorization.ServiceUnauthorizedError
+			if errors.As(err, &serviceErr) {
+				id = request.IDResponseErrorsForbidden
+			}
+			c.Result.SetWithError(id, err)
+			c.Write()
+			return
+		}
+		c.Result.SetDefault(request.IDResponseValidAccepted)
+		c.Write()
+	}
+}
+
+// decodeSpans decodes a JSON array of Zipkin v2 spans, translating
+// them to OpenTelemetry traces.
+func decodeSpans(body []byte) (pdata.Traces, error) {
+	var spans []*zipkinmodel.SpanModel
+	if err := json.Unmarshal(body, &spans); err != nil {
+		return pdata.Traces{}, err
+	}
+	MonitoringMap[request.IDEventReceivedCount].Add(int64(len(spans)))
+	return zipkintranslator.V2SpansToInternalTraces(spans, false)
+}
@@ -0,0 +1,222 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package zipkin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/beater/beatertest"
+	"github.com/elastic/apm-server/beater/request"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+	"github.com/elastic/apm-server/processor/stream"
+)
+
+const testMaxRequestSize = 1024 * 1024
+
+const testSpans = `[{
+  "traceId": "5af7183fb1d4cf5f",
+  "id": "6b221d5bc9e6496c",
+  "name": "get /api",
+  "kind": "SERVER",
+  "timestamp": 1472470996199000,
+  "duration": 207000,
+  "localEndpoint": {"serviceName": "frontend", "ipv4": "127.0.0.1"},
+  "tags": {"http.method": "GET", "http.path": "/api"}
+}, {
+  "traceId": "5af7183fb1d4cf5f",
+  "parentId": "6b221d5bc9e6496c",
+  "id": "352bff9a74ca9ad2",
+  "name": "get",
+  "kind": "CLIENT",
+  "timestamp": 1472470996238000,
+  "duration": 91000,
+  "localEndpoint": {"serviceName": "frontend", "ipv4": "127.0.0.1"},
+  "remoteEndpoint": {"serviceName": "backend", "ipv4": "192.168.99.101", "port": 9000}
+}]`
+
+func TestHTTPSpansHandler(t *testing.T) {
+	beatertest.ClearRegistry(MonitoringMap)
+
+	var batches []*model.Batch
+	processor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	c, recorder := newRequestContext(http.MethodPost, strings.NewReader(testSpans))
+	HTTPSpansHandler(processor, nil, testMaxRequestSize, nil)(c)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Equal(t, int64(2), MonitoringMap[request.IDEventReceivedCount].Get())
+
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Transactions, 1)
+	require.Len(t, batches[0].Spans, 1)
+	tx, span := batches[0].Transactions[0], batches[0].Spans[0]
+	assert.Equal(t, "get /api", tx.Name)
+	assert.Equal(t, "frontend", tx.Metadata.Service.Name)
+	assert.Equal(t, "00000000000000005af7183fb1d4cf5f", tx.TraceID)
+	assert.Equal(t, "6b221d5bc9e6496c", tx.ID)
+	assert.Equal(t, tx.TraceID, span.TraceID)
+	assert.Equal(t, tx.ID, span.ParentID)
+	assert.Equal(t, "352bff9a74ca9ad2", span.ID)
+}
+
+func TestHTTPSpansHandler_InstrumentationScopes(t *testing.T) {
+	spans := strings.Replace(testSpans, `"tags": {`, `"tags": {"otel.library.name": "io.opentelemetry.noisy", `, 1)
+	var batches []*model.Batch
+	processor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	c, recorder := newRequestContext(http.MethodPost, strings.NewReader(spans))
+	HTTPSpansHandler(processor, []otel.InstrumentationScopeRule{{
+		Name: "io.opentelemetry.*", SampleRate: 0,
+	}}, testMaxRequestSize, nil)(c)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+
+	// The span of the matching instrumentation scope is dropped.
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0].Transactions, 0)
+	assert.Len(t, batches[0].Spans, 1)
+}
+
+func TestHTTPSpansHandler_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(testSpans))
+	zw.Close()
+
+	var events int
+	processor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		events += batch.Len()
+		return nil
+	})
+	c, recorder := newRequestContext(http.MethodPost, &buf)
+	c.Request.Header.Set("Content-Encoding", "gzip")
+	HTTPSpansHandler(processor, nil, testMaxRequestSize, nil)(c)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Equal(t, 2, events)
+}
+
+func TestHTTPSpansHandler_Errors(t *testing.T) {
+	for name, test := range map[string]struct {
+		method       string
+		contentType  string
+		body         string
+		processorErr error
+		expectedCode int
+		expectedBody string
+	}{
+		"method not allowed": {
+			method:       http.MethodGet,
+			expectedCode: http.StatusMethodNotAllowed,
+			expectedBody: `{"code":"ERR_METHOD_NOT_ALLOWED","error":"method not supported: only POST requests are allowed"}`,
+		},
+		"invalid content type": {
+			method:       http.MethodPost,
+			contentType:  "application/x-thrift",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: `{"code":"ERR_VALIDATE","error":"data validation error: invalid content type \"application/x-thrift\", expected \"application/json\""}`,
+		},
+		"invalid json": {
+			method:       http.MethodPost,
+			body:         `{"traceId"`,
+			expectedCode: http.StatusBadRequest,
+		},
+		"invalid span": {
+			method:       http.MethodPost,
+			body:         `[{"traceId": "5af7183fb1d4cf5f", "id": "zzz"}]`,
+			expectedCode: http.StatusBadRequest,
+		},
+		"processor fails": {
+			method:       http.MethodPost,
+			body:         testSpans,
+			processorErr: errors.New("oh noes"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			processor := model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+				return test.processorErr
+			})
+			c, recorder := newRequestContext(test.method, strings.NewReader(test.body))
+			if test.contentType != "" {
+				c.Request.Header.Set("Content-Type", test.contentType)
+			}
+			HTTPSpansHandler(processor, nil, testMaxRequestSize, nil)(c)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedBody != "" {
+				assert.Equal(t, test.expectedBody+"\n", recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestHTTPSpansHandler_MaxRequestSize(t *testing.T) {
+	var events int
+	processor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		events += len(batch.Transactions) + len(batch.Spans)
+		return nil
+	})
+	c, recorder := newRequestContext(http.MethodPost, strings.NewReader(testSpans))
+	HTTPSpansHandler(processor, nil, len(testSpans)-1, nil)(c)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.Zero(t, events)
+
+	// Bodies of exactly the maximum size are accepted.
+	c, recorder = newRequestContext(http.MethodPost, strings.NewReader(testSpans))
+	HTTPSpansHandler(processor, nil, len(testSpans), nil)(c)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Equal(t, 2, events)
+}
+
+func TestHTTPSpansHandler_DecodeLimiter(t *testing.T) {
+	processor := model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return nil })
+	limiter := stream.NewDecodeLimiter(1, 10*time.Millisecond)
+	require.True(t, limiter.Acquire(context.Background()))
+	c, recorder := newRequestContext(http.MethodPost, strings.NewReader(testSpans))
+	HTTPSpansHandler(processor, nil, testMaxRequestSize, limiter)(c)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "too many concurrent decoders")
+
+	limiter.Release()
+	c, recorder = newRequestContext(http.MethodPost, strings.NewReader(testSpans))
+	HTTPSpansHandler(processor, nil, testMaxRequestSize, limiter)(c)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+}
+
+func newRequestContext(method string, body io.Reader) (*request.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	c := request.NewContext()
+	req := httptest.NewRequest(method, "/api/v2/spans", body)
+	req.Header.Set("Content-Type", "application/json")
+	c.Reset(rec, req)
+	return c, rec
+}
@@ -0,0 +1,108 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+
+	"github.com/elastic/apm-server/agentcfg"
+	"github.com/elastic/apm-server/beater/config"
+)
+
+func genAgentConfigCmd(settings instance.Settings) *cobra.Command {
+	short := "Manage sensitive agent configuration settings"
+	agentConfigCmd := &cobra.Command{
+		Use:   "agent-config",
+		Short: short,
+	}
+	agentConfigCmd.AddCommand(encryptAgentConfigCmd(settings))
+	return agentConfigCmd
+}
+
+func encryptAgentConfigCmd(settings instance.Settings) *cobra.Command {
+	short := "Encrypt a sensitive agent configuration value read from stdin"
+	return &cobra.Command{
+		Use:   "encrypt",
+		Short: short,
+		Long: short + `.
+The value is encrypted with the first key in "apm-server.agent.config.encryption.keys",
+and printed in a form that can be stored as the setting's value in Kibana. APM Server
+decrypts the value only when serving agent configuration to authenticated agents.
+
+The value is read from stdin, so it does not appear in the shell history. A single
+trailing newline is removed.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			keys, err := agentConfigEncryptionKeys(settings)
+			if err == nil {
+				err = encryptAgentConfigValue(keys, os.Stdin, os.Stdout)
+			}
+			if err != nil {
+				printErr(err, false)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+// agentConfigEncryptionKeys returns the configured agent config encryption keys.
+func agentConfigEncryptionKeys(settings instance.Settings) ([]string, error) {
+	beat, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := beat.BeatConfig()
+	if err != nil {
+		return nil, err
+	}
+	beaterConfig, err := config.NewConfig(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	return beaterConfig.AgentConfig.Encryption.Keys, nil
+}
+
+// encryptAgentConfigValue reads a value from r, encrypts it with keys,
+// and writes the encrypted value to w.
+func encryptAgentConfigValue(keys []string, r io.Reader, w io.Writer) error {
+	if len(keys) == 0 {
+		return errors.New(`no encryption keys configured, set "apm-server.agent.config.encryption.keys"`)
+	}
+	c, err := agentcfg.NewCipher(keys)
+	if err != nil {
+		return err
+	}
+	value, err := ioutil.ReadAll(r)
+	if err != nil {
+		return errors.Wrap(err, "reading value")
+	}
+	encrypted, err := c.Encrypt(strings.TrimSuffix(strings.TrimSuffix(string(value), "\n"), "\r"))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, encrypted)
+	return err
+}
@@ -0,0 +1,46 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/agentcfg"
+)
+
+func TestEncryptAgentConfigValue(t *testing.T) {
+	keys := []string{"MTExMTExMTExMTExMTExMQ=="}
+	var out bytes.Buffer
+	require.NoError(t, encryptAgentConfigValue(keys, strings.NewReader("user:pass\n"), &out))
+
+	encrypted := strings.TrimSuffix(out.String(), "\n")
+	assert.True(t, strings.HasPrefix(encrypted, agentcfg.EncryptedValuePrefix))
+	c, err := agentcfg.NewCipher(keys)
+	require.NoError(t, err)
+	decrypted, err := c.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "user:pass", decrypted)
+
+	err = encryptAgentConfigValue(nil, strings.NewReader("user:pass"), &out)
+	assert.Error(t, err)
+}
@@ -67,25 +67,29 @@ Check the Elastic Security API documentation for details.`,
 
 func createApikeyCmd(settings instance.Settings) *cobra.Command {
 	var keyName, expiration string
+	var services []string
 	var ingest, sourcemap, agentConfig, json bool
 	short := "Create an API Key with the specified privilege(s)"
 	create := &cobra.Command{
 		Use:   "create",
 		Short: short,
 		Long: short + `.
-If no privilege(s) are specified, the API Key will be valid for all.`,
+If no privilege(s) are specified, the API Key will be valid for all.
+If no service(s) are specified, the API Key will be valid for events of any service.`,
 		Run: makeAPIKeyRun(settings, &json, func(client es.Client, config *config.Config, args []string) error {
 			privileges := booleansToPrivileges(ingest, sourcemap, agentConfig)
 			if len(privileges) == 0 {
 				// No privileges specified, grant all.
 				privileges = auth.ActionsAll()
 			}
-			return createAPIKey(client, keyName, expiration, privileges, json)
+			return createAPIKey(client, keyName, expiration, privileges, apiKeyResources(services), json)
 		}),
 	}
 	create.Flags().StringVar(&keyName, "name", "apm-key", "API Key name")
 	create.Flags().StringVar(&expiration, "expiration", "",
 		`expiration for the key, eg. "1d" (default never)`)
+	create.Flags().StringSliceVar(&services, "service", nil,
+		`restrict the key to events of the given service name, may be repeated or comma-separated, and may contain the wildcard "*"`)
 	create.Flags().BoolVar(&ingest, "ingest", false,
 		fmt.Sprintf("give the %v privilege to this key, required for ingesting events", auth.PrivilegeEventWrite))
 	create.Flags().BoolVar(&sourcemap, "sourcemap", false,
@@ -158,11 +162,12 @@ If neither of them are, an error will be returned.`,
 }
 
 func verifyApikeyCmd(settings instance.Settings) *cobra.Command {
-	var credentials string
+	var credentials, service string
 	var ingest, sourcemap, agentConfig, json bool
 	short := `Check if a "credentials" string has the given privilege(s)`
 	long := short + `.
-If no privilege(s) are specified, the credentials will be queried for all.`
+If no privilege(s) are specified, the credentials will be queried for all.
+If a service is specified, the credentials will be queried for events of that service.`
 	verify := &cobra.Command{
 		Use:   "verify",
 		Short: short,
@@ -173,10 +178,15 @@ If no privilege(s) are specified, the credentials will be queried for all.`
 				// can't use "*" for querying
 				privileges = auth.ActionsAll()
 			}
-			return verifyAPIKey(config, privileges, credentials, json)
+			resource := auth.ResourceInternal
+			if service != "" {
+				resource = auth.ResourceService(service)
+			}
+			return verifyAPIKey(config, privileges, resource, credentials, json)
 		}),
 	}
 	verify.Flags().StringVar(&credentials, "credentials", "", `credentials for which check privileges (required)`)
+	verify.Flags().StringVar(&service, "service", "", `service name for which check privileges`)
 	verify.Flags().BoolVar(&ingest, "ingest", false,
 		fmt.Sprintf("ask for the %v privilege, required for ingesting events", auth.PrivilegeEventWrite))
 	verify.Flags().BoolVar(&sourcemap, "sourcemap", false,
@@ -266,7 +276,36 @@ func booleansToPrivileges(ingest, sourcemap, agentConfig bool) []es.PrivilegeAct
 	return privileges
 }
 
-func createAPIKey(client es.Client, keyName, expiry string, privileges []es.PrivilegeAction, asJSON bool) error {
+// apiKeyServiceName returns the name of the service to which an API Key
+// with the application privilege resources is restricted, if it is
+// restricted to a single service without wildcards.
+func apiKeyServiceName(resources []es.Resource) string {
+	var services []string
+	for _, resource := range resources {
+		if strings.HasPrefix(string(resource), auth.ServiceResourcePrefix) {
+			services = append(services, strings.TrimPrefix(string(resource), auth.ServiceResourcePrefix))
+		}
+	}
+	if len(services) != 1 || strings.Contains(services[0], "*") {
+		return ""
+	}
+	return services[0]
+}
+
+// apiKeyResources returns the application privilege resources for an API Key
+// restricted to events of services, or for all services if services is empty.
+func apiKeyResources(services []string) []es.Resource {
+	if len(services) == 0 {
+		return []es.Resource{auth.ResourceAny}
+	}
+	resources := []es.Resource{auth.ResourceInternal}
+	for _, service := range services {
+		resources = append(resources, auth.ResourceService(service))
+	}
+	return resources
+}
+
+func createAPIKey(client es.Client, keyName, expiry string, privileges []es.PrivilegeAction, resources []es.Resource, asJSON bool) error {
 
 	// Elasticsearch will allow a user without the right apm privileges to create API keys, but the keys won't validate
 	// check first whether the user has the right privileges, and bail out early if not
@@ -315,13 +354,16 @@ PUT /_security/role/my_role {
 					{
 						Name:       auth.Application,
 						Privileges: privileges,
-						Resources:  []es.Resource{auth.ResourceAny},
+						Resources:  resources,
 					},
 				},
 			},
 		},
 		Metadata: map[string]interface{}{"application": "apm"},
 	}
+	if service := apiKeyServiceName(resources); service != "" {
+		apikeyRequest.Metadata[auth.APIKeyServiceNameMetadata] = service
+	}
 	if expiry != "" {
 		apikeyRequest.Expiration = &expiry
 	}
@@ -413,7 +455,7 @@ func invalidateAPIKey(client es.Client, id string, name string, asJSON bool) err
 	return nil
 }
 
-func verifyAPIKey(config *config.Config, privileges []es.PrivilegeAction, credentials string, asJSON bool) error {
+func verifyAPIKey(config *config.Config, privileges []es.PrivilegeAction, resource es.Resource, credentials string, asJSON bool) error {
 	perms := make(es.Permissions)
 	printText, printJSON := printers(asJSON)
 	for _, privilege := range privileges {
@@ -424,7 +466,7 @@ func verifyAPIKey(config *config.Config, privileges []es.PrivilegeAction, creden
 		result, err := builder.
 			ForPrivilege(privilege).
 			AuthorizationFor(headers.APIKey, credentials).
-			AuthorizedFor(context.Background(), auth.ResourceInternal)
+			AuthorizedFor(context.Background(), resource)
 		if err != nil {
 			return err
 		}
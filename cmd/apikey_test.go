@@ -0,0 +1,41 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	es "github.com/elastic/apm-server/elasticsearch"
+)
+
+func TestAPIKeyResources(t *testing.T) {
+	assert.Equal(t, []es.Resource{"*"}, apiKeyResources(nil))
+	assert.Equal(t,
+		[]es.Resource{"-", "service:opbeans", "service:team-a-*"},
+		apiKeyResources([]string{"opbeans", "team-a-*"}),
+	)
+}
+
+func TestAPIKeyServiceName(t *testing.T) {
+	assert.Equal(t, "opbeans", apiKeyServiceName(apiKeyResources([]string{"opbeans"})))
+	assert.Equal(t, "", apiKeyServiceName(apiKeyResources(nil)))
+	assert.Equal(t, "", apiKeyServiceName(apiKeyResources([]string{"opbeans", "opbeans-go"})))
+	assert.Equal(t, "", apiKeyServiceName(apiKeyResources([]string{"team-a-*"})))
+}
@@ -0,0 +1,300 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/api"
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/headers"
+)
+
+const defaultImportBatchSize = 1000
+
+func genImportCmd(settings instance.Settings) *cobra.Command {
+	var from, speed, serverURL, apiKey string
+	var batchSize int
+	short := "Import historical events from an intake payload file"
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: short,
+		Long: short + `.
+Events are read from an ndjson file in the events intake format, and sent to the
+events intake API of a running APM Server. Events keep their original timestamps,
+so they are indexed into indices matching their dates.
+
+By default events are sent as fast as possible. With --speed, events are replayed
+with the time between them reduced by the given factor, e.g. "10x".`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if from == "" {
+				printErr(errors.New("--from is required"), false)
+				os.Exit(1)
+			}
+			factor, err := parseImportSpeed(speed)
+			if err != nil {
+				printErr(err, false)
+				os.Exit(1)
+			}
+			imp, err := newImporter(settings, serverURL, apiKey)
+			if err != nil {
+				printErr(err, false)
+				os.Exit(1)
+			}
+			imp.speed = factor
+			imp.batchSize = batchSize
+
+			f, err := os.Open(from)
+			if err != nil {
+				printErr(err, false)
+				os.Exit(1)
+			}
+			defer f.Close()
+			stats, err := imp.run(f)
+			fmt.Fprintf(os.Stdout, "Imported %d events in %d requests\n", stats.events, stats.requests)
+			if err != nil {
+				printErr(err, false)
+				os.Exit(1)
+			}
+		},
+	}
+	importCmd.Flags().StringVar(&from, "from", "", "path to the ndjson file to import")
+	importCmd.Flags().StringVar(&speed, "speed", "max", `replay speed relative to the original event timestamps, e.g. "10x", or "max"`)
+	importCmd.Flags().StringVar(&serverURL, "url", "", "APM Server URL; defaults to the configured apm-server.host")
+	importCmd.Flags().StringVar(&apiKey, "api-key", "", "API Key for sending events; defaults to using the configured apm-server.secret_token")
+	importCmd.Flags().IntVar(&batchSize, "batch-size", defaultImportBatchSize, "maximum number of events to send per request")
+	return importCmd
+}
+
+// parseImportSpeed parses a replay speed such as "10x" or "0.5",
+// returning zero for "max".
+func parseImportSpeed(s string) (float64, error) {
+	if s == "max" {
+		return 0, nil
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(s, "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, errors.Errorf(`invalid speed %q, expected a positive factor such as "10x", or "max"`, s)
+	}
+	return speed, nil
+}
+
+func newImporter(settings instance.Settings, serverURL, apiKey string) (*importer, error) {
+	beat, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := beat.BeatConfig()
+	if err != nil {
+		return nil, err
+	}
+	var esOutputCfg *common.Config
+	if beat.Config.Output.Name() == "elasticsearch" {
+		esOutputCfg = beat.Config.Output.Config()
+	}
+	beaterConfig, err := config.NewConfig(cfg, esOutputCfg)
+	if err != nil {
+		return nil, err
+	}
+	return newIntakeImporter(beaterConfig, serverURL, apiKey)
+}
+
+// newIntakeImporter returns an importer sending events to serverURL, or the
+// server configured in beaterConfig if serverURL is empty, authenticating with
+// apiKey or the configured secret token.
+func newIntakeImporter(beaterConfig *config.Config, serverURL, apiKey string) (*importer, error) {
+	if serverURL == "" {
+		scheme := "http"
+		if beaterConfig.TLS.IsEnabled() {
+			scheme = "https"
+		}
+		serverURL = scheme + "://" + beaterConfig.Host
+	}
+	intakeURL, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid APM Server URL")
+	}
+	intakeURL.Path = strings.TrimSuffix(intakeURL.Path, "/") + api.IntakePath
+
+	header := make(http.Header)
+	header.Set(headers.ContentType, "application/x-ndjson")
+	if apiKey != "" {
+		header.Set(headers.Authorization, headers.APIKey+" "+apiKey)
+	} else if beaterConfig.SecretToken != "" {
+		header.Set(headers.Authorization, headers.Bearer+" "+beaterConfig.SecretToken)
+	}
+	return &importer{
+		client: http.DefaultClient,
+		url:    intakeURL.String(),
+		header: header,
+	}, nil
+}
+
+// importer sends events from an intake payload to the events intake API,
+// optionally replaying them at a pace relative to their original timestamps.
+type importer struct {
+	client    *http.Client
+	url       string
+	header    http.Header
+	batchSize int
+
+	// speed holds the replay speed factor. If speed is zero,
+	// events are sent as fast as possible.
+	speed float64
+
+	// now and sleep are used for pacing, and may be overridden in tests.
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+type importStats struct {
+	events   int
+	requests int
+}
+
+// run reads the intake payload from r, sending events in batches.
+//
+// Metadata lines apply to all following events, until the next metadata line.
+func (imp *importer) run(r io.Reader) (importStats, error) {
+	var stats importStats
+	now, sleep := imp.now, imp.sleep
+	if now == nil {
+		now = time.Now
+	}
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	batchSize := imp.batchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	var metadata []byte
+	var events [][]byte
+	flush := func() error {
+		if len(events) == 0 {
+			return nil
+		}
+		if err := imp.send(metadata, events); err != nil {
+			return err
+		}
+		stats.requests++
+		stats.events += len(events)
+		events = events[:0]
+		return nil
+	}
+
+	var start time.Time
+	var firstTimestamp int64
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 10*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var event map[string]struct {
+			Timestamp *int64 `json:"timestamp"`
+		}
+		if err := json.Unmarshal(line, &event); err != nil || len(event) != 1 {
+			return stats, errors.Errorf("invalid intake line: %s", line)
+		}
+		line = append([]byte(nil), line...)
+		if _, ok := event["metadata"]; ok {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+			metadata = line
+			continue
+		}
+		if metadata == nil {
+			return stats, errors.New("intake payload must begin with a metadata line")
+		}
+
+		var timestamp *int64
+		for _, v := range event {
+			timestamp = v.Timestamp
+		}
+		if imp.speed > 0 && timestamp != nil {
+			if start.IsZero() {
+				start, firstTimestamp = now(), *timestamp
+			}
+			offset := time.Duration(*timestamp-firstTimestamp) * time.Microsecond
+			if wait := start.Add(time.Duration(float64(offset) / imp.speed)).Sub(now()); wait > 0 {
+				if err := flush(); err != nil {
+					return stats, err
+				}
+				sleep(wait)
+			}
+		}
+		events = append(events, line)
+		if len(events) == batchSize {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return stats, err
+	}
+	return stats, flush()
+}
+
+func (imp *importer) send(metadata []byte, events [][]byte) error {
+	var body bytes.Buffer
+	body.Write(metadata)
+	body.WriteByte('\n')
+	for _, event := range events {
+		body.Write(event)
+		body.WriteByte('\n')
+	}
+	req, err := http.NewRequest(http.MethodPost, imp.url, &body)
+	if err != nil {
+		return err
+	}
+	for k, v := range imp.header {
+		req.Header[k] = v
+	}
+	resp, err := imp.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("failed to send events (%s): %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportSpeed(t *testing.T) {
+	for in, expected := range map[string]float64{"max": 0, "10x": 10, "0.5x": 0.5, "2": 2} {
+		speed, err := parseImportSpeed(in)
+		require.NoError(t, err)
+		assert.Equal(t, expected, speed, in)
+	}
+	for _, in := range []string{"", "x", "-1x", "0x", "fast"} {
+		_, err := parseImportSpeed(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestImporter(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/intake/v2/events", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	const payload = `{"metadata":{"service":{"name":"a"}}}
+{"transaction":{"id":"1","timestamp":1000000000}}
+{"span":{"id":"2","timestamp":1001000000}}
+{"error":{"id":"3"}}
+
+{"metadata":{"service":{"name":"b"}}}
+{"transaction":{"id":"4","timestamp":1021000000}}
+{"transaction":{"id":"5","timestamp":1021000000}}
+{"transaction":{"id":"6","timestamp":1021000000}}
+`
+	now := time.Unix(0, 0)
+	var sleeps []time.Duration
+	imp := &importer{
+		client:    srv.Client(),
+		url:       srv.URL + "/intake/v2/events",
+		header:    http.Header{"Content-Type": []string{"application/x-ndjson"}},
+		batchSize: 2,
+		speed:     10,
+		now:       func() time.Time { return now },
+		sleep: func(d time.Duration) {
+			sleeps = append(sleeps, d)
+			now = now.Add(d)
+		},
+	}
+	stats, err := imp.run(strings.NewReader(payload))
+	require.NoError(t, err)
+	assert.Equal(t, importStats{events: 6, requests: 4}, stats)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 2 * time.Second}, sleeps)
+	assert.Equal(t, []string{
+		"{\"metadata\":{\"service\":{\"name\":\"a\"}}}\n{\"transaction\":{\"id\":\"1\",\"timestamp\":1000000000}}\n",
+		"{\"metadata\":{\"service\":{\"name\":\"a\"}}}\n{\"span\":{\"id\":\"2\",\"timestamp\":1001000000}}\n{\"error\":{\"id\":\"3\"}}\n",
+		"{\"metadata\":{\"service\":{\"name\":\"b\"}}}\n{\"transaction\":{\"id\":\"4\",\"timestamp\":1021000000}}\n{\"transaction\":{\"id\":\"5\",\"timestamp\":1021000000}}\n",
+		"{\"metadata\":{\"service\":{\"name\":\"b\"}}}\n{\"transaction\":{\"id\":\"6\",\"timestamp\":1021000000}}\n",
+	}, bodies)
+}
+
+func TestImporterErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid"}`))
+	}))
+	defer srv.Close()
+	imp := &importer{client: srv.Client(), url: srv.URL}
+
+	_, err := imp.run(strings.NewReader(`{"transaction":{"id":"1"}}`))
+	assert.EqualError(t, err, "intake payload must begin with a metadata line")
+
+	_, err = imp.run(strings.NewReader(`not json`))
+	assert.EqualError(t, err, "invalid intake line: not json")
+
+	stats, err := imp.run(strings.NewReader("{\"metadata\":{}}\n{\"transaction\":{\"id\":\"1\"}}\n"))
+	assert.EqualError(t, err, `failed to send events (400 Bad Request): {"error":"invalid"}`)
+	assert.Equal(t, importStats{}, stats)
+}
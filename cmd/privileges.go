@@ -0,0 +1,144 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/preflight"
+	es "github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/idxmgmt"
+)
+
+// requiredPrivileges holds the Elasticsearch privileges
+// required for setting up and running APM Server.
+type requiredPrivileges struct {
+	Setup   preflight.Privileges             `json:"setup"`
+	Runtime []preflight.CredentialPrivileges `json:"runtime"`
+}
+
+func genPrivilegesCmd(settings instance.Settings) *cobra.Command {
+	var format string
+	short := "Print the Elasticsearch privileges required by the current configuration"
+	privilegesCmd := &cobra.Command{
+		Use:   "privileges",
+		Short: short,
+		Long: short + `.
+Setup privileges are required for loading index templates, ILM policies, and ingest pipelines,
+e.g. by running "apm-server setup". Runtime privileges are required by the credentials
+configured in "output.elasticsearch.*" for indexing events, by the credentials configured in
+"apm-server.rum.source_mapping.elasticsearch.*", which default to the output credentials, for
+reading source maps, by the credentials configured in "apm-server.kibana.*" for querying
+agent configuration, and by the credentials configured in "apm-server.agent.config.elasticsearch.*",
+which default to the Kibana credentials, for reading agent configuration from Elasticsearch.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			asJSON := format == "json"
+			if format != "json" && format != "text" {
+				printErr(fmt.Errorf("invalid format %q, expected one of: text, json", format), false)
+				os.Exit(1)
+			}
+			privileges, err := configuredPrivileges(settings)
+			if err != nil {
+				printErr(err, asJSON)
+				os.Exit(1)
+			}
+			if asJSON {
+				data, _ := json.MarshalIndent(privileges, "", "\t")
+				fmt.Fprintln(os.Stdout, string(data))
+				return
+			}
+			printPrivileges(os.Stdout, "Setup", privileges.Setup)
+			for _, runtime := range privileges.Runtime {
+				printPrivileges(os.Stdout, fmt.Sprintf("Runtime (%s)", runtime.Credentials), runtime.Privileges)
+			}
+		},
+	}
+	privilegesCmd.Flags().StringVar(&format, "format", "text", `output format, one of "text" or "json"`)
+	return privilegesCmd
+}
+
+func configuredPrivileges(settings instance.Settings) (requiredPrivileges, error) {
+	beat, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return requiredPrivileges{}, err
+	}
+	cfg, err := beat.BeatConfig()
+	if err != nil {
+		return requiredPrivileges{}, err
+	}
+	var esOutputCfg *common.Config
+	if beat.Config.Output.Name() == "elasticsearch" {
+		esOutputCfg = beat.Config.Output.Config()
+	}
+	beaterConfig, err := config.NewConfig(cfg, esOutputCfg)
+	if err != nil {
+		return requiredPrivileges{}, err
+	}
+	idxConfig, err := idxmgmt.NewIndexManagementConfig(beat.Info, beat.RawConfig)
+	if err != nil {
+		return requiredPrivileges{}, errors.Wrap(err, "failed to read index management config")
+	}
+	return requiredPrivileges{
+		Setup:   preflight.SetupPrivileges(beaterConfig, idxConfig),
+		Runtime: preflight.RuntimePrivileges(beaterConfig),
+	}, nil
+}
+
+func printPrivileges(w io.Writer, kind string, privileges preflight.Privileges) {
+	fmt.Fprintf(w, "%s privileges:\n", kind)
+	if len(privileges.Cluster) == 0 && len(privileges.Index) == 0 && len(privileges.Applications) == 0 {
+		fmt.Fprintln(w, "  none")
+		return
+	}
+	if len(privileges.Cluster) > 0 {
+		fmt.Fprintf(w, "  cluster: %s\n", joinPrivileges(privileges.Cluster))
+	}
+	for _, index := range privileges.Index {
+		fmt.Fprintf(w, "  index %s: %s", strings.Join(index.Names, ", "), joinPrivileges(index.Privileges))
+		if index.AllowRestrictedIndices {
+			fmt.Fprint(w, " (allow restricted indices)")
+		}
+		fmt.Fprintln(w)
+	}
+	for _, app := range privileges.Applications {
+		resources := make([]string, len(app.Resources))
+		for i, resource := range app.Resources {
+			resources[i] = string(resource)
+		}
+		fmt.Fprintf(w, "  application %s: %s on %s\n", app.Name, joinPrivileges(app.Privileges), strings.Join(resources, ", "))
+	}
+}
+
+func joinPrivileges(privileges []es.PrivilegeAction) string {
+	s := make([]string, len(privileges))
+	for i, privilege := range privileges {
+		s[i] = string(privilege)
+	}
+	return strings.Join(s, ", ")
+}
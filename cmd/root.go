@@ -19,7 +19,9 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
@@ -37,6 +39,10 @@ import (
 const (
 	beatName        = "apm-server"
 	apmIndexPattern = "apm"
+
+	// upgradeKey is used for upgrading index templates and ingest pipelines
+	// installed by older versions of APM Server in the setup command.
+	upgradeKey = "upgrade"
 )
 
 var libbeatConfigOverrides = []cfgfile.ConditionalOverride{{
@@ -52,8 +58,39 @@ var libbeatConfigOverrides = []cfgfile.ConditionalOverride{{
 			"json": true,
 		},
 	}),
+}, {
+	// Monitoring settings not specified in `monitoring.elasticsearch` are
+	// inherited from `output.elasticsearch`. When monitoring is configured
+	// with its own credentials, don't inherit the output's credentials, as
+	// the monitoring cluster may be a different cluster, and API Key and
+	// username/password auth cannot be combined.
+	Check: func(cfg *common.Config) bool {
+		return monitoringESConfigHasAny(cfg, "username", "password") && !monitoringESConfigHasAny(cfg, "api_key")
+	},
+	Config: common.MustNewConfigFrom(map[string]interface{}{
+		"monitoring.elasticsearch.api_key": "",
+	}),
+}, {
+	Check: func(cfg *common.Config) bool {
+		return monitoringESConfigHasAny(cfg, "api_key") && !monitoringESConfigHasAny(cfg, "username", "password")
+	},
+	Config: common.MustNewConfigFrom(map[string]interface{}{
+		"monitoring.elasticsearch.username": "",
+		"monitoring.elasticsearch.password": "",
+	}),
 }}
 
+// monitoringESConfigHasAny reports whether any of the given fields
+// are set in the `monitoring.elasticsearch` config.
+func monitoringESConfigHasAny(cfg *common.Config, fields ...string) bool {
+	for _, field := range fields {
+		if ok, _ := cfg.Has("monitoring.elasticsearch."+field, -1); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // DefaultSettings return the default settings for APM Server to pass into
 // the GenRootCmdWithSettings.
 func DefaultSettings() instance.Settings {
@@ -75,6 +112,10 @@ func DefaultSettings() instance.Settings {
 func NewRootCommand(newBeat beat.Creator, settings instance.Settings) *cmd.BeatsRootCmd {
 	rootCmd := cmd.GenRootCmdWithSettings(newBeat, settings)
 	rootCmd.AddCommand(genApikeyCmd(settings))
+	rootCmd.AddCommand(genPrivilegesCmd(settings))
+	rootCmd.AddCommand(genImportCmd(settings))
+	rootCmd.AddCommand(genAgentConfigCmd(settings))
+	rootCmd.AddCommand(genVerifyCmd(settings))
 	modifyBuiltinCommands(rootCmd, settings)
 	return rootCmd
 }
@@ -100,6 +141,15 @@ func modifyBuiltinCommands(rootCmd *cmd.BeatsRootCmd, settings instance.Settings
 
  * Index management including loading Elasticsearch templates, ILM policies and write aliases.
  * Ingest pipelines
+
+With --upgrade, index templates and ingest pipelines installed by older versions
+of APM Server are upgraded, and existing write aliases are rolled over, even if
+apm-server.template_upgrade.enabled is false.
+
+With --simulate-pipeline, no setup is performed. Instead, sample documents are
+read from the given ndjson file, one document per line, and run through the
+ingest pipeline registered in Elasticsearch using the simulate pipeline API.
+The transformed documents and any pipeline failures are reported.
 `
 	setup.ResetFlags()
 
@@ -109,4 +159,26 @@ func modifyBuiltinCommands(rootCmd *cmd.BeatsRootCmd, settings instance.Settings
 	setup.Flags().MarkDeprecated(tmplKey, fmt.Sprintf("please use --%s instead", cmd.IndexManagementKey))
 	setup.Flags().Bool(cmd.IndexManagementKey, false, "Setup Elasticsearch index management")
 	setup.Flags().Bool(cmd.PipelineKey, false, "Setup ingest pipelines")
+	setup.Flags().Bool(upgradeKey, false, "Upgrade index templates and ingest pipelines installed by older versions")
+	setup.Flags().String(simulatePipelineKey, "", "Simulate the ingest pipeline with sample documents from an ndjson file, instead of performing setup")
+
+	run := setup.Run
+	setup.Run = func(c *cobra.Command, args []string) {
+		if path, _ := c.Flags().GetString(simulatePipelineKey); path != "" {
+			if err := simulatePipeline(settings, path, os.Stdout); err != nil {
+				printErr(err, false)
+				os.Exit(1)
+			}
+			return
+		}
+		if upgrade, _ := c.Flags().GetBool(upgradeKey); upgrade {
+			// Enable upgrades through a configuration overwrite, as if
+			// "-E apm-server.template_upgrade.enabled=true" were specified.
+			if err := c.Flags().Set("E", "apm-server.template_upgrade.enabled=true"); err != nil {
+				fmt.Fprintf(os.Stderr, "Error enabling upgrade: %s\n", err)
+				os.Exit(1)
+			}
+		}
+		run(c, args)
+	}
 }
@@ -0,0 +1,86 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestMonitoringCredentialsNotInherited(t *testing.T) {
+	type credentials struct {
+		Username string `config:"username"`
+		Password string `config:"password"`
+		APIKey   string `config:"api_key"`
+	}
+
+	for name, test := range map[string]struct {
+		output     map[string]interface{}
+		monitoring map[string]interface{}
+		expected   credentials
+	}{
+		"monitoring username/password": {
+			output:     map[string]interface{}{"api_key": "output_api_key"},
+			monitoring: map[string]interface{}{"username": "monitor", "password": "secret"},
+			expected:   credentials{Username: "monitor", Password: "secret"},
+		},
+		"monitoring api_key": {
+			output:     map[string]interface{}{"username": "output", "password": "output_secret"},
+			monitoring: map[string]interface{}{"api_key": "monitoring_api_key"},
+			expected:   credentials{APIKey: "monitoring_api_key"},
+		},
+		"monitoring inherits credentials": {
+			output:     map[string]interface{}{"username": "output", "password": "output_secret"},
+			monitoring: map[string]interface{}{"hosts": []string{"monitoring:9200"}},
+			expected:   credentials{Username: "output", Password: "output_secret"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			userConfig := common.MustNewConfigFrom(map[string]interface{}{
+				"output.elasticsearch":     test.output,
+				"monitoring.elasticsearch": test.monitoring,
+			})
+
+			// Apply overrides as libbeat does when loading the config file.
+			merged := common.NewConfig()
+			for _, o := range libbeatConfigOverrides {
+				if o.Check(userConfig) {
+					require.NoError(t, merged.Merge(o.Config))
+				}
+			}
+			require.NoError(t, merged.Merge(userConfig))
+
+			// Merge the monitoring config with the output config, as
+			// the monitoring reporter does.
+			outputConfig, err := merged.Child("output.elasticsearch", -1)
+			require.NoError(t, err)
+			monitoringConfig, err := merged.Child("monitoring.elasticsearch", -1)
+			require.NoError(t, err)
+			reporterConfig, err := common.MergeConfigs(outputConfig, monitoringConfig)
+			require.NoError(t, err)
+
+			var actual credentials
+			require.NoError(t, reporterConfig.Unpack(&actual))
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/ingest/pipeline"
+)
+
+// simulatePipelineKey is used for simulating the registered ingest pipeline
+// with sample documents in the setup command.
+const simulatePipelineKey = "simulate-pipeline"
+
+// simulatePipeline runs the documents in the ndjson file at path through the
+// default ingest pipeline, or the ones configured for the output, as registered
+// in Elasticsearch, and writes the results to out. An error is returned if a
+// pipeline fails for any document.
+func simulatePipeline(settings instance.Settings, path string, out io.Writer) error {
+	beat, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return err
+	}
+	cfg, err := beat.BeatConfig()
+	if err != nil {
+		return err
+	}
+	if beat.Config.Output.Name() != "elasticsearch" {
+		return errors.New("simulating pipelines requires the Elasticsearch output")
+	}
+	esOutputCfg := beat.Config.Output.Config()
+	beaterConfig, err := config.NewConfig(cfg, esOutputCfg)
+	if err != nil {
+		return err
+	}
+	ids, err := simulatePipelineIDs(beaterConfig, esOutputCfg)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	docs, err := readSimulateDocs(f)
+	if err != nil {
+		return err
+	}
+
+	conn, err := eslegclient.NewConnectedClient(esOutputCfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var failed []string
+	for _, id := range ids {
+		results, err := pipeline.Simulate(conn, id, docs)
+		if err != nil {
+			return err
+		}
+		if err := reportSimulateResults(out, id, results); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// simulatePipelineIDs returns the ids of the ingest pipelines to simulate: the
+// default pipeline, or those configured for the output with `pipeline` and
+// `pipelines`. Pipelines named with format strings are resolved per event,
+// and cannot be simulated.
+func simulatePipelineIDs(beaterConfig *config.Config, esOutputCfg *common.Config) ([]string, error) {
+	if beaterConfig.Pipeline != "" {
+		// The default pipeline is not used when the output
+		// is configured with its own pipelines.
+		return []string{beaterConfig.Pipeline}, nil
+	}
+	var outputConfig struct {
+		Pipeline  string `config:"pipeline"`
+		Pipelines []struct {
+			Pipeline string `config:"pipeline"`
+		} `config:"pipelines"`
+	}
+	if err := esOutputCfg.Unpack(&outputConfig); err != nil {
+		return nil, err
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if id != "" && !strings.Contains(id, "%{") && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, rule := range outputConfig.Pipelines {
+		add(rule.Pipeline)
+	}
+	add(outputConfig.Pipeline)
+	if len(ids) == 0 {
+		return nil, errors.New("no ingest pipeline configured")
+	}
+	return ids, nil
+}
+
+// readSimulateDocs reads sample documents from r, one JSON object per line.
+// Empty lines are ignored.
+func readSimulateDocs(r io.Reader) ([]common.MapStr, error) {
+	var docs []common.MapStr
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 10*1024*1024)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var doc common.MapStr
+		if err := json.Unmarshal(line, &doc); err != nil || doc == nil {
+			return nil, errors.Errorf("invalid document on line %d: %s", lineno, line)
+		}
+		docs = append(docs, doc)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, errors.New("no documents to simulate")
+	}
+	return docs, nil
+}
+
+// reportSimulateResults writes the transformed documents and failures to out,
+// followed by a summary, returning an error if any document failed.
+func reportSimulateResults(out io.Writer, id string, results []pipeline.SimulateResult) error {
+	var failed int
+	for i, result := range results {
+		switch {
+		case result.Error != "":
+			failed++
+			fmt.Fprintf(out, "Document %d: failed: %s\n", i+1, result.Error)
+		case result.Dropped:
+			fmt.Fprintf(out, "Document %d: dropped\n", i+1)
+		default:
+			source, err := json.Marshal(result.Source)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Document %d: %s\n", i+1, source)
+		}
+	}
+	fmt.Fprintf(out, "Simulated %d documents with pipeline %s: %d failed\n", len(results), id, failed)
+	if failed > 0 {
+		return errors.Errorf("pipeline %s failed for %d of %d documents", id, failed, len(results))
+	}
+	return nil
+}
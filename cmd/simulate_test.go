@@ -0,0 +1,115 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/ingest/pipeline"
+)
+
+func TestSimulatePipelineIDs(t *testing.T) {
+	for name, test := range map[string]struct {
+		beaterConfig *config.Config
+		output       map[string]interface{}
+		ids          []string
+		err          string
+	}{
+		"default": {
+			beaterConfig: &config.Config{Pipeline: "apm"},
+			ids:          []string{"apm"},
+		},
+		"pipeline": {
+			beaterConfig: &config.Config{},
+			output:       map[string]interface{}{"pipeline": "custom"},
+			ids:          []string{"custom"},
+		},
+		"pipelines": {
+			beaterConfig: &config.Config{},
+			output: map[string]interface{}{
+				"pipeline": "fallback",
+				"pipelines": []map[string]interface{}{
+					{"pipeline": "errors", "when.equals.processor.event": "error"},
+					{"pipeline": "%{[fields.pipeline]}"},
+					{"pipeline": "fallback"},
+				},
+			},
+			ids: []string{"errors", "fallback"},
+		},
+		"none": {
+			beaterConfig: &config.Config{},
+			output:       map[string]interface{}{"pipelines": []map[string]interface{}{{"pipeline": "%{[fields.pipeline]}"}}},
+			err:          "no ingest pipeline configured",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ids, err := simulatePipelineIDs(test.beaterConfig, common.MustNewConfigFrom(test.output))
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.ids, ids)
+		})
+	}
+}
+
+func TestReadSimulateDocs(t *testing.T) {
+	docs, err := readSimulateDocs(strings.NewReader(`{"a":"b"}
+
+{"c":{"d":1}}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []common.MapStr{
+		{"a": "b"},
+		{"c": map[string]interface{}{"d": float64(1)}},
+	}, docs)
+
+	_, err = readSimulateDocs(strings.NewReader("{}\n[1]\n"))
+	assert.EqualError(t, err, "invalid document on line 2: [1]")
+
+	_, err = readSimulateDocs(strings.NewReader("\n"))
+	assert.EqualError(t, err, "no documents to simulate")
+}
+
+func TestReportSimulateResults(t *testing.T) {
+	var out strings.Builder
+	err := reportSimulateResults(&out, "apm", []pipeline.SimulateResult{
+		{Source: common.MapStr{"a": "b"}},
+		{Error: "illegal_argument_exception: field [x] not present"},
+		{Dropped: true},
+	})
+	assert.EqualError(t, err, "pipeline apm failed for 1 of 3 documents")
+	assert.Equal(t, `Document 1: {"a":"b"}
+Document 2: failed: illegal_argument_exception: field [x] not present
+Document 3: dropped
+Simulated 3 documents with pipeline apm: 1 failed
+`, out.String())
+
+	out.Reset()
+	err = reportSimulateResults(&out, "apm", []pipeline.SimulateResult{{Source: common.MapStr{}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Document 1: {}\nSimulated 1 documents with pipeline apm: 0 failed\n", out.String())
+}
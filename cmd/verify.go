@@ -0,0 +1,507 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/elasticsearch"
+)
+
+const (
+	// verifyIndex holds the indices searched for verification events,
+	// covering both data streams and legacy indices.
+	verifyIndex = "traces-apm*,logs-apm*,metrics-apm*,apm-*"
+
+	// verifyLabel holds the label set on all verification events,
+	// identifying the events of a single verification run.
+	verifyLabel = "verify_id"
+
+	verifyServiceName = "apm-server-verify"
+)
+
+// verifyChecks holds the fields expected in the document produced for each
+// verification event type. Fields set by ingest pipelines are included to
+// check that the pipelines ran.
+var verifyChecks = map[string][]string{
+	"transaction": {
+		"@timestamp", "service.name", "observer.version", "labels." + verifyLabel,
+		"trace.id", "transaction.id", "transaction.duration.us",
+		"user_agent.name", "event.ingested",
+	},
+	"span": {
+		"@timestamp", "service.name", "observer.version", "labels." + verifyLabel,
+		"trace.id", "parent.id", "span.id", "span.duration.us",
+		"event.ingested",
+	},
+	"error": {
+		"@timestamp", "service.name", "observer.version", "labels." + verifyLabel,
+		"trace.id", "error.id", "error.grouping_key",
+		"error.grouping_name", "event.ingested",
+	},
+	"metric": {
+		"@timestamp", "service.name", "observer.version", "labels." + verifyLabel,
+		"verify.count", "event.ingested",
+	},
+}
+
+// verifyMappings holds the expected mapping type of fields
+// in the indices the verification documents are stored in.
+var verifyMappings = map[string]string{
+	"@timestamp":              "date",
+	"event.ingested":          "date",
+	"service.name":            "keyword",
+	"trace.id":                "keyword",
+	"transaction.id":          "keyword",
+	"transaction.duration.us": "long",
+	"span.duration.us":        "long",
+	"error.grouping_key":      "keyword",
+	"labels." + verifyLabel:   "keyword",
+}
+
+func genVerifyCmd(settings instance.Settings) *cobra.Command {
+	var opts verifyOptions
+	short := "Verify that events are ingested end-to-end into Elasticsearch"
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: short,
+		Long: short + `.
+A transaction, span, error, and metricset are sent to the events intake API of a
+running APM Server. Elasticsearch is then searched until the resulting documents
+are searchable, and the documents are checked for the fields expected to be set
+by APM Server and the ingest pipelines, and for the expected field mappings.
+
+The events are sent for the service "` + verifyServiceName + `", and are labeled with
+"labels.` + verifyLabel + `" to identify them.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			v, err := newVerifier(settings, opts)
+			if err != nil {
+				printErr(err, false)
+				os.Exit(1)
+			}
+			if err := v.run(context.Background(), os.Stdout); err != nil {
+				printErr(err, false)
+				os.Exit(1)
+			}
+		},
+	}
+	verifyCmd.Flags().StringVar(&opts.serverURL, "url", "", "APM Server URL; defaults to the configured apm-server.host")
+	verifyCmd.Flags().StringVar(&opts.apiKey, "api-key", "", "API Key for sending events; defaults to using the configured apm-server.secret_token")
+	verifyCmd.Flags().StringVar(&opts.esURL, "es-url", "", "Elasticsearch URL; defaults to the configured output.elasticsearch.hosts")
+	verifyCmd.Flags().StringVar(&opts.esUsername, "es-username", "", "Elasticsearch username; defaults to the configured output.elasticsearch.username")
+	verifyCmd.Flags().StringVar(&opts.esPassword, "es-password", "", "Elasticsearch password; defaults to the configured output.elasticsearch.password")
+	verifyCmd.Flags().StringVar(&opts.esAPIKey, "es-api-key", "", "Elasticsearch API Key; defaults to the configured output.elasticsearch.api_key")
+	verifyCmd.Flags().DurationVar(&opts.timeout, "timeout", time.Minute, "maximum time to wait for the events to be searchable")
+	return verifyCmd
+}
+
+type verifyOptions struct {
+	serverURL  string
+	apiKey     string
+	esURL      string
+	esUsername string
+	esPassword string
+	esAPIKey   string
+	timeout    time.Duration
+}
+
+func newVerifier(settings instance.Settings, opts verifyOptions) (*verifier, error) {
+	beat, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := beat.BeatConfig()
+	if err != nil {
+		return nil, err
+	}
+	var esOutputCfg *common.Config
+	if beat.Config.Output.Name() == "elasticsearch" {
+		esOutputCfg = beat.Config.Output.Config()
+	}
+	beaterConfig, err := config.NewConfig(cfg, esOutputCfg)
+	if err != nil {
+		return nil, err
+	}
+	imp, err := newIntakeImporter(beaterConfig, opts.serverURL, opts.apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	esConfig := elasticsearch.DefaultConfig()
+	if esOutputCfg != nil {
+		if err := esOutputCfg.Unpack(esConfig); err != nil {
+			return nil, errors.Wrap(err, "invalid Elasticsearch output config")
+		}
+	}
+	if opts.esURL != "" {
+		esConfig.Hosts = elasticsearch.Hosts{opts.esURL}
+	}
+	if opts.esUsername != "" || opts.esPassword != "" {
+		esConfig.Username, esConfig.Password, esConfig.APIKey = opts.esUsername, opts.esPassword, ""
+	}
+	if opts.esAPIKey != "" {
+		esConfig.Username, esConfig.Password, esConfig.APIKey = "", "", opts.esAPIKey
+	}
+	client, err := elasticsearch.NewClient(esConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &verifier{
+		importer: imp,
+		es:       client,
+		timeout:  opts.timeout,
+	}, nil
+}
+
+// verifier sends canonical events to APM Server, and verifies the
+// documents produced for them in Elasticsearch.
+type verifier struct {
+	importer *importer
+	es       elasticsearch.Client
+
+	// timeout holds the maximum time to wait for events to be searchable.
+	timeout time.Duration
+
+	// interval holds the interval between searches, defaulting to one second.
+	interval time.Duration
+
+	// now is used for event timestamps, and may be overridden in tests.
+	now func() time.Time
+}
+
+// verifyDocument holds a document found for a verification event.
+type verifyDocument struct {
+	Index  string                 `json:"_index"`
+	Source map[string]interface{} `json:"_source"`
+}
+
+// run sends the verification events, waits for their documents to be
+// searchable, and checks them, writing the results to out. An error is
+// returned if any check fails.
+func (v *verifier) run(ctx context.Context, out io.Writer) error {
+	id, err := newVerifyID()
+	if err != nil {
+		return err
+	}
+	now := time.Now
+	if v.now != nil {
+		now = v.now
+	}
+	metadata, events, err := verifyEvents(id, now())
+	if err != nil {
+		return err
+	}
+	if err := v.importer.send(metadata, events); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Sent %d events with labels.%s %q\n", len(events), verifyLabel, id)
+
+	docs, err := v.waitDocuments(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var failures int
+	report := func(ok bool, format string, args ...interface{}) {
+		status := "OK  "
+		if !ok {
+			status = "FAIL"
+			failures++
+		}
+		fmt.Fprintf(out, "%s %s\n", status, fmt.Sprintf(format, args...))
+	}
+	var indices []string
+	for _, eventType := range verifyEventTypes() {
+		doc, ok := docs[eventType]
+		if !ok {
+			report(false, "%s: no document found within %s", eventType, v.timeout)
+			continue
+		}
+		indices = append(indices, doc.Index)
+		var missing []string
+		for _, field := range verifyChecks[eventType] {
+			if _, ok := lookupSourceField(doc.Source, field); !ok {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			report(false, "%s: document in %s is missing fields: %s", eventType, doc.Index, strings.Join(missing, ", "))
+		} else {
+			report(true, "%s: document in %s", eventType, doc.Index)
+		}
+	}
+
+	if len(indices) > 0 {
+		mismatches, err := v.checkMappings(ctx, indices)
+		if err != nil {
+			return err
+		}
+		for _, field := range verifyMappingFields() {
+			if mismatch, ok := mismatches[field]; ok {
+				report(false, "mapping: %s is %s, expected %s", field, mismatch, verifyMappings[field])
+			} else {
+				report(true, "mapping: %s is %s", field, verifyMappings[field])
+			}
+		}
+	}
+
+	if failures > 0 {
+		return errors.Errorf("verification failed: %d checks failed", failures)
+	}
+	return nil
+}
+
+// waitDocuments searches for the documents of the verification events
+// labeled with id, until documents have been found for all event types
+// or the timeout elapses. The documents found are returned by event type.
+func (v *verifier) waitDocuments(ctx context.Context, id string) (map[string]verifyDocument, error) {
+	interval := v.interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	docs := make(map[string]verifyDocument)
+	for {
+		if err := v.searchDocuments(ctx, id, docs); err != nil {
+			if ctx.Err() != nil {
+				return docs, nil
+			}
+			return nil, err
+		}
+		if len(docs) == len(verifyChecks) {
+			return docs, nil
+		}
+		select {
+		case <-ctx.Done():
+			return docs, nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (v *verifier) searchDocuments(ctx context.Context, id string, docs map[string]verifyDocument) error {
+	query := map[string]interface{}{
+		"size": 100,
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"labels." + verifyLabel: id},
+		},
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return err
+	}
+	status, respBody, err := v.es.SearchQuery(ctx, verifyIndex, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer respBody.Close()
+	if status >= http.StatusMultipleChoices {
+		msg, _ := ioutil.ReadAll(respBody)
+		return errors.Errorf("failed to search for events (%d): %s", status, strings.TrimSpace(string(msg)))
+	}
+	var result struct {
+		Hits struct {
+			Hits []verifyDocument `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(respBody).Decode(&result); err != nil {
+		return errors.Wrap(err, "failed to decode search response")
+	}
+	for _, doc := range result.Hits.Hits {
+		eventType, _ := lookupSourceField(doc.Source, "processor.event")
+		if s, ok := eventType.(string); ok {
+			if _, ok := verifyChecks[s]; ok {
+				docs[s] = doc
+			}
+		}
+	}
+	return nil
+}
+
+// checkMappings checks the mapping of verifyMappings fields in indices,
+// returning the actual mapping types of fields which are not mapped as
+// expected.
+func (v *verifier) checkMappings(ctx context.Context, indices []string) (map[string]string, error) {
+	path := "/" + url.PathEscape(strings.Join(indices, ",")) + "/_field_caps?fields=" +
+		url.QueryEscape(strings.Join(verifyMappingFields(), ","))
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.es.Perform(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return nil, errors.Errorf("failed to get field mappings (%s): %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var result struct {
+		Fields map[string]map[string]interface{} `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "failed to decode field capabilities response")
+	}
+
+	mismatches := make(map[string]string)
+	for field, expected := range verifyMappings {
+		var types []string
+		for t := range result.Fields[field] {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		switch {
+		case len(types) == 0:
+			mismatches[field] = "not mapped"
+		case len(types) > 1 || types[0] != expected:
+			mismatches[field] = strings.Join(types, " and ")
+		}
+	}
+	return mismatches, nil
+}
+
+// verifyMappingFields returns the fields of verifyMappings in sorted order.
+func verifyMappingFields() []string {
+	fields := make([]string, 0, len(verifyMappings))
+	for field := range verifyMappings {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// verifyEventTypes returns the verification event types in a stable order.
+func verifyEventTypes() []string {
+	return []string{"transaction", "span", "error", "metric"}
+}
+
+// verifyEvents returns the metadata and events sent for verification,
+// labeled with id, and with timestamps derived from now.
+func verifyEvents(id string, now time.Time) ([]byte, [][]byte, error) {
+	traceID, transactionID, spanID := id, id[:16], id[16:]
+	timestamp := now.UnixNano() / int64(time.Microsecond)
+	lines := []map[string]interface{}{{
+		"metadata": map[string]interface{}{
+			"service": map[string]interface{}{
+				"name":  verifyServiceName,
+				"agent": map[string]interface{}{"name": "go", "version": "1.0.0"},
+			},
+			"labels": map[string]interface{}{verifyLabel: id},
+		},
+	}, {
+		"transaction": map[string]interface{}{
+			"id": transactionID, "trace_id": traceID,
+			"name": "GET /verify", "type": "request",
+			"timestamp": timestamp, "duration": 10.0,
+			"span_count": map[string]interface{}{"started": 1},
+			"context": map[string]interface{}{
+				"request": map[string]interface{}{
+					"method": "GET",
+					"url":    map[string]interface{}{"full": "http://localhost/verify"},
+					"headers": map[string]interface{}{
+						"user-agent": "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/90.0.4430.93 Safari/537.36",
+					},
+				},
+			},
+		},
+	}, {
+		"span": map[string]interface{}{
+			"id": spanID, "trace_id": traceID,
+			"transaction_id": transactionID, "parent_id": transactionID,
+			"name": "SELECT FROM verify", "type": "db.postgresql.query",
+			"timestamp": timestamp + 1000, "duration": 5.0,
+		},
+	}, {
+		"error": map[string]interface{}{
+			"id": id, "trace_id": traceID,
+			"transaction_id": transactionID, "parent_id": transactionID,
+			"timestamp": timestamp + 2000,
+			"exception": map[string]interface{}{"message": "verification error", "type": "VerifyError"},
+		},
+	}, {
+		"metricset": map[string]interface{}{
+			"timestamp": timestamp,
+			"samples": map[string]interface{}{
+				"verify.count": map[string]interface{}{"value": 1},
+			},
+		},
+	}}
+
+	encoded := make([][]byte, len(lines))
+	for i, line := range lines {
+		data, err := json.Marshal(line)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded[i] = data
+	}
+	return encoded[0], encoded[1:], nil
+}
+
+// newVerifyID returns a random 32 character hex ID, usable as a trace ID.
+func newVerifyID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+// lookupSourceField returns the value of the field with the given dotted
+// name in source, which may hold the field either in nested objects or
+// under dotted keys.
+func lookupSourceField(source map[string]interface{}, field string) (interface{}, bool) {
+	if v, ok := source[field]; ok {
+		return v, true
+	}
+	for i := strings.IndexByte(field, '.'); i >= 0; {
+		if m, ok := source[field[:i]].(map[string]interface{}); ok {
+			if v, ok := lookupSourceField(m, field[i+1:]); ok {
+				return v, true
+			}
+		}
+		next := strings.IndexByte(field[i+1:], '.')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return nil, false
+}
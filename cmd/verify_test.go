@@ -0,0 +1,223 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/elasticsearch"
+)
+
+func TestVerifyEvents(t *testing.T) {
+	const id = "0123456789abcdef0123456789abcdef"
+	metadata, events, err := verifyEvents(id, time.Unix(1, 0))
+	require.NoError(t, err)
+	assert.Contains(t, string(metadata), `"labels":{"verify_id":"`+id+`"}`)
+
+	var eventTypes []string
+	var timestamps []float64
+	for _, event := range events {
+		var m map[string]map[string]interface{}
+		require.NoError(t, json.Unmarshal(event, &m))
+		for k, v := range m {
+			eventTypes = append(eventTypes, k)
+			timestamps = append(timestamps, v["timestamp"].(float64))
+		}
+	}
+	assert.Equal(t, []string{"transaction", "span", "error", "metricset"}, eventTypes)
+	assert.Equal(t, []float64{1000000, 1001000, 1002000, 1000000}, timestamps)
+}
+
+func TestLookupSourceField(t *testing.T) {
+	source := map[string]interface{}{
+		"labels.verify_id": "a",
+		"transaction": map[string]interface{}{
+			"duration": map[string]interface{}{"us": 10},
+		},
+		"verify": map[string]interface{}{"count": 1},
+	}
+	for field, expected := range map[string]interface{}{
+		"labels.verify_id":        "a",
+		"transaction.duration.us": 10,
+		"verify.count":            1,
+	} {
+		v, ok := lookupSourceField(source, field)
+		assert.True(t, ok, field)
+		assert.Equal(t, expected, v, field)
+	}
+	for _, field := range []string{"labels", "transaction.id", "verify.count.value"} {
+		_, ok := lookupSourceField(source, field)
+		assert.False(t, ok, field)
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	var intakeBody string
+	intake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		intakeBody = string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer intake.Close()
+
+	var searches int
+	fieldTypes := map[string]string{
+		"@timestamp":              "date",
+		"event.ingested":          "date",
+		"service.name":            "keyword",
+		"trace.id":                "keyword",
+		"transaction.id":          "keyword",
+		"transaction.duration.us": "long",
+		"span.duration.us":        "long",
+		"error.grouping_key":      "keyword",
+		"labels.verify_id":        "text",
+	}
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_search"):
+			assert.Equal(t, "/"+verifyIndex+"/_search", r.URL.Path)
+			searches++
+			common := map[string]interface{}{
+				"@timestamp": "2021-01-01T00:00:00Z", "service": map[string]interface{}{"name": "apm-server-verify"},
+				"observer": map[string]interface{}{"version": "8.0.0"}, "labels": map[string]interface{}{"verify_id": "x"},
+				"event": map[string]interface{}{"ingested": "2021-01-01T00:00:01Z"},
+				"trace": map[string]interface{}{"id": "x"},
+			}
+			hit := func(index string, fields map[string]interface{}) map[string]interface{} {
+				source := make(map[string]interface{})
+				for k, v := range common {
+					source[k] = v
+				}
+				for k, v := range fields {
+					source[k] = v
+				}
+				return map[string]interface{}{"_index": index, "_source": source}
+			}
+			hits := []interface{}{
+				hit("traces-apm-default", map[string]interface{}{
+					"processor":   map[string]interface{}{"event": "transaction"},
+					"transaction": map[string]interface{}{"id": "x", "duration": map[string]interface{}{"us": 10}},
+					"user_agent":  map[string]interface{}{"name": "Chrome"},
+				}),
+				hit("logs-apm.error-default", map[string]interface{}{
+					"processor": map[string]interface{}{"event": "error"},
+					"error":     map[string]interface{}{"id": "x", "grouping_key": "x"},
+				}),
+			}
+			if searches > 1 {
+				// The span and metricset become searchable later.
+				hits = append(hits,
+					hit("traces-apm-default", map[string]interface{}{
+						"processor": map[string]interface{}{"event": "span"},
+						"parent":    map[string]interface{}{"id": "x"},
+						"span":      map[string]interface{}{"id": "x", "duration": map[string]interface{}{"us": 5}},
+					}),
+					hit("metrics-apm.app.apm_server_verify-default", map[string]interface{}{
+						"processor":    map[string]interface{}{"event": "metric"},
+						"event":        nil,
+						"verify.count": 1,
+					}),
+				)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"hits": map[string]interface{}{"hits": hits},
+			})
+		case strings.HasSuffix(r.URL.Path, "/_field_caps"):
+			fields := make(map[string]interface{})
+			for field, fieldType := range fieldTypes {
+				fields[field] = map[string]interface{}{fieldType: map[string]interface{}{"type": fieldType}}
+			}
+			delete(fields, "span.duration.us")
+			json.NewEncoder(w).Encode(map[string]interface{}{"fields": fields})
+		default:
+			w.Write([]byte(`{"version":{"number":"7.13.0"}}`))
+		}
+	}))
+	defer es.Close()
+
+	client, err := elasticsearch.NewClient(&elasticsearch.Config{Hosts: elasticsearch.Hosts{es.URL}})
+	require.NoError(t, err)
+	v := &verifier{
+		importer: &importer{client: intake.Client(), url: intake.URL},
+		es:       client,
+		timeout:  10 * time.Second,
+		interval: time.Millisecond,
+	}
+
+	var out bytes.Buffer
+	err = v.run(context.Background(), &out)
+	assert.EqualError(t, err, "verification failed: 4 checks failed")
+	assert.Equal(t, 2, searches)
+	assert.Equal(t, 5, strings.Count(intakeBody, "\n"))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 14)
+	assert.Regexp(t, `^Sent 4 events with labels.verify_id "[0-9a-f]{32}"$`, lines[0])
+	assert.Equal(t, []string{
+		"OK   transaction: document in traces-apm-default",
+		"OK   span: document in traces-apm-default",
+		"FAIL error: document in logs-apm.error-default is missing fields: error.grouping_name",
+		"FAIL metric: document in metrics-apm.app.apm_server_verify-default is missing fields: event.ingested",
+		"OK   mapping: @timestamp is date",
+		"OK   mapping: error.grouping_key is keyword",
+		"OK   mapping: event.ingested is date",
+		"FAIL mapping: labels.verify_id is text, expected keyword",
+		"OK   mapping: service.name is keyword",
+		"FAIL mapping: span.duration.us is not mapped, expected long",
+		"OK   mapping: trace.id is keyword",
+		"OK   mapping: transaction.duration.us is long",
+		"OK   mapping: transaction.id is keyword",
+	}, lines[1:])
+}
+
+func TestVerifierTimeout(t *testing.T) {
+	intake := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer intake.Close()
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hits":{"hits":[]}}`))
+	}))
+	defer es.Close()
+
+	client, err := elasticsearch.NewClient(&elasticsearch.Config{Hosts: elasticsearch.Hosts{es.URL}})
+	require.NoError(t, err)
+	v := &verifier{
+		importer: &importer{client: intake.Client(), url: intake.URL},
+		es:       client,
+		timeout:  50 * time.Millisecond,
+		interval: 10 * time.Millisecond,
+	}
+	var out bytes.Buffer
+	err = v.run(context.Background(), &out)
+	assert.EqualError(t, err, "verification failed: 4 checks failed")
+	assert.Contains(t, out.String(), "FAIL transaction: no document found within 50ms\n")
+}
@@ -0,0 +1,65 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package decoder
+
+import "sync"
+
+// lineBufferSizes holds the capacities of pooled line buffers, in
+// increasing order. Line buffers are used for lines which do not fit
+// in a stream decoder's read buffer; pooling them by size avoids both
+// reallocating buffers as they grow, and retaining large buffers for
+// small lines.
+var lineBufferSizes = [...]int{
+	32 * 1024,
+	128 * 1024,
+	512 * 1024,
+	2 * 1024 * 1024,
+}
+
+var lineBufferPools [len(lineBufferSizes)]sync.Pool
+
+// lineBuffer wraps a byte slice, so it can be pooled without allocating.
+type lineBuffer struct {
+	b []byte
+}
+
+// getLineBuffer returns an empty lineBuffer with a capacity of at least
+// size bytes, from the pool of the smallest sufficient size.
+func getLineBuffer(size int) *lineBuffer {
+	for i, n := range lineBufferSizes {
+		if size <= n {
+			if buf, ok := lineBufferPools[i].Get().(*lineBuffer); ok {
+				buf.b = buf.b[:0]
+				return buf
+			}
+			return &lineBuffer{b: make([]byte, 0, n)}
+		}
+	}
+	return &lineBuffer{b: make([]byte, 0, size)}
+}
+
+// putLineBuffer returns buf to the pool matching its capacity. Buffers
+// larger than the largest pooled size are left for garbage collection.
+func putLineBuffer(buf *lineBuffer) {
+	for i, n := range lineBufferSizes {
+		if cap(buf.b) == n {
+			lineBufferPools[i].Put(buf)
+			return
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLineBuffer(t *testing.T) {
+	for _, test := range []struct {
+		size        int
+		expectedCap int
+	}{
+		{size: 1, expectedCap: 32 * 1024},
+		{size: 32 * 1024, expectedCap: 32 * 1024},
+		{size: 32*1024 + 1, expectedCap: 128 * 1024},
+		{size: 2 * 1024 * 1024, expectedCap: 2 * 1024 * 1024},
+		{size: 2*1024*1024 + 1, expectedCap: 2*1024*1024 + 1},
+	} {
+		buf := getLineBuffer(test.size)
+		assert.Len(t, buf.b, 0)
+		assert.Equal(t, test.expectedCap, cap(buf.b), test.size)
+		putLineBuffer(buf)
+	}
+}
+
+func TestPutLineBufferReuse(t *testing.T) {
+	buf := getLineBuffer(1)
+	buf.b = append(buf.b, "foo"...)
+	putLineBuffer(buf)
+
+	// sync.Pool does not guarantee reuse, but reused
+	// buffers must always be returned empty.
+	buf = getLineBuffer(1)
+	assert.Len(t, buf.b, 0)
+}
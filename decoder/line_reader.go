@@ -27,10 +27,15 @@ import (
 var ErrLineTooLong = errors.New("Line exceeded permitted length")
 
 // LineReader reads length-limited lines from streams using a limited amount of memory.
+//
+// Lines which do not fit in the underlying *bufio.Reader's buffer are
+// assembled in a pooled buffer, so the *bufio.Reader may be smaller than
+// maxLineLength.
 type LineReader struct {
 	br            *bufio.Reader
 	maxLineLength int
 	skip          bool
+	buf           *lineBuffer
 }
 
 func NewLineReader(reader *bufio.Reader, maxLineLength int) *LineReader {
@@ -44,34 +49,83 @@ func NewLineReader(reader *bufio.Reader, maxLineLength int) *LineReader {
 func (lr *LineReader) Reset(br *bufio.Reader) {
 	lr.br = br
 	lr.skip = false
+	if lr.buf != nil {
+		putLineBuffer(lr.buf)
+		lr.buf = nil
+	}
 }
 
 // ReadLine reads the next line from the given reader.
 // If it encounters a line that is longer than `maxLineLength` it will
 // return the first `maxLineLength` bytes with `ErrLineTooLong`. On the next
 // call it will return the next line.
+//
+// The returned slice is only valid until the next call to ReadLine or Reset.
 func (lr *LineReader) ReadLine() ([]byte, error) {
+	assembling := false
 	for {
-		prefix := false
 		line, err := lr.br.ReadSlice('\n')
-		if err == bufio.ErrBufferFull {
-			prefix = true
+		prefix := err == bufio.ErrBufferFull
+
+		if lr.skip {
+			if err == io.EOF {
+				return nil, io.EOF
+			} else if !prefix {
+				lr.skip = false
+			}
+			continue
 		}
 
-		if !lr.skip {
+		if prefix || assembling {
+			if !assembling {
+				assembling = true
+				if lr.buf != nil {
+					lr.buf.b = lr.buf.b[:0]
+				}
+			}
+			lr.appendLine(line)
+			line = lr.buf.b
 			if prefix {
+				if len(line) < lr.maxLineLength {
+					continue
+				}
 				lr.skip = true
 				return line[:lr.maxLineLength], ErrLineTooLong
 			}
-
-			if len(line) > 0 && line[len(line)-1] == '\n' {
-				line = line[:len(line)-1]
+			if n := len(line); n > 0 && line[n-1] == '\n' {
+				line = line[:n-1]
+			}
+			if len(line) >= lr.maxLineLength {
+				return line[:lr.maxLineLength], ErrLineTooLong
 			}
 			return line, err
-		} else if err == io.EOF {
-			return nil, io.EOF
-		} else if !prefix {
-			lr.skip = false
 		}
+
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			line = line[:len(line)-1]
+		}
+		return line, err
+	}
+}
+
+// appendLine appends p to the line being assembled in lr.buf, growing
+// lr.buf as needed. At most maxLineLength+1 bytes are retained, which is
+// enough to determine whether the line is too long.
+func (lr *LineReader) appendLine(p []byte) {
+	var n int
+	if lr.buf != nil {
+		n = len(lr.buf.b)
+	}
+	if max := lr.maxLineLength + 1 - n; len(p) > max {
+		p = p[:max]
+	}
+	if lr.buf == nil || n+len(p) > cap(lr.buf.b) {
+		buf := getLineBuffer(n + len(p))
+		if lr.buf != nil {
+			buf.b = append(buf.b, lr.buf.b...)
+			putLineBuffer(lr.buf)
+		}
+		lr.buf = buf
 	}
+	lr.buf.b = append(lr.buf.b, p...)
 }
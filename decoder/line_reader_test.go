@@ -212,3 +212,46 @@ func TestLineReaderShort(t *testing.T) {
 	assert.Equal(t, io.EOF, err)
 	assert.Equal(t, []byte("line2"), buf)
 }
+
+func TestLineReaderAssembleLines(t *testing.T) {
+	for _, r := range [](func(io.Reader) io.Reader){
+		func(r io.Reader) io.Reader { return r },
+		iotest.HalfReader,
+		iotest.OneByteReader,
+		iotest.DataErrReader,
+	} {
+		// The bufio.Reader's minimum buffer size is 16 bytes,
+		// so lines longer than that are assembled in a line buffer.
+		readBuf := bytes.NewBufferString("line1\n0123456789012345678901234\n01234567890123456789012345678901234567890\nline4")
+		lr := NewLineReader(bufio.NewReaderSize(r(readBuf), 16), 30)
+
+		buf, err := lr.ReadLine()
+		assert.NoError(t, err)
+		assert.Equal(t, "line1", string(buf))
+
+		buf, err = lr.ReadLine()
+		assert.NoError(t, err)
+		assert.Equal(t, "0123456789012345678901234", string(buf))
+
+		buf, err = lr.ReadLine()
+		assert.Equal(t, ErrLineTooLong, err)
+		assert.Equal(t, "012345678901234567890123456789", string(buf))
+
+		buf, err = lr.ReadLine()
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, "line4", string(buf))
+	}
+}
+
+func TestLineReaderAssembleLineTooLongWithNewline(t *testing.T) {
+	readBuf := bytes.NewBufferString("01234567890123456789\nline2\n")
+	lr := NewLineReader(bufio.NewReaderSize(readBuf, 16), 20)
+
+	buf, err := lr.ReadLine()
+	assert.Equal(t, ErrLineTooLong, err)
+	assert.Equal(t, "01234567890123456789", string(buf))
+
+	buf, err = lr.ReadLine()
+	assert.NoError(t, err)
+	assert.Equal(t, "line2", string(buf))
+}
@@ -21,7 +21,9 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"sync"
 
 	"github.com/pkg/errors"
 
@@ -38,10 +40,17 @@ var (
 	uncompressedLengthAccumulator = monitoring.NewInt(decoderMetrics, "uncompressed.content-length")
 	uncompressedCounter           = monitoring.NewInt(decoderMetrics, "uncompressed.count")
 	readerCounter                 = monitoring.NewInt(decoderMetrics, "reader.count")
+
+	gzipReaderPool sync.Pool
+	zlibReaderPool sync.Pool
 )
 
 // CompressedRequestReader returns a reader that will decompress
-// the body according to the supplied Content-Encoding header in the request
+// the body according to the supplied Content-Encoding header in the request.
+//
+// Decompressing readers are pooled; the returned reader must be closed once
+// the body has been read, and must not be used after it is closed. Closing
+// the returned reader does not close the request body.
 func CompressedRequestReader(req *http.Request) (io.ReadCloser, error) {
 	reader := req.Body
 	if reader == nil {
@@ -60,7 +69,7 @@ func CompressedRequestReader(req *http.Request) (io.ReadCloser, error) {
 			deflateCounter.Inc()
 		}
 		var err error
-		reader, err = zlib.NewReader(reader)
+		reader, err = newZlibReader(reader)
 		if err != nil {
 			return nil, err
 		}
@@ -71,7 +80,7 @@ func CompressedRequestReader(req *http.Request) (io.ReadCloser, error) {
 			gzipCounter.Inc()
 		}
 		var err error
-		reader, err = gzip.NewReader(reader)
+		reader, err = newGzipReader(reader)
 		if err != nil {
 			return nil, err
 		}
@@ -80,7 +89,56 @@ func CompressedRequestReader(req *http.Request) (io.ReadCloser, error) {
 			uncompressedLengthAccumulator.Add(cLen)
 			uncompressedCounter.Inc()
 		}
+		reader = ioutil.NopCloser(reader)
 	}
 	readerCounter.Inc()
 	return reader, nil
 }
+
+// pooledReader is an io.ReadCloser which releases a pooled decompressing
+// reader when closed.
+type pooledReader struct {
+	io.Reader
+	release func()
+}
+
+func (r *pooledReader) Close() error {
+	if r.release != nil {
+		r.release()
+		r.release = nil
+		r.Reader = nil
+	}
+	return nil
+}
+
+func newGzipReader(r io.Reader) (io.ReadCloser, error) {
+	zr, ok := gzipReaderPool.Get().(*gzip.Reader)
+	if ok {
+		if err := zr.Reset(r); err != nil {
+			gzipReaderPool.Put(zr)
+			return nil, err
+		}
+	} else {
+		var err error
+		if zr, err = gzip.NewReader(r); err != nil {
+			return nil, err
+		}
+	}
+	return &pooledReader{Reader: zr, release: func() { gzipReaderPool.Put(zr) }}, nil
+}
+
+func newZlibReader(r io.Reader) (io.ReadCloser, error) {
+	zr, ok := zlibReaderPool.Get().(io.ReadCloser)
+	if ok {
+		if err := zr.(zlib.Resetter).Reset(r, nil); err != nil {
+			zlibReaderPool.Put(zr)
+			return nil, err
+		}
+	} else {
+		var err error
+		if zr, err = zlib.NewReader(r); err != nil {
+			return nil, err
+		}
+	}
+	return &pooledReader{Reader: zr, release: func() { zlibReaderPool.Put(zr) }}, nil
+}
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package decoder_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/decoder"
+)
+
+func TestCompressedRequestReader(t *testing.T) {
+	compress := map[string]func(w io.Writer) io.WriteCloser{
+		"gzip":    func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"":        nil,
+	}
+	for contentEncoding, newWriter := range compress {
+		t.Run(contentEncoding, func(t *testing.T) {
+			// Read several bodies, so pooled readers are reused.
+			for _, content := range []string{"first body", "second body", "third body"} {
+				var body bytes.Buffer
+				if newWriter != nil {
+					w := newWriter(&body)
+					w.Write([]byte(content))
+					require.NoError(t, w.Close())
+				} else {
+					body.WriteString(content)
+				}
+
+				req := httptest.NewRequest(http.MethodPost, "/", &body)
+				req.Header.Set("Content-Encoding", contentEncoding)
+				reader, err := decoder.CompressedRequestReader(req)
+				require.NoError(t, err)
+				data, err := ioutil.ReadAll(reader)
+				require.NoError(t, err)
+				assert.Equal(t, content, string(data))
+				assert.NoError(t, reader.Close())
+				assert.NoError(t, reader.Close()) // Close is idempotent
+			}
+		})
+	}
+}
+
+func TestCompressedRequestReaderInvalid(t *testing.T) {
+	for _, contentEncoding := range []string{"gzip", "deflate"} {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("not compressed")))
+		req.Header.Set("Content-Encoding", contentEncoding)
+		_, err := decoder.CompressedRequestReader(req)
+		assert.Error(t, err, contentEncoding)
+	}
+}
@@ -25,11 +25,19 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
+// readBufferSize is the maximum size of an NDJSONStreamDecoder's read buffer.
+// Lines longer than this are assembled in pooled, size-tiered buffers.
+const readBufferSize = 16 * 1024
+
 // NewNDJSONStreamDecoder returns a new NDJSONStreamDecoder which decodes
 // ND-JSON lines from r, with a maximum line length of maxLineLength.
 func NewNDJSONStreamDecoder(r io.Reader, maxLineLength int) *NDJSONStreamDecoder {
 	var dec NDJSONStreamDecoder
-	dec.bufioReader = bufio.NewReaderSize(r, maxLineLength)
+	bufferSize := maxLineLength
+	if bufferSize > readBufferSize {
+		bufferSize = readBufferSize
+	}
+	dec.bufioReader = bufio.NewReaderSize(r, bufferSize)
 	dec.lineReader = NewLineReader(dec.bufioReader, maxLineLength)
 	dec.resetDecoder()
 	return &dec
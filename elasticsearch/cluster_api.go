@@ -0,0 +1,82 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+// GetVersion returns the version of the Elasticsearch cluster.
+func GetVersion(ctx context.Context, client Client) (*common.Version, error) {
+	var info struct {
+		Version struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}
+	if err := doRequest(ctx, client, esapi.InfoRequest{}, &info); err != nil {
+		return nil, err
+	}
+	return common.NewVersion(info.Version.Number)
+}
+
+// IndexTemplateExists reports whether the named (composable) index template exists.
+func IndexTemplateExists(ctx context.Context, client Client, name string) (bool, error) {
+	return exists(ctx, client, esapi.IndicesExistsIndexTemplateRequest{Name: name})
+}
+
+// LegacyTemplateExists reports whether the named legacy index template exists.
+func LegacyTemplateExists(ctx context.Context, client Client, name string) (bool, error) {
+	return exists(ctx, client, esapi.IndicesExistsTemplateRequest{Name: []string{name}})
+}
+
+// ILMPolicyExists reports whether the named ILM policy exists.
+func ILMPolicyExists(ctx context.Context, client Client, name string) (bool, error) {
+	return exists(ctx, client, esapi.ILMGetLifecycleRequest{Policy: name})
+}
+
+// IngestPipelineExists reports whether the named ingest pipeline exists.
+func IngestPipelineExists(ctx context.Context, client Client, name string) (bool, error) {
+	return exists(ctx, client, esapi.IngestGetPipelineRequest{PipelineID: name})
+}
+
+// CountIngestNodes returns the number of nodes in the cluster with the ingest role.
+func CountIngestNodes(ctx context.Context, client Client) (int, error) {
+	var nodes struct {
+		Nodes struct {
+			Total int `json:"total"`
+		} `json:"_nodes"`
+	}
+	req := esapi.NodesInfoRequest{NodeID: []string{"ingest:true"}, Metric: []string{"_none"}}
+	if err := doRequest(ctx, client, req, &nodes); err != nil {
+		return 0, err
+	}
+	return nodes.Nodes.Total, nil
+}
+
+func exists(ctx context.Context, client Client, req esapi.Request) (bool, error) {
+	err := doRequest(ctx, client, req, nil)
+	if err, ok := err.(*Error); ok && err.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
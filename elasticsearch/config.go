@@ -20,7 +20,6 @@ package elasticsearch
 import (
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -29,12 +28,12 @@ import (
 	"github.com/elastic/beats/v7/libbeat/common/transport"
 	"github.com/elastic/beats/v7/libbeat/common/transport/tlscommon"
 	"github.com/elastic/beats/v7/libbeat/outputs/elasticsearch"
+
+	"github.com/elastic/apm-server/httpproxy"
 )
 
 const (
-	prefixHTTP       = "http"
-	prefixHTTPSchema = prefixHTTP + "://"
-	defaultESPort    = 9200
+	defaultESPort = 9200
 )
 
 var (
@@ -50,6 +49,7 @@ type Config struct {
 	Path         string            `config:"path"`
 	ProxyURL     string            `config:"proxy_url"`
 	ProxyDisable bool              `config:"proxy_disable"`
+	NoProxy      []string          `config:"no_proxy"`
 	Timeout      time.Duration     `config:"timeout"`
 	TLS          *tlscommon.Config `config:"ssl"`
 	Username     string            `config:"username"`
@@ -103,23 +103,8 @@ func connectionConfig(config *Config) (http.RoundTripper, []string, http.Header,
 }
 
 func httpProxyURL(cfg *Config) (func(*http.Request) (*url.URL, error), error) {
-	if cfg.ProxyDisable {
-		return nil, nil
-	}
-
-	if cfg.ProxyURL == "" {
-		return http.ProxyFromEnvironment, nil
-	}
-
-	proxyStr := cfg.ProxyURL
-	if !strings.HasPrefix(proxyStr, prefixHTTP) {
-		proxyStr = prefixHTTPSchema + proxyStr
-	}
-	u, err := url.Parse(proxyStr)
-	if err != nil {
-		return nil, err
-	}
-	return http.ProxyURL(u), nil
+	proxy := httpproxy.Config{URL: cfg.ProxyURL, Disable: cfg.ProxyDisable, NoProxy: cfg.NoProxy}
+	return proxy.ProxyFunc()
 }
 
 func addresses(cfg *Config) ([]string, error) {
@@ -74,6 +74,24 @@ func TestHttpProxyUrl(t *testing.T) {
 		url, err = proxy(httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
 		require.Nil(t, err)
 		assert.Equal(t, "http://foo", url.String())
+
+		// create proxy function from SOCKS5 URL
+		proxy, err = httpProxyURL(&Config{ProxyURL: "socks5://foo:1080"})
+		require.Nil(t, err)
+		url, err = proxy(httptest.NewRequest(http.MethodGet, "http://example.com/", nil))
+		require.Nil(t, err)
+		assert.Equal(t, "socks5://foo:1080", url.String())
+	})
+
+	t.Run("no proxy", func(t *testing.T) {
+		proxy, err := httpProxyURL(&Config{ProxyURL: "http://foo", NoProxy: []string{"example.com"}})
+		require.Nil(t, err)
+		url, err := proxy(httptest.NewRequest(http.MethodGet, "http://es.example.com/", nil))
+		require.Nil(t, err)
+		assert.Nil(t, url)
+		url, err = proxy(httptest.NewRequest(http.MethodGet, "http://elastic.co/", nil))
+		require.Nil(t, err)
+		assert.Equal(t, "http://foo", url.String())
 	})
 }
 
@@ -135,8 +153,9 @@ func TestBeatsConfigSynced(t *testing.T) {
 	libbeatStructFields := getStructFields(libbeatType)
 	localStructFields := getStructFields(localType)
 
-	// "hosts" is only expected in the local struct
+	// "hosts" and "no_proxy" are only expected in the local struct
 	delete(localStructFields, "hosts")
+	delete(localStructFields, "no_proxy")
 
 	// We expect the libbeat struct to be a superset of all other
 	// fields defined in the local struct, with identical tags and
@@ -64,6 +64,23 @@ func NewTransport(t *testing.T, statusCode int, esBody map[string]interface{}) *
 	}
 }
 
+// NewTransportFunc creates test transport instance decoding each request body as JSON, and
+// returning the status code and body returned by fn when called with the decoded request body.
+func NewTransportFunc(t *testing.T, fn func(req map[string]interface{}) (int, map[string]interface{})) *Transport {
+	return &Transport{
+		roundTripFn: func(r *http.Request) (*http.Response, error) {
+			var req map[string]interface{}
+			if r.Body != nil {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			}
+			statusCode, esBody := fn(req)
+			resp, err := json.Marshal(esBody)
+			require.NoError(t, err)
+			return &http.Response{StatusCode: statusCode, Body: ioutil.NopCloser(bytes.NewReader(resp))}, nil
+		},
+	}
+}
+
 // NewElasticsearchClient creates ES client using the given transport instance
 func NewElasticsearchClient(transport *Transport) (elasticsearch.Client, error) {
 	return elasticsearch.NewVersionedClient("", "", "", []string{}, nil, transport, 3, elasticsearch.DefaultBackoff)
@@ -33,7 +33,8 @@ func CreateAPIKey(ctx context.Context, client Client, apikeyReq CreateAPIKeyRequ
 	return apikey, err
 }
 
-// GetAPIKeys requires manage_api_key cluster privilege
+// GetAPIKeys requires manage_api_key cluster privilege, unless an API Key
+// queries its own information with apikeyReq.Owner and apikeyReq.Credentials.
 func GetAPIKeys(ctx context.Context, client Client, apikeyReq GetAPIKeyRequest) (GetAPIKeyResponse, error) {
 	req := esapi.SecurityGetAPIKeyRequest{}
 	if apikeyReq.ID != nil {
@@ -41,6 +42,14 @@ func GetAPIKeys(ctx context.Context, client Client, apikeyReq GetAPIKeyRequest)
 	} else if apikeyReq.Name != nil {
 		req.Name = *apikeyReq.Name
 	}
+	if apikeyReq.Owner {
+		req.Owner = &apikeyReq.Owner
+	}
+	if apikeyReq.Credentials != "" {
+		header := make(http.Header)
+		header.Set("Authorization", "ApiKey "+apikeyReq.Credentials)
+		req.Header = header
+	}
 	var apikey GetAPIKeyResponse
 	err := doRequest(ctx, client, req, &apikey)
 	return apikey, err
@@ -81,6 +90,10 @@ type CreateAPIKeyResponse struct {
 type GetAPIKeyRequest struct {
 	APIKeyQuery
 	Owner bool `json:"owner"`
+
+	// Credentials, if non-empty, holds the base64-encoded API Key
+	// with which the request is sent, instead of the client's.
+	Credentials string `json:"-"`
 }
 
 type GetAPIKeyResponse struct {
@@ -89,12 +102,16 @@ type GetAPIKeyResponse struct {
 
 type HasPrivilegesRequest struct {
 	// can't reuse the `Applications` type because here the JSON attribute must be singular
-	Applications []Application `json:"application"`
+	Applications []Application     `json:"application,omitempty"`
+	Cluster      []PrivilegeAction `json:"cluster,omitempty"`
+	Index        []IndexPrivileges `json:"index,omitempty"`
 }
 type HasPrivilegesResponse struct {
 	Username    string                             `json:"username"`
 	HasAll      bool                               `json:"has_all_requested"`
 	Application map[AppName]PermissionsPerResource `json:"application"`
+	Cluster     Permissions                        `json:"cluster"`
+	Index       PermissionsPerResource             `json:"index"`
 }
 
 type InvalidateAPIKeyRequest struct {
@@ -120,6 +137,17 @@ type Application struct {
 	Resources  []Resource        `json:"resources"`
 }
 
+// IndexPrivileges holds a set of index privileges, and the
+// index names or patterns to which they apply.
+type IndexPrivileges struct {
+	Names      []string          `json:"names"`
+	Privileges []PrivilegeAction `json:"privileges"`
+
+	// AllowRestrictedIndices must be set for privileges
+	// on system indices, such as .apm-agent-configuration.
+	AllowRestrictedIndices bool `json:"allow_restricted_indices,omitempty"`
+}
+
 type APIKeyResponse struct {
 	APIKey
 	Creation    int64                  `json:"creation"`
@@ -0,0 +1,150 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package featureflag provides a registry of feature flags, which can be
+// used to switch risky subsystems on and off while the server is running.
+package featureflag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+// Flag identifies a feature flag.
+type Flag string
+
+const (
+	// FastValidation controls whether event validation is skipped for
+	// trusted agents, when configured with `apm-server.fast_validation`.
+	FastValidation Flag = "fast_validation"
+
+	// SLOEvaluation controls whether transactions are recorded by the
+	// service level objective evaluator, when configured with `apm-server.slo`.
+	SLOEvaluation Flag = "slo_evaluation"
+
+	// OTLPExport controls whether traces are exported to an OpenTelemetry
+	// collector, when configured with `apm-server.otel.export`.
+	OTLPExport Flag = "otlp_export"
+
+	// TransactionMarksMetrics controls whether RUM page-load marks are
+	// aggregated into metrics, when configured with
+	// `apm-server.aggregation.transaction_marks`.
+	TransactionMarksMetrics Flag = "transaction_marks_metrics"
+
+	// ErrorMetrics controls whether errors are counted per grouping key
+	// into metrics, when configured with `apm-server.aggregation.errors`.
+	ErrorMetrics Flag = "error_metrics"
+
+	// BreakdownMetrics controls whether breakdown metrics are computed
+	// from received spans, when configured with
+	// `apm-server.aggregation.breakdown`.
+	BreakdownMetrics Flag = "breakdown_metrics"
+)
+
+// defaults holds all known flags, and their default states.
+//
+// Flags guard subsystems which must also be enabled in their own
+// configuration, so they are enabled by default. Disabling a flag
+// switches off the subsystem without restarting the server.
+var defaults = map[Flag]bool{
+	FastValidation: true,
+	SLOEvaluation:  true,
+	OTLPExport:     true,
+
+	TransactionMarksMetrics: true,
+	ErrorMetrics:            true,
+	BreakdownMetrics:        true,
+}
+
+// Default is the registry used by the server, reporting flag states
+// in monitoring under `apm-server.feature_flags`.
+var Default = NewRegistry(monitoring.Default.NewRegistry("apm-server.feature_flags"))
+
+// Enabled reports whether flag is enabled in the Default registry.
+func Enabled(flag Flag) bool {
+	return Default.Enabled(flag)
+}
+
+// Known reports whether name identifies a known flag.
+func Known(name string) bool {
+	_, ok := defaults[Flag(name)]
+	return ok
+}
+
+// Registry holds the states of all known flags.
+//
+// Flag states may be read concurrently with updates.
+type Registry struct {
+	mu    sync.Mutex // serializes Update
+	flags map[Flag]*monitoring.Bool
+}
+
+// NewRegistry returns a new Registry with all flags in their default state,
+// reporting flag states in reg.
+func NewRegistry(reg *monitoring.Registry) *Registry {
+	flags := make(map[Flag]*monitoring.Bool, len(defaults))
+	for flag, enabled := range defaults {
+		v := monitoring.NewBool(reg, string(flag))
+		v.Set(enabled)
+		flags[flag] = v
+	}
+	return &Registry{flags: flags}
+}
+
+// Enabled reports whether flag is enabled. Unknown flags are never enabled.
+func (r *Registry) Enabled(flag Flag) bool {
+	v, ok := r.flags[flag]
+	return ok && v.Get()
+}
+
+// Update sets the state of each flag named in overrides, and resets all
+// other flags to their default state. Update returns the flags whose
+// state changed.
+//
+// If overrides names an unknown flag, Update returns an error and no
+// flags are changed.
+func (r *Registry) Update(overrides map[string]bool) (map[Flag]bool, error) {
+	var unknown []string
+	for name := range overrides {
+		if !Known(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown feature flags: %s", strings.Join(unknown, ", "))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := make(map[Flag]bool)
+	for flag, v := range r.flags {
+		enabled, ok := overrides[string(flag)]
+		if !ok {
+			enabled = defaults[flag]
+		}
+		if v.Get() != enabled {
+			v.Set(enabled)
+			changed[flag] = enabled
+		}
+	}
+	return changed, nil
+}
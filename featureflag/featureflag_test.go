@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package featureflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+func TestRegistryDefaults(t *testing.T) {
+	r := NewRegistry(monitoring.NewRegistry())
+	for flag, enabled := range defaults {
+		assert.Equal(t, enabled, r.Enabled(flag), flag)
+	}
+	assert.False(t, r.Enabled("unknown"))
+}
+
+func TestRegistryUpdate(t *testing.T) {
+	reg := monitoring.NewRegistry()
+	r := NewRegistry(reg)
+
+	changed, err := r.Update(map[string]bool{"otlp_export": false, "slo_evaluation": true})
+	require.NoError(t, err)
+	assert.Equal(t, map[Flag]bool{OTLPExport: false}, changed)
+	assert.False(t, r.Enabled(OTLPExport))
+	assert.True(t, r.Enabled(SLOEvaluation))
+
+	snapshot := monitoring.CollectFlatSnapshot(reg, monitoring.Full, false)
+	assert.Equal(t, map[string]bool{
+		"fast_validation":           true,
+		"otlp_export":               false,
+		"slo_evaluation":            true,
+		"transaction_marks_metrics": true,
+		"error_metrics":             true,
+		"breakdown_metrics":         true,
+	}, snapshot.Bools)
+
+	// Flags which are no longer overridden are reset to their defaults.
+	changed, err = r.Update(nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[Flag]bool{OTLPExport: true}, changed)
+	assert.True(t, r.Enabled(OTLPExport))
+}
+
+func TestRegistryUpdateUnknown(t *testing.T) {
+	r := NewRegistry(monitoring.NewRegistry())
+	changed, err := r.Update(map[string]bool{"otlp_export": false, "warp_drive": true})
+	assert.EqualError(t, err, "unknown feature flags: warp_drive")
+	assert.Nil(t, changed)
+	assert.True(t, r.Enabled(OTLPExport))
+}
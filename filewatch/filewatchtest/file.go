@@ -0,0 +1,56 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package filewatchtest provides helpers for testing code which
+// watches files for changes.
+package filewatchtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// WriteFile replaces the contents of the file at path with data.
+//
+// The data is written to a temporary file which is then renamed into
+// place, so watchers never observe partially written contents. The
+// modification time is advanced past that of any file being replaced,
+// so changes are detected even if the file system's timestamps have a
+// coarse resolution.
+func WriteFile(t testing.TB, path string, data []byte) {
+	t.Helper()
+	modTime := time.Now()
+	if info, err := os.Stat(path); err == nil && modTime.Before(info.ModTime().Add(time.Second)) {
+		modTime = info.ModTime().Add(time.Second)
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	require.NoError(t, err)
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	require.NoError(t, err)
+	require.NoError(t, os.Chmod(f.Name(), 0644))
+	require.NoError(t, os.Chtimes(f.Name(), modTime, modTime))
+	require.NoError(t, os.Rename(f.Name(), path))
+}
@@ -0,0 +1,123 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package filewatch provides a Reloader for files which are loaded into
+// memory, and reloaded periodically when they change.
+package filewatch
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+// LoadFunc loads the file at path, returning a non-nil value
+// representing its contents.
+type LoadFunc func(path string) (interface{}, error)
+
+// Reloader holds the most recently loaded contents of a file, which is
+// reloaded by Watch when it changes.
+type Reloader struct {
+	path    string
+	load    LoadFunc
+	logger  *logp.Logger
+	reloads *monitoring.Int
+	errors  *monitoring.Int
+
+	value   atomic.Value
+	modTime time.Time
+	size    int64
+}
+
+// NewReloader returns a new Reloader with the file at path loaded by load.
+// Successful reloads and reload errors are counted by reloads and errors,
+// and logged with logger.
+func NewReloader(
+	path string,
+	load LoadFunc,
+	reloads, errors *monitoring.Int,
+	logger *logp.Logger,
+) (*Reloader, error) {
+	r := &Reloader{
+		path:    path,
+		load:    load,
+		logger:  logger,
+		reloads: reloads,
+		errors:  errors,
+	}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Path returns the path of the file.
+func (r *Reloader) Path() string {
+	return r.path
+}
+
+// Current returns the most recently loaded contents of the file.
+func (r *Reloader) Current() interface{} {
+	return r.value.Load()
+}
+
+// Watch checks the file for changes every period, reloading it when its
+// modification time or size changes, until ctx is cancelled. If the file
+// cannot be loaded, an error is logged and the previously loaded contents
+// continue to be used.
+func (r *Reloader) Watch(ctx context.Context, period time.Duration) error {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		reloaded, err := r.reload()
+		if err != nil {
+			r.errors.Inc()
+			r.logger.Errorf("failed to reload %s, keeping previously loaded contents: %s", r.path, err)
+		} else if reloaded {
+			r.reloads.Inc()
+			r.logger.Infof("reloaded %s", r.path)
+		}
+	}
+}
+
+// reload loads the file if it has changed since it was last loaded,
+// reporting whether the file was reloaded.
+func (r *Reloader) reload() (bool, error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return false, err
+	}
+	if r.Current() != nil && info.ModTime().Equal(r.modTime) && info.Size() == r.size {
+		return false, nil
+	}
+	value, err := r.load(r.path)
+	if err != nil {
+		return false, err
+	}
+	r.value.Store(value)
+	r.modTime, r.size = info.ModTime(), info.Size()
+	return true, nil
+}
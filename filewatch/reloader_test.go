@@ -0,0 +1,101 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package filewatch
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/filewatch/filewatchtest"
+)
+
+func TestReloader(t *testing.T) {
+	path := writeFile(t, "", "one")
+	registry := monitoring.NewRegistry()
+	reloads := monitoring.NewInt(registry, "reloads")
+	reloadErrors := monitoring.NewInt(registry, "reload_errors")
+
+	var loads int64
+	load := func(path string) (interface{}, error) {
+		atomic.AddInt64(&loads, 1)
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if string(data) == "invalid" {
+			return nil, errors.New("invalid contents")
+		}
+		return string(data), nil
+	}
+	reloader, err := NewReloader(path, load, reloads, reloadErrors, logp.NewLogger("filewatch"))
+	require.NoError(t, err)
+	assert.Equal(t, "one", reloader.Current())
+	assert.Equal(t, path, reloader.Path())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watchErr := make(chan error, 1)
+	go func() { watchErr <- reloader.Watch(ctx, 10*time.Millisecond) }()
+
+	// The file is not reloaded until it changes.
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&loads))
+	assert.Zero(t, reloads.Get())
+
+	// Invalid contents are counted as errors, keeping the previous contents.
+	writeFile(t, path, "invalid")
+	assert.Eventually(t, func() bool { return reloadErrors.Get() > 0 }, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "one", reloader.Current())
+
+	writeFile(t, path, "two")
+	assert.Eventually(t, func() bool { return reloader.Current() == "two" }, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(1), reloads.Get())
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-watchErr)
+}
+
+func TestNewReloaderError(t *testing.T) {
+	load := func(path string) (interface{}, error) { return path, nil }
+	registry := monitoring.NewRegistry()
+	_, err := NewReloader(
+		filepath.Join(t.TempDir(), "missing"), load,
+		monitoring.NewInt(registry, "reloads"), monitoring.NewInt(registry, "reload_errors"),
+		logp.NewLogger("filewatch"),
+	)
+	assert.Error(t, err)
+}
+
+func writeFile(t testing.TB, path, content string) string {
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "file")
+	}
+	filewatchtest.WriteFile(t, path, []byte(content))
+	return path
+}
@@ -0,0 +1,192 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package geoip provides a beat.Processor for adding geographical
+// information about client IP addresses to events, using a MaxMind
+// database, for outputs without Elasticsearch ingest pipelines.
+package geoip
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/filewatch"
+	logs "github.com/elastic/apm-server/log"
+)
+
+const (
+	ipField  = "client.ip"
+	geoField = "client.geo"
+
+	// language holds the language of the place names added to events.
+	language = "en"
+)
+
+var (
+	registry           = monitoring.Default.NewRegistry("apm-server.geoip")
+	monitoringEnriched = monitoring.NewInt(registry, "enriched")
+	monitoringNotFound = monitoring.NewInt(registry, "not_found")
+	monitoringReload   = monitoring.NewInt(registry, "reloads")
+	monitoringErrors   = monitoring.NewInt(registry, "reload_errors")
+)
+
+// Enricher is a beat.Processor which adds client.geo fields to events
+// with a client.ip, looking up the IP address in a MaxMind GeoIP2 or
+// GeoLite2 City database. The fields match those added by the geoip
+// processor of the Elasticsearch ingest pipeline. The database is
+// reloaded by Watch when it changes.
+type Enricher struct {
+	reloader *filewatch.Reloader
+}
+
+// NewEnricher returns a new Enricher with the database loaded from path.
+func NewEnricher(path string) (*Enricher, error) {
+	reloader, err := filewatch.NewReloader(
+		path, loadDatabase, monitoringReload, monitoringErrors,
+		logp.NewLogger(logs.GeoIP),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Enricher{reloader: reloader}, nil
+}
+
+// Watch checks the database for changes every period, reloading it
+// until ctx is cancelled, as described by filewatch.Reloader.Watch.
+func (e *Enricher) Watch(ctx context.Context, period time.Duration) error {
+	return e.reloader.Watch(ctx, period)
+}
+
+// loadDatabase loads the database at path.
+//
+// The database is read into memory rather than memory-mapped, so readers
+// of replaced databases need not be closed while events are enriched.
+func loadDatabase(path string) (interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := maxminddb.FromBytes(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid GeoIP database %s", path)
+	}
+	return reader, nil
+}
+
+func (e *Enricher) currentReader() *maxminddb.Reader {
+	return e.reloader.Current().(*maxminddb.Reader)
+}
+
+// Run adds client.geo fields to event for its client.ip, if the IP address
+// is found in the database. Events which already have client.geo fields are
+// not modified.
+func (e *Enricher) Run(event *beat.Event) (*beat.Event, error) {
+	value, _ := event.Fields.GetValue(ipField)
+	s, ok := value.(string)
+	if !ok {
+		return event, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return event, nil
+	}
+	if ok, _ := event.Fields.HasKey(geoField); ok {
+		return event, nil
+	}
+	var record cityRecord
+	if err := e.currentReader().Lookup(ip, &record); err != nil {
+		// Lookups fail for IPv6 addresses in IPv4-only databases.
+		monitoringNotFound.Inc()
+		return event, nil
+	}
+	geo := record.fields()
+	if len(geo) == 0 {
+		monitoringNotFound.Inc()
+		return event, nil
+	}
+	if _, err := event.Fields.Put(geoField, geo); err != nil {
+		return event, err
+	}
+	monitoringEnriched.Inc()
+	return event, nil
+}
+
+func (e *Enricher) String() string {
+	return "geoip=[path=" + e.reloader.Path() + "]"
+}
+
+// cityRecord holds the fields of a GeoIP2 or GeoLite2 City database record
+// which are added to events.
+type cityRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Continent struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"continent"`
+	Country struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+	Subdivisions []struct {
+		ISOCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+}
+
+// fields returns the client.geo fields for the record, named like the
+// fields added by the Elasticsearch geoip processor.
+func (r *cityRecord) fields() common.MapStr {
+	fields := common.MapStr{}
+	setString := func(key, value string) {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	setString("continent_name", r.Continent.Names[language])
+	setString("country_iso_code", r.Country.ISOCode)
+	setString("country_name", r.Country.Names[language])
+	if len(r.Subdivisions) > 0 {
+		subdivision := r.Subdivisions[0]
+		if r.Country.ISOCode != "" && subdivision.ISOCode != "" {
+			setString("region_iso_code", r.Country.ISOCode+"-"+subdivision.ISOCode)
+		}
+		setString("region_name", subdivision.Names[language])
+	}
+	setString("city_name", r.City.Names[language])
+	if r.Location.Latitude != nil && r.Location.Longitude != nil {
+		fields["location"] = common.MapStr{
+			"lat": *r.Location.Latitude,
+			"lon": *r.Location.Longitude,
+		}
+	}
+	return fields
+}
@@ -0,0 +1,234 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package geoip
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/filewatch/filewatchtest"
+)
+
+var londonRecord = map[string]interface{}{
+	"city":      map[string]interface{}{"names": map[string]interface{}{"en": "London"}},
+	"continent": map[string]interface{}{"names": map[string]interface{}{"en": "Europe"}},
+	"country": map[string]interface{}{
+		"iso_code": "GB",
+		"names":    map[string]interface{}{"en": "United Kingdom"},
+	},
+	"location": map[string]interface{}{"latitude": 51.5142, "longitude": -0.0931},
+	"subdivisions": []interface{}{
+		map[string]interface{}{"iso_code": "ENG", "names": map[string]interface{}{"en": "England"}},
+	},
+}
+
+func TestEnricher(t *testing.T) {
+	path := writeDatabase(t, "", "81.2.69.0/24", londonRecord)
+	enricher, err := NewEnricher(path)
+	require.NoError(t, err)
+
+	event := enrich(t, enricher, "81.2.69.142")
+	assert.Equal(t, common.MapStr{
+		"continent_name":   "Europe",
+		"country_iso_code": "GB",
+		"country_name":     "United Kingdom",
+		"region_iso_code":  "GB-ENG",
+		"region_name":      "England",
+		"city_name":        "London",
+		"location":         common.MapStr{"lat": 51.5142, "lon": -0.0931},
+	}, event.Fields["client"].(common.MapStr)["geo"])
+
+	for _, ip := range []string{"81.2.70.1", "::1", "not-an-ip"} {
+		event := enrich(t, enricher, ip)
+		geo, _ := event.Fields.GetValue("client.geo")
+		assert.Nil(t, geo, ip)
+	}
+
+	// Events without a client IP are not modified.
+	event, err = enricher.Run(&beat.Event{Fields: common.MapStr{"processor": "transaction"}})
+	require.NoError(t, err)
+	assert.Equal(t, common.MapStr{"processor": "transaction"}, event.Fields)
+
+	// Existing client.geo fields are not overwritten.
+	event, err = enricher.Run(&beat.Event{Fields: common.MapStr{
+		"client": common.MapStr{"ip": "81.2.69.142", "geo": common.MapStr{"city_name": "Paris"}},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, common.MapStr{"city_name": "Paris"}, event.Fields["client"].(common.MapStr)["geo"])
+}
+
+func TestEnricherWatch(t *testing.T) {
+	path := writeDatabase(t, "", "81.2.69.0/24", londonRecord)
+	enricher, err := NewEnricher(path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go enricher.Watch(ctx, 10*time.Millisecond)
+
+	// An invalid database is ignored, keeping the previous database.
+	require.NoError(t, ioutil.WriteFile(path, []byte("invalid"), 0644))
+	bumpModTime(t, path, time.Hour)
+	time.Sleep(50 * time.Millisecond)
+	geo, _ := enrich(t, enricher, "81.2.69.142").Fields.GetValue("client.geo.city_name")
+	assert.Equal(t, "London", geo)
+
+	writeDatabase(t, path, "81.2.69.0/24", map[string]interface{}{
+		"city": map[string]interface{}{"names": map[string]interface{}{"en": "Croydon"}},
+	})
+	bumpModTime(t, path, 2*time.Hour)
+	assert.Eventually(t, func() bool {
+		geo, _ := enrich(t, enricher, "81.2.69.142").Fields.GetValue("client.geo.city_name")
+		return geo == "Croydon"
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestNewEnricherInvalidDatabase(t *testing.T) {
+	_, err := NewEnricher(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "invalid.mmdb")
+	require.NoError(t, ioutil.WriteFile(path, []byte("invalid"), 0644))
+	_, err = NewEnricher(path)
+	assert.Error(t, err)
+}
+
+func enrich(t testing.TB, enricher *Enricher, ip string) *beat.Event {
+	event, err := enricher.Run(&beat.Event{Fields: common.MapStr{
+		"client": common.MapStr{"ip": ip},
+	}})
+	require.NoError(t, err)
+	return event
+}
+
+func bumpModTime(t testing.TB, path string, d time.Duration) {
+	modTime := time.Now().Add(d)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// writeDatabase writes an IPv4 MaxMind database holding record for the
+// network cidr, returning its path. If path is empty, a temporary file
+// is created.
+func writeDatabase(t testing.TB, path, cidr string, record map[string]interface{}) string {
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	require.NoError(t, err)
+	ip := network.IP.To4()
+	prefixLen, _ := network.Mask.Size()
+
+	// The search tree holds one node per bit of the network prefix. Each
+	// node has a 24-bit record for each bit value: records on the path
+	// to the network point to the next node, or to the data section for
+	// the last node, and all other records point to "not found", which
+	// is the node count.
+	nodeCount := uint32(prefixLen)
+	var buf bytes.Buffer
+	for i := 0; i < prefixLen; i++ {
+		next := uint32(i + 1)
+		if next == nodeCount {
+			// Pointers to data are offset by the node count,
+			// and the 16 byte data section separator.
+			next = nodeCount + 16
+		}
+		records := [2]uint32{nodeCount, nodeCount}
+		records[ip[i/8]>>(7-uint(i%8))&1] = next
+		for _, record := range records {
+			buf.Write([]byte{byte(record >> 16), byte(record >> 8), byte(record)})
+		}
+	}
+	buf.Write(make([]byte, 16))
+	encodeValue(&buf, record)
+	buf.WriteString("\xab\xcd\xefMaxMind.com")
+	encodeValue(&buf, map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint32(time.Now().Unix()),
+		"database_type":               "GeoLite2-City",
+		"description":                 map[string]interface{}{"en": "test"},
+		"ip_version":                  uint16(4),
+		"languages":                   []interface{}{"en"},
+		"node_count":                  nodeCount,
+		"record_size":                 uint16(24),
+	})
+	filewatchtest.WriteFile(t, path, buf.Bytes())
+	return path
+}
+
+// encodeValue encodes v in the MaxMind DB data section format. Only the
+// types and sizes required by the tests are supported.
+func encodeValue(buf *bytes.Buffer, v interface{}) {
+	const (
+		typeString = 2
+		typeDouble = 3
+		typeUint16 = 5
+		typeUint32 = 6
+		typeMap    = 7
+		typeArray  = 11
+	)
+	control := func(typ, size int) {
+		if typ > 7 {
+			// Extended types are encoded in the following byte.
+			buf.Write([]byte{byte(size), byte(typ - 7)})
+			return
+		}
+		buf.WriteByte(byte(typ<<5 | size))
+	}
+	switch v := v.(type) {
+	case string:
+		control(typeString, len(v))
+		buf.WriteString(v)
+	case float64:
+		control(typeDouble, 8)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(v))
+	case uint16:
+		control(typeUint16, 2)
+		binary.Write(buf, binary.BigEndian, v)
+	case uint32:
+		control(typeUint32, 4)
+		binary.Write(buf, binary.BigEndian, v)
+	case map[string]interface{}:
+		control(typeMap, len(v))
+		for key, value := range v {
+			encodeValue(buf, key)
+			encodeValue(buf, value)
+		}
+	case []interface{}:
+		control(typeArray, len(v))
+		for _, value := range v {
+			encodeValue(buf, value)
+		}
+	default:
+		panic("unsupported type")
+	}
+}
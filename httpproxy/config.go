@@ -0,0 +1,106 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package httpproxy provides configuration of the proxies used for
+// outgoing HTTP requests, per destination.
+package httpproxy
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Config holds the proxy configuration of HTTP requests to a destination.
+type Config struct {
+	// URL holds the URL of the proxy, with the scheme http, https, or
+	// socks5. If the scheme is omitted, http is assumed. If URL is empty,
+	// the proxy is taken from the HTTP_PROXY and HTTPS_PROXY environment
+	// variables.
+	URL string `config:"proxy_url"`
+
+	// Disable disables the proxy, including any proxy taken from the
+	// environment.
+	Disable bool `config:"proxy_disable"`
+
+	// NoProxy holds the hosts which are connected to directly, in the
+	// format of the NO_PROXY environment variable: host names, domain
+	// names (matching subdomains), IP addresses, or CIDR ranges, each
+	// optionally with a port. If non-empty, NoProxy takes precedence
+	// over the NO_PROXY environment variable.
+	NoProxy []string `config:"no_proxy"`
+}
+
+// Validate validates the proxy URL.
+func (c *Config) Validate() error {
+	_, err := parseURL(c.URL)
+	return err
+}
+
+// ProxyFunc returns a function for use as http.Transport.Proxy, or nil if
+// the proxy is disabled.
+func (c *Config) ProxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	if c.Disable {
+		return nil, nil
+	}
+	u, err := parseURL(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.NoProxy) == 0 {
+		if u == nil {
+			return http.ProxyFromEnvironment, nil
+		}
+		return http.ProxyURL(u), nil
+	}
+
+	cfg := httpproxy.FromEnvironment()
+	if u != nil {
+		cfg.HTTPProxy = u.String()
+		cfg.HTTPSProxy = u.String()
+	}
+	cfg.NoProxy = strings.Join(c.NoProxy, ",")
+	proxy := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+func parseURL(rawurl string) (*url.URL, error) {
+	if rawurl == "" {
+		return nil, nil
+	}
+	if !strings.Contains(rawurl, "://") {
+		rawurl = "http://" + rawurl
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proxy URL")
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.Errorf("unsupported proxy URL scheme %q, expected http, https, or socks5", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("invalid proxy URL: missing host")
+	}
+	return u, nil
+}
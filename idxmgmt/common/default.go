@@ -32,6 +32,16 @@ func ConditionalOnboardingIndex() map[string]interface{} {
 	return Condition("onboarding", APMPrefix+"-onboarding-%{+yyyy.MM.dd}")
 }
 
+// ConditionalUsageIndex returns the condition routing service usage
+// documents, which have the metricset.name "service_usage", to their own
+// index rather than to the metric index.
+func ConditionalUsageIndex() map[string]interface{} {
+	return map[string]interface{}{
+		"index": APMPrefix + "-usage-%{+yyyy.MM.dd}",
+		"when":  map[string]interface{}{"equals": map[string]interface{}{"metricset.name": "service_usage"}},
+	}
+}
+
 func Condition(event string, index string) map[string]interface{} {
 	return map[string]interface{}{
 		"index": index,
@@ -169,8 +169,9 @@ func validate(c *Config) error {
 		}
 		if !c.Setup.RequirePolicy {
 			// `require_policy=false` indicates that policies are set up outside
-			// the APM Server, therefore do not throw an error here.
-			return nil
+			// the APM Server, therefore do not throw an error here, but keep
+			// validating the remaining mappings.
+			continue
 		}
 		if _, ok := c.Setup.Policies[m.PolicyName]; !ok {
 			return errors.Errorf("policy '%s' not configured for ILM setup, "+
@@ -263,6 +264,7 @@ func (c *Config) conditionalIndices() []map[string]interface{} {
 	conditions := []map[string]interface{}{
 		common.ConditionalOnboardingIndex(),
 		common.ConditionalSourcemapIndex(),
+		common.ConditionalUsageIndex(),
 	}
 	for _, m := range c.Setup.Mappings {
 		conditions = append(conditions, common.Condition(m.EventType, m.Index))
@@ -191,6 +191,9 @@ func TestConfig_Invalid(t *testing.T) {
 		{name: "invalid event_type",
 			cfg:    `{"setup":{"mapping":[{"event_type": "xyz", "policy_name": "rollover30Days"}]}}`,
 			errMsg: "event_type 'xyz' not supported"},
+		{name: "invalid event_type without required policy",
+			cfg:    `{"setup":{"require_policy":false,"mapping":[{"event_type":"span","policy_name":"custom"},{"event_type":"xyz","policy_name":"custom"}]}}`,
+			errMsg: "event_type 'xyz' not supported"},
 		{name: "invalid policy",
 			cfg:    `{"setup":{"mapping":[{"event_type":"span","policy_name":"xyz"}]}}`,
 			errMsg: "policy 'xyz' not configured"},
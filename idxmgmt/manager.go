@@ -94,17 +94,24 @@ func (m *manager) Setup(loadTemplate, loadILM libidxmgmt.LoadMode) error {
 	m.supporter.templateConfig.Enabled = templateFeature.enabled
 	m.supporter.templateConfig.Overwrite = templateFeature.overwrite
 
-	//(1) load general apm template
-	if err := m.loadTemplate(templateFeature, ilmFeature); err != nil {
+	//(1) load general apm template, overwriting it if it was installed by an
+	//    older version of APM Server
+	upgrade, err := m.prepareUpgrade(templateFeature, ilmFeature)
+	if err != nil {
+		log.Warnf("Failed to check for index template upgrade: %s", err)
+	}
+	if err := m.loadTemplate(templateFeature, upgrade != nil); err != nil {
 		return err
 	}
 
 	if !ilmFeature.load {
+		if upgrade != nil {
+			return upgrade.finish(m)
+		}
 		return nil
 	}
 
 	policiesLoaded := make(map[string]bool)
-	var err error
 	for _, ilmSupporter := range m.supporter.ilmSupporters {
 		//(2) load event type policies, respecting ILM settings
 		if err := m.loadPolicy(ilmFeature, ilmSupporter, policiesLoaded); err != nil {
@@ -123,6 +130,14 @@ func (m *manager) Setup(loadTemplate, loadILM libidxmgmt.LoadMode) error {
 		}
 	}
 
+	//(5) roll over write aliases which existed prior to an upgrade, so the
+	//    upgraded template applies to new indices
+	if upgrade != nil {
+		if err := upgrade.finish(m); err != nil {
+			return err
+		}
+	}
+
 	log.Info("Finished index management setup.")
 	return nil
 }
@@ -191,7 +206,7 @@ func (m *manager) ilmFeature(loadMode libidxmgmt.LoadMode) feature {
 	return f
 }
 
-func (m *manager) loadTemplate(templateFeature, ilmFeature feature) error {
+func (m *manager) loadTemplate(templateFeature feature, upgrade bool) error {
 	if !templateFeature.load {
 		return nil
 	}
@@ -204,7 +219,11 @@ func (m *manager) loadTemplate(templateFeature, ilmFeature feature) error {
 		m.supporter.templateConfig.Pattern = m.supporter.templateConfig.Name + "*"
 		m.supporter.log.Infof("Set setup.template.pattern to '%s'.", m.supporter.templateConfig.Pattern)
 	}
-	if err := m.clientHandler.Load(m.supporter.templateConfig, m.supporter.info,
+	templateConfig := m.supporter.templateConfig
+	if upgrade {
+		templateConfig.Overwrite = true
+	}
+	if err := m.clientHandler.Load(templateConfig, m.supporter.info,
 		m.assets.Fields(m.supporter.info.Beat), m.supporter.migration); err != nil {
 		return fmt.Errorf("error loading Elasticsearch template: %+v", err)
 	}
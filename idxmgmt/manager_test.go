@@ -18,6 +18,7 @@
 package idxmgmt
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -425,6 +426,188 @@ func TestManager_SetupILM(t *testing.T) {
 	}
 }
 
+func TestManager_SetupTemplateUpgrade(t *testing.T) {
+	fields := []byte("apm-server fields")
+	writeIndex := existingILMAlias + "-000001"
+	upgradeRequests := []string{
+		"GET /_template/custom",
+		"GET /_alias/" + existingILMAlias,
+		"GET /" + writeIndex + "/_mapping",
+	}
+
+	for name, tc := range map[string]struct {
+		cfg               common.MapStr
+		installedVersion  string
+		writeIndexVersion string
+		rolledOver        bool
+		missing           bool
+
+		overwrittenTemplate bool
+		requests            []string
+	}{
+		"OlderTemplate": {
+			installedVersion:    "6.8.0",
+			writeIndexVersion:   "6.8.0",
+			overwrittenTemplate: true,
+			requests:            append(upgradeRequests, "POST /"+existingILMAlias+"/_rollover/"+existingILMAlias+"-000002"),
+		},
+		"OlderTemplate rolled over concurrently": {
+			installedVersion:    "6.8.0",
+			writeIndexVersion:   "6.8.0",
+			rolledOver:          true,
+			overwrittenTemplate: true,
+			requests:            append(upgradeRequests, "POST /"+existingILMAlias+"/_rollover/"+existingILMAlias+"-000002"),
+		},
+		"OlderTemplate write index up to date": {
+			installedVersion:    "6.8.0",
+			writeIndexVersion:   info.Version,
+			overwrittenTemplate: true,
+			requests:            upgradeRequests,
+		},
+		"OlderTemplate ILM disabled": {
+			cfg:                 common.MapStr{"apm-server.ilm.enabled": false},
+			installedVersion:    "6.8.0",
+			overwrittenTemplate: true,
+			requests:            []string{"GET /_template/custom"},
+		},
+		"CurrentTemplate": {
+			installedVersion: info.Version,
+			requests:         []string{"GET /_template/custom"},
+		},
+		"MissingTemplate": {
+			missing:  true,
+			requests: []string{"GET /_template/custom"},
+		},
+		"UpgradeDisabled": {
+			cfg:              common.MapStr{"apm-server.template_upgrade.enabled": false},
+			installedVersion: "6.8.0",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := common.MapStr{"apm-server.template_upgrade.enabled": true}
+			cfg.DeepUpdate(tc.cfg)
+			support := defaultSupporter(t, cfg)
+			client := &mockESClient{
+				templateVersion:   tc.installedVersion,
+				writeIndex:        writeIndex,
+				writeIndexVersion: tc.writeIndexVersion,
+				rolledOver:        tc.rolledOver,
+				missing:           tc.missing,
+			}
+			support.newESClient = func() (esClient, error) { return client, nil }
+
+			clientHandler := newMockClientHandler("8.0.0")
+			m := support.Manager(clientHandler, libidxmgmt.BeatsAssets(fields))
+			require.NoError(t, m.Setup(libidxmgmt.LoadModeEnabled, libidxmgmt.LoadModeEnabled))
+
+			assert.Equal(t, tc.overwrittenTemplate, contains(clientHandler.overwrittenTemplates, "custom"), "overwritten template")
+			assert.Equal(t, tc.requests, client.requests)
+			assert.Equal(t, len(tc.requests) > 0, client.closed, "client closed")
+		})
+	}
+}
+
+func TestManager_SetupTemplateUpgradeVersionedName(t *testing.T) {
+	// The default template name embeds the server version, so older
+	// versions installed templates under other names.
+	cfg, err := common.NewConfigFrom(common.MapStr{
+		"output.elasticsearch.enabled":        true,
+		"apm-server.template_upgrade.enabled": true,
+	})
+	require.NoError(t, err)
+	sup, err := MakeDefaultSupporter(nil, info, cfg)
+	require.NoError(t, err)
+	support := sup.(*supporter)
+
+	writeIndex := existingILMAlias + "-000001"
+	client := &mockESClient{
+		templates: map[string]string{
+			"apm-6.7.0":      "6.7.0",
+			"apm-6.8.0":      "6.8.0",
+			"apm-6.8.0-span": "6.8.0",
+			"apm-custom":     "",
+		},
+		writeIndex:        writeIndex,
+		writeIndexVersion: "6.8.0",
+	}
+	support.newESClient = func() (esClient, error) { return client, nil }
+
+	clientHandler := newMockClientHandler("8.0.0")
+	m := support.Manager(clientHandler, libidxmgmt.BeatsAssets([]byte("apm-server fields")))
+	require.NoError(t, m.Setup(libidxmgmt.LoadModeEnabled, libidxmgmt.LoadModeEnabled))
+
+	assert.Equal(t, []string{
+		"GET /_template/apm-*",
+		"GET /_alias/" + existingILMAlias,
+		"GET /" + writeIndex + "/_mapping",
+		"POST /" + existingILMAlias + "/_rollover/" + existingILMAlias + "-000002",
+	}, client.requests)
+	assert.True(t, client.closed)
+
+	// Once the current version's template is installed, there is nothing to upgrade.
+	client.templates["apm-"+info.Version] = info.Version
+	client.requests = nil
+	require.NoError(t, m.Setup(libidxmgmt.LoadModeEnabled, libidxmgmt.LoadModeEnabled))
+	assert.Equal(t, []string{"GET /_template/apm-*"}, client.requests)
+}
+
+func TestManager_SetupTemplateUpgradeDefault(t *testing.T) {
+	support := defaultSupporter(t, common.MapStr{})
+	assert.False(t, support.upgrade)
+}
+
+type mockESClient struct {
+	templates         map[string]string // template name to version
+	templateVersion   string
+	writeIndex        string
+	writeIndexVersion string
+	rolledOver        bool
+	missing           bool
+
+	requests []string
+	closed   bool
+}
+
+func (c *mockESClient) Request(method, path string, _ string, _ map[string]string, _ interface{}) (int, []byte, error) {
+	c.requests = append(c.requests, method+" "+path)
+	switch {
+	case method == "POST" && c.rolledOver:
+		body := []byte(`{"error":{"type":"resource_already_exists_exception"}}`)
+		return 400, body, fmt.Errorf("400 Bad Request: %s", body)
+	case method != "GET":
+		return 200, []byte(`{"acknowledged":true}`), nil
+	case strings.HasPrefix(path, "/_alias/"):
+		alias := strings.TrimPrefix(path, "/_alias/")
+		return 200, []byte(fmt.Sprintf(`{%q:{"aliases":{%q:{"is_write_index":true}}}}`, c.writeIndex, alias)), nil
+	case path == "/"+c.writeIndex+"/_mapping":
+		return 200, []byte(fmt.Sprintf(`{%q:{"mappings":{"_meta":{"version":%q}}}}`, c.writeIndex, c.writeIndexVersion)), nil
+	case c.missing:
+		return 404, []byte(`{}`), errors.New("404 Not Found: {}")
+	case c.templates != nil:
+		templates := make(map[string]interface{}, len(c.templates))
+		for name, version := range c.templates {
+			templates[name] = map[string]interface{}{"mappings": map[string]interface{}{"_meta": map[string]string{"version": version}}}
+		}
+		body, err := json.Marshal(templates)
+		return 200, body, err
+	}
+	return 200, []byte(fmt.Sprintf(`{"custom":{"mappings":{"_meta":{"version":%q}}}}`, c.templateVersion)), nil
+}
+
+func (c *mockESClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, item := range s {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
 type mockClientHandler struct {
 	// mockClientHandler loads templates, ilm templates, policies and aliases
 	// The handler generally treats them as non-existing in Elasticsearch.
@@ -439,6 +622,7 @@ type mockClientHandler struct {
 	templates, templatesILMEnabled int
 	templatesILMOrder              int
 	templateForceLoad              bool
+	overwrittenTemplates           []string
 
 	esVersion *common.Version
 }
@@ -471,6 +655,9 @@ func (h *mockClientHandler) Load(config template.TemplateConfig, _ beat.Info, fi
 		return errors.New("unexpected template order")
 	}
 	h.templateForceLoad = config.Overwrite
+	if config.Overwrite {
+		h.overwrittenTemplates = append(h.overwrittenTemplates, config.Name)
+	}
 	return nil
 }
 
@@ -0,0 +1,95 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package idxmgmt
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	libilm "github.com/elastic/beats/v7/libbeat/idxmgmt/ilm"
+	"github.com/elastic/beats/v7/libbeat/outputs"
+)
+
+const (
+	// MetaIndexField holds the name of the @metadata field in which the
+	// index, write alias, or data stream selected for events is stored.
+	MetaIndexField = "target_index"
+
+	// MetaEventTypeField holds the name of the @metadata field in which
+	// the processor.event of events is stored.
+	MetaEventTypeField = "event_type"
+
+	eventTypeField = "processor.event"
+)
+
+// NewMetadataProcessor returns a beat.Processor which stores the index
+// which the Elasticsearch output would write events to, along with their
+// event type, in their @metadata, for outputs which do not select indices,
+// such as Logstash.
+//
+// As the ILM state cannot be checked without an Elasticsearch output, ILM
+// write aliases are selected when `apm-server.ilm.enabled` is "auto" and
+// indices are not customized, assuming Elasticsearch supports ILM.
+func NewMetadataProcessor(info beat.Info, configRoot *common.Config) (beat.Processor, error) {
+	cfg, err := NewIndexManagementConfig(info, configRoot)
+	if err != nil {
+		return nil, err
+	}
+	var selector outputs.IndexSelector
+	if cfg.DataStreams {
+		selector, err = dataStreamsSupporter{}.BuildSelector(nil)
+	} else {
+		s := &supporter{ilmConfig: cfg.ILM, unmanagedIdxConfig: cfg.unmanagedIdxCfg}
+		switch cfg.ILM.Mode {
+		case libilm.ModeEnabled:
+			s.st.ilmEnabled.Store(true)
+		case libilm.ModeAuto:
+			s.st.ilmEnabled.Store(!cfg.unmanagedIdxCfg.Customized())
+		}
+		s.st.isSet.Store(true)
+		selector, err = s.BuildSelector(nil)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "building index selector failed")
+	}
+	return &metadataProcessor{selector: selector}, nil
+}
+
+type metadataProcessor struct {
+	selector outputs.IndexSelector
+}
+
+// Run stores the selected index and event type of event in its @metadata.
+// If no index can be selected for event, only its event type is stored.
+func (p *metadataProcessor) Run(event *beat.Event) (*beat.Event, error) {
+	if event.Meta == nil {
+		event.Meta = common.MapStr{}
+	}
+	if index, err := p.selector.Select(event); err == nil && index != "" {
+		event.Meta[MetaIndexField] = index
+	}
+	if eventType, _ := event.Fields.GetValue(eventTypeField); eventType != nil {
+		event.Meta[MetaEventTypeField] = eventType
+	}
+	return event, nil
+}
+
+func (p *metadataProcessor) String() string {
+	return "index_metadata"
+}
@@ -0,0 +1,84 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package idxmgmt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestMetadataProcessor(t *testing.T) {
+	for name, test := range map[string]struct {
+		cfg    common.MapStr
+		fields common.MapStr
+		meta   common.MapStr
+		expect common.MapStr
+	}{
+		"ILMAuto": {
+			fields: common.MapStr{"processor": common.MapStr{"event": "transaction"}},
+			expect: common.MapStr{MetaIndexField: "apm-7.0.0-transaction", MetaEventTypeField: "transaction"},
+		},
+		"ILMDisabled": {
+			cfg:    common.MapStr{"apm-server.ilm.enabled": false},
+			fields: common.MapStr{"processor": common.MapStr{"event": "span"}},
+			expect: common.MapStr{MetaIndexField: fmt.Sprintf("apm-7.0.0-span-%s", day), MetaEventTypeField: "span"},
+		},
+		"MetaAlias": {
+			fields: common.MapStr{"processor": common.MapStr{"event": "error"}},
+			meta:   common.MapStr{"alias": "apm-7.0.0-error-opbeans"},
+			expect: common.MapStr{
+				"alias":            "apm-7.0.0-error-opbeans",
+				MetaIndexField:     "apm-7.0.0-error-opbeans",
+				MetaEventTypeField: "error",
+			},
+		},
+		"DataStreams": {
+			cfg: common.MapStr{"apm-server.data_streams.enabled": true},
+			fields: common.MapStr{
+				"processor":   common.MapStr{"event": "metric"},
+				"data_stream": common.MapStr{"type": "metrics", "dataset": "apm.internal", "namespace": "default"},
+			},
+			expect: common.MapStr{MetaIndexField: "metrics-apm.internal-default", MetaEventTypeField: "metric"},
+		},
+		"NoEventType": {
+			cfg:    common.MapStr{"apm-server.data_streams.enabled": true},
+			fields: common.MapStr{},
+			expect: common.MapStr{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := common.MapStr{"output.logstash.hosts": []string{"localhost:5044"}}
+			c.DeepUpdate(test.cfg)
+			cfg, err := common.NewConfigFrom(c)
+			require.NoError(t, err)
+			processor, err := NewMetadataProcessor(info, cfg)
+			require.NoError(t, err)
+
+			test.fields.DeepUpdate(common.MapStr{"observer": common.MapStr{"version": "7.0.0"}})
+			event, err := processor.Run(&beat.Event{Timestamp: today, Fields: test.fields, Meta: test.meta})
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, event.Meta)
+		})
+	}
+}
@@ -55,6 +55,11 @@ type supporter struct {
 	migration          bool
 	ilmSupporters      []libilm.Supporter
 
+	// upgrade reports whether index templates installed by older versions
+	// of APM Server should be upgraded, using a client created by newESClient.
+	upgrade     bool
+	newESClient func() (esClient, error)
+
 	st indexState
 }
 
@@ -97,7 +102,7 @@ func newSupporter(log *logp.Logger, info beat.Info, cfg *IndexManagementConfig)
 		return nil, err
 	}
 
-	return &supporter{
+	s := &supporter{
 		log:                log,
 		info:               info,
 		templateConfig:     cfg.Template,
@@ -106,7 +111,12 @@ func newSupporter(log *logp.Logger, info beat.Info, cfg *IndexManagementConfig)
 		migration:          false,
 		st:                 st,
 		ilmSupporters:      ilmSupporters,
-	}, nil
+	}
+	if cfg.TemplateUpgrade && cfg.Output.Name() == esKey {
+		s.upgrade = true
+		s.newESClient = newESClientFunc(cfg.Output.Config())
+	}
+	return s, nil
 }
 
 // Enabled indicates whether or not a callback should be registered to take care of setup.
@@ -37,6 +37,10 @@ type IndexManagementConfig struct {
 	ILM         ilm.Config
 	Output      common.ConfigNamespace
 
+	// TemplateUpgrade reports whether index templates installed by older
+	// versions of APM Server should be upgraded when connecting to Elasticsearch.
+	TemplateUpgrade bool
+
 	unmanagedIdxCfg                 unmanaged.Config
 	registerIngestPipelineSpecified bool
 	setupTemplateSpecified          bool
@@ -82,6 +86,7 @@ func NewIndexManagementConfig(info beat.Info, configRoot *common.Config) (*Index
 		DataStreams            *common.Config         `config:"apm-server.data_streams"`
 		RegisterIngestPipeline *common.Config         `config:"apm-server.register.ingest.pipeline"`
 		ILM                    *common.Config         `config:"apm-server.ilm"`
+		TemplateUpgrade        *common.Config         `config:"apm-server.template_upgrade"`
 		Template               *common.Config         `config:"setup.template"`
 		Output                 common.ConfigNamespace `config:"output"`
 	}
@@ -113,6 +118,15 @@ func NewIndexManagementConfig(info beat.Info, configRoot *common.Config) (*Index
 		return nil, errors.Wrap(err, "creating ILM config fails")
 	}
 
+	var templateUpgrade struct {
+		Enabled bool `config:"enabled"`
+	}
+	if cfg.TemplateUpgrade != nil {
+		if err := cfg.TemplateUpgrade.Unpack(&templateUpgrade); err != nil {
+			return nil, errors.Wrap(err, "unpacking template upgrade config failed")
+		}
+	}
+
 	var unmanagedIdxCfg unmanaged.Config
 	if cfg.Output.Name() == esKey {
 		if err := cfg.Output.Config().Unpack(&unmanagedIdxCfg); err != nil {
@@ -129,6 +143,9 @@ func NewIndexManagementConfig(info beat.Info, configRoot *common.Config) (*Index
 		Template:    templateConfig,
 		ILM:         ilmConfig,
 
+		// Template upgrades are disabled by default.
+		TemplateUpgrade: templateUpgrade.Enabled,
+
 		unmanagedIdxCfg:                 unmanagedIdxCfg,
 		registerIngestPipelineSpecified: cfg.RegisterIngestPipeline != nil,
 		setupTemplateSpecified:          setupTemplateSpecified,
@@ -143,6 +143,11 @@ func TestIndexSupport_BuildSelector(t *testing.T) {
 			withIlm: "apm-7.0.0-metric",
 			fields:  common.MapStr{"processor.event": "metric"},
 		},
+		"DefaultUsage": {
+			noIlm:   fmt.Sprintf("apm-7.0.0-usage-%s", day),
+			withIlm: fmt.Sprintf("apm-7.0.0-usage-%s", day),
+			fields:  common.MapStr{"processor.event": "metric", "metricset.name": "service_usage"},
+		},
 		"DefaultSourcemap": {
 			noIlm:   "apm-7.0.0-sourcemap",
 			withIlm: "apm-7.0.0-sourcemap",
@@ -0,0 +1,324 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package idxmgmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
+	"github.com/elastic/beats/v7/libbeat/template"
+
+	idxcommon "github.com/elastic/apm-server/idxmgmt/common"
+)
+
+// esClient is the subset of the Elasticsearch client used for upgrading
+// index templates installed by older versions of APM Server.
+type esClient interface {
+	Request(method, path string, pipeline string, params map[string]string, body interface{}) (int, []byte, error)
+	Close() error
+}
+
+func newESClientFunc(cfg *common.Config) func() (esClient, error) {
+	return func() (esClient, error) {
+		return eslegclient.NewConnectedClient(cfg)
+	}
+}
+
+// templateUpgrade holds the state of an upgrade of index templates installed
+// by an older version of APM Server.
+type templateUpgrade struct {
+	client           esClient
+	installedVersion *common.Version
+	serverVersion    *common.Version
+
+	// aliases holds the ILM write aliases which existed prior to the upgrade,
+	// which must be rolled over for new indices to use the upgraded templates.
+	aliases []string
+}
+
+// prepareUpgrade checks the version stamped in the installed APM index template,
+// returning a non-nil templateUpgrade if it is older than the server version.
+//
+// Upgrades are only performed for legacy index templates generated from the
+// APM Server fields, as only these are stamped with the server version.
+func (m *manager) prepareUpgrade(templateFeature, ilmFeature feature) (*templateUpgrade, error) {
+	cfg := m.supporter.templateConfig
+	if !m.supporter.upgrade || !templateFeature.load || cfg.JSON.Enabled || cfg.Type != template.IndexTemplateLegacy {
+		return nil, nil
+	}
+	if cfg.Name == "" && cfg.Pattern == "" {
+		cfg.Name = idxcommon.APMPrefix
+	}
+	info := m.supporter.info
+	tmpl, err := template.New(info.Version, info.IndexPrefix, info.ElasticLicensed, common.Version{}, cfg, m.supporter.migration)
+	if err != nil {
+		return nil, err
+	}
+	serverVersion, err := common.NewVersion(info.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := m.supporter.newESClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "error connecting to Elasticsearch for checking the installed index template")
+	}
+	installedName, installedVersion, err := installedTemplateVersion(client, tmpl.GetName(), info.Version)
+	if err != nil || installedVersion == nil || !installedVersion.LessThan(serverVersion) {
+		client.Close()
+		return nil, err
+	}
+	m.supporter.log.Infof(
+		"Index template %s was installed by APM Server %s, upgrading to %s.",
+		installedName, installedVersion, serverVersion,
+	)
+
+	upgrade := &templateUpgrade{client: client, installedVersion: installedVersion, serverVersion: serverVersion}
+	if ilmFeature.enabled {
+		for _, ilmSupporter := range m.supporter.ilmSupporters {
+			alias := ilmSupporter.Alias().Name
+			exists, err := m.clientHandler.HasAlias(alias)
+			if err != nil {
+				client.Close()
+				return nil, err
+			}
+			if exists {
+				upgrade.aliases = append(upgrade.aliases, alias)
+			}
+		}
+	}
+	return upgrade, nil
+}
+
+// finish rolls over the write aliases which existed prior to the upgrade,
+// so new data is written to indices created with the upgraded templates.
+// Existing indices are left untouched.
+func (u *templateUpgrade) finish(m *manager) error {
+	defer u.client.Close()
+	for _, alias := range u.aliases {
+		if err := u.rollover(m, alias); err != nil {
+			return errors.Wrapf(err, "error rolling over write alias %s", alias)
+		}
+	}
+	m.supporter.log.Infof("Finished upgrading index templates installed by APM Server %s.", u.installedVersion)
+	return nil
+}
+
+// rollover rolls over alias, unless its write index was already created with
+// the upgraded template, e.g. by another APM Server performing the upgrade.
+//
+// The new index is named after the checked write index, so if several APM
+// Servers upgrade concurrently, only one of them rolls over the alias; the
+// others fail to create the existing index, which is not treated as an error.
+func (u *templateUpgrade) rollover(m *manager, alias string) error {
+	writeIndex, err := u.writeIndex(alias)
+	if err != nil {
+		return err
+	}
+	version, err := u.indexVersion(writeIndex)
+	if err != nil {
+		return err
+	}
+	if !version.LessThan(u.serverVersion) {
+		m.supporter.log.Infof("Write index %s of alias %s is up to date, not rolling over.", writeIndex, alias)
+		return nil
+	}
+	newIndex, err := nextRolloverIndex(writeIndex)
+	if err != nil {
+		return err
+	}
+	status, body, err := u.client.Request(http.MethodPost, "/"+alias+"/_rollover/"+newIndex, "", nil, nil)
+	if status == http.StatusBadRequest && bytes.Contains(body, []byte("resource_already_exists_exception")) {
+		m.supporter.log.Infof("Write alias %s was already rolled over to %s.", alias, newIndex)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	m.supporter.log.Infof("Rolled over write alias %s to %s.", alias, newIndex)
+	return nil
+}
+
+// writeIndex returns the name of the write index of alias.
+func (u *templateUpgrade) writeIndex(alias string) (string, error) {
+	_, body, err := u.client.Request(http.MethodGet, "/_alias/"+alias, "", nil, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "error fetching alias")
+	}
+	var indices map[string]struct {
+		Aliases map[string]struct {
+			IsWriteIndex *bool `json:"is_write_index"`
+		} `json:"aliases"`
+	}
+	if err := json.Unmarshal(body, &indices); err != nil {
+		return "", errors.Wrap(err, "error decoding alias")
+	}
+	for index, info := range indices {
+		isWriteIndex := info.Aliases[alias].IsWriteIndex
+		// An alias pointing to a single index writes to it,
+		// unless it is explicitly not the write index.
+		if (isWriteIndex == nil && len(indices) == 1) || (isWriteIndex != nil && *isWriteIndex) {
+			return index, nil
+		}
+	}
+	return "", errors.New("no write index found")
+}
+
+// indexVersion returns the version stamped in the mappings of index.
+// Indices without a version are treated as having version 0.0.0.
+func (u *templateUpgrade) indexVersion(index string) (*common.Version, error) {
+	_, body, err := u.client.Request(http.MethodGet, "/"+index+"/_mapping", "", nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error fetching mappings of index %s", index)
+	}
+	version, err := decodeMetaVersion(body, index)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding mappings of index %s", index)
+	}
+	if version == nil {
+		return &common.Version{}, nil
+	}
+	return version, nil
+}
+
+// nextRolloverIndex returns the name of the index created by rolling over
+// an alias with the write index named index, incrementing its numeric
+// suffix as Elasticsearch does.
+func nextRolloverIndex(index string) (string, error) {
+	i := strings.LastIndexByte(index, '-')
+	if i < 0 {
+		return "", errors.Errorf("index name %s does not end with a number", index)
+	}
+	n, err := strconv.Atoi(index[i+1:])
+	if err != nil {
+		return "", errors.Errorf("index name %s does not end with a number", index)
+	}
+	return fmt.Sprintf("%s-%06d", index[:i], n+1), nil
+}
+
+// installedTemplateVersion returns the name of the newest legacy index
+// template installed under name, and the version stamped in its mappings,
+// or a nil version if there is none. Templates without a version are
+// treated as having version 0.0.0.
+//
+// If name embeds serverVersion, as the default "apm-%{[observer.version]}"
+// does, older versions installed their templates under names embedding
+// their own versions, so all templates matching name with any version in
+// place of serverVersion are considered.
+func installedTemplateVersion(client esClient, name, serverVersion string) (string, *common.Version, error) {
+	i := strings.Index(name, serverVersion)
+	if i < 0 {
+		status, body, err := client.Request(http.MethodGet, "/_template/"+name, "", nil, nil)
+		if status == http.StatusNotFound {
+			return "", nil, nil
+		} else if err != nil {
+			return "", nil, errors.Wrapf(err, "error fetching index template %s", name)
+		}
+		version, err := decodeMetaVersion(body, name)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "error decoding index template %s", name)
+		}
+		return name, version, nil
+	}
+
+	prefix, suffix := name[:i], name[i+len(serverVersion):]
+	pattern := prefix + "*" + suffix
+	status, body, err := client.Request(http.MethodGet, "/_template/"+pattern, "", nil, nil)
+	if status == http.StatusNotFound {
+		return "", nil, nil
+	} else if err != nil {
+		return "", nil, errors.Wrapf(err, "error fetching index templates %s", pattern)
+	}
+	versions, err := decodeMetaVersions(body)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "error decoding index templates %s", pattern)
+	}
+	var newestName string
+	var newestVersion *common.Version
+	for templateName, rawVersion := range versions {
+		// The pattern also matches templates with other suffixes,
+		// such as those of event types when ILM is enabled.
+		if !strings.HasPrefix(templateName, prefix) || !strings.HasSuffix(templateName, suffix) {
+			continue
+		}
+		embedded := templateName[len(prefix) : len(templateName)-len(suffix)]
+		if _, err := common.NewVersion(embedded); err != nil {
+			continue
+		}
+		version, err := parseMetaVersion(rawVersion)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "error decoding index template %s", templateName)
+		}
+		if newestVersion == nil || newestVersion.LessThan(version) {
+			newestName, newestVersion = templateName, version
+		}
+	}
+	return newestName, newestVersion, nil
+}
+
+// decodeMetaVersion decodes the version stamped in the mappings of the index
+// or template with the given name from body, a response holding mappings
+// keyed by name. If name is not in body, decodeMetaVersion returns nil.
+// Mappings without a version are treated as having version 0.0.0.
+func decodeMetaVersion(body []byte, name string) (*common.Version, error) {
+	versions, err := decodeMetaVersions(body)
+	if err != nil {
+		return nil, err
+	}
+	version, ok := versions[name]
+	if !ok {
+		return nil, nil
+	}
+	return parseMetaVersion(version)
+}
+
+// decodeMetaVersions decodes the versions stamped in the mappings of the
+// indices or templates in body, a response holding mappings keyed by name.
+func decodeMetaVersions(body []byte) (map[string]string, error) {
+	var mappings map[string]struct {
+		Mappings struct {
+			Meta struct {
+				Version string `json:"version"`
+			} `json:"_meta"`
+		} `json:"mappings"`
+	}
+	if err := json.Unmarshal(body, &mappings); err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string, len(mappings))
+	for name, m := range mappings {
+		versions[name] = m.Mappings.Meta.Version
+	}
+	return versions, nil
+}
+
+// parseMetaVersion parses a version stamped in mappings, treating
+// mappings without a version as having version 0.0.0.
+func parseMetaVersion(version string) (*common.Version, error) {
+	if version == "" {
+		return &common.Version{}, nil
+	}
+	return common.NewVersion(version)
+}
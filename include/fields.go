@@ -32,5 +32,5 @@ func init() {
 // AssetBuildFieldsFieldsYml returns asset data.
 // This is the base64 encoded gzipped contents of build/fields/fields.yml.
 func AssetBuildFieldsFieldsYml() string {
-	return "eJzs/XtTIzm2Lwz/359CLzvipehtJza3onieiTg00N3EqQtTUNOze3oC5EzZ1pApZUtKjPvE+e5PaC1JqUynKaAKV1U3s8+pNnamLkvS0rr+1n+RXw7fvz19+9P/jxxLIqQhLOOGmCnXZMxzRjKuWGryeY9wQ2ZUkwkTTFHDMjKaEzNl5OTonJRK/oelpvfdf5ER1SwjUsD3N0xpLgUZJq+SQfLdf5GznFHNyA3X3JCpMaU+2NyccDOtRkkqi02WU214uslSTYwkuppMmDYknVIxYfCVbXbMWZ7p5Lvv+uSazQ8IS/V3hBhucnZgH/iOkIzpVPHScCngK/Kje4e4tw++I4SQPhG0YAdk/X8ZXjBtaFGuww+E5OyG5QcklYq5bxT7veKKZQfEqMp/aeYlOyAZNf6LRs/rx9SwTds2mU2ZAIKxGyYMkYpPuLCETL5zb15YqnMND2XhPXZrFE0twcdKFnULPds1T2mez4lipWKaCcPFBDrygwvddS6dlpVKWej/dBy9gL+RKdVESD/anAQy9XCT3NC8YjDoMJhSllVuu3HNus7GXGkD77eGpVjK+E09qpKXLOeiHtd7R3dcOTKWitA8xxZ04teL3dKitBtgfWsw3OsPdvtb2xeD/YPB7sH2TrK/u/3remPJczpiuV662LiucmT3tfsK/7jEX67ZfCZV1rnoR5U2srCPbCJ9SsqVDvM5ooKMGKnsQTGS0CwjBTOUcDGWqqC2Efu9mx85n8oqz+BwplIYygURTNtlxAHBprb/O8xzXA9NqGJEG2mJRrUfaxjAiSfVVSbTa6auCBUZubre11eOLB1U/T9rtCxznsL41g7I2ljK/oiqtR5ZY+LGflMqmVUp/P5/m8QumNZ0wj5CbcNuTSdBf5SK5HLiSAK7xLXo9oQjDP5kn3Q/94gsDS/4H2E32t1zw9nMnhQuCIWn7RdMBfrY7rRRVWoqS8FcTjSZcTOVlSFU1IehMYYekWbKlGMvJMVFTqVIqWEiOg9G2kEUhJJpVVDRV4xmdJQzoquioGpOZHQO48NZVLnhZR7mrgm75doygimb1x0WIy5YRrgwkkgRnl5c0p9Znkvyi1R51lgsQycfOxfN3c8nQip2SUfyhh2Q4WBrp2sVX3Nt7NzcuzocAEMnhNF06mfc3nn/ijcW7rattX83NxidMOH3jrsIDqOvJkpW5QHZ6txdF1OG74e1c8fMMWJK6MguPbLMsZnZ02WZrbHX4ti1ScXcrgS1pzTP7bnskYwZ/CAVkSPN1I1dNNzE0m6+qbTrJxUx9JppUjCqK8UK+4BnOv6x9unVhIs0rzJGfmDU8gmYryYFnROaa0lUJezbrl+lE7gHYaLJ926qrkk9tQx1xGreDfvdjp/yXPsdiURSlRD29EgkkB1bND/lmpxNmYo5/ZSWJbP70k4Wzm+YKtwClgAi7NGxlEZIY1ffT/eAnGKHqZUg5BinDefZHtBePcLEbgniJJgRo2FL2XN9ePYGZBl3zzan5NacluWmnQxPWULq3RHz50wyTzxgzCCgED7G/cI1sbcxMVMlq8mU/F6xyrav59qwQpOcXzPyv+n4mvbIe5Zx3CGlkinTmouJXxb3uK7SqeXjr+VEG6qnBOdBzoHggWh4NGGrezJGok58WkYVz7PE87Hwc9d5X3bm7zz3i2fs5NYwkdlL3nbdIOXY7Qhcu+9CExcohFr2buUi4RowMpxPKuYd7cEZpLgQKMWEJu3ZKJW84RnrWbFGlyzlY54SfBvEJ66DoBco2+BHBTOKp3ZfBfn2ZbKXDMgLWmR7Oxs9kvMR/Ixf/2uPbm2z/fH+eHsw3h0MhiO6vbPDdtjuTrafvUpH+1vpaDh4mUadEZiXIVuDrUF/sNUf7JKt7YPh4GA4IP89GAwG5MPF0b8b1B7TKjeXQK8DMqa5ZgvLzsopK5ii+SXPFheduSX6zAvv+yQ8szxzzJlCfsK1O1cv+BguKrjN9EZ7C3Ar+6gCZEuvCNBUSW0XShuqLIMdVYZc4Q7i2RUcT3swu1dwn+7YhRgvEKiLLJ//LHwQ/HcrND+cHkFws5wM+R+8NwMJccQIcDu+ZOO6aWcL07b/rmLiTi4G9hxfKQsrrgnFp/A+Rclmwm8YCMZUuNfwaffzlOXluMotD7Ycxc06NGxmkvzo7gPChTZUpE5Qbl1o2nYMt5rdVE5KI7WUxkqqgNOEtrkmgrEM9d7ZlKfTxa7CxZDKwnZmlblo3qdjy4/8xQVTxRvNfyXHhgmSs7EhrCjNvHuJx1IurK5duFWs7sW8vGNZ/SVqOyI0n9G5JtrYfwPNrRKip34r43I7nRDftQJjUpNMBFEgULt+Fo+E62jE6kdANuLjxoaoV7K9MRqboqDp1Cqm3aRvt+Xp7y6JFSzBP9x11FyEjrHuJYNk0FfpVlOG1i0BujJSyEJWmpyDJHIPYfpQEFq/hgIMeXF4voGH2onGbpCpFIKBaeNUGKYEM+RMSSNTWcskL07PNoiSFdzNpWJjfss0qUTGUGqwsoCSuW3O8kypSCEVI4KZmVTXRJZMUSOVlbdDmyM2pfnYvkKJFbdyRmhWcMG1sSf7xkv3trVMFqgKUEOckQUnUhRS9EiaM6ry+Xf1eoxB+wojljlP56DlTBkIMXaayYMlNlEVoyBbk3tc2rkMYuTCIrkLCNskNM9lCjK/G+XCAjohOHzdEFTcGrvGXhyev90gFXSQz+s7TqOGF5YFT9Vpix6NTTrcHe69WiCEVBMq+B/AgJPui+tzCTKgb1+2VyQeTGSwaPbU8YOVYXRbMgubZrnw1lq7d9H8YRSdtPtJSrurX78+apzwNOcLavJR/N2devKhe98eZb/PqXYbmxtuzxkeKr/M7oA7Kd8r9qj/KjahKgOtyCo9Uuhe9DxqRCOO9mcuBc3JOJczolgqVaYbtpqLozPXKt6Z9TAXxma/sI9HI4OjrZkI2rB95vx/3pKSptfMvNAbCfSC5p3SMaiFrtC6aoXURqdejVegVTBtx+HUTE8lo6jQFAaTkHNZsKD4VRpVaMNUQda8yViqtdqUpNjYc0I3FNGaoMbj6352Jg5c2RELKj6YOCICuGNthyUmfpnrLuLxo9nGbSTfgb0/K11ZgrhWa9sCF3Z4/6kELgCYGtB44E37HY3V9BXSLDRpRT5crz5wAW89DTZXbG/T9xMs5nCQUIikWUY0K6gwPIV7hd0aJ2+yW9Q8eijeebuXDlKnkeSG2+nyP1htN7ITZQp0Vc1NRd1ynI7JXFYq9DGmebBE+7vGcuSJVPOefdSLRdrwPCdM6Eo52diZ6a3olDFt7PawJLUEG/M8D0yQlqWSpeLUsHz+CIsBzTLFtF6V1ginAA1Hbs+5ATjJLLCfYsQnlax0PsddDu+EZmeWXFoWDNwWJOcaDLinZz1C/c0uFaH2wrolWtr9kxDyPzXFg6Qay2p2fRWd+TH583CVuC+ukIxN+VcQbiLxNqvQmI7X7lXCyys7lKsEh3XVIxkrmcicYoJahRT1IMCGFYmitRSXPAsLNS2e5YVHyAvxmEdzwzrOfafy0tpHaFVbbKIxwB/sj2guDQ5Od+bdFkOW3b3s+zsLA8YDtAJ1y90p2F+yMI4Jk0nKzfxyReaVI6uxLF3hN1ZPYpGxtzFMKQwXTJjLVGarGOvFTPZzZgyzl2DGmg7sMJp1vXw+bw8bbd/D+tmc6IoW5W1k6wqdd09IKjMlhwVTPKVLBl8Jo+aXXMtVrdMRdklOz9/BQnWO/OjwzuGuavu7oS7dMUdU0KybsnCn3d9GM2HyspRcmGVjeS3FhJsqQ8Espwb+6BzV+v8hazk41Psvt5O94c7+9qBH1nJq1g7Izm6yO9h9Ndwn/3fxorcDf9oLrWW21kz1veAV/YQqnydhjzgzHIrhckwmiooqp4qbeSxBzUlqJTnQOyJJ6cgLSMH4iaeHKxSpU2bFAKd9jXMplZMmemDUm/Jat6mvHBxeTsrpXHP7wXtvU88zdTSEt9JE4SzgpeZo1ipA8pkw6WfbbQocSW2k6Gdp55qVUhuar+oEr59Bd8hmqdYy5bV/F6Ml3FRqAvzDBZHUOoNz0gWHXHAyjxi5FnImrIZIiZ0adCQV+fX0jDTmaI8CCOQ3VM3JjGdW5oMr33EKdO3Bx266vtoZ7Awew/YVm3ApVsk430OPH+Ob/b8vylvReFfEOd1YlzLOv1dsxLr3stWr/oi1kicUGaYMo8f+ADvvuLF5e8FHfnr49jB6bumk3EW7eagmIHbQzR8qJqS+POSqJazeY5Px8p4UaDzYmN/pWdAnvbyA8uWL07ObHXuiTs9u9jYW5cyCpqvgI28Oj7oH2HLzCGmCX7+gTqh//+MReTnY2YLICAzqZNkBObFqnkwNM+QFmDO47pH9/ojXN6vVHTbQKe9ERBcpOJPkX1VZMpVSzf5NpuyWZizlBc1JxifcgBfNipN2pBAWF9p0w8eOLeMSpBKaT1zYFJswlZDzKoVojBv3oAuqQ+8fjqEWJqbzcsqW3AaDQX8w6O+ewL/b/a3thRUU1CRdO2jpPb58F61fKCo02sROz+xMnYUI43LfHl4Ecyt5wZJJ4nwS9oaIDcFgW/TuioZLPlyMkYWRGEXBzSUmJJc0IyOaU5HCPT3mis1onqNFV8nKXt8ddgtLhFIq83izhVc3tVF8uT0jppDt71uiEVo4H6mNN6hwhi09Wu/eWhxb59o9xExw97qdubWKGU97DPbe1IYpll0uswI8jWxsGd+UT6ZMm2gQnp44lh5MsCxZ5qehq5E3HoSWf6xjF1DGjZpzFksri62NpUzcc0kqizXLItfiL9pBFRiJ7IIlMmaYKkCSLxVLubayGIiEFK2kEKEG0djVKOcp0dV4zG9Di/DMi6kx5cHmJj6CTyRSTTYScqHmwHolCpG33ErOKECO5kTzosznxNDreL3hPqfaAGvHMGSUF4U0BAyBM5bnMPuL18d1VNxaKpPqeq2b+UYUWdgtgfyr3CWhUzgwQX0aV5Zd/F7RnI95vdQY/YFxnpE6k+d+C4HuQthtykpTh2PCa7WPfOFYJBA9QUlJleGRi4YsjAAYEse+7P93v6PUVet4oIxVdq1szykVtY+GNPdbL6JAiNpemNCI5XLWvf27z0rzPMW0XZvNZgmj2iTF3LWAGwZPDNVmLYopwUG4VqZU10HXMFcQfUI3tdS5pqvRVqKr0bBxKHuNzV0PD5Uo5wzw0Yx1G2s9PItC2kuD5xBIwBSXSwK77CQeKrEaWV7ClL4Ap2Tjsb0Ub5gdhdtEjjIv2MXr440eKpdBs6zXJLSL7KbnnbzAOOx29vsoOkDJIlNt9xuajULH7ArCHvm2uSlw0mWMtF6J+7NU+G1hP1WaqWS1Wym2rWKcgFTofbeDwYCjgoGnSY6XXbFUkNfHh2cQUo2UOA5NxXtoURiDGbOC8o4A6ieZ8Ac7K+jQK2KLEhAMynLiJcaDP7WvyxJoXdcXEZgE6Q3lOR3lS8wA+YgpQ0640IZFW7dBT3CQfzWbG0azmt2NE19ZsPhiYLTPDcA5+1hMcCJvljk1VnVYcghw7Cs0kMcrhp13D2xK9XRVu8hRFPih7RdNrEoxq/8uZGFQxzgFoUKKeZw2h1patM0+aOaira9gVjzD+AX4w872Kgg2qRRjXFOaN/qkIuuQFSHKd8mGXEmA/pL4fCTjkkV9OlPtV8lpz6dWG0f3DORfcdFNpIjVUmC13eRTMu8KMniSQ3GoFIUkS9js0LP3WYHduJl+2TGR9X+tXfMRFfQSoobXemRNMdA4xOTSNuqTEz9C1zoqU1YhOdQHZdZf3RWTiVntBM9ziMmBBkFxE2NFQyZrPS20ZWImQn3AISPBfe7MvxuTN3XuE9dx4gQV5ORoC7VPe6zHzKRTpsFn913dPuFGu6THeqCWJTQzeBtJl1yHwPvmEFy7qhIum1KxQpoQpk9kZTTPWNRTe2Q4Jkpcsp+fkGvYhXXBq87f2Ew2xkbrhiCv0XXuDWi2Wa7roTqCPSYELwXn2Oqu4/WLmnDYN+R5xgFFPAtZvI5dzknGx2OmYjMpeFs5ZKxaocUytL5hggpDmLjhSoqi6Ueo99zhL+ehc571fKASnA/y7v1P5DTD7FoIzq3anLtbg9nb23v58uX+/v6rVx0xY47Mq3StLxLas1aac6rvoHGgbWj702iMYvMClTOuy5zO28JibINA5I5+xm4eaopwEjrPuZlfdnsTn+YiiPpFbyH3gZbAdYCZ1Wyu8y6odJ9RbfrDRf+ozyVa3YE99blmp8f+FoM5ePbZNQHeH25t7+zuvdx/NaCjNGPjwfKZrPBMhLnE2YLds4kcofBDd8Lbk4zyjef2Ue7bR0lutpKCZbxatEg7HJ0vwuJd3zHz7GIWDdZwFt7pkcM/rIhRf7MkeXredx09lEt42nwZvuypg/EE96ULctMmZZaz0GJOHk+bG549KOT7swU1evLAABJPkRiIhs50j1BLhB6ZpGWvoXdIhe56msuUUdGtOcz0wpQxRGZFE3YRMp9wPSyM3+NLfJnt7CVTn10coytkXFtNpuJ66p/TLckYYElqGcRbYhAlBwQQvyF6hE1AwLDawY0mr2kxymiP/HR0Rn46OiE39coeliU5ERMuwpH5xxv7iv3eIV10HTxaloS51+xnN+Sem6mqRI+MqZpQw3okh+67jx/+9iDFTWbsUvOJoFa9amlwMmPkvPXbXarcxZRp1kagaVhLQB8acUHVHMP5Qtf64QmlCC5wT5PFSMqcUbFsY/2AP4NxiZaganLMrXXjs1vMRe90a9RGVexeOnPjCPGJ3ZMrBLOwBygyC3mx3A4E3TuI4NKBZ7AgwTucGQ+oRQoqqjF1iFOjuaWehxq6YSKTkU3lImiiEBaVsxuKQRuHpeW83787J1LkS2IoU1kktl+W3JZpUip5O38w3Q011cpSwQ6zjLsE0MXTADcQUwZd0swNrZv+4yr3aEETSDNQ89LIiaLllKeEKSWVrsNi41ZvaM6zOHxZKmJUpY3vj7xm9IaRSkQ5jmMfeAav1q/4e7NuPzQ7s6qeSKcsvV4GPnPy/v2795cf3l68/3B+cXJ8+f7du4sHr1+FQHYrCi89x+4agnxgXw2ZpU5W4amSdv+TI6lKqdrB+ffzcDNarJg32C4/J4OA9qRyHMABFni24EDRkpof1DaAh/OFk7///M9f99/sH/7jwXS225o9hM4fuU7Wz41UDC2B8THrODoknTZDQv5hzxs1Pu5z2ZHD9yCMHTHzfDJmD2yEVr4LTTaCNSwBm0BpozkxUuba4U6Blw2Qilh6jVZA5BGdVH/cxQdM5TPSu/v+Rs8jiObNm/yGKQwRohPKRRR6Yt8I8omVKGN7UyeLpI1FuQf/uz/BaiENRKnAaIJ81vz6TuSD8HAzu93lnS+Ad0ZwgQ5gzLUaxkJctDOpYVjtLo0aiVBhI7lwyvIycuCB6RmjVkPT2hm1xdzKvoZH2ub9pcNV+tlqovBs0ajDCzpZqZYUK7fQecjdw0HaTYrgbLIj6ROHa+hkRaOtd6YbK510RIU0sG7vM6QG7i25G/t2YUynMBIHJLswlhUuZU2cZhpIsCHg+ViVEQF7s5I+neAFxXW9sToVI8TnbfCzCOahydGOF364k6dFj9eYtcj8G5ggLgoTMJqb8C7f+cEiNsYmRqcnTY4FkkoN04H+tgZySM+FtfdIjZYC3hyH/4AEsgSL4EhpJ3qK55kdc4vwSD6GRILseBwAw4nTuZZgbUQdpFJonkGwKtyFVAOSdYxshoASrtWRR+mAu1A0Z6yXdejJ0CSm3wwxmEwD0RtCKkPbXkp16mcz5QjGG6O5gpccfKCi+zlPhTBLzA0MlP6mIEcaSCr3wh0JrTpY88+EOxKaBfyRZ9yRZ9yRbyZC6JvEHYkPvk+CcjUSOgb/RcFH4ivuGYHk4WN9RiBZnNAzAskzAskzAskzAskzAskzAskzAsnCKP96CCSxPPz1wpBEo3zGIvlKsUh4aWca76ePgG2wBspGqfiNvQSO3/y60YWzAVckXChfPfwI4FhEbh83e3AG1fQy0i6qpc4xgzixp5n1UwOKPFCh/rKoIg0+0h7I1wYtki3o/8/4Is/4Is/4Is/4Is/4Is/4Is/4Is/4Ig+e8DO+yDO+yDO+yDO+yDO+SINyz/giz/gi3zS+SJZ7CcJHCr5+7b74WF30+2Skgbsl5yNFFWeaZHNBCzRQuVZzSTNfkN4lQIC3y/38hoq5q9MY18h2hc0kWdNTCmCqjX7WXIXykIQIip1XiEYeOcRpQsxgexqE3FobHMs8lzMuJgd+NN+TY5xAP+fi2vU3Jy+ukizPrzZc4UdvfJOC/MJFJme6fv8ch/sOs15eXCVadr33QfDbPgjuC3NfGEtjGPOcj7oaLGj67vzhYXbNJMbkT5AX2JrRc5rgl0kTbC/Dc9bgN5012F7Ov0YSYWvWzzmFq8kpbJP9OcVwxSmGrQV4zjh8IP2sMpwU2e6KOMWb413s8lHj1FM6XNFAz38+HH7aSLd291Y31q3dvU8b7W7k037y0e4Otz5ltDpj7CGRH5802vPjk5Ozx412RSJHw0TqFLD2JVsXpS5oqX2IQyykjHnOEC1HX3czmGumBMu3t5JaT783KUpqVmXr+7HKc5yN7XSBLh0TOzr4zSnHv52DYru99dsnTZYlVKVTblgaYeo89byPzj6QuFtiqJowE0zHliSd07/d23nEDK2gQMV8RZM7DYWNsNuFrdvzSE4ZoQae4jnrQ1b5k8ncJUuiQa6aEq248kcS4oy2g/PvN3Hb1SWUiVr9zF23nzDrvWQ7ebU3GCTDlzvD3UdMnxflKt0Xh+i0CNn+pVTGodSeneDJJoeCuFGRfh+iVeAx0hhnv+999V7nGnMxYapUXDjMLkgvvGGC0LFhiiiG1HTptx7B1srdfZhzLeMqKnQwYWgypRAGmlZKWWUCMzRnGOoBidikgGRRRYMJBEaPiHxN+VgJfJgaMjWm1Aebm7PZLBlzxdgcmNHmKJeTTTNVjJq+YppZXri5NRjubA6Gm0bR9JqLSb+guVUg+0icvu2Qi0kyNUW+JBI13dsfbKc77NXW1tB+yFK6+2pvm9Jsey/Lxo/YPFLxCRc0v4RDtFKsUneCPpV7np8dnr69SE7+efKI6TvdfdVzdt1+6tzXwtXx2+3hibeqw+d3wT6OYsTafYgTnB/Cq9ne+fH23H1xp/PDIau7TB7b+fHbc/J7xeAgWw2eCj1jqj5Q9neHru40ecbhTIdocTB9i0nOQltzUiouwc0xYQbm6Jp1jb64yoQGRNsDeP5qg6DcMfedxK1DZILHY0D3p3PbmJCjjt0GiAeNwTq0ESTnxoD2hhlDwxCuZUjDgXYWR4mvXm08JuG/MfP7bOAFmJIWexeEgjMuwpCgwr2F8Uk0nbp+icZCjUQxUykR9TaaBzT3BgblBcC9c2HPkaNVnWfvFwVpr5nrtQkjMJqTk6Pz+jy8Z6lUmWsL+Dxw59iCXdTTwR9954LM7FsnR+eu+XZCmV1fu+8giQ2jsCEvgcEvTcwP+5zf3+TQkIILXlRFz30Z2vWTKipt4r1GrmwvV3ZwgEKxMA2u68CZnlWeQpMQE5nCpczB0mhnRDUppdZ8hEEnGaFZBhBHtLbzO0elxwfoHijVJK20kYXH9+gAMnDzTnO6MvQJRGalmNMSFsljw2S4i8AC67IGFOyUTk56+nbplKJqDE89I7BdwywiFooBtz4DoHmQGMXSCD6FEl8tmci0D8wBlG3gap5UcYOeJp3ixnCQ+P+3lDqrhuKNY7CNjKGFW1MiJVMQyBzR7BRMmWBilmNy9PbwzYk9VCNmiWjfz2+sdBgxuPV1Ta4wcqZmUybCLpFQtgIua6WYLqUlfXBtRY3A2U7IaeB3QhofJtpu08lk5Or3iukAhnFlry3WAIZpLBfEVN8RWu+XzJiHBEfelb8Skvsg/egG/Ib2SgAiAFU6V8ab92k6bXRIyIiNgek1wFe4TqnKWJaQX5mSHiS7AFP41AWJIH+uCTtqBtJgV0sAKZZv7hWC4l9Ma0D8T+BjsKcX5jNlNGPqcpzTyeqcyT6iZ4s4yAjLonEkBEbSQKYuWWpYFiGgH5DDwx65OOqR98c98v6wRw6Pe+TouEeO3y1xOvxr7f3xWo+svT+Mg33ugud7sqW0c8U8sdgNSbVLUnTSUankRNECtzGaRU3rRGB+B1MImRQ3BjhoJa/RfZD96CVWhq3hcBFQXZZLMquflDAudkcK9EajEIigj869eM0FJGSh3N0QxfF/BdOaTlgSB/5zDTFLjraOiRrvrsWmULwHqkFoVbvdO2n49w8n7/9ngYaBR38x2Uc5CRjvMVS37i3mNK6YVd7kcIW3hhzf1MEp0CogJaTog4nIisH2Nlc0NVbxeoEJSttbABNmR0GGW3sbcb6P1I036ksmTk6mmjCd0tKeU6oZGQ58UrEmL347Pj6OErl/oOk10TnVU6fg/l5JgEgKLbumEnJBR7pHUqoUpxPmNCaNknnOo2T8MWNZ3EIqxQ1TLsH1N9Mjvyl86zcB+5U53/bjpIKw/l9dsuZzgubXmKAZ9ssXztTkDeOLm/ld6ZULzOUbSyiczWbLF+M5c/A5c/BzZg7WG+vLqEBOU/y4RHN4eLiIY+ZV+cvPBcpxuGARzXNyemYFTgYljq5ia9FVy0zjf7zyllW3z/h4zNMqB4NdpVmPjFhKKx08AjdUcWbmXiWMd3VBjbZqsm3KDSshJ7fGSjb1+CIgVj9QM2UKLCdgfY4IdRWaL+g1g8a99RDCIUXGbu3bBcD1RE2jLIIvwe+Mag5ZCKHFG64rmvM/mBORrCQ+lksKTa7/ay0yPFl9rv5z2KXYeZn9S6gyvu/lcFFv30Eg8cKoV3iw1uOTFTwuPogw67kVsVIzbNTmVTmXlYrg9iOPDQRdTvgN0/ah2NfTgy/i2MxUsTj+OhM6tDLGsbWdNvcdRT0A74Fx/pnGIFr9S+Fxi0um3PxfyBKt4vncNqGlDLeV00PxGG0k5FBkUAE3laJekgXobXsIl3uOvJ/FaqeOeXSeh8goH+pQpS3/3MnRffxzb5ih/dih4CstOI/BwwtiLQ26aAWWKfZ7xRXLoPDJZ444Ozk6D1EYcImGdcDyakYm5IqlOnEPXWGarh9SzVVBXANeVmmDtb4grCHP3VaLduQvUyZwbWGhUyV1JEVykUEBuH7fGaidA8oOCMLQcz6Zmryr7G80G3g/SsjKmcGicxPlohpo9h87VA8SlE5ZQRfWJE6dW7LFhskgGTR3mFKyVfzjJPrqASlwVETeVpf6AVt+DlaeQNMPmuH1AYIMPudcemXJAFQ7Z1iI2pLcMw/IjE2pveJmeLUFiw7uA240y8cRrInA1h/ha10RCCEQGk1jLTcQDvxels0VJOD6YL4lo3ImunsOrZUW20EQb/Jb6Egbml5fWtHnr4atcIFpDCkUdEhZ8APCitiDUebgW2a3jVRBkOWWYtB8GYE/bKRerLRhfgjYOeNQGYRWakCORdzsP/SGJjkVk+RtlednEpxTJ/6VJpu7qXmvZ3PRVx9jc47FdNU3gsyKW7MkkS6XXrXDUkiKpw12FbjioX2UADquK6KgF2petKp8QAmFKbKMwD5r7em1DMwT7jkHZFWnWlMTvKqglYpJ3UYoIWo7qifh2vNNUZ9eZTcnZGATO3uAwepFVUicgwSVvICB59r04RagJ8fIbT2sAuEbSaUQLghlxMzMqjk0LrVBm0U5sDMuuEFAXbtUudR2bod+JT5ObsAy9U1CnIaoEJk0JwWjulKsAJJg1lIXZaPHIKPG0GsW9nNM5nh71DQuWCEhEopp24xvLqsp7Uqg3PDAFg0rwPNSKZaQc4ZrfoU59fYuvsJpc+Pq9gIv8RE+gEURAkfCiYgDYNxIocCJoWZB5nhAVFP6AFzvT1aNsLegD3kPkgMbaFqKAko1Rr/G0TkieoucQqUy2Bq1lD2lwtM7pYZNJKg5vv2w6JaRXAGZ+jTLrnrkyp2nPpwnBl+Nec76qNVkV+gc9G6w0KK9ZkCdiWKnXNBuDjuPLdHBK81Uv6RaW8L2MTRuURxyU1jNMmF2pgMRHltt0IrKRzgGXzMDAxDRIgFCODW4UrWhEXQ2Zy10S2Yb8pMhU84UVek0Tltpr1kt0eI2WBvxCRlVAOa5ZscXtciZblosI4UjN0w57tjq4sCt+BWZu8slaCEEgGKcBdE9FtqEPEdu5s4xGlLZgcflc3zXlbHBHu3iXPmIaJ//SmMQdl2N/LDapyG077VZ1y/YJ2mey5kdoVWx0+ZCuXvKTSkyc1Jj5RJYmqBORZTs1pArM7VSaasawN1i+9NYWk6bFaJAiA/Rn65yFx+j+QFAIyKKRsWUfKRhpVm4fDKmG3nJTh7WpBJRZaUeUWxCVZbHuwJuEXiaWLmosh+kInaaoL6CGokXlrxhCm4rCOL3YpgXLHm8VMwHHKPMRE6Pu5dnZ29nf3FRkHPdk3dkse2mTXN3crDBWtFzYUFsE+7ema/C5YpNUbt5VZQVqxhFcAhB6ATWSSr7NxieSl5CUbKl+x/rv6cOxfd/QQkrQ4sSWQw18Vd1kQI31gZNYbQMLbm++FsUz5AvXEunghT2utfcVGgX6LnoWTOTJHTrDuWIdVgf8Prwf0ZqVCs/I6V5ClnBSFyWQyAXCl2xgc6FsrjQYdz2NUOJRSJYFngViI5rEgBvMsKN4yitkRRScCPrUNW6ifV1sAr4FbN/0hHPuYFCBdeMlaQq0Z0DL8UHrknVlGonFjfpaK9nPIUpzXvxytY++xZeVmze3hoM9/qD3f7W9sVg/2Cwe7C9k+zvvvx10bCdUUM1uy+U8+dDtsNuW3GTokEpdH1B1AMmzljRBmIhnT5n1Rap/JWFIOU0bdxVuZz0nKqay8lGL+48RmJA+Wlel1WLznEqiwijyh6WeNiwGSBpqABeDxBHQppg/IPmrUzV6BtUzRDDWcisymtyI4YhYiB5OJlMmqjOX9zMkgurpOmUJS16hGWvFlIAHwHh3dEKF2VlLv0DggrpYjWjZ2Rl4oeofsPznC99Dh2ksIeGSzfXsRtKwxRJwJ0bhtDcbcjjcGUsv8C/mVXnlC9dYmrHbSM0t4uPeSYFvYvMqyh23Xkn0iITD4kWvOt6qoe+cDO1LyXco/Zi9t97ca5hI7J3F/iC5QhU244CwCvMB/yZ6il5UTI1paW2B1kb+02UyrcBDl06c7elgfIzFP2KjYllrJBCG2VJA2YPsHVbabbrEA23tnd2917uvxp0fTr84ej4ixpKT4/tTL26+BH0un26M94dDLLFEQsstfE55KSLcE/B/gocnSrFb3ycMYNSTYrmLqTaSLUg9YC84yHkQEC5qi/BWJdo7W8vwuTzkIqZOC5dSwe5lgutNyS8uIOCOWQdD4iCiXRWhmgUKfZCHdF01qnbnwqnLNtTikYOq0ZqXRVWihGS2LmBttYL0ouTB7yHcaqkkLmcNHD87DUnr33ICNcHDVqR/7c9ufobv/RX95YjdpPhYPjrg2FXrnkXs/uKdXgfLPgoJR4NXeg0tg31fSttOy1khnlxJv7ZLJSu9ZwdI7rsy86mGflXjSslGHzeta2o0zrgIuG95oW8E9WMScX1lNCcKeMFLDgnDUthKwYFL8pmay2ZGudIpgjyrzEKG0bQyNCMLlUypSLLIbh1yubg5ZxRpeyZrI+wYnbOYLitv0TRBwiiZF7Pmpu6SBnU7YeIPm2gCtmUQWpoyPdIZYHGXEINeHQnVU5VSEap1V9lhb4loljehX/akvlWJHhjr1EqF4T4wxzbkq0LiHBqEChayN+qEvPx3XaDEhRW/YemUTPKqwlIIYvWozoig8IJEV7aR/n9EMRWuOM3ev48YctXrTjHhkpbu27AJGqfv0suXlgNf2+sYj3e2wuC3ZpgMLHbXxiuwmn94I7LHdLKEiOB1TzA98ZQAs1kelknvNjDbaWiDIzHCMEM6jqgB7CsPiRWi3GxXxC6bhRnN95WcHWJa3YFOaSVZgC1iCjT8oYpxTO3xWjE9n14lx9uL5RlJ5X27ucZz7OUqgw3pyV29zKes5IMX5HB/sHW3sFwgN6Io5MfDwb///8abu38P+csrSyh8C+COAlQ4Z8p/G6YuEeHA/ehKQFbPqWxvhcWadFGliXL/Ev4X63Svw0Hif2/Icm0+dtWMky2ki1dmr8Nt7a3GqrRHS5RWRmrZ35Td6TVTh97Rbr5XvlY1IwJSGqIGTBefJEdnPoFAfdcrZJTnlvBLNixSqZ8ykK4BqGsJ9rJEBmBZZ1S2ltpXLoQSrAeGSCq308iH03WsCIjc8Psztb9ba8cD3gXXVL1FdwiTM/eX854ilc7r01R0QSjoR/aG02E8XtZm2K0J9xlpay8qktehLnh3y7FFMWM0Ggdto5Sp5sjmFJqHOc6vT5g+gXjBooFtvXoWtchNhDvEcs2aJ7HC3yvZb2JA5vcwsbBhT9WCvZTTRbhUu7dRQQWS0jptwJ8XQXVrcMSyck0mG6NNmYbr0kwbnne7c7wvZpp7B05sILRVS8WJ6iYB8kL9HUOGcN+YCSTDNl9Qa/r1dFM6CUs05F2gfU4CAm1Kv/FeYiw7Dp/aOeH04ZSho8wP59rZ/BbdAu8lpPI5F2gLNiQD+qwTq+T+iuwo6Jwjbu6JGuy5k4otZzPdWGFz6kxZbYBZn3Eb61GCB0eykm2IMVDiy8QgK1XI3X13RT7/irrH1ZWaxSTjbvw6haWVzGqV4ZZtf4eeiOz6TwOlPTBHYtMbdEq3uECt60BPaFuWQoSuGPFltLuQATfesNLHtr9BUL33JnDt6+aPMg1GfiN86W5V5COVx01pHGyDgHWTj6EWrSKspAZGxHA7Ha5HqI1nqhJe9ozJri7phSDdIlacwl3SWt4ge221t0OEjfr1SiX6TXLiOaGXS3ZTBeQngJcDCqfMp893Zbn72kAAJT/hr30yTei65B8eP+a5Fxc+4SYuxHI/Z5t70jfClYqgOATnsbBLCEwDpnLYaQ694IA1QCviawFB6Aj2ktfMbypCynANQvXd7PGdveKeZwwZCxx9tgm9LP5X4MBGC8fvHRcX1/qlhx6l3Q6ziVdGmj5nutrAq2Boqi4VBzzT9pMVTu+R7TMK7CWbSQNEf2DZs4lCFMGp5xzYKLMYU98R8XQMJ9LIVXxgA1558TW34Jxj//BMujiI5PsYRSVTin4vkOrA7u/hoNBh0G0oNzVlXAVeOaygj3SdI+5Gwc5EqAB6GhAuukltU3MnIFTM7v3RD0NpKKLPAfpDOtgdDgztOVPDzvejyvTe+46crX9l4kRELbaehRi8nFO3rUIzjW9EBTRA/8vvW5iprBbmhoiVeYiaoLRKoqOiGMj/Nhq521wZXVS8Iapph/ms1fVxaR4DCMMHTbPXuOCvst7/UvARQnKTGgxxk+JMtHwKe8o8wEosY3AczydOM9qVXpBIQooC6sDYYmuV+5MHGBU0CbWC9wOju2YIHpYia8T9MjJmmE+I2bJDQiUV7mcJBp+T/zvSSozdpV4hu6/rq/z2I1QZyogeJnrYkEwarjCkSP66tH1ET49Pt9IfLJx442gHritTrjRRM5E6BFTl6w8UeckhXZTWWIY3/LpRvFdYcLdN9PLxb1u6IMqen/c+Yme1o+6P11wY+wAbVwp3hlaB+/c4QG15/oPKVaYYne3wt2Yqj08NeOxuyG0izZCF5Lt5tCUhXLFaOblRSck+ENRe6Wi6xgPq99IiJk647phnUhTViKgRujUZ5kCFg+1rEIKUGNPj13nayeVkiXbPCy0YSqjxVoEtkFHI8VuUF/3j59frG2g+kx+/vmgKGrGw2nun+oPdg8Gg7WNDjbcnZvwFVvhzJSrRwabQlxm08DWiqFc09Woj1GnayBR9HCbYQRn4w4KisdCJCtuWbwTeoQJuwd0FJrq+HIGERcyMuzhpCCDvVR2mUEQdkYrn/Dr6sIvsYc/adCos5vNS9YlElVqVQVi19sqjoB+AAbWS4MSmCMXlT3qN0wbPvEzblq17qH9CMREdk1jThwX/YyVZrrQOl5zzktZG7jQOS/ijCKXMSxAeSZlTlO2VI9aoj/VrOGT9ahi3qFJQTebu1svhxnLRv3x7mjQ39ka7vf3X44H/R2a7uy/HNDt/TG7j5bld8qYB0egyxD6sf7mzgShQ0T+b2WTAEbVgkcZEnU0GVnZqxka6xJe7K8QMexTPGzbjgh+P/wIZRMcaKcT7SKrKTAB8MX4FfM5NP5vKrJNqeIpx/F7PQegFEz0ozl2eeo9X+RN7Y/814+nb/7tgY51nS1jL2yeMr2R4MsuecoZOlsZJWD5AVALliE1W/PxKxPFmDir7qOyTjDq9DMJP+uvqYtDcWEpOdbO8d10Oja8BbxeXo1Bq4AaDpY2NMIvCVyjxig+qszKKnfWQH+4PqH/WLwIX2LFSWT1N1TN7R4KNYPJz0xhwC6AfLHbKa00eBQAUkWO3T3V5PyWmwQLmM9acscZalDcsB64VwCuIuvVlZvtfQel6WLnKrtlaWVYj0x5ljHRg8Bw/FeKfN5znLVHZoqbJdb89X+t+efXemQN37hnAVLyXGbyuczkc5nJ5zKTn7Ccz2Umn34XP5eZfC4z+Vxm8lssM7k09fBxUj5oMdAmqHJQq+Wegj1E1+PmbLy/KNanjXD2z6+X1GK706AoRthC5ne3poK/hdoR0IxbaJTvqxKsvFeF7erKGXm4PWtMkyuYUeRUd8mJmIuK9W+CH8A+2iOaizQ05+1HftxxFZ0O+jVwEO5DwE+5G46hMxfopVshLuau8nc6o4tD9+rKqkbv+6sBlYIhI5fAJuMyBjF+SKb4TdNmj9DvzjAZGQs7Z785lQXbpHm8UoEKtulLbO5zEmLpITlWoHgidP0dlGiaNOGC8HdxLV0KHxPVGZUfpY2WJVMp1a5gS8MZAFJV3nBPxsD/D+WCQLIVVsxDPhl67RF2ayfmr+qcUficyTsCPsMCgKjZAO4NDWvywhsFDVXJ5I+NHqxK417C9DkRkz5EKL1Ym/yx1gPar2ELa0vicMrIwu7JOlmZBHqmeGEvV7BsgVPmp9PjjY+ymPXhYDBcZJCxfWy1I2/XQOscdTdT+OIFo7+iitBfWcnnr6ym87dZtJmL1UGXnNq+an+j58t439Ws17sc6laijMTdve397UXeUvCCXa4QV+7N6ZsTzNzzMkqsZKPFqVlqWhFtFCjfYzKaN43nxGXAxLU+ORU0kWqyiTFcAJ2yWbCM0z54HOPPye3UFPm/Tg/fHjZaleMxTznN0Uf5754TMjzocoIYox3oClbyRbPjyAGgN9pFMJCQeRiRweNSPHQbFqvbhW/sJoyXhgsiU6t0hp1JlwL/rQ/2dgYd2+8z6k4dqlPQeSgkt4EyvMh4Vlhd5W27kjqKkAFEtBbBfLYqKvdO7O8krRepukQeORMrS1BAF6ftcB1saApANu4vMTxt7fevEiQUatBD+flIU++1NkWQqjtUu0bfQc17tGq3eddeei5X/1yu/rlc/XO5+s828+dy9c/l6p/L1X/inL/ScvUxcTT/4xPyWzoslbZByyZAHYxO1LvYwovCD2DxuoBdrsma/XNJtaXh3vb+zsLgUdy4/AsKpxcoaIF4CvHD8wJCWTsC+ldnP4D1B8X5Be7yjCsIwnSj2+jc3VHUZBRnvNKqs1YhAkvwB7AEqzpNLwpSeHHeMhOjPvUQY/Ht7uBVQnPgDobfICNeVfzQaxcL6KJcSDQOF1344vzw7UaCejAYV0KoYVeMjv0frcwU0wChQGzkhYZtMKqMC1euQVtbNbCO356TNhUIeQG4RQ42RW+gV4cVlOf1u92E/z5hOdWGp0kqH+zhhvXhWldMJTjuVV6VfoFcgDdcGi+O3sKes4OCUK2IxIH4nZRwqOhgZSY/88mUHGpdKSpSRs4B2Z8cHX4agSphVuZdrYkDvZIXRxuI7dw19w/nnzKxCCyMZavcAMdxx279jx+7/kd/+3DeI+/+5vfBqUh75N2Hv7Xq2vbI0du/3bFXGsfys+ybXKY078wJfdKN47v1vO71RqeYabeX5VL/4Gz2KbOUakKFS8BZ8UzjrjV58e4TGcipSD8XIWh+WQm+KjG8ix40J3YEliwfHkmXZYWhH0EbqPZxKdUlqAOrA5IIogBUG4Fsfuw/CAIXPXIOottZ5zE5ojkfSyU4fdT0hTSXoOo/YL53eRcuFqrExMsJCHKguYARAeHZEAmAdxdH3RpsDfqDl/3hHhlsHwx3D7Zf/fdgcDAYPHq2IzbuxJp4muliku89pzp81R/sw1SHBzuDg63dT5gqVu29vGbzS5pP7BmaPgSL4lP29KHvL5iZPMQRRhRjyeFr1n2o358/7k6LJpxW6maVFcCgP5ysL7yT5/aB1P1UT5mExcC4q8alDgXBPe28/7OTSIJrU+5uDT+VUuy2lIKJh9wDd9kdTlxzYeEzBo6W1rKHEPJ7znZvd3f7Zbw6XVCHj6TAZ7S6ACgI/yPYl6JV1yVN0RbDTbe6tDWISow8ZC6aKU7zSwQhWdGmdwDh2HWNf6Kr+gR03+KAWBUgNNJ5r7n/xzEkPuyPckodoEiPcBOFAaCp2SdRSlBvc6goKrI6CLHRfDqlUJJEda/A7u6PP/zw6ujl8ckPPw5e7Q9eHQ+3jo4OH8eNQoD5yrnvabO8ZSMTKUS9R1zoF1bXl8A4kQbRUAwZA7AjF+QnSV5TMSFHkKhEcj5SVM2xFpu3zU+4mVYjMMtPZE7FZHMiN0e5HG1O5DAZ7mxqlW5iptOmJRb8k0zkf73e3n7Zf729u925Phik1X/s/eCMLV+HNUEHc4IfVteM9ZQqliWTXI5oHiRdwR7ssmsR4GuwFnxGY4Gf2NdoLVhIBnQmPwSMvcNccH7xt1q075HXfzungvyoqEi5TmVkTuhZtTAB48HT7Jev2krQoMonTfNrMxPcxRgaS//ZZv0V2gQ6iPDwOf5VdXsXKbFa0fAfdXiGHYST0zp38fZ9ZhWi+5k/3g7D5KfwxZ0QJj8xGRdyT6lSc0R/x2xtWgeaQkKMHXtUYjJkGTZxa0CBmTAZXonTwD0kHcOiNAgIx9IpCM014rAd2emZl4ClcnEZqq+rssx5lDH4gPru3MxXlTR95Jlzd5SAFEYxuggYjDhBTJjLFRWjv5jJvkuCShcCxMNo1vXyubx9sMRZT3JFi/G2kbnuOu+ejFRmSg6x1nPHwEFMu+Rarmp9jpxkeHr+DhaoWyg6XDrUVW13N8ylu+SICtqR/OhZxD2HOGHyspTtsLz4VpFiwg0UKRcZyamBP7pdsP+HrOVSrB2Q/svtZG+4s7896JG1nJq1A7Kzm+wOdl8N98n/XXSPr1B4Xv9geZ7Hl2rFNdJAvp5Pp0XUNjkmE0VFldMGqKmZsrnl8Qy5exT0chTX14uigLhylXgA+hMLj5JxLqVydo1eMEssQnPj8HJSTucaqxeAWN8DPoy3fDMVMIKRBxMZF4RWRhZw3UT3SXfozUhqI0U/SxfWq5Ta0HxVJ3b9DLpDdtqG54I18tNo4BpAlZgW2l5U6SBgj498fUQAG7NTg46kIr+enjWVRxec4JCYZjxj+RwvXK9vAjIdfOym6audwc6DLeyKTaxgtUIm+R56/BiP7P/9aNlYV8Ql3TiXMsm/V2zEFvfvcqzTzy8O+MK9fzhszHjD9oJ0dnr49jB6bumE3EW6eagmIFLQzR8qJqS+POSK3QvHvit/1cu40Vd3Sbl2TpiK6kRdywE7IIHhGV2Dji2kecfgwMmDpc9MFnHhrCe+NxoFf0PGvwmkADzugjkY77hoS6NqgiCvjw/PLHc5xNovNXoAzgfXfvGOXFlcnLO286Zht54oFvRzqG6bAYvuS17q8drAIJPGVo+i0t1O/7n+5k51zu70KVZnhY1e7+0IAZybGdfuuWDpjpHA8d5vhZoDFm6wdytvrrWtMF//+c3xbg9SlzcIAhoxJ7gk5DDL/KDGASoSw+VdE6M51LRSKQ31yJpDRLmEeju8qxwIOP+alVRRI5VnKbR5l77Qgl4jDGmPYH2DKd2+3B1ubYQJ1lAE9a0bF6penDQ8HGEMVYDdeROMCZQoCOW30hgTAPuOwcbkBAShftCtXYOeu/6HbrskBaRA4PEAy5rV6bo4RIBoCT72uVPQyQuTo7+nZD2imK/elM83HqE6fw1J+F9H/v3Xk3r/9WTdf9UJ94GtygBe5dlq/c1HgH4BVbcN9Osqj7mzj5VKtaEiqmhwcnQO7ybfe+63tFrbIjAudAr1nNzRdo22yzpJhUJ9HWZTMKorBQUb4oJIdr4uYLqZ+jmlKptRxXrkhitT0ZwUNJ1yAYHPMr3GeCRDuQAl0DKV/12NmBIMIF9lFmFwPwCO985E0M8u2r5rlf9q9N+d/bm/d7m3mM2RllVSaTp5iLgApWayy7uL2JwxZRVtSFeFOyfUwY7qwrgIgroCi30aiss09D5u8PZxJTewsk0k5p2nNIfCs9RKc5ZyjbI3kRR4AH9QYmYS+oF92Vtenp/eMOUKqzag8t3ruheKkAxgpMOG6qlxXJfxuB6qgGZcXyeK0SxpYz18agCLkaYO6fBYEuTFhFYTtgEYws2ypS/oZKLYpAVHR3BNaJ7DUPWGgz4LyEWubmEq85ylbTiE+5MAEYxXSwPbp2HiayHDl9O5UDuS44gt+1vEK17LtK4aTgxPWRofL9vI+rpepo+FFqUib5n54fTdeUNTg55ec1HddrRdDzrqKbQImqAvWLkEpezd24t35+8eukwTJpNvwDkDw/wrOGiaE/3GnDQ4+G/GURMP9yt31tihfpMOGzvwZ6fNt+W0sWv27LhZqePGkvxbct5E4/26HTh2oH9lJw5pmWFWtFrrP7u+Yok2OrynxinWdda3JjNfWvPKj/QK7L72TCpmKiW09ymAPO8sJfd0gXz+OTr/B+oWMZL3oQ60RhOCITSf0bkmFbzSg9JcrlpocHoVjAouJlDkVrgapOKGKwkgiHGt+FCtGnN8FGa2OM3/asSogTv3qosyHbbCTso0HmzMG2xZvOxKsw82a5quapORN4dH8VDCw1DzVSKmoUemBEb+/scj8nKws2WXRleTCdOGZQfkhKZTIlPDDHnh8MV7ZL8/igJPrb69gSUhnCbgrEIzSf4Vcjv+TabslmYs5QXNEdlfkwm/8b4VWPdaWXQV8qFjqrHsOJT3sBe6YROmEnKOKj2/cQ+iI9X5XlxJnNDidF5O2ZJLf/1fa4NBfzDo757Av9v9re21Hln4cicuqXS3n+7zL+vbO/kGREs6oAjgGBG3iLjEB8FvfeFmJ4eBQeT3iuYAJBnajHR0sOhSlOici6i2+1XaLgVWWBYZU8Qucca0MzM0l9VI+3zHAXSF4RI2sTv2s5uIlhmHwBUpK7Bq0zwP9ensFlNjmrbQNdyUgbV9dtNQiwQlTa9ZV/Gtz0AE1/ZXTQYuVrMVFEsZBP56YnyFNFjlXgj0+AroIHUypgXPV5XM8u6cYH/khZdJFcug0G3GRpyKHhkrxkY665EZGkS7oZnw6c75VPkTFoL9KiG3FvxceKc08WADTqSzKi43htLUrtMb+R9607ljrpkSbFXFdhfmhr2H6YBqrejMleLvnNFOspMM+sPhVt9FZXTN6mmtRd/SvokxpB2Z79oo/+yipo+6+lK7xPfv+EzKhJG6R6pRJUz1Md5C1Yx38pYVItatf9DI6a9cv76wNxTxckW08QnZnjwXps79iwwCIyVpBqotU4BTD7yYt8AQ/eNQwDnP5cy27JTGJuw8eeHjt9jGAcm5qG57VgcDSgt+W2ezzxZq5ZzikN6dW41zfV0xkjH0T4PxzamvLu4p5+h/Zs3abfaJUb0gIbwiIWc5oxoArUmlwQhn71FZMqvpWl0HkvOxq5Oj856laqlkKTUjPJIDqCts2a3VwFQfceWuFil44Ww8hHUOB8lwJxkuzKL7JDxB3f55CfuxpY/9KBU5ymWVBe+kd5xirhmExKCZBvH+cn7NyJXZSgqW8aq4SuwGvCnqHbroGg3xLz0w8Na+Wo/6G+e41UaU0GKXMaWjbH35wFoGdwme5yyVItO1wBgqkPuI0/bybm/tLg7JKpxfS+wyAGA+degyzBhAHFc04Q92Vg3UyEVjFgzKSrNPnLr/VYomlkDrGmwnXpodE3pDeU5HS5D7D/MRU4accKEN67i/gZ4YfP21bO7VBOZHE//mYvSjsX/5cP2Fga0S9d5RFPghBImDQ1C50O/4LhqDMREZpyBUSDEv+B/ReJDU4c8PWHebj8kVzIpnV3aX4R/eHYKGz1SKMa5pu9S3yOzdK2RktXa1iZZsyJX4Sha3oVtVGEL3wJ7O3PxVctrzqVQeBx4Kr9fhYE0iNSrxWFbbTT4l85UBRx8qRed2tLDZoWcfYQFeTjePRvoWafklrvmICnpJs4KLtR5ZU6yUyorLl7bR2B9xn4hzY1opaz9fXJy5b+6KOP/Rp+8EpAX7IlafBqCwoA5WKveqoGaQzwY1hyNBlpFK5X7uiv1eMf2I5DX/4khm80+1ESOfOFgoxNbciDE8f2v4BEbRtYL7+y/vHror5vUXk58unOMJt9FHqfgzy3NJZlJFJegXqLmCPXAhsRr5HTvhhZ0E3DdTRq3i123EGe5sL98YKxOD1g+d670tCTGaTptr07jOcznRPmg+tJ3mnAmDGV4a8K6h6g1UmqFg/2/7oXlWZ6uhMovRWkRI0deGioyqDIeBxKxDRa7+2X+PI+ufHtdFva1c8M/+kRsol8L+uqQwxtY229nde9ln+69G/eFWtt2nO7t7/Z2tvb3hzvDlziPi/P0CFsxM5coWsbFO2HVE6DPFrWgrIfVnmOwlA1eU0tvOJhXPANx+RnUor3JQN7B2EQxbmF1RVPassjgHxMiQK4nxZr9XTM25mCRrjfgPOa6HgRaz0DsE/5WKpei6Zymt3J3iS/BAumQ7iwPn6/dR5WRFkCsLms9JxoxzPhHyrtGQLwBf2H0WJwJwAYPcSgbJoHPr/HRy0SNn787tvx/sP/L8YvleWHEt0vU33FWhCHZVy5Ha9210EEPaDCwslJxoZIONqEZ3oDc7gbd48QKsrVVCGv/81RG+0L8AIzGe44QcyaKkyjt/injINDQ641F5mqi39XVN4mZdq966NmV56XaBW33oRjFqNJE1slbBNSgME6jC7dhXN7PgBZ2wzQl/cAUpT2/FxkyplcF6vXfd1bGfMZPovJE8HOgol5MYpn2zY066lEKzLy574TAeKnzFg3+Wvs7uQce7xS9Pzy8tf7lZfJoA5ibztTFtN6zPx7WjJf+MbNu12sG38ZfHMO4Glw6tOgHzs3NrR2htqKn0koDzh25p9F8tW/rmGcSOl8ed7wwW039X66+D8d7ldx86f1yIrfWBQ03Lw2nr648B5oRmYtAcD1yvWCqVVR4gXgqLXuHHdu+kYReEanpoUnHxooLIEW4uhyEy5orNaJ73iJIV1B/OJbXHK7diq9qIxcZw1G7DUQutTanIwIFMQ0hVKoUIwumpex1l3NAqJZqLSR41VBMCB+hb00xoqSBsi+iSCmJntYGcIR6JjyrrJEgHGsDDbTM053RVNrawjbBXDNmq17O24vc68oL82jbuUm63ceHrviO4DBCaQ1WQHpGVcR8UyYo/wCiZgsHZD0XQYpnH2r38UM60MpNATc/T4zYxG4ejpub52zdnHefM/u/0eMnt+2DVeoXuldN4HdnyHdVdt95M7zW3Gm9y0uSLr8MXd0KAHC+gc4AZ2d6wuZxM4OZk6ZQKrgtnOYcvwRxjZxOB44JBp0YEsYy1Xt2PooIsdOfa9bw5tYoXZFNuWmXE9x+ZvpueRD3XuZyEjkYsulYBYolc2eHiY8n3V42J+LcCkpGRLswBMEq0rJRlFY0ZWrHHToJlcfvfX3nBaFQZoqiLqSBXOObvwQnFhQu2ODk6d+R7BO4IVHN92lqsrXqz7Qr9luBQmRN0RaRVajf/WMmiFaoZWr2rhD+5T9n+0NJp5AghM6rF+rpB8AoEZAjj65FMwnr5XRklNnbLgps3VG3mcrI5rgQUCtZJfeTuPKGNU9oonv2EAUXBRGVnHNJw/RI1Ua0D3dyujt1J3pCnHfFcUwq0ysoqUeyGKcijNXFdI47ROVI46LGJBGwePBLQCMYmwZly/WaS4YrhoZvbt2ulYi4rsM6VlYlPYuADlmP5wZApU16sOUc24H/aiKFiZMH8CiO7uppRJa565IopZf/D4Z9arqH5EiswUyqyCEVMedJlq3mSxMg4HxA7dpKGvW9pWebcJfmGOkyVroBxxYeRuOIvAbAgp9rn4HDBDfeW2tALyC9O86IkrbSRxfKwa6kmvkAslmtPRlIabRQtkx/8pwVCouk2AQaX865c06UMzmV53UU522KUyRDKCztvgldT3TaFsHIkiLMot9FfWsesgwo7W3dOcZUp/e2t8+iZL2MuXfiDPvA1gJWmtIQKRfElgtlg4C1KDb5Xd9b9im0XWEy4Cpec17DVkv/Qm0VACL8YlUi7MSGerKhcYylc9/aAOf9Em/r32Gs8Lu7RnCBdyV3k2U3DB2PHXDANabUQVePTlH0uYfxEaBqrOBJd5twgMJ0hVWkvFjCQljkjJVWmEYGN+YyKovMRjFKuWR9ggESNMx+psCsMZcMyaDFWpT3JXSu9eF83puEn21uYUOJSLUObU3pjNU8rq8yJtvdSITOsHgTKI8XaEBjgy0QqQYqSigg2A/5llYtC3rDmEckZFZZArSG3DYCNMwll9lgGq5LJ9NLFxNvrMeOajnKWES0t5VMK1/WIgZsuTvoc+bwKsCy6y0ExozgLtTGuLpGtLDmh56wkw1dksH+wtXcwHCB6B0T7vpmTpujVWRgwQDjC/f+AEyyhfMxdZ9SJFAUzFPBIYoHJQZBEQiiKKAU37WvihlPXVIjk14yR9z8eabK7s7Vjl3d7uLfTAUuJb41pynNu5skq7Ivr0cxdbT7iB7AgW7bjWEOjh2kqFWoAMpqp3Wt2qkvQ96jw13gNsVdbtkRGtra7N9HW9kdpt8K7NqKgFZ/7aFq/NxE75geHYtH/4OZYKi7Vw0p4PW5rtLaF73fxYDxyS7C6Sa7JPvm+Jtp/B8k+afK0UJ7Svq/w3mC3JUtd4Flg9W63tbAbXw2XhHtsL2YSOHKHgXzacfzoqQtazr1PXUMvdsIclCnVU8h6CowoVvdqZO12x6FdpFzbvn16fL7RizU7q5otDN6d7om0i+GMIf7Hq+TOoVtFEa4qryjawWrDRWoifdQqjPbmkSVqblH2bypLNMC1lMPOoXRug6V8JWyCVcvwX3qThA6bSA332hzg6FiyMyKDwRfcFNEoOvfDSdD/W7n3TaPs28aXH4W8DznzDYBm9A4VRSWceIgmOHnDlBNlaYwJDUIithMDLOuGvdMjBTwGztm37qN4XbNtoDErU9/E2WEPcATV1otVHalD1MQm/IYJrAgXj8LZwUoljUxl7kwq3giiRtwoqmK8Aaod6IwLpBETjbJ8wVMlNVM3PAUE4cogqJrtbI4KS/2wvp6XkUmMp7/37E3IRlJe94iZWRlTucHMGimsXBDNTeW0iRnYxzDxWWSR4Uoq4sZSl7Ozt1kWAiaxrF2wCWxmTBtyekbkDCG0S6mM7sXhUTOuWKgHGN3RnxAMCHWHEdc1rYKLLbSt0RlK1k69C84ysJOj87Xuw0t5sbDlloShdGrHjwlBWccYFIw9AQ0BIq1gpUbSnjNIe2pFu56OyRUSHuNkrkBYubKLwAVa9/z3yqHy9ciVP9zuJxSJeL1CuiqW3HJ7+wuEcZzHzC9XGV4MqUByHBwvAuAn/aTJ6ZmrCIG7j2oyY3numGVo1h/XGs+xyUejCvXESJn36URIbext6oOIjfQhzvXZHufNnO/XjCpBCitwUtNVHttuopxPpmYzELPPM6iasUToPJi++2/9dufn/37z0+6b/9ncn56qf579nu78+vc/Bn9bWKKwdVZg3Vk79p15ScNfB0bR8ZinyW/ivS80zjJSWxMOfhPkt9Dsb+R7H3bxmyDkexd3gZ+5GMlKZPiHrEz0F/iLBc3dS7f+r7hl8j2pBByG38Rv4pcpE6SgZWmZAtxI2ruH7K3ptLJCCm6k8ljE7Nb04iY7/EY1awR8aU0AHtZS5YazWc8VdwlWEU1+W/MTXoublor8tuZmv5bcOV5PaqlIyRQvmGFqYfxx234qd4+/MfD2soaOGvTonBwu01qP/LYWFg3+Cou25mbrly0iRPKbqC3HjVecncrem9BrGBGBLqjizNVy4BotzPFIjQzgsC0pymuAZiZhCTXILS4cJ3SSoEHbXtKNZnGY9UxC540e3aHo6MsDFcaN+ta84TIaxEWdyB+l7Uex6/bb0/MzTaSKm/zH2dtwxQdQgWSt23AM9FxgL2OpZlRlLLv8XHCHp2c+Pxy9u5HPIfrJmZBLJW+740uHr7aSYTJMFp0rnAq62oLIgEF65i+ct2iMeOEvg9lsltgxJVJNNlE2tGKK3vRXVB8Hu/hFcjs1Rd6IhiHk3F1PIDblrv60f1O7zUJzPhHuYgSB/C0zP+Zyhok28Mnl5TXahhwdVCt8IkXX3DoXZG9xIYR40BLcbYx9G/DNBFNx2AnNMnfDO2QTe4K8GHSTU+Eebsy0cU4hUlAwVdj9+Y/Xh29xZ/7e56L/O35hKAascE0cXmVCDnMrsjYp6MblIxps9wlH+zp8dqEPMIdobK3oEiuzNJqF8WgmMheOA3wFFjb4QvYHW8nwd8JESktd5U76t9pMK9qv0TCq7L8ydt0jv3DF9JSq62SjsQj3CTWzE0rcbFd06mBdFgPOGmGLnZzjwXFk0exWaOl558wWONFlYWV3TvWRgYOrhiVAxRvRjLACHuQAO5WoBoT3h7prqj9B9s8vfMwXprMUCfE+Cl6XIufhDx+jyrl3O5S5+pcOdc7/WNsDnGK3XKHbWoz69tfECnSF9dcvPcuudTHkfuw2AU2pR3K4Tv5D0+teFOQXLC1fpwUhSoiPMnL86FdB2nPHCwLkZy3xoFUJ8GJoFknq/xv7ax5tJ+3XlM/p3EouVVb2iEnLHuHlzV6fp0XZI8ykycbXuSIm7ViQFaF5uRD8d+en5I3MWI5K1ixG3fLH4LWlbmJpuoOUjax7pWZpj5S8AEJ/nWS2A1+g85/1zv9z3/YhNMi1595xnot3zW/vLisZxfe3a0uCd4YGOOCe3XEV+mak6jD0ZwxUVR8EjvlcPd8+xh5iYPhHW+w31RtnSrF3LiIMxx6RGCUvBDX6apLYKGSbAwaRmypo8AGKbiHZzEiiKnF/AhAtx8Z2l/iSBO3qlt6rpntkxkagGIPpgwujKsA+DFnrm6WC+UK7Hrbd6wC1rcg1jEqBazYeUtQjRL7kUoPys9C0perh2ZuQXxcIG+3XyNdEMYV+iavJ3Uk+/4aPCRUh3RCojvPUYV9onyaAe0PXCs8d9IZZuFYx4k7xNCFvXJTa7xWrsGFycvEaiqNKAVvIm5NLJaGOQG2nC82E+s2KofFKQmiulRI9PbQDDHiEf4w1E7E+Va32Z91ByJOpRP21Tu8Cb1SUp4TmDUsaQLFrqJOWa+KGgGoscTMQoka15uO5z7PzVmNCzjFfjaqiYc5stO29Um19tpW95r2ZkMNW6cUcNhIhydr/xdCzbkAPvSwc/H4gUvKczfbZs9kWaPyXT29boMifLd+tY7J/VnEznt6fVursmHB3MbGnMTW5W8PXE/NuqmBPvWPmd90ZkEFYu6epYhAl3LzjeshAT51nq0dOnLuneX8ev/m1R35+3yOv2cQ+ZZXuLqKfVaOcp5fYHDMPJf5zqdrnUrXPpWqfS9U+l6p9LlX7XKr2uVTtwnSeS9U+l6p9OB3alWoX9Ybao/5EVixv41mJGSsYlP4sdixfO/DZkPWUsEwLRP7LW7IWSfJnNmX52f5ZbVmN+f0ljFl+xl/QmsVFKos4OvDx1qwa8Ipiy61bznHQBUsWWK8aDd/DknX85tcHU/vTI5HrSOMaDXa5tLKiGuyN8uuLowrvPZdj79hHT1CO/WlsFUc1aNKda+yTk+BBWDaXpRenKYY3G0mJHhM3ShYIDfNxHTIcPPzB604RjITlNVIqIqRINaGC/9FWx0/HRMgYBwoSNhjLWBYXt3TjytnYEFaUZokSPbyEeP/znxYW6Lmo858BQ/y5qPNzUeeaeM9FnZ+LOj8XdX4u6vykRZ1LJbMqfcJiHQuGYNfjEgGvY+h6KyoaFRBmmOI0X20aozeius6diXRRHVpZoexps7xFrb5OGXrcIHIXtF6r/zRVJIWyqD0vzLsGfbpk3dK8ZDrpAof1ia/qquYOV15gBqTYTMN/SvgPCK7wQeY5AzxZtOraT3Vw7BJEmQXjZV2moQHZ8XTE/gd0dP9Nez4vqDAdrpelPOOzDzls18bdX4Ne1gqLa8FHty/+8lFwnrg1H8nMhOLpFDci3gVx8diAmJPKoqTCKy5WIwMHb2MTt5yfMVqPDrUrrFYHmEZUKSom4Mkc89ww536GmpleXwOYRLhLBDzodb4wjHo+j0Ef/wqKLjc10vYA/4pScrxHa62pvs9bxyBct+dw3d7jKFygIOT2owfV6972bWHv4RVB/lQK/7O2/6Ta/p9Q1f8rcrAn0vP/hEr+s4b/rOF/qob/jar3MaxDjRfvpJqzxpcfEWZq2Xq5LAOytDY0Ryh0zNL0fUcopaaGgweODkEkC435F+ugMGRx0TWo+R/NdiGYMTTuBoOtuqTJujWILoPKTWlDMH4A/KZKp9yw1FRqVQzGrVmj685dcLu/d7m3mII+qnieXa52N68fuvPYuc7A4uyo6oUdO5ght5VqVuF3UvgmKmwVUJgsp+SGnP98iCHNAjOcGcC/+SaWwDqOd8Yv2f6rLNsbjgav9vdHwy3GBoPB6NX+q729/b2XL4eDNHso80inLL3W1aru1CPX3QIh/exBL7xhKtRR6Aap2h9tb73K6Kv9V9tse2fw6lX6Mtun2W46epW+2lm0w0WDWNFMj5vh64CC1uY20YzelUwEBGclJ4oWYBDLqZhUli5Guq2oIRpuU7Gc01HONtl4zFPerGRM6kT0RZ0fSX6pU7ky2eNUZLCcYkKmchYTA6othF3g0qsqzVQf4ul7ZJLLEc07aYY/LZsge4htI6NmqTh7YZkxIHd1jnmRwjlPmdArk+teY3eugB3CvrUp7JlMxJ+sOEit4KOMu72A9igdYYuxuUbJgpyfHf+T+O5ec20QlTiSl7Tmo5zVoHm6zG4BMM81qTc3uvnbYUnTKQuNbyWLZ3iF0R3+Kmt1We88uaicrK6G3xk10wgP2q8z79yUcT28SqtNOFabRyzPqdqcyM1hMtxKXnVVegeQ+HRVJP9ZFnYqaA8NnZMP71+HqBYvtYEMz3UthDWqPZOP1N8I4L7S8la7OR96Z1rR7gFUeVR9Dr8DGwXPu+/Cra3tRYfZKhXLC+fcWZSBIJrJ6VZeNo+3LNadnJes56tsmiltPlJQQeNacIg95tFVDogqix7JyutJj4wUm/WIsF9MWNEjooKv/0NVN89RZfF161p+Eyz2Glfc3kpeNRWqti51Qn6GWuuP1aZ+QV2bnEll7LEiJ7csrfDji7OTjVBV6ZtQU47OPjS6JYaqCTPBIQFl6DrVlr2dB0vXDafRU8/MZ6X7bhtuQCyh6uCDM0INPMVzBpVRuw1xUOdAjg05kqqUqp3Aei8SrF7yDmTIFkXwR1DhjLbTcO8xa9vPilXZMO2WrvqIKe8l28mrvcEgGb7cGe4+dO68KKdUr6ygcV1oABTKAuoJYKWAsxNXlPJQ+FGRfh+KN8NjpDHOft8HuHrksjEXE6ZKxYUhIy4ApRxgoggdG2bFFCQl2gykcgWhU5mxfrNsMEKMevOCxtqBMk0rpazWg0I7og2mU/AKQy0Co2gwT8Do0Wr60cIFs9ksGXPF2JxB9YJRLiebZqoYNX3FsIjr5tZguLM5GG4aRdNrLib9guZWvuojcfq2Qy4mydQUefcFOkj39gfb6Q57tbU1tB+ylO6+2tumNNvey7LxQ3eOL754Ccdn1blDlsifyjHPzw5P314kJ/88eejcVxvhFCbcFeb0iImvhbvit9vDEy8xwOe2g3rtPpSJqJLWCaZeoGl8+bGgj3tZh31H3SEbli2EsA0obgt1GBxMXKM9LJvkmyM824y2cVTRPBQVBW/8le++5NkVkWPDBNGGzrX3V2BXhBvN8jGhAeEfIqdKjuzKPog2EV8dAtyxONzY5/AQ+WyyqrTR9UOl6Nyh4APxqJoA3KnuWWIoE3w5dqJ0pGVeGeZr2desdsoICwJqxCrf0Lll7hhrgxQrlbRSIGTucsNvGjnCSxNUQI8ecbGp9XStR9b6uf230kzZ/w4Hif2/4V5Xhoql5yUgEDxegWxZf5iYmHD9+X1k+4EAo3l33dj6ovMJJh5t1hUmsVSwf42q9JoZQgXN55prIgWZyllosrBiZlgnMmOK1YzDSFy36HiRN3BThRcKXJMo0oI7UyEKMLrSJU+5rHQoVda9LI8QzTN2qflEUPBTsFuu740tPpIyZ1QsW5Mf8Oe44DUfE0pCb3H5gc49ZlTF1j9xRvYTF5MVlnyynCwyGXopyg6ksTkX06P8o75OE57wCOezoKIaU9DXMJmstgZhbGVcSZTVxZEVy9mNK+hyWJY5I9+/O4dc/O5tlMoisf2y5LZME4gG/dRlMNRUq+OhH3NnpUwZ9K0wgkPrXg7PFnKJBTNSNS+NnChaTnlKmFJS6ZoZx63e0JxnMRKP1atVpY3vz8qsN4xUooaOdfWX/av1Kx7Xqm4/NDujmlQC/FcsW1Lh8f37d+8vP7y9eP/h/OLk+PL9u3cXn7qcFQBYrAo45Ry7a4hnEGAFCZjqybT21qwNo8WKGYnt8nNyE2gPfIsQIQ3u1zoOAKXgpGYe9X30cCZy8vef//nr/pv9w398KtntGXiQH+sjN9P6uZGKaVcuoD6THefM6qG8BdnEMxTk69eXvecvdqs4ABO3Wi4VGRjuQ5ON2BsASGkASo/mUNLP19my9z/L5+iyRo8AMpRuOe1z3KHAkD4j+bslAwgh5xNuaN6UEdAPblWwCeVCm4aMCwaJOZaHE6ZpElhkr7SxRvfgnY+lX1FQkV3m/CkRFFYS9dhcuR+rPPezI3Z2uClBvrXcpmZI7dBYr6iEpp3C0lJU8HjQPK+l42hNIbNlQWz+RFUm1mNIH0psKhLUl4cuPeZDrPCWWJ5T0HU1sBaKELIuvDCw/DSvETYwEQOiMbASi44zi2rJZkxmkNHfiGEExywAD/qBYHAwHMUPH06Pe+R8rgspvCGD/PTh9Fj3YoGJRtVTC3uY7VTzebjSEMQ0oNnDbbY46yMptFFVCkybOttAPnfNxZQDiAK7vaUgpbKsNoWQk4IbPomlrrPTY6JYpVmjYGt011JPzTTo8VDl2vDC7nGoANlOYyEeY8tST2qzhKWnW+nO7m72avzq1fbL3QeHOtVn7RvnTB+dceukHLZMJfEZaZhKOmge84gOivJleL6PM2ZcAEvkBmXcpk2jRt/EYr9MFR6rGWuUd1XbqcOL6ciq2+66DUmFdWeed2C9dayn17Q+EuT2SwJYhtsvH7oh7RFPimx3RQzzzfEudtk9ED2lwxWN5Pznw+FHhrK1u7e6wWzt7n1kOLvDrdUNZ3e4dedwdMZYB3bS0wzn/Pjk5Kw1nHvu779g6su6v9IxAaohv9kbUJPUhbtijKdT7BXRvOD5skCVLt5bUmWZ3rOBvk2sxxno7+ulq6n+bMb/Gsz4bkH+fNb87ok9G/W/jFF/yWo82/a/adv+klX9a5j4uyf/bOlfjaV/CfWfDf4rNvh3r8Oz3f+xZHw2//9Fzf9uBzx7AZ69AF+1F8Dv0z+LM+BpLf0PJeqzP+AT/AGOil/cLfCI4X5Z58HjBvyFXQyPG/QXdkQ8ZtDfirvCDfpP4LVYsUviYfQtWfInz/qsJ/qc/xkR46+ZCVrP/6+cE/r/sffuy43jSL/g//MUCE+ctT1HpuVb3U70fuGyXd3eqYun7Jqeb6YnbIiEJIwpgA2Adqk3NmJfY19vn+QEEheCFCmLsq1WVbPi+ybatgRkJhKJRCLzl4UUuurQrjp0uerQQof+0HWiXgpdxehDMhq1CkMthdNyXkRPrCygrDLIkrDRd3cLHxA9j0SKL+ugZnVc1T5LLYc840tX6xFv9w/3lyW8lvKnxs3RUzlpb6KsmY29JdmAW38LPuZii7mWw6GK2KhyfTLBfn/vxU7/aGf/4Kr/6k3/6M3BYfTq6OCfy4bswe4n7fpXLrUyVzAROj99arWyHKzwGLCsNIJcG4p2WnfcdAxR9X2FJf0VFDirBOa01sPveybKbe6kvo0dln5fGOinE8x8Y/iEDgEoTBV8BM3yEEYDwe8ltEJRcKBQZYlwwdF7MjCoY+BsMZUaUN/K61ib1cszzcHyu6kczyAxZ0n5BBljfYoQhvKsHmT3YH9Z//yeC+3rXSdUkFhx8Yw319VroVY2yyDyDFYz7uoEujvmE7KLUxqT1nL9/oMdXZTDSOEPGt74g8c1uoBGF9BYOqDxB49kdCGMOcL5VmIXnuD1iUp4ktY13uCBhNc8kuA98TWMEVRoW+fbvyf1O7jXo2UL4r7Nq7+T3Hpd6h1V39t1va1yPdmNvgC9H1GpvBwt3uTn8m/nAU6+M4CRBiASnGoHnu0Gcc21OJPt4bIBZBug4Fd13fhkHVQDQI/uBVWKWJjLAZbkxSEiLOYJdHzyo7zjwjMuZhkvGh1dEvV37eOffYX8589k9LeciKn9Xa9cPQBQljIze4cXKboZp8yWD9yk2bX+3U3ka0R4Zm8Ug1w5/64Yc0CUu9rcEYEHNKVqCrQUqYJFQr22MJ/Pfrx+e/7x+PN/G85J4q4ptZeGf/7tbX580j/++9/eXh0fHx/Dz+bfD22dQlh+c4IuWtz4qMU/MTUGpvmJXnpo+wXz25bLxZJfeCFhibSdhmLhum/Cmtn1c8oRgcpIykbBCWk/7xUIpkRbegEu/9mDhTj7x8Xxx9Pry39uG10JEzw9DTTozMEZcQ3KzJTk15ywmED2tJ0QlFuP/uHL+6tzmAvGdsOladgk7g4LaMSDUijhNcOyfEIEjYHXQtv1mKc/f/p8apT97Mfrv+mfSqQHmhkonq/GS0hMJzhFgtiSU3MZ3yLRCN1s7G3cNKSjbv5r4+TNL0LhXwRJrpXKfhlQ9stkirMsIl/JEqXQoIz1bSaevkZOYZZgkZR1wRz41vq4ghhZx71RmbYcjundKpg7HgwEuTNto+FG6sK0ev7a4+unv77/0JaZW1LjHTw5Lz/RO7Jj8IbvbBY6H+oR68/hy0/vrn4+/nz2S3GTdsfGx6tfToxf9ncT4/vlfKKdtXfUN6DQiv8JJpa/3FOmida63PqqXdu558lFAyVEeq6wQkgvcU8PCxYBzpGmBf/l0cIqeVo1gvvllAzy0ahUc7pYV5QK3c8lwo9BvAbmdP5IrXK14aZwAMHeVv2/8JcP4I17bAZJlHY/JsTWkw5xrJ0LrAjK6B031TiC5yxBGGWUxJo1Nyg0U7E/QPEXfAAOqbD22wZwpb44QA0mm6IsxfqTpn/82cmlrYZAVyEJdmgTGtWUWJsz6Zn+88XpyYdQlQZT2J6t5uymInDIiju9rdJn6MbKMrrxnBxrIx0LonwVlZbQ+QXCSSIg3ORiwy4yDS3NxlyqHuIDScQdET1XklUsgLKFHz0Up5Qw1UPuo3pXMaL0JSEacnGPRUKSa5pF6HxomvBnGbGFd+cXHmeeF9TT7KZncN9NYyJmhQYSw2hE7wjTLChB7yhO02kPMY4mGNzKsD0aVTAZhgj4YFogdQRTvdl7vR/1o/1o7+hmCbT2Fb5DHKepOaOwHBNp1IMzLSjhFM56hKYQ0m0LaLpbtkS5NNdzqPkuZGtH9tj7lCFJVW5fFEz7synPN4VWE5kLAlV4pZEdgQinIy6oGk+0vm2ZsmQiyJDDt7TCaRMMh68nYrt1fhYUra1Q/no+PbMs3mL0r4KKvvqFsc2zSqIKv2OOJ4Le/e30o+yhhE8wZcCj3p9c3Errddpf6Y2QUizLDcoW6xXTpjCk9OEZadhz4fyilunZiI4snQyr2CtQYGsWbB6FixxTuV8+d0YVv5l7QOnPubYWWBRvf64sHYJvtnTTVJSyqTe9iDuR4ZG+F2oybLG+q21FOCVCBRrJuGkUBkwWF0bXwE1PEdTZ2tHM06C7/pibYkC4VdA3zrQ7opIJlfAcrO8Cgqe+Y7vsuY9qwmCTnJ9e7p5fXBZ/GFJB7nGaah0nAzdkAFcTfCAXqa2+lj1EWAIRCJQQRWKDycL0VUWfjJKgrbPTz9u2W7av7yUqXqYdR67GfFVq+xGagnIxwoz+Zs9XLlAmSZ5wNvU9fA1RYAXgv7Rx5igWBJehLFCxjk7rvNbAATGzD8Lb66XCYuc9F8kSV1Xb3n5VGSrHRT99oznGV7VDGuZtibkpl7dHoBNPSWb6KCwUy5WrN4vqWCkyyfRd8jxwFN8TfNv6gr/yhJYrCHDM5LKAylhVcXJqFsDblMe3SJBfcyIVOKtZPkhpjE4/Xpoi9J+uri4u0S66en8JuAk85mnrrvsrQzg4NvyfnxrTSKUr3L+namzbA0EvZ2OtjWkO3OWSNnmzXKt4rZVtr986iXq1zZHDG2Pa0Ce52SKVRGcKwfWNDifkgR6ztptx2MV4QfGs9B2TlDIgQA4QhXVQNIvvufefTv56ffrx8lpvsOur95dt+V51u+HNz6UWw4rrO+h8eLVQT/ywtSeY/6sWsR5eX2KMj2Dj3gabanNTooTHeQFHUp4Nbql6129uFrrIuCq0r6fvTXHwLIpRStkt8GPSpAyBqXnpNCIYuCvZn0J5mps+Eao+gOxyrQiL7uktzUhCMXT21j/tPmrptRdJVpUE9LGi8ZKoHsp4SuNpz/hgxssxeR3Oc9C3T7AYrf0Zg04xIZMBEfUxUBvbvr6wR9H1O+NZtpVjnq/pmQShMC58dlFJhnBcyeK8MvfJ4JCiRC50TM0a6/oja6/fN//fVr6rTXG9Gvu+z2gXCXJHZdUlGhAtFdA9CEZZcNd6tqOF+PWZSbAW5SvmZfi7uZfMY/tZrRgJGVJmXgXNgx/EC/XfGML+8hVzxuxyDv0Fx1wZkSAjLCBaLglc72Qv+LzRlwE17//Gfg9Tfg9PuiIpbpzvuEBXJxd21J7RUU+moS0m9K7IhqOMKopTdPnfH6EdOVFbctv+0Q6qByxoMW9/Rne9Y1mdyRrkdDojDydufS5buUAiLbaDQyDY3iMRjlVuYJwksbCHYoI2/Hgb2h7BqRoM66hgFcKlwUO0f7a3bHtY6FNDYZrK4nCyIxpSAKePjdwCF1OEfNjI02VpAhN/AC7siAF0HVzi/5MzoxTw/mmCu/bbdYMVomVczQw5BLOul9FkK1dDEidm+F3HQvmJ1UQicZIgSSaYKRqbF8evcKZjhshXk8rcKx0UVEL0cpin+mN3VLNLfyNF8oJmlAjoS1qEN114Wvg5hjj1dxSQLS4OJxOfti/fUtE0RcRERA1IHURWICYRxMpBYEOapt5u4SwTPBMUKxLgXbYITpgg/qocONgN5oi1C+ZfEYA3b3gmAzrKeS7TqdFy+I4fFp7zpcdCSalUejXPL3oIu9AnRPpzRr8iybX+RAj9dyFxnN7jqTTvJoVHpfc2vnc0uf1wE9lf3Bgxln1Fpr25IoMhyR2UJrxIRDS70aTcRIasmx5KSEbg8QVx658gHiAvUn0012SzYRmxXDskLdZqXkKbxeAzYyKcptxTbQNEnPEJz6U1GWYdil+Xjm5rVexgW8eXH7dnkO+gMAHH4yKqZ8RrMrxJw8l/tPfidZ0swhDX9wD/g1qm0X0K+G9Oa/2R81FK0Pv3JzMybMhIa5P9XR2iDMEOuWeA1wYtlINzxqqYORrql/3V4WxoCzbQghQ/KnHFUGnmm32EGBEexVRNV4U1fULVtHmFP3CmBMFpPZmcKcoIawL4e3Jar+75Tmqy8wCQzx/HLtkHqNmUzfx8PG7r4pcZXdGifCwBf9vJ6xniQo3RMaSW4Qbic6bE9JpKvqp1OjFTovPLT7BQtZSfHM8ld1Xqb0lt1JgTzHBSL1k402qvf7Vkjgi/hohPEy3vORtRlSfGMUuxgh/qXz/+b7SRcrbxBu28PIhe7B2+Ouj30EaK1cYbdHgUHfWPXu+9Qv/P7EGvCV9hRHHziyRixzlelUg+Rk6EPYRNjMu44XyIRgKzPMUibC2gxmSKYkDQ1feOEqCtdZBUOYJJhXGpY8LMyxzUdaXc5GMOiCggQ93dpjhyDHkpysZTSfV/mAh5D8XOZobZrh+50vLSHzRXMLixaI9mAp7PiHDHbX04bcCl4mwniWvXLONS4XRVO3jzAqYzZhZLyWNaTjr1rJTA4XPtaBd3BptC5POfJnjqo5K3jN8zSAxGmjWD5irQP88vUIlHvRXAIb/DYoruaaJ9PjjyraWAp2rzn/VyfX3YP2wddNciF2REOVul4fwMMz5kN3f+NutvBfSuyHJaWhsN599yMiD1uqzvVb/xOjz4p3cZXA2cns8/+3ir49Kwz48/Hgefa2TKHrS7x2IEbgfefZsTxuX1MRWk/YNhTapLrQSa01yKTDnHnPWCt84v7g71jjq/uHuxPetnTnC8Cjvy4fiknsDKkwjj9rnDeP9mh39+d4Je9g/3AYo9H42IVCR5g870NY/Hiii0ZYPNPfRqZ0CLk1XfHbZN+w7rItoH9nuO/pVnGRExluTfaEy+Ypc/D30XJBrROxdZLiexWvLNxKaiIWfQkUVbekVGREToMo9jIiW9sx80gQdJMixcP4vCmRhPszFpOA36/Z1+f+foDP73YGf/YGYFGVbREyVLbV4JzKQNs0G1bxgWGmB9mH08vvLRVgt+Te3dujigOcoEvdOm//TDP7eDJS4fjHCMpBwnaIBTzGI4moNkGi6Q4Lk+sWtCFZrvjLeqkl2q8jQUCmAlrLdYTBxzyTt3pYAZRlr6dl1Tdl27XE9YCm6XJzQvVRpMnQgRJLluuus/X0OjMR2NiVQBEU6ehpYeMJhlJPFs5AMXIvAjvyuqWXtBrRQMZ+OS2uPaGHIe2c9FMZ9saEO4Ef6i2jfJZCPZDOuEGOB8wH8mMZXa4wLVxCYWmtJbW1NusmhkPhzSr35E+MzWWKnsze6u+Yj5RMTFaDtCVybHWXHjKn6lE/88OpgiSSdZOkUK34brDac2lgoMeIoHJJXGK2RcQf6paTmhub96fyr9ub4R8yi/3ag3sYFEZrTFi3+VWuInhQ3jL0nDXFuIX3Ocml4kQfaty3sMLi1F3qvJKyVfY5KZyxVkOcLXTMJLWYXstogQOmfaK8dC0eAhBs1QAAbJNi3S/2//bnMj/U0Orlx5anFFYsyKlxhU1rdeIIGYM4Upk7MMDUjK7+vVv36vlPdTKNuN+/v7iGCposnUjmAUxuwYLNVGYUjObXsmM8oYF91KDK+mXtBNU/iWGzIf7EcyH+yVNmWvpNwFeaWuFFYKwRgbPbMXGUdKYJrqrZQRQXlD8ybNRFu/VPHsGlj6HSwlGQ4JdPnSVFglspLZIlfvT7d75grp74/FmvhxjbnpuUdcMBxanZ0eBRsomjWq1Xn9sAGYgF5B0JFv25qCJW0ypMVKLG5S4W8z+pRLIqLVqlIYQS1wAHzKfZCBg/iw6YjFDL0/Pb7QZu7YSOLUDxXqUA04hOaYTDBNV8TwF80VTOiuW/X1DZG2xM+MKrWWL1paQJuyOIgg8PdAymY6IEKhM8qkIoHqluQJz+Bro9wmn2ol2m0YX1l+WXNTN5tDZlPM4Kl411V5NGwCQ/sKw+DhipnJ6wlbJWyglSjYQyiJg0CqMKWHpYRXU9doDCdDmHE2ndDfAnqMqP2PX0xDYTpEN8AVTUyWAvygub3xjk3M2dCsaTXJliU1vqK+Jjcp5MJoY0+rhnZVgYR6wp4vILuWlvZyrG/jzLYMSvmIsnohBaYWg6mtF5/g6crAbY5dgTMoO8zsXqYgOmz5mFuWdEsHmOFrnEwo2+ihDUHgxsFG13rQBWuVwqzLAmQ4SLssfvlA9TlxY8wk8anwb6ZcC6I9RR1EgpWLVt5jiWKepiQG9Dj726sxkX5gqMmd8hwNKUvMBvbmJOUjae2IbwPp5gacA5PXukSOGcnGZEIETlfYYfTMzTljCKj0bG3RIWDLkK9UKrk906s6gQ0H0T+TJihdF0xBAKVPmhajN3ZAMKMJJ1L76vXu9yt8ODzq94czQlqJbaxpvOpzkxkz2bqGE3eXdqKiEqA1BZXBSvGhQS9gPCH25bYkiiIrzsPDgYLBZSYhs83B/a16pmtqSIyFiZrgWyIRVSjjUtKBwYjz+lxcz7ReawWeECVobHQcEIUqWl7GANAbDAIscZ5iAfT6IcmEKtdBuFo98pErm8JJDVgBI7YzOiHFF6TZxyUyIPbDS2IvIhBBsqipYTXuFFboRn/Pntn6CIcftfTBKcYNQYfk4CU5IoMh6WPyIj58/XI/GZDXw/7ey0O89+Lg5WDwav/w5fDFjJ6u6O2y5EE7JTS5t4GVAylW36lqvgh9Qu1OhnMDECCsHuE05fdGLRIqlaCDPKyBtWPYImeRQwm4jysBfEDZLzNxL5cRLRUGpCp4iyh2DvPhsoD8c/PbGEvg4CzFUtHYwiuUdpdz0cIIlAn651IFKaJFAOUtwUrWDWLCEPaYg1bCmYfj8x/VC3pTOJMGLmSoN4yJcQaNmBuCXCEvO3YrzioXT8hKc1+clmGvKkBCxS4FGqLuubFdXsX0CO7Lzoq6q47+G2zroPgshMGElA1IrDW4Fr1gcZwovBktUlcGrq20H9QeS54yh3HiRltMxyomPCBhVtMqBOjPGl0IqonKCmx1M9Ik6OkdHkhph3Mi2eZm4WcCCLlNsoMoOTDnZ+tVouZcOCItakMIl15E1zjsdMpGOZVjv2rFZoWtrs8XlGcll8Gei1xqUlF49bEghVYujEj31uhNRTF8xTqVtaYwPE57ttGOsRZexpapCWamnESSBnfDzbnTt//2Zi26VGWE4mdL5DNgV2a+igxm72UrAqkD99tVUrY+a+CLFUB+E+yo8a1LPog//UsXBcNRMMmZW8jzoRmECz8GFqRCXXU3N5jve+eV3ZSs8s0ClntmmRrr655+pf5ebhvhFsoX5ZTuP7OrVdhxxVHK+a2+NmKLY0IU4iydVu8/QacKf0LUS+kg2o8Oy/dCqOCpXAvD3829FZpPunubA3qZKepCksA7obGKrm7LFjXtmoSDqO4mqJUnqLLSyoPLhV89m68QlnZC4Y41sMVzhaOqRERYBFeAooVMBZVkD9SQhTkdtpCs4KmpVCqYJeZM0gReIbXMtCuWUkZCxFhTD2RHHbgiK4hAszLfsmlCJ4ayMJ0ChDWB50GMwryV+bHdzcw+0boMc1PlBvSGBZ0Q/jDoXPWfc1LwXJrUTi/ub6tizMq9qxjrKsa6ijG7Pt97xZjZ8w45vDC3a1g2Zkjtysba09qVjc0y1JWNdWVjXdlYVzbWlY11ZWNd2dgMlX/AsjHjX65x2RgQ2JWNrXXZmNWiB0qj9AkBsaTiiOa+aqq2PCrApUJKYIicstE3WULWKKLokTL6BkrI2l23f+c6MmtvqjSsXR1ZGBjo6si6OrKujqyrI+vqyLo6sq6OrKsja81wV0fW1ZF1dWRdHVlXR1aSXFdH1tWRfdN1ZGosiG+FaPMFr8LfzcsX3LA97PXGTrGUdDh1hSYYOmVCfzccx9yA8kOrETMjUvgrZ3wy/cUO/It3ALUAPpxffT5Dx1dX/8fJX3/5enyGhgJPCPTm/IXNpBRq+6F5L1FSDGzpMBly/rZHhW8kZ+Jr56eXPfTxx3c/96Dl2rbLKcco5pOJtv+W5KgYGt4DgKFI4VjROPoLUORbuYbN8sZ0NLaev28Wwt31Vo9RjGso+mWDTjIcq182tqPSVCQeg52I/hKKYWZSSNoqBr2lDMI/4MjjeAzNOHyHMXizVCaN1czTgwWLYz7JUirNQ92I49RQV4z7y0bQ145pQ6svqiZvVZO+8adw+yyS7edX+3c4Pq1+ehJ8ivUwF5CP6ju2mYd1p28liwYDgULAZ/yC+cI32LNe0hF656ez49GZZADkrnw26T8xFTTQ24SNfNdARPT90LTXV4iyEZEKjIsJ8hIluMzMxSutEI1HI8Oqa3dQY4DCnTl7sTd7YGVO24beFNTENI20S/rshPvftidvLgnCM7blFz/6L3aUXukqjrbI18g3NcJK4fg2mlAlCDQ1Ml+Ru1fH/X5/fxdtb9SJzfy1SWAr9BQ3SrruShAWFV4oqxkb/Xjh1ctutit5RXyr7ggGaucnhRafayTEcPh6gS460qy8/cHzu2xxb0Ufu8vdQO3E7L4ld6/2+kevG7QV/jZHcn/A2MhGqUK29SqGSxfulOdcxRM+mWCLVnBpOGEjkwKeCRIWu86u8JqYo4XlHMp3duM8r5wX//4cgct88HtZJoj3GfMUUvHYUyAc63Fi7/f35hmqqN++L2uD4L8Dw9ZstVou7lzDtYrFveD3RFyOSZo+0equh1FbeBlC0dc7BCtZhnZjtArc+B5vLmrj26nOC9nYiI1DioCIzNX7y1JnwlJ8ZcjjXLrod9iy1XUtRFRJkg7hpKRMEaags2I6RfiOU2jBv5OQTI1918niEmyI+Bod9QuPKSbCliJCPSTx19DFwwcxzcat6s8eVYADmUOIsgSu7DbXz5BgVDTJhf+1rUYPxF1rmt9fXp+dnP50dv358vj65/Orn66Pzy6v9/ZfXZ+8Pbm+/Ol4/+hF241tobkD+a5IQhdnH3YIi3lCEiQVZskOTjkjpZXmgE3hm91bWuG9u2S6IWRlqncnuWlruUO+xmluciSH6GaWzet4jCm7QZKy2D5dl1MxEeS1SG4hmXzXxJTK+ijsh/PzKIoevwCGslXHnsP1CIiZAaAorVA5EDWGytXmNVtqnW5Kc7jVwsomPJRxnYZUSFVSIQdGMy4VwpVj52bhNnrI/ddi0fKaVRxjOY4mydGKFu+kZBXZSF9DKFNBu+MPp0cooRDt40N0evbZr/EsWgJkHy+w/d4ZZBNJpSIstnklpiofXvRgYWSvEvr0KSrFipl3N2Wa+2onyeVMwzi1q9V/9/LFyct3+ydHR2/fnb48fXX26u2rd4dv37191z95fXbymHWTY7y3Ngt3+dPx3ne1cq/PDl4fnL4+2Dt49erVq9P9V6/2X7w42T99vXe0v3d4une6d3Jy9na/de1kZQXLR+Dvvob7Ry/qV7Ek4wDb4/GrWB7ZrOjT7b8Xr16+e/HixXH/6PDs3d7L4/6rs/13+3sv9s+O3x6evD3pn+6/ODrbO3356uXR27OXh2/fHZy83Ns/OX69f3r8rnWJlOWZSpmvzHc7LfCYSBLe9f5DYp/NZyhyP4Gz2ngwlqtD5vdDOfn4gwXaQZ85V+jkuIc+ffnhnA0FlkrkMbzhXRE86aHTkx98Xs3pyQ9hRnE78f4HH6zK3bBJH1BRUyArGDosMp2+V4xNsu0UZURoNdXqeXn5fje8ayDA5GKJHOPb+lyq5JAcDfZeJS8GR0fxy739l/uvXh/s7+/Fr18M8H77gj0rLsbVNR6qVgqZlJWirHBYkd0rConV/r5wPybMgaiUHBqJGIeCBSICNJnqrqdJvbez39/f2+nr/7vq99/A/0X9fv+fy3o8WhYDAIz7nYRhXb7Wgth7/bL/lIIwIDfPnORY3kuA1oMBHEhvj4/n1s4rkqalZvoGp2XMpQJrpnipxbylp8DsoRLpY2FiwtuQoAUXU6R4hH42AD/+ONEfdgmNvQJNpjT2iOgVyqiFlQlreSywTO0aQZ68gfyKYr7suhi7vU5nx8xpUZwQJbEtdFpMpubvcEyc8jifEGbLHJ/4lJB5ZvITrk0cY+XpUXbaer+oFFAxvxmTNOVNl76GaMr+0YvrH08+XF/+dHzw6lDfCYsPnp2czvuon2jjUXfIr0f91xFOoaxP0TsCJmVVsn5PjafqNDagwxbCbF0ef9yOTHKMnk+aovnBtNH9wbkac0HV1GTGBGoPmQaDXNlcKlO6CRmaRcmx9l5PP16iqhQQ2rJl+0mMRSK3ezB8KRud1GerbP4lMCuPWibjBUaG/FXafrdONgkNDM3Wycdt/TtNFIDVBJL2a1ArEOdx6gsL+omOxuhYylxgFhPXgf5k2YtZWU4AobByGRkMh62TbUA8kHUi+HL5BPwlofVfpTrUHDtbp8tqw8kPXy576JO/h5yzGA4XOI6LSqReeFep0ZzSXn1KLQLAlBCyZTVq5KZ1dvD9dp3wPmhl0xbs75TcPwGzIVLcihkOp5Zo69Mjjcs5i59YHji9zhldlWtXJxacIk2Bls6XJcVT2VVPICIANb7m4hpSQlf3OOt9BwOqLJCb33sOVz10CQmnF7V75wSndMgFo/gxUnjq2zncRbEKGp0scB2fc/Pc7+/3d/ovd/ZeoP7Bm72jNwev/ydcPx/L9JNewx/kunrvnsvx3uud/ivgeO/NYf/N/tHjOTZVqde3ZHqN05HeX+PJyi7edr4CaJswIlzDBVtSe0vqN/zny0cdggHfcS7uVrW5r8w79V2YYEEQSVP9gdj+qeAc+TWpf671f/YA17WyYlSq7Gi/dTJSg8DI14wzwh6B5FKSyZkdzqtBQgS9m1EC//a5INMvjo4OXoaLxBLytS4taXlBSPpbG8WZJwSAkKC/+ezxQAdkhmN4gR3QhrqB/f7hq8ewJImgOL1uDS38BMV4ZmoHGgxHbxGRqD39qw835Xc2BxFYRNXSbIxZDmBgvTJMc/Fwc0/VmMMFOtVOmr4B+1ec0vDxGAscA5RP3UIcHb17+/b1ycvTs7fv+q9f9V+f7u2fnBw/ylJJOmJY5VqqKzbQ5+V63XA5PFGhhfqZIEH0VZpo+cky5g+y7suQ55DMhH7k6D1mI3QippniKKUDgcU0QpeE+NSuEVXjfKAdut0RTzEb7Y747iDlg90R34v2DneliHdjGGBXCwv+JxrxP78/OHi58/7g6KB2mcyL4s4jjxAb3FmPsIX0cQtHVh3jcowFSaJRygc49f4yI61RRurlsA5hiaePSjj+1jEsUTWTLuBo4E/nxCUur34o7gk99P6HS8zQO4FZTGXMg7hFT984I4hSPKv2rHU4oiScp+B23eIR84xGSRGemvk1DD7UyGJpVv/gQQSbkbNazzLoKqSJsG5erWoftGZuhXfFhuTn4vboQVSgMZx51uuZR36cQQeSJnQcSeJs/+iFaH0jJFLhQQoHUQspDDhPCWZNjL41f0bDFJfYtfB0V+8vESMjrqh5j73HAGgVEymHeaqd9ZKbCX15qP6kzcFniDDwEfXPOWMkbb21Gfmqrl1S/u+6/L4yYEDgV8APSSJ0YTEBTaoaouXERuixcvzx2MLuaX/J+dj39/cRxQxD6QSW2rOfEKbkrkrlDnCmd5PmZ8eM3fiH6OtYTdI/4zRjO47OHZrI7ZpUSIMJGlzGUn4P9f+yXmM1tbt7UWuFFUTmk5UpK5WVgg9QVksDgGSUJKFV86tx7qpa3lpFTY7Kt1GBYGldpgJhls11qUBoomxFy/DcFQjhmi21Tt9GBYKl+7uuQHBL+T1VIITr9v1WIKzDyj1XBUJlBb/jCoQFV7E88jdegWB5XmkFwmWrWoOZ+oLyeWXor70qr7TWwBLyH3ywssTR+mIDQ8iTFxscvD48PNzDgxdHL48Oyf5+/+Vgj+wNDo9eDg5eHO4lS8rrOdIZpMKTbCa/3iaLr2OxQSCLJ89yaCOM373YwApitUntlwunr1cOigajM5NI+OxGp8upXu+c6nCZupzqlnJah8fLJ8qpruFvHR8vv/Gc6hopr/Uj5qNyqmuYXbc3zJXmVD8gjzV81lx5TnWNiP6Az6GhFP4wOdVVpr//nOqQ4z9STnUD311OdUuB/fFyqhsE8S3nVIcsdTnVa5ZTXVqcLqd6fXKqSwvzB86prpfDOoQlnj4q0eVUP3VOdZ101zoc8aic6jpu1y0e8Ww51Q8xv4bBh2VzqutY/YMHEb67nOrZNJhnbUdl3NZST2GXtpFhIW2eJvyeCzqiWplNtmrDo2S03/qhxq3hilOKP+pVS+lvJDHptpAi4jOK4RAMRbAI+wVU+lzmvSJnmBVdJOp5reOzkcfatpizHR71vK7nHPwcc9OdSRspJXBMfAvMY/NhQexzLGTU8IwIm33uGtdBnjmGLN+iXzhGgvyaQ1cwjjCDZB47rm3aBvYBowQrPNDi/zUnYurbYha7aDh8jV+9frU3eBnHyRH+04ICNrz8DhKuChF+NojyQbN128HQ9M0uBGiTUwdEX+eR4iOiBVfu721Htr1MnZjHmCWpud76SShTROzYJGySOMnLWSkfDoav94cHRy9fDg4OE/wCH8Tk9f7rpE/65PDlwYuqaB29v5OA3fQLa3L4HdtQfUxHYy043+IfGuNNCJa5sLd2UG+vrla1vfhDBXcH04xg+/1h/8VLjPsD/Lq/P3hZEmQunMGz7RW+fH5vfzGvvcKXz+9d4wTbpQ9ZLD1zleZ6ansyYwFNab98fi/N47v9ZGGstCwGgkBzdZTwe6bVhiMZj8mE9GwT5R7KsBrbETgq0vIX75ew2u7Sp6b3s2vZKtLCNG2UcSDDzvfnDEk+IVB9oW2YlvQET02LEFtDc36hpbCrhaslblpXp9Oej/PgKqsMWvSfW5hLPbbp0V8kUKB7CBuNuJ5D/+nG4lgaaYYUGoY0YTYPQ0IPfkUETtH5xd0LPyZhccptIPjmXzewdjf/vkFb52dX79Dnd0Vq8f7Lg/1tQ1P4wSIe5eJaUD0wIK73Z+KS0R25fkRDdm13/QYUTl9Y5eppVqUp0GBJk1sI1CTeayvtiKlxhez29+OCjkHuf+LSZVOCE7OrVLCEV7OjU4kgtUYShai2YLZEo6f1lXGljwsxhf40Yzhcy9+vDO6mzYigPEGTXCoYZKBPBE0fSconSlEvZT48IGgjY6MAglJ/fSPSvwvm+siVrVy4NyCuli/wnzSdxWnnKJVoy13tFRbR6LftHnBeskZaORBnYWKvV7itjdFvGz1DjxlhY7tez7IgQhi0fB1N2j0uPEq3LrhQ9rZgzRCCZ0+zaf58ExglxbONyjre/PnGvF2qkpPumKhpIT7M02d0odey7d/50LRH0yccNCimE22XbZPiKc+hP05hdaeBVknFq0mXlKGbXKSRHvMGakghDR0st7ESVEIgmpnkQpKYCzB4yM4cgnNXGtY0gW4s6HPlh4VdfHN4eLArCRbx+L9+/cH+3vz8Z8WzmZV3ZukPtvqbX9iEJ9o9TArLDNtMIkkIK62IX4UaC0YZYkQZF5Azqri+6BnDyAfg3CXeGxgQbYGt0oGOCIILh0p7jVCUi1I+kj1/XkNPKkUY+o+2sf5aZYsVwMEqGYBQ43zXbf81PyyW+ry4x9IT2iu5gIyregO5lOLpERv+PKOTGZayomHPWn9ppys6f8EBHdXQpcYro0mNK/QENt8KcqOGRC4e8QJuglJvbLiikTZenCkztB0e1r9uHR4ezBALN/dVumswod0c5q8DYrw28xdbU13Hmx9Xy7+iwDPn73/B+Wt8ujAcFs4S6RMIlx1yxvV3YecHyT4mDSmgPbLevDBvyTDfIFf+U71gMsOs8Qr9iFAnhRkik0wV9ADp5pM39tu2p7vPgaBQh8UUxYqgAVH3hLCSC6buubkE1TgZpnKeCJJcr/ZOdxXc2gsiwNy7W6qWQZYRbwNkPjB/CpZ2xvMNxjIfhmv0xpDzMOtvQy/SRviLqrU2HrSVdUIUERPKSKK9g5hKktrCNgwF1zb0U2RjyHw4pF/9iPAZwCZ4s7trPmI+EXEx2o7QlZjabgo4ywT/Sicmt4lKfd+TdJKlU6TgVj/rXOvlTfGApBLd0zQFFxPOvnuSpsD91ftTWRiqmEf57Ub9MVKXGOmjnBBEWJV+XMJszeYWDrzqBcZkx9y8qXW1Df1zjs9Zjp3CrXJT+Enh3LAXDuOiTNGvOU6Ng2Q/A9czexEsGMBp6rg2rzjka0wy42aMub5B6q/lLLG3lxkrEEH4BLuQU3Cnq1IAkV+LaWIsHPw9NnFlH49TrvMxzBxjxnhxJpZ2WC+QQBEVqTI0IKkpzJvd8PXWoWxBQtmaEBKWKppM7QhmixgbgaUKznQbsrGjlO7FwKu074Lehjl9lflgP5L5YK9khnql7VyQZ04Ie7lxNTrFGBsmQKUPFyUwTYsAQcO2xrL107vi2TWw9DscDGQ4JDHUQGkv1SiRlcwWuXp/ut0z0atbxu+ZFm+xJn5cY2B7LkIMpjI0B8EGqgmgVOctgmFB19+YT0BHvu3zA86OpqOjWInFDxH424w+5ZKIFabCfLHT1V4gQprsAC5oX/xmXtQetBSebGzs3nmuiDLjsGvjggc8N0YXPmruptBKmdxhH1SwUWK41Xotsl2Xtf6M8R2BCBqB9CUuglAcU4ISad1WmARMEhdwE2bwNZo4K+MeGDBDGIBV7G3ZnB6BkZ34hW3RQnmM2YjIaLXW4mOwxCZ6z8W0EDm44hMCKaN82OQ3Yobenx5faNEeG2U/9UOFZqJ9OxgrEyiSXKHil6sy22P+WbL1gf3M6Wurb9Kv5bMpC2ekp70X36Ws9p58nA6IUOiMMqkIZcuKE3bR2uwPoGZdNogRDV1VdGn2OdqjDoJUbPN6OZWKTHazFCtt1JfeR4a7FR5+4aqbyZclvQLZ8qy6alfFHWNj011QmKb/pYN1CNkmxsIzhBln0wn9LaDHLJf/8YskwzzVG/1GfymiyY3WZfODZvjGO9kxZ0OjFzgtH+Ysqbm35JIky6t9ncLH5Zqy51B294IlyzAMrUiv1+anIH71R8LlmAt7J+YCpXwUJBvIBqgKDGfCstITPF0ZxITH0DMpRnpmhI1bqmhhJ6z/WcPq5r82bukAM3yNkwllGz20IQhcoNnoWg+6BCLdupyCz+ol+jQId4lam8N/HZzjQirfmHtcEN45yE/iIBcC7VzkB4XzvTnJVf6+KTe5IL5zlJ/DUS7k+w26ygXxnbO8jLNcyO8P7y6vi4cU5iZ+D47PgwvwfL6R4+KP5PKUef5mPJky2evhoDiaOr+jld/hxLZO7oTvx/JtewmopS19AkfCZ7H90f0DhcWIqLU5RdYhvGRFsi6eUzuqvxf/6ncOLFlp/pFcrGUk8804Ysswtx7uWjvKO6fuOYJJVrjr5Pq1o/wbdxB/pzCSFd4f0kcMEx2v8aioZAzSHVH4+4WSHs1ILvWRAf4K9BKYEFNXg9FA8PsA0cJbhasxmdrKOTnm90ifhAzdk4GDZoA6Oz0UZaOiSMcCuOSeXFcg0z5PMSF6mt/rWLCz1605vRhzNqu+vxOhhajrgRHxEAs6Q+x3UrlaMeWB9l2XtK9OMh/4bzRN8e5R1EdbZk3/Fzq5+GLXF326RHv713sm5fsDjvUv/rGNjrMsJT+TwV+p2n3RP4r2or2jErFbf/3p6sP7nvnejyS+5dsOwGp3bz/qow98QFOyu3d0tnf4yi7Q7ov+YdBe1C+TjIZ4QtNVFfp9ukRmPrTlMsgFScZY9VBCBhSzHhoKQgYy6aF7yhJ+L7drhWs+XcvPH69a/pOBb2Ij6zC7SxULYSt88zYBoJfmulCrt0YVP/D/4LtZK8RldEsEI6u6Ss/wZmb37BhUKnw/byceRodRf2dvb38H4MJpXMfVd3DJRk+jNw7yJtCaeYryjzppuivb76Ulbn5rZ2LCFJc9lA9ypvKHbAsW9zXvM1xGmtCVXcekKQS6sfNa1CC4lWFFRlzQ38wneJV5ylQBwaOPGnuUDwTHCUAAExHrCxbYYkpkcFf75D8uCRryNOX3emTbd7tA2YCq4y2Ps7f9BqWU5V97aIJjkDSjX4uiNivvWcijT5doyvPNTaE9Igz1a1AOZcs2LdBDSqXqWSiaoB7OwN/4ITOe5fqumkToIiVYEpQShXIJ1WFoMNWCYnoGzAxsuJnq7OSyp6WaCZ5xSRANarFxkkCv9fr6JmC17S2Ey2i1kJAze6ON6dzrR3t1jsNqWQjwORdwSbUTVLr03KXeVbDXnb+/P/646EVHf9ZdcbAo6u3tlX6KXvX3o71fkcKjLbltinQzHN8S5UH9pKmjwxJRNgIYL+iYZv4TxsdS8phahF09BHPAHxBjgSCMloDf2Ni3MrCTmYPddWb3O+2jwT+JtAzquBAk5iLRw1E2Si23Co+gnBesSw7QRtAO3glxbKB3NKG/7lC28ysiLMaZzA2VsmdDRHWUoRKmiZpmNA7qim1lGsCbYQ/eIgmTXKAtEo0i9E9CbnvoZyqIHGNxuw2IJPSOpFPkr8YQQBR4CJ0eKpKgjBHRuKpmCGQ+ZJkrFliiLVdzZ0e1fyvzv93A5Hz2DH923LZczmHPWEs7LiCSOftNmbdwmndWoyta0U3XS+LEofBoBPbCDvlp4NrfBsrttDcKtdyeIjX65z5uh/S6HYYAAY/M7wqLtekChwmVsSAQqKzuMDsmUBCM17QuQyrIPU5T2UMClF/2TGQKJ2iAU8xiIuQS8YaVBdeB0fNTc7nSqlL0x/CrUm/r255lKwxJfMos8jZwB+HCtvzxXEmaLNTJxZ8cecqIwAMa4My7I6TmT/POEn2UlIZboGIY1xKAZgqILYhlKYzYomDXuJMrBfuBBqR86FwSfVqIeEwVMZ1dgUE1IzEMiYDBY+oVuKkWvsv5/DveemwNwze0U4gZ6Lkuv1yebev/MO2jUvjgnyoK00OnDsWYC/TOWoXtEkaAqcWXb3Z3f81xOpWjHIskMv8NPUh+vSeDMUmz3SG/BgS7dFd7oylJRkQPvVti8Np59kRGYzX5199gIE9YWRjFZ/+9XYsw5lAfXZV3vRO7+a8Nx9sS+QZxqo8lB/OxIg2ClliliT3UeUlCMgbAN6tkpYUrR9RC0DRorxZxMdqN76TcrYe6//tl6/4gAQffeJhhZi2CX9QvBGxne8ZK73LgFE7v6rx1IzRsuviORBOqBIH1Apu5O8S/wuZJ/xzfkWuASrgOCJTXsSD6kvivE2iFU5o6tOmUGB/i7GvGpbZJJ38/Czn9d61mnDN9K/x0iUxPQLQf7e1HL3ohIFhZNPaG+/niJIraJ5ZDl6pVbz1nu4M3TfDmzBM+lXOWrn7b1S1jw747ayuilXldWjJOItYobZ2fbjvYGduurATlVH+4A8dabSN0HoJyoLz8lGwnsQO7/IlZ2ZcHbreF7sdYXVN5rbcSTbbtnqnuk9IMM3vm/PTfDWu5Y/pT9vv91n0GAbubrK47yjESxACNNhuz0t3CWjYDrj6hio7M1bAkK7doficllfWrE1z9ysUjujOgTP8WIvvxiP6X/o8fvJxf7O0tIWatsNcr3UT2Bs4FkjFm9ere2O10r7/3KlpGofRcjIjojrCEr6rvzJWFMWtyX4AkZEiqZfmKMDxI2zenjLkg0aDcqvAhRocpx6rRqb/UQxqcIoHZyL7/96O+vpvs9aO+RSrT/4kGxL1KTbhUSJI7IkIE37fa6ZZ2RK5v+9qHlZJIOYGEAzhRspRT5YQ1IUrQWKItrBSOb9EdJMcVEWgDnvuVqmkPZYLe0ZSMiO2eYDOZFBGmmcR2D9FJhmNVjBrmJekx/Lj6ayMBw+qhbBYj0LRtOyvEPCMNDkyNw+kuL6DuOwmPc83ydq3vfhQdLbf0hN1RwZkeudUb/Qp14Cwk8SFlwGyKPGQ0aI9duR5aZuUgW4UKoseXa7Z0ikwyLtZ11a4sdQ8tGDxBT7DKzQJoUSc0gIoslgkS/+waxk+7j1pIfbXvHRAi+eh6zZUiTUVQYuvj30+3C0cE8DYVVjR4tIflAX3G7JayETwtbLzn9xs9tPGBJDSfbBjt3/iJjsYbsDT6covu9vViezPsRwQNkdXAMeTqFnMpmKoY6yDqW2zGKcR+EzKkLBCZvQMVHy6tXaBd8AkqEb9nJDGeFWZ4ZOJ/784/X15Fn8TItB1EW/ALbYTRl8sdzUuCGGc7meDDoH1g2Nivh+7HXBsPKl0HD8XRmKQZnB/wEiJJDEqrPXOwK9ozzDgLVEcRPJEIx4JL4/zfc5EmDarL7pKIUamiEb+DaNCONV2gxvXGwz1wtVFjuzwr9Gy8RtR6N4BoqCULxsUdtBg6/SoiyrljSJ/ZXFBlFwoJMsICclsC07GchGsvJ3qquETCg7Hir0f91+UQMfQSPCl6rS4QJd40Lcs1FXD4mLc2c+vSG9EFjfXmguGDVq6y1PE9jCZT05MsnaKUj0a2Dxa6en+JtFE2L3YJHVE4aV2f5qLx8p+cdEicK+1vogFlWFDtP13ufjj/cFaejdnalwFP4DNwQON0KqFZArSTcVRyeLm59Xv8Z9dzJmxRa1LQpenJpb/dg+4fPh8Acmpv9B+gh+RNBMPYEcdYjol0Onh69nmHMH36JOVm4YIUFSi2aZL+5g20soMWPqVntAEp0g38+655nzSE6C9Hcoz3j17cbHv2zu7somJVpLwHZMw+Abg3wuIBVfbKpDhRmM6VRh4horN9ItCrbQOD6EalMgq6bt7Y5ld2RPhznFLClBVo+9cunMIm1scR1C+tKuPatzG1bY0DOixi9Nbl8cftyOS46vkkusNiqk+QeGarmn++k7xxRoL1glDYANrA6y0Kuc9mVYs2YHoHnH68RFUpILSlh7unaRJjkUh7XSiVjZH6pvGbfwl6e7T2aCD3RaxHE3Lfg1wTpc15fQf/OiHYJsPQmh1p9wUdS5kLzGKCLs0b8Unrbu1eNuvQmFzWsd2+M7nlaR2bkbdfddOL3Pca165eD3368gNgtxdw3NB9fI6GlPbhY7VlrRuRf9BKpS3S3ym5X5LBdes9vryxOGfxE8hgDVuQtxdJZccsKZY/YLtyxtU1NPxrwWpS9inKdyU6IQj6LdJ4POP8mgZDKWcjAml0kibQ4+IOpzRpiIPv93f6L3f2XqD+wZu9ozcHr/9nv/+m3z69h6tr8465Kk4hhrMol3uvd/qvgMu9N4f9N/tHy3Fp2ihc35LpNU5Her+MV5W2fuzm8+lwJiFFlXpA3JL6Dfz5svWBFfAa5+JuVZsVEjb0fIZPm6xF0lR/ILZ/KrhFfh1KN1o7qyz+7ANFtfLRd/7saL/1U1AgJPI146xda9FK17aSHM7scEXPLiKguUl5sU0Sz+KMvjg6OngZLgxLyNe6vI12zEv6WxsFmcc4hIPob/6hK1hrmeFYX3zRgKr6285+//BV+3CXoDi9Nq+FK9JyW+ptpnbv8nBMepWvP50hbAVGTyrC4uCNAggY2qwKaCQDWpGNMWR+0LiHqAqqMkz0QNkID4eLaaodJ3jmyjJTrFEavugHXSv8o6N3b9++Pnl5evb2Xf/1q/7r0739k5Pj1pbHh5VWbmTPyyAT4RJ4okKL8zOBpOTJhMDTYLnXIrLuhQuNoR85eo/ZCJ2IaaY4SulAYDGN0CUh/qV+RNU4H0A+4IinmI12R3x3kPLB7ojvRXuHu1LEuzEMsPv1qP8a/ica8T+/Pzh4ufP+4Ki+a6O+1hy92FniGLDBkfUIAUgfA3Bk1TErx1iQJBqlfIBT77cy0hoFoOB9Ha74T3PDdzyt4xW/avZcYE5vyrl3/MurHwofvYfe/3CJGXqnb+9UxjyIAZj3HrjxP7mWrPXVviSQZTlct7v9PCNQWvCnYHgNL/I1/Ldi7w94IbeP86v19oKaRPPyAq5XrdoePMTQjqbkDRoQSNPALB5zYX7ciV2Wsn1TfGs+UyLl/4TxT1yPTHu26q/79zH3tAUv92lqW6fD84cmOXzF8S84UGA55lIFB4mRG06pb42eYTV2Hw4+WEOg/ndKMkFieDXbgZer4ovwTAg/0XJ9JWau2LNEn+YvUnRCfivQUprIM5UxlQ9P6MjkTr9BSuSkPLqRSGlYDhvM/sr8cF2nRw2s+/WBdDNIaRnlAhbFTFbH3wKi1ysUfm4uWzDosms6d2QtXH31ITKiTKogyP6gjCAsZL6L3HcRTdy2iFOeJ8UOONE/urwXgSZE4QQrXL8pPti/mqSmuPRVSN4t7mY4Sa7hA9duSP3JmEhpki/DPVLiHL4U0QkeBej0BVbVhO7gQZzs7R8czleQcz0COj/1acGGXCcRqx5/Rsd6peBDPE1CRXUEafojQ5Xj9YGlrv3w3OUO5nAEFmnC86fxDPnPt55pAe2tzLWoGgezTXA8poxcB6gP8yezXwhhIhadK8w+vF7AoM3/1qKzZoKDFVtw4ezH26+bIKPCu5w/R+mjteM7s5Dw+BZ01dqFU/dzzfYyfwPfRJ+PaUpi5Xr/m7/pHS7HXKhrY5kLX8Mdx2a+HW8TGo5NTxZqV4RpTgdAR5zxU0JhBQKr/0qt0Bqm0han/Wxg6Sq3yxazVr652KTLT2db4qM/o6tPp5/eoJ/4vXYvJjgzKCf/NUNL6aBH8w971GzPkbfphoTIaa4+fwu9/cn8VDPIORvyUFvtsQCN252tCRRU/75WPe25cXZyGSIeuO7iMiKxjKaTNLKfM0W1WJi4NONsp/hmuW2yZWS+pjcvTQkz1A0x4DwlmC0o3mEhESiuK5Z9dl4uo0FO00WuAf703th7dbrXf72xGDmfLhHMEOZx1RMS84TU7oN5tEgliIrHixPjZjEAy2zqNfA2HxDBiIIUE6uHfw1/VzNu8Xfvc5UdqGJQFGrhfKtafOlBy1oier7OVSWe8aTe7LTazIEEMm7CXLOLq6fKa2z4sjNd8AR9OT+tn4hmM/OUfrX4FOcXszPAxT3D8dOJrRhxdjKezBwqj5zMwdw1TFa5Bj1+QjdgHTKFnvH//3//P2nx62ZJsmfEXx59GgV/vp7gLKNsZD+78ZcFTUfAkz09JzibJRngmE30bu3oDmirJ16SFErE1o90T1k94YJkKY2xnMWBfpz2FuM2bJqEZCmfTipBgcdPXIzbMDGEFId5+uQsBwM3TP2A17rsxH5Y+1aS0CHUMyukxtgkwMMfPO6xyJmiE7K9Im++LRdmautc2HO88Cwu/C9qxrV/LHwKH9Co8wGKsVErB4B8XVQydoaoqHaYc+2wHONsskOE4MFt9PjiAzqzvyrngOhfFhA1hmUu9Bfm88zFbDRwTlDtwn2rhPTK74i4F1SRuht7MRHES9vPVAIgmjsVRAsVnmStVzKXMwtZyTyBN9Y3aG8xfb5ylJTA2PXtaEJjwSWJOUskkvCU8YXRr4hkPB6Hgf65nOYibeSxgaxjW62sCPoiUlt+KeMx0Z483HUwS0BdHRXJlOEJjcsPJNULWFHRqEf6U0WIM6asWWZXY6J1RfGYp05qFt+9h6AGZsOkPmwEUqoVUqWT4CNp0kP0UMalpIN0auLXO1hKMhmkJEFfPr+vo9anaQTJsG8ODw92JcEiHv/Xrz/YWgvz858Vz1qwVW02sxRj3rGsF3ZAeBuJawe1Slh5Kz2gAgDTUEfR4eFBGzqwGj9WG7EaNwjHLmMbwfyakzAxZCmKYAyLrNpAWUWxWm0WgUeTUl5iayLRsR/FZhdOARsXcg9cHR+GjgwZHhHkqFY8e5BSHx9SqtnAt7JZM/XXy+0jpbICCL20KCgl2GClc5NuV+3J8dCS2HGqFIYc1wXFSvHyMU9KS9TkZM5d14BVM2RbTucwG64twQkRsobemdsTQgampAaPfAFOYsw4ozFO3ZSOnwlnVHFBEvTT1dVF0WGkJSMAJSSIWFDyczMEKux8tkMX0FjtKS3IrFSZL6pg4S0ql9eVrLN6F2ruqkAyLwxmYBzscljWDJUl3poXoXhtZ5A6NkvYbDh4Lm1fgvzyj5o406YjRLEFGFkLOeDIRSkdEhRP4xTgEsGXR2MsEY/jXAiStOSnZnM07Y3mrfHQGiy+MRZdk3Is6k+NhIfxjgwLPCn56XODJZU/V5e38mcZ45Qk11UAE/1rykbXQxwrLvQuhH/VgwUYq2d3roSP0TDFCtlIjmvftGNhK1xIzHjlcMD3HB9IX7dM4rfBOq7kdNjeRu3OxAYqL22fpPBhpvna9PjwxflkYqv/Q1fUdWsiE6qUwYapO1Iat0wzoPwSJFZw4p+KtgAt6XH0uSULBmxFCCvb7toDYEYD5mjBPDYeYEX/swUPE4IZZaNhntZqBsTaK999kNMUs1FeF85q4rbmYJ9hdkl3KuyGlQk+EngCTckcjaYLYVu3Y1bhlyawovaPpdF7HCbmuG5LYMlaN6kvQVZwm5uQey5u103UnrB1E3ZrwnzcUWAmDcrfchfTKo+PfekFQN2CKHR+Ou9EqM4uIfDzyCSKq2J6C02DBUGbduxNdE/T1OHQQvJwmE1c5Dk0jMO4cmSakfQvxviOIJlhJm1zEEW+zj0Kq4yrciemZV5jzIchZd0F4y1YjqlHDM6wTemaLG+RUYQ27QVus4c2Bzi+1crDkv/wwWYPERVvt+BjQbfs6XC950jkR9McTX8E6uI8TnGon04wMTcg0L5xjDvujYR+PLtCu9prlrtvaLK5vXC4XAnc1j9+rq0Je/P81NkcoEzfJIvqdfNUMCD69iyR4nO4RJWnFiyqLyzrwqch7UE3ucJQqafscrG+J7yrhIeYiQBUz4nf5UKyJCkkG5MJETi9forT5syNptfcI09DL7CcQX809wS66J7178Dt1t/l81e+Pb98goiZrk1DbqoAatDTwFQ5614ls+ijAh8z6N58WDnUTCM8M6VFgnQ90eHtFoAl/Yh+DmkbILOp8wyrN/Qn38f2Oha8yj+sZi1y6+at4V+rX28gsy6HMMyUgg0iF7EPz5WX9oC80Prexmsz38ofeZCljM/q4AJ3kBWw5BMuF+QoJKqcjvl0NLnUzAVIWihjuk30z/fDgnyA+hjgzPKGPZYef5CUOjbxIBO7cI3uIRMy5iIhCSq3kX1QF58mPbPu+fwJiXxaV2VxwhA6VwCh6/AmpR6mdACafNSoYF4gbAJ09kQsXkq0o96K7UfkHZ9fVLjFyjIpC9ZbEVPqwWoPpKLp6SU0fwo+UYHfXUB/Pvm6CSftek+j2og3etTpUNNCGT3OdEGmhGuMvITKLZY+9iTm7TwQcEaEFrp/siZVRxXdQ3cl/RfwKDQTvmzmgcsTFqPHQdPOkblLbcRilJtWAqU/fzBt64Y0VYB1ZnoQa4MKHUEpYNaWKkkeWIjSYtR4k/MQmipGyaALeQEXt0N3zV/0jlCi6WmImiFmU7pL6zJUgcV43EJfloVihlzY9670Pn3s7TkFFKkybFFj4npDeGp+4pDBJvYrAChXxbSotuapxWm+FFE/Vc7444sPFtf1mW79y4nOU1V01m+i4XqC/1PqRGRDu1M175ayEBkf9Mg+Eco+WFvBeW2c3SiLxVyXosj3lYMQ8YAA4rri6AZnkx1D0E3lLV07LY/aMrU+XD1XjZRfWe8pVD2LsU9Zxa9qUL6GTNGWhJya+PTjSAjMc8vpA5C1x5EA8OfLUnEG2OnLEFBgOdAijlkf8Xio0LXVes7JJmvk8gRotOMvvrZZhZTgF83L6lHsPZSXmRwi/95RnPHZHYBl0WGbDyAoZWvuscuB25ToHzvvuLjHeiD9X67v/D92PhOc7pxf2Owm/fshTlOJBji+Nb0gRvSOsAJKBbzFIRdIkAlXxJG+sIhKCdE13kmjmC6CTGjbN2ExdTNBx/VWt0sTGP191K2IynbqVqtuRkALqltCpKIshHpz2Bkzf5jbpCb4+EwX84QoTFMZXIuDWc1CZji+JWrXLqMnvWZUE+PPTY9+32+FwiL8J2fmSdIk4hlFsYGwRfuV+FugWTpPStM18HG7avOST9xFOxSKb/YRdsdCeDKgo5znMgVE4BJUpLmra1dJ8gmBYipwwBFm6Pyih7B7r4agT87oVyS5FnqE0H/z3CHG4vQeT8Prr1TcXqQFvvd70b753kT2FzdGiuUdSFgZijbJoZxUmYvtTUSzG03NTWQou+mhhGSEJebGYB9VCwgbkKtCVAZdyxc3OXb9Sq3L5puhTWN7AtMTrFDI6Qlmmj3OwCrBwzw0Fri4O9T8nV/cvQi6tyxMfMkYNJNfMhAmMvDGJqHW8xXailqO5hevSSKuw6fdeY9AXyQRpXfgBgPmfGYzsNvqTDOTQgcdV/iK0UDwez2qy5jX350iPlSkOOnkmN+jPNNaek8GPkCR8hFA4Wn98mW0GRaS2IafZnYju6jRXtTfZhrOYC7oiIady1r6r1+YJbBSo1JIq1i3AhbkA/+NpinePYr6aItejDkj/wudXHxB5r/Rp0u0t3+9By3A0Acc61/8YxsdZ1lKfiaDv1K1+6J/FO1Fe0do668/XX143zOf/ZHEt3zbPWLv7u1HffSBD2hKdveOzvYOX6FLPMSC7r7oH0Z7Gw+pelMaS0MKS9v0lUs+VPfQxwsWFscQiaV6t45xOjRHj5ZkD5FRZEubnILtGriUyaBmxz+wcxe4PjZdiQMkVy3HhxUkfCwolDiqklSNYrSjqnH2v89qJWrSSq0sMy4qCZLf60PiTTR5P6U0QrMjGYSPY85iIpjrW2qIKCh+bBZKc7jDy8Lsw8W0OFxhR2rVysjHiLDxZWRujOPxTyBzUldrXzqa3zmaJf7AC8inCnHFY8iWzOMxiglTXPZQPsiZynvonrKE38vtctjeL2uCxT1lj8zFncdMYOKt1d54BLMMgKBcXwBoyDkTgmwqx346Fj7w/+A78ng+TD6q29T+vLSdhRlAHtSxhSc0nT4pYwkZUMzacHRpybBqB09uyRgr7RLrsXpoKAgZyCRUwRpm2uVXL8bNXj/aKx3m7RfILQaGnDJ8732sGhYsGtJTcnAYHUb9nb29/Z2RSWh9DC+GvgdYKkBGMiJoqal6TfHckuVpx7bfMk5L87j+pEx5HNuReWoskeYwdBEq4+jW3/YXRqowWLyLp3KVFx/HANUx9zSrvXAsmAv7nPDfhnPLASolAbXM+V4dlZWJ6+n0i9OALftcwRFLatiFX89aJtrv8lzuECzV3g5+wO/xilHGpP4Wlc2xsGuxP9dc62bIbaV+9ktPuVyV98vfQQIVCrw2q/1oAt34FxaPhWL+hrXZcrDmSuyobKW7FRz157WYHgi/3lCO4oduhgXAxCh0Jp+TZjPVA6Z9QbLLJfVPshNWqGVlB9RIxxdO24uOmRbaBRZtltynpE+Gq1wECkhBd8s1kAWA6uOUZuEcJYduVy/hBpZOtU8Y40zlwvqEtpOHdhQF0U4rQA4x+6BhwDZs1A4HMBaW11ZoZ+3KKixm3ILrVC6YAtm0yjKK8zQTdBEwAUtXY7LqO/cIFuM0tY8o91jaOnA6wWKKMiIyogRW3CZnFCAIM5TBqlI2ur4l0wXIm5eQhSVAchk0U61xGRGKElnJzzBYK0Mu/Nwoy0XGpQfPqKXviRKvFUdYSh5TQIahalzAv8BU5dAPYDdKW/AX5KNC8jV8Hm0V/23FuI0meGoqXs2e1F806CAltoGlCJ1wJvMJAfTXKYrHnEvz2oTFgCqBBU2nNYIhX2OSqbrUtWrO8bzc7PqYnn3VSPX9UvB7Nqvxc2tCqohDbZKYr9z+MnEdSZTB7/H7Do1xlhFGEotPpu+hAyzDb0X1ZE2IlGWECdT06oDqd2IttU5algA7SxMNPMnTRwnHjFAUMPk0vhIZDdNX/NGHJq8xkrU0QSJfLR12kfQf/i98hwutRXGKpZyN2wUJpJgl5VJ3NA8kasEVOzeoUFxotVJjIgLNgjRynI/GCl7ZTZK5fQs3wFcCMV5nRVM+m3zbYhsGoZ5SlN2B7WvL6SKf7bYiuDKPUTdJ7ojQd3OPKRdzkTQsGVh4UTXVreecya0URZcoU0u7yv2N/eKk0/IZNn+rA07W9TyilqrpODYDEwXdNDwN6Ezvs80TyMZgHHqqM30OK47+h9ys1nV4TFd9Rk/dKIhKJBVNU3h/p0IbWcySIMmD/Jrj1J2CJQ57aJArc6JmKY7JmKcAUSYI/JjMUnDOYJshSVWOHYhEZVRNkW+VDuctMUey4iPYvlEIyRzgFuyAhOIAlHrj+OJDCFmBPphPbNTDVOtPhzgIdjwjkJo/FAjPhedj+jq7mH/wpUhwrmY7ENnbTocJ/b1hQnf4es+Er2c3H3m4K1FVhZ62kFP7qXzoyKltChoiEZV46DACO4zADiOwwwjsMAI7jEDUYQQuLewOI5B1GIGPE3yHEbhWGIEhL4ktwHqUq1NOPwpHqeLhP5B9VpHZR19RjUcjQUamCiZQ/aiWHJnXg31UWWpiCzVdiR/k7WH+Zng8ruXMr0uveluuCZNKkg6vF3If5oFscKECqx/QonekK9MzwVvGUUIFiRWKxzRNDNqJeT7tNObZNWb2cqD/PahH5GuY6Qv+U0YE5YnDfcG14zYt9CKKORAE3yb8fj2ty6wcT/RMts1I0S/acyF96+iZoENIr+B89u7arh1zgSZpQx+mPpZzVVZmU7Ebm1I6D+IY4zTOU5N/G34fkFf1OpXcNO0aPI1/tqCj0C6GuLTv8AZtJoMo41KNBJG/phG0ktJ+hCKTLMWKRERoN2IzxvGYWH+i1eEp88Gz8HyMhrmAVzSZD3YSekdDhxyeBbfgAbDgrodKTbDm+UUrOT+0Vj3dwdFsChoMwcK1LbUHBnjLUc381XOi6ZSoPyPmnBBzzoeHTocHz4bjMmOPPRLaHgg+EbpDNu6Qjdutf4dsXF7mDtm4QzZ+xBtCh2zcIRs3rJA/pDtk4w7ZuEM27pCNO2TjwiR2yMYdsvEjbXiHbNwhGy8mug7Z+DHIxg8sZIjH9qjN1AK0ahWYxx3gcAc43AEOh0LqAIc7wOG1VrcOcLgDHO4Ah6smpwMc7gCHO8DhDnC4AxxegqoOcLjNi0sHONwBDlcZalHY0wEOd4DDHeDwU3LQAQ53gMPmXwc43AEOe8XoAIc7wOEOcHh9tLkDHH4ai9kBDneAwysAHJ5BG5vzJFlEKnIV81CWdYHtJinOE2KzDG/Mq5Kd+QZJOsnSKUoI44rIMqzkXQn7WLv2Mo8hr4qLcs3SENM0FyQIJBMhMxJD5pwDJWNKOy2QZ5oJnuSxXrSaRFOv6Xa2PzUvgUXPky5S48DyTOjmAYw8+yGpsMplgZAHod2Tiy8AlTchEy6mKDcYgR4Boqha9RzXRXYWxseTYahp4dvFjfnajYs5SZTyGKfublaB82qIycdZPt82NKnSTZzlM1NruWmBytnAFqoov+IKpxHjYhJl8WxguwHrzb3pZETEZfCqnNHa3xsxWAS5Ec5HLTKh7Gh4BFqsmYPqPZkF90mXa2jg9SCzBks1+whvn+EBCA5R96ADMJh2pCL/S8+kjYAEJaQKCcxGpFzPCJrX12Zvr9//HzNRUKO5Sy6t+fLM6trd0GaBZ9a1kmbq1nMwVSX+zGpWcuyWXkpNiaU+mqETxyqvIbQNAi6MUBgLksC6DQUhdVi3xW8KuCRSFxCdKVRtklaDvB6Q2APBHsuVps2xRpmZOkLnUK/nSuC1DmMJmeqgyZ8uI/SJofeU5V+1+sacSSqVLMCO/JiVSbM018PGY6v7g1wf0xKG+3T5D/PmDScRZM6HxAG06ZggyrA5eOyC66/+bOKEPft9AIKtzKy4M6iR/aIe/GZmY5Wz69vuLPvtYGs5++GiRD3Y/SXAVsvgDD5j084LbfoS6ryoZZ+n0Y3W/QH7Ps/CN9v4xyn601v6p7b1s9a+Ku+ZzbTAqn+A7xTQv3p5KYAbaHHU5fBnggzp1zdo41+wbv/eWEgXJP1t/awbJDrDuXBHRWi+w5Ue41p8AiFlNEvkunD1mUioJkeXRKFL+huJTJXgBIKcgPM6wyiP4zyjJncMEqPsZ7Y+H3/YntW2uKpXC2mbgUjRWp7SCVUFzosZrheCwUwhRd8EyfWpAkfJYqanbP2a0SPKF++LL8VGMPR4UJeC4vIun1c0tnyl6/lpIZdcwA3V0nNy8aUeVykeVs1FM65SmWuub1qKpFP0DlOBLuMxSfKUCLR18u5yuyKIqo2bD79hIC6idigcZereXdpRZjEvql80e8l8ZuaPD+yoguZfc67wY0mGQZ6XYm9Z4UC2YCK1KlBDcgMwi4MkkYQwd0rNaFyFstggOc2hTY0FVyolyRNR6cd7dnrZkqRiViCug/dQkPwAqUYjmB6gHROB5cNxrbf1sPVDx+YVjLLRt2sIrde53MqZW6J3/Zrn1nubFcv85MtYczFaaBWr/twTrN6ETCL4To2/M0euzT5Po9ezgJWuYdYw1LRWMi2lslUZg6je2jHmOAPqnLWoZyxoHpIJPqRQPmtjoNp9uvC/LOc8w69LGx3SE7QUjy8+RF0Tj66JR9fEY9EmHl0DjK4BRtcAo2uA0TXA6BpghC86XQOMdsJeugFGh3i6wLnZIZ52iKcd4mmHePosTmOHeNohnjasUAHv1yGedoinHeJph3jaIZ56k9ghnnaIpx3i6feMeDpDTIdrus64pqXN0aGXun8demmHXlrSyT8snGSHXvqU6tahl3bopR16aYdeush+6tBLa/jq0Es79NIOvbRDL+3QSzv00g69tEMv7dBLO/TSDr20Qy/t0Es79NIOvfSbUrYOvbRDLw3SWTr00g69tEMv7dBLV4JeWhSP18t4brJDVb4L1RK0q4225QUBIISluHx3P05TJEHPysLQnzTvKWN8Zx5AIGfV/en8tCZJJrFvgDPMPKrK+9S9LBZ1fZqE3ny4mLaMOdor8JMc4dFIkBEUkNiPyB6aFk9GQyqkMksfgJ41C2kRoLm5ZQgLAy/UiPkBUYPgPOaVh/Ww/CS9RhSPGS7vcfowPOQ8NmcAhZ6NTU1qDZ9l1Zph0OrWo3gsX/aehc0Aq8gqpdsUZbMA+bkGfaqmECFNeXxt7uzfEMeWYEM+vLsWKIQel1BhfT9vZLq+cKkNy62wUh6ryw7W03Hc8/CjvVbMU5ZL8j2sOGaJA4JJpzb1rZHf72ixF+Rb8QUQCet9xeeCsGrEVwhcGcUzrbzxramAdvkYMWeKfAXZUAVIuykRsh7MaGiTZVqQudx6vXNZOZCO2sBBA400JS1L9Jaj0Wa0wUuBm7QdqSll9WQ+md6HNKYQPTHbvpHM2SNb//VbU3f8Oyh6a43Gz6LLyyrtAtS0UddH6GWFkgB7zGTnTXBWRh+7DH5dmtf/AViViDBB4zFJTK6fe2/4E0K3dIAZNlpqJrk2JUvBY4OfPArJqG2yEPwdtc/IrYq9Gppo3IMPF++Ua2jrJkXPVuh8Na4HfwJcJS8yNCBamyRSvF4J614rH7cjLB3NZSyDnCUpaATJsBrPLESrOqr32u0IcaY854C4QPX12lQAwsXDpqxBDpIqb4YMoM/CfWB+U94CGWYd+l6Hvteh73Xoex363vL0deh7i3Laoe916HsNS9Ch73Xoe0uj7ymBmcThrXwV74cPVXoXRMHDVTMv1dnNi8IsCVW3aj4JxfSyaBq4acfeNE92JseVIJymLjEqJRUEuNpxGFeOTDOS/gU8AOq7hzRpshBTacP4gsX380DNzIe1RvmbjSApucPaabbRngI3w9awbZFRhDZdBWcPbQ5wfKuVhyX/4YPNHiIq3m7Bx4JuWX2xCmouWEFLFK38aFJU9UfoKKyRDPXTCSbmmS2FlZSN0uK4NxL68ewK7WqvWe6+ocnm9sJXDyVwW//4ubYm7M0CQwUo09dqUx5YXLvqgw3zb62AwrKWbFqAmMW8ZP9i14GadqCm7da/AzUtL3MHatqBmj7iLt6Bmnagpg0r5A/pDtS0AzXtQE07UNMO1LQwiR2oaQdq2oGadqCmHahpm7hqB2q6HKLoQsCIjYScWhy1Dle1w1XtcFU7XNWnU7cOV3Wd1a3DVe1wVTtc1arJ6XBVO1zVDle1w1XtcFWXoKrDVW3zgNPhqna4qlWGWuTbdriqHa5qh6v6lBx0uKodrqr51+GqdriqBZRMh6va4ap2uKpro80drurTWMwOV7XDVX1+XNVZAI05T5JFpCJXMQ9lWRfYbpLiPCE2y/DGvCrZmW+QpJMsnaKEMK4I5AOqMRFBXqMgmSDaf9auvcxjSNPiAuFw1CGmaS5IEEgmQmYkhkQ8h7PBlHZaIG01EzzJY71oNXmrXtPtbH9abAniMU2TZgd91YWp56dbcts/emvaDKNb/rLqd2iGn6igdsEMpHaAKUsXe75Bm8kgyrhUI0Hkr2n0a07EdLOHNhWZ6E1HIiIG+ucYx2NiC0DnbDlUA46WD56F6WM0zAXsBJkPdhJ6R8PILmRibZFoFKGCvR4iKZaKxpJgEY/nVLLW8LGQxi2dORbkfWaYmVfMBPLcyVdEWMy1/ZyJQrQM9LYTcGPBLFA4Wykb1NHWoZ5WseKehsirsAjuljLYBO5xGY4KoDIf7IBGeFNZ1AcAN0ZTQPt7KOaMkVht1wL9idkHnnVCH/40HEqiZnCygrXZlAXUkyuRnLooJDAYauIiiMWN6NytqqueWzJVtO9FuZNTFs9w1gqB4JwlkEZQPrtB7+6xROQriXMAEZ6yeCw4g4QJOMBLv6mT/OBBmT+IOlm7GCllt7XLsVRtxilWeIAlwBjeRvUzCn4vr/FwGNY1lKduj6BY4Pfq0ZEb3e38xJElFVZkUk7laU44empZz2IXNs8wd5YZiYTpSM4lt2gUla/VEoia/ZYylfVKMUPO7ALpf1fTjNSkdwSIC9o0bybghpTO783tCF2abJ0ibW4QQO9jCbss0lRGzbzVlF4+EW9BTrFDk53D4lip7M3uruUxivksxz20KfBgQNXk183tZo4EKaVl/j5cOSLQgMDlDh4moPKqkeE3r/pzed79NSc5udZMbtYczxMi5SKoWsvtU5h74V364I56Pq0LH+CtSAzxkCnKMxoHRWXuA1SiLB+kVI71EgkkSEzoXQnDHZUhMR4rCr9q1YN/jsFfgP/jkfVHPWf6iE9T6o/4ACC0DJgU4IRelf5QmjT4Uz1qaAca+h2ChuYibY4ANAFtxnySpUQR9EWkPZvlG4/JhPRMbSpmCcqw8lQsVAzn/QYY6U8VIc5YjmaZXRk0PcVj7ktObJpiD4GF3tAWWr7ZCKTUlBlXn3WxFE16iJ6+tks6SO0z7A6WkkwGKUnQl8/v66hFhtbdXRucimI+eXN4eLBrjpH/+vWH0rHyZ8WzFmxVahOWY6xaq18RdkB4G4mX8m5rt9IDKhDk15YpOjw8aENHCfd5OW3EatwgHLuMbQQD1/lHUgRj2MBLA2UVxWq1WQQelXFXWxOJjv0oNhACl3mMUgeiDeHyezJAmT4NHdWKZw9S6utblWo28K1s1ky+z3L7SKmsmmBsFwWlBCe+mr4GD/ehJbHjVCms+DVzUgEnRI152XNr9unmejOeVTNkW04XQ7E0tUKyht5Syo/5RxjWJrju6vkgJzFmnOlLmpvSu6icURPu/Onq6sInyrdlRJAhEYKIBSU/94Grws5nO7S97lC5BKXB1SzjTM6c2w8qWBCDVLm8jssATQ0u80PAeGYwk3Fol8OyZqgs8da8CN6eUQYXiFnCZgNmc2n7IoswzUdNnMmIVxxRG0zTVxgfhXbkopQOCYqncUoQZogIwQUaY4l4DD2Lkpb81GyOpr3RvDUeWoPFN8aia+KjeB3QfQd03wHd+wyADui+A7rvgO7XZAk6oPsO6H5poHtJpFwtyP2yCXHV7A5D+Byg7WiGPqnvGawGEXZxeDHj7NtxKnBJs9kSC9BYWo2u7UDXdsD869oOdG0HWtnDru1A13agazvQtR3o2g50bQfWLzLStR3o2g40rJA/pLu2A13bga7tQNd2oGs7UJjEru1A13bgkTa8azvQtR0IBdS1HVi/tgOPw/vXlCdPgfnP1qP7Qdd6oGs90LUeCIXUtR7oWg+stbp1rQe61gNd64GqyelaD3StB7rWA13rga71wBJUda0H2jzidK0HutYDVYZaZEB3rQe61gNd64Gn5KBrPdC1HjD/utYDXesBrxhd64Gu9UDXemB9tLlrPfA0FrNrPdC1HuhaD/h/32/rgScvUW0Neh2qV/FCULlC1aP4LJCGyuE2U4K4pjLkehGoa0Fknj4SG+PKwPHkqQpK/DwuvEGyCVGHhlzAL3dMAVASfryOxgkWt7PQkTN4PHPBa+qvkQ8wdlxGjwlQZWDF4eULiJsFHK1nIvpL9JeWjCyFo7MG/Jo7d20Ne5vtEqf1+6VBLIuJZkYMWoNP8kluEfTf4ynPFboc06HSl3VB4/qSmGFDSczTU/eOCqnQOctyhU5Jiqdz6VKD+hrmp6fLWKG3KY9vtapc0QmZS5m2cwrLeqT5WujzcMb39tve7O2ai7qZsaopdXqFSgWXOauKAc2zyLUSUCCBIi+UDz2TshkKWubVigS0yCqhhVeqllaZT0L6vBjnEDrBX38HQif46yyVNY0aMsyuq8vY3sgkgmfZki0IChXAE9BFPnTjWWAGjyA4v1vKkyGZ13G4HJB5dZnmlCeiB2pqZuT2beGYN0miBsi8jf2oF8LDcOb/OwAA///rgebC"
+	return "eJzsvX1zGzeWL/x/PgUeTdUjey/ZomTJlnXvVi1HciaqazseS5nsJpkSwW6QxKgb6ABoyczWfvdbP7w1mmzKkl+YzK5qtrJWs/vg4ODg4LzjT+TH8fu352//8v+RM0mENIQV3BCz4JrMeMlIwRXLTbkcEG7ILdVkzgRT1LCCTJfELBh5dXpBaiX/wXIz+OZPZEo1K4gU9vkNU5pLQfazl9ko++ZP5F3JqGbkhmtuyMKYWp/s7c25WTTTLJfVHiupNjzfY7kmRhLdzOdMG5IvqJgz+whgZ5yVhc6++WZIrtnyhLBcf0OI4aZkJxj3G0IKpnPFa8OlsI/It/4b4r8++YYQQoZE0IqdkN1/M7xi2tCq3rU/EFKyG1aekFwq5p8o9mvDFStOiFFNeGiWNTshBTXhQWfk3TNq2B5gk9sFE5Zg7IYJQ6Ticy5AyOwb/+UlqM61famI37EPRtEcBJ8pWbUQBsQsa57TslwSxWrFNBOGi7kdyENsh+tdOi0blbM4/vkswc/9RhZUEyEDtiWJZBo4JrmhZcMI1wkytaybEhPzYP1gM660sd8nowAtxXLGb1qsal6zkosWr/ee7m7lyEwqQsvSQdBZWC/2gVY1GGD3YLT/fDg6Gh48uxwdn4yOTp4dZsdHz37a7Sx5Saes1BsX262rnIKv/SP3x5X75Zotb6Uqehf9tNFGVuDNPUefmnKl43xOqSBTRhpsFCMJLQpSMUMJFzOpKgqeBaf7+ZGLhWzKwm7OXApDuSCCaSyjQ8gyNeCOy5LY8TShihFtJIhGdcA1IvAqkGpSyPyaqQmhoiCT62M98WTpoep/7tC6Lnlu8ds5ITszKYdTqnYGZIeJGzyplSya3P7+X11iV0xrOmcfobZhH0wvQb+VipRy7kli+cdD9DzhCeP2Dt70Pw+IrA2v+G+RG8E9N5zdYqdwQaiFiwdMRfpgOG1Uk5sGFCzlXJNbbhayMYSKdjN0cBgQaRZMefFCcrfIuRQ5NUwk+8FIsHBFKFk0FRVDxWhBpyUjuqkqqpZEJvsw4nQ+I1VTGl6Xce6asA9cG+xEtmwHrKZcsIJwYSSRIr69vqTfsbKU5EepyqKzWIbOP7YvutzP50IqdkWn8oadkP3RwWHfKr7m2mBu/lsdN4Chc8JovggzXkFz9+eUsRy3Hez8vctgdM5E4B1/EIyTR3Mlm/qEHPThtXu5YO77uHZ+m3lBTAmdYunxp5Yzc4vdBWFrcCzOPEwqllgJakguy5LlRg9IwYz7h1RETjVTN0wHJpZgvoXE+klFDL1mmlSM6kaxChvfg42vre5eTbjIy6Zg5M+MQk7Y+WpS0SWhpZZENQLnsB9X6cyeg3ai2b/4qXqQegGBOmWt7Lb8DvwpL3XgSPst4ArsHkipBbO4JfNTHuTtgqlU0i9oXTPwJSa7YOlUrV4BAojIozMpjZAGqx+me0LO3YA5NAg5c9PGVsIW1oMWwwwsQbwGM2U0shT29fjdG6vLcN0zJb/mtK73MBmes4y03JHK50KysEJWMFsFhfAZNAGKsXEaE7NQspkvyK8Na0AyvdSGVZqU/JqR/0tn13RA3rOCa8sDtZI505qLuYccXtdNviBUk9dyrg3VC7w8fveGXIChVCSa25qW1f2TVNVJd8u04WWRBTkWf+7b75v2/J37fn2PvfpgmChwyGPoDilnniPoPJV3Xh2yMwA9ufAAjIz7k4plDzy7B6lbCKfFRJDYG7WSN7xgA6g1umY5n/EcXFRRY9UnDo3EKRyRsh15VDGjeA6+ivrti+x5NiJPaFU8P3w6ICWf2p/d45+f04Nn7Hh2PHs2mh2NRvtT+uzwkB2yo8PiuHiZT48P8un+6EWeDEbsvAw5GB2MhqOD4eiIHDw72R+d7I/I/xqNRiPyw+Xp35MPCjajTWmuLL1OyIyWmq0tO6sXrGKKlle8WF905pfoCy98GJPwAjJzxply8oRrv6+e8Jk9qOxppp+usgCH7qMqq1sGQ4DmSmoslDZUQcBOG0MmFlzGi4ndntCY+lfwmB5iIWZrBOLFNvbCD4L/2rBPoYeXhSdWkjn5Z+l4azXEKSNgu4wXd067WJs2/ruNiXu9GMN1jpS1FdeEWsPMn6dOs5nzG1hQEiqYW2n3tld8FqysZ00JGQyJ4mcdAZtbSb715wHhQhsqcq8orxxoGgPbUw1M5bU00mpprKbKHgIRNtdEMAbpJgW5XfB8sT5UPBhyWWEwGHPJvM9nkEfh4LJTdSdaeCRnhglSspkhrKrNsn+JZ1KurS6k9zZW93JZ37Gs/pkdiNDyli410Qb/jTSHEaIXgZUtDYJNaOFZhTGc5QTqQFAFIrXbd92W8ANNWfuK1Y34rMMQEeYaY3SYoqL5AoZpP+lXYQX6+0NiC0vwN38cdRehB9fn2SgbDVV+0NWh9YoC3RgpZCUbTS6sJnIPZXosCG0/cwoMeTK+eAoepkE19kjmUghmXRvnwjAlmCHvlDQyl61O8uT83VOiZGPP5lqxGf/ANGlEwZzWAF1AyRJcAJkpFamkYkQwcyvVNZE1vFVSQd+OMKdsQcsZPqEE6lbJCC0qLrg22Nk3QbuHnlXICha3FUTeyeImUlVSDEheMqrKZQRdsJm1viLGsuT5ElILyHI/zezBGptoqilT6zy08dAupZhv4hN/ADmY8KVI2KhFwHJtAb0SHB8ncIOS6hHEUr99Sho7QLlszzjtLLy4LKAoI+cr9Ogw6f7R/vOXa4SQak4F/80K4Kz/4PpSioy1t69WVyRFJnFYELLBkxH+Bx1Gr2pm91DeVtbu+2T+liS9tPuLlPOSkdevTzs7PC/5mpl8WvJ72slj/z22cuBzWG6Wsbnh2GduU4Vl9hvca/keqLd/FZtTVWCPaBg9UuhB8r6ziKbc+Z+5FLQks1LeEsVyOA/i2QJN6PL0nbeq3ZnZormGGx7g9QQzu7U1E9EaxjsX//GW1DS/ZuaJfppZfcu5d2ovoNaGct5VKKmdQT1MqaxVweCUC2ZmoJJRVGhqZ5mRC1kxv6esh8S+aZiqyI6314xUOwFTSRSbMdVBRaxMULvt63/2Lg7HU1MWTXzr4ghgFwEFArTEPCxzO0SKvyV9Rk47A+D8bHQDrd1DbX0LXAC9fzTC4udcDbC3o/esD1hLXyHNGkiofG69hlYKeH6IbOLh7YVxosfcbiSnRMIRq1lFheE5EMQmBompIOyDszwGTr3zQLmOWqeRCGo0tOS/seDAh0eX5ExZW1Vz01C/HOczspSNimPMaBk90eGsgUSeS7Uc4NWgFmnD4fgWurG+FRrd9FCdCqYN2AMkBcFmvCyjEKR1rWStODWsXH6Cx4AWhWJafz1h2xU1dhfYJQw85xHwmlkUP9WUzxvZ6HLpuNx+E8HeglxaVgxhB7hdtHXgnr8bEBpOdkQTcGB9IBrOcJMR8h8txaOmmupqWF9FbwNOYT9MMv9g4vg2Mh98GUzA6+ShYt81zpnuPBqTjNcTSLxJ5tCawHlYM1F4w8SyHazkCNL6sLLd9ZXS2aOy4JUFqrNHfeET9IUU5+nSMH1P42WFj5xXbR1EB8E/A75zl8YAp9/znsWcyO5f9uPDNYTdBronxp8jpfyZ4sbL1vCYM5nl3Cyv+rnuy6PDzXLzCr+BncRo2Y+mROiYCXOVy2IbuF7eymHJjGE4BAvWDWBHbHb15vm8HX9zzw3RP9EtLcrbxNcVB++fkFRmQcYVUzynG5BvhFHLK67lttbp1A1Jzi++twvVi/np+E50t8X+HtWNHHNKBS36KVvKvOvRuxvNOZNXteTCbMLltRRzbhCbg2JWUmP/6MVq9z/JTinFzgkZvniWPd8/PH42GpCdkpqdE3J4lB2Njl7uH5P/2u1F/OseaJ057f6gmRoGxSv5yZl8gYQD4t1wloD4ba6oaEqquGndJD5irZgLrSaa0mlQkKLz0+0erpwHNWfwGXjra1ZKqbw2gVCs86IH26Y9chx6JakXS40slRi9zYPMbA1KQt5Kk6SzwOkIDQ+KTmU1nzmTYbbZbt+aTqU2UgyLvHfNaqkNLbe1g3ff2eHs7iVUa5nzNr4LGseptAT4m08iaW0GBMhCVhgCcjHIPGXkWshbAQuREkzNDiQV+en8HenMEVvBKuQ3SHK45QUrl+7I95ICKqX/Zz9dXx6ODkefIvYVm3Mptik439sRPyY3h389vQvfLUlOj+tGwfnXhk1ZPy/DrvpNim1gCesOwxGMF47UwLyDGCM/H78dJ+9tnJQ/aPfGCl5yLujenxsmpL4ac8X0Q5mM1/ekAK83ze/8XbQng77g9Msn5+9uDmEbnr+7ef40Wxu7ovk9B/8c8u++GZ/2I5hITqyRkCbG9Svqlfr3356SF6PDA/jkfFIn8ihfwScsc8MMeWLdcMiEOB5OeXuywnawwYOoIvpMwVtJfm7qmikEgf5OFuwDLVjOK1qSgs+5sVE0qJPA1KbFRZgefTcwBJcgjdB87tOm2JypjFw0uc3GuPEv+qQ6F/1zOLTKxGJZL5L0lQ6njUbD0Wh49Mr+99nw4NnaCgoEbesHnOObuWj3UlGhnU/s/B0WynuIXF7u2/FldLeSJyybZz4mQUu/mhGwkTFc0QnJx4Mx8TASo6gNc4k5KSUtyJSWCKMpPSAzrtgtHFzWo4u4CFNt1maXCLVU5gFk2GBuaqPaHJo7KYTx/plo5Dycep1E97HGO1R45yB9st19sI5b79o9xE1w97q982uVCp5VHHBuasMUK642eQE2MtNnCUcIvgWfL5Cs3iIR6OlwGdgJ1jXSBGaOwM00OA8i5G/b3AWn4ybgvMcSuhiybzP/HjLndyAid9IHKT+2mdo+WQKJiaqykcpasZxr6GJWJaTOS2oz1DB83UxLnhPdzGb8Q4Ro33mCBP6TvT33insD/ranGblUS/A5nOdQIj9waM5OgZwuieZVjTgJvU7XG6Mh/d9G5l0astMXkWBnHYG3rCzt7C9fn7VZcTu5zJrrnWy3j1ETiqxxSyT/NrkkDmqFTzSfZg0iG78igDDj7VKDrUOeZ7vtkazpWQgvIA84Z7Uzu2ziCp4mMfK1bZHZ7AlKaqoMT0I0ZA0DK7StYeUsMv+707paGw8/YQqWsgimtDEa0uW3QUIBn8en1yc0ZYj19bJ//14hZhNtd25vbzNGtcmqpYfgGMbtGKrNTitIfAmEh4Lih5h0beeKPJx2mFbr3NHN9CDTzXS/sykHEXAXPWdE+WCAp0ICY2fgYmJC4tDgJbZSzRSXGxK7MLuHaqxG1ld2Sr+DpGSzGRSHG0aMrD0Teco8YZevz54OXAZztCzbNYlwnbgZhCCvFRxg58BHHh4mm6WM4oTq6rgRbJI6hhUE+J1/bmlqJekmQdquxP1Fqv1tjZ8azZSPXW2LlVLfqssTkMpF34EMlo6SitmwlZz1i40BdP/XZ+N3EHNjR4mzCCrlod3+GbOK8nJLE4YDjtgBgyG2rgFZpCCJN7hd/1vHukCgXd0eRNYlSG8oL5G5mfUx87icMmXIKyT+MS766WkD5H8Y5rbYbIe77VDZ1pLF1xOjQ22ARSQEgl0Qea8uqYHpsGET2E++csS3g326Ym7wfsQWVC+2hFJINQdBUMq5cC5WpRg8BGtVGNQLTkGokGKZls05Ky1hsx8089nWE3xks+6Rv2D/ANUnsUwjl2LmEgFp2RkTHuF1XREO900MuZUE/XU29Ktq59aPWD+ffQnU/pCS9mIBaxzDQ7SUcs5FP5ESUUutqP1mFXtLVCVLptfp91U2xVgpaossMTKxI4eYlfUbd8sveyay+/PONZ9SQa9s1jAKYBWz1pyYXwFoKE78CF0DBfJSNsVqUmb76K6czG+xVqiDKdOcHAsQC8PFTNFYydpOy/kyXSWCB0t8RcJd9Xcz8qatfeI6LZygKPg/cMWA2NYzZvIF0zZm50cAfAK/LF4K2ZNAFGKotQXXii45ahpd4n0XBQ9XNcJXUypWSRPT9IlsjOYFS0ZaxczOmVDii/3ChDxgn9ZlP/Xxxm6xsf0lAWQW7eDBgcZzlMe3qHqCfUoKXm6DY9s7jncvW8K5scFTaUIV4UWs4vXickkKPpsxlbpJ8YNBWhfCqC5NamiYoMIQJm64kqLqxhFanhv/eBEH58UgJCqdWqy+f/8Xcl5YF4VLzm1WJXe227eBnz9//uLFi+Pj45cvX24k8xY1hx5CB9FKS071HTSOtI2wP4/GGLeHygXXdUl9ckUvTRkscJ4PC3ZzH9nXobjT0HmJZKn+aOJXIfs4GddFFXlItLS2NeRUIuZ6z4JGD+F9Ge6vx0dDLdH2Nuy5H5Gcn4XT2M7By6TeCfDh/sGzw6PnL45fjug0L9hstHkmW9wTcS5ptWD/bAKa4Yf+grevguWbIO2X9UeQTEhuDrKKFbyp1mbg++j8LiLej50Kzz5h0REN7+I3AzL+DSpG+6Rf9FbLoR/ooVLCf/Y7yWU/us9HvC9d8PYqZTaL0GoZJvkJtEFNu9oSXVJLN5LHIpAFiqSNaOitHhD6W6PYgMzzunWK+8IYG66npcwZFVkfYeitXpsy4gJSbGnCPkPmM46HNfy9bvg7sXPQTEN1cdpdoeCoI5k3XC+CBhsnGUFLkeggwRPjuuRYBSQwxICwuVUwYB3caPKaVtOCDshfTt+Rv5y+Ijftyo7rmrwScy7ilvnbG3Kj7XPf6aJPING6Jsx/hn97lAd+pqoRAzKjak4NG5DSDt+//dxv99l6YQ2R3naFvBFqGsVWLDikwV2s/HaXKXe5YJqtdqDpeEusPTTlAulzGJrEoXX2YGvCNRdY57xel8VUypJRsYmx/ux+BvPktMZ8reO1xQ8s5rN3erfJLhqq7d6H9OkUAJ6L+RabWUBPb8/HqJYDEXv0+w4uPf0M1jR432cmNNQiFRXNjPqOU9MloW2roRsmCpn4VC6jJQrllJXsBlq+kdgpJSP/8v0FkaJc9nN5LqsM47LsQ51niM0vH0x3Q02jt0XzcVFwXwC6vhtARVTYuZA086j10x/uSt8taA4jPFfL2si5ovWC54QphSrxmBabQr2hJS/S9GV4pVWjTRiPvGb0hpFGJDWOs5B4Zj9tP5GzVfgRLPogNSJfsPx6U/OZV+/ff//+6oe3l+9/uLh8dXb1/vvvLx+8fo3tG7etxPwLN1yazN2KL6b6ZvmGo7+LnBlyKlUtV9pt3HOahtFqy7IBQ35JAWHhSeUlgG9YEMSCb4rm6h4tJSLQT5ALr/763b//dPzmePy3B9MZO4E9hM4fOU52L9C50HkC023Ws3XQGbSTEvI3bFVqQt7npi3nvrNp7FPrCA3FmAgIF1a/iyA7yRoQrN1GaehQImUJdNF3ysaQkfmHXWyH9TJi98sdfFaofEF695/fwN2r5t2T/IYpsHdB6BzZO621gi+ifiJM19/UKyJpZ1HuIf/uT7BALK9KMbWqn3Uf36Wa7caXg36G/Wc3Nw6Utead7dkUGox5qBEX4rOdkzas4O4ESKRcRy9EuXkSwLOuZ5fZG0Fr79QWSyjtiGhkuw/WDnmxhYPBx9laovAiW8ekovOtWkmpcWsHj7V7DkkwqWvOJnuKPu0XmaHzLWHbcqbHlc57skI6vW7vg1Kn7+1Het+u4XRuMfGNZNdw2eJStsTploEEVPz+2BI2791o0PSpVUBxyrSMlfWJuwItCVRHniVtHroS7WzthztlWvJ6kC5B+Hd6gvgsTNujudvexYMNvTH2XHZ61rVkqUoPSx9vC5X9vpG1+xBxHA/SRXPsr4FAIFjAK0Wi0z0lyMyeuSX9SD7WicSJY9+JxEO8XLBNvTaSAXIpEHFE02BQDZIZnazTzmauoYSHOg1dOnDeUtGdsd40YCBDl5geZKeZTKejt02pjLCDlurNz6Ai+WYbwDft5mqj5DYGKvrfC2SOs3S1gZHSn3AG+Ry3Le3OtOVISuz79R2JUH1b8y/UdySCRdsg9th35LHvyGPfka/ZdyTd+EZ27kjoQf53bT6SHnGhMGIVmccOJI8dSB47kDx2IHnsQPLYgeSxA8ljB5LHDiSPHUge1IEk1Yf/uG1IEiwfe5H8QXuR8BqrlfLTR5ptsJbsRpJa8RtEiM7e/PS0r8+GjcXYA+UP337E9rFIwj5+9uBG09LLSCwqqHPGUJqQfZ1Zf+2GIg80qA/WEexdkoc4Ae7fVSRBdl28rfVQWMdqI6N8jdYiKWUf+4s89hd57C/y2F/ksb/IY3+Rx/4ij/1FHvuLPPYXeewv8thf5LG/yGN/kcf+Iv/j+4sUZbmSKfj69X0yBDt5fGGzdfaXzXNGdIGUfKqoQgpNsRS0cg4qDxUuuXAhvS+AsNEu//MbXOPs7mlM78j2F5tJsqMXFN6J7jg7TgFuixDBLDoYRNPQOcRbQgxtWWdMaQioxBqcybKUt1zMT4Lk+Rdy5iYwLLm49uMtyZNJVpTl5Km/+DE436QgP3JRyFvdfn/h0P3eptDiQy37vvtB8A9D2xhwbe5ruHTQWJZ82gewovn3F7vfdMLE90iz6xYxZv8N6gJXZvRYJvj7lAmuLsNj1eA/ddXg6nL+zygiXJn1Y03hdmoKV8n+WGK45RLDlQV4rDi8R8VhSj8Yw1lVHD2AZp8jKd6cHdn+ntkn4akXdH9LiF58N97/PEwPjp5vD9eDo+efh+3R/sH2sD3aP/gcbHXBWL0tbC/OXr1692nYbknl6LhIvQGWSJfLRefS74rWOqQ4pErKjJcM1lnB9XW/gLlGtkv57CBr7fR7k6KmZlu+vm/h9bazwaBrdOmZ2OnJL944/uXCulSfHfzyWZNlGVX5ghtm26Rsad6n734g6bDEUDVnJrqOQZLe6X94fvgJM0QvAiqWW5rcebzYyA3b0a8xs0Fo7VWgRy6Q4yUbIuky+2o6d82yBMltUyJ5+hmEeEdXk/PvN3EMdWWvidr+zP2wnzHr59mz7OXz0Sjbf3G4f/QJ0+dVvc3wxdgeQGHCvIKT1XepffcKpbksI2NBPFZkOIQN714jHTyHwxCrDzbXjIs5U7Xiwvfsgm8U1aGEznDtsWKOmr78NnSwhd49tDs+wrZZgdGFockCTgOZ541SMCZcw9JbW9/pCrFJZYtFFY0uEODqO/J19WMl3MvUEKQH6JO9PaSzIGmSLa0w2puWcr5nFopRM4SrDrJw72C0f7g32t8ziua4dmRYoa5ZsaEjzhADoifewlRl/6k3yp8fj57lh+zlwcE+/lHk9Ojl82eUFs+eF8XsE5hHKj7ngpZXWLivHJ/u30GfKz0v3o3P315mr/791SdM39vu256zH/Zz574Tj45fPoxfBa+6/ff30T/u1Iid+xAnEKYQeiX48fbiPsEP31ndV/LA83f29oL82jAEH2xRFRX6lql2Q+F3313dW/KM2z0ds8XhaeECbUIDrCUym6FISDJnxs7Rg/VAn0wKoTPLovb9yVOIILNgy+AuSKEjMSP2Y7BIhrCNiTXqFkzsIUC1S9ahnSQ5j4PzN9wyxdq1jGU4Fs46lu7TydPs4ZGI7sw/qU1Jdw3HglDb7N9TwZHXf2UVOFvv7cYlGndSSUEUM40SyWjTZezm3ulBCQe7TXG4Zkvvm/FrMmUhrOFr9DXzo3bbCEyX5NXpRbsf3rNcqsLDsnLeSufUg12103GiOwyO3qTUAJ4Hn3oEcaU1LUswvytic8nn9o5UZlc+MoSN6eE9vxwZGRtSccGrphr4hxFumFQFF1pACxw0wSgTSBjbtH9tGly3iTMDUtHW7rR3rqHnwAytBoy0M6IalV2a27fB17RAN/8loa2f3wcqvQ22AVGqSd5oIyv/era7iQ2zvKRb6z4BVrLjYdpxkTxB4bgCVW0Yx1cNQH1QRa8kPX+7cUrJbQxfe0bWd43x/ONp8JeGKaxuJEbd1QihhNJ9ilYUOiTmACsn1QKpUoCBJrt9RNkfZeH/NlJnixqDo06bIIUDIGktvDIlUjOFTJd0R59bV6btYidn5PTt+M0ruOinDETE9+UNtMNEwO3uajLBYJNwdLga1wgSHfCt5LEZPLqWokhCWwkQLOskI+dR3iHx06eJrsL0OhmZ/NowHZthTFBCxTqNYTrLBSX0rtT6sGTGlA9YsbvqV2JxH4qu1I2NG+JIsESwVOldmeDep/miMyBuvZxZoZceCgXXOVUFKzLyE1PSq+SW98MYftMkhJ221MT/3FC9O3//eDNzb7Ep/mXYqnL2OXLM8vTafBaMFkxdzUo635Zw3o0ZPQfEt4yAiHaYEItJsjFffahZblgRFpcqpIGOB+TydEDenw3I+/GAjM8G5PRsQM6+7+f/3Z933p/tDMjO+3Ga7BOIsLUIJJYSc3V1YmkYkmpfpOi1o1qhlS6qvqjxbtFuxI74+g6mXMukFJjtg1bztruPEz+631R4frC/v79GE1lvqKz+qoTxuTsSxSaFF5++6aMPL15zUeCosjP36mAClZCKaY2mgWniP67JZybQ1gtRE8K1DpQ9CB3VbGrVKtw7afjXH169/481GkYZ/bvpPsprwO4cw0Q5u7ea0zlitoS9Pckx/CrKqzU/9p2VC6SEFEPrIoIajJ60iuao1CRPXIHSswNYexYLsn/w/Gla7yN154v2kIkGIbwOmjCdU9w5PqWakf1RKCrW5MkvZ2dnSSH3n2l+TXRJ9cIbuL820rAUsgeVkUs61QN0z1AcrR6dxYRLHtBrhCfF+DPG2qZwOCeluGHKF7j+YgbkF+W++kXgaIXktO1zP00riOu/VpC2TWboK9Z8LND8IxZoRn6J67BNPomDEt5xvviZ31VeuSZc/skKCm9vbzcvxmPl4GPl4JesHGwZ6/cxgbyl+HGNZjwer/cxC6b81ZdqyjFe84iWJTl/B4UTvUIFmQSTEYbopMNeLP44CZ5Vz2d8NuN5U1qHXaPZgExZThsdIwI3KA4w1ojqOKGcY1PD1ZfTWLGNa6QQWjItfqEkkbWIwrmODUOs9zkh1CSCr+g1rp000XuI17ko2AfszQr6UQra6SLuI/s7oxr2iZER4g3XKFH/jXkVCZr4TKp+htz9eSdxPMGea//c7zPsgs7+e5gyYezN7aLefm+vH1nDeosbazfdWTHiEpIIi4FfEWjNlosT9j2fkaVslHd9d76Hh7FcWme4xktprGdgH/ijz76WIxbaTrgQOkKZOdxWgzb3xaJFwG+zEJ/pILEyPtx2dnwcuX7+T6Sll81MomgkLeNp5e1Qt42eIr5dEOo9XRGmp2pXSGyOHIU4i5xFf1Pvfkic8oF3WL4Sn3t1ep/43Btm6DANKHijO/cRg+zB0aeNSRcriWWK/dpwxQp78Qn7ssyNUEfIwrCHaFwHTBT5ZRmZsFxn/qUJtAAaUYpw7RytgLKBGVvTA9GOVbQgU0/yjwsmHNvYhUYUNtEiuSjsBXDDoXdQ+wAUEAKtdcnnC1P2XfubzEYjdyIpyCrRKshaospnNdDiH0DV+390vmAVDV9HiP4w8VPoZbH9bJSNuhyGm51WeCx59IASOCqSaKsv/bAsv7RenkjTHzR0IFY5nc2950N6dc0QqEManb2IGiQPwkNhidBBTJNbd7RFj459BZdns3IWtiWMdAc9230wt/efL188h/IV0LMHymoYyCG+hhcvtoDV5jrwDVh5F909UVspi+0hSHD5rQ2kDc2vr6D69Az237q3As51O3tiZx/jgHZFsDHqEoYoMGvJRpwutwr6d9BLUrUkMtIgNdr8vQnwc6apMq61UqflWCLN/kFvaFZSMc/eNmX5TkJkqlfhk66Yu2llbxBzyaOPiTmvQvTdbwR5wz6YDYV0pQymnWVsXM7vYTlxFaXimJRyjgMrZD84zWFN1QgKBjohyYrhQtF5Kj5b6+m1jMLTnnO+kVVbak1NjKpCIgNQhBGvEMVKtJPw8AIoGsqrUGNjK7ARjKb2kudBe/lGCJA4Iy/2wPMwQ7oFzkuadm6z3flaILkUwiehTJm5hZlD06s2qM9B8WDdYFxwgwa5BWDlpdSY2zisxMfJDfUxHoYVQpaicS3ASwQmdaMYbufXvmqpj7LJa7aixtBrFvk5JXPKHi2NK1ahXwgOVowWwBUtpf0VKGiE6KEaVtnIS6NYRi4YVpeRiV28DGfxxE3b5obEqGPI8AFTt4kjHmLUay1ne0wxLirD1nSO+5+0NI9b9eNHx+eIoF3IIDdatIdCBMk3G4ifXna6VFvipbvbJ/G4rxDn59olG7Va9oKKQG9cqDeX1swhZGXRke00sWQa0qKYDMjE76eh3U/MPkKq5NBZNcXEBQdDGCxCxDFjzZnAzn5m8CRbzmMbbHA0TBjWVGvI9qHLkF1bpDCF7SyTq870TYRnsEmhKp86HMKdGS4B0XkkrBJOEYxJg3zOZvPeQr9kABQmQxacKaTrLpOVX12zVqO1wMnOlM/JtEG4V+9gzyYQOdNdj2WEOuOlYcpLx5UhTvyKT8jSHy7RCkFZuq+kjK0hIkyw8g03Sx8YjaXsVsaVS/etv8bGjYg9NAkZ0aH+lbY8RBFwCWit7oYIP1izflzbIpKiTQQwhImddxfKn1N+ShGoPbFmsLi4aM2p8C3bYL7QBncsGV/uv86n23ML7Z579dh3HrBKfMz+9Dd38RnuBnIGZmpTJpcphUxDOAPD4VOAk5O6ZK8Pa9KI5GalAXqTUlWUKVfIWQiYE+hFDeKPUqF/ZwE+cmYk5IEmEvdS4bSCkyOQOCqWiWwB3/uEY6czkfOz/uU5fH54vL4oTnKtr0uv7ChS380qzf3OcQDDKe2jDNSwPcwGxmEin+2JO+MqqYpVDD2QkSFAKHqLWzVjuoQPSZGa1/ZSso387+5/z30X33/DkNrQqnbHKDXpo/aSAo9rhBk1BfYBCn28/C3JZ/BSIEHkHOUIuFaZm8ZynbtlAeb0rSRxWL8pp6zH+4Bdy+KfiRm1Up+R0zK35dW+9W9pE7mc0pU66Hwqi08dtgi3Yq+jEtllsZ9aoqMHszZBqiFJw3iJsoJJJQU3UQMjCQgk28l2xfAnnfISjSGMJNeM1aSpXTjHfpRuuC5V4VWwE12hI45ntwtzWg7SlfW+Ro9n/27YPRjtPx+OjoYHzy5Hxyejo5Nnh9nx0Yufdtf2CJz+mpl77pEvVrbph02JkVy055fYhr5s1oPVccwCOeKitedgtkhP3dCknOads6qU84HzxcDIeTpIB087MTj9aemPKIjOdh/nskra/mOzpGgbrD5CSVVlZb1tcYSwWXD+WfDQqTpjg/ptDmcli6ZsyY0fYaPicAvtZAppknv+UjD9PEBr5CNmK/SIy96slQB+QgvvHihc1I25Ci8IKqTP1UzekY1JX6L6DS9LvvE9lwdh5fD+RuY686hE8/3GJ/knKHS5zS4uut8oZ8S5vxly2FW4usS0gdt235l+ORaEFH62UJyJgnVP7/pt14CJYp38G1WGu46nFvW1k2n1UHI8KlX7PKhzCXDXu8bGguXUmrZFtoZ+p87u66o836FM7knN1ALF3KWca4MnSSnfU6w7rmB0pyW69OMCoZKlIUL8r2CVFNookAbyA46buYI227eJ9g+eHR49f3H8ctT3r/GfT8/WyLJNR+n5GYRLMBfbVe6dyzE9nB2NRsU6xmLO+ru4PFxPuoznlOW7KNGRkXYT8ozRjkUYRUufUo2WLz1dcsIe8wrPpD0EU1tihb+DClMuYylm5qV0HMBeT7QKvaPhpQMgCdykDVEwAadDdC4pDkod0fQ2XYr4wrmwXRhtUbdwTg6k5mndVNBi0PSbwnnCxdynnIT5xky9fKGkkKWcd/r4EVJKeR1SRrg+6dCK/J/VybVPwtJP7q1HHGX7o/1Ej/iIMznwGFw/9+SvP4YNH5IFP8mIx2wnPmgMQMMAZdVPayvDgjqT/pyiErQQJ9ldRpdsok8zia+GqwRjzDtyYb93wGfCB8vLrqA3M+YN1wtCSzRJ9gqW3Sfe/+a9bummDb6hLrQVndrNkSzkrbcfQCrrUvaDOEaPYKeMLKgoSvhOLxdsaaOctwhqCxMPYehaaDFkHbftQ6f6YLMZJct21ty0l5TZe/ttRp82YIbbBUP2StSxUB8LKiNGi0JOxea4sC4Wo0SgUqEmpH8bWSqubYkVnW9LircbNSnlguPGzXFVs/UJEd4MwhtevjU1asm17/UmEPTACjrQzjIqm7m1j9e9R36dEZ61O0QEg87p72OrtkJZ108HYT85yLE8ym+FCDJmcQfWc+9vWAw7wNpqhHNjG+vxHgcEAjfBYQL2F4aruFt/8NvlDm1lg5MAloeNvSGwAOe8zK/aghdsbmhFhS0Scy2YoSm57gGsaDcJrBif+zVFpqtRnN0EX8Hkyq0Zas1m2Nu21aLrMg3njuKFZzGaiP2Q3hXQHcRr2UmjQ/j5lpcFSrHc7gPz9y/jBavJ/ksyOj45eH6yP3LRiNNX356M/v8/7R8c/u8LljdQ69xfxPVJsDf8M+We7Wf+1f2R/0cyDoHjqkJ2I2QIyreXRBuJS3zCR+7/a5X/6/4IuQ3ZPim0+deDbD87yA50bf51/+DZwX1DorIxsDO3wX5f7IyEdfqpR6Sf7yTkohZM2KKGVADbN1M/OA0LQhAoiiBnlJeIT0U/Vs1UKFmIx6C91hPhEeM7I7CiHSTB7600vlzIapaxM0Byfz9JYjRFx4tsMdauujNCtA/tkRMa3iUnV3sErxBmQGiee+epO9p564pKJpigPsaJJiL+fkWsi8mK21xWtWyCqUuexLnZkUOJqZWtrbyOc/Nap5/j00Gyk4Ng7jRHjM4NO0ULPQKdQrXy+rQ7RyA24JNPFvhey5pE0klY2LQXwLeNsgd6SxZIyDbfynksbUk/FektqH4dNsRVkrl3uo0BeEuC2UrkXQ/aUc0irDiE3AkUo0kLH/wtluFtwHGeKXjAHGKkkExD57BpsXF1NBN6g8j0pF0TPb6FhFqXPV/FmN+9iBmWffvP+fntbnNaRsgwv1hq7/BbDwsg4aB1eSPKkHqlkkv9ZOtlDEdgcOm0GgRr+65uqJqMiHut5WKpKyifSMgvnlq3PkZCWMu1Dg+AV1uKR4hPXAO2Qdupa+inOAxH2XDcwGoU86f96+sgrC2vYlRLsa3FfW9HI7eLZRKPiskd60LNL8zdIXBAs/REuQbS2akKAXWp4obw8iQ6GTxnBbg/2tQ9v+fc15OuDPIgo7zxsTT/iaPjpEUtYgz0YgdYKZJUi47wIJTcsimE1IdQ6yFW8ElAYrcXTHB/TMGbzHRiuQQps4peFLsr6w4kHbNOpqXMr1mBaA6bbGCmS1uFBqYHlo1goXp6VZ+/pwNAsVV/6VdnRD8g+eH9axQ/XnumSzqTrBvkLc+ucmSAYo0aOK2o4XmazBKMDy9cxonpPIgKVDuZ6TKsHEzgE2sjTgZWSaf+DgFo1u74juFdS+P+FQt9wpxgSavH9uw4e38ajazz8sFLx/X1lV7RQ+/STmelpGbTwrzn+ppYaJCStssSFDM5WxOq2ss9omXZ4GOdFMWGdF5rorop7+o2gOl0Duz47I75XMEftz6pjQx558R238K5gwqUgqiPT3KAjAxKdE5t7DtCHYG/9kejVf5Dpg/l/l4JfwMPag/AI93wmD9xnESy3QB0glC8isNFSQHi1js4NUNenWin4ajoM8+hM/l7MLLdNaJqyKeHbe9Puhd298IP5O/279C0QzNYtd1XkUPiOSOEFm1wrc0S8acRotvS6VUdR9wHmhsiVeEzaqLTKsmOSHMjAm7RF+tr/ZBU2kfBG6a6cZi7NtmnUe9yEdMI44AdEnYP6Lui1z/GvijRmIkQvVUD13lQsVqTJwTKQgJK6iMIEk9nPrLa1EFRSBLK4upo2Ad+VO5dHNapoOEPjFA9BwdtOpzvmla9+gcLumacz5SB3MiMJJNSzjNtf8/C7xkyZyZZEOjhcXucp2GEaM9agRfeXVeMUrJ7iRhuj2638PnZxdMsFBt3vojmgWd1FCkQxDTDiAO496xZ2tYkRbi5rKHTsDumm+R3hR82hBFerPM64qLrjP4ZwU8Xx/1o+NMnN6YB0ARum9vWJu/cEQHFvv5NCvaAiXyWSnP5EYO7M1VsnlbwgBsiXBiNIcc3zKEbnCiRqRT0Ra8khE0RgabHsdusgZFcz9RbrtN9Nc7hKIb3sR00VJnaXjwUokIKa8aen/nBd141SO7bG1eotC9otZM026DTqWI3zl4Pr19c7thWjFSQ7747qapW8OAWOv/WcHR0MhrtpLrv3bUJvSL4j+GFMwuuPjHZFHPtJJpSkneHx93EQ5d1ugMNwyAwyoTP4OycQdHw8MADAhPPsu5MGBAmwAM6SU31crmAdIJyHUG6SdkK9lphmXHMBadVKPj198Jv8Id/1aRR7zdb1kz3cFKjym1JhlUTR9hxbAPxoA3i/kdhuEB9lbhB5f08zDiCva/1I+z+Doqmq4njYliw2izWoFueCPnpEaoPzou0oshXDAtrPJO6pDnbaEdtsJ8i/M+3o6pljyVlh9k7OnixX7BiOpwdTUfDw4P94+Hxi9loeEjzw+MXI/rseMbuY2UFTkGefbdC6Nv2yZ0FQmNsI7ZaTWJ7VK1FlG2hDjo4MbGSGusLXnDtuc0YDiUegO2JEPgBiMWmnV61S7ymVgjYWExYsVBDE/6motiTKp2y3/5WNA98A6Xoop8u3ZDnIfJF3rTxyJ+/PX/zd/8utJvgwsSBjeLYp5n72BdPeUdnWykda6WobWqB8BYv1+bjgbZKRPTqflLVCQJKrHiAZLgz4eY19Xkosb+zVV3CML2BjeABb5dXu6RVJARfY8v60PmGxDVqjOLTxjD9gFl8mUZ/QDkZP5neOD60WHpRf0PVEqIi3hlMvmOKQVeBoSuG7MOCNtpGFGxLFTnzSRERrqUYpEn0gIWqJb+dcbbyG4ZoZoWDVKO9Z7y5GeedvZouDa6yDyxvDBuQBS8KJmAz0sL9F40MBl6yDsit4maDN3/3553wPvpXuC/S1hWfdqHU4zWTj9dMPl4z+XjN5OM1k4/XTD5eM/l7XTP5eM3kP/k1k1376rO1fGvFWJg4xaxafl/FXoPTLZd0v99dx7mTzv7l7ZJWbfcWFLU5fq7yu99Scb/FuyMwr7DQTr9vamBBJhWGmngnDyIFiAZMcvs0wg3Fia4WFbayaeMAeHUA31IewQX/UcA77HKg0UO/Th+Er302nNnBfKKXTjIpOkj2MbQu6DrqwVzZFvZhvLahUnRklBKewyK9xiA6q+HVRyFJApf41u/eMZk4C3tNir2FrNgeLdOVilQA6CsH7ksSYhMVds8wYGhdfwclui5NHLhRoY+wpQg5Ub1Z+Z6SSMWva6YQY3MHUScYACkhy054MqHd6UOloCVZf/O2r8JWTk7GUQe4cqlsipBbXDJq/11I0yttYgDFLgCm0G3cGwGj2NQ7BQ1V2fw3pHyJcrneSlWKlPT+rCvIk535bzsDG57bcRB2nvbTvBbzNbLOt2bKvlO8ggfF+iBtUOYv52dPPypidvdHo/3ddbwT/9h2MU9z+zZi3S8UfvcLo/9AN0L/wa58/oPd6fzPeWkzF9trXXKOsdp4Y5DL2JEhdNmqrr17dPfg6Pmz42frsqXiFbvaYl+5N+dvXlkwUUcJPVPsLGzYJZU4UHm1UYxWeDpddp3nxFfAhNgTLlTgVNBMqvmey+FCUrPeq1jB6RDjdv6dfcCFnj+fj9+2jarxP4nuz4h727f+PvBKRmi6nLkeoz3dFaD5Orfj1DdA78B1zUBi5WFChtCX4qFsWG2PC9/IonMYgPVkDqMzcqaPKfcx4Oj54aiH/b6g7dRjOkWbB+qILKwxnK0hscXbVdKSQk/DVNNKVLBQrYrHMSrSS1r/j6xP5ZG3gqktzc0qkXbAXetDU0DhARrD1737/Q/ZJNTeQY95p5b6YIUpolbdY9p1xo5m3iebdnt38dLjdfWP19U/Xlf/eF3943X1j9fVP15X/3hd/Z3X1afE0fw39gCydLJjV6btPJUACDFhzcFkR32fenid8jMBM9qrS+zdEjv4c8NtS/vPnx0friHv1I2r/4HK6aWdOcHMrball5VNZc2+WUNue/4Du/4AQ55gKW3G2IC02D3N+pY2yZpssW625g5GShEMIusJ/sF6glVbppckKTy5WHETo/KEqd45bXAWfzgavcwoUsQFRcDbCuJt5Q+99rmAPsuFJHj47MInF+O3TzNnB2M8XOPoUg2T+HICntgOwhJVMjbskkah8b1tuOLSldumrSt3YOHyo1UqEPIE4ELbFDTNwd+sorxsv+0n/L9krKTa8DzL5e4399xcnfXhWjdM4XyvpNjmURkWyCd4Y2Ty5PSt5TkgBZszJXEkfi8lfFd062Um3/H5goy1bhRFdvqF7exPTsefR6BGGLXcOnHsqOTJ6VOrEOq+uf9w8TkTS5qFsWKbDHCWDmwRI0/OPnX9T//1h4sB+f5fAx+ci3xAvv/hX1futR2Q07f/egevJKDJl+EbRIBLbrbNOGHYIOteP+2j2hvZ2Go78jfObj9nllLNqfAFOFueaTq0Jk++/0wBci7yL0UIWl41gpvfkR60JMAAZPnhE+my6WLoT6ANEvTYlVRX1pPwsErzL0EZOz4UtDB+VAQuB+TCqm7verfJKS35TCrB6SdNX0hzZU39B8z3rujC5dotMelyco27gmG5WCeCa8/GCiSR8CLrm97B6GA0HL0Y7j8no2cn+0cnz17+r9HoZDT65NlO2UyqhyzvZ03XdXK951T3Xw5Hx3aq+yeHo5ODo8+Yqq3Lzq+u2fKKlnMcTotqSzw9DuNFN1NocZReOXzN+jf1+4vx5044b9QN29JkYbTY8dxkw8U7ZQlq5P6ndsokLobLJkzA2jr++HOMf/YSSXBt6qOD/c+lFPtQS8GEeQCx7vI7vPLg4sKjl8XN2rLHCoh7zvb50dGzFx9rdfiJFPiCXhewAsAFyzRZdV3THEWsZMpNv7l0MDo8/qS5aKY4La9cv5IHzOQLNAh3Q7f9T3TT7oD+U9x2rIotNPJlW7SG/3FfqhtugSK0rBfUNxQZEB7DetOQAxGKKBECtqmFSKkt2iTEDvh8Qe2VJKp/BY6Ovv3zn1+evjh79edvRy+PRy/P9g9OT8fjT1uVkGC+del73r3eMl2HNus9IpWRH1l7v4TLE0kgE6+GzGxjRy7IXyR5TcWcnKLoUJKSTxVVS3cXW/DNz7lZNFOY7Xtzidt69uYSDvrp3lzuZ/uHe1rle67SaQ/Esv/J5vJPr589ezF8/ezoWe/6wHw+ej781PPBO1v+GN4EHd0JAa2+GesFRYP5eSmntIyarmDmMwnwR/AW9M33h4vPmtgf0VuwKgo9rr5hbO+qO3fBxeW/tqr9gLz+1wsqyLdwGnGdy8SdMCDnIs+s8+Dr8Msf2kvQocpnTTO1Tbc81V43QcCrb9adpf9is/4D+gR6iPDwOf5Pte19psR2VcO/tekZYDSvp/Vy8bP7zCrMaM5kt4fJX5i8TwuTvzAZGnTktl2cUkvkR1Lfn4NG08NKHEwivWIyVhl2+9ZYA2bOZPwkLQP3ORbO9vE97Q3LF1ZpbjsOA7Pzd0EDxlV9Lvw11A1yY1nxCf0/cm6W2yqaPg3CuXdx3+BeDEbLdRQlUrOZMFcrasDXwvPyVg59EVS+liAesdnVm+fydnwfTu2f5JYWI82YjYP3T0YqsyBja1PRHsStmnbFtdzW+px6zfD84nsbR+rF+nS8EdVtsbtHcyOXnFJBe4ofg4i4J4pzJq9quZqWl+DxWoo5N6ing6VbUmP/6MVo9z/JTinFzgkZvniWPd8/PH42GpCdkpqdE3J4lB2Njl7uH5P/2l1Dup+mX+XA2P0BNzWH/lLJT2BnGoXyIJTTWjbEb3NFBXp1p6qpWbAlZDxz0j1JejkNjoOVZuxc+Zt4bOtPtGZFTkEpceOOPTcH0S2x3prboVeSerHUtrGgU+sHJI+Ka4LCW2mSNvLWRYaLfxojK3vcJOdJf+rNVGojxbDI19arltrQcls7dvedHc7u1tV2VpZ+cRrt5P/mb4lps6xX2qW1vcen4X5EdJqyU7MDSUV+On/XNR59coLvxHTLC1Yu3YHrpQPOcP/Pfpq+PBwdPtjDrtgcitUWheR7O+LHZOTwr6ebcN2SlPR4bhSSf23YlK3zb2jZuQUML33LZPKb742ZMuwgamcodkre2zghf5DujRVur+KC7v25YULqqzFXTN+HsQINUv0u6LjJo7u0XMzJ/h5UXUjAnpbA9p3Q6EOx9TJvX1/hld2Hap+FrNKLs77yuZGqPLH0x6JvcbXRFFIxyHAiZ4kc7t6aIMjrs/E7BFjH9sabpHuAm8/qLbhhtrzYrrc9SYALVR1uoui5sQiN2fdiL7o1hLd4qKdrY5HMOqyeZKV7Tv+ufXKnOQdOx9eB0VveTjqAc4PmwC6fPHq6007g7txfSTW3vXC9aQ4Ce3ctoLBw//Obs6MBcnP2n9r9UyvmFZeMjIsiIDWLrSJdJ1MPYrq0d1qhO0IoWuqiaAcHmr5e090cCAlINKupokaqIFJo9yx9ogW6miLmMSAWVb2gz66O9g+exgm2rQjaUze9qHp90lZQJD2GGpzyuEspIEEUOqhgpW2Il+c+2Zi8sorQMNrWHmCQrv+gz3yRAn4ALT1E25Y13rESULQtWmKM3SYvw6XyxKDNpihIzdDeMNzeVC7bbgf3F15/hCL8P0b9/R+n9P6PU3X/hy64D0iiM+6KWG2f3ClWx+6Gw9VGv/7mMb/3IbS4wE2myY0GuJEP32b/4ndWEqNtW2NZy2C9MS4+RC/guLU90NVrndDRF0p9fI9UjOpG2Qsb0guRvgNA7FC9Uvq5oKpA6c+A3HBlGlqSiuYLLtAy/Az3FamQFc2U74f+f5sprryyLV+R3voJMuXuQtAvrtp+v3L9V6citFd7/XD8/Or54Rreed1kjaZz9gCGt1fNFFd3X2Lzjilczm3LVa0BHO/BTu6F8RkEPq1AzuzbuUzVaZyR3LjTx1+5McIZsR/5kpALhK3EnMwoNEOI5NGoXws8sX9QYm5hLCrHl/4GwtX+RmA+itsE50HBiRDD53oQLyEZWU7aj6yD/wOh0DE2xes+uzxdoILr6wwNerPVXg+fm8BipGlTOkIvCfJkTps5e2p7CIdLFN21pU/ofI47abrt6IhbE1qWuBP2Wj/1rc9i5yJ/b2Euy5Llq+0Q7k8C18F4uzTAmIaJPwoZfj+by44M+rRiOZwiwfDq32+DpJ2Y22V5ur0ABD20N9hjEaJU5C0zfz7//iLggs3hrtR8zUXzoQe2f1HO0pEiRGsJ+oJA1dm0UVKcfv/28vuL7x+6THMms3+C4IxFM8Yu/hsHaLoT3dKipLsnDv4JQRqH/D9NoCZFd1vs71F9aLAGqAZf4D3R/GMEbIB4P20fgzZ/1KAN1uwxcLPVwA1I/s8UvEnw/WMHcIDo/+QgTkoL6K9bWq3d7/xYgQYYO9m858ZfuNpWfWtyG67WnARMJ/DwVNiTiplGCR1iCngheEqy3bWZ8mIbc/TxD4sLTzt5j3WkNYwO9LtFg5elRhbhrw0bQF57N38b9EJcjIs5rt7gArdUKsLEDVdSVN0O7z7LMtb44JY/Yt0ioPZkyqjJLPX6KFPfkzK83jRvLC3hdV+ZfRilovk9h/lsJiNvxqcpKvFlEEdIY48Nn+3nBPn7b0/Ji9HhAZZGN/M5Q1P0E/KK5gsic8MMeeL7iw/I8XCaJJ7C3n5KeBLd8V6hW0l+jrUdfycL9oEWLOcVhZthjmLSOb8JsRW77hGm3x9uYJxPuHZc8zmSTjhu/2IqIxfOpEcU0L7oAqk+9uJwaHXGxbJesA2H/u7PO6PRcDQaHr2y/302PHiGe5RWHx7u/H2dd7YlO97eKTdyKoLIcBIjkRaJlPhB8A/e3Rj0MOsX+rVB8ipP9M7ERrceXWr/GdIvW78fciBRHAfCIwBGsMQF097N0F1WI7HHezagvxguY3Nw7Bd3EW1yDsFsJXBv23tOy3g/HVhMzWjeQYKEKYOyX941tEKCmubXzHwdInjYf2gycLEdVlAsZzbxNxDjD0iDbfJCpMcfgA5SZzNa8XL5gJl/joz9/oK48ciToJMqVtiLbgs25VQMyEwxNtVokeccov2tmdzbvfNpyvLrzeYP2XJrLc4FTFb7wcY+kd6ruFH5f0Nz8v0FeSP/QW9YH4WvEQYst8Uxq3Nzo8fpQHUhit76q/h7Z3SYHWaj4f7+wdBnZfTNql/L+J/IN2kPaU/muxjl379ZnYrUWci6+noUvXsmYXwvZ+AYk3pAmmkjTPMx2ULVLRd9s9pix3ukgVvBP/Hjhou9jWwv0XZngVydPBcm1AKRcOM5XpwqSQtr2jKVc1o6Wcw75sv38XWNovKylLeA7I3GNu5ro9VPQv4We3pCSoSYBrCOLaUF/9BWs3t6pxq1HQP8s5TN7q5ipGA2Duyud/Tmq897wmWxXplO726DgTttFySmV2TkXcmoRm68IY222dNQLWXNBEagwjbXYW6oV6cXA8TKkYiNy5B5ogdQf7Flv1Vjp/rNPfdewkZ+U22Jk9b2hh/+XqJzf5TtH2b7a7Po3wlf3ia7RLM+OVu1xxDSPC1lU4R2pSoETl2tGVjFu2ksFqTk14xMzEGGaxyaapKR8xm5qVoOXQ+NemMPd13NOrHa0PU3rXFrnSgRYp8zZd0ua+oH3mVwl+J5wXIpCt0qjPEG8qbuX95nB0frKMHg/B3j6DG+Hazfr566jEEy28RxSxNGuKjbNTLrRwrZFBuChP+tVRMQaFdb34nXSviM0BvKSzrdcKHUuJwyZcgrpOSxnvPb0tNm/WX/sxLzk4n/0+XoJ7h/3V3QwX5Duv4aYp1u8l8XpeD1x7g2HR0qSy6VT/1OzyKcVtQLTkGokGJZIZEwgsarCZv94O7d5jMywUcZLybgMvdHCIfYsxAO0Jlb09WrvtFklwohW3nglcRNDLmVWMk6G/pVtXPrR6yfz74Ean9ISXuxkCr0gbcXr3PRT6RE1FIrar9Zxd6+r2TJ9Dr9vsqmGCtFl8AWIxM7csiwgFYV5tEp3+pMZPfnnWs+pYJe0aLiAiEJxXBRBBJSATSNR9xB10ABtHxayTi/vHx3j4zzb0P5Tuy08N3l5Tt3+zQa45BoDjaqDKYgilVwN7lJeRMvNaoMc1cMTRY+oXgtfDiVxfJzfcROTpysXcTWIcFF2p5/BX1isehbwePjF3ej7i/zegDy/x129aUPPDk2+igVv2NlKcmtVGWxmZpb4IFLm22t7+KEJ5iETR1bMArDr9+Js3/47MXGqWxNDdodk2btCML5zBD17axN5zgv5VyHpPkIOy85biW0c9e23zWqOnBljNAQFFKsxqF50Var2QPcBncJJUKKIQpXCqoKxyKOmG2qyOTfh+8dZsPzs0mECr3g34enHlEuBX7NdntX4OAZOzx6/mLIjl9Oh/sHxbMhPTx6Pjw8eP58/3D/xeEn5PmHBayYWcitLWJnndzQCaHfKQ7VVtrSn/3seTbyl1IG39m84QVqhGxhjvdXFCctgJ3L6NiybjZSNRqOLZ+qAtUN4KOvzarSvzZMLeHw3mkBje1miWg4j1kc3Sb/1YqhkTSSCHPa+DMlXMFjyyX9fotA3XwDHzVeV4TGJytaLknBjA8+EfJ9B1C4AB6JNJ1CAC4srQ6yUTbqZZ2/vLockHffX+C/P+A/8uJyMy9s+S7S3Tfc30IRRJOVSF0x1dmIsWzGLqy9cqJTDTalcKZ6kyhcPtmFZw/A1lsFz6d/f3LqPhheWiex28cZOcXVcCoEf6oUZRqBwv8ZYSajodogBeulQ/CuLVhZey7wq2+HwWVTmsRaX0IqtOYTMz63t3B78dUvLHhF52xvzh98g5THOFNsxpTaWluv9364NvczFRK9J1JoBzot5Tw2I0RT0J456VoKzX533cuh8VDlK0X+UfuC9vUxOt6tfgV6/t76l5/F5ylgfjJ/NKHt0fpyUjtZ8i8otj3UHrntfvkUwd2R0hGqVzC/uLT2hEYPyEZvSDh/KEu7+NWmpe/uQTfw5rzzw9HhGtbbjddZfP2Q/TvLx+MCejFxqOt5OF95fJf7AQIrgvEuAZvrGRrXoyW2gvFgc8asCeLS6NZGJx2/oL1Nz7lUfL6oIHLqmMv3EJlxxW5pWQ6Iko29f7hEmHpKS6itqu0E6oPB9kj5ELdahLagorABZBpTqnIpRFROz/3nTseNUCkSDedlAqglhEMwQNNMaFz5iYtGdU0FwayQzlYuO5iErLJegrQR9ChJHu6boSWnekvsGNkI928jZKw769l68Qc9dUFhbRPgBDe2lrZTgW/wCxXKEprbW0EGyBf1/1CkqH6zTkkUJrULI2i1KWLtP36oZOLF1ul5frZKzM7maKl58fbNu3byCWBCzs82nL4PNq23GF5pSYBBN3NU78yYWdxrbmFepZx35eJrOb+HRNw9W+vOYd3IOGFLOZ9D0FQsX1DBdeU95/ahUVRozCaagBCu0PljRxAI1nZ1P9oVZG04DzfI5hyGF4NM3pOqHT9xfXcjiXqpSzmPA01ZcqzaFktkAnTda9m/TDoTCV/FTkZG+jQHjES0bFTOVmYItQeTYEUK/18mQTHCZYyK+pwKMrF0zv7FBqEQjLA/wGHgyJftPlhe4iLP7OvexdplnrUb+kFwYOFsRTtDkoP5URqykqoZod51hf+9ru1fzbZy495SLXZ3jWtegfFtCejc3/9bSJt5FbgyKWzs1wX3bqjaw23Ts0bYi4J11m65e0uf7uXZXzTq1l2Z6KLCisQy3LBEPlK0SjfP1f5F+1JIPNKEpqCUNTfRT0ezG4ab2uCFS+414i47B1l5ttpnLpn14totYfFxuUl2T/lxC8ncirlNt0ROX2tULGVjvXN1Y9KdGOUAJFZAhiyYCmrNhd3f8ae2LxohF7JiYYVdzc/klioxGZAJUwr/j9v/tHoNLTd4gZlSUq0vN6SB2sJ6X3YLpf3AXtNATgVFb3Ff5BvvYWp0YwVXuhnD5S8BUl5SHWpwuOCIiDtPbRzF6i/e8qIkb7SR1eaaS6nm4YJYd117NpXSaKNonf05/GuNkM51m2HrZiUX7AECzld53UU5QEwqGeL1wj6aEMxUz6awqTxBvEd5tfvLyjbrocLhwZ1T3KKSsrvKOp8885VZx+d9/QdD4mtsVprTGt2yIumAhasGs8kfuXHftYP1fwK4VsTEo3DDfo2slv2D3tCNi9GIvL8nxFdZi7Wl8MNjg/n4xCr1V6neM1WeXu7RnSDdylkUxE0nBoMVq5i2ZbUwsz3H6VhLmL4RQbtbHImuS25sHjw3BKllwjlIcc1uTZVJA4TnwnKzQnGd11ImHmxIMHBETSsfqYCVaa8NKyzE1JQOJPdQfMcwB6ozjTDZwdqEMl9qGWEu6A2u54KusiQa51IlCyie3ni08psVLsGXiVwWmL1URLBbpKQzGBeVvEn3oyR5yagAgVZQTshzubYn7TV7aAUoClLI/MrnxON4LLhGXmJBtMS1bDm1x/WU2TBdWvQ59Tq9/Ta45RTquFi8G2Ny5cTKhh16wWqy/5KMjk8Onp/sj2yPitJm+75ZJtbOxosBA6c7/X6dzzfuYGmvj9nEzdijXqWomKG2ibzfrlbw+RYkQX1CLNSqKBW6h6WDEXLDqQcVM/k1Y+T9t6eaHB0eHGLbP9t/fphtmFM2ozkvkTazDf/ibjJzfzcfCQgEgRUF0WoeawQ6zuFgA+8amcwUEgBT3dB9D5UM7hhvW+xFkPj24Fk/Ex08+yjttnjWJhSE+jx0rvV7E7FnfnZTvNg0xxoh+7TlztdijRW2COOGGX02S7AWJNfkmPxLS7T/FTX7lkBWpnl9zhkiyp0b7AN6BXt3QhD1ntsiY9mR91/u93PU/rOjTeSOiHzedvzorgvjfJRhVu27ju/CXlOqF6B7K4hSc6/trL06cITrKLfq3z4/u3g6SC07mGZryPvdPZdYDO8MCT9OsjtRh6ForfdgKAJZXAKYmwjfImBPHmlJScuk+jeXtXPA+VmHj3pR2b37EqtNTLBtHd5P5Xdjkjhgt1PDvZgD0nATZyQOg9+RKRIsevnhVbT/Az/4cErXKfu28/BOxyzWNIRkug2aMfVcVlUjvHroXHBo0O5VWZr2hLZKooOTNlhudeRkpE9q5xyghyxeD3a10Rh06pu0OuwBgaDWe7GtLTW2y0jm/IYJcEDXh+L9YLWSRuayhKXaVuVRNeVGUZX2G6DaN53xiTRirp0uX/FcScRCeI6m1FCcbVM1KPy2G1r6sr5e1olLjOe/DnASsqmU1wNibqFjKo/MbVi/EKTS3DTemrjFiegLn0WROK6kIh6XMJmC4TQrYsKkVd1bn8BegYTb83dE3grbkg8ueT1I06NuuQr3NifS5rOSAe29w0CikHkTQ2wRtnbBULJzHkJwOOVenV7s9J/plFdrLLchDaXXOv6UFJRdlwNsh3HBDJtpZcOJU4l9ZsueVrJdz2dk4gjv8mQmVlmZYBHgD0AoPTxXvivfgEzC5vY/OZWItyukm6qfMM+eH68Rxkses7zaWixxd+xKgeQsBl5gm7aTJufv/I0QjvuoJresLL2wjGDDdo1bgnblqN85tgzTSFkO6VxIeCpJTCI2MqQ4t3t7VnZrvl8zqgSpoHBS03c9Npio5POF2YvEHPLC3prRvw77J4vv/5d+e/jd/3rzl6M3/7F3vDhX//7u1/zwp7/+NvrXtSWKrLO+Pl/cu7NzFgYLmkY4DoyisxnPs1/E+3DROPO73TrbT34R5JcI9hfyLyHt4hdByL8QlvybiyluInd/yMYkf8G9qwQt/Ucfwl8pZPIvpBF2M/wifhE/In5U0bqGULAnopdq7tT0VlklBTdShV7E7IMZpCB74kataASYXU1se1hQ5Yaz24G/3CV6RTT5ZSdMeCcFLRX5ZcfPfie7E99AalwyyxSvmGFqDf8UdpjK3fh3EF9d1jhQhx69k3PLtDMgv+zERbN/xUXb8bMNy5YQIvtFtJ7jzifeT4Vz044aMSJ2QKo483c5cI0bI4RJMTXSM/B0VYsKFiAa+mMJtdVbfDpOHCRDb1j46rTsgHVotjOJg3dG9JuiZ6zQqDAFGqAFx2WCxGVbyJ+U7Se563h6fvEOmcopyL+9exuPeK/HK53t9Ekdv4Br4mUm1S1VBSuueH1PCcPrTfLDtjs8fxfqw110N4k5JD95F3Kt5If+/NL9lwfZfrafrQdXOGrftnohsu1B+i4cOG/t0ORJOAxub28z4JRJNd9zuiHUFL0XjqihQ3b9QfZhYaqykw1DyIU/nqzahC4i2LThS+2ZhZZ8LvzBCMZGF/1vS3lrD05t/+Xr8jqwbY2OMytCIUXf3HoX5Pn6QgjB1BdzxnpzKrNQ07QTWkBn5SJ2NsEO8hIsuymp8C8ngEl3n9pMQcFUBf782+vxW8eZvw65GP7qHhjqEla4Jr5fZUbGqIZZoaDHK2Q0YPiMO/+6/bdPfbBzSHBbyS5p9ApYiw96Jfp0HBy2VmK1sZDj0UG2/ythIqe1hryHOol5tkeHy/brAHYm+0+MXQ/Ij+iEu6DqOnv60FwHuyiZn+0Dlvxzdp1dl/WEs07aYh+j7o8+Y3Zb9PR8790WjgE3pZXdOdVPTBzc4iTftoa362bkbsAD33rLLmTr8+ja6J3qX1CkQn7kM742nY2dEO9j4PUZcqH94aeYcv7bHmOu/aXHnAs/RpDBsNts0B0crlPCy/F7kuJzFnb39YvgAYvD+sQv9iEjOBwHpLTn3D9ofj1ok3vi639QD0JSEN9SNmK/DdJeeFkQmCPReJxXyfaLoeGOSYiJ/+vG6zYe9tp+S/mSLpGm2xT1gJi8HhBe3zwf8ryqB4SZPHv6x1wRk/csSG8hzpdfC5+C//3FOXkjC1YS03HIYZJhG7wGdTPQ9NBRNvHu1ZrlA1LzyhL6j0lmIL5G5/+uZ/5/79M+zDLA60Yuvu8+vTN0MU7y+7uhC+r6ftPYDngAUdsgzAKfcY+jv2DWVA1J4K6eaxDg2498YvhHIQ675o13peDMdU2329M5Ma7TpMZwm6RDE2U7dgTip2ot+NiKbq3YDFdyN+L+BCBazgyGy8KVBKu3W4aomh6QWzbFGfnBuj64MKqxbWN9+ZsUe7Wy88XD2Lbdo5D4ijxgZxR4sClKyYg286WUWpM+0KDq+N0bT5rY9u0y5dck1oQbAzeHmuSsU3+D1BKxDILSUt3NU0e+0KFMwPGGJvQe9Laz8FBdxp3ieUbeuJwp6A64UAIze3X5Gr6jWuK+NH+NMhdYAHuPQOuni2CCpgmbDkHKXNrUXGiJgR5YXagYnxAfY91CrM81q8Ne9y3kyULCHE3Lu2zoKqlTsjobaG4Ras8w/A9S0zGEvY0lBYPET+QXI1zrx/KGbEbIhatXo6rquDM7sENUit5dvRaimbaGDV6M1Ro2knSSxf+lrWc9QvcRoetrkkUiZY/VbF+8mm2NxrzYOoH/WOVtaxTZorrS0uQr1rv1TPa/q7qZTu+/rdbZM+H+y8S+ylyDGRbuEwthqiD175r5XWfGgnXC01QxCvDdM27gBOi5j2wNyCsf7umen2dvfhqQ794PyGs2x1swuvuI/g4JffmVA8fMQ4n/eFXt41W1j1fVPl5V+3hV7eNVtY9X1T5eVft4Ve3jVbVf4Kra1Ztq1+2GgJR3xazj9LleLC626MbioqPj//P7sbhYdSk8OrK+uCOLi0dP1oona50k/RLrv4cri4v/3r6szvz+RzizuPjdvVlc5LJKswM/3ZsV6i68I8tPLh4qQYKuebKs96oD+B6erLM3Pz2Y2vU6jR+YidxmGrfdYNcZY8t3sHeuX1/H6vE69i1fx/5V9u3uads06c41DsVJ9kUb+PZVemmZYvyyU5QYeuImxQIRMJ+1KcNBZ2qj7hirsmlMsVMq/Au4YndOBf9t1Rw/nxEh0z5QwFkwVrAivdzS41WymSGsqs0GI3r/CmkNy4u/rC3Q46XOj5c6P17q/Hip8+Olzo+XOj9e6vx4qfO9L3WulSya3GxpCkh59CNuUPB6UNcHo9Ea3popTsvtljEGJyoSOW3W6kqxX8CtX7p8eXvoctHeFJFS0ZLUpqTazF1r9aJIOtm1l7bpBXRR7BcWQoOhXLKFtKyZzvqaw4bCVxVbOhMyCQqz7RRbaPv/avv/rOJq/yHLktl+si47Ef9qk2M39L8LcNdIvdKy4+sR+292oPsz7cWyosL0hF42yowvjnJkVz9k1s1sTwyWlez29V8+2pwnNX9CJjMTCoWylhHdWZCECdqOOcgdpiIYLrDIbIC3w8Qrwc/IyJfudHPjwaqzPY2oUlTMbSRzxkvUwlsc7J2ZwV6zbRLtWYJjVUWbL6LRzudTuo9vzWu5+dLldArZli36P6aWnPJoazUFjKRe2QbxuL2w2tg9tgIE8vehZ31sqtfP9qvKXvZgHvtvZfA/Wvtf1dr/b2jqP9r5X8zO/29o5D9a+I8W/uda+H4vbYmN1nbFQ8Rlat4H9NESoe0X77Wad52HH1FmNPu4LgMfjEBWpGuF7qo0w9gtruembQdvnbe07AMWPmyTwjCXQVgKaOq4KbwD1yYzRuAeGYeiL5psoSHpDUDazMmHal1U5QuOAstGsS1xhl+zztC9XPDh+PnV88M1lKcNL4srT8It4bw79vuxd50hHyxW7cLOfJshz0oRbuSk+CS52Cp2YYIvlBty8d0YkNxl54pZ0VJEZuyVDM+ezw5nL9jxy6J4vj8dvTw+nu4fMDYajaYvj18+f378/MWL/VFePFR45AuWX+tmW2fqqR9ujZBh9tYuRBPfGe+yfocaz4+nzw5eFvTl8ctn7Nnh6OXL/EVxTIujfPoyf3m47odLkNjSTM/aP8JkwwL3zej7momQsVErOVe0sg6xkop5g11lpGdFbbPh9tCOEo369xiyOXhbBu2zE0Mh+hopPMmvdC63pnuci8Iup5iThbxNiWFvno9c4MurGs3UELKvHJB5Kae07KWZ+2nTBFnxgMkV1LBNyF9CGNvOXb04r1O45DkTmj1g+E20/X/sfW1zGjn27/v5FCq/uCRbuA34OVX7goC9ca2TeIwzszuTf9miW4A2Tav/rcY2e+t+91s/PbUaGgfsmHgSNjuJDd3S0TlHRzrPy+C2dq6nMw3sdNm3WQxbIePJJ1wHKe5fWW5OL4V7vGkWUDLXIDCld9H9F7HTncMIq6oSuyFTVHfsx6womifT6F4VzDNDyp3X1fKtndJwxNzgraCxZk2q8iibmbLgPDEH3Rp7+F2gdHhRD9rSmVcypQf1zkSiH15I450Oi2Oa7QzFTjNotoLjnbn1qK71LAnZmhb1Drb7FOsQWTE5+XR5bsWnu7UpLx2XxSWs1O2Z+JXyK7BgWXMoIFvBnKuembjarYCVR/XnsBxYanheuZ6DVmu3+V0Vyyvj3Jm/A6loJqNbmatviWXR8ktBULddNvMRLT8ypgn1e8FheUV1lTckS8d1EqVfhnXSz1CzN8EHQzT9TSbq4//QrFrmZOl4VdKv93ZqmWB+VrcGvX3LCtWsLnVC3qle64/Vpn7Xuja5EFmObUVO7lk40T++ujh5jXopqqvSX0JN6Vx8Kk1LcpoNWe4cEuDJyo12f7C3KruUnUbPvTKblW6nLbkBsay6bQMSEcQhinHKY6Y6o1Yu+D1HvWYxyElHZKnIfFfb0ijwIFw3GrxPn4CFCzqbhrvEqjHPmlVZt2wz7ROWfBDsBscHjUbQPNxr7q+6dj5OUfp/Tcv2Gg1gtXyMpCvchAiF5MPqA9JOLFRkexuGFf0YKcG5vW0DXG3lsgFPhixLM5RW7/NEVSlXZaIIHcBfm6FIfsp1kV4MqxtCI95xu9w2WNVmdOYFqXsHijCcoH9E3bSA0dUG0Ul7iPBdlBLOqDNPAFZjNf1q4wLUqkYwApsy1b2gjy7I+QiFtbYRFw75t9NqNPd2Gs2dPKPhF54Mt8c0xv1qWyNnGxPCkIfi1dUHaCM8OGrshnvsuNVq4ocopPvHB7uURrsHUTRYlXNs88VrUG1Bburz7p2nSszeRfvsw1Vw8q+TVddu4o3WvWAz7VMXvuXOis/37RN7Y1A/FwZj7aDeWgYzHlbCIsHUXmhKHz50naktax22E7mdVX6RJkXYhmpuC6+ALRNXGk85ANxwhEc7HhubFhClpqLKG39jp095dEPEIGcJ+pdMpfVX6KngSWAxSulZqqvIqZRrcQWm1TYR49XAlcaC6/scVrmfDeWaWLLWzjI6NVXwFfJoNlR1fmUdyMhy58vBQmlfiniSM9vL3g2LL5m7oHqi8j2dQrjrWBuNMZQoZqrZWCJ5juwVj5bVMq/255bSo/s82ZFyhNyU7Rh/w2iFf5uNAH+aB1UZKsDntSpGsAJWHyxlfc6SYe6OP8tHmEcFGE2r+8YWB51NMLHVZk1jEmAB+O5PULCa0ITGU8klivSNxJ0bckyTaUEncgebhBMcKP4Nunnbi7xXJ5V7AVUeUK6zuCRxYyrEgTogciJTHnIxka5VWTVZ9paRKj4lcEhfo4kDhe4RsHsuczlPlMpwt74Q6NK7iCZv9dd+w2vUvyRuNr/9QNViank2YbUnrgg/8WS4xpZPV6OSydDeogBIiTlnOJKbapxenyb1uF/nc0yTyYAqfS1COBwtrEE6tjKoKLWs8uBidmsaurRTuGL+9rGncvGr2SgU4wDzsuA+DQMVDfpUMuQ0n8gX484KWQbyIKQfmzOfLCCHFQux0A0zwmya5nBLpCMeEobWibIQxv6otzTmkV+JB3p1hkLvZj7cWW8ZmSTOvW77L9tXi1fEYHZ8NyzM2pNE+a9YVE3Nk8vLj5fXnz5cXX7qXZ10ry8/frx6KjknqoDFugqn9PR0pesZIFKyhGVVi/4mWvvMqnNGx2sWJJjyW0oTNZ7yLUJc4GzxZIi5BQeF8HCDPkKInPz67l9/HL0/av/2VLRj27BV0P6Vk6nWQ3CxNO0Cij1Zsc9IOKLczxH9DZtTX+SL1xe9Zw92aEyqODa0XBRiyUe8EIal2BsI5XJBaaS7CxHbPls4/1k8JWrLq2mNQKk92xmqBNI3RH/1zQBLUVnYNC7fEbQfHLrnEEEjhRUKbyiDxFSFq6h7hPuyUrzSEo2WkJ2Pxd94TJPoOubJXz3qsUy500kc29UhMM3kciu9hEW+QJoNjbWKihvaKCwzioreHjSOi9uxR1OV2TJ3bX6iKuPrMWQ7htzMiFNfViU97n1r7TC6OKeg6mhgM1WEtMjT9x1V33/AiwobcGaYaAzdiUX6mUVuVDEgdyqjvxTDqByzuJk6tUcHB6u8kE+fzrp1tKsei8QaMsg/Pp11ZRHbiJ4CXvfUMTYzlhpP3ZEG5vKq2YtBMZm36o5IZJ5NQiW0qbENoNjSHOaQvKLYW6AkPlStUIWcjHnOh/6t6+KsSzKGeCa/Yat31pp2EWhsZgDSXa5hM6sTiruJnE1jIbbGFrCHzorV/Bq2wr39/eh4cHy8e7gfrcygbq89H4e+yHjs9oypxN8jHlaCr8mIX2bXwu55vqDc4eOMGdimGFSdw/ZyYSAtqm8qZs0ZDBReF4iZ3V66W8DE0Ec5FHPcuqTCYjIrO9RYpgW+mdmNq6T9ggCW5u7hL0uSx6IPWzwYR/srYO8pAvN9d19JlaAaEDmizTVB0nvXbn4FlNb+wfqAae0ffAWc/WZrfeDsN1sPgiMjxtJ1gdPrnpxczICzJH9X68g/tKit2SMdEHgSCfc3nICo7aPDXTMYU2z9IISLj3m8KFClSvamNIPQ2xjov42BfkmW9rC+MeO/BDO+IciPZ82vXtjGqP99jPoLqLGx7f+lbfsLqPpzmPirF7+x9K/H0r8A+xuD/5oN/tV02Nj9V7T7OzRuzP8/qfnfcMDGC7DxArxoL4DlU7fzno9R1y+nvrmlf1WkbvwBT/AHGCx+d7fAI8D9vs6DxwH8nV0MjwP6OzsiHgP0X8VdYYD+AbwWa3ZJrIbflAU/eNZnsdCfPP8T+Z8FMjxY140Q79N1Z4IW6/+Zc0ILLGyyQzfZoY/LDi146KfOE3VYMKbsdS/dTPvSMkarcDTk0eM10eXqtJwV1hODC5VW6UVJGOu7+Y30GTRedP0MHrssHi3plnrUiux1kVcXEdpr7bUeC3j6/PS4UFNZ3NdIungZzUcuQ2n9K6zjwdpituWwzyLGqlwJd63VaB5sN/a3W7tXjaM3jf03u3vB0f7uH7VHrkbJ/Sh4fspcqYnIWfdbs5VZwRqPAbOUhUWuNUTbjccuCGnuz7eU76iCqgR+735iGq2E6vO6tnLj0JRFGzsq3b4AcAHp0MQ1ho/4QBUKy4t1eM3yCCX9TNzBrSFZrg4UnhsgrHH0jvV11TFViiPJY922Z8Y7tgr1JilWsAL5ZnZTCXM9FookKp8gIypJn7GETNJK7mvuth57P0crUoQXRTxjYS6y6Y/EhWA2s0DiFmhPbYPcSoTujMSY7VCUMFwZrz++sWNj5YCV46c1b/zkdo2NQWNj0Hi0QeMnt2RsTBgPmDD+KrYLB/DLsUo4kF6qvcEC+NItCRbOl2gjmIHtJWv/DtQfQK//qgD7sVR/i7mXpdRbqH40dX1V5vpmGr1dQ8aGXObZtFxv8rL86UMFJ08VgpChpAKwc2FvBW4Q21wLDTtXLseIiNRAlYJ/PkqX1lP7aC6oRM1K7jKeozilqvzVp5Id7BGWhALhzt7mRu8bu/BsfuFFo6Mey39DSduTe1X+5JINf0UdQvNZvZw9oEpZylTvHVGE6KaCJyZ94CZOr/HZTeByRERqNArU8TD3u2LMPsutanPLMtrnMXKUaOKHChYB9TAuXp784/rt2Yf25b/1yllk1ZRaFav98evbSbvTaP/269urdrvdVr/jh3b7778syfYl8ut7UgUDVF62nkT8js4x0M1PQHpsND2/ablckPzCIQmNwBKdtVr5JlZh6WeZI1AsI3ky9E5I87xjIDUleQUC9P6oE/x78q+L9ofude+P15pX/ABPBwP3OnOgeZ1pUKanZP87QZcLiVuvmVAxN0Z//+n86kzNpca2w8Wx3yTulmYcEfwkVpU49UqSyRjdINVaC27HmN3fP152NbOf/OP6V/xWAt2NW2I8l40XsZCPaUwyZlJOtTKO+FRys9XculkQjlr7c6vz5nOW088Zi67zPP3c58nn8ZSmKWKhH5EKjaUF1W0mvjkn9nKaRDSLCmGCifWBb6SPTYiRVasH4nt/rLrCEb9dx+La/X7GbrmiMyZ2ZlrMX3l8vfvn+ftVF/OFTdewlnf8lm2r0w8pKCrVRQyAnepzuPfx9Or39uXJ50KTtsfGh6vPHX0v+02bFj+fjeGnOeWuAQUY/6NCovx8xxMQALy8KmaqO/d8c9SoCmKYy88QAonrGFZJBHWOVOEJBP/8ZGR5I5MqxH3usv5k6DdbWRKDs3A/Fwo/ePYaNae9j1Qy1yqrsSsxF7fZ+5//4VfqjbvaDJLluH6MmcknHdAQlwuk4aX8ViidhGZikkTI2uEsxNLsoJCt9mxVyV/qAXVI+bnfxoAroTiooovJlKQxxZNogJCgFanJhiBXPghmaN0JAJAYmTNGJQ+ReacnkoriWE9herbqs5ub2uLqQlbo9CZLPyE3BpfBjVtJG0I6zFjusqiAobMLdEXNlLnJ2oatZVq1NBsJNGsVfcmyW5bVbUqWGTRiMjeJH3USxuiEVyf2UeyqhOVQEoKByO5oFrHomqcBORugTyzqETCTeHd2Yc+OXBTQ8/Smrp4ESDmuMxppSkpTMuSwj59dkDzjtxx5VXXkkYypulb67dF4riajygLenxaVOryp3jSPW0EjaAXN/ZugtrJ6sEY/RDuOwQTQW0do4wv2EAkQlVmGMzdCLFHdbxRn4K7DfDcRIRNc+whXm8TDrRnZ1d7nCZE8nxiPgsIspqtlSIyT8HIiC680sgWQ0HgoMp6PxuC3V2AKeC7YAJyuGQ4iGCKgAOJ1sIzgKKFfyHxdihnwj32BmWXhi8FHXkZfNWFM8yxvaFJ6Rx9PjJz+2v0g6yQSYyR4q5nqBFtJmuRJ8xE2QsypZHJllPF0BXzx9CFsmHPh7KJy0XMzTyTLVpj7W+wVTKlmfxjCB1Bngc8mMZs5o4pPHjygLiexyYWThGaF78+mpQNOm7qpjhsU+LeilwiLMjqE1RtgwF5Hc9MsOWeExizLPY5MhEo51IssFEbbwA1TeHm2ZjTdCceqP4qumQe4YdA3VrRboKIxl7j94JjJMxG7ju2ybh/FVlGb5Kzb2zm76BVfoF/OHY1jWSd3rG+H9MrVeA9MsthkX8s6YUmkLBAkYoi3wPyQMPpklIy8Oulevjbdsl1+L8vDRwh4OslHYl1si9tWnYhsSBP+X3O+ojmEZJNIJNOx3XEaKCBW/yTJ3UiQEN7XEkSkoKPlOsc16oCY2wfuKlf7c6uX02z7XGTRI1RV095+uibEtYt++gpt2j5kh0SevUsxR/BwPjVHoEWPN65yj3mMJQZfQ1U7z9k4hS555l0Uzxn9sirWvDWtCXGw53ofWAYDTiyrWDwtRsDbWIRfSAa7jszhqyTppB/zkHQ/9HQp2ndXVxc9skOuznuwEuciFLFcFTs8WhNS2nr9Z10tGlHCSSfuwyZk2gOpXs5AF67tEM3eddkblxRiuZLxVma2ZmPlIGrTCHhNyPM1xnhBn+TFEskblZhEcGh0NGJf6TFruhn7XYyXRM9a/ZisFAGh8CAyrxTN8nvu/GPnn9fdD71rbLDrq/Pequt2HX/XtPjaZanFcC6gg9rYo+ryaj6fuGFL/GIx5L6FQEMHYygx+o5g7N66vl2tJkkkwklRjqQ8m9JSsetrtYIXE5EX3FeH3hR6blGK4vRfIPWoDpPSAMY6yFmjoG9VMjdmbjr4qstbUKsisY21Yklwx7/wlEWcqs7e+G3nSaTHLZLlayK8LxGAY8nyOklFzMNpXd/BcOcxAdH2IJ8iwE5JjJXvM9A4KRmzcZ9llXvH2ravL8xRdH2qb5ar4nEyeaFnElzwwKmNLvJGNZqELM4rWZ85pNBzc5ljqjTqYlHVbDYa+r9V8bveEFeIDhvdukPgIPADXRUa+gxYUbyHg9sWd61edrDUeu1a9d2hrGL2/M8eVDLb5lnwfsQGPNFeQQW40opwAMNA6ZSvUCSJIefAKTiKiMqBTDM4mIlkSr2Tde95zS99rv3/Wn4PYnGnXLpZVGiccMtddS6MIqrsUGaZABO/ZSxk/LaIhuMJzzmNSe/fH1THcpa/kq/Nl2ZQDFjAon1/mnfdxXJ2JiOQ4+kcPsyY+NjiRfWNpWZwZQg2eiTq6E5gbsSJZMoeZmOy5cbbgjxSp6o3rIUimQFcwl3uvjZatjkscGrklMeyOArNiBoUQALiUDkzhb8OY3nqlSbQ9ge1CjNi4fDkCWj8n0kSFm01tXHXvF01WIHaRORzQ2KPaDLq5ryzJomOHn7HLqHsYkXl7gSXBCLZmCY5DwEgYluAaJoQdq879xoTthmUS9U5FCVXc0FuuZzQGC39XfACFsqynJbMm9Y8nbk5BrBM2DFx6afF4aTt08bzLXMex4QlUltz0KNGWVaUg8mzlSvrz4DHsZNbNE0zkWZwRMbTxxgntBF/TfKxpnaDIqElmPMiqLU5wTPu8+FETGQ81Vyu3nHDKne+dLVQYi5RJpucXdQJtaZPCFeccPdECvBPQMi/C4wjjHyKrE3f3QX6ZvTOwmT3w01gPrjRaHTMpwLiEtzmzKjIbpvYUppgsZuApzeQdTeBBuumTiKWskSJRmHuJ0R4lRc5juagNk8pGSQTXEhWoNVDAW2mBp8eE74U4aA2BiKRiDGaeGqRoelQfOyN66SKGexVu/fh9VzlO9wJGA1HTr4IjV4d4c0WnPz7zYPjKlz4Jq7geRWwFxlG99Fb/+Kw1n8IMYwZOT/v/DIL84KItEr/74K44NkhSgC+xZfoAZDrFsreOWNYTB8N1WQ/2psDWG+gJSF+ipQyZ5SeL5iDY8hEEPJ8uiDz45uD00Fw20IKv4fVnNG4GkyR5Bx1JhcU+PvmsF7die1YR+dhxuI4tsE+CpqaiQ2uWs+H9i9Lbojqha6JKL5G6iavJNAHkeUj0lahZXQB8JMkz6bXXIp10amDNhjZlJz1PqpUsErIO+0HwV0X+xtQF3JMhyY0qsasOtMq1b9KMIdMXCuLzyJYzkUy5DncrLiYwU+eTxYgr/Z/yVYskq03ZPtwNzho7h3tNupkK6b51huytx/sN/aPm0fk/80f9AD8eQ+00ppqnyTLtu3Fy/sK7E2JRWEdmWXgdoVAfDfMaDKJaea3FshHbEpC3OSU3uHdlDr2gpSXLZg8U4EzJGS4BhjFaxALHY/ZZ1lRMtTqNsWRo8GLSTqaSo4fcNHIp3USWplZaAqEfBA58IUHtQqmNBbcaMbq5jNkwq42qFXRtC9kLpLtKKykWSpkTuN17eDahZpO7V5CpRQhLweduqUUCFAR2tLXGUwIkYt/QkF4a5X8koi7BCYASrA0NZHIyB9nF6S0RmwFdSG/pRkibSPc+dSRbyQFLsLmx2q8Hu819lY2umObILJUJOsUnMgIEMnX5Ob2r52H4F2T5DSwLhScv05Yn1XzMvSq/4pkHVC6HDjMZ49Uy7xFGPZZ+0Pbe27hosxBu9PO4FLjCd15O2GJkNdtnjG5KpPxdEkMLA5zKSLl7OLMLfjV2cXtHnTDs4vbg9fB3NxjGi45+VPQX3vf7lQD6ElO0AgxIdbWN6bmUn952iGHjb0WbGQSYajohPGGnEDNE2HOcvLKGJvr5Gi7zwtlCLrDa7zmrojGwX4nyJ+TNGVZSCX7HzJi99TGz6t+yxLhe9ayXA5iNeDriSG4EkSZoSMLJH3OhiwLSG8SIsUIoc7qQW2Fkiylme1nUVwmRtN0xBacBo3GdqOxvX+i/t7dbu3OUTChecDTFc7xxVxUu8poIo2ZDfb4klkIST0R+dC+ctZWU/yaG93aDQvPesZv4Sztvv/jtUfi8sGojpFY0Ij0aUyTUB3NXjCNyEgmJjixg1rlulG7YIWVPyrz1EcK5nvhaNF2TDmPlWV07tLCL/RIj9auW/OwVZJrFWPAw6S6MOTxxcssDDgdpeoieL1I11/IP08SgRBvIz4cMZl7QFh8algQY53xNGWRW8akb00EbuTTIpu1blwFbjhjl8SNa2sgRGCeC0Ix3oIg3PI/KAlfdJeBoLMR1ggtysbKQ5NmLOQSNy7FmvAky5zE/IvJKddRNHIyGPB7N6J65hWcw292dnSgjX4CvuHXAbnKVJcKmMhxVbznY+ce7U/R9TKFI4R+8emN2WIqc5LfCRLTPoulvhXC9a3MfarlBFZ/dd6V7lzfCkUw+bIV1KoY1cPIHLc49K+TS9ykSt44JWkwgf/if+EmGPCC1GBrG/dYbHtaxL3iAQknBEu1cqWiHPGpCXgps5DZFgEhZ/DDpTTLueeIIXMQKIFkmhZhKPO9iY10mhy+whIUZsFkhSeGlPmt7mEAZg90OppfUJ/Bl1fJ/tV7heSLcLt1d3cXMCrzYDw1I2iG0TuGynyrECRnpj2Teg5R7q5bieYMFRvrpinullty0m8FctJvljZl0dalDF6pK4XBgjfGVl334UsEauPwGFspZRkX5fJ0ZqY3BKtb9V6ai/RaLek7SEo2GMABeYsuM6kxBxjMvGJX593XdV3qz+mPBU3cuFrc1K0TVwkOsLPlIzMeFhv4jKKF6uy8VcUEQEEMv/XXlqZKki4SpAUllhep6rs5fkKUu/FQrYuVfAtqUQfAhdx7EThEDKrFBlKFyHm3fQEx19aY6LqhfB6qVa+YjSmP17RgmNmImtCqW/M3IAUUJPEC4+oP7dECgmqyOIiU4c8FzQVVzNyO+yzLyQlPZM54Uo1PFfXyYphbQbMe7lZTrVYA6CmLXtzUzcSQmRAz5U3esVkeCzaBemWdZnCfYnryasDWmK5n2+QBIbjNqOZgKn0TKm8p4BWYpkZwIpMFqR78vx48GtXu10+SIbqFD8gNXgp4pKMU1C/A+o293uHfgfbgzwbZJlHFXRGRbYsYkkdLqpjflg0NVdXaqgGr5rNvAdqLlLS9EbRx2zIoFkOeVCPJE7VUidpfZqFXSM2E31rxeTdF2yY4Y2aiZraeKWUdNutYHEta+3PrC+/ThF7TaMyTrTri75Q2lwyvMeiSuUoWA7AS8HAms69X+vDBuEsbw4e2jbNBfNZaor5DGkimza5FHgR8Z2ZY9IkNRRyzECnQdq9fjZh0AyOqTcWdDTjyp5PIEyexGEqTH+3aQNq54eIxca2PiDFj6YiNWUbjNXYYPbFzzgkCLt2yXvEBQq8Iu+cyl689+ajrEEWq5hU0bFPxVNoumBlTZQBlXRUoujEDKjEaCSZJIvKgVsV9R3RvsN9oDOaQtBbZWNF41eyXbJIk0FLsSqwubX7HhQVlRTMuPUqJga5ekIiIGc9tCRVFVJwrD6cYDJYGvFKBcPPKXNdUHxhTJmpMv6BkQg7vpORIV/WPRzey4msw8JjlGfJjAYJIioxZO2y5BgA2GDRSHsLHreB1Q7IxCmpFvqBx330QuQnh5LpYQcJ0KKBkrHhB6n1cAkPZfsTAX2lhgfCCRXUOKxKRlWvlBu+pm5I+wtWvYER1KaYLjA7R7iHbZ/0Ba1B2EO4dH7aiPjseNJqHe7R5sHvY7x+19g4HB3N8+nxH5eIbtMGGib31pJzCYomLytlR9kUui50MEQ8XEksMHyH08U6zRYQ6Rbw/8XNgzRgwVFBkT6uSCc6uBGzL8r0ME5vqEIoG8J5I7Ytwg5rQx5nufmf6Uzi+sIITmEV4aMorlHaXvaL5Fig8EMboRV+EiBYGlLeM5rK8RfHlDXZ2f2qPOdVKOHXl+NyjkMg3blRTLmSADYNBSo2Yq/mN+WvZNltxnrngUq/msOc5JiyXUccq2OgljipzCKzbeKTwlWIE+7KVooa8SuKBQ/zkM78MJkIwIrChqWtR94hjUeHEaBG60rdtpd2g5lhykNkaJ3a05XhsRoQ7alRx2gwAeFYR0c8mKjOw4c0AxmuwOFLOma1eYBCIwzOp1Yp7rypCboLslJVcLc7NVp+xmovMAmmqNlgj38TffblQO50nwwmXI0e1YrOqrY7zhUzS0pXBnItCwkTpJSwQW6TQ4CVB/VPtgnWiohheDEqLLnONG9Fxz2uyjS88HJtFjWmiEjGQq1W97eyc2w3zv+bB3KaTXm2h5xTpptgV6hvnsxI6mINqTUXqlEXbZlKufNaoFz0Ow0GgLvtVd+vSHcSd/iVFQaPZm8RU3HgDtlMXGZG5MRC2UIZudjcvEN939lZ2U5LKN9UsVHpmjkxGK1gHpUyVtFlCuaScO/ogtQo5ngsSC/EFaiM1dUyQ65jE01n9x6yudEJUY2k3aAV7Zb1QZfDMqIX+Zw9qhfpJq7fZQi9zSV2IAEAemgLQ5W2ZpKYdHXAQVGmCYB4vywrs6A0A5blu8q781E58bgVs4fy1UJWA8JPgLBnKi/Iyyb6SQ+bHdJhEMjMidu+CVClvllAkkkfKCwmc4SoW84T5FWN1PpAZtW+TrJQFOimvWy6a0KKhjEwzZCkn0OhWeljlK3NjW83MuGgNr5skOsxYSvJU5g91BibVz1lcu1Xq0E6H7kdo88Z5Mb/jnz9jzOB9kzG2yRjbZIz9JBljes8bFvPE7QtMG9Og2jiXWTg2aWObtLFN2tgmbWyTNrZJG9ukjW3SxjZpY5u0sdXSxvT98gWnjSkAN2ljLzptzHDRV1Kj0KlZ2ZzcwLlwWVOV6VFeXSqEvSvLaTL8S6aQLURR8EQc/QVSyFZTt1vzAFbSbBUTwQp5ZEbezMIwlzAzD9BCJnqWPDLfMLDJI9vkkW3yyDZ5ZJs8sk0e2SaPbJNHtskj2+SRbfLINnlkmzyyTR7ZJo/sJ84jy0dovlWOF7zyP3soXnDL9LCHyySmUiJjxiSaYHuZ/m40RHV9e4k0M5Kc3iNuZ/rZDPzZXQDB7e/Pri5PSPvq6v90/vn5vn1CBhkdM9wfg8/JXEgh5AfWXoKkGNjAoSPknLbHM2MisTbIs26vTj784/T3umq59trGlCM5YTwWiQM5KIaGRqEXFOQobh8Gf1MQuVaufrM8FPsxN3/XLMQQXI9RjKsh+rzFxykN889br4PSVCwcKTkR/M1Hw9ykKmirGPQL0u+g8cOlBqM3l16HMeWzRFc6Fa8IcOpAJ6g3TmNErGMNQ0Fjja9i3M9bXl+7BIIWiqoOAgboW7+U/NZLRPs5aq9pW/rHp+FPB4ILsR5MMtWuwdAOpfnB5ZbfvLG1BqQZQjnfHcHsJHrPOkwH5NRNZ8YzZo7SqEblM0H/im6mt0kyNDcTNBmEn0GZlmlOOPIHcyVctO2b5ZlA1gwS+z3bC/6f0+EQIAmziSsFkL8z5+hm9sDaLm1b2H9cMbXBdomfLXL/bXryTiS6Hs3KFsvIYGU9Sr2kipNX7D5wTY1ontPwSzDmecYQ3LejX5E7V+1Go9HaIa+3qtCmv12EsDXeFLdKvG5TEJZFno+rWTx+A+RV426+K/kM+tbdEUyxnZtUtfh8QUj0h69G6LIjzePbHTzfZYs7Kfp1HM8h1seKW8ZqaLZvyZ2rZmP/eKcaueq7BzD3E9pGtkoZshYTS+wUTUWfdP5OeU4qdsR4TE21gp7e+clQh4Cn6Eqf/TKPVzPwSxFHS+PZx+/8xnlePC///gMIl5P+95JMMHca8eRDsQRv+1ifw7k/1tPQ3mg0F6BefR80Vu/L6uYIfDB/BMG2WGqtSNwHBdc6iHsh7ljWG7E4/kbUfRlCbWky+Kj3KLJuMqw2xjKkcmSK5YzV5ry3hMnGWGxspQgo+Kq5dRFIFZRTNgcinEhr/fZbttquhYTnksUDpeciDinBIPDrE3oruGrBvx2xNB+5rpOFEqyBuA/2G0XuVMgyk4oIGGImg5XNByFPRyxbE6P2VOQQ4UmkVHYT66dB0CwaTTIbAhiabHQP3VVsc3Xeuz7pdN+dXF/22te/n129u26f9K6braPrztvOde9du7V/sAy3lLCiQusCD79rwtDFyfttliACOUIafhJt0xhZ6D6lxQDixWxnm1IKVpzp1qpMVjp7dzzJ1Q/b7B5FHuBgEgNyM7/M63BEeXJDJIe2ljsXemlgFUiuSzK5ronwjS8wgpwFQfB0AmjI1kQGZ3v26eEBM1eAokQhb1SCulQ8eYhmj6JTUSsAfyy1aG4CHorcVcw24JnMfQBtFqyCrZJqtT+3NOFgNTc//U/tkVSERysYR/trIl7HW+gARr0szdAkvGh3/L67TyKurH1iQLonl47G89USCCiwxPZDkJNKY5c5S0ITV6Kb1mL9mjCyiDTCn2J/ednG2u8GYW8inW3MtKowWUmtxunhQefwtNXZ33972j3sHp0cvT063Xt7+va00Tk+6TyFbnJEmy+GcL137eYPRbnjk93j3e7xbnP36OjoqNs6OmodHHRa3ePmfqu51212m53OydtW+4kULB+B352Grf2Daip6oxK/tsfTqVgeWVP02+2/g6PD04ODg3Zjf+/ktHnYbhydtE5bzYPWSfvtXudtp9FtHeyfNLuHR4f7b08O996e7nYOm61O+7jVbZ82HkldLuVkbXe3blGPiUW+rvcfFrpoPg2R/U1dVn3aeWMTUs4OKShZheDOh7+bQjvkUoicdNp18vHT38+SQUZlnk1C5cO7YnRcJ93O38176mc/ong19P6H7q4Jt20T9DGieRHOIA0cpjId9IqRbtowJSnLwKZgz17vfMfXNfBnRJNIjuiX6liqaI/t95tH0UF/fz88bLYOW0fHu61WMzw+6NPW3mO5MRH5NR3kKzFkVGaKMsPRnO1cwdPv6Qt3KM5hiqj4QkHVD1TpFMyIAVVNxhubkFsa8yj4hfx/9t51OZFcWRT+30+hcP+wvTYU4GvbX8w34bF71jiW+3Iae2bHnliBRZUArS6kaqmwzfw6r3Fe7zzJiUxJVSoQGDBm3JcdE2u3qapUZiqVSqXyMs2R7b3mXqvehP+um81T/C9qNpv/s6rFI2Te6WLBuL+JGdbkW5oRrZPj5joZYYrhPHOQY4VTZ3A4gSp8YEAJ0n5/afV8ztK00kwfr/yxfhugB3e5fot5i0+hl8BMh21hmOU2PsMeTEkuI/IHyKW3nXBdBjTWymoyFdh9BjOUcVtWxs/lsYVlgnOEcfIQMszjKJarzovR2xuak4X2jqndotwhPLhksd1iODZKAreJCxmPhkzYNMc17xJ6lJn4hI7xY+gN8bQ4otphw3ZRxaGCHCHg6JRBvm3/uTXDm7J3eNT55/k78KbsvzmAM2H54tvzi3mvFgNtPekM+XDYPIko1NWFdLc7hiplU7y+gmJUnsR6eNhEmJ322fvdyFQPgfHArFRjmAtPoD3wBMoTDCS05kVvoC/28D1WrTaxVCZ1EyM0y5RjqOZ88b5NJrlAyI5N209iqhINKRoiqUajMx2e/X94auVJ02SsQMjLGM5Min/WebJBaMAcsnP+fhe0DSAFK8HndDEHQYY4ixMOLOQ3CEg703qkICPUdaA/P1sLn7CawsZ5hKOSnfNdLPugQyy4aa+BPq+SK0s2KQ6BbWfnYlVpOP/ppl0jH4pzyKWIcXPB7dhehsRyWPPPKgHJ8UCTtUoRVFZIeb5pMXLDOj14tRti3juoEgIa7HfO7tdArF8pbsME+0NrsvPhicrlUsRr5gdNOyPB87+RLTSFQoE5cOdmRfZMrKo1sAiLGnek6mBI6OYuZx2DbFFlRdz4heVwXSNtDDj9GFw759ARUirB6VO4sO7TOZ5FaW6LXU5cgMw6js85ee4195r15nG9dUSa+6etw9P9k//C4+dTiV7rMfxRqifP3XMpbp3Um2+Q4tbpQfN07/DpFJvE1c5nNu7QFCKv88FwCdqfIuhnbryy0DYTTNG8klL7mYUX/Kf22Zrojkfqjm2IZgh7wfG8AAtGWJrCC7F9VFJOijkJX9cWj4sC10FeCa7z7HCvtSaGsYdMCr9KyuM8m6gMUuHJWwuuEIOEKX43JQTF3eeCRB8dHu4few+4SNjDJKVPY4Tmf7E1MQEEA8A554YnAzqjMfg0SZfPyBvYax68eQpJmilO087SpYXXkIxnhnZFg3HrLT0Swd1/8uKmes/Ge5NetTQbUDHCqpueY616cQN3r1B/PZYpGGlwIi5ucSrg4wFVNMYKR6GJODz89ZdfTs6PL97+8mvz5E3z5KK1d35+9iRNpXlfULiu2LiCviwTJSGCy5+OAilfQ/0BYUdwlGbAP+1XLXCda6DlygiDmcg/Jbmiok/O1TiD+v28q6gaR6TNWBHa1ef5YNQFx0CjL1Mq+o2+bHRT2W30ZStqHTS0ihsxAmgAs/B/or58fbW/f1y/2j/cD64XOGYdHtWfuIVY587LcFvowm/h0AoRrgdUsSTqp7JL08JeFixfDx9eglsiRPZNex30vUS3xKSatLja8qdBGTB+ifb1T+U5oUaufmpTAYlyIuY6lp7fokYuRRyhl+JZpedFuyMqzFkHtf7pd8MUB/0RDq8Q8RVBWDfxL9D5EODFyqR+504EG5GzWcvS6yoESFgzLyja+0sTt8Gz4ozg5/L0WBRRgdaHNjC7Zi75aYYdSGZVx9EszvYOj9TSJ0Kmc9qFhHWWLMGFrpQpo2IWob+Yx6SX0gq5tjwdhNAL1pc5R2chdoXSpnYrdLymwh/Q9uXh8KaNwReECbQR4e+RECyNliVbsIe844LylyB8/dNfZAZ0Gf6E9LAkIh9tTUA8+EBigAfXhCVg4WRTdk+NyY6zscFzyqmgmO5KNVj2cPetG3mq60gZBNXBsqwb2DMfRA+DfJi+pmkm6g7POoc7xQlcoLmkEe7yMJZCUg02MQxKLGDbaEVLC6xiejRkyRJz9hRh5Xoi4QOF1eKANVk9sFAw3TQqBk5MSPnSImpiVPyQ7yVo3nAGgsV1lQyEaTJfSgbCLMw2NA3PnYFgyVs0A2GaG19HBoLF+5vOQLA0/q1x7OvOQPDn7dvNQHgJM/dcGQgTM/gNZyAsOIvfVAaCpXmjGQht6wxbLNdgKr/AA0ucdIZYudlcA4vIf+i+3hAbZyQbGETWnmywf3JwcNCi3aPD48MDtrfXPO62WKt7cHjc3T86aCUr8us5whl0ToeZfwbAo7cNFl8gumHRGPu1JRt4vFh7lMMyzJgMeliUEWtLNrCMsF66JbiwRnX0uAJyshvixfn76UDCZ1c6P2KqX3ZMtT9NP2KqZ8dUB/n0DcVUB+j7EVO99pjqAJe/3ZjqALH+JdqGCQ7eYW40pvoRfnznMdUYUx1g0Xd4Hepz4buJqZ4k+tuPqfYp9iJEv/mY6hl0/4ipnhVTPYNh319M9QxGfM0x1T5JP2KqX1hMdWVyfsRUv5yY6srEfMcx1WE+fDsx1SH6fsRUryumOsTdbzemOkStf/rdMMVBf4TDK0R8RRDWTfw3FFMdIvU7dyJ8czHVlqANUfLemK2VnsIWA/gN7rKL3vlS8T4XNLXRqkFyt1vR3vaKJG86pPg9zFoK3R9NuC0G+jgcELUKCxYhP0/1IsQ7wnVGRdlFIkxriM6ZNAbbYhZRCkW0B4zres7B/q5jaboz8VxDxHjMihaYZ+Zlxex1LBxdiMwgMZ3LAgiFr4SGnj7S6xdOiWJfRhAlBNXfBQbTWbi2aRvqBwoOLgqRE+TLiKlx0Raz4Ot+r3dC35y8aXWP4zg5pK8WZLCh5W/g8CQT8W9TLt9rtm6an9m+2SUDbXBql4G3kuSyz4Bx1f7eFrLtZerYPKAiSY3XpxgEKuWrug3CZolrl6enuXzQ7Z3s9fYPj4+7+wcJPaL7MTvZO0marMkOjvePJlnr8P2bGOyGX1iS/W9sQ3VolAiMK1r8Y2O8IaN6pOypHcW7EFcr2hZmVcDdxjTF2Gaz1zw6prTZpSfNve5xhZEjlVbbK9x8ulqgvcLNpyvXOMF26SO2lh5sR3Bwy1Jmd2aqcnCB3Hy60uby3b5ZKivgXVcxbK5OEuhHz0UuiY4HDBpMm4KKNZLRfGAhSCLF8v0SNttd+gJHcyIzUmmpmraqdSD9zveXgmg5ZJh9oQlFTg/p2LQIsTk0UEpOJA0we4DjpnV1Oq4Vfh46SSrQH5FLW+YSYENhTeYFUJB7jGDsSxgDHt3aOpZmnn0MDUGAmI3DADxTnjNFU3L58e6ogMlEnErrCL798xawJrf/viU7l2+vfyWffi1Di/eO9/d2DU7+i6U/yvm1sH9Bl7nen+hq8dEtIBq03WpYpAqnE5Min2ZTkgJec0S3ZCiQBF1BSmQCppBd/gVclDGM/U9cuGzKKP47kbk3hdfT0Dk0P0nHRLMcnIw8tykaNZBXIXPC7pgaY3+aAW6u1e8ngLthM6a4TMhwpHME0gVHL+DHkuqOUuZLmZe7jGxlou+VoITPtyL4zRvrvcxt5gLG+xVcg4mEUElvt3OYQhtce7TPqYr6f+3WkPICJgAB4SCQOVN6UwuB29nq/7VVQ3K2DISt3bCcZaI/JVw9RfvD5S4XniRbH6XKufTVEMFrT2T47etbTynlMvN5C0Jy+/oWnMxAq2+kOyKi7Wn6Rmn6fLS9yLZ/lz2kGlS1aVDMh1BV1jYpHssRdm0ute7Ykyqdy8mgSy7I7UilEcC8xRxSMMCM5kbKYUbAES1McCFLcJs1FrJTh2jcVcCaJtDBg4NzR1f14unBwX5DM6riwc9ffrK/m79f5zJ7Ncl5p5a+s9nfvhFDmYD9nZSaGZeZJpoxUZkR24o7qMG4IILlxgSUgucSsjLNlii7aNwlhTXQhU5fTuhQRhQr7EIUIYpJuSSVfcxUMPs1KPlezgT5D+jY4lhlkxXQwKooAF/iiq7bxWcFWKqhmA+kVzpEaxUTUMg8rCBXEjyQ9hmPX03KQ0a1npCwNctkRRY+2uGcnrQbdBTAKx9sDKd8MIGPp/MtI7cCKEqVL4HixOWvSYM5te6KmbhJlc/E7eAgfLt1cLA/hSye3JfA9ikM3YaNEQe0i8OIQJcZq808sTnVIdoKuFSTrQkBntp/f8b919h0zhc0OUoEJjmtGuRCktufb3Hle8E+uJXbbxH3yFrzCkOUKHyDkdburZo3GH5grcICIpw4wCnDhlle4oOomzdv7de2p3sRA8Exm05AijUjXZbfM1YeV2DQ/B5aAWjPVVBOt8mch3iizmbPdNfeqb1EAq1id0oFHmQZSwp316hrHnlTO2X5erDMy3iM3upJWdxDxXK4BZO05f9QEZciLMDyGnrmqyEXLIF8qphrltrENjiugd+Bf66EoehRr8cfCoj4DtYmOG00zCvmjUiq/m5ErtXYdlOgWabkAx/CXKKd0h0TzYdZOiY5nuqnjWuY3pR2WarJPU9TNKdx77tnaYrUX19d6FJRxTIafd4KbyMeR6ZExTgRNiUfbRxtpkrbAmZqf9bgAGPCpm5Pg6a2wT9MN0KbptgJ3KaIvvalnGTlgcOYKGPyZQS3LbwUbljF7iBYWi00TR3V8IIm7CFmWY4/QKcPpI+MRMLUxKKxWiAi4OKgzuXknekmMUDPr61pAuDtc0iXkqL0x+Wu8zGOHFMhZLknVlZYzeNAoYSnCAKn6v0E7hajsHYg+SzeGhcS1Xk0HFsIZomAEG0xqvOtaNJlY6FUzsVIq7b3goUOc/KqR909KKDUqqih8lBeRc/sEPZwY7ngwdgyDirYgnJFeVo6CGYsa+r5Nh4xyp3w5zLrIEl/w8bAej3odQqhfTKzQmQ5s8Oury6gnwN4rz4LcHFS7c1JAdco2JrzEMOhq6IOLDwgNuBAmRy3AOt3/Y3lEMBvfd37B+4ds7aOciYW30Tw2ZQ8wQXHBkNhbuxwEztIVPXZa6YmnPblL/O89iilQJHz3TvLlXBhDHbwFNEulF/N3avmbAoeh5Td0cKpYL3E6GcopMh2XQb5GVDofyoYlD9UY1C3pStO5Iozbc1WHARVklRgPcANn4BLF6dl3AUDFYRiYRWDkd09vF1jGG0vfSUQD6joMx1tVlu896bYeO+lGpcsR1N8yCCmgMheeGeAixBydXH2EVh7ZoT9ogDlq4ntZXWo4wkmSW6IJSD41azMaFW0YcN+5vC1jTiipvizrUtjpAZO+qJLWRRSaGdpl6mcvOVC54yLVdmJCuXFrA/E5qUsEESmvH9+brZM3/cXVQcBEde8Xo91zoaNLKU5KPWV15GhboObnz/rZvBVUZ8o2fKcSLsgAbeNDcC7EUtlmv5XNlaYLbvfgYNWSDEeQgBQAdpMV/HnjWZQVJH3yC18FPHkFmTZ/AEE37qjBvz/nglToGl1MxdJ4NwCXpjVxT4k8HE1p+w5hN1KBlK/KuphaV4H8pvfEtoD8J4CbJCKVPa5mMWjYk+guCesyj0lU6an2fe8NfSAFoIjQ3olUJfzUk9Y+zNA6vafW595lwraocmQC+h7qBg6J0S/A0BXqEj3XViJjtjiEPXDOPaM45IrX5l5XCL+w0Bei4FcMvSHiTxlIk8y51szkifpe97lVKHw6WZyifwPQ/k5DOWSv1+hqVwi/8NYXsVYLvn33ZvLL8VCcvh8K4bPoxPwfLaRo+J7MnmqNH81lkwV7ecV+0UNFIfTD7tjKbvDse0lmRMOp6/cSlhWl67BkHDYf/f2QU5Vn+U/3Euee8my5CvzLVmsfziW1uJYstz84VWa8ipVOPPVGGKrEPe8q6hC3hxzbTnMfxh1Sxl1yzH3JZl+y2H+lRuI67b+lmPed2kjOh7AJx3aLzMZvXBH4v++UNCjgeRCH2G2IUQcEtUgoVUOCSVdJe+9ihaFVrgesLHNnNMDeU9G0F2D3LOuq2cBoqABFAS0Fkk6toDLqEDXJcgsH6eYMBjm79oW7OihOecfB1JMi+/fhGjJ6qCAtmmPKj6F7DeSuVrhyo3wpK9Tkb4QZ97Jv3ia0sZh1CQ7Zk7/P3L+8cbOL/nQJq29TsukDL2jMfzw37vkLMtS9gfr/ovnjaPmYdSKWn6fPkJ2/vXb9burmvnunyz+LHddAatGay9qkneyy1PWaB2+bR28sRPUOGoeRK3padJRjw55On6+eaqw8EObmPHIjosgVywZ0LxGEtblVNRITzHW1QkkNohE3uvdIHPN20F6vr9s+Q+mfJPoW4PZHaqEX7bCVQHDWjKJqeoWllsjiu/kf+gdC3H4M1OCpZuSmEnazOhFLzosnKHo/byVeBAdRM16q7VX7zMB5dFCVD2vev2a5MaVvPGkZp6g/PerSVKkjtyR7fk4Op8SN77VMzETudQ1MuqORD56TLdQdc9FiCoQ5w1RtH2jIXWDkVs77q3NBYNYWJozKKH8l3lDThIPpZ4KuFIUW3lXSZqASTVkKuY0NboY8kbKs9qH4nUNnRfTVN4DZNt3u6yyAScvslPU2ds9JSkXo4caGdIYOS34Q5nUZvkdvZrMn/vQJmM52t5WYBFRzF8D8XNpm7bQA6TNmrzpSj4cvNEtJyST2QgihKFdeMqohlI9UCIes8OghJrMmIARKJQL0yNmcufenrdrcNbNlMykZlBprABJkwR7rUfbIUFBUl8tuPY8MbKLakOSNLU27PALqc5WM2qFDIfNkuDV51zAJAUjqHLouUupqB53fr86e7/oQQfedUccqsp8e3ukH5M3zb2o9YXktL+jsRwspMvGn1nu5J9qk0cHRT1EH9zBGETOzD8RPtVaxtxW2AUQwhX+UHAuwtB34ECxsGnRysAOBncnZWf2YqW9N/VPIuBBiAqoeqMSQgm09E0ttTntYzovMFuOsLQR7ZdN9+FnqCMAtH6pc1H/QpiIaaZh+QEFNesiCmFGKjVN8nHGYy+v2GamYXkzWhRv0UxoqcgOi/oR+R/GPtfIH1wxqE3+eRcrkvA7yKQsjsboQFS0h50eJjjBhWBq5qwaEMS8ZIkrJ1iTHZdzZ6HaZ1X6d2cQOZ88Q5+FuyyVc8gz2tLChbpchf7motBwIAsiICu5dF0vmWNHTvt9NLUsyA9WUCNfuC31KvKl3O4iAflzr1uQhWz7LkCsR+ZedLU2neMw4TpWUBxneoVZmDjjHrxZ89Ljit3TNNU1olD4Na6FFPbOLk2hN5/SK/gbNuZcR0IvL0AGjTSX/TEc98J6c+m2QBt0SXzIbOVtpA4GXpo+OcqhSdQiRDoC70Yp9Bfqcq/OvNtCAo/m7SWwlVTALZAxTIMIkKkEYlvEsuJGXFwwrTk53tBMovcQwMD2YU0S2C1UPOA5i6FviSEwn+IYxUBA7zL1esA0c+W7nM1fL7THjpekXyMX6DOAtdy+ab/dhX/geZCm+OKrCYGpkQtXxVgq8qvVCruVGgFkx9XbgnIXY90fUZVE5t9Q16Hx5Z51ByzNGj3ZAVGlaQOs0ZQlfdalmjUqBHacZc90NMiHf/4vBFQgVmVG+e6/d4MVxlzVR5flHTZit//ccrStEG8Qp7AtuTIfG5IgEKDqwM4yrHJIx1KVdm9l4jzQpJxEKACC7dWgnEIjvtO6ES51/3t76f4gHgXPx6aNuBmm5sL7ITwRuJztHqsLk4OmcN03NW4IwoxFF9+xaMhzxXC+sMpCo0e/4OJJX8d3rIOlEjoegroTKwaHxD/PsRVOZWhfp3MwVESCvcY06KTz39/6YvjvoGRcCjgAf2gT0xOQ7EWtvejIFg0DlT2h0t0J99PH8yiKlpUpJqDc/6aXntPd3p0mWnOgbLHUyOypCy+70DTOWHdvl2XRxqwu4IzjiFVKO5cXu67sjG1XVinlVOGTB5dgPY9xRC79ohxkVL1KtoNYwC5+Ypr3VcDLLaH7Ac07XHdgKfFk166Ziu3DK/mrZHrNXF78+9UUAjiXddOfstlstpadVqzdzTbXHeWMKGYKjc5WZpWzhdVsELmTkCHPeR8fVHnlJs1NKUsm5i/EuPDMxX1e73LRiO8YCH4U9/nP8I+fCj4ftVorsBkEtrPRRWRP4FIRDVVvguIeZAxQ2Wq23kSrCBSMJZiK7phIpNoguX5tosrkO5SIQSlI8jUTtJuy5YmVikXdaqvCxwjtpZLmsyjZbkP0iYbCCERBaru5/29GTTibtJpR01Yqg3+SLnO3UkMoQqehEHrZ2IGQX8Do1haiBB8Y2LBaM62hRjZ6l9hDlkqeO2YNWa54rMkOzXMafyZ3GIVaeqBN8dwHno9rJFP8jqesz2z3BBvJBNX2sZnEbo3wYUbjvITqxyUBjAIudOPoQ5dJA8pGMSJOu7azAjRjmWHABAxOd3hBdVFPZDwCkneDtvthdLja1DNxx5UUAJmmL1MG3vooPiYMVIxJUTIapcfOXI2sMnNYXZYrBoPrFzZ1OYNS6C911q4tdo9NGPRdJEPoB4oTAKxOuFcqspwmWFVuDuP1rqMluL7Z+w50kbx3veZ8a2tcOiV23v9+sVsaIuB04DmFehQFWFSs8JGiAmr+YVHKrSt5D3Fk71jCR8Mto6S2fuP9wRYq1vPf221ytwdqulDDBUSUELjGKAwf19zEGwt809qDtR81bW3GMfraE9aDEroFUHsGKl+uzJ0nXfgG10TeQwVFwHtIBe0b/9+vl5/a19EH1Tf9J8kO/gBKmNy0610KRxQhRT1Tsue1D/Qb+0HZd7gCHHKtXVMhScB/A7emGTiDiWYxCi1Y5iCTOViGmRSe6OSMDjWhsZIaqSb3UqXJDNEVd0kE3bajvrxDb1Ddqi7UKWHl4S64lhFjOz0bkuBrXyKC1g3oG+QsKhdLM8oiKGZVjR0jsGdDJ2M7UVColiqMbfFUx2ocDjH4HIaKaboIlx2HoXNg1UUMv5DzstfqAl7ibfTfQZ1Os/kg42zTXlBEzmkMiwvBe61cdaXju+9N5hqjxdIxBFX2bR8scn3VJmBSwbGkRhLe5zlNyz7NZeNlC5E9sHiUg71JulxQ8DzWSLvx7vLd24rvmgub+9KVCb4D3l0BnkxYuj1sJ+OwlHhz87lY43+4njN+i1q8/YRa8NI2yanBdV0ZD4AxtbcAFltp3kYIxkKEUHamnYV98fZTnQnYfZLKEKCWrAXgKr/ewpe32MoOW/hUrtG6rAw3KO538f7OIgIfR3pA9w6PbncL8t7e2UmleRny7qHhsxHP4u5OzrtA1bUqKo4VQLrjh1/R2V4RwGxbxyC5zVMd2ZsS+OzWNr+yEPFxnHK4TcDHK9x20RQXMWxHmL+kN6R4ijamtq2xh0fRyPTs/W5kYlxhPE3uqBrDDuLNhAeelJ3kgU2V+YLvsbq/WaIY+2zuI8s2YLACLt63ySQXCNkBcPc8TWKqEm2PC5W0MaajkHra/ofX22N7ESXlTw3GvqiX0YS86EEOSIE697lb8H1ek2HS/u1sj4D5Qs60HkHLZUbaKNvk/Gxl3ryExuQ6RPZNe0WaXmIz8uVn3fQiL3qNg6lXIx9ufoITgH0JJBu7j8+REA80ebK0vOhG5O/kyGik3zm7X5FA30beMJHB3uOrK4tLEa+BBy+wBfnyLJlYMSuy5TtsVy5k3sGGf0uQmlRtigop1xyCWXJyP+DxYMr4xUhY7AXFwG0iIIQFWtne0ZQnwYnda+41683jeuuINPdPW4en+yf/1WyeNpcP75F5x9xjbopS9OEsSiXcHb1BKlunB83TvcPVqDRtFDqf2bhDUwioyAebCls/c+MV4XAmICWv9ID4zMIL+FP77Am0xiN1xzZEJ3gDcDxDpw3WYmkKjIjto5JaUsxD5URrR9Xl48JRFOQPeFWyw73WE5jEHjIpyqTIFbq2Vfjw1oIrpjthit9NTTYSvQShR4eH+8feAy4S9jBJ3fLEa/4XWxPhIAAAzh3DvbnWGXRS54J0eR4+7ew1D94sS4ZmitO0Yy7HlyBiDaneZmh34Y/bZCHy4d0Z3Vao9HTOROzdUcB/vGejKiB41khFNqAY+cHjGrSJLLMyjPcgtx4e8B/EMgXDCU6aoywzyRoV8GU/6CDzDw9//eWXk/Pji7e//No8edM8uWjtnZ+fLa15CrfSxpXspbtcV+iS9qegQKpcaRH5A5wmcDhmwDPtt8pxfb9L1xj5pyRXVPTJuRpnuSQp7yqqxhFpM1bc1Pd5Phh14VjS6MuUin6jLxvdVHYbfdmKWgcNreJGjAAa4G/B/4n68vXV/v5x/Wr/MNy1EY41h0f1FbYB6xx5GS4AXfgAHFohYiEKniVRP5VdmhZ2q2D56rS/hCN+iNSb9qo0vcQj/qTas7hCiNOsk4k547evfypt9Bq5+qlNBfkVTu9cx9LzAZj7Hjzxr11KXvTRvsKQVSn0T5UbpjJ4tnd4hQiuTPg6CH6BB/kA/UuR9x0eyO3l/GatPS8nEbZra3oFxXb/MYLqsJxOSZdhmAYV8UAq82fdbNGvikItv5h3Kqj8/wj/3PXItHsrfG6vZ8qrLby5T1PbOh24gDa3f4vjuIoFLiLoEuptJPDFKaEpL1qjQ99t97L3YgBB+O8COp3DaT8hdTgFeR9CPob5i1fzKyF6pF/2xHP4AX0RNC75S4rH0ENyJ18e8j4kjcNk5mrEqtANR+ybBqzEhWl/Mn90QnI0g/RifjDcDENa+iOFk2IGC9G3AOthhvz35pKFTFt1TudCBubC0YfpCIr0eU72R3mEDjDzLXHfEp64ZRGncpSUK+Ac/nRxLwpC9Cjc0YYXxTv71IQjxpVPMROhdEfQJOngCx0HEgaBlv9STa6RCuX4UcSHtO9Vpy8WPx3yOu3GSWtv/yBEfCkglwCBXF4UYcEIuOCIFY/X5AxmCl+SaeILqkMI8I/w48jR+shUB1+eO93eGA7BMkx4/jAFQTxZdaQFpHdirEXF2BttSOMBF6zjVX2YP5j9wC8TsehYVkFjiFdnAYU2/6tFR82URC224MTZ10shX3QcaM0sxUJjVF4NwndqIZHxZ6ZKvXDh/g4sL/MMbRPYH9OUxbAFoVIwz2CFayhF1zGaubQ13HZsxqsXOmHGtlmgtVy8hI0bwJ50xcMQszyGhT8JMm3GUKBxlh8NvvL3hSVHnfhysUFXHw7bGmtCXpPrDxcfTslv8h7MiyHNQMlq9rMHNrDRP7LZz9HnpU43KEROcmH/LeX2N/NXAMil6ElfWu22AJ8Tp2s8AYXfg+Jp94235y4CCKMwXXdxHbFYR+NhGtn3TFItvALbIoRMll9W2yZbQuZL+uypqdQMdSC6UqaMigXZ2ys5Ap5Rb9qnx5U66o54Oj3k9IwWu/dW681Fq3mytRg6kPcHI/jhcGFEwPcTXAfzcNG5Ynk8WBwZN4ppbSvGhQR+HnWhjFbOdCmH//J/C8Atnxc2V9WAKoGWhtOjWrX86FHNWr76qMxNcjyTSbQgu+dw1ONAJhPEanpyYagRT9Y20keZkJvLi/BAPJsah2crDXH5cXoE+F+8nlkbMSXE6cFkMrWpPHEwV+ZuxmATx6CnD+gAhipTwIj/93//H01s9bwplOwe8Y8n70be486QZhlUijV0bf1ja2ma7O45pNk0F7EJLm76Lw9vD7cw8pqBESjVy0O9wCyMuGJZyuEar3I2L5GfRm+xYUu4MxZNwrJUjodMrHngEu6MgcFshwriayfZAzxj6NKeWOvABVh7V5LwHuYzQ9ETagLg8UFR91iNBDhYdudhuEZrflkqEIgzLuw+XloWH4sfAnDtw9KmKBwaIRughL2cAcAeFuWMHSEqsx3mHDssxTQb1plS0juNnn18R97anypU449liRr0M4Ev5+zju/k0y4oMhuSvMo7lq1SVSq+QTHuveM78c9P0QOgvXX6kSgGiuUOBMOucDrOlZ3KkH4s8wTvWU1KN+5kpz9cOE3dZiUSAn3zIIYOLxVJAETW8yrgR/IGwTMYDR+WjlLKHDErVdYoMzKkwuRnIAWIE4wy9eDmXx+hqHiUsZTnUmKMx1FGDCFCbjwccrueynkKAkfM221Mkz7V3AwC03oII24wQxHh8u9A8jlQ6cwZn0HVmc7FzRm5UapNLdTxgcE4BEwrTIGAxOgySsaBDHpeHB18+7J8FRgaS+zUsvrORA75D5J2SuYxl6mTCVq+vEczw2TKBHVvRq/ki4GFVrUq9Ek4AokYyqTXvpmPjna9Trdmwm7KE3Hy6CmFbBKHYYyTEfJweHOw3NKMqHvz85SebSWL+fp3LbAmyJlvprERYYTaHme0hvgzHwfx+NVdRzMcKvg9P/8HB/jJ40HzwRAbBWgijsmWncRnGfBkxNX4iRgjD1o2dgdmEYC2DYU/R/rDcgFZBkpwVUGzs5BhQpRhZ4bIUKfabyMBD57DOZfYopg5NWFmzld8yOmsqu3ylSQF8HKiJSSEpo+XmwK32X2JKLJzyg2mKA/R5AIYsH0iPlNlEzp9Xj1QDcllK5xDroTtgNGFKB/CdOhtCtSYwE6fCExaiJKZCCiiB64Z09Ayl4LmEGPjfrq8/OvKWJgQLJSmmApSEOD83/mGCnE8WtHVMc70CpiWaEzn0iwqYAwBHuZHuTMTUBfX+/FkB8TLACABz02FJM1hGrxYgrcStxwVG5L0ijzu75+J240XPvwfk0Aio1OjFIrm2oIJDl6S8x0g8jlMsBsngBEIGUKI4jkdKsWRJegKLY9bamL00HpuDxRfGonPi0DcurVczEfe9ORlVdFg5hXhPp5fQxOPJ6Z14rGOasqQzWZ4FfgYPUo+CFwhWIf7f5MaChIXJncvhM9JLaU6sn8o1p6rbohzO4Wesctzga05MIULHhrWbSs5RlbG2c9OrWQs4uCfOwLJtu0D5107l+ndvuZEn3Bez9NuMseC/y+HQ1jbwTVHXi4oNeQ4BrjO2lKC4zd3gV0Fxogr+mnD7f+xdbW/ctpb+Pr+CCHAxCTCR46ItFt1PhpO03l7fGnWKdrFY2ByJM6NEI6miZGf66xeHPJRIipQ0L07cu7y3H9rxDPmcF76ew+cQjQvqOHzKZFqDeykpN+du5wLQ84ABLxgSY0QU+Aefc2wZhaIRqyZzegaA1qWcJCm8AWjoerq0swnC+kUdFFSv9VVWxbqi2y14usIIWXrmAuGXb8jhDwZouf2xGBVCvFF9biZAWM9N6wfAUqBWFd0y4DB5bqpugT03Ze8NTEGqK5pzGmup/BMXYaV5W8Zj49iwkdNAkau30cwvi907Fxc/fQj2tmoYQtc9Eu9AZssc256TxzTLFMsuoVlm5Ep3WRyedqAEETYlW4IPNvSBEV5SKOVSKY7rfQTXEggPVPzP0krgUW2oAamAILcWyyvLNWzOVQnpl2wdkTke4OYLMl/S+BM4T558LJbzBWF1/GoPOaxR65PjdKzlAxr5EV5WpzG4inj1h+ONGv6pFBMXkuK6LYujlnupoR/ffSBnsGvmZz+kyfxVNKATe4DG7HkMTTE2r96qOUcgg5NkF2sQW0myZHBrCuRbA1IScx4qadXtJp+XnBLa6BbeEoiuD5anJ9DEQTEgkr6ICWS9dcK7fPkXrwNwWMvWgVBYCQGbimYmzfQBeMDS71RrkEivuKOBp5tUTQ4beRXlHMWo8LVR7v3sj3HgS+vXw49DWKVnaLas+hAbd3DDgf3b2d2G2VWJEV+T3OXFSm8mwlRP7BJ5LlXFdxGZXlKu3Vu1fXAs75zv1M4wGnP7Y42Lx7EWAkmTySackDk4ZMMu5WE2CNOVIanngYkBwscU9ZRZdyP6esancWden/rjRJHKIpks0ZcVqU0nnSiRDspMNj0dJpV4OgHSpHxw5wjzwGmrfUGLaJPRGUavIDUb18aAJmAhMepR4Qon0HRbo0eR5wl5FywhZpFcr+V0VVkWPxCoHT4/IcjTblWmAyNQ+QQIgnG2Jxx2PMYCKLNt2+epcNCiArVaEbtICWzU9xL7iKzqqxtLWlqjkLwTfS8wRoVZXJC6kq63on6a9g2LXHgELjj6L+2rEKVt907DLjMcTZ5L1X/rYqlibcYf/S42IoeSRbV6iMt5bDE183Cf6e1KU3DJKtj5tSFrZm9UyeOG4VscsSV7pNoTtplb0wo0rdb8qPfwAzrHtD9Cq7Wg1jd21IRcywS1VQoEvLLWZ1kVUOBP1DtNgRlY97RoNmIIXa7SsZu0grwDyP8luZNaBV+9tW/18S/7YToNqB6YOVeH1kNQiRnjOEPfmkqRu+JREAqAKsm538BRDt2TB0qBiyreE2SycU2S9kKyNrcWEBxeXbfE+aJLLUNPBeqndpmTrgHZxbe9Sqd20z2Y00/9B6HsUKn7hciL4W5LPxZV/2p3V7NjYVxDy6ofFbAuVkaB2Nmhd64HIWqr5okr4iUM7RomRC3r9h5VpeDApuWoIePcw7ml8iL/gLsn3fWwgkCaW/sqWzEWDCtTdE8gb8Wvj4SQJod2r1HIHQdBkLsfiuId/PggHajuJaf/kBP1fc3jVpPsOZBN5pXyUmDEiMRkxWr7896G3dtVx9GvtCoVBP9Fu41ib8+u6Dm7+uHFEvZwilGAqhy4OSd/vH5fVI8UGoJ/I/iG+4/XvzKavb66wewm+HxFs4wTCLnA3EDJOn1geZu6L3eLkGpXsW0BJSsk9MkqMhKiHbsTr5putExorAox3KfqUV46Pm93uxUYv5K7YUXJ4G4+d5MKGulT9ZgwYD/UiezwuPy294fBEjza19Ed8QvivU1N04xrx2KtV0BNSUnjT6w+QzNiD4Q4WpV3/I0waVdNBkrCFPnHJpchc5mIJ/SAv5tcjUUpBk03GzsGHjeq5rfFVh20daVg74zrtb8I3S7TdVM0PNuJMETbJlFnddgq8WLLILoA9YvEwyBydbMgFIcreDEF+rzPhAPrSh0R8t9Fo/hwafZIterphHDIpxZGq+ijwqWC4fcRfnAvtdg5nbhayE2i3aQRj2XBXrCPi9LyHtDcRxLZ/YIkrGS5yLkvcgyqdgQ98P+0JimP5sO+7Zxy0H5GYbbhaWgulzpt6tEspEt6SXMQr8hFrEgE5kXZhJuHb0G+q5uH7zuDRvODJgM/fGOCkFckP2ASqlsufa5wSuTEpVDBzulOD+261yg5ifwGrG3Gl90TmNozy4ZxRIpS6mKcx4V61kvJsioeoVWcvsU42JFiVbfFqwR7xiNpSvBSeKyiLiiyYg23QCJTr30kXNIKIoZNixR1F3nnC1s95ixiGbGo0nWq12VzzxcevUAEDwGqoxkartNWZzd89PUDeXFd/JVmGT37LnpDXqY3myJn/0kub34j8t+B5uT8m7tzUeCMXNMYPvjjFbkoy4z9zpY/p/XZ92++i86j8+/Iy59/+nD9z4X87o8s/lS8UrlXZ+ffRG/IdbFMM3Z2/t2782//g9zSFa3Ss+/ffBudvxh2KX8aiyeFZd/0ldtiVT/CgiHdCvJV8jV4xZJtaLaCQUDFWWRBIEVFPm1SDnYGSomL7bI3Przi7HF8tFvo2VDqcTYqpR4s6Jw4siEti3pm3RpYCWE+RN6ef98wqNBv9Szui+OMcg704QnQelCyLOqF2LRnMJuihgWtKd/l9YZBQQN8ogCDsy6KTNDv6C9sq2YbLYv6rq7oapXG93CYwJcSPWlxsBxoA6/EVu6Gru6e/WBo2LASpqX6u6YWP6Z2V2a04EWqX5bHRR6zSl6Wi0UMmugQWxOXx4t9OvQj1nQhZx3tLxODXwrqzARW8GNU6I0DeUGdJuDT03MnkIrAaH/y63tI44OaJZroElwX+nnJm3hDYpbXBV+QZtnkdQOvzPOkeORGqp5m1oRWj2nuEshynUOFaXt6odaoF0cIC6AWbY0HMBZOE5FLBPPx+elEuC4+0gd2vBwy+1YNapSEqCrR8B23WHSbZruTCpawZUrzfSS6RRjodiLAmGxoDQcAaGtBVhVjS57oLugQBqU+qTTnb6JzY+uyv4GUMcTSB6cmtaN0iIDMVqeU4Nvo2+jN6/Pzb16LwkhpfIwsEt+ISEoY9rlkVWoUyHc8FWwPDJoMbskMqBdYO5tmRj+q1mxeq9QFsQ2xn9kpPmRtIbjUPrLuNvrLikdroo09EtdM4wP/SNOejVzdeo5XdoPO+3r/nYXfxwbuLSwdSMlRAmKkPPnc1LM6fDmUVsdunAqljyfYD9gNdwCsC6rWKwFOYxN0O8ob/ppRXp+/prNp4ihS57+xsykRzpDH9Zl7XQ+uhWDYXvijU5qr3pVfWQMWgtab628iKEjfbCerB2m1T6meL+zNKMEzd2KFci/ftTjx/TjdKAcwuhHKogbuiXIdl7NpqA0W9afFLLsamdonwsbbzVOOBAOXXxM+XQxqw7uV6rZT6r5WHXSkc0PATavVpb7F29Q/6yDQ0UOqU64kaIBiLq3T6CdbW83m1hZpCd0a9oj0FvaEMS0hHVpk1qqqLKSCki6waYUDHNCLiPCNpBbBO0qqkXagrNFswJzKmD1rplMshvx/E+1kPg8TuhnVpA4pbrKySuvpuL7x4XqvQn6QU4AhI7iBBFxllW5ptSMlq0pWV7QuMBWlo6PoIRNWBSKRT2w3Ad6Ajn6ifAPdSWZa8DjIWk0Zt7JRhPbEiyXVNymbqiw440P4rDF6AEBxcwxJE5wXcQqvl2B4dGQ3Ao5uViLJOTk+b9Syb0Wqufg+edn9O6rxleBdFO975ZiEH0ouFENsIVIEr7o45Jpy8bN4UxRcxtZotUzrilZptnMohn2OWalFq90DdUQlV+47PYzhZHC+rIrHvO/xvcGnQ4vNV0FD5urh+6DGlyRWghw4wVbUjjuyoWXJcqCzFGxscByGB2jaryI3rC3jnK7dyKwYi2ckOtGqiBcCwF58GIqkydwQJipHttA911Kjy4Th6d7aj4517pgknZigDTcONBL84b/oA+28VoZMkHbWiXVD88R82D/0uH+qxa4kB1ZRkUctnCNNB/NoTJv1phY5BTKlHiP/whuB2qioPXh5TeNP4oX0XcVWFgZH6Hp0cMI/NwVP9Ufnmv42mOwI8xetspRVmnbVzCYftwJBVgdvITJL4bTGaAJmq1jJKLIDMe2LmqBKyKxYHzPfaHdaRjhBVYiAhEWFY785R+zZnEqfOK44e2AVXEKgH8tUK4+tASer7DVp7z57KbNVe4TEJ9LO3p9oIqOtcbKduVgPz2mC/uxuCNQEdfRd/wJeNNAtq0UJmBYDeQez/vxSJNkAkUZc5Dmc2OqC/IPPdYfp9m64GdmpViCWymtIHoK0irSC1QQIhbvcHfZnQzO13BsSLsiyqcXOGsJJMdsUGbBAwhYX/tPICVKrrJhPCE/rhipuEKtVQNTW94cNglQ9UCqsxTzVlmWBELFGR/FaaCjWarS8gBR7jYmEXMtvvHBzq8O3dXoLbE8qxPGHjpa82+LJYuQquKH9KKqKou6XzcJjnRrRbn5xbXS53MhDLx6IzAOR+VMRmQdSyCcihcSphdV7DxBrCXYrd/xYiKMBNkaQ0ijh6F7f3lZpk5spA95ZeCVwXmJ4cKnXdO5H7afXQSC2DMSWgdgyEFsGYstAbBmILQOxZSC2DMSWpyO21GVJ8NXg5OnQpRAzi2zgcDxkJofO/tXSAND1umJr+XRLc/3ICYc3WycYWySfWN4D/6hs4/L1ZLxwStbaZWHfBWiXfq28LFvdTdo+DHgkvMrSZn0Ny5y3aJDWJodIeAWXfPEGKic/0jYKPhtWbfCY4z3GtJv636gfsc96wja4CwSm0yLhaFU6s5rENAa3oac45rJi9FNSPOazMcV+DV/p6/ES5jGsjdOVcG+l4G01d03Dfam3tPrE72zUTsR+tANIR318IXF2n0hI7TWK7ivyL/BGrX3LLrMX0kocNkmTQ27TvdZaJH5zH/Ulh8vl4/ZHLWlHi1bIAg0bIsKzyxSig/CWruNcjWkWN5mMnem/F2FA8FDzhqhsYR25M524RdrvbvjgXdMPZJ4so7Lg9bpi/M8sEpXfYAdVsy0kI7GIVbCBmsc03jDcSUUDftkTmDfLJ5H5gqyaCsIrhDfL10n6kOpHEeiSvBQR7E66BTFq1g3tCPtiPMHKCRzyp1sy/ZOgZwq0ZSbEI4RzGgHwPHL0b6+Qbi25JBhZGz1ijIkyYpPemgiCHbsY7rsUTszd22ei0fOaZrYS+4PPr8Evnjj2JdZDDNGKWLCZFgbgPrGdXBlVEB2DCoatdLKAPW3VE3nigXNItEAaH0jjA2l8II0PpPGBND6QxgfS+EAaH0jjA2l8II0PpPGBND6QxgfS+EAa/3ckjfd38m9NJ58mh4Jow0LVkRACl3vgcg9c7oHLPXC5By73wOUeuNwDl3vgcg9c7oHLPXC5By73wOUeuNwDl3vgcg9c7oHLPXC5By73wOUeuNwDl3vgcg9c7oHLPXC5By73wOUeuNxPw+Xe4zfUNawUObMUWTQ13F3PbFUa1/g+LQ4p0a/DexFMirDne8LTbZntSMLyAtLzDcbeB4NWHk4rvIkhiQ3CFsZruhVNs6bCS3j4bckqoAUReYJ4WwPpHjVswal4gJY0MUu6fjRNt56Ovc2mmaDRuFH7JvBo5Cqv6Se4nwSSJ3Hfi36z6ILHaU7u8dM78cV79dCMdXmTtolbWOxB2+e7sTl/r7ehPZM2/u6M8eDFuUCIXIZie6vNt2NOYr3B07qXxoIDeczSB3zhpOU7wntMdekgdIVvMPG4ZQumPdf+ohJBvycWRX8R+kVlER2fWJjWv7+GQG3nJxaqrIpVmrGvIRJ2fQKBlDCQa8nvtPQdjyQq4iu+r33e5Cl+Opss8oC4nagYeCXLItmJ9v3iLsiSrSB9IGFxsS0hAO5OO1XCJuwzS+4g6WPbsd1+bdHfIZxEy1YVgAkCtsWeI4kmvFNGwmmuQg149/RCPg0ZoZXGL/Ga1g1HCThGYiGaC+zSW7Ytqp1c4BYdrVhHhdIu2a7QxGRK6VYA9wrnUd+9/Nm9CppwkhWCSF98rCTyLLGq67hsZqYfmGurp29C7uOy6XUNegOFtr2a8uod10VNswgqykeldtQkZJBAWHllyaq42zN2ftn/3HDNNW0MWk+vdPimRraGuxsQDlJ/4JlVdyGqngZIRmr4BMg3+jlzmDUn2IUhVRMkp5lgjseWugEAPcEulguK87QmFc3XxigkcrP4BhLlzt+8+Udk21V67oGmlT/uWRdHwz4G7tn1ZLPMPqYEJDiWox5OGteNA6ipq8H2L0QL2AEErhNhNwizdN31FdVBgG9qH3t05deWR18jGhuUqZUKsCnRILkDDBWRqxp8WLELgQ9TELuQzIW/3Ebkl5z8M82bzyQVFRB4yus21Utr0+q0zBpoNt6g7y8bqE/ERXO/3P4BjaXyKCUeuungQOXQeZoDJdKD+lz89HcZ6Frg70XtBKtnSJrDqRt/CI13pE7KVjjeZ0Pe4tXrPf5aG1r4SRvmWIjR3y5H2ipkTej+kafP6W6Ag6afOrMPebR3dh+Z34dmeP8cf5yjn36mP/Vc35/tbX33BtMEq1+L33TVMmBehyugmMOxU4mrS1ZWbJV+/oG8+B9B7P+/Lyb5Ak//en6zGxhdOCl5SCt9+tYtvaE8cghUcR71QT4XqX5lXJC/kFtWk9v0LwbUcIzQLWzLweMcgkKdwxISyoC3CUjT8Dsvf7241hJUlAJi268meRuIJb08S7dQ80Lt7GVzC21bvdyJF3Uy2AzpymIpiSa5mzn7+bBZ6C5vfusGgsRDtj3EOgAXBE+4Y+Aaso/k6m2nl6YSV6yI5/Lmt8jZWbzizt76MttSF3ApXrNsR97TtCK38YZB8baKvLx8f/vKUoTZuVv+DhSevfcjODPRvb/FVvp0YuoH6n9yZZDf6f1xZER1mP9sipoeC1k08rSIFV6YsXmEPG2ziZANwNolC7K9ccZytUr1PG70pG9jg/KMdZ2x5EQo2/aeHG9+IFSaK5vj7qGDPAJVekQODewnhBIhLhsax/VBsx+5iEXDab7++06EuOs8zHIf4Mfd1s/fN4ztvDPzyc3oOBhNsqK9nzuB9bZsG4nF2rHfGdCrf8/j3fWMznlOYaVAPlvxzMjFtgUTt3rPTjAlmUCnZgu3YHj9CWn83e083oHC9umm/dDoQX5sDHSRXwdavLi5jkLdu1D3LtS9C3XvoO4dBl28w8OZ++JBqch03Jx26lueNBm3cULNuFAzLtSMCzXjQs24UDPu37NmnP6+f+Ly29O5R+M+yQbkCvTrgX490K8H+vVAvx7o1wP9eqBfD/TrgX490K8H+vVAvx7o1wP9+v83+vUemOln+8MU1PatbhECyfoeJOuBSj1QqQcq9UClHqjUA5V6oFIPVOqBSj1QqQcq9UClHqjUA5V6oFIPVOqBSj1QqQcq9UClHqjUA5V6oFIPVOqBSj1QqQcq9UClHqjUA5V6oFIPVOqBSv1vQKXeEQG4dezU8KSZxqfD/d6542MKjdwDEZtn94ssI1xMuqYygM9ARo829EGGe0SGrvrT1dto1hMjwYin1sMJXuy/VXFUvARCCAv7zf5xginsZisfCkLX64qtad19hS/IrguQrdKK13J4qQPHoJKmkAbafqL/fjpfjkPNI6qGfy5a/rKWogXlSRZeRpaZjfKRZtlRYjZfTEyA6pDTdK2ZjQ996ygZzcPek4ip8U6hU6pBYU4LMF96WbxplhXxXbH8yP6PvWvpbRxHwnf/CmL24M7ArSwwfVgEWCx6ZnpmG5t5oDt9TmSJtrmRJbVIZZN/vyg+JEoi9XDkKD0oIJc4Dvl9xaJIkVVfReIbYqwEI7iCL8VTa0VJE+nKRQi5W17S7jStKZQn6d4815e1nGDFeFNJyW4mkWdpyemtNuCz6C884qCCqEV9kicd6Ofl+xca7JG8RZYP8vXsFfsNwWKnFbq7mhGMu1sZkeXgvNG9yvc20SeQ3UIfpW1A8gaiCkHT2Alwp0ODJsA8bbx+0R3Jfn0MPBhZQltb7/Ng1PF78qbAdDoNasJSN8zZ/N7GCL2ZgHAvzFUbo/zrt+bu4QKOPtmjw2EHMW41Hwyv04bzuusz/LKFxNKRU7GIxzBvKsl9tj5u9Fv9QVLlhKYFAxFZ+dJW3TesCLln2zANlZeqTm5VglbluW/158cwD2wYtn8bO9l/906O7jxqTxXf0YTrf0emKjUzhofm57yeB+kUdiqFpkUExBhXJiNbCt7EicjcTqjv0ObDZWyjG3Y8ArdlGifSI2geisNquNueLq+zqPFiXjOX+hKQ/afzHaE/E6AHbxpMNCeDqq9lzwP1SaPzz3mYopIiKimikiIqKaKSIiopopIiKimikiIqKaKS4utSUuyWAR65CBvLtzk2Dld85HqoweuaXRwYruX8XNq9q/uSLoT2tqofQt09r8tbrnXba3UhqSJ4KZxem7CvpCmg4mknzYSBqVqCD+T1JlQQ5pBzpk+MphBvxeacYPj/KBcEV6/e2wqa0AeIATJnWXoNW3OTj/iG7gOy1m+L6w1ZQ3YtOE8a/zfbrjeEiuhiAo/WrPXxcCce9SQfnZKA9KsKwIWvsL1O7ZRJR7Z/GsNEWa7TmjlL90m93CsL/frhhlxCng2/vGLx+iJYjZ+gEX0dU1POzVoPRyKD97D6vU29bbmPUnpZKkWdV0lTi/2M2yUbDOH+ZDodPiOnRA8jFKhFgVoUqEWBWhSoRYFaFKhFgVoUqEWBWhSoRYFaFKhFgVoUqEWBWhSoRYHaZQRqR4lceoH8rDXxngWBxad2jxq5qJGLGrmokYsauaiRixq5qJGLGrmokYsauaiRixq5qJGLGrmokYsauaiRixq5qJGLGrmokYsauaiRixq5qJGLGrmokYsauaiRixq5qJH7ujRyu2IotoWNIVctQ2algPpuq7YpG8f4Piv2GdFvwzuJNNA93xHOjnnyRGKaZhCG/j/r0E5+E+o6FBRqTMDWnpeRDEqDSyK71V3IEojcro/NaQGRwYI9VKc1EO4hQDU2lLF1cRnRuO7HsnTl6bq3Vf8QWPY0srWjDNq4GKluRrpNsDQvxa35u6UJW38lK4X9nZD/xpKEdb7mH5W28G+lL+OUoO13xejAktjris6pPmrJ801mPyudC/iGXxheEpsa8DfVS7vpTWsjzYC7tY/wIZ8mAnRyiu8VWcfbIM+42BeUf02CryUtniDdV9AjPHxoQAvI9l1HYXSgOu3XGu72eJNVhzEvt2ch/Z7sykI+EXi5fRuzB2afcEOX5A0N9gGp6W0ITUIuWMRpWESHi0k8Rnlcq5lxTJp5qeBr6u5aluk70EdCUzgai7unMZ5lbx4De9OkJcJufrSVPe2A2NAimA/kjZ36eM9SOQlMSIFcMqVNy+1b6K9eMuqsEMlGeYr0/g2JsjSlkbjokpBCxVb/3YeBa+579b98T3yfHQZsAT9/7HYQbdrWfrPGZs1r+TKTGPtkTmMlQdsTx6hwdxemAcMsYpn2QjaWHX9Ko27gcusKsrf3j2ksg0eaexjoXyai0EcalVIY+ymNDkWWylLScCAeNj5xYJOObtvNcWgJP/q2sblEDaD+INuuA9UyPZWk2czp9x85TW9oQo9UFE+KksJEMk0UPnmMaK41+2kYHeRNhtRMrRxxo7fMoJgcClGwbSkodzCOt4Ne5lyPB90vYaktS9P3hBowHPz8HIpwG3IpknsfuHuEcJDbcLez83cGfX+0Cje0Tkzr5lkXG1hchIIemyFrBpcVWXMmW+utiJNzu4feXjoWscPudC9GdaX1b06APTu1MU4xYoDg5wZWom4YUwVYLUbrWG68GjuW9UVAPqtwtzo8dGsV0Ai5nIQBoAz83Fr7hRm5WbHzRhO6h+JBiPzq8lJzDKKsy3hD1kW43TJx/Lq2VuQ2o4I2wo+XYWVAkC2FBVXdqMukVi/hq3/8vZfz5deSlvQWSNr0De0j5XyMetxp81T2vXJZ0zVLB2fU+bzODr3QJlHgYRUVWc4iK3nSfIFxkpfbhHFQcJaRzxFlD41KDDb6cP9sU5imjnzlNoLjgT+C//u93oFXzED9tX7Nb8j8WjvBptqvpWrVFv21/uTW/kXpX5T+/cakf8si8Y6gh9d7CKrMQbGYfCmSjY7Vj0BnaKPS/GH7CpLewarnsWv8Q/9aIVItmU/d7usHp9+FIbU6i7IqcUwHG2+IXH++g/WHX30XrPpdwBtNdBImKNq9gWMYzraJDi94G3JOj9uExuTLp2sXWqKwXl7qQ9cgyo5X7979cKkOb/719Z+NRfNvIssn0GplGJ1m7Fp0wmlsC/gUizei550Pin5UuRUl30T07t0PU3A0tOlPMhDMBY9x9DBOMYw8nnkmItmGPkjzIGs51hSEuyLcN9WTJ4Mk76tW9MGWPJwJSWKE/uU1EMR757DWG9QiyweRGpgws/wPvynPLB1d9Qy+4LGApwrUag4KSWhYLw4OVeuhIdHt1P/QZezgZzVwpOLQ0IvykuwfV4uqanIq0x6yFlyVYNrcYHpOhXrOhUYwicI0SyEh1nRp+OhweBqTf9/c/GnoTSZS0B0tClo4mLgs33tx26LzSTetX+YYPwFpDZPnWcrpZAczDcBRTMlvo6bMmvO53z8q4F6qMVWORw+HpqZQBqsR1GpsO5bK16MVGT4A7cX2hdeHUL8DOLkJgEnN9OEovKBVtwoGLknYjpLoKUoogYPEosgKcgg5ySJZVy2eyMcxOXxzwz81hsZg/MQYOyZYrgLLVWC5CixXgeUqsFwFlqvAchV/vXIVnHIL0sgF2Fi9zW9UpE7v+0If51a0jgLeI5cfdPBxeCOyExo8u/0hFKadluhZN/plBMbGaDTvCF5qRLB4CBYPweIhWDwEi4dg8RAsHoLFQ7B4CBYPweIhWDwEi4dg8RAsHoLFQ7B4CBYPweIhWDwEi4dg8RAsHnKO4iGjZPe92AG5PgA+pWqFZ8O4XA0TFp8KwkoXwgIiWEAEC4hgAREsIIIFRLCACBYQwQIiWEAEC4hgAREsIIIFRLCACBYQwQIiWEAEC4hgAREsIIIFRLCACBYQwQIiWEAEC4hgAREsIIIFRLCACBYQwQIiWEAEC4iYAiJWjqXXIZ0T3jfduyZxtOhqwCvs6RuEASvJn5tMloVsGIxxm/UYwfqC8jIRqzac7qOqBw3Ecql2zLBZIAKlX2RrTcHFP3z4VqV9xfbXXRgXEK63EC2oX38Mi/sRvBtqS46RMz5uLYoDVnnfFEuyRJSkq8sYEwmuqx7sJhF8H3w/kchJslGvhq88XAkgvOAm+4UVXPzYDEB9Lkc/lxspYJIKlkhP3kHnMvzVTE6pBKnzEqSG9abNakPsrAV9XPTpy2+tOzM35zg7fkwFLWA+PyxCuVIP3tIIIjtZDWd2qj9pkd9FecoHSZIQXm6Nqjyv5PhIkoUQKjIvdelWkEJMU7Erkz9DloolbCBxyLsmc6eaA5S5nToJiz19FYQ1koqyrKdyHupp+MD2cndxwyAYJNhRER0+O8sanYP571X/sHbCA/muRnAnH7SNZxo4eof7eHYquuo6y+7LfFGSHSDn5fohjV8D0w9pPD9PXZ1r0eG0MZyN4YKDWCOYn52OXlt0/GwM52CohF0XpmiBOB/HBZ3UgjA/vzg7XqtN1lL0agRnYffSG3o3QwvFWVjq3R0MJY3lwcCik9KP6IXYLzhdfXjOxPwF3+F8ZBWE+fnBpnh5X26iOCPLBX3WxvAMhipoxyn5OeWcOaq030cZY5xBOkaBo9+fymOpC8heh08QM/j5wHaCwDEpi1ZOdDsWvxA6efhGPkLxc/IzTcKnXlxiK14Ilzq+/zHJonvwHvmW34cMLghEyN1lR9uO0enxWv93dV9wqSJ9VI9tT3H5lY2lGY014irDaQEhLVCn0We7iiRfefvmjeCH0aM0fqScWHl5tPFVZuwBegwfFwB6DB+7KFerNji4vLhtD+P0h0xcZHneuOnudYMOWuUC4RGAgH11e1rHtiq4Yt3GOB6Y1cWsfAZ5WHks7rL0ygO4uQDAHA7zvMge2REqktR+LDqivZKEUey1/mzdKW8ajce0gIP5+hpZ/TMpoCc7eLKK43TYZbZyn+b351f7NL+3W2sFUgzdRY4YHleaxGsq9ml+b7d15F47jHmuuo0wseZnmUbmnluHAEPJT6PufGP/3dG5LmCeqRpPevA0b6IbV85by7rICyeTHa6UJ+yUJt3IbQ3tLvBUEZVVf4KoTPKCCfMP1RPKldHlsZ/J2rprtHgnz9mrZgNH30kG29H9nhayEO+MGFot92LRUT+BVaVkFiSOdkfhmM0UdoPjes7ic3SfxWMwQHltuAjN0nl6r9sb7tfOWTawzfXgfGj6ehnGON+4mNaG++TlFp4D83WrGxzued5uB/u0dhrzGbrd6GgEKkJodgyq2dEoZhuCdqO9CMoiCUyG+yy92w3291znv88LoNtuB8f/BwB8WKVr"
 }
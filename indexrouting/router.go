@@ -0,0 +1,103 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package indexrouting
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/datastreams"
+	"github.com/elastic/apm-server/filewatch"
+	logs "github.com/elastic/apm-server/log"
+)
+
+var (
+	registry         = monitoring.Default.NewRegistry("apm-server.index_routing")
+	monitoringRoutes = monitoring.NewInt(registry, "routed")
+	monitoringReload = monitoring.NewInt(registry, "reloads")
+	monitoringErrors = monitoring.NewInt(registry, "reload_errors")
+)
+
+// Router is a beat.Processor which routes events to custom index aliases
+// or data stream namespaces according to the rules in a mapping file.
+// The mapping file is reloaded by Watch when it changes.
+type Router struct {
+	dataStreams bool
+	reloader    *filewatch.Reloader
+}
+
+// NewRouter returns a new Router with the rules loaded from the mapping file
+// at path. If dataStreams is true, events are routed by setting their data
+// stream namespace; otherwise events are routed by setting their index alias.
+func NewRouter(path string, dataStreams bool) (*Router, error) {
+	reloader, err := filewatch.NewReloader(
+		path, loadTable, monitoringReload, monitoringErrors,
+		logp.NewLogger(logs.IndexRouting),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Router{dataStreams: dataStreams, reloader: reloader}, nil
+}
+
+func loadTable(path string) (interface{}, error) {
+	return LoadTable(path)
+}
+
+// Watch checks the mapping file for changes every period, reloading it
+// until ctx is cancelled, as described by filewatch.Reloader.Watch.
+func (r *Router) Watch(ctx context.Context, period time.Duration) error {
+	return r.reloader.Watch(ctx, period)
+}
+
+func (r *Router) currentTable() *Table {
+	return r.reloader.Current().(*Table)
+}
+
+// Run routes event according to the first rule matching it, if any.
+func (r *Router) Run(event *beat.Event) (*beat.Event, error) {
+	rule, ok := r.currentTable().LookupEvent(event.Fields)
+	if !ok {
+		return event, nil
+	}
+	if r.dataStreams {
+		if rule.Namespace == "" {
+			return event, nil
+		}
+		event.Fields[datastreams.NamespaceField] = rule.Namespace
+	} else {
+		if rule.Alias == "" {
+			return event, nil
+		}
+		if event.Meta == nil {
+			event.Meta = common.MapStr{}
+		}
+		event.Meta["alias"] = rule.Alias
+	}
+	monitoringRoutes.Inc()
+	return event, nil
+}
+
+func (r *Router) String() string {
+	return "index_routing=[path=" + r.reloader.Path() + "]"
+}
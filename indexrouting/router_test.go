@@ -0,0 +1,156 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package indexrouting
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/filewatch/filewatchtest"
+)
+
+func TestRouterDataStreams(t *testing.T) {
+	path := writeRoutingFile(t, "", `
+rules:
+  - service: "noisy-*"
+    namespace: cold
+  - service: legacy
+    alias: apm-legacy
+`)
+	router, err := NewRouter(path, true)
+	require.NoError(t, err)
+
+	event := routeEvent(t, router, "noisy-service")
+	assert.Equal(t, "cold", event.Fields["data_stream.namespace"])
+	assert.Nil(t, event.Meta)
+
+	// Rules without a namespace do not apply when data streams are enabled.
+	event = routeEvent(t, router, "legacy")
+	assert.Equal(t, "default", event.Fields["data_stream.namespace"])
+
+	event = routeEvent(t, router, "quiet")
+	assert.Equal(t, "default", event.Fields["data_stream.namespace"])
+}
+
+func TestRouterIndices(t *testing.T) {
+	path := writeRoutingFile(t, "", `
+rules:
+  - service: "noisy-*"
+    namespace: cold
+  - service: legacy
+    alias: apm-legacy
+`)
+	router, err := NewRouter(path, false)
+	require.NoError(t, err)
+
+	event := routeEvent(t, router, "legacy")
+	assert.Equal(t, common.MapStr{"alias": "apm-legacy"}, event.Meta)
+	assert.Equal(t, "default", event.Fields["data_stream.namespace"])
+
+	event = routeEvent(t, router, "noisy-service")
+	assert.Nil(t, event.Meta)
+
+	event, err = router.Run(&beat.Event{Fields: common.MapStr{"processor": "transaction"}})
+	require.NoError(t, err)
+	assert.Nil(t, event.Meta)
+}
+
+func TestRouterEventAttributes(t *testing.T) {
+	path := writeRoutingFile(t, "", `
+rules:
+  - service: checkout
+    environment: production
+    labels:
+      tier: gold
+    namespace: gold
+`)
+	router, err := NewRouter(path, true)
+	require.NoError(t, err)
+
+	event, err := router.Run(&beat.Event{Fields: common.MapStr{
+		"service":               common.MapStr{"name": "checkout", "environment": "production"},
+		"labels":                common.MapStr{"tier": "gold"},
+		"data_stream.namespace": "default",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, "gold", event.Fields["data_stream.namespace"])
+
+	event = routeEvent(t, router, "checkout")
+	assert.Equal(t, "default", event.Fields["data_stream.namespace"])
+}
+
+func TestRouterWatch(t *testing.T) {
+	path := writeRoutingFile(t, "", `
+rules:
+  - service: noisy
+    namespace: cold
+`)
+	router, err := NewRouter(path, true)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go router.Watch(ctx, 10*time.Millisecond)
+
+	// An invalid mapping file is ignored, keeping the previous rules.
+	writeRoutingFile(t, path, `
+rules:
+  - service: noisy
+`)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "cold", routeEvent(t, router, "noisy").Fields["data_stream.namespace"])
+
+	writeRoutingFile(t, path, `
+rules:
+  - service: noisy
+    namespace: frozen
+`)
+	assert.Eventually(t, func() bool {
+		return routeEvent(t, router, "noisy").Fields["data_stream.namespace"] == "frozen"
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestNewRouterInvalidFile(t *testing.T) {
+	_, err := NewRouter(filepath.Join(t.TempDir(), "missing.yml"), true)
+	assert.Error(t, err)
+}
+
+func writeRoutingFile(t testing.TB, path, content string) string {
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "index_routing.yml")
+	}
+	filewatchtest.WriteFile(t, path, []byte(content))
+	return path
+}
+
+func routeEvent(t testing.TB, router *Router, serviceName string) *beat.Event {
+	event, err := router.Run(&beat.Event{Fields: common.MapStr{
+		"service":               common.MapStr{"name": serviceName},
+		"data_stream.namespace": "default",
+	}})
+	require.NoError(t, err)
+	return event
+}
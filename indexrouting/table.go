@@ -0,0 +1,242 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package indexrouting routes events of specific services, environments,
+// or labels to custom index aliases or data stream namespaces, according
+// to a mapping file.
+package indexrouting
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+// invalidNamespaceChars holds the characters which may not appear in
+// a data stream namespace.
+const invalidNamespaceChars = `\/*?"<>| ,#:-`
+
+// Rule maps events whose service name, service environment, and labels
+// match patterns to a custom index alias or data stream namespace.
+//
+// In patterns, '*' matches any sequence of characters and '?' matches
+// any single character.
+type Rule struct {
+	// Service holds a service name pattern. If empty, events of
+	// any service match.
+	Service string `config:"service"`
+
+	// Environment holds a service environment pattern. If empty, events
+	// of any environment match, including events with no environment.
+	Environment string `config:"environment"`
+
+	// Labels holds label value patterns by label name. Events match only
+	// if they have all of the labels, with values matching the patterns.
+	// Numeric and boolean label values are matched in their string form,
+	// such as "42", "1.5", or "true".
+	Labels map[string]string `config:"labels"`
+
+	// Alias holds the index alias to which events are written when data
+	// streams are disabled. If empty, the default index is used.
+	Alias string `config:"alias"`
+
+	// Namespace holds the data stream namespace to which events are
+	// written when data streams are enabled. If empty, the configured
+	// namespace is used.
+	Namespace string `config:"namespace"`
+}
+
+// Validate validates the rule, implementing ucfg.Validator.
+func (r *Rule) Validate() error {
+	if r.Service == "" && r.Environment == "" && len(r.Labels) == 0 {
+		return errors.New("rule must specify at least one of service, environment, or labels")
+	}
+	if r.Alias == "" && r.Namespace == "" {
+		return errors.Errorf("rule for %s must specify at least one of alias or namespace", r)
+	}
+	if r.Alias != strings.ToLower(r.Alias) {
+		return errors.Errorf("invalid alias %q: must be lowercase", r.Alias)
+	}
+	if r.Namespace != strings.ToLower(r.Namespace) {
+		return errors.Errorf("invalid namespace %q: must be lowercase", r.Namespace)
+	}
+	if strings.ContainsAny(r.Namespace, invalidNamespaceChars) {
+		return errors.Errorf("invalid namespace %q: must not contain any of %q", r.Namespace, invalidNamespaceChars)
+	}
+	return nil
+}
+
+// String returns a description of the events matched by the rule.
+func (r *Rule) String() string {
+	var parts []string
+	if r.Service != "" {
+		parts = append(parts, fmt.Sprintf("service %q", r.Service))
+	}
+	if r.Environment != "" {
+		parts = append(parts, fmt.Sprintf("environment %q", r.Environment))
+	}
+	labels := make([]string, 0, len(r.Labels))
+	for name := range r.Labels {
+		labels = append(labels, name)
+	}
+	sort.Strings(labels)
+	for _, name := range labels {
+		parts = append(parts, fmt.Sprintf("label %s %q", name, r.Labels[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Table holds an ordered list of routing rules. The first rule matching
+// an event is used to route the event.
+type Table struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	service     *regexp.Regexp
+	environment *regexp.Regexp
+	labels      map[string]*regexp.Regexp
+}
+
+// match reports whether the rule matches an event with the given fields.
+func (r *compiledRule) match(fields common.MapStr) bool {
+	if r.service != nil && !matchField(fields, "service.name", r.service) {
+		return false
+	}
+	if r.environment != nil && !matchField(fields, "service.environment", r.environment) {
+		return false
+	}
+	for name, pattern := range r.labels {
+		if !matchField(fields, "labels."+name, pattern) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchField reports whether fields holds a value for key whose string
+// form matches pattern.
+func matchField(fields common.MapStr, key string, pattern *regexp.Regexp) bool {
+	v, _ := fields.GetValue(key)
+	s, ok := formatField(v)
+	return ok && pattern.MatchString(s)
+}
+
+// formatField returns the string form of a string, numeric, or boolean
+// field value, and reports whether v is of one of these types.
+func formatField(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case common.Float:
+		return strconv.FormatFloat(float64(v), 'f', -1, 64), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	}
+	return "", false
+}
+
+// tableConfig holds the contents of a mapping file.
+type tableConfig struct {
+	Rules []Rule `config:"rules"`
+}
+
+// NewTable returns a new Table with the given rules.
+func NewTable(rules []Rule) (*Table, error) {
+	t := &Table{rules: make([]compiledRule, len(rules))}
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		compiled := compiledRule{Rule: rule}
+		if rule.Service != "" {
+			compiled.service = compilePattern(rule.Service)
+		}
+		if rule.Environment != "" {
+			compiled.environment = compilePattern(rule.Environment)
+		}
+		if len(rule.Labels) > 0 {
+			compiled.labels = make(map[string]*regexp.Regexp, len(rule.Labels))
+			for name, pattern := range rule.Labels {
+				compiled.labels[name] = compilePattern(pattern)
+			}
+		}
+		t.rules[i] = compiled
+	}
+	return t, nil
+}
+
+// LoadTable loads a Table from the YAML mapping file at path.
+func LoadTable(path string) (*Table, error) {
+	cfg, err := common.LoadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading index routing file")
+	}
+	var tableConfig tableConfig
+	if err := cfg.Unpack(&tableConfig); err != nil {
+		return nil, errors.Wrapf(err, "error unpacking index routing file %s", path)
+	}
+	return NewTable(tableConfig.Rules)
+}
+
+// LookupEvent returns the first rule matching an event with the given
+// fields, and reports whether any rule matched.
+func (t *Table) LookupEvent(fields common.MapStr) (Rule, bool) {
+	for _, rule := range t.rules {
+		if rule.match(fields) {
+			return rule.Rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Len returns the number of rules in the table.
+func (t *Table) Len() int {
+	return len(t.rules)
+}
+
+// compilePattern compiles a pattern into an anchored regular
+// expression, escaping all characters other than the '*' and '?' wildcards.
+func compilePattern(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteRune('^')
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteRune('.')
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteRune('$')
+	return regexp.MustCompile(sb.String())
+}
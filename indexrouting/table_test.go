@@ -0,0 +1,219 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package indexrouting
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestTableLookup(t *testing.T) {
+	table, err := NewTable([]Rule{
+		{Service: "noisy-*", Alias: "apm-noisy", Namespace: "noisy"},
+		{Service: "opbeans-?", Namespace: "opbeans"},
+		{Service: "*.internal", Alias: "apm-internal"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, table.Len())
+
+	for serviceName, expected := range map[string]string{
+		"noisy-service":    "noisy-*",
+		"noisy-":           "noisy-*",
+		"opbeans-1":        "opbeans-?",
+		"opbeans-10":       "",
+		"billing.internal": "*.internal",
+		"billing-internal": "",
+		"quiet":            "",
+	} {
+		rule, ok := table.LookupEvent(serviceFields(serviceName))
+		assert.Equal(t, expected != "", ok, serviceName)
+		assert.Equal(t, expected, rule.Service, serviceName)
+	}
+}
+
+func TestTableFirstMatchWins(t *testing.T) {
+	table, err := NewTable([]Rule{
+		{Service: "noisy-batch", Namespace: "batch"},
+		{Service: "noisy-*", Namespace: "noisy"},
+	})
+	require.NoError(t, err)
+	rule, ok := table.LookupEvent(serviceFields("noisy-batch"))
+	require.True(t, ok)
+	assert.Equal(t, "batch", rule.Namespace)
+}
+
+func TestTableLookupEvent(t *testing.T) {
+	table, err := NewTable([]Rule{
+		{Service: "checkout-*", Environment: "prod*", Labels: map[string]string{"tier": "gold"}, Namespace: "gold"},
+		{Environment: "staging", Namespace: "staging"},
+		{Labels: map[string]string{"tier": "bronze"}, Namespace: "bronze"},
+		{Labels: map[string]string{"shard": "4?"}, Namespace: "shard"},
+		{Labels: map[string]string{"canary": "true"}, Namespace: "canary"},
+	})
+	require.NoError(t, err)
+
+	for name, test := range map[string]struct {
+		fields    common.MapStr
+		namespace string
+	}{
+		"all patterns match": {
+			fields: common.MapStr{
+				"service": common.MapStr{"name": "checkout-api", "environment": "production"},
+				"labels":  common.MapStr{"tier": "gold"},
+			},
+			namespace: "gold",
+		},
+		"label mismatch": {
+			fields: common.MapStr{
+				"service": common.MapStr{"name": "checkout-api", "environment": "production"},
+				"labels":  common.MapStr{"tier": "silver"},
+			},
+		},
+		"missing label": {
+			fields: common.MapStr{
+				"service": common.MapStr{"name": "checkout-api", "environment": "production"},
+			},
+		},
+		"environment only": {
+			fields: common.MapStr{
+				"service": common.MapStr{"name": "checkout-api", "environment": "staging"},
+				"labels":  common.MapStr{"tier": "gold"},
+			},
+			namespace: "staging",
+		},
+		"label only": {
+			fields: common.MapStr{
+				"service": common.MapStr{"name": "billing"},
+				"labels":  common.MapStr{"tier": "bronze"},
+			},
+			namespace: "bronze",
+		},
+		"non-string label mismatch": {
+			fields: common.MapStr{
+				"service": common.MapStr{"name": "billing"},
+				"labels":  common.MapStr{"tier": 1},
+			},
+		},
+		"numeric label": {
+			fields: common.MapStr{
+				"service": common.MapStr{"name": "billing"},
+				"labels":  common.MapStr{"shard": common.Float(42)},
+			},
+			namespace: "shard",
+		},
+		"boolean label": {
+			fields: common.MapStr{
+				"service": common.MapStr{"name": "billing"},
+				"labels":  common.MapStr{"canary": true},
+			},
+			namespace: "canary",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rule, ok := table.LookupEvent(test.fields)
+			assert.Equal(t, test.namespace != "", ok)
+			assert.Equal(t, test.namespace, rule.Namespace)
+		})
+	}
+}
+
+func TestTableInvalidRules(t *testing.T) {
+	for name, test := range map[string]struct {
+		rule Rule
+		err  string
+	}{
+		"no target": {
+			rule: Rule{Service: "noisy"},
+			err:  `rule for service "noisy" must specify at least one of alias or namespace`,
+		},
+		"no patterns": {
+			rule: Rule{Namespace: "cold"},
+			err:  "rule must specify at least one of service, environment, or labels",
+		},
+		"no target for labels": {
+			rule: Rule{Environment: "staging", Labels: map[string]string{"tier": "gold", "region": "eu-*"}},
+			err:  `rule for environment "staging", label region "eu-*", label tier "gold" must specify at least one of alias or namespace`,
+		},
+		"uppercase alias": {
+			rule: Rule{Service: "noisy", Alias: "APM-Noisy"},
+			err:  `invalid alias "APM-Noisy": must be lowercase`,
+		},
+		"uppercase namespace": {
+			rule: Rule{Service: "noisy", Namespace: "Noisy"},
+			err:  `invalid namespace "Noisy": must be lowercase`,
+		},
+		"namespace with dash": {
+			rule: Rule{Service: "noisy", Namespace: "cold-tier"},
+			err:  `invalid namespace "cold-tier"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewTable([]Rule{test.rule})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestLoadTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index_routing.yml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+rules:
+  - service: "noisy-*"
+    namespace: cold
+  - service: legacy
+    alias: apm-legacy
+  - environment: staging
+    labels:
+      tier: bronze
+    namespace: bronze
+`), 0644))
+
+	table, err := LoadTable(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, table.Len())
+	rule, ok := table.LookupEvent(serviceFields("legacy"))
+	require.True(t, ok)
+	assert.Equal(t, Rule{Service: "legacy", Alias: "apm-legacy"}, rule)
+	rule, ok = table.LookupEvent(common.MapStr{
+		"service": common.MapStr{"name": "billing", "environment": "staging"},
+		"labels":  common.MapStr{"tier": "bronze"},
+	})
+	require.True(t, ok)
+	assert.Equal(t, Rule{Environment: "staging", Labels: map[string]string{"tier": "bronze"}, Namespace: "bronze"}, rule)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+rules:
+  - service: "noisy-*"
+`), 0644))
+	_, err = LoadTable(path)
+	assert.Error(t, err)
+
+	_, err = LoadTable(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.Error(t, err)
+}
+
+func serviceFields(serviceName string) common.MapStr {
+	return common.MapStr{"service": common.MapStr{"name": serviceName}}
+}
@@ -22,29 +22,48 @@ package pipeline
 import (
 	"encoding/json"
 	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
 
 	logs "github.com/elastic/apm-server/log"
 
+	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
 	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/paths"
 )
 
-func RegisterPipelines(conn *eslegclient.Connection, overwrite bool, path string) error {
+// RegisterPipelines registers the ingest pipelines defined in the file at path.
+//
+// Pipelines are stamped with a version number derived from serverVersion.
+// Existing pipelines are overwritten if overwrite is true, or if upgrade is
+// true and they were registered by an older version of APM Server.
+func RegisterPipelines(conn *eslegclient.Connection, overwrite, upgrade bool, path, serverVersion string) error {
 	logger := logp.NewLogger(logs.Pipelines)
+	version, err := Version(serverVersion)
+	if err != nil {
+		return err
+	}
 	pipelines, err := loadPipelinesFromJSON(path)
 	if err != nil {
 		return err
 	}
-	var exists bool
 	for _, p := range pipelines {
+		register := overwrite
 		if !overwrite {
-			exists, err = conn.PipelineExists(p.Id)
+			installedVersion, exists, err := installedPipelineVersion(conn, p.Id)
 			if err != nil {
 				return err
 			}
+			register = !exists
+			if exists && upgrade && installedVersion < version {
+				logger.Infof("Pipeline %s has version %d, upgrading to %d.", p.Id, installedVersion, version)
+				register = true
+			}
 		}
-		if overwrite || !exists {
+		if register {
+			p.Body["version"] = version
 			_, _, err := conn.CreatePipeline(p.Id, nil, p.Body)
 			if err != nil {
 				logger.Errorf("Pipeline registration failed for %s.", p.Id)
@@ -59,6 +78,35 @@ func RegisterPipelines(conn *eslegclient.Connection, overwrite bool, path string
 	return nil
 }
 
+// Version returns the pipeline version number for the given APM Server version,
+// e.g. 7013001 for 7.13.1.
+func Version(serverVersion string) (int, error) {
+	v, err := common.NewVersion(serverVersion)
+	if err != nil {
+		return 0, err
+	}
+	return v.Major*1000000 + v.Minor*1000 + v.Bugfix, nil
+}
+
+// installedPipelineVersion returns the version of the pipeline with the given
+// id, and whether it exists. Pipelines without a version have version 0.
+func installedPipelineVersion(conn *eslegclient.Connection, id string) (int, bool, error) {
+	status, body, err := conn.Request(http.MethodGet, "/_ingest/pipeline/"+id, "", nil, nil)
+	if status == http.StatusNotFound {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	var pipelines map[string]struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(body, &pipelines); err != nil {
+		return 0, false, errors.Wrapf(err, "error decoding pipeline %s", id)
+	}
+	p, ok := pipelines[id]
+	return p.Version, ok, nil
+}
+
 type pipeline struct {
 	Id   string                 `json:"id"`
 	Body map[string]interface{} `json:"body"`
@@ -18,7 +18,10 @@
 package pipeline
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -38,24 +41,102 @@ func TestRegisterPipelines(t *testing.T) {
 	require.NoError(t, err)
 
 	// pipeline loading goes wrong
-	err = RegisterPipelines(esClient, true, "non-existing")
+	err = RegisterPipelines(esClient, true, false, "non-existing", "7.13.0")
 	assert.Error(t, err)
 	assertContainsErrMsg(t, err.Error(), []string{"cannot find the file", "no such file or directory"})
 
 	// pipeline definition empty
 	emptyPath, err := loader.FindFile("..", "testdata", "ingest", "pipeline", "empty.json")
 	require.NoError(t, err)
-	err = RegisterPipelines(esClient, true, emptyPath)
+	err = RegisterPipelines(esClient, true, false, emptyPath, "7.13.0")
 	assert.NoError(t, err)
 
 	// invalid esClient
 	invalidClients, err := eslegclient.NewClients(getFakeESConfig(1234))
 	require.NoError(t, err)
-	err = RegisterPipelines(&invalidClients[0], true, path)
+	err = RegisterPipelines(&invalidClients[0], true, false, path, "7.13.0")
 	assert.Error(t, err)
 	assertContainsErrMsg(t, err.Error(), []string{"connect: cannot assign requested address", "connection refused"})
 }
 
+func TestRegisterPipelinesUpgrade(t *testing.T) {
+	path, err := loader.FindFile("..", "ingest", "pipeline", "definition.json")
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		installed map[string]int // pipeline id -> version
+		overwrite bool
+		upgrade   bool
+		expected  []string
+	}{
+		"missing": {
+			installed: map[string]int{"apm": 7013000},
+			expected:  []string{"apm_user_agent"},
+		},
+		"older version": {
+			installed: map[string]int{"apm": 7012000, "apm_user_agent": 0},
+			upgrade:   true,
+			expected:  []string{"apm", "apm_user_agent"},
+		},
+		"older version upgrade disabled": {
+			installed: map[string]int{"apm": 7012000, "apm_user_agent": 0},
+		},
+		"newer version": {
+			installed: map[string]int{"apm": 7014000, "apm_user_agent": 7013000},
+			upgrade:   true,
+		},
+		"overwrite": {
+			installed: map[string]int{"apm": 7014000, "apm_user_agent": 7013000},
+			overwrite: true,
+			expected:  []string{"apm", "apm_user_agent"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var registered []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id := strings.TrimPrefix(r.URL.Path, "/_ingest/pipeline/")
+				switch r.Method {
+				case http.MethodGet:
+					version, ok := tc.installed[id]
+					if !ok {
+						w.WriteHeader(http.StatusNotFound)
+						w.Write([]byte("{}"))
+						return
+					}
+					json.NewEncoder(w).Encode(map[string]interface{}{id: map[string]interface{}{"version": version}})
+				case http.MethodPut:
+					var body map[string]interface{}
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					assert.Equal(t, float64(7013000), body["version"])
+					registered = append(registered, id)
+					w.Write([]byte(`{"acknowledged":true}`))
+				}
+			}))
+			defer srv.Close()
+
+			esClients, err := eslegclient.NewClients(common.MustNewConfigFrom(map[string]interface{}{"hosts": []string{srv.URL}}))
+			require.NoError(t, err)
+			require.NoError(t, RegisterPipelines(&esClients[0], tc.overwrite, tc.upgrade, path, "7.13.0"))
+
+			// Pipelines which are not installed are always registered.
+			expected := append([]string{
+				"apm_user_geo", "apm_ingest_timestamp", "apm_remove_span_metadata",
+				"apm_error_grouping_name", "apm_opentelemetry_metrics",
+			}, tc.expected...)
+			assert.ElementsMatch(t, expected, registered)
+		})
+	}
+}
+
+func TestVersion(t *testing.T) {
+	version, err := Version("7.13.1")
+	require.NoError(t, err)
+	assert.Equal(t, 7013001, version)
+
+	_, err = Version("invalid")
+	assert.Error(t, err)
+}
+
 func getFakeESConfig(port int) *common.Config {
 	cfg := map[string]interface{}{
 		"hosts": []string{fmt.Sprintf("http://localhost:%v", port)},
@@ -0,0 +1,89 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pipeline
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
+)
+
+// SimulateResult holds the outcome of running a single document through
+// an ingest pipeline.
+type SimulateResult struct {
+	// Source holds the transformed document, if the pipeline succeeded.
+	Source common.MapStr
+
+	// Error holds the reason the pipeline failed for the document, if any.
+	Error string
+
+	// Dropped reports whether the pipeline dropped the document.
+	Dropped bool
+}
+
+// Simulate runs docs through the registered ingest pipeline with the given id,
+// using the Elasticsearch simulate pipeline API, and returns the results in
+// the same order as docs. Documents are not indexed.
+func Simulate(conn *eslegclient.Connection, id string, docs []common.MapStr) ([]SimulateResult, error) {
+	simulateDocs := make([]common.MapStr, len(docs))
+	for i, doc := range docs {
+		simulateDocs[i] = common.MapStr{"_source": doc}
+	}
+	body := common.MapStr{"docs": simulateDocs}
+	status, resp, err := conn.Request(http.MethodPost, "/_ingest/pipeline/"+url.PathEscape(id)+"/_simulate", "", nil, body)
+	if status == http.StatusNotFound {
+		return nil, errors.Errorf("pipeline %s not found, register it with `setup --pipelines`", id)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "error simulating pipeline %s", id)
+	}
+
+	var result struct {
+		Docs []struct {
+			Doc *struct {
+				Source common.MapStr `json:"_source"`
+			} `json:"doc"`
+			Error *struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"docs"`
+	}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return nil, errors.Wrapf(err, "error decoding simulate response for pipeline %s", id)
+	}
+	if len(result.Docs) != len(docs) {
+		return nil, errors.Errorf("expected %d simulated documents, got %d", len(docs), len(result.Docs))
+	}
+	results := make([]SimulateResult, len(docs))
+	for i, doc := range result.Docs {
+		switch {
+		case doc.Error != nil:
+			results[i].Error = doc.Error.Type + ": " + doc.Error.Reason
+		case doc.Doc != nil:
+			results[i].Source = doc.Doc.Source
+		default:
+			results[i].Dropped = true
+		}
+	}
+	return results, nil
+}
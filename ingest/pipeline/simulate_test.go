@@ -0,0 +1,77 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pipeline
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
+)
+
+func TestSimulate(t *testing.T) {
+	var requestBody map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_ingest/pipeline/apm/_simulate" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("{}"))
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&requestBody))
+		w.Write([]byte(`{"docs":[
+			{"doc":{"_index":"_index","_source":{"a":"b","observer":{"version_major":7}}}},
+			{"error":{"type":"illegal_argument_exception","reason":"field [x] not present"}},
+			null
+		]}`))
+	}))
+	defer srv.Close()
+
+	esClients, err := eslegclient.NewClients(common.MustNewConfigFrom(map[string]interface{}{"hosts": []string{srv.URL}}))
+	require.NoError(t, err)
+	conn := &esClients[0]
+
+	docs := []common.MapStr{{"a": "b"}, {"c": "d"}, {"e": "f"}}
+	results, err := Simulate(conn, "apm", docs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"docs": []interface{}{
+		map[string]interface{}{"_source": map[string]interface{}{"a": "b"}},
+		map[string]interface{}{"_source": map[string]interface{}{"c": "d"}},
+		map[string]interface{}{"_source": map[string]interface{}{"e": "f"}},
+	}}, requestBody)
+	assert.Equal(t, []SimulateResult{
+		{Source: common.MapStr{"a": "b", "observer": map[string]interface{}{"version_major": float64(7)}}},
+		{Error: "illegal_argument_exception: field [x] not present"},
+		{Dropped: true},
+	}, results)
+
+	_, err = Simulate(conn, "apm", docs[:1])
+	assert.EqualError(t, err, "expected 1 simulated documents, got 3")
+
+	_, err = Simulate(conn, "apm/../_simulate", docs)
+	assert.EqualError(t, err, "pipeline apm/../_simulate not found, register it with `setup --pipelines`")
+
+	_, err = Simulate(conn, "missing", docs)
+	assert.EqualError(t, err, "pipeline missing not found, register it with `setup --pipelines`")
+}
@@ -0,0 +1,191 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package internaldocs provides a beat.Processor for directing internal
+// documents, i.e. documents generated by APM Server itself rather than
+// received from agents, to custom indices, and for generating their IDs.
+package internaldocs
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/beat/events"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/common/fmtstr"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/datastreams"
+)
+
+var (
+	registry         = monitoring.Default.NewRegistry("apm-server.internal_documents")
+	monitoringRouted = monitoring.NewInt(registry, "routed")
+	monitoringErrors = monitoring.NewInt(registry, "errors")
+)
+
+// IDGenerator generates the document IDs of internal documents.
+type IDGenerator interface {
+	// GenerateID returns the document ID for event, or an empty
+	// string if Elasticsearch should generate the ID.
+	GenerateID(event *beat.Event) (string, error)
+}
+
+// IndexSelector selects the index aliases of internal documents.
+type IndexSelector interface {
+	// SelectIndex returns the index alias for event, or an empty
+	// string if the event should be indexed as usual.
+	SelectIndex(event *beat.Event) (string, error)
+}
+
+// ContentHashID is an IDGenerator which generates IDs from a hash of
+// the event's timestamp and fields, so that retried or duplicated
+// publications of the same document are indexed only once.
+type ContentHashID struct{}
+
+// GenerateID returns a URL-safe base64 encoding of the SHA-256 hash
+// of the event's timestamp and fields.
+func (ContentHashID) GenerateID(event *beat.Event) (string, error) {
+	encoded, err := json.Marshal(struct {
+		Timestamp interface{}   `json:"@timestamp"`
+		Fields    common.MapStr `json:"fields"`
+	}{event.Timestamp.UnixNano(), event.Fields})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// FormatIndex is an IndexSelector which formats index aliases from
+// an event format string, e.g. "apm-internal-%{+yyyy.MM.dd}".
+type FormatIndex struct {
+	format *fmtstr.EventFormatString
+}
+
+// NewFormatIndex returns a FormatIndex for the event format string format.
+func NewFormatIndex(format string) (*FormatIndex, error) {
+	compiled, err := fmtstr.CompileEvent(format)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid index %q", format)
+	}
+	return &FormatIndex{format: compiled}, nil
+}
+
+// SelectIndex returns the formatted index alias for event.
+func (f *FormatIndex) SelectIndex(event *beat.Event) (string, error) {
+	return f.format.Run(event)
+}
+
+// Processor is a beat.Processor which sets the index and document ID
+// of internal documents: onboarding documents, and metrics aggregated
+// by APM Server, such as transaction metrics and usage documents.
+// Internal documents sent to data streams are directed by setting
+// their namespace, and other internal documents by setting their
+// index alias.
+// Other events are not modified.
+type Processor struct {
+	// IDs, if non-nil, generates the document IDs of internal documents.
+	IDs IDGenerator
+
+	// Index, if non-nil, selects the index aliases of internal
+	// documents. Index is ignored for events sent to data streams.
+	Index IndexSelector
+
+	// Namespace, if non-empty, holds the data stream namespace
+	// of internal documents sent to data streams.
+	Namespace string
+}
+
+// Run sets the index and document ID of event if it is an internal document.
+func (p *Processor) Run(event *beat.Event) (*beat.Event, error) {
+	if !IsInternal(event) {
+		return event, nil
+	}
+	if err := p.route(event); err != nil {
+		monitoringErrors.Inc()
+		return nil, err
+	}
+	monitoringRouted.Inc()
+	return event, nil
+}
+
+func (p *Processor) route(event *beat.Event) error {
+	if p.IDs != nil {
+		id, err := p.IDs.GenerateID(event)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate internal document ID")
+		}
+		if id != "" {
+			setMeta(event, events.FieldMetaID, id)
+		}
+	}
+	if _, ok := event.Fields[datastreams.TypeField]; ok {
+		if p.Namespace != "" {
+			event.Fields[datastreams.NamespaceField] = p.Namespace
+		}
+	} else if p.Index != nil {
+		index, err := p.Index.SelectIndex(event)
+		if err != nil {
+			return errors.Wrap(err, "failed to select internal document index")
+		}
+		if index != "" {
+			setMeta(event, "alias", index)
+		}
+	}
+	return nil
+}
+
+func (p *Processor) String() string {
+	return "internal_documents"
+}
+
+// aggregatedMetricsetNames holds the names of metricsets aggregated by APM Server.
+var aggregatedMetricsetNames = map[string]bool{
+	"transaction":           true,
+	"service_destination":   true,
+	"error_grouping":        true,
+	"transaction_marks":     true,
+	"service_usage":         true,
+	"service_slo_violation": true,
+}
+
+// IsInternal reports whether event is an internal document: an onboarding
+// document, or a metricset aggregated by APM Server.
+func IsInternal(event *beat.Event) bool {
+	processorEvent, _ := event.Fields.GetValue("processor.event")
+	switch processorEvent {
+	case "onboarding":
+		return true
+	case "metric":
+		name, _ := event.Fields.GetValue("metricset.name")
+		s, _ := name.(string)
+		return aggregatedMetricsetNames[s]
+	}
+	return false
+}
+
+func setMeta(event *beat.Event, key string, value interface{}) {
+	if event.Meta == nil {
+		event.Meta = common.MapStr{}
+	}
+	event.Meta[key] = value
+}
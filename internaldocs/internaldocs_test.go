@@ -0,0 +1,110 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package internaldocs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/internaldocs"
+)
+
+var timestamp = time.Date(2021, 5, 12, 10, 0, 0, 0, time.UTC)
+
+func onboardingEvent() *beat.Event {
+	return &beat.Event{
+		Timestamp: timestamp,
+		Fields: common.MapStr{
+			"processor": common.MapStr{"name": "onboarding", "event": "onboarding"},
+			"observer":  common.MapStr{"listening": "localhost:8200"},
+		},
+	}
+}
+
+func metricEvent(name string) *beat.Event {
+	return &beat.Event{
+		Timestamp: timestamp,
+		Fields: common.MapStr{
+			"processor":      common.MapStr{"name": "metric", "event": "metric"},
+			"metricset.name": name,
+		},
+	}
+}
+
+func TestIsInternal(t *testing.T) {
+	assert.True(t, internaldocs.IsInternal(onboardingEvent()))
+	assert.True(t, internaldocs.IsInternal(metricEvent("transaction")))
+	assert.True(t, internaldocs.IsInternal(metricEvent("service_usage")))
+	assert.False(t, internaldocs.IsInternal(metricEvent("app")))
+	assert.False(t, internaldocs.IsInternal(metricEvent("span_breakdown")))
+	assert.False(t, internaldocs.IsInternal(&beat.Event{Fields: common.MapStr{
+		"processor": common.MapStr{"name": "transaction", "event": "transaction"},
+	}}))
+}
+
+func TestProcessorIndex(t *testing.T) {
+	index, err := internaldocs.NewFormatIndex("apm-internal-%{+yyyy.MM.dd}")
+	require.NoError(t, err)
+	processor := &internaldocs.Processor{Index: index, Namespace: "internal"}
+
+	event, err := processor.Run(onboardingEvent())
+	require.NoError(t, err)
+	assert.Equal(t, common.MapStr{"alias": "apm-internal-2021.05.12"}, event.Meta)
+
+	// Other events are not modified.
+	event, err = processor.Run(metricEvent("app"))
+	require.NoError(t, err)
+	assert.Nil(t, event.Meta)
+
+	// Internal documents sent to data streams are directed by namespace.
+	event = metricEvent("transaction")
+	event.Fields["data_stream.type"] = "metrics"
+	event.Fields["data_stream.namespace"] = "default"
+	event, err = processor.Run(event)
+	require.NoError(t, err)
+	assert.Nil(t, event.Meta)
+	assert.Equal(t, "internal", event.Fields["data_stream.namespace"])
+}
+
+func TestProcessorContentHashID(t *testing.T) {
+	processor := &internaldocs.Processor{IDs: internaldocs.ContentHashID{}}
+
+	event1, err := processor.Run(metricEvent("transaction"))
+	require.NoError(t, err)
+	event2, err := processor.Run(metricEvent("transaction"))
+	require.NoError(t, err)
+	event3, err := processor.Run(metricEvent("service_destination"))
+	require.NoError(t, err)
+
+	id := event1.Meta["_id"]
+	assert.NotEmpty(t, id)
+	assert.Equal(t, id, event2.Meta["_id"])
+	assert.NotEqual(t, id, event3.Meta["_id"])
+	assert.Len(t, event1.Meta, 1)
+}
+
+func TestNewFormatIndexInvalid(t *testing.T) {
+	_, err := internaldocs.NewFormatIndex("apm-%{[observer.version")
+	assert.Error(t, err)
+}
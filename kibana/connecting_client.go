@@ -20,7 +20,9 @@ package kibana
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -127,7 +129,7 @@ func (c *ConnectingClient) SupportsVersion(ctx context.Context, v *common.Versio
 	if !retry || upToDate {
 		return upToDate, nil
 	}
-	client, err := kibana.NewClientWithConfig(c.clientConfig())
+	client, err := newClient(c.cfg)
 	if err != nil {
 		log.Errorf("failed to obtain connection to Kibana: %s", err.Error())
 		return upToDate, err
@@ -148,7 +150,7 @@ func (c *ConnectingClient) connect() error {
 	if c.client != nil {
 		return nil
 	}
-	client, err := kibana.NewClientWithConfig(c.clientConfig())
+	client, err := newClient(c.cfg)
 	if err != nil {
 		return err
 	}
@@ -162,9 +164,66 @@ func (c *ConnectingClient) connect() error {
 	return nil
 }
 
-func (c *ConnectingClient) clientConfig() *kibana.ClientConfig {
-	if c != nil && c.cfg != nil {
-		return &c.cfg.ClientConfig
+// newClient returns a Kibana client for cfg, connecting to Kibana through
+// the configured proxy, if any.
+func newClient(cfg *config.KibanaConfig) (*kibana.Client, error) {
+	if cfg == nil {
+		return nil, errors.New("Kibana config missing")
 	}
+	if cfg.Proxy.URL == "" {
+		return kibana.NewClientWithConfig(&cfg.ClientConfig)
+	}
+	proxy, err := cfg.Proxy.ProxyFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	// The Kibana version is read when creating the client, so the
+	// client is created without reading the version, and the version
+	// is read once the proxy is set.
+	clientConfig := cfg.ClientConfig
+	clientConfig.IgnoreVersion = true
+	client, err := kibana.NewClientWithConfig(&clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	if transport, ok := client.HTTP.Transport.(*http.Transport); ok {
+		transport.Proxy = proxy
+	}
+	if !cfg.IgnoreVersion {
+		if err := readVersion(client); err != nil {
+			return nil, fmt.Errorf("fail to get the Kibana version: %v", err)
+		}
+	}
+	return client, nil
+}
+
+// readVersion reads the version of Kibana from its status API.
+func readVersion(client *kibana.Client) error {
+	var status struct {
+		Version struct {
+			Number   string `json:"number"`
+			Snapshot bool   `json:"build_snapshot"`
+		} `json:"version"`
+	}
+	code, body, err := client.Request(http.MethodGet, "/api/status", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	if code >= http.StatusBadRequest {
+		return fmt.Errorf("HTTP GET request to %s/api/status returned status %d", client.URL, code)
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return fmt.Errorf("fail to unmarshal the response from GET %s/api/status: %v", client.URL, err)
+	}
+	versionString := status.Version.Number
+	if status.Version.Snapshot {
+		versionString += "-SNAPSHOT"
+	}
+	version, err := common.NewVersion(versionString)
+	if err != nil {
+		return fmt.Errorf("fail to parse kibana version (%v): %v", versionString, err)
+	}
+	client.Version = *version
 	return nil
 }
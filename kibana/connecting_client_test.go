@@ -21,12 +21,14 @@ import (
 	"context"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/httpproxy"
 
 	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/kibana"
@@ -63,6 +65,31 @@ func TestNewConnectingClientWithAPIKey(t *testing.T) {
 	assert.Equal(t, "ApiKey Zm9vLWlkOmJhci1hcGlrZXk=", client.Headers.Get("Authorization"))
 }
 
+func TestNewConnectingClientWithProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		w.Write([]byte(`{"version":{"number":"7.14.0","build_snapshot":true}}`))
+	}))
+	defer proxy.Close()
+
+	cfg := &config.KibanaConfig{
+		Enabled:      true,
+		ClientConfig: kibana.ClientConfig{Host: "kibana.invalid:5601"},
+		Proxy:        httpproxy.Config{URL: proxy.URL},
+	}
+	conn := &ConnectingClient{cfg: cfg}
+	require.NoError(t, conn.connect())
+	require.NotNil(t, conn.client)
+	assert.Equal(t, *common.MustNewVersion("7.14.0-SNAPSHOT"), conn.client.Version)
+	assert.Equal(t, []string{"http://kibana.invalid:5601/api/status"}, proxied)
+
+	cfg.Proxy.NoProxy = []string{"kibana.invalid"}
+	conn = &ConnectingClient{cfg: cfg}
+	assert.Error(t, conn.connect())
+	assert.Len(t, proxied, 1)
+}
+
 func TestConnectingClient_Send(t *testing.T) {
 	t.Run("Send", func(t *testing.T) {
 		c := mockClient()
@@ -20,14 +20,23 @@ package logs
 // logging selectors
 const (
 	Beater             = "beater"
+	AlertWebhook       = "alert-webhook"
 	Config             = "config"
+	GeoIP              = "geoip"
 	Handler            = "handler"
 	Ilm                = "ilm"
 	IndexManagement    = "index-management"
+	IndexRouting       = "index-routing"
 	Jaeger             = "jaeger"
+	Journal            = "journal"
 	Kibana             = "kibana"
+	Lookup             = "lookup"
+	Lifecycle          = "lifecycle"
 	Onboarding         = "onboarding"
 	Otel               = "otel"
+	OtelExport         = "otel-export"
+	Panic              = "panic"
+	Forward            = "forward"
 	Pipelines          = "pipelines"
 	Request            = "request"
 	Response           = "response"
@@ -36,6 +45,12 @@ const (
 	Stacktrace         = "stacktrace"
 	TransactionMetrics = "txmetrics"
 	SpanMetrics        = "spanmetrics"
+	MarksMetrics       = "marksmetrics"
+	ErrorMetrics       = "errormetrics"
+	BreakdownMetrics   = "breakdownmetrics"
 	Transform          = "transform"
+	Usage              = "usage"
 	Sampling           = "sampling"
+	SLO                = "slo"
+	Synthetics         = "synthetics"
 )
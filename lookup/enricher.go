@@ -0,0 +1,106 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package lookup
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/filewatch"
+	logs "github.com/elastic/apm-server/log"
+)
+
+var (
+	registry           = monitoring.Default.NewRegistry("apm-server.lookup")
+	monitoringEnriched = monitoring.NewInt(registry, "enriched")
+	monitoringReload   = monitoring.NewInt(registry, "reloads")
+	monitoringErrors   = monitoring.NewInt(registry, "reload_errors")
+)
+
+// Enricher is a beat.Processor which adds fields from a lookup file to
+// events, looking up the value of an event field. The lookup file is
+// reloaded by Watch when it changes.
+type Enricher struct {
+	field       string
+	targetField string
+	reloader    *filewatch.Reloader
+}
+
+// NewEnricher returns a new Enricher with the table loaded from the lookup
+// file at path. Events whose field value matches a lookup key have the
+// key's fields added under targetField, without overwriting existing values.
+func NewEnricher(path, field, targetField string) (*Enricher, error) {
+	reloader, err := filewatch.NewReloader(
+		path, loadTable, monitoringReload, monitoringErrors,
+		logp.NewLogger(logs.Lookup),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Enricher{field: field, targetField: targetField, reloader: reloader}, nil
+}
+
+func loadTable(path string) (interface{}, error) {
+	return LoadTable(path)
+}
+
+// Watch checks the lookup file for changes every period, reloading it
+// until ctx is cancelled, as described by filewatch.Reloader.Watch.
+func (e *Enricher) Watch(ctx context.Context, period time.Duration) error {
+	return e.reloader.Watch(ctx, period)
+}
+
+func (e *Enricher) currentTable() *Table {
+	return e.reloader.Current().(*Table)
+}
+
+// Run adds the fields for the value of the lookup field to event, if any.
+func (e *Enricher) Run(event *beat.Event) (*beat.Event, error) {
+	value, _ := event.Fields.GetValue(e.field)
+	key, ok := value.(string)
+	if !ok {
+		return event, nil
+	}
+	fields, ok := e.currentTable().Lookup(key)
+	if !ok {
+		return event, nil
+	}
+	var enriched bool
+	for name, value := range fields {
+		field := e.targetField + "." + name
+		if ok, _ := event.Fields.HasKey(field); ok {
+			continue
+		}
+		if _, err := event.Fields.Put(field, value); err != nil {
+			return event, err
+		}
+		enriched = true
+	}
+	if enriched {
+		monitoringEnriched.Inc()
+	}
+	return event, nil
+}
+
+func (e *Enricher) String() string {
+	return "lookup=[path=" + e.reloader.Path() + ", field=" + e.field + "]"
+}
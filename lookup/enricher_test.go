@@ -0,0 +1,116 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package lookup
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/filewatch/filewatchtest"
+)
+
+func TestEnricher(t *testing.T) {
+	path := writeLookupFile(t, "", "service,team,tier\nopbeans,checkout,1\n")
+	enricher, err := NewEnricher(path, "service.name", "labels")
+	require.NoError(t, err)
+
+	event := enrichEvent(t, enricher, common.MapStr{
+		"service": common.MapStr{"name": "opbeans"},
+	})
+	assert.Equal(t, common.MapStr{"team": "checkout", "tier": "1"}, event.Fields["labels"])
+
+	// Existing values are not overwritten.
+	event = enrichEvent(t, enricher, common.MapStr{
+		"service": common.MapStr{"name": "opbeans"},
+		"labels":  common.MapStr{"team": "search"},
+	})
+	assert.Equal(t, common.MapStr{"team": "search", "tier": "1"}, event.Fields["labels"])
+
+	// Events without a matching key are left unchanged.
+	for _, fields := range []common.MapStr{
+		{"service": common.MapStr{"name": "other"}},
+		{"processor": common.MapStr{"event": "metric"}},
+	} {
+		event = enrichEvent(t, enricher, fields.Clone())
+		assert.Equal(t, fields, event.Fields)
+	}
+}
+
+func TestEnricherLabelField(t *testing.T) {
+	path := writeLookupFile(t, "", "team,owner\ncheckout,alice@example.com\n")
+	enricher, err := NewEnricher(path, "labels.team", "organization")
+	require.NoError(t, err)
+
+	event := enrichEvent(t, enricher, common.MapStr{
+		"labels": common.MapStr{"team": "checkout"},
+	})
+	assert.Equal(t, common.MapStr{"owner": "alice@example.com"}, event.Fields["organization"])
+}
+
+func TestEnricherWatch(t *testing.T) {
+	path := writeLookupFile(t, "", "service,team\nopbeans,checkout\n")
+	enricher, err := NewEnricher(path, "service.name", "labels")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go enricher.Watch(ctx, 10*time.Millisecond)
+
+	team := func() interface{} {
+		event := enrichEvent(t, enricher, common.MapStr{"service": common.MapStr{"name": "opbeans"}})
+		value, _ := event.Fields.GetValue("labels.team")
+		return value
+	}
+
+	// An invalid lookup file is ignored, keeping the previous table.
+	writeLookupFile(t, path, "service\nopbeans\n")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "checkout", team())
+
+	writeLookupFile(t, path, "service,team\nopbeans,payments\n")
+	assert.Eventually(t, func() bool {
+		return team() == "payments"
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestNewEnricherInvalidFile(t *testing.T) {
+	_, err := NewEnricher(filepath.Join(t.TempDir(), "missing.csv"), "service.name", "labels")
+	assert.Error(t, err)
+}
+
+func writeLookupFile(t testing.TB, path, content string) string {
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "lookup.csv")
+	}
+	filewatchtest.WriteFile(t, path, []byte(content))
+	return path
+}
+
+func enrichEvent(t testing.TB, enricher *Enricher, fields common.MapStr) *beat.Event {
+	event, err := enricher.Run(&beat.Event{Fields: fields})
+	require.NoError(t, err)
+	return event
+}
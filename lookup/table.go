@@ -0,0 +1,165 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package lookup annotates events with fields from a lookup file,
+// joined on the value of an event field such as service.name.
+package lookup
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+// invalidFieldNameChars holds the characters which may not
+// appear in the names of looked up fields.
+const invalidFieldNameChars = `.*"`
+
+// Table maps lookup keys to the fields with which matching
+// events are annotated.
+type Table struct {
+	entries map[string]common.MapStr
+}
+
+// LoadTable loads a Table from the lookup file at path, whose format is
+// determined by its extension.
+//
+// CSV files (".csv") must have a header row. The first column holds the
+// lookup keys, and each remaining column holds the values of the field
+// named in the header row; empty values are ignored.
+//
+// JSON files (".json") must hold an object mapping lookup keys to objects
+// of fields, whose values must be strings, numbers, or booleans.
+func LoadTable(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading lookup file")
+	}
+	defer f.Close()
+
+	var table *Table
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		table, err = decodeCSV(f)
+	case ".json":
+		table, err = decodeJSON(f)
+	default:
+		return nil, errors.Errorf("unsupported lookup file extension %q, expected .csv or .json", ext)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding lookup file %s", path)
+	}
+	return table, nil
+}
+
+func decodeCSV(r io.Reader) (*Table, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("missing header row")
+	}
+	header := records[0]
+	if len(header) < 2 {
+		return nil, errors.New("header row must name a key column and at least one field column")
+	}
+	for _, name := range header[1:] {
+		if err := validateFieldName(name); err != nil {
+			return nil, err
+		}
+	}
+	table := &Table{entries: make(map[string]common.MapStr, len(records)-1)}
+	for _, record := range records[1:] {
+		fields := make(common.MapStr)
+		for i, value := range record[1:] {
+			if value != "" {
+				fields[header[i+1]] = value
+			}
+		}
+		if err := table.add(record[0], fields); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+func decodeJSON(r io.Reader) (*Table, error) {
+	var entries map[string]map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+	table := &Table{entries: make(map[string]common.MapStr, len(entries))}
+	for key, entry := range entries {
+		fields := make(common.MapStr, len(entry))
+		for name, value := range entry {
+			if err := validateFieldName(name); err != nil {
+				return nil, err
+			}
+			switch value.(type) {
+			case string, float64, bool:
+			default:
+				return nil, errors.Errorf("invalid value for field %q of key %q: must be a string, number, or boolean", name, key)
+			}
+			fields[name] = value
+		}
+		if err := table.add(key, fields); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+func (t *Table) add(key string, fields common.MapStr) error {
+	if key == "" {
+		return errors.New("lookup keys must not be empty")
+	}
+	if _, ok := t.entries[key]; ok {
+		return errors.Errorf("duplicate lookup key %q", key)
+	}
+	t.entries[key] = fields
+	return nil
+}
+
+func validateFieldName(name string) error {
+	if name == "" {
+		return errors.New("field names must not be empty")
+	}
+	if strings.ContainsAny(name, invalidFieldNameChars) {
+		return errors.Errorf("invalid field name %q: must not contain any of %q", name, invalidFieldNameChars)
+	}
+	return nil
+}
+
+// Lookup returns the fields for key, and reports whether key was found.
+// The returned fields must not be modified.
+func (t *Table) Lookup(key string) (common.MapStr, bool) {
+	fields, ok := t.entries[key]
+	return fields, ok
+}
+
+// Len returns the number of keys in the table.
+func (t *Table) Len() int {
+	return len(t.entries)
+}
@@ -0,0 +1,119 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package lookup
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestLoadTableCSV(t *testing.T) {
+	path := writeTempFile(t, "owners.csv", `service,team,tier,cost_center
+opbeans-go,checkout,1,cc-1234
+opbeans-java,payments,,cc-5678
+`)
+	table, err := LoadTable(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, table.Len())
+
+	fields, ok := table.Lookup("opbeans-go")
+	assert.True(t, ok)
+	assert.Equal(t, common.MapStr{"team": "checkout", "tier": "1", "cost_center": "cc-1234"}, fields)
+
+	// Empty values are ignored.
+	fields, ok = table.Lookup("opbeans-java")
+	assert.True(t, ok)
+	assert.Equal(t, common.MapStr{"team": "payments", "cost_center": "cc-5678"}, fields)
+
+	_, ok = table.Lookup("opbeans-node")
+	assert.False(t, ok)
+}
+
+func TestLoadTableJSON(t *testing.T) {
+	path := writeTempFile(t, "owners.json", `{
+  "opbeans-go": {"team": "checkout", "tier": 1, "critical": true}
+}`)
+	table, err := LoadTable(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, table.Len())
+
+	fields, ok := table.Lookup("opbeans-go")
+	assert.True(t, ok)
+	assert.Equal(t, common.MapStr{"team": "checkout", "tier": 1.0, "critical": true}, fields)
+}
+
+func TestLoadTableInvalid(t *testing.T) {
+	for name, test := range map[string]struct {
+		filename string
+		content  string
+		err      string
+	}{
+		"unsupported extension": {
+			filename: "owners.yml",
+			content:  "opbeans: {}",
+			err:      `unsupported lookup file extension ".yml", expected .csv or .json`,
+		},
+		"csv missing header": {
+			filename: "owners.csv",
+			err:      "missing header row",
+		},
+		"csv no field columns": {
+			filename: "owners.csv",
+			content:  "service\nopbeans\n",
+			err:      "header row must name a key column and at least one field column",
+		},
+		"csv invalid field name": {
+			filename: "owners.csv",
+			content:  "service,owner.team\nopbeans,checkout\n",
+			err:      `invalid field name "owner.team"`,
+		},
+		"csv duplicate key": {
+			filename: "owners.csv",
+			content:  "service,team\nopbeans,checkout\nopbeans,payments\n",
+			err:      `duplicate lookup key "opbeans"`,
+		},
+		"json nested value": {
+			filename: "owners.json",
+			content:  `{"opbeans": {"team": {"name": "checkout"}}}`,
+			err:      `invalid value for field "team" of key "opbeans"`,
+		},
+		"json empty key": {
+			filename: "owners.json",
+			content:  `{"": {"team": "checkout"}}`,
+			err:      "lookup keys must not be empty",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadTable(writeTempFile(t, test.filename, test.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func writeTempFile(t testing.TB, filename, content string) string {
+	path := filepath.Join(t.TempDir(), filename)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
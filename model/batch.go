@@ -20,10 +20,23 @@ package model
 import (
 	"context"
 
-	"github.com/elastic/apm-server/transform"
 	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/transform"
 )
 
+// expiresAtField holds the name of the field recording the time after
+// which a document may be deleted, as configured in transform.Config.Expiry.
+const expiresAtField = "expires_at"
+
+// truncatedSuffix is appended to the name of a keyword field truncated
+// according to transform.Config.KeywordTruncation, for recording that the
+// field was truncated.
+const truncatedSuffix = "_truncated"
+
 // BatchProcessor can be used to process a batch of events, giving the
 // opportunity to update, add or remove events.
 type BatchProcessor interface {
@@ -68,20 +81,88 @@ func (b *Batch) Len() int {
 
 func (b *Batch) Transform(ctx context.Context, cfg *transform.Config) []beat.Event {
 	events := make([]beat.Event, 0, b.Len())
+	n := len(events)
 	for _, event := range b.Transactions {
 		events = event.appendBeatEvents(cfg, events)
 	}
+	n = setExpiry(cfg, transactionDocType, events, n)
 	for _, event := range b.Spans {
 		events = event.appendBeatEvents(ctx, cfg, events)
 	}
+	n = setExpiry(cfg, spanDocType, events, n)
 	for _, event := range b.Metricsets {
 		events = event.appendBeatEvents(cfg, events)
 	}
+	n = setExpiry(cfg, metricsetDocType, events, n)
 	for _, event := range b.Errors {
 		events = event.appendBeatEvents(ctx, cfg, events)
 	}
+	n = setExpiry(cfg, errorDocType, events, n)
 	for _, event := range b.Profiles {
 		events = event.appendBeatEvents(cfg, events)
 	}
+	setExpiry(cfg, profileDocType, events, n)
+	truncateKeywords(cfg, events)
 	return events
 }
+
+// setExpiry sets the expiry timestamp of events[from:], which must all be of
+// the given event type, according to the time-to-live configured for the event
+// type. setExpiry returns len(events), for passing to the next call.
+func setExpiry(cfg *transform.Config, docType string, events []beat.Event, from int) int {
+	if ttl, ok := cfg.Expiry[docType]; ok {
+		for i := from; i < len(events); i++ {
+			events[i].Fields[expiresAtField] = common.Time(events[i].Timestamp.Add(ttl))
+		}
+	}
+	return len(events)
+}
+
+// truncateKeywords truncates the keyword fields of events configured in
+// cfg.KeywordTruncation, so that their values are indexed rather than being
+// dropped by Elasticsearch for exceeding the template's ignore_above limit.
+func truncateKeywords(cfg *transform.Config, events []beat.Event) {
+	maxLength := cfg.KeywordTruncation.MaxLength
+	if maxLength <= 0 {
+		return
+	}
+	for _, event := range events {
+		for _, field := range cfg.KeywordTruncation.Fields {
+			v, err := event.Fields.GetValue(field)
+			if err != nil {
+				continue
+			}
+			s, ok := v.(string)
+			if !ok {
+				continue
+			}
+			truncated, ok := truncateRunes(s, maxLength)
+			if !ok {
+				continue
+			}
+			if _, err := event.Fields.Put(field, truncated); err != nil {
+				logp.NewLogger(logs.Transform).With(logp.Error(err)).Warnf("failed to truncate %s", field)
+				continue
+			}
+			if _, err := event.Fields.Put(field+truncatedSuffix, true); err != nil {
+				logp.NewLogger(logs.Transform).With(logp.Error(err)).Warnf("failed to mark %s as truncated", field)
+			}
+		}
+	}
+}
+
+// truncateRunes returns s truncated at n runes, and whether s was truncated.
+func truncateRunes(s string, n int) (string, bool) {
+	if len(s) <= n {
+		// s has at most len(s) runes.
+		return s, false
+	}
+	var j int
+	for i := range s {
+		if j == n {
+			return s[:i], true
+		}
+		j++
+	}
+	return s, false
+}
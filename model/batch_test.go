@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestBatchTransformExpiry(t *testing.T) {
+	timestamp := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	batch := &model.Batch{
+		Transactions: []*model.Transaction{{Timestamp: timestamp}},
+		Spans:        []*model.Span{{Timestamp: timestamp}, {Timestamp: timestamp}},
+		Errors:       []*model.Error{{Timestamp: timestamp}},
+	}
+	events := batch.Transform(context.Background(), &transform.Config{
+		Expiry: map[string]time.Duration{
+			"span":  7 * 24 * time.Hour,
+			"error": 90 * 24 * time.Hour,
+		},
+	})
+	require.Len(t, events, 4)
+
+	var expiry []interface{}
+	for _, event := range events {
+		expiry = append(expiry, event.Fields["expires_at"])
+	}
+	assert.Equal(t, []interface{}{
+		nil, // no TTL configured for transactions
+		common.Time(timestamp.Add(7 * 24 * time.Hour)),
+		common.Time(timestamp.Add(7 * 24 * time.Hour)),
+		common.Time(timestamp.Add(90 * 24 * time.Hour)),
+	}, expiry)
+}
+
+func TestBatchTransformKeywordTruncation(t *testing.T) {
+	batch := &model.Batch{
+		Transactions: []*model.Transaction{{Name: "short"}, {Name: "ünïcödé-name"}},
+		Spans:        []*model.Span{{Name: "a-very-long-span-name"}},
+	}
+	events := batch.Transform(context.Background(), &transform.Config{
+		KeywordTruncation: transform.KeywordTruncationConfig{
+			MaxLength: 7,
+			Fields:    []string{"transaction.name", "span.name"},
+		},
+	})
+	require.Len(t, events, 3)
+
+	assert.Equal(t, common.MapStr{"name": "short"}, fieldsSubset(t, events[0].Fields, "transaction", "name", "name_truncated"))
+	assert.Equal(t,
+		common.MapStr{"name": "ünïcödé", "name_truncated": true},
+		fieldsSubset(t, events[1].Fields, "transaction", "name", "name_truncated"),
+	)
+	assert.Equal(t,
+		common.MapStr{"name": "a-very-", "name_truncated": true},
+		fieldsSubset(t, events[2].Fields, "span", "name", "name_truncated"),
+	)
+}
+
+func fieldsSubset(t *testing.T, fields common.MapStr, group string, keys ...string) common.MapStr {
+	v, err := fields.GetValue(group)
+	require.NoError(t, err)
+	subset := common.MapStr{}
+	for _, k := range keys {
+		if value, ok := v.(common.MapStr)[k]; ok {
+			subset[k] = value
+		}
+	}
+	return subset
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"hash"
 	"io"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -42,6 +43,7 @@ var (
 	errorTransformations   = monitoring.NewInt(errorMetrics, "transformations")
 	errorStacktraceCounter = monitoring.NewInt(errorMetrics, "stacktraces")
 	errorFrameCounter      = monitoring.NewInt(errorMetrics, "frames")
+	errorDedupCounter      = monitoring.NewInt(errorMetrics, "deduplicated_stacktraces")
 	errorProcessorEntry    = common.MapStr{"name": errorProcessorName, "event": errorDocType}
 )
 
@@ -213,6 +215,7 @@ func findSmappedNonLibraryFrame(frames []*StacktraceFrame) *StacktraceFrame {
 
 func (e *Error) exceptionFields(ctx context.Context, cfg *transform.Config, chain []Exception) []common.MapStr {
 	var result []common.MapStr
+	var stacktraces [][]common.MapStr
 	for _, exception := range chain {
 		var ex mapStr
 		ex.maybeSetString("message", exception.Message)
@@ -237,8 +240,19 @@ func (e *Error) exceptionFields(ctx context.Context, cfg *transform.Config, chai
 			ex.set("code", code.String())
 		}
 
-		if st := exception.Stacktrace.transform(ctx, cfg, e.RUM, &e.Metadata.Service); len(st) > 0 {
-			ex.set("stacktrace", st)
+		st := exception.Stacktrace.transform(ctx, cfg, e.RUM, &e.Metadata.Service, e.Timestamp)
+		if len(st) > 0 {
+			if ref, ok := findStacktrace(stacktraces, st); cfg.DedupStacktraces && ok {
+				// The stacktrace is identical to that of an earlier exception
+				// in the chain, so refer to it by position instead of repeating it.
+				ex.set("stacktrace_ref", ref)
+				errorDedupCounter.Inc()
+			} else {
+				ex.set("stacktrace", st)
+			}
+		}
+		if cfg.DedupStacktraces {
+			stacktraces = append(stacktraces, st)
 		}
 
 		result = append(result, common.MapStr(ex))
@@ -246,6 +260,17 @@ func (e *Error) exceptionFields(ctx context.Context, cfg *transform.Config, chai
 	return result
 }
 
+// findStacktrace returns the position of the first stacktrace in
+// stacktraces equal to st, and whether there is one.
+func findStacktrace(stacktraces [][]common.MapStr, st []common.MapStr) (int, bool) {
+	for i, other := range stacktraces {
+		if len(other) == len(st) && reflect.DeepEqual(other, st) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
 func (e *Error) logFields(ctx context.Context, cfg *transform.Config) common.MapStr {
 	if e.Log == nil {
 		return nil
@@ -255,7 +280,7 @@ func (e *Error) logFields(ctx context.Context, cfg *transform.Config) common.Map
 	log.maybeSetString("param_message", e.Log.ParamMessage)
 	log.maybeSetString("logger_name", e.Log.LoggerName)
 	log.maybeSetString("level", e.Log.Level)
-	if st := e.Log.Stacktrace.transform(ctx, cfg, e.RUM, &e.Metadata.Service); len(st) > 0 {
+	if st := e.Log.Stacktrace.transform(ctx, cfg, e.RUM, &e.Metadata.Service, e.Timestamp); len(st) > 0 {
 		log.set("stacktrace", st)
 	}
 	return common.MapStr(log)
@@ -294,6 +319,32 @@ func (k *groupingKey) String() string {
 	return hex.EncodeToString(k.hash.Sum(nil))
 }
 
+// ApplyStacktraceConfig applies source mapping and the RUM library and
+// exclude_from_grouping frame patterns in cfg to the stacktraces of a RUM
+// error, as is otherwise done when the error is transformed. Frames are not
+// source mapped again during transformation.
+func (e *Error) ApplyStacktraceConfig(ctx context.Context, cfg *transform.Config) {
+	if !e.RUM {
+		return
+	}
+	if e.Log != nil {
+		e.Log.Stacktrace.applyConfig(ctx, cfg, e.RUM, &e.Metadata.Service, e.Timestamp)
+	}
+	for _, exception := range flattenExceptionTree(e.Exception) {
+		exception.Stacktrace.applyConfig(ctx, cfg, e.RUM, &e.Metadata.Service, e.Timestamp)
+	}
+}
+
+// GroupingKey returns the error's grouping key, as recorded in
+// error.grouping_key.
+//
+// For RUM errors, the grouping key recorded in the error document reflects
+// source mapped stack frames, and will only be returned by GroupingKey if
+// ApplyStacktraceConfig has been called first.
+func (e *Error) GroupingKey() string {
+	return e.calcGroupingKey(flattenExceptionTree(e.Exception))
+}
+
 // calcGroupingKey computes a value for deduplicating errors - events with
 // same grouping key can be collapsed together.
 func (e *Error) calcGroupingKey(chain []Exception) string {
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"regexp"
 	"testing"
 	"time"
 
@@ -116,6 +117,58 @@ func TestHandleExceptionTree(t *testing.T) {
 	assert.Equal(t, 0, *exceptions[6].Parent)
 }
 
+func TestDedupStacktraces(t *testing.T) {
+	newError := func() *Error {
+		stacktrace := func(function string) Stacktrace {
+			return Stacktrace{{Filename: "file", Function: function}, {Filename: "file", Function: "main"}}
+		}
+		return &Error{
+			Exception: &Exception{
+				Message:    "message0",
+				Stacktrace: stacktrace("handler"),
+				Cause: []Exception{{
+					Message:    "message1",
+					Stacktrace: stacktrace("handler"),
+				}, {
+					Message:    "message2",
+					Stacktrace: stacktrace("query"),
+					Cause: []Exception{{
+						Message:    "message3",
+						Stacktrace: stacktrace("query"),
+					}, {
+						Message: "message4",
+					}},
+				}},
+			},
+		}
+	}
+
+	exceptions := func(cfg *transform.Config) []common.MapStr {
+		fields := newError().fields(context.Background(), cfg)
+		return fields["exception"].([]common.MapStr)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		for _, ex := range exceptions(&transform.Config{})[:4] {
+			assert.Contains(t, ex, "stacktrace")
+			assert.NotContains(t, ex, "stacktrace_ref")
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		result := exceptions(&transform.Config{DedupStacktraces: true})
+		require.Len(t, result, 5)
+		assert.Contains(t, result[0], "stacktrace")
+		assert.Equal(t, 0, result[1]["stacktrace_ref"])
+		assert.NotContains(t, result[1], "stacktrace")
+		assert.Contains(t, result[2], "stacktrace")
+		assert.Equal(t, 2, result[3]["stacktrace_ref"])
+		assert.NotContains(t, result[3], "stacktrace")
+		assert.NotContains(t, result[4], "stacktrace")
+		assert.NotContains(t, result[4], "stacktrace_ref")
+	})
+}
+
 func TestEventFields(t *testing.T) {
 	id := "45678"
 	culprit := "some trigger"
@@ -605,6 +658,12 @@ func TestExplicitGroupingKey(t *testing.T) {
 	}
 }
 
+func TestGroupingKey(t *testing.T) {
+	attr := "hello world"
+	e := Error{Exception: baseException().withType(attr)}
+	assert.Equal(t, hex.EncodeToString(md5With(attr)), e.GroupingKey())
+}
+
 func TestFramesUsableForGroupingKey(t *testing.T) {
 	webpackLineno := 77
 	tmpLineno := 45
@@ -805,7 +864,7 @@ func TestSourcemapping(t *testing.T) {
 	assert.Equal(t, 1, *event.Exception.Stacktrace[0].Lineno)
 
 	// transform with sourcemap store
-	store, err := sourcemap.NewStore(test.ESClientWithValidSourcemap(t), "apm-*sourcemap*", time.Minute)
+	store, err := sourcemap.NewStore(test.ESClientWithValidSourcemap(t), "apm-*sourcemap*", time.Minute, sourcemap.VersionSelectionLatest)
 	require.NoError(t, err)
 	transformedWithSourcemap := event.fields(context.Background(), &transform.Config{
 		RUM: transform.RUMConfig{SourcemapStore: store},
@@ -815,3 +874,40 @@ func TestSourcemapping(t *testing.T) {
 	assert.NotEqual(t, transformedNoSourcemap["exception"], transformedWithSourcemap["exception"])
 	assert.NotEqual(t, transformedNoSourcemap["grouping_key"], transformedWithSourcemap["grouping_key"])
 }
+
+func TestErrorApplyStacktraceConfig(t *testing.T) {
+	newEvent := func() *Error {
+		return &Error{
+			Metadata: Metadata{Service: Service{Name: "foo", Version: "bar"}},
+			Exception: &Exception{
+				Type: "Error",
+				Stacktrace: Stacktrace{
+					&StacktraceFrame{Filename: "/a/b/c", Lineno: tests.IntPtr(1), Colno: tests.IntPtr(23), AbsPath: "../a/b"},
+					&StacktraceFrame{Filename: "/webpack/d", AbsPath: "/webpack/d"},
+				},
+			},
+			RUM: true,
+		}
+	}
+	store, err := sourcemap.NewStore(test.ESClientWithValidSourcemap(t), "apm-*sourcemap*", time.Minute, sourcemap.VersionSelectionLatest)
+	require.NoError(t, err)
+	cfg := &transform.Config{RUM: transform.RUMConfig{
+		SourcemapStore:      store,
+		ExcludeFromGrouping: regexp.MustCompile("^/webpack"),
+	}}
+
+	event := newEvent()
+	unmappedKey := event.GroupingKey()
+	event.ApplyStacktraceConfig(context.Background(), cfg)
+	assert.Equal(t, 5, *event.Exception.Stacktrace[0].Lineno)
+	assert.True(t, event.Exception.Stacktrace[1].ExcludeFromGrouping)
+	groupingKey := event.GroupingKey()
+	assert.NotEqual(t, unmappedKey, groupingKey)
+
+	// Transforming the event must not map the frames again,
+	// and must record the same grouping key.
+	fields := event.fields(context.Background(), cfg)
+	assert.Equal(t, 5, *event.Exception.Stacktrace[0].Lineno)
+	assert.Equal(t, groupingKey, fields["grouping_key"])
+	assert.Equal(t, newEvent().fields(context.Background(), cfg), fields)
+}
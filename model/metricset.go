@@ -37,6 +37,7 @@ const (
 	metricsetEventKey       = "event"
 	metricsetTransactionKey = "transaction"
 	metricsetSpanKey        = "span"
+	metricsetErrorKey       = "error"
 	AppMetricsDataset       = "apm.app"
 	InternalMetricsDataset  = "apm.internal"
 )
@@ -68,6 +69,10 @@ type Metricset struct {
 	// metrics are associated.
 	Span MetricsetSpan
 
+	// Error holds information about the error group with which the
+	// metrics are associated.
+	Error MetricsetError
+
 	// Labels holds arbitrary labels to apply to the metrics.
 	//
 	// These labels override any with the same names in Metadata.Labels.
@@ -86,6 +91,11 @@ type Metricset struct {
 
 	// Name holds an optional name for the metricset.
 	Name string
+
+	// DataStreamDataset holds an optional data stream dataset for the
+	// metricset. If specified, the metricset is stored in this dataset
+	// rather than one derived from its service name.
+	DataStreamDataset string
 }
 
 // Sample represents a single named metric.
@@ -155,6 +165,12 @@ type MetricsetSpan struct {
 	DestinationService DestinationService
 }
 
+// MetricsetError provides enough information to connect a metricset to the related group of errors.
+type MetricsetError struct {
+	// GroupingKey holds the error grouping key.
+	GroupingKey string
+}
+
 func (me *Metricset) appendBeatEvents(cfg *transform.Config, events []beat.Event) []beat.Event {
 	metricsetTransformations.Inc()
 	if me == nil {
@@ -193,6 +209,10 @@ func (me *Metricset) appendBeatEvents(cfg *transform.Config, events []beat.Event
 		isInternal = true
 		common.MapStr(fields).DeepUpdate(common.MapStr{metricsetSpanKey: spanFields})
 	}
+	if errorFields := me.Error.fields(); errorFields != nil {
+		isInternal = true
+		common.MapStr(fields).DeepUpdate(common.MapStr{metricsetErrorKey: errorFields})
+	}
 
 	if me.TimeseriesInstanceID != "" {
 		fields["timeseries"] = common.MapStr{"instance": me.TimeseriesInstanceID}
@@ -205,15 +225,18 @@ func (me *Metricset) appendBeatEvents(cfg *transform.Config, events []beat.Event
 	fields["processor"] = metricsetProcessorEntry
 
 	if cfg.DataStreams {
-		dataset := AppMetricsDataset
-		// Metrics are stored in "metrics" data streams.
-		if isInternal {
-			// Metrics that include well-defined transaction/span fields
-			// (i.e. breakdown metrics, transaction and span metrics) will
-			// be stored separately from application and runtime metrics.
-			dataset = InternalMetricsDataset
+		dataset := me.DataStreamDataset
+		if dataset == "" {
+			// Metrics are stored in "metrics" data streams.
+			dataset = AppMetricsDataset
+			if isInternal {
+				// Metrics that include well-defined transaction/span fields
+				// (i.e. breakdown metrics, transaction and span metrics) will
+				// be stored separately from application and runtime metrics.
+				dataset = InternalMetricsDataset
+			}
+			dataset += fmt.Sprintf(".%s", datastreams.NormalizeServiceName(me.Metadata.Service.Name))
 		}
-		dataset += fmt.Sprintf(".%s", datastreams.NormalizeServiceName(me.Metadata.Service.Name))
 		fields[datastreams.DatasetField] = dataset
 		fields[datastreams.TypeField] = datastreams.MetricsType
 	}
@@ -251,6 +274,12 @@ func (s *MetricsetSpan) fields() common.MapStr {
 	return common.MapStr(fields)
 }
 
+func (e *MetricsetError) fields() common.MapStr {
+	var fields mapStr
+	fields.maybeSetString("grouping_key", e.GroupingKey)
+	return common.MapStr(fields)
+}
+
 func (s *Sample) set(fields common.MapStr) error {
 	switch {
 	case len(s.Counts) > 0:
@@ -226,6 +226,46 @@ func TestTransform(t *testing.T) {
 			},
 			Msg: "Payload with destination service.",
 		},
+		{
+			Metricset: &Metricset{
+				Timestamp: timestamp,
+				Metadata:  metadata,
+				Name:      "error_grouping",
+				Error:     MetricsetError{GroupingKey: "abc123"},
+				Samples:   []Sample{{Name: "error.count", Value: 3}},
+			},
+			Output: []common.MapStr{
+				{
+					"data_stream.type":    "metrics",
+					"data_stream.dataset": "apm.internal.myservice",
+					"processor":           common.MapStr{"event": "metric", "name": "metric"},
+					"service":             common.MapStr{"name": "myservice"},
+					"metricset.name":      "error_grouping",
+					"error":               common.MapStr{"grouping_key": "abc123", "count": 3.0},
+				},
+			},
+			Msg: "Payload with error grouping key.",
+		},
+		{
+			Metricset: &Metricset{
+				Timestamp:         timestamp,
+				Metadata:          metadata,
+				Name:              "service_usage",
+				DataStreamDataset: "apm.usage",
+				Samples:           []Sample{{Name: "usage.bytes_in", Value: 123}},
+			},
+			Output: []common.MapStr{
+				{
+					"data_stream.type":    "metrics",
+					"data_stream.dataset": "apm.usage",
+					"processor":           common.MapStr{"event": "metric", "name": "metric"},
+					"service":             common.MapStr{"name": "myservice"},
+					"metricset.name":      "service_usage",
+					"usage":               common.MapStr{"bytes_in": 123.0},
+				},
+			},
+			Msg: "Payload with data stream dataset.",
+		},
 	}
 
 	for idx, test := range tests {
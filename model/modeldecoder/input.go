@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/utility"
 )
 
 // Input holds the input required for decoding an event.
@@ -39,6 +40,16 @@ type Input struct {
 	// static configuration defined in one location, removing
 	// the possibility of inconsistent configuration.
 	Config Config
+
+	// SkipValidation controls whether validation of the decoded
+	// input model is skipped, relying on the typed decoder alone.
+	// This is only honoured by the v2 event decoders; metadata is
+	// always validated.
+	SkipValidation bool
+
+	// PhaseTimings, if non-nil, records the time spent validating
+	// the decoded input model.
+	PhaseTimings *utility.PhaseTimings
 }
 
 // Config holds static configuration which applies to all decoding.
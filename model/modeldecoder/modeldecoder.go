@@ -19,6 +19,7 @@ package modeldecoder
 
 import (
 	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -49,6 +50,75 @@ func (e *ValidationError) Unwrap() error {
 	return e.err
 }
 
+// Field returns the path of the field which failed validation, with path
+// elements separated by dots, e.g. "transaction.span_count.started". If a
+// rule requires one of several fields, the alternative field names are
+// separated by semicolons, e.g. "error.exception;log".
+func (e ValidationError) Field() string {
+	field, _ := parseValidationError(e.err.Error())
+	return field
+}
+
+// Rule returns the kind of validation rule which was violated, e.g.
+// "required" or "maxLength".
+func (e ValidationError) Rule() string {
+	_, rule := parseValidationError(e.err.Error())
+	return rule
+}
+
+const (
+	validationRuleRequired      = "required"
+	validationRuleRequiredAnyOf = "requiredAnyOf"
+	validationRuleUnknown       = "unknown"
+)
+
+// parseValidationError parses the field path and violated rule out of a
+// validation error message produced by the generated validation code, e.g.
+// "transaction: context: 'tags': validation rule 'maxLengthVals(1024)' violated".
+func parseValidationError(msg string) (field, rule string) {
+	var path []string
+	parts := strings.Split(msg, ": ")
+	for i, part := range parts {
+		if strings.HasPrefix(part, "requires at least one of the fields ") {
+			path = append(path, quoted(part))
+			rule = validationRuleRequiredAnyOf
+			break
+		}
+		if !strings.HasPrefix(part, "'") {
+			path = append(path, part)
+			continue
+		}
+		path = append(path, quoted(part))
+		if i+1 < len(parts) {
+			rule = quoted(parts[i+1])
+			if n := strings.IndexByte(rule, '('); n >= 0 {
+				rule = rule[:n]
+			}
+		} else if strings.Contains(part, " required") {
+			rule = validationRuleRequired
+		}
+		break
+	}
+	if rule == "" {
+		rule = validationRuleUnknown
+	}
+	return strings.Join(path, "."), rule
+}
+
+// quoted returns the first single-quoted substring of s,
+// or s if it contains no single-quoted substring.
+func quoted(s string) string {
+	start := strings.IndexByte(s, '\'')
+	if start < 0 {
+		return s
+	}
+	end := strings.IndexByte(s[start+1:], '\'')
+	if end < 0 {
+		return s
+	}
+	return s[start+1 : start+1+end]
+}
+
 var jsoniterErrRegexp = regexp.MustCompile(` but found .*error found in .* bigger context.*`)
 
 // NewDecoderErrFromJSONIter returns a DecoderError where
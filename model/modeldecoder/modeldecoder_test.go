@@ -0,0 +1,61 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modeldecoder
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationErrorFieldRule(t *testing.T) {
+	for _, test := range []struct {
+		message string
+		field   string
+		rule    string
+	}{{
+		message: "transaction: span_count: 'started' required",
+		field:   "transaction.span_count.started",
+		rule:    "required",
+	}, {
+		message: "span: 'trace_id' required when 'transaction_id' is set",
+		field:   "span.trace_id",
+		rule:    "required",
+	}, {
+		message: "transaction: context: 'tags': validation rule 'maxLengthVals(1024)' violated",
+		field:   "transaction.context.tags",
+		rule:    "maxLengthVals",
+	}, {
+		message: "error: context: 'tags': validation rule 'inputTypesVals(string;bool;number)' violated for key foo",
+		field:   "error.context.tags",
+		rule:    "inputTypesVals",
+	}, {
+		message: "error: requires at least one of the fields 'exception;log'",
+		field:   "error.exception;log",
+		rule:    "requiredAnyOf",
+	}, {
+		message: "something unexpected",
+		field:   "something unexpected",
+		rule:    "unknown",
+	}} {
+		err := NewValidationErr(errors.New(test.message))
+		assert.Equal(t, test.field, err.Field(), test.message)
+		assert.Equal(t, test.rule, err.Rule(), test.message)
+	}
+}
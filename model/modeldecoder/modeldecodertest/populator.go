@@ -123,6 +123,8 @@ func SetStructValues(in interface{}, values *Values, opts ...SetStructValuesOpti
 			fieldVal = reflect.ValueOf(values.Str)
 		case reflect.Int:
 			fieldVal = reflect.ValueOf(values.Int)
+		case reflect.Bool:
+			fieldVal = reflect.ValueOf(values.Bool)
 		case reflect.Slice:
 			var elemVal reflect.Value
 			switch v := f.Interface().(type) {
@@ -291,6 +293,9 @@ func AssertStructValues(t *testing.T, i interface{}, isException func(string) bo
 			newVal = values.HTTPHeader
 		case time.Time:
 			newVal = values.Time
+		case time.Duration:
+			// durations are decoded from float64 milliseconds
+			newVal = time.Duration(values.Float * float64(time.Millisecond))
 		default:
 			// the populator recursively iterates over struct and structPtr
 			// calling this function for all fields;
@@ -32,6 +32,7 @@ import (
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/model/modeldecoder"
 	"github.com/elastic/apm-server/model/modeldecoder/nullable"
+	"github.com/elastic/apm-server/utility"
 )
 
 var (
@@ -120,7 +121,10 @@ func DecodeNestedError(d decoder.Decoder, input *modeldecoder.Input, out *model.
 	if err := d.Decode(root); err != nil && err != io.EOF {
 		return modeldecoder.NewDecoderErrFromJSONIter(err)
 	}
-	if err := root.validate(); err != nil {
+	start := time.Now()
+	err := root.validate()
+	input.PhaseTimings.Add(utility.PhaseValidate, time.Since(start))
+	if err != nil {
 		return modeldecoder.NewValidationErr(err)
 	}
 	mapToErrorModel(&root.Error, &input.Metadata, input.RequestTime, out)
@@ -138,7 +142,10 @@ func DecodeNestedMetricset(d decoder.Decoder, input *modeldecoder.Input, out *mo
 	if err := d.Decode(root); err != nil && err != io.EOF {
 		return modeldecoder.NewDecoderErrFromJSONIter(err)
 	}
-	if err := root.validate(); err != nil {
+	start := time.Now()
+	err := root.validate()
+	input.PhaseTimings.Add(utility.PhaseValidate, time.Since(start))
+	if err != nil {
 		return modeldecoder.NewValidationErr(err)
 	}
 	mapToMetricsetModel(&root.Metricset, &input.Metadata, input.RequestTime, out)
@@ -164,7 +171,10 @@ func DecodeNestedTransaction(d decoder.Decoder, input *modeldecoder.Input, out *
 	if err := d.Decode(root); err != nil && err != io.EOF {
 		return modeldecoder.NewDecoderErrFromJSONIter(err)
 	}
-	if err := root.validate(); err != nil {
+	start := time.Now()
+	err := root.validate()
+	input.PhaseTimings.Add(utility.PhaseValidate, time.Since(start))
+	if err != nil {
 		return modeldecoder.NewValidationErr(err)
 	}
 	mapToTransactionModel(&root.Transaction, &input.Metadata, input.RequestTime, &out.Transaction)
@@ -612,7 +622,7 @@ func mapToSpanModel(from *span, metadata *model.Metadata, reqTime time.Time, out
 		out.Labels = from.Context.Tags.Clone()
 	}
 	if from.Duration.IsSet() {
-		out.Duration = from.Duration.Val
+		out.Duration = time.Duration(from.Duration.Val * float64(time.Millisecond))
 	}
 	if from.ID.IsSet() {
 		out.ID = from.ID.Val
@@ -750,7 +760,7 @@ func mapToTransactionModel(from *transaction, metadata *model.Metadata, reqTime
 		}
 	}
 	if from.Duration.IsSet() {
-		out.Duration = from.Duration.Val
+		out.Duration = time.Duration(from.Duration.Val * float64(time.Millisecond))
 	}
 	if from.ID.IsSet() {
 		out.ID = from.ID.Val
@@ -39,7 +39,7 @@ func initializedMetadata() *model.Metadata {
 	modeldecodertest.SetStructValues(&input, modeldecodertest.DefaultValues())
 	mapToMetadataModel(&input, &out)
 	// initialize values that are not set by input
-	out.UserAgent = model.UserAgent{Name: "init", Original: "init"}
+	out.UserAgent = model.UserAgent{Name: "init", Original: "init", Bot: true}
 	out.Client.Domain = "init"
 	out.Client.IP = net.ParseIP("127.0.0.1")
 	out.Client.Port = 1
@@ -109,7 +109,7 @@ func TestDecodeMetadataMappingToModel(t *testing.T) {
 			Labels: labels,
 			// these values are not set from http headers and
 			// are not expected change with updated input data
-			UserAgent: model.UserAgent{Original: "init", Name: "init"},
+			UserAgent: model.UserAgent{Original: "init", Name: "init", Bot: true},
 			Client: model.Client{
 				Domain: "init",
 				IP:     net.ParseIP("127.0.0.1"),
@@ -151,7 +151,7 @@ func TestDecodeMetadataMappingToModel(t *testing.T) {
 		modeldecodertest.SetStructValues(&input, defaultVal)
 		mapToMetadataModel(&input, &out1)
 		// initialize values that are not set by input
-		out1.UserAgent = model.UserAgent{Name: "init", Original: "init"}
+		out1.UserAgent = model.UserAgent{Name: "init", Original: "init", Bot: true}
 		out1.Client.Domain = "init"
 		out1.Client.IP = net.ParseIP("127.0.0.1")
 		out1.Client.Port = 1
@@ -163,7 +163,7 @@ func TestDecodeMetadataMappingToModel(t *testing.T) {
 		input.Reset()
 		modeldecodertest.SetStructValues(&input, otherVal)
 		mapToMetadataModel(&input, &out2)
-		out2.UserAgent = model.UserAgent{Name: "init", Original: "init"}
+		out2.UserAgent = model.UserAgent{Name: "init", Original: "init", Bot: true}
 		out2.Client.Domain = "init"
 		out2.Client.IP = net.ParseIP("127.0.0.1")
 		out2.Client.Port = 1
@@ -86,8 +86,10 @@ func TestDecodeMapToMetricsetModel(t *testing.T) {
 				strings.HasPrefix(key, "Transaction") ||
 				// only set by aggregator
 				strings.HasPrefix(key, "Event") ||
+				strings.HasPrefix(key, "Error") ||
 				key == "Name" ||
 				key == "TimeseriesInstanceID" ||
+				key == "DataStreamDataset" ||
 				strings.HasPrefix(key, "Span.DestinationService") ||
 				// test Samples separately
 				strings.HasPrefix(key, "Samples") {
@@ -127,7 +127,7 @@ func DecodeNestedMetadata(d decoder.Decoder, out *model.Metadata) error {
 }
 
 // DecodeNestedError uses the given decoder to create the input model,
-// then runs the defined validations on the input model
+// then runs the defined validations on the input model, unless input.SkipValidation is set,
 // and finally maps the values fom the input model to the given *model.Error instance
 //
 // DecodeNestedError should be used when the stream in the decoder contains the `error` key
@@ -138,15 +138,20 @@ func DecodeNestedError(d decoder.Decoder, input *modeldecoder.Input, out *model.
 	if err = d.Decode(root); err != nil && err != io.EOF {
 		return modeldecoder.NewDecoderErrFromJSONIter(err)
 	}
-	if err := root.validate(); err != nil {
-		return modeldecoder.NewValidationErr(err)
+	if !input.SkipValidation {
+		start := time.Now()
+		err := root.validate()
+		input.PhaseTimings.Add(utility.PhaseValidate, time.Since(start))
+		if err != nil {
+			return modeldecoder.NewValidationErr(err)
+		}
 	}
 	mapToErrorModel(&root.Error, &input.Metadata, input.RequestTime, input.Config, out)
 	return err
 }
 
 // DecodeNestedMetricset uses the given decoder to create the input model,
-// then runs the defined validations on the input model
+// then runs the defined validations on the input model, unless input.SkipValidation is set,
 // and finally maps the values fom the input model to the given *model.Metricset instance
 //
 // DecodeNestedMetricset should be used when the stream in the decoder contains the `metricset` key
@@ -157,15 +162,20 @@ func DecodeNestedMetricset(d decoder.Decoder, input *modeldecoder.Input, out *mo
 	if err = d.Decode(root); err != nil && err != io.EOF {
 		return modeldecoder.NewDecoderErrFromJSONIter(err)
 	}
-	if err := root.validate(); err != nil {
-		return modeldecoder.NewValidationErr(err)
+	if !input.SkipValidation {
+		start := time.Now()
+		err := root.validate()
+		input.PhaseTimings.Add(utility.PhaseValidate, time.Since(start))
+		if err != nil {
+			return modeldecoder.NewValidationErr(err)
+		}
 	}
 	mapToMetricsetModel(&root.Metricset, &input.Metadata, input.RequestTime, input.Config, out)
 	return err
 }
 
 // DecodeNestedSpan uses the given decoder to create the input model,
-// then runs the defined validations on the input model
+// then runs the defined validations on the input model, unless input.SkipValidation is set,
 // and finally maps the values fom the input model to the given *model.Span instance
 //
 // DecodeNestedSpan should be used when the stream in the decoder contains the `span` key
@@ -176,15 +186,20 @@ func DecodeNestedSpan(d decoder.Decoder, input *modeldecoder.Input, out *model.S
 	if err = d.Decode(root); err != nil && err != io.EOF {
 		return modeldecoder.NewDecoderErrFromJSONIter(err)
 	}
-	if err := root.validate(); err != nil {
-		return modeldecoder.NewValidationErr(err)
+	if !input.SkipValidation {
+		start := time.Now()
+		err := root.validate()
+		input.PhaseTimings.Add(utility.PhaseValidate, time.Since(start))
+		if err != nil {
+			return modeldecoder.NewValidationErr(err)
+		}
 	}
 	mapToSpanModel(&root.Span, &input.Metadata, input.RequestTime, input.Config, out)
 	return err
 }
 
 // DecodeNestedTransaction uses the given decoder to create the input model,
-// then runs the defined validations on the input model
+// then runs the defined validations on the input model, unless input.SkipValidation is set,
 // and finally maps the values fom the input model to the given *model.Transaction instance
 //
 // DecodeNestedTransaction should be used when the stream in the decoder contains the `transaction` key
@@ -195,8 +210,13 @@ func DecodeNestedTransaction(d decoder.Decoder, input *modeldecoder.Input, out *
 	if err = d.Decode(root); err != nil && err != io.EOF {
 		return modeldecoder.NewDecoderErrFromJSONIter(err)
 	}
-	if err := root.validate(); err != nil {
-		return modeldecoder.NewValidationErr(err)
+	if !input.SkipValidation {
+		start := time.Now()
+		err := root.validate()
+		input.PhaseTimings.Add(utility.PhaseValidate, time.Since(start))
+		if err != nil {
+			return modeldecoder.NewValidationErr(err)
+		}
 	}
 	mapToTransactionModel(&root.Transaction, &input.Metadata, input.RequestTime, input.Config, out)
 	return err
@@ -867,7 +887,7 @@ func mapToSpanModel(from *span, metadata *model.Metadata, reqTime time.Time, con
 		out.Labels = from.Context.Tags.Clone()
 	}
 	if from.Duration.IsSet() {
-		out.Duration = from.Duration.Val
+		out.Duration = time.Duration(from.Duration.Val * float64(time.Millisecond))
 	}
 	if from.ID.IsSet() {
 		out.ID = from.ID.Val
@@ -1054,7 +1074,7 @@ func mapToTransactionModel(from *transaction, metadata *model.Metadata, reqTime
 		}
 	}
 	if from.Duration.IsSet() {
-		out.Duration = from.Duration.Val
+		out.Duration = time.Duration(from.Duration.Val * float64(time.Millisecond))
 	}
 	if from.ID.IsSet() {
 		out.ID = from.ID.Val
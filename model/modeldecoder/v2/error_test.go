@@ -75,6 +75,13 @@ func TestDecodeNestedError(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "validation")
 	})
+
+	t.Run("skip validation", func(t *testing.T) {
+		var out model.Error
+		input := modeldecoder.Input{SkipValidation: true}
+		err := DecodeNestedError(decoder.NewJSONDecoder(strings.NewReader(`{}`)), &input, &out)
+		require.NoError(t, err)
+	})
 }
 
 func TestDecodeMapToErrorModel(t *testing.T) {
@@ -50,6 +50,7 @@ func isUnmappedMetadataField(key string) bool {
 		"System.OSType",
 		"System.Type",
 		"UserAgent",
+		"UserAgent.Bot",
 		"UserAgent.Name",
 		"UserAgent.Original":
 		return true
@@ -63,6 +63,13 @@ func TestDecodeNestedMetricset(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "validation")
 	})
+
+	t.Run("skip validation", func(t *testing.T) {
+		var out model.Metricset
+		input := modeldecoder.Input{SkipValidation: true}
+		err := DecodeNestedMetricset(decoder.NewJSONDecoder(strings.NewReader(`{}`)), &input, &out)
+		require.NoError(t, err)
+	})
 }
 
 func TestDecodeMapToMetricsetModel(t *testing.T) {
@@ -85,8 +92,10 @@ func TestDecodeMapToMetricsetModel(t *testing.T) {
 			if strings.HasPrefix(key, "Metadata") ||
 				// only set by aggregator
 				strings.HasPrefix(key, "Event") ||
+				strings.HasPrefix(key, "Error") ||
 				key == "Name" ||
 				key == "TimeseriesInstanceID" ||
+				key == "DataStreamDataset" ||
 				key == "Transaction.Result" ||
 				key == "Transaction.Root" ||
 				strings.HasPrefix(key, "Span.DestinationService") ||
@@ -60,6 +60,13 @@ func TestDecodeNestedSpan(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "validation")
 	})
+
+	t.Run("skip validation", func(t *testing.T) {
+		var out model.Span
+		input := modeldecoder.Input{SkipValidation: true}
+		err := DecodeNestedSpan(decoder.NewJSONDecoder(strings.NewReader(`{}`)), &input, &out)
+		require.NoError(t, err)
+	})
 }
 
 func TestDecodeMapToSpanModel(t *testing.T) {
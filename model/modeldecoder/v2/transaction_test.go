@@ -76,6 +76,13 @@ func TestDecodeNestedTransaction(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "validation")
 	})
+
+	t.Run("skip validation", func(t *testing.T) {
+		var out model.Transaction
+		input := modeldecoder.Input{SkipValidation: true}
+		err := DecodeNestedTransaction(decoder.NewJSONDecoder(strings.NewReader(`{}`)), &input, &out)
+		require.NoError(t, err)
+	})
 }
 
 func TestDecodeMapToTransactionModel(t *testing.T) {
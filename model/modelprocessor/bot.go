@@ -0,0 +1,100 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modelprocessor
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+)
+
+// ClassifyBotTraffic is a model.BatchProcessor that classifies events
+// sent from bots, headless browsers, and synthetic monitoring tools
+// by their User-Agent, and either tags or drops them.
+type ClassifyBotTraffic struct {
+	// UserAgentPattern matches the User-Agent of bots.
+	UserAgentPattern *regexp.Regexp
+
+	// Drop controls whether events from bots are dropped. If Drop is
+	// false, events from bots are tagged by setting UserAgent.Bot.
+	Drop bool
+
+	// Dropped, if non-nil, is incremented by the number of events dropped.
+	Dropped *monitoring.Int
+}
+
+// ProcessBatch tags or drops events from bots.
+func (c *ClassifyBotTraffic) ProcessBatch(ctx context.Context, b *model.Batch) error {
+	if !c.Drop {
+		return MetadataProcessorFunc(c.tagBot).ProcessBatch(ctx, b)
+	}
+	n := b.Len()
+	transactions := b.Transactions[:0]
+	for _, event := range b.Transactions {
+		if !c.isBot(&event.Metadata) {
+			transactions = append(transactions, event)
+		}
+	}
+	b.Transactions = transactions
+	spans := b.Spans[:0]
+	for _, event := range b.Spans {
+		if !c.isBot(&event.Metadata) {
+			spans = append(spans, event)
+		}
+	}
+	b.Spans = spans
+	metricsets := b.Metricsets[:0]
+	for _, event := range b.Metricsets {
+		if !c.isBot(&event.Metadata) {
+			metricsets = append(metricsets, event)
+		}
+	}
+	b.Metricsets = metricsets
+	errors := b.Errors[:0]
+	for _, event := range b.Errors {
+		if !c.isBot(&event.Metadata) {
+			errors = append(errors, event)
+		}
+	}
+	b.Errors = errors
+	profiles := b.Profiles[:0]
+	for _, event := range b.Profiles {
+		if !c.isBot(&event.Metadata) {
+			profiles = append(profiles, event)
+		}
+	}
+	b.Profiles = profiles
+	if c.Dropped != nil {
+		c.Dropped.Add(int64(n - b.Len()))
+	}
+	return nil
+}
+
+func (c *ClassifyBotTraffic) tagBot(ctx context.Context, meta *model.Metadata) error {
+	if c.isBot(meta) {
+		meta.UserAgent.Bot = true
+	}
+	return nil
+}
+
+func (c *ClassifyBotTraffic) isBot(meta *model.Metadata) bool {
+	return meta.UserAgent.Original != "" && c.UserAgentPattern.MatchString(meta.UserAgent.Original)
+}
@@ -0,0 +1,70 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modelprocessor_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modelprocessor"
+)
+
+func TestClassifyBotTrafficTag(t *testing.T) {
+	processor := modelprocessor.ClassifyBotTraffic{UserAgentPattern: regexp.MustCompile("(?i)headless")}
+	testProcessBatchMetadata(t, &processor,
+		model.Metadata{UserAgent: model.UserAgent{Original: "Mozilla/5.0 HeadlessChrome/90.0"}},
+		model.Metadata{UserAgent: model.UserAgent{Original: "Mozilla/5.0 HeadlessChrome/90.0", Bot: true}},
+	)
+	testProcessBatchMetadata(t, &processor,
+		model.Metadata{UserAgent: model.UserAgent{Original: "Mozilla/5.0 Chrome/90.0"}},
+		model.Metadata{UserAgent: model.UserAgent{Original: "Mozilla/5.0 Chrome/90.0"}},
+	)
+	testProcessBatchMetadata(t, &processor, model.Metadata{}, model.Metadata{})
+}
+
+func TestClassifyBotTrafficDrop(t *testing.T) {
+	dropped := monitoring.NewInt(monitoring.NewRegistry(), "dropped")
+	processor := modelprocessor.ClassifyBotTraffic{UserAgentPattern: regexp.MustCompile("bot"), Drop: true, Dropped: dropped}
+	bot := model.Metadata{UserAgent: model.UserAgent{Original: "Googlebot/2.1"}}
+	browser := model.Metadata{UserAgent: model.UserAgent{Original: "Mozilla/5.0"}}
+
+	batch := &model.Batch{
+		Transactions: []*model.Transaction{{Metadata: bot, ID: "1"}, {Metadata: browser, ID: "2"}},
+		Spans:        []*model.Span{{Metadata: browser, ID: "3"}, {Metadata: bot, ID: "4"}},
+		Metricsets:   []*model.Metricset{{Metadata: bot}},
+		Errors:       []*model.Error{{Metadata: browser, ID: "5"}},
+		Profiles:     []*model.PprofProfile{{Metadata: bot}},
+	}
+	err := processor.ProcessBatch(context.Background(), batch)
+	require.NoError(t, err)
+	assert.Equal(t, &model.Batch{
+		Transactions: []*model.Transaction{{Metadata: browser, ID: "2"}},
+		Spans:        []*model.Span{{Metadata: browser, ID: "3"}},
+		Metricsets:   []*model.Metricset{},
+		Errors:       []*model.Error{{Metadata: browser, ID: "5"}},
+		Profiles:     []*model.PprofProfile{},
+	}, batch)
+	assert.Equal(t, int64(4), dropped.Get())
+}
@@ -0,0 +1,59 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modelprocessor
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/publish"
+)
+
+// LimitInFlightEvents is a model.BatchProcessor that limits the total
+// number of events being processed concurrently by Processor, i.e. the
+// number of events that have been decoded but not yet accepted by the
+// publisher.
+//
+// Batches which would take the number of in-flight events above MaxEvents
+// are rejected with publish.ErrFull. A batch is always accepted when there
+// are no other events in flight, so that batches larger than MaxEvents can
+// still be processed.
+type LimitInFlightEvents struct {
+	Processor model.BatchProcessor
+	MaxEvents int
+
+	inFlight int64
+}
+
+// ProcessBatch passes batch to p.Processor, if doing so would not exceed
+// the in-flight event limit, and otherwise returns publish.ErrFull.
+func (p *LimitInFlightEvents) ProcessBatch(ctx context.Context, batch *model.Batch) error {
+	n := int64(batch.Len())
+	if inFlight := atomic.AddInt64(&p.inFlight, n); inFlight > int64(p.MaxEvents) && inFlight != n {
+		atomic.AddInt64(&p.inFlight, -n)
+		return publish.ErrFull
+	}
+	defer atomic.AddInt64(&p.inFlight, -n)
+	return p.Processor.ProcessBatch(ctx, batch)
+}
+
+// InFlight returns the number of events currently being processed.
+func (p *LimitInFlightEvents) InFlight() int {
+	return int(atomic.LoadInt64(&p.inFlight))
+}
@@ -0,0 +1,73 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modelprocessor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modelprocessor"
+	"github.com/elastic/apm-server/publish"
+)
+
+func TestLimitInFlightEvents(t *testing.T) {
+	release := make(chan struct{})
+	processing := make(chan struct{})
+	limiter := &modelprocessor.LimitInFlightEvents{
+		MaxEvents: 3,
+		Processor: model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+			processing <- struct{}{}
+			<-release
+			return nil
+		}),
+	}
+	batchOf := func(n int) *model.Batch {
+		batch := &model.Batch{}
+		for i := 0; i < n; i++ {
+			batch.Transactions = append(batch.Transactions, &model.Transaction{})
+		}
+		return batch
+	}
+
+	// A batch larger than MaxEvents is accepted when nothing else is in flight.
+	errs := make(chan error, 2)
+	go func() { errs <- limiter.ProcessBatch(context.Background(), batchOf(5)) }()
+	<-processing
+	assert.Equal(t, 5, limiter.InFlight())
+	assert.Equal(t, publish.ErrFull, limiter.ProcessBatch(context.Background(), batchOf(1)))
+	release <- struct{}{}
+	require.NoError(t, <-errs)
+	assert.Equal(t, 0, limiter.InFlight())
+
+	// Batches are accepted until the limit would be exceeded.
+	go func() { errs <- limiter.ProcessBatch(context.Background(), batchOf(2)) }()
+	<-processing
+	go func() { errs <- limiter.ProcessBatch(context.Background(), batchOf(1)) }()
+	<-processing
+	assert.Equal(t, 3, limiter.InFlight())
+	assert.Equal(t, publish.ErrFull, limiter.ProcessBatch(context.Background(), batchOf(1)))
+	release <- struct{}{}
+	release <- struct{}{}
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+	assert.Equal(t, 0, limiter.InFlight())
+}
@@ -0,0 +1,186 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modelprocessor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/pseudonymize"
+)
+
+// Pseudonymize is a model.BatchProcessor which pseudonymizes model fields
+// according to rules. It runs before events are sampled, aggregated, or
+// stored, so raw identifiers are neither kept in the tail-sampling storage
+// nor published.
+type Pseudonymize struct {
+	processor *pseudonymize.Processor
+}
+
+// NewPseudonymize returns a Pseudonymize for the rules whose fields are
+// supported by the model, and a pseudonymize.Processor for the remaining
+// rules, whose fields are pseudonymized when events are published. Either
+// is nil if it has no rules.
+func NewPseudonymize(salt string, rules []pseudonymize.Rule) (*Pseudonymize, *pseudonymize.Processor) {
+	var modelRules, eventRules []pseudonymize.Rule
+	for _, rule := range rules {
+		if pseudonymizeModelSupported(rule) {
+			modelRules = append(modelRules, rule)
+		} else {
+			eventRules = append(eventRules, rule)
+		}
+	}
+	var modelProcessor *Pseudonymize
+	var eventProcessor *pseudonymize.Processor
+	if len(modelRules) > 0 {
+		modelProcessor = &Pseudonymize{processor: pseudonymize.NewProcessor(salt, modelRules)}
+	}
+	if len(eventRules) > 0 {
+		eventProcessor = pseudonymize.NewProcessor(salt, eventRules)
+	}
+	return modelProcessor, eventProcessor
+}
+
+// pseudonymizeModelSupported reports whether Pseudonymize supports rule.
+// Cookies can only be removed in the model.
+//
+// Hash rules for client.ip and source.ip are rejected by the configuration,
+// as the model holds client IP addresses as net.IPs.
+func pseudonymizeModelSupported(rule pseudonymize.Rule) bool {
+	switch rule.Field {
+	case "user.id", "user.email", "user.name", "user.domain", "http.request.socket.remote_address",
+		"client.ip", "source.ip":
+		return true
+	case "http.request.cookies":
+		return rule.Method == pseudonymize.MethodRemove
+	}
+	return strings.HasPrefix(rule.Field, pseudonymize.HeadersPrefix)
+}
+
+// ProcessBatch pseudonymizes the fields of the events in b.
+func (p *Pseudonymize) ProcessBatch(ctx context.Context, b *model.Batch) error {
+	for _, event := range b.Transactions {
+		p.processMetadata(&event.Metadata)
+		p.processHTTP(event.HTTP)
+	}
+	for _, event := range b.Errors {
+		p.processMetadata(&event.Metadata)
+		p.processHTTP(event.HTTP)
+	}
+	for _, event := range b.Spans {
+		p.processMetadata(&event.Metadata)
+	}
+	for _, event := range b.Metricsets {
+		p.processMetadata(&event.Metadata)
+	}
+	for _, event := range b.Profiles {
+		p.processMetadata(&event.Metadata)
+	}
+	return nil
+}
+
+func (p *Pseudonymize) String() string {
+	return p.processor.String()
+}
+
+func (p *Pseudonymize) processMetadata(meta *model.Metadata) {
+	for _, rule := range p.processor.Rules() {
+		switch rule.Field {
+		case "user.id":
+			p.processString(rule, &meta.User.ID)
+		case "user.email":
+			p.processString(rule, &meta.User.Email)
+		case "user.name":
+			p.processString(rule, &meta.User.Name)
+		case "user.domain":
+			p.processString(rule, &meta.User.Domain)
+		case "client.ip", "source.ip":
+			// source.ip is published from the client IP address.
+			if meta.Client.IP == nil {
+				continue
+			}
+			truncated, ok := p.processor.Apply(rule, meta.Client.IP.String())
+			if !ok {
+				meta.Client.IP = nil
+				continue
+			}
+			ip := net.ParseIP(truncated.(string))
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+			}
+			meta.Client.IP = ip
+		}
+	}
+}
+
+func (p *Pseudonymize) processHTTP(h *model.Http) {
+	if h == nil || h.Request == nil {
+		return
+	}
+	req := h.Request
+	for _, rule := range p.processor.Rules() {
+		switch {
+		case rule.Field == "http.request.socket.remote_address":
+			if req.Socket != nil {
+				p.processString(rule, &req.Socket.RemoteAddress)
+			}
+		case rule.Field == "http.request.cookies":
+			if req.Cookies != nil {
+				p.processor.Apply(rule, req.Cookies)
+				req.Cookies = nil
+			}
+		case strings.HasPrefix(rule.Field, pseudonymize.HeadersPrefix):
+			p.processHeader(rule, req.Headers)
+		}
+	}
+}
+
+// processString pseudonymizes the non-empty string value according to
+// rule, clearing it if the field should be removed.
+func (p *Pseudonymize) processString(rule pseudonymize.Rule, value *string) {
+	if *value == "" {
+		return
+	}
+	pseudonymized, ok := p.processor.Apply(rule, *value)
+	if !ok {
+		*value = ""
+		return
+	}
+	*value = pseudonymized.(string)
+}
+
+// processHeader pseudonymizes the values of the header of rule, matching
+// its name case-insensitively, as headers are not canonicalized by all
+// agents.
+func (p *Pseudonymize) processHeader(rule pseudonymize.Rule, headers http.Header) {
+	name := rule.Field[len(pseudonymize.HeadersPrefix):]
+	for key, values := range headers {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		pseudonymized, ok := p.processor.Apply(rule, values)
+		if !ok {
+			delete(headers, key)
+			continue
+		}
+		headers[key] = pseudonymized.([]string)
+	}
+}
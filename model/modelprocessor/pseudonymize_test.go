@@ -0,0 +1,97 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modelprocessor_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modelprocessor"
+	"github.com/elastic/apm-server/pseudonymize"
+)
+
+func TestNewPseudonymize(t *testing.T) {
+	modelProcessor, eventProcessor := modelprocessor.NewPseudonymize("secret", []pseudonymize.Rule{
+		{Field: "user.id", Method: pseudonymize.MethodHash},
+		{Field: "client.ip", Method: pseudonymize.MethodTruncate},
+		{Field: "http.request.cookies", Method: pseudonymize.MethodHash},
+		{Field: "labels.customer", Method: pseudonymize.MethodHash},
+	})
+	assert.Equal(t, "pseudonymize=[user.id:hash,client.ip:truncate]", modelProcessor.String())
+	assert.Equal(t, "pseudonymize=[http.request.cookies:hash,labels.customer:hash]", eventProcessor.String())
+
+	modelProcessor, eventProcessor = modelprocessor.NewPseudonymize("secret", pseudonymize.DefaultRules())
+	assert.NotNil(t, modelProcessor)
+	assert.Nil(t, eventProcessor)
+}
+
+func TestPseudonymize(t *testing.T) {
+	modelProcessor, _ := modelprocessor.NewPseudonymize("secret", pseudonymize.DefaultRules())
+	metadata := model.Metadata{
+		User:   model.User{ID: "123", Email: "jane@example.com", Name: "jane"},
+		Client: model.Client{IP: net.ParseIP("192.168.1.23")},
+	}
+	request := &model.Req{
+		Method: "GET",
+		Socket: &model.Socket{RemoteAddress: "[2001:db8:85a3:8d3:1319:8a2e:370:7348]:1234"},
+		Headers: http.Header{
+			"x-forwarded-for": []string{"192.168.1.23"},
+			"Forwarded":       []string{"for=192.168.1.23"},
+			"Cookie":          []string{"c=1"},
+			"User-Agent":      []string{"curl"},
+		},
+		Cookies: common.MapStr{"c": "1"},
+	}
+	batch := &model.Batch{
+		Transactions: []*model.Transaction{{Metadata: metadata, HTTP: &model.Http{Request: request}}},
+		Spans:        []*model.Span{{Metadata: metadata}},
+	}
+	require.NoError(t, modelProcessor.ProcessBatch(context.Background(), batch))
+
+	expectedMetadata := model.Metadata{
+		User:   model.User{ID: hashPseudonymized("secret", "123"), Email: hashPseudonymized("secret", "jane@example.com"), Name: "jane"},
+		Client: model.Client{IP: net.ParseIP("192.168.1.0").To4()},
+	}
+	assert.Equal(t, expectedMetadata, batch.Transactions[0].Metadata)
+	assert.Equal(t, expectedMetadata, batch.Spans[0].Metadata)
+	assert.Equal(t, &model.Req{
+		Method: "GET",
+		Socket: &model.Socket{RemoteAddress: "2001:db8:85a3::"},
+		Headers: http.Header{
+			"x-forwarded-for": []string{"192.168.1.0"},
+			"User-Agent":      []string{"curl"},
+		},
+	}, batch.Transactions[0].HTTP.Request)
+}
+
+func hashPseudonymized(salt, value string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
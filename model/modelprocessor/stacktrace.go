@@ -0,0 +1,43 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modelprocessor
+
+import (
+	"context"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/transform"
+)
+
+// ApplyStacktraceConfig is a model.BatchProcessor that source maps RUM error
+// stacktraces and applies the RUM frame patterns, so that error grouping keys
+// computed before events are transformed, e.g. for aggregation, match those
+// recorded in error documents.
+type ApplyStacktraceConfig struct {
+	// Config holds the transform configuration, including the sourcemap
+	// store and frame patterns, which is used for publishing events.
+	Config *transform.Config
+}
+
+// ProcessBatch applies the stacktrace configuration to RUM errors.
+func (p ApplyStacktraceConfig) ProcessBatch(ctx context.Context, b *model.Batch) error {
+	for _, e := range b.Errors {
+		e.ApplyStacktraceConfig(ctx, p.Config)
+	}
+	return nil
+}
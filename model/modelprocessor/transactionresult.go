@@ -0,0 +1,111 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modelprocessor
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/apm-server/model"
+)
+
+var httpStatusResultRegexp = regexp.MustCompile(`(?i)^(?:HTTP\s*)?([1-5])(?:\d\d|xx)$`)
+
+// grpcStatusResults maps gRPC status codes, and their names lower-cased
+// without underscores, to the canonical status code names.
+var grpcStatusResults = make(map[string]string)
+
+func init() {
+	for code, name := range []string{
+		"OK", "CANCELLED", "UNKNOWN", "INVALID_ARGUMENT", "DEADLINE_EXCEEDED",
+		"NOT_FOUND", "ALREADY_EXISTS", "PERMISSION_DENIED", "RESOURCE_EXHAUSTED",
+		"FAILED_PRECONDITION", "ABORTED", "OUT_OF_RANGE", "UNIMPLEMENTED",
+		"INTERNAL", "UNAVAILABLE", "DATA_LOSS", "UNAUTHENTICATED",
+	} {
+		grpcStatusResults[strconv.Itoa(code)] = name
+		grpcStatusResults[grpcStatusResultKey(name)] = name
+	}
+	// Go's codes.Canceled uses the American spelling.
+	grpcStatusResults["canceled"] = "CANCELLED"
+}
+
+func grpcStatusResultKey(result string) string {
+	return strings.ToLower(strings.Replace(result, "_", "", -1))
+}
+
+// TransactionResultRule holds a rule for normalizing transaction.result values.
+type TransactionResultRule struct {
+	// Match matches the transaction.result values to which the rule applies.
+	Match *regexp.Regexp
+
+	// Result holds the normalized value, which may refer to submatches
+	// of Match as described for regexp.Regexp.Expand, e.g. "${1}".
+	Result string
+}
+
+// NormalizeTransactionResult is a model.BatchProcessor that normalizes the
+// transaction.result values of transactions and metricsets, so that agents
+// reporting the same result differently produce the same value.
+type NormalizeTransactionResult struct {
+	// Rules holds custom normalization rules, applied in order before
+	// the built-in normalizations. Only the first matching rule applies.
+	Rules []TransactionResultRule
+
+	// HTTP enables normalizing HTTP status codes to their class,
+	// e.g. "200" and "HTTP 200" to "HTTP 2xx".
+	HTTP bool
+
+	// GRPC enables normalizing gRPC status codes to their canonical names,
+	// e.g. "14" and "Unavailable" to "UNAVAILABLE".
+	GRPC bool
+}
+
+// ProcessBatch normalizes the transaction.result values of transactions and metricsets.
+func (n *NormalizeTransactionResult) ProcessBatch(ctx context.Context, b *model.Batch) error {
+	for _, tx := range b.Transactions {
+		tx.Result = n.normalize(tx.Result)
+	}
+	for _, ms := range b.Metricsets {
+		ms.Transaction.Result = n.normalize(ms.Transaction.Result)
+	}
+	return nil
+}
+
+func (n *NormalizeTransactionResult) normalize(result string) string {
+	if result == "" {
+		return result
+	}
+	for _, rule := range n.Rules {
+		if submatches := rule.Match.FindStringSubmatchIndex(result); submatches != nil {
+			return string(rule.Match.ExpandString(nil, rule.Result, result, submatches))
+		}
+	}
+	if n.HTTP {
+		if submatches := httpStatusResultRegexp.FindStringSubmatch(result); submatches != nil {
+			return "HTTP " + submatches[1] + "xx"
+		}
+	}
+	if n.GRPC {
+		if name, ok := grpcStatusResults[grpcStatusResultKey(result)]; ok {
+			return name
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package modelprocessor_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modelprocessor"
+)
+
+func TestNormalizeTransactionResult(t *testing.T) {
+	processor := &modelprocessor.NormalizeTransactionResult{
+		Rules: []modelprocessor.TransactionResultRule{{
+			Match:  regexp.MustCompile(`(?i)^success(ful)?$`),
+			Result: "success",
+		}, {
+			Match:  regexp.MustCompile(`^HTTP (\d)\d\d \w+$`),
+			Result: "HTTP ${1}xx",
+		}},
+		HTTP: true,
+		GRPC: true,
+	}
+
+	for in, out := range map[string]string{
+		"":                 "",
+		"200":              "HTTP 2xx",
+		"HTTP 404":         "HTTP 4xx",
+		"http 5XX":         "HTTP 5xx",
+		"HTTP 2xx":         "HTTP 2xx",
+		"600":              "600",
+		"HTTP 302 Found":   "HTTP 3xx",
+		"0":                "OK",
+		"14":               "UNAVAILABLE",
+		"Unavailable":      "UNAVAILABLE",
+		"DeadlineExceeded": "DEADLINE_EXCEEDED",
+		"Canceled":         "CANCELLED",
+		"NOT_FOUND":        "NOT_FOUND",
+		"17":               "17",
+		"Successful":       "success",
+		"unchanged":        "unchanged",
+	} {
+		batch := &model.Batch{
+			Transactions: []*model.Transaction{{Result: in}},
+			Metricsets:   []*model.Metricset{{Transaction: model.MetricsetTransaction{Result: in}}},
+		}
+		require.NoError(t, processor.ProcessBatch(context.Background(), batch))
+		assert.Equal(t, out, batch.Transactions[0].Result, in)
+		assert.Equal(t, out, batch.Metricsets[0].Transaction.Result, in)
+	}
+}
+
+func TestNormalizeTransactionResultDisabledBuiltins(t *testing.T) {
+	processor := &modelprocessor.NormalizeTransactionResult{}
+	batch := &model.Batch{Transactions: []*model.Transaction{{Result: "200"}, {Result: "14"}}}
+	require.NoError(t, processor.ProcessBatch(context.Background(), batch))
+	assert.Equal(t, "200", batch.Transactions[0].Result)
+	assert.Equal(t, "14", batch.Transactions[1].Result)
+}
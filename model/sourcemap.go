@@ -19,9 +19,12 @@ package model
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/beat/events"
 	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/logp"
 	"github.com/elastic/beats/v7/libbeat/monitoring"
@@ -61,16 +64,32 @@ func (pa *Sourcemap) Transform(ctx context.Context, cfg *transform.Config) []bea
 		cfg.RUM.SourcemapStore.Added(ctx, pa.ServiceName, pa.ServiceVersion, pa.BundleFilepath)
 	}
 
+	bundleFilepath := utility.UrlPath(pa.BundleFilepath)
 	ev := beat.Event{
 		Fields: common.MapStr{
 			"processor": sourcemapProcessorEntry,
 			sourcemapDocType: common.MapStr{
-				"bundle_filepath": utility.UrlPath(pa.BundleFilepath),
+				"bundle_filepath": bundleFilepath,
 				"service":         common.MapStr{"name": pa.ServiceName, "version": pa.ServiceVersion},
 				"sourcemap":       pa.Sourcemap,
 			},
 		},
+		// Uploads of different sourcemaps for the same bundle are stored
+		// as separate versions, while retried uploads of the same sourcemap
+		// are deduplicated by their document ID.
+		Meta:      common.MapStr{events.FieldMetaID: pa.documentID(bundleFilepath)},
 		Timestamp: time.Now(),
 	}
 	return []beat.Event{ev}
 }
+
+// documentID returns a document ID derived from the sourcemap's service,
+// bundle file path, and content.
+func (pa *Sourcemap) documentID(bundleFilepath string) string {
+	h := sha256.New()
+	for _, s := range []string{pa.ServiceName, pa.ServiceVersion, bundleFilepath, pa.Sourcemap} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -66,6 +66,31 @@ func TestTransform(t *testing.T) {
 	assert.Equal(t, "mysmap", getStr(output, "sourcemap"))
 }
 
+func TestTransformDocumentID(t *testing.T) {
+	documentID := func(p model.Sourcemap) interface{} {
+		events := p.Transform(context.Background(), &transform.Config{})
+		require.Len(t, events, 1)
+		return events[0].Meta["_id"]
+	}
+	p := model.Sourcemap{
+		ServiceName:    "myService",
+		ServiceVersion: "1.0",
+		BundleFilepath: "http://localhost/my/path",
+		Sourcemap:      "mysmap",
+	}
+	id := documentID(p)
+	assert.NotEmpty(t, id)
+
+	// retried uploads of the same sourcemap have the same ID,
+	// regardless of the bundle URL's host
+	p.BundleFilepath = "http://example.com/my/path"
+	assert.Equal(t, id, documentID(p))
+
+	// new versions have a different ID
+	p.Sourcemap = "mysmap2"
+	assert.NotEqual(t, id, documentID(p))
+}
+
 func TestParseSourcemaps(t *testing.T) {
 	fileBytes, err := loader.LoadDataAsBytes("../testdata/sourcemap/bundle.js.map")
 	assert.NoError(t, err)
@@ -86,7 +111,7 @@ func TestInvalidateCache(t *testing.T) {
 		// create sourcemap store
 		client, err := estest.NewElasticsearchClient(estest.NewTransport(t, http.StatusOK, nil))
 		require.NoError(t, err)
-		store, err := sourcemap.NewStore(client, "foo", time.Minute)
+		store, err := sourcemap.NewStore(client, "foo", time.Minute, sourcemap.VersionSelectionLatest)
 		require.NoError(t, err)
 
 		// transform with sourcemap store
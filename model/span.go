@@ -58,7 +58,7 @@ type Span struct {
 	Name       string
 	Outcome    string
 	Start      *float64
-	Duration   float64
+	Duration   time.Duration
 	Stacktrace Stacktrace
 	Sync       *bool
 	Labels     common.MapStr
@@ -229,6 +229,7 @@ func (e *Span) appendBeatEvents(ctx context.Context, cfg *transform.Config, even
 	fields.maybeSetMapStr("destination", e.Destination.fields())
 
 	common.MapStr(fields).Put("event.outcome", e.Outcome)
+	common.MapStr(fields).Put("event.duration", e.Duration.Nanoseconds())
 
 	return append(events, beat.Event{
 		Fields:    common.MapStr(fields),
@@ -250,7 +251,7 @@ func (e *Span) fields(ctx context.Context, cfg *transform.Config) common.MapStr
 	if e.Start != nil {
 		fields.set("start", utility.MillisAsMicros(*e.Start))
 	}
-	fields.set("duration", utility.MillisAsMicros(e.Duration))
+	fields.set("duration", utility.DurationAsMicros(e.Duration))
 
 	fields.maybeSetMapStr("db", e.DB.fields())
 	fields.maybeSetMapStr("http", e.HTTP.fields())
@@ -261,7 +262,7 @@ func (e *Span) fields(ctx context.Context, cfg *transform.Config) common.MapStr
 
 	// TODO(axw) we should be using a merged service object, combining
 	// the stream metadata and event-specific service info.
-	if st := e.Stacktrace.transform(ctx, cfg, e.RUM, &e.Metadata.Service); len(st) > 0 {
+	if st := e.Stacktrace.transform(ctx, cfg, e.RUM, &e.Metadata.Service, e.Timestamp); len(st) > 0 {
 		fields.set("stacktrace", st)
 	}
 	return common.MapStr(fields)
@@ -66,7 +66,7 @@ func TestSpanTransform(t *testing.T) {
 					"name":     "",
 					"type":     "",
 				},
-				"event":     common.MapStr{"outcome": ""},
+				"event":     common.MapStr{"outcome": "", "duration": int64(0)},
 				"labels":    common.MapStr{"label_a": "a", "label_b": "b", "c": 1},
 				"timestamp": common.MapStr{"us": timestampUs},
 			},
@@ -86,7 +86,7 @@ func TestSpanTransform(t *testing.T) {
 				},
 				"timestamp": common.MapStr{"us": timestampUs},
 				"labels":    common.MapStr{"label_a": "a", "label_b": "b", "c": 1},
-				"event":     common.MapStr{"outcome": "success"},
+				"event":     common.MapStr{"outcome": "success", "duration": int64(0)},
 			},
 		},
 		{
@@ -104,7 +104,7 @@ func TestSpanTransform(t *testing.T) {
 				Start:               &start,
 				Outcome:             "unknown",
 				RepresentativeCount: 5,
-				Duration:            1.20,
+				Duration:            1200 * time.Microsecond,
 				RUM:                 true,
 				Stacktrace:          Stacktrace{{AbsPath: path}},
 				Labels:              common.MapStr{"label_a": 12},
@@ -169,7 +169,7 @@ func TestSpanTransform(t *testing.T) {
 				"trace":       common.MapStr{"id": traceID},
 				"parent":      common.MapStr{"id": parentID},
 				"destination": common.MapStr{"address": address, "ip": address, "port": port},
-				"event":       common.MapStr{"outcome": "unknown"},
+				"event":       common.MapStr{"outcome": "unknown", "duration": int64(1200000)},
 			},
 		},
 	}
@@ -19,6 +19,7 @@ package model
 
 import (
 	"context"
+	"time"
 
 	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/logp"
@@ -30,10 +31,38 @@ import (
 
 type Stacktrace []*StacktraceFrame
 
-func (st *Stacktrace) transform(ctx context.Context, cfg *transform.Config, rum bool, service *Service) []common.MapStr {
-	if st == nil {
+// transform returns the stacktrace's fields. For RUM stacktraces, sourcemaps are
+// applied, selecting the sourcemap version by the event's timestamp if configured.
+func (st *Stacktrace) transform(ctx context.Context, cfg *transform.Config, rum bool, service *Service, timestamp time.Time) []common.MapStr {
+	if st == nil || len(*st) == 0 {
 		return nil
 	}
+	st.applyConfig(ctx, cfg, rum, service, timestamp)
+	frames := make([]common.MapStr, len(*st))
+	for idx, fr := range *st {
+		frames[idx] = fr.transform(cfg, rum)
+	}
+	return frames
+}
+
+// applyConfig applies source mapping and the RUM library and
+// exclude_from_grouping frame patterns in cfg to the stacktrace's frames.
+//
+// The frames are updated in place, and frames are not source mapped again
+// once a sourcemap store has been consulted for them. This allows the
+// configuration to be applied before the stacktrace is transformed, e.g.
+// for computing error grouping keys, without mapping the frames twice.
+func (st Stacktrace) applyConfig(ctx context.Context, cfg *transform.Config, rum bool, service *Service, timestamp time.Time) {
+	if !rum {
+		return
+	}
+	st.applySourcemaps(ctx, cfg, service, timestamp)
+	for _, fr := range st {
+		fr.applyConfig(cfg)
+	}
+}
+
+func (st Stacktrace) applySourcemaps(ctx context.Context, cfg *transform.Config, service *Service, timestamp time.Time) {
 	// source map algorithm:
 	// apply source mapping frame by frame
 	// if no source map could be found, set updated to false and set sourcemap error
@@ -51,43 +80,30 @@ func (st *Stacktrace) transform(ctx context.Context, cfg *transform.Config, rum
 	// - abs_path is set to the cleaned abs_path
 	// - sourcmeap.updated is set to true
 
-	if !rum || cfg.RUM.SourcemapStore == nil {
-		return st.transformFrames(cfg, rum, noSourcemapping)
+	if cfg.RUM.SourcemapStore == nil {
+		return
+	}
+	for _, fr := range st {
+		if fr.SourcemapUpdated != nil {
+			return
+		}
 	}
 	if service == nil || service.Name == "" || service.Version == "" {
-		return st.transformFrames(cfg, rum, noSourcemapping)
+		return
 	}
 
 	var errMsg string
 	var sourcemapErrorSet = map[string]interface{}{}
 	logger := logp.NewLogger(logs.Stacktrace)
 	fct := "<anonymous>"
-	return st.transformFrames(cfg, rum, func(frame *StacktraceFrame) {
-		fct, errMsg = frame.applySourcemap(ctx, cfg.RUM.SourcemapStore, service, fct)
+	for idx := len(st) - 1; idx >= 0; idx-- {
+		fct, errMsg = st[idx].applySourcemap(ctx, cfg.RUM.SourcemapStore, service, timestamp, fct)
 		if errMsg == "" || !logger.IsDebug() {
-			return
+			continue
 		}
 		if _, ok := sourcemapErrorSet[errMsg]; !ok {
 			logger.Debug(errMsg)
 			sourcemapErrorSet[errMsg] = nil
 		}
-	})
-}
-
-func (st *Stacktrace) transformFrames(cfg *transform.Config, rum bool, apply func(*StacktraceFrame)) []common.MapStr {
-	frameCount := len(*st)
-	if frameCount == 0 {
-		return nil
 	}
-
-	var fr *StacktraceFrame
-	frames := make([]common.MapStr, frameCount)
-	for idx := frameCount - 1; idx >= 0; idx-- {
-		fr = (*st)[idx]
-		apply(fr)
-		frames[idx] = fr.transform(cfg, rum)
-	}
-	return frames
 }
-
-func noSourcemapping(_ *StacktraceFrame) {}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/elastic/beats/v7/libbeat/common"
 
@@ -54,6 +55,8 @@ type StacktraceFrame struct {
 	SourcemapUpdated *bool
 	SourcemapError   string
 	Original         Original
+
+	configApplied bool
 }
 
 type Original struct {
@@ -77,16 +80,12 @@ func (s *StacktraceFrame) transform(cfg *transform.Config, rum bool) common.MapS
 	m.maybeSetString("function", s.Function)
 	m.maybeSetMapStr("vars", s.Vars)
 
-	if rum && cfg.RUM.LibraryPattern != nil {
-		s.setLibraryFrame(cfg.RUM.LibraryPattern)
+	if rum {
+		s.applyConfig(cfg)
 	}
 	if s.LibraryFrame != nil {
 		m.set("library_frame", *s.LibraryFrame)
 	}
-
-	if rum && cfg.RUM.ExcludeFromGrouping != nil {
-		s.setExcludeFromGrouping(cfg.RUM.ExcludeFromGrouping)
-	}
 	m.set("exclude_from_grouping", s.ExcludeFromGrouping)
 
 	var context mapStr
@@ -132,6 +131,21 @@ func (s *StacktraceFrame) IsSourcemapApplied() bool {
 	return s.SourcemapUpdated != nil && *s.SourcemapUpdated
 }
 
+// applyConfig sets the library_frame and exclude_from_grouping flags
+// according to the RUM frame patterns in cfg, if not already set.
+func (s *StacktraceFrame) applyConfig(cfg *transform.Config) {
+	if s.configApplied {
+		return
+	}
+	s.configApplied = true
+	if cfg.RUM.LibraryPattern != nil {
+		s.setLibraryFrame(cfg.RUM.LibraryPattern)
+	}
+	if cfg.RUM.ExcludeFromGrouping != nil {
+		s.setExcludeFromGrouping(cfg.RUM.ExcludeFromGrouping)
+	}
+}
+
 func (s *StacktraceFrame) setExcludeFromGrouping(pattern *regexp.Regexp) {
 	s.ExcludeFromGrouping = s.Filename != "" && pattern.MatchString(s.Filename)
 }
@@ -143,7 +157,7 @@ func (s *StacktraceFrame) setLibraryFrame(pattern *regexp.Regexp) {
 	s.LibraryFrame = &libraryFrame
 }
 
-func (s *StacktraceFrame) applySourcemap(ctx context.Context, store *sourcemap.Store, service *Service, prevFunction string) (function string, errMsg string) {
+func (s *StacktraceFrame) applySourcemap(ctx context.Context, store *sourcemap.Store, service *Service, timestamp time.Time, prevFunction string) (function string, errMsg string) {
 	function = prevFunction
 
 	var valid bool
@@ -155,7 +169,7 @@ func (s *StacktraceFrame) applySourcemap(ctx context.Context, store *sourcemap.S
 	s.setOriginalSourcemapData()
 
 	path := utility.CleanUrlPath(s.Original.AbsPath)
-	mapper, err := store.Fetch(ctx, service.Name, service.Version, path)
+	mapper, err := store.FetchAt(ctx, service.Name, service.Version, path, timestamp)
 	if err != nil {
 		errMsg = err.Error()
 		return
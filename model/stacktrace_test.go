@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -110,7 +111,7 @@ func TestStacktraceTransform(t *testing.T) {
 	}
 
 	for idx, test := range tests {
-		output := test.Stacktrace.transform(context.Background(), &transform.Config{}, false, &service)
+		output := test.Stacktrace.transform(context.Background(), &transform.Config{}, false, &service, time.Time{})
 		assert.Equal(t, test.Output, output, fmt.Sprintf("Failed at idx %v; %s", idx, test.Msg))
 	}
 }
@@ -259,8 +260,8 @@ func TestStacktraceTransformWithSourcemapping(t *testing.T) {
 			}
 
 			// run `Stacktrace.transform` twice to ensure method is idempotent
-			tc.Stacktrace.transform(context.Background(), cfg, true, &service)
-			output := tc.Stacktrace.transform(context.Background(), cfg, true, &service)
+			tc.Stacktrace.transform(context.Background(), cfg, true, &service, time.Time{})
+			output := tc.Stacktrace.transform(context.Background(), cfg, true, &service, time.Time{})
 			assert.Equal(t, tc.Output, output)
 		})
 	}
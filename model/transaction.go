@@ -55,7 +55,7 @@ type Transaction struct {
 	Name           string
 	Result         string
 	Outcome        string
-	Duration       float64
+	Duration       time.Duration
 	Marks          TransactionMarks
 	Message        *Message
 	Sampled        *bool
@@ -73,7 +73,9 @@ type Transaction struct {
 	// RepresentativeCount holds the approximate number of
 	// transactions that this transaction represents for aggregation.
 	//
-	// This may be used for scaling metrics; it is not indexed.
+	// This may be used for scaling metrics. It is indexed for sampled
+	// transactions, so queries over transaction documents can account
+	// for head-based sampling.
 	RepresentativeCount float64
 }
 
@@ -97,7 +99,7 @@ func (e *Transaction) fields() common.MapStr {
 	var fields mapStr
 	fields.set("id", e.ID)
 	fields.set("type", e.Type)
-	fields.set("duration", utility.MillisAsMicros(e.Duration))
+	fields.set("duration", utility.DurationAsMicros(e.Duration))
 	fields.maybeSetString("name", e.Name)
 	fields.maybeSetString("result", e.Result)
 	fields.maybeSetMapStr("marks", e.Marks.fields())
@@ -117,7 +119,11 @@ func (e *Transaction) fields() common.MapStr {
 	}
 	// TODO(axw) change Sampled to be non-pointer, and set its final value when
 	// instantiating the model type.
-	fields.set("sampled", e.Sampled == nil || *e.Sampled)
+	sampled := e.Sampled == nil || *e.Sampled
+	fields.set("sampled", sampled)
+	if sampled && e.RepresentativeCount > 0 {
+		fields.set("representative_count", e.RepresentativeCount)
+	}
 	return common.MapStr(fields)
 }
 
@@ -156,6 +162,7 @@ func (e *Transaction) appendBeatEvents(cfg *transform.Config, events []beat.Even
 		fields.set("experimental", e.Experimental)
 	}
 	common.MapStr(fields).Put("event.outcome", e.Outcome)
+	common.MapStr(fields).Put("event.duration", e.Duration.Nanoseconds())
 
 	return append(events, beat.Event{
 		Timestamp: e.Timestamp,
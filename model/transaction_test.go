@@ -58,7 +58,7 @@ func TestTransactionTransform(t *testing.T) {
 			Transaction: Transaction{
 				ID:       id,
 				Type:     "tx",
-				Duration: 65.98,
+				Duration: 65980 * time.Microsecond,
 			},
 			Output: common.MapStr{
 				"id":       id,
@@ -72,7 +72,7 @@ func TestTransactionTransform(t *testing.T) {
 			Transaction: Transaction{
 				ID:        id,
 				Type:      "tx",
-				Duration:  65.98,
+				Duration:  65980 * time.Microsecond,
 				SpanCount: SpanCount{Started: &startedSpans},
 			},
 			Output: common.MapStr{
@@ -88,7 +88,7 @@ func TestTransactionTransform(t *testing.T) {
 			Transaction: Transaction{
 				ID:        id,
 				Type:      "tx",
-				Duration:  65.98,
+				Duration:  65980 * time.Microsecond,
 				SpanCount: SpanCount{Dropped: &dropped},
 			},
 			Output: common.MapStr{
@@ -107,7 +107,7 @@ func TestTransactionTransform(t *testing.T) {
 				Type:      "tx",
 				Result:    result,
 				Timestamp: time.Now(),
-				Duration:  65.98,
+				Duration:  65980 * time.Microsecond,
 				Sampled:   &sampled,
 				SpanCount: SpanCount{Started: &startedSpans, Dropped: &dropped},
 			},
@@ -134,7 +134,18 @@ func TestTransactionTransformOutcome(t *testing.T) {
 	tx := Transaction{Outcome: "success"}
 	events := tx.appendBeatEvents(&transform.Config{}, nil)
 	require.Len(t, events, 1)
-	assert.Equal(t, common.MapStr{"outcome": "success"}, events[0].Fields["event"])
+	assert.Equal(t, common.MapStr{"outcome": "success", "duration": int64(0)}, events[0].Fields["event"])
+}
+
+func TestTransactionTransformDuration(t *testing.T) {
+	// Durations are recorded with nanosecond precision in event.duration,
+	// and truncated to microseconds in transaction.duration.us.
+	tx := Transaction{Duration: 1500 * time.Nanosecond}
+	events := tx.appendBeatEvents(&transform.Config{}, nil)
+	require.Len(t, events, 1)
+	fields := events[0].Fields
+	assert.Equal(t, int64(1500), fields["event"].(common.MapStr)["duration"])
+	assert.Equal(t, common.MapStr{"us": 1}, fields["transaction"].(common.MapStr)["duration"])
 }
 
 func TestEventsTransformWithMetadata(t *testing.T) {
@@ -214,7 +225,7 @@ func TestEventsTransformWithMetadata(t *testing.T) {
 			},
 			"message": common.MapStr{"queue": common.MapStr{"name": "routeUser"}},
 		},
-		"event":  common.MapStr{"outcome": ""},
+		"event":  common.MapStr{"outcome": "", "duration": int64(0)},
 		"labels": common.MapStr{"a": "b"},
 		"url":    common.MapStr{"original": url},
 		"http": common.MapStr{
@@ -254,7 +265,7 @@ func TestTransactionTransformPage(t *testing.T) {
 				ID:        id,
 				Type:      "tx",
 				Timestamp: time.Now(),
-				Duration:  65.98,
+				Duration:  65980 * time.Microsecond,
 				URL:       ParseURL("https://localhost:8200/", "", ""),
 				Page: &Page{
 					URL: ParseURL(urlExample, "", ""),
@@ -29,11 +29,18 @@ type UserAgent struct {
 	// If Original is set, then this should typically not be set, as the full
 	// User-Agent string can be parsed by ingest node.
 	Name string
+
+	// Bot reports whether the User-Agent was classified as a bot,
+	// headless browser, or synthetic monitoring tool.
+	Bot bool
 }
 
 func (u *UserAgent) fields() common.MapStr {
 	var fields mapStr
 	fields.maybeSetString("original", u.Original)
 	fields.maybeSetString("name", u.Name)
+	if u.Bot {
+		fields.set("bot", true)
+	}
 	return common.MapStr(fields)
 }
@@ -38,6 +38,9 @@ func TestUserAgentFields(t *testing.T) {
 	}, {
 		UserAgent: UserAgent{Name: "mosaic"},
 		Output:    common.MapStr{"name": "mosaic"},
+	}, {
+		UserAgent: UserAgent{Original: "Googlebot/2.1", Bot: true},
+		Output:    common.MapStr{"original": "Googlebot/2.1", "bot": true},
 	}}
 
 	for _, test := range tests {
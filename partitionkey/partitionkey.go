@@ -0,0 +1,96 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package partitionkey provides a beat.Processor for setting the partition
+// key of events published to streaming outputs, such as Kafka, so that all
+// events of a trace are written to the same partition.
+package partitionkey
+
+import (
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+const (
+	// MetaField holds the name of the @metadata field holding events'
+	// partition keys.
+	MetaField = "partition_key"
+
+	// KeyFormat holds the event format string which outputs must use as
+	// their message key, e.g. `output.kafka.key`, to partition events by
+	// the partition key.
+	KeyFormat = "%{[@metadata." + MetaField + "]}"
+
+	traceIDField = "trace.id"
+)
+
+var (
+	registry           = monitoring.Default.NewRegistry("apm-server.partition_key")
+	monitoringTrace    = monitoring.NewInt(registry, "trace")
+	monitoringFallback = monitoring.NewInt(registry, "fallback")
+	monitoringNone     = monitoring.NewInt(registry, "none")
+)
+
+// Processor is a beat.Processor which sets the partition key of events
+// to their trace ID, so that all transactions, spans, and errors of a
+// trace are written to the same partition, as required by downstream
+// stream processors assembling traces.
+type Processor struct {
+	// FallbackField holds the name of the event field whose value is
+	// used as the partition key of events without a trace ID, such as
+	// metrics. If empty, or if an event has no such field, no partition
+	// key is set, and the output distributes the event across partitions.
+	FallbackField string
+}
+
+// Run sets the partition key of event.
+func (p *Processor) Run(event *beat.Event) (*beat.Event, error) {
+	if key, ok := stringField(event, traceIDField); ok {
+		monitoringTrace.Inc()
+		setMeta(event, key)
+		return event, nil
+	}
+	if p.FallbackField != "" {
+		if key, ok := stringField(event, p.FallbackField); ok {
+			monitoringFallback.Inc()
+			setMeta(event, key)
+			return event, nil
+		}
+	}
+	monitoringNone.Inc()
+	return event, nil
+}
+
+func (p *Processor) String() string {
+	return "partition_key"
+}
+
+// stringField returns the non-empty string value of the event field key,
+// and reports whether it exists.
+func stringField(event *beat.Event, key string) (string, bool) {
+	v, _ := event.Fields.GetValue(key)
+	s, ok := v.(string)
+	return s, ok && s != ""
+}
+
+func setMeta(event *beat.Event, key string) {
+	if event.Meta == nil {
+		event.Meta = common.MapStr{}
+	}
+	event.Meta[MetaField] = key
+}
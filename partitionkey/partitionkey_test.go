@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package partitionkey_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/common/fmtstr"
+
+	"github.com/elastic/apm-server/partitionkey"
+)
+
+func TestProcessor(t *testing.T) {
+	processor := &partitionkey.Processor{FallbackField: "service.name"}
+	for name, test := range map[string]struct {
+		fields common.MapStr
+		key    interface{}
+	}{
+		"trace": {
+			fields: common.MapStr{
+				"trace":   common.MapStr{"id": "0af7651916cd43dd8448eb211c80319c"},
+				"service": common.MapStr{"name": "opbeans"},
+			},
+			key: "0af7651916cd43dd8448eb211c80319c",
+		},
+		"fallback": {
+			fields: common.MapStr{"service": common.MapStr{"name": "opbeans"}},
+			key:    "opbeans",
+		},
+		"empty trace ID": {
+			fields: common.MapStr{
+				"trace":   common.MapStr{"id": ""},
+				"service": common.MapStr{"name": "opbeans"},
+			},
+			key: "opbeans",
+		},
+		"none": {
+			fields: common.MapStr{"processor": common.MapStr{"event": "onboarding"}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			event, err := processor.Run(&beat.Event{Fields: test.fields})
+			require.NoError(t, err)
+			if test.key == nil {
+				assert.Nil(t, event.Meta)
+			} else {
+				assert.Equal(t, common.MapStr{partitionkey.MetaField: test.key}, event.Meta)
+			}
+		})
+	}
+}
+
+func TestProcessorNoFallback(t *testing.T) {
+	processor := &partitionkey.Processor{}
+	event, err := processor.Run(&beat.Event{Fields: common.MapStr{
+		"service": common.MapStr{"name": "opbeans"},
+	}})
+	require.NoError(t, err)
+	assert.Nil(t, event.Meta)
+}
+
+func TestKeyFormat(t *testing.T) {
+	format, err := fmtstr.CompileEvent(partitionkey.KeyFormat)
+	require.NoError(t, err)
+
+	event, err := (&partitionkey.Processor{}).Run(&beat.Event{Fields: common.MapStr{
+		"trace": common.MapStr{"id": "0af7651916cd43dd8448eb211c80319c"},
+	}})
+	require.NoError(t, err)
+	key, err := format.Run(event)
+	require.NoError(t, err)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", key)
+}
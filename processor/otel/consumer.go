@@ -42,6 +42,7 @@ import (
 	"strconv"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
@@ -50,6 +51,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/logp"
 
+	"github.com/elastic/apm-server/beater/config"
 	logs "github.com/elastic/apm-server/log"
 	"github.com/elastic/apm-server/model"
 )
@@ -69,6 +71,19 @@ type Consumer struct {
 	stats consumerStats
 
 	Processor model.BatchProcessor
+
+	// InstrumentationScopes holds rules for renaming, down-sampling, or
+	// dropping spans according to their instrumentation scope (library).
+	InstrumentationScopes []InstrumentationScopeRule
+
+	// ResourceMappings holds rules for mapping resource attributes,
+	// and Jaeger process tags, to APM metadata fields.
+	ResourceMappings []config.ResourceMappingConfig
+
+	// MinLogSeverity holds the minimum severity of log records to
+	// convert into errors. If MinLogSeverity is undefined, only log
+	// records with a severity of ERROR or above are converted.
+	MinLogSeverity pdata.SeverityNumber
 }
 
 // ConsumerStats holds a snapshot of statistics about data consumption.
@@ -76,18 +91,25 @@ type ConsumerStats struct {
 	// UnsupportedMetricsDropped records the number of unsupported metrics
 	// that have been dropped by the consumer.
 	UnsupportedMetricsDropped int64
+
+	// UnsupportedLogsDropped records the number of log records with a
+	// severity below the consumer's MinLogSeverity that have been dropped
+	// by the consumer.
+	UnsupportedLogsDropped int64
 }
 
 // consumerStats holds the current statistics, which must be accessed and
 // modified using atomic operations.
 type consumerStats struct {
 	unsupportedMetricsDropped int64
+	unsupportedLogsDropped    int64
 }
 
 // Stats returns a snapshot of the current statistics about data consumption.
 func (c *Consumer) Stats() ConsumerStats {
 	return ConsumerStats{
 		UnsupportedMetricsDropped: atomic.LoadInt64(&c.stats.unsupportedMetricsDropped),
+		UnsupportedLogsDropped:    atomic.LoadInt64(&c.stats.unsupportedLogsDropped),
 	}
 }
 
@@ -95,6 +117,7 @@ func (c *Consumer) Stats() ConsumerStats {
 // converting into Elastic APM events and reporting to the Elastic APM schema.
 func (c *Consumer) ConsumeTraces(ctx context.Context, traces pdata.Traces) error {
 	batch := c.convert(traces)
+	setPropagatedTraceContext(ctx, batch)
 	return c.Processor.ProcessBatch(ctx, batch)
 }
 
@@ -104,12 +127,60 @@ func (c *Consumer) convert(td pdata.Traces) *model.Batch {
 	for i := 0; i < resourceSpans.Len(); i++ {
 		c.convertResourceSpans(resourceSpans.At(i), &batch)
 	}
+	setTransactionIDs(&batch)
 	return &batch
 }
 
+// setTransactionIDs sets the transaction ID of spans, and errors recorded
+// for span events, by following their parent IDs to the nearest transaction
+// in batch. Events whose ancestors in batch include no transaction, e.g.
+// because the transaction is sent in another request, are left unchanged.
+func setTransactionIDs(batch *model.Batch) {
+	if len(batch.Spans) == 0 {
+		return
+	}
+	type eventKey struct {
+		traceID, id string
+	}
+	// parents maps spans to their parent IDs, and transactions to
+	// an empty string, identifying the end of a walk.
+	parents := make(map[eventKey]*string, len(batch.Transactions)+len(batch.Spans))
+	var empty string
+	for _, tx := range batch.Transactions {
+		parents[eventKey{tx.TraceID, tx.ID}] = &empty
+	}
+	for _, span := range batch.Spans {
+		parents[eventKey{span.TraceID, span.ID}] = &span.ParentID
+	}
+	transactionID := func(traceID, parentID string) string {
+		// Bound the walk, in case of cycles in the parent IDs.
+		for i := 0; i < len(parents) && parentID != ""; i++ {
+			parent, ok := parents[eventKey{traceID, parentID}]
+			if !ok {
+				return ""
+			}
+			if *parent == "" {
+				return parentID
+			}
+			parentID = *parent
+		}
+		return ""
+	}
+	for _, span := range batch.Spans {
+		if span.TransactionID == "" {
+			span.TransactionID = transactionID(span.TraceID, span.ParentID)
+		}
+	}
+	for _, err := range batch.Errors {
+		if err.TransactionID == "" {
+			err.TransactionID = transactionID(err.TraceID, err.ParentID)
+		}
+	}
+}
+
 func (c *Consumer) convertResourceSpans(resourceSpans pdata.ResourceSpans, out *model.Batch) {
 	var metadata model.Metadata
-	translateResourceMetadata(resourceSpans.Resource(), &metadata)
+	translateResourceMetadata(resourceSpans.Resource(), c.ResourceMappings, &metadata)
 	instrumentationLibrarySpans := resourceSpans.InstrumentationLibrarySpans()
 	for i := 0; i < instrumentationLibrarySpans.Len(); i++ {
 		c.convertInstrumentationLibrarySpans(instrumentationLibrarySpans.At(i), metadata, out)
@@ -117,15 +188,20 @@ func (c *Consumer) convertResourceSpans(resourceSpans pdata.ResourceSpans, out *
 }
 
 func (c *Consumer) convertInstrumentationLibrarySpans(in pdata.InstrumentationLibrarySpans, metadata model.Metadata, out *model.Batch) {
+	scope := translateInstrumentationScope(in.InstrumentationLibrary(), c.InstrumentationScopes)
 	otelSpans := in.Spans()
 	for i := 0; i < otelSpans.Len(); i++ {
-		c.convertSpan(otelSpans.At(i), in.InstrumentationLibrary(), metadata, out)
+		otelSpan := otelSpans.At(i)
+		if !scope.sampled(otelSpan.TraceID()) {
+			continue
+		}
+		c.convertSpan(otelSpan, scope, metadata, out)
 	}
 }
 
 func (c *Consumer) convertSpan(
 	otelSpan pdata.Span,
-	otelLibrary pdata.InstrumentationLibrary,
+	scope instrumentationScope,
 	metadata model.Metadata,
 	out *model.Batch,
 ) {
@@ -143,9 +219,9 @@ func (c *Consumer) convertSpan(
 
 	startTime := otelSpan.StartTime().AsTime()
 	endTime := otelSpan.EndTime().AsTime()
-	var durationMillis float64
+	var duration time.Duration
 	if endTime.After(startTime) {
-		durationMillis = endTime.Sub(startTime).Seconds() * 1000
+		duration = endTime.Sub(startTime)
 	}
 
 	var transaction *model.Transaction
@@ -159,11 +235,11 @@ func (c *Consumer) convertSpan(
 			ParentID:  parentID,
 			TraceID:   traceID,
 			Timestamp: startTime,
-			Duration:  durationMillis,
+			Duration:  duration,
 			Name:      name,
 			Outcome:   spanStatusOutcome(otelSpan.Status()),
 		}
-		translateTransaction(otelSpan, otelLibrary, metadata, &transactionBuilder{Transaction: transaction})
+		translateTransaction(otelSpan, scope, metadata, &transactionBuilder{Transaction: transaction})
 		out.Transactions = append(out.Transactions, transaction)
 	} else {
 		span = &model.Span{
@@ -172,11 +248,11 @@ func (c *Consumer) convertSpan(
 			ParentID:  parentID,
 			TraceID:   traceID,
 			Timestamp: startTime,
-			Duration:  durationMillis,
+			Duration:  duration,
 			Name:      name,
 			Outcome:   spanStatusOutcome(otelSpan.Status()),
 		}
-		translateSpan(otelSpan, metadata, span)
+		translateSpan(otelSpan, scope, metadata, span)
 		out.Spans = append(out.Spans, span)
 	}
 
@@ -188,7 +264,7 @@ func (c *Consumer) convertSpan(
 
 func translateTransaction(
 	span pdata.Span,
-	library pdata.InstrumentationLibrary,
+	scope instrumentationScope,
 	metadata model.Metadata,
 	tx *transactionBuilder,
 ) {
@@ -239,6 +315,8 @@ func translateTransaction(
 			default:
 				labels[k] = v.IntVal()
 			}
+		case pdata.AttributeValueARRAY:
+			translatePropagationArrayAttribute(kDots, v.ArrayVal(), labels)
 		case pdata.AttributeValueSTRING:
 			stringval := truncate(v.StringVal())
 			switch kDots {
@@ -323,6 +401,9 @@ func translateTransaction(
 				component = stringval
 				fallthrough
 			default:
+				if translatePropagationAttribute(kDots, stringval, labels) {
+					break
+				}
 				labels[k] = stringval
 			}
 		}
@@ -387,11 +468,12 @@ func translateTransaction(
 	if tx.Result == "" {
 		tx.Result = spanStatusResult(span.Status())
 	}
-	tx.setFramework(library.Name(), library.Version())
+	tx.setFramework(scope.library.Name(), scope.library.Version())
+	scope.setLabels(labels)
 	tx.Labels = labels
 }
 
-func translateSpan(span pdata.Span, metadata model.Metadata, event *model.Span) {
+func translateSpan(span pdata.Span, scope instrumentationScope, metadata model.Metadata, event *model.Span) {
 	isJaeger := strings.HasPrefix(metadata.Service.Agent.Name, "Jaeger")
 	labels := make(common.MapStr)
 
@@ -446,6 +528,8 @@ func translateSpan(span pdata.Span, metadata model.Metadata, event *model.Span)
 			default:
 				labels[k] = v.IntVal()
 			}
+		case pdata.AttributeValueARRAY:
+			translatePropagationArrayAttribute(kDots, v.ArrayVal(), labels)
 		case pdata.AttributeValueSTRING:
 			stringval := truncate(v.StringVal())
 			switch kDots {
@@ -530,6 +614,9 @@ func translateSpan(span pdata.Span, metadata model.Metadata, event *model.Span)
 				component = stringval
 				fallthrough
 			default:
+				if translatePropagationAttribute(kDots, stringval, labels) {
+					break
+				}
 				labels[k] = stringval
 			}
 		}
@@ -660,6 +747,7 @@ func translateSpan(span pdata.Span, metadata model.Metadata, event *model.Span)
 		event.RepresentativeCount = 1
 	}
 
+	scope.setLabels(labels)
 	event.Labels = labels
 }
 
@@ -690,19 +778,19 @@ func convertSpanEvent(
 ) {
 	var e *model.Error
 	isJaeger := strings.HasPrefix(metadata.Service.Agent.Name, "Jaeger")
-	if isJaeger {
+	switch {
+	case isJaeger:
 		e = convertJaegerErrorSpanEvent(logger, event)
-	} else {
-		// Translate exception span events to errors.
+	default:
+		// Translate exception span events to errors, and all other
+		// span events to log errors.
 		//
 		// If it's not Jaeger, we assume OpenTelemetry semantic conventions.
-		//
-		// TODO(axw) we don't currently support arbitrary events, we only look
-		// for exceptions and convert those to Elastic APM error events.
 		if event.Name() != "exception" {
 			// Per OpenTelemetry semantic conventions:
 			//   `The name of the event MUST be "exception"`
-			return
+			e = convertOpenTelemetryLogSpanEvent(event)
+			break
 		}
 		var exceptionEscaped bool
 		var exceptionMessage, exceptionStacktrace, exceptionType string
@@ -742,6 +830,39 @@ func convertSpanEvent(
 	}
 }
 
+// convertOpenTelemetryLogSpanEvent converts a non-exception span event into
+// an error with a log message, using the event name as the message. Event
+// attributes are recorded as labels, with the exception of well-known log
+// level attributes which are used for the log level.
+func convertOpenTelemetryLogSpanEvent(event pdata.SpanEvent) *model.Error {
+	logMessage := event.Name()
+	if logMessage == "" {
+		return nil
+	}
+	log := model.Log{Message: truncate(logMessage), Level: "info"}
+	labels := make(common.MapStr)
+	event.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		switch k {
+		case "level", "log.level", "log.severity":
+			if v.Type() == pdata.AttributeValueSTRING {
+				log.Level = strings.ToLower(truncate(v.StringVal()))
+				return
+			}
+		}
+		if value := ifaceAttributeValue(v); value != nil {
+			labels[replaceDots(k)] = value
+		}
+	})
+	e := &model.Error{
+		Timestamp: event.Timestamp().AsTime(),
+		Log:       &log,
+	}
+	if len(labels) > 0 {
+		e.Labels = labels
+	}
+	return e
+}
+
 func convertJaegerErrorSpanEvent(logger *logp.Logger, event pdata.SpanEvent) *model.Error {
 	var isError bool
 	var exMessage, exType string
@@ -102,6 +102,38 @@ func TestOutcome(t *testing.T) {
 	test(t, "failure", "Error", pdata.StatusCodeError)
 }
 
+func TestSpanTransactionID(t *testing.T) {
+	traces, spans := newTracesSpans()
+	newSpan := func(spanID, parentID byte) pdata.Span {
+		span := pdata.NewSpan()
+		span.SetTraceID(pdata.NewTraceID([16]byte{1}))
+		span.SetSpanID(pdata.NewSpanID([8]byte{spanID}))
+		if parentID != 0 {
+			span.SetParentSpanID(pdata.NewSpanID([8]byte{parentID}))
+		}
+		return span
+	}
+	// Spans are sent before their parents, as they end first.
+	grandchild := newSpan(4, 3)
+	grandchild.Events().Append(pdata.NewSpanEvent())
+	grandchild.Events().At(0).SetName("exception")
+	grandchild.Events().At(0).Attributes().InsertString("exception.message", "boom")
+	spans.Spans().Append(grandchild)
+	spans.Spans().Append(newSpan(3, 2))
+	spans.Spans().Append(newSpan(2, 0))
+	spans.Spans().Append(newSpan(6, 5)) // parent not sent
+
+	batch := transformTraces(t, traces)
+	require.Len(t, batch.Transactions, 1)
+	require.Len(t, batch.Spans, 3)
+	require.Len(t, batch.Errors, 1)
+	transactionID := batch.Transactions[0].ID
+	assert.Equal(t, transactionID, batch.Spans[0].TransactionID)
+	assert.Equal(t, transactionID, batch.Spans[1].TransactionID)
+	assert.Empty(t, batch.Spans[2].TransactionID)
+	assert.Equal(t, transactionID, batch.Errors[0].TransactionID)
+}
+
 func TestRepresentativeCount(t *testing.T) {
 	traces, spans := newTracesSpans()
 	otelSpan1 := pdata.NewSpan()
@@ -473,6 +505,77 @@ func TestInstrumentationLibrary(t *testing.T) {
 
 	assert.Equal(t, "library-name", tx.Metadata.Service.Framework.Name)
 	assert.Equal(t, "1.2.3", tx.Metadata.Service.Framework.Version)
+	assert.Equal(t, common.MapStr{
+		"otel_scope_name":    "library-name",
+		"otel_scope_version": "1.2.3",
+	}, tx.Labels)
+}
+
+func TestInstrumentationLibrarySpan(t *testing.T) {
+	traces, spans := newTracesSpans()
+	spans.InstrumentationLibrary().SetName("library-name")
+	spans.InstrumentationLibrary().SetVersion("1.2.3")
+	otelSpan := pdata.NewSpan()
+	otelSpan.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	otelSpan.SetSpanID(pdata.NewSpanID([8]byte{2}))
+	otelSpan.SetParentSpanID(pdata.NewSpanID([8]byte{3}))
+	spans.Spans().Append(otelSpan)
+	events := transformTraces(t, traces)
+	span := events.Spans[0]
+
+	assert.Equal(t, model.Framework{}, span.Metadata.Service.Framework)
+	assert.Equal(t, common.MapStr{
+		"otel_scope_name":    "library-name",
+		"otel_scope_version": "1.2.3",
+	}, span.Labels)
+}
+
+func TestInstrumentationScopeRules(t *testing.T) {
+	newTraces := func() pdata.Traces {
+		traces := pdata.NewTraces()
+		resourceSpans := pdata.NewResourceSpans()
+		for _, name := range []string{"io.opentelemetry.jdbc", "io.opentelemetry.redis", "io.opentelemetry.kafka", "other"} {
+			librarySpans := pdata.NewInstrumentationLibrarySpans()
+			librarySpans.InstrumentationLibrary().SetName(name)
+			librarySpans.InstrumentationLibrary().SetVersion("1.0.0")
+			for i := 0; i < 100; i++ {
+				otelSpan := pdata.NewSpan()
+				otelSpan.SetTraceID(pdata.NewTraceID([16]byte{15: byte(i), 8: byte(i * 7)}))
+				otelSpan.SetSpanID(pdata.NewSpanID([8]byte{byte(i)}))
+				otelSpan.SetParentSpanID(pdata.NewSpanID([8]byte{1}))
+				librarySpans.Spans().Append(otelSpan)
+			}
+			resourceSpans.InstrumentationLibrarySpans().Append(librarySpans)
+		}
+		traces.ResourceSpans().Append(resourceSpans)
+		return traces
+	}
+
+	var batches []*model.Batch
+	consumer := &otel.Consumer{
+		Processor: batchRecorderBatchProcessor(&batches),
+		InstrumentationScopes: []otel.InstrumentationScopeRule{
+			{Name: "io.opentelemetry.jdbc", Rename: "jdbc", SampleRate: 1},
+			{Name: "io.opentelemetry.redis", SampleRate: 0},
+			{Name: "io.opentelemetry.*", SampleRate: 0.5},
+		},
+	}
+	require.NoError(t, consumer.ConsumeTraces(context.Background(), newTraces()))
+	require.NoError(t, consumer.ConsumeTraces(context.Background(), newTraces()))
+	require.Len(t, batches, 2)
+
+	counts := make(map[string]int)
+	for _, span := range batches[0].Spans {
+		counts[span.Labels["otel_scope_name"].(string)]++
+	}
+	assert.Equal(t, 100, counts["jdbc"])
+	assert.Equal(t, 100, counts["other"])
+	assert.Zero(t, counts["io.opentelemetry.jdbc"])
+	assert.Zero(t, counts["io.opentelemetry.redis"])
+	assert.InDelta(t, 50, counts["io.opentelemetry.kafka"], 25)
+
+	// Sampling decisions are made by trace ID, and are consistent.
+	assert.Equal(t, batches[0], batches[1])
 }
 
 func TestRPCTransaction(t *testing.T) {
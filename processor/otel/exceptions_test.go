@@ -43,13 +43,12 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 
+	"github.com/elastic/beats/v7/libbeat/common"
+
 	"github.com/elastic/apm-server/model"
 )
 
-func TestEncodeSpanEventsNonExceptions(t *testing.T) {
-	nonExceptionEvent := pdata.NewSpanEvent()
-	nonExceptionEvent.SetName("not_exception")
-
+func TestEncodeSpanEventsIncompleteExceptions(t *testing.T) {
 	incompleteExceptionEvent := pdata.NewSpanEvent()
 	incompleteExceptionEvent.SetName("exception")
 	incompleteExceptionEvent.Attributes().InitFromMap(map[string]pdata.AttributeValue{
@@ -57,10 +56,45 @@ func TestEncodeSpanEventsNonExceptions(t *testing.T) {
 		conventions.AttributeExceptionStacktrace: pdata.NewAttributeValueString("stacktrace"),
 	})
 
-	_, errors := transformTransactionSpanEvents(t, "java", nonExceptionEvent, incompleteExceptionEvent)
+	_, errors := transformTransactionSpanEvents(t, "java", incompleteExceptionEvent)
 	require.Empty(t, errors)
 }
 
+func TestEncodeSpanEventsNonExceptions(t *testing.T) {
+	timestamp := time.Unix(123, 0).UTC()
+
+	nonExceptionEvent := pdata.NewSpanEvent()
+	nonExceptionEvent.SetTimestamp(pdata.TimestampFromTime(timestamp))
+	nonExceptionEvent.SetName("cache miss")
+	nonExceptionEvent.Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"log.severity": pdata.NewAttributeValueString("WARN"),
+		"cache.key":    pdata.NewAttributeValueString("abc"),
+		"cache.size":   pdata.NewAttributeValueInt(123),
+	})
+
+	unnamedEvent := pdata.NewSpanEvent()
+
+	transaction, errors := transformTransactionSpanEvents(t, "java", nonExceptionEvent, unnamedEvent)
+	require.Len(t, errors, 1)
+	assert.Equal(t, &model.Error{
+		TraceID:            transaction.TraceID,
+		ParentID:           transaction.ID,
+		TransactionID:      transaction.ID,
+		Metadata:           transaction.Metadata,
+		Timestamp:          timestamp,
+		TransactionSampled: transaction.Sampled,
+		TransactionType:    transaction.Type,
+		Log: &model.Log{
+			Message: "cache miss",
+			Level:   "warn",
+		},
+		Labels: common.MapStr{
+			"cache_key":  "abc",
+			"cache_size": int64(123),
+		},
+	}, errors[0])
+}
+
 func TestEncodeSpanEventsJavaExceptions(t *testing.T) {
 	timestamp := time.Unix(123, 0).UTC()
 
@@ -0,0 +1,245 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/elastic/apm-server/model"
+)
+
+// ExportInstrumentationLibrary is the name of the instrumentation library
+// recorded for spans exported by TracesFromBatch.
+const ExportInstrumentationLibrary = "github.com/elastic/apm-server"
+
+// TracesFromBatch translates the transactions and spans in batch into
+// OpenTelemetry trace data, grouped into one resource per service.
+//
+// Errors, metricsets and profiles have no OpenTelemetry trace representation,
+// and are ignored. Transactions and spans with invalid trace or span IDs are
+// also ignored.
+func TracesFromBatch(batch *model.Batch) pdata.Traces {
+	traces := pdata.NewTraces()
+	resources := make(map[exportResourceKey]pdata.SpanSlice)
+	spanSlice := func(metadata *model.Metadata) pdata.SpanSlice {
+		key := newExportResourceKey(metadata)
+		spans, ok := resources[key]
+		if !ok {
+			rss := traces.ResourceSpans()
+			rss.Resize(rss.Len() + 1)
+			rs := rss.At(rss.Len() - 1)
+			key.setAttributes(rs.Resource().Attributes())
+			ilss := rs.InstrumentationLibrarySpans()
+			ilss.Resize(1)
+			ils := ilss.At(0)
+			ils.InstrumentationLibrary().SetName(ExportInstrumentationLibrary)
+			spans = ils.Spans()
+			resources[key] = spans
+		}
+		return spans
+	}
+
+	for _, tx := range batch.Transactions {
+		traceID, spanID, parentID, ok := decodeExportIDs(tx.TraceID, tx.ID, tx.ParentID)
+		if !ok {
+			continue
+		}
+		span := appendSpan(spanSlice(&tx.Metadata))
+		span.SetTraceID(traceID)
+		span.SetSpanID(spanID)
+		span.SetParentSpanID(parentID)
+		span.SetName(tx.Name)
+		span.SetKind(pdata.SpanKindINTERNAL)
+		if tx.Type == "request" || tx.HTTP != nil {
+			span.SetKind(pdata.SpanKindSERVER)
+		}
+		setExportTimes(span, tx.Timestamp, tx.Duration)
+		setExportStatus(span, tx.Outcome)
+
+		attrs := span.Attributes()
+		attrs.InsertString(exportAttributeType, tx.Type)
+		if tx.Result != "" {
+			attrs.InsertString(exportAttributeResult, tx.Result)
+		}
+		if tx.HTTP != nil && tx.HTTP.Response != nil && tx.HTTP.Response.StatusCode > 0 {
+			attrs.InsertInt(conventions.AttributeHTTPStatusCode, int64(tx.HTTP.Response.StatusCode))
+		}
+		setExportLabels(attrs, tx.Labels)
+	}
+
+	for _, s := range batch.Spans {
+		traceID, spanID, parentID, ok := decodeExportIDs(s.TraceID, s.ID, s.ParentID)
+		if !ok {
+			continue
+		}
+		span := appendSpan(spanSlice(&s.Metadata))
+		span.SetTraceID(traceID)
+		span.SetSpanID(spanID)
+		span.SetParentSpanID(parentID)
+		span.SetName(s.Name)
+		span.SetKind(pdata.SpanKindINTERNAL)
+		if s.DestinationService != nil || s.HTTP != nil || s.DB != nil {
+			span.SetKind(pdata.SpanKindCLIENT)
+		}
+		setExportTimes(span, s.Timestamp, s.Duration)
+		setExportStatus(span, s.Outcome)
+
+		attrs := span.Attributes()
+		attrs.InsertString(exportAttributeType, s.Type)
+		if s.Subtype != "" {
+			attrs.InsertString(exportAttributeSubtype, s.Subtype)
+		}
+		if s.Action != "" {
+			attrs.InsertString(exportAttributeAction, s.Action)
+		}
+		if s.DestinationService != nil && s.DestinationService.Resource != "" {
+			attrs.InsertString(conventions.AttributePeerService, s.DestinationService.Resource)
+		}
+		if s.HTTP != nil && s.HTTP.StatusCode > 0 {
+			attrs.InsertInt(conventions.AttributeHTTPStatusCode, int64(s.HTTP.StatusCode))
+		}
+		if s.DB != nil {
+			if s.DB.Type != "" {
+				attrs.InsertString(conventions.AttributeDBSystem, s.DB.Type)
+			}
+			if s.DB.Statement != "" {
+				attrs.InsertString(conventions.AttributeDBStatement, s.DB.Statement)
+			}
+		}
+		setExportLabels(attrs, s.Labels)
+	}
+	return traces
+}
+
+const (
+	exportAttributeType    = "elastic.type"
+	exportAttributeSubtype = "elastic.subtype"
+	exportAttributeAction  = "elastic.action"
+	exportAttributeResult  = "elastic.result"
+)
+
+// exportResourceKey holds the metadata which identifies an exported resource.
+type exportResourceKey struct {
+	serviceName        string
+	serviceVersion     string
+	serviceEnvironment string
+	agentName          string
+	agentVersion       string
+	languageName       string
+	hostname           string
+}
+
+func newExportResourceKey(metadata *model.Metadata) exportResourceKey {
+	hostname := metadata.System.ConfiguredHostname
+	if hostname == "" {
+		hostname = metadata.System.DetectedHostname
+	}
+	return exportResourceKey{
+		serviceName:        metadata.Service.Name,
+		serviceVersion:     metadata.Service.Version,
+		serviceEnvironment: metadata.Service.Environment,
+		agentName:          metadata.Service.Agent.Name,
+		agentVersion:       metadata.Service.Agent.Version,
+		languageName:       metadata.Service.Language.Name,
+		hostname:           hostname,
+	}
+}
+
+func (k exportResourceKey) setAttributes(attrs pdata.AttributeMap) {
+	insert := func(key, value string) {
+		if value != "" {
+			attrs.InsertString(key, value)
+		}
+	}
+	insert(conventions.AttributeServiceName, k.serviceName)
+	insert(conventions.AttributeServiceVersion, k.serviceVersion)
+	insert(conventions.AttributeDeploymentEnvironment, k.serviceEnvironment)
+	insert(conventions.AttributeTelemetrySDKName, k.agentName)
+	insert(conventions.AttributeTelemetrySDKVersion, k.agentVersion)
+	insert(conventions.AttributeTelemetrySDKLanguage, k.languageName)
+	insert(conventions.AttributeHostName, k.hostname)
+}
+
+func appendSpan(spans pdata.SpanSlice) pdata.Span {
+	spans.Resize(spans.Len() + 1)
+	return spans.At(spans.Len() - 1)
+}
+
+func decodeExportIDs(traceID, spanID, parentID string) (pdata.TraceID, pdata.SpanID, pdata.SpanID, bool) {
+	var traceIDBytes [16]byte
+	var spanIDBytes, parentIDBytes [8]byte
+	if err := decodeHexID(traceIDBytes[:], traceID); err != nil {
+		return pdata.TraceID{}, pdata.SpanID{}, pdata.SpanID{}, false
+	}
+	if err := decodeHexID(spanIDBytes[:], spanID); err != nil {
+		return pdata.TraceID{}, pdata.SpanID{}, pdata.SpanID{}, false
+	}
+	if parentID != "" {
+		if err := decodeHexID(parentIDBytes[:], parentID); err != nil {
+			return pdata.TraceID{}, pdata.SpanID{}, pdata.SpanID{}, false
+		}
+	}
+	return pdata.NewTraceID(traceIDBytes), pdata.NewSpanID(spanIDBytes), pdata.NewSpanID(parentIDBytes), true
+}
+
+func decodeHexID(out []byte, id string) error {
+	if hex.DecodedLen(len(id)) != len(out) {
+		return fmt.Errorf("invalid ID length %d", len(id))
+	}
+	_, err := hex.Decode(out, []byte(id))
+	return err
+}
+
+func setExportTimes(span pdata.Span, start time.Time, duration time.Duration) {
+	end := start.Add(duration)
+	span.SetStartTime(pdata.TimestampFromTime(start))
+	span.SetEndTime(pdata.TimestampFromTime(end))
+}
+
+func setExportStatus(span pdata.Span, outcome string) {
+	switch outcome {
+	case outcomeSuccess:
+		span.Status().SetCode(pdata.StatusCodeOk)
+	case outcomeFailure:
+		span.Status().SetCode(pdata.StatusCodeError)
+	}
+}
+
+func setExportLabels(attrs pdata.AttributeMap, labels map[string]interface{}) {
+	for k, v := range labels {
+		switch v := v.(type) {
+		case string:
+			attrs.InsertString(k, v)
+		case bool:
+			attrs.InsertBool(k, v)
+		case float64:
+			attrs.InsertDouble(k, v)
+		case int:
+			attrs.InsertInt(k, int64(v))
+		case int64:
+			attrs.InsertInt(k, v)
+		default:
+			attrs.InsertString(k, fmt.Sprint(v))
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+)
+
+func TestTracesFromBatch(t *testing.T) {
+	metadata := model.Metadata{
+		Service: model.Service{
+			Name:        "service_name",
+			Version:     "service_version",
+			Environment: "service_environment",
+			Agent:       model.Agent{Name: "go", Version: "1.0.0"},
+		},
+		System: model.System{DetectedHostname: "host_name"},
+	}
+	timestamp := time.Unix(123, 0).UTC()
+	batch := model.Batch{
+		Transactions: []*model.Transaction{{
+			Metadata:  metadata,
+			TraceID:   "0102030405060708090a0b0c0d0e0f10",
+			ID:        "0102030405060708",
+			Timestamp: timestamp,
+			Type:      "request",
+			Name:      "GET /",
+			Result:    "HTTP 5xx",
+			Outcome:   "failure",
+			Duration:  1500 * time.Microsecond,
+			HTTP:      &model.Http{Response: &model.Resp{MinimalResp: model.MinimalResp{StatusCode: 500}}},
+			Labels:    common.MapStr{"key": "value"},
+		}, {
+			Metadata: metadata,
+			TraceID:  "invalid",
+			ID:       "0102030405060708",
+		}},
+		Spans: []*model.Span{{
+			Metadata:           model.Metadata{Service: model.Service{Name: "other_service"}},
+			TraceID:            "0102030405060708090a0b0c0d0e0f10",
+			ID:                 "1112131415161718",
+			ParentID:           "0102030405060708",
+			Timestamp:          timestamp,
+			Name:               "SELECT FROM foo",
+			Outcome:            "success",
+			Duration:           time.Millisecond,
+			Type:               "db",
+			Subtype:            "postgresql",
+			DB:                 &model.DB{Type: "sql", Statement: "SELECT * FROM foo"},
+			DestinationService: &model.DestinationService{Resource: "postgresql"},
+		}},
+		Errors: []*model.Error{{Metadata: metadata}},
+	}
+
+	traces := otel.TracesFromBatch(&batch)
+	require.Equal(t, 2, traces.ResourceSpans().Len())
+	assert.Equal(t, 2, traces.SpanCount())
+
+	rs := traces.ResourceSpans().At(0)
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"service.name":           pdata.NewAttributeValueString("service_name"),
+		"service.version":        pdata.NewAttributeValueString("service_version"),
+		"deployment.environment": pdata.NewAttributeValueString("service_environment"),
+		"telemetry.sdk.name":     pdata.NewAttributeValueString("go"),
+		"telemetry.sdk.version":  pdata.NewAttributeValueString("1.0.0"),
+		"host.name":              pdata.NewAttributeValueString("host_name"),
+	}, rs.Resource().Attributes())
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	assert.Equal(t, otel.ExportInstrumentationLibrary, ils.InstrumentationLibrary().Name())
+	tx := ils.Spans().At(0)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", tx.TraceID().HexString())
+	assert.Equal(t, "0102030405060708", tx.SpanID().HexString())
+	assert.True(t, tx.ParentSpanID().IsEmpty())
+	assert.Equal(t, "GET /", tx.Name())
+	assert.Equal(t, pdata.SpanKindSERVER, tx.Kind())
+	assert.Equal(t, pdata.TimestampFromTime(timestamp), tx.StartTime())
+	assert.Equal(t, pdata.TimestampFromTime(timestamp.Add(1500*time.Microsecond)), tx.EndTime())
+	assert.Equal(t, pdata.StatusCodeError, tx.Status().Code())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"elastic.type":     pdata.NewAttributeValueString("request"),
+		"elastic.result":   pdata.NewAttributeValueString("HTTP 5xx"),
+		"http.status_code": pdata.NewAttributeValueInt(500),
+		"key":              pdata.NewAttributeValueString("value"),
+	}, tx.Attributes())
+
+	rs = traces.ResourceSpans().At(1)
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"service.name": pdata.NewAttributeValueString("other_service"),
+	}, rs.Resource().Attributes())
+	span := rs.InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, "1112131415161718", span.SpanID().HexString())
+	assert.Equal(t, "0102030405060708", span.ParentSpanID().HexString())
+	assert.Equal(t, pdata.SpanKindCLIENT, span.Kind())
+	assert.Equal(t, pdata.StatusCodeOk, span.Status().Code())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"elastic.type":    pdata.NewAttributeValueString("db"),
+		"elastic.subtype": pdata.NewAttributeValueString("postgresql"),
+		"peer.service":    pdata.NewAttributeValueString("postgresql"),
+		"db.system":       pdata.NewAttributeValueString("sql"),
+		"db.statement":    pdata.NewAttributeValueString("SELECT * FROM foo"),
+	}, span.Attributes())
+}
+
+func assertAttributes(t testing.TB, expected map[string]pdata.AttributeValue, attrs pdata.AttributeMap) {
+	t.Helper()
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(expected).Sort(), attrs.Sort())
+}
@@ -0,0 +1,216 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+// ConsumeLogs consumes OpenTelemetry log data, converting log records
+// into Elastic APM errors and sending to the reporter.
+//
+// Only log records with a severity of at least MinLogSeverity, by default
+// ERROR, are converted, as the Elastic APM schema has no representation for
+// logs other than errors; other log records are dropped and counted in
+// ConsumerStats.
+func (c *Consumer) ConsumeLogs(ctx context.Context, logs pdata.Logs) error {
+	batch := c.convertLogs(logs)
+	return c.Processor.ProcessBatch(ctx, batch)
+}
+
+func (c *Consumer) convertLogs(logs pdata.Logs) *model.Batch {
+	batch := model.Batch{}
+	resourceLogs := logs.ResourceLogs()
+	for i := 0; i < resourceLogs.Len(); i++ {
+		c.convertResourceLogs(resourceLogs.At(i), &batch)
+	}
+	return &batch
+}
+
+func (c *Consumer) convertResourceLogs(resourceLogs pdata.ResourceLogs, out *model.Batch) {
+	var metadata model.Metadata
+	translateResourceMetadata(resourceLogs.Resource(), c.ResourceMappings, &metadata)
+	instrumentationLibraryLogs := resourceLogs.InstrumentationLibraryLogs()
+	for i := 0; i < instrumentationLibraryLogs.Len(); i++ {
+		c.convertInstrumentationLibraryLogs(instrumentationLibraryLogs.At(i), metadata, out)
+	}
+}
+
+func (c *Consumer) convertInstrumentationLibraryLogs(in pdata.InstrumentationLibraryLogs, metadata model.Metadata, out *model.Batch) {
+	var unsupported int64
+	loggerName := in.InstrumentationLibrary().Name()
+	records := in.Logs()
+	for i := 0; i < records.Len(); i++ {
+		record := records.At(i)
+		if logRecordSeverity(record) < c.minLogSeverity() {
+			unsupported++
+			continue
+		}
+		e := convertLogRecord(record, metadata, loggerName)
+		out.Errors = append(out.Errors, e)
+	}
+	if unsupported > 0 {
+		atomic.AddInt64(&c.stats.unsupportedLogsDropped, unsupported)
+	}
+}
+
+// LogSeverity returns the lowest severity number for the log severity
+// name, one of config.OTelLogSeverities, or SeverityNumberUNDEFINED if the
+// name is unknown.
+func LogSeverity(name string) pdata.SeverityNumber {
+	switch name {
+	case "trace":
+		return pdata.SeverityNumberTRACE
+	case "debug":
+		return pdata.SeverityNumberDEBUG
+	case "info":
+		return pdata.SeverityNumberINFO
+	case "warn":
+		return pdata.SeverityNumberWARN
+	case "error":
+		return pdata.SeverityNumberERROR
+	case "fatal":
+		return pdata.SeverityNumberFATAL
+	}
+	return pdata.SeverityNumberUNDEFINED
+}
+
+func (c *Consumer) minLogSeverity() pdata.SeverityNumber {
+	if c.MinLogSeverity == pdata.SeverityNumberUNDEFINED {
+		return pdata.SeverityNumberERROR
+	}
+	return c.MinLogSeverity
+}
+
+// logRecordSeverity returns the severity number of record. If the record
+// has no severity number, it is derived from its severity text, and records
+// with neither are treated as having an undefined severity.
+func logRecordSeverity(record pdata.LogRecord) pdata.SeverityNumber {
+	if severity := record.SeverityNumber(); severity != pdata.SeverityNumberUNDEFINED {
+		return severity
+	}
+	switch strings.ToLower(record.SeverityText()) {
+	case "trace":
+		return pdata.SeverityNumberTRACE
+	case "debug":
+		return pdata.SeverityNumberDEBUG
+	case "info", "information", "notice":
+		return pdata.SeverityNumberINFO
+	case "warn", "warning":
+		return pdata.SeverityNumberWARN
+	case "error":
+		return pdata.SeverityNumberERROR
+	case "fatal", "critical", "alert", "emergency":
+		return pdata.SeverityNumberFATAL
+	}
+	return pdata.SeverityNumberUNDEFINED
+}
+
+// logLevel returns the log level to record for a log record with the given
+// severity, when the record has no severity text.
+func logLevel(severity pdata.SeverityNumber) string {
+	switch {
+	case severity >= pdata.SeverityNumberFATAL:
+		return "fatal"
+	case severity >= pdata.SeverityNumberERROR:
+		return "error"
+	case severity >= pdata.SeverityNumberWARN:
+		return "warn"
+	case severity >= pdata.SeverityNumberINFO:
+		return "info"
+	case severity >= pdata.SeverityNumberDEBUG:
+		return "debug"
+	case severity >= pdata.SeverityNumberTRACE:
+		return "trace"
+	}
+	return "error"
+}
+
+// convertLogRecord converts a log record into an error. Log records with
+// exception attributes are converted into exceptions, and all other log
+// records into log errors using the log body as the message. Remaining
+// attributes are recorded as labels.
+func convertLogRecord(record pdata.LogRecord, metadata model.Metadata, loggerName string) *model.Error {
+	var exceptionEscaped bool
+	var exceptionMessage, exceptionStacktrace, exceptionType string
+	labels := make(common.MapStr)
+	record.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		switch k {
+		case conventions.AttributeExceptionMessage:
+			exceptionMessage = v.StringVal()
+		case conventions.AttributeExceptionStacktrace:
+			exceptionStacktrace = v.StringVal()
+		case conventions.AttributeExceptionType:
+			exceptionType = v.StringVal()
+		case "exception.escaped":
+			exceptionEscaped = v.BoolVal()
+		default:
+			if value := ifaceAttributeValue(v); value != nil {
+				labels[replaceDots(k)] = value
+			}
+		}
+	})
+
+	var e *model.Error
+	if exceptionMessage != "" || exceptionType != "" {
+		e = convertOpenTelemetryExceptionSpanEvent(
+			record.Timestamp().AsTime(),
+			exceptionType, exceptionMessage, exceptionStacktrace,
+			exceptionEscaped, metadata.Service.Language.Name,
+		)
+	} else {
+		e = &model.Error{Timestamp: record.Timestamp().AsTime()}
+	}
+
+	var message string
+	if body := ifaceAttributeValue(record.Body()); body != nil {
+		message = truncate(fmt.Sprint(body))
+	}
+	if message != "" || e.Exception == nil {
+		level := strings.ToLower(truncate(record.SeverityText()))
+		if level == "" {
+			level = logLevel(record.SeverityNumber())
+		}
+		e.Log = &model.Log{
+			Message:    message,
+			Level:      level,
+			LoggerName: truncate(loggerName),
+		}
+	}
+
+	e.Metadata = metadata
+	if traceID := record.TraceID(); !traceID.IsEmpty() {
+		e.TraceID = traceID.HexString()
+	}
+	if spanID := record.SpanID(); !spanID.IsEmpty() {
+		e.ParentID = spanID.HexString()
+	}
+	if len(labels) > 0 {
+		e.Labels = labels
+	}
+	return e
+}
@@ -0,0 +1,139 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestConsumeLogs(t *testing.T) {
+	logs := pdata.NewLogs()
+	logs.ResourceLogs().Resize(1)
+	resourceLogs := logs.ResourceLogs().At(0)
+	resourceLogs.Resource().Attributes().InsertString("service.name", "checkout")
+	resourceLogs.InstrumentationLibraryLogs().Resize(1)
+	instrumentationLibraryLogs := resourceLogs.InstrumentationLibraryLogs().At(0)
+	instrumentationLibraryLogs.InstrumentationLibrary().SetName("com.example.Checkout")
+	records := instrumentationLibraryLogs.Logs()
+	appendRecord := func(severity pdata.SeverityNumber, severityText, body string) pdata.LogRecord {
+		n := records.Len()
+		records.Resize(n + 1)
+		record := records.At(n)
+		record.SetTimestamp(pdata.TimestampFromTime(time.Unix(123, 0)))
+		record.SetSeverityNumber(severity)
+		record.SetSeverityText(severityText)
+		if body != "" {
+			record.Body().SetStringVal(body)
+		}
+		return record
+	}
+
+	var expectDropped int64
+	record := appendRecord(pdata.SeverityNumberERROR, "Error", "payment failed")
+	record.SetTraceID(pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	record.SetSpanID(pdata.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	record.Attributes().InsertString("order.id", "123")
+
+	record = appendRecord(pdata.SeverityNumberFATAL, "", "")
+	record.Attributes().InsertString("exception.type", "java.lang.IllegalStateException")
+	record.Attributes().InsertString("exception.message", "bad state")
+
+	// Severity text is used in the absence of a severity number.
+	appendRecord(pdata.SeverityNumberUNDEFINED, "CRITICAL", "disk full")
+
+	// Log records below ERROR cannot be represented, and are dropped.
+	appendRecord(pdata.SeverityNumberINFO, "INFO", "order placed")
+	expectDropped++
+	appendRecord(pdata.SeverityNumberUNDEFINED, "", "no severity")
+	expectDropped++
+
+	var batches []*model.Batch
+	consumer := &otel.Consumer{Processor: batchRecorderBatchProcessor(&batches)}
+	err := consumer.ConsumeLogs(context.Background(), logs)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, expectDropped, consumer.Stats().UnsupportedLogsDropped)
+
+	errors := batches[0].Errors
+	require.Len(t, errors, 3)
+	for _, e := range errors {
+		assert.Equal(t, "checkout", e.Metadata.Service.Name)
+		assert.Equal(t, time.Unix(123, 0).UTC(), e.Timestamp.UTC())
+	}
+
+	assert.Equal(t, &model.Log{
+		Message:    "payment failed",
+		Level:      "error",
+		LoggerName: "com.example.Checkout",
+	}, errors[0].Log)
+	assert.Nil(t, errors[0].Exception)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", errors[0].TraceID)
+	assert.Equal(t, "0102030405060708", errors[0].ParentID)
+	assert.Equal(t, common.MapStr{"order_id": "123"}, errors[0].Labels)
+
+	assert.Nil(t, errors[1].Log)
+	require.NotNil(t, errors[1].Exception)
+	assert.Equal(t, "java.lang.IllegalStateException", errors[1].Exception.Type)
+	assert.Equal(t, "bad state", errors[1].Exception.Message)
+
+	assert.Equal(t, &model.Log{
+		Message:    "disk full",
+		Level:      "critical",
+		LoggerName: "com.example.Checkout",
+	}, errors[2].Log)
+}
+
+func TestConsumeLogsMinSeverity(t *testing.T) {
+	logs := pdata.NewLogs()
+	logs.ResourceLogs().Resize(1)
+	resourceLogs := logs.ResourceLogs().At(0)
+	resourceLogs.InstrumentationLibraryLogs().Resize(1)
+	records := resourceLogs.InstrumentationLibraryLogs().At(0).Logs()
+	records.Resize(4)
+	records.At(0).SetSeverityNumber(pdata.SeverityNumberDEBUG)
+	records.At(1).SetSeverityNumber(pdata.SeverityNumberWARN2)
+	records.At(2).SetSeverityText("Warning")
+	records.At(3).SetSeverityNumber(pdata.SeverityNumberERROR)
+
+	var batches []*model.Batch
+	consumer := &otel.Consumer{
+		Processor:      batchRecorderBatchProcessor(&batches),
+		MinLogSeverity: otel.LogSeverity("warn"),
+	}
+	err := consumer.ConsumeLogs(context.Background(), logs)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, int64(1), consumer.Stats().UnsupportedLogsDropped)
+
+	var levels []string
+	for _, e := range batches[0].Errors {
+		levels = append(levels, e.Log.Level)
+	}
+	// The severity number is used for the level in the absence of text.
+	assert.Equal(t, []string{"warn", "warning", "error"}, levels)
+}
@@ -26,6 +26,7 @@ import (
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 
+	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/beats/v7/libbeat/common"
 )
@@ -38,7 +39,7 @@ var (
 	serviceNameInvalidRegexp = regexp.MustCompile("[^a-zA-Z0-9 _-]")
 )
 
-func translateResourceMetadata(resource pdata.Resource, out *model.Metadata) {
+func translateResourceMetadata(resource pdata.Resource, mappings []config.ResourceMappingConfig, out *model.Metadata) {
 	var exporterVersion string
 	resource.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
 		switch k {
@@ -172,6 +173,8 @@ func translateResourceMetadata(resource pdata.Resource, out *model.Metadata) {
 		}
 	}
 
+	applyResourceMappings(resource, mappings, out)
+
 	if out.Service.Name == "" {
 		// service.name is a required field.
 		out.Service.Name = "unknown"
@@ -18,12 +18,17 @@
 package otel_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/consumer/pdata"
 
+	"github.com/elastic/apm-server/beater/config"
 	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+	"github.com/elastic/beats/v7/libbeat/common"
 )
 
 func TestResourceConventions(t *testing.T) {
@@ -201,6 +206,72 @@ func TestResourceConventions(t *testing.T) {
 	}
 }
 
+func TestResourceMappings(t *testing.T) {
+	attrs := map[string]pdata.AttributeValue{
+		"host.name":       pdata.NewAttributeValueString("host_name"),
+		"deployment.node": pdata.NewAttributeValueString("node-1"),
+		"dc":              pdata.NewAttributeValueString("eu-west-1a"),
+		"hostname":        pdata.NewAttributeValueString("jaeger_hostname"),
+		"shard":           pdata.NewAttributeValueInt(3),
+		"other":           pdata.NewAttributeValueString("other"),
+	}
+
+	meta := transformResourceMetadataMappings(t, attrs, []config.ResourceMappingConfig{
+		{Attribute: "deployment.node", Field: "service.node.name"},
+		{Attribute: "dc", Field: "cloud.availability_zone"},
+		{Attribute: "hostname", Field: "host.hostname"},
+		{Attribute: "shard", Field: "kubernetes.pod.name"},
+		{Attribute: "missing", Field: "service.environment"},
+	})
+	assert.Equal(t, "node-1", meta.Service.Node.Name)
+	assert.Equal(t, "eu-west-1a", meta.Cloud.AvailabilityZone)
+	assert.Equal(t, "host_name", meta.System.DetectedHostname) // standard attribute takes precedence
+	assert.Equal(t, "3", meta.System.Kubernetes.PodName)
+	assert.Equal(t, "", meta.Service.Environment)
+	assert.Equal(t, common.MapStr{"other": "other"}, meta.Labels)
+
+	meta = transformResourceMetadataMappings(t, attrs, []config.ResourceMappingConfig{
+		{Attribute: "hostname", Field: "host.hostname", Override: true},
+	})
+	assert.Equal(t, "jaeger_hostname", meta.System.DetectedHostname)
+}
+
+func TestResourceMappingFields(t *testing.T) {
+	attrs := map[string]pdata.AttributeValue{"custom": pdata.NewAttributeValueString("value")}
+	unmapped := transformResourceMetadataMappings(t, attrs, nil)
+	for _, field := range config.ResourceMappingFields {
+		meta := transformResourceMetadataMappings(t, attrs, []config.ResourceMappingConfig{
+			{Attribute: "custom", Field: field, Override: true},
+		})
+		assert.NotEqual(t, unmapped, meta, field)
+		assert.Nil(t, meta.Labels, field)
+	}
+}
+
+func transformResourceMetadataMappings(
+	t *testing.T,
+	resourceAttrs map[string]pdata.AttributeValue,
+	mappings []config.ResourceMappingConfig,
+) model.Metadata {
+	traces, spans := newTracesSpans()
+	traces.ResourceSpans().At(0).Resource().Attributes().InitFromMap(resourceAttrs)
+	otelSpan := pdata.NewSpan()
+	otelSpan.SetTraceID(pdata.NewTraceID([16]byte{1}))
+	otelSpan.SetSpanID(pdata.NewSpanID([8]byte{2}))
+	spans.Spans().Append(otelSpan)
+
+	var batch *model.Batch
+	consumer := &otel.Consumer{
+		Processor: model.ProcessBatchFunc(func(ctx context.Context, b *model.Batch) error {
+			batch = b
+			return nil
+		}),
+		ResourceMappings: mappings,
+	}
+	require.NoError(t, consumer.ConsumeTraces(context.Background(), traces))
+	return batch.Transactions[0].Metadata
+}
+
 func transformResourceMetadata(t *testing.T, resourceAttrs map[string]pdata.AttributeValue) model.Metadata {
 	traces, spans := newTracesSpans()
 	traces.ResourceSpans().At(0).Resource().Attributes().InitFromMap(resourceAttrs)
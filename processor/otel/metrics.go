@@ -65,7 +65,7 @@ func (c *Consumer) convertMetrics(metrics pdata.Metrics) *model.Batch {
 
 func (c *Consumer) convertResourceMetrics(resourceMetrics pdata.ResourceMetrics, out *model.Batch) {
 	var metadata model.Metadata
-	translateResourceMetadata(resourceMetrics.Resource(), &metadata)
+	translateResourceMetadata(resourceMetrics.Resource(), c.ResourceMappings, &metadata)
 	instrumentationLibraryMetrics := resourceMetrics.InstrumentationLibraryMetrics()
 	for i := 0; i < instrumentationLibraryMetrics.Len(); i++ {
 		c.convertInstrumentationLibraryMetrics(instrumentationLibraryMetrics.At(i), metadata, out)
@@ -0,0 +1,267 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/model"
+)
+
+// Trace context propagation headers which may be recorded as span attributes,
+// either using their bare names or following the OpenTelemetry convention for
+// captured request headers: "http.request.header.<lowercase_name>".
+const (
+	headerAttributePrefix = "http.request.header."
+
+	headerB3             = "b3"
+	headerB3TraceID      = "x-b3-traceid"
+	headerB3SpanID       = "x-b3-spanid"
+	headerB3ParentSpanID = "x-b3-parentspanid"
+	headerB3Sampled      = "x-b3-sampled"
+	headerXRayTraceID    = "x-amzn-trace-id"
+)
+
+// Labels used for recording the trace context of foreign propagation formats.
+// IDs are normalized to the same lowercase hex representation used for
+// trace.id and parent.id, so they can be used to find related traces.
+const (
+	labelB3TraceID      = "b3_trace_id"
+	labelB3SpanID       = "b3_span_id"
+	labelB3ParentSpanID = "b3_parent_span_id"
+	labelB3Sampled      = "b3_sampled"
+	labelXRayTraceID    = "xray_trace_id"
+	labelXRayParentID   = "xray_parent_id"
+	labelXRaySampled    = "xray_sampled"
+	labelXRayRoot       = "xray_root"
+)
+
+type traceHeadersKey struct{}
+
+// propagatedTraceContext holds the trace context propagated in the B3 or
+// AWS X-Ray headers of a request carrying trace data.
+type propagatedTraceContext struct {
+	// labels holds the propagated trace context, as recorded
+	// for propagation headers recorded as span attributes.
+	labels common.MapStr
+
+	// traceID and parentID hold the propagated trace ID, and the ID of
+	// the span which sent the request, preferring B3 over X-Ray.
+	traceID  string
+	parentID string
+}
+
+// ContextWithTraceHeaders returns a copy of ctx with the B3 (single and multi
+// header) and AWS X-Ray trace context propagated in header, the headers of a
+// request carrying trace data. The propagated trace context is used for events
+// whose trace or parent IDs are missing.
+func ContextWithTraceHeaders(ctx context.Context, header http.Header) context.Context {
+	labels := make(common.MapStr)
+	for _, key := range []string{
+		headerB3, headerB3TraceID, headerB3SpanID,
+		headerB3ParentSpanID, headerB3Sampled, headerXRayTraceID,
+	} {
+		if value := header.Get(key); value != "" {
+			translatePropagationAttribute(key, value, labels)
+		}
+	}
+	p := propagatedTraceContext{labels: labels}
+	if traceID, _ := labels[labelB3TraceID].(string); traceID != "" {
+		p.traceID = traceID
+		p.parentID, _ = labels[labelB3SpanID].(string)
+	} else if traceID, _ := labels[labelXRayTraceID].(string); traceID != "" {
+		p.traceID = traceID
+		p.parentID, _ = labels[labelXRayParentID].(string)
+	}
+	if p.traceID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, traceHeadersKey{}, &p)
+}
+
+// setPropagatedTraceContext sets the trace ID of events in batch which have
+// none, and the parent ID of transactions which have none and belong to the
+// propagated trace, from the trace context propagated in the request headers
+// recorded in ctx. Events so updated are labeled with the propagated trace
+// context, without overriding labels translated from span attributes.
+func setPropagatedTraceContext(ctx context.Context, batch *model.Batch) {
+	p, _ := ctx.Value(traceHeadersKey{}).(*propagatedTraceContext)
+	if p == nil {
+		return
+	}
+	setTraceID := func(traceID *string, labels *common.MapStr) bool {
+		if *traceID != "" {
+			return false
+		}
+		*traceID = p.traceID
+		p.setLabels(labels)
+		return true
+	}
+	for _, tx := range batch.Transactions {
+		setTraceID(&tx.TraceID, &tx.Labels)
+		if tx.ParentID == "" && tx.TraceID == p.traceID && p.parentID != "" {
+			tx.ParentID = p.parentID
+			p.setLabels(&tx.Labels)
+		}
+	}
+	for _, span := range batch.Spans {
+		setTraceID(&span.TraceID, &span.Labels)
+	}
+	for _, err := range batch.Errors {
+		setTraceID(&err.TraceID, &err.Labels)
+	}
+}
+
+func (p *propagatedTraceContext) setLabels(labels *common.MapStr) {
+	if *labels == nil {
+		*labels = make(common.MapStr, len(p.labels))
+	}
+	for k, v := range p.labels {
+		if _, ok := (*labels)[k]; !ok {
+			(*labels)[k] = v
+		}
+	}
+}
+
+// translatePropagationAttribute translates B3 (single and multi header) and
+// AWS X-Ray trace headers recorded as span attributes into labels, returning
+// true if the attribute was recognized as a propagation header.
+func translatePropagationAttribute(key, value string, labels common.MapStr) bool {
+	key = strings.ToLower(key)
+	key = strings.TrimPrefix(key, headerAttributePrefix)
+	key = strings.Replace(key, "_", "-", -1)
+	switch key {
+	case headerB3:
+		translateB3SingleHeader(value, labels)
+	case headerB3TraceID:
+		setHexIDLabel(labels, labelB3TraceID, value, 32)
+	case headerB3SpanID:
+		setHexIDLabel(labels, labelB3SpanID, value, 16)
+	case headerB3ParentSpanID:
+		setHexIDLabel(labels, labelB3ParentSpanID, value, 16)
+	case headerB3Sampled:
+		setB3SampledLabel(labels, value)
+	case headerXRayTraceID:
+		translateXRayHeader(value, labels)
+	default:
+		return false
+	}
+	return true
+}
+
+// translatePropagationArrayAttribute translates a propagation header recorded
+// as a string array attribute, as is done for captured request headers by
+// OpenTelemetry instrumentation. Only the first header value is translated.
+func translatePropagationArrayAttribute(key string, values pdata.AnyValueArray, labels common.MapStr) bool {
+	if values.Len() == 0 || values.At(0).Type() != pdata.AttributeValueSTRING {
+		return false
+	}
+	return translatePropagationAttribute(key, values.At(0).StringVal(), labels)
+}
+
+// translateB3SingleHeader translates a B3 single header value of the form
+// "{TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}", where everything
+// but the trace and span IDs are optional. A bare sampling state ("0", "1"
+// or "d") is also permitted.
+func translateB3SingleHeader(value string, labels common.MapStr) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) == 1 {
+		setB3SampledLabel(labels, parts[0])
+		return
+	}
+	setHexIDLabel(labels, labelB3TraceID, parts[0], 32)
+	setHexIDLabel(labels, labelB3SpanID, parts[1], 16)
+	if len(parts) > 2 {
+		setB3SampledLabel(labels, parts[2])
+	}
+	if len(parts) > 3 {
+		setHexIDLabel(labels, labelB3ParentSpanID, parts[3], 16)
+	}
+}
+
+func setB3SampledLabel(labels common.MapStr, value string) {
+	switch strings.TrimSpace(value) {
+	case "1", "true", "d":
+		labels[labelB3Sampled] = true
+	case "0", "false":
+		labels[labelB3Sampled] = false
+	}
+}
+
+// translateXRayHeader translates an X-Amzn-Trace-Id header value of the form
+// "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1".
+//
+// The X-Ray root trace ID is made up of a version, an 8 hex digit epoch
+// timestamp, and a 24 hex digit identifier; concatenating the timestamp and
+// identifier gives the 128-bit trace ID used by OpenTelemetry and W3C Trace
+// Context propagators.
+func translateXRayHeader(value string, labels common.MapStr) {
+	for _, field := range strings.Split(value, ";") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch k, v := kv[0], strings.TrimSpace(kv[1]); k {
+		case "Root":
+			parts := strings.Split(v, "-")
+			if len(parts) != 3 || parts[0] != "1" || len(parts[1]) != 8 || len(parts[2]) != 24 {
+				continue
+			}
+			labels[labelXRayRoot] = truncate(v)
+			setHexIDLabel(labels, labelXRayTraceID, parts[1]+parts[2], 32)
+		case "Parent":
+			setHexIDLabel(labels, labelXRayParentID, v, 16)
+		case "Sampled":
+			switch v {
+			case "1":
+				labels[labelXRaySampled] = true
+			case "0":
+				labels[labelXRaySampled] = false
+			}
+		}
+	}
+}
+
+// setHexIDLabel sets labels[key] to the normalized hex ID if it is valid.
+// IDs shorter than width are left-padded with zeroes, which is how 64-bit
+// B3 trace IDs are represented when translated to 128-bit trace IDs.
+func setHexIDLabel(labels common.MapStr, key, id string, width int) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	if id == "" || len(id) > width || !isHex(id) {
+		return
+	}
+	if n := width - len(id); n > 0 {
+		id = strings.Repeat("0", n) + id
+	}
+	labels[key] = id
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestB3SingleHeader(t *testing.T) {
+	tx := transformTransactionWithAttributes(t, map[string]pdata.AttributeValue{
+		"http.request.header.b3": pdata.NewAttributeValueString("80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1-05e3ac9a4f6e3b90"),
+	})
+	assert.Equal(t, common.MapStr{
+		"b3_trace_id":       "80f198ee56343ba864fe8b2a57d3eff7",
+		"b3_span_id":        "e457b5a2e4d86bd1",
+		"b3_parent_span_id": "05e3ac9a4f6e3b90",
+		"b3_sampled":        true,
+	}, tx.Labels)
+}
+
+func TestB3MultiHeader(t *testing.T) {
+	span := transformSpanWithAttributes(t, map[string]pdata.AttributeValue{
+		// 64-bit trace IDs are left-padded to 128 bits.
+		"X-B3-TraceId": pdata.NewAttributeValueString("A3CE929D0E0E4736"),
+		"x-b3-spanid":  pdata.NewAttributeValueString("00f067aa0ba902b7"),
+		"x_b3_sampled": pdata.NewAttributeValueString("0"),
+		// Invalid IDs are ignored.
+		"x-b3-parentspanid": pdata.NewAttributeValueString("not-hex"),
+	})
+	assert.Equal(t, common.MapStr{
+		"b3_trace_id": "0000000000000000a3ce929d0e0e4736",
+		"b3_span_id":  "00f067aa0ba902b7",
+		"b3_sampled":  false,
+	}, span.Labels)
+}
+
+func TestXRayHeader(t *testing.T) {
+	tx := transformTransactionWithAttributes(t, map[string]pdata.AttributeValue{
+		"http.request.header.x_amzn_trace_id": pdata.NewAttributeValueString(
+			"Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+		),
+	})
+	assert.Equal(t, common.MapStr{
+		"xray_root":      "1-5759e988-bd862e3fe1be46a994272793",
+		"xray_trace_id":  "5759e988bd862e3fe1be46a994272793",
+		"xray_parent_id": "53995c3f42cd8ad8",
+		"xray_sampled":   true,
+	}, tx.Labels)
+}
+
+func TestB3HeaderArrayValue(t *testing.T) {
+	// Captured request headers are recorded as string arrays.
+	values := pdata.NewAttributeValueArray()
+	values.ArrayVal().Append(pdata.NewAttributeValueString("80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1"))
+	values.ArrayVal().Append(pdata.NewAttributeValueString("ignored"))
+	span := transformSpanWithAttributes(t, map[string]pdata.AttributeValue{
+		"http.request.header.b3": values,
+	})
+	assert.Equal(t, common.MapStr{
+		"b3_trace_id": "80f198ee56343ba864fe8b2a57d3eff7",
+		"b3_span_id":  "e457b5a2e4d86bd1",
+	}, span.Labels)
+}
@@ -0,0 +1,93 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/model"
+)
+
+// metadataFields maps the APM metadata fields listed in
+// config.ResourceMappingFields to their location in model.Metadata.
+var metadataFields = map[string]func(*model.Metadata) *string{
+	"service.name":            func(m *model.Metadata) *string { return &m.Service.Name },
+	"service.version":         func(m *model.Metadata) *string { return &m.Service.Version },
+	"service.environment":     func(m *model.Metadata) *string { return &m.Service.Environment },
+	"service.node.name":       func(m *model.Metadata) *string { return &m.Service.Node.Name },
+	"cloud.provider":          func(m *model.Metadata) *string { return &m.Cloud.Provider },
+	"cloud.account.id":        func(m *model.Metadata) *string { return &m.Cloud.AccountID },
+	"cloud.region":            func(m *model.Metadata) *string { return &m.Cloud.Region },
+	"cloud.availability_zone": func(m *model.Metadata) *string { return &m.Cloud.AvailabilityZone },
+	"cloud.instance.id":       func(m *model.Metadata) *string { return &m.Cloud.InstanceID },
+	"cloud.instance.name":     func(m *model.Metadata) *string { return &m.Cloud.InstanceName },
+	"cloud.project.id":        func(m *model.Metadata) *string { return &m.Cloud.ProjectID },
+	"container.id":            func(m *model.Metadata) *string { return &m.System.Container.ID },
+	"container.name":          func(m *model.Metadata) *string { return &m.System.Container.Name },
+	"host.hostname":           func(m *model.Metadata) *string { return &m.System.DetectedHostname },
+	"host.name":               func(m *model.Metadata) *string { return &m.System.ConfiguredHostname },
+	"kubernetes.namespace":    func(m *model.Metadata) *string { return &m.System.Kubernetes.Namespace },
+	"kubernetes.node.name":    func(m *model.Metadata) *string { return &m.System.Kubernetes.NodeName },
+	"kubernetes.pod.name":     func(m *model.Metadata) *string { return &m.System.Kubernetes.PodName },
+	"kubernetes.pod.uid":      func(m *model.Metadata) *string { return &m.System.Kubernetes.PodUID },
+}
+
+// applyResourceMappings sets metadata fields from resource attributes
+// according to mappings, which are applied in order.
+//
+// A mapped value replaces a non-empty field value, e.g. one translated from
+// a standard resource attribute or set by an earlier mapping, only if the
+// mapping's Override is true. Mapped attributes are not recorded as labels.
+func applyResourceMappings(resource pdata.Resource, mappings []config.ResourceMappingConfig, out *model.Metadata) {
+	if len(mappings) == 0 {
+		return
+	}
+	attributes := resource.Attributes()
+	for _, mapping := range mappings {
+		field, ok := metadataFields[mapping.Field]
+		if !ok {
+			continue
+		}
+		v, ok := attributes.Get(mapping.Attribute)
+		if !ok {
+			continue
+		}
+		delete(out.Labels, replaceDots(mapping.Attribute))
+
+		iface := ifaceAttributeValue(v)
+		if iface == nil {
+			continue
+		}
+		value := truncate(fmt.Sprint(iface))
+		if mapping.Field == "service.name" {
+			value = cleanServiceName(value)
+		}
+		if value == "" {
+			continue
+		}
+		if p := field(out); *p == "" || mapping.Override {
+			*p = value
+		}
+	}
+	if len(out.Labels) == 0 {
+		out.Labels = nil
+	}
+}
@@ -0,0 +1,112 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package otel
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+const (
+	scopeNameLabel    = "otel_scope_name"
+	scopeVersionLabel = "otel_scope_version"
+)
+
+// InstrumentationScopeRule holds a rule for renaming, down-sampling,
+// or dropping spans according to their instrumentation scope (library).
+type InstrumentationScopeRule struct {
+	// Name holds the instrumentation scope name to match. The name may
+	// end with a "*" wildcard, in which case it is treated as a prefix.
+	Name string
+
+	// Rename, if non-empty, replaces the recorded instrumentation scope name.
+	Rename string
+
+	// SampleRate holds the fraction of traces for which spans of the
+	// instrumentation scope will be kept.
+	SampleRate float64
+}
+
+// instrumentationScope holds the translated instrumentation scope
+// (library) for a set of spans, after applying any configured rules.
+type instrumentationScope struct {
+	library    pdata.InstrumentationLibrary
+	name       string
+	version    string
+	sampleRate float64
+}
+
+// sampled reports whether spans with the given trace ID should be kept.
+//
+// The decision is made deterministically from the trace ID, so that
+// spans of the same trace are either all kept or all dropped.
+func (s instrumentationScope) sampled(traceID pdata.TraceID) bool {
+	if s.sampleRate >= 1 {
+		return true
+	} else if s.sampleRate <= 0 {
+		return false
+	}
+	bytes := traceID.Bytes()
+	n := binary.BigEndian.Uint64(bytes[8:])
+	return float64(n) < s.sampleRate*math.MaxUint64
+}
+
+// setLabels records the instrumentation scope name and version in labels.
+func (s instrumentationScope) setLabels(labels common.MapStr) {
+	if s.name == "" {
+		return
+	}
+	labels[scopeNameLabel] = s.name
+	if s.version != "" {
+		labels[scopeVersionLabel] = s.version
+	}
+}
+
+// translateInstrumentationScope returns the instrumentation scope for
+// library, applying the first matching rule in rules.
+func translateInstrumentationScope(library pdata.InstrumentationLibrary, rules []InstrumentationScopeRule) instrumentationScope {
+	scope := instrumentationScope{
+		library:    library,
+		name:       library.Name(),
+		version:    library.Version(),
+		sampleRate: 1,
+	}
+	for _, rule := range rules {
+		if !matchInstrumentationScope(rule.Name, scope.name) {
+			continue
+		}
+		if rule.Rename != "" {
+			scope.name = rule.Rename
+		}
+		scope.sampleRate = rule.SampleRate
+		break
+	}
+	return scope
+}
+
+func matchInstrumentationScope(pattern, name string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(name, pattern[:len(pattern)-1])
+	}
+	return pattern == name
+}
@@ -0,0 +1,68 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// DecodeLimiter limits the number of batches of events that may be
+// decoded concurrently by all Processors sharing the limiter.
+type DecodeLimiter struct {
+	sem     *semaphore.Weighted
+	timeout time.Duration
+}
+
+// NewDecodeLimiter returns a DecodeLimiter permitting up to maxDecoders
+// batches to be decoded concurrently. Decoders wait up to timeout for
+// another decoder to finish before giving up.
+func NewDecodeLimiter(maxDecoders int, timeout time.Duration) *DecodeLimiter {
+	return &DecodeLimiter{
+		sem:     semaphore.NewWeighted(int64(maxDecoders)),
+		timeout: timeout,
+	}
+}
+
+// Acquire waits for a decoder to become available, returning false if
+// none becomes available within the limiter's timeout, or before ctx
+// is done. Acquire always succeeds for a nil DecodeLimiter.
+//
+// Callers should acquire a decoder only once the data to decode has
+// been read, so that decoders are not held while waiting on clients.
+func (l *DecodeLimiter) Acquire(ctx context.Context) bool {
+	if l == nil {
+		return true
+	}
+	if l.sem.TryAcquire(1) {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+	return l.sem.Acquire(ctx, 1) == nil
+}
+
+// Release releases a decoder acquired by a successful call to Acquire.
+func (l *DecodeLimiter) Release() {
+	if l == nil {
+		return
+	}
+	l.sem.Release(1)
+}
@@ -0,0 +1,109 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"context"
+	"io"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+)
+
+// SynthesizedMetadataLabel holds the name of the label with which events
+// are tagged when their metadata is synthesized by MissingMetadataPolicy.
+const SynthesizedMetadataLabel = "apm_server_synthesized_metadata"
+
+var mSynthesizedMetadata = monitoring.NewInt(m, "metadata.synthesized")
+
+type serviceNameKey struct{}
+
+// ContextWithServiceName returns a copy of ctx with serviceName, the service
+// name specified by the request, e.g. in a header. The service name is used
+// when synthesizing metadata for streams without valid metadata.
+func ContextWithServiceName(ctx context.Context, serviceName string) context.Context {
+	return context.WithValue(ctx, serviceNameKey{}, serviceName)
+}
+
+// MissingMetadataPolicy synthesizes minimal metadata for streams whose first
+// line is missing, or is not a valid metadata object, so that constrained
+// senders may omit metadata. Events with synthesized metadata are tagged
+// with the label SynthesizedMetadataLabel.
+type MissingMetadataPolicy struct {
+	// DefaultServiceName holds the service name of synthesized metadata
+	// for requests which do not specify a service name. If empty, streams
+	// without valid metadata from such requests are rejected.
+	DefaultServiceName string
+}
+
+// serviceName returns the service name of synthesized metadata for the
+// request with context ctx, or an empty string if there is none.
+func (p *MissingMetadataPolicy) serviceName(ctx context.Context) string {
+	if name, _ := ctx.Value(serviceNameKey{}).(string); name != "" {
+		return name
+	}
+	return p.DefaultServiceName
+}
+
+// readOrSynthesizeMetadata reads the stream's metadata into meta like
+// readMetadata, but synthesizes meta if the first line of the stream is
+// not a metadata object, or is an invalid metadata object followed by
+// events. An invalid metadata object is recorded as an error in res.
+//
+// If metadata cannot be synthesized because the request has no service
+// name, the stream is rejected as it would be without a policy.
+func (p *Processor) readOrSynthesizeMetadata(ctx context.Context, reader *streamReader, meta *model.Metadata, res *Result) error {
+	serviceName := p.MissingMetadata.serviceName(ctx)
+	if serviceName == "" {
+		return p.readMetadata(reader, meta)
+	}
+	body, err := reader.ReadAhead()
+	if (err != nil && err != io.EOF) || len(body) == 0 {
+		return p.readMetadata(reader, meta)
+	}
+	switch string(p.IdentifyEventType(body)) {
+	case metadataEventType, rumv3MetadataEventType:
+		decoded := *meta
+		err := p.readMetadata(reader, &decoded)
+		if err == nil {
+			*meta = decoded
+			return nil
+		}
+		if reader.IsEOF() {
+			// There are no events following the invalid metadata.
+			return err
+		}
+		res.LimitedAdd(err)
+	default:
+		// The first line is an event: leave it to be read by readBatch.
+		reader.unread = true
+	}
+	meta.Service.Name = serviceName
+	// meta.Labels may be shared with other streams, so it is copied
+	// rather than modified.
+	labels := make(common.MapStr, len(meta.Labels)+1)
+	for k, v := range meta.Labels {
+		labels[k] = v
+	}
+	labels[SynthesizedMetadataLabel] = true
+	meta.Labels = labels
+	mSynthesizedMetadata.Inc()
+	return nil
+}
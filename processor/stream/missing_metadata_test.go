@@ -0,0 +1,140 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/model"
+)
+
+func TestMissingMetadata(t *testing.T) {
+	validMetadata := `{"metadata": {"service": {"name": "service-a", "agent": {"name": "go", "version": "1.0.0"}}}}`
+	invalidMetadata := `{"metadata": {"service": {}}}`
+	event1 := `{"error": {"id": "1", "log": {"message": "one"}}}`
+	event2 := `{"error": {"id": "2", "log": {"message": "two"}}}`
+
+	type expectation struct {
+		accepted    int
+		errors      int
+		serviceName string
+		synthesized bool
+	}
+	for name, test := range map[string]struct {
+		body               []string
+		ctx                context.Context
+		defaultServiceName string
+		reject             expectation
+		synthesize         expectation
+	}{
+		"valid metadata": {
+			body:               []string{validMetadata, event1},
+			defaultServiceName: "sensor",
+			reject:             expectation{accepted: 1, serviceName: "service-a"},
+			synthesize:         expectation{accepted: 1, serviceName: "service-a"},
+		},
+		"missing metadata": {
+			body:               []string{event1, event2},
+			defaultServiceName: "sensor",
+			reject:             expectation{errors: 1},
+			synthesize:         expectation{accepted: 2, serviceName: "sensor", synthesized: true},
+		},
+		"missing metadata single event": {
+			body:               []string{event1},
+			defaultServiceName: "sensor",
+			reject:             expectation{errors: 1},
+			synthesize:         expectation{accepted: 1, serviceName: "sensor", synthesized: true},
+		},
+		"invalid metadata": {
+			body:               []string{invalidMetadata, event1},
+			defaultServiceName: "sensor",
+			reject:             expectation{errors: 1},
+			synthesize:         expectation{accepted: 1, errors: 1, serviceName: "sensor", synthesized: true},
+		},
+		"invalid metadata without events": {
+			body:               []string{invalidMetadata},
+			defaultServiceName: "sensor",
+			reject:             expectation{errors: 1},
+			synthesize:         expectation{errors: 1},
+		},
+		"service name from request": {
+			body:               []string{event1},
+			ctx:                ContextWithServiceName(context.Background(), "thermostat"),
+			defaultServiceName: "sensor",
+			reject:             expectation{errors: 1},
+			synthesize:         expectation{accepted: 1, serviceName: "thermostat", synthesized: true},
+		},
+		"no service name": {
+			body:       []string{event1},
+			reject:     expectation{errors: 1},
+			synthesize: expectation{errors: 1},
+		},
+	} {
+		for action, expected := range map[string]expectation{
+			config.MissingMetadataActionReject:     test.reject,
+			config.MissingMetadataActionSynthesize: test.synthesize,
+		} {
+			t.Run(name+"/"+action, func(t *testing.T) {
+				ctx := test.ctx
+				if ctx == nil {
+					ctx = context.Background()
+				}
+				var events []*model.Error
+				batchProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+					events = append(events, batch.Errors...)
+					return nil
+				})
+				p := BackendProcessor(&config.Config{
+					MaxEventSize: 100 * 1024,
+					MissingMetadata: config.MissingMetadataConfig{
+						Action:             action,
+						DefaultServiceName: test.defaultServiceName,
+					},
+				})
+				requestLabels := common.MapStr{"gateway": "gw-1"}
+				baseMetadata := model.Metadata{
+					UserAgent: model.UserAgent{Original: "request-user-agent"},
+					Labels:    requestLabels,
+				}
+				result := p.HandleStream(ctx, nil, &baseMetadata, strings.NewReader(strings.Join(test.body, "\n")), batchProcessor)
+				// The labels of the request's metadata are merged, not modified.
+				assert.Equal(t, common.MapStr{"gateway": "gw-1"}, requestLabels)
+				assert.Len(t, result.Errors, expected.errors)
+				assert.Equal(t, expected.accepted, result.Accepted)
+				require.Len(t, events, expected.accepted)
+				for _, event := range events {
+					assert.Equal(t, expected.serviceName, event.Metadata.Service.Name)
+					assert.Equal(t, "request-user-agent", event.Metadata.UserAgent.Original)
+					if expected.synthesized {
+						assert.Equal(t, common.MapStr{"gateway": "gw-1", SynthesizedMetadataLabel: true}, event.Metadata.Labels)
+					} else {
+						assert.Equal(t, common.MapStr{"gateway": "gw-1"}, event.Metadata.Labels)
+					}
+				}
+			})
+		}
+	}
+}
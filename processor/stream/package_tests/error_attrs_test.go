@@ -55,7 +55,9 @@ func errorFieldsNotInPayloadAttrs() *tests.Set {
 	return tests.NewSet(
 		"view errors", "error id icon",
 		"host.ip", "transaction.name", "source.ip",
-		"error.grouping_name", // added by ingest node
+		"error.grouping_name",            // added by ingest node
+		"expires_at",                     // added when expiry is configured
+		"error.exception.stacktrace_ref", // added when stacktrace deduplication is enabled
 		tests.Group("event"),
 		tests.Group("observer"),
 		tests.Group("user"),
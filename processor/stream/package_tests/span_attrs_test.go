@@ -55,6 +55,7 @@ func spanFieldsNotInPayloadAttrs() *tests.Set {
 			"transaction.sampled",
 			"transaction.type",
 			"transaction.name",
+			"expires_at", // added when expiry is configured
 			tests.Group("container"),
 			tests.Group("host"),
 			tests.Group("kubernetes"),
@@ -58,6 +58,9 @@ func transactionFieldsNotInPayloadAttrs() *tests.Set {
 		"host.name",
 		"transaction.duration.count",
 		"transaction.marks.*.*",
+		"expires_at", // added when expiry is configured
+		tests.Group("transaction.marks.agent"),
+		tests.Group("transaction.marks.navigationTiming"),
 		tests.Group("observer"),
 		tests.Group("user"),
 		tests.Group("client"),
@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"regexp"
 	"sync"
 	"time"
 
@@ -30,8 +31,14 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/errorcode"
 	"github.com/elastic/apm-server/decoder"
+	"github.com/elastic/apm-server/featureflag"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/model/modeldecoder"
 	"github.com/elastic/apm-server/model/modeldecoder/rumv3"
@@ -49,10 +56,12 @@ const (
 	batchSize = 10
 
 	errorEventType            = "error"
+	metadataEventType         = "metadata"
 	metricsetEventType        = "metricset"
 	spanEventType             = "span"
 	transactionEventType      = "transaction"
 	rumv3ErrorEventType       = "e"
+	rumv3MetadataEventType    = "m"
 	rumv3TransactionEventType = "x"
 	rumv3MetricsetEventType   = "me"
 )
@@ -66,14 +75,32 @@ type Processor struct {
 	decodeMetadata      decodeMetadataFunc
 	isRUM               bool
 	allowedServiceNames map[string]bool
+
+	// botTraffic classifies RUM events sent from bots,
+	// or is nil if bot traffic classification is disabled.
+	botTraffic model.BatchProcessor
+
+	// fastValidationAgents maps agent names to the minimum agent
+	// version for which event validation is skipped.
+	fastValidationAgents map[string]*common.Version
+
+	// DecodeLimiter, if non-nil, limits the number of batches of
+	// events decoded concurrently across all streams.
+	DecodeLimiter *DecodeLimiter
+
+	// MissingMetadata, if non-nil, synthesizes metadata for streams
+	// without valid metadata, instead of rejecting them.
+	MissingMetadata *MissingMetadataPolicy
 }
 
 func BackendProcessor(cfg *config.Config) *Processor {
 	return &Processor{
-		Mconfig:        modeldecoder.Config{Experimental: cfg.Mode == config.ModeExperimental},
-		MaxEventSize:   cfg.MaxEventSize,
-		decodeMetadata: v2.DecodeNestedMetadata,
-		isRUM:          false,
+		Mconfig:              modeldecoder.Config{Experimental: cfg.Mode == config.ModeExperimental},
+		MaxEventSize:         cfg.MaxEventSize,
+		decodeMetadata:       v2.DecodeNestedMetadata,
+		isRUM:                false,
+		fastValidationAgents: makeFastValidationAgentsMap(cfg),
+		MissingMetadata:      makeMissingMetadataPolicy(cfg.MissingMetadata),
 	}
 }
 
@@ -84,6 +111,7 @@ func RUMV2Processor(cfg *config.Config) *Processor {
 		decodeMetadata:      v2.DecodeNestedMetadata,
 		isRUM:               true,
 		allowedServiceNames: makeAllowedServiceNamesMap(cfg.RumConfig.AllowServiceNames),
+		botTraffic:          makeBotTrafficProcessor(cfg.RumConfig.BotTraffic),
 	}
 }
 
@@ -94,9 +122,15 @@ func RUMV3Processor(cfg *config.Config) *Processor {
 		decodeMetadata:      rumv3.DecodeNestedMetadata,
 		isRUM:               true,
 		allowedServiceNames: makeAllowedServiceNamesMap(cfg.RumConfig.AllowServiceNames),
+		botTraffic:          makeBotTrafficProcessor(cfg.RumConfig.BotTraffic),
 	}
 }
 
+// IsRUM reports whether p processes events sent by RUM agents.
+func (p *Processor) IsRUM() bool {
+	return p.isRUM
+}
+
 func makeAllowedServiceNamesMap(allowed []string) map[string]bool {
 	if len(allowed) == 0 {
 		return nil
@@ -108,8 +142,76 @@ func makeAllowedServiceNamesMap(allowed []string) map[string]bool {
 	return m
 }
 
+// mBotTrafficDropped counts events dropped by bot traffic classification.
+var mBotTrafficDropped = monitoring.NewInt(m, "bot_traffic.dropped")
+
+// makeBotTrafficProcessor returns a model.BatchProcessor for tagging or dropping
+// events sent from bots, or nil if bot traffic classification is disabled.
+func makeBotTrafficProcessor(cfg config.BotTrafficConfig) model.BatchProcessor {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &modelprocessor.ClassifyBotTraffic{
+		UserAgentPattern: regexp.MustCompile(cfg.UserAgentPattern),
+		Drop:             cfg.Action == config.BotTrafficActionDrop,
+		Dropped:          mBotTrafficDropped,
+	}
+}
+
+// makeMissingMetadataPolicy returns a MissingMetadataPolicy for synthesizing
+// metadata, or nil if streams without valid metadata must be rejected.
+func makeMissingMetadataPolicy(cfg config.MissingMetadataConfig) *MissingMetadataPolicy {
+	if cfg.Action != config.MissingMetadataActionSynthesize {
+		return nil
+	}
+	return &MissingMetadataPolicy{DefaultServiceName: cfg.DefaultServiceName}
+}
+
+// makeFastValidationAgentsMap returns the minimum agent versions for which
+// event validation is skipped, or nil if validation must not be skipped.
+func makeFastValidationAgentsMap(cfg *config.Config) map[string]*common.Version {
+	if !cfg.FastValidation.Enabled {
+		return nil
+	}
+	m := make(map[string]*common.Version, len(cfg.FastValidation.MinAgentVersions))
+	for name, version := range cfg.FastValidation.MinAgentVersions {
+		v, err := common.NewVersion(version)
+		if err != nil {
+			// Versions are checked when the config is unpacked.
+			continue
+		}
+		m[name] = v
+	}
+	return m
+}
+
+// skipValidation reports whether validation may be skipped for events
+// with the given metadata, based on the agent name and version.
+//
+// Validation is only ever skipped for requests authenticated with a secret
+// token or API Key, so that anonymous clients, and clients exempt from
+// authorization, cannot send unvalidated events.
+func (p *Processor) skipValidation(ctx context.Context, metadata *model.Metadata) bool {
+	if p.fastValidationAgents == nil || !featureflag.Enabled(featureflag.FastValidation) {
+		return false
+	}
+	if !authorization.Authenticated(ctx) {
+		return false
+	}
+	minVersion, ok := p.fastValidationAgents[metadata.Service.Agent.Name]
+	if !ok {
+		return false
+	}
+	version, err := common.NewVersion(metadata.Service.Agent.Version)
+	if err != nil {
+		return false
+	}
+	return !version.LessThan(minVersion)
+}
+
 func (p *Processor) readMetadata(reader *streamReader, metadata *model.Metadata) error {
 	if err := p.decodeMetadata(reader, metadata); err != nil {
+		validationErrors.record(err, metadata)
 		err = reader.wrapError(err)
 		if err == io.EOF {
 			return &Error{
@@ -123,10 +225,12 @@ func (p *Processor) readMetadata(reader *streamReader, metadata *model.Metadata)
 		}
 		return &Error{
 			Type:     InvalidInputErrType,
+			Code:     invalidInputErrorCode(err),
 			Message:  err.Error(),
 			Document: string(reader.LatestLine()),
 		}
 	}
+	reader.writeValidLine()
 	return nil
 }
 
@@ -161,10 +265,17 @@ func (p *Processor) IdentifyEventType(body []byte) []byte {
 // readBatch will read up to `batchSize` objects from the ndjson stream,
 // adding events to batch and returning a boolean indicating that there
 // might be more to read.
+//
+// Metadata objects may appear mid-stream, in which case they replace
+// streamMetadata for all subsequent events in the stream. Mid-stream
+// metadata is decoded on top of baseMetadata, which holds the metadata
+// derived from the request, so that metadata is not mixed between the
+// metadata objects of a stream.
 func (p *Processor) readBatch(
 	ctx context.Context,
 	ipRateLimiter *rate.Limiter,
 	requestTime time.Time,
+	baseMetadata model.Metadata,
 	streamMetadata *model.Metadata,
 	batchSize int,
 	batch *model.Batch,
@@ -174,9 +285,11 @@ func (p *Processor) readBatch(
 
 	if ipRateLimiter != nil {
 		// use provided rate limiter to throttle batch read
+		start := time.Now()
 		ctxT, cancel := context.WithTimeout(ctx, time.Second)
 		err := ipRateLimiter.WaitN(ctxT, batchSize)
 		cancel()
+		utility.PhaseTimingsFromContext(ctx).Add(utility.PhaseRateLimit, time.Since(start))
 		if err != nil {
 			response.Add(&Error{
 				Type:    RateLimitErrType,
@@ -186,6 +299,9 @@ func (p *Processor) readBatch(
 		}
 	}
 
+	skipValidation := p.skipValidation(ctx, streamMetadata)
+	timings := utility.PhaseTimingsFromContext(ctx)
+
 	// input events are decoded and appended to the batch
 	for i := 0; i < batchSize && !reader.IsEOF(); i++ {
 		body, err := reader.ReadAhead()
@@ -205,88 +321,129 @@ func (p *Processor) readBatch(
 			continue
 		}
 		input := modeldecoder.Input{
-			RequestTime: requestTime,
-			Metadata:    *streamMetadata,
-			Config:      p.Mconfig,
+			RequestTime:    requestTime,
+			Metadata:       *streamMetadata,
+			Config:         p.Mconfig,
+			SkipValidation: skipValidation,
+			PhaseTimings:   timings,
 		}
-		switch eventType := p.IdentifyEventType(body); string(eventType) {
-		case errorEventType:
-			var event model.Error
-			err := v2.DecodeNestedError(reader, &input, &event)
-			if handleDecodeErr(err, reader, response) {
-				continue
-			}
-			event.RUM = p.isRUM
-			batch.Errors = append(batch.Errors, &event)
-		case metricsetEventType:
-			var event model.Metricset
-			err := v2.DecodeNestedMetricset(reader, &input, &event)
-			if handleDecodeErr(err, reader, response) {
-				continue
-			}
-			batch.Metricsets = append(batch.Metricsets, &event)
-		case spanEventType:
-			var event model.Span
-			err := v2.DecodeNestedSpan(reader, &input, &event)
-			if handleDecodeErr(err, reader, response) {
-				continue
-			}
-			event.RUM = p.isRUM
-			batch.Spans = append(batch.Spans, &event)
-		case transactionEventType:
-			var event model.Transaction
-			err := v2.DecodeNestedTransaction(reader, &input, &event)
-			if handleDecodeErr(err, reader, response) {
-				continue
-			}
-			batch.Transactions = append(batch.Transactions, &event)
-		case rumv3ErrorEventType:
-			var event model.Error
-			err := rumv3.DecodeNestedError(reader, &input, &event)
-			if handleDecodeErr(err, reader, response) {
-				continue
-			}
-			event.RUM = p.isRUM
-			batch.Errors = append(batch.Errors, &event)
-		case rumv3MetricsetEventType:
-			var event model.Metricset
-			err := rumv3.DecodeNestedMetricset(reader, &input, &event)
-			if handleDecodeErr(err, reader, response) {
-				continue
-			}
-			batch.Metricsets = append(batch.Metricsets, &event)
-		case rumv3TransactionEventType:
-			var event rumv3.Transaction
-			err := rumv3.DecodeNestedTransaction(reader, &input, &event)
-			if handleDecodeErr(err, reader, response) {
-				continue
-			}
-			batch.Transactions = append(batch.Transactions, &event.Transaction)
-			batch.Metricsets = append(batch.Metricsets, event.Metricsets...)
-			for _, span := range event.Spans {
-				span.RUM = true
-				batch.Spans = append(batch.Spans, span)
-			}
-		default:
-			response.LimitedAdd(&Error{
-				Type:     InvalidInputErrType,
-				Message:  errors.Wrap(ErrUnrecognizedObject, string(eventType)).Error(),
-				Document: string(reader.LatestLine()),
+		// Acquire a decoder only once the event has been read into
+		// the buffer, so decoders are not held while waiting for
+		// agents to send more data.
+		if !p.DecodeLimiter.Acquire(ctx) {
+			response.Add(&Error{
+				Type:    QueueFullErrType,
+				Message: "too many concurrent decoders",
 			})
-			continue
+			return true
 		}
+		p.decodeEvent(ctx, body, reader, &input, baseMetadata, streamMetadata, &skipValidation, batch, response)
+		p.DecodeLimiter.Release()
 	}
 	return reader.IsEOF()
 }
 
-func handleDecodeErr(err error, r *streamReader, result *Result) bool {
+// decodeEvent decodes the event in body, which has been read ahead by reader,
+// adding it to batch. If the event is a metadata object, it replaces
+// streamMetadata, and skipValidation is updated for subsequent events.
+func (p *Processor) decodeEvent(
+	ctx context.Context,
+	body []byte,
+	reader *streamReader,
+	input *modeldecoder.Input,
+	baseMetadata model.Metadata,
+	streamMetadata *model.Metadata,
+	skipValidation *bool,
+	batch *model.Batch,
+	response *Result,
+) {
+	switch eventType := p.IdentifyEventType(body); string(eventType) {
+	case metadataEventType, rumv3MetadataEventType:
+		metadata := baseMetadata
+		err := p.decodeMetadata(reader, &metadata)
+		if handleDecodeErr(err, &metadata, reader, response) {
+			return
+		}
+		*streamMetadata = metadata
+		*skipValidation = p.skipValidation(ctx, streamMetadata)
+	case errorEventType:
+		var event model.Error
+		err := v2.DecodeNestedError(reader, input, &event)
+		if handleDecodeErr(err, streamMetadata, reader, response) {
+			return
+		}
+		event.RUM = p.isRUM
+		batch.Errors = append(batch.Errors, &event)
+	case metricsetEventType:
+		var event model.Metricset
+		err := v2.DecodeNestedMetricset(reader, input, &event)
+		if handleDecodeErr(err, streamMetadata, reader, response) {
+			return
+		}
+		batch.Metricsets = append(batch.Metricsets, &event)
+	case spanEventType:
+		var event model.Span
+		err := v2.DecodeNestedSpan(reader, input, &event)
+		if handleDecodeErr(err, streamMetadata, reader, response) {
+			return
+		}
+		event.RUM = p.isRUM
+		batch.Spans = append(batch.Spans, &event)
+	case transactionEventType:
+		var event model.Transaction
+		err := v2.DecodeNestedTransaction(reader, input, &event)
+		if handleDecodeErr(err, streamMetadata, reader, response) {
+			return
+		}
+		batch.Transactions = append(batch.Transactions, &event)
+	case rumv3ErrorEventType:
+		var event model.Error
+		err := rumv3.DecodeNestedError(reader, input, &event)
+		if handleDecodeErr(err, streamMetadata, reader, response) {
+			return
+		}
+		event.RUM = p.isRUM
+		batch.Errors = append(batch.Errors, &event)
+	case rumv3MetricsetEventType:
+		var event model.Metricset
+		err := rumv3.DecodeNestedMetricset(reader, input, &event)
+		if handleDecodeErr(err, streamMetadata, reader, response) {
+			return
+		}
+		batch.Metricsets = append(batch.Metricsets, &event)
+	case rumv3TransactionEventType:
+		var event rumv3.Transaction
+		err := rumv3.DecodeNestedTransaction(reader, input, &event)
+		if handleDecodeErr(err, streamMetadata, reader, response) {
+			return
+		}
+		batch.Transactions = append(batch.Transactions, &event.Transaction)
+		batch.Metricsets = append(batch.Metricsets, event.Metricsets...)
+		for _, span := range event.Spans {
+			span.RUM = true
+			batch.Spans = append(batch.Spans, span)
+		}
+	default:
+		response.LimitedAdd(&Error{
+			Type:     InvalidInputErrType,
+			Message:  errors.Wrap(ErrUnrecognizedObject, string(eventType)).Error(),
+			Document: string(reader.LatestLine()),
+		})
+		return
+	}
+	reader.writeValidLine()
+}
+
+func handleDecodeErr(err error, metadata *model.Metadata, r *streamReader, result *Result) bool {
 	if err == nil || err == io.EOF {
 		return false
 	}
+	validationErrors.record(err, metadata)
 	e, ok := err.(*Error)
 	if !ok || (e.Type != InvalidInputErrType && e.Type != InputTooLargeErrType) {
 		e = &Error{
 			Type:     InvalidInputErrType,
+			Code:     invalidInputErrorCode(err),
 			Message:  err.Error(),
 			Document: string(r.LatestLine()),
 		}
@@ -295,18 +452,53 @@ func handleDecodeErr(err error, r *streamReader, result *Result) bool {
 	return true
 }
 
+// invalidInputErrorCode returns the error code for invalid input which
+// caused err: input which could not be decoded is reported as a decode
+// error, and all other invalid input as a validation error.
+func invalidInputErrorCode(err error) errorcode.Code {
+	if _, ok := err.(modeldecoder.DecoderError); ok {
+		return errorcode.Decode
+	}
+	return errorcode.Validate
+}
+
 // HandleStream processes a stream of events
 func (p *Processor) HandleStream(ctx context.Context, ipRateLimiter *rate.Limiter, meta *model.Metadata, reader io.Reader, processor model.BatchProcessor) *Result {
 	res := &Result{}
+	p.HandleStreamResult(ctx, ipRateLimiter, meta, reader, processor, res)
+	return res
+}
 
+// HandleStreamResult processes a stream of events, recording accepted events
+// and errors in res as processing proceeds. Callers may use a Result created
+// with NewResult to observe the outcome of processing incrementally.
+func (p *Processor) HandleStreamResult(ctx context.Context, ipRateLimiter *rate.Limiter, meta *model.Metadata, reader io.Reader, processor model.BatchProcessor, res *Result) {
 	sr := p.getStreamReader(reader)
+	sr.validLines = validLinesFromContext(ctx)
 	defer sr.release()
 
+	// Time spent decoding is recorded against the decode phase, excluding
+	// the time recorded against other phases while decoding, such as
+	// rate limiting, decompression and validation.
+	timings := utility.PhaseTimingsFromContext(ctx)
+	decodeStart, otherPhases := time.Now(), timings.Total()
+	recordDecode := func() {
+		timings.Add(utility.PhaseDecode, time.Since(decodeStart)-(timings.Total()-otherPhases))
+	}
+
 	// first item is the metadata object
-	if err := p.readMetadata(sr, meta); err != nil {
+	baseMetadata := *meta
+	var err error
+	if p.MissingMetadata != nil {
+		err = p.readOrSynthesizeMetadata(ctx, sr, meta, res)
+	} else {
+		err = p.readMetadata(sr, meta)
+	}
+	recordDecode()
+	if err != nil {
 		// no point in continuing if we couldn't read the metadata
 		res.Add(err)
-		return res
+		return
 	}
 
 	var allowedServiceNamesProcessor model.BatchProcessor = modelprocessor.Nop{}
@@ -321,13 +513,25 @@ func (p *Processor) HandleStream(ctx context.Context, ipRateLimiter *rate.Limite
 	var done bool
 	for !done {
 		var batch model.Batch
-		done = p.readBatch(ctx, ipRateLimiter, requestTime, meta, batchSize, &batch, sr, res)
+		decodeStart, otherPhases = time.Now(), timings.Total()
+		done = p.readBatch(ctx, ipRateLimiter, requestTime, baseMetadata, meta, batchSize, &batch, sr, res)
+		recordDecode()
 		if batch.Len() == 0 {
 			continue
 		}
 		if err := allowedServiceNamesProcessor.ProcessBatch(ctx, &batch); err != nil {
 			res.Add(err)
-			return res
+			return
+		}
+		if p.botTraffic != nil {
+			if err := p.botTraffic.ProcessBatch(ctx, &batch); err != nil {
+				res.Add(err)
+				return
+			}
+			if batch.Len() == 0 {
+				// All events were dropped.
+				continue
+			}
 		}
 
 		// NOTE(axw) ProcessBatch takes ownership of batch, which means we cannot reuse
@@ -335,7 +539,10 @@ func (p *Processor) HandleStream(ctx context.Context, ipRateLimiter *rate.Limite
 		// processor and publisher which would enable better memory reuse, e.g. by using
 		// a sync.Pool for creating batches, and having the publisher (terminal processor)
 		// release batches back into the pool.
-		if err := processor.ProcessBatch(ctx, &batch); err != nil {
+		publishStart := time.Now()
+		err := processor.ProcessBatch(ctx, &batch)
+		timings.Add(utility.PhasePublish, time.Since(publishStart))
+		if err != nil {
 			switch err {
 			case publish.ErrChannelClosed:
 				res.Add(&Error{
@@ -348,13 +555,20 @@ func (p *Processor) HandleStream(ctx context.Context, ipRateLimiter *rate.Limite
 					Message: err.Error(),
 				})
 			default:
+				var serviceErr *authorization.ServiceUnauthorizedError
+				if errors.As(err, &serviceErr) {
+					res.Add(&Error{
+						Type:    ForbiddenErrType,
+						Message: err.Error(),
+					})
+					return
+				}
 				res.Add(err)
 			}
-			return res
+			return
 		}
 		res.AddAccepted(batch.Len())
 	}
-	return res
 }
 
 func (p *Processor) restrictAllowedServiceNames(ctx context.Context, meta *model.Metadata) error {
@@ -386,15 +600,53 @@ func (p *Processor) getStreamReader(r io.Reader) *streamReader {
 type streamReader struct {
 	processor *Processor
 	*decoder.NDJSONStreamDecoder
+
+	// unread reports whether the latest line has been read ahead,
+	// but must be returned again by the next call to ReadAhead.
+	unread bool
+
+	// validLines, if non-nil, is written each line which is
+	// decoded successfully.
+	validLines io.Writer
+}
+
+// ReadAhead reads the next NDJSON line, or returns the latest line again
+// if it has been marked as unread.
+func (sr *streamReader) ReadAhead() ([]byte, error) {
+	if sr.unread {
+		sr.unread = false
+		if sr.NDJSONStreamDecoder.IsEOF() {
+			return sr.LatestLine(), io.EOF
+		}
+		return sr.LatestLine(), nil
+	}
+	return sr.NDJSONStreamDecoder.ReadAhead()
+}
+
+// IsEOF reports whether the underlying reader reached the end,
+// and there is no unread line.
+func (sr *streamReader) IsEOF() bool {
+	return !sr.unread && sr.NDJSONStreamDecoder.IsEOF()
 }
 
 // release releases the streamReader, adding it to its Processor's sync.Pool.
 // The streamReader must not be used after release returns.
 func (sr *streamReader) release() {
 	sr.Reset(nil)
+	sr.unread = false
+	sr.validLines = nil
 	sr.processor.streamReaderPool.Put(sr)
 }
 
+// writeValidLine writes the latest line, which has been decoded
+// successfully, to sr.validLines.
+func (sr *streamReader) writeValidLine() {
+	if sr.validLines != nil {
+		sr.validLines.Write(sr.LatestLine())
+		sr.validLines.Write([]byte{'\n'})
+	}
+}
+
 func (sr *streamReader) wrapError(err error) error {
 	if err == nil {
 		return nil
@@ -402,6 +654,7 @@ func (sr *streamReader) wrapError(err error) error {
 	if _, ok := err.(decoder.JSONDecodeError); ok {
 		return &Error{
 			Type:     InvalidInputErrType,
+			Code:     errorcode.Decode,
 			Message:  err.Error(),
 			Document: string(sr.LatestLine()),
 		}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"net"
 	"path/filepath"
+	"strings"
 	"testing"
 	"testing/iotest"
 	"time"
@@ -32,9 +33,14 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/time/rate"
 
+	"github.com/elastic/beats/v7/libbeat/common"
+
 	"github.com/elastic/apm-server/approvaltest"
+	"github.com/elastic/apm-server/beater/authorization"
 	"github.com/elastic/apm-server/beater/beatertest"
 	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/beater/errorcode"
+	"github.com/elastic/apm-server/featureflag"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/model/modelprocessor"
 	"github.com/elastic/apm-server/publish"
@@ -210,7 +216,7 @@ func TestRUMAllowedServiceNames(t *testing.T) {
 		AllowServiceNames: []string{"reject_everything"},
 		ExpectedResult: &Result{
 			Accepted: 0,
-			Errors:   []*Error{{Type: InvalidInputErrType, Message: "service name is not allowed"}},
+			Errors:   []*Error{{Type: InvalidInputErrType, Message: "service name is not allowed", Code: errorcode.Validate}},
 		},
 	}} {
 		p := RUMV2Processor(&config.Config{
@@ -227,6 +233,123 @@ func TestRUMAllowedServiceNames(t *testing.T) {
 	}
 }
 
+func TestRUMBotTraffic(t *testing.T) {
+	for name, test := range map[string]struct {
+		BotTraffic     config.BotTrafficConfig
+		UserAgent      string
+		ExpectedBot    bool
+		ExpectedEvents int
+	}{
+		"disabled": {
+			BotTraffic:     config.BotTrafficConfig{Action: config.BotTrafficActionDrop, UserAgentPattern: "bot"},
+			UserAgent:      "Googlebot/2.1",
+			ExpectedEvents: 2,
+		},
+		"tag": {
+			BotTraffic:     config.BotTrafficConfig{Enabled: true, Action: config.BotTrafficActionTag, UserAgentPattern: "bot"},
+			UserAgent:      "Googlebot/2.1",
+			ExpectedBot:    true,
+			ExpectedEvents: 2,
+		},
+		"drop": {
+			BotTraffic: config.BotTrafficConfig{Enabled: true, Action: config.BotTrafficActionDrop, UserAgentPattern: "bot"},
+			UserAgent:  "Googlebot/2.1",
+		},
+		"no_match": {
+			BotTraffic:     config.BotTrafficConfig{Enabled: true, Action: config.BotTrafficActionDrop, UserAgentPattern: "bot"},
+			UserAgent:      "Mozilla/5.0",
+			ExpectedEvents: 2,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := RUMV2Processor(&config.Config{
+				MaxEventSize: 100 * 1024,
+				RumConfig:    &config.RumConfig{BotTraffic: test.BotTraffic},
+			})
+
+			b, err := loader.LoadDataAsBytes(filepath.Join("../testdata/intake-v2/transactions_spans_rum.ndjson"))
+			require.NoError(t, err)
+
+			var events int
+			batchProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+				events += batch.Len()
+				for _, tx := range batch.Transactions {
+					assert.Equal(t, test.ExpectedBot, tx.Metadata.UserAgent.Bot)
+				}
+				for _, span := range batch.Spans {
+					assert.Equal(t, test.ExpectedBot, span.Metadata.UserAgent.Bot)
+				}
+				return nil
+			})
+			meta := model.Metadata{UserAgent: model.UserAgent{Original: test.UserAgent}}
+			result := p.HandleStream(context.Background(), nil, &meta, bytes.NewBuffer(b), batchProcessor)
+			assert.Empty(t, result.Errors)
+			assert.Equal(t, test.ExpectedEvents, result.Accepted)
+			assert.Equal(t, test.ExpectedEvents, events)
+		})
+	}
+}
+
+func TestMidStreamMetadata(t *testing.T) {
+	body := strings.Join([]string{
+		`{"metadata": {"service": {"name": "service-a", "agent": {"name": "go", "version": "1.0.0"}}, "labels": {"a": "b"}}}`,
+		`{"error": {"id": "1", "log": {"message": "one"}}}`,
+		`{"metadata": {"service": {"name": "service-b", "agent": {"name": "go", "version": "1.0.0"}}}}`,
+		`{"error": {"id": "2", "log": {"message": "two"}}}`,
+		`{"metadata": {"service": {}}}`,
+		`{"error": {"id": "3", "log": {"message": "three"}}}`,
+	}, "\n")
+
+	var batches []*model.Batch
+	batchProcessor := model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	baseMetadata := model.Metadata{UserAgent: model.UserAgent{Original: "request-user-agent"}}
+	result := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024}).HandleStream(
+		context.Background(), nil, &baseMetadata, strings.NewReader(body), batchProcessor,
+	)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, InvalidInputErrType, result.Errors[0].Type)
+	assert.Equal(t, 3, result.Accepted)
+
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Errors, 3)
+	events := batches[0].Errors
+
+	assert.Equal(t, "service-a", events[0].Metadata.Service.Name)
+	assert.Equal(t, common.MapStr{"a": "b"}, events[0].Metadata.Labels)
+	assert.Equal(t, "request-user-agent", events[0].Metadata.UserAgent.Original)
+
+	// Metadata is replaced, not merged with the previous metadata.
+	assert.Equal(t, "service-b", events[1].Metadata.Service.Name)
+	assert.Nil(t, events[1].Metadata.Labels)
+	assert.Equal(t, "request-user-agent", events[1].Metadata.UserAgent.Original)
+
+	// Invalid mid-stream metadata is reported, and the previous metadata is retained.
+	assert.Equal(t, "service-b", events[2].Metadata.Service.Name)
+}
+
+func TestValidLines(t *testing.T) {
+	lines := []string{
+		`{"metadata": {"service": {"name": "service-a", "agent": {"name": "go", "version": "1.0.0"}}}}`,
+		`{"error": {"id": "1", "log": {"message": "one"}}}`,
+		`{"error": {"id": "2"}}`,
+		`{"metadata": {"service": {}}}`,
+		`{"unknown": {}}`,
+		`{"error": {"id": "3", "log": {"message": "three"}}}`,
+	}
+
+	var validLines bytes.Buffer
+	ctx := ContextWithValidLines(context.Background(), &validLines)
+	result := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024}).HandleStream(
+		ctx, nil, &model.Metadata{}, strings.NewReader(strings.Join(lines, "\n")), modelprocessor.Nop{},
+	)
+	assert.Len(t, result.Errors, 3)
+	assert.Equal(t, 2, result.Accepted)
+	assert.Equal(t, lines[0]+"\n"+lines[1]+"\n"+lines[5]+"\n", validLines.String())
+}
+
 func TestRateLimiting(t *testing.T) {
 	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/ratelimit.ndjson")
 	require.NoError(t, err)
@@ -254,6 +377,52 @@ func TestRateLimiting(t *testing.T) {
 	}
 }
 
+func TestDecodeLimiter(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/events.ndjson")
+	require.NoError(t, err)
+
+	p := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024})
+	p.DecodeLimiter = NewDecodeLimiter(1, 10*time.Millisecond)
+
+	// Occupy the only decoder, so the stream cannot be decoded.
+	require.True(t, p.DecodeLimiter.Acquire(context.Background()))
+	result := p.HandleStream(context.Background(), nil, &model.Metadata{}, bytes.NewReader(b), nopBatchProcessor{})
+	assert.Zero(t, result.Accepted)
+	assert.Equal(t, []*Error{{
+		Type:    QueueFullErrType,
+		Code:    errorcode.QueueFull,
+		Message: "too many concurrent decoders",
+	}}, result.Errors)
+
+	p.DecodeLimiter.Release()
+	result = p.HandleStream(context.Background(), nil, &model.Metadata{}, bytes.NewReader(b), nopBatchProcessor{})
+	assert.Empty(t, result.Errors)
+	assert.NotZero(t, result.Accepted)
+}
+
+func TestPhaseTimings(t *testing.T) {
+	b, err := loader.LoadDataAsBytes("../testdata/intake-v2/events.ndjson")
+	require.NoError(t, err)
+
+	batchProcessor := model.ProcessBatchFunc(func(context.Context, *model.Batch) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	var timings utility.PhaseTimings
+	ctx := utility.ContextWithPhaseTimings(context.Background(), &timings)
+	lim := rate.NewLimiter(rate.Inf, 0)
+	result := BackendProcessor(&config.Config{MaxEventSize: 100 * 1024}).HandleStream(
+		ctx, lim, &model.Metadata{}, bytes.NewReader(b), batchProcessor)
+	require.Empty(t, result.Errors)
+
+	assert.NotZero(t, timings.Duration(utility.PhaseRateLimit))
+	assert.NotZero(t, timings.Duration(utility.PhaseDecode))
+	assert.NotZero(t, timings.Duration(utility.PhaseValidate))
+	assert.GreaterOrEqual(t, int64(timings.Duration(utility.PhasePublish)), int64(time.Millisecond))
+	assert.Zero(t, timings.Duration(utility.PhaseAuth))
+	assert.Zero(t, timings.Duration(utility.PhaseDecompress))
+}
+
 func makeApproveEventsBatchProcessor(t *testing.T, name string) model.BatchProcessor {
 	return model.ProcessBatchFunc(func(ctx context.Context, b *model.Batch) error {
 		events := b.Transform(ctx, &transform.Config{DataStreams: true})
@@ -268,3 +437,83 @@ type nopBatchProcessor struct{}
 func (nopBatchProcessor) ProcessBatch(context.Context, *model.Batch) error {
 	return nil
 }
+
+func TestFastValidation(t *testing.T) {
+	body := strings.Join([]string{
+		`{"metadata": {"service": {"name": "service-a", "agent": {"name": "go", "version": "1.11.0"}}}}`,
+		`{"error": {"log": {"message": "missing id"}}}`,
+		`{"metadata": {"service": {"name": "service-a", "agent": {"name": "go", "version": "1.10.0"}}}}`,
+		`{"error": {"log": {"message": "missing id"}}}`,
+	}, "\n")
+	fastValidation := config.FastValidationConfig{
+		Enabled:          true,
+		MinAgentVersions: map[string]string{"go": "1.11.0"},
+	}
+
+	secretToken := func(cfg *config.Config) authorization.Authorization {
+		builder, err := authorization.NewBuilder(cfg)
+		require.NoError(t, err)
+		return builder.ForPrivilege(authorization.PrivilegeEventWrite.Action).AuthorizationFor("Bearer", "abc")
+	}
+	anonymous := func(cfg *config.Config) authorization.Authorization {
+		builder, err := authorization.NewBuilder(cfg)
+		require.NoError(t, err)
+		return builder.ForAnonymousPrivilege(authorization.PrivilegeEventWrite.Action).AuthorizationFor("", "")
+	}
+
+	for name, test := range map[string]struct {
+		cfg      config.Config
+		auth     func(*config.Config) authorization.Authorization
+		flags    map[string]bool
+		accepted int
+	}{
+		"disabled": {
+			cfg:      config.Config{SecretToken: "abc"},
+			auth:     secretToken,
+			accepted: 0,
+		},
+		"secret_token": {
+			cfg:      config.Config{FastValidation: fastValidation, SecretToken: "abc"},
+			auth:     secretToken,
+			accepted: 1,
+		},
+		"anonymous": {
+			cfg: config.Config{
+				FastValidation: fastValidation,
+				SecretToken:    "abc",
+				Auth:           config.AuthConfig{Anonymous: config.AnonymousAuthConfig{Enabled: true}},
+			},
+			auth:     anonymous,
+			accepted: 0,
+		},
+		"bypassed": {
+			// Requests exempt from authorization have no
+			// authorization recorded in their context.
+			cfg:      config.Config{FastValidation: fastValidation, SecretToken: "abc"},
+			accepted: 0,
+		},
+		"feature_flag_disabled": {
+			cfg:      config.Config{FastValidation: fastValidation, SecretToken: "abc"},
+			auth:     secretToken,
+			flags:    map[string]bool{"fast_validation": false},
+			accepted: 0,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := featureflag.Default.Update(test.flags)
+			require.NoError(t, err)
+			defer featureflag.Default.Update(nil)
+
+			ctx := context.Background()
+			if test.auth != nil {
+				ctx = authorization.ContextWithAuthorization(ctx, test.auth(&test.cfg))
+			}
+			test.cfg.MaxEventSize = 100 * 1024
+			result := BackendProcessor(&test.cfg).HandleStream(
+				ctx, nil, &model.Metadata{}, strings.NewReader(body), modelprocessor.Nop{},
+			)
+			assert.Equal(t, test.accepted, result.Accepted)
+			assert.Len(t, result.Errors, 2-test.accepted)
+		})
+	}
+}
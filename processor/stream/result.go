@@ -21,12 +21,18 @@ import (
 	"strings"
 
 	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/errorcode"
 )
 
 type Error struct {
 	Type     StreamError `json:"-"`
 	Message  string      `json:"message"`
 	Document string      `json:"document,omitempty"`
+
+	// Code holds the machine-readable error code. If Code is empty
+	// when the error is added to a Result, it is derived from Type.
+	Code errorcode.Code `json:"code,omitempty"`
 }
 
 func (s *Error) Error() string {
@@ -35,6 +41,27 @@ func (s *Error) Error() string {
 
 type StreamError int
 
+// ErrorCode returns the machine-readable error code for the error type.
+func (t StreamError) ErrorCode() errorcode.Code {
+	switch t {
+	case QueueFullErrType:
+		return errorcode.QueueFull
+	case InvalidInputErrType:
+		return errorcode.Validate
+	case InputTooLargeErrType:
+		return errorcode.TooLarge
+	case ShuttingDownErrType:
+		return errorcode.ShuttingDown
+	case MethodForbiddenErrType:
+		return errorcode.MethodNotAllowed
+	case RateLimitErrType:
+		return errorcode.RateLimit
+	case ForbiddenErrType:
+		return errorcode.Forbidden
+	}
+	return errorcode.Internal
+}
+
 const (
 	QueueFullErrType StreamError = iota
 	InvalidInputErrType
@@ -43,6 +70,7 @@ const (
 	ServerErrType
 	MethodForbiddenErrType
 	RateLimitErrType
+	ForbiddenErrType
 )
 
 const (
@@ -64,6 +92,26 @@ var (
 type Result struct {
 	Accepted int      `json:"accepted"`
 	Errors   []*Error `json:"errors,omitempty"`
+
+	listener ResultListener
+}
+
+// ResultListener is notified of accepted events and errors as they are
+// added to a Result, enabling results to be reported incrementally while
+// a stream is still being processed.
+type ResultListener interface {
+	// AddedAccepted is called with the number of newly accepted events.
+	AddedAccepted(n int)
+
+	// AddedError is called for every error added to the Result,
+	// including errors which are not retained due to the errors limit.
+	AddedError(err *Error)
+}
+
+// NewResult returns a new Result which notifies l, if non-nil, of
+// accepted events and errors as they are added.
+func NewResult(l ResultListener) *Result {
+	return &Result{listener: l}
 }
 
 func (r *Result) LimitedAdd(err error) {
@@ -77,6 +125,9 @@ func (r *Result) Add(err error) {
 func (r *Result) AddAccepted(ct int) {
 	r.Accepted += ct
 	mAccepted.Add(int64(ct))
+	if r.listener != nil {
+		r.listener.AddedAccepted(ct)
+	}
 }
 
 func (r *Result) Error() string {
@@ -92,10 +143,16 @@ func (r *Result) add(err error, add bool) {
 	if !ok {
 		e = &Error{Message: err.Error(), Type: ServerErrType}
 	}
+	if e.Code == "" {
+		e.Code = e.Type.ErrorCode()
+	}
 	if add {
 		r.Errors = append(r.Errors, e)
 	}
 	countErr(e.Type)
+	if r.listener != nil {
+		r.listener.AddedError(e)
+	}
 }
 
 func countErr(e StreamError) {
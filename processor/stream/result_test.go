@@ -22,8 +22,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/errorcode"
 )
 
 func TestStreamResponseSimple(t *testing.T) {
@@ -77,3 +80,51 @@ func TestMonitoring(t *testing.T) {
 		assert.Equal(t, ct+test.expected, test.counter.Get())
 	}
 }
+
+func TestStreamErrorCode(t *testing.T) {
+	for errType, code := range map[StreamError]errorcode.Code{
+		QueueFullErrType:       errorcode.QueueFull,
+		InvalidInputErrType:    errorcode.Validate,
+		InputTooLargeErrType:   errorcode.TooLarge,
+		ShuttingDownErrType:    errorcode.ShuttingDown,
+		ServerErrType:          errorcode.Internal,
+		MethodForbiddenErrType: errorcode.MethodNotAllowed,
+		RateLimitErrType:       errorcode.RateLimit,
+	} {
+		assert.Equal(t, code, errType.ErrorCode())
+	}
+
+	sr := Result{}
+	sr.Add(&Error{Type: InputTooLargeErrType})
+	sr.Add(&Error{Type: InvalidInputErrType, Code: errorcode.Decode})
+	sr.Add(errors.New("transmogrifier error"))
+	assert.Equal(t, errorcode.TooLarge, sr.Errors[0].Code)
+	assert.Equal(t, errorcode.Decode, sr.Errors[1].Code)
+	assert.Equal(t, errorcode.Internal, sr.Errors[2].Code)
+}
+
+type recordingListener struct {
+	accepted []int
+	errors   []*Error
+}
+
+func (l *recordingListener) AddedAccepted(n int)   { l.accepted = append(l.accepted, n) }
+func (l *recordingListener) AddedError(err *Error) { l.errors = append(l.errors, err) }
+
+func TestResultListener(t *testing.T) {
+	var l recordingListener
+	sr := NewResult(&l)
+	sr.AddAccepted(10)
+	for i := 0; i < errorsLimit+2; i++ {
+		sr.LimitedAdd(&Error{Type: InvalidInputErrType, Message: "err"})
+	}
+	sr.AddAccepted(3)
+	sr.Add(errors.New("transmogrifier error"))
+
+	assert.Equal(t, 13, sr.Accepted)
+	assert.Len(t, sr.Errors, errorsLimit+1)
+	assert.Equal(t, []int{10, 3}, l.accepted)
+	require.Len(t, l.errors, errorsLimit+3)
+	assert.Equal(t, errorcode.Validate, l.errors[0].Code)
+	assert.Equal(t, &Error{Type: ServerErrType, Message: "transmogrifier error", Code: errorcode.Internal}, l.errors[errorsLimit+2])
+}
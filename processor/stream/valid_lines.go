@@ -0,0 +1,41 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"context"
+	"io"
+)
+
+type validLinesKey struct{}
+
+// ContextWithValidLines returns a copy of ctx with w, to which
+// HandleStreamResult writes each ND-JSON line of the stream that is
+// decoded successfully, including metadata lines, terminated by a
+// newline. Invalid lines are not written.
+//
+// Lines are written as they are decoded, before events are processed,
+// so lines of events which are subsequently dropped are also written.
+func ContextWithValidLines(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, validLinesKey{}, w)
+}
+
+func validLinesFromContext(ctx context.Context) io.Writer {
+	w, _ := ctx.Value(validLinesKey{}).(io.Writer)
+	return w
+}
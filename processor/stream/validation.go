@@ -0,0 +1,171 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modeldecoder"
+)
+
+// maxValidationErrorGroups is the maximum number of distinct
+// (agent name, agent version, field, rule) groups for which validation
+// errors are counted. Errors for additional groups are counted in a group
+// with the agent name and version "_other", so agents sending many distinct
+// versions, such as RUM agents, cannot hide errors of other agents.
+const maxValidationErrorGroups = 1000
+
+const (
+	// unknownValidationErrorKey is reported in place of an empty agent
+	// name or version, e.g. for errors in metadata which failed validation.
+	unknownValidationErrorKey = "unknown"
+
+	// overflowValidationErrorKey is the agent name and version of the
+	// groups in which errors are counted once maxValidationErrorGroups
+	// is reached.
+	overflowValidationErrorKey = "_other"
+)
+
+var validationErrors = newValidationErrorCounts(maxValidationErrorGroups)
+
+func init() {
+	monitoring.NewFunc(m, "validation", validationErrors.collectMonitoring, monitoring.Report)
+}
+
+type validationErrorKey struct {
+	agentName    string
+	agentVersion string
+	field        string
+	rule         string
+}
+
+// validationErrorCounts counts validation errors by agent name,
+// agent version, field path, and violated rule.
+type validationErrorCounts struct {
+	mu        sync.Mutex
+	maxGroups int
+	groups    int
+	counts    map[validationErrorKey]int64
+	overflow  int64
+}
+
+func newValidationErrorCounts(maxGroups int) *validationErrorCounts {
+	return &validationErrorCounts{
+		maxGroups: maxGroups,
+		counts:    make(map[validationErrorKey]int64),
+	}
+}
+
+// record counts err if it is a modeldecoder.ValidationError,
+// attributing it to the agent described by metadata.
+func (c *validationErrorCounts) record(err error, metadata *model.Metadata) {
+	verr, ok := err.(modeldecoder.ValidationError)
+	if !ok {
+		return
+	}
+	key := validationErrorKey{
+		agentName:    metadata.Service.Agent.Name,
+		agentVersion: metadata.Service.Agent.Version,
+		field:        verr.Field(),
+		rule:         verr.Rule(),
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.counts[key]; !ok {
+		if c.groups >= c.maxGroups {
+			// The overflow groups are bounded by the number of
+			// fields and rules, so they are not limited.
+			key.agentName = overflowValidationErrorKey
+			key.agentVersion = overflowValidationErrorKey
+			c.overflow++
+		} else {
+			c.groups++
+		}
+	}
+	c.counts[key]++
+}
+
+// collectMonitoring reports the validation error counts, nested by agent
+// name, agent version, the segments of the field path, and rule. Dots in
+// agent names and versions are replaced with underscores, so they are not
+// mistaken for nesting. It is intended to be used with
+// libbeat/monitoring.NewFunc.
+func (c *validationErrorCounts) collectMonitoring(_ monitoring.Mode, V monitoring.Visitor) {
+	V.OnRegistryStart()
+	defer V.OnRegistryFinished()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errors := make(validationErrorTree)
+	for key, count := range c.counts {
+		agentName := key.agentName
+		if agentName == "" {
+			agentName = unknownValidationErrorKey
+		}
+		agentVersion := key.agentVersion
+		if agentVersion == "" {
+			agentVersion = unknownValidationErrorKey
+		}
+		path := []string{sanitizeValidationErrorKey(agentName), sanitizeValidationErrorKey(agentVersion)}
+		path = append(path, strings.Split(key.field, ".")...)
+		errors.add(path, key.rule, count)
+	}
+
+	monitoring.ReportInt(V, "overflow", c.overflow)
+	monitoring.ReportNamespace(V, "errors", func() {
+		errors.report(V)
+	})
+}
+
+// validationErrorTree holds validation error counts nested by path.
+// Its values are either validationErrorTrees, or the int64 counts of
+// the rules of a field.
+type validationErrorTree map[string]interface{}
+
+func (t validationErrorTree) add(path []string, rule string, count int64) {
+	for _, k := range path {
+		child, ok := t[k].(validationErrorTree)
+		if !ok {
+			child = make(validationErrorTree)
+			t[k] = child
+		}
+		t = child
+	}
+	n, _ := t[rule].(int64)
+	t[rule] = n + count
+}
+
+func (t validationErrorTree) report(V monitoring.Visitor) {
+	for k, v := range t {
+		switch v := v.(type) {
+		case int64:
+			monitoring.ReportInt(V, k, v)
+		case validationErrorTree:
+			monitoring.ReportNamespace(V, k, func() { v.report(V) })
+		}
+	}
+}
+
+func sanitizeValidationErrorKey(k string) string {
+	return strings.Replace(k, ".", "_", -1)
+}
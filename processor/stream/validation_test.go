@@ -0,0 +1,97 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stream
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/beater/config"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modeldecoder"
+	"github.com/elastic/apm-server/model/modelprocessor"
+)
+
+func TestValidationErrorCounts(t *testing.T) {
+	counts := newValidationErrorCounts(2)
+	goAgent := &model.Metadata{Service: model.Service{Agent: model.Agent{Name: "go", Version: "1.11.0"}}}
+	rumAgent := &model.Metadata{Service: model.Service{Agent: model.Agent{Name: "rum-js", Version: "5.6.0"}}}
+	required := modeldecoder.NewValidationErr(errors.New("transaction: 'id' required"))
+	maxLength := modeldecoder.NewValidationErr(errors.New("transaction: 'name': validation rule 'maxLength(1024)' violated"))
+
+	counts.record(required, goAgent)
+	counts.record(required, goAgent)
+	counts.record(required, &model.Metadata{})
+	counts.record(maxLength, goAgent)  // overflow
+	counts.record(maxLength, rumAgent) // overflow
+	counts.record(required, rumAgent)  // overflow
+	counts.record(errors.New("not a validation error"), goAgent)
+
+	registry := monitoring.NewRegistry()
+	monitoring.NewFunc(registry, "validation", counts.collectMonitoring)
+	snapshot := monitoring.CollectStructSnapshot(registry, monitoring.Full, false)
+	assert.Equal(t, map[string]interface{}{
+		"validation": map[string]interface{}{
+			"overflow": int64(3),
+			"errors": map[string]interface{}{
+				"go": map[string]interface{}{
+					"1_11_0": map[string]interface{}{
+						"transaction": map[string]interface{}{
+							"id": map[string]interface{}{"required": int64(2)},
+						},
+					},
+				},
+				"unknown": map[string]interface{}{
+					"unknown": map[string]interface{}{
+						"transaction": map[string]interface{}{
+							"id": map[string]interface{}{"required": int64(1)},
+						},
+					},
+				},
+				"_other": map[string]interface{}{
+					"_other": map[string]interface{}{
+						"transaction": map[string]interface{}{
+							"id":   map[string]interface{}{"required": int64(1)},
+							"name": map[string]interface{}{"maxLength": int64(2)},
+						},
+					},
+				},
+			},
+		},
+	}, snapshot)
+}
+
+func TestMetadataValidationErrorCounted(t *testing.T) {
+	counts := validationErrors
+	validationErrors = newValidationErrorCounts(maxValidationErrorGroups)
+	defer func() { validationErrors = counts }()
+
+	body := `{"metadata": {"service": {"name": "service-a"}}}`
+	BackendProcessor(&config.Config{MaxEventSize: 100 * 1024}).HandleStream(
+		context.Background(), nil, &model.Metadata{}, strings.NewReader(body), modelprocessor.Nop{},
+	)
+	assert.Equal(t, map[validationErrorKey]int64{
+		{field: "metadata.service.agent", rule: "required"}: 1,
+	}, validationErrors.counts)
+}
@@ -0,0 +1,252 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package pseudonymize provides a beat.Processor for replacing user
+// identifiers in events with salted hashes or generalized values, so that
+// events of a user can still be correlated without storing the raw
+// identifiers.
+package pseudonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+// Method identifies how the value of a field is pseudonymized.
+type Method string
+
+const (
+	// MethodHash replaces values with the hex-encoded HMAC-SHA256 of the
+	// value, keyed by the salt. Equal values have equal hashes, so events
+	// can be correlated by the hashed value.
+	MethodHash Method = "hash"
+
+	// MethodTruncate replaces IP addresses with the network address of
+	// the configured prefix length, e.g. 192.168.1.0 for 192.168.1.23
+	// with a prefix length of 24. Comma-separated lists of addresses,
+	// as in the X-Forwarded-For header, are truncated address by address,
+	// and ports are dropped. Values which are not IP addresses are
+	// removed.
+	MethodTruncate Method = "truncate"
+
+	// MethodRemove removes fields, for values which cannot be usefully
+	// pseudonymized, such as cookies.
+	MethodRemove Method = "remove"
+)
+
+// HeadersPrefix is the prefix of request header fields, whose names are
+// matched case-insensitively.
+const HeadersPrefix = "http.request.headers."
+
+var (
+	registry            = monitoring.Default.NewRegistry("apm-server.pseudonymize")
+	monitoringHashed    = monitoring.NewInt(registry, "hashed")
+	monitoringTruncated = monitoring.NewInt(registry, "truncated")
+	monitoringRemoved   = monitoring.NewInt(registry, "removed")
+)
+
+// Rule holds the policy for pseudonymizing an event field.
+type Rule struct {
+	// Field holds the name of the event field, e.g. "user.id".
+	Field string
+
+	Method Method
+
+	// IPv4Prefix and IPv6Prefix hold the number of leading bits of IPv4
+	// and IPv6 addresses kept by MethodTruncate.
+	IPv4Prefix int
+	IPv6Prefix int
+}
+
+// DefaultRules returns the rules used when none are configured: user.id
+// and user.email are hashed; client.ip, source.ip, the request's socket
+// address, and the X-Forwarded-For and X-Real-Ip headers are truncated to
+// their /24 IPv4 or /48 IPv6 networks; and the Forwarded and Cookie headers
+// and request cookies are removed.
+func DefaultRules() []Rule {
+	truncate := func(field string) Rule {
+		return Rule{Field: field, Method: MethodTruncate, IPv4Prefix: 24, IPv6Prefix: 48}
+	}
+	return []Rule{
+		{Field: "user.id", Method: MethodHash},
+		{Field: "user.email", Method: MethodHash},
+		truncate("client.ip"),
+		truncate("source.ip"),
+		truncate("http.request.socket.remote_address"),
+		truncate(HeadersPrefix + "X-Forwarded-For"),
+		truncate(HeadersPrefix + "X-Real-Ip"),
+		{Field: HeadersPrefix + "Forwarded", Method: MethodRemove},
+		{Field: HeadersPrefix + "Cookie", Method: MethodRemove},
+		{Field: "http.request.cookies", Method: MethodRemove},
+	}
+}
+
+// Processor is a beat.Processor which pseudonymizes event fields
+// according to rules. Events without a rule's field are not modified.
+type Processor struct {
+	salt  []byte
+	rules []Rule
+}
+
+// NewProcessor returns a new Processor, hashing field values with salt.
+func NewProcessor(salt string, rules []Rule) *Processor {
+	return &Processor{salt: []byte(salt), rules: rules}
+}
+
+// Run pseudonymizes the fields of event.
+func (p *Processor) Run(event *beat.Event) (*beat.Event, error) {
+	for _, rule := range p.rules {
+		field := rule.Field
+		if strings.HasPrefix(field, HeadersPrefix) {
+			var ok bool
+			if field, ok = headerField(event.Fields, field); !ok {
+				continue
+			}
+		}
+		value, err := event.Fields.GetValue(field)
+		if err != nil {
+			continue
+		}
+		value, ok := p.Apply(rule, value)
+		if !ok {
+			event.Fields.Delete(field)
+			continue
+		}
+		if _, err := event.Fields.Put(field, value); err != nil {
+			return event, err
+		}
+	}
+	return event, nil
+}
+
+// Apply returns value pseudonymized according to rule, and reports whether
+// the field should be kept. Fields are removed by MethodRemove, and by
+// MethodTruncate if the value is not an IP address, rather than storing
+// the value unmodified.
+func (p *Processor) Apply(rule Rule, value interface{}) (interface{}, bool) {
+	switch rule.Method {
+	case MethodHash:
+		monitoringHashed.Inc()
+		if values, ok := value.([]string); ok {
+			hashed := make([]string, len(values))
+			for i, value := range values {
+				hashed[i] = p.hash(value)
+			}
+			return hashed, true
+		}
+		return p.hash(value), true
+	case MethodTruncate:
+		var truncated interface{}
+		ok := false
+		switch value := value.(type) {
+		case string:
+			truncated, ok = truncateIPs(value, rule.IPv4Prefix, rule.IPv6Prefix)
+		case []string:
+			values := make([]string, len(value))
+			ok = len(value) > 0
+			for i := 0; ok && i < len(value); i++ {
+				values[i], ok = truncateIPs(value[i], rule.IPv4Prefix, rule.IPv6Prefix)
+			}
+			truncated = values
+		}
+		if ok {
+			monitoringTruncated.Inc()
+			return truncated, true
+		}
+	}
+	monitoringRemoved.Inc()
+	return nil, false
+}
+
+// Rules returns the rules of the processor.
+func (p *Processor) Rules() []Rule {
+	return p.rules
+}
+
+func (p *Processor) String() string {
+	fields := make([]string, len(p.rules))
+	for i, rule := range p.rules {
+		fields[i] = rule.Field + ":" + string(rule.Method)
+	}
+	return "pseudonymize=[" + strings.Join(fields, ",") + "]"
+}
+
+func (p *Processor) hash(value interface{}) string {
+	mac := hmac.New(sha256.New, p.salt)
+	fmt.Fprint(mac, value)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// headerField returns the name of the event field holding the request
+// header of field, whose name is matched case-insensitively, and reports
+// whether the event has the header.
+func headerField(fields common.MapStr, field string) (string, bool) {
+	value, err := fields.GetValue(strings.TrimSuffix(HeadersPrefix, "."))
+	if err != nil {
+		return "", false
+	}
+	headers, ok := value.(common.MapStr)
+	if !ok {
+		return "", false
+	}
+	name := field[len(HeadersPrefix):]
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return HeadersPrefix + key, true
+		}
+	}
+	return "", false
+}
+
+// truncateIPs returns the network addresses of the comma-separated IP
+// addresses in s with the given prefix lengths, and reports whether all
+// of them are IP addresses. Addresses may have ports, which are dropped.
+func truncateIPs(s string, ipv4Prefix, ipv6Prefix int) (string, bool) {
+	addrs := strings.Split(s, ",")
+	for i, addr := range addrs {
+		ip := parseIP(strings.TrimSpace(addr))
+		if ip == nil {
+			return "", false
+		}
+		addrs[i] = truncateIP(ip, ipv4Prefix, ipv6Prefix).String()
+	}
+	return strings.Join(addrs, ", "), true
+}
+
+// parseIP parses s as an IP address, with or without a port.
+func parseIP(s string) net.IP {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	return net.ParseIP(s)
+}
+
+// truncateIP returns the network address of ip with the given prefix lengths.
+func truncateIP(ip net.IP, ipv4Prefix, ipv6Prefix int) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(ipv4Prefix, 8*net.IPv4len))
+	}
+	return ip.Mask(net.CIDRMask(ipv6Prefix, 8*net.IPv6len))
+}
@@ -0,0 +1,151 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pseudonymize_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/pseudonymize"
+)
+
+func TestProcessor(t *testing.T) {
+	processor := pseudonymize.NewProcessor("secret", pseudonymize.DefaultRules())
+	for name, test := range map[string]struct {
+		fields common.MapStr
+		expect common.MapStr
+	}{
+		"ipv4": {
+			fields: common.MapStr{
+				"user":   common.MapStr{"id": "123", "email": "jane@example.com", "name": "jane"},
+				"client": common.MapStr{"ip": "192.168.1.23"},
+			},
+			expect: common.MapStr{
+				"user":   common.MapStr{"id": hash("secret", "123"), "email": hash("secret", "jane@example.com"), "name": "jane"},
+				"client": common.MapStr{"ip": "192.168.1.0"},
+			},
+		},
+		"ipv6": {
+			fields: common.MapStr{"client": common.MapStr{"ip": "2001:db8:85a3:8d3:1319:8a2e:370:7348"}},
+			expect: common.MapStr{"client": common.MapStr{"ip": "2001:db8:85a3::"}},
+		},
+		"invalid ip": {
+			fields: common.MapStr{"client": common.MapStr{"ip": "unknown"}},
+			expect: common.MapStr{"client": common.MapStr{}},
+		},
+		"non-string id": {
+			fields: common.MapStr{"user": common.MapStr{"id": 123}},
+			expect: common.MapStr{"user": common.MapStr{"id": hash("secret", "123")}},
+		},
+		"request": {
+			fields: common.MapStr{
+				"source": common.MapStr{"ip": "10.1.2.3"},
+				"http": common.MapStr{"request": common.MapStr{
+					"socket": common.MapStr{"remote_address": "10.1.2.3:1234"},
+					"headers": common.MapStr{
+						"x-forwarded-for": []string{"10.1.2.3, 2001:db8:85a3:8d3:1319:8a2e:370:7348"},
+						"X-Real-Ip":       []string{"10.1.2.3"},
+						"Forwarded":       []string{"for=10.1.2.3"},
+						"Cookie":          []string{"c=1"},
+						"User-Agent":      []string{"curl"},
+					},
+					"cookies": common.MapStr{"c": "1"},
+				}},
+			},
+			expect: common.MapStr{
+				"source": common.MapStr{"ip": "10.1.2.0"},
+				"http": common.MapStr{"request": common.MapStr{
+					"socket": common.MapStr{"remote_address": "10.1.2.0"},
+					"headers": common.MapStr{
+						"x-forwarded-for": []string{"10.1.2.0, 2001:db8:85a3::"},
+						"X-Real-Ip":       []string{"10.1.2.0"},
+						"User-Agent":      []string{"curl"},
+					},
+				}},
+			},
+		},
+		"no fields": {
+			fields: common.MapStr{"service": common.MapStr{"name": "opbeans"}},
+			expect: common.MapStr{"service": common.MapStr{"name": "opbeans"}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			event, err := processor.Run(&beat.Event{Fields: test.fields})
+			require.NoError(t, err)
+			assert.Equal(t, test.expect, event.Fields)
+		})
+	}
+}
+
+func TestProcessorRules(t *testing.T) {
+	processor := pseudonymize.NewProcessor("", []pseudonymize.Rule{
+		{Field: "client.ip", Method: pseudonymize.MethodTruncate, IPv4Prefix: 16, IPv6Prefix: 32},
+		{Field: "source.ip", Method: pseudonymize.MethodTruncate, IPv4Prefix: 8, IPv6Prefix: 32},
+	})
+	event, err := processor.Run(&beat.Event{Fields: common.MapStr{
+		"client": common.MapStr{"ip": "192.168.1.23"},
+		"source": common.MapStr{"ip": "10.1.2.3"},
+		"user":   common.MapStr{"id": "123"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, common.MapStr{
+		"client": common.MapStr{"ip": "192.168.0.0"},
+		"source": common.MapStr{"ip": "10.0.0.0"},
+		"user":   common.MapStr{"id": "123"},
+	}, event.Fields)
+	assert.Equal(t, "pseudonymize=[client.ip:truncate,source.ip:truncate]", processor.String())
+}
+
+func TestProcessorSalt(t *testing.T) {
+	run := func(salt string) interface{} {
+		rules := []pseudonymize.Rule{{Field: "user.id", Method: pseudonymize.MethodHash}}
+		event, err := pseudonymize.NewProcessor(salt, rules).Run(&beat.Event{Fields: common.MapStr{
+			"user": common.MapStr{"id": "123"},
+		}})
+		require.NoError(t, err)
+		value, _ := event.Fields.GetValue("user.id")
+		return value
+	}
+	assert.Equal(t, run("a"), run("a"))
+	assert.NotEqual(t, run("a"), run("b"))
+}
+
+func TestProcessorRemove(t *testing.T) {
+	processor := pseudonymize.NewProcessor("", []pseudonymize.Rule{
+		{Field: "user.name", Method: pseudonymize.MethodRemove},
+	})
+	event, err := processor.Run(&beat.Event{Fields: common.MapStr{
+		"user": common.MapStr{"id": "123", "name": "jane"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, common.MapStr{"user": common.MapStr{"id": "123"}}, event.Fields)
+}
+
+func hash(salt, value string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
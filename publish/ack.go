@@ -0,0 +1,193 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package publish
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common/acker"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+	"github.com/elastic/beats/v7/libbeat/monitoring/adapter"
+)
+
+var (
+	ackRegistry = monitoring.Default.NewRegistry("apm-server.publish.ack")
+
+	// queueACKMetrics holds acknowledgement metrics for each
+	// of the publisher's queues, keyed by queue name.
+	queueACKMetrics = map[string]*ackMetrics{
+		tracesQueue:  newACKMetrics(tracesQueue),
+		errorsQueue:  newACKMetrics(errorsQueue),
+		metricsQueue: newACKMetrics(metricsQueue),
+	}
+)
+
+// ackMetrics holds metrics for the acknowledgement of
+// events published through a single pipeline client.
+type ackMetrics struct {
+	// pending holds the number of events which have been
+	// published, but not yet acknowledged by the output.
+	pending *monitoring.Int
+
+	// lag holds the durations in milliseconds between requests
+	// being accepted, and all of their events being acknowledged.
+	lag metrics.Sample
+}
+
+func newACKMetrics(name string) *ackMetrics {
+	registry := ackRegistry.NewRegistry(name)
+	m := &ackMetrics{
+		pending: monitoring.NewInt(registry, "events.pending"),
+		// Use an exponentially decaying sample, biased towards the
+		// last 5 minutes, so the histogram reflects the current lag.
+		lag: metrics.NewExpDecaySample(1028, 0.015),
+	}
+	adapter.NewGoMetrics(registry, "histogram", adapter.Accept).
+		Register("lag_ms", metrics.NewHistogram(m.lag))
+	return m
+}
+
+type ackWaiterKey struct{}
+
+// ACKWaiter keeps track of requests sent to a Publisher, and provides
+// a Wait method that blocks until all events of those requests have
+// been acknowledged by the output.
+type ACKWaiter struct {
+	mu      sync.Mutex
+	pending int
+	// done is closed once pending drops to zero.
+	done chan struct{}
+}
+
+// NewACKWaiter returns a new ACKWaiter.
+func NewACKWaiter() *ACKWaiter {
+	return &ACKWaiter{}
+}
+
+// ContextWithACKWaiter returns a copy of ctx with w. Requests sent to a
+// Publisher with the returned context will be tracked by w.
+func ContextWithACKWaiter(ctx context.Context, w *ACKWaiter) context.Context {
+	return context.WithValue(ctx, ackWaiterKey{}, w)
+}
+
+func ackWaiterFromContext(ctx context.Context) *ACKWaiter {
+	w, _ := ctx.Value(ackWaiterKey{}).(*ACKWaiter)
+	return w
+}
+
+// Wait blocks until all events of the tracked requests have been acknowledged,
+// or until ctx is cancelled. Wait must not be called concurrently with sending
+// requests tracked by w.
+func (w *ACKWaiter) Wait(ctx context.Context) error {
+	w.mu.Lock()
+	pending, done := w.pending, w.done
+	w.mu.Unlock()
+	if pending == 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
+
+// add starts tracking a request.
+func (w *ACKWaiter) add() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending == 0 {
+		w.done = make(chan struct{})
+	}
+	w.pending++
+}
+
+// release stops tracking a request, whose events have all been
+// acknowledged, or which was not enqueued.
+func (w *ACKWaiter) release() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending--
+	if w.pending == 0 {
+		close(w.done)
+	}
+}
+
+// pendingACK is set as the private field of each published event,
+// and counts the events of a request that have not yet been acknowledged.
+type pendingACK struct {
+	metrics  *ackMetrics
+	accepted time.Time
+	waiter   *ACKWaiter
+	record   *journalRecord
+	pending  int64 // atomic
+}
+
+// trackEvents sets the private field of events to a new pendingACK,
+// recording metrics once all of them have been acknowledged, releasing
+// waiter and acknowledging record. Either of waiter and record may be nil.
+func trackEvents(events []beat.Event, m *ackMetrics, accepted time.Time, waiter *ACKWaiter, record *journalRecord) {
+	p := &pendingACK{
+		metrics:  m,
+		accepted: accepted,
+		waiter:   waiter,
+		record:   record,
+		pending:  int64(len(events)),
+	}
+	if len(events) == 0 {
+		p.done()
+		return
+	}
+	m.pending.Add(int64(len(events)))
+	for i := range events {
+		events[i].Private = p
+	}
+}
+
+func (p *pendingACK) done() {
+	p.metrics.lag.Update(time.Since(p.accepted).Milliseconds())
+	if p.waiter != nil {
+		p.waiter.release()
+	}
+	if p.record != nil {
+		p.record.ack()
+	}
+}
+
+// newEventACKer returns a beat.ACKer which releases ACKWaiters and
+// acknowledges journal records once all events of their tracked
+// requests have been acknowledged or dropped.
+func newEventACKer() beat.ACKer {
+	return acker.EventPrivateReporter(func(_ int, data []interface{}) {
+		for _, v := range data {
+			if p, ok := v.(*pendingACK); ok {
+				p.metrics.pending.Dec()
+				if atomic.AddInt64(&p.pending, -1) == 0 {
+					p.done()
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,76 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package publish_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.elastic.co/apm/apmtest"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/outputs"
+
+	"github.com/elastic/apm-server/publish"
+	"github.com/elastic/apm-server/transform"
+)
+
+func TestACKWaiter(t *testing.T) {
+	pipeline := newBlockingPipeline(t)
+	publisher, err := publish.NewPublisher(
+		pipeline, apmtest.DiscardTracer, &publish.PublisherConfig{
+			TransformConfig: &transform.Config{},
+		},
+	)
+	require.NoError(t, err)
+	defer publisher.Stop(context.Background())
+
+	waiter := publish.NewACKWaiter()
+	ctx := publish.ContextWithACKWaiter(context.Background(), waiter)
+	require.NoError(t, publisher.Send(ctx, publish.PendingReq{
+		Transformable: makeTransformable(beat.Event{Fields: make(common.MapStr)}),
+	}))
+	require.NoError(t, publisher.Send(ctx, publish.PendingReq{
+		Transformable: makeTransformable(),
+	}))
+
+	// There is no output, so the events are never acknowledged.
+	waitCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, waiter.Wait(waitCtx))
+
+	// Set an output which acknowledges events immediately, unblocking the waiter.
+	assert.NoError(t, pipeline.OutputReloader().Reload(nil,
+		func(outputs.Observer, common.ConfigNamespace) (outputs.Group, error) {
+			return outputs.Group{Clients: []outputs.Client{&mockClient{}}}, nil
+		},
+	))
+	waitCtx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.NoError(t, waiter.Wait(waitCtx))
+}
+
+func TestACKWaiterNoRequests(t *testing.T) {
+	waiter := publish.NewACKWaiter()
+	assert.NoError(t, waiter.Wait(context.Background()))
+}
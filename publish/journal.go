@@ -0,0 +1,525 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package publish
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	logs "github.com/elastic/apm-server/log"
+)
+
+const (
+	journalSegmentExt = ".journal"
+
+	// JournalDefaultSegmentSize holds the default size in bytes after
+	// which a new journal segment file is started. The journal's maximum
+	// size must be at least the segment size.
+	JournalDefaultSegmentSize = 64 * 1024 * 1024
+)
+
+var (
+	journalRegistry        = monitoring.Default.NewRegistry("apm-server.journal")
+	journalRecordsWritten  = monitoring.NewInt(journalRegistry, "records.written")
+	journalRecordsDropped  = monitoring.NewInt(journalRegistry, "records.dropped")
+	journalRecordsReplayed = monitoring.NewInt(journalRegistry, "records.replayed")
+	journalRecordsExpired  = monitoring.NewInt(journalRegistry, "records.expired")
+)
+
+// JournalConfig holds configuration for journaling accepted events to
+// local disk until they have been acknowledged by the output, so they
+// can be replayed after the process crashes.
+type JournalConfig struct {
+	// Dir holds the directory in which journal segment files are stored.
+	Dir string
+
+	// MaxSize holds the maximum total size in bytes of journal segment
+	// files, which must be at least SegmentSize. While the journal is
+	// full, events are published without being journaled.
+	MaxSize int64
+
+	// MaxAge holds the maximum age of journal segment files replayed
+	// on startup. Events in older segment files are discarded.
+	MaxAge time.Duration
+
+	// SegmentSize holds the size in bytes after which a new journal
+	// segment file is started. If SegmentSize is zero,
+	// JournalDefaultSegmentSize is used.
+	SegmentSize int64
+}
+
+// journalEntry is a line in a journal segment file, holding either
+// a batch of events, or the acknowledgement of a previous batch.
+//
+// Queue holds the name of the queue through which the batch's events
+// are published. It is empty for entries written by older versions,
+// whose events were all published through the traces queue.
+type journalEntry struct {
+	ID     uint64         `json:"id,omitempty"`
+	Queue  string         `json:"queue,omitempty"`
+	Events []journalEvent `json:"events,omitempty"`
+	ACK    uint64         `json:"ack,omitempty"`
+}
+
+type journalEvent struct {
+	Timestamp time.Time     `json:"@timestamp"`
+	Meta      common.MapStr `json:"meta,omitempty"`
+	Fields    common.MapStr `json:"fields"`
+}
+
+// journal is a write-ahead log of batches of events, split into segment
+// files. Segment files are removed, oldest first, once all of their
+// batches have been acknowledged. Record IDs increase monotonically,
+// also across restarts.
+//
+// Entries are encoded before mu is locked, and synced to disk after it
+// is unlocked, so concurrent appends are only serialized while writing.
+type journal struct {
+	config JournalConfig
+	logger *logp.Logger
+
+	mu       sync.Mutex
+	file     *os.File
+	full     bool
+	nextID   uint64
+	nextSeq  uint64
+	size     int64
+	segments []*journalSegment
+}
+
+type journalSegment struct {
+	path    string
+	size    int64
+	pending int
+}
+
+// journalRecord identifies a journaled batch of events, which must
+// be acknowledged once all of its events have been published.
+type journalRecord struct {
+	journal *journal
+	id      uint64
+	segment *journalSegment
+}
+
+// journalReplay holds the unacknowledged batches of events found in
+// the segment files existing when the journal was opened.
+type journalReplay struct {
+	batches []journalBatch
+	paths   []string
+}
+
+// journalBatch holds a batch of events replayed from the journal, and
+// the name of the queue through which they were published.
+type journalBatch struct {
+	queue  string
+	events []beat.Event
+}
+
+// openJournal opens the journal in cfg.Dir, returning the unacknowledged
+// batches of events found in existing segment files. The existing segment
+// files must be removed with removeSegmentFiles once the batches have been
+// journaled again.
+func openJournal(cfg JournalConfig) (*journal, journalReplay, error) {
+	if cfg.SegmentSize <= 0 {
+		cfg.SegmentSize = JournalDefaultSegmentSize
+	}
+	if cfg.MaxSize > 0 && cfg.MaxSize < cfg.SegmentSize {
+		return nil, journalReplay{}, errors.Errorf(
+			"journal max size %d must be at least the segment size %d",
+			cfg.MaxSize, cfg.SegmentSize,
+		)
+	}
+	if err := os.MkdirAll(cfg.Dir, 0750); err != nil {
+		return nil, journalReplay{}, errors.Wrap(err, "failed to create journal directory")
+	}
+	j := &journal{config: cfg, logger: logp.NewLogger(logs.Journal)}
+
+	paths, maxSeq, err := listJournalSegments(cfg.Dir)
+	if err != nil {
+		return nil, journalReplay{}, err
+	}
+	j.nextSeq = maxSeq + 1
+
+	type record struct {
+		id      uint64
+		queue   string
+		events  []journalEvent
+		expired bool
+	}
+	var records []record
+	var maxID uint64
+	acked := make(map[uint64]bool)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, journalReplay{}, err
+		}
+		expired := cfg.MaxAge > 0 && time.Since(info.ModTime()) > cfg.MaxAge
+		if err := j.readSegment(path, func(entry journalEntry) {
+			if entry.ID > maxID {
+				maxID = entry.ID
+			}
+			if entry.ACK != 0 {
+				acked[entry.ACK] = true
+			} else if entry.ID != 0 {
+				records = append(records, record{id: entry.ID, queue: entry.Queue, events: entry.Events, expired: expired})
+			}
+		}); err != nil {
+			return nil, journalReplay{}, err
+		}
+	}
+	j.nextID = maxID + 1
+
+	replay := journalReplay{paths: paths}
+	for _, record := range records {
+		switch {
+		case acked[record.id]:
+			continue
+		case record.expired:
+			journalRecordsExpired.Inc()
+			continue
+		}
+		events := make([]beat.Event, len(record.events))
+		for i, event := range record.events {
+			events[i] = beat.Event{Timestamp: event.Timestamp, Meta: event.Meta, Fields: event.Fields}
+		}
+		replay.batches = append(replay.batches, journalBatch{queue: record.queue, events: events})
+	}
+	if n := len(replay.batches); n > 0 {
+		j.logger.Infof("replaying %d unacknowledged batches of events from journal", n)
+	}
+	return j, replay, nil
+}
+
+// listJournalSegments returns the paths of the segment files in dir,
+// ordered from oldest to newest, and the highest segment sequence number.
+func listJournalSegments(dir string) ([]string, uint64, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+journalSegmentExt))
+	if err != nil {
+		return nil, 0, err
+	}
+	type segmentFile struct {
+		path string
+		seq  uint64
+	}
+	var files []segmentFile
+	for _, path := range matches {
+		seq, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(path), journalSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		files = append(files, segmentFile{path: path, seq: seq})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].seq < files[j].seq })
+
+	var maxSeq uint64
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.path
+		maxSeq = file.seq
+	}
+	return paths, maxSeq, nil
+}
+
+// readSegment calls f for each entry in the segment file at path. Reading
+// stops at the first invalid entry, which may have been partially written
+// when the process crashed.
+//
+// Numbers are decoded as int64 where possible, rather than float64, so
+// integers such as durations in microseconds are replayed without loss
+// of precision.
+func (j *journal) readSegment(path string, f func(journalEntry)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				j.logger.Warnf("ignoring incomplete entry at end of journal segment %s", path)
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+		var entry journalEntry
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&entry); err != nil {
+			j.logger.Warnf("ignoring invalid entry in journal segment %s: %s", path, err)
+			return nil
+		}
+		for _, event := range entry.Events {
+			convertJournalNumbers(event.Meta)
+			convertJournalNumbers(event.Fields)
+		}
+		f(entry)
+	}
+}
+
+// convertJournalNumbers replaces json.Number values in m, recursively,
+// with int64 values if they are integers, and float64 values otherwise.
+func convertJournalNumbers(m map[string]interface{}) {
+	for k, v := range m {
+		m[k] = convertJournalNumber(v)
+	}
+}
+
+func convertJournalNumber(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		convertJournalNumbers(v)
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = convertJournalNumber(elem)
+		}
+	}
+	return v
+}
+
+// removeSegmentFiles removes the segment files at paths.
+func (j *journal) removeSegmentFiles(paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			j.logger.Warnf("failed to remove journal segment %s: %s", path, err)
+		}
+	}
+}
+
+// append journals events published through the named queue, returning a
+// journalRecord which must be acknowledged once all of the events have been
+// published. If events is empty, or could not be journaled, append returns
+// nil. The entry is synced to disk before append returns.
+func (j *journal) append(queue string, events []beat.Event) *journalRecord {
+	if len(events) == 0 {
+		return nil
+	}
+	journalEvents := make([]journalEvent, len(events))
+	for i, event := range events {
+		journalEvents[i] = journalEvent{Timestamp: event.Timestamp, Meta: event.Meta, Fields: event.Fields}
+	}
+	encodedQueue, err := json.Marshal(queue)
+	if err != nil {
+		journalRecordsDropped.Inc()
+		j.logger.Errorf("failed to encode journal entry: %s", err)
+		return nil
+	}
+	encodedEvents, err := json.Marshal(journalEvents)
+	if err != nil {
+		journalRecordsDropped.Inc()
+		j.logger.Errorf("failed to encode journal entry: %s", err)
+		return nil
+	}
+
+	j.mu.Lock()
+	record, file := j.appendLocked(encodedQueue, encodedEvents)
+	j.mu.Unlock()
+	if record != nil {
+		j.sync(file)
+	}
+	return record
+}
+
+func (j *journal) appendLocked(encodedQueue, encodedEvents []byte) (*journalRecord, *os.File) {
+	id := j.nextID
+	line := make([]byte, 0, len(encodedQueue)+len(encodedEvents)+50)
+	line = append(line, `{"id":`...)
+	line = strconv.AppendUint(line, id, 10)
+	line = append(line, `,"queue":`...)
+	line = append(line, encodedQueue...)
+	line = append(line, `,"events":`...)
+	line = append(line, encodedEvents...)
+	line = append(line, "}\n"...)
+	if j.config.MaxSize > 0 && j.size+int64(len(line)) > j.config.MaxSize {
+		journalRecordsDropped.Inc()
+		if !j.full {
+			j.full = true
+			j.logger.Warn("journal is full, events will be published without being journaled")
+		}
+		return nil, nil
+	}
+	j.full = false
+
+	segment := j.currentSegment()
+	if segment != nil && segment.size > 0 && segment.size+int64(len(line)) > j.config.SegmentSize {
+		segment = nil
+	}
+	if segment == nil {
+		var err error
+		if segment, err = j.newSegment(); err != nil {
+			journalRecordsDropped.Inc()
+			j.logger.Errorf("failed to create journal segment: %s", err)
+			return nil, nil
+		}
+	}
+	if err := j.write(segment, line); err != nil {
+		journalRecordsDropped.Inc()
+		j.logger.Errorf("failed to write journal entry: %s", err)
+		return nil, nil
+	}
+	j.nextID++
+	segment.pending++
+	journalRecordsWritten.Inc()
+	return &journalRecord{journal: j, id: id, segment: segment}, j.file
+}
+
+// sync commits the entries written to file to disk. Entries written by
+// concurrent appends are committed along with them. If file has been
+// closed by rotation, it was synced before being closed.
+func (j *journal) sync(file *os.File) {
+	if err := file.Sync(); err != nil && !errors.Is(err, os.ErrClosed) {
+		j.logger.Errorf("failed to sync journal segment: %s", err)
+	}
+}
+
+// ack records that all events of record have been published, removing
+// segment files whose records have all been acknowledged.
+func (r *journalRecord) ack() {
+	j := r.journal
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	r.segment.pending--
+	if segment := j.currentSegment(); segment != nil && j.file != nil {
+		line := append(strconv.AppendUint([]byte(`{"ack":`), r.id, 10), "}\n"...)
+		if err := j.write(segment, line); err != nil {
+			j.logger.Errorf("failed to write journal entry: %s", err)
+		}
+	}
+	j.removeAcknowledgedSegments()
+}
+
+// close closes the current segment file. If all records have been
+// acknowledged, all segment files are removed.
+func (j *journal) close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Sync()
+	if closeErr := j.file.Close(); err == nil {
+		err = closeErr
+	}
+	j.file = nil
+	pending := false
+	for _, segment := range j.segments {
+		pending = pending || segment.pending > 0
+	}
+	if !pending {
+		for _, segment := range j.segments {
+			j.removeSegment(segment)
+		}
+		j.segments = nil
+	}
+	return err
+}
+
+func (j *journal) currentSegment() *journalSegment {
+	if len(j.segments) == 0 {
+		return nil
+	}
+	return j.segments[len(j.segments)-1]
+}
+
+func (j *journal) newSegment() (*journalSegment, error) {
+	path := filepath.Join(j.config.Dir, fmt.Sprintf("%020d%s", j.nextSeq, journalSegmentExt))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if j.file != nil {
+		// Sync the previous segment before it is closed,
+		// committing its entries to disk.
+		if err := j.file.Sync(); err != nil {
+			j.logger.Errorf("failed to sync journal segment: %s", err)
+		}
+		j.file.Close()
+	}
+	if err := syncDir(j.config.Dir); err != nil {
+		j.logger.Errorf("failed to sync journal directory: %s", err)
+	}
+	j.file = file
+	j.nextSeq++
+	segment := &journalSegment{path: path}
+	j.segments = append(j.segments, segment)
+	j.removeAcknowledgedSegments()
+	return segment, nil
+}
+
+// syncDir syncs the directory at path, committing the creation of files
+// in it to disk.
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+func (j *journal) write(segment *journalSegment, line []byte) error {
+	n, err := j.file.Write(line)
+	segment.size += int64(n)
+	j.size += int64(n)
+	return err
+}
+
+// removeAcknowledgedSegments removes the oldest segments, other than the
+// current one, while all of their records have been acknowledged. Segments
+// are removed in order, as acknowledgements are written to the current
+// segment and may refer to records in any older segment.
+func (j *journal) removeAcknowledgedSegments() {
+	for len(j.segments) > 1 && j.segments[0].pending == 0 {
+		j.removeSegment(j.segments[0])
+		j.segments = j.segments[1:]
+	}
+}
+
+func (j *journal) removeSegment(segment *journalSegment) {
+	if err := os.Remove(segment.path); err != nil {
+		j.logger.Warnf("failed to remove journal segment %s: %s", segment.path, err)
+	}
+	j.size -= segment.size
+}
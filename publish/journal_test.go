@@ -0,0 +1,167 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package publish
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+)
+
+func TestJournalAppendACK(t *testing.T) {
+	dir := t.TempDir()
+	j, replay, err := openJournal(JournalConfig{Dir: dir, SegmentSize: 1})
+	require.NoError(t, err)
+	assert.Empty(t, replay.batches)
+
+	var records []*journalRecord
+	for i := 0; i < 3; i++ {
+		record := j.append(tracesQueue, makeJournalEvents(i))
+		require.NotNil(t, record)
+		records = append(records, record)
+	}
+	// Each record is written to a new segment, as the segment size is 1.
+	assert.Len(t, listJournalDir(t, dir), 3)
+
+	// Segments are removed in order, once all of their records
+	// have been acknowledged, excluding the current segment.
+	records[1].ack()
+	assert.Len(t, listJournalDir(t, dir), 3)
+	records[0].ack()
+	assert.Len(t, listJournalDir(t, dir), 1)
+	records[2].ack()
+	assert.Len(t, listJournalDir(t, dir), 1)
+
+	require.NoError(t, j.close())
+	assert.Empty(t, listJournalDir(t, dir))
+}
+
+func TestJournalReplay(t *testing.T) {
+	dir := t.TempDir()
+	j, _, err := openJournal(JournalConfig{Dir: dir, SegmentSize: 1})
+	require.NoError(t, err)
+	var records []*journalRecord
+	for i := 0; i < 3; i++ {
+		records = append(records, j.append(tracesQueue, makeJournalEvents(i)))
+	}
+	records[0].ack()
+	records[2].ack()
+	require.NoError(t, j.close())
+
+	// Simulate an entry partially written when the process crashed.
+	f, err := os.OpenFile(filepath.Join(dir, "00000000000000000003.journal"), os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"id":4,"events":[{"fie`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	j, replay, err := openJournal(JournalConfig{Dir: dir})
+	require.NoError(t, err)
+	require.Len(t, replay.batches, 1)
+	assert.Equal(t, tracesQueue, replay.batches[0].queue)
+	require.Len(t, replay.batches[0].events, 1)
+	assert.Equal(t, time.Unix(1, 0).UTC(), replay.batches[0].events[0].Timestamp.UTC())
+	assert.Equal(t, common.MapStr{"i": int64(1)}, replay.batches[0].events[0].Fields)
+	assert.Len(t, replay.paths, 2)
+
+	// Record IDs continue from the highest ID found on startup.
+	record := j.append(replay.batches[0].queue, replay.batches[0].events)
+	require.NotNil(t, record)
+	assert.Equal(t, uint64(4), record.id)
+	j.removeSegmentFiles(replay.paths)
+	assert.Len(t, listJournalDir(t, dir), 1)
+}
+
+func TestJournalReplayExpired(t *testing.T) {
+	dir := t.TempDir()
+	j, _, err := openJournal(JournalConfig{Dir: dir})
+	require.NoError(t, err)
+	require.NotNil(t, j.append(tracesQueue, makeJournalEvents(0)))
+	require.NoError(t, j.close())
+
+	paths := listJournalDir(t, dir)
+	require.Len(t, paths, 1)
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, paths[0]), old, old))
+
+	_, replay, err := openJournal(JournalConfig{Dir: dir, MaxAge: time.Hour})
+	require.NoError(t, err)
+	assert.Empty(t, replay.batches)
+	assert.Len(t, replay.paths, 1)
+}
+
+func TestJournalReplayNumbers(t *testing.T) {
+	dir := t.TempDir()
+	j, _, err := openJournal(JournalConfig{Dir: dir})
+	require.NoError(t, err)
+	j.append(tracesQueue, []beat.Event{{Fields: common.MapStr{
+		"duration": common.MapStr{"us": int64(1<<53 + 1)},
+		"ratio":    0.5,
+		"values":   []interface{}{1, 1.5},
+	}}})
+	require.NoError(t, j.file.Close())
+	j.file = nil
+
+	_, replay, err := openJournal(JournalConfig{Dir: dir})
+	require.NoError(t, err)
+	require.Len(t, replay.batches, 1)
+	assert.Equal(t, common.MapStr{
+		"duration": map[string]interface{}{"us": int64(1<<53 + 1)},
+		"ratio":    0.5,
+		"values":   []interface{}{int64(1), 1.5},
+	}, replay.batches[0].events[0].Fields)
+}
+
+func TestJournalMaxSize(t *testing.T) {
+	_, _, err := openJournal(JournalConfig{Dir: t.TempDir(), MaxSize: 100})
+	assert.EqualError(t, err, "journal max size 100 must be at least the segment size 67108864")
+
+	j, _, err := openJournal(JournalConfig{Dir: t.TempDir(), MaxSize: 100, SegmentSize: 100})
+	require.NoError(t, err)
+	defer j.close()
+
+	record := j.append(tracesQueue, makeJournalEvents(0))
+	require.NotNil(t, record)
+	assert.Nil(t, j.append(tracesQueue, makeJournalEvents(1)))
+	assert.Nil(t, j.append(tracesQueue, nil))
+}
+
+func makeJournalEvents(i int) []beat.Event {
+	return []beat.Event{{
+		Timestamp: time.Unix(int64(i), 0),
+		Fields:    common.MapStr{"i": i},
+	}}
+}
+
+func listJournalDir(t testing.TB, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name()
+	}
+	return names
+}
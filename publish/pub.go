@@ -27,6 +27,7 @@ import (
 	"go.elastic.co/apm"
 
 	"github.com/elastic/apm-server/datastreams"
+	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/transform"
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
@@ -43,20 +44,66 @@ type Reporter func(context.Context, PendingReq) error
 // queue size in libbeat. As the publisher is not waiting for the outputs ACK, the total
 // number of events active in the system can exceed the queue size. Only the number of
 // concurrent HTTP requests trying to publish at the same time is limited.
+//
+// If PublisherConfig.Queues is specified, batches are split by event type, and
+// traces, errors, and metrics are published through separate queues, each with
+// its own pipeline client.
 type Publisher struct {
 	stopped         chan struct{}
 	tracer          *apm.Tracer
-	client          beat.Client
 	transformConfig *transform.Config
 
-	mu              sync.RWMutex
-	stopping        bool
+	// traces is the queue used for traces, and for any requests
+	// which are not split by event type. errors and metrics are
+	// nil unless separate queues are configured.
+	traces  *queue
+	errors  *queue
+	metrics *queue
+	queues  []*queue
+
+	// journal is nil unless journaling is enabled.
+	journal *journal
+
+	mu       sync.RWMutex
+	stopping bool
+}
+
+const (
+	tracesQueue  = "traces"
+	errorsQueue  = "errors"
+	metricsQueue = "metrics"
+)
+
+type queue struct {
+	name            string
+	metrics         *ackMetrics
+	client          beat.Client
 	pendingRequests chan PendingReq
+	flushMinEvents  int
+
+	// slots holds a token for each request enqueued, or reserved for
+	// enqueuing, in pendingRequests. Reserving slots in all queues of
+	// a split batch before enqueuing any part ensures that a batch is
+	// either enqueued entirely or not at all.
+	slots        chan struct{}
+	flushTimeout time.Duration
 }
 
 type PendingReq struct {
 	Transformable transform.Transformable
 	Trace         bool
+
+	ackWaiter *ACKWaiter
+
+	// events and journalRecord are set if the request was transformed
+	// and journaled before being enqueued.
+	events        []beat.Event
+	transformed   bool
+	journalRecord *journalRecord
+
+	// accepted holds the time at which the request was sent
+	// to the publisher, for measuring acknowledgement lag.
+	accepted time.Time
 }
 
 // PublisherConfig is a struct holding configuration information for the publisher.
@@ -66,12 +113,61 @@ type PublisherConfig struct {
 	Namespace       string
 	Processor       beat.ProcessorList
 	TransformConfig *transform.Config
+
+	// Queues, if non-nil, holds configuration for publishing traces,
+	// errors, and metrics through separate queues and pipeline clients.
+	Queues *QueuesConfig
+
+	// Journal, if non-nil, holds configuration for journaling accepted
+	// events until they are acknowledged, and replaying them on startup.
+	Journal *JournalConfig
+}
+
+// QueuesConfig holds configuration for publishing events through separate
+// queues by event type, so that bursts of one event type do not delay the
+// publishing of other event types.
+type QueuesConfig struct {
+	// Traces holds configuration for the queue of transactions and spans.
+	Traces QueueConfig
+
+	// Errors holds configuration for the queue of errors.
+	Errors QueueConfig
+
+	// Metrics holds configuration for the queue of metricsets and profiles.
+	Metrics QueueConfig
+}
+
+// QueueConfig holds configuration for a single queue of pending requests.
+type QueueConfig struct {
+	// Size holds the number of pending requests buffered by the queue.
+	// If Size is zero, GOMAXPROCS is used.
+	Size int
+
+	// FlushMinEvents holds the minimum number of events each worker
+	// buffers before publishing them. If FlushMinEvents is less than
+	// or equal to one, events are published immediately.
+	FlushMinEvents int
+
+	// FlushTimeout holds the maximum duration for which events are
+	// buffered when FlushMinEvents is greater than one.
+	FlushTimeout time.Duration
 }
 
 func (cfg *PublisherConfig) Validate() error {
 	if cfg.TransformConfig == nil {
 		return errors.New("TransfromConfig unspecified")
 	}
+	if cfg.Queues != nil {
+		for name, queueConfig := range map[string]QueueConfig{
+			"traces":  cfg.Queues.Traces,
+			"errors":  cfg.Queues.Errors,
+			"metrics": cfg.Queues.Metrics,
+		} {
+			if queueConfig.FlushMinEvents > 1 && queueConfig.FlushTimeout <= 0 {
+				return errors.Errorf("%s queue: FlushTimeout must be positive if FlushMinEvents is greater than one", name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -82,8 +178,8 @@ var (
 
 // newPublisher creates a new publisher instance.
 //
-// GOMAXPROCS goroutines are started for forwarding events to libbeat.
-// Stop must be called to close the beat.Client and free resources.
+// GOMAXPROCS goroutines are started for each queue for forwarding events to libbeat.
+// Stop must be called to close the beat.Clients and free resources.
 func NewPublisher(pipeline beat.Pipeline, tracer *apm.Tracer, cfg *PublisherConfig) (*Publisher, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, errors.Wrap(err, "invalid config")
@@ -111,32 +207,101 @@ func NewPublisher(pipeline beat.Pipeline, tracer *apm.Tracer, cfg *PublisherConf
 		processingCfg.Meta = map[string]interface{}{"pipeline": cfg.Pipeline}
 	}
 
+	clientConfig := beat.ClientConfig{
+		PublishMode: beat.GuaranteedSend,
+		Processing:  processingCfg,
+	}
 	p := &Publisher{
 		tracer:          tracer,
 		stopped:         make(chan struct{}),
 		transformConfig: cfg.TransformConfig,
-
-		// One request will be actively processed by the
-		// worker, while the other concurrent requests will be buffered in the queue.
-		pendingRequests: make(chan PendingReq, runtime.GOMAXPROCS(0)),
+	}
+	newQueue := func(name string, queueConfig QueueConfig) (*queue, error) {
+		clientConfig.ACKHandler = newEventACKer()
+		client, err := pipeline.ConnectWith(clientConfig)
+		if err != nil {
+			return nil, err
+		}
+		size := queueConfig.Size
+		if size <= 0 {
+			// One request will be actively processed by the
+			// worker, while the other concurrent requests will be buffered in the queue.
+			size = runtime.GOMAXPROCS(0)
+		}
+		q := &queue{
+			name:            name,
+			metrics:         queueACKMetrics[name],
+			client:          client,
+			pendingRequests: make(chan PendingReq, size),
+			slots:           make(chan struct{}, size),
+			flushMinEvents:  queueConfig.FlushMinEvents,
+			flushTimeout:    queueConfig.FlushTimeout,
+		}
+		p.queues = append(p.queues, q)
+		return q, nil
 	}
 
-	client, err := pipeline.ConnectWith(beat.ClientConfig{
-		PublishMode: beat.GuaranteedSend,
-		Processing:  processingCfg,
-	})
+	var err error
+	if cfg.Queues == nil {
+		p.traces, err = newQueue(tracesQueue, QueueConfig{})
+	} else {
+		if p.traces, err = newQueue(tracesQueue, cfg.Queues.Traces); err == nil {
+			if p.errors, err = newQueue(errorsQueue, cfg.Queues.Errors); err == nil {
+				p.metrics, err = newQueue(metricsQueue, cfg.Queues.Metrics)
+			}
+		}
+	}
 	if err != nil {
+		for _, q := range p.queues {
+			q.client.Close()
+		}
 		return nil, err
 	}
-	p.client = client
+
+	var replay journalReplay
+	if cfg.Journal != nil {
+		if p.journal, replay, err = openJournal(*cfg.Journal); err != nil {
+			for _, q := range p.queues {
+				q.client.Close()
+			}
+			return nil, err
+		}
+	}
 
 	var wg sync.WaitGroup
-	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+	if len(replay.batches) > 0 {
+		// Journal the replayed events again before removing the old
+		// segment files, so they are not lost if the server crashes
+		// again before they have been published.
+		queues := make([]*queue, len(replay.batches))
+		records := make([]*journalRecord, len(replay.batches))
+		for i, batch := range replay.batches {
+			queues[i] = p.queue(batch.queue)
+			records[i] = p.journal.append(queues[i].name, batch.events)
+		}
+		p.journal.removeSegmentFiles(replay.paths)
+
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			p.run()
+			accepted := time.Now()
+			for i, batch := range replay.batches {
+				trackEvents(batch.events, queues[i].metrics, accepted, nil, records[i])
+				queues[i].client.PublishAll(batch.events)
+				journalRecordsReplayed.Inc()
+			}
 		}()
+	} else if p.journal != nil {
+		p.journal.removeSegmentFiles(replay.paths)
+	}
+	for _, q := range p.queues {
+		for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+			wg.Add(1)
+			go func(q *queue) {
+				defer wg.Done()
+				p.run(q)
+			}(q)
+		}
 	}
 	go func() {
 		defer close(p.stopped)
@@ -146,6 +311,17 @@ func NewPublisher(pipeline beat.Pipeline, tracer *apm.Tracer, cfg *PublisherConf
 	return p, nil
 }
 
+// queue returns the queue with the given name, or the traces queue if
+// there is no such queue, e.g. because separate queues are not configured.
+func (p *Publisher) queue(name string) *queue {
+	for _, q := range p.queues {
+		if q.name == name {
+			return q
+		}
+	}
+	return p.traces
+}
+
 // Stop closes all channels and waits for the the worker to stop, or for the
 // context to be signalled. If the context is never cancelled, Stop may block
 // indefinitely.
@@ -159,26 +335,47 @@ func (p *Publisher) Stop(ctx context.Context) error {
 	p.mu.Lock()
 	if !p.stopping {
 		p.stopping = true
-		close(p.pendingRequests)
+		for _, q := range p.queues {
+			close(q.pendingRequests)
+		}
 	}
 	p.mu.Unlock()
 
 	// Wait for enqueued events to be published. Order of events is
 	// important here:
 	//   (1) wait for pendingRequests to be drained and published (p.stopped)
-	//   (2) close the beat.Client to prevent more events being published
+	//   (2) close the beat.Clients to prevent more events being published
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
 	case <-p.stopped:
 	}
-	return p.client.Close()
+	var closeErr error
+	for _, q := range p.queues {
+		if err := q.client.Close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+	if p.journal != nil {
+		if err := p.journal.close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+	return closeErr
 }
 
 // Send tries to forward pendingReq to the publishers worker. If the queue is full,
 // an error is returned.
 //
 // Calling Send after Stop will return an error without enqueuing the request.
+//
+// If ctx holds an ACKWaiter, the request is tracked by it until all of its
+// events have been acknowledged by the output.
+//
+// If separate queues are configured and req holds a *model.Batch, the batch is
+// split by event type and each part is enqueued separately. Either all parts
+// are enqueued, or none are and an error is returned, so agents retrying a
+// rejected batch do not resend events which were already enqueued.
 func (p *Publisher) Send(ctx context.Context, req PendingReq) error {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -186,37 +383,157 @@ func (p *Publisher) Send(ctx context.Context, req PendingReq) error {
 		return ErrChannelClosed
 	}
 
+	type queuedReq struct {
+		q   *queue
+		req PendingReq
+	}
+	var parts []queuedReq
+	if batch, ok := req.Transformable.(*model.Batch); !ok || p.errors == nil {
+		parts = []queuedReq{{p.traces, req}}
+	} else {
+		for _, part := range []struct {
+			q     *queue
+			batch *model.Batch
+		}{
+			{p.traces, &model.Batch{Transactions: batch.Transactions, Spans: batch.Spans}},
+			{p.errors, &model.Batch{Errors: batch.Errors}},
+			{p.metrics, &model.Batch{Metricsets: batch.Metricsets, Profiles: batch.Profiles}},
+		} {
+			if part.batch.Len() == 0 {
+				continue
+			}
+			partReq := req
+			partReq.Transformable = part.batch
+			parts = append(parts, queuedReq{part.q, partReq})
+		}
+	}
+
+	// TODO(axw) instead of having an arbitrary delay here,
+	// make it the caller's responsibility to use a context
+	// with a timeout.
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	for i, part := range parts {
+		if err := part.q.reserve(ctx, timer.C); err != nil {
+			for _, part := range parts[:i] {
+				part.q.unreserve()
+			}
+			return err
+		}
+	}
+	for _, part := range parts {
+		p.enqueue(ctx, part.q, part.req)
+	}
+	return nil
+}
+
+// reserve reserves a slot for enqueuing a request in q, waiting until
+// a slot is free, ctx is done, or timeout fires.
+func (q *queue) reserve(ctx context.Context, timeout <-chan time.Time) error {
 	select {
+	case q.slots <- struct{}{}:
+		return nil
 	case <-ctx.Done():
 		return ctx.Err()
-	case p.pendingRequests <- req:
-		return nil
-	case <-time.After(time.Second * 1):
-		// TODO(axw) instead of having an arbitrary delay here,
-		// make it the caller's responsibility to use a context
-		// with a timeout.
+	case <-timeout:
 		return ErrFull
 	}
 }
 
-func (p *Publisher) run() {
+// unreserve frees a slot reserved or used by a request in q.
+func (q *queue) unreserve() {
+	<-q.slots
+}
+
+// enqueue enqueues req in q, in a slot reserved by the caller.
+func (p *Publisher) enqueue(ctx context.Context, q *queue, req PendingReq) {
+	req.accepted = time.Now()
+	req.ackWaiter = ackWaiterFromContext(ctx)
+	if req.ackWaiter != nil {
+		req.ackWaiter.add()
+	}
+	if p.journal != nil {
+		// Transform and journal events before enqueuing the request,
+		// so they can be replayed if the server crashes before they
+		// have been published.
+		req.events = transformTransformable(ctx, req.Transformable, p.transformConfig)
+		req.transformed = true
+		req.journalRecord = p.journal.append(q.name, req.events)
+	}
+	// pendingRequests has a buffer slot for each reserved slot,
+	// so this never blocks.
+	q.pendingRequests <- req
+}
+
+func (p *Publisher) run(q *queue) {
 	ctx := context.Background()
-	for req := range p.pendingRequests {
-		p.processPendingReq(ctx, req)
+	if q.flushMinEvents <= 1 {
+		for req := range q.pendingRequests {
+			q.unreserve()
+			p.processPendingReq(ctx, q, req)
+		}
+		return
+	}
+
+	// Buffer events until there are at least flushMinEvents,
+	// or flushTimeout has elapsed since the first buffered event.
+	var events []beat.Event
+	var timer *time.Timer
+	var timeout <-chan time.Time
+	flush := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, timeout = nil, nil
+		}
+		if len(events) > 0 {
+			q.client.PublishAll(events)
+			events = nil
+		}
+	}
+	defer flush()
+	for {
+		select {
+		case req, ok := <-q.pendingRequests:
+			if !ok {
+				return
+			}
+			q.unreserve()
+			events = append(events, p.transformPendingReq(ctx, q, req)...)
+			if len(events) >= q.flushMinEvents {
+				flush()
+			} else if timer == nil {
+				timer = time.NewTimer(q.flushTimeout)
+				timeout = timer.C
+			}
+		case <-timeout:
+			timer, timeout = nil, nil
+			flush()
+		}
 	}
 }
 
-func (p *Publisher) processPendingReq(ctx context.Context, req PendingReq) {
+func (p *Publisher) processPendingReq(ctx context.Context, q *queue, req PendingReq) {
 	var tx *apm.Transaction
 	if req.Trace {
 		tx = p.tracer.StartTransaction("ProcessPending", "Publisher")
 		defer tx.End()
 		ctx = apm.ContextWithTransaction(ctx, tx)
 	}
-	events := transformTransformable(ctx, req.Transformable, p.transformConfig)
+	events := p.transformPendingReq(ctx, q, req)
 	span := tx.StartSpan("PublishAll", "Publisher", nil)
 	defer span.End()
-	p.client.PublishAll(events)
+	q.client.PublishAll(events)
+}
+
+// transformPendingReq transforms req into events, unless it was transformed
+// before being enqueued, tracking their acknowledgement by the output.
+func (p *Publisher) transformPendingReq(ctx context.Context, q *queue, req PendingReq) []beat.Event {
+	events := req.events
+	if !req.transformed {
+		events = transformTransformable(ctx, req.Transformable, p.transformConfig)
+	}
+	trackEvents(events, q.metrics, req.accepted, req.ackWaiter, req.journalRecord)
+	return events
 }
 
 func transformTransformable(ctx context.Context, transformable transform.Transformable, cfg *transform.Config) []beat.Event {
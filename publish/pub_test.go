@@ -19,6 +19,7 @@ package publish_test
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,12 +30,14 @@ import (
 
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
 	"github.com/elastic/beats/v7/libbeat/outputs"
 	"github.com/elastic/beats/v7/libbeat/publisher"
 	"github.com/elastic/beats/v7/libbeat/publisher/pipeline"
 	"github.com/elastic/beats/v7/libbeat/publisher/queue"
 	"github.com/elastic/beats/v7/libbeat/publisher/queue/memqueue"
 
+	"github.com/elastic/apm-server/model"
 	"github.com/elastic/apm-server/publish"
 	"github.com/elastic/apm-server/transform"
 )
@@ -100,6 +103,185 @@ func TestPublisherStopShutdownInactive(t *testing.T) {
 	assert.NoError(t, publisher.Stop(context.Background()))
 }
 
+func TestPublisherQueues(t *testing.T) {
+	pipeline := &recordingPipeline{}
+	publisher, err := publish.NewPublisher(pipeline, apmtest.DiscardTracer, &publish.PublisherConfig{
+		TransformConfig: &transform.Config{},
+		Queues: &publish.QueuesConfig{
+			Errors: publish.QueueConfig{FlushMinEvents: 3, FlushTimeout: time.Minute},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, pipeline.clients, 3)
+	traces, errors, metrics := pipeline.clients[0], pipeline.clients[1], pipeline.clients[2]
+
+	send := func(batch *model.Batch) {
+		err := publisher.Send(context.Background(), publish.PendingReq{Transformable: batch})
+		require.NoError(t, err)
+	}
+	send(&model.Batch{
+		Transactions: []*model.Transaction{{}},
+		Spans:        []*model.Span{{}},
+		Errors:       []*model.Error{{}},
+		Metricsets:   []*model.Metricset{{}},
+	})
+	assert.Eventually(t, func() bool {
+		return traces.len() == 2 && metrics.len() == 1
+	}, time.Second, time.Millisecond)
+
+	// Errors are buffered until there are at least 3,
+	// or the publisher is stopped.
+	assert.Equal(t, 0, errors.len())
+	send(&model.Batch{Errors: []*model.Error{{}}})
+
+	require.NoError(t, publisher.Stop(context.Background()))
+	assert.Equal(t, 2, errors.len())
+	for _, client := range pipeline.clients {
+		assert.True(t, client.closed)
+	}
+}
+
+func TestPublisherQueuesFull(t *testing.T) {
+	pipeline := &recordingPipeline{}
+	publisher, err := publish.NewPublisher(pipeline, apmtest.DiscardTracer, &publish.PublisherConfig{
+		TransformConfig: &transform.Config{},
+		Queues: &publish.QueuesConfig{
+			Errors: publish.QueueConfig{Size: 1},
+		},
+	})
+	require.NoError(t, err)
+	traces, errors, metrics := pipeline.clients[0], pipeline.clients[1], pipeline.clients[2]
+	errors.block = make(chan struct{})
+
+	// Block publishing of errors until the errors queue is full.
+	for {
+		err := publisher.Send(context.Background(), publish.PendingReq{
+			Transformable: &model.Batch{Errors: []*model.Error{{}}},
+		})
+		if err == publish.ErrFull {
+			break
+		}
+		require.NoError(t, err)
+	}
+
+	// None of the batch is enqueued if any part cannot be enqueued,
+	// so the batch can be retried without duplicating events.
+	err = publisher.Send(context.Background(), publish.PendingReq{
+		Transformable: &model.Batch{
+			Transactions: []*model.Transaction{{}},
+			Errors:       []*model.Error{{}},
+			Metricsets:   []*model.Metricset{{}},
+		},
+	})
+	assert.Equal(t, publish.ErrFull, err)
+
+	close(errors.block)
+	require.NoError(t, publisher.Stop(context.Background()))
+	assert.Equal(t, 0, traces.len())
+	assert.Equal(t, 0, metrics.len())
+}
+
+func TestPublisherJournalReplay(t *testing.T) {
+	journalConfig := &publish.JournalConfig{Dir: t.TempDir()}
+	newPublisher := func(pipeline beat.Pipeline) *publish.Publisher {
+		publisher, err := publish.NewPublisher(pipeline, apmtest.DiscardTracer, &publish.PublisherConfig{
+			TransformConfig: &transform.Config{},
+			Journal:         journalConfig,
+		})
+		require.NoError(t, err)
+		return publisher
+	}
+
+	// Events are published but never acknowledged,
+	// e.g. because the output is unavailable.
+	pipeline := &recordingPipeline{}
+	publisher := newPublisher(pipeline)
+	event := beat.Event{Timestamp: time.Unix(123, 0).UTC(), Fields: common.MapStr{"k": "v"}}
+	require.NoError(t, publisher.Send(context.Background(), publish.PendingReq{
+		Transformable: makeTransformable(event),
+	}))
+	require.NoError(t, publisher.Stop(context.Background()))
+	require.Len(t, pipeline.clients[0].events, 1)
+
+	// Unacknowledged events are replayed by the next publisher.
+	pipeline = &recordingPipeline{}
+	publisher = newPublisher(pipeline)
+	client := pipeline.clients[0]
+	assert.Eventually(t, func() bool { return client.len() == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, event.Timestamp, client.events[0].Timestamp.UTC())
+	assert.Equal(t, event.Fields, client.events[0].Fields)
+
+	client.ackAll()
+	require.NoError(t, publisher.Stop(context.Background()))
+
+	// All events were acknowledged, so there is nothing left to replay.
+	pipeline = &recordingPipeline{}
+	publisher = newPublisher(pipeline)
+	require.NoError(t, publisher.Stop(context.Background()))
+	assert.Equal(t, 0, pipeline.clients[0].len())
+}
+
+func TestPublisherJournalReplayQueues(t *testing.T) {
+	journalConfig := &publish.JournalConfig{Dir: t.TempDir()}
+	newPublisher := func(pipeline beat.Pipeline) *publish.Publisher {
+		publisher, err := publish.NewPublisher(pipeline, apmtest.DiscardTracer, &publish.PublisherConfig{
+			TransformConfig: &transform.Config{},
+			Queues:          &publish.QueuesConfig{},
+			Journal:         journalConfig,
+		})
+		require.NoError(t, err)
+		return publisher
+	}
+
+	pipeline := &recordingPipeline{}
+	publisher := newPublisher(pipeline)
+	require.NoError(t, publisher.Send(context.Background(), publish.PendingReq{
+		Transformable: &model.Batch{
+			Transactions: []*model.Transaction{{}},
+			Errors:       []*model.Error{{}, {}},
+			Metricsets:   []*model.Metricset{{}, {}, {}},
+		},
+	}))
+	require.NoError(t, publisher.Stop(context.Background()))
+
+	// Replayed events are published through the queue of their event type.
+	pipeline = &recordingPipeline{}
+	publisher = newPublisher(pipeline)
+	traces, errors, metrics := pipeline.clients[0], pipeline.clients[1], pipeline.clients[2]
+	assert.Eventually(t, func() bool {
+		return traces.len() == 1 && errors.len() == 2 && metrics.len() == 3
+	}, time.Second, time.Millisecond)
+	require.NoError(t, publisher.Stop(context.Background()))
+}
+
+func TestPublisherACKMetrics(t *testing.T) {
+	registry := monitoring.Default.GetRegistry("apm-server.publish.ack.traces")
+	require.NotNil(t, registry)
+	snapshot := func() monitoring.FlatSnapshot {
+		return monitoring.CollectFlatSnapshot(registry, monitoring.Full, false)
+	}
+	before := snapshot()
+
+	pipeline := &recordingPipeline{}
+	publisher, err := publish.NewPublisher(pipeline, apmtest.DiscardTracer, &publish.PublisherConfig{
+		TransformConfig: &transform.Config{},
+	})
+	require.NoError(t, err)
+	defer publisher.Stop(context.Background())
+
+	require.NoError(t, publisher.Send(context.Background(), publish.PendingReq{
+		Transformable: makeTransformable(beat.Event{Fields: common.MapStr{}}, beat.Event{Fields: common.MapStr{}}),
+	}))
+	client := pipeline.clients[0]
+	assert.Eventually(t, func() bool { return client.len() == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, before.Ints["events.pending"]+2, snapshot().Ints["events.pending"])
+
+	client.ackAll()
+	after := snapshot()
+	assert.Equal(t, before.Ints["events.pending"], after.Ints["events.pending"])
+	assert.Equal(t, before.Ints["histogram.lag_ms.count"]+1, after.Ints["histogram.lag_ms.count"])
+}
+
 func newBlockingPipeline(t testing.TB) *pipeline.Pipeline {
 	pipeline, err := pipeline.New(
 		beat.Info{},
@@ -129,6 +311,68 @@ func (f transformableFunc) Transform(ctx context.Context, cfg *transform.Config)
 	return f(ctx, cfg)
 }
 
+type recordingPipeline struct {
+	clients []*recordingClient
+}
+
+func (p *recordingPipeline) Connect() (beat.Client, error) {
+	return p.ConnectWith(beat.ClientConfig{})
+}
+
+func (p *recordingPipeline) ConnectWith(cfg beat.ClientConfig) (beat.Client, error) {
+	client := &recordingClient{acker: cfg.ACKHandler}
+	p.clients = append(p.clients, client)
+	return client, nil
+}
+
+type recordingClient struct {
+	acker  beat.ACKer
+	mu     sync.Mutex
+	events []beat.Event
+	closed bool
+
+	// block, if non-nil, blocks publishing until it is closed.
+	block chan struct{}
+}
+
+func (c *recordingClient) Publish(event beat.Event) {
+	c.PublishAll([]beat.Event{event})
+}
+
+func (c *recordingClient) PublishAll(events []beat.Event) {
+	if c.block != nil {
+		<-c.block
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, event := range events {
+		if c.acker != nil {
+			c.acker.AddEvent(event, true)
+		}
+	}
+	c.events = append(c.events, events...)
+}
+
+// ackAll acknowledges all events published so far.
+func (c *recordingClient) ackAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.acker != nil {
+		c.acker.ACKEvents(len(c.events))
+	}
+}
+
+func (c *recordingClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *recordingClient) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.events)
+}
+
 type mockClient struct{}
 
 func (*mockClient) String() string { return "mock_client" }
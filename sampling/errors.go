@@ -0,0 +1,130 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+)
+
+var (
+	errorSamplingMonitoringRegistry = monitoring.Default.NewRegistry("apm-server.error_sampling")
+	errorsDroppedCounter            = monitoring.NewInt(errorSamplingMonitoringRegistry, "dropped")
+)
+
+// rumAgentNames holds the names of RUM agents, new and old.
+var rumAgentNames = map[string]bool{"rum-js": true, "js-base": true}
+
+// ErrorSamplerConfig holds configuration for NewRUMErrorSampler.
+type ErrorSamplerConfig struct {
+	// MaxPerKey holds the maximum number of errors kept per
+	// service and grouping key in each interval.
+	MaxPerKey int
+
+	// Interval holds the interval after which counts are reset.
+	Interval time.Duration
+
+	// MaxGroups holds the maximum number of service and grouping key
+	// pairs tracked in each interval. Errors of further pairs are kept.
+	MaxGroups int
+}
+
+type errorSamplingKey struct {
+	serviceName string
+	groupingKey string
+}
+
+// RUMErrorSampler is a model.BatchProcessor which samples errors sent by
+// RUM agents, keeping the first MaxPerKey errors per service and grouping
+// key in each interval, and discarding the rest.
+type RUMErrorSampler struct {
+	config ErrorSamplerConfig
+
+	mu          sync.Mutex
+	counts      map[errorSamplingKey]int
+	windowStart time.Time
+}
+
+// NewRUMErrorSampler returns a new RUMErrorSampler with the given config.
+//
+// The returned RUMErrorSampler does not guarantee order preservation
+// of errors retained in the batch.
+func NewRUMErrorSampler(config ErrorSamplerConfig) *RUMErrorSampler {
+	return &RUMErrorSampler{
+		config: config,
+		counts: make(map[errorSamplingKey]int),
+	}
+}
+
+// ProcessBatch discards errors sent by RUM agents in excess of the
+// configured maximum for their service and grouping key.
+func (s *RUMErrorSampler) ProcessBatch(ctx context.Context, batch *model.Batch) error {
+	errors := batch.Errors
+	if len(errors) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now := time.Now(); now.Sub(s.windowStart) >= s.config.Interval {
+		s.counts = make(map[errorSamplingKey]int)
+		s.windowStart = now
+	}
+
+	var dropped int64
+	for i := 0; i < len(errors); {
+		e := errors[i]
+		if s.keep(e) {
+			i++
+			continue
+		}
+		n := len(errors)
+		errors[i], errors[n-1] = errors[n-1], errors[i]
+		errors = errors[:n-1]
+		dropped++
+	}
+	if dropped > 0 {
+		errorsDroppedCounter.Add(dropped)
+	}
+	batch.Errors = errors
+	return nil
+}
+
+func (s *RUMErrorSampler) keep(e *model.Error) bool {
+	if !rumAgentNames[e.Metadata.Service.Agent.Name] {
+		return true
+	}
+	key := errorSamplingKey{
+		serviceName: e.Metadata.Service.Name,
+		groupingKey: e.GroupingKey(),
+	}
+	count, ok := s.counts[key]
+	if !ok && len(s.counts) >= s.config.MaxGroups {
+		return true
+	}
+	if count >= s.config.MaxPerKey {
+		return false
+	}
+	s.counts[key] = count + 1
+	return true
+}
@@ -0,0 +1,120 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sampling_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/sampling"
+)
+
+func TestRUMErrorSampler(t *testing.T) {
+	sampler := sampling.NewRUMErrorSampler(sampling.ErrorSamplerConfig{
+		MaxPerKey: 2,
+		Interval:  time.Hour,
+		MaxGroups: 3,
+	})
+	newError := func(agentName, serviceName, message string) *model.Error {
+		return &model.Error{
+			Metadata: model.Metadata{Service: model.Service{
+				Name:  serviceName,
+				Agent: model.Agent{Name: agentName},
+			}},
+			Log: &model.Log{Message: message},
+		}
+	}
+
+	var errors []*model.Error
+	for i := 0; i < 3; i++ {
+		errors = append(errors,
+			newError("rum-js", "frontend", "boom"),
+			newError("js-base", "frontend", "bang"),
+			newError("rum-js", "other", "boom"),
+			newError("go", "backend", "boom"),
+		)
+	}
+	batch := model.Batch{Errors: errors}
+	require.NoError(t, sampler.ProcessBatch(context.Background(), &batch))
+
+	count := func(agentName, serviceName, message string) int {
+		var n int
+		for _, e := range batch.Errors {
+			if e.Metadata.Service.Agent.Name == agentName &&
+				e.Metadata.Service.Name == serviceName &&
+				e.Log.Message == message {
+				n++
+			}
+		}
+		return n
+	}
+	assert.Equal(t, 2, count("rum-js", "frontend", "boom"))
+	assert.Equal(t, 2, count("js-base", "frontend", "bang"))
+	assert.Equal(t, 2, count("rum-js", "other", "boom"))
+	assert.Equal(t, 3, count("go", "backend", "boom"))
+
+	// Errors of groups in excess of MaxGroups are kept.
+	batch = model.Batch{Errors: []*model.Error{
+		newError("rum-js", "frontend", "new"),
+		newError("rum-js", "frontend", "new"),
+		newError("rum-js", "frontend", "new"),
+	}}
+	require.NoError(t, sampler.ProcessBatch(context.Background(), &batch))
+	assert.Len(t, batch.Errors, 3)
+
+	snapshot := monitoring.CollectFlatSnapshot(
+		monitoring.GetRegistry("apm-server.error_sampling"),
+		monitoring.Full,
+		false, // expvar
+	)
+	assert.Equal(t, int64(3), snapshot.Ints["dropped"])
+}
+
+func TestRUMErrorSamplerInterval(t *testing.T) {
+	sampler := sampling.NewRUMErrorSampler(sampling.ErrorSamplerConfig{
+		MaxPerKey: 1,
+		Interval:  time.Millisecond,
+		MaxGroups: 10,
+	})
+	newBatch := func() *model.Batch {
+		return &model.Batch{Errors: []*model.Error{{
+			Metadata: model.Metadata{Service: model.Service{
+				Name:  "frontend",
+				Agent: model.Agent{Name: "rum-js"},
+			}},
+			Log: &model.Log{Message: "boom"},
+		}}}
+	}
+
+	batch := newBatch()
+	require.NoError(t, sampler.ProcessBatch(context.Background(), batch))
+	assert.Len(t, batch.Errors, 1)
+
+	// Counts are reset once the interval has elapsed.
+	time.Sleep(5 * time.Millisecond)
+	batch = newBatch()
+	require.NoError(t, sampler.ProcessBatch(context.Background(), batch))
+	assert.Len(t, batch.Errors, 1)
+}
@@ -0,0 +1,222 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package sidelookup provides a bounded worker pool for lookups against
+// external dependencies performed while enriching events, such as fetching
+// source maps. Lookups are bounded in concurrency and duration, and are
+// skipped while the dependency is failing, so that a slow or unavailable
+// dependency degrades enrichment rather than the latency of event intake.
+package sidelookup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+var (
+	// ErrSaturated is returned by Pool.Do when all workers are busy
+	// and the queue of pending lookups is full.
+	ErrSaturated = errors.New("lookup skipped: worker pool saturated")
+
+	// ErrCircuitOpen is returned by Pool.Do while the circuit breaker
+	// is open, following consecutive lookup failures.
+	ErrCircuitOpen = errors.New("lookup skipped: circuit breaker open")
+
+	// ErrTimeout is returned by Pool.Do when a lookup does not complete
+	// within the lookup timeout.
+	ErrTimeout = errors.New("lookup timed out")
+)
+
+var (
+	registry            = monitoring.Default.NewRegistry("apm-server.side_lookups")
+	monitoringLookups   = monitoring.NewInt(registry, "lookups")
+	monitoringFailures  = monitoring.NewInt(registry, "failures")
+	monitoringTimeouts  = monitoring.NewInt(registry, "timeouts")
+	monitoringSaturated = monitoring.NewInt(registry, "saturated")
+	monitoringOpen      = monitoring.NewInt(registry, "circuit_open")
+)
+
+// Config holds configuration for a Pool.
+type Config struct {
+	// Workers holds the maximum number of concurrent lookups.
+	Workers int
+
+	// QueueSize holds the maximum number of lookups waiting for a
+	// worker. Further lookups are skipped.
+	QueueSize int
+
+	// Timeout holds the maximum duration of a lookup, including the
+	// time spent waiting for a worker.
+	Timeout time.Duration
+
+	// FailureThreshold holds the number of consecutive failed lookups
+	// after which the circuit breaker opens. If zero, the circuit
+	// breaker is disabled.
+	FailureThreshold int
+
+	// Cooldown holds the duration for which lookups are skipped once
+	// the circuit breaker opens. After the cooldown, lookups are
+	// attempted again; the circuit breaker reopens on the next failure.
+	Cooldown time.Duration
+}
+
+// Pool is a bounded worker pool for lookups.
+type Pool struct {
+	cfg     Config
+	pending chan struct{} // workers and queue
+	workers chan struct{}
+	breaker *Breaker
+}
+
+// Breaker is a circuit breaker for the lookups against a dependency.
+// Lookups against different dependencies sharing a Pool should use their
+// own Breaker, so that a failing dependency does not cause lookups against
+// the others to be skipped.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// NewPool returns a new Pool.
+func NewPool(cfg Config) (*Pool, error) {
+	if cfg.Workers <= 0 {
+		return nil, errors.New("workers must be greater than zero")
+	}
+	if cfg.QueueSize < 0 {
+		return nil, errors.New("queue size must not be negative")
+	}
+	if cfg.Timeout <= 0 {
+		return nil, errors.New("timeout must be greater than zero")
+	}
+	p := &Pool{
+		cfg:     cfg,
+		pending: make(chan struct{}, cfg.Workers+cfg.QueueSize),
+		workers: make(chan struct{}, cfg.Workers),
+	}
+	p.breaker = p.NewBreaker()
+	return p, nil
+}
+
+// NewBreaker returns a new Breaker with the pool's failure threshold and
+// cooldown, for use with DoWithBreaker.
+func (p *Pool) NewBreaker() *Breaker {
+	return &Breaker{threshold: p.cfg.FailureThreshold, cooldown: p.cfg.Cooldown}
+}
+
+// Do performs the lookup fn in a worker, returning its result, or an error
+// if the lookup is skipped or does not complete within the lookup timeout.
+// Do uses the pool's own circuit breaker; see DoWithBreaker.
+func (p *Pool) Do(ctx context.Context, fn func(context.Context) error) error {
+	return p.DoWithBreaker(ctx, p.breaker, fn)
+}
+
+// DoWithBreaker performs the lookup fn in a worker like Do, using the
+// circuit breaker b, which must have been created by p.NewBreaker.
+//
+// fn is passed a context which is cancelled when the lookup times out. If
+// fn does not return when the context is cancelled, DoWithBreaker returns
+// regardless, and the worker remains busy until fn returns. Errors returned
+// by fn are counted as failures by the circuit breaker.
+func (p *Pool) DoWithBreaker(ctx context.Context, b *Breaker, fn func(context.Context) error) error {
+	now := time.Now()
+	if !b.allow(now) {
+		monitoringOpen.Inc()
+		return ErrCircuitOpen
+	}
+	select {
+	case p.pending <- struct{}{}:
+	default:
+		monitoringSaturated.Inc()
+		return ErrSaturated
+	}
+	monitoringLookups.Inc()
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	select {
+	case p.workers <- struct{}{}:
+	case <-ctx.Done():
+		cancel()
+		<-p.pending
+		return b.timedOut(ctx)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			cancel()
+			<-p.workers
+			<-p.pending
+		}()
+		result <- fn(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		b.record(err == nil)
+		if err != nil {
+			monitoringFailures.Inc()
+		}
+		return err
+	case <-ctx.Done():
+		return b.timedOut(ctx)
+	}
+}
+
+func (b *Breaker) timedOut(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		// The caller gave up, which says nothing about the dependency.
+		return ctx.Err()
+	}
+	monitoringTimeouts.Inc()
+	b.record(false)
+	return ErrTimeout
+}
+
+// allow reports whether a lookup may be performed at the given time.
+func (b *Breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !now.Before(b.openUntil)
+}
+
+// record records the outcome of a lookup with the circuit breaker.
+func (b *Breaker) record(success bool) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+		// Reopen on the next failure after the cooldown.
+		b.failures = b.threshold - 1
+	}
+}
@@ -0,0 +1,165 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sidelookup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolDo(t *testing.T) {
+	pool, err := NewPool(Config{Workers: 1, Timeout: time.Second})
+	require.NoError(t, err)
+
+	var called bool
+	err = pool.Do(context.Background(), func(ctx context.Context) error {
+		called = true
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+
+	errLookup := errors.New("boom")
+	err = pool.Do(context.Background(), func(context.Context) error { return errLookup })
+	assert.Equal(t, errLookup, err)
+}
+
+func TestPoolTimeout(t *testing.T) {
+	pool, err := NewPool(Config{Workers: 1, QueueSize: 1, Timeout: 10 * time.Millisecond})
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	defer close(release)
+	err = pool.Do(context.Background(), func(context.Context) error {
+		// Ignore the context, to ensure Do returns regardless.
+		<-release
+		return nil
+	})
+	assert.Equal(t, ErrTimeout, err)
+
+	// The worker is still busy, so the next lookup times
+	// out waiting for a worker.
+	err = pool.Do(context.Background(), func(context.Context) error { return nil })
+	assert.Equal(t, ErrTimeout, err)
+}
+
+func TestPoolSaturated(t *testing.T) {
+	pool, err := NewPool(Config{Workers: 1, QueueSize: 1, Timeout: time.Minute})
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 2)
+	go func() {
+		done <- pool.Do(context.Background(), func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	go func() {
+		done <- pool.Do(context.Background(), func(context.Context) error { return nil })
+	}()
+
+	// One lookup is running and one is queued, so further lookups are skipped.
+	assert.Eventually(t, func() bool { return len(pool.pending) == 2 }, time.Second, time.Millisecond)
+	err = pool.Do(context.Background(), func(context.Context) error {
+		t.Error("lookup should have been skipped")
+		return nil
+	})
+	assert.Equal(t, ErrSaturated, err)
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+}
+
+func TestPoolCircuitBreaker(t *testing.T) {
+	pool, err := NewPool(Config{Workers: 1, Timeout: time.Second, FailureThreshold: 2, Cooldown: 50 * time.Millisecond})
+	require.NoError(t, err)
+
+	errLookup := errors.New("boom")
+	fail := func(context.Context) error { return errLookup }
+	succeed := func(context.Context) error { return nil }
+
+	// Successes reset the consecutive failure count.
+	assert.Equal(t, errLookup, pool.Do(context.Background(), fail))
+	assert.NoError(t, pool.Do(context.Background(), succeed))
+	assert.Equal(t, errLookup, pool.Do(context.Background(), fail))
+	assert.Equal(t, errLookup, pool.Do(context.Background(), fail))
+	assert.Equal(t, ErrCircuitOpen, pool.Do(context.Background(), succeed))
+
+	// After the cooldown, a single failure reopens the circuit breaker.
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, errLookup, pool.Do(context.Background(), fail))
+	assert.Equal(t, ErrCircuitOpen, pool.Do(context.Background(), succeed))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, pool.Do(context.Background(), succeed))
+	assert.Equal(t, errLookup, pool.Do(context.Background(), fail))
+	assert.NoError(t, pool.Do(context.Background(), succeed))
+}
+
+func TestPoolBreakers(t *testing.T) {
+	pool, err := NewPool(Config{Workers: 1, Timeout: time.Second, FailureThreshold: 1, Cooldown: time.Minute})
+	require.NoError(t, err)
+	failing, healthy := pool.NewBreaker(), pool.NewBreaker()
+
+	errLookup := errors.New("boom")
+	fail := func(context.Context) error { return errLookup }
+	succeed := func(context.Context) error { return nil }
+
+	// Failures only open the circuit breaker they are recorded with.
+	assert.Equal(t, errLookup, pool.DoWithBreaker(context.Background(), failing, fail))
+	assert.Equal(t, ErrCircuitOpen, pool.DoWithBreaker(context.Background(), failing, succeed))
+	assert.NoError(t, pool.DoWithBreaker(context.Background(), healthy, succeed))
+	assert.NoError(t, pool.Do(context.Background(), succeed))
+}
+
+func TestPoolCallerCancelled(t *testing.T) {
+	pool, err := NewPool(Config{Workers: 1, Timeout: time.Minute, FailureThreshold: 1, Cooldown: time.Minute})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = pool.Do(ctx, func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+
+	// Cancellation by the caller does not open the circuit breaker.
+	assert.NoError(t, pool.Do(context.Background(), func(context.Context) error { return nil }))
+}
+
+func TestNewPoolInvalid(t *testing.T) {
+	_, err := NewPool(Config{Timeout: time.Second})
+	assert.EqualError(t, err, "workers must be greater than zero")
+	_, err = NewPool(Config{Workers: 1, QueueSize: -1, Timeout: time.Second})
+	assert.EqualError(t, err, "queue size must not be negative")
+	_, err = NewPool(Config{Workers: 1})
+	assert.EqualError(t, err, "timeout must be greater than zero")
+}
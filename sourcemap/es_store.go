@@ -25,6 +25,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -37,6 +38,10 @@ import (
 const (
 	emptyResult          = ""
 	errMsgParseSourcemap = "Could not parse Sourcemap."
+
+	// maxSourcemapVersions limits the number of sourcemap versions considered
+	// when selecting a sourcemap by upload time.
+	maxSourcemapVersions = 100
 )
 
 var (
@@ -87,10 +92,98 @@ func (s *esStore) fetch(ctx context.Context, name, version, path string) (string
 	return parse(body, name, version, path, s.logger)
 }
 
+// sourcemapVersion identifies an uploaded version of a sourcemap.
+type sourcemapVersion struct {
+	id       string
+	uploaded time.Time
+}
+
+type esSourcemapVersionsResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string `json:"_id"`
+			Source struct {
+				Timestamp time.Time `json:"@timestamp"`
+			} `json:"_source"`
+			// Sort holds the hit's sort values, the first of which is its score.
+			Sort []interface{} `json:"sort"`
+		}
+	} `json:"hits"`
+}
+
+// fetchVersions returns the uploaded versions of a sourcemap, most recent first.
+//
+// As with fetch, sourcemaps matching the full bundle URL are preferred: only
+// the versions of the best matching bundle file path are returned.
+func (s *esStore) fetchVersions(ctx context.Context, name, version, path string) ([]sourcemapVersion, error) {
+	statusCode, body, err := s.search(ctx, versionsQuery(name, version, path))
+	if err != nil {
+		return nil, errors.Wrap(err, errMsgESFailure)
+	}
+	defer body.Close()
+	if statusCode >= http.StatusMultipleChoices {
+		if statusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, errors.Wrap(err, errMsgParseSourcemap)
+		}
+		return nil, errors.New(fmt.Sprintf("%s %s", errMsgParseSourcemap, b))
+	}
+
+	var resp esSourcemapVersionsResponse
+	if err := json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, err
+	}
+	var versions []sourcemapVersion
+	for _, hit := range resp.Hits.Hits {
+		if len(versions) > 0 && !sameScore(hit.Sort, resp.Hits.Hits[0].Sort) {
+			// Hits are sorted by score first, so all remaining
+			// hits match the bundle file path less closely.
+			break
+		}
+		versions = append(versions, sourcemapVersion{id: hit.ID, uploaded: hit.Source.Timestamp})
+	}
+	return versions, nil
+}
+
+// fetchID returns the sourcemap stored in the document with the given ID.
+func (s *esStore) fetchID(ctx context.Context, id string) (string, error) {
+	statusCode, body, err := s.search(ctx, idQuery(id))
+	if err != nil {
+		return "", errors.Wrap(err, errMsgESFailure)
+	}
+	defer body.Close()
+	if statusCode >= http.StatusMultipleChoices {
+		if statusCode == http.StatusNotFound {
+			return "", nil
+		}
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			return "", errors.Wrap(err, errMsgParseSourcemap)
+		}
+		return "", errors.New(fmt.Sprintf("%s %s", errMsgParseSourcemap, b))
+	}
+	return parse(body, "", "", id, s.logger)
+}
+
+// sameScore reports whether the hits with the sort values a and b have the same score.
+func sameScore(a, b []interface{}) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	return a[0] == b[0]
+}
+
 func (s *esStore) runSearchQuery(ctx context.Context, name, version, path string) (int, io.ReadCloser, error) {
+	return s.search(ctx, query(name, version, path))
+}
+
+func (s *esStore) search(ctx context.Context, query map[string]interface{}) (int, io.ReadCloser, error) {
 	// build and encode the query
 	var buf bytes.Buffer
-	if err := json.NewEncoder(&buf).Encode(query(name, version, path)); err != nil {
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
 		return 0, nil, err
 	}
 	// Perform the runSearchQuery request.
@@ -122,27 +215,47 @@ func parse(body io.ReadCloser, name, version, path string, logger *logp.Logger)
 
 func query(name, version, path string) map[string]interface{} {
 	return searchFirst(
-		boolean(
-			must(
-				term("processor.name", "sourcemap"),
-				term("sourcemap.service.name", name),
-				term("sourcemap.service.version", version),
-				term("processor.name", "sourcemap"),
-				boolean(
-					should(
-						// prefer full URL match
-						boostedTerm("sourcemap.bundle_filepath", path, 2.0),
-						term("sourcemap.bundle_filepath", utility.UrlPath(path)),
-					),
-				),
-			),
-		),
+		matchQuery(name, version, path),
 		"sourcemap.sourcemap",
 		desc("_score"),
 		desc("@timestamp"),
 	)
 }
 
+func versionsQuery(name, version, path string) map[string]interface{} {
+	return map[string]interface{}{
+		"query":   matchQuery(name, version, path),
+		"size":    maxSourcemapVersions,
+		"sort":    []map[string]interface{}{desc("_score"), desc("@timestamp")},
+		"_source": "@timestamp",
+	}
+}
+
+func idQuery(id string) map[string]interface{} {
+	return searchFirst(
+		wrap("ids", map[string]interface{}{"values": []string{id}}),
+		"sourcemap.sourcemap",
+		desc("_score"),
+	)
+}
+
+func matchQuery(name, version, path string) map[string]interface{} {
+	return boolean(
+		must(
+			term("processor.name", "sourcemap"),
+			term("sourcemap.service.name", name),
+			term("sourcemap.service.version", version),
+			boolean(
+				should(
+					// prefer full URL match
+					boostedTerm("sourcemap.bundle_filepath", path, 2.0),
+					term("sourcemap.bundle_filepath", utility.UrlPath(path)),
+				),
+			),
+		),
+	)
+}
+
 func wrap(k string, v map[string]interface{}) map[string]interface{} {
 	return map[string]interface{}{k: v}
 }
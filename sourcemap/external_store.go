@@ -0,0 +1,259 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sourcemap
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+
+	"github.com/elastic/apm-server/httpproxy"
+	"github.com/elastic/apm-server/utility"
+)
+
+const (
+	defaultExternalTimeout               = 10 * time.Second
+	defaultExternalMaxSize               = 50 * 1024 * 1024
+	defaultExternalMaxConcurrentRequests = 10
+
+	// externalFailureTTL is the duration for which temporary failures
+	// to fetch a source map are cached, so that events of a failing
+	// bundle do not each send a request to the external store.
+	externalFailureTTL = 30 * time.Second
+
+	errMsgExternalFailure = "failure fetching sourcemap from external store"
+)
+
+// ExternalSource describes an external artifact store, such as an S3 or GCS
+// bucket or an HTTP server, from which source maps of a service are fetched
+// when no source map has been uploaded for a bundle.
+type ExternalSource struct {
+	// ServiceName holds the name of the service whose source maps are
+	// fetched from this source.
+	ServiceName string
+
+	// ServiceVersion holds the version of the service whose source maps
+	// are fetched from this source. If empty, any version matches.
+	ServiceVersion string
+
+	// URL holds the template of the source map URL. The placeholders
+	// {service.name}, {service.version}, {bundle_filepath} (the bundle's
+	// URL path without leading slash), and {bundle_filename} (the last
+	// element of the bundle's URL path) are replaced for each bundle.
+	// URLs with the schemes s3:// and gs:// are fetched from the public
+	// HTTPS endpoints of the bucket. Requests are not signed, so only
+	// public buckets, or buckets authorizing requests by Headers, are
+	// supported.
+	URL string
+
+	// Headers holds HTTP headers sent with each request, e.g. for
+	// authorization.
+	Headers map[string]string
+
+	// Timeout holds the maximum duration of a request. If zero, a
+	// default of 10 seconds is used.
+	Timeout time.Duration
+
+	// MaxSize holds the maximum size of a source map in bytes. Larger
+	// source maps are not applied. If zero, a default of 50MiB is used.
+	MaxSize int64
+
+	// MaxConcurrentRequests holds the maximum number of concurrent
+	// requests to the source. If zero, a default of 10 is used.
+	MaxConcurrentRequests int
+
+	// Proxy holds the proxy configuration for requests to the source.
+	Proxy httpproxy.Config
+}
+
+// ValidateURL returns an error if rawurl is not a valid source map URL template.
+func ValidateURL(rawurl string) error {
+	_, err := expandURL(rawurl, "service", "1.0", "/bundle.js")
+	return err
+}
+
+// expandURL returns the source map URL for a bundle from the template rawurl.
+//
+// The bundle's path is cleaned, so that it cannot refer to a parent of the
+// template's path, and each of its segments is escaped.
+func expandURL(rawurl, name, version, bundleFilepath string) (string, error) {
+	bundlePath := strings.TrimPrefix(path.Clean("/"+utility.UrlPath(bundleFilepath)), "/")
+	segments := strings.Split(bundlePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	expanded := strings.NewReplacer(
+		"{service.name}", url.PathEscape(name),
+		"{service.version}", url.PathEscape(version),
+		"{bundle_filepath}", strings.Join(segments, "/"),
+		"{bundle_filename}", segments[len(segments)-1],
+	).Replace(rawurl)
+	u, err := url.Parse(expanded)
+	if err != nil {
+		return "", err
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "s3":
+		u.Scheme = "https"
+		u.Host = u.Host + ".s3.amazonaws.com"
+	case "gs":
+		u.Scheme = "https"
+		u.Path = "/" + u.Host + u.Path
+		u.Host = "storage.googleapis.com"
+	default:
+		return "", errors.Errorf("unsupported scheme %q, expected http, https, s3, or gs", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", errors.New("missing host")
+	}
+	return u.String(), nil
+}
+
+type externalStore struct {
+	sources  []ExternalSource
+	clients  []*http.Client  // clients[i] sends requests to sources[i]
+	requests []chan struct{} // requests[i] limits concurrent requests to sources[i]
+	failures *gocache.Cache  // temporary failures by URL
+	logger   *logp.Logger
+}
+
+func newExternalStore(sources []ExternalSource, logger *logp.Logger) (*externalStore, error) {
+	clients := make([]*http.Client, len(sources))
+	requests := make([]chan struct{}, len(sources))
+	for i, source := range sources {
+		proxy, err := source.Proxy.ProxyFunc()
+		if err != nil {
+			return nil, errors.Wrapf(err, "external source for service %q", source.ServiceName)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = proxy
+		clients[i] = &http.Client{Transport: transport}
+		maxRequests := source.MaxConcurrentRequests
+		if maxRequests <= 0 {
+			maxRequests = defaultExternalMaxConcurrentRequests
+		}
+		requests[i] = make(chan struct{}, maxRequests)
+	}
+	return &externalStore{
+		sources:  sources,
+		clients:  clients,
+		requests: requests,
+		failures: gocache.New(externalFailureTTL, cleanupInterval(externalFailureTTL)),
+		logger:   logger,
+	}, nil
+}
+
+// source returns the index of the first source matching the service name
+// and version, or -1 if there is none.
+func (s *externalStore) source(name, version string) int {
+	for i, source := range s.sources {
+		if source.ServiceName != name {
+			continue
+		}
+		if source.ServiceVersion != "" && source.ServiceVersion != version {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+func (s *externalStore) fetch(ctx context.Context, name, version, bundleFilepath string) (string, error) {
+	i := s.source(name, version)
+	if i < 0 {
+		return emptyResult, nil
+	}
+	source := s.sources[i]
+	u, err := expandURL(source.URL, name, version, bundleFilepath)
+	if err != nil {
+		return "", err
+	}
+	if cached, found := s.failures.Get(u); found {
+		return "", cached.(error)
+	}
+	result, err := s.fetchURL(ctx, i, u)
+	if err != nil && isTemporary(err) {
+		s.failures.SetDefault(u, err)
+	}
+	return result, err
+}
+
+func (s *externalStore) fetchURL(ctx context.Context, i int, u string) (string, error) {
+	source := s.sources[i]
+	timeout := source.Timeout
+	if timeout <= 0 {
+		timeout = defaultExternalTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	select {
+	case s.requests[i] <- struct{}{}:
+		defer func() { <-s.requests[i] }()
+	case <-ctx.Done():
+		return "", errors.Wrap(ctx.Err(), errMsgExternalFailure)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range source.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.clients[i].Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, errMsgExternalFailure)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		s.logger.Debugf("no sourcemap found at %s", u)
+		return emptyResult, nil
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return "", errors.Errorf("%s: %s returned %s", errMsgExternalFailure, u, resp.Status)
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		// Client errors, e.g. access denied, are not expected to be resolved by retrying.
+		return "", errors.Errorf("fetching sourcemap from %s returned %s", u, resp.Status)
+	}
+	maxSize := source.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultExternalMaxSize
+	}
+	if resp.ContentLength > maxSize {
+		return "", errors.Errorf("sourcemap at %s exceeds the limit of %d bytes", u, maxSize)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return "", errors.Wrap(err, errMsgExternalFailure)
+	}
+	if int64(len(body)) > maxSize {
+		return "", errors.Errorf("sourcemap at %s exceeds the limit of %d bytes", u, maxSize)
+	}
+	return string(body), nil
+}
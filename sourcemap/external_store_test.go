@@ -0,0 +1,244 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package sourcemap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/httpproxy"
+	"github.com/elastic/apm-server/sourcemap/test"
+)
+
+func TestExpandURL(t *testing.T) {
+	for name, tc := range map[string]struct {
+		url, expected string
+	}{
+		"http": {
+			url:      "http://maps.example.com/{service.name}/{service.version}/{bundle_filepath}.map",
+			expected: "http://maps.example.com/my%20app/1.0.1/static/js/bundle.js.map",
+		},
+		"filename": {
+			url:      "https://maps.example.com/{bundle_filename}.map",
+			expected: "https://maps.example.com/bundle.js.map",
+		},
+		"s3": {
+			url:      "s3://my-bucket/{service.version}/{bundle_filepath}.map",
+			expected: "https://my-bucket.s3.amazonaws.com/1.0.1/static/js/bundle.js.map",
+		},
+		"gs": {
+			url:      "gs://my-bucket/{service.version}/{bundle_filepath}.map",
+			expected: "https://storage.googleapis.com/my-bucket/1.0.1/static/js/bundle.js.map",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			out, err := expandURL(tc.url, "my app", "1.0.1", "http://localhost:8000/static/js/bundle.js?v=1")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestExpandURLEscaping(t *testing.T) {
+	for bundleFilepath, expected := range map[string]string{
+		"http://localhost/static/a%3Fb%23c.js": "http://maps.example.com/maps/static/a%3Fb%23c.js.map",
+		"http://localhost/static/a b.js":       "http://maps.example.com/maps/static/a%20b.js.map",
+		"http://localhost/../../secret.js":     "http://maps.example.com/maps/secret.js.map",
+		"/static/../../../secret.js":           "http://maps.example.com/maps/secret.js.map",
+	} {
+		out, err := expandURL("http://maps.example.com/maps/{bundle_filepath}.map", "foo", "1.0.1", bundleFilepath)
+		require.NoError(t, err)
+		assert.Equal(t, expected, out, bundleFilepath)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	assert.NoError(t, ValidateURL("s3://bucket/{bundle_filepath}.map"))
+	assert.EqualError(t, ValidateURL("ftp://host/{bundle_filepath}.map"),
+		`unsupported scheme "ftp", expected http, https, s3, or gs`)
+	assert.EqualError(t, ValidateURL("/{bundle_filepath}.map"),
+		`unsupported scheme "", expected http, https, s3, or gs`)
+	assert.EqualError(t, ValidateURL("http:///{bundle_filepath}.map"), "missing host")
+}
+
+func TestStore_FetchExternal(t *testing.T) {
+	serviceName, serviceVersion, path := "foo", "1.0.1", "/static/bundle.js"
+	key := "foo_1.0.1_/static/bundle.js"
+
+	var requests []*http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch r.URL.Path {
+		case "/foo/1.0.1/static/bundle.js.map":
+			w.Write([]byte(test.ValidSourcemap))
+		case "/unavailable/static/bundle.js.map":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/forbidden/static/bundle.js.map":
+			w.WriteHeader(http.StatusForbidden)
+		case "/large/static/bundle.js.map":
+			w.Write([]byte(test.ValidSourcemap))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	newStore := func(t *testing.T, sources ...ExternalSource) *Store {
+		store, err := NewStore(test.ESClientWithSourcemapNotFound(t), "apm-*sourcemap*", time.Minute, VersionSelectionLatest, sources...)
+		require.NoError(t, err)
+		return store
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		requests = nil
+		store := newStore(t, ExternalSource{
+			ServiceName: serviceName,
+			URL:         srv.URL + "/{service.name}/{service.version}/{bundle_filepath}.map",
+			Headers:     map[string]string{"Authorization": "Bearer abc"},
+		})
+		mapper, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+		require.NoError(t, err)
+		require.NotNil(t, mapper)
+		assert.Equal(t, "bundle.js", mapper.File())
+		require.Len(t, requests, 1)
+		assert.Equal(t, "Bearer abc", requests[0].Header.Get("Authorization"))
+
+		cached, found := store.cache.Get(key)
+		require.True(t, found)
+		assert.Equal(t, mapper, cached)
+	})
+
+	t.Run("uploadedTakesPrecedence", func(t *testing.T) {
+		requests = nil
+		store, err := NewStore(test.ESClientWithValidSourcemap(t), "apm-*sourcemap*", time.Minute, VersionSelectionLatest, ExternalSource{
+			ServiceName: serviceName,
+			URL:         srv.URL + "/{service.name}/{service.version}/{bundle_filepath}.map",
+		})
+		require.NoError(t, err)
+		mapper, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+		require.NoError(t, err)
+		require.NotNil(t, mapper)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("noMatchingSource", func(t *testing.T) {
+		requests = nil
+		store := newStore(t,
+			ExternalSource{ServiceName: "bar", URL: srv.URL + "/{bundle_filepath}.map"},
+			ExternalSource{ServiceName: serviceName, ServiceVersion: "2.0.0", URL: srv.URL + "/{bundle_filepath}.map"},
+		)
+		mapper, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+		require.NoError(t, err)
+		assert.Nil(t, mapper)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		store := newStore(t, ExternalSource{ServiceName: serviceName, URL: srv.URL + "/missing/{bundle_filepath}.map"})
+		mapper, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+		require.NoError(t, err)
+		assert.Nil(t, mapper)
+
+		// ensure nil value is added to cache
+		cached, found := store.cache.Get(key)
+		assert.True(t, found)
+		assert.Nil(t, cached)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		store := newStore(t, ExternalSource{ServiceName: serviceName, URL: srv.URL + "/forbidden/{bundle_filepath}.map"})
+		mapper, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+		require.Error(t, err)
+		assert.Nil(t, mapper)
+
+		// ensure nil value is added to cache
+		_, found := store.cache.Get(key)
+		assert.True(t, found)
+	})
+
+	t.Run("unavailable", func(t *testing.T) {
+		store := newStore(t, ExternalSource{ServiceName: serviceName, URL: srv.URL + "/unavailable/{bundle_filepath}.map"})
+		mapper, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+		require.Error(t, err)
+		assert.Nil(t, mapper)
+
+		// ensure not cached
+		_, found := store.cache.Get(key)
+		assert.False(t, found)
+	})
+
+	t.Run("unavailableRetry", func(t *testing.T) {
+		requests = nil
+		store := newStore(t, ExternalSource{ServiceName: serviceName, URL: srv.URL + "/unavailable/{bundle_filepath}.map"})
+		for i := 0; i < 3; i++ {
+			_, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+			require.Error(t, err)
+		}
+		// ensure failures are not retried until the failure expires
+		assert.Len(t, requests, 1)
+	})
+
+	t.Run("tooLarge", func(t *testing.T) {
+		store := newStore(t, ExternalSource{
+			ServiceName: serviceName,
+			URL:         srv.URL + "/large/{bundle_filepath}.map",
+			MaxSize:     int64(len(test.ValidSourcemap) - 1),
+		})
+		mapper, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds the limit")
+		assert.Nil(t, mapper)
+
+		// ensure nil value is added to cache
+		_, found := store.cache.Get(key)
+		assert.True(t, found)
+	})
+}
+
+func TestStore_FetchExternalProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		w.Write([]byte(test.ValidSourcemap))
+	}))
+	defer proxy.Close()
+
+	store, err := NewStore(test.ESClientWithSourcemapNotFound(t), "apm-*sourcemap*", time.Minute, VersionSelectionLatest, ExternalSource{
+		ServiceName: "foo",
+		URL:         "http://maps.invalid/{bundle_filepath}.map",
+		Proxy:       httpproxy.Config{URL: proxy.URL},
+	})
+	require.NoError(t, err)
+	mapper, err := store.Fetch(context.Background(), "foo", "1.0.1", "/static/bundle.js")
+	require.NoError(t, err)
+	require.NotNil(t, mapper)
+	assert.Equal(t, []string{"http://maps.invalid/static/bundle.js.map"}, proxied)
+
+	_, err = NewStore(test.ESClientWithSourcemapNotFound(t), "apm-*sourcemap*", time.Minute, VersionSelectionLatest, ExternalSource{
+		ServiceName: "foo",
+		URL:         "http://maps.invalid/{bundle_filepath}.map",
+		Proxy:       httpproxy.Config{URL: "ftp://proxy"},
+	})
+	assert.EqualError(t, err, `external source for service "foo": unsupported proxy URL scheme "ftp", expected http, https, or socks5`)
+}
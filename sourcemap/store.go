@@ -32,6 +32,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/logp"
 
 	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/sidelookup"
 )
 
 const (
@@ -42,25 +43,78 @@ var (
 	errInit = errors.New("Cache cannot be initialized. Expiration and CleanupInterval need to be >= 0")
 )
 
-// Store holds information necessary to fetch a sourcemap, either from an Elasticsearch instance or an internal cache.
+// VersionSelection determines which of the uploaded versions of a sourcemap is used
+// for mapping an event's stacktrace.
+type VersionSelection string
+
+const (
+	// VersionSelectionLatest selects the most recently uploaded sourcemap.
+	VersionSelectionLatest VersionSelection = "latest"
+
+	// VersionSelectionUploadTime selects the most recent sourcemap uploaded
+	// at or before the event's timestamp, so that re-uploading a sourcemap
+	// does not change the mapping of earlier events.
+	VersionSelectionUploadTime VersionSelection = "upload_time"
+)
+
+// Store holds information necessary to fetch a sourcemap, either from an Elasticsearch instance,
+// an external artifact store, or an internal cache.
 type Store struct {
-	cache   *gocache.Cache
-	esStore *esStore
-	logger  *logp.Logger
+	cache         *gocache.Cache
+	versions      *gocache.Cache
+	selection     VersionSelection
+	esStore       *esStore
+	externalStore *externalStore
+	pool          *sidelookup.Pool
+	esBreaker     *sidelookup.Breaker
+	extBreaker    *sidelookup.Breaker
+	logger        *logp.Logger
 }
 
 // NewStore creates a new instance for fetching sourcemaps. The client and index parameters are needed to be able to
 // fetch sourcemaps from Elasticsearch. The expiration time is used for the internal cache.
-func NewStore(client elasticsearch.Client, index string, expiration time.Duration) (*Store, error) {
+// The selection determines the sourcemap version used by FetchAt.
+//
+// Sourcemaps not found in Elasticsearch are fetched from the first of the external sources, if any, matching
+// the service name and version.
+func NewStore(
+	client elasticsearch.Client,
+	index string,
+	expiration time.Duration,
+	selection VersionSelection,
+	external ...ExternalSource,
+) (*Store, error) {
 	if expiration < 0 {
 		return nil, errInit
 	}
 	logger := logp.NewLogger(logs.Sourcemap)
-	return &Store{
-		cache:   gocache.New(expiration, cleanupInterval(expiration)),
-		esStore: &esStore{client: client, index: index, logger: logger},
-		logger:  logger,
-	}, nil
+	store := &Store{
+		cache:     gocache.New(expiration, cleanupInterval(expiration)),
+		versions:  gocache.New(expiration, cleanupInterval(expiration)),
+		selection: selection,
+		esStore:   &esStore{client: client, index: index, logger: logger},
+		logger:    logger,
+	}
+	if len(external) > 0 {
+		externalStore, err := newExternalStore(external, logger)
+		if err != nil {
+			return nil, err
+		}
+		store.externalStore = externalStore
+	}
+	return store, nil
+}
+
+// SetLookupPool sets the pool in which sourcemaps not found in the cache are
+// fetched from Elasticsearch and external stores. If a lookup is skipped or
+// times out, the stacktrace is left unmapped. Timed out lookups are cancelled
+// and their results are not cached, so the sourcemap is fetched again for
+// later events. Elasticsearch and the external stores each have their own
+// circuit breaker, so that one failing does not skip lookups against the other.
+func (s *Store) SetLookupPool(pool *sidelookup.Pool) {
+	s.pool = pool
+	s.esBreaker = pool.NewBreaker()
+	s.extBreaker = pool.NewBreaker()
 }
 
 // Fetch a sourcemap from the store.
@@ -73,10 +127,25 @@ func (s *Store) Fetch(ctx context.Context, name string, version string, path str
 		return consumer, nil
 	}
 
+	return s.fetch(ctx, key, name, version, path)
+}
+
+func (s *Store) fetch(ctx context.Context, key, name, version, path string) (*sourcemap.Consumer, error) {
 	// fetch from Elasticsearch and ensure caching for all non-temporary results
-	sourcemapStr, err := s.esStore.fetch(ctx, name, version, path)
+	var sourcemapStr string
+	err := s.lookup(ctx, s.esBreaker, func(ctx context.Context) (err error) {
+		sourcemapStr, err = s.esStore.fetch(ctx, name, version, path)
+		return err
+	})
+	if err == nil && sourcemapStr == emptyResult && s.externalStore != nil {
+		// uploaded sourcemaps take precedence over those in external stores
+		err = s.lookup(ctx, s.extBreaker, func(ctx context.Context) (err error) {
+			sourcemapStr, err = s.externalStore.fetch(ctx, name, version, path)
+			return err
+		})
+	}
 	if err != nil {
-		if !strings.Contains(err.Error(), errMsgESFailure) {
+		if !isTemporary(err) {
 			s.add(key, nil)
 		}
 		return nil, err
@@ -96,13 +165,95 @@ func (s *Store) Fetch(ctx context.Context, name string, version string, path str
 	return consumer, nil
 }
 
+// FetchAt fetches a sourcemap from the store for an event with the given timestamp.
+//
+// If the store selects sourcemaps by upload time, the most recent sourcemap uploaded
+// at or before the timestamp is returned, or the earliest uploaded one if the event
+// precedes all uploads. Otherwise, or if timestamp is zero, FetchAt is equivalent to Fetch.
+func (s *Store) FetchAt(ctx context.Context, name, version, path string, timestamp time.Time) (*sourcemap.Consumer, error) {
+	if s.selection != VersionSelectionUploadTime || timestamp.IsZero() {
+		return s.Fetch(ctx, name, version, path)
+	}
+
+	key := key([]string{name, version, path})
+	var versions []sourcemapVersion
+	if val, found := s.versions.Get(key); found {
+		versions, _ = val.([]sourcemapVersion)
+	} else if err := s.lookup(ctx, s.esBreaker, func(ctx context.Context) (err error) {
+		versions, err = s.fetchVersions(ctx, key, name, version, path)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		// no uploaded sourcemaps, fall back to external stores
+		return s.Fetch(ctx, name, version, path)
+	}
+
+	selected := versions[len(versions)-1]
+	for _, v := range versions {
+		if !v.uploaded.After(timestamp) {
+			selected = v
+			break
+		}
+	}
+	return s.fetchID(ctx, selected.id)
+}
+
+func (s *Store) fetchVersions(ctx context.Context, key, name, version, path string) ([]sourcemapVersion, error) {
+	versions, err := s.esStore.fetchVersions(ctx, name, version, path)
+	if err != nil {
+		if !isTemporary(err) {
+			s.versions.SetDefault(key, []sourcemapVersion(nil))
+		}
+		return nil, err
+	}
+	s.versions.SetDefault(key, versions)
+	return versions, nil
+}
+
+func (s *Store) fetchID(ctx context.Context, id string) (*sourcemap.Consumer, error) {
+	key := "id:" + id
+	if val, found := s.cache.Get(key); found {
+		consumer, _ := val.(*sourcemap.Consumer)
+		return consumer, nil
+	}
+	var sourcemapStr string
+	if err := s.lookup(ctx, s.esBreaker, func(ctx context.Context) (err error) {
+		sourcemapStr, err = s.esStore.fetchID(ctx, id)
+		return err
+	}); err != nil {
+		if !isTemporary(err) {
+			s.add(key, nil)
+		}
+		return nil, err
+	}
+	if sourcemapStr == emptyResult {
+		s.add(key, nil)
+		return nil, nil
+	}
+	consumer, err := sourcemap.Parse("", []byte(sourcemapStr))
+	if err != nil {
+		s.add(key, nil)
+		return nil, errors.Wrap(err, errMsgParseSourcemap)
+	}
+	s.add(key, consumer)
+	return consumer, nil
+}
+
 // Added ensures the internal cache is cleared for the given parameters. This should be called when a sourcemap is uploaded.
+// Prior versions of the sourcemap are kept, and remain available for selection by upload time.
+//
+// Only the cache of this store is cleared: other APM Server instances sharing the
+// Elasticsearch index keep using their cached sourcemap, or cached absence of one,
+// until the entry expires after the configured cache expiration.
 func (s *Store) Added(ctx context.Context, name string, version string, path string) {
 	if sourcemap, err := s.Fetch(ctx, name, version, path); err == nil && sourcemap != nil {
-		s.logger.Warnf("Overriding sourcemap for service %s version %s and file %s",
+		s.logger.Infof("Adding new version of sourcemap for service %s version %s and file %s",
 			name, version, path)
 	}
 	key := key([]string{name, version, path})
+	s.versions.Delete(key)
 	s.cache.Delete(key)
 	if !s.logger.IsDebug() {
 		return
@@ -118,6 +269,38 @@ func (s *Store) add(key string, consumer *sourcemap.Consumer) {
 	s.logger.Debugf("Added id %v. Cache now has %v entries.", key, s.cache.ItemCount())
 }
 
+// lookup calls fn, which fetches from Elasticsearch or an external store,
+// in the lookup pool if set, recording its outcome in breaker. Only temporary
+// errors count as lookup failures.
+func (s *Store) lookup(ctx context.Context, breaker *sidelookup.Breaker, fn func(context.Context) error) error {
+	if s.pool == nil {
+		return fn(ctx)
+	}
+	var fnErr error
+	if err := s.pool.DoWithBreaker(ctx, breaker, func(ctx context.Context) error {
+		fnErr = fn(ctx)
+		if fnErr != nil && isTemporary(fnErr) {
+			return fnErr
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return fnErr
+}
+
+// isTemporary reports whether err is a temporary failure to fetch a sourcemap,
+// whose result should not be cached. Skipped and timed out lookups are temporary.
+func isTemporary(err error) bool {
+	switch errors.Cause(err) {
+	case sidelookup.ErrSaturated, sidelookup.ErrCircuitOpen, sidelookup.ErrTimeout,
+		context.Canceled, context.DeadlineExceeded:
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, errMsgESFailure) || strings.Contains(msg, errMsgExternalFailure)
+}
+
 func key(s []string) string {
 	return strings.Join(s, "_")
 }
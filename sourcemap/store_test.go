@@ -20,6 +20,9 @@ package sourcemap
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,15 +32,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/apm-server/elasticsearch"
+	"github.com/elastic/apm-server/elasticsearch/estest"
+	"github.com/elastic/apm-server/sidelookup"
 
 	"github.com/elastic/apm-server/sourcemap/test"
 )
 
 func Test_NewStore(t *testing.T) {
-	_, err := NewStore(nil, "", -1)
+	_, err := NewStore(nil, "", -1, VersionSelectionLatest)
 	require.Error(t, err)
 
-	f, err := NewStore(nil, "", 100)
+	f, err := NewStore(nil, "", 100, VersionSelectionLatest)
 	require.NoError(t, err)
 	assert.NotNil(t, f.cache)
 }
@@ -164,6 +169,88 @@ func TestStore_Added(t *testing.T) {
 	assert.Equal(t, "bundle.js", mapper.File())
 }
 
+func TestStore_FetchAt(t *testing.T) {
+	name, version, path := "foo", "1.0.1", "/tmp"
+	uploaded := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var queries []map[string]interface{}
+	client, err := estest.NewElasticsearchClient(estest.NewTransportFunc(t, func(query map[string]interface{}) (int, map[string]interface{}) {
+		queries = append(queries, query)
+		if ids, ok := query["query"].(map[string]interface{})["ids"]; ok {
+			values := ids.(map[string]interface{})["values"].([]interface{})
+			if values[0] != "v1" && values[0] != "v2" {
+				return http.StatusOK, map[string]interface{}{"hits": map[string]interface{}{"total": map[string]interface{}{"value": 0}}}
+			}
+			return http.StatusOK, map[string]interface{}{
+				"hits": map[string]interface{}{
+					"total": map[string]interface{}{"value": 1},
+					"hits": []map[string]interface{}{{
+						"_source": map[string]interface{}{
+							"sourcemap": map[string]interface{}{
+								"sourcemap": strings.Replace(test.ValidSourcemap, `"file": "bundle.js"`, `"file": "`+values[0].(string)+`.js"`, 1),
+							},
+						},
+					}},
+				},
+			}
+		}
+		return http.StatusOK, map[string]interface{}{
+			"hits": map[string]interface{}{
+				"hits": []map[string]interface{}{
+					{"_id": "v2", "_source": map[string]interface{}{"@timestamp": uploaded.Add(time.Hour)}, "sort": []interface{}{2.0, 2}},
+					{"_id": "v1", "_source": map[string]interface{}{"@timestamp": uploaded}, "sort": []interface{}{2.0, 1}},
+					// v0 matches only the bundle file path, not the full URL
+					{"_id": "v0", "_source": map[string]interface{}{"@timestamp": uploaded.Add(-time.Hour)}, "sort": []interface{}{1.0, 0}},
+				},
+			},
+		}
+	}))
+	require.NoError(t, err)
+
+	store, err := NewStore(client, "apm-*sourcemap*", time.Minute, VersionSelectionUploadTime)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		timestamp time.Time
+		file      string
+	}{
+		{timestamp: uploaded.Add(-time.Minute), file: "v1.js"},
+		{timestamp: uploaded, file: "v1.js"},
+		{timestamp: uploaded.Add(time.Minute), file: "v1.js"},
+		{timestamp: uploaded.Add(2 * time.Hour), file: "v2.js"},
+	} {
+		mapper, err := store.FetchAt(context.Background(), name, version, path, tc.timestamp)
+		require.NoError(t, err)
+		require.NotNil(t, mapper)
+		assert.Equal(t, tc.file, mapper.File(), tc.timestamp)
+	}
+	// versions and sourcemaps are cached
+	assert.Len(t, queries, 3)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"_score": map[string]interface{}{"order": "desc"}},
+		map[string]interface{}{"@timestamp": map[string]interface{}{"order": "desc"}},
+	}, queries[0]["sort"])
+
+	// uploading a new version invalidates the cached versions
+	queries = nil
+	store.Added(context.Background(), name, version, path)
+	_, err = store.FetchAt(context.Background(), name, version, path, uploaded)
+	require.NoError(t, err)
+	assert.Len(t, queries, 2) // Added's latest lookup, and versions
+}
+
+func TestStore_FetchAtLatest(t *testing.T) {
+	store := testStore(t, test.ESClientWithValidSourcemap(t))
+	mapper, err := store.FetchAt(context.Background(), "foo", "1.0.1", "/tmp", time.Now())
+	require.NoError(t, err)
+	require.NotNil(t, mapper)
+
+	// selecting the latest version shares the cache with Fetch
+	cached, found := store.cache.Get("foo_1.0.1_/tmp")
+	require.True(t, found)
+	assert.Equal(t, mapper, cached)
+}
+
 func TestExpiration(t *testing.T) {
 	store := testStore(t, test.ESClientUnavailable(t)) //if ES was queried it would return an error
 	store.cache = gocache.New(25*time.Millisecond, 100)
@@ -201,8 +288,51 @@ func TestCleanupInterval(t *testing.T) {
 	}
 }
 
+func TestStore_FetchLookupPool(t *testing.T) {
+	serviceName, serviceVersion, path := "foo", "1.0.1", "/tmp"
+	key := "foo_1.0.1_/tmp"
+
+	client := &blockingClient{Client: test.ESClientWithValidSourcemap(t), release: make(chan struct{})}
+	store := testStore(t, client)
+	pool, err := sidelookup.NewPool(sidelookup.Config{Workers: 1, Timeout: 10 * time.Millisecond})
+	require.NoError(t, err)
+	store.SetLookupPool(pool)
+
+	// The lookup times out and is cancelled, and the result is not cached.
+	mapper, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+	assert.Equal(t, sidelookup.ErrTimeout, err)
+	assert.Nil(t, mapper)
+	_, found := store.cache.Get(key)
+	assert.False(t, found)
+
+	// The sourcemap is fetched again once Elasticsearch responds.
+	close(client.release)
+	assert.Eventually(t, func() bool {
+		mapper, err := store.Fetch(context.Background(), serviceName, serviceVersion, path)
+		return err == nil && mapper != nil
+	}, 10*time.Second, 10*time.Millisecond)
+	_, found = store.cache.Get(key)
+	assert.True(t, found)
+}
+
+// blockingClient is an elasticsearch.Client whose searches block until
+// release is closed or the context is cancelled.
+type blockingClient struct {
+	elasticsearch.Client
+	release chan struct{}
+}
+
+func (c *blockingClient) SearchQuery(ctx context.Context, index string, body io.Reader) (int, io.ReadCloser, error) {
+	select {
+	case <-c.release:
+	case <-ctx.Done():
+		return 0, nil, ctx.Err()
+	}
+	return c.Client.SearchQuery(ctx, index, body)
+}
+
 func testStore(t *testing.T, client elasticsearch.Client) *Store {
-	store, err := NewStore(client, "apm-*sourcemap*", time.Minute)
+	store, err := NewStore(client, "apm-*sourcemap*", time.Minute, VersionSelectionLatest)
 	require.NoError(t, err)
 	return store
 }
@@ -128,12 +128,12 @@ class CorsTest(CorsBaseTest):
                              headers={'Origin': 'http://www.elastic.co',
                                       'Access-Control-Request-Method': 'POST',
                                       'Access-Control-Request-Headers': 'Content-Type, Content-Encoding'})
-        assert r.status_code == 200, r.status_code
+        assert r.status_code == 204, r.status_code
         assert r.headers['Access-Control-Allow-Origin'] == 'http://www.elastic.co', r.headers
         assert r.headers['Access-Control-Allow-Headers'] == 'Content-Type, Content-Encoding, Accept', r.headers
         assert r.headers['Access-Control-Allow-Methods'] == 'POST, OPTIONS', r.headers
         assert r.headers['Vary'] == 'Origin', r.headers
-        assert r.headers['Content-Length'] == '0', r.headers
+        assert 'Content-Length' not in r.headers.keys(), r.headers
         assert r.headers['Access-Control-Max-Age'] == '3600', r.headers
 
     def test_preflight_bad_headers(self):
@@ -141,7 +141,7 @@ class CorsTest(CorsBaseTest):
             r = requests.options(self.intake_url,
                                  json=self.get_event_payload(),
                                  headers=h)
-            assert r.status_code == 200, r.status_code
+            assert r.status_code == 204, r.status_code
             assert 'Access-Control-Allow-Origin' not in r.headers.keys(), r.headers
             assert r.headers['Access-Control-Allow-Headers'] == 'Content-Type, Content-Encoding, Accept', r.headers
             assert r.headers['Access-Control-Allow-Methods'] == 'POST, OPTIONS', r.headers
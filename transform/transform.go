@@ -20,6 +20,7 @@ package transform
 import (
 	"context"
 	"regexp"
+	"time"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
 
@@ -38,9 +39,34 @@ type Config struct {
 	// If true, then data_stream fields should be added to all events.
 	DataStreams bool
 
+	// Expiry maps event types, as recorded in processor.event, to the
+	// time-to-live of their documents. Documents of event types with a
+	// time-to-live are stamped with an expiry timestamp.
+	Expiry map[string]time.Duration
+
+	// KeywordTruncation holds configuration for truncating the values of
+	// keyword fields which would otherwise not be indexed.
+	KeywordTruncation KeywordTruncationConfig
+
+	// DedupStacktraces records whether stacktraces repeated within an
+	// error's exception chain should be replaced by a reference to the
+	// first exception with the identical stacktrace.
+	DedupStacktraces bool
+
 	RUM RUMConfig
 }
 
+// KeywordTruncationConfig holds configuration for truncating keyword fields.
+type KeywordTruncationConfig struct {
+	// MaxLength holds the maximum number of characters of keyword field
+	// values. If MaxLength is zero, keyword fields are not truncated.
+	MaxLength int
+
+	// Fields holds the names of the keyword fields to truncate. For each
+	// truncated field "<name>", the field "<name>_truncated" is set to true.
+	Fields []string
+}
+
 // RUMConfig holds RUM-related transformation configuration.
 type RUMConfig struct {
 	LibraryPattern      *regexp.Regexp
@@ -29,6 +29,14 @@ func MillisAsMicros(ms float64) common.MapStr {
 	return m
 }
 
+// DurationAsMicros returns d as whole microseconds, for compatibility
+// with fields recorded in microseconds.
+func DurationAsMicros(d time.Duration) common.MapStr {
+	m := common.MapStr{}
+	m["us"] = int(d / time.Microsecond)
+	return m
+}
+
 func TimeAsMicros(t time.Time) common.MapStr {
 	if t.IsZero() {
 		return nil
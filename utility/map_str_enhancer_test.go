@@ -19,12 +19,18 @@ package utility
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/elastic/beats/v7/libbeat/common"
 )
 
+func TestDurationAsMicros(t *testing.T) {
+	m := DurationAsMicros(4500*time.Microsecond + 999*time.Nanosecond)
+	assert.Equal(t, common.MapStr{"us": 4500}, m)
+}
+
 func TestMillisAsMicros(t *testing.T) {
 	ms := 4.5
 	m := MillisAsMicros(ms)
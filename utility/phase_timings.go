@@ -0,0 +1,122 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package utility
+
+import (
+	"context"
+	"time"
+)
+
+// Phase identifies a phase of processing an intake request.
+type Phase int
+
+const (
+	// PhaseAuth is the phase of authorizing a request.
+	PhaseAuth Phase = iota
+
+	// PhaseRateLimit is the phase of obtaining a rate limiter for
+	// a request, and waiting for it to admit events.
+	PhaseRateLimit
+
+	// PhaseDecompress is the phase of reading and decompressing
+	// a compressed request body.
+	PhaseDecompress
+
+	// PhaseDecode is the phase of decoding events from a request body,
+	// excluding the time spent in the other phases while decoding.
+	PhaseDecode
+
+	// PhaseValidate is the phase of validating decoded events.
+	PhaseValidate
+
+	// PhasePublish is the phase of processing and publishing events.
+	PhasePublish
+
+	// NumPhases holds the number of phases.
+	NumPhases
+)
+
+var phaseNames = [NumPhases]string{
+	PhaseAuth:       "auth",
+	PhaseRateLimit:  "rate_limit",
+	PhaseDecompress: "decompress",
+	PhaseDecode:     "decode",
+	PhaseValidate:   "validate",
+	PhasePublish:    "publish",
+}
+
+// String returns the name of the phase.
+func (p Phase) String() string {
+	return phaseNames[p]
+}
+
+// PhaseTimings records the time spent in each phase of processing a request.
+//
+// Methods may be called on a nil *PhaseTimings, in which case no timings are
+// recorded. PhaseTimings is not safe for concurrent use.
+type PhaseTimings struct {
+	durations [NumPhases]time.Duration
+}
+
+// Add adds d to the time spent in phase p.
+func (t *PhaseTimings) Add(p Phase, d time.Duration) {
+	if t != nil {
+		t.durations[p] += d
+	}
+}
+
+// Duration returns the time spent in phase p.
+func (t *PhaseTimings) Duration(p Phase) time.Duration {
+	if t == nil {
+		return 0
+	}
+	return t.durations[p]
+}
+
+// Total returns the time spent in all phases.
+func (t *PhaseTimings) Total() time.Duration {
+	if t == nil {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range t.durations {
+		total += d
+	}
+	return total
+}
+
+// Reset sets the time spent in all phases to zero.
+func (t *PhaseTimings) Reset() {
+	if t != nil {
+		t.durations = [NumPhases]time.Duration{}
+	}
+}
+
+const phaseTimingsContextKey = contextKey("phaseTimings")
+
+// ContextWithPhaseTimings returns a copy of ctx holding t.
+func ContextWithPhaseTimings(ctx context.Context, t *PhaseTimings) context.Context {
+	return context.WithValue(ctx, phaseTimingsContextKey, t)
+}
+
+// PhaseTimingsFromContext returns the PhaseTimings held in ctx, or nil
+// if ctx holds none.
+func PhaseTimingsFromContext(ctx context.Context) *PhaseTimings {
+	t, _ := ctx.Value(phaseTimingsContextKey).(*PhaseTimings)
+	return t
+}
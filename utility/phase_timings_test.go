@@ -0,0 +1,64 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package utility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhaseTimings(t *testing.T) {
+	var timings PhaseTimings
+	timings.Add(PhaseAuth, time.Millisecond)
+	timings.Add(PhaseDecode, 2*time.Millisecond)
+	timings.Add(PhaseDecode, 3*time.Millisecond)
+	assert.Equal(t, time.Millisecond, timings.Duration(PhaseAuth))
+	assert.Equal(t, 5*time.Millisecond, timings.Duration(PhaseDecode))
+	assert.Zero(t, timings.Duration(PhasePublish))
+	assert.Equal(t, 6*time.Millisecond, timings.Total())
+
+	timings.Reset()
+	assert.Zero(t, timings.Total())
+}
+
+func TestPhaseTimingsNil(t *testing.T) {
+	var timings *PhaseTimings
+	timings.Add(PhaseAuth, time.Millisecond)
+	timings.Reset()
+	assert.Zero(t, timings.Duration(PhaseAuth))
+	assert.Zero(t, timings.Total())
+}
+
+func TestPhaseTimingsContext(t *testing.T) {
+	assert.Nil(t, PhaseTimingsFromContext(context.Background()))
+
+	timings := &PhaseTimings{}
+	ctx := ContextWithPhaseTimings(context.Background(), timings)
+	assert.Equal(t, timings, PhaseTimingsFromContext(ctx))
+}
+
+func TestPhaseString(t *testing.T) {
+	names := make([]string, NumPhases)
+	for p := Phase(0); p < NumPhases; p++ {
+		names[p] = p.String()
+	}
+	assert.Equal(t, []string{"auth", "rate_limit", "decompress", "decode", "validate", "publish"}, names)
+}
@@ -0,0 +1,657 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package breakdownmetrics
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/apm-server/featureflag"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/beats/v7/libbeat/logp"
+)
+
+const (
+	spanBreakdownMetricsetName        = "span_breakdown"
+	transactionBreakdownMetricsetName = "transaction_breakdown"
+
+	// appSpanType is the span type with which the self-time of
+	// transactions is recorded, as is done by agents.
+	appSpanType = "app"
+)
+
+// defaultAgents holds the agent name prefixes of agents which do not
+// send breakdown metrics themselves.
+var defaultAgents = []string{"opentelemetry", "otlp", "Jaeger"}
+
+// AggregatorConfig holds configuration for creating an Aggregator.
+type AggregatorConfig struct {
+	// BatchProcessor is a model.BatchProcessor for asynchronously
+	// processing metrics documents.
+	BatchProcessor model.BatchProcessor
+
+	// MaxGroups is the maximum number of distinct breakdown groups
+	// to store within an aggregation period. Once this number of groups
+	// is reached, any new aggregation keys will cause individual metrics
+	// documents to be immediately published.
+	MaxGroups int
+
+	// MaxPendingSpans is the maximum number of spans buffered until
+	// their transaction is received, and of transactions buffered while
+	// waiting for spans received after them. Once this number of spans
+	// is reached, further spans are not accounted for in breakdown
+	// metrics; once this number of transactions is reached, further
+	// transactions are accounted for without waiting for later spans.
+	MaxPendingSpans int
+
+	// Interval is the interval between publishing of aggregated metrics.
+	// There may be additional metrics reported at arbitrary times if the
+	// aggregation groups fill up.
+	//
+	// Spans are buffered for up to two intervals, waiting for their
+	// transaction to be received. Transactions are buffered until the
+	// end of the interval following the one in which they are received,
+	// waiting for spans received after them, e.g. because they ended
+	// after the transaction.
+	Interval time.Duration
+
+	// Agents holds the agent name prefixes of agents for which
+	// breakdown metrics are computed. If Agents is empty, breakdown
+	// metrics are computed for OpenTelemetry and Jaeger agents.
+	Agents []string
+
+	// Logger is the logger for logging metrics aggregation/publishing.
+	//
+	// If Logger is nil, a new logger will be constructed.
+	Logger *logp.Logger
+}
+
+// Validate validates the aggregator config.
+func (config AggregatorConfig) Validate() error {
+	if config.BatchProcessor == nil {
+		return errors.New("BatchProcessor unspecified")
+	}
+	if config.MaxGroups <= 0 {
+		return errors.New("MaxGroups unspecified or negative")
+	}
+	if config.MaxPendingSpans <= 0 {
+		return errors.New("MaxPendingSpans unspecified or negative")
+	}
+	if config.Interval <= 0 {
+		return errors.New("Interval unspecified or negative")
+	}
+	return nil
+}
+
+// Aggregator computes the self-time of spans per transaction group and
+// span type from received transactions and spans, periodically publishing
+// breakdown metrics, for agents which do not send them.
+type Aggregator struct {
+	stopMu   sync.Mutex
+	stopping chan struct{}
+	stopped  chan struct{}
+
+	config AggregatorConfig
+
+	mu               sync.RWMutex
+	active, inactive *metricsBuffer
+
+	// pending holds the spans received in the current interval, and
+	// previous the spans received in the previous interval, keyed by
+	// trace ID. Spans whose transaction is not received by the end of
+	// the following interval are discarded.
+	//
+	// transactions holds the transactions received in the current
+	// interval, and previousTransactions those received in the previous
+	// interval, along with the spans received before them, whose number
+	// is numHeld.
+	pendingMu                          sync.Mutex
+	pending, previous                  map[string][]pendingSpan
+	numPending, numPrevious, numHeld   int
+	transactions, previousTransactions []pendingTransaction
+}
+
+// NewAggregator returns a new Aggregator with the given config.
+func NewAggregator(config AggregatorConfig) (*Aggregator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid aggregator config")
+	}
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger(logs.BreakdownMetrics)
+	}
+	if len(config.Agents) == 0 {
+		config.Agents = defaultAgents
+	}
+	return &Aggregator{
+		stopping: make(chan struct{}),
+		stopped:  make(chan struct{}),
+		config:   config,
+		active:   newMetricsBuffer(config.MaxGroups),
+		inactive: newMetricsBuffer(config.MaxGroups),
+		pending:  make(map[string][]pendingSpan),
+		previous: make(map[string][]pendingSpan),
+	}, nil
+}
+
+// Run runs the Aggregator, periodically publishing and clearing aggregated
+// metrics. Run returns when either a fatal error occurs, or the Aggregator's
+// Stop method is invoked.
+func (a *Aggregator) Run() error {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	defer func() {
+		a.stopMu.Lock()
+		defer a.stopMu.Unlock()
+		select {
+		case <-a.stopped:
+		default:
+			close(a.stopped)
+		}
+	}()
+	var stop bool
+	for !stop {
+		select {
+		case <-a.stopping:
+			stop = true
+		case <-ticker.C:
+		}
+		if err := a.publish(context.Background(), stop); err != nil {
+			a.config.Logger.With(logp.Error(err)).Warnf(
+				"publishing breakdown metrics failed: %s", err,
+			)
+		}
+	}
+	return nil
+}
+
+// Stop stops the Aggregator if it is running, waiting for it to flush any
+// aggregated metrics and return, or for the context to be cancelled.
+//
+// After Stop has been called the aggregator cannot be reused, as the Run
+// method will always return immediately.
+func (a *Aggregator) Stop(ctx context.Context) error {
+	a.stopMu.Lock()
+	select {
+	case <-a.stopped:
+	case <-a.stopping:
+		// Already stopping/stopped.
+	default:
+		close(a.stopping)
+	}
+	a.stopMu.Unlock()
+
+	select {
+	case <-a.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// publish computes breakdown metrics for the transactions received in the
+// previous interval, or all buffered transactions if final is true, and
+// publishes the aggregated metrics.
+func (a *Aggregator) publish(ctx context.Context, final bool) error {
+	a.pendingMu.Lock()
+	transactions := a.previousTransactions
+	a.previousTransactions, a.transactions = a.transactions, nil
+	if final {
+		transactions = append(transactions, a.previousTransactions...)
+		a.previousTransactions = nil
+	}
+	a.pendingMu.Unlock()
+
+	// Transactions are processed before discarding spans received
+	// in the previous interval, which may belong to them.
+	var metricsets []*model.Metricset
+	a.mu.RLock()
+	for _, tx := range transactions {
+		metricsets = append(metricsets, a.processTransaction(tx)...)
+	}
+	a.mu.RUnlock()
+
+	a.pendingMu.Lock()
+	a.previous, a.numPrevious = a.pending, a.numPending
+	a.pending, a.numPending = make(map[string][]pendingSpan), 0
+	a.pendingMu.Unlock()
+
+	// We hold a.mu only long enough to swap the buffers. After the
+	// lock is released nothing will be accessing a.inactive.
+	a.mu.Lock()
+	a.active, a.inactive = a.inactive, a.active
+	a.mu.Unlock()
+
+	size := len(a.inactive.m)
+	if size == 0 && len(metricsets) == 0 {
+		a.config.Logger.Debugf("no breakdown metrics to publish")
+		return nil
+	}
+
+	now := time.Now()
+	for key, metrics := range a.inactive.m {
+		metricset := makeMetricset(now, key, metrics, a.config.Interval.Milliseconds())
+		metricsets = append(metricsets, &metricset)
+		delete(a.inactive.m, key)
+	}
+	a.config.Logger.Debugf("publishing %d metricsets", len(metricsets))
+	return a.config.BatchProcessor.ProcessBatch(ctx, &model.Batch{Metricsets: metricsets})
+}
+
+// ProcessBatch buffers the spans contained in "b" until their transaction
+// is received, and buffers the transactions contained in "b" until spans
+// received after them have had time to arrive. If too many transactions
+// are buffered, breakdown metrics are computed for the transactions
+// immediately, adding to "b" any metricsets requiring immediate publication.
+//
+// Spans are matched to their transactions by trace ID, and by their
+// transaction ID if set, or by following their parent IDs otherwise.
+//
+// This method is expected to be used immediately prior to publishing
+// the events. Events are not aggregated while the BreakdownMetrics
+// feature flag is disabled.
+func (a *Aggregator) ProcessBatch(ctx context.Context, b *model.Batch) error {
+	if !featureflag.Enabled(featureflag.BreakdownMetrics) {
+		return nil
+	}
+	for _, span := range b.Spans {
+		if span.TraceID == "" || span.RepresentativeCount <= 0 {
+			continue
+		}
+		if a.matchAgent(span.Metadata.Service.Agent.Name) {
+			a.addPendingSpan(span)
+		}
+	}
+
+	for _, tx := range b.Transactions {
+		if tx.RepresentativeCount <= 0 || !a.matchAgent(tx.Metadata.Service.Agent.Name) {
+			continue
+		}
+		pending := pendingTransaction{
+			key: aggregationKey{
+				serviceName:        tx.Metadata.Service.Name,
+				serviceEnvironment: tx.Metadata.Service.Environment,
+				agentName:          tx.Metadata.Service.Agent.Name,
+				transactionName:    tx.Name,
+				transactionType:    tx.Type,
+			},
+			id:       tx.ID,
+			traceID:  tx.TraceID,
+			interval: interval{start: tx.Timestamp, end: tx.Timestamp.Add(tx.Duration)},
+			count:    tx.RepresentativeCount,
+		}
+		if a.addPendingTransaction(&pending) {
+			continue
+		}
+		a.mu.RLock()
+		b.Metricsets = append(b.Metricsets, a.processTransaction(pending)...)
+		a.mu.RUnlock()
+	}
+	return nil
+}
+
+func (a *Aggregator) matchAgent(agentName string) bool {
+	for _, prefix := range a.config.Agents {
+		if strings.HasPrefix(agentName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Aggregator) addPendingSpan(span *model.Span) {
+	a.pendingMu.Lock()
+	defer a.pendingMu.Unlock()
+	if a.numPending+a.numPrevious+a.numHeld >= a.config.MaxPendingSpans {
+		return
+	}
+	pending := pendingSpan{
+		id:            span.ID,
+		parentID:      span.ParentID,
+		transactionID: span.TransactionID,
+		timestamp:     span.Timestamp,
+		duration:      span.Duration,
+		spanType:      span.Type,
+		subtype:       span.Subtype,
+	}
+	if span.Start != nil {
+		pending.offset = time.Duration(*span.Start * float64(time.Millisecond))
+	}
+	a.pending[span.TraceID] = append(a.pending[span.TraceID], pending)
+	a.numPending++
+}
+
+// addPendingTransaction takes the spans buffered for tx, and buffers tx
+// until the end of the following interval. addPendingTransaction returns
+// false if too many transactions are buffered, in which case tx must be
+// processed immediately.
+func (a *Aggregator) addPendingTransaction(tx *pendingTransaction) bool {
+	a.pendingMu.Lock()
+	defer a.pendingMu.Unlock()
+	tx.spans = a.takePendingSpansLocked(tx.traceID, tx.id)
+	if len(a.transactions)+len(a.previousTransactions) >= a.config.MaxPendingSpans {
+		return false
+	}
+	a.transactions = append(a.transactions, *tx)
+	a.numHeld += len(tx.spans)
+	return true
+}
+
+// takePendingSpans removes and returns the spans buffered for the
+// transaction with the given trace ID and ID.
+func (a *Aggregator) takePendingSpans(traceID, transactionID string) []pendingSpan {
+	a.pendingMu.Lock()
+	defer a.pendingMu.Unlock()
+	return a.takePendingSpansLocked(traceID, transactionID)
+}
+
+func (a *Aggregator) takePendingSpansLocked(traceID, transactionID string) []pendingSpan {
+	previous, current := a.previous[traceID], a.pending[traceID]
+	if len(previous) == 0 && len(current) == 0 {
+		return nil
+	}
+	trace := make([]pendingSpan, 0, len(previous)+len(current))
+	trace = append(trace, previous...)
+	trace = append(trace, current...)
+	belongs := belongsToTransaction(trace, transactionID)
+
+	var spans []pendingSpan
+	take := func(m map[string][]pendingSpan, in []pendingSpan, belongs []bool) int {
+		var keep []pendingSpan
+		for i, span := range in {
+			if belongs[i] {
+				spans = append(spans, span)
+			} else {
+				keep = append(keep, span)
+			}
+		}
+		if len(keep) == 0 {
+			delete(m, traceID)
+		} else {
+			m[traceID] = keep
+		}
+		return len(in) - len(keep)
+	}
+	if len(previous) > 0 {
+		a.numPrevious -= take(a.previous, previous, belongs[:len(previous)])
+	}
+	if len(current) > 0 {
+		a.numPending -= take(a.pending, current, belongs[len(previous):])
+	}
+	return spans
+}
+
+// belongsToTransaction reports, for each of the spans of a trace, whether
+// it belongs to the transaction with the given ID. Spans with a transaction
+// ID belong to that transaction; other spans belong to the transaction of
+// their parent, found by following parent IDs.
+func belongsToTransaction(spans []pendingSpan, transactionID string) []bool {
+	byID := make(map[string]*pendingSpan, len(spans))
+	for i := range spans {
+		byID[spans[i].id] = &spans[i]
+	}
+	belongs := make([]bool, len(spans))
+	for i, span := range spans {
+		// Bound the walk, in case of cycles in the parent IDs.
+		for n := 0; n <= len(spans); n++ {
+			if span.transactionID != "" {
+				belongs[i] = span.transactionID == transactionID
+				break
+			}
+			if span.parentID == transactionID {
+				belongs[i] = true
+				break
+			}
+			parent, ok := byID[span.parentID]
+			if !ok {
+				break
+			}
+			span = *parent
+		}
+	}
+	return belongs
+}
+
+func (a *Aggregator) processTransaction(tx pendingTransaction) []*model.Metricset {
+	spans := append(tx.spans, a.takePendingSpans(tx.traceID, tx.id)...)
+	if len(tx.spans) > 0 {
+		a.pendingMu.Lock()
+		a.numHeld -= len(tx.spans)
+		a.pendingMu.Unlock()
+	}
+	key := tx.key
+	var metricsets []*model.Metricset
+	store := func(key aggregationKey, metrics breakdownMetrics) {
+		if !a.active.storeOrUpdate(key, metrics) {
+			metricset := makeMetricset(time.Now(), key, metrics, 0)
+			metricsets = append(metricsets, &metricset)
+		}
+	}
+	store(key, breakdownMetrics{count: tx.count})
+	for spanType, selfTime := range selfTimes(tx, spans) {
+		key := key
+		key.spanType = spanType.spanType
+		key.spanSubtype = spanType.subtype
+		store(key, breakdownMetrics{
+			count: selfTime.count * tx.count,
+			sum:   float64(selfTime.sum.Microseconds()) * tx.count,
+		})
+	}
+	return metricsets
+}
+
+type pendingTransaction struct {
+	key     aggregationKey
+	id      string
+	traceID string
+	interval
+	count float64
+
+	// spans holds the spans received before the transaction.
+	spans []pendingSpan
+}
+
+type pendingSpan struct {
+	id            string
+	parentID      string
+	transactionID string
+	timestamp     time.Time
+	offset        time.Duration
+	duration      time.Duration
+	spanType      string
+	subtype       string
+}
+
+type spanType struct {
+	spanType string
+	subtype  string
+}
+
+type selfTime struct {
+	count float64
+	sum   time.Duration
+}
+
+type interval struct {
+	start, end time.Time
+}
+
+// selfTimes returns the sum of the self-time of the transaction and its
+// spans, per span type. The self-time of a span is its duration, less
+// the time during which any of its direct children were active.
+func selfTimes(tx pendingTransaction, spans []pendingSpan) map[spanType]selfTime {
+	type node struct {
+		id string
+		interval
+		spanType
+	}
+	nodes := make([]node, 0, len(spans)+1)
+	nodes = append(nodes, node{
+		id:       tx.id,
+		interval: tx.interval,
+		spanType: spanType{spanType: appSpanType},
+	})
+	children := make(map[string][]interval)
+	for _, span := range spans {
+		start := span.timestamp
+		if start.IsZero() {
+			start = tx.start.Add(span.offset)
+		}
+		n := node{
+			id:       span.id,
+			interval: interval{start: start, end: start.Add(span.duration)},
+			spanType: spanType{spanType: span.spanType, subtype: span.subtype},
+		}
+		if n.spanType.spanType == "" {
+			// An empty span type identifies transaction breakdown groups.
+			n.spanType.spanType = "unknown"
+		}
+		nodes = append(nodes, n)
+		children[span.parentID] = append(children[span.parentID], n.interval)
+	}
+
+	out := make(map[spanType]selfTime)
+	for _, n := range nodes {
+		d := n.end.Sub(n.start) - covered(n.interval, children[n.id])
+		if d < 0 {
+			d = 0
+		}
+		t := out[n.spanType]
+		t.count++
+		t.sum += d
+		out[n.spanType] = t
+	}
+	return out
+}
+
+// covered returns the total duration within parent covered by any of
+// the given intervals.
+func covered(parent interval, intervals []interval) time.Duration {
+	if len(intervals) == 0 {
+		return 0
+	}
+	clipped := make([]interval, 0, len(intervals))
+	for _, i := range intervals {
+		if i.start.Before(parent.start) {
+			i.start = parent.start
+		}
+		if i.end.After(parent.end) {
+			i.end = parent.end
+		}
+		if i.end.After(i.start) {
+			clipped = append(clipped, i)
+		}
+	}
+	sort.Slice(clipped, func(i, j int) bool {
+		return clipped[i].start.Before(clipped[j].start)
+	})
+	var total time.Duration
+	var current interval
+	for i, next := range clipped {
+		if i > 0 && !next.start.After(current.end) {
+			if next.end.After(current.end) {
+				current.end = next.end
+			}
+			continue
+		}
+		total += current.end.Sub(current.start)
+		current = next
+	}
+	return total + current.end.Sub(current.start)
+}
+
+type metricsBuffer struct {
+	maxSize int
+
+	mu sync.Mutex
+	m  map[aggregationKey]breakdownMetrics
+}
+
+func newMetricsBuffer(maxSize int) *metricsBuffer {
+	return &metricsBuffer{
+		maxSize: maxSize,
+		m:       make(map[aggregationKey]breakdownMetrics),
+	}
+}
+
+func (mb *metricsBuffer) storeOrUpdate(key aggregationKey, value breakdownMetrics) bool {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	old, ok := mb.m[key]
+	if !ok && len(mb.m) == mb.maxSize {
+		return false
+	}
+	mb.m[key] = breakdownMetrics{count: value.count + old.count, sum: value.sum + old.sum}
+	return true
+}
+
+// aggregationKey identifies a breakdown group. Transaction breakdown
+// groups have an empty span type.
+type aggregationKey struct {
+	serviceName        string
+	serviceEnvironment string
+	agentName          string
+	transactionName    string
+	transactionType    string
+	spanType           string
+	spanSubtype        string
+}
+
+type breakdownMetrics struct {
+	count float64
+	sum   float64
+}
+
+func makeMetricset(timestamp time.Time, key aggregationKey, metrics breakdownMetrics, interval int64) model.Metricset {
+	out := model.Metricset{
+		Timestamp: timestamp,
+		Metadata: model.Metadata{
+			Service: model.Service{
+				Name:        key.serviceName,
+				Environment: key.serviceEnvironment,
+				Agent:       model.Agent{Name: key.agentName},
+			},
+		},
+		Transaction: model.MetricsetTransaction{
+			Name: key.transactionName,
+			Type: key.transactionType,
+		},
+	}
+	if key.spanType == "" {
+		out.Name = transactionBreakdownMetricsetName
+		out.Samples = []model.Sample{{
+			Name:  "transaction.breakdown.count",
+			Value: math.Round(metrics.count),
+		}}
+	} else {
+		out.Name = spanBreakdownMetricsetName
+		out.Span = model.MetricsetSpan{Type: key.spanType, Subtype: key.spanSubtype}
+		out.Samples = []model.Sample{{
+			Name:  "span.self_time.count",
+			Value: math.Round(metrics.count),
+		}, {
+			Name:  "span.self_time.sum.us",
+			Value: math.Round(metrics.sum),
+		}}
+	}
+	if interval > 0 {
+		// Only set metricset.period for a positive interval.
+		//
+		// An interval of zero means the metricset is computed
+		// from an instantaneous value, meaning there is no
+		// aggregation period.
+		out.Samples = append(out.Samples, model.Sample{
+			Name:  "metricset.period",
+			Value: float64(interval),
+		})
+	}
+	return out
+}
@@ -0,0 +1,355 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package breakdownmetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/processor/otel"
+)
+
+func TestNewAggregatorConfigInvalid(t *testing.T) {
+	report := makeErrBatchProcessor(nil)
+
+	type test struct {
+		config AggregatorConfig
+		err    string
+	}
+
+	for _, test := range []test{{
+		config: AggregatorConfig{},
+		err:    "BatchProcessor unspecified",
+	}, {
+		config: AggregatorConfig{
+			BatchProcessor: report,
+		},
+		err: "MaxGroups unspecified or negative",
+	}, {
+		config: AggregatorConfig{
+			BatchProcessor: report,
+			MaxGroups:      1,
+		},
+		err: "MaxPendingSpans unspecified or negative",
+	}, {
+		config: AggregatorConfig{
+			BatchProcessor:  report,
+			MaxGroups:       1,
+			MaxPendingSpans: 1,
+		},
+		err: "Interval unspecified or negative",
+	}} {
+		agg, err := NewAggregator(test.config)
+		require.Error(t, err)
+		require.Nil(t, agg)
+		assert.EqualError(t, err, "invalid aggregator config: "+test.err)
+	}
+}
+
+func TestAggregatorRun(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor:  makeChanBatchProcessor(batches),
+		Interval:        10 * time.Millisecond,
+		MaxGroups:       1000,
+		MaxPendingSpans: 1000,
+	})
+	require.NoError(t, err)
+
+	// The transaction lasts 100ms, with two overlapping child spans
+	// (10-30ms and 20-60ms), the latter having a child span (40-50ms).
+	ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+	start := time.Unix(1600000000, 0)
+	tx := makeTransaction("opentelemetry/go", "tx1", start, ms(100))
+	spans := []*model.Span{
+		makeSpan("opentelemetry/go", "span1", "tx1", "tx1", start.Add(ms(10)), ms(20), "db", "postgresql"),
+		makeSpan("opentelemetry/go", "span2", "tx1", "tx1", start.Add(ms(20)), ms(40), "external", "http"),
+		makeSpan("opentelemetry/go", "span3", "span2", "tx1", time.Time{}, ms(10), "db", "postgresql"),
+	}
+	offset := float64(40)
+	spans[2].Start = &offset
+
+	// Spans of other agents are ignored.
+	ignored := makeTransaction("go", "tx2", start, ms(100))
+	batch := model.Batch{
+		Spans: append(spans, makeSpan("go", "span4", "tx2", "tx2", start, ms(10), "db", "mysql")),
+	}
+	require.NoError(t, agg.ProcessBatch(context.Background(), &batch))
+	batch = model.Batch{Transactions: []*model.Transaction{tx, ignored}}
+	require.NoError(t, agg.ProcessBatch(context.Background(), &batch))
+	assert.Empty(t, batch.Metricsets)
+
+	go agg.Run()
+	defer agg.Stop(context.Background())
+
+	out := expectBatch(t, batches)
+	for _, ms := range out.Metricsets {
+		require.NotZero(t, ms.Timestamp)
+		ms.Timestamp = time.Time{}
+	}
+	metadata := model.Metadata{Service: model.Service{Name: "service", Agent: model.Agent{Name: "opentelemetry/go"}}}
+	transaction := model.MetricsetTransaction{Name: "GET /", Type: "request"}
+	spanBreakdown := func(spanType, subtype string, count, sum float64) *model.Metricset {
+		return &model.Metricset{
+			Name:        "span_breakdown",
+			Metadata:    metadata,
+			Transaction: transaction,
+			Span:        model.MetricsetSpan{Type: spanType, Subtype: subtype},
+			Samples: []model.Sample{
+				{Name: "span.self_time.count", Value: count},
+				{Name: "span.self_time.sum.us", Value: sum},
+				{Name: "metricset.period", Value: 10},
+			},
+		}
+	}
+	assert.ElementsMatch(t, []*model.Metricset{{
+		Name:        "transaction_breakdown",
+		Metadata:    metadata,
+		Transaction: transaction,
+		Samples: []model.Sample{
+			{Name: "transaction.breakdown.count", Value: 1},
+			{Name: "metricset.period", Value: 10},
+		},
+	},
+		spanBreakdown("app", "", 1, 50000),
+		spanBreakdown("db", "postgresql", 2, 30000),
+		spanBreakdown("external", "http", 1, 30000),
+	}, out.Metricsets)
+}
+
+func TestAggregatorMaxPendingSpans(t *testing.T) {
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor:  makeErrBatchProcessor(nil),
+		Interval:        time.Minute,
+		MaxGroups:       1000,
+		MaxPendingSpans: 1,
+	})
+	require.NoError(t, err)
+
+	start := time.Unix(1600000000, 0)
+	batch := model.Batch{Spans: []*model.Span{
+		makeSpan("Jaeger", "span1", "tx1", "tx1", start, time.Millisecond, "db", "mysql"),
+		makeSpan("Jaeger", "span2", "tx1", "tx1", start, time.Millisecond, "db", "mysql"),
+	}}
+	require.NoError(t, agg.ProcessBatch(context.Background(), &batch))
+	assert.Len(t, agg.takePendingSpans("trace", "tx1"), 1)
+	assert.Empty(t, agg.takePendingSpans("trace", "tx1"))
+}
+
+func TestAggregatorMaxPendingTransactions(t *testing.T) {
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor:  makeErrBatchProcessor(nil),
+		Interval:        time.Minute,
+		MaxGroups:       1,
+		MaxPendingSpans: 1,
+	})
+	require.NoError(t, err)
+
+	// Once too many transactions are buffered, transactions are
+	// processed immediately, without waiting for spans. The second
+	// transaction's breakdown group fills the aggregator's groups,
+	// so its span breakdown group requires immediate publication.
+	start := time.Unix(1600000000, 0)
+	batch := model.Batch{Transactions: []*model.Transaction{
+		makeTransaction("otlp", "tx1", start, time.Second),
+		makeTransaction("otlp", "tx2", start, time.Second),
+	}}
+	require.NoError(t, agg.ProcessBatch(context.Background(), &batch))
+	require.Len(t, batch.Metricsets, 1)
+	assert.Equal(t, "span_breakdown", batch.Metricsets[0].Name)
+}
+
+func TestAggregatorLateSpans(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor:  makeChanBatchProcessor(batches),
+		Interval:        time.Minute,
+		MaxGroups:       1000,
+		MaxPendingSpans: 1000,
+	})
+	require.NoError(t, err)
+
+	// Spans received after their transaction, in the same or the
+	// following interval, are accounted for. Spans without a
+	// transaction ID are matched by following their parent IDs.
+	start := time.Unix(1600000000, 0)
+	tx := makeTransaction("otlp", "tx1", start, 100*time.Millisecond)
+	batch := model.Batch{Transactions: []*model.Transaction{tx}}
+	require.NoError(t, agg.ProcessBatch(context.Background(), &batch))
+	batch = model.Batch{Spans: []*model.Span{
+		makeSpan("otlp", "span1", "tx1", "", start, 50*time.Millisecond, "db", "postgresql"),
+	}}
+	require.NoError(t, agg.ProcessBatch(context.Background(), &batch))
+	require.NoError(t, agg.publish(context.Background(), false))
+	batch = model.Batch{Spans: []*model.Span{
+		makeSpan("otlp", "span2", "span1", "", start, 10*time.Millisecond, "external", "http"),
+		makeSpan("otlp", "span3", "other", "", start, 10*time.Millisecond, "external", "http"),
+	}}
+	require.NoError(t, agg.ProcessBatch(context.Background(), &batch))
+	require.NoError(t, agg.publish(context.Background(), false))
+
+	out := expectBatch(t, batches)
+	selfTimes := make(map[string]float64)
+	for _, ms := range out.Metricsets {
+		if ms.Name == "span_breakdown" {
+			selfTimes[ms.Span.Type] = ms.Samples[1].Value
+		}
+	}
+	assert.Equal(t, map[string]float64{"app": 50000, "db": 40000, "external": 10000}, selfTimes)
+	assert.Equal(t, 1, agg.numPrevious) // span3
+}
+
+func TestAggregatorOpenTelemetry(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor:  makeChanBatchProcessor(batches),
+		Interval:        time.Minute,
+		MaxGroups:       1000,
+		MaxPendingSpans: 1000,
+	})
+	require.NoError(t, err)
+
+	start := time.Unix(1600000000, 0)
+	traces := pdata.NewTraces()
+	resourceSpans := pdata.NewResourceSpans()
+	resourceSpans.Resource().Attributes().InsertString("service.name", "service")
+	resourceSpans.Resource().Attributes().InsertString("telemetry.sdk.name", "opentelemetry")
+	resourceSpans.Resource().Attributes().InsertString("telemetry.sdk.language", "go")
+	librarySpans := pdata.NewInstrumentationLibrarySpans()
+	resourceSpans.InstrumentationLibrarySpans().Append(librarySpans)
+	traces.ResourceSpans().Append(resourceSpans)
+	addSpan := func(spanID, parentID byte, offset, duration time.Duration) pdata.Span {
+		span := pdata.NewSpan()
+		span.SetTraceID(pdata.NewTraceID([16]byte{1}))
+		span.SetSpanID(pdata.NewSpanID([8]byte{spanID}))
+		if parentID != 0 {
+			span.SetParentSpanID(pdata.NewSpanID([8]byte{parentID}))
+			span.SetKind(pdata.SpanKindCLIENT)
+		}
+		span.SetStartTime(pdata.TimestampFromTime(start.Add(offset)))
+		span.SetEndTime(pdata.TimestampFromTime(start.Add(offset + duration)))
+		librarySpans.Spans().Append(span)
+		return librarySpans.Spans().At(librarySpans.Spans().Len() - 1)
+	}
+	// Spans are sent before their parents, as they end first.
+	addSpan(3, 2, 20*time.Millisecond, 10*time.Millisecond)
+	addSpan(2, 1, 10*time.Millisecond, 40*time.Millisecond).Attributes().InsertString("http.url", "http://example.com")
+	addSpan(1, 0, 0, 100*time.Millisecond)
+
+	consumer := &otel.Consumer{Processor: agg}
+	require.NoError(t, consumer.ConsumeTraces(context.Background(), traces))
+	require.NoError(t, agg.publish(context.Background(), true))
+
+	out := expectBatch(t, batches)
+	selfTimes := make(map[string][2]float64)
+	for _, ms := range out.Metricsets {
+		assert.Equal(t, "opentelemetry/go", ms.Metadata.Service.Agent.Name)
+		if ms.Name == "span_breakdown" {
+			selfTimes[ms.Span.Type] = [2]float64{ms.Samples[0].Value, ms.Samples[1].Value}
+		}
+	}
+	// The self-time of the transaction and the internal span is
+	// recorded as "app", and the HTTP span's as "external".
+	assert.Equal(t, map[string][2]float64{
+		"app":      {2, 70000},
+		"external": {1, 30000},
+	}, selfTimes)
+}
+
+func TestAggregatorOverflow(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor:  makeChanBatchProcessor(batches),
+		Interval:        time.Minute,
+		MaxGroups:       1,
+		MaxPendingSpans: 1000,
+	})
+	require.NoError(t, err)
+
+	// The transaction breakdown group fills the aggregator's groups,
+	// so the span breakdown group requires immediate publication.
+	start := time.Unix(1600000000, 0)
+	batch := model.Batch{
+		Transactions: []*model.Transaction{makeTransaction("otlp", "tx1", start, time.Second)},
+	}
+	require.NoError(t, agg.ProcessBatch(context.Background(), &batch))
+	assert.Empty(t, batch.Metricsets)
+	require.NoError(t, agg.publish(context.Background(), true))
+
+	out := expectBatch(t, batches)
+	require.Len(t, out.Metricsets, 2)
+	assert.Equal(t, "span_breakdown", out.Metricsets[0].Name)
+	assert.Equal(t, []model.Sample{
+		{Name: "span.self_time.count", Value: 1},
+		{Name: "span.self_time.sum.us", Value: 1000000},
+		// No metricset.period is recorded as these metrics are instantanous, not aggregated.
+	}, out.Metricsets[0].Samples)
+	assert.Equal(t, "transaction_breakdown", out.Metricsets[1].Name)
+}
+
+func makeTransaction(agentName, id string, timestamp time.Time, duration time.Duration) *model.Transaction {
+	return &model.Transaction{
+		Metadata:            model.Metadata{Service: model.Service{Name: "service", Agent: model.Agent{Name: agentName}}},
+		ID:                  id,
+		TraceID:             "trace",
+		Name:                "GET /",
+		Type:                "request",
+		Timestamp:           timestamp,
+		Duration:            duration,
+		RepresentativeCount: 1,
+	}
+}
+
+func makeSpan(
+	agentName, id, parentID, transactionID string,
+	timestamp time.Time, duration time.Duration,
+	spanType, subtype string,
+) *model.Span {
+	return &model.Span{
+		Metadata:            model.Metadata{Service: model.Service{Name: "service", Agent: model.Agent{Name: agentName}}},
+		ID:                  id,
+		ParentID:            parentID,
+		TraceID:             "trace",
+		TransactionID:       transactionID,
+		Timestamp:           timestamp,
+		Duration:            duration,
+		Type:                spanType,
+		Subtype:             subtype,
+		RepresentativeCount: 1,
+	}
+}
+
+func makeErrBatchProcessor(err error) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return err })
+}
+
+func makeChanBatchProcessor(ch chan<- *model.Batch) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- batch:
+			return nil
+		}
+	})
+}
+
+func expectBatch(t *testing.T, ch <-chan *model.Batch) *model.Batch {
+	t.Helper()
+	select {
+	case batch := <-ch:
+		return batch
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected publish")
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,128 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package errormetrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/elastic/apm-server/featureflag"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/internal/periodic"
+	"github.com/elastic/beats/v7/libbeat/logp"
+)
+
+const (
+	metricsetName = "error_grouping"
+
+	countSampleName = "error.count"
+)
+
+// AggregatorConfig holds configuration for creating an Aggregator.
+// MaxGroups limits the number of distinct error groups stored within
+// an aggregation period.
+type AggregatorConfig = periodic.Config
+
+// Aggregator counts errors per service and error grouping key,
+// periodically publishing the counts as metrics.
+type Aggregator struct {
+	*periodic.Aggregator
+}
+
+// NewAggregator returns a new Aggregator with the given config.
+func NewAggregator(config AggregatorConfig) (*Aggregator, error) {
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger(logs.ErrorMetrics)
+	}
+	agg, err := periodic.New(config, errorGroup{}, "error")
+	if err != nil {
+		return nil, err
+	}
+	return &Aggregator{Aggregator: agg}, nil
+}
+
+// ProcessBatch counts all errors contained in "b", adding to it any
+// metricsets requiring immediate publication.
+//
+// This method is expected to be used immediately prior to publishing
+// the events. Errors are not counted while the ErrorMetrics feature
+// flag is disabled.
+func (a *Aggregator) ProcessBatch(ctx context.Context, b *model.Batch) error {
+	if !featureflag.Enabled(featureflag.ErrorMetrics) {
+		return nil
+	}
+	for _, e := range b.Errors {
+		if metricset := a.processError(e); metricset != nil {
+			b.Metricsets = append(b.Metricsets, metricset)
+		}
+	}
+	return nil
+}
+
+// processError counts e in its error group. The error's stacktraces are
+// expected to have been source mapped already, so the grouping key matches
+// the one recorded in the error document.
+func (a *Aggregator) processError(e *model.Error) *model.Metricset {
+	key := aggregationKey{
+		serviceEnvironment: e.Metadata.Service.Environment,
+		serviceName:        e.Metadata.Service.Name,
+		agentName:          e.Metadata.Service.Agent.Name,
+		groupingKey:        e.GroupingKey(),
+	}
+	return a.Add(key, int64(1))
+}
+
+// errorGroup implements periodic.Group, counting errors.
+type errorGroup struct{}
+
+func (errorGroup) Merge(old, value interface{}) interface{} {
+	count, _ := old.(int64)
+	return count + value.(int64)
+}
+
+func (errorGroup) Metricset(timestamp time.Time, key, value interface{}, interval int64) model.Metricset {
+	return makeMetricset(timestamp, key.(aggregationKey), value.(int64), interval)
+}
+
+type aggregationKey struct {
+	serviceName        string
+	serviceEnvironment string
+	agentName          string
+	groupingKey        string
+}
+
+func makeMetricset(timestamp time.Time, key aggregationKey, count int64, interval int64) model.Metricset {
+	out := model.Metricset{
+		Timestamp: timestamp,
+		Name:      metricsetName,
+		Metadata: model.Metadata{
+			Service: model.Service{
+				Name:        key.serviceName,
+				Environment: key.serviceEnvironment,
+				Agent:       model.Agent{Name: key.agentName},
+			},
+		},
+		Error: model.MetricsetError{
+			GroupingKey: key.groupingKey,
+		},
+		Samples: []model.Sample{{
+			Name:  countSampleName,
+			Value: float64(count),
+		}},
+	}
+	if interval > 0 {
+		// Only set metricset.period for a positive interval.
+		//
+		// An interval of zero means the metricset is computed
+		// from an instantaneous value, meaning there is no
+		// aggregation period.
+		out.Samples = append(out.Samples, model.Sample{
+			Name:  "metricset.period",
+			Value: float64(interval),
+		})
+	}
+	return out
+}
@@ -0,0 +1,240 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package errormetrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/featureflag"
+	"github.com/elastic/apm-server/model"
+)
+
+func TestNewAggregatorConfigInvalid(t *testing.T) {
+	report := makeErrBatchProcessor(nil)
+
+	type test struct {
+		config AggregatorConfig
+		err    string
+	}
+
+	for _, test := range []test{{
+		config: AggregatorConfig{},
+		err:    "BatchProcessor unspecified",
+	}, {
+		config: AggregatorConfig{
+			BatchProcessor: report,
+		},
+		err: "MaxGroups unspecified or negative",
+	}, {
+		config: AggregatorConfig{
+			BatchProcessor: report,
+			MaxGroups:      1,
+		},
+		err: "Interval unspecified or negative",
+	}} {
+		agg, err := NewAggregator(test.config)
+		require.Error(t, err)
+		require.Nil(t, agg)
+		assert.EqualError(t, err, "invalid aggregator config: "+test.err)
+	}
+}
+
+func TestAggregatorRun(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor: makeChanBatchProcessor(batches),
+		Interval:       10 * time.Millisecond,
+		MaxGroups:      1000,
+	})
+	require.NoError(t, err)
+
+	type input struct {
+		serviceName   string
+		exceptionType string
+	}
+	inputs := []input{
+		{serviceName: "service-A", exceptionType: "NullPointerException"},
+		{serviceName: "service-A", exceptionType: "NullPointerException"},
+		{serviceName: "service-A", exceptionType: "IOException"},
+		{serviceName: "service-B", exceptionType: "NullPointerException"},
+	}
+
+	var wg sync.WaitGroup
+	for _, in := range inputs {
+		wg.Add(1)
+		go func(in input) {
+			defer wg.Done()
+			batch := &model.Batch{Errors: []*model.Error{makeError(in.serviceName, in.exceptionType)}}
+			for i := 0; i < 100; i++ {
+				err := agg.ProcessBatch(context.Background(), batch)
+				require.NoError(t, err)
+				assert.Empty(t, batch.Metricsets)
+			}
+		}(in)
+	}
+	wg.Wait()
+
+	// Start the aggregator after processing to ensure metrics are aggregated deterministically.
+	go agg.Run()
+	defer agg.Stop(context.Background())
+
+	batch := expectBatch(t, batches)
+	for _, ms := range batch.Metricsets {
+		require.NotZero(t, ms.Timestamp)
+		ms.Timestamp = time.Time{}
+	}
+
+	npeKey := makeError("", "NullPointerException").GroupingKey()
+	ioeKey := makeError("", "IOException").GroupingKey()
+	assert.ElementsMatch(t, []*model.Metricset{{
+		Name: "error_grouping",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "service-A", Agent: model.Agent{Name: "java"}},
+		},
+		Error: model.MetricsetError{GroupingKey: npeKey},
+		Samples: []model.Sample{
+			{Name: "error.count", Value: 200},
+			{Name: "metricset.period", Value: 10},
+		},
+	}, {
+		Name: "error_grouping",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "service-A", Agent: model.Agent{Name: "java"}},
+		},
+		Error: model.MetricsetError{GroupingKey: ioeKey},
+		Samples: []model.Sample{
+			{Name: "error.count", Value: 100},
+			{Name: "metricset.period", Value: 10},
+		},
+	}, {
+		Name: "error_grouping",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "service-B", Agent: model.Agent{Name: "java"}},
+		},
+		Error: model.MetricsetError{GroupingKey: npeKey},
+		Samples: []model.Sample{
+			{Name: "error.count", Value: 100},
+			{Name: "metricset.period", Value: 10},
+		},
+	}}, batch.Metricsets)
+
+	select {
+	case <-batches:
+		t.Fatal("unexpected publish")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAggregatorOverflow(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor: makeChanBatchProcessor(batches),
+		Interval:       10 * time.Millisecond,
+		MaxGroups:      2,
+	})
+	require.NoError(t, err)
+
+	// The first two error groups will not require immediate publication,
+	// as we have configured the aggregator with a maximum of two buckets.
+	var batch model.Batch
+	for i := 0; i < 10; i++ {
+		batch.Errors = append(batch.Errors,
+			makeError("service", "a"),
+			makeError("service", "b"),
+		)
+	}
+	err = agg.ProcessBatch(context.Background(), &batch)
+	require.NoError(t, err)
+	assert.Empty(t, batch.Metricsets)
+
+	// The third group will return a metricset for immediate publication.
+	e := makeError("service", "c")
+	batch.Errors = []*model.Error{e}
+	err = agg.ProcessBatch(context.Background(), &batch)
+	require.NoError(t, err)
+	require.Len(t, batch.Metricsets, 1)
+
+	m := batch.Metricsets[0]
+	require.False(t, m.Timestamp.IsZero())
+	m.Timestamp = time.Time{}
+	assert.Equal(t, &model.Metricset{
+		Name: "error_grouping",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "service", Agent: model.Agent{Name: "java"}},
+		},
+		Error: model.MetricsetError{GroupingKey: e.GroupingKey()},
+		Samples: []model.Sample{
+			{Name: "error.count", Value: 1},
+			// No metricset.period is recorded as these metrics are instantanous, not aggregated.
+		},
+	}, m)
+}
+
+func TestAggregatorFeatureFlagDisabled(t *testing.T) {
+	_, err := featureflag.Default.Update(map[string]bool{"error_metrics": false})
+	require.NoError(t, err)
+	defer featureflag.Default.Update(nil)
+
+	batches := make(chan *model.Batch, 1)
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor: makeChanBatchProcessor(batches),
+		Interval:       10 * time.Millisecond,
+		MaxGroups:      1,
+	})
+	require.NoError(t, err)
+
+	// Errors are not counted, so overflowing groups are not
+	// returned for immediate publication either.
+	batch := model.Batch{Errors: []*model.Error{makeError("service", "a"), makeError("service", "b")}}
+	require.NoError(t, agg.ProcessBatch(context.Background(), &batch))
+	assert.Empty(t, batch.Metricsets)
+
+	go agg.Run()
+	defer agg.Stop(context.Background())
+	select {
+	case batch := <-batches:
+		t.Fatalf("unexpected publish: %+v", batch)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func makeError(serviceName, exceptionType string) *model.Error {
+	return &model.Error{
+		Metadata:  model.Metadata{Service: model.Service{Name: serviceName, Agent: model.Agent{Name: "java"}}},
+		Exception: &model.Exception{Type: exceptionType},
+	}
+}
+
+func makeErrBatchProcessor(err error) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return err })
+}
+
+func makeChanBatchProcessor(ch chan<- *model.Batch) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- batch:
+			return nil
+		}
+	})
+}
+
+func expectBatch(t *testing.T, ch <-chan *model.Batch) *model.Batch {
+	t.Helper()
+	select {
+	case batch := <-ch:
+		return batch
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected publish")
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,223 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package periodic provides the common behaviour of aggregators which store
+// a bounded number of aggregation groups, periodically publishing them as
+// metricsets.
+package periodic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/beats/v7/libbeat/logp"
+)
+
+// Config holds configuration for creating an Aggregator.
+type Config struct {
+	// BatchProcessor is a model.BatchProcessor for asynchronously
+	// processing metrics documents.
+	BatchProcessor model.BatchProcessor
+
+	// MaxGroups is the maximum number of distinct aggregation groups
+	// to store within an aggregation period. Once this number of groups
+	// is reached, any new aggregation keys will cause individual metrics
+	// documents to be immediately published.
+	MaxGroups int
+
+	// Interval is the interval between publishing of aggregated metrics.
+	// There may be additional metrics reported at arbitrary times if the
+	// aggregation groups fill up.
+	Interval time.Duration
+
+	// Logger is the logger for logging metrics aggregation/publishing.
+	//
+	// If Logger is nil, a new logger will be constructed.
+	Logger *logp.Logger
+}
+
+// Validate validates the aggregator config.
+func (config Config) Validate() error {
+	if config.BatchProcessor == nil {
+		return errors.New("BatchProcessor unspecified")
+	}
+	if config.MaxGroups <= 0 {
+		return errors.New("MaxGroups unspecified or negative")
+	}
+	if config.Interval <= 0 {
+		return errors.New("Interval unspecified or negative")
+	}
+	return nil
+}
+
+// Group defines how aggregated values are combined and published.
+type Group interface {
+	// Merge returns the combination of the aggregated value old, which
+	// is nil if no value has been stored for the group, and value.
+	Merge(old, value interface{}) interface{}
+
+	// Metricset returns a metricset for the aggregated value of the group
+	// identified by key. The interval is zero for metricsets which are
+	// published immediately, rather than at the end of an aggregation
+	// period.
+	Metricset(timestamp time.Time, key, value interface{}, interval int64) model.Metricset
+}
+
+// Aggregator stores aggregated values for up to Config.MaxGroups groups,
+// periodically publishing and clearing them.
+type Aggregator struct {
+	stopMu   sync.Mutex
+	stopping chan struct{}
+	stopped  chan struct{}
+
+	config Config
+	group  Group
+	name   string
+
+	mu               sync.RWMutex
+	active, inactive *metricsBuffer
+}
+
+// New returns a new Aggregator with the given config, combining and
+// publishing values as defined by group. The name describes the metrics
+// in log messages, e.g. "error".
+func New(config Config, group Group, name string) (*Aggregator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid aggregator config")
+	}
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger("aggregator")
+	}
+	return &Aggregator{
+		stopping: make(chan struct{}),
+		stopped:  make(chan struct{}),
+		config:   config,
+		group:    group,
+		name:     name,
+		active:   newMetricsBuffer(config.MaxGroups),
+		inactive: newMetricsBuffer(config.MaxGroups),
+	}, nil
+}
+
+// Run runs the Aggregator, periodically publishing and clearing aggregated
+// metrics. Run returns when either a fatal error occurs, or the Aggregator's
+// Stop method is invoked.
+func (a *Aggregator) Run() error {
+	ticker := time.NewTicker(a.config.Interval)
+	defer ticker.Stop()
+	defer func() {
+		a.stopMu.Lock()
+		defer a.stopMu.Unlock()
+		select {
+		case <-a.stopped:
+		default:
+			close(a.stopped)
+		}
+	}()
+	var stop bool
+	for !stop {
+		select {
+		case <-a.stopping:
+			stop = true
+		case <-ticker.C:
+		}
+		if err := a.publish(context.Background()); err != nil {
+			a.config.Logger.With(logp.Error(err)).Warnf(
+				"publishing %s metrics failed: %s", a.name, err,
+			)
+		}
+	}
+	return nil
+}
+
+// Stop stops the Aggregator if it is running, waiting for it to flush any
+// aggregated metrics and return, or for the context to be cancelled.
+//
+// After Stop has been called the aggregator cannot be reused, as the Run
+// method will always return immediately.
+func (a *Aggregator) Stop(ctx context.Context) error {
+	a.stopMu.Lock()
+	select {
+	case <-a.stopped:
+	case <-a.stopping:
+		// Already stopping/stopped.
+	default:
+		close(a.stopping)
+	}
+	a.stopMu.Unlock()
+
+	select {
+	case <-a.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// Add adds value to the aggregation group identified by key. If the
+// group cannot be stored because the maximum number of groups has been
+// reached, Add returns a metricset for the value which must be published
+// immediately; otherwise Add returns nil.
+func (a *Aggregator) Add(key, value interface{}) *model.Metricset {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.active.storeOrUpdate(key, value, a.group.Merge) {
+		return nil
+	}
+	metricset := a.group.Metricset(time.Now(), key, value, 0)
+	return &metricset
+}
+
+func (a *Aggregator) publish(ctx context.Context) error {
+	// We hold a.mu only long enough to swap the buffers. After the
+	// lock is released nothing will be accessing a.inactive.
+	a.mu.Lock()
+	a.active, a.inactive = a.inactive, a.active
+	a.mu.Unlock()
+
+	size := len(a.inactive.m)
+	if size == 0 {
+		a.config.Logger.Debugf("no %s metrics to publish", a.name)
+		return nil
+	}
+
+	now := time.Now()
+	metricsets := make([]*model.Metricset, 0, size)
+	for key, value := range a.inactive.m {
+		metricset := a.group.Metricset(now, key, value, a.config.Interval.Milliseconds())
+		metricsets = append(metricsets, &metricset)
+		delete(a.inactive.m, key)
+	}
+	a.config.Logger.Debugf("publishing %d metricsets", len(metricsets))
+	return a.config.BatchProcessor.ProcessBatch(ctx, &model.Batch{Metricsets: metricsets})
+}
+
+type metricsBuffer struct {
+	maxSize int
+
+	mu sync.Mutex
+	m  map[interface{}]interface{}
+}
+
+func newMetricsBuffer(maxSize int) *metricsBuffer {
+	return &metricsBuffer{
+		maxSize: maxSize,
+		m:       make(map[interface{}]interface{}),
+	}
+}
+
+func (mb *metricsBuffer) storeOrUpdate(key, value interface{}, merge func(old, value interface{}) interface{}) bool {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	old, ok := mb.m[key]
+	if !ok && len(mb.m) == mb.maxSize {
+		return false
+	}
+	mb.m[key] = merge(old, value)
+	return true
+}
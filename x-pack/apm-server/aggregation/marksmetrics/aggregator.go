@@ -0,0 +1,207 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package marksmetrics
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elastic/apm-server/featureflag"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/internal/periodic"
+	"github.com/elastic/beats/v7/libbeat/logp"
+)
+
+const (
+	metricsetName = "transaction_marks"
+
+	countSampleName   = "transaction.marks_count"
+	marksSamplePrefix = "transaction.marks."
+)
+
+// AggregatorConfig holds configuration for creating an Aggregator.
+// MaxGroups limits the number of distinct transaction groups stored
+// within an aggregation period.
+type AggregatorConfig = periodic.Config
+
+// Aggregator aggregates transaction marks, such as RUM page-load navigation
+// timing marks, per service and transaction group, periodically publishing
+// the mean of each mark.
+type Aggregator struct {
+	*periodic.Aggregator
+}
+
+// NewAggregator returns a new Aggregator with the given config.
+func NewAggregator(config AggregatorConfig) (*Aggregator, error) {
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger(logs.MarksMetrics)
+	}
+	agg, err := periodic.New(config, marksGroup{}, "transaction marks")
+	if err != nil {
+		return nil, err
+	}
+	return &Aggregator{Aggregator: agg}, nil
+}
+
+// ProcessBatch aggregates the marks of all transactions contained in "b",
+// adding to it any metricsets requiring immediate publication.
+//
+// This method is expected to be used immediately prior to publishing
+// the events. Transactions are not aggregated while the
+// TransactionMarksMetrics feature flag is disabled.
+func (a *Aggregator) ProcessBatch(ctx context.Context, b *model.Batch) error {
+	if !featureflag.Enabled(featureflag.TransactionMarksMetrics) {
+		return nil
+	}
+	for _, tx := range b.Transactions {
+		if metricset := a.processTransaction(tx); metricset != nil {
+			b.Metricsets = append(b.Metricsets, metricset)
+		}
+	}
+	return nil
+}
+
+func (a *Aggregator) processTransaction(tx *model.Transaction) *model.Metricset {
+	if len(tx.Marks) == 0 {
+		return nil
+	}
+	if tx.RepresentativeCount <= 0 {
+		// RepresentativeCount is zero when the sample rate is unknown.
+		// We cannot calculate accurate metrics without the sample rate,
+		// so we don't calculate any at all in this case.
+		return nil
+	}
+
+	key := aggregationKey{
+		serviceEnvironment: tx.Metadata.Service.Environment,
+		serviceName:        tx.Metadata.Service.Name,
+		agentName:          tx.Metadata.Service.Agent.Name,
+		transactionName:    tx.Name,
+		transactionType:    tx.Type,
+	}
+	metrics := marksMetrics{
+		count: tx.RepresentativeCount,
+		marks: make(map[markKey]markMetrics),
+	}
+	for group, marks := range tx.Marks {
+		for name, value := range marks {
+			metrics.marks[markKey{group: group, name: name}] = markMetrics{
+				count: tx.RepresentativeCount,
+				sum:   value * tx.RepresentativeCount,
+			}
+		}
+	}
+	return a.Add(key, metrics)
+}
+
+// marksGroup implements periodic.Group, combining transaction marks.
+type marksGroup struct{}
+
+func (marksGroup) Merge(old, value interface{}) interface{} {
+	if old == nil {
+		return value
+	}
+	metrics := old.(marksMetrics)
+	add := value.(marksMetrics)
+	metrics.count += add.count
+	for k, v := range add.marks {
+		m := metrics.marks[k]
+		metrics.marks[k] = markMetrics{count: m.count + v.count, sum: m.sum + v.sum}
+	}
+	return metrics
+}
+
+func (marksGroup) Metricset(timestamp time.Time, key, value interface{}, interval int64) model.Metricset {
+	return makeMetricset(timestamp, key.(aggregationKey), value.(marksMetrics), interval)
+}
+
+type aggregationKey struct {
+	serviceName        string
+	serviceEnvironment string
+	agentName          string
+	transactionName    string
+	transactionType    string
+}
+
+type markKey struct {
+	group string
+	name  string
+}
+
+type marksMetrics struct {
+	// count holds the number of transactions in the group.
+	count float64
+
+	// marks holds the metrics for each mark. A mark's count
+	// may be lower than the transaction count, if not all
+	// transactions in the group recorded the mark.
+	marks map[markKey]markMetrics
+}
+
+type markMetrics struct {
+	count float64
+	sum   float64
+}
+
+func makeMetricset(timestamp time.Time, key aggregationKey, metrics marksMetrics, interval int64) model.Metricset {
+	out := model.Metricset{
+		Timestamp: timestamp,
+		Name:      metricsetName,
+		Metadata: model.Metadata{
+			Service: model.Service{
+				Name:        key.serviceName,
+				Environment: key.serviceEnvironment,
+				Agent:       model.Agent{Name: key.agentName},
+			},
+		},
+		Transaction: model.MetricsetTransaction{
+			Name: key.transactionName,
+			Type: key.transactionType,
+		},
+		Samples: []model.Sample{{
+			Name:  countSampleName,
+			Value: math.Round(metrics.count),
+		}},
+	}
+	marks := make([]model.Sample, 0, len(metrics.marks))
+	for k, v := range metrics.marks {
+		// Marks are recorded with the same field names as in transaction
+		// documents, so they can be queried together.
+		marks = append(marks, model.Sample{
+			Name:  marksSamplePrefix + sanitizeMarkKey(k.group) + "." + sanitizeMarkKey(k.name),
+			Value: v.sum / v.count,
+		})
+	}
+	sort.Slice(marks, func(i, j int) bool { return marks[i].Name < marks[j].Name })
+	out.Samples = append(out.Samples, marks...)
+	if interval > 0 {
+		// Only set metricset.period for a positive interval.
+		//
+		// An interval of zero means the metricset is computed
+		// from an instantaneous value, meaning there is no
+		// aggregation period.
+		out.Samples = append(out.Samples, model.Sample{
+			Name:  "metricset.period",
+			Value: float64(interval),
+		})
+	}
+	return out
+}
+
+// sanitizeMarkKey replaces characters reserved in field names,
+// as is done for marks in transaction documents.
+func sanitizeMarkKey(k string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '"':
+			return '_'
+		}
+		return r
+	}, k)
+}
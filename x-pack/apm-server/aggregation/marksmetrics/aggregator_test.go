@@ -0,0 +1,234 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package marksmetrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/apm-server/model"
+)
+
+func TestNewAggregatorConfigInvalid(t *testing.T) {
+	report := makeErrBatchProcessor(nil)
+
+	type test struct {
+		config AggregatorConfig
+		err    string
+	}
+
+	for _, test := range []test{{
+		config: AggregatorConfig{},
+		err:    "BatchProcessor unspecified",
+	}, {
+		config: AggregatorConfig{
+			BatchProcessor: report,
+		},
+		err: "MaxGroups unspecified or negative",
+	}, {
+		config: AggregatorConfig{
+			BatchProcessor: report,
+			MaxGroups:      1,
+		},
+		err: "Interval unspecified or negative",
+	}} {
+		agg, err := NewAggregator(test.config)
+		require.Error(t, err)
+		require.Nil(t, agg)
+		assert.EqualError(t, err, "invalid aggregator config: "+test.err)
+	}
+}
+
+func TestAggregatorRun(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor: makeChanBatchProcessor(batches),
+		Interval:       10 * time.Millisecond,
+		MaxGroups:      1000,
+	})
+	require.NoError(t, err)
+
+	type input struct {
+		serviceName     string
+		transactionName string
+		marks           model.TransactionMarks
+		count           float64
+	}
+
+	navigationTiming := func(domComplete float64) model.TransactionMarks {
+		return model.TransactionMarks{
+			"navigationTiming": model.TransactionMark{"domComplete": domComplete},
+		}
+	}
+	inputs := []input{
+		{serviceName: "service-A", transactionName: "/", marks: navigationTiming(100), count: 1},
+		{serviceName: "service-A", transactionName: "/", marks: navigationTiming(200), count: 3},
+		{serviceName: "service-A", transactionName: "/", marks: model.TransactionMarks{
+			"agent": model.TransactionMark{"timeToFirstByte": 10},
+		}, count: 1},
+		{serviceName: "service-A", transactionName: "/products", marks: navigationTiming(300), count: 1},
+		{serviceName: "service-B", transactionName: "/", marks: model.TransactionMarks{
+			"custom.group": model.TransactionMark{"first*paint": 50},
+		}, count: 1},
+		{serviceName: "service-B", transactionName: "/", marks: navigationTiming(100), count: 0}, // unknown sample rate
+		{serviceName: "service-B", transactionName: "/", count: 1},                               // no marks
+	}
+
+	var wg sync.WaitGroup
+	for _, in := range inputs {
+		wg.Add(1)
+		go func(in input) {
+			defer wg.Done()
+			tx := makeTransaction(in.serviceName, in.transactionName, in.marks, in.count)
+			batch := &model.Batch{Transactions: []*model.Transaction{tx}}
+			for i := 0; i < 100; i++ {
+				err := agg.ProcessBatch(context.Background(), batch)
+				require.NoError(t, err)
+				assert.Empty(t, batch.Metricsets)
+			}
+		}(in)
+	}
+	wg.Wait()
+
+	// Start the aggregator after processing to ensure metrics are aggregated deterministically.
+	go agg.Run()
+	defer agg.Stop(context.Background())
+
+	batch := expectBatch(t, batches)
+	for _, ms := range batch.Metricsets {
+		require.NotZero(t, ms.Timestamp)
+		ms.Timestamp = time.Time{}
+	}
+
+	assert.ElementsMatch(t, []*model.Metricset{{
+		Name: "transaction_marks",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "service-A", Agent: model.Agent{Name: "rum-js"}},
+		},
+		Transaction: model.MetricsetTransaction{Name: "/", Type: "page-load"},
+		Samples: []model.Sample{
+			{Name: "transaction.marks_count", Value: 500},
+			{Name: "transaction.marks.agent.timeToFirstByte", Value: 10},
+			{Name: "transaction.marks.navigationTiming.domComplete", Value: 175},
+			{Name: "metricset.period", Value: 10},
+		},
+	}, {
+		Name: "transaction_marks",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "service-A", Agent: model.Agent{Name: "rum-js"}},
+		},
+		Transaction: model.MetricsetTransaction{Name: "/products", Type: "page-load"},
+		Samples: []model.Sample{
+			{Name: "transaction.marks_count", Value: 100},
+			{Name: "transaction.marks.navigationTiming.domComplete", Value: 300},
+			{Name: "metricset.period", Value: 10},
+		},
+	}, {
+		Name: "transaction_marks",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "service-B", Agent: model.Agent{Name: "rum-js"}},
+		},
+		Transaction: model.MetricsetTransaction{Name: "/", Type: "page-load"},
+		Samples: []model.Sample{
+			{Name: "transaction.marks_count", Value: 100},
+			{Name: "transaction.marks.custom_group.first_paint", Value: 50},
+			{Name: "metricset.period", Value: 10},
+		},
+	}}, batch.Metricsets)
+
+	select {
+	case <-batches:
+		t.Fatal("unexpected publish")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAggregatorOverflow(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	agg, err := NewAggregator(AggregatorConfig{
+		BatchProcessor: makeChanBatchProcessor(batches),
+		Interval:       10 * time.Millisecond,
+		MaxGroups:      2,
+	})
+	require.NoError(t, err)
+
+	marks := model.TransactionMarks{"agent": model.TransactionMark{"domComplete": 100}}
+
+	// The first two transaction groups will not require immediate publication,
+	// as we have configured the aggregator with a maximum of two buckets.
+	var batch model.Batch
+	for i := 0; i < 10; i++ {
+		batch.Transactions = append(batch.Transactions,
+			makeTransaction("service", "/a", marks, 1),
+			makeTransaction("service", "/b", marks, 1),
+		)
+	}
+	err = agg.ProcessBatch(context.Background(), &batch)
+	require.NoError(t, err)
+	assert.Empty(t, batch.Metricsets)
+
+	// The third group will return a metricset for immediate publication.
+	batch.Transactions = []*model.Transaction{makeTransaction("service", "/c", marks, 1)}
+	err = agg.ProcessBatch(context.Background(), &batch)
+	require.NoError(t, err)
+	require.Len(t, batch.Metricsets, 1)
+
+	m := batch.Metricsets[0]
+	require.False(t, m.Timestamp.IsZero())
+	m.Timestamp = time.Time{}
+	assert.Equal(t, &model.Metricset{
+		Name: "transaction_marks",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "service", Agent: model.Agent{Name: "rum-js"}},
+		},
+		Transaction: model.MetricsetTransaction{Name: "/c", Type: "page-load"},
+		Samples: []model.Sample{
+			{Name: "transaction.marks_count", Value: 1},
+			{Name: "transaction.marks.agent.domComplete", Value: 100},
+			// No metricset.period is recorded as these metrics are instantanous, not aggregated.
+		},
+	}, m)
+}
+
+func makeTransaction(serviceName, transactionName string, marks model.TransactionMarks, count float64) *model.Transaction {
+	return &model.Transaction{
+		Metadata:            model.Metadata{Service: model.Service{Name: serviceName, Agent: model.Agent{Name: "rum-js"}}},
+		Name:                transactionName,
+		Type:                "page-load",
+		Marks:               marks,
+		RepresentativeCount: count,
+	}
+}
+
+func makeErrBatchProcessor(err error) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return err })
+}
+
+func makeChanBatchProcessor(ch chan<- *model.Batch) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- batch:
+			return nil
+		}
+	})
+}
+
+func expectBatch(t *testing.T, ch <-chan *model.Batch) *model.Batch {
+	t.Helper()
+	select {
+	case batch := <-ch:
+		return batch
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected publish")
+	}
+	panic("unreachable")
+}
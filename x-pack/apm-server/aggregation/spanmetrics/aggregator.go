@@ -8,6 +8,7 @@ import (
 	"context"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -15,10 +16,19 @@ import (
 	logs "github.com/elastic/apm-server/log"
 	"github.com/elastic/apm-server/model"
 	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
 )
 
 const (
 	metricsetName = "service_destination"
+
+	// overflowBucket is the service name and destination resource of
+	// the group into which spans are aggregated once MaxGroups is reached.
+	overflowBucket = "_other"
+
+	// tooManyGroupsLoggerRateLimit is the maximum frequency at which
+	// "too many groups" log messages are logged.
+	tooManyGroupsLoggerRateLimit = time.Minute
 )
 
 // AggregatorConfig holds configuration for creating an Aggregator.
@@ -29,13 +39,12 @@ type AggregatorConfig struct {
 
 	// MaxGroups is the maximum number of distinct service destination
 	// group metrics to store within an aggregation period. Once this
-	// number of groups is reached, any new aggregation keys will cause
-	// individual metrics documents to be immediately published.
+	// number of groups is reached, spans with new aggregation keys are
+	// aggregated into a group with the service name and destination
+	// resource "_other".
 	MaxGroups int
 
 	// Interval is the interval between publishing of aggregated metrics.
-	// There may be additional metrics reported at arbitrary times if the
-	// aggregation groups fill up.
 	Interval time.Duration
 
 	// Logger is the logger for logging metrics aggregation/publishing.
@@ -64,12 +73,18 @@ type Aggregator struct {
 	stopping chan struct{}
 	stopped  chan struct{}
 
-	config AggregatorConfig
+	config              AggregatorConfig
+	metrics             aggregatorMetrics
+	tooManyGroupsLogger *logp.Logger
 
 	mu               sync.RWMutex
 	active, inactive *metricsBuffer
 }
 
+type aggregatorMetrics struct {
+	overflowed int64
+}
+
 // NewAggregator returns a new Aggregator with the given config.
 func NewAggregator(config AggregatorConfig) (*Aggregator, error) {
 	if err := config.Validate(); err != nil {
@@ -79,11 +94,12 @@ func NewAggregator(config AggregatorConfig) (*Aggregator, error) {
 		config.Logger = logp.NewLogger(logs.SpanMetrics)
 	}
 	return &Aggregator{
-		stopping: make(chan struct{}),
-		stopped:  make(chan struct{}),
-		config:   config,
-		active:   newMetricsBuffer(config.MaxGroups),
-		inactive: newMetricsBuffer(config.MaxGroups),
+		stopping:            make(chan struct{}),
+		stopped:             make(chan struct{}),
+		config:              config,
+		tooManyGroupsLogger: config.Logger.WithOptions(logs.WithRateLimit(tooManyGroupsLoggerRateLimit)),
+		active:              newMetricsBuffer(config.MaxGroups),
+		inactive:            newMetricsBuffer(config.MaxGroups),
 	}, nil
 }
 
@@ -142,6 +158,25 @@ func (a *Aggregator) Stop(ctx context.Context) error {
 	return nil
 }
 
+// CollectMonitoring may be called to collect monitoring metrics from the
+// aggregation. It is intended to be used with libbeat/monitoring.NewFunc.
+//
+// The metrics should be added to the "apm-server.aggregation.spanmetrics" registry.
+func (a *Aggregator) CollectMonitoring(_ monitoring.Mode, V monitoring.Visitor) {
+	V.OnRegistryStart()
+	defer V.OnRegistryFinished()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	m := a.active
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	monitoring.ReportInt(V, "active_groups", int64(len(m.m)))
+	monitoring.ReportInt(V, "overflowed", atomic.LoadInt64(&a.metrics.overflowed))
+}
+
 func (a *Aggregator) publish(ctx context.Context) error {
 	// We hold a.mu only long enough to swap the spanMetrics. This will
 	// be blocked by spanMetrics updates, which is OK, as we prefer not
@@ -164,12 +199,12 @@ func (a *Aggregator) publish(ctx context.Context) error {
 		metricsets = append(metricsets, &metricset)
 		delete(a.inactive.m, key)
 	}
+	a.inactive.overflowGroups = 0
 	a.config.Logger.Debugf("publishing %d metricsets", len(metricsets))
 	return a.config.BatchProcessor.ProcessBatch(ctx, &model.Batch{Metricsets: metricsets})
 }
 
-// ProcessBatch aggregates all spans contained in "b", adding to it any
-// metricsets requiring immediate publication.
+// ProcessBatch aggregates all spans contained in "b".
 //
 // This method is expected to be used immediately prior to publishing
 // the events.
@@ -177,22 +212,20 @@ func (a *Aggregator) ProcessBatch(ctx context.Context, b *model.Batch) error {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
 	for _, span := range b.Spans {
-		if metricset := a.processSpan(span); metricset != nil {
-			b.Metricsets = append(b.Metricsets, metricset)
-		}
+		a.processSpan(span)
 	}
 	return nil
 }
 
-func (a *Aggregator) processSpan(span *model.Span) *model.Metricset {
+func (a *Aggregator) processSpan(span *model.Span) {
 	if span.DestinationService == nil || span.DestinationService.Resource == "" {
-		return nil
+		return
 	}
 	if span.RepresentativeCount <= 0 {
 		// RepresentativeCount is zero when the sample rate is unknown.
 		// We cannot calculate accurate span metrics without the sample
 		// rate, so we don't calculate any at all in this case.
-		return nil
+		return
 	}
 
 	key := aggregationKey{
@@ -202,23 +235,31 @@ func (a *Aggregator) processSpan(span *model.Span) *model.Metricset {
 		outcome:            span.Outcome,
 		resource:           span.DestinationService.Resource,
 	}
-	duration := time.Duration(span.Duration * float64(time.Millisecond))
+	duration := span.Duration
 	metrics := spanMetrics{
 		count: span.RepresentativeCount,
 		sum:   float64(duration.Microseconds()) * span.RepresentativeCount,
 	}
 	if a.active.storeOrUpdate(key, metrics) {
-		return nil
+		return
 	}
-	metricset := makeMetricset(time.Now(), key, metrics, 0)
-	return &metricset
+	// Too many aggregation keys: aggregate the span into the overflow
+	// group, which is always stored, so its throughput is not lost.
+	a.tooManyGroupsLogger.Warn(`
+Service destination group limit reached, aggregating further groups into "_other".
+This is typically caused by high-cardinality destination resources, e.g. by
+including unique identifiers in span destination resources.`[1:],
+	)
+	atomic.AddInt64(&a.metrics.overflowed, 1)
+	a.active.update(overflowKey(key), metrics)
 }
 
 type metricsBuffer struct {
 	maxSize int
 
-	mu sync.RWMutex
-	m  map[aggregationKey]spanMetrics
+	mu             sync.RWMutex
+	m              map[aggregationKey]spanMetrics
+	overflowGroups int
 }
 
 func newMetricsBuffer(maxSize int) *metricsBuffer {
@@ -228,17 +269,31 @@ func newMetricsBuffer(maxSize int) *metricsBuffer {
 	}
 }
 
+// storeOrUpdate adds value to the metrics for key, returning false if
+// key is not yet stored and the buffer is full. Overflow groups do not
+// count towards the buffer's size.
 func (mb *metricsBuffer) storeOrUpdate(key aggregationKey, value spanMetrics) bool {
 	mb.mu.Lock()
 	defer mb.mu.Unlock()
 	old, ok := mb.m[key]
-	if !ok && len(mb.m) == mb.maxSize {
+	if !ok && len(mb.m)-mb.overflowGroups >= mb.maxSize {
 		return false
 	}
 	mb.m[key] = spanMetrics{count: value.count + old.count, sum: value.sum + old.sum}
 	return true
 }
 
+// update adds value to the metrics for the overflow group key.
+func (mb *metricsBuffer) update(key aggregationKey, value spanMetrics) {
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	old, ok := mb.m[key]
+	if !ok {
+		mb.overflowGroups++
+	}
+	mb.m[key] = spanMetrics{count: value.count + old.count, sum: value.sum + old.sum}
+}
+
 type aggregationKey struct {
 	// origin
 	serviceName        string
@@ -249,6 +304,16 @@ type aggregationKey struct {
 	outcome  string
 }
 
+// overflowKey returns the key of the overflow group for key. Only the
+// outcome is retained, so there are at most a few overflow groups.
+func overflowKey(key aggregationKey) aggregationKey {
+	return aggregationKey{
+		serviceName: overflowBucket,
+		resource:    overflowBucket,
+		outcome:     key.outcome,
+	}
+}
+
 type spanMetrics struct {
 	count float64
 	sum   float64
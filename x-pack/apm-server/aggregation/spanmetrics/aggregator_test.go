@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/apm-server/model"
+	"github.com/elastic/beats/v7/libbeat/monitoring"
 )
 
 func BenchmarkAggregateSpan(b *testing.B) {
@@ -202,8 +203,8 @@ func TestAggregatorOverflow(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	// The first two transaction groups will not require immediate publication,
-	// as we have configured the spanmetrics with a maximum of two buckets.
+	// The first two groups fill the aggregator's groups; the third and
+	// fourth groups are aggregated into the "_other" group.
 	var batch model.Batch
 	for i := 0; i < 10; i++ {
 		batch.Spans = append(batch.Spans,
@@ -211,43 +212,55 @@ func TestAggregatorOverflow(t *testing.T) {
 			makeSpan("service", "agent", "destination2", "success", 100*time.Millisecond, 1),
 		)
 	}
+	batch.Spans = append(batch.Spans,
+		makeSpan("service", "agent", "destination3", "success", 100*time.Millisecond, 1),
+		makeSpan("service", "agent", "destination4", "success", 100*time.Millisecond, 2),
+	)
 	err = agg.ProcessBatch(context.Background(), &batch)
 	require.NoError(t, err)
 	assert.Empty(t, batch.Metricsets)
 
-	// The third group will return a metricset for immediate publication.
-	for i := 0; i < 2; i++ {
-		batch.Spans = append(batch.Spans,
-			makeSpan("service", "agent", "destination3", "success", 100*time.Millisecond, 1),
-		)
-	}
-	err = agg.ProcessBatch(context.Background(), &batch)
-	require.NoError(t, err)
-	assert.Len(t, batch.Metricsets, 2)
+	expectedMonitoring := monitoring.MakeFlatSnapshot()
+	expectedMonitoring.Ints["spanmetrics.active_groups"] = 3
+	expectedMonitoring.Ints["spanmetrics.overflowed"] = 2
+	registry := monitoring.NewRegistry()
+	monitoring.NewFunc(registry, "spanmetrics", agg.CollectMonitoring)
+	assert.Equal(t, expectedMonitoring, monitoring.CollectFlatSnapshot(
+		registry,
+		monitoring.Full,
+		false, // expvar
+	))
 
-	for _, m := range batch.Metricsets {
-		require.NotNil(t, m)
-		require.False(t, m.Timestamp.IsZero())
+	go agg.Run()
+	defer agg.Stop(context.Background())
 
-		m.Timestamp = time.Time{}
-		assert.Equal(t, &model.Metricset{
-			Name: "service_destination",
-			Metadata: model.Metadata{
-				Service: model.Service{Name: "service", Agent: model.Agent{Name: "agent"}},
-			},
-			Event: model.MetricsetEventCategorization{
-				Outcome: "success",
-			},
-			Span: model.MetricsetSpan{
-				DestinationService: model.DestinationService{Resource: "destination3"},
-			},
-			Samples: []model.Sample{
-				{Name: "span.destination.service.response_time.count", Value: 1.0},
-				{Name: "span.destination.service.response_time.sum.us", Value: 100000.0},
-				// No metricset.period is recorded as these metrics are instantanous, not aggregated.
-			},
-		}, m)
+	batch = *expectBatch(t, batches)
+	require.Len(t, batch.Metricsets, 3)
+	var other *model.Metricset
+	for _, m := range batch.Metricsets {
+		if m.Span.DestinationService.Resource == "_other" {
+			other = m
+		}
 	}
+	require.NotNil(t, other)
+	other.Timestamp = time.Time{}
+	assert.Equal(t, &model.Metricset{
+		Name: "service_destination",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "_other"},
+		},
+		Event: model.MetricsetEventCategorization{
+			Outcome: "success",
+		},
+		Span: model.MetricsetSpan{
+			DestinationService: model.DestinationService{Resource: "_other"},
+		},
+		Samples: []model.Sample{
+			{Name: "span.destination.service.response_time.count", Value: 3.0},
+			{Name: "span.destination.service.response_time.sum.us", Value: 300000.0},
+			{Name: "metricset.period", Value: 10},
+		},
+	}, other)
 }
 
 func makeSpan(
@@ -258,7 +271,7 @@ func makeSpan(
 	span := &model.Span{
 		Metadata:            model.Metadata{Service: model.Service{Name: serviceName, Agent: model.Agent{Name: agentName}}},
 		Name:                serviceName + ":" + destinationServiceResource,
-		Duration:            duration.Seconds() * 1000,
+		Duration:            duration,
 		RepresentativeCount: count,
 		Outcome:             outcome,
 	}
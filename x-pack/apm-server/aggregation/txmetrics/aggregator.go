@@ -260,8 +260,7 @@ func (a *Aggregator) AggregateTransaction(tx *model.Transaction) *model.Metricse
 	key := a.makeTransactionAggregationKey(tx)
 	hash := key.hash()
 	count := transactionCount(tx)
-	duration := time.Duration(tx.Duration * float64(time.Millisecond))
-	if a.updateTransactionMetrics(key, hash, tx.RepresentativeCount, duration) {
+	if a.updateTransactionMetrics(key, hash, tx.RepresentativeCount, tx.Duration) {
 		return nil
 	}
 	// Too many aggregation keys: could not update metrics, so immediately
@@ -273,7 +272,7 @@ unique transaction names.`[1:],
 	)
 	atomic.AddInt64(&a.metrics.overflowed, 1)
 	counts := []int64{int64(math.Round(count))}
-	values := []float64{float64(durationMicros(duration))}
+	values := []float64{float64(durationMicros(tx.Duration))}
 	metricset := makeMetricset(key, hash, time.Now(), counts, values)
 	return &metricset
 }
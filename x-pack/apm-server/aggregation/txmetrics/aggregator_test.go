@@ -96,7 +96,7 @@ func TestProcessTransformablesOverflow(t *testing.T) {
 	for i := 0; i < 2; i++ {
 		batch.Transactions = append(batch.Transactions, &model.Transaction{
 			Name:                "baz",
-			Duration:            float64(time.Minute / time.Millisecond),
+			Duration:            time.Minute,
 			RepresentativeCount: 1,
 		})
 	}
@@ -328,10 +328,6 @@ func testHDRHistogramSignificantFigures(t *testing.T, sigfigs int) {
 		})
 		require.NoError(t, err)
 
-		durationMillis := func(d time.Duration) float64 {
-			return float64(d) / float64(time.Millisecond)
-		}
-
 		// The following values will be recorded in either 1, 2, 3, 4, or 5
 		// buckets according to the configured number of significant figures.
 		for _, duration := range []time.Duration{
@@ -343,7 +339,7 @@ func testHDRHistogramSignificantFigures(t *testing.T, sigfigs int) {
 		} {
 			metricset := agg.AggregateTransaction(&model.Transaction{
 				Name:                "T-1000",
-				Duration:            durationMillis(duration),
+				Duration:            duration,
 				RepresentativeCount: 1,
 			})
 			require.Nil(t, metricset)
@@ -456,7 +452,7 @@ func BenchmarkAggregateTransaction(b *testing.B) {
 
 	tx := &model.Transaction{
 		Name:                "T-1000",
-		Duration:            1,
+		Duration:            time.Millisecond,
 		RepresentativeCount: 1,
 	}
 
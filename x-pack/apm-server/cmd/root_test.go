@@ -12,14 +12,18 @@ import (
 
 func TestSubCommands(t *testing.T) {
 	validCommands := map[string]struct{}{
-		"apikey":     {},
-		"completion": {},
-		"export":     {},
-		"keystore":   {},
-		"run":        {},
-		"setup":      {},
-		"test":       {},
-		"version":    {},
+		"agent-config": {},
+		"apikey":       {},
+		"completion":   {},
+		"export":       {},
+		"import":       {},
+		"keystore":     {},
+		"privileges":   {},
+		"run":          {},
+		"setup":        {},
+		"test":         {},
+		"version":      {},
+		"verify":       {},
 	}
 
 	rootCmd := NewXPackRootCommand(beater.NewCreator(beater.CreatorParams{}))
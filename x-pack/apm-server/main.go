@@ -22,11 +22,17 @@ import (
 	"github.com/elastic/apm-server/beater"
 	"github.com/elastic/apm-server/elasticsearch"
 	"github.com/elastic/apm-server/model"
+	"github.com/elastic/apm-server/model/modelprocessor"
+	"github.com/elastic/apm-server/transform"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/breakdownmetrics"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/errormetrics"
+	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/marksmetrics"
 	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/spanmetrics"
 	"github.com/elastic/apm-server/x-pack/apm-server/aggregation/txmetrics"
 	"github.com/elastic/apm-server/x-pack/apm-server/cmd"
 	"github.com/elastic/apm-server/x-pack/apm-server/sampling"
 	"github.com/elastic/apm-server/x-pack/apm-server/sampling/pubsub"
+	"github.com/elastic/apm-server/x-pack/apm-server/slo"
 )
 
 var (
@@ -79,6 +85,78 @@ func newProcessors(args beater.ServerParams) ([]namedProcessor, error) {
 			return nil, errors.Wrapf(err, "error creating %s", name)
 		}
 		processors = append(processors, namedProcessor{name: name, processor: spanAggregator})
+		monitoring.NewFunc(aggregationMonitoringRegistry, "spanmetrics", spanAggregator.CollectMonitoring, monitoring.Report)
+	}
+	if args.Config.Aggregation.TransactionMarks.Enabled {
+		const name = "transaction marks aggregation"
+		args.Logger.Infof("creating %s with config: %+v", name, args.Config.Aggregation.TransactionMarks)
+		marksAggregator, err := marksmetrics.NewAggregator(marksmetrics.AggregatorConfig{
+			BatchProcessor: args.BatchProcessor,
+			Interval:       args.Config.Aggregation.TransactionMarks.Interval,
+			MaxGroups:      args.Config.Aggregation.TransactionMarks.MaxGroups,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating %s", name)
+		}
+		processors = append(processors, namedProcessor{name: name, processor: marksAggregator})
+	}
+	if args.Config.Aggregation.Errors.Enabled {
+		// Source map RUM error stacktraces before errors are aggregated,
+		// so aggregated grouping keys match those of error documents.
+		processors = append(processors, namedProcessor{
+			name:      "stacktrace config",
+			processor: newStacktraceProcessor(args.TransformConfig),
+		})
+
+		const name = "error metrics aggregation"
+		args.Logger.Infof("creating %s with config: %+v", name, args.Config.Aggregation.Errors)
+		errorAggregator, err := errormetrics.NewAggregator(errormetrics.AggregatorConfig{
+			BatchProcessor: args.BatchProcessor,
+			Interval:       args.Config.Aggregation.Errors.Interval,
+			MaxGroups:      args.Config.Aggregation.Errors.MaxGroups,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating %s", name)
+		}
+		processors = append(processors, namedProcessor{name: name, processor: errorAggregator})
+	}
+	if args.Config.Aggregation.Breakdown.Enabled {
+		const name = "breakdown metrics aggregation"
+		args.Logger.Infof("creating %s with config: %+v", name, args.Config.Aggregation.Breakdown)
+		breakdownAggregator, err := breakdownmetrics.NewAggregator(breakdownmetrics.AggregatorConfig{
+			BatchProcessor:  args.BatchProcessor,
+			Interval:        args.Config.Aggregation.Breakdown.Interval,
+			MaxGroups:       args.Config.Aggregation.Breakdown.MaxGroups,
+			MaxPendingSpans: args.Config.Aggregation.Breakdown.MaxPendingSpans,
+			Agents:          args.Config.Aggregation.Breakdown.Agents,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating %s", name)
+		}
+		processors = append(processors, namedProcessor{name: name, processor: breakdownAggregator})
+	}
+	if args.Config.SLO.Enabled {
+		const name = "service level objective evaluator"
+		args.Logger.Infof("creating %s with config: %+v", name, args.Config.SLO)
+		objectives := make([]slo.Objective, len(args.Config.SLO.Services))
+		for i, in := range args.Config.SLO.Services {
+			objectives[i] = slo.Objective{
+				ServiceName:        in.Name,
+				ServiceEnvironment: in.Environment,
+				MaxFailureRate:     in.MaxFailureRate,
+				LatencyThreshold:   in.Latency.Threshold,
+				LatencyPercentile:  in.Latency.Percentile,
+			}
+		}
+		evaluator, err := slo.NewEvaluator(slo.EvaluatorConfig{
+			BatchProcessor: args.BatchProcessor,
+			Interval:       args.Config.SLO.Interval,
+			Objectives:     objectives,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating %s", name)
+		}
+		processors = append(processors, namedProcessor{name: name, processor: evaluator})
 	}
 	if args.Config.Sampling.Tail != nil && args.Config.Sampling.Tail.Enabled {
 		const name = "tail sampler"
@@ -92,6 +170,32 @@ func newProcessors(args beater.ServerParams) ([]namedProcessor, error) {
 	return processors, nil
 }
 
+// stacktraceProcessor is a processor which applies the stacktrace
+// configuration to RUM errors. It has no background work to run.
+type stacktraceProcessor struct {
+	modelprocessor.ApplyStacktraceConfig
+	stopped chan struct{}
+}
+
+func newStacktraceProcessor(cfg *transform.Config) *stacktraceProcessor {
+	return &stacktraceProcessor{
+		ApplyStacktraceConfig: modelprocessor.ApplyStacktraceConfig{Config: cfg},
+		stopped:               make(chan struct{}),
+	}
+}
+
+// Run blocks until Stop is called.
+func (p *stacktraceProcessor) Run() error {
+	<-p.stopped
+	return nil
+}
+
+// Stop stops the processor.
+func (p *stacktraceProcessor) Stop(context.Context) error {
+	close(p.stopped)
+	return nil
+}
+
 // licensePlatinumCovered fails if license is neither a valid trial nor a valid platinum license.
 func licensePlatinumCovered(client *eslegclient.Connection) error {
 	log := logp.NewLogger("elasticsearch")
@@ -162,6 +266,7 @@ func newTailSamplingProcessor(args beater.ServerParams) (*sampling.Processor, er
 				ServiceEnvironment: in.Service.Environment,
 				TraceName:          in.Trace.Name,
 				TraceOutcome:       in.Trace.Outcome,
+				TraceMinDuration:   in.Trace.MinDuration,
 			},
 			SampleRate: in.SampleRate,
 		}
@@ -183,6 +288,7 @@ func newTailSamplingProcessor(args beater.ServerParams) (*sampling.Processor, er
 			StorageDir:        paths.Resolve(paths.Data, tailSamplingConfig.StorageDir),
 			StorageGCInterval: tailSamplingConfig.StorageGCInterval,
 			TTL:               tailSamplingConfig.TTL,
+			StorageLimit:      int64(tailSamplingConfig.StorageLimit),
 		},
 	})
 }
@@ -94,6 +94,14 @@ type StorageConfig struct {
 	// are expired from local storage.
 	TTL time.Duration
 
+	// StorageLimit holds the maximum size of local storage, in bytes.
+	// Once the limit is reached, trace events for which no sampling
+	// decision has been made are indexed immediately instead of being
+	// stored, except for root transactions which are not admitted to
+	// the sampling reservoir, which are dropped as usual. If StorageLimit
+	// is zero, local storage is unlimited.
+	StorageLimit int64
+
 	// ValueLogFileSize holds the size for Badger value log files.
 	// If unspecified, then the default value of 128MB will be used.
 	ValueLogFileSize int64
@@ -145,6 +153,13 @@ type PolicyCriteria struct {
 	// from the same service) will be grouped together for sampling purposes,
 	// similar to head-based sampling.
 	TraceName string
+
+	// TraceMinDuration holds the minimum root transaction duration for
+	// which this policy applies.
+	//
+	// If unspecified, root transactions of any duration will be grouped
+	// together for sampling purposes.
+	TraceMinDuration time.Duration
 }
 
 // Validate validates the configuration.
@@ -219,6 +234,9 @@ func (config StorageConfig) validate() error {
 	if config.TTL <= 0 {
 		return errors.New("TTL unspecified or negative")
 	}
+	if config.StorageLimit < 0 {
+		return errors.New("StorageLimit negative")
+	}
 	return nil
 }
 
@@ -229,5 +247,8 @@ func (p Policy) validate() error {
 	if p.SampleRate < 0 || p.SampleRate >= 1 {
 		return errors.New("SampleRate unspecified or out of range [0,1)")
 	}
+	if p.TraceMinDuration < 0 {
+		return errors.New("TraceMinDuration negative")
+	}
 	return nil
 }
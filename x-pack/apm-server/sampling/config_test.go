@@ -47,6 +47,11 @@ func TestNewProcessorConfigInvalid(t *testing.T) {
 		assertInvalidConfigError("invalid local sampling config: Policy 0 invalid: SampleRate unspecified or out of range [0,1)")
 	}
 	config.Policies[0].SampleRate = 0.5
+	config.Policies = append(config.Policies, sampling.Policy{
+		PolicyCriteria: sampling.PolicyCriteria{TraceMinDuration: -1},
+	})
+	assertInvalidConfigError("invalid local sampling config: Policy 1 invalid: TraceMinDuration negative")
+	config.Policies = config.Policies[:1]
 
 	for _, invalid := range []float64{-1, 0, 2.0} {
 		config.IngestRateDecayFactor = invalid
@@ -75,4 +80,8 @@ func TestNewProcessorConfigInvalid(t *testing.T) {
 
 	assertInvalidConfigError("invalid storage config: TTL unspecified or negative")
 	config.TTL = 1
+
+	config.StorageLimit = -1
+	assertInvalidConfigError("invalid storage config: StorageLimit negative")
+	config.StorageLimit = 0
 }
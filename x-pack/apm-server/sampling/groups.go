@@ -56,6 +56,9 @@ func (g *policyGroup) match(tx *model.Transaction) bool {
 	if g.policy.TraceName != "" && g.policy.TraceName != tx.Name {
 		return false
 	}
+	if g.policy.TraceMinDuration > 0 && tx.Duration < g.policy.TraceMinDuration {
+		return false
+	}
 	return true
 }
 
@@ -163,7 +166,7 @@ func (g *traceGroup) sampleTrace(tx *model.Transaction) (bool, error) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 	g.total++
-	return g.reservoir.Sample(tx.Duration, tx.TraceID), nil
+	return g.reservoir.Sample(float64(tx.Duration), tx.TraceID), nil
 }
 
 // finalizeSampledTraces locks the groups, appends their current trace IDs to
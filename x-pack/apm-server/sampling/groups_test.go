@@ -7,6 +7,7 @@ package sampling
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
@@ -81,6 +82,32 @@ func TestTraceGroupsPolicies(t *testing.T) {
 	}
 }
 
+func TestTraceGroupsMinDuration(t *testing.T) {
+	policies := []Policy{
+		{SampleRate: 0.5, PolicyCriteria: PolicyCriteria{TraceMinDuration: time.Second}},
+		{SampleRate: 0.1},
+	}
+	groups := newTraceGroups(policies, 1000, 1.0)
+
+	assertSampleRate := func(sampleRate float64, duration time.Duration) {
+		const N = 1000
+		for i := 0; i < N; i++ {
+			_, err := groups.sampleTrace(&model.Transaction{
+				Metadata: model.Metadata{Service: model.Service{Name: "service_name"}},
+				TraceID:  uuid.Must(uuid.NewV4()).String(),
+				ID:       uuid.Must(uuid.NewV4()).String(),
+				Duration: duration,
+			})
+			require.NoError(t, err)
+		}
+		sampled := groups.finalizeSampledTraces(nil)
+		assert.Len(t, sampled, int(sampleRate*N))
+	}
+	assertSampleRate(0.1, time.Second-1)
+	assertSampleRate(0.5, time.Second)
+	assertSampleRate(0.5, time.Minute)
+}
+
 func TestTraceGroupsMax(t *testing.T) {
 	const (
 		maxDynamicServices    = 100
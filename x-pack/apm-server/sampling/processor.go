@@ -28,6 +28,10 @@ const (
 	// tooManyGroupsLoggerRateLimit is the maximum frequency at which
 	// "too many groups" log messages are logged.
 	tooManyGroupsLoggerRateLimit = time.Minute
+
+	// storageLimitLoggerRateLimit is the maximum frequency at which
+	// "storage limit reached" log messages are logged.
+	storageLimitLoggerRateLimit = time.Minute
 )
 
 // ErrStopped is returned when calling ProcessBatch on a stopped Processor.
@@ -38,12 +42,14 @@ type Processor struct {
 	config              Config
 	logger              *logp.Logger
 	tooManyGroupsLogger *logp.Logger
+	storageLimitLogger  *logp.Logger
 	groups              *traceGroups
 
-	storageMu    sync.RWMutex
-	db           *badger.DB
-	storage      *eventstorage.ShardedReadWriter
-	eventMetrics eventMetrics
+	storageMu       sync.RWMutex
+	db              *badger.DB
+	storage         *eventstorage.ShardedReadWriter
+	eventMetrics    eventMetrics
+	decisionMetrics decisionMetrics
 
 	stopMu   sync.Mutex
 	stopping chan struct{}
@@ -54,6 +60,19 @@ type eventMetrics struct {
 	processed int64
 	dropped   int64
 	stored    int64
+	bypassed  int64
+}
+
+// decisionMetrics holds counters for tail-sampling decisions, which are
+// shared between APM Servers through Elasticsearch.
+type decisionMetrics struct {
+	// local holds the number of sampled trace IDs decided locally,
+	// and published for other servers.
+	local int64
+
+	// remote holds the number of sampled trace IDs received from
+	// other servers.
+	remote int64
 }
 
 // NewProcessor returns a new Processor, for tail-sampling trace events.
@@ -82,6 +101,7 @@ func NewProcessor(config Config) (*Processor, error) {
 		config:              config,
 		logger:              logger,
 		tooManyGroupsLogger: logger.WithOptions(logs.WithRateLimit(tooManyGroupsLoggerRateLimit)),
+		storageLimitLogger:  logger.WithOptions(logs.WithRateLimit(storageLimitLoggerRateLimit)),
 		groups:              newTraceGroups(config.Policies, config.MaxDynamicServices, config.IngestRateDecayFactor),
 		db:                  db,
 		storage:             readWriter,
@@ -123,6 +143,11 @@ func (p *Processor) CollectMonitoring(_ monitoring.Mode, V monitoring.Visitor) {
 		monitoring.ReportInt(V, "processed", atomic.LoadInt64(&p.eventMetrics.processed))
 		monitoring.ReportInt(V, "dropped", atomic.LoadInt64(&p.eventMetrics.dropped))
 		monitoring.ReportInt(V, "stored", atomic.LoadInt64(&p.eventMetrics.stored))
+		monitoring.ReportInt(V, "bypassed", atomic.LoadInt64(&p.eventMetrics.bypassed))
+	})
+	monitoring.ReportNamespace(V, "decisions", func() {
+		monitoring.ReportInt(V, "local", atomic.LoadInt64(&p.decisionMetrics.local))
+		monitoring.ReportInt(V, "remote", atomic.LoadInt64(&p.decisionMetrics.remote))
 	})
 }
 
@@ -215,6 +240,11 @@ func (p *Processor) processTransaction(tx *model.Transaction) (report, stored bo
 	}
 
 	if tx.ParentID != "" {
+		if p.storageLimitReached() {
+			// There is no room to store the transaction while we wait
+			// for a sampling decision, so index it immediately.
+			return true, false, nil
+		}
 		// Non-root transaction: write to local storage while we wait
 		// for a sampling decision.
 		return false, true, p.storage.WriteTransaction(tx)
@@ -244,6 +274,15 @@ sampling policies without service name specified.
 		return false, false, p.storage.WriteTraceSampled(tx.TraceID, false)
 	}
 
+	if p.storageLimitReached() {
+		// The root transaction was admitted to the sampling reservoir,
+		// but there is no room to store it while we wait for the sampling
+		// decision to be finalised, so index it immediately. Roots which
+		// were not admitted are still dropped above, so reaching the limit
+		// does not cause every trace to be indexed.
+		return true, false, nil
+	}
+
 	// The root transaction was admitted to the sampling reservoir, so we
 	// can proceed to write the transaction to storage; we may index it later,
 	// after finalising the sampling decision.
@@ -254,6 +293,11 @@ func (p *Processor) processSpan(span *model.Span) (report, stored bool, _ error)
 	traceSampled, err := p.storage.IsTraceSampled(span.TraceID)
 	if err != nil {
 		if err == eventstorage.ErrNotFound {
+			if p.storageLimitReached() {
+				// There is no room to store the span while we wait
+				// for a sampling decision, so index it immediately.
+				return true, false, nil
+			}
 			// Tail-sampling decision has not yet been made, write span to local storage.
 			return false, true, p.storage.WriteSpan(span)
 		}
@@ -266,6 +310,28 @@ func (p *Processor) processSpan(span *model.Span) (report, stored bool, _ error)
 	return true, false, nil
 }
 
+// storageLimitReached reports whether the configured storage limit has been
+// reached, in which case trace events should be indexed without waiting for
+// a sampling decision.
+//
+// Storage size is recalculated periodically by Badger, so the limit may be
+// exceeded by the amount of data written between recalculations.
+func (p *Processor) storageLimitReached() bool {
+	if p.config.StorageLimit <= 0 {
+		return false
+	}
+	lsmSize, valueLogSize := p.db.Size()
+	if lsmSize+valueLogSize < p.config.StorageLimit {
+		return false
+	}
+	atomic.AddInt64(&p.eventMetrics.bypassed, 1)
+	p.storageLimitLogger.Warnf(
+		"Tail-sampling storage limit of %d bytes reached, indexing trace events without sampling.",
+		p.config.StorageLimit,
+	)
+	return true
+}
+
 // Stop stops the processor, flushing and closing the event storage.
 func (p *Processor) Stop(ctx context.Context) error {
 	p.stopMu.Lock()
@@ -397,6 +463,7 @@ func (p *Processor) Run() error {
 				if len(traceIDs) == 0 {
 					continue
 				}
+				atomic.AddInt64(&p.decisionMetrics.local, int64(len(traceIDs)))
 				if err := pubsub.PublishSampledTraceIDs(ctx, traceIDs...); err != nil {
 					return err
 				}
@@ -424,6 +491,7 @@ func (p *Processor) Run() error {
 				return ctx.Err()
 			case traceID = <-remoteSampledTraceIDs:
 				p.logger.Debug("received remotely sampled trace ID")
+				atomic.AddInt64(&p.decisionMetrics.remote, 1)
 				remoteDecision = true
 			case traceID = <-localSampledTraceIDs:
 			}
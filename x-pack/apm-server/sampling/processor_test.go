@@ -111,6 +111,7 @@ func TestProcessAlreadyTailSampled(t *testing.T) {
 	expectedMonitoring.Ints["sampling.events.processed"] = 4
 	expectedMonitoring.Ints["sampling.events.stored"] = 2
 	expectedMonitoring.Ints["sampling.events.dropped"] = 0
+	expectedMonitoring.Ints["sampling.events.bypassed"] = 0
 	assertMonitoring(t, processor, expectedMonitoring, `sampling.events.*`)
 
 	// Stop the processor so we can access the database.
@@ -215,7 +216,10 @@ func TestProcessLocalTailSampling(t *testing.T) {
 	expectedMonitoring.Ints["sampling.events.processed"] = 4
 	expectedMonitoring.Ints["sampling.events.stored"] = 4
 	expectedMonitoring.Ints["sampling.events.dropped"] = 0
-	assertMonitoring(t, processor, expectedMonitoring, `sampling.events.*`)
+	expectedMonitoring.Ints["sampling.events.bypassed"] = 0
+	expectedMonitoring.Ints["sampling.decisions.local"] = 1
+	expectedMonitoring.Ints["sampling.decisions.remote"] = 0
+	assertMonitoring(t, processor, expectedMonitoring, `sampling.events.*`, `sampling.decisions.*`)
 
 	// Stop the processor so we can access the database.
 	assert.NoError(t, processor.Stop(context.Background()))
@@ -429,7 +433,10 @@ func TestProcessRemoteTailSampling(t *testing.T) {
 	expectedMonitoring.Ints["sampling.events.processed"] = 1
 	expectedMonitoring.Ints["sampling.events.stored"] = 1
 	expectedMonitoring.Ints["sampling.events.dropped"] = 0
-	assertMonitoring(t, processor, expectedMonitoring, `sampling.events.*`)
+	expectedMonitoring.Ints["sampling.events.bypassed"] = 0
+	expectedMonitoring.Ints["sampling.decisions.local"] = 0
+	expectedMonitoring.Ints["sampling.decisions.remote"] = 4
+	assertMonitoring(t, processor, expectedMonitoring, `sampling.events.*`, `sampling.decisions.*`)
 
 	assert.Equal(t, trace1Events, events)
 
@@ -488,6 +495,7 @@ func TestGroupsMonitoring(t *testing.T) {
 	expectedMonitoring.Ints["sampling.events.processed"] = int64(config.MaxDynamicServices) + 1
 	expectedMonitoring.Ints["sampling.events.stored"] = int64(config.MaxDynamicServices)
 	expectedMonitoring.Ints["sampling.events.dropped"] = 1 // final event dropped, after service limit reached
+	expectedMonitoring.Ints["sampling.events.bypassed"] = 0
 	assertMonitoring(t, processor, expectedMonitoring, `sampling.events.*`, `sampling.dynamic_service_groups`)
 }
 
@@ -524,6 +532,63 @@ func TestStorageMonitoring(t *testing.T) {
 	assert.NotZero(t, metrics.Ints, "sampling.storage.value_log_size")
 }
 
+func TestStorageLimit(t *testing.T) {
+	config := newTempdirConfig(t)
+
+	writeBatch := func(processor *sampling.Processor) *model.Batch {
+		traceID := uuid.Must(uuid.NewV4()).String()
+		batch := model.Batch{
+			Transactions: []*model.Transaction{{
+				TraceID:  traceID,
+				ID:       traceID,
+				Duration: 123,
+			}},
+			Spans: []*model.Span{{
+				TraceID: traceID,
+				ID:      traceID,
+			}},
+		}
+		err := processor.ProcessBatch(context.Background(), &batch)
+		require.NoError(t, err)
+		return &batch
+	}
+
+	processor, err := sampling.NewProcessor(config)
+	require.NoError(t, err)
+	go processor.Run()
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, 0, writeBatch(processor).Len())
+	}
+	processor.Stop(context.Background())
+
+	// Reopen storage with a limit below its current size, which is
+	// calculated when storage is opened. Trace events should then be
+	// indexed immediately rather than stored.
+	config.StorageLimit = 1
+	processor, err = sampling.NewProcessor(config)
+	require.NoError(t, err)
+	go processor.Run()
+	assert.Equal(t, 2, writeBatch(processor).Len())
+
+	expectedMonitoring := monitoring.MakeFlatSnapshot()
+	expectedMonitoring.Ints["sampling.events.processed"] = 2
+	expectedMonitoring.Ints["sampling.events.stored"] = 0
+	expectedMonitoring.Ints["sampling.events.dropped"] = 0
+	expectedMonitoring.Ints["sampling.events.bypassed"] = 2
+	assertMonitoring(t, processor, expectedMonitoring, `sampling.events.*`)
+	processor.Stop(context.Background())
+
+	// Root transactions which are not admitted to the sampling reservoir
+	// should still be dropped, along with the rest of their trace, when
+	// the storage limit has been reached.
+	config.Policies = []sampling.Policy{{SampleRate: 0}}
+	processor, err = sampling.NewProcessor(config)
+	require.NoError(t, err)
+	go processor.Run()
+	defer processor.Stop(context.Background())
+	assert.Equal(t, 0, writeBatch(processor).Len())
+}
+
 func TestStorageGC(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping slow test")
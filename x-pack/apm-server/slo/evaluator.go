@@ -0,0 +1,315 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package slo
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/go-hdrhistogram"
+
+	"github.com/elastic/apm-server/featureflag"
+	logs "github.com/elastic/apm-server/log"
+	"github.com/elastic/apm-server/model"
+)
+
+const (
+	metricsetName = "service_slo_violation"
+
+	objectiveFailureRate = "failure_rate"
+	objectiveLatency     = "latency"
+
+	minDuration time.Duration = 0
+	maxDuration time.Duration = time.Hour
+
+	hdrHistogramSignificantFigures = 2
+)
+
+// EvaluatorConfig holds configuration for creating an Evaluator.
+type EvaluatorConfig struct {
+	// BatchProcessor is a model.BatchProcessor for asynchronously
+	// processing violation documents.
+	BatchProcessor model.BatchProcessor
+
+	// Interval is the interval at which service level objectives
+	// are evaluated against the transactions observed within it.
+	Interval time.Duration
+
+	// Objectives holds the service level objectives to evaluate.
+	Objectives []Objective
+
+	// Logger is the logger for logging evaluation/publishing.
+	//
+	// If Logger is nil, a new logger will be constructed.
+	Logger *logp.Logger
+}
+
+// Objective holds the service level objectives for a service.
+type Objective struct {
+	// ServiceName holds the service name to match.
+	ServiceName string
+
+	// ServiceEnvironment, if non-empty, holds the service environment to match.
+	ServiceEnvironment string
+
+	// MaxFailureRate holds the maximum fraction of failed transactions
+	// within an interval. If MaxFailureRate is zero, the failure rate
+	// is not evaluated.
+	MaxFailureRate float64
+
+	// LatencyThreshold holds the maximum transaction duration at
+	// LatencyPercentile. If LatencyThreshold is zero, latency is
+	// not evaluated.
+	LatencyThreshold time.Duration
+
+	// LatencyPercentile holds the transaction duration percentile
+	// to compare against LatencyThreshold.
+	LatencyPercentile float64
+}
+
+// Validate validates the evaluator config.
+func (config EvaluatorConfig) Validate() error {
+	if config.BatchProcessor == nil {
+		return errors.New("BatchProcessor unspecified")
+	}
+	if config.Interval <= 0 {
+		return errors.New("Interval unspecified or negative")
+	}
+	for _, objective := range config.Objectives {
+		if objective.ServiceName == "" {
+			return errors.New("ServiceName unspecified")
+		}
+		if objective.LatencyThreshold > 0 && (objective.LatencyPercentile <= 0 || objective.LatencyPercentile > 100) {
+			return errors.Errorf("LatencyPercentile for %q out of range", objective.ServiceName)
+		}
+	}
+	return nil
+}
+
+// Evaluator observes transactions, periodically evaluating per-service
+// failure rates and latency against configured service level objectives,
+// and publishing a metricset document for each violated objective.
+type Evaluator struct {
+	stopMu   sync.Mutex
+	stopping chan struct{}
+	stopped  chan struct{}
+
+	config EvaluatorConfig
+
+	mu    sync.Mutex
+	stats []objectiveStats
+}
+
+// objectiveStats holds the transaction statistics for an objective,
+// observed within the current interval.
+type objectiveStats struct {
+	count     float64
+	failures  float64
+	histogram *hdrhistogram.Histogram
+}
+
+// NewEvaluator returns a new Evaluator with the given config.
+func NewEvaluator(config EvaluatorConfig) (*Evaluator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid evaluator config")
+	}
+	if config.Logger == nil {
+		config.Logger = logp.NewLogger(logs.SLO)
+	}
+	stats := make([]objectiveStats, len(config.Objectives))
+	for i := range stats {
+		stats[i].histogram = hdrhistogram.New(
+			durationMicros(minDuration),
+			durationMicros(maxDuration),
+			hdrHistogramSignificantFigures,
+		)
+	}
+	return &Evaluator{
+		stopping: make(chan struct{}),
+		stopped:  make(chan struct{}),
+		config:   config,
+		stats:    stats,
+	}, nil
+}
+
+// Run runs the Evaluator, periodically evaluating service level objectives
+// and publishing violations. Run returns when either a fatal error occurs,
+// or the Evaluator's Stop method is invoked.
+func (e *Evaluator) Run() error {
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+	defer func() {
+		e.stopMu.Lock()
+		defer e.stopMu.Unlock()
+		select {
+		case <-e.stopped:
+		default:
+			close(e.stopped)
+		}
+	}()
+	var stop bool
+	for !stop {
+		select {
+		case <-e.stopping:
+			stop = true
+		case <-ticker.C:
+		}
+		if err := e.publish(context.Background()); err != nil {
+			e.config.Logger.With(logp.Error(err)).Warnf(
+				"publishing service level objective violations failed: %s", err,
+			)
+		}
+	}
+	return nil
+}
+
+// Stop stops the Evaluator if it is running, waiting for it to evaluate
+// the final interval and return, or for the context to be cancelled.
+//
+// After Stop has been called the evaluator cannot be reused, as the Run
+// method will always return immediately.
+func (e *Evaluator) Stop(ctx context.Context) error {
+	e.stopMu.Lock()
+	select {
+	case <-e.stopped:
+	case <-e.stopping:
+		// Already stopping/stopped.
+	default:
+		close(e.stopping)
+	}
+	e.stopMu.Unlock()
+
+	select {
+	case <-e.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
+
+// ProcessBatch records all transactions contained in "b" which match
+// a service level objective. The batch is not modified.
+//
+// Transactions are not recorded while the SLOEvaluation feature flag
+// is disabled.
+func (e *Evaluator) ProcessBatch(ctx context.Context, b *model.Batch) error {
+	if !featureflag.Enabled(featureflag.SLOEvaluation) {
+		return nil
+	}
+	for _, tx := range b.Transactions {
+		e.processTransaction(tx)
+	}
+	return nil
+}
+
+func (e *Evaluator) processTransaction(tx *model.Transaction) {
+	if tx.RepresentativeCount <= 0 {
+		// RepresentativeCount is zero when the sample rate is unknown.
+		// We cannot calculate accurate rates without the sample rate,
+		// so we don't record the transaction at all in this case.
+		return
+	}
+	duration := tx.Duration
+	if duration < minDuration {
+		duration = minDuration
+	} else if duration > maxDuration {
+		duration = maxDuration
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i, objective := range e.config.Objectives {
+		if !objective.matches(&tx.Metadata.Service) {
+			continue
+		}
+		stats := &e.stats[i]
+		stats.count += tx.RepresentativeCount
+		if tx.Outcome == "failure" {
+			stats.failures += tx.RepresentativeCount
+		}
+		stats.histogram.RecordValues(durationMicros(duration), int64(math.Round(tx.RepresentativeCount)))
+	}
+}
+
+func (o *Objective) matches(service *model.Service) bool {
+	if service.Name != o.ServiceName {
+		return false
+	}
+	return o.ServiceEnvironment == "" || service.Environment == o.ServiceEnvironment
+}
+
+func (e *Evaluator) publish(ctx context.Context) error {
+	now := time.Now()
+	var metricsets []*model.Metricset
+	e.mu.Lock()
+	for i, objective := range e.config.Objectives {
+		stats := &e.stats[i]
+		if stats.count == 0 {
+			continue
+		}
+		if objective.MaxFailureRate > 0 {
+			if rate := stats.failures / stats.count; rate > objective.MaxFailureRate {
+				metricsets = append(metricsets, e.makeMetricset(
+					now, objective, objectiveFailureRate, rate, objective.MaxFailureRate, stats.count,
+				))
+			}
+		}
+		if objective.LatencyThreshold > 0 {
+			latency := stats.histogram.ValueAtQuantile(objective.LatencyPercentile)
+			if threshold := durationMicros(objective.LatencyThreshold); latency > threshold {
+				metricsets = append(metricsets, e.makeMetricset(
+					now, objective, objectiveLatency, float64(latency), float64(threshold), stats.count,
+				))
+			}
+		}
+		stats.count = 0
+		stats.failures = 0
+		stats.histogram.Reset()
+	}
+	e.mu.Unlock()
+
+	if len(metricsets) == 0 {
+		e.config.Logger.Debugf("no service level objective violations to publish")
+		return nil
+	}
+	e.config.Logger.Debugf("publishing %d service level objective violations", len(metricsets))
+	return e.config.BatchProcessor.ProcessBatch(ctx, &model.Batch{Metricsets: metricsets})
+}
+
+// makeMetricset returns a metricset describing the violation of an objective,
+// holding the observed and threshold values. Latency values are in microseconds.
+func (e *Evaluator) makeMetricset(
+	timestamp time.Time,
+	objective Objective,
+	name string,
+	observed, threshold, count float64,
+) *model.Metricset {
+	return &model.Metricset{
+		Timestamp: timestamp,
+		Name:      metricsetName,
+		Metadata: model.Metadata{
+			Service: model.Service{
+				Name:        objective.ServiceName,
+				Environment: objective.ServiceEnvironment,
+			},
+		},
+		Labels: common.MapStr{"slo_objective": name},
+		Samples: []model.Sample{
+			{Name: "slo.observed", Value: observed},
+			{Name: "slo.threshold", Value: threshold},
+			{Name: "slo.transaction.count", Value: math.Round(count)},
+			{Name: "metricset.period", Value: float64(e.config.Interval.Milliseconds())},
+		},
+	}
+}
+
+func durationMicros(d time.Duration) int64 {
+	return int64(d / time.Microsecond)
+}
@@ -0,0 +1,214 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package slo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/apm-server/featureflag"
+	"github.com/elastic/apm-server/model"
+)
+
+func TestNewEvaluatorConfigInvalid(t *testing.T) {
+	report := makeErrBatchProcessor(nil)
+
+	type test struct {
+		config EvaluatorConfig
+		err    string
+	}
+
+	for _, test := range []test{{
+		config: EvaluatorConfig{},
+		err:    "BatchProcessor unspecified",
+	}, {
+		config: EvaluatorConfig{
+			BatchProcessor: report,
+		},
+		err: "Interval unspecified or negative",
+	}, {
+		config: EvaluatorConfig{
+			BatchProcessor: report,
+			Interval:       time.Minute,
+			Objectives:     []Objective{{}},
+		},
+		err: "ServiceName unspecified",
+	}, {
+		config: EvaluatorConfig{
+			BatchProcessor: report,
+			Interval:       time.Minute,
+			Objectives:     []Objective{{ServiceName: "foo", LatencyThreshold: time.Second}},
+		},
+		err: `LatencyPercentile for "foo" out of range`,
+	}} {
+		evaluator, err := NewEvaluator(test.config)
+		require.Error(t, err)
+		require.Nil(t, evaluator)
+		assert.EqualError(t, err, "invalid evaluator config: "+test.err)
+	}
+}
+
+func TestEvaluatorRun(t *testing.T) {
+	batches := make(chan *model.Batch, 1)
+	evaluator, err := NewEvaluator(EvaluatorConfig{
+		BatchProcessor: makeChanBatchProcessor(batches),
+		Interval:       10 * time.Millisecond,
+		Objectives: []Objective{{
+			ServiceName:    "service-A",
+			MaxFailureRate: 0.1,
+		}, {
+			ServiceName:        "service-B",
+			ServiceEnvironment: "production",
+			MaxFailureRate:     0.5,
+			LatencyThreshold:   100 * time.Millisecond,
+			LatencyPercentile:  95,
+		}, {
+			ServiceName:       "service-C",
+			MaxFailureRate:    0.5,
+			LatencyThreshold:  time.Second,
+			LatencyPercentile: 99,
+		}},
+	})
+	require.NoError(t, err)
+
+	var transactions []*model.Transaction
+	for i := 0; i < 10; i++ {
+		outcome := "success"
+		if i < 2 {
+			outcome = "failure"
+		}
+		transactions = append(transactions,
+			makeTransaction("service-A", "", outcome, 10*time.Millisecond, 1),
+			makeTransaction("service-B", "production", outcome, 200*time.Millisecond, 2),
+			makeTransaction("service-B", "staging", "failure", 200*time.Millisecond, 1),
+			makeTransaction("service-C", "", outcome, 200*time.Millisecond, 1),
+			makeTransaction("service-C", "", "failure", time.Minute, 0), // unknown sample rate
+		)
+	}
+	batch := &model.Batch{Transactions: transactions}
+	require.NoError(t, evaluator.ProcessBatch(context.Background(), batch))
+	assert.Len(t, batch.Transactions, len(transactions))
+	assert.Empty(t, batch.Metricsets)
+
+	// Start the evaluator after processing to ensure objectives are evaluated deterministically.
+	go evaluator.Run()
+	defer evaluator.Stop(context.Background())
+
+	batch = expectBatch(t, batches)
+	require.Len(t, batch.Metricsets, 2)
+	for _, ms := range batch.Metricsets {
+		require.NotZero(t, ms.Timestamp)
+		ms.Timestamp = time.Time{}
+	}
+
+	assert.Equal(t, &model.Metricset{
+		Name: "service_slo_violation",
+		Metadata: model.Metadata{
+			Service: model.Service{Name: "service-A"},
+		},
+		Labels: common.MapStr{"slo_objective": "failure_rate"},
+		Samples: []model.Sample{
+			{Name: "slo.observed", Value: 0.2},
+			{Name: "slo.threshold", Value: 0.1},
+			{Name: "slo.transaction.count", Value: 10},
+			{Name: "metricset.period", Value: 10},
+		},
+	}, batch.Metricsets[0])
+
+	latency := batch.Metricsets[1]
+	assert.Equal(t, model.Metadata{
+		Service: model.Service{Name: "service-B", Environment: "production"},
+	}, latency.Metadata)
+	assert.Equal(t, common.MapStr{"slo_objective": "latency"}, latency.Labels)
+	require.Len(t, latency.Samples, 4)
+	assert.Equal(t, "slo.observed", latency.Samples[0].Name)
+	assert.InDelta(t, 200000, latency.Samples[0].Value, 2000)
+	assert.Equal(t, model.Sample{Name: "slo.threshold", Value: 100000}, latency.Samples[1])
+	assert.Equal(t, model.Sample{Name: "slo.transaction.count", Value: 20}, latency.Samples[2])
+
+	// Statistics are reset after each interval, so there
+	// should be no more violations published.
+	select {
+	case batch := <-batches:
+		t.Fatalf("unexpected publish: %+v", batch)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestEvaluatorFeatureFlagDisabled(t *testing.T) {
+	_, err := featureflag.Default.Update(map[string]bool{"slo_evaluation": false})
+	require.NoError(t, err)
+	defer featureflag.Default.Update(nil)
+
+	batches := make(chan *model.Batch, 1)
+	evaluator, err := NewEvaluator(EvaluatorConfig{
+		BatchProcessor: makeChanBatchProcessor(batches),
+		Interval:       10 * time.Millisecond,
+		Objectives:     []Objective{{ServiceName: "service-A", MaxFailureRate: 0.1}},
+	})
+	require.NoError(t, err)
+
+	batch := &model.Batch{Transactions: []*model.Transaction{
+		makeTransaction("service-A", "", "failure", 10*time.Millisecond, 1),
+	}}
+	require.NoError(t, evaluator.ProcessBatch(context.Background(), batch))
+
+	go evaluator.Run()
+	defer evaluator.Stop(context.Background())
+
+	// Transactions are not recorded while the flag is disabled,
+	// so there should be no violations published.
+	select {
+	case batch := <-batches:
+		t.Fatalf("unexpected publish: %+v", batch)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func makeTransaction(
+	serviceName, serviceEnvironment, outcome string,
+	duration time.Duration, count float64,
+) *model.Transaction {
+	return &model.Transaction{
+		Metadata: model.Metadata{
+			Service: model.Service{Name: serviceName, Environment: serviceEnvironment},
+		},
+		Outcome:             outcome,
+		Duration:            duration,
+		RepresentativeCount: count,
+	}
+}
+
+func makeErrBatchProcessor(err error) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(context.Context, *model.Batch) error { return err })
+}
+
+func makeChanBatchProcessor(ch chan<- *model.Batch) model.BatchProcessor {
+	return model.ProcessBatchFunc(func(ctx context.Context, batch *model.Batch) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch <- batch:
+			return nil
+		}
+	})
+}
+
+func expectBatch(t *testing.T, ch <-chan *model.Batch) *model.Batch {
+	t.Helper()
+	select {
+	case batch := <-ch:
+		return batch
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected publish")
+	}
+	panic("unreachable")
+}